@@ -2,25 +2,49 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/swagger"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/redis/go-redis/v9"
 	"github.com/rusgainew/tunduck-app/internal/conf"
+	"github.com/rusgainew/tunduck-app/internal/grpcapi"
+	"github.com/rusgainew/tunduck-app/internal/services"
 	"github.com/rusgainew/tunduck-app/internal/services/service_impl"
+	"github.com/rusgainew/tunduck-app/pkg/buildinfo"
+	"github.com/rusgainew/tunduck-app/pkg/cache"
+	"github.com/rusgainew/tunduck-app/pkg/canary"
 	"github.com/rusgainew/tunduck-app/pkg/container"
-	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/dbresolver"
+	"github.com/rusgainew/tunduck-app/pkg/esfgateway"
+	"github.com/rusgainew/tunduck-app/pkg/fieldcrypt"
 	"github.com/rusgainew/tunduck-app/pkg/health"
+	"github.com/rusgainew/tunduck-app/pkg/lifecycle"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/logship"
+	"github.com/rusgainew/tunduck-app/pkg/metering"
 	"github.com/rusgainew/tunduck-app/pkg/metrics"
 	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/migrations"
+	"github.com/rusgainew/tunduck-app/pkg/retry"
+	"github.com/rusgainew/tunduck-app/pkg/scheduler"
+	"github.com/rusgainew/tunduck-app/pkg/search"
+	"github.com/rusgainew/tunduck-app/pkg/shadow"
+	"github.com/rusgainew/tunduck-app/pkg/signature"
+	"github.com/rusgainew/tunduck-app/pkg/storage"
 	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
 	"gorm.io/gorm"
 )
 
@@ -35,6 +59,10 @@ type App struct {
 	container     *container.Container  // DI контейнер со всеми зависимостями
 	metrics       *metrics.Metrics      // Prometheus метрики
 	healthChecker *health.HealthChecker // Health check компонент
+	statusPage    *health.StatusPage    // Публичная сводка состояния /status
+	dbResolver    *dbresolver.Resolver  // Маршрутизация чтения на реплики (nil без DB_REPLICA_HOSTS)
+	grpcServer    *grpc.Server          // Внутренний gRPC API (nil без GRPC_ADDR)
+	logLevels     *logger.Levels        // Уровни логирования подсистем, изменяемые во время работы
 }
 
 // NewApp создает и инициализирует новое приложение
@@ -45,15 +73,9 @@ func NewApp(ctx context.Context, envPath string) (*App, error) {
 		ctx: ctx,
 	}
 
-	// Создаем файл логов
-	logFile, err := os.OpenFile("logs.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open log file: %w", err)
-	}
-
-	// Создаем логер и настраиваем вывод в файл и терминал одновременно
+	// Создаем логер с выводом в терминал
 	app.logger = logrus.New()
-	app.logger.SetOutput(io.MultiWriter(os.Stdout, logFile))
+	app.logger.SetOutput(os.Stdout)
 	app.logger.SetFormatter(&logrus.TextFormatter{
 		FullTimestamp:   true,
 		TimestampFormat: "2006-01-02 15:04:05",
@@ -61,26 +83,52 @@ func NewApp(ctx context.Context, envPath string) (*App, error) {
 
 	// Инициализируем конфигурацию
 	app.conf = conf.NewConf(app.logger, envPath)
+	logBuildInfo(app.logger)
+
+	// Дублируем журнал в файл на диске инстанса (LOG_FILE); с MULTI_INSTANCE по умолчанию только stdout
+	if path := app.conf.LogFile(); path != "" {
+		logFile, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open log file: %w", err)
+		}
+		app.logger.SetOutput(io.MultiWriter(os.Stdout, logFile))
+	}
+
+	// Уровни подсистем (LOG_LEVELS) и выборка отладочных записей; меняются через /api/admin/log-levels
+	app.logLevels = logger.NewLevels(app.logger, app.conf.LoggingConfig())
+	app.conf.SetDBLogger(logger.NewGormLogger(app.logLevels.Module(logger.ModuleGorm), logger.DefaultSlowQueryThreshold))
+
+	// Отправка журнала в Loki или Logstash (LOG_SHIPPING_URL); записи копятся в буфере до Container.Start
+	shipper, err := setupLogShipping(app.conf, app.logLevels, app.logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up log shipping: %w", err)
+	}
+
+	// Ключ подписи JWT выбирается по расписанию JWT_KEYS при каждом выпуске токена
+	if keys, err := app.conf.JWTKeys(); err == nil {
+		if current, err := keys.Current(); err == nil {
+			app.logger.WithFields(logrus.Fields{"kid": current.ID, "keys": len(keys.Keys())}).Info("JWT signing keys loaded")
+		}
+	}
+
+	// Шифрование банковских счетов и учетных данных шлюза в БД (FIELD_ENCRYPTION_KMS)
+	if err := setupFieldEncryption(app.conf, app.logger); err != nil {
+		return nil, fmt.Errorf("failed to set up field encryption: %w", err)
+	}
 
 	// Подключаемся к БД
 	app.db = app.conf.DBConnect()
 
 	// Инициализируем Redis подключение с retry logic
-	redisHost := app.conf.GetConValue("REDIS_HOST")
-	if redisHost == "" {
-		redisHost = "localhost"
-	}
-	redisPort := app.conf.GetConValue("REDIS_PORT")
-	if redisPort == "" {
-		redisPort = "6379"
-	}
-	redisAddr := fmt.Sprintf("%s:%s", redisHost, redisPort)
-	app.redisClient = redis.NewClient(&redis.Options{
-		Addr: redisAddr,
-	})
+	app.redisClient = newRedisClient(app.conf)
+	redisAddr := app.redisClient.Options().Addr
 
 	// Пытаемся подключиться к Redis с retry logic
 	if err := app.connectToRedisWithRetry(ctx, 3); err != nil {
+		// Без Redis кеш, лимиты и флаги реплик расходились бы, поэтому несколько реплик без него не запускаются
+		if app.conf.MultiInstance() {
+			return nil, fmt.Errorf("redis is required with MULTI_INSTANCE: %w", err)
+		}
 		app.logger.WithError(err).Warn("Failed to connect to Redis after retries (cache will be unavailable, but app will continue)")
 	} else {
 		app.logger.Infof("Redis connected successfully at %s", redisAddr)
@@ -92,57 +140,95 @@ func NewApp(ctx context.Context, envPath string) (*App, error) {
 	}
 
 	// Выполняем миграции БД
-	if err := app.db.AutoMigrate(&entity.User{}, &entity.EstOrganization{}); err != nil {
+	applied, err := migrations.Main().Up(ctx, app.db)
+	if err != nil {
 		return nil, fmt.Errorf("failed to run migrations: %w", err)
 	}
-	app.logger.Info("Database migrations completed successfully")
+	app.logger.WithField("applied", applied).Info("Database migrations completed successfully")
+
+	// Подключаем реплики для чтения после миграций, чтобы они шли на primary
+	if err := app.setupReadReplicas(); err != nil {
+		return nil, fmt.Errorf("failed to set up read replicas: %w", err)
+	}
 
 	// Создаем Fiber приложение
-	app.fiber = fiber.New()
+	app.fiber = fiber.New(fiber.Config{
+		// Ошибки, не обработанные middleware (404 маршрута, 405 и т.п.), отдаются в едином формате
+		ErrorHandler: middleware.GlobalErrorHandler(app.logger),
+	})
 
 	// Инициализируем Prometheus метрики
 	app.metrics = metrics.NewMetrics()
+	app.registerPoolMetrics()
 
-	// Инициализируем Health Checker
+	// Инициализируем Health Checker; компоненты проверяются параллельно с таймаутом HEALTH_CHECK_TIMEOUT
 	app.healthChecker = health.NewHealthChecker(app.db, app.redisClient, app.logger)
+	app.healthChecker.SetTimeout(app.conf.HealthCheckTimeout())
+	app.healthChecker.SetMetrics(app.metrics)
+	// Публичная сводка отслеживает инциденты с первой проверки
+	app.statusPage = health.NewStatusPage(app.healthChecker, app.conf.StatusConfig())
 
 	// Добавляем middleware для восстановления после паник (ПЕРВЫМ, перед другими)
-	app.fiber.Use(middleware.RecoveryMiddleware(app.logger))
-
-	// Добавляем CORS middleware
-	origins := app.conf.GetConValue("ALLOWED_ORIGINS")
-	if origins == "" {
-		// по умолчанию разрешаем порты разработки 3000, 3002, 5173 (Vite)
-		origins = "http://localhost:3000,http://localhost:3002,http://localhost:5173"
-	}
-	app.fiber.Use(cors.New(cors.Config{
-		AllowOrigins: origins,
-		AllowHeaders: "Origin, Content-Type, Accept, Authorization",
-		AllowMethods: "GET, POST, PUT, DELETE, OPTIONS",
+	app.fiber.Use(middleware.RecoveryMiddlewareWithConfig(middleware.RecoveryConfig{
+		Logger:  app.logger,
+		Metrics: app.metrics,
+		Release: buildinfo.Get().Release,
 	}))
 
+	// Добавляем CORS middleware; источники и кеширование preflight зависят от окружения (APP_ENV)
+	corsHandler, err := middleware.CORSMiddleware(app.conf.CORSConfig())
+	if err != nil {
+		return nil, fmt.Errorf("invalid CORS configuration: %w", err)
+	}
+	app.fiber.Use(corsHandler)
+
 	// Добавляем middleware для уникальных ID запросов (трассировка)
 	app.fiber.Use(middleware.RequestIDMiddleware())
 
+	// Добавляем middleware для определения языка сообщений (Accept-Language)
+	app.fiber.Use(middleware.I18nMiddleware())
+
+	// Добавляем проверку подписи запросов клиентов с ключом API (если ключи настроены)
+	if rawKeys := app.conf.GetConValue("API_KEYS"); rawKeys != "" {
+		apiKeys, err := signature.ParseKeys(rawKeys)
+		if err != nil {
+			return nil, fmt.Errorf("invalid API_KEYS: %w", err)
+		}
+		verifier := signature.NewVerifier(apiKeys, signature.NewRedisNonceStore(app.redisClient), signature.DefaultTolerance)
+		app.fiber.Use(middleware.HMACSignatureMiddleware(verifier, app.logger))
+		app.logger.WithField("keys", len(apiKeys)).Info("HMAC request signing enabled for API keys")
+	}
+
 	// Добавляем middleware для обработки ошибок
 	app.fiber.Use(middleware.ErrorHandlingMiddleware(app.logger))
 
 	// Добавляем middleware для логирования
-	app.fiber.Use(middleware.LogrusMiddleware(app.logger))
+	app.fiber.Use(middleware.LogrusMiddleware(app.logLevels.Module(logger.ModuleHTTP)))
 
 	// Добавляем middleware для метрик (ДОЛЖЕН быть после RequestID)
 	app.fiber.Use(middleware.MetricsMiddleware(app.metrics))
 
+	// Внедрение сбоев для проверки фронтенда и повторов на стенде (FAULT_INJECTION_ENABLED, не в продакшене)
+	if err := app.setupFaultInjection(); err != nil {
+		return nil, fmt.Errorf("failed to set up fault injection: %w", err)
+	}
+
 	// Инициализируем DI контейнер со всеми зависимостями
-	app.container = container.NewContainer(app.db, app.logger, app.redisClient)
+	app.container = container.NewContainer(container.WithDatabase(app.db), container.WithLogger(app.logger), container.WithRedis(app.redisClient), container.WithLogLevels(app.logLevels), container.WithDefaultTimezone(app.conf.DefaultTimezone()))
 	app.logger.Info("Dependency injection container initialized with Redis cache")
 
-	// Инициализируем Rate Limiter (доступен из контейнера для handlers)
-	_ = app.container.GetRateLimiter()
-	app.logger.Info("Rate limiter initialized with Redis backend")
+	// Изменения уровней логирования через /api/admin/log-levels применяются на всех инстансах
+	go app.container.GetLogLevelSync().Run(app.ctx)
 
-	// Выполняем cache warming для основных данных
-	app.warmCache()
+	// Журнал отправляется до остановки Redis и основной БД, после остальных компонентов
+	if shipper != nil {
+		app.container.Manage("log shipping", shipper)
+	}
+
+	// Реплики и пулы БД организаций закрываются после остальных компонентов, но до Redis и основной БД
+	if app.dbResolver != nil {
+		app.container.Manage("read replicas", lifecycle.Hook{OnStop: func(context.Context) error { return app.dbResolver.Close() }})
+	}
 
 	// Инициализируем сервис управления динамическими БД организаций
 	organizationDBService := service_impl.NewOrganizationDBService(
@@ -154,15 +240,159 @@ func NewApp(ctx context.Context, envPath string) (*App, error) {
 		app.conf.GetConValue("DB_PASSWORD"),
 		app.conf.GetConValue("DB_SSLMODE"),
 	)
+	// Учетные данные организаций после ротации хранятся в основной БД; без них — общие DB_USER и DB_PASSWORD
+	organizationDBService.SetCredentials(app.container.GetTenantCredentialRepository())
+	// Создание БД организации под блокировкой в Redis: повторный запрос на другом экземпляре ждет его завершения
+	if locker := app.container.GetLocker(); locker != nil {
+		organizationDBService.SetLocker(locker)
+	}
+	app.container.Manage("tenant pools", organizationDBService)
 	app.logger.Info("Organization database service initialized")
 
+	// Выборочная проверка БД организаций; останавливается до закрытия их пулов (TENANT_HEALTH_ENABLED)
+	app.setupTenantMonitor()
+
+	// Режим только для чтения при недоступности основной БД (READ_ONLY_MODE_ENABLED)
+	app.setupReadOnlyMode()
+
+	// Журнал аудита изменяющих запросов; регистрируется до маршрутов, чтобы охватить все API
+	app.fiber.Use(middleware.AuditMiddleware(app.container.GetAuditService(), app.logger))
+
+	// Экспорт журнала аудита в SIEM по syslog/CEF (SIEM_SYSLOG_ADDRESS)
+	if err := app.setupSIEM(); err != nil {
+		return nil, fmt.Errorf("failed to set up SIEM export: %w", err)
+	}
+
+	// Включаем локальный LRU перед Redis для справочных данных и организаций (CACHE_LOCAL_SIZE > 0)
+	app.enableLocalCache()
+
+	// Кеш ответов справочников и публичных данных организаций (RESPONSE_CACHE_ENABLED)
+	app.setupResponseCache()
+
+	// Инициализируем Rate Limiter (доступен из контейнера для handlers)
+	_ = app.container.GetRateLimiter()
+	app.logger.Info("Rate limiter initialized with Redis backend")
+
+	// Выполняем cache warming для основных данных
+	app.warmCache()
+
+	// Выборы лидера для работы в единственном экземпляре: планировщика и индексатора (LEADER_ELECTION_BACKEND)
+	if err := app.setupLeaderElection(); err != nil {
+		return nil, fmt.Errorf("failed to set up leader election: %w", err)
+	}
+
+	// Включаем индексацию документов в OpenSearch (OPENSEARCH_URL)
+	app.setupSearch()
+
+	// Уведомления в реальном времени через /ws; до шины событий, чтобы она дублировала в них статусы
+	app.setupRealtime()
+
+	// Запускаем публикацию доменных событий из outbox в шину событий
+	app.setupEvents()
+
+	// Подключаем объектное хранилище файлов (STORAGE_BACKEND) и антивирус (CLAMAV_ADDRESS)
+	if err := app.setupStorage(); err != nil {
+		return nil, fmt.Errorf("failed to set up storage: %w", err)
+	}
+
+	// Вложения пользователей со скачиванием по подписанным ссылкам без JWT
+	if err := app.setupAttachments(); err != nil {
+		return nil, fmt.Errorf("failed to set up attachments: %w", err)
+	}
+
+	// Теневой трафик на вторую установку или в хранилище (SHADOW_MODE); до регистрации маршрутов
+	if err := app.setupShadow(); err != nil {
+		return nil, fmt.Errorf("failed to set up shadow traffic: %w", err)
+	}
+
+	// Канареечный выкат новой реализации сервиса документов (CANARY_ENABLED); до регистрации маршрутов
+	if err := app.setupCanary(); err != nil {
+		return nil, fmt.Errorf("failed to set up canary routing: %w", err)
+	}
+
+	// Флаги функций с постепенным выкатом по организациям
+	app.setupFeatureFlags()
+
+	// Учет потребления для выставления счетов (METERING_ENABLED)
+	app.setupMetering()
+
+	// Квоты тарифных планов на создание документов и загрузку вложений (QUOTA_ENABLED)
+	if err := app.setupQuotas(); err != nil {
+		return nil, fmt.Errorf("failed to set up quotas: %w", err)
+	}
+
+	// Создаем очередь фоновых задач
+	app.setupJobs()
+
+	// Сброс месячного расхода квот отложенной задачей в начале месяца
+	app.setupQuotaReset()
+
+	// Отправка документов к заданному времени и напоминания о черновиках (DRAFT_REMINDER_AFTER)
+	app.setupDocumentSchedule()
+
+	// Подключаем отправку писем через очередь (SMTP_HOST)
+	if err := app.setupMail(); err != nil {
+		return nil, fmt.Errorf("failed to set up mail: %w", err)
+	}
+
+	// Подключаем фоновые выгрузки в объектное хранилище
+	if err := app.setupExports(); err != nil {
+		return nil, fmt.Errorf("failed to set up exports: %w", err)
+	}
+
+	// Корзина документов с массовым удалением и восстановлением (TRASH_RETENTION)
+	app.setupTrash()
+
+	// Миграция БД всех организаций фоновой задачей (TENANT_MIGRATION_CONCURRENCY)
+	app.setupTenantMigrations()
+
+	// Ротация учетных данных БД организаций с переподключением пулов
+	app.setupTenantCredentials(organizationDBService)
+
+	// Шлюз ЭСФ; без ESF_GATEWAY_URL — встроенная имитация на /mock-esf
+	if err := app.setupESFGateway(); err != nil {
+		return nil, fmt.Errorf("failed to set up ESF gateway: %w", err)
+	}
+
+	// Источник официальных курсов валют (EXCHANGE_RATES_URL)
+	app.container.GetExchangeRateService().SetSource(app.conf.ExchangeRatesSource())
+
+	// Воркеры запускаются вместе с компонентами контейнера в конце сборки (JOBS_WORKERS_ENABLED)
+	app.startJobWorkers()
+
+	// Запускаем периодические задачи (SCHEDULER_ENABLED)
+	if err := app.setupScheduler(); err != nil {
+		return nil, fmt.Errorf("failed to set up scheduler: %w", err)
+	}
+
+	// Лидер запускает планировщик и индексатор после воркеров задач и останавливает их раньше
+	app.container.Manage("leader election", app.container.GetLeaderElector())
+
 	// Регистрируем все handlers с контейнером зависимостей
 	RegisterHandlers(app.fiber, app.container, organizationDBService)
 
+	// Внутренний gRPC API на отдельном порту поверх тех же сервисов (GRPC_ADDR)
+	if err := app.setupGRPC(); err != nil {
+		return nil, fmt.Errorf("failed to set up gRPC API: %w", err)
+	}
+
+	// Служебные маршруты: метрики, спецификация OpenAPI, Swagger UI, проверка здоровья
+	app.registerSystemRoutes()
+
+	// Запускаем воркеры и планировщик после регистрации всех обработчиков
+	if err := app.container.Start(ctx); err != nil {
+		return nil, fmt.Errorf("failed to start components: %w", err)
+	}
+
+	return app, nil
+}
+
+// registerSystemRoutes регистрирует служебные маршруты: метрики, документацию API и проверку здоровья
+func (a *App) registerSystemRoutes() {
 	// Регистрируем Prometheus metrics endpoint в правильном формате
 	// Prometheus scraper ожидает текстовый формат по пути /metrics
 	metricsHandler := promhttp.Handler()
-	app.fiber.All("/metrics", func(c *fiber.Ctx) error {
+	a.fiber.Get("/metrics", func(c *fiber.Ctx) error {
 		c.Set(fiber.HeaderContentType, "text/plain; version=0.0.4; charset=utf-8")
 
 		// Используем буфер для захвата вывода prometheus handler
@@ -191,55 +421,20 @@ func NewApp(ctx context.Context, envPath string) (*App, error) {
 		return c.Send(w.body)
 	})
 
-	// Регистрируем Swagger JSON endpoint
-	app.fiber.Get("/swagger/doc.json", func(c *fiber.Ctx) error {
-		swaggerSpec := map[string]interface{}{
-			"openapi": "3.0.0",
-			"info": map[string]interface{}{
-				"title":       "Tunduc API System",
-				"description": "Enterprise API для управления ЭСФ документами, организациями и пользователями с кешированием, ограничением частоты запросов и мониторингом здоровья системы",
-				"version":     "1.0.0",
-				"contact": map[string]string{
-					"name":  "API Support",
-					"url":   "https://github.com/rusgainew/tunduck-app",
-					"email": "support@example.com",
-				},
-				"license": map[string]string{
-					"name": "Apache 2.0",
-					"url":  "http://www.apache.org/licenses/LICENSE-2.0.html",
-				},
-			},
-			"servers": []map[string]string{
-				{"url": "http://localhost:8080", "description": "Development"},
-				{"url": "https://api.example.com", "description": "Production"},
-			},
-			"components": map[string]interface{}{
-				"securitySchemes": map[string]interface{}{
-					"BearerAuth": map[string]interface{}{
-						"type":         "http",
-						"scheme":       "bearer",
-						"bearerFormat": "JWT",
-					},
-				},
-				"schemas": getSwaggerSchemas(),
-			},
-			"paths": getSwaggerPaths(),
-		}
-		return c.JSON(swaggerSpec)
-	})
+	// Клиенты API генерируются по той же спецификации
+	a.registerSDKRoutes()
 
-	// Регистрируем Swagger UI endpoint
-	// Используем gofiber/swagger для интеграции с Fiber
-	app.fiber.Get("/swagger/*", swagger.HandlerDefault)
+	// Swagger UI, /docs и спецификация; доступ зависит от DOCS_MODE и APP_ENV
+	a.registerDocsRoutes()
 
-	// Регистрируем API документацию редирект
-	app.fiber.Get("/docs", func(c *fiber.Ctx) error {
-		return c.Redirect("/swagger/index.html")
+	// Сведения о сборке: версия, коммит, время сборки, версия Go и включенные при сборке функции
+	a.fiber.Get("/version", func(c *fiber.Ctx) error {
+		return c.JSON(buildinfo.Get())
 	})
 
-	// Регистрируем Health Check endpoint
-	app.fiber.Get("/health", func(c *fiber.Ctx) error {
-		healthStatus := app.healthChecker.Check(c.Context())
+	// Регистрируем Health Check endpoint; DEGRADED отвечает 200, чтобы балансировщик не снимал инстанс
+	a.fiber.Get("/health", func(c *fiber.Ctx) error {
+		healthStatus := a.healthChecker.Check(c.Context())
 		statusCode := http.StatusOK
 		if healthStatus.Status == health.StatusDown {
 			statusCode = http.StatusServiceUnavailable
@@ -247,7 +442,25 @@ func NewApp(ctx context.Context, envPath string) (*App, error) {
 		return c.Status(statusCode).JSON(healthStatus)
 	})
 
-	return app, nil
+	// Публичная сводка для страницы состояния: всегда 200, без сообщений об ошибках, кешируется на STATUS_CACHE_TTL
+	a.fiber.Get("/status", func(c *fiber.Ctx) error {
+		maxAge := max(int(a.statusPage.Config().CacheTTL.Seconds()), 1)
+		c.Set(fiber.HeaderCacheControl, fmt.Sprintf("public, max-age=%d", maxAge))
+		return c.JSON(a.statusPage.Summary(c.Context()))
+	})
+}
+
+// logBuildInfo пишет в журнал сведения о сборке, чтобы по журналу было видно, какая версия запущена
+func logBuildInfo(log *logrus.Logger) {
+	info := buildinfo.Get()
+	log.WithFields(logrus.Fields{
+		"version":    info.Version,
+		"commit":     info.Commit,
+		"build_time": info.BuildTime,
+		"go_version": info.GoVersion,
+		"features":   strings.Join(info.Features, ","),
+		"release":    info.Release,
+	}).Info("Build info")
 }
 
 // Run запускает веб-сервер и блокирует выполнение до завершения работы
@@ -263,27 +476,741 @@ func (a *App) Run() error {
 
 	a.logger.Infof("Starting server on %s", addr)
 
+	if a.grpcServer != nil {
+		grpcAddr := a.conf.GRPCConfig().Addr
+		listener, err := net.Listen("tcp", grpcAddr)
+		if err != nil {
+			return fmt.Errorf("failed to listen on gRPC address %s: %w", grpcAddr, err)
+		}
+		a.logger.Infof("Starting gRPC server on %s", grpcAddr)
+		go func() {
+			if err := a.grpcServer.Serve(listener); err != nil {
+				a.logger.WithError(err).Error("gRPC server stopped")
+			}
+		}()
+	}
+
 	if err := a.fiber.Listen(addr); err != nil {
 		return fmt.Errorf("failed to start server: %w", err)
 	}
 	return nil
 }
 
-// Shutdown корректно завершает работу приложения и освобождает ресурсы
+// Shutdown корректно завершает работу приложения и освобождает ресурсы (не дольше shutdownTimeout)
 func (a *App) Shutdown() error {
-	// Закрываем Redis соединение
-	if a.redisClient != nil {
-		if err := a.redisClient.Close(); err != nil {
-			a.logger.WithError(err).Warn("Failed to close Redis connection")
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	return a.ShutdownWithContext(ctx)
+}
+
+// shutdownTimeout время на остановку приложения в Shutdown
+const shutdownTimeout = 30 * time.Second
+
+// setupReadReplicas направляет чтение на реплики из DB_REPLICA_HOSTS.
+// DB_REPLICA_MAX_LAG задает допустимое отставание реплики (по умолчанию 10s).
+func (a *App) setupReadReplicas() error {
+	replicas := a.conf.ConnectReadReplicas()
+	if len(replicas) == 0 {
+		return nil
+	}
+
+	maxLag := dbresolver.DefaultMaxLag
+	if raw := a.conf.GetConValue("DB_REPLICA_MAX_LAG"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			a.logger.WithError(err).Warn("Invalid DB_REPLICA_MAX_LAG, using default")
+		} else {
+			maxLag = parsed
 		}
 	}
 
-	if a.fiber != nil {
-		return a.fiber.Shutdown()
+	resolver := dbresolver.New(dbresolver.Config{MaxLag: maxLag, Logger: a.logger}, replicas...)
+	if err := a.db.Use(resolver); err != nil {
+		return err
+	}
+	resolver.Start(a.ctx)
+
+	a.dbResolver = resolver
+	a.logger.WithField("replicas", len(replicas)).Info("Read replica routing enabled")
+	return nil
+}
+
+// registerPoolMetrics публикует метрики пулов соединений primary и реплик
+func (a *App) registerPoolMetrics() {
+	pools := map[string]*sql.DB{}
+	if sqlDB, err := a.db.DB(); err == nil {
+		pools["main"] = sqlDB
+	}
+	if a.dbResolver != nil {
+		for name, pool := range a.dbResolver.Pools() {
+			if sqlDB, ok := pool.(*sql.DB); ok {
+				pools[name] = sqlDB
+			}
+		}
+	}
+
+	for name, sqlDB := range pools {
+		if err := metrics.RegisterDBStats(prometheus.DefaultRegisterer, sqlDB, name); err != nil {
+			a.logger.WithError(err).WithField("db_name", name).Warn("Failed to register DB pool metrics")
+		}
+	}
+}
+
+// Параметры локального кеша по умолчанию
+const (
+	defaultLocalCacheTTL = 30 * time.Second
+)
+
+// enableLocalCache включает двухуровневый кеш по настройкам CACHE_LOCAL_SIZE и CACHE_LOCAL_TTL
+func (a *App) enableLocalCache() {
+	size, _ := strconv.Atoi(a.conf.GetConValue("CACHE_LOCAL_SIZE"))
+	if size <= 0 {
+		return
+	}
+
+	ttl := defaultLocalCacheTTL
+	if raw := a.conf.GetConValue("CACHE_LOCAL_TTL"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil {
+			a.logger.WithError(err).Warn("Invalid CACHE_LOCAL_TTL, using default")
+		} else {
+			ttl = parsed
+		}
+	}
+
+	manager, ok := a.container.GetCacheManager().(*cache.RedisCacheManager)
+	if !ok {
+		return
+	}
+	manager.EnableLocalCache(a.ctx, size, ttl, "cache", "org")
+}
+
+// setupResponseCache включает кеширование ответов GET в Redis, если RESPONSE_CACHE_ENABLED не false
+func (a *App) setupResponseCache() {
+	cfg := a.conf.ResponseCacheConfig()
+	if !cfg.Enabled {
+		return
+	}
+
+	a.container.EnableResponseCache(cfg)
+	a.logger.WithFields(logrus.Fields{
+		"ttl":     cfg.TTL.String(),
+		"max_age": cfg.MaxAge.String(),
+	}).Info("Response cache enabled")
+}
+
+// setupSearch включает индексацию документов в OpenSearch и запускает индексатор.
+// Без OPENSEARCH_URL поиск документов выполняется полнотекстовым поиском Postgres.
+func (a *App) setupSearch() {
+	cfg := a.conf.SearchConfig()
+	if !cfg.Enabled() {
+		a.logger.Info("OpenSearch not configured, document search uses Postgres full-text search")
+		return
+	}
+
+	client := search.NewClient(cfg)
+	if err := client.Ping(a.ctx); err != nil {
+		// Outbox накапливает события, поиск использует Postgres, пока OpenSearch недоступен
+		a.logger.WithError(err).Warn("OpenSearch is unavailable at startup, indexing will retry")
+	}
+
+	a.container.EnableSearch(client, cfg.IndexInterval)
+	// Несколько индексаторов разбирали бы один outbox параллельно, поэтому он работает только на лидере
+	a.container.GetLeaderElector().Add("search indexer", a.container.GetSearchIndexer().Run)
+
+	a.logger.WithFields(logrus.Fields{
+		"index_prefix":  cfg.IndexPrefix,
+		"organizations": len(cfg.Organizations),
+	}).Info("OpenSearch document indexing enabled")
+}
+
+// setupFieldEncryption включает прозрачное шифрование полей до первого обращения к БД
+func setupFieldEncryption(cfg *conf.Conf, log *logrus.Logger) error {
+	fcfg := cfg.FieldEncryptionConfig()
+	if !fcfg.Enabled() {
+		if cfg.IsProduction() {
+			log.Warn("FIELD_ENCRYPTION_KMS is not set, bank accounts and gateway tokens are stored unencrypted")
+		}
+		return nil
+	}
+
+	cipher, err := fieldcrypt.New(fcfg)
+	if err != nil {
+		return err
+	}
+	fieldcrypt.SetDefault(cipher)
+	log.WithField("kms", fcfg.KMS).Info("Field-level encryption enabled")
+	return nil
+}
+
+// setupLogShipping подключает отправку журнала ко всем логгерам, включая логгеры подсистем
+func setupLogShipping(cfg *conf.Conf, levels *logger.Levels, log *logrus.Logger) (*logship.Shipper, error) {
+	scfg := cfg.LogShippingConfig()
+	if !scfg.Enabled() {
+		return nil, nil
+	}
+
+	shipper, err := logship.New(scfg)
+	if err != nil {
+		return nil, err
+	}
+	levels.AddHook(shipper)
+	log.WithFields(logrus.Fields{
+		"url":    scfg.URL,
+		"format": scfg.Format,
+		"level":  scfg.Level.String(),
+	}).Info("Log shipping enabled")
+	return shipper, nil
+}
+
+// setupSIEM запускает экспорт журнала аудита, включая вход и выход пользователей, в SIEM
+func (a *App) setupSIEM() error {
+	cfg := a.conf.SIEMConfig()
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	exporter, err := a.container.EnableSIEM(cfg)
+	if err != nil {
+		return err
+	}
+	go exporter.Run(a.ctx)
+
+	a.logger.WithFields(logrus.Fields{
+		"address": cfg.Address,
+		"network": cfg.Network,
+	}).Info("Audit log export to SIEM enabled")
+	return nil
+}
+
+// setupRealtime запускает хаб уведомлений, который получает сообщения других инстансов через Redis pub/sub
+func (a *App) setupRealtime() {
+	cfg := a.conf.RealtimeConfig()
+	hub := a.container.EnableRealtime(cfg, a.conf.WebSocketConfig())
+	go hub.Run(a.ctx)
+
+	a.logger.WithField("buffer_size", cfg.BufferSize).Info("Realtime notification hub started")
+}
+
+// setupEvents запускает ретранслятор outbox доменных событий в поток Redis
+func (a *App) setupEvents() {
+	cfg := a.conf.EventsConfig()
+	relay := a.container.EnableEvents(cfg)
+	go relay.Run(a.ctx)
+
+	a.logger.WithFields(logrus.Fields{
+		"relay_interval": cfg.RelayInterval.String(),
+		"retention":      cfg.Retention.String(),
+	}).Info("Domain event relay started")
+}
+
+// setupStorage создает объектное хранилище: локальный каталог по умолчанию или S3-совместимый бакет.
+// С CLAMAV_ADDRESS файлы пользователей проверяются антивирусом перед сохранением.
+func (a *App) setupStorage() error {
+	cfg := a.conf.StorageConfig()
+	if _, err := a.container.EnableStorage(cfg); err != nil {
+		return err
+	}
+
+	backend := cfg.Backend
+	if backend == "" {
+		backend = storage.BackendLocal
+	}
+	a.logger.WithFields(logrus.Fields{
+		"backend":         backend,
+		"lifecycle_rules": len(cfg.Lifecycle),
+	}).Info("Object storage initialized")
+
+	avCfg := a.conf.AntivirusConfig()
+	if !avCfg.Enabled() {
+		a.logger.Warn("CLAMAV_ADDRESS is not set, uploaded files are not scanned for viruses")
+		return nil
+	}
+
+	scanner := a.container.EnableAntivirus(avCfg)
+	ctx, cancel := context.WithTimeout(a.ctx, 5*time.Second)
+	defer cancel()
+	if err := scanner.Ping(ctx); err != nil {
+		// Загрузки будут отклоняться, пока clamd недоступен
+		a.logger.WithError(err).Warn("ClamAV is not reachable, uploads will fail until it is available")
+	} else {
+		a.logger.WithField("address", avCfg.Address).Info("Antivirus scanning of uploads enabled")
+	}
+	return nil
+}
+
+// setupShadow копирует запросы к сервису документов на вторую установку (mirror) или записывает
+// их в хранилище (record) для проверки новой версии на трафике продакшена
+func (a *App) setupShadow() error {
+	cfg := a.conf.ShadowConfig()
+	if !cfg.Enabled() {
+		return nil
+	}
+
+	s, err := shadow.New(cfg, a.container.GetStorage(), a.logger)
+	if err != nil {
+		return err
+	}
+	a.container.Manage("shadow traffic", s)
+	a.fiber.Use(middleware.ShadowMiddleware(s))
+
+	cfg = s.Config()
+	a.logger.WithFields(logrus.Fields{
+		"mode":        cfg.Mode,
+		"target":      cfg.TargetURL,
+		"routes":      cfg.Routes,
+		"sample_rate": cfg.SampleRate,
+	}).Info("Shadow traffic enabled")
+	return nil
+}
+
+// setupCanary направляет запросы с X-Canary: 1 и CANARY_PERCENT процентов организаций на реализацию
+// сервиса документов, зарегистрированную через container.WithCanaryDocumentService
+func (a *App) setupCanary() error {
+	cfg := a.conf.CanaryConfig()
+	if !cfg.Enabled {
+		return nil
+	}
+	if !a.container.HasCanaryDocumentService() {
+		a.logger.Warn("CANARY_ENABLED is set, but no canary document service is registered; all requests use the primary implementation")
+		return nil
+	}
+
+	router, err := canary.New(cfg)
+	if err != nil {
+		return err
+	}
+	a.fiber.Use(middleware.CanaryMiddleware(router))
+
+	cfg = router.Config()
+	a.logger.WithFields(logrus.Fields{
+		"percent": cfg.Percent,
+		"routes":  cfg.Routes,
+	}).Info("Canary routing enabled")
+	return nil
+}
+
+// setupAttachments создает сервис вложений поверх хранилища с проверкой антивирусом
+func (a *App) setupAttachments() error {
+	cfg := a.conf.AttachmentConfig()
+	if _, err := a.container.EnableAttachments(cfg); err != nil {
+		return err
+	}
+	if cfg.BaseURL == "" {
+		a.logger.Warn("PUBLIC_BASE_URL is not set, download links are relative and cannot be used in emails")
+	}
+	a.logger.WithFields(logrus.Fields{
+		"link_ttl": cfg.LinkTTL.String(),
+		"max_size": cfg.MaxSize,
+	}).Info("Attachments enabled")
+	return nil
+}
+
+// setupFeatureFlags загружает флаги функций из Redis и следит за их изменениями
+func (a *App) setupFeatureFlags() {
+	flags := a.container.EnableFeatureFlags(a.conf.FeatureFlagsConfig())
+
+	// Загружаем флаги до приема запросов, чтобы включенные функции не выключались на время старта
+	ctx, cancel := context.WithTimeout(a.ctx, featureFlagsLoadTimeout)
+	defer cancel()
+	if err := flags.Load(ctx); err != nil {
+		a.logger.WithError(err).Warn("Failed to load feature flags, all flags are off until Redis is available")
+	}
+	go flags.Run(a.ctx)
+}
+
+// featureFlagsLoadTimeout время на загрузку флагов функций при старте
+const featureFlagsLoadTimeout = 5 * time.Second
+
+// setupMetering запускает учет потребления организаций: приращения пишутся в БД каждые
+// METERING_FLUSH_INTERVAL, остаток — при остановке приложения
+func (a *App) setupMetering() {
+	cfg := a.conf.MeteringConfig()
+	if !cfg.Enabled {
+		return
+	}
+
+	meter := a.container.EnableMetering(cfg)
+	a.fiber.Use(metering.CountRequests(meter))
+	go meter.Run(a.ctx)
+	a.logger.WithField("flush_interval", cfg.FlushInterval.String()).Info("Usage metering enabled")
+}
+
+// setupQuotas включает квоты тарифных планов, если QUOTA_ENABLED=true
+func (a *App) setupQuotas() error {
+	cfg, err := a.conf.QuotaConfig()
+	if err != nil {
+		return err
+	}
+	if !cfg.Enabled {
+		return nil
+	}
+
+	enforcer, err := a.container.EnableQuotas(cfg)
+	if err != nil {
+		return err
+	}
+	a.logger.WithFields(logrus.Fields{
+		"plans":        enforcer.PlanNames(),
+		"default_plan": cfg.DefaultPlan,
+	}).Info("Plan quotas enabled")
+	return nil
+}
+
+// setupJobs создает менеджер фоновых задач; обработчики регистрируются до startJobWorkers
+func (a *App) setupJobs() {
+	a.container.EnableJobs(a.conf.JobsConfig())
+}
+
+// setupQuotaReset планирует сброс месячного расхода квот на начало следующего месяца, если квоты включены
+func (a *App) setupQuotaReset() {
+	enforcer := a.container.GetQuotaEnforcer()
+	if enforcer == nil {
+		return
+	}
+
+	reset := service_impl.NewQuotaReset(enforcer, a.container.GetJobManager(), a.logger)
+	ctx, cancel := context.WithTimeout(a.ctx, 5*time.Second)
+	defer cancel()
+	at, err := reset.Schedule(ctx)
+	if err != nil {
+		a.logger.WithError(err).Warn("Failed to schedule monthly quota reset")
+		return
+	}
+	a.logger.WithField("next_reset", at).Info("Monthly quota reset scheduled")
+}
+
+// setupDocumentSchedule подключает отложенную отправку документов и напоминания о черновиках;
+// обе работают отложенными задачами с ключом документа
+func (a *App) setupDocumentSchedule() {
+	cfg := a.conf.DocumentScheduleConfig()
+	a.container.EnableDocumentSchedule(cfg)
+
+	a.logger.WithFields(logrus.Fields{
+		"queue":                cfg.Queue,
+		"draft_reminder_after": cfg.DraftReminderAfter.String(),
+	}).Info("Document send scheduling and draft reminders enabled")
+}
+
+// setupMail создает сервис писем. Без SMTP_HOST письма пишутся в лог.
+func (a *App) setupMail() error {
+	cfg := a.conf.MailConfig()
+	if _, err := a.container.EnableMail(cfg); err != nil {
+		return err
+	}
+
+	if !cfg.Enabled() {
+		a.logger.Warn("SMTP_HOST is not set, emails will be logged instead of sent")
+	}
+	return nil
+}
+
+// setupExports создает сервис фоновых выгрузок; файлы хранятся EXPORT_RETENTION
+func (a *App) setupExports() error {
+	cfg := a.conf.ExportConfig()
+	if _, err := a.container.EnableExports(cfg); err != nil {
+		return err
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"queue":     cfg.Queue,
+		"link_ttl":  cfg.LinkTTL.String(),
+		"retention": cfg.Retention.String(),
+	}).Info("Background exports enabled")
+	return nil
+}
+
+// setupTrash создает сервис корзины документов; удаленные документы хранятся TRASH_RETENTION
+func (a *App) setupTrash() {
+	cfg := a.conf.TrashConfig()
+	a.container.EnableTrash(cfg)
+
+	a.logger.WithFields(logrus.Fields{
+		"queue":           cfg.Queue,
+		"retention":       cfg.Retention.String(),
+		"async_threshold": cfg.AsyncThreshold,
+	}).Info("Document trash enabled")
+}
+
+// setupReadOnlyMode следит за основной БД: пока она недоступна, запись отклоняется с 503,
+// а GET отдают кешированные ответы. Регистрируется до маршрутов, чтобы охватить весь API.
+func (a *App) setupReadOnlyMode() {
+	if !a.conf.ReadOnlyModeEnabled() {
+		return
+	}
+
+	cfg := a.conf.ReadOnlyConfig()
+	mode := health.NewReadOnlyMode(a.healthChecker, cfg, a.logger)
+	a.container.Manage("read-only mode", mode)
+	a.fiber.Use(middleware.ReadOnlyMiddleware(mode))
+
+	a.logger.WithFields(logrus.Fields{
+		"interval":          cfg.Interval.String(),
+		"failure_threshold": cfg.FailureThreshold,
+	}).Info("Read-only mode on primary database failure enabled")
+}
+
+// setupFaultInjection включает внедрение сбоев по правилам FAULT_INJECTION_RULES. Регистрируется после
+// логирования и метрик, чтобы внедренные сбои были видны в них так же, как настоящие.
+func (a *App) setupFaultInjection() error {
+	cfg, err := a.conf.FaultInjectionConfig()
+	if err != nil {
+		return err
+	}
+	if !cfg.Enabled {
+		return nil
+	}
+	if a.conf.IsProduction() {
+		return fmt.Errorf("fault injection must not be enabled in production")
+	}
+
+	a.fiber.Use(middleware.FaultInjectionMiddleware(cfg, a.logger))
+
+	rules := make([]string, 0, len(cfg.Rules))
+	for _, rule := range cfg.Rules {
+		rules = append(rules, rule.String())
 	}
+	a.logger.WithFields(logrus.Fields{
+		"env":   a.conf.AppEnv(),
+		"rules": rules,
+	}).Warn("Fault injection enabled")
 	return nil
 }
 
+// setupTenantMonitor запускает проверку БД организаций группами по TENANT_HEALTH_SAMPLE_SIZE;
+// недоступные БД отмечаются в /health статусом DEGRADED
+func (a *App) setupTenantMonitor() {
+	if !a.conf.TenantHealthEnabled() {
+		a.logger.Info("Tenant database health monitoring disabled")
+		return
+	}
+
+	cfg := a.conf.TenantMonitorConfig()
+	monitor := a.container.EnableTenantMonitor(cfg)
+	a.container.Manage("tenant health", monitor)
+	a.healthChecker.Register(monitor.Probe())
+
+	a.logger.WithFields(logrus.Fields{
+		"interval":          cfg.Interval.String(),
+		"sample_size":       cfg.SampleSize,
+		"failure_threshold": cfg.FailureThreshold,
+	}).Info("Tenant database health monitoring enabled")
+}
+
+// setupTenantCredentials создает сервис ротации учетных данных БД организаций, запускаемой администратором
+func (a *App) setupTenantCredentials(pools services.OrganizationDBService) {
+	cfg := a.conf.TenantCredentialConfig()
+	a.container.EnableTenantCredentials(cfg, pools)
+
+	a.logger.WithField("queue", cfg.Queue).Info("Tenant credential rotation enabled")
+}
+
+// setupTenantMigrations создает сервис миграции БД организаций, запускаемой администратором
+func (a *App) setupTenantMigrations() {
+	cfg := a.conf.TenantMigrationConfig()
+	a.container.EnableTenantMigrations(cfg)
+
+	a.logger.WithFields(logrus.Fields{
+		"queue":       cfg.Queue,
+		"concurrency": cfg.Concurrency,
+	}).Info("Tenant migrations enabled")
+}
+
+// mockESFPrefix путь, по которому отдается имитация шлюза ЭСФ
+const mockESFPrefix = "/mock-esf"
+
+// setupESFGateway создает клиент шлюза ЭСФ. Имитация шлюза также отдается по HTTP на /mock-esf,
+// чтобы фронтенд и внешние тесты работали без учетных данных песочницы.
+func (a *App) setupESFGateway() error {
+	cfg := a.conf.ESFGatewayConfig()
+	cfg.Logger = a.logLevels.Module(logger.ModuleGateway)
+	gw, err := a.container.EnableESFGateway(cfg)
+	if err != nil {
+		return err
+	}
+
+	if failover, ok := gw.(*esfgateway.Failover); ok {
+		failover.OnSwitch(func(from, to esfgateway.Endpoint, reason string) {
+			a.logger.WithFields(logrus.Fields{"from": from.Name, "to": to.Name, "reason": reason}).Warn("ESF gateway endpoint switched")
+		})
+		go failover.Run(a.ctx, esfgateway.DefaultOverrideInterval)
+
+		// Без шлюза документы не отправляются в налоговую, но остальной API работает: статус DEGRADED
+		a.healthChecker.Register(health.Probe{Name: "ESF gateway", Run: failover.Check, Message: "Gateway reachable", Optional: true})
+
+		status := failover.Status()
+		a.logger.WithFields(logrus.Fields{
+			"active":    status.Active,
+			"endpoints": len(status.Endpoints),
+		}).Info("ESF gateway client initialized")
+		return nil
+	}
+
+	mock, ok := gw.(*esfgateway.Mock)
+	if !ok {
+		return nil
+	}
+
+	handler := http.StripPrefix(mockESFPrefix, esfgateway.NewMockHandler(mock))
+	a.fiber.All(mockESFPrefix+"/*", adaptor.HTTPHandler(handler))
+	a.logger.WithFields(logrus.Fields{
+		"path":       mockESFPrefix,
+		"latency":    cfg.Mock.Latency.String(),
+		"error_rate": cfg.Mock.ErrorRate,
+	}).Warn("ESF gateway mock enabled, documents are not sent to the tax service")
+	return nil
+}
+
+// grpcShutdownTimeout время на завершение текущих gRPC-вызовов при остановке
+const grpcShutdownTimeout = 10 * time.Second
+
+// setupGRPC создает внутренний gRPC API (DocumentService, OrganizationService) с авторизацией по JWT.
+// Сервер запускается в Run на GRPC_ADDR; без GRPC_ADDR API отключен.
+func (a *App) setupGRPC() error {
+	cfg := a.conf.GRPCConfig()
+	if cfg.Addr == "" {
+		a.logger.Info("gRPC API disabled (GRPC_ADDR is not set)")
+		return nil
+	}
+	keys, err := a.conf.JWTKeys()
+	if err != nil {
+		return err
+	}
+
+	a.grpcServer = grpcapi.NewServer(cfg, keys, a.container.GetEsfDocumentService(), a.container.GetEsfOrganizationService(), a.logger)
+	// Останавливается первым из компонентов, дожидаясь текущих вызовов не дольше grpcShutdownTimeout
+	a.container.Manage("grpc", lifecycle.Hook{OnStop: func(ctx context.Context) error {
+		ctx, cancel := context.WithTimeout(ctx, grpcShutdownTimeout)
+		defer cancel()
+		return grpcapi.GracefulStop(ctx, a.grpcServer)
+	}})
+	return nil
+}
+
+// startJobWorkers регистрирует воркеры фоновых задач в контейнере: они запускаются в Container.Start
+// и останавливаются до закрытия Redis. С JOBS_WORKERS_ENABLED=false инстанс только ставит задачи в очередь.
+func (a *App) startJobWorkers() {
+	manager := a.container.GetJobManager()
+	hook := lifecycle.Hook{OnStop: func(context.Context) error {
+		manager.Stop()
+		return nil
+	}}
+
+	if a.conf.JobWorkersEnabled() {
+		hook.OnStart = func(ctx context.Context) error {
+			manager.Start(ctx)
+			return nil
+		}
+	} else {
+		a.logger.Info("Job workers disabled, this instance only enqueues jobs")
+	}
+	a.container.Manage("jobs", hook)
+}
+
+// setupScheduler создает планировщик периодических задач; он работает только на избранном лидере.
+// С SCHEDULER_ENABLED=false инстанс не запускает задачи, даже став лидером.
+func (a *App) setupScheduler() error {
+	sched := a.container.EnableScheduler(a.conf.SchedulerConfig())
+
+	for _, task := range a.scheduledTasks() {
+		if err := sched.Add(task); err != nil {
+			return err
+		}
+	}
+
+	if !a.conf.SchedulerEnabled() {
+		a.logger.Info("Scheduler disabled on this instance")
+		return nil
+	}
+
+	a.container.GetLeaderElector().Add("scheduler", func(ctx context.Context) {
+		sched.Start(ctx)
+		<-ctx.Done()
+		sched.Stop()
+	})
+	return nil
+}
+
+// setupLeaderElection создает участника выборов лидера; работа в единственном экземпляре
+// добавляется в него при настройке и запускается, пока инстанс остается лидером
+func (a *App) setupLeaderElection() error {
+	cfg := a.conf.LeaderElectionConfig()
+	backend := a.conf.LeaderElectionBackend()
+	if _, err := a.container.EnableLeaderElection(cfg, backend == conf.LeaderBackendPostgres); err != nil {
+		return err
+	}
+
+	a.logger.WithFields(logrus.Fields{
+		"backend": backend,
+		"ttl":     cfg.TTL.String(),
+	}).Info("Leader election enabled for singleton background work")
+	return nil
+}
+
+// scheduledTasks перечисляет периодические задачи приложения. Архивация добавляется сюда по мере
+// появления сервиса; сброс квот, отложенная отправка документов и напоминания о черновиках —
+// отложенные задачи очереди (jobs.At), а не задачи планировщика.
+func (a *App) scheduledTasks() []scheduler.Task {
+	retention := a.conf.EventsConfig().Retention
+	lifecycle := a.conf.StorageConfig().Lifecycle
+
+	return []scheduler.Task{
+		{
+			// Опубликованные события хранятся EVENTS_RETENTION для повторной отправки
+			Name: "events.cleanup",
+			Spec: "30 3 * * *",
+			Run: func(ctx context.Context) error {
+				return a.container.GetEventRelay().PurgePublished(ctx, time.Now().Add(-retention))
+			},
+		},
+		{
+			// Удаление устаревших файлов по правилам STORAGE_LIFECYCLE
+			Name: "storage.lifecycle",
+			Spec: "0 4 * * *",
+			Run: func(ctx context.Context) error {
+				deleted, err := storage.ApplyLifecycle(ctx, a.container.GetStorage(), lifecycle, time.Now())
+				if deleted > 0 {
+					a.logger.WithField("deleted", deleted).Info("Expired storage objects removed")
+				}
+				return err
+			},
+		},
+		{
+			// Документы, пролежавшие в корзине дольше TRASH_RETENTION, удаляются окончательно
+			Name: "trash.purge",
+			Spec: "15 4 * * *",
+			Run: func(ctx context.Context) error {
+				_, err := a.container.GetDocumentTrashService().PurgeExpired(ctx)
+				return err
+			},
+		},
+		{
+			// Курсы НБКР публикуются раз в день; несколько попыток на случай задержки публикации
+			Name:    "rates.refresh",
+			Spec:    "0 */4 * * *",
+			Timeout: 2 * time.Minute,
+			Run:     a.container.GetExchangeRateService().Refresh,
+		},
+		{
+			// Официальные справочники ЭСФ из шлюза налоговой службы
+			Name: "reference.sync",
+			Spec: "0 5 * * *",
+			Run: func(ctx context.Context) error {
+				_, err := a.container.GetReferenceDataService().SyncDirectories(ctx)
+				return err
+			},
+		},
+	}
+}
+
+// Параметры прогрева кеша при запуске
+const (
+	cacheWarmingTimeout    = 30 * time.Second
+	cacheWarmingUsersLimit = 100
+	cacheWarmingLock       = "cache:warmup"
+	cacheWarmingLockTTL    = 15 * time.Second
+)
+
 // warmCache предварительно загружает часто используемые данные в кеш
 func (a *App) warmCache() {
 	if a.container == nil || a.container.GetCacheManager() == nil {
@@ -293,49 +1220,82 @@ func (a *App) warmCache() {
 
 	a.logger.Info("Starting cache warming...")
 
-	a.logger.Info("Cache warming completed")
+	runner := cache.NewWarmupRunner(a.logger, cacheWarmingTimeout).
+		WithDurationMetric(a.metrics.CacheWarmingDuration)
+	// Кеш общий для всех экземпляров: при одновременном запуске реплик его прогревает одна из них
+	if locker := a.container.GetLocker(); locker != nil {
+		runner.WithLock(locker, cacheWarmingLock, cacheWarmingLockTTL)
+	}
+
+	// Справочные данные и настройки организаций читаются чаще всего
+	runner.Register(
+		cache.NewWarmer("organizations", a.container.GetEsfOrganizationService().CacheWarmOrganizations),
+		cache.NewWarmer("reference_data", a.container.GetReferenceDataService().CacheWarmReferenceData),
+		cache.NewWarmer("users", func(ctx context.Context) error {
+			return a.container.GetUserService().CacheWarmUsers(ctx, cacheWarmingUsersLimit)
+		}),
+	)
+
+	// Ошибки прогрева не блокируют запуск: данные загрузятся в кеш при первом обращении
+	if err := runner.Run(context.Background()); err != nil {
+		a.logger.WithError(err).Warn("Cache warming finished with errors")
+	}
 }
 
-// ShutdownWithContext корректно завершает работу приложения с поддержкой контекста и таймаута
+// ShutdownWithContext корректно завершает работу приложения с поддержкой контекста и таймаута.
+// Сначала HTTP-сервер перестает принимать запросы, затем компоненты контейнера останавливаются
+// в обратном порядке регистрации: gRPC, планировщик, воркеры задач, учет потребления,
+// пулы БД организаций, реплики и последними Redis и основная БД.
 func (a *App) ShutdownWithContext(ctx context.Context) error {
-	// Закрываем Redis соединение
-	if a.redisClient != nil {
-		if err := a.redisClient.Close(); err != nil {
-			a.logger.WithError(err).Warn("Failed to close Redis connection")
+	var errs []error
+	if a.fiber != nil {
+		if err := a.fiber.ShutdownWithContext(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("shut down HTTP server: %w", err))
 		}
 	}
 
-	if a.fiber != nil {
-		return a.fiber.ShutdownWithContext(ctx)
+	if a.container != nil {
+		if err := a.container.Stop(ctx); err != nil {
+			errs = append(errs, err)
+		}
 	}
-	return nil
+	return errors.Join(errs...)
 }
 
-// connectToRedisWithRetry пытается подключиться к Redis с retry logic
-func (a *App) connectToRedisWithRetry(ctx context.Context, maxRetries int) error {
-	var lastErr error
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		a.logger.Infof("Attempting to connect to Redis (attempt %d/%d)...", attempt, maxRetries)
-
-		if err := a.redisClient.Ping(ctx).Err(); err != nil {
-			lastErr = err
-			a.logger.WithError(err).Warnf("Redis connection attempt %d failed, retrying in 2 seconds...", attempt)
-
-			if attempt < maxRetries {
-				select {
-				case <-time.After(2 * time.Second):
-					// Continue to next retry
-				case <-ctx.Done():
-					return ctx.Err()
-				}
-			}
-		} else {
-			// Connection successful
-			return nil
-		}
+// newRedisClient создает клиент Redis по REDIS_HOST и REDIS_PORT (по умолчанию 6379);
+// REDIS_HOST обязателен и проверяется в conf.Validate
+func newRedisClient(c *conf.Conf) *redis.Client {
+	return redis.NewClient(newRedisOptions(c))
+}
+
+func newRedisOptions(c *conf.Conf) *redis.Options {
+	redisHost := c.GetConValue("REDIS_HOST")
+	redisPort := c.GetConValue("REDIS_PORT")
+	if redisPort == "" {
+		redisPort = "6379"
+	}
+	return &redis.Options{
+		Addr: fmt.Sprintf("%s:%s", redisHost, redisPort),
 	}
+}
 
-	return fmt.Errorf("failed to connect to Redis after %d attempts: %w", maxRetries, lastErr)
+// connectToRedisWithRetry пытается подключиться к Redis с экспоненциальной задержкой от 2 секунд
+func (a *App) connectToRedisWithRetry(ctx context.Context, maxRetries int) error {
+	policy := retry.Policy{
+		Name:            "redis connect",
+		MaxAttempts:     maxRetries,
+		InitialInterval: 2 * time.Second,
+		MaxInterval:     10 * time.Second,
+		Jitter:          0.2,
+		Logger:          logger.New(a.logger),
+	}
+	err := retry.Do(ctx, policy, func(ctx context.Context) error {
+		return a.redisClient.Ping(ctx).Err()
+	})
+	if err != nil {
+		return fmt.Errorf("failed to connect to Redis after %d attempts: %w", maxRetries, err)
+	}
+	return nil
 }
 
 // responseWriter реализует интерфейс http.ResponseWriter для использования с prometheus
@@ -357,497 +1317,3 @@ func (rw *responseWriter) Write(data []byte) (int, error) {
 func (rw *responseWriter) WriteHeader(statusCode int) {
 	rw.statusCode = statusCode
 }
-
-// getSwaggerSchemas возвращает определения схем OpenAPI
-func getSwaggerSchemas() map[string]interface{} {
-	return map[string]interface{}{
-		"User": map[string]interface{}{
-			"type": "object",
-			"properties": map[string]interface{}{
-				"id":         map[string]string{"type": "string", "format": "uuid"},
-				"username":   map[string]string{"type": "string"},
-				"email":      map[string]string{"type": "string", "format": "email"},
-				"created_at": map[string]string{"type": "string", "format": "date-time"},
-			},
-		},
-		"RegisterRequest": map[string]interface{}{
-			"type":     "object",
-			"required": []string{"username", "email", "password"},
-			"properties": map[string]interface{}{
-				"username": map[string]string{"type": "string", "minLength": "3"},
-				"email":    map[string]string{"type": "string", "format": "email"},
-				"password": map[string]string{"type": "string", "minLength": "8"},
-			},
-		},
-		"LoginRequest": map[string]interface{}{
-			"type":     "object",
-			"required": []string{"email", "password"},
-			"properties": map[string]interface{}{
-				"email":    map[string]string{"type": "string", "format": "email"},
-				"password": map[string]string{"type": "string"},
-			},
-		},
-		"LoginResponse": map[string]interface{}{
-			"type": "object",
-			"properties": map[string]interface{}{
-				"token": map[string]string{"type": "string"},
-				"user": map[string]interface{}{
-					"$ref": "#/components/schemas/User",
-				},
-			},
-		},
-		"Organization": map[string]interface{}{
-			"type": "object",
-			"properties": map[string]interface{}{
-				"id":         map[string]string{"type": "string", "format": "uuid"},
-				"name":       map[string]string{"type": "string"},
-				"inn":        map[string]string{"type": "string"},
-				"kpp":        map[string]string{"type": "string"},
-				"director":   map[string]string{"type": "string"},
-				"created_at": map[string]string{"type": "string", "format": "date-time"},
-			},
-		},
-		"Document": map[string]interface{}{
-			"type": "object",
-			"properties": map[string]interface{}{
-				"id":              map[string]string{"type": "string", "format": "uuid"},
-				"number":          map[string]string{"type": "string"},
-				"status":          map[string]string{"type": "string", "enum": "draft|sent|received|processed"},
-				"amount":          map[string]string{"type": "number"},
-				"organization_id": map[string]string{"type": "string", "format": "uuid"},
-				"created_at":      map[string]string{"type": "string", "format": "date-time"},
-			},
-		},
-		"HealthStatus": map[string]interface{}{
-			"type": "object",
-			"properties": map[string]interface{}{
-				"status":    map[string]string{"type": "string"},
-				"timestamp": map[string]string{"type": "string", "format": "date-time"},
-				"uptime":    map[string]string{"type": "string"},
-				"components": map[string]interface{}{
-					"type": "array",
-					"items": map[string]interface{}{
-						"type": "object",
-						"properties": map[string]interface{}{
-							"name":          map[string]string{"type": "string"},
-							"status":        map[string]string{"type": "string"},
-							"response_time": map[string]string{"type": "string"},
-							"message":       map[string]string{"type": "string"},
-						},
-					},
-				},
-			},
-		},
-		"ErrorResponse": map[string]interface{}{
-			"type": "object",
-			"properties": map[string]interface{}{
-				"error":   map[string]string{"type": "string"},
-				"message": map[string]string{"type": "string"},
-			},
-		},
-	}
-}
-
-// getSwaggerPaths возвращает определения путей OpenAPI
-func getSwaggerPaths() map[string]interface{} {
-	return map[string]interface{}{
-		"/api/auth/register": map[string]interface{}{
-			"post": map[string]interface{}{
-				"tags":    []string{"Authentication"},
-				"summary": "Регистрация нового пользователя",
-				"requestBody": map[string]interface{}{
-					"required": true,
-					"content": map[string]interface{}{
-						"application/json": map[string]interface{}{
-							"schema": map[string]string{"$ref": "#/components/schemas/RegisterRequest"},
-						},
-					},
-				},
-				"responses": map[string]interface{}{
-					"201": map[string]interface{}{
-						"description": "Пользователь успешно зарегистрирован",
-					},
-					"400": map[string]interface{}{
-						"description": "Неверный ввод",
-					},
-					"429": map[string]interface{}{
-						"description": "Превышен лимит запросов",
-					},
-				},
-			},
-		},
-		"/api/auth/login": map[string]interface{}{
-			"post": map[string]interface{}{
-				"tags":    []string{"Authentication"},
-				"summary": "Вход пользователя",
-				"requestBody": map[string]interface{}{
-					"required": true,
-					"content": map[string]interface{}{
-						"application/json": map[string]interface{}{
-							"schema": map[string]string{"$ref": "#/components/schemas/LoginRequest"},
-						},
-					},
-				},
-				"responses": map[string]interface{}{
-					"200": map[string]interface{}{
-						"description": "Успешный вход",
-					},
-					"401": map[string]interface{}{
-						"description": "Неверные учётные данные",
-					},
-					"429": map[string]interface{}{
-						"description": "Превышен лимит запросов",
-					},
-				},
-			},
-		},
-		"/api/auth/logout": map[string]interface{}{
-			"post": map[string]interface{}{
-				"tags":    []string{"Authentication"},
-				"summary": "Выход пользователя",
-				"security": []map[string]interface{}{
-					{"BearerAuth": []string{}},
-				},
-				"responses": map[string]interface{}{
-					"200": map[string]interface{}{
-						"description": "Успешный выход",
-					},
-					"401": map[string]interface{}{
-						"description": "Отсутствует или неверный токен",
-					},
-				},
-			},
-		},
-		"/api/organizations": map[string]interface{}{
-			"get": map[string]interface{}{
-				"tags":        []string{"Organizations"},
-				"summary":     "Получить все организации",
-				"description": "Получить список всех организаций с пагинацией",
-				"security": []map[string]interface{}{
-					{"BearerAuth": []string{}},
-				},
-				"parameters": []map[string]interface{}{
-					{
-						"name":     "page",
-						"in":       "query",
-						"schema":   map[string]string{"type": "integer"},
-						"required": false,
-					},
-					{
-						"name":     "limit",
-						"in":       "query",
-						"schema":   map[string]string{"type": "integer"},
-						"required": false,
-					},
-				},
-				"responses": map[string]interface{}{
-					"200": map[string]interface{}{
-						"description": "Список организаций",
-					},
-					"401": map[string]interface{}{
-						"description": "Отсутствует или неверный токен",
-					},
-				},
-			},
-			"post": map[string]interface{}{
-				"tags":    []string{"Organizations"},
-				"summary": "Создать новую организацию",
-				"security": []map[string]interface{}{
-					{"BearerAuth": []string{}},
-				},
-				"requestBody": map[string]interface{}{
-					"required": true,
-					"content": map[string]interface{}{
-						"application/json": map[string]interface{}{
-							"schema": map[string]string{"$ref": "#/components/schemas/Organization"},
-						},
-					},
-				},
-				"responses": map[string]interface{}{
-					"201": map[string]interface{}{
-						"description": "Организация успешно создана",
-					},
-					"400": map[string]interface{}{
-						"description": "Неверные данные",
-					},
-					"401": map[string]interface{}{
-						"description": "Отсутствует или неверный токен",
-					},
-				},
-			},
-		},
-		"/api/organizations/{id}": map[string]interface{}{
-			"get": map[string]interface{}{
-				"tags":    []string{"Organizations"},
-				"summary": "Получить организацию по ID",
-				"security": []map[string]interface{}{
-					{"BearerAuth": []string{}},
-				},
-				"parameters": []map[string]interface{}{
-					{
-						"name":     "id",
-						"in":       "path",
-						"required": true,
-						"schema":   map[string]string{"type": "string", "format": "uuid"},
-					},
-				},
-				"responses": map[string]interface{}{
-					"200": map[string]interface{}{
-						"description": "Данные организации",
-					},
-					"404": map[string]interface{}{
-						"description": "Организация не найдена",
-					},
-					"401": map[string]interface{}{
-						"description": "Отсутствует или неверный токен",
-					},
-				},
-			},
-			"put": map[string]interface{}{
-				"tags":    []string{"Organizations"},
-				"summary": "Обновить организацию",
-				"security": []map[string]interface{}{
-					{"BearerAuth": []string{}},
-				},
-				"parameters": []map[string]interface{}{
-					{
-						"name":     "id",
-						"in":       "path",
-						"required": true,
-						"schema":   map[string]string{"type": "string", "format": "uuid"},
-					},
-				},
-				"requestBody": map[string]interface{}{
-					"required": true,
-					"content": map[string]interface{}{
-						"application/json": map[string]interface{}{
-							"schema": map[string]string{"$ref": "#/components/schemas/Organization"},
-						},
-					},
-				},
-				"responses": map[string]interface{}{
-					"200": map[string]interface{}{
-						"description": "Организация успешно обновлена",
-					},
-					"400": map[string]interface{}{
-						"description": "Неверные данные",
-					},
-					"404": map[string]interface{}{
-						"description": "Организация не найдена",
-					},
-					"401": map[string]interface{}{
-						"description": "Отсутствует или неверный токен",
-					},
-				},
-			},
-			"delete": map[string]interface{}{
-				"tags":    []string{"Organizations"},
-				"summary": "Удалить организацию",
-				"security": []map[string]interface{}{
-					{"BearerAuth": []string{}},
-				},
-				"parameters": []map[string]interface{}{
-					{
-						"name":     "id",
-						"in":       "path",
-						"required": true,
-						"schema":   map[string]string{"type": "string", "format": "uuid"},
-					},
-				},
-				"responses": map[string]interface{}{
-					"204": map[string]interface{}{
-						"description": "Организация успешно удалена",
-					},
-					"404": map[string]interface{}{
-						"description": "Организация не найдена",
-					},
-					"401": map[string]interface{}{
-						"description": "Отсутствует или неверный токен",
-					},
-				},
-			},
-		},
-		"/api/documents": map[string]interface{}{
-			"get": map[string]interface{}{
-				"tags":        []string{"Documents"},
-				"summary":     "Получить все ЭСФ документы",
-				"description": "Получить список всех электронных счётов-фактур с пагинацией и фильтрацией",
-				"security": []map[string]interface{}{
-					{"BearerAuth": []string{}},
-				},
-				"parameters": []map[string]interface{}{
-					{
-						"name":     "page",
-						"in":       "query",
-						"schema":   map[string]string{"type": "integer"},
-						"required": false,
-					},
-					{
-						"name":     "limit",
-						"in":       "query",
-						"schema":   map[string]string{"type": "integer"},
-						"required": false,
-					},
-					{
-						"name":        "status",
-						"in":          "query",
-						"schema":      map[string]string{"type": "string"},
-						"required":    false,
-						"description": "Фильтр по статусу (draft, sent, received, processed)",
-					},
-				},
-				"responses": map[string]interface{}{
-					"200": map[string]interface{}{
-						"description": "Список документов",
-					},
-					"401": map[string]interface{}{
-						"description": "Отсутствует или неверный токен",
-					},
-				},
-			},
-			"post": map[string]interface{}{
-				"tags":    []string{"Documents"},
-				"summary": "Создать новый ЭСФ документ",
-				"security": []map[string]interface{}{
-					{"BearerAuth": []string{}},
-				},
-				"requestBody": map[string]interface{}{
-					"required": true,
-					"content": map[string]interface{}{
-						"application/json": map[string]interface{}{
-							"schema": map[string]string{"$ref": "#/components/schemas/Document"},
-						},
-					},
-				},
-				"responses": map[string]interface{}{
-					"201": map[string]interface{}{
-						"description": "Документ успешно создан",
-					},
-					"400": map[string]interface{}{
-						"description": "Неверные данные",
-					},
-					"401": map[string]interface{}{
-						"description": "Отсутствует или неверный токен",
-					},
-				},
-			},
-		},
-		"/api/documents/{id}": map[string]interface{}{
-			"get": map[string]interface{}{
-				"tags":    []string{"Documents"},
-				"summary": "Получить ЭСФ документ по ID",
-				"security": []map[string]interface{}{
-					{"BearerAuth": []string{}},
-				},
-				"parameters": []map[string]interface{}{
-					{
-						"name":     "id",
-						"in":       "path",
-						"required": true,
-						"schema":   map[string]string{"type": "string", "format": "uuid"},
-					},
-				},
-				"responses": map[string]interface{}{
-					"200": map[string]interface{}{
-						"description": "Данные документа",
-					},
-					"404": map[string]interface{}{
-						"description": "Документ не найден",
-					},
-					"401": map[string]interface{}{
-						"description": "Отсутствует или неверный токен",
-					},
-				},
-			},
-			"put": map[string]interface{}{
-				"tags":    []string{"Documents"},
-				"summary": "Обновить ЭСФ документ",
-				"security": []map[string]interface{}{
-					{"BearerAuth": []string{}},
-				},
-				"parameters": []map[string]interface{}{
-					{
-						"name":     "id",
-						"in":       "path",
-						"required": true,
-						"schema":   map[string]string{"type": "string", "format": "uuid"},
-					},
-				},
-				"requestBody": map[string]interface{}{
-					"required": true,
-					"content": map[string]interface{}{
-						"application/json": map[string]interface{}{
-							"schema": map[string]string{"$ref": "#/components/schemas/Document"},
-						},
-					},
-				},
-				"responses": map[string]interface{}{
-					"200": map[string]interface{}{
-						"description": "Документ успешно обновлен",
-					},
-					"400": map[string]interface{}{
-						"description": "Неверные данные",
-					},
-					"404": map[string]interface{}{
-						"description": "Документ не найден",
-					},
-					"401": map[string]interface{}{
-						"description": "Отсутствует или неверный токен",
-					},
-				},
-			},
-			"delete": map[string]interface{}{
-				"tags":    []string{"Documents"},
-				"summary": "Удалить ЭСФ документ",
-				"security": []map[string]interface{}{
-					{"BearerAuth": []string{}},
-				},
-				"parameters": []map[string]interface{}{
-					{
-						"name":     "id",
-						"in":       "path",
-						"required": true,
-						"schema":   map[string]string{"type": "string", "format": "uuid"},
-					},
-				},
-				"responses": map[string]interface{}{
-					"204": map[string]interface{}{
-						"description": "Документ успешно удален",
-					},
-					"404": map[string]interface{}{
-						"description": "Документ не найден",
-					},
-					"401": map[string]interface{}{
-						"description": "Отсутствует или неверный токен",
-					},
-				},
-			},
-		},
-		"/health": map[string]interface{}{
-			"get": map[string]interface{}{
-				"tags":        []string{"System"},
-				"summary":     "Проверка здоровья системы",
-				"description": "Проверить состояние системы (PostgreSQL, Redis)",
-				"responses": map[string]interface{}{
-					"200": map[string]interface{}{
-						"description": "Системы работают",
-					},
-					"503": map[string]interface{}{
-						"description": "Система недоступна",
-					},
-				},
-			},
-		},
-		"/metrics": map[string]interface{}{
-			"get": map[string]interface{}{
-				"tags":    []string{"System"},
-				"summary": "Метрики Prometheus",
-				"responses": map[string]interface{}{
-					"200": map[string]interface{}{
-						"description": "Данные метрик",
-					},
-				},
-			},
-		},
-	}
-}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/pkg/testutil"
+)
+
+// Интеграционный тест всего приложения: PostgreSQL и Redis запускаются в контейнерах
+// (testcontainers-go), App собирается так же, как при запуске сервера.
+// Требует Docker, иначе пропускается; в режиме -short пропускается всегда.
+
+// appEnvelope ответ API в формате response.Envelope
+type appEnvelope struct {
+	Success bool            `json:"success"`
+	Data    json.RawMessage `json:"data"`
+	Error   *struct {
+		Code string `json:"code"`
+	} `json:"error"`
+}
+
+// appClient выполняет запросы к App без сетевого сокета
+type appClient struct {
+	t   *testing.T
+	app *App
+}
+
+// do отправляет запрос; body сериализуется в JSON. headers — пары ключ, значение.
+func (c *appClient) do(method, path string, body interface{}, headers ...string) (*http.Response, appEnvelope) {
+	c.t.Helper()
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		require.NoError(c.t, err)
+		reader = bytes.NewReader(payload)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	resp, err := c.app.fiber.Test(req, -1)
+	require.NoError(c.t, err)
+	defer resp.Body.Close()
+
+	var envelope appEnvelope
+	raw, err := io.ReadAll(resp.Body)
+	require.NoError(c.t, err)
+	if len(raw) > 0 {
+		require.NoError(c.t, json.Unmarshal(raw, &envelope), "response: %s", raw)
+	}
+	return resp, envelope
+}
+
+// startApp запускает контейнеры и собирает App с подключением к ним
+func startApp(t *testing.T) *appClient {
+	t.Helper()
+	infra := testutil.StartInfrastructure(t)
+
+	env := infra.Env()
+	env["APP_HOST"] = "127.0.0.1"
+	env["APP_PORT"] = "8080"
+	env["APP_ENV"] = "test"
+	env["JWT_SECRET"] = "integration-test-jwt-secret-0123456789"
+	env["LOG_FILE"] = ""
+	env["LOG_LEVEL"] = "warn"
+	for key, value := range env {
+		t.Setenv(key, value)
+	}
+	// Конфигурация берется из окружения, но NewConf требует существующий файл
+	envFile := filepath.Join(t.TempDir(), ".env")
+	require.NoError(t, os.WriteFile(envFile, nil, 0o600))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	app, err := NewApp(ctx, envFile)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		cancel()
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer shutdownCancel()
+		if err := app.ShutdownWithContext(shutdownCtx); err != nil {
+			t.Logf("shutdown failed: %v", err)
+		}
+	})
+	return &appClient{t: t, app: app}
+}
+
+func TestApp_Integration(t *testing.T) {
+	client := startApp(t)
+	var token string
+
+	t.Run("auth", func(t *testing.T) {
+		username := "it_" + uuid.NewString()[:8]
+		register := models.RegisterRequest{
+			Username: username, Email: username + "@example.com", FullName: "Integration Test",
+			Phone: "+996700000001", Password: "secret123", ConfirmPassword: "secret123",
+		}
+		resp, _ := client.do(http.MethodPost, "/api/auth/register", register)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+		resp, body := client.do(http.MethodPost, "/api/auth/login", models.LoginRequest{Username: username, Password: "wrong-password"})
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.False(t, body.Success)
+
+		resp, body = client.do(http.MethodPost, "/api/auth/login", models.LoginRequest{Username: username, Password: "secret123"})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var auth models.AuthResponse
+		require.NoError(t, json.Unmarshal(body.Data, &auth))
+		require.NotEmpty(t, auth.Token)
+		token = auth.Token
+
+		resp, _ = client.do(http.MethodGet, "/api/auth/me", nil)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+		resp, body = client.do(http.MethodGet, "/api/auth/me", nil, "Authorization", "Bearer "+token)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var me models.UserInfo
+		require.NoError(t, json.Unmarshal(body.Data, &me))
+		assert.Equal(t, username, me.Username)
+
+		// Токен после выхода попадает в черный список в Redis
+		resp, body = client.do(http.MethodPost, "/api/auth/login", models.LoginRequest{Username: username, Password: "secret123"})
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var second models.AuthResponse
+		require.NoError(t, json.Unmarshal(body.Data, &second))
+		resp, _ = client.do(http.MethodPost, "/api/auth/logout", nil, "Authorization", "Bearer "+second.Token)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		resp, _ = client.do(http.MethodGet, "/api/auth/me", nil, "Authorization", "Bearer "+second.Token)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	})
+	require.NotEmpty(t, token, "auth flow failed")
+	authHeader := "Bearer " + token
+
+	createOrganization := func(t *testing.T, name string) string {
+		t.Helper()
+		resp, body := client.do(http.MethodPost, "/api/esf-organizations", models.EsfOrganizationModel{Name: name}, "Authorization", authHeader)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var created struct {
+			ID string `json:"id"`
+		}
+		require.NoError(t, json.Unmarshal(body.Data, &created))
+		require.NotEmpty(t, created.ID)
+		return created.ID
+	}
+	listDocumentIDs := func(t *testing.T, orgID string) []string {
+		t.Helper()
+		resp, body := client.do(http.MethodGet, "/api/esf-documents/paginated?page_size=100", nil, "X-Org-Id", orgID, "Authorization", authHeader)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var docs []struct {
+			ID string `json:"id"`
+		}
+		require.NoError(t, json.Unmarshal(body.Data, &docs))
+		ids := make([]string, 0, len(docs))
+		for _, doc := range docs {
+			ids = append(ids, doc.ID)
+		}
+		return ids
+	}
+
+	orgA := createOrganization(t, "Integration A "+uuid.NewString()[:8])
+	orgB := createOrganization(t, "Integration B "+uuid.NewString()[:8])
+	doc := testutil.NewDocumentRequest()
+	var docID string
+
+	t.Run("document CRUD", func(t *testing.T) {
+		resp, body := client.do(http.MethodPost, "/api/esf-documents", doc, "X-Org-Id", orgA, "Authorization", authHeader)
+		require.Equal(t, http.StatusCreated, resp.StatusCode)
+		var created models.EsfCreateDocumentResponse
+		require.NoError(t, json.Unmarshal(body.Data, &created))
+		docID = created.DocumentUuid
+		require.NotEmpty(t, docID)
+
+		resp, body = client.do(http.MethodGet, "/api/esf-documents/"+docID, nil, "X-Org-Id", orgA)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var fetched models.EsfCreateDocumentRequest
+		require.NoError(t, json.Unmarshal(body.Data, &fetched))
+		assert.Equal(t, doc.ContractorTin, fetched.ContractorTin)
+		assert.Len(t, fetched.CatalogEntries, len(doc.CatalogEntries))
+		etag := resp.Header.Get("ETag")
+		require.NotEmpty(t, etag)
+
+		// Изменение требует текущую версию (If-Match) и сбрасывает кеш документа
+		update := models.EsfEditDocumentRequest{ID: uuid.MustParse(docID), EsfCreateDocumentRequest: *doc}
+		update.Comment = "Updated by integration test"
+		resp, _ = client.do(http.MethodPut, "/api/esf-documents/"+docID, update, "X-Org-Id", orgA, "Authorization", authHeader)
+		assert.Equal(t, http.StatusPreconditionRequired, resp.StatusCode)
+		resp, _ = client.do(http.MethodPut, "/api/esf-documents/"+docID, update, "X-Org-Id", orgA, "Authorization", authHeader, "If-Match", etag)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp, body = client.do(http.MethodGet, "/api/esf-documents/"+docID, nil, "X-Org-Id", orgA)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.NoError(t, json.Unmarshal(body.Data, &fetched))
+		assert.Equal(t, "Updated by integration test", fetched.Comment)
+		assert.NotEqual(t, etag, resp.Header.Get("ETag"))
+
+		assert.Contains(t, listDocumentIDs(t, orgA), docID)
+	})
+	require.NotEmpty(t, docID, "document flow failed")
+
+	t.Run("tenancy isolation", func(t *testing.T) {
+		// Документ организации A хранится в ее БД и не виден из организации B, даже если он уже в кеше
+		resp, body := client.do(http.MethodGet, "/api/esf-documents/"+docID, nil, "X-Org-Id", orgB)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		require.NotNil(t, body.Error)
+		assert.Equal(t, "DOCUMENT_NOT_FOUND", body.Error.Code)
+		assert.NotContains(t, listDocumentIDs(t, orgB), docID)
+
+		update := models.EsfEditDocumentRequest{ID: uuid.MustParse(docID), EsfCreateDocumentRequest: *doc}
+		resp, _ = client.do(http.MethodPut, "/api/esf-documents/"+docID, update, "X-Org-Id", orgB, "Authorization", authHeader, "If-Match", `"2"`)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		resp, _ = client.do(http.MethodDelete, "/api/esf-documents/"+docID, nil, "X-Org-Id", orgB, "Authorization", authHeader)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+		// Документ в организации A не изменился
+		resp, _ = client.do(http.MethodGet, "/api/esf-documents/"+docID, nil, "X-Org-Id", orgA)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("document delete", func(t *testing.T) {
+		resp, _ := client.do(http.MethodDelete, "/api/esf-documents/"+docID, nil, "X-Org-Id", orgA, "Authorization", authHeader)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+
+		resp, _ = client.do(http.MethodGet, "/api/esf-documents/"+docID, nil, "X-Org-Id", orgA)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+		assert.NotContains(t, listDocumentIDs(t, orgA), docID)
+	})
+}
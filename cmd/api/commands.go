@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/conf"
+	"github.com/rusgainew/tunduck-app/internal/seed"
+	"github.com/rusgainew/tunduck-app/pkg/container"
+	"github.com/rusgainew/tunduck-app/pkg/metrics"
+	"github.com/rusgainew/tunduck-app/pkg/migrations"
+)
+
+// commands служебные подкоманды
+var commands = map[string]func(ctx context.Context, args []string) error{
+	"seed":          runSeed,
+	"migrate":       runMigrate,
+	"openapi":       func(_ context.Context, args []string) error { return runOpenAPI(args) },
+	"sdk":           func(_ context.Context, args []string) error { return runSDK(args) },
+	"loadtest":      runLoadTest,
+	"shadow-replay": runShadowReplay,
+	"doctor":        runDoctor,
+	"create-admin":  runCreateAdmin,
+}
+
+// runCommand выполняет служебную подкоманду вместо запуска сервера. Итог команды отправляется
+// в Prometheus Pushgateway (PUSHGATEWAY_URL), чтобы запуски из cron были видны без опроса.
+func runCommand(ctx context.Context, name string, args []string) error {
+	run, ok := commands[name]
+	if !ok {
+		return fmt.Errorf("unknown command %q (available: seed, migrate, openapi, sdk, loadtest, shadow-replay, doctor, create-admin)", name)
+	}
+
+	job := metrics.NewBatchJob(commandJobName(name))
+	err := run(metrics.WithBatchJob(ctx, job), args)
+	pushCommandMetrics(ctx, job, err)
+	return err
+}
+
+// commandJobName метка job команды в Pushgateway
+func commandJobName(name string) string {
+	return "tunduck_" + strings.ReplaceAll(name, "-", "_")
+}
+
+// pushCommandMetrics отправляет метрики команды; PUSHGATEWAY_URL читается после выполнения команды,
+// поэтому он может быть задан и в ее файле -env. Ошибка отправки не меняет результат команды.
+func pushCommandMetrics(ctx context.Context, job *metrics.BatchJob, err error) {
+	log := logrus.New()
+	log.SetOutput(os.Stderr)
+
+	cfg := conf.EnvConf(log).PushgatewayConfig()
+	if !cfg.Enabled() {
+		return
+	}
+	if pushErr := job.Push(ctx, cfg, err); pushErr != nil {
+		log.WithError(pushErr).Warn("Failed to push command metrics")
+	}
+}
+
+// runSeed заполняет БД демо-данными: go run ./cmd/api seed [-orgs 2] [-documents 30]
+func runSeed(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	orgs := fs.Int("orgs", seed.DefaultOrganizations, "number of demo organizations")
+	documents := fs.Int("documents", seed.DefaultDocuments, "documents per new organization")
+	password := fs.String("password", seed.DefaultPassword, "password of the demo users")
+	randSeed := fs.Int64("rand-seed", seed.DefaultRandSeed, "random seed; the same seed produces the same documents")
+	envPath := fs.String("env", ".env", "path to the environment file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	log := logrus.New()
+	log.SetOutput(os.Stdout)
+
+	cfg := conf.NewConf(log, *envPath)
+	if err := setupFieldEncryption(cfg, log); err != nil {
+		return fmt.Errorf("failed to set up field encryption: %w", err)
+	}
+	db := cfg.DBConnect()
+	if _, err := migrations.Main().Up(ctx, db); err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	redisClient := newRedisClient(cfg)
+	defer redisClient.Close()
+	if err := redisClient.Ping(ctx).Err(); err != nil {
+		log.WithError(err).Warn("Redis is not available, cache will not be invalidated")
+	}
+
+	cnt := container.NewContainer(container.WithDatabase(db), container.WithLogger(log), container.WithRedis(redisClient))
+	seeder := seed.New(cnt.GetUserRepository(), cnt.GetEsfOrganizationService(), cnt.GetEsfDocumentService(), log)
+
+	summary, err := seeder.Run(ctx, seed.Options{
+		Organizations: *orgs,
+		Documents:     *documents,
+		Password:      *password,
+		RandSeed:      *randSeed,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Seeded %d users, %d organizations, %d documents (%d paid, %d in trash); %d existing records skipped\n",
+		summary.Users, summary.Organizations, summary.Documents, summary.Paid, summary.Deleted, summary.Skipped)
+	return nil
+}
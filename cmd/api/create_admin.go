@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/bootstrap"
+	"github.com/rusgainew/tunduck-app/internal/conf"
+	"github.com/rusgainew/tunduck-app/pkg/container"
+	"github.com/rusgainew/tunduck-app/pkg/migrations"
+)
+
+// runCreateAdmin создает первого администратора новой установки без интерактивного ввода:
+// ADMIN_PASSWORD=... go run ./cmd/api create-admin -username admin -email admin@example.kg [-reset]
+// Пустые флаги берутся из ADMIN_USERNAME, ADMIN_EMAIL, ADMIN_PASSWORD, ADMIN_FULL_NAME и ADMIN_PHONE
+// (окружение или -env), поэтому пароль не попадает в историю команд и список процессов.
+func runCreateAdmin(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("create-admin", flag.ContinueOnError)
+	username := fs.String("username", "", "username (ADMIN_USERNAME, default "+bootstrap.DefaultAdminUsername+")")
+	email := fs.String("email", "", "email, required for a new user (ADMIN_EMAIL)")
+	password := fs.String("password", "", "password; prefer ADMIN_PASSWORD")
+	fullName := fs.String("full-name", "", "full name (ADMIN_FULL_NAME, default "+bootstrap.DefaultAdminFullName+")")
+	phone := fs.String("phone", "", "phone (ADMIN_PHONE)")
+	reset := fs.Bool("reset", false, "if the user exists, set its password and make it an active admin")
+	envPath := fs.String("env", ".env", "path to the environment file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	log := logrus.New()
+	log.SetOutput(os.Stderr)
+
+	cfg := conf.NewConf(log, *envPath)
+	opts := bootstrap.AdminOptions{
+		Username: valueOrEnv(cfg, *username, "ADMIN_USERNAME"),
+		Email:    valueOrEnv(cfg, *email, "ADMIN_EMAIL"),
+		Password: valueOrEnv(cfg, *password, "ADMIN_PASSWORD"),
+		FullName: valueOrEnv(cfg, *fullName, "ADMIN_FULL_NAME"),
+		Phone:    valueOrEnv(cfg, *phone, "ADMIN_PHONE"),
+		Reset:    *reset,
+	}
+	if opts.Username == "" {
+		opts.Username = bootstrap.DefaultAdminUsername
+	}
+	// Параметры проверяются до подключения к БД и миграций
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+
+	if err := setupFieldEncryption(cfg, log); err != nil {
+		return fmt.Errorf("failed to set up field encryption: %w", err)
+	}
+	db := cfg.DBConnect()
+	if _, err := migrations.Main().Up(ctx, db); err != nil {
+		return fmt.Errorf("failed to run migrations: %w", err)
+	}
+
+	redisClient := newRedisClient(cfg)
+	defer redisClient.Close()
+	if err := redisClient.Ping(ctx).Err(); err != nil {
+		log.WithError(err).Warn("Redis is not available, cached logins of an existing user are not cleared")
+	}
+
+	cnt := container.NewContainer(container.WithDatabase(db), container.WithLogger(log), container.WithRedis(redisClient))
+	result, err := bootstrap.CreateAdmin(ctx, cnt.GetUserRepository(), cnt.GetCacheManager(), opts)
+	if err != nil {
+		return err
+	}
+
+	if result.Created {
+		fmt.Printf("Created admin %q (%s), id %s\n", result.User.Username, result.User.Email, result.User.ID)
+	} else {
+		fmt.Printf("Reset admin %q (%s), id %s\n", result.User.Username, result.User.Email, result.User.ID)
+	}
+	return nil
+}
+
+// valueOrEnv возвращает значение флага, а если он не задан — переменную окружения key
+func valueOrEnv(cfg *conf.Conf, value, key string) string {
+	if value != "" {
+		return value
+	}
+	return cfg.GetConValue(key)
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/swagger"
+
+	"github.com/rusgainew/tunduck-app/internal/conf"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+)
+
+// docsCookie cookie с JWT администратора для Swagger UI: браузер не передает заголовок
+// Authorization при загрузке страницы и ее ресурсов
+const docsCookie = "docs_token"
+
+// docsCookieTTL время жизни cookie документации
+const docsCookieTTL = time.Hour
+
+// docsMode режим доступа к документации; без конфигурации (выгрузка спецификации) документация открыта
+func (a *App) docsMode() conf.DocsMode {
+	if a.conf == nil {
+		return conf.DocsModeOpen
+	}
+	return a.conf.DocsMode()
+}
+
+// registerDocsRoutes регистрирует Swagger UI, /docs и /swagger/doc.json.
+// В режиме admin токен принимается из заголовка, параметра token или cookie:
+// вход выполняется по ссылке /docs?token=<JWT>.
+func (a *App) registerDocsRoutes() {
+	mode := a.docsMode()
+	if mode == conf.DocsModeDisabled {
+		a.logger.Info("API documentation is disabled")
+		return
+	}
+
+	var guard []fiber.Handler
+	if mode == conf.DocsModeAdmin {
+		guard = []fiber.Handler{
+			middleware.JWTLookupMiddleware("header:" + fiber.HeaderAuthorization + ",query:token,cookie:" + docsCookie),
+			rbac.RequireAdminRole(),
+		}
+		a.logger.Info("API documentation is restricted to administrators")
+	}
+	route := func(path string, handler fiber.Handler) {
+		a.fiber.Get(path, append(append([]fiber.Handler{}, guard...), handler)...)
+	}
+
+	// Спецификация OpenAPI строится по зарегистрированным маршрутам
+	route("/swagger/doc.json", a.serveOpenAPI)
+
+	// Регистрируем Swagger UI endpoint
+	// Используем gofiber/swagger для интеграции с Fiber
+	route("/swagger/*", swagger.HandlerDefault)
+
+	// Регистрируем API документацию редирект
+	route("/docs", func(c *fiber.Ctx) error {
+		if token := c.Query("token"); token != "" && mode == conf.DocsModeAdmin {
+			c.Cookie(&fiber.Cookie{
+				Name:     docsCookie,
+				Value:    token,
+				Path:     "/swagger",
+				Expires:  time.Now().Add(docsCookieTTL),
+				HTTPOnly: true,
+				Secure:   c.Protocol() == "https",
+				SameSite: fiber.CookieSameSiteStrictMode,
+			})
+		}
+		return c.Redirect("/swagger/index.html")
+	})
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/conf"
+	"github.com/rusgainew/tunduck-app/internal/doctor"
+)
+
+// runDoctor проверяет доступность PostgreSQL, Redis, хранилища, SMTP и шлюза ЭСФ с текущей
+// конфигурацией и печатает таблицу результатов: go run ./cmd/api doctor [-env .env] [-timeout 10s]
+func runDoctor(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	envPath := fs.String("env", ".env", "path to the environment file")
+	timeout := fs.Duration("timeout", doctor.DefaultTimeout, "timeout of one check")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// Предупреждения конфигурации не смешиваются с таблицей
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	cfg, err := conf.LoadConf(log, *envPath)
+	var invalid *conf.ValidationError
+	if err != nil && !errors.As(err, &invalid) {
+		return fmt.Errorf("failed to load %s: %w", *envPath, err)
+	}
+
+	redis.SetLogger(discardRedisLogger{})
+
+	checks := []doctor.Check{
+		{Name: "config", Run: func(context.Context) (string, error) {
+			if invalid == nil {
+				return *envPath, nil
+			}
+			problems := make([]string, 0, len(invalid.Problems))
+			for _, p := range invalid.Problems {
+				problems = append(problems, p.Key+": "+p.Message)
+			}
+			return "", errors.New(strings.Join(problems, "; "))
+		}},
+		doctor.Postgres(cfg.DatabaseDSN()),
+		doctor.Redis(newRedisOptions(cfg)),
+		doctor.Storage(cfg.StorageConfig()),
+		doctor.SMTP(cfg.MailConfig()),
+	}
+	checks = append(checks, doctor.ESFGateway(cfg.ESFGatewayConfig())...)
+
+	results := doctor.Run(ctx, checks, *timeout)
+	doctor.Write(os.Stdout, results)
+	if failed := doctor.Failed(results); failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", failed, len(results))
+	}
+	return nil
+}
+
+// discardRedisLogger скрывает сообщения go-redis о неудачных подключениях: ошибка выводится в таблице
+type discardRedisLogger struct{}
+
+func (discardRedisLogger) Printf(context.Context, string, ...interface{}) {}
@@ -5,6 +5,7 @@ import (
 	"github.com/rusgainew/tunduck-app/internal/controllers"
 	"github.com/rusgainew/tunduck-app/internal/services"
 	"github.com/rusgainew/tunduck-app/pkg/container"
+	"github.com/rusgainew/tunduck-app/pkg/esfgateway"
 	"github.com/rusgainew/tunduck-app/pkg/middleware"
 )
 
@@ -17,9 +18,53 @@ func RegisterHandlers(app *fiber.App, cnt *container.Container, orgDBService ser
 	// Инициализируем контроллеры с зависимостями из контейнера
 	// Передаем сервисы из контейнера вместо их создания в контроллерах
 	controllers.NewAuthController(app, cnt.GetUserService(), logger, cnt.GetCacheManager())
-	controllers.NewEsfDocumentController(app, cnt.GetLogrus(), cnt.GetDatabase())
-	controllers.NewEsfOrganizationController(app, cnt.GetLogrus(), cnt.GetDatabase())
+	// Корзина регистрируется до контроллера документов: иначе /api/esf-documents/trash совпадет с /:id
+	controllers.NewDocumentTrashController(app, logger, cnt.GetDocumentTrashService())
+	controllers.NewEsfDocumentController(app, cnt.GetLogrus(), cnt.GetEsfDocumentService(), cnt.GetDelegationService(), cnt.GetSavedViewService(), cnt.GetQuotaEnforcer())
+	if schedule := cnt.GetDocumentScheduleService(); schedule != nil {
+		controllers.NewDocumentScheduleController(app, logger, schedule, cnt.GetDelegationService())
+	}
+	controllers.NewEsfOrganizationController(app, cnt.GetLogrus(), cnt.GetDatabase(), cnt.GetResponseCache())
 	controllers.NewUserController(app, cnt.GetLogrus(), cnt.GetDatabase())
+	controllers.NewExportController(app, logger, cnt.GetExportService())
+	controllers.NewReportController(app, logger, cnt.GetExportService())
+	controllers.NewAttachmentController(app, logger, cnt.GetAttachmentService(), cnt.GetQuotaEnforcer())
+	controllers.NewBankStatementController(app, logger, cnt.GetBankStatementService())
+	controllers.NewContractController(app, logger, cnt.GetContractService())
+	controllers.NewPriceListController(app, logger, cnt.GetPriceListService())
+	controllers.NewReferenceDataController(app, logger, cnt.GetReferenceDataService(), cnt.GetResponseCache())
+	controllers.NewExchangeRateController(app, logger, cnt.GetExchangeRateService(), cnt.GetResponseCache())
+	controllers.NewDelegationController(app, logger, cnt.GetDelegationService())
+	controllers.NewSavedViewController(app, logger, cnt.GetSavedViewService())
+	controllers.NewOperationController(app, logger, cnt.GetOperationService())
+	controllers.NewCallbackController(app, logger, cnt.GetCallbackService())
+	controllers.NewUsageController(app, logger, cnt.GetUsageService())
+	controllers.NewFeatureFlagController(app, logger, cnt.GetFeatureFlags())
+	controllers.NewLogLevelController(app, logger, cnt.GetLogLevelSync())
+	controllers.NewTenantHealthController(app, logger, cnt.GetTenantMonitor())
+	controllers.NewGraphQLController(app, logger, cnt.GetDatabase(), cnt.GetEsfOrganizationService(), cnt.GetEsfDocumentService())
+	if gateway, ok := cnt.GetESFGateway().(*esfgateway.Failover); ok {
+		controllers.NewEsfGatewayController(app, logger, gateway)
+	}
+	if hub := cnt.GetRealtimeHub(); hub != nil {
+		controllers.NewRealtimeController(app, logger, hub, cnt.GetEsfOrganizationService(), cnt.GetWebSocketConfig())
+	}
+	controllers.NewAdminController(
+		app,
+		logger,
+		cnt.GetMigrationService(),
+		cnt.GetUserService(),
+		cnt.GetEsfOrganizationService(),
+		cnt.GetEsfDocumentService(),
+		cnt.GetJobManager(),
+		cnt.GetEmailService(),
+		cnt.GetAuditService(),
+		cnt.GetDeadLetterService(),
+		cnt.GetEventReplayService(),
+		cnt.GetQuotaEnforcer(),
+		cnt.GetTenantMigrationService(),
+		cnt.GetTenantCredentialService(),
+	)
 
 	// Применяем Rate Limiting для публичных endpoints (регистрация, логин)
 	// Эти routes переопределяются в auth_controller.go
@@ -30,6 +75,8 @@ func RegisterHandlers(app *fiber.App, cnt *container.Container, orgDBService ser
 	// Применяем Rate Limiting для health и metrics endpoints (более высокий лимит)
 	app.Use("/health", middleware.RateLimitMiddleware(rateLimiter, "health", logger))
 	app.Use("/metrics", middleware.RateLimitMiddleware(rateLimiter, "metrics", logger))
+	app.Use("/version", middleware.RateLimitMiddleware(rateLimiter, "health", logger))
+	app.Use("/status", middleware.RateLimitMiddleware(rateLimiter, "health", logger))
 
 	// Применяем Rate Limiting для sensitive endpoints (logout)
 	// Используем auth middleware для определения пользователя
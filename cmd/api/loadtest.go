@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/internal/loadtest"
+	"github.com/rusgainew/tunduck-app/internal/seed"
+)
+
+// runLoadTest нагружает развернутое окружение и печатает перцентили задержек:
+// go run ./cmd/api loadtest -url https://staging.example.com -org <id> [-rps 50] [-duration 1m]
+func runLoadTest(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("loadtest", flag.ContinueOnError)
+	baseURL := fs.String("url", "http://localhost:8080", "base URL of the target environment")
+	username := fs.String("user", "admin", "username; sending documents requires the signing permission")
+	password := fs.String("password", seed.DefaultPassword, "password")
+	orgID := fs.String("org", "", "organization ID used for documents (required)")
+	rps := fs.Int("rps", loadtest.DefaultRPS, "requests per second")
+	duration := fs.Duration("duration", loadtest.DefaultDuration, "test duration")
+	workers := fs.Int("workers", loadtest.DefaultWorkers, "maximum concurrent requests")
+	mix := fs.String("mix", "login=5,create=25,list=60,send=10", "operation weights")
+	timeout := fs.Duration("timeout", loadtest.DefaultTimeout, "timeout of one request")
+	randSeed := fs.Int64("rand-seed", seed.DefaultRandSeed, "random seed of operations and documents")
+	maxErrorRate := fs.Float64("max-error-rate", 1, "fail if the share of failed requests is higher (0-1)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	org, err := uuid.Parse(*orgID)
+	if err != nil {
+		return fmt.Errorf("invalid -org %q: %w", *orgID, err)
+	}
+	weights, err := loadtest.ParseMix(*mix)
+	if err != nil {
+		return err
+	}
+
+	runner, err := loadtest.New(loadtest.Config{
+		BaseURL:        *baseURL,
+		Username:       *username,
+		Password:       *password,
+		OrganizationID: org,
+		RPS:            *rps,
+		Duration:       *duration,
+		Workers:        *workers,
+		Mix:            weights,
+		Timeout:        *timeout,
+		RandSeed:       *randSeed,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Ctrl+C завершает тест досрочно, отчет печатается по выполненным запросам
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(os.Stderr, "Load testing %s at %d rps for %s...\n", *baseURL, *rps, *duration)
+	report, err := runner.Run(ctx)
+	if err != nil {
+		return err
+	}
+	if err := report.Write(os.Stdout); err != nil {
+		return err
+	}
+	if report.ErrorRate() > *maxErrorRate {
+		return fmt.Errorf("error rate %.2f%% exceeds %.2f%%", report.ErrorRate()*100, *maxErrorRate*100)
+	}
+	return nil
+}
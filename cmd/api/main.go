@@ -15,6 +15,14 @@ func main() {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// Служебные подкоманды (например, "seed") выполняются вместо запуска сервера
+	if len(os.Args) > 1 {
+		if err := runCommand(ctx, os.Args[1], os.Args[2:]); err != nil {
+			log.Fatalf("Ошибка выполнения команды %s: %v", os.Args[1], err)
+		}
+		return
+	}
+
 	// Создаем и инициализируем приложение с контекстом
 	app, err := NewApp(ctx, ".env")
 	if err != nil {
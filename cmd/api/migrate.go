@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/conf"
+	"github.com/rusgainew/tunduck-app/pkg/metrics"
+	"github.com/rusgainew/tunduck-app/pkg/migrations"
+)
+
+// runMigrate применяет миграции основной БД без запуска сервера, например перед выкатом:
+// go run ./cmd/api migrate [-dry-run] [-env .env]
+func runMigrate(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	dryRun := fs.Bool("dry-run", false, "print the SQL of pending migrations without applying them")
+	envPath := fs.String("env", ".env", "path to the environment file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	log := logrus.New()
+	log.SetOutput(os.Stderr)
+
+	cfg := conf.NewConf(log, *envPath)
+	if err := setupFieldEncryption(cfg, log); err != nil {
+		return fmt.Errorf("failed to set up field encryption: %w", err)
+	}
+	db := cfg.DBConnect()
+
+	if *dryRun {
+		plans, err := migrations.Main().DryRun(ctx, db)
+		if err != nil {
+			return err
+		}
+		for _, plan := range plans {
+			fmt.Printf("-- %s %s\n", plan.Version, plan.Description)
+			for _, statement := range plan.SQL {
+				fmt.Printf("%s;\n", statement)
+			}
+		}
+		fmt.Printf("%d pending migrations\n", len(plans))
+		return nil
+	}
+
+	applied, err := migrations.Main().Up(ctx, db)
+	if job := metrics.BatchJobFromContext(ctx); job != nil {
+		gauge := prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "migrations_applied",
+			Help: "Number of migrations applied by the last migrate run",
+		})
+		gauge.Set(float64(len(applied)))
+		job.Registerer().MustRegister(gauge)
+	}
+	if err != nil {
+		return err
+	}
+
+	for _, version := range applied {
+		fmt.Println("Applied", version)
+	}
+	fmt.Printf("%d migrations applied\n", len(applied))
+	return nil
+}
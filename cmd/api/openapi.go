@@ -0,0 +1,178 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"github.com/rusgainew/tunduck-app/internal/controllers"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/buildinfo"
+	"github.com/rusgainew/tunduck-app/pkg/container"
+	"github.com/rusgainew/tunduck-app/pkg/esfgateway"
+	"github.com/rusgainew/tunduck-app/pkg/health"
+	"github.com/rusgainew/tunduck-app/pkg/openapi"
+	"github.com/rusgainew/tunduck-app/pkg/realtime"
+)
+
+// openAPIInfo общие сведения об API для спецификации
+var openAPIInfo = openapi.Info{
+	Title:       "Tunduc API System",
+	Description: "Enterprise API для управления ЭСФ документами, организациями и пользователями",
+	Version:     "1.0.0",
+	Servers: []openapi.Server{
+		{URL: "http://localhost:8080", Description: "Development"},
+		{URL: "https://api.example.com", Description: "Production"},
+	},
+}
+
+// openAPIRegistry описания маршрутов контроллеров и служебных маршрутов cmd/api
+func openAPIRegistry() *openapi.Registry {
+	reg := openapi.NewRegistry()
+	controllers.DescribeRoutes(reg)
+	describeSDKRoutes(reg)
+
+	system := []string{"System"}
+	reg.Add(fiber.MethodGet, "/health", openapi.Operation{
+		Tags: system, Summary: "Проверка здоровья системы (PostgreSQL, Redis)",
+		Response: health.HealthCheck{}, Raw: true,
+	})
+	reg.Add(fiber.MethodGet, "/status", openapi.Operation{
+		Tags: system, Summary: "Публичная сводка состояния: время работы, компоненты и недавние инциденты",
+		Response: health.StatusSummary{}, Raw: true,
+	})
+	reg.Add(fiber.MethodGet, "/version", openapi.Operation{
+		Tags: system, Summary: "Версия сборки: коммит, время сборки, версия Go и включенные функции",
+		Response: buildinfo.Info{}, Raw: true,
+	})
+	reg.Add(fiber.MethodGet, "/metrics", openapi.Operation{
+		Tags: system, Summary: "Метрики Prometheus", ContentType: fiber.MIMETextPlainCharsetUTF8,
+	})
+	reg.Add(fiber.MethodGet, "/swagger/doc.json", openapi.Operation{
+		Tags: system, Summary: "Спецификация OpenAPI", ContentType: fiber.MIMEApplicationJSON,
+	})
+	reg.Add(fiber.MethodGet, "/docs", openapi.Operation{
+		Tags: system, Summary: "Переход к Swagger UI", Status: fiber.StatusFound,
+	})
+	return reg
+}
+
+// buildOpenAPI формирует спецификацию по маршрутам приложения
+func buildOpenAPI(routes []fiber.Route) *openapi.Document {
+	return openapi.Build(openAPIInfo, routes, openAPIRegistry())
+}
+
+var (
+	openAPIOnce sync.Once
+	openAPIDoc  *openapi.Document
+	openAPISpec []byte
+	openAPIErr  error
+)
+
+// openAPIDocument строит спецификацию при первом обращении, когда все маршруты уже зарегистрированы
+func (a *App) openAPIDocument() (*openapi.Document, []byte, error) {
+	openAPIOnce.Do(func() {
+		openAPIDoc = buildOpenAPI(a.fiber.GetRoutes(true))
+		openAPISpec, openAPIErr = json.Marshal(openAPIDoc)
+	})
+	return openAPIDoc, openAPISpec, openAPIErr
+}
+
+// serveOpenAPI отдает спецификацию
+func (a *App) serveOpenAPI(c *fiber.Ctx) error {
+	_, spec, err := a.openAPIDocument()
+	if err != nil {
+		return fmt.Errorf("failed to build OpenAPI spec: %w", err)
+	}
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Send(spec)
+}
+
+// runOpenAPI выгружает спецификацию без запуска сервера и подключения к БД:
+// go run ./cmd/api openapi [-o docs/openapi.json] [-check]
+// С -check команда завершается ошибкой, если есть маршруты без описания или описания
+// без маршрутов, что позволяет проверять актуальность документации в CI.
+func runOpenAPI(args []string) error {
+	fs := flag.NewFlagSet("openapi", flag.ContinueOnError)
+	output := fs.String("o", "", "output file (default stdout)")
+	check := fs.Bool("check", false, "fail if some routes are not described or descriptions have no route")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	log := logrus.New()
+	log.SetOutput(os.Stderr)
+	log.SetLevel(logrus.WarnLevel)
+
+	routes, err := offlineRoutes(log)
+	if err != nil {
+		return err
+	}
+
+	reg := openAPIRegistry()
+	if *check {
+		undocumented, stale := reg.Undocumented(routes), reg.Stale(routes)
+		if len(undocumented) > 0 || len(stale) > 0 {
+			return fmt.Errorf("OpenAPI descriptions are out of date: undocumented routes [%s], stale descriptions [%s]",
+				strings.Join(undocumented, ", "), strings.Join(stale, ", "))
+		}
+	}
+
+	data, err := json.MarshalIndent(openapi.Build(openAPIInfo, routes, reg), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode OpenAPI spec: %w", err)
+	}
+	data = append(data, '\n')
+
+	if *output == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(*output, data, 0644)
+}
+
+// offlineRoutes регистрирует маршруты приложения без подключения к внешним сервисам:
+// соединения с PostgreSQL и Redis создаются, но не открываются, обработчики не вызываются
+func offlineRoutes(log *logrus.Logger) ([]fiber.Route, error) {
+	// JWT middleware не создается без секрета; токены при выгрузке не проверяются
+	if os.Getenv("JWT_SECRET") == "" {
+		os.Setenv("JWT_SECRET", "openapi-export")
+	}
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               gormlogger.Discard,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create offline database handle: %w", err)
+	}
+	redisClient := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	defer redisClient.Close()
+
+	app := &App{
+		logger:    log,
+		db:        db,
+		fiber:     fiber.New(),
+		container: container.NewContainer(container.WithDatabase(db), container.WithLogger(log), container.WithRedis(redisClient)),
+	}
+	// Опциональные маршруты (/ws, адреса шлюза ЭСФ) описываются так, как будто подсистема включена
+	app.container.EnableRealtime(realtime.Config{}, realtime.ConnConfig{})
+	app.container.EnableDocumentSchedule(services.DocumentScheduleConfig{})
+	if _, err := app.container.EnableESFGateway(esfgateway.Config{URL: "http://localhost"}); err != nil {
+		return nil, err
+	}
+
+	RegisterHandlers(app.fiber, app.container, nil)
+	app.registerSystemRoutes()
+	return app.fiber.GetRoutes(true), nil
+}
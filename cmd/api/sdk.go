@@ -0,0 +1,108 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/openapi"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+	"github.com/rusgainew/tunduck-app/pkg/sdkgen"
+)
+
+// sdkQuery параметры скачивания клиента
+type sdkQuery struct {
+	Package string `query:"package"`
+}
+
+// registerSDKRoutes регистрирует скачивание клиентов API: GET /api/admin/sdk/:lang (go, typescript)
+func (a *App) registerSDKRoutes() {
+	a.fiber.Get("/api/admin/sdk/:lang", middleware.JWTMiddleware(), rbac.RequireAdminRole(), a.serveSDK)
+}
+
+// serveSDK генерирует клиент по текущей спецификации и отдает его файлом
+func (a *App) serveSDK(c *fiber.Ctx) error {
+	lang, err := sdkgen.ParseLanguage(c.Params("lang"))
+	if err != nil {
+		return response.Error(c, apperror.New(apperror.ErrInvalidRequest, err.Error()))
+	}
+	var query sdkQuery
+	if err := c.QueryParser(&query); err != nil {
+		return response.Error(c, apperror.New(apperror.ErrInvalidRequest, "Invalid query parameters"))
+	}
+
+	doc, _, err := a.openAPIDocument()
+	if err != nil {
+		return fmt.Errorf("failed to build OpenAPI spec: %w", err)
+	}
+	artifact, err := sdkgen.Generate(doc, lang, sdkgen.Options{GoPackage: query.Package})
+	if err != nil {
+		return fmt.Errorf("failed to generate %s SDK: %w", lang, err)
+	}
+
+	c.Set(fiber.HeaderContentType, artifact.ContentType)
+	c.Attachment(artifact.Filename)
+	return c.Send(artifact.Content)
+}
+
+// describeSDKRoutes описывает маршрут скачивания клиентов в спецификации
+func describeSDKRoutes(reg *openapi.Registry) {
+	reg.Add(fiber.MethodGet, "/api/admin/sdk/:lang", openapi.Operation{
+		Tags: []string{"Admin"}, Secured: true,
+		Summary:     "Скачать клиент API (go, typescript)",
+		Description: "Клиент генерируется по этой спецификации; package задает имя пакета Go клиента",
+		Query:       sdkQuery{}, ContentType: fiber.MIMEOctetStream,
+	})
+}
+
+// runSDK генерирует клиенты без запуска сервера и подключения к БД:
+// go run ./cmd/api sdk [-lang go,typescript] [-o sdk] [-package tunduck]
+// Для каждого языка записывается один файл: sdk/go/client.go, sdk/typescript/client.ts.
+func runSDK(args []string) error {
+	fs := flag.NewFlagSet("sdk", flag.ContinueOnError)
+	langs := fs.String("lang", "go,typescript", "comma separated client languages")
+	output := fs.String("o", "sdk", "output directory")
+	pkg := fs.String("package", sdkgen.DefaultGoPackage, "Go client package name")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	log := logrus.New()
+	log.SetOutput(os.Stderr)
+	log.SetLevel(logrus.WarnLevel)
+
+	routes, err := offlineRoutes(log)
+	if err != nil {
+		return err
+	}
+	doc := buildOpenAPI(routes)
+
+	for _, name := range strings.Split(*langs, ",") {
+		lang, err := sdkgen.ParseLanguage(name)
+		if err != nil {
+			return err
+		}
+		artifact, err := sdkgen.Generate(doc, lang, sdkgen.Options{GoPackage: *pkg})
+		if err != nil {
+			return err
+		}
+		dir := filepath.Join(*output, string(lang))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+		path := filepath.Join(dir, artifact.Filename)
+		if err := os.WriteFile(path, artifact.Content, 0644); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "%s SDK written to %s\n", lang, path)
+	}
+	return nil
+}
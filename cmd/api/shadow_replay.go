@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/conf"
+	"github.com/rusgainew/tunduck-app/pkg/shadow"
+	"github.com/rusgainew/tunduck-app/pkg/storage"
+)
+
+// runShadowReplay воспроизводит записанный теневой трафик на проверяемой установке и сравнивает
+// статусы ответов с основной версией: go run ./cmd/api shadow-replay -target http://canary:8080 [-prefix shadow/2026-10-16/]
+// Без файлов в аргументах записи читаются из хранилища по настройкам -env.
+func runShadowReplay(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("shadow-replay", flag.ContinueOnError)
+	target := fs.String("target", "", "base URL of the deployment under test (required)")
+	prefix := fs.String("prefix", shadow.DefaultKeyPrefix+time.Now().UTC().Format("2006-01-02")+"/", "storage key prefix of the recordings")
+	token := fs.String("token", "", "JWT sent as Authorization; recordings do not keep credentials")
+	concurrency := fs.Int("concurrency", shadow.DefaultReplayConcurrency, "concurrent requests; 1 keeps the recorded order")
+	timeout := fs.Duration("timeout", shadow.DefaultTimeout, "timeout of one request")
+	strict := fs.Bool("strict", false, "fail if any response status differs from the primary")
+	envPath := fs.String("env", ".env", "path to the environment file with storage settings")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: shadow-replay -target URL [flags] [recording.ndjson ...]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	replayer, err := shadow.NewReplayer(shadow.ReplayConfig{
+		TargetURL:   *target,
+		Token:       *token,
+		Concurrency: *concurrency,
+		Timeout:     *timeout,
+	})
+	if err != nil {
+		return err
+	}
+
+	// Ctrl+C прекращает воспроизведение, отчет печатается по выполненным запросам
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	replay := func(name string, open func() (io.ReadCloser, error)) error {
+		r, err := open()
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		fmt.Fprintf(os.Stderr, "Replaying %s to %s...\n", name, *target)
+		if err := replayer.Replay(ctx, r); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		return nil
+	}
+
+	if files := fs.Args(); len(files) > 0 {
+		for _, file := range files {
+			if err := replay(file, func() (io.ReadCloser, error) { return os.Open(file) }); err != nil {
+				return err
+			}
+		}
+	} else {
+		log := logrus.New()
+		log.SetOutput(os.Stderr)
+		store, err := storage.New(conf.NewConf(log, *envPath).StorageConfig())
+		if err != nil {
+			return err
+		}
+		objects, err := store.List(ctx, *prefix)
+		if err != nil {
+			return err
+		}
+		if len(objects) == 0 {
+			return fmt.Errorf("no recordings under %q", *prefix)
+		}
+		sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+		for _, obj := range objects {
+			open := func() (io.ReadCloser, error) {
+				r, _, err := store.Get(ctx, obj.Key)
+				return r, err
+			}
+			if err := replay(obj.Key, open); err != nil {
+				return err
+			}
+		}
+	}
+
+	report := replayer.Report()
+	report.Write(os.Stdout)
+	if *strict && report.Mismatched+report.Failed > 0 {
+		return fmt.Errorf("%d of %d responses differ from the primary", report.Mismatched+report.Failed, report.Requests)
+	}
+	return nil
+}
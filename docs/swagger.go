@@ -66,7 +66,7 @@ const docTemplate = `{
       "HealthStatus": {
         "type": "object",
         "properties": {
-          "status": {"type": "string", "enum": ["UP", "DOWN"]},
+          "status": {"type": "string", "enum": ["UP", "DEGRADED", "DOWN"]},
           "timestamp": {"type": "string", "format": "date-time"},
           "uptime": {"type": "string"},
           "components": {
@@ -77,6 +77,7 @@ const docTemplate = `{
                 "name": {"type": "string"},
                 "status": {"type": "string", "enum": ["UP", "DOWN"]},
                 "response_time": {"type": "string"},
+                "duration_ms": {"type": "integer"},
                 "message": {"type": "string"}
               }
             }
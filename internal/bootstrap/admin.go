@@ -0,0 +1,151 @@
+// Package bootstrap готовит новую установку к первому входу: создает администратора без ручных
+// SQL-запросов (команда create-admin).
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/auth"
+	"github.com/rusgainew/tunduck-app/pkg/cache"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+)
+
+// Значения по умолчанию
+const (
+	DefaultAdminUsername = "admin"
+	DefaultAdminFullName = "Administrator"
+	// MinPasswordLength пароль администратора длиннее минимального пароля при регистрации (6)
+	MinPasswordLength = 8
+)
+
+// ErrUserExists пользователь уже существует, а сброс не запрошен
+var ErrUserExists = errors.New("user already exists")
+
+// AdminOptions параметры администратора
+type AdminOptions struct {
+	Username string
+	Email    string
+	Password string
+	// FullName и Phone не обязательны; при сбросе пустые значения не меняют сохраненные
+	FullName string
+	Phone    string
+	// Reset для существующего пользователя задает новый пароль, роль admin и снимает блокировку
+	Reset bool
+}
+
+// Validate проверяет параметры до обращения к БД
+func (o AdminOptions) Validate() error {
+	if len(o.Username) < 3 || len(o.Username) > 50 {
+		return fmt.Errorf("username must be 3 to 50 characters long, got %q", o.Username)
+	}
+	if len(o.Password) < MinPasswordLength {
+		return fmt.Errorf("password must be at least %d characters long", MinPasswordLength)
+	}
+	if o.Email != "" && !strings.Contains(o.Email, "@") {
+		return fmt.Errorf("invalid email %q", o.Email)
+	}
+	return nil
+}
+
+// AdminResult итог: пользователь и признак создания (false — существующий сброшен)
+type AdminResult struct {
+	User    *entity.User
+	Created bool
+}
+
+// CreateAdmin создает администратора или, с Reset, сбрасывает существующего пользователя.
+// Кеш пользователей очищается, иначе вход проверял бы пароль по закешированной записи до истечения кеша.
+func CreateAdmin(ctx context.Context, users repository.UserRepository, cacheManager cache.CacheManager, opts AdminOptions) (AdminResult, error) {
+	if err := opts.Validate(); err != nil {
+		return AdminResult{}, err
+	}
+	hash, err := auth.HashPassword(opts.Password)
+	if err != nil {
+		return AdminResult{}, err
+	}
+
+	user, err := users.GetByUsername(ctx, opts.Username)
+	if err != nil {
+		return AdminResult{}, fmt.Errorf("looking up user %q: %w", opts.Username, err)
+	}
+
+	if user == nil {
+		if opts.Email == "" {
+			return AdminResult{}, errors.New("email is required for a new user")
+		}
+		if err := checkEmailFree(ctx, users, opts.Email, uuid.Nil); err != nil {
+			return AdminResult{}, err
+		}
+		fullName := opts.FullName
+		if fullName == "" {
+			fullName = DefaultAdminFullName
+		}
+		user = &entity.User{
+			ID:       uuid.New(),
+			Username: opts.Username,
+			Email:    opts.Email,
+			FullName: fullName,
+			Phone:    opts.Phone,
+			Password: hash,
+			Role:     rbac.RoleAdmin,
+			IsActive: true,
+		}
+		if err := users.Create(ctx, user); err != nil {
+			// Мягко удаленный пользователь с тем же именем не находится поиском, но занимает уникальный индекс
+			return AdminResult{}, fmt.Errorf("creating user %q (restore a deleted user with this name or email first): %w", opts.Username, err)
+		}
+		return AdminResult{User: user, Created: true}, nil
+	}
+
+	if !opts.Reset {
+		return AdminResult{}, fmt.Errorf("%w: %q; reset its password and role explicitly", ErrUserExists, opts.Username)
+	}
+
+	previousEmail := user.Email
+	if opts.Email != "" && opts.Email != user.Email {
+		if err := checkEmailFree(ctx, users, opts.Email, user.ID); err != nil {
+			return AdminResult{}, err
+		}
+		user.Email = opts.Email
+	}
+	if opts.FullName != "" {
+		user.FullName = opts.FullName
+	}
+	if opts.Phone != "" {
+		user.Phone = opts.Phone
+	}
+	user.Password = hash
+	user.Role = rbac.RoleAdmin
+	user.IsActive = true
+	if err := users.Update(ctx, user); err != nil {
+		return AdminResult{}, fmt.Errorf("updating user %q: %w", opts.Username, err)
+	}
+
+	if cacheManager != nil {
+		keys := []string{"username:" + user.Username, "email:" + user.Email, "email:" + previousEmail, "id:" + user.ID.String()}
+		for _, key := range keys {
+			if err := cacheManager.User().Delete(ctx, key); err != nil {
+				return AdminResult{User: user}, fmt.Errorf("user %q is updated, but its cache entry %q was not cleared: %w", opts.Username, key, err)
+			}
+		}
+	}
+	return AdminResult{User: user}, nil
+}
+
+func checkEmailFree(ctx context.Context, users repository.UserRepository, email string, owner uuid.UUID) error {
+	existing, err := users.GetByEmail(ctx, email)
+	if err != nil {
+		return fmt.Errorf("looking up email %q: %w", email, err)
+	}
+	if existing != nil && existing.ID != owner {
+		return fmt.Errorf("email %q belongs to user %q", email, existing.Username)
+	}
+	return nil
+}
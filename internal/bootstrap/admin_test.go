@@ -0,0 +1,112 @@
+package bootstrap
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/auth"
+	"github.com/rusgainew/tunduck-app/pkg/cache"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+)
+
+type memoryUserRepository struct {
+	repository.UserRepository
+	users map[string]*entity.User
+}
+
+func (r *memoryUserRepository) GetByUsername(ctx context.Context, username string) (*entity.User, error) {
+	if user, ok := r.users[username]; ok {
+		copied := *user
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *memoryUserRepository) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryUserRepository) Create(ctx context.Context, user *entity.User) error {
+	r.users[user.Username] = user
+	return nil
+}
+
+func (r *memoryUserRepository) Update(ctx context.Context, user *entity.User) error {
+	r.users[user.Username] = user
+	return nil
+}
+
+func TestCreateAdmin_New(t *testing.T) {
+	users := &memoryUserRepository{users: map[string]*entity.User{}}
+
+	result, err := CreateAdmin(context.Background(), users, nil, AdminOptions{Username: "admin", Email: "admin@tunduck.kg", Password: "first-secret"})
+	require.NoError(t, err)
+	assert.True(t, result.Created)
+
+	admin := users.users["admin"]
+	require.NotNil(t, admin)
+	assert.Equal(t, rbac.RoleAdmin, admin.Role)
+	assert.True(t, admin.IsActive)
+	assert.Equal(t, DefaultAdminFullName, admin.FullName)
+	assert.True(t, auth.VerifyPassword(admin.Password, "first-secret"))
+
+	// Повторный запуск без сброса не меняет пользователя
+	_, err = CreateAdmin(context.Background(), users, nil, AdminOptions{Username: "admin", Password: "second-secret"})
+	assert.True(t, errors.Is(err, ErrUserExists))
+	assert.True(t, auth.VerifyPassword(users.users["admin"].Password, "first-secret"))
+}
+
+func TestCreateAdmin_Reset(t *testing.T) {
+	hash, err := auth.HashPassword("old-secret")
+	require.NoError(t, err)
+	users := &memoryUserRepository{users: map[string]*entity.User{
+		"ops":   {ID: uuid.New(), Username: "ops", Email: "ops@tunduck.kg", FullName: "Ops", Password: hash, Role: rbac.RoleViewer, IsActive: false},
+		"buyer": {ID: uuid.New(), Username: "buyer", Email: "buyer@tunduck.kg"},
+	}}
+
+	// Закешированная при входе запись со старым паролем
+	cacheManager := cache.NewMemoryCacheManager()
+	require.NoError(t, cacheManager.User().Set(context.Background(), "username:ops", users.users["ops"], time.Hour))
+
+	_, err = CreateAdmin(context.Background(), users, cacheManager, AdminOptions{Username: "ops", Email: "buyer@tunduck.kg", Password: "new-secret", Reset: true})
+	assert.ErrorContains(t, err, `belongs to user "buyer"`)
+
+	result, err := CreateAdmin(context.Background(), users, cacheManager, AdminOptions{Username: "ops", Password: "new-secret", Reset: true})
+	require.NoError(t, err)
+	assert.False(t, result.Created)
+
+	ops := users.users["ops"]
+	assert.Equal(t, rbac.RoleAdmin, ops.Role)
+	assert.True(t, ops.IsActive)
+	assert.Equal(t, "Ops", ops.FullName)
+	assert.Equal(t, "ops@tunduck.kg", ops.Email)
+	assert.True(t, auth.VerifyPassword(ops.Password, "new-secret"))
+
+	cached, _ := cacheManager.User().Get(context.Background(), "username:ops")
+	assert.Nil(t, cached)
+}
+
+func TestAdminOptions_Validate(t *testing.T) {
+	assert.NoError(t, AdminOptions{Username: "admin", Password: "12345678"}.Validate())
+	assert.ErrorContains(t, AdminOptions{Username: "admin", Password: "short"}.Validate(), "at least 8")
+	assert.ErrorContains(t, AdminOptions{Username: "ad", Password: "12345678"}.Validate(), "username")
+	assert.ErrorContains(t, AdminOptions{Username: "admin", Email: "admin", Password: "12345678"}.Validate(), "email")
+
+	users := &memoryUserRepository{users: map[string]*entity.User{}}
+	_, err := CreateAdmin(context.Background(), users, nil, AdminOptions{Username: "admin", Password: "12345678"})
+	assert.ErrorContains(t, err, "email is required")
+}
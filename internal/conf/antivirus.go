@@ -0,0 +1,20 @@
+package conf
+
+import (
+	"github.com/rusgainew/tunduck-app/pkg/antivirus"
+)
+
+// AntivirusConfig читает параметры clamd из CLAMAV_ADDRESS, CLAMAV_TIMEOUT
+// и CLAMAV_QUARANTINE_PREFIX. Без CLAMAV_ADDRESS вложения не проверяются.
+func (c *Conf) AntivirusConfig() antivirus.Config {
+	prefix := c.GetConValue("CLAMAV_QUARANTINE_PREFIX")
+	if prefix == "" {
+		prefix = antivirus.DefaultQuarantinePrefix
+	}
+
+	return antivirus.Config{
+		Address:          c.GetConValue("CLAMAV_ADDRESS"),
+		Timeout:          c.durationValue("CLAMAV_TIMEOUT", antivirus.DefaultTimeout),
+		QuarantinePrefix: prefix,
+	}
+}
@@ -0,0 +1,27 @@
+package conf
+
+import (
+	"github.com/rusgainew/tunduck-app/internal/services"
+)
+
+// AttachmentConfig читает параметры вложений из ATTACHMENT_LINK_TTL, ATTACHMENT_LINK_MAX_TTL
+// и ATTACHMENT_MAX_SIZE. Ссылки подписываются тем же ключом, что и ссылки на выгрузки,
+// а PUBLIC_BASE_URL делает их абсолютными для писем.
+func (c *Conf) AttachmentConfig() services.AttachmentConfig {
+	return services.AttachmentConfig{
+		URLSecret:  c.signedURLSecret(),
+		BaseURL:    c.GetConValue("PUBLIC_BASE_URL"),
+		LinkTTL:    c.durationValue("ATTACHMENT_LINK_TTL", services.DefaultAttachmentLinkTTL),
+		MaxLinkTTL: c.durationValue("ATTACHMENT_LINK_MAX_TTL", services.DefaultAttachmentMaxLinkTTL),
+		MaxSize:    int64(c.intValue("ATTACHMENT_MAX_SIZE", services.DefaultAttachmentMaxSize)),
+	}
+}
+
+// signedURLSecret ключ подписи ссылок на скачивание: SIGNED_URL_SECRET, а без него — JWT_SECRET.
+// Если не задан ни один, Validate отклоняет конфигурацию, а сервисы вложений и выгрузок не создаются.
+func (c *Conf) signedURLSecret() string {
+	if secret := c.GetConValue("SIGNED_URL_SECRET"); secret != "" {
+		return secret
+	}
+	return c.GetJWTSecret()
+}
@@ -0,0 +1,33 @@
+package conf
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/rusgainew/tunduck-app/pkg/canary"
+)
+
+// CanaryConfig читает параметры канареечного выката: CANARY_ENABLED (по умолчанию false), CANARY_PERCENT
+// (доля организаций на новой реализации от 0 до 100, по умолчанию 0 — только по заголовку X-Canary)
+// и CANARY_ROUTES (префиксы путей через запятую, по умолчанию /api/esf-documents)
+func (c *Conf) CanaryConfig() canary.Config {
+	return canary.Config{
+		Enabled: c.boolValue("CANARY_ENABLED", false),
+		Percent: c.floatValue("CANARY_PERCENT", 0),
+		Routes:  c.listValue("CANARY_ROUTES"),
+	}
+}
+
+// validateCanary проверяет долю канареечного трафика
+func validateCanary() []Problem {
+	raw := os.Getenv("CANARY_PERCENT")
+	if raw == "" {
+		return nil
+	}
+	percent, err := strconv.ParseFloat(raw, 64)
+	if err != nil || percent < 0 || percent > 100 {
+		return []Problem{{Key: "CANARY_PERCENT", Message: fmt.Sprintf("must be a number from 0 to 100, got %q", raw)}}
+	}
+	return nil
+}
@@ -1,21 +1,25 @@
 package conf
 
 import (
+	"database/sql"
 	"fmt"
 	"os"
-	"time"
+	"strings"
 
-	"github.com/gofiber/fiber/v2/log"
 	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+
+	"github.com/rusgainew/tunduck-app/pkg/dbresolver"
 )
 
 type Conf struct {
 	log *logrus.Logger
 	db  *gorm.DB
+	// dbLogger логгер запросов основной БД и реплик (nil — стандартный логгер GORM)
+	dbLogger logger.Interface
 }
 
 func NewConf(log *logrus.Logger, fileName ...string) *Conf {
@@ -24,14 +28,32 @@ func NewConf(log *logrus.Logger, fileName ...string) *Conf {
 		os.Exit(1)
 	}
 
-	// Валидация обязательных переменных окружения
-	if err := validateRequiredEnvVars(); err != nil {
-		log.Fatal(err)
+	// Проверка всей конфигурации: все ошибки выводятся одним отчетом, запуск прерывается
+	if err := Validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		log.Error("Configuration is invalid, fix the problems listed above")
+		os.Exit(1)
 	}
+	log.Info("Configuration is valid")
 
 	return &Conf{log: log, db: nil}
 }
 
+// LoadConf загружает окружение из файла как NewConf, но не завершает процесс: ошибка загрузки
+// файла возвращается, а ошибки конфигурации (*ValidationError) — вместе с конфигурацией
+func LoadConf(log *logrus.Logger, fileName ...string) (*Conf, error) {
+	if err := godotenv.Load(fileName...); err != nil {
+		return nil, err
+	}
+	return &Conf{log: log}, Validate()
+}
+
+// EnvConf возвращает конфигурацию по текущему окружению процесса без загрузки файла и проверки,
+// например после того, как команда уже загрузила свой -env
+func EnvConf(log *logrus.Logger) *Conf {
+	return &Conf{log: log}
+}
+
 func (c *Conf) GetConValue(key string) string {
 	// Retrieve specific configuration value by key
 	return os.Getenv(key)
@@ -42,56 +64,33 @@ func (c *Conf) GetJWTSecret() string {
 	return os.Getenv("JWT_SECRET")
 }
 
-// validateRequiredEnvVars проверяет наличие всех обязательных переменных окружения
-func validateRequiredEnvVars() error {
-	requiredVars := []string{
-		"APP_HOST",
-		"APP_PORT",
-		"DB_HOST",
-		"DB_PORT",
-		"DB_USER",
-		"DB_NAME",
-		"DB_PASSWORD",
-		"JWT_SECRET",
-	}
-
-	var missing []string
-	for _, v := range requiredVars {
-		if os.Getenv(v) == "" {
-			missing = append(missing, v)
-		}
-	}
-
-	if len(missing) > 0 {
-		return fmt.Errorf("missing required environment variables: %v", missing)
-	}
+// SetDBLogger задает логгер запросов для DBConnect и ConnectReadReplicas
+func (c *Conf) SetDBLogger(log logger.Interface) {
+	c.dbLogger = log
+}
 
-	// Дополнительная проверка JWT_SECRET на минимальную длину
-	jwtSecret := os.Getenv("JWT_SECRET")
-	if len(jwtSecret) < 32 {
-		return fmt.Errorf("JWT_SECRET must be at least 32 characters long, got %d", len(jwtSecret))
+func (c *Conf) gormLogger() logger.Interface {
+	if c.dbLogger != nil {
+		return c.dbLogger
 	}
-
-	log.Info("All required environment variables are set")
-	return nil
+	return logger.Default.LogMode(logger.Info)
 }
-func (c *Conf) DBConnect() *gorm.DB {
-	host := c.GetConValue("DB_HOST")
-	port := c.GetConValue("DB_PORT")
-	user := c.GetConValue("DB_USER")
-	dbname := c.GetConValue("DB_NAME")
-	password := c.GetConValue("DB_PASSWORD")
-	sslmode := c.GetConValue("DB_SSLMODE")
 
+// DatabaseDSN строка подключения к основной БД из DB_HOST, DB_PORT, DB_USER, DB_PASSWORD, DB_NAME и DB_SSLMODE
+func (c *Conf) DatabaseDSN() string {
+	sslmode := c.GetConValue("DB_SSLMODE")
 	if sslmode == "" {
 		sslmode = "disable"
 	}
 
-	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
-		host, user, password, dbname, port, sslmode)
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
+		c.GetConValue("DB_HOST"), c.GetConValue("DB_USER"), c.GetConValue("DB_PASSWORD"),
+		c.GetConValue("DB_NAME"), c.GetConValue("DB_PORT"), sslmode)
+}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+func (c *Conf) DBConnect() *gorm.DB {
+	db, err := gorm.Open(postgres.Open(c.DatabaseDSN()), &gorm.Config{
+		Logger: c.gormLogger(),
 	})
 	if err != nil {
 		c.log.Fatal("Failed to connect to database: ", err)
@@ -106,9 +105,7 @@ func (c *Conf) DBConnect() *gorm.DB {
 		os.Exit(1)
 	}
 
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetMaxOpenConns(100)
-	sqlDB.SetConnMaxLifetime(time.Hour)
+	c.PoolConfig().Apply(sqlDB)
 
 	// Ping the database to verify connection
 	if err := sqlDB.Ping(); err != nil {
@@ -147,9 +144,7 @@ func (c *Conf) ConnectToDb(db_name string) *gorm.DB {
 		os.Exit(1)
 	}
 
-	sqlDB.SetMaxIdleConns(10)
-	sqlDB.SetMaxOpenConns(100)
-	sqlDB.SetConnMaxLifetime(time.Hour)
+	c.PoolConfig().Apply(sqlDB)
 	// 	// Ping the database to verify connection
 	if err := sqlDB.Ping(); err != nil {
 		c.log.Fatal("Failed to ping database: ", err)
@@ -159,6 +154,61 @@ func (c *Conf) ConnectToDb(db_name string) *gorm.DB {
 	return db
 }
 
+// ConnectReadReplicas подключается к репликам из DB_REPLICA_HOSTS ("host:port,host:port").
+// Учетные данные и имя БД совпадают с primary. Недоступная реплика пропускается:
+// чтение просто остается на primary.
+func (c *Conf) ConnectReadReplicas() []*dbresolver.Replica {
+	hosts := c.GetConValue("DB_REPLICA_HOSTS")
+	if hosts == "" {
+		return nil
+	}
+
+	user := c.GetConValue("DB_USER")
+	dbname := c.GetConValue("DB_NAME")
+	password := c.GetConValue("DB_PASSWORD")
+	sslmode := c.GetConValue("DB_SSLMODE")
+	if sslmode == "" {
+		sslmode = "disable"
+	}
+
+	var replicas []*dbresolver.Replica
+	for _, addr := range strings.Split(hosts, ",") {
+		addr = strings.TrimSpace(addr)
+		if addr == "" {
+			continue
+		}
+
+		host, port, found := strings.Cut(addr, ":")
+		if !found {
+			port = c.GetConValue("DB_PORT")
+		}
+
+		dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
+			host, user, password, dbname, port, sslmode)
+
+		db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+			Logger: c.gormLogger(),
+		})
+		if err != nil {
+			c.log.WithError(err).WithField("replica", addr).Warn("Failed to connect to read replica, skipping")
+			continue
+		}
+
+		replica, err := dbresolver.NewReplica(addr, db)
+		if err != nil {
+			c.log.WithError(err).WithField("replica", addr).Warn("Failed to get read replica instance, skipping")
+			continue
+		}
+		if sqlDB, ok := replica.Pool.(*sql.DB); ok {
+			c.PoolConfig().Apply(sqlDB)
+		}
+		replicas = append(replicas, replica)
+		c.log.WithField("replica", addr).Info("Read replica connection established")
+	}
+
+	return replicas
+}
+
 // git remote add origin https://github.com/rusgainew/tunduct-project-system.git
 // git branch -M dev
 // git push -u origin dev
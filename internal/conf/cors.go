@@ -0,0 +1,76 @@
+package conf
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+)
+
+// devCORSOrigins источники по умолчанию при APP_ENV=dev: порты разработки 3000, 3002, 5173 (Vite)
+var devCORSOrigins = []string{"http://localhost:3000", "http://localhost:3002", "http://localhost:5173"}
+
+// CORSConfig читает параметры CORS: источники CORS_ALLOWED_ORIGINS (точные, шаблоны https://*.tunduck.kg
+// или "*"; прежнее имя ALLOWED_ORIGINS поддерживается), CORS_ALLOW_CREDENTIALS (по умолчанию false),
+// срок кеширования preflight-ответа CORS_MAX_AGE и дополнительные заголовки CORS_ALLOW_HEADERS
+// и CORS_EXPOSE_HEADERS. Без CORS_ALLOWED_ORIGINS в dev разрешены порты разработки на localhost,
+// в staging и prod межсайтовые запросы запрещены.
+func (c *Conf) CORSConfig() middleware.CORSConfig {
+	origins := corsOrigins(c.GetConValue)
+	if origins == nil && c.AppEnv() == "dev" {
+		origins = devCORSOrigins
+	}
+	return middleware.CORSConfig{
+		AllowOrigins:     origins,
+		AllowHeaders:     appendHeaders(middleware.DefaultCORSAllowHeaders, c.listValue("CORS_ALLOW_HEADERS")),
+		ExposeHeaders:    appendHeaders(middleware.DefaultCORSExposeHeaders, c.listValue("CORS_EXPOSE_HEADERS")),
+		AllowCredentials: c.boolValue("CORS_ALLOW_CREDENTIALS", false),
+		MaxAge:           c.durationValue("CORS_MAX_AGE", middleware.DefaultCORSMaxAge),
+	}
+}
+
+// corsOrigins список источников из CORS_ALLOWED_ORIGINS или ALLOWED_ORIGINS; nil, если не заданы
+func corsOrigins(getenv func(string) string) []string {
+	raw := getenv("CORS_ALLOWED_ORIGINS")
+	if raw == "" {
+		raw = getenv("ALLOWED_ORIGINS")
+	}
+	var origins []string
+	for _, origin := range strings.Split(raw, ",") {
+		if origin = strings.TrimSpace(origin); origin != "" {
+			origins = append(origins, origin)
+		}
+	}
+	return origins
+}
+
+// appendHeaders дополняет заголовки по умолчанию заголовками из конфигурации
+func appendHeaders(defaults, extra []string) []string {
+	return append(append([]string(nil), defaults...), extra...)
+}
+
+// validateCORS проверяет источники CORS. "*" не сочетается с CORS_ALLOW_CREDENTIALS и запрещен в продакшене.
+func validateCORS() []Problem {
+	key := "CORS_ALLOWED_ORIGINS"
+	if os.Getenv(key) == "" && os.Getenv("ALLOWED_ORIGINS") != "" {
+		key = "ALLOWED_ORIGINS"
+	}
+
+	matcher, err := middleware.ParseOrigins(corsOrigins(os.Getenv))
+	if err != nil {
+		return []Problem{{Key: key, Message: err.Error()}}
+	}
+	if !matcher.Any() {
+		return nil
+	}
+
+	var problems []Problem
+	if credentials, _ := strconv.ParseBool(os.Getenv("CORS_ALLOW_CREDENTIALS")); credentials {
+		problems = append(problems, Problem{Key: "CORS_ALLOW_CREDENTIALS", Message: "must be false when CORS_ALLOWED_ORIGINS is \"*\""})
+	}
+	if isProductionEnv() {
+		problems = append(problems, Problem{Key: key, Message: `must list origins explicitly in production, got "*"`})
+	}
+	return problems
+}
@@ -0,0 +1,47 @@
+package conf
+
+import "strings"
+
+// DocsMode режим доступа к документации API: Swagger UI, /docs и спецификации /swagger/doc.json
+type DocsMode string
+
+const (
+	DocsModeOpen     DocsMode = "open"     // доступна всем
+	DocsModeAdmin    DocsMode = "admin"    // только администраторам с JWT
+	DocsModeDisabled DocsMode = "disabled" // маршруты не регистрируются
+)
+
+// AppEnv возвращает окружение приложения из APP_ENV (dev, staging, prod); по умолчанию dev
+func (c *Conf) AppEnv() string {
+	env := strings.ToLower(strings.TrimSpace(c.GetConValue("APP_ENV")))
+	if env == "" {
+		return "dev"
+	}
+	return env
+}
+
+// IsProduction сообщает, что приложение запущено в продакшене (APP_ENV=prod или production)
+func (c *Conf) IsProduction() bool {
+	env := c.AppEnv()
+	return env == "prod" || env == "production"
+}
+
+// DocsMode читает DOCS_MODE (open, admin, disabled). По умолчанию документация открыта,
+// а при APP_ENV=prod отключена.
+func (c *Conf) DocsMode() DocsMode {
+	def := DocsModeOpen
+	if c.IsProduction() {
+		def = DocsModeDisabled
+	}
+
+	raw := strings.ToLower(strings.TrimSpace(c.GetConValue("DOCS_MODE")))
+	switch mode := DocsMode(raw); mode {
+	case "":
+		return def
+	case DocsModeOpen, DocsModeAdmin, DocsModeDisabled:
+		return mode
+	default:
+		c.log.WithField("mode", raw).Warn("Invalid DOCS_MODE, using default")
+		return def
+	}
+}
@@ -0,0 +1,20 @@
+package conf
+
+import (
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/jobs"
+)
+
+// DocumentScheduleConfig читает параметры отложенной отправки документов и напоминаний о черновиках
+// из DRAFT_REMINDER_AFTER (0 отключает напоминания) и DOCUMENT_SCHEDULE_QUEUE
+func (c *Conf) DocumentScheduleConfig() services.DocumentScheduleConfig {
+	queue := c.GetConValue("DOCUMENT_SCHEDULE_QUEUE")
+	if queue == "" {
+		queue = jobs.DefaultQueue
+	}
+
+	return services.DocumentScheduleConfig{
+		DraftReminderAfter: c.durationValue("DRAFT_REMINDER_AFTER", services.DefaultDraftReminderAfter),
+		Queue:              queue,
+	}
+}
@@ -0,0 +1,39 @@
+package conf
+
+import (
+	"strings"
+
+	"github.com/rusgainew/tunduck-app/pkg/esfgateway"
+)
+
+// ESFGatewayConfig читает параметры шлюза ЭСФ из ESF_GATEWAY_BACKEND (http или mock),
+// ESF_GATEWAY_URL или ESF_GATEWAY_ENDPOINTS ("prod=https://...,mirror=https://..."), ESF_GATEWAY_TIMEOUT,
+// ESF_GATEWAY_FAILOVER_THRESHOLD и ESF_GATEWAY_FAILBACK_AFTER. Без адресов используется встроенная
+// имитация, настраиваемая ESF_MOCK_LATENCY, ESF_MOCK_ERROR_RATE (0..1) и ESF_MOCK_SEED.
+func (c *Conf) ESFGatewayConfig() esfgateway.Config {
+	errorRate := c.floatValue("ESF_MOCK_ERROR_RATE", 0)
+	if errorRate > 1 {
+		c.log.WithField("key", "ESF_MOCK_ERROR_RATE").Warn("Error rate is above 1, using 1")
+		errorRate = 1
+	}
+
+	endpoints, err := esfgateway.ParseEndpoints(c.GetConValue("ESF_GATEWAY_ENDPOINTS"))
+	if err != nil {
+		c.log.WithError(err).WithField("key", "ESF_GATEWAY_ENDPOINTS").Warn("Invalid gateway endpoints, using ESF_GATEWAY_URL")
+		endpoints = nil
+	}
+
+	return esfgateway.Config{
+		Backend:           strings.ToLower(c.GetConValue("ESF_GATEWAY_BACKEND")),
+		URL:               c.GetConValue("ESF_GATEWAY_URL"),
+		Endpoints:         endpoints,
+		Timeout:           c.durationValue("ESF_GATEWAY_TIMEOUT", esfgateway.DefaultTimeout),
+		FailoverThreshold: c.intValue("ESF_GATEWAY_FAILOVER_THRESHOLD", esfgateway.DefaultFailoverThreshold),
+		FailbackAfter:     c.durationValue("ESF_GATEWAY_FAILBACK_AFTER", esfgateway.DefaultFailbackAfter),
+		Mock: esfgateway.MockConfig{
+			Latency:   c.durationValue("ESF_MOCK_LATENCY", 0),
+			ErrorRate: errorRate,
+			Seed:      int64(c.intValue("ESF_MOCK_SEED", 1)),
+		},
+	}
+}
@@ -0,0 +1,16 @@
+package conf
+
+import (
+	"github.com/rusgainew/tunduck-app/pkg/events"
+)
+
+// EventsConfig читает параметры шины доменных событий из EVENTS_STREAM, EVENTS_STREAM_MAX_LEN,
+// EVENTS_RELAY_INTERVAL и EVENTS_RETENTION.
+func (c *Conf) EventsConfig() events.Config {
+	return events.Config{
+		Stream:        c.GetConValue("EVENTS_STREAM"),
+		StreamMaxLen:  int64(c.intValue("EVENTS_STREAM_MAX_LEN", events.DefaultStreamMaxLen)),
+		RelayInterval: c.durationValue("EVENTS_RELAY_INTERVAL", events.DefaultRelayInterval),
+		Retention:     c.durationValue("EVENTS_RETENTION", events.DefaultRetention),
+	}
+}
@@ -0,0 +1,12 @@
+package conf
+
+import "github.com/rusgainew/tunduck-app/pkg/exchangerates"
+
+// ExchangeRatesSource создает источник официальных курсов по EXCHANGE_RATES_URL
+// (по умолчанию ежедневные курсы НБКР) и EXCHANGE_RATES_TIMEOUT
+func (c *Conf) ExchangeRatesSource() exchangerates.Source {
+	return exchangerates.NewClient(
+		c.GetConValue("EXCHANGE_RATES_URL"),
+		c.durationValue("EXCHANGE_RATES_TIMEOUT", exchangerates.DefaultTimeout),
+	)
+}
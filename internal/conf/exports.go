@@ -0,0 +1,32 @@
+package conf
+
+import (
+	"time"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/jobs"
+)
+
+// Значения по умолчанию для фоновых выгрузок
+const (
+	defaultExportLinkTTL   = time.Hour
+	defaultExportRetention = 7 * 24 * time.Hour
+)
+
+// ExportConfig читает параметры фоновых выгрузок из EXPORT_LINK_TTL, EXPORT_RETENTION и EXPORT_QUEUE.
+// Ссылки на скачивание подписываются SIGNED_URL_SECRET, а без него — JWT_SECRET;
+// PUBLIC_BASE_URL делает их абсолютными.
+func (c *Conf) ExportConfig() services.ExportConfig {
+	queue := c.GetConValue("EXPORT_QUEUE")
+	if queue == "" {
+		queue = jobs.DefaultQueue
+	}
+
+	return services.ExportConfig{
+		URLSecret: c.signedURLSecret(),
+		BaseURL:   c.GetConValue("PUBLIC_BASE_URL"),
+		LinkTTL:   c.durationValue("EXPORT_LINK_TTL", defaultExportLinkTTL),
+		Retention: c.durationValue("EXPORT_RETENTION", defaultExportRetention),
+		Queue:     queue,
+	}
+}
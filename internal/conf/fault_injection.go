@@ -0,0 +1,40 @@
+package conf
+
+import (
+	"os"
+	"strconv"
+
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+)
+
+// FaultInjectionConfig читает параметры внедрения сбоев: FAULT_INJECTION_ENABLED (по умолчанию false)
+// и правила FAULT_INJECTION_RULES (см. middleware.ParseFaultRules)
+func (c *Conf) FaultInjectionConfig() (middleware.FaultInjectionConfig, error) {
+	cfg := middleware.FaultInjectionConfig{Enabled: c.boolValue("FAULT_INJECTION_ENABLED", false)}
+	if !cfg.Enabled {
+		return cfg, nil
+	}
+	rules, err := middleware.ParseFaultRules(c.GetConValue("FAULT_INJECTION_RULES"))
+	if err != nil {
+		return middleware.FaultInjectionConfig{}, err
+	}
+	cfg.Rules = rules
+	return cfg, nil
+}
+
+// validateFaultInjection запрещает внедрение сбоев в продакшене и проверяет правила
+func validateFaultInjection() []Problem {
+	enabled, _ := strconv.ParseBool(os.Getenv("FAULT_INJECTION_ENABLED"))
+	if !enabled {
+		return nil
+	}
+
+	var problems []Problem
+	if isProductionEnv() {
+		problems = append(problems, Problem{Key: "FAULT_INJECTION_ENABLED", Message: "must not be enabled in production"})
+	}
+	if _, err := middleware.ParseFaultRules(os.Getenv("FAULT_INJECTION_RULES")); err != nil {
+		problems = append(problems, Problem{Key: "FAULT_INJECTION_RULES", Message: err.Error()})
+	}
+	return problems
+}
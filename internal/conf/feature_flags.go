@@ -0,0 +1,14 @@
+package conf
+
+import (
+	"github.com/rusgainew/tunduck-app/pkg/featureflag"
+)
+
+// FeatureFlagsConfig читает параметры флагов функций из FEATURE_FLAGS_PREFIX (ключ хеша в Redis)
+// и FEATURE_FLAGS_REFRESH_INTERVAL (перечитывание на случай пропущенного уведомления)
+func (c *Conf) FeatureFlagsConfig() featureflag.Config {
+	return featureflag.Config{
+		Prefix:          c.GetConValue("FEATURE_FLAGS_PREFIX"),
+		RefreshInterval: c.durationValue("FEATURE_FLAGS_REFRESH_INTERVAL", featureflag.DefaultRefreshInterval),
+	}
+}
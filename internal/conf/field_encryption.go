@@ -0,0 +1,25 @@
+package conf
+
+import (
+	"strings"
+
+	"github.com/rusgainew/tunduck-app/pkg/fieldcrypt"
+)
+
+// FieldEncryptionConfig читает параметры шифрования полей из FIELD_ENCRYPTION_KMS (local или vault),
+// FIELD_ENCRYPTION_KEY_ROTATION, для local — FIELD_ENCRYPTION_KEY_ID, FIELD_ENCRYPTION_KEY (base64, 32 байта)
+// и FIELD_ENCRYPTION_OLD_KEYS ("id:base64,..."), для vault — VAULT_ADDR, VAULT_TOKEN,
+// FIELD_ENCRYPTION_VAULT_KEY и VAULT_TIMEOUT. Без FIELD_ENCRYPTION_KMS поля не шифруются.
+func (c *Conf) FieldEncryptionConfig() fieldcrypt.Config {
+	return fieldcrypt.Config{
+		KMS:          strings.ToLower(c.GetConValue("FIELD_ENCRYPTION_KMS")),
+		Rotation:     c.durationValue("FIELD_ENCRYPTION_KEY_ROTATION", fieldcrypt.DefaultKeyRotation),
+		LocalKeyID:   c.GetConValue("FIELD_ENCRYPTION_KEY_ID"),
+		LocalKey:     c.GetConValue("FIELD_ENCRYPTION_KEY"),
+		LocalOldKeys: c.GetConValue("FIELD_ENCRYPTION_OLD_KEYS"),
+		VaultAddress: c.GetConValue("VAULT_ADDR"),
+		VaultToken:   c.GetConValue("VAULT_TOKEN"),
+		VaultKey:     c.GetConValue("FIELD_ENCRYPTION_VAULT_KEY"),
+		VaultTimeout: c.durationValue("VAULT_TIMEOUT", fieldcrypt.DefaultVaultTimeout),
+	}
+}
@@ -0,0 +1,14 @@
+package conf
+
+import (
+	"github.com/rusgainew/tunduck-app/internal/grpcapi"
+)
+
+// GRPCConfig читает параметры внутреннего gRPC API из GRPC_ADDR (например ":9090"; пусто — сервер не запускается)
+// и GRPC_MAX_MESSAGE_SIZE (байты, по умолчанию 4 МБ).
+func (c *Conf) GRPCConfig() grpcapi.Config {
+	return grpcapi.Config{
+		Addr:           c.GetConValue("GRPC_ADDR"),
+		MaxMessageSize: c.intValue("GRPC_MAX_MESSAGE_SIZE", grpcapi.DefaultMaxMessageSize),
+	}
+}
@@ -0,0 +1,55 @@
+package conf
+
+import (
+	"time"
+
+	"github.com/rusgainew/tunduck-app/pkg/health"
+)
+
+// HealthCheckTimeout время на проверку одного компонента в /health (HEALTH_CHECK_TIMEOUT)
+func (c *Conf) HealthCheckTimeout() time.Duration {
+	return c.durationValue("HEALTH_CHECK_TIMEOUT", health.DefaultCheckTimeout)
+}
+
+// TenantHealthEnabled включает наблюдение за БД организаций (TENANT_HEALTH_ENABLED, по умолчанию true)
+func (c *Conf) TenantHealthEnabled() bool {
+	return c.boolValue("TENANT_HEALTH_ENABLED", true)
+}
+
+// TenantMonitorConfig читает параметры наблюдения за БД организаций из TENANT_HEALTH_INTERVAL,
+// TENANT_HEALTH_SAMPLE_SIZE, TENANT_HEALTH_FAILURE_THRESHOLD и TENANT_HEALTH_MAX_REPLICATION_LAG;
+// таймаут проверки одной БД — HEALTH_CHECK_TIMEOUT
+func (c *Conf) TenantMonitorConfig() health.TenantMonitorConfig {
+	return health.TenantMonitorConfig{
+		Interval:          c.durationValue("TENANT_HEALTH_INTERVAL", health.DefaultTenantInterval),
+		SampleSize:        c.intValue("TENANT_HEALTH_SAMPLE_SIZE", health.DefaultTenantSampleSize),
+		FailureThreshold:  c.intValue("TENANT_HEALTH_FAILURE_THRESHOLD", health.DefaultTenantFailureThreshold),
+		MaxReplicationLag: c.durationValue("TENANT_HEALTH_MAX_REPLICATION_LAG", health.DefaultTenantMaxReplicationLag),
+		Timeout:           c.HealthCheckTimeout(),
+	}
+}
+
+// ReadOnlyModeEnabled включает режим только для чтения при недоступности основной БД
+// (READ_ONLY_MODE_ENABLED, по умолчанию true)
+func (c *Conf) ReadOnlyModeEnabled() bool {
+	return c.boolValue("READ_ONLY_MODE_ENABLED", true)
+}
+
+// ReadOnlyConfig читает параметры режима только для чтения из READ_ONLY_CHECK_INTERVAL
+// и READ_ONLY_FAILURE_THRESHOLD
+func (c *Conf) ReadOnlyConfig() health.ReadOnlyConfig {
+	return health.ReadOnlyConfig{
+		Interval:         c.durationValue("READ_ONLY_CHECK_INTERVAL", health.DefaultReadOnlyInterval),
+		FailureThreshold: c.intValue("READ_ONLY_FAILURE_THRESHOLD", health.DefaultReadOnlyFailureThreshold),
+	}
+}
+
+// StatusConfig читает параметры публичной сводки /status из STATUS_CACHE_TTL, STATUS_INCIDENT_WINDOW
+// и STATUS_MAX_INCIDENTS
+func (c *Conf) StatusConfig() health.StatusConfig {
+	return health.StatusConfig{
+		CacheTTL:       c.durationValue("STATUS_CACHE_TTL", health.DefaultStatusCacheTTL),
+		IncidentWindow: c.durationValue("STATUS_INCIDENT_WINDOW", health.DefaultStatusIncidentWindow),
+		MaxIncidents:   c.intValue("STATUS_MAX_INCIDENTS", health.DefaultStatusMaxIncidents),
+	}
+}
@@ -0,0 +1,43 @@
+package conf
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/rusgainew/tunduck-app/pkg/jobs"
+)
+
+// JobsConfig читает параметры очереди фоновых задач из JOBS_PREFIX, JOBS_QUEUES,
+// JOBS_POLL_INTERVAL, JOBS_CLAIM_AFTER и JOBS_SCHEDULE_INTERVAL. JOBS_QUEUES задается как "default:10,exports:2"
+// (очередь:количество воркеров).
+func (c *Conf) JobsConfig() jobs.Config {
+	queues := make(map[string]int)
+	for _, item := range c.listValue("JOBS_QUEUES") {
+		name, rawWorkers, found := strings.Cut(item, ":")
+		workers := jobs.DefaultConcurrency
+		if found {
+			n, err := strconv.Atoi(strings.TrimSpace(rawWorkers))
+			if err != nil || n < 0 {
+				c.log.WithField("queue", name).Warn("Invalid JOBS_QUEUES worker count, using default")
+			} else {
+				workers = n
+			}
+		}
+		queues[strings.TrimSpace(name)] = workers
+	}
+
+	return jobs.Config{
+		Prefix:       c.GetConValue("JOBS_PREFIX"),
+		Queues:       queues,
+		PollInterval: c.durationValue("JOBS_POLL_INTERVAL", jobs.DefaultPollInterval),
+		ClaimAfter:   c.durationValue("JOBS_CLAIM_AFTER", jobs.DefaultClaimAfter),
+
+		ScheduleInterval: c.durationValue("JOBS_SCHEDULE_INTERVAL", jobs.DefaultScheduleInterval),
+	}
+}
+
+// JobWorkersEnabled сообщает, запускать ли воркеры в этом процессе (JOBS_WORKERS_ENABLED, по умолчанию true).
+// Без воркеров инстанс только ставит задачи в очередь.
+func (c *Conf) JobWorkersEnabled() bool {
+	return c.boolValue("JOBS_WORKERS_ENABLED", true)
+}
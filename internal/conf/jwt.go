@@ -0,0 +1,42 @@
+package conf
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/rusgainew/tunduck-app/pkg/auth"
+)
+
+// minJWTSecretLength минимальная длина секрета подписи JWT
+const minJWTSecretLength = 32
+
+// JWTKeys возвращает ключи подписи JWT: JWT_SECRET (kid "default") и расписание ротации из JWT_KEYS
+func (c *Conf) JWTKeys() (*auth.KeyRing, error) {
+	return auth.KeyRingFromEnv()
+}
+
+// validateJWTKeys проверяет ключи подписи: нужен JWT_SECRET или JWT_KEYS, секреты не короче
+// 32 символов и хотя бы один ключ должен действовать уже сейчас
+func validateJWTKeys() error {
+	if os.Getenv("JWT_SECRET") == "" && os.Getenv("JWT_KEYS") == "" {
+		return fmt.Errorf("missing required environment variables: [JWT_SECRET]")
+	}
+
+	keys, err := auth.KeyRingFromEnv()
+	if err != nil {
+		return err
+	}
+	for _, k := range keys.Keys() {
+		if len(k.Secret) >= minJWTSecretLength {
+			continue
+		}
+		if k.ID == auth.LegacyKeyID {
+			return fmt.Errorf("JWT_SECRET must be at least %d characters long, got %d", minJWTSecretLength, len(k.Secret))
+		}
+		return fmt.Errorf("JWT key %q secret must be at least %d characters long, got %d", k.ID, minJWTSecretLength, len(k.Secret))
+	}
+	if _, err := keys.Current(); err != nil {
+		return fmt.Errorf("no JWT signing key is active now: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package conf
+
+import (
+	"github.com/rusgainew/tunduck-app/pkg/leader"
+)
+
+// Хранилища блокировки лидерства (LEADER_ELECTION_BACKEND)
+const (
+	LeaderBackendRedis    = "redis"
+	LeaderBackendPostgres = "postgres"
+)
+
+// LeaderElectionConfig читает параметры выборов лидера: LEADER_ELECTION_NAME, LEADER_ELECTION_TTL
+// (по умолчанию 15s) и LEADER_ELECTION_RETRY_INTERVAL (по умолчанию 5s)
+func (c *Conf) LeaderElectionConfig() leader.Config {
+	return leader.Config{
+		Name:          c.GetConValue("LEADER_ELECTION_NAME"),
+		TTL:           c.durationValue("LEADER_ELECTION_TTL", leader.DefaultTTL),
+		RetryInterval: c.durationValue("LEADER_ELECTION_RETRY_INTERVAL", leader.DefaultRetryInterval),
+	}
+}
+
+// LeaderElectionBackend хранилище блокировки лидерства: redis (по умолчанию) или postgres
+// (advisory-блокировка; не работает через PgBouncer в режиме transaction)
+func (c *Conf) LeaderElectionBackend() string {
+	if c.GetConValue("LEADER_ELECTION_BACKEND") == LeaderBackendPostgres {
+		return LeaderBackendPostgres
+	}
+	return LeaderBackendRedis
+}
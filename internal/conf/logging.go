@@ -0,0 +1,81 @@
+package conf
+
+import (
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/logship"
+)
+
+// LoggingConfig читает уровни логирования: LOG_LEVEL (общий, по умолчанию info) и LOG_LEVELS
+// (уровни подсистем, например "gorm=warn,gateway=debug,http=info"), а также выборку отладочных
+// записей LOG_SAMPLING_INITIAL и LOG_SAMPLING_THEREAFTER (LOG_SAMPLING_INITIAL=0 выключает выборку).
+// Некорректные значения заменяются значениями по умолчанию.
+func (c *Conf) LoggingConfig() logger.Config {
+	cfg := logger.Config{
+		Level:              logrus.InfoLevel,
+		SamplingInitial:    c.intValue("LOG_SAMPLING_INITIAL", logger.DefaultSamplingInitial),
+		SamplingThereafter: c.intValue("LOG_SAMPLING_THEREAFTER", logger.DefaultSamplingThereafter),
+	}
+
+	if raw := c.GetConValue("LOG_LEVEL"); raw != "" {
+		level, err := logrus.ParseLevel(raw)
+		if err != nil {
+			c.log.WithField("key", "LOG_LEVEL").Warn("Invalid log level, using default")
+		} else {
+			cfg.Level = level
+		}
+	}
+
+	modules, err := logger.ParseModuleLevels(c.GetConValue("LOG_LEVELS"))
+	if err != nil {
+		c.log.WithError(err).WithField("key", "LOG_LEVELS").Warn("Invalid module log levels, ignoring")
+	}
+	cfg.Modules = modules
+	return cfg
+}
+
+// LogShippingConfig читает параметры отправки журнала: LOG_SHIPPING_URL (без него отправка выключена),
+// LOG_SHIPPING_FORMAT (loki или gelf), LOG_SHIPPING_APP_NAME, LOG_SHIPPING_LEVEL (по умолчанию info), LOG_SHIPPING_LABELS
+// ("env=prod,region=kg"), LOG_SHIPPING_TENANT_ID, LOG_SHIPPING_USERNAME, LOG_SHIPPING_PASSWORD,
+// LOG_SHIPPING_BATCH_SIZE, LOG_SHIPPING_FLUSH_INTERVAL, LOG_SHIPPING_BUFFER_SIZE и LOG_SHIPPING_TIMEOUT
+func (c *Conf) LogShippingConfig() logship.Config {
+	cfg := logship.Config{
+		URL:           c.GetConValue("LOG_SHIPPING_URL"),
+		Format:        strings.ToLower(c.GetConValue("LOG_SHIPPING_FORMAT")),
+		Level:         logship.DefaultLevel,
+		AppName:       c.GetConValue("LOG_SHIPPING_APP_NAME"),
+		Labels:        map[string]string{},
+		TenantID:      c.GetConValue("LOG_SHIPPING_TENANT_ID"),
+		Username:      c.GetConValue("LOG_SHIPPING_USERNAME"),
+		Password:      c.GetConValue("LOG_SHIPPING_PASSWORD"),
+		BatchSize:     c.intValue("LOG_SHIPPING_BATCH_SIZE", logship.DefaultBatchSize),
+		FlushInterval: c.durationValue("LOG_SHIPPING_FLUSH_INTERVAL", logship.DefaultFlushInterval),
+		BufferSize:    c.intValue("LOG_SHIPPING_BUFFER_SIZE", logship.DefaultBufferSize),
+		Timeout:       c.durationValue("LOG_SHIPPING_TIMEOUT", logship.DefaultTimeout),
+	}
+
+	if cfg.Format == "" {
+		cfg.Format = logship.DefaultFormat
+	}
+	if raw := c.GetConValue("LOG_SHIPPING_LEVEL"); raw != "" {
+		level, err := logrus.ParseLevel(raw)
+		if err != nil {
+			c.log.WithField("key", "LOG_SHIPPING_LEVEL").Warn("Invalid log level, using default")
+		} else {
+			cfg.Level = level
+		}
+	}
+
+	for _, item := range c.listValue("LOG_SHIPPING_LABELS") {
+		name, value, ok := strings.Cut(item, "=")
+		if name = strings.TrimSpace(name); !ok || name == "" {
+			c.log.WithField("label", item).Warn("Invalid LOG_SHIPPING_LABELS entry, expected name=value")
+			continue
+		}
+		cfg.Labels[name] = strings.TrimSpace(value)
+	}
+	return cfg
+}
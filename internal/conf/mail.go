@@ -0,0 +1,32 @@
+package conf
+
+import (
+	"strings"
+
+	"github.com/rusgainew/tunduck-app/pkg/mail"
+)
+
+// MailConfig читает параметры SMTP из SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD,
+// SMTP_FROM, SMTP_TLS (starttls, tls или none) и SMTP_TIMEOUT.
+// Без SMTP_HOST письма не отправляются, а пишутся в лог.
+func (c *Conf) MailConfig() mail.Config {
+	tls := strings.ToLower(c.GetConValue("SMTP_TLS"))
+	switch tls {
+	case "":
+		tls = mail.TLSStartTLS
+	case mail.TLSStartTLS, mail.TLSImplicit, mail.TLSNone:
+	default:
+		c.log.WithField("value", tls).Warn("Invalid SMTP_TLS, using starttls")
+		tls = mail.TLSStartTLS
+	}
+
+	return mail.Config{
+		Host:     c.GetConValue("SMTP_HOST"),
+		Port:     c.intValue("SMTP_PORT", mail.DefaultPort),
+		Username: c.GetConValue("SMTP_USERNAME"),
+		Password: c.GetConValue("SMTP_PASSWORD"),
+		From:     c.GetConValue("SMTP_FROM"),
+		TLS:      tls,
+		Timeout:  c.durationValue("SMTP_TIMEOUT", mail.DefaultTimeout),
+	}
+}
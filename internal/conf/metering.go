@@ -0,0 +1,14 @@
+package conf
+
+import (
+	"github.com/rusgainew/tunduck-app/pkg/metering"
+)
+
+// MeteringConfig читает параметры учета потребления для выставления счетов: METERING_ENABLED
+// (по умолчанию true) и METERING_FLUSH_INTERVAL (1m)
+func (c *Conf) MeteringConfig() metering.Config {
+	return metering.Config{
+		Enabled:       c.boolValue("METERING_ENABLED", true),
+		FlushInterval: c.durationValue("METERING_FLUSH_INTERVAL", metering.DefaultFlushInterval),
+	}
+}
@@ -0,0 +1,108 @@
+package conf
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Параметры пула соединений по умолчанию
+const (
+	defaultMaxOpenConns    = 100
+	defaultMaxIdleConns    = 10
+	defaultConnMaxLifetime = time.Hour
+)
+
+// PoolConfig параметры пула соединений database/sql
+type PoolConfig struct {
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// PoolConfig читает параметры пула из DB_MAX_OPEN_CONNS, DB_MAX_IDLE_CONNS,
+// DB_CONN_MAX_LIFETIME и DB_CONN_MAX_IDLE_TIME. Некорректные значения заменяются значениями по умолчанию.
+func (c *Conf) PoolConfig() PoolConfig {
+	return PoolConfig{
+		MaxOpenConns:    c.intValue("DB_MAX_OPEN_CONNS", defaultMaxOpenConns),
+		MaxIdleConns:    c.intValue("DB_MAX_IDLE_CONNS", defaultMaxIdleConns),
+		ConnMaxLifetime: c.durationValue("DB_CONN_MAX_LIFETIME", defaultConnMaxLifetime),
+		ConnMaxIdleTime: c.durationValue("DB_CONN_MAX_IDLE_TIME", 0),
+	}
+}
+
+// Apply применяет параметры к пулу
+func (p PoolConfig) Apply(sqlDB *sql.DB) {
+	sqlDB.SetMaxOpenConns(p.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(p.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(p.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(p.ConnMaxIdleTime)
+}
+
+func (c *Conf) intValue(key string, def int) int {
+	raw := c.GetConValue(key)
+	if raw == "" {
+		return def
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		c.log.WithField("key", key).Warn("Invalid integer value, using default")
+		return def
+	}
+	return value
+}
+
+func (c *Conf) durationValue(key string, def time.Duration) time.Duration {
+	raw := c.GetConValue(key)
+	if raw == "" {
+		return def
+	}
+
+	value, err := time.ParseDuration(raw)
+	if err != nil || value < 0 {
+		c.log.WithField("key", key).Warn("Invalid duration value, using default")
+		return def
+	}
+	return value
+}
+
+func (c *Conf) floatValue(key string, def float64) float64 {
+	raw := c.GetConValue(key)
+	if raw == "" {
+		return def
+	}
+
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil || value < 0 {
+		c.log.WithField("key", key).Warn("Invalid number value, using default")
+		return def
+	}
+	return value
+}
+
+func (c *Conf) boolValue(key string, def bool) bool {
+	raw := c.GetConValue(key)
+	if raw == "" {
+		return def
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		c.log.WithField("key", key).Warn("Invalid boolean value, using default")
+		return def
+	}
+	return value
+}
+
+func (c *Conf) listValue(key string) []string {
+	var items []string
+	for _, item := range strings.Split(c.GetConValue(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
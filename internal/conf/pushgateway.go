@@ -0,0 +1,33 @@
+package conf
+
+import (
+	"net/url"
+	"os"
+
+	"github.com/rusgainew/tunduck-app/pkg/metrics"
+)
+
+// PushgatewayConfig читает параметры отправки метрик команд CLI в Prometheus Pushgateway:
+// PUSHGATEWAY_URL (пустой — метрики не отправляются), PUSHGATEWAY_INSTANCE (метка instance группы)
+// и PUSHGATEWAY_TIMEOUT
+func (c *Conf) PushgatewayConfig() metrics.PushConfig {
+	return metrics.PushConfig{
+		URL:      c.GetConValue("PUSHGATEWAY_URL"),
+		Instance: c.GetConValue("PUSHGATEWAY_INSTANCE"),
+		Timeout:  c.durationValue("PUSHGATEWAY_TIMEOUT", metrics.DefaultPushTimeout),
+	}
+}
+
+// validatePushgateway проверяет адрес Pushgateway
+func validatePushgateway() []Problem {
+	raw := os.Getenv("PUSHGATEWAY_URL")
+	if raw == "" {
+		return nil
+	}
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		// Значение не выводится: URL может содержать пароль
+		return []Problem{{Key: "PUSHGATEWAY_URL", Message: "must be an http(s) URL such as http://pushgateway:9091"}}
+	}
+	return nil
+}
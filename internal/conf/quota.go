@@ -0,0 +1,27 @@
+package conf
+
+import (
+	"github.com/rusgainew/tunduck-app/pkg/quota"
+)
+
+// QuotaConfig читает параметры квот тарифных планов: QUOTA_ENABLED, QUOTA_PLANS (JSON,
+// по умолчанию quota.DefaultPlans), QUOTA_DEFAULT_PLAN (free), QUOTA_CACHE_TTL и QUOTA_UPGRADE_URL
+func (c *Conf) QuotaConfig() (quota.Config, error) {
+	cfg := quota.Config{
+		Enabled:     c.boolValue("QUOTA_ENABLED", false),
+		DefaultPlan: c.GetConValue("QUOTA_DEFAULT_PLAN"),
+		CacheTTL:    c.durationValue("QUOTA_CACHE_TTL", quota.DefaultCacheTTL),
+		UpgradeURL:  c.GetConValue("QUOTA_UPGRADE_URL"),
+	}
+	if cfg.DefaultPlan == "" {
+		cfg.DefaultPlan = "free"
+	}
+	if raw := c.GetConValue("QUOTA_PLANS"); raw != "" {
+		plans, err := quota.ParsePlans(raw)
+		if err != nil {
+			return quota.Config{}, err
+		}
+		cfg.Plans = plans
+	}
+	return cfg, nil
+}
@@ -0,0 +1,24 @@
+package conf
+
+import (
+	"github.com/rusgainew/tunduck-app/pkg/realtime"
+)
+
+// RealtimeConfig читает параметры уведомлений в реальном времени из REALTIME_PREFIX
+// (префикс каналов Redis pub/sub) и REALTIME_BUFFER_SIZE (очередь одного соединения).
+func (c *Conf) RealtimeConfig() realtime.Config {
+	return realtime.Config{
+		Prefix:     c.GetConValue("REALTIME_PREFIX"),
+		BufferSize: c.intValue("REALTIME_BUFFER_SIZE", realtime.DefaultBufferSize),
+	}
+}
+
+// WebSocketConfig читает параметры соединений /ws из WS_READ_LIMIT (байты), WS_PING_INTERVAL
+// (отрицательное значение отключает ping) и WS_PONG_TIMEOUT.
+func (c *Conf) WebSocketConfig() realtime.ConnConfig {
+	return realtime.ConnConfig{
+		ReadLimit:    int64(c.intValue("WS_READ_LIMIT", realtime.DefaultReadLimit)),
+		PingInterval: c.durationValue("WS_PING_INTERVAL", realtime.DefaultPingInterval),
+		PongTimeout:  c.durationValue("WS_PONG_TIMEOUT", realtime.DefaultPongTimeout),
+	}
+}
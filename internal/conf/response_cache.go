@@ -0,0 +1,13 @@
+package conf
+
+import "github.com/rusgainew/tunduck-app/pkg/middleware"
+
+// ResponseCacheConfig читает параметры кеширования ответов GET: RESPONSE_CACHE_ENABLED (по умолчанию true),
+// срок хранения в Redis RESPONSE_CACHE_TTL и срок повторного использования клиентом RESPONSE_CACHE_MAX_AGE
+func (c *Conf) ResponseCacheConfig() middleware.ResponseCacheConfig {
+	return middleware.ResponseCacheConfig{
+		Enabled: c.boolValue("RESPONSE_CACHE_ENABLED", true),
+		TTL:     c.durationValue("RESPONSE_CACHE_TTL", middleware.DefaultResponseCacheTTL),
+		MaxAge:  c.durationValue("RESPONSE_CACHE_MAX_AGE", middleware.DefaultResponseCacheMaxAge),
+	}
+}
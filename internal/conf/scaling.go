@@ -0,0 +1,54 @@
+package conf
+
+import (
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/rusgainew/tunduck-app/pkg/esfgateway"
+	"github.com/rusgainew/tunduck-app/pkg/storage"
+)
+
+// defaultLogFile файл журнала одиночного инстанса
+const defaultLogFile = "logs.log"
+
+// MultiInstance сообщает, что приложение запущено несколькими репликами за балансировщиком
+// (MULTI_INSTANCE). Общее состояние реплик хранится в Redis и Postgres; Validate отклоняет
+// настройки, которые хранят состояние в памяти или на диске одного инстанса.
+func (c *Conf) MultiInstance() bool {
+	return c.boolValue("MULTI_INSTANCE", false)
+}
+
+// LogFile путь к файлу журнала (LOG_FILE); пустое значение — только stdout. По умолчанию
+// logs.log, а с MULTI_INSTANCE файл не ведется: журнал собирается из stdout или LOG_SHIPPING_URL.
+func (c *Conf) LogFile() string {
+	if path, ok := os.LookupEnv("LOG_FILE"); ok {
+		return path
+	}
+	if c.MultiInstance() {
+		return ""
+	}
+	return defaultLogFile
+}
+
+// validateMultiInstance проверяет, что с MULTI_INSTANCE нет состояния, привязанного к одному инстансу.
+// Остаются ограничения, которые проверить нельзя: с недоступным Redis кеш, лимиты запросов и флаги
+// функций работают в памяти каждой реплики до восстановления Redis.
+func validateMultiInstance() []Problem {
+	multi, _ := strconv.ParseBool(os.Getenv("MULTI_INSTANCE"))
+	if !multi {
+		return nil
+	}
+
+	var problems []Problem
+	if backend := os.Getenv("STORAGE_BACKEND"); backend == "" || backend == storage.BackendLocal {
+		problems = append(problems, Problem{Key: "STORAGE_BACKEND", Message: "must be s3 with MULTI_INSTANCE: local files are not visible to other instances"})
+	}
+
+	gateway := strings.ToLower(os.Getenv("ESF_GATEWAY_BACKEND"))
+	configured := os.Getenv("ESF_GATEWAY_URL") != "" || os.Getenv("ESF_GATEWAY_ENDPOINTS") != ""
+	if gateway == esfgateway.BackendMock || (gateway == "" && !configured) {
+		problems = append(problems, Problem{Key: "ESF_GATEWAY_BACKEND", Message: "mock gateway keeps invoices in one instance's memory; set ESF_GATEWAY_URL with MULTI_INSTANCE"})
+	}
+	return problems
+}
@@ -0,0 +1,29 @@
+package conf
+
+import (
+	"time"
+
+	"github.com/rusgainew/tunduck-app/pkg/scheduler"
+)
+
+// SchedulerConfig читает параметры планировщика из SCHEDULER_PREFIX и SCHEDULER_TIMEZONE
+// (IANA, например "Asia/Bishkek"; по умолчанию часовой пояс сервера).
+func (c *Conf) SchedulerConfig() scheduler.Config {
+	cfg := scheduler.Config{Prefix: c.GetConValue("SCHEDULER_PREFIX")}
+
+	if tz := c.GetConValue("SCHEDULER_TIMEZONE"); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			c.log.WithField("timezone", tz).Warn("Invalid SCHEDULER_TIMEZONE, using server time zone")
+		} else {
+			cfg.Location = loc
+		}
+	}
+	return cfg
+}
+
+// SchedulerEnabled сообщает, запускать ли периодические задачи в этом процессе (SCHEDULER_ENABLED, по умолчанию true).
+// Блокировка в Redis гарантирует единственный запуск и при нескольких включенных репликах.
+func (c *Conf) SchedulerEnabled() bool {
+	return c.boolValue("SCHEDULER_ENABLED", true)
+}
@@ -0,0 +1,25 @@
+package conf
+
+import (
+	"github.com/rusgainew/tunduck-app/pkg/search"
+)
+
+// SearchConfig читает параметры OpenSearch из OPENSEARCH_URL, OPENSEARCH_USERNAME,
+// OPENSEARCH_PASSWORD, OPENSEARCH_INDEX_PREFIX, OPENSEARCH_TIMEOUT, OPENSEARCH_INDEX_INTERVAL
+// и OPENSEARCH_ORGANIZATIONS. Без OPENSEARCH_URL поиск выполняется средствами Postgres.
+func (c *Conf) SearchConfig() search.Config {
+	prefix := c.GetConValue("OPENSEARCH_INDEX_PREFIX")
+	if prefix == "" {
+		prefix = search.DefaultIndexPrefix
+	}
+
+	return search.Config{
+		URL:           c.GetConValue("OPENSEARCH_URL"),
+		Username:      c.GetConValue("OPENSEARCH_USERNAME"),
+		Password:      c.GetConValue("OPENSEARCH_PASSWORD"),
+		IndexPrefix:   prefix,
+		Timeout:       c.durationValue("OPENSEARCH_TIMEOUT", search.DefaultTimeout),
+		IndexInterval: c.durationValue("OPENSEARCH_INDEX_INTERVAL", search.DefaultIndexInterval),
+		Organizations: c.listValue("OPENSEARCH_ORGANIZATIONS"),
+	}
+}
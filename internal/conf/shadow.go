@@ -0,0 +1,44 @@
+package conf
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/rusgainew/tunduck-app/pkg/shadow"
+)
+
+// ShadowConfig читает параметры теневого трафика: SHADOW_MODE (mirror или record, пустой — выключен),
+// SHADOW_TARGET_URL, SHADOW_ROUTES (префиксы путей через запятую), SHADOW_SAMPLE_RATE, SHADOW_MAX_BODY_SIZE,
+// SHADOW_BUFFER_SIZE, SHADOW_WORKERS, SHADOW_TIMEOUT, SHADOW_BATCH_SIZE, SHADOW_FLUSH_INTERVAL и SHADOW_KEY_PREFIX
+func (c *Conf) ShadowConfig() shadow.Config {
+	return shadow.Config{
+		Mode:          strings.ToLower(c.GetConValue("SHADOW_MODE")),
+		TargetURL:     c.GetConValue("SHADOW_TARGET_URL"),
+		Routes:        c.listValue("SHADOW_ROUTES"),
+		SampleRate:    c.floatValue("SHADOW_SAMPLE_RATE", shadow.DefaultSampleRate),
+		MaxBodySize:   c.intValue("SHADOW_MAX_BODY_SIZE", shadow.DefaultMaxBodySize),
+		BufferSize:    c.intValue("SHADOW_BUFFER_SIZE", shadow.DefaultBufferSize),
+		Workers:       c.intValue("SHADOW_WORKERS", shadow.DefaultWorkers),
+		Timeout:       c.durationValue("SHADOW_TIMEOUT", shadow.DefaultTimeout),
+		BatchSize:     c.intValue("SHADOW_BATCH_SIZE", shadow.DefaultBatchSize),
+		FlushInterval: c.durationValue("SHADOW_FLUSH_INTERVAL", shadow.DefaultFlushInterval),
+		KeyPrefix:     c.GetConValue("SHADOW_KEY_PREFIX"),
+	}
+}
+
+// validateShadow проверяет режим теневого трафика и адрес второй установки
+func validateShadow() []Problem {
+	mode := strings.ToLower(os.Getenv("SHADOW_MODE"))
+	switch mode {
+	case "", shadow.ModeRecord:
+		return nil
+	case shadow.ModeMirror:
+		if target := os.Getenv("SHADOW_TARGET_URL"); !isHTTPURL(target) {
+			return []Problem{{Key: "SHADOW_TARGET_URL", Message: fmt.Sprintf("must be an http(s) URL for SHADOW_MODE=mirror, got %q", target)}}
+		}
+		return nil
+	default:
+		return []Problem{{Key: "SHADOW_MODE", Message: fmt.Sprintf("must be mirror or record, got %q", mode)}}
+	}
+}
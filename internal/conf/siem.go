@@ -0,0 +1,22 @@
+package conf
+
+import (
+	"strings"
+
+	"github.com/rusgainew/tunduck-app/pkg/siem"
+)
+
+// SIEMConfig читает параметры экспорта журнала аудита в SIEM из SIEM_SYSLOG_ADDRESS (host:port),
+// SIEM_SYSLOG_NETWORK (udp, tcp или tls), SIEM_APP_NAME, SIEM_FACILITY, SIEM_BUFFER_SIZE,
+// SIEM_ENQUEUE_TIMEOUT и SIEM_TIMEOUT. Без SIEM_SYSLOG_ADDRESS экспорт выключен.
+func (c *Conf) SIEMConfig() siem.Config {
+	return siem.Config{
+		Address:        c.GetConValue("SIEM_SYSLOG_ADDRESS"),
+		Network:        strings.ToLower(c.GetConValue("SIEM_SYSLOG_NETWORK")),
+		AppName:        c.GetConValue("SIEM_APP_NAME"),
+		Facility:       c.intValue("SIEM_FACILITY", siem.DefaultFacility),
+		BufferSize:     c.intValue("SIEM_BUFFER_SIZE", siem.DefaultBufferSize),
+		EnqueueTimeout: c.durationValue("SIEM_ENQUEUE_TIMEOUT", siem.DefaultEnqueueTimeout),
+		Timeout:        c.durationValue("SIEM_TIMEOUT", siem.DefaultTimeout),
+	}
+}
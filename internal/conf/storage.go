@@ -0,0 +1,43 @@
+package conf
+
+import (
+	"strings"
+	"time"
+
+	"github.com/rusgainew/tunduck-app/pkg/storage"
+)
+
+// StorageConfig читает параметры объектного хранилища из STORAGE_BACKEND (local или s3),
+// STORAGE_LOCAL_PATH, STORAGE_S3_ENDPOINT, STORAGE_S3_REGION, STORAGE_S3_BUCKET,
+// STORAGE_S3_ACCESS_KEY, STORAGE_S3_SECRET_KEY, STORAGE_S3_PATH_STYLE, STORAGE_TIMEOUT
+// и STORAGE_LIFECYCLE. STORAGE_LIFECYCLE задается как "exports/:168h,tmp/:24h"
+// (префикс:срок хранения).
+func (c *Conf) StorageConfig() storage.Config {
+	var rules []storage.LifecycleRule
+	for _, item := range c.listValue("STORAGE_LIFECYCLE") {
+		sep := strings.LastIndex(item, ":")
+		if sep < 0 {
+			c.log.WithField("rule", item).Warn("Invalid STORAGE_LIFECYCLE rule, skipping")
+			continue
+		}
+		expire, err := time.ParseDuration(strings.TrimSpace(item[sep+1:]))
+		if err != nil || expire <= 0 {
+			c.log.WithField("rule", item).Warn("Invalid STORAGE_LIFECYCLE duration, skipping")
+			continue
+		}
+		rules = append(rules, storage.LifecycleRule{Prefix: strings.TrimSpace(item[:sep]), ExpireAfter: expire})
+	}
+
+	return storage.Config{
+		Backend:   strings.ToLower(c.GetConValue("STORAGE_BACKEND")),
+		LocalPath: c.GetConValue("STORAGE_LOCAL_PATH"),
+		Endpoint:  c.GetConValue("STORAGE_S3_ENDPOINT"),
+		Region:    c.GetConValue("STORAGE_S3_REGION"),
+		Bucket:    c.GetConValue("STORAGE_S3_BUCKET"),
+		AccessKey: c.GetConValue("STORAGE_S3_ACCESS_KEY"),
+		SecretKey: c.GetConValue("STORAGE_S3_SECRET_KEY"),
+		PathStyle: c.boolValue("STORAGE_S3_PATH_STYLE", false),
+		Timeout:   c.durationValue("STORAGE_TIMEOUT", storage.DefaultTimeout),
+		Lifecycle: rules,
+	}
+}
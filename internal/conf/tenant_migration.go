@@ -0,0 +1,30 @@
+package conf
+
+import (
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/jobs"
+)
+
+// TenantMigrationConfig читает параметры миграции БД организаций из TENANT_MIGRATION_CONCURRENCY
+// и TENANT_MIGRATION_QUEUE
+func (c *Conf) TenantMigrationConfig() services.TenantMigrationConfig {
+	queue := c.GetConValue("TENANT_MIGRATION_QUEUE")
+	if queue == "" {
+		queue = jobs.DefaultQueue
+	}
+
+	concurrency := c.intValue("TENANT_MIGRATION_CONCURRENCY", services.DefaultTenantMigrationConcurrency)
+	return services.TenantMigrationConfig{
+		Concurrency: min(max(concurrency, 1), services.MaxTenantMigrationConcurrency),
+		Queue:       queue,
+	}
+}
+
+// TenantCredentialConfig читает очередь задач ротации учетных данных БД организаций из TENANT_CREDENTIALS_QUEUE
+func (c *Conf) TenantCredentialConfig() services.TenantCredentialConfig {
+	queue := c.GetConValue("TENANT_CREDENTIALS_QUEUE")
+	if queue == "" {
+		queue = jobs.DefaultQueue
+	}
+	return services.TenantCredentialConfig{Queue: queue}
+}
@@ -0,0 +1,35 @@
+package conf
+
+import (
+	"os"
+	"time"
+
+	"github.com/rusgainew/tunduck-app/pkg/dates"
+)
+
+// DefaultTimezone читает часовой пояс организаций, для которых он не задан, из DEFAULT_TIMEZONE
+// (IANA, по умолчанию dates.DefaultTimezone). По нему определяется "сегодня" при проверке дат документов.
+func (c *Conf) DefaultTimezone() *time.Location {
+	name := c.GetConValue("DEFAULT_TIMEZONE")
+	if name == "" {
+		name = dates.DefaultTimezone
+	}
+	loc, err := dates.LoadLocation(name)
+	if err != nil {
+		c.log.WithField("timezone", name).Warn("Invalid DEFAULT_TIMEZONE, using " + dates.DefaultTimezone)
+		loc, _ = dates.LoadLocation(dates.DefaultTimezone)
+	}
+	return loc
+}
+
+// validateTimezone проверяет часовой пояс по умолчанию
+func validateTimezone() []Problem {
+	name := os.Getenv("DEFAULT_TIMEZONE")
+	if name == "" {
+		return nil
+	}
+	if _, err := dates.LoadLocation(name); err != nil {
+		return []Problem{{Key: "DEFAULT_TIMEZONE", Message: "must be an IANA time zone such as Asia/Bishkek"}}
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package conf
+
+import (
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/jobs"
+)
+
+// TrashConfig читает параметры корзины документов из TRASH_RETENTION, TRASH_ASYNC_THRESHOLD и TRASH_QUEUE
+func (c *Conf) TrashConfig() services.TrashConfig {
+	queue := c.GetConValue("TRASH_QUEUE")
+	if queue == "" {
+		queue = jobs.DefaultQueue
+	}
+
+	return services.TrashConfig{
+		Retention:      c.durationValue("TRASH_RETENTION", services.DefaultTrashRetention),
+		AsyncThreshold: c.intValue("TRASH_ASYNC_THRESHOLD", services.DefaultTrashAsyncThreshold),
+		Queue:          queue,
+	}
+}
@@ -0,0 +1,157 @@
+package conf
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/rusgainew/tunduck-app/pkg/esfgateway"
+)
+
+// requiredVars переменные окружения, без которых приложение не запускается
+var requiredVars = []string{
+	"APP_HOST",
+	"APP_PORT",
+	"DB_HOST",
+	"DB_PORT",
+	"DB_USER",
+	"DB_NAME",
+	"DB_PASSWORD",
+	"REDIS_HOST",
+}
+
+// portVars переменные с номером порта
+var portVars = []string{"APP_PORT", "DB_PORT", "REDIS_PORT"}
+
+// sslModes допустимые значения DB_SSLMODE
+var sslModes = []string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}
+
+// Problem ошибка в значении переменной окружения
+type Problem struct {
+	Key     string
+	Message string
+}
+
+// ValidationError сводный отчет о всех ошибках конфигурации
+type ValidationError struct {
+	Problems []Problem
+}
+
+func (e *ValidationError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid configuration (%d problems):", len(e.Problems))
+	for _, p := range e.Problems {
+		fmt.Fprintf(&b, "\n  - %s: %s", p.Key, p.Message)
+	}
+	return b.String()
+}
+
+// Validate проверяет конфигурацию целиком: обязательные переменные БД и Redis, порты,
+// ключи подписи JWT, адреса шлюза ЭСФ, настройки нескольких реплик (MULTI_INSTANCE), внедрения сбоев, теневого трафика и канареечного выката. Возвращает *ValidationError со всеми найденными
+// ошибками, чтобы их можно было исправить за один перезапуск.
+func Validate() error {
+	var problems []Problem
+	add := func(key, format string, args ...interface{}) {
+		problems = append(problems, Problem{Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, key := range requiredVars {
+		if os.Getenv(key) == "" {
+			add(key, "is required")
+		}
+	}
+
+	for _, key := range portVars {
+		raw := os.Getenv(key)
+		if raw == "" {
+			continue
+		}
+		if port, err := strconv.Atoi(raw); err != nil || port < 1 || port > 65535 {
+			add(key, "must be a port number from 1 to 65535, got %q", raw)
+		}
+	}
+
+	if mode := os.Getenv("DB_SSLMODE"); mode != "" && !slices.Contains(sslModes, mode) {
+		add("DB_SSLMODE", "must be one of %s, got %q", strings.Join(sslModes, ", "), mode)
+	}
+
+	// JWT_SECRET может быть заменен расписанием ключей JWT_KEYS
+	if os.Getenv("JWT_SECRET") == "" && os.Getenv("JWT_KEYS") == "" {
+		add("JWT_SECRET", "is required (or set JWT_KEYS)")
+	} else if err := validateJWTKeys(); err != nil {
+		key := "JWT_SECRET"
+		if os.Getenv("JWT_KEYS") != "" {
+			key = "JWT_KEYS"
+		}
+		add(key, "%v", err)
+	}
+
+	// Ссылки на скачивание без SIGNED_URL_SECRET подписываются JWT_SECRET; с одним JWT_KEYS
+	// ключ подписи оказался бы пустым, и ссылки мог бы подделать кто угодно
+	if os.Getenv("SIGNED_URL_SECRET") == "" && os.Getenv("JWT_SECRET") == "" {
+		add("SIGNED_URL_SECRET", "is required when JWT_SECRET is not set")
+	}
+
+	problems = append(problems, validateESFGateway()...)
+	problems = append(problems, validateMultiInstance()...)
+	problems = append(problems, validateFaultInjection()...)
+	problems = append(problems, validateShadow()...)
+	problems = append(problems, validateCanary()...)
+	problems = append(problems, validatePushgateway()...)
+	problems = append(problems, validateTimezone()...)
+	problems = append(problems, validateCORS()...)
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+// validateESFGateway проверяет адреса шлюза ЭСФ. В продакшене шлюз обязателен: имитация
+// допускается только явным ESF_GATEWAY_BACKEND=mock.
+func validateESFGateway() []Problem {
+	var problems []Problem
+
+	backend := strings.ToLower(os.Getenv("ESF_GATEWAY_BACKEND"))
+	if backend != "" && backend != esfgateway.BackendHTTP && backend != esfgateway.BackendMock {
+		problems = append(problems, Problem{Key: "ESF_GATEWAY_BACKEND", Message: fmt.Sprintf("must be http or mock, got %q", backend)})
+	}
+
+	rawURL := os.Getenv("ESF_GATEWAY_URL")
+	if rawURL != "" && !isHTTPURL(rawURL) {
+		problems = append(problems, Problem{Key: "ESF_GATEWAY_URL", Message: fmt.Sprintf("must be an http(s) URL, got %q", rawURL)})
+	}
+
+	endpoints, err := esfgateway.ParseEndpoints(os.Getenv("ESF_GATEWAY_ENDPOINTS"))
+	if err != nil {
+		problems = append(problems, Problem{Key: "ESF_GATEWAY_ENDPOINTS", Message: err.Error()})
+	}
+	for _, e := range endpoints {
+		if !isHTTPURL(e.URL) {
+			problems = append(problems, Problem{Key: "ESF_GATEWAY_ENDPOINTS", Message: fmt.Sprintf("endpoint %s must be an http(s) URL, got %q", e.Name, e.URL)})
+		}
+	}
+
+	configured := rawURL != "" || os.Getenv("ESF_GATEWAY_ENDPOINTS") != ""
+	switch {
+	case backend == esfgateway.BackendHTTP && !configured:
+		problems = append(problems, Problem{Key: "ESF_GATEWAY_URL", Message: "is required for ESF_GATEWAY_BACKEND=http (or set ESF_GATEWAY_ENDPOINTS)"})
+	case backend == "" && !configured && isProductionEnv():
+		problems = append(problems, Problem{Key: "ESF_GATEWAY_URL", Message: "is required in production (or set ESF_GATEWAY_BACKEND=mock explicitly)"})
+	}
+	return problems
+}
+
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// isProductionEnv то же, что Conf.IsProduction, до создания Conf
+func isProductionEnv() bool {
+	env := strings.ToLower(strings.TrimSpace(os.Getenv("APP_ENV")))
+	return env == "prod" || env == "production"
+}
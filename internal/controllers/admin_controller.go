@@ -0,0 +1,629 @@
+package controllers
+
+import (
+	"bufio"
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/jobs"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+	"github.com/rusgainew/tunduck-app/pkg/quota"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+	"github.com/rusgainew/tunduck-app/pkg/validation"
+)
+
+type AdminController struct {
+	logger           *logger.Logger
+	migrationService services.MigrationService
+	userService      services.UserService
+	orgService       services.EsfOrganizationService
+	documentService  services.EsfDocumentService
+	jobManager       *jobs.Manager
+	emailService     services.EmailService
+	auditService     services.AuditService
+	deadLetters      services.DeadLetterService
+	eventReplay      services.EventReplayService
+	quotas           *quota.Enforcer
+	tenantMigrations services.TenantMigrationService
+	tenantCreds      services.TenantCredentialService
+}
+
+// NewAdminController регистрирует административные маршруты; сервисы берутся из контейнера
+func NewAdminController(
+	app *fiber.App,
+	log *logrus.Logger,
+	migrationService services.MigrationService,
+	userService services.UserService,
+	orgService services.EsfOrganizationService,
+	documentService services.EsfDocumentService,
+	jobManager *jobs.Manager,
+	emailService services.EmailService,
+	auditService services.AuditService,
+	deadLetters services.DeadLetterService,
+	eventReplay services.EventReplayService,
+	quotas *quota.Enforcer,
+	tenantMigrations services.TenantMigrationService,
+	tenantCreds services.TenantCredentialService,
+) {
+	controller := &AdminController{
+		logger:           logger.New(log),
+		migrationService: migrationService,
+		userService:      userService,
+		orgService:       orgService,
+		documentService:  documentService,
+		jobManager:       jobManager,
+		emailService:     emailService,
+		auditService:     auditService,
+		deadLetters:      deadLetters,
+		eventReplay:      eventReplay,
+		quotas:           quotas,
+		tenantMigrations: tenantMigrations,
+		tenantCreds:      tenantCreds,
+	}
+
+	controller.logger.Info(context.Background(), "AdminController инициализирован")
+	controller.registerRoutes(app)
+}
+
+func (c *AdminController) registerRoutes(app *fiber.App) {
+	// Все маршруты требуют JWT и роль администратора
+	admin := app.Group("/api/admin")
+	admin.Use(middleware.JWTMiddleware())
+	admin.Use(rbac.RequireAdminRole())
+
+	admin.Get("/migrations", c.getMigrations)
+
+	// Миграция БД всех организаций фоновой задачей с итогом по каждой БД и возобновлением
+	admin.Post("/migrations/tenants", c.migrateTenants)
+	admin.Get("/migrations/tenants/:id", c.getTenantMigration)
+	admin.Post("/migrations/tenants/:id/resume", c.resumeTenantMigration)
+
+	// Ротация учетных данных БД организаций без простоя
+	admin.Post("/tenant-credentials/rotate", c.rotateTenantCredentials)
+
+	// Дашборд фоновых задач: размеры очередей и последние задачи в dead
+	admin.Get("/jobs", c.getJobStats)
+	admin.Get("/jobs/dead/:queue", c.getDeadJobs)
+	admin.Get("/jobs/scheduled", c.getScheduledJobs)
+	admin.Delete("/jobs/scheduled/:key", c.cancelScheduledJob)
+
+	// Очередь недоставленных сообщений: просмотр полезной нагрузки и повторная постановка
+	admin.Get("/dead-letters", c.getDeadLetters)
+	admin.Get("/dead-letters/:id", c.getDeadLetter)
+	admin.Post("/dead-letters/requeue", c.requeueDeadLetters)
+
+	// Повторная отправка опубликованных доменных событий организации потребителям
+	admin.Post("/events/replay", c.replayEvents)
+
+	// Журнал доставки писем
+	admin.Get("/emails", c.getEmailDeliveries)
+	admin.Get("/emails/:id", c.getEmailDelivery)
+
+	// Журнал аудита изменяющих запросов
+	admin.Get("/audit", c.getAuditLogs)
+	admin.Get("/audit/export", c.exportAuditLogs)
+
+	// Корзина: восстановление и окончательное удаление мягко удаленных записей
+	admin.Post("/users/:id/restore", c.restoreUser)
+	admin.Delete("/users/:id/purge", c.purgeUser)
+	// Тарифный план организации, определяющий ее квоты
+	admin.Put("/organizations/:id/plan", c.setOrganizationPlan)
+
+	admin.Post("/organizations/:id/restore", c.restoreOrganization)
+	admin.Delete("/organizations/:id/purge", c.purgeOrganization)
+	admin.Post("/organizations/:org_id/documents/:id/restore", c.restoreDocument)
+	admin.Delete("/organizations/:org_id/documents/:id/purge", c.purgeDocument)
+}
+
+// getMigrations возвращает статус миграций основной БД и БД организаций.
+// С ?dry_run=true добавляет SQL, который выполнят неприменные миграции.
+func (c *AdminController) getMigrations(ctx *fiber.Ctx) error {
+	dryRun := ctx.QueryBool("dry_run", false)
+	c.logger.Info(ctx.Context(), "Получение статуса миграций", logrus.Fields{"dry_run": dryRun})
+
+	report, err := c.migrationService.GetStatus(ctx.Context(), dryRun)
+	if err != nil {
+		appErr := apperror.From(err, apperror.ErrInternal, "failed to get migration status")
+		c.logger.Error(ctx.Context(), "Ошибка получения статуса миграций", err)
+		return response.Error(ctx, appErr)
+	}
+
+	return response.OK(ctx, report)
+}
+
+// tenantMigrationRequest тело запроса миграции БД организаций; поле необязательно
+type tenantMigrationRequest struct {
+	Concurrency int `json:"concurrency"`
+}
+
+// migrateTenants ставит миграцию БД всех организаций и отвечает 202 со ссылкой на операцию
+func (c *AdminController) migrateTenants(ctx *fiber.Ctx) error {
+	return c.queueTenantMigration(ctx, "Tenant migration queued", c.tenantMigrations.Start)
+}
+
+// resumeTenantMigration повторяет миграцию для БД, которые не удалось мигрировать
+func (c *AdminController) resumeTenantMigration(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return response.Error(ctx, apperror.ValidationError("invalid UUID format"))
+	}
+
+	return c.queueTenantMigration(ctx, "Tenant migration resumed", func(rctx context.Context, req services.TenantMigrationRequest) (*entity.Operation, error) {
+		return c.tenantMigrations.Resume(rctx, id, req)
+	})
+}
+
+func (c *AdminController) queueTenantMigration(ctx *fiber.Ctx, message string, queue func(context.Context, services.TenantMigrationRequest) (*entity.Operation, error)) error {
+	if c.tenantMigrations == nil {
+		return response.Error(ctx, apperror.New(apperror.ErrConfigError, "tenant migrations are not configured"))
+	}
+
+	var body tenantMigrationRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&body); err != nil {
+			return response.Error(ctx, apperror.ValidationError("invalid request body"))
+		}
+	}
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrUnauthorized, "unauthorized"))
+	}
+
+	op, err := queue(ctx.Context(), services.TenantMigrationRequest{RequestedBy: userID.String(), Concurrency: body.Concurrency})
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка постановки миграции БД организаций", err)
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to queue tenant migration"))
+	}
+
+	ctx.Set(fiber.HeaderLocation, services.OperationLocation(op.ID))
+	return response.Success(ctx, fiber.StatusAccepted, message, op)
+}
+
+// getTenantMigration возвращает итог миграции: состояние операции и результат каждой БД
+func (c *AdminController) getTenantMigration(ctx *fiber.Ctx) error {
+	if c.tenantMigrations == nil {
+		return response.Error(ctx, apperror.New(apperror.ErrConfigError, "tenant migrations are not configured"))
+	}
+
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return response.Error(ctx, apperror.ValidationError("invalid UUID format"))
+	}
+
+	summary, err := c.tenantMigrations.GetSummary(ctx.Context(), id)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to get tenant migration"))
+	}
+
+	return response.OK(ctx, summary)
+}
+
+// rotateTenantCredentials ставит ротацию учетных данных БД организаций и отвечает 202 со ссылкой на операцию.
+// Пустой organizationIds — все организации.
+func (c *AdminController) rotateTenantCredentials(ctx *fiber.Ctx) error {
+	if c.tenantCreds == nil {
+		return response.Error(ctx, apperror.New(apperror.ErrConfigError, "tenant credential rotation is not configured"))
+	}
+
+	var req services.TenantCredentialRotationRequest
+	if len(ctx.Body()) > 0 {
+		if err := ctx.BodyParser(&req); err != nil {
+			return response.Error(ctx, apperror.ValidationError("invalid request body"))
+		}
+	}
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrUnauthorized, "unauthorized"))
+	}
+	req.RequestedBy = userID.String()
+
+	op, err := c.tenantCreds.Rotate(ctx.Context(), req)
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка постановки ротации учетных данных БД организаций", err)
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to queue credential rotation"))
+	}
+
+	ctx.Set(fiber.HeaderLocation, services.OperationLocation(op.ID))
+	return response.Success(ctx, fiber.StatusAccepted, "Tenant credential rotation queued", op)
+}
+
+// getJobStats возвращает состояние очередей фоновых задач
+func (c *AdminController) getJobStats(ctx *fiber.Ctx) error {
+	if c.jobManager == nil {
+		return response.Error(ctx, apperror.New(apperror.ErrConfigError, "background jobs are not configured"))
+	}
+
+	stats, err := c.jobManager.Stats(ctx.Context())
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка получения статистики фоновых задач", err)
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to get job stats"))
+	}
+
+	return response.OK(ctx, stats)
+}
+
+// getDeadJobs возвращает последние задачи очереди, исчерпавшие попытки (?limit, по умолчанию 50)
+func (c *AdminController) getDeadJobs(ctx *fiber.Ctx) error {
+	if c.jobManager == nil {
+		return response.Error(ctx, apperror.New(apperror.ErrConfigError, "background jobs are not configured"))
+	}
+
+	limit := ctx.QueryInt("limit", 50)
+	if limit < 1 || limit > 1000 {
+		return response.Error(ctx, apperror.ValidationError("invalid limit"))
+	}
+
+	queue := ctx.Params("queue")
+	dead, err := c.jobManager.DeadJobs(ctx.Context(), queue, int64(limit))
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка получения неудавшихся задач", err, logrus.Fields{"queue": queue})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to get job stats"))
+	}
+
+	return response.OK(ctx, dead)
+}
+
+// getScheduledJobs возвращает отложенные задачи и повторы в порядке запуска (?limit, по умолчанию 50, и ?offset)
+func (c *AdminController) getScheduledJobs(ctx *fiber.Ctx) error {
+	if c.jobManager == nil {
+		return response.Error(ctx, apperror.New(apperror.ErrConfigError, "background jobs are not configured"))
+	}
+
+	limit := ctx.QueryInt("limit", 50)
+	if limit < 1 || limit > 1000 {
+		return response.Error(ctx, apperror.ValidationError("invalid limit"))
+	}
+	offset := ctx.QueryInt("offset", 0)
+	if offset < 0 {
+		return response.Error(ctx, apperror.ValidationError("invalid offset"))
+	}
+
+	scheduled, total, err := c.jobManager.Scheduled(ctx.Context(), int64(offset), int64(limit))
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка получения отложенных задач", err)
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to get scheduled jobs"))
+	}
+
+	return response.List(ctx, scheduled, pagination.NewOffsetInfo(offset, limit, total))
+}
+
+// cancelScheduledJob снимает с расписания отложенную задачу с ключом :key
+func (c *AdminController) cancelScheduledJob(ctx *fiber.Ctx) error {
+	if c.jobManager == nil {
+		return response.Error(ctx, apperror.New(apperror.ErrConfigError, "background jobs are not configured"))
+	}
+
+	key := ctx.Params("key")
+	cancelled, err := c.jobManager.Cancel(ctx.Context(), key)
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка отмены отложенной задачи", err, logrus.Fields{"key": key})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to cancel scheduled job"))
+	}
+	if !cancelled {
+		return response.Error(ctx, apperror.NotFoundError("scheduled job"))
+	}
+
+	return response.SuccessNoContent(ctx)
+}
+
+// getDeadLetters возвращает очередь недоставленных сообщений с пагинацией; ?source, ?type и ?status фильтруют
+func (c *AdminController) getDeadLetters(ctx *fiber.Ctx) error {
+	params := pagination.ExtractPaginationParams(ctx)
+	filter := repository.DeadLetterFilter{
+		Source: ctx.Query("source"),
+		Type:   ctx.Query("type"),
+		Status: ctx.Query("status"),
+	}
+
+	letters, total, err := c.deadLetters.ListDeadLetters(ctx.Context(), params, filter)
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка получения очереди недоставленных сообщений", err)
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to fetch dead letters"))
+	}
+
+	return response.List(ctx, letters, pagination.NewPaginationInfo(params.Page, params.PageSize, total))
+}
+
+// getDeadLetter возвращает запись очереди вместе с полезной нагрузкой
+func (c *AdminController) getDeadLetter(ctx *fiber.Ctx) error {
+	raw := ctx.Params("id")
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		c.logger.Warn(ctx.Context(), "Невалидный формат UUID", logrus.Fields{"id": raw})
+		return response.Error(ctx, apperror.ValidationError("invalid UUID format"))
+	}
+
+	letter, err := c.deadLetters.GetDeadLetter(ctx.Context(), id)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to fetch dead letters"))
+	}
+
+	return response.OK(ctx, letter)
+}
+
+// requeueDeadLettersRequest тело запроса повторной постановки
+type requeueDeadLettersRequest struct {
+	IDs []uuid.UUID `json:"ids"`
+}
+
+// requeueDeadLetters ставит выбранные записи обратно в обработку
+func (c *AdminController) requeueDeadLetters(ctx *fiber.Ctx) error {
+	var req requeueDeadLettersRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return response.Error(ctx, apperror.ValidationError("invalid request body"))
+	}
+
+	result, err := c.deadLetters.Requeue(ctx.Context(), req.IDs)
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка повторной постановки сообщений", err, logrus.Fields{"count": len(req.IDs)})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to requeue dead letters"))
+	}
+
+	c.logger.Info(ctx.Context(), "Сообщения поставлены в обработку повторно", logrus.Fields{
+		"requeued": len(result.Requeued),
+		"failed":   len(result.Failed),
+	})
+	return response.OK(ctx, result)
+}
+
+// replayEvents повторно публикует события организации за период
+func (c *AdminController) replayEvents(ctx *fiber.Ctx) error {
+	if c.eventReplay == nil {
+		return response.Error(ctx, apperror.New(apperror.ErrConfigError, "domain events are not configured"))
+	}
+
+	var req services.EventReplayRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		return response.Error(ctx, apperror.ValidationError("invalid request body"))
+	}
+
+	result, err := c.eventReplay.ReplayEvents(ctx.Context(), req)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to replay events"))
+	}
+
+	return response.OK(ctx, result)
+}
+
+// getEmailDeliveries возвращает журнал доставки писем с пагинацией; ?status фильтрует по статусу
+func (c *AdminController) getEmailDeliveries(ctx *fiber.Ctx) error {
+	if c.emailService == nil {
+		return response.Error(ctx, apperror.New(apperror.ErrConfigError, "email delivery is not configured"))
+	}
+
+	params := pagination.ExtractPaginationParams(ctx)
+	status := ctx.Query("status")
+
+	deliveries, total, err := c.emailService.ListDeliveries(ctx.Context(), params, status)
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка получения журнала писем", err, logrus.Fields{"status": status})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to fetch email deliveries"))
+	}
+
+	return response.List(ctx, deliveries, pagination.NewPaginationInfo(params.Page, params.PageSize, total))
+}
+
+// getEmailDelivery возвращает статус доставки одного письма
+func (c *AdminController) getEmailDelivery(ctx *fiber.Ctx) error {
+	if c.emailService == nil {
+		return response.Error(ctx, apperror.New(apperror.ErrConfigError, "email delivery is not configured"))
+	}
+
+	raw := ctx.Params("id")
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		c.logger.Warn(ctx.Context(), "Невалидный формат UUID", logrus.Fields{"id": raw})
+		return response.Error(ctx, apperror.ValidationError("invalid UUID format"))
+	}
+
+	delivery, err := c.emailService.GetDelivery(ctx.Context(), id)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to fetch email deliveries"))
+	}
+
+	return response.OK(ctx, delivery)
+}
+
+// auditExportTimeout ограничивает длительность выгрузки журнала аудита
+const auditExportTimeout = 10 * time.Minute
+
+// getAuditLogs возвращает журнал аудита с пагинацией и фильтрами
+func (c *AdminController) getAuditLogs(ctx *fiber.Ctx) error {
+	filter, appErr := auditFilter(ctx)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+	params := pagination.ExtractPaginationParams(ctx)
+
+	logs, total, err := c.auditService.ListLogs(ctx.Context(), params, filter)
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка получения журнала аудита", err)
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to fetch audit logs"))
+	}
+
+	return response.List(ctx, logs, pagination.NewPaginationInfo(params.Page, params.PageSize, total))
+}
+
+// exportAuditLogs выгружает журнал аудита потоком в CSV (по умолчанию) или NDJSON
+func (c *AdminController) exportAuditLogs(ctx *fiber.Ctx) error {
+	filter, appErr := auditFilter(ctx)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	format := ctx.Query("format", services.ExportFormatCSV)
+	contentType := "text/csv; charset=utf-8"
+	switch format {
+	case services.ExportFormatCSV:
+	case services.ExportFormatNDJSON:
+		contentType = "application/x-ndjson"
+	default:
+		return response.Error(ctx, apperror.ValidationError("invalid export format"))
+	}
+
+	c.logger.Info(ctx.Context(), "Выгрузка журнала аудита", logrus.Fields{"format": format})
+
+	ctx.Set(fiber.HeaderContentType, contentType)
+	ctx.Set(fiber.HeaderContentDisposition, `attachment; filename="audit-log.`+format+`"`)
+	ctx.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		// Поток пишется после возврата из обработчика, поэтому контекст запроса не используется
+		exportCtx, cancel := context.WithTimeout(context.Background(), auditExportTimeout)
+		defer cancel()
+
+		if err := c.auditService.ExportLogs(exportCtx, filter, format, w); err != nil {
+			c.logger.Error(exportCtx, "Ошибка выгрузки журнала аудита", err, logrus.Fields{"format": format})
+		}
+		_ = w.Flush()
+	})
+	return nil
+}
+
+// auditFilter читает фильтры журнала аудита из query: actor_id, organization_id, entity_type,
+// entity_id, action, from и to (RFC 3339)
+func auditFilter(ctx *fiber.Ctx) (repository.AuditLogFilter, *apperror.AppError) {
+	filter := repository.AuditLogFilter{
+		ActorID:        ctx.Query("actor_id"),
+		OrganizationID: ctx.Query("organization_id"),
+		EntityType:     ctx.Query("entity_type"),
+		EntityID:       ctx.Query("entity_id"),
+		Action:         ctx.Query("action"),
+	}
+
+	for key, target := range map[string]**time.Time{"from": &filter.From, "to": &filter.To} {
+		raw := ctx.Query(key)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return filter, apperror.ValidationError("invalid date range")
+		}
+		*target = &t
+	}
+	return filter, nil
+}
+
+// restoreUser восстанавливает удаленного пользователя
+func (c *AdminController) restoreUser(ctx *fiber.Ctx) error {
+	return c.trashAction(ctx, "id", "failed to restore user", "Пользователь восстановлен", func(id uuid.UUID) error {
+		return c.userService.RestoreUser(ctx.Context(), id)
+	})
+}
+
+// purgeUser окончательно удаляет пользователя из корзины
+func (c *AdminController) purgeUser(ctx *fiber.Ctx) error {
+	return c.trashAction(ctx, "id", "failed to purge user", "Пользователь удален окончательно", func(id uuid.UUID) error {
+		return c.userService.PurgeUser(ctx.Context(), id)
+	})
+}
+
+// organizationPlanResource тарифный план, назначенный организации
+type organizationPlanResource struct {
+	ID   string `json:"id"`
+	Plan string `json:"plan"`
+}
+
+// setOrganizationPlan назначает организации тарифный план из QUOTA_PLANS и сбрасывает кеш ее расхода
+func (c *AdminController) setOrganizationPlan(ctx *fiber.Ctx) error {
+	if c.quotas == nil {
+		return response.Error(ctx, apperror.New(apperror.ErrConfigError, "plan quotas are not enabled"))
+	}
+
+	raw := ctx.Params("id")
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return response.Error(ctx, apperror.ValidationError("invalid UUID format"))
+	}
+
+	var req models.OrganizationPlanRequest
+	if appErr := validation.ParseBody(ctx, &req); appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+	if !c.quotas.HasPlan(req.Plan) {
+		return response.Error(ctx, apperror.ValidationError("unknown plan: {plan}").WithParams(map[string]interface{}{"plan": req.Plan}))
+	}
+
+	if err := c.orgService.SetPlan(ctx.Context(), id, req.Plan); err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка назначения тарифного плана", err, logrus.Fields{"id": raw})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to set organization plan"))
+	}
+	if err := c.quotas.Invalidate(ctx.Context(), id); err != nil {
+		// Расход перечитается из БД после истечения QUOTA_CACHE_TTL
+		c.logger.Warn(ctx.Context(), "Не удалось сбросить кеш расхода организации", logrus.Fields{"id": raw, "error": err.Error()})
+	}
+
+	c.logger.Info(ctx.Context(), "Тарифный план назначен", logrus.Fields{"id": raw, "plan": req.Plan})
+	return response.SuccessOK(ctx, "Тарифный план назначен", organizationPlanResource{ID: raw, Plan: req.Plan})
+}
+
+// restoreOrganization восстанавливает удаленную организацию
+func (c *AdminController) restoreOrganization(ctx *fiber.Ctx) error {
+	return c.trashAction(ctx, "id", "failed to restore organization", "Организация восстановлена", func(id uuid.UUID) error {
+		return c.orgService.RestoreOrganization(ctx.Context(), id)
+	})
+}
+
+// purgeOrganization окончательно удаляет организацию из корзины
+func (c *AdminController) purgeOrganization(ctx *fiber.Ctx) error {
+	return c.trashAction(ctx, "id", "failed to purge organization", "Организация удалена окончательно", func(id uuid.UUID) error {
+		return c.orgService.PurgeOrganization(ctx.Context(), id)
+	})
+}
+
+// restoreDocument восстанавливает удаленный документ организации
+func (c *AdminController) restoreDocument(ctx *fiber.Ctx) error {
+	return c.trashAction(ctx, "org_id", "failed to restore document", "Документ восстановлен", func(orgID uuid.UUID) error {
+		return c.withDocumentID(ctx, func(id uuid.UUID) error {
+			return c.documentService.RestoreDocument(ctx.Context(), orgID, id)
+		})
+	})
+}
+
+// purgeDocument окончательно удаляет документ организации из корзины
+func (c *AdminController) purgeDocument(ctx *fiber.Ctx) error {
+	return c.trashAction(ctx, "org_id", "failed to purge document", "Документ удален окончательно", func(orgID uuid.UUID) error {
+		return c.withDocumentID(ctx, func(id uuid.UUID) error {
+			return c.documentService.PurgeDocument(ctx.Context(), orgID, id)
+		})
+	})
+}
+
+// withDocumentID разбирает параметр id документа
+func (c *AdminController) withDocumentID(ctx *fiber.Ctx, fn func(id uuid.UUID) error) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return apperror.ValidationError("invalid UUID format")
+	}
+	return fn(id)
+}
+
+// trashAction разбирает UUID из параметра маршрута, выполняет действие и формирует ответ
+func (c *AdminController) trashAction(ctx *fiber.Ctx, param, failMsg, successMsg string, fn func(id uuid.UUID) error) error {
+	raw := ctx.Params(param)
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		c.logger.Warn(ctx.Context(), "Невалидный формат UUID", logrus.Fields{param: raw})
+		return response.Error(ctx, apperror.ValidationError("invalid UUID format"))
+	}
+
+	if err := fn(id); err != nil {
+		appErr := apperror.From(err, apperror.ErrInternal, failMsg)
+		c.logger.Error(ctx.Context(), "Ошибка операции с корзиной", err, logrus.Fields{param: raw, "path": ctx.Path()})
+		return response.Error(ctx, appErr)
+	}
+
+	c.logger.Info(ctx.Context(), successMsg, logrus.Fields{param: raw, "path": ctx.Path()})
+	return response.SuccessOK(ctx, successMsg, fiber.Map{"id": ctx.Params("id")})
+}
@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"context"
+	"path"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/quota"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+	"github.com/rusgainew/tunduck-app/pkg/signature"
+	"github.com/rusgainew/tunduck-app/pkg/validation"
+)
+
+type AttachmentController struct {
+	logger      *logger.Logger
+	attachments services.AttachmentService
+	quotas      *quota.Enforcer
+}
+
+// NewAttachmentController регистрирует маршруты вложений и скачивания по подписанным ссылкам.
+// quotas ограничивает место под вложения тарифным планом (nil — без ограничения)
+func NewAttachmentController(app *fiber.App, log *logrus.Logger, attachments services.AttachmentService, quotas *quota.Enforcer) {
+	controller := &AttachmentController{
+		logger:      logger.New(log),
+		attachments: attachments,
+		quotas:      quotas,
+	}
+
+	controller.logger.Info(context.Background(), "AttachmentController инициализирован")
+	controller.registerRoutes(app)
+}
+
+func (c *AttachmentController) registerRoutes(app *fiber.App) {
+	// Скачивание по подписанной ссылке (без JWT): браузер и ссылки из писем
+	app.Get(services.FilesPath+"*", c.download)
+
+	attachments := app.Group("/api/attachments")
+	attachments.Use(middleware.JWTMiddleware())
+	attachments.Post("/", quota.Require(c.quotas, quota.ResourceStorage, resolveOrgID, quota.FormFileSize("file")), c.upload)
+	attachments.Post("/links", c.createLink)
+}
+
+// upload сохраняет файл организации (multipart, поле file) и возвращает ссылку на скачивание
+func (c *AttachmentController) upload(ctx *fiber.Ctx) error {
+	orgID, err := resolveOrgID(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "invalid organization ID"))
+	}
+
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		return response.Error(ctx, apperror.ValidationError("file is required (multipart field file)"))
+	}
+	file, err := header.Open()
+	if err != nil {
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "failed to read uploaded file"))
+	}
+	defer file.Close()
+
+	link, err := c.attachments.Upload(ctx.Context(), orgID, services.AttachmentUpload{
+		FileName:    header.Filename,
+		ContentType: header.Header.Get(fiber.HeaderContentType),
+		Size:        header.Size,
+		Body:        file,
+	})
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to upload attachment"))
+	}
+	return response.Success(ctx, fiber.StatusCreated, "Attachment uploaded", link)
+}
+
+// createLink выдает новую подписанную ссылку на вложение организации, например для письма
+func (c *AttachmentController) createLink(ctx *fiber.Ctx) error {
+	orgID, err := resolveOrgID(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "invalid organization ID"))
+	}
+
+	var req models.AttachmentLinkRequest
+	if appErr := validation.ParseBody(ctx, &req); appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+	var ttl time.Duration
+	if req.ExpiresIn != "" {
+		if ttl, err = time.ParseDuration(req.ExpiresIn); err != nil || ttl <= 0 {
+			return response.Error(ctx, apperror.ValidationError("expiresIn must be a positive duration, e.g. 24h"))
+		}
+	}
+
+	link, err := c.attachments.CreateLink(ctx.Context(), orgID, req.Key, ttl)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to create attachment link"))
+	}
+	return response.OK(ctx, link)
+}
+
+// download отдает вложение по подписанной ссылке
+func (c *AttachmentController) download(ctx *fiber.Ctx) error {
+	key := ctx.Params("*")
+	r, info, err := c.attachments.Open(ctx.Context(), key, ctx.Query(signature.QueryExpires), ctx.Query(signature.QuerySignature))
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to download attachment"))
+	}
+
+	contentType := info.ContentType
+	if contentType == "" {
+		contentType = fiber.MIMEOctetStream
+	}
+	ctx.Set(fiber.HeaderContentType, contentType)
+	ctx.Set(fiber.HeaderContentDisposition, `attachment; filename="`+path.Base(key)+`"`)
+	ctx.Set(fiber.HeaderCacheControl, "private, no-store")
+	// Поток закрывается fasthttp после отправки ответа
+	return ctx.SendStream(r, int(info.Size))
+}
@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/internal/services/service_impl"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/storage"
+	"github.com/rusgainew/tunduck-app/pkg/testutil"
+)
+
+func TestAttachmentController_SignedDownload(t *testing.T) {
+	h := testutil.NewHarness(t)
+	store, err := storage.New(storage.Config{Backend: storage.BackendLocal, LocalPath: t.TempDir()})
+	require.NoError(t, err)
+	attachments, err := service_impl.NewAttachmentService(store, services.AttachmentConfig{URLSecret: "attachment-secret"}, h.Logger)
+	require.NoError(t, err)
+	NewAttachmentController(h.App, h.Logger, attachments, nil)
+
+	orgID := uuid.New()
+	uploaded, err := attachments.Upload(context.Background(), orgID, services.AttachmentUpload{
+		FileName: "act.txt", ContentType: "text/plain", Size: 5, Body: strings.NewReader("hello"),
+	})
+	require.NoError(t, err)
+
+	// Ссылка открывается без JWT
+	resp := h.Do(http.MethodGet, uploaded.URL, nil)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+	assert.Equal(t, "hello", string(resp.Body))
+	assert.Equal(t, `attachment; filename="act.txt"`, resp.Header.Get(fiber.HeaderContentDisposition))
+
+	resp = h.Do(http.MethodGet, strings.Replace(uploaded.URL, "signature=", "signature=0", 1), nil)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+
+	user := testutil.NewUser()
+	token := testutil.WithToken(h.Token(user.ID.String(), user.Email))
+	body := map[string]string{"key": uploaded.Key, "expiresIn": "24h"}
+
+	resp = h.Do(http.MethodPost, "/api/attachments/links", body, testutil.WithHeader("X-Org-Id", orgID.String()))
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+	resp = h.Do(http.MethodPost, "/api/attachments/links", body, token, testutil.WithHeader("X-Org-Id", uuid.NewString()))
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode, "key of another organization")
+
+	resp = h.Do(http.MethodPost, "/api/attachments/links", map[string]string{"key": uploaded.Key, "expiresIn": "soon"}, token, testutil.WithHeader("X-Org-Id", orgID.String()))
+	assert.Equal(t, string(apperror.ErrValidation), resp.ErrorCode())
+
+	resp = h.Do(http.MethodPost, "/api/attachments/links", body, token, testutil.WithHeader("X-Org-Id", orgID.String()))
+	require.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+	var link services.AttachmentLink
+	resp.DecodeData(&link)
+	resp = h.Do(http.MethodGet, link.URL, nil)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+}
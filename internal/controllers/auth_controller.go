@@ -2,24 +2,25 @@ package controllers
 
 import (
 	"context"
-	"os"
 	"time"
 
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
 	"github.com/rusgainew/tunduck-app/internal/models"
 	"github.com/rusgainew/tunduck-app/internal/services"
 	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/auth"
 	"github.com/rusgainew/tunduck-app/pkg/cache"
 	"github.com/rusgainew/tunduck-app/pkg/logger"
 	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+	"github.com/rusgainew/tunduck-app/pkg/validation"
 	"github.com/sirupsen/logrus"
 )
 
 type AuthController struct {
 	logger       *logger.Logger
 	service      services.UserService
-	validate     *validator.Validate
+	validate     *validation.Validator
 	cacheManager cache.CacheManager
 }
 
@@ -30,7 +31,7 @@ func NewAuthController(app *fiber.App, userService services.UserService, log *lo
 	controller := &AuthController{
 		logger:       l,
 		service:      userService, // Используем сервис из контейнера
-		validate:     validator.New(),
+		validate:     validation.Default(),
 		cacheManager: cacheManager,
 	}
 
@@ -46,8 +47,12 @@ func (c *AuthController) registerRoutes(app *fiber.App, log *logrus.Logger) {
 	authGroup.Post("/login", c.login)
 
 	// Защищенные endpoints с JWT валидацией и поддержкой blacklist для logout
+	keys, err := auth.KeyRingFromEnv()
+	if err != nil {
+		log.WithError(err).Fatal("Invalid JWT signing keys")
+	}
 	protected := authGroup.Group("")
-	protected.Use(middleware.JWTBlacklistMiddleware(os.Getenv("JWT_SECRET"), log, c.cacheManager))
+	protected.Use(middleware.JWTBlacklistMiddleware(keys, log, c.cacheManager))
 	protected.Get("/me", c.getCurrentUser)
 	protected.Post("/logout", c.logout)
 }
@@ -65,35 +70,27 @@ func (c *AuthController) registerRoutes(app *fiber.App, log *logrus.Logger) {
 func (c *AuthController) register(ctx *fiber.Ctx) error {
 	var req models.RegisterRequest
 
-	if err := ctx.BodyParser(&req); err != nil {
-		c.logger.Warn(ctx.Context(), "Failed to parse register request", logrus.Fields{"error": err.Error()})
-		return ctx.Status(fiber.StatusBadRequest).JSON(apperror.New(apperror.ErrInvalidRequest, "invalid request format").ToResponse())
-	}
-
-	// Валидация
-	if err := c.validate.Struct(req); err != nil {
-		c.logger.Warn(ctx.Context(), "Validation failed for register request", logrus.Fields{"error": err.Error()})
-		return ctx.Status(fiber.StatusBadRequest).JSON(apperror.New(apperror.ErrValidation, "validation error").WithDetails(err.Error()).ToResponse())
+	// Разбор и валидация запроса
+	if appErr := c.validate.ParseBody(ctx, &req); appErr != nil {
+		c.logger.Warn(ctx.Context(), "Invalid register request", logrus.Fields{"code": appErr.Code})
+		return response.Error(ctx, appErr)
 	}
 
 	// Проверка совпадения паролей
 	if req.Password != req.ConfirmPassword {
 		c.logger.Warn(ctx.Context(), "Password mismatch during registration")
-		return ctx.Status(fiber.StatusBadRequest).JSON(apperror.New(apperror.ErrPasswordMismatch, "passwords do not match").ToResponse())
+		return response.Error(ctx, apperror.New(apperror.ErrPasswordMismatch, "passwords do not match"))
 	}
 
-	response, err := c.service.Register(ctx.Context(), &req)
+	authResponse, err := c.service.Register(ctx.Context(), &req)
 	if err != nil {
-		appErr, ok := err.(*apperror.AppError)
-		if !ok {
-			appErr = apperror.New(apperror.ErrInternal, "registration failed").WithError(err)
-		}
+		appErr := apperror.From(err, apperror.ErrInternal, "registration failed")
 		c.logger.Error(ctx.Context(), "Registration failed", err, logrus.Fields{"username": req.Username})
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
 	c.logger.Info(ctx.Context(), "User registered successfully", logrus.Fields{"username": req.Username})
-	return ctx.Status(fiber.StatusCreated).JSON(response)
+	return response.Success(ctx, fiber.StatusCreated, "", authResponse)
 }
 
 // @Summary Вход в систему
@@ -109,29 +106,21 @@ func (c *AuthController) register(ctx *fiber.Ctx) error {
 func (c *AuthController) login(ctx *fiber.Ctx) error {
 	var req models.LoginRequest
 
-	if err := ctx.BodyParser(&req); err != nil {
-		c.logger.Warn(ctx.Context(), "Failed to parse login request", logrus.Fields{"error": err.Error()})
-		return ctx.Status(fiber.StatusBadRequest).JSON(apperror.New(apperror.ErrInvalidRequest, "invalid request format").ToResponse())
-	}
-
-	// Валидация
-	if err := c.validate.Struct(req); err != nil {
-		c.logger.Warn(ctx.Context(), "Validation failed for login request", logrus.Fields{"error": err.Error()})
-		return ctx.Status(fiber.StatusBadRequest).JSON(apperror.New(apperror.ErrValidation, "validation error").WithDetails(err.Error()).ToResponse())
+	// Разбор и валидация запроса
+	if appErr := c.validate.ParseBody(ctx, &req); appErr != nil {
+		c.logger.Warn(ctx.Context(), "Invalid login request", logrus.Fields{"code": appErr.Code})
+		return response.Error(ctx, appErr)
 	}
 
-	response, err := c.service.Login(ctx.Context(), &req)
+	authResponse, err := c.service.Login(ctx.Context(), &req)
 	if err != nil {
-		appErr, ok := err.(*apperror.AppError)
-		if !ok {
-			appErr = apperror.New(apperror.ErrInternal, "login failed").WithError(err)
-		}
+		appErr := apperror.From(err, apperror.ErrInternal, "login failed")
 		c.logger.Warn(ctx.Context(), "Login failed", logrus.Fields{"username": req.Username})
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
 	c.logger.Info(ctx.Context(), "User logged in successfully", logrus.Fields{"username": req.Username})
-	return ctx.Status(fiber.StatusOK).JSON(response)
+	return response.OK(ctx, authResponse)
 }
 
 // @Summary Получить текущего пользователя
@@ -149,16 +138,16 @@ func (c *AuthController) getCurrentUser(ctx *fiber.Ctx) error {
 	userID, err := middleware.GetUserIDFromContext(ctx)
 	if err != nil {
 		c.logger.Warn(ctx.Context(), "Failed to extract user ID from token", logrus.Fields{"error": err.Error()})
-		appErr := err.(*apperror.AppError)
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		appErr := apperror.From(err, apperror.ErrUnauthorized, "unauthorized")
+		return response.Error(ctx, appErr)
 	}
 
 	// Извлекаем все claims из токена
 	claims, err := middleware.GetClaimsFromContext(ctx)
 	if err != nil {
 		c.logger.Warn(ctx.Context(), "Failed to extract claims from token", logrus.Fields{"error": err.Error()})
-		appErr := err.(*apperror.AppError)
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		appErr := apperror.From(err, apperror.ErrUnauthorized, "unauthorized")
+		return response.Error(ctx, appErr)
 	}
 
 	userInfo := &models.UserInfo{
@@ -169,7 +158,7 @@ func (c *AuthController) getCurrentUser(ctx *fiber.Ctx) error {
 	}
 
 	c.logger.Debug(ctx.Context(), "Current user info retrieved", logrus.Fields{"user_id": userID})
-	return ctx.Status(fiber.StatusOK).JSON(userInfo)
+	return response.OK(ctx, userInfo)
 }
 
 // @Summary Выход из системы
@@ -187,8 +176,8 @@ func (c *AuthController) logout(ctx *fiber.Ctx) error {
 	userID, err := middleware.GetUserIDFromContext(ctx)
 	if err != nil {
 		c.logger.Warn(ctx.Context(), "Failed to extract user ID from token", logrus.Fields{"error": err.Error()})
-		appErr := err.(*apperror.AppError)
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		appErr := apperror.From(err, apperror.ErrUnauthorized, "unauthorized")
+		return response.Error(ctx, appErr)
 	}
 
 	// Получаем токен из контекста (установлен middleware'ом)
@@ -196,15 +185,15 @@ func (c *AuthController) logout(ctx *fiber.Ctx) error {
 	if !ok {
 		c.logger.Warn(ctx.Context(), "Failed to extract token from context")
 		appErr := apperror.New(apperror.ErrInvalidToken, "Failed to extract token")
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
 	// Получаем claims для получения времени экспирации
 	claims, err := middleware.GetClaimsFromContext(ctx)
 	if err != nil {
 		c.logger.Warn(ctx.Context(), "Failed to extract claims from token", logrus.Fields{"error": err.Error()})
-		appErr := err.(*apperror.AppError)
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		appErr := apperror.From(err, apperror.ErrUnauthorized, "unauthorized")
+		return response.Error(ctx, appErr)
 	}
 
 	// Получаем время экспирации токена
@@ -222,7 +211,5 @@ func (c *AuthController) logout(ctx *fiber.Ctx) error {
 	}
 
 	c.logger.Info(ctx.Context(), "User logged out successfully", logrus.Fields{"user_id": userID})
-	return ctx.Status(fiber.StatusOK).JSON(map[string]string{
-		"message": "Logged out successfully",
-	})
+	return response.SuccessOK(ctx, "Logged out successfully", nil)
 }
@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/bankstatement"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+	"github.com/rusgainew/tunduck-app/pkg/validation"
+)
+
+// statementMatchRequest ручное сопоставление строки выписки с документом
+type statementMatchRequest struct {
+	DocumentID uuid.UUID `json:"documentId" validate:"required"`
+}
+
+type BankStatementController struct {
+	logger     *logger.Logger
+	statements services.BankStatementService
+}
+
+// NewBankStatementController регистрирует маршруты сверки банковских выписок
+func NewBankStatementController(app *fiber.App, log *logrus.Logger, statements services.BankStatementService) {
+	controller := &BankStatementController{
+		logger:     logger.New(log),
+		statements: statements,
+	}
+
+	controller.logger.Info(context.Background(), "BankStatementController инициализирован")
+	controller.registerRoutes(app)
+}
+
+func (c *BankStatementController) registerRoutes(app *fiber.App) {
+	statements := app.Group("/api/bank-statements")
+	statements.Use(middleware.JWTMiddleware())
+	statements.Get("/", c.listStatements)
+	statements.Post("/", c.uploadStatement)
+	statements.Get("/:id", c.getStatement)
+	statements.Get("/:id/unmatched", c.getUnmatched)
+	statements.Post("/:id/lines/:lineId/match", c.matchLine)
+}
+
+// listStatements возвращает выписки организации
+func (c *BankStatementController) listStatements(ctx *fiber.Ctx) error {
+	orgID, err := resolveOrgID(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "invalid organization ID"))
+	}
+
+	statements, err := c.statements.List(ctx.Context(), orgID)
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка получения выписок", err, logrus.Fields{"org_id": orgID.String()})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to fetch bank statements"))
+	}
+	return response.OK(ctx, statements)
+}
+
+// uploadStatement принимает файл выписки (multipart, поле file) и сопоставляет поступления с документами.
+// Формат передается в поле format или определяется по файлу.
+func (c *BankStatementController) uploadStatement(ctx *fiber.Ctx) error {
+	orgID, err := resolveOrgID(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "invalid organization ID"))
+	}
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrUnauthorized, "unauthorized"))
+	}
+
+	header, err := ctx.FormFile("file")
+	if err != nil {
+		return response.Error(ctx, apperror.ValidationError("bank statement file is required (multipart field file)"))
+	}
+	format := ctx.FormValue("format")
+	if format != "" && format != bankstatement.FormatMT940 && format != bankstatement.FormatCSV {
+		return response.Error(ctx, apperror.ValidationError("format must be mt940 or csv"))
+	}
+	file, err := header.Open()
+	if err != nil {
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "failed to read uploaded file"))
+	}
+	defer file.Close()
+
+	statement, err := c.statements.Import(ctx.Context(), orgID, services.BankStatementUpload{
+		FileName:   header.Filename,
+		Format:     format,
+		UploadedBy: userID.String(),
+		Body:       file,
+	})
+	if err != nil {
+		c.logger.Warn(ctx.Context(), "Выписка не загружена", logrus.Fields{"org_id": orgID.String(), "file": header.Filename, "error": err.Error()})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to import bank statement"))
+	}
+	return response.Success(ctx, fiber.StatusCreated, "Bank statement imported", statement)
+}
+
+// getStatement возвращает выписку со всеми строками
+func (c *BankStatementController) getStatement(ctx *fiber.Ctx) error {
+	orgID, id, appErr := statementTarget(ctx)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	statement, err := c.statements.Get(ctx.Context(), orgID, id)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to fetch bank statement"))
+	}
+	return response.OK(ctx, statement)
+}
+
+// getUnmatched возвращает несопоставленные строки с предложенными документами
+func (c *BankStatementController) getUnmatched(ctx *fiber.Ctx) error {
+	orgID, id, appErr := statementTarget(ctx)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	lines, err := c.statements.Unmatched(ctx.Context(), orgID, id)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to fetch unmatched lines"))
+	}
+	return response.OK(ctx, lines)
+}
+
+// matchLine вручную сопоставляет строку выписки с документом и вносит оплату
+func (c *BankStatementController) matchLine(ctx *fiber.Ctx) error {
+	orgID, id, appErr := statementTarget(ctx)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+	lineID, err := uuid.Parse(ctx.Params("lineId"))
+	if err != nil {
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "invalid line ID format"))
+	}
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrUnauthorized, "unauthorized"))
+	}
+
+	var req statementMatchRequest
+	if appErr := validation.ParseBody(ctx, &req); appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	line, err := c.statements.MatchLine(ctx.Context(), orgID, id, lineID, req.DocumentID, userID.String())
+	if err != nil {
+		c.logger.Warn(ctx.Context(), "Строка выписки не сопоставлена", logrus.Fields{"line_id": lineID.String(), "error": err.Error()})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to match statement line"))
+	}
+	return response.OK(ctx, line)
+}
+
+// statementTarget разбирает организацию и ID выписки из запроса
+func statementTarget(ctx *fiber.Ctx) (uuid.UUID, uuid.UUID, *apperror.AppError) {
+	orgID, err := resolveOrgID(ctx)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, apperror.New(apperror.ErrInvalidRequest, "invalid organization ID")
+	}
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, apperror.New(apperror.ErrInvalidRequest, "invalid bank statement ID format")
+	}
+	return orgID, id, nil
+}
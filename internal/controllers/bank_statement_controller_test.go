@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/testutil"
+)
+
+// stubBankStatementService запоминает загруженный файл и сопоставление
+type stubBankStatementService struct {
+	services.BankStatementService
+	upload    services.BankStatementUpload
+	content   string
+	matchedTo uuid.UUID
+}
+
+func (s *stubBankStatementService) Import(ctx context.Context, orgID uuid.UUID, upload services.BankStatementUpload) (*entity.BankStatement, error) {
+	body, _ := io.ReadAll(upload.Body)
+	s.upload, s.content = upload, string(body)
+	return &entity.BankStatement{ID: uuid.New(), FileName: upload.FileName, Format: "csv", LineCount: 1}, nil
+}
+
+func (s *stubBankStatementService) MatchLine(ctx context.Context, orgID uuid.UUID, statementID uuid.UUID, lineID uuid.UUID, documentID uuid.UUID, matchedBy string) (*entity.BankStatementLine, error) {
+	if s.matchedTo != uuid.Nil {
+		return nil, apperror.ConflictError("statement line is already matched")
+	}
+	s.matchedTo = documentID
+	return &entity.BankStatementLine{ID: lineID, Status: entity.StatementLineMatched, DocumentID: &documentID, MatchedBy: matchedBy}, nil
+}
+
+func statementUpload(t *testing.T, fileName, content, format string) ([]byte, string) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	if format != "" {
+		require.NoError(t, w.WriteField("format", format))
+	}
+	part, err := w.CreateFormFile("file", fileName)
+	require.NoError(t, err)
+	_, err = part.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes(), w.FormDataContentType()
+}
+
+func TestBankStatementController(t *testing.T) {
+	h := testutil.NewHarness(t)
+	svc := &stubBankStatementService{}
+	NewBankStatementController(h.App, h.Logger, svc)
+	user := testutil.NewUser()
+	token := testutil.WithToken(h.Token(user.ID.String(), user.Email))
+	org := testutil.WithHeader("X-Org-Id", uuid.NewString())
+
+	body, contentType := statementUpload(t, "feb.csv", "date;amount\n2026-02-10;5\n", "")
+	resp := h.Do(http.MethodPost, "/api/bank-statements", body, org, testutil.WithHeader("Content-Type", contentType))
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+	resp = h.Do(http.MethodPost, "/api/bank-statements", body, org, token, testutil.WithHeader("Content-Type", contentType))
+	require.Equal(t, fiber.StatusCreated, resp.StatusCode, string(resp.Body))
+	assert.Equal(t, "feb.csv", svc.upload.FileName)
+	assert.Equal(t, user.ID.String(), svc.upload.UploadedBy)
+	assert.Equal(t, "date;amount\n2026-02-10;5\n", svc.content)
+
+	body, contentType = statementUpload(t, "feb.csv", "x", "xlsx")
+	resp = h.Do(http.MethodPost, "/api/bank-statements", body, org, token, testutil.WithHeader("Content-Type", contentType))
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, string(apperror.ErrValidation), resp.ErrorCode())
+
+	resp = h.Do(http.MethodPost, "/api/bank-statements", []byte("{}"), org, token)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode, "file is required")
+
+	docID := uuid.New()
+	matchPath := "/api/bank-statements/" + uuid.NewString() + "/lines/" + uuid.NewString() + "/match"
+	resp = h.Do(http.MethodPost, matchPath, map[string]string{"documentId": docID.String()}, org, token)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+	var line entity.BankStatementLine
+	resp.DecodeData(&line)
+	assert.Equal(t, docID, *line.DocumentID)
+	assert.Equal(t, user.ID.String(), line.MatchedBy)
+
+	resp = h.Do(http.MethodPost, matchPath, map[string]string{"documentId": docID.String()}, org, token)
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	resp = h.Do(http.MethodPost, matchPath, map[string]string{}, org, token)
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+}
@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+)
+
+type CallbackController struct {
+	logger  *logger.Logger
+	service services.CallbackService
+}
+
+// callbackAck ответ на принятое событие
+type callbackAck struct {
+	EventID   string `json:"eventId"`
+	Duplicate bool   `json:"duplicate"`
+}
+
+// NewCallbackController регистрирует маршруты обратных вызовов внешних сервисов
+func NewCallbackController(app *fiber.App, log *logrus.Logger, service services.CallbackService) {
+	controller := &CallbackController{
+		logger:  logger.New(log),
+		service: service,
+	}
+
+	controller.logger.Info(context.Background(), "CallbackController инициализирован")
+	app.Post("/api/callbacks/esf", controller.consumeESF)
+}
+
+// consumeESF принимает обратный вызов шлюза ЭСФ. Запрос подписывается ключом API
+// (HMACSignatureMiddleware), ID ключа служит источником при дедупликации событий.
+// Повторная доставка подтверждается 200 с duplicate=true, чтобы отправитель прекратил попытки.
+func (c *CallbackController) consumeESF(ctx *fiber.Ctx) error {
+	source, _ := ctx.Locals(middleware.APIKeyLocalsKey).(string)
+	if source == "" {
+		return response.Error(ctx, apperror.New(apperror.ErrUnauthorized, "signed request is required"))
+	}
+
+	var event services.CallbackEvent
+	if err := ctx.BodyParser(&event); err != nil {
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "invalid event"))
+	}
+
+	duplicate, err := c.service.Consume(ctx.Context(), source, event)
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка обработки обратного вызова", err, logrus.Fields{"source": source, "event_id": event.ID})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to process event"))
+	}
+	return response.OK(ctx, callbackAck{EventID: event.ID, Duplicate: duplicate})
+}
@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+	"github.com/rusgainew/tunduck-app/pkg/validation"
+)
+
+type ContractController struct {
+	logger    *logger.Logger
+	contracts services.ContractService
+}
+
+// NewContractController регистрирует маршруты справочника договоров
+func NewContractController(app *fiber.App, log *logrus.Logger, contracts services.ContractService) {
+	controller := &ContractController{
+		logger:    logger.New(log),
+		contracts: contracts,
+	}
+
+	controller.logger.Info(context.Background(), "ContractController инициализирован")
+	controller.registerRoutes(app)
+}
+
+func (c *ContractController) registerRoutes(app *fiber.App) {
+	contracts := app.Group("/api/contracts")
+	contracts.Use(middleware.JWTMiddleware())
+	contracts.Get("/", c.listContracts)
+	contracts.Post("/", c.createContract)
+	contracts.Get("/:id", c.getContract)
+	contracts.Put("/:id", c.updateContract)
+	contracts.Delete("/:id", c.deleteContract)
+}
+
+// listContracts возвращает договоры организации; ?contractorTin= отбирает договоры контрагента
+func (c *ContractController) listContracts(ctx *fiber.Ctx) error {
+	orgID, err := resolveOrgID(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "invalid organization ID"))
+	}
+
+	contracts, err := c.contracts.List(ctx.Context(), orgID, ctx.Query("contractorTin"))
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка получения договоров", err, logrus.Fields{"org_id": orgID.String()})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to fetch contracts"))
+	}
+	return response.OK(ctx, contracts)
+}
+
+// createContract добавляет договор в справочник
+func (c *ContractController) createContract(ctx *fiber.Ctx) error {
+	orgID, err := resolveOrgID(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "invalid organization ID"))
+	}
+
+	var req models.ContractRequest
+	if appErr := validation.ParseBody(ctx, &req); appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	contract, err := c.contracts.Create(ctx.Context(), orgID, &req)
+	if err != nil {
+		c.logger.Warn(ctx.Context(), "Договор не создан", logrus.Fields{"org_id": orgID.String(), "error": err.Error()})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to create contract"))
+	}
+	return response.SuccessCreated(ctx, "Contract created", contract)
+}
+
+// getContract возвращает договор с суммой документов по нему и остатком лимита
+func (c *ContractController) getContract(ctx *fiber.Ctx) error {
+	orgID, id, appErr := contractTarget(ctx)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	contract, err := c.contracts.Get(ctx.Context(), orgID, id)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to fetch contract"))
+	}
+	return response.OK(ctx, contract)
+}
+
+// updateContract изменяет договор
+func (c *ContractController) updateContract(ctx *fiber.Ctx) error {
+	orgID, id, appErr := contractTarget(ctx)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	var req models.ContractRequest
+	if appErr := validation.ParseBody(ctx, &req); appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	contract, err := c.contracts.Update(ctx.Context(), orgID, id, &req)
+	if err != nil {
+		c.logger.Warn(ctx.Context(), "Договор не изменен", logrus.Fields{"contract_id": id.String(), "error": err.Error()})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to update contract"))
+	}
+	return response.SuccessOK(ctx, "Contract updated", contract)
+}
+
+// deleteContract удаляет договор, по которому нет документов
+func (c *ContractController) deleteContract(ctx *fiber.Ctx) error {
+	orgID, id, appErr := contractTarget(ctx)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	if err := c.contracts.Delete(ctx.Context(), orgID, id); err != nil {
+		c.logger.Warn(ctx.Context(), "Договор не удален", logrus.Fields{"contract_id": id.String(), "error": err.Error()})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to delete contract"))
+	}
+	return response.SuccessOK(ctx, "Contract deleted", nil)
+}
+
+// contractTarget разбирает организацию и ID договора из запроса
+func contractTarget(ctx *fiber.Ctx) (uuid.UUID, uuid.UUID, *apperror.AppError) {
+	orgID, err := resolveOrgID(ctx)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, apperror.New(apperror.ErrInvalidRequest, "invalid organization ID")
+	}
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, apperror.New(apperror.ErrInvalidRequest, "invalid contract ID format")
+	}
+	return orgID, id, nil
+}
@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/testutil"
+)
+
+// stubContractService хранит договоры в памяти
+type stubContractService struct {
+	services.ContractService
+	contracts map[uuid.UUID]*entity.Contract
+	tinFilter string
+}
+
+func (s *stubContractService) List(ctx context.Context, orgID uuid.UUID, contractorTin string) ([]entity.Contract, error) {
+	s.tinFilter = contractorTin
+	contracts := []entity.Contract{}
+	for _, c := range s.contracts {
+		contracts = append(contracts, *c)
+	}
+	return contracts, nil
+}
+
+func (s *stubContractService) Create(ctx context.Context, orgID uuid.UUID, req *models.ContractRequest) (*entity.Contract, error) {
+	c := &entity.Contract{ID: uuid.New(), Number: req.Number, ContractorTin: req.ContractorTin, StartDate: req.StartDate, AmountLimit: req.AmountLimit}
+	s.contracts[c.ID] = c
+	return c, nil
+}
+
+func (s *stubContractService) Get(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*services.ContractDetails, error) {
+	c, ok := s.contracts[id]
+	if !ok {
+		return nil, apperror.NotFoundError("contract")
+	}
+	remaining := c.AmountLimit - 250
+	return &services.ContractDetails{Contract: *c, DocumentCount: 1, UsedAmount: 250, RemainingLimit: &remaining}, nil
+}
+
+func (s *stubContractService) Delete(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error {
+	if _, ok := s.contracts[id]; ok {
+		return apperror.ConflictError("contract is referenced by documents")
+	}
+	return apperror.NotFoundError("contract")
+}
+
+func TestContractController(t *testing.T) {
+	h := testutil.NewHarness(t)
+	svc := &stubContractService{contracts: map[uuid.UUID]*entity.Contract{}}
+	NewContractController(h.App, h.Logger, svc)
+	user := testutil.NewUser()
+	token := testutil.WithToken(h.Token(user.ID.String(), user.Email))
+	org := testutil.WithHeader("X-Org-Id", uuid.NewString())
+
+	body := map[string]interface{}{
+		"number": "D-2026/15", "contractorTin": "01234567890123",
+		"startDate": "2026-01-01T00:00:00Z", "amountLimit": 1000,
+	}
+	resp := h.Do(http.MethodPost, "/api/contracts", body, org)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+	resp = h.Do(http.MethodPost, "/api/contracts", body, org, token)
+	require.Equal(t, fiber.StatusCreated, resp.StatusCode, string(resp.Body))
+	var created entity.Contract
+	resp.DecodeData(&created)
+	assert.Equal(t, "D-2026/15", created.Number)
+
+	resp = h.Do(http.MethodPost, "/api/contracts", map[string]interface{}{"number": "D-1", "contractorTin": "12AB"}, org, token)
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+
+	resp = h.Do(http.MethodGet, "/api/contracts?contractorTin=01234567890123", nil, org, token)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var list []entity.Contract
+	resp.DecodeData(&list)
+	assert.Len(t, list, 1)
+	assert.Equal(t, "01234567890123", svc.tinFilter)
+
+	resp = h.Do(http.MethodGet, "/api/contracts/"+created.ID.String(), nil, org, token)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	var details services.ContractDetails
+	resp.DecodeData(&details)
+	assert.Equal(t, created.ID, details.ID)
+	assert.Equal(t, 750.0, *details.RemainingLimit)
+
+	resp = h.Do(http.MethodGet, "/api/contracts/"+uuid.NewString(), nil, org, token)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	resp = h.Do(http.MethodGet, "/api/contracts/not-a-uuid", nil, org, token)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	resp = h.Do(http.MethodDelete, "/api/contracts/"+created.ID.String(), nil, org, token)
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+}
@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+	"github.com/rusgainew/tunduck-app/pkg/validation"
+)
+
+type DelegationController struct {
+	logger      *logger.Logger
+	delegations services.DelegationService
+}
+
+// NewDelegationController регистрирует маршруты делегирования права подписи
+func NewDelegationController(app *fiber.App, log *logrus.Logger, delegations services.DelegationService) {
+	controller := &DelegationController{
+		logger:      logger.New(log),
+		delegations: delegations,
+	}
+
+	controller.logger.Info(context.Background(), "DelegationController инициализирован")
+	controller.registerRoutes(app)
+}
+
+func (c *DelegationController) registerRoutes(app *fiber.App) {
+	delegations := app.Group("/api/delegations")
+	delegations.Use(middleware.JWTMiddleware())
+	delegations.Get("/", c.listDelegations)
+	delegations.Post("/", c.createDelegation)
+	delegations.Delete("/:id", c.revokeDelegation)
+}
+
+// listDelegations возвращает делегирования, выданные текущим пользователем или полученные им
+func (c *DelegationController) listDelegations(ctx *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrUnauthorized, "unauthorized"))
+	}
+
+	delegations, err := c.delegations.List(ctx.Context(), userID)
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка получения делегирований", err, logrus.Fields{"user_id": userID.String()})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to fetch delegations"))
+	}
+	return response.OK(ctx, delegations)
+}
+
+// createDelegation передает право подписи текущего пользователя другому на срок
+func (c *DelegationController) createDelegation(ctx *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrUnauthorized, "unauthorized"))
+	}
+
+	var req models.DelegationRequest
+	if appErr := validation.ParseBody(ctx, &req); appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	delegation, err := c.delegations.Create(ctx.Context(), userID, &req)
+	if err != nil {
+		c.logger.Warn(ctx.Context(), "Делегирование не создано", logrus.Fields{"user_id": userID.String(), "error": err.Error()})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to create delegation"))
+	}
+	return response.SuccessCreated(ctx, "Signing rights delegated", delegation)
+}
+
+// revokeDelegation досрочно отзывает делегирование
+func (c *DelegationController) revokeDelegation(ctx *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrUnauthorized, "unauthorized"))
+	}
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "invalid delegation ID format"))
+	}
+
+	delegation, err := c.delegations.Revoke(ctx.Context(), userID, id)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to revoke delegation"))
+	}
+	return response.SuccessOK(ctx, "Delegation revoked", delegation)
+}
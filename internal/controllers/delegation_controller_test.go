@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+	"github.com/rusgainew/tunduck-app/pkg/testutil"
+)
+
+// stubAuthority подтверждает право подписи по заранее заданным Grant
+type stubAuthority struct {
+	services.DelegationService
+	grants map[uuid.UUID]*rbac.Grant
+}
+
+func (s *stubAuthority) Authorize(ctx context.Context, userID uuid.UUID, permission rbac.Permission) (*rbac.Grant, error) {
+	if grant, ok := s.grants[userID]; ok {
+		return grant, nil
+	}
+	return nil, apperror.ForbiddenError("no signing rights or active delegation")
+}
+
+// stubSendService запоминает Grant, с которым документ был отправлен
+type stubSendService struct {
+	stubDocumentService
+	grant *rbac.Grant
+}
+
+func (s *stubSendService) SendDocument(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*models.EsfCreateDocumentResponse, error) {
+	s.grant = rbac.GrantFromContext(ctx)
+	return &models.EsfCreateDocumentResponse{DocumentUuid: id.String()}, nil
+}
+
+func TestEsfDocumentController_SendRequiresAuthority(t *testing.T) {
+	h := testutil.NewHarness(t)
+	orgID, docID := uuid.New(), uuid.New()
+	delegate, stranger := testutil.NewUser(), testutil.NewUser()
+	delegatorID, delegationID := uuid.New(), uuid.New()
+	authority := &stubAuthority{grants: map[uuid.UUID]*rbac.Grant{
+		delegate.ID: {UserID: delegate.ID, Permission: rbac.PermissionSendDocument, DelegatorID: &delegatorID, DelegationID: &delegationID},
+	}}
+	svc := &stubSendService{stubDocumentService: stubDocumentService{orgID: orgID}}
+	NewEsfDocumentController(h.App, h.Logger, svc, authority, nil, nil)
+	org := testutil.WithHeader("X-Org-Id", orgID.String())
+	path := "/api/esf-documents/" + docID.String() + "/send"
+
+	resp := h.Do(http.MethodPost, path, nil, org, testutil.WithToken(h.Token(stranger.ID.String(), stranger.Email)))
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	assert.Nil(t, svc.grant, "the document is not sent without signing rights")
+
+	resp = h.Do(http.MethodPost, path, nil, org, testutil.WithToken(h.Token(delegate.ID.String(), delegate.Email)))
+	require.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+	require.NotNil(t, svc.grant)
+	assert.True(t, svc.grant.Delegated())
+	assert.Equal(t, delegatorID, *svc.grant.DelegatorID)
+}
+
+// stubDelegationService хранит делегирования в памяти
+type stubDelegationService struct {
+	services.DelegationService
+	created []models.DelegationRequest
+}
+
+func (s *stubDelegationService) Create(ctx context.Context, delegatorID uuid.UUID, req *models.DelegationRequest) (*entity.SigningDelegation, error) {
+	s.created = append(s.created, *req)
+	return &entity.SigningDelegation{ID: uuid.New(), DelegatorID: delegatorID, DelegateID: req.DelegateID, ValidFrom: req.ValidFrom, ValidTo: req.ValidTo}, nil
+}
+
+func (s *stubDelegationService) Revoke(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*entity.SigningDelegation, error) {
+	return nil, apperror.NotFoundError("delegation")
+}
+
+func TestDelegationController(t *testing.T) {
+	h := testutil.NewHarness(t)
+	svc := &stubDelegationService{}
+	NewDelegationController(h.App, h.Logger, svc)
+	user := testutil.NewUser()
+	token := testutil.WithToken(h.Token(user.ID.String(), user.Email))
+
+	resp := h.Do(http.MethodPost, "/api/delegations", fiber.Map{"validFrom": "2026-07-01T00:00:00Z", "validTo": "2026-07-14T00:00:00Z"}, token)
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode, "delegateId is required")
+
+	delegateID := uuid.New()
+	resp = h.Do(http.MethodPost, "/api/delegations", fiber.Map{
+		"delegateId": delegateID, "validFrom": "2026-07-01T00:00:00Z", "validTo": "2026-07-14T00:00:00Z", "reason": "vacation",
+	}, token)
+	require.Equal(t, fiber.StatusCreated, resp.StatusCode, string(resp.Body))
+	var delegation entity.SigningDelegation
+	resp.DecodeData(&delegation)
+	assert.Equal(t, user.ID, delegation.DelegatorID, "the delegator is the authenticated user")
+	assert.Equal(t, delegateID, delegation.DelegateID)
+
+	resp = h.Do(http.MethodDelete, "/api/delegations/"+uuid.NewString(), nil, token)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+)
+
+// resourceLink ссылка HATEOAS: адрес и метод действия
+type resourceLink struct {
+	Href   string `json:"href"`
+	Method string `json:"method"`
+}
+
+// documentResource документ ЭСФ со ссылками на действия, доступные в его текущем статусе.
+// Клиенту не нужно повторять правила статусов: нет ссылки — нет действия.
+type documentResource struct {
+	models.EsfCreateDocumentRequest
+	Links map[string]resourceLink `json:"_links"`
+}
+
+// documentBasePath префикс маршрутов документов
+const documentBasePath = "/api/esf-documents"
+
+// linkAccess права вызывающего, от которых зависят ссылки на действия
+type linkAccess struct {
+	// authenticated запрос с действующим JWT: изменение, PDF и платежи закрыты для анонимных запросов
+	authenticated bool
+	// canSend право подписи (rbac.PermissionSendDocument) по роли или по делегированию
+	canSend bool
+}
+
+// linkAccess определяет права вызывающего один раз на запрос. Маршруты чтения документов
+// публичные, поэтому пользователь известен, только если OptionalJWT принял токен.
+func (c *EsfDocumentController) linkAccess(ctx *fiber.Ctx) linkAccess {
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return linkAccess{}
+	}
+	access := linkAccess{authenticated: true}
+	if c.authority != nil {
+		grant, err := c.authority.Authorize(ctx.Context(), userID, rbac.PermissionSendDocument)
+		access.canSend = err == nil && grant != nil
+	}
+	return access
+}
+
+// documentLinks формирует ссылки документа. Организация передается в orgId, чтобы ссылки
+// работали без заголовка X-Org-Id. Изменение, удаление, отправка и планирование отправки
+// доступны, пока документ можно редактировать (entity.IsEsfDocumentEditable); отправка и
+// планирование требуют еще и права подписи. Выгрузки XML и истории версий у документа нет,
+// поэтому и ссылок на них нет.
+func documentLinks(orgID uuid.UUID, doc *models.EsfCreateDocumentRequest, access linkAccess) map[string]resourceLink {
+	query := "?orgId=" + orgID.String()
+	base := documentBasePath + "/" + doc.ID.String()
+	item := base + query
+
+	links := map[string]resourceLink{
+		"self":       {Href: item, Method: fiber.MethodGet},
+		"collection": {Href: documentBasePath + "/paginated" + query, Method: fiber.MethodGet},
+	}
+	if !access.authenticated {
+		return links
+	}
+
+	links["pdf"] = resourceLink{Href: base + "/pdf" + query, Method: fiber.MethodGet}
+	links["payments"] = resourceLink{Href: base + "/payments" + query, Method: fiber.MethodGet}
+	if !entity.IsEsfDocumentEditable(doc.EsfStatus) {
+		return links
+	}
+	links["update"] = resourceLink{Href: item, Method: fiber.MethodPut}
+	links["delete"] = resourceLink{Href: item, Method: fiber.MethodDelete}
+	if access.canSend {
+		links["send"] = resourceLink{Href: base + "/send" + query, Method: fiber.MethodPost}
+		links["schedule-send"] = resourceLink{Href: base + "/schedule-send" + query, Method: fiber.MethodPost}
+	}
+	return links
+}
+
+// newDocumentResource добавляет к документу ссылки
+func newDocumentResource(orgID uuid.UUID, doc *models.EsfCreateDocumentRequest, access linkAccess) documentResource {
+	return documentResource{EsfCreateDocumentRequest: *doc, Links: documentLinks(orgID, doc, access)}
+}
+
+// newDocumentResources добавляет ссылки к каждому документу списка
+func newDocumentResources(orgID uuid.UUID, docs []models.EsfCreateDocumentRequest, access linkAccess) []documentResource {
+	resources := make([]documentResource, len(docs))
+	for i := range docs {
+		resources[i] = newDocumentResource(orgID, &docs[i], access)
+	}
+	return resources
+}
+
+// ensureDocumentEditable запрещает изменять документ, отправленный в ЭСФ:
+// REST и GraphQL проверяют то же правило, по которому строятся ссылки update и delete
+func ensureDocumentEditable(ctx context.Context, service services.EsfDocumentService, orgID, docID uuid.UUID) *apperror.AppError {
+	doc, err := service.GetDocumentByID(ctx, orgID, docID)
+	if err != nil {
+		return apperror.From(err, apperror.ErrInternal, "failed to fetch document")
+	}
+	if doc == nil {
+		return apperror.New(apperror.ErrDocumentNotFound, "document not found")
+	}
+	if !entity.IsEsfDocumentEditable(doc.EsfStatus) {
+		return apperror.New(apperror.ErrConflict, "document has ESF status "+doc.EsfStatus+" and can no longer be changed")
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+	"github.com/rusgainew/tunduck-app/pkg/testutil"
+)
+
+func TestDocumentLinks_DependOnStatus(t *testing.T) {
+	orgID := uuid.New()
+	doc := testutil.NewDocumentRequest(func(d *models.EsfCreateDocumentRequest) { d.ID = uuid.New() })
+	base := "/api/esf-documents/" + doc.ID.String()
+	query := "?orgId=" + orgID.String()
+	signer := linkAccess{authenticated: true, canSend: true}
+
+	links := documentLinks(orgID, doc, signer)
+	assert.Equal(t, resourceLink{Href: base + query, Method: fiber.MethodGet}, links["self"])
+	assert.Equal(t, resourceLink{Href: base + query, Method: fiber.MethodPut}, links["update"])
+	assert.Equal(t, resourceLink{Href: base + query, Method: fiber.MethodDelete}, links["delete"])
+	assert.Equal(t, resourceLink{Href: base + "/send" + query, Method: fiber.MethodPost}, links["send"])
+	assert.Equal(t, resourceLink{Href: base + "/schedule-send" + query, Method: fiber.MethodPost}, links["schedule-send"])
+	assert.Equal(t, resourceLink{Href: base + "/pdf" + query, Method: fiber.MethodGet}, links["pdf"])
+	assert.Equal(t, resourceLink{Href: base + "/payments" + query, Method: fiber.MethodGet}, links["payments"])
+
+	doc.EsfStatus = entity.EsfStatusRejected
+	links = documentLinks(orgID, doc, signer)
+	assert.Contains(t, links, "update", "rejected documents can be corrected")
+	assert.Contains(t, links, "send", "rejected documents can be sent again")
+
+	doc.EsfStatus = entity.EsfStatusRegistered
+	links = documentLinks(orgID, doc, signer)
+	assert.Contains(t, links, "self")
+	assert.Contains(t, links, "pdf")
+	assert.Contains(t, links, "payments")
+	assert.NotContains(t, links, "update")
+	assert.NotContains(t, links, "delete")
+	assert.NotContains(t, links, "send")
+	assert.NotContains(t, links, "schedule-send")
+}
+
+func TestDocumentLinks_DependOnCaller(t *testing.T) {
+	orgID := uuid.New()
+	doc := testutil.NewDocumentRequest(func(d *models.EsfCreateDocumentRequest) { d.ID = uuid.New() })
+
+	anonymous := documentLinks(orgID, doc, linkAccess{})
+	assert.Len(t, anonymous, 2)
+	assert.Contains(t, anonymous, "self")
+	assert.Contains(t, anonymous, "collection")
+
+	member := documentLinks(orgID, doc, linkAccess{authenticated: true})
+	assert.Contains(t, member, "update")
+	assert.Contains(t, member, "pdf")
+	assert.NotContains(t, member, "send", "sending requires signing rights")
+	assert.NotContains(t, member, "schedule-send")
+}
+
+func TestEsfDocumentController_LinksAndLockedDocuments(t *testing.T) {
+	h := testutil.NewHarness(t)
+	orgID := uuid.New()
+	draftID, sentID := uuid.New(), uuid.New()
+	docs := &stubDocumentService{orgID: orgID, docs: map[uuid.UUID]*models.EsfCreateDocumentRequest{
+		draftID: testutil.NewDocumentRequest(func(d *models.EsfCreateDocumentRequest) { d.ID = draftID }),
+		sentID: testutil.NewDocumentRequest(func(d *models.EsfCreateDocumentRequest) {
+			d.ID, d.EsfStatus = sentID, entity.EsfStatusSent
+		}),
+	}}
+	user, signer := testutil.NewUser(), testutil.NewUser()
+	authority := &stubAuthority{grants: map[uuid.UUID]*rbac.Grant{
+		signer.ID: {UserID: signer.ID, Permission: rbac.PermissionSendDocument},
+	}}
+	NewEsfDocumentController(h.App, h.Logger, docs, authority, nil, nil)
+	token := testutil.WithToken(h.Token(user.ID.String(), user.Email))
+	signerToken := testutil.WithToken(h.Token(signer.ID.String(), signer.Email))
+	org := testutil.WithHeader("X-Org-Id", orgID.String())
+
+	resp := h.Do(http.MethodGet, "/api/esf-documents/"+draftID.String(), nil, org)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+	var draft documentResource
+	resp.DecodeData(&draft)
+	assert.Equal(t, draftID, draft.ID)
+	assert.NotContains(t, draft.Links, "delete", "anonymous callers get read-only links")
+
+	resp = h.Do(http.MethodGet, "/api/esf-documents/"+draftID.String(), nil, org, token)
+	resp.DecodeData(&draft)
+	assert.Equal(t, fiber.MethodDelete, draft.Links["delete"].Method)
+	assert.NotContains(t, draft.Links, "send")
+
+	resp = h.Do(http.MethodGet, "/api/esf-documents/"+draftID.String(), nil, org, signerToken)
+	resp.DecodeData(&draft)
+	assert.Equal(t, fiber.MethodPost, draft.Links["send"].Method)
+
+	resp = h.Do(http.MethodGet, "/api/esf-documents/"+sentID.String(), nil, org, signerToken)
+	var sent documentResource
+	resp.DecodeData(&sent)
+	assert.NotContains(t, sent.Links, "delete")
+	assert.NotContains(t, sent.Links, "send")
+	assert.Contains(t, sent.Links, "pdf")
+
+	resp = h.Do(http.MethodDelete, "/api/esf-documents/"+sentID.String(), nil, org, token)
+	assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	assert.Equal(t, string(apperror.ErrConflict), resp.ErrorCode())
+	assert.Contains(t, docs.docs, sentID)
+
+	resp = h.Do(http.MethodDelete, "/api/esf-documents/"+draftID.String(), nil, org, token)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+	assert.Equal(t, []uuid.UUID{draftID}, docs.deleted)
+}
@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+	"github.com/rusgainew/tunduck-app/pkg/validation"
+)
+
+// paymentTarget разбирает организацию и ID документа из запроса к оплатам
+func paymentTarget(ctx *fiber.Ctx) (uuid.UUID, uuid.UUID, *apperror.AppError) {
+	orgID, err := resolveOrgID(ctx)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, apperror.New(apperror.ErrInvalidRequest, "invalid organization ID")
+	}
+	docID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, apperror.New(apperror.ErrInvalidRequest, "invalid document ID format")
+	}
+	return orgID, docID, nil
+}
+
+// listPayments возвращает оплаты документа, оплаченную сумму и остаток
+func (c *EsfDocumentController) listPayments(ctx *fiber.Ctx) error {
+	orgID, docID, appErr := paymentTarget(ctx)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	payments, err := c.service.ListPayments(ctx.Context(), orgID, docID)
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Failed to fetch payments", err, logrus.Fields{"org_id": orgID.String(), "doc_id": docID.String()})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to fetch payments"))
+	}
+	return response.OK(ctx, payments)
+}
+
+// recordPayment вносит оплату по документу. Оплата допускается в любом статусе ЭСФ:
+// поступление денег не меняет содержимое отправленного документа.
+func (c *EsfDocumentController) recordPayment(ctx *fiber.Ctx) error {
+	orgID, docID, appErr := paymentTarget(ctx)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrUnauthorized, "unauthorized"))
+	}
+
+	var req models.PaymentRequest
+	if appErr := validation.ParseBody(ctx, &req); appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	payments, err := c.service.RecordPayment(ctx.Context(), orgID, docID, userID.String(), &req)
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Failed to record payment", err, logrus.Fields{"org_id": orgID.String(), "doc_id": docID.String()})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to record payment"))
+	}
+	return response.Success(ctx, fiber.StatusCreated, "Payment recorded", payments)
+}
+
+// deletePayment удаляет оплату и уменьшает оплаченную сумму документа
+func (c *EsfDocumentController) deletePayment(ctx *fiber.Ctx) error {
+	orgID, docID, appErr := paymentTarget(ctx)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+	paymentID, err := uuid.Parse(ctx.Params("paymentId"))
+	if err != nil {
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "invalid payment ID format"))
+	}
+
+	payments, err := c.service.DeletePayment(ctx.Context(), orgID, docID, paymentID)
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Failed to delete payment", err, logrus.Fields{"org_id": orgID.String(), "doc_id": docID.String()})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to delete payment"))
+	}
+	return response.SuccessOK(ctx, "Payment deleted", payments)
+}
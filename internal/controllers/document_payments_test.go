@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/testutil"
+)
+
+// stubPaymentService хранит оплаты одного документа с суммой к оплате 100
+type stubPaymentService struct {
+	stubDocumentService
+	payments []entity.Payment
+}
+
+func (s *stubPaymentService) summary(id uuid.UUID) *services.DocumentPayments {
+	var paid float64
+	for _, p := range s.payments {
+		paid += p.Amount
+	}
+	return &services.DocumentPayments{DocumentID: id, AmountDue: 100, PaidAmount: paid, RemainingAmount: 100 - paid, Payments: s.payments}
+}
+
+func (s *stubPaymentService) ListPayments(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*services.DocumentPayments, error) {
+	if _, ok := s.docs[id]; !ok || orgID != s.orgID {
+		return nil, apperror.New(apperror.ErrDocumentNotFound, "document not found")
+	}
+	return s.summary(id), nil
+}
+
+func (s *stubPaymentService) RecordPayment(ctx context.Context, orgID uuid.UUID, id uuid.UUID, createdBy string, req *models.PaymentRequest) (*services.DocumentPayments, error) {
+	s.payments = append(s.payments, entity.Payment{ID: uuid.New(), DocumentID: id, PaidAt: req.PaidAt, Amount: req.Amount, Method: req.Method, CreatedBy: createdBy})
+	return s.summary(id), nil
+}
+
+func (s *stubPaymentService) DeletePayment(ctx context.Context, orgID uuid.UUID, id uuid.UUID, paymentID uuid.UUID) (*services.DocumentPayments, error) {
+	for i, p := range s.payments {
+		if p.ID == paymentID {
+			s.payments = append(s.payments[:i], s.payments[i+1:]...)
+			return s.summary(id), nil
+		}
+	}
+	return nil, apperror.NotFoundError("payment")
+}
+
+func TestEsfDocumentController_Payments(t *testing.T) {
+	h := testutil.NewHarness(t)
+	orgID, docID := uuid.New(), uuid.New()
+	svc := &stubPaymentService{stubDocumentService: stubDocumentService{orgID: orgID, docs: map[uuid.UUID]*models.EsfCreateDocumentRequest{
+		docID: testutil.NewDocumentRequest(func(d *models.EsfCreateDocumentRequest) { d.ID = docID }),
+	}}}
+	NewEsfDocumentController(h.App, h.Logger, svc, nil, nil, nil)
+	user := testutil.NewUser()
+	token := testutil.WithToken(h.Token(user.ID.String(), user.Email))
+	org := testutil.WithHeader("X-Org-Id", orgID.String())
+	path := "/api/esf-documents/" + docID.String() + "/payments"
+
+	resp := h.Do(http.MethodGet, path, nil, org)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode, "payments require authentication")
+
+	resp = h.Do(http.MethodPost, path, map[string]interface{}{
+		"paidAt": time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), "amount": 40, "method": entity.PaymentMethodCash,
+	}, org, token)
+	require.Equal(t, fiber.StatusCreated, resp.StatusCode, string(resp.Body))
+	var payments services.DocumentPayments
+	resp.DecodeData(&payments)
+	assert.Equal(t, 40.0, payments.PaidAmount)
+	assert.Equal(t, 60.0, payments.RemainingAmount)
+	require.Len(t, payments.Payments, 1)
+	assert.Equal(t, user.ID.String(), payments.Payments[0].CreatedBy)
+
+	resp = h.Do(http.MethodPost, path, map[string]interface{}{"paidAt": time.Now(), "amount": 10, "method": "barter"}, org, token)
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+	resp = h.Do(http.MethodPost, path, map[string]interface{}{"paidAt": time.Now(), "amount": -5, "method": entity.PaymentMethodCard}, org, token)
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+
+	resp = h.Do(http.MethodDelete, path+"/"+uuid.NewString(), nil, org, token)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+
+	resp = h.Do(http.MethodDelete, path+"/"+payments.Payments[0].ID.String(), nil, org, token)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+	resp.DecodeData(&payments)
+	assert.Equal(t, 100.0, payments.RemainingAmount)
+	assert.Empty(t, payments.Payments)
+
+	resp = h.Do(http.MethodGet, "/api/esf-documents/"+uuid.NewString()+"/payments", nil, org, token)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
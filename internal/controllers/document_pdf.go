@@ -0,0 +1,31 @@
+package controllers
+
+import (
+	"bytes"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/report"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+)
+
+// downloadPDF отдает печатную форму документа; наименования выбираются по языку документа
+func (c *EsfDocumentController) downloadPDF(ctx *fiber.Ctx) error {
+	orgID, docID, appErr := paymentTarget(ctx)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	var buf bytes.Buffer
+	if err := c.service.WriteDocumentPDF(ctx.Context(), orgID, docID, &buf); err != nil {
+		c.logger.Error(ctx.Context(), "Failed to render document PDF", err, logrus.Fields{"org_id": orgID.String(), "doc_id": docID.String()})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to render document"))
+	}
+
+	ctx.Set(fiber.HeaderContentType, report.ContentTypePDF)
+	ctx.Set(fiber.HeaderContentDisposition, `attachment; filename="invoice-`+docID.String()+`.pdf"`)
+	ctx.Set(fiber.HeaderCacheControl, "private, no-store")
+	return ctx.Send(buf.Bytes())
+}
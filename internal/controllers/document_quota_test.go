@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/quota"
+	"github.com/rusgainew/tunduck-app/pkg/testutil"
+)
+
+// countingQuotaSource план free и расход документов по числу документов заглушки сервиса
+type countingQuotaSource struct {
+	docs *stubDocumentService
+}
+
+func (s countingQuotaSource) Plan(ctx context.Context, orgID uuid.UUID) (string, error) {
+	return "", nil
+}
+
+func (s countingQuotaSource) Usage(ctx context.Context, orgID uuid.UUID, resource quota.Resource) (int64, error) {
+	return int64(len(s.docs.docs)), nil
+}
+
+func TestEsfDocumentController_CreateRespectsPlanQuota(t *testing.T) {
+	h := testutil.NewHarness(t)
+	orgID := uuid.New()
+	docs := &stubDocumentService{orgID: orgID, docs: map[uuid.UUID]*models.EsfCreateDocumentRequest{}}
+	src := countingQuotaSource{docs: docs}
+	enforcer, err := quota.NewEnforcer(quota.Config{
+		Plans: []quota.Plan{
+			{Name: "free", Limits: map[quota.Resource]int64{quota.ResourceDocuments: 1}, Upgrade: "standard"},
+			{Name: "standard"},
+		},
+		DefaultPlan: "free",
+		UpgradeURL:  "https://tunduck.example/billing",
+	}, src, src)
+	require.NoError(t, err)
+	NewEsfDocumentController(h.App, h.Logger, docs, nil, nil, enforcer)
+
+	user := testutil.NewUser()
+	token := testutil.WithToken(h.Token(user.ID.String(), user.Email))
+	org := testutil.WithHeader("X-Org-Id", orgID.String())
+
+	resp := h.Do(http.MethodPost, "/api/esf-documents", testutil.NewDocumentRequest(), token, org)
+	require.Equal(t, fiber.StatusCreated, resp.StatusCode, string(resp.Body))
+
+	resp = h.Do(http.MethodPost, "/api/esf-documents", testutil.NewDocumentRequest(), token, org, testutil.WithHeader("Accept-Language", "ru"))
+	require.Equal(t, fiber.StatusPaymentRequired, resp.StatusCode, string(resp.Body))
+	env := resp.Envelope()
+	assert.Equal(t, string(apperror.ErrQuotaExceeded), env.Error.Code)
+	assert.Equal(t, "Исчерпана квота documents тарифного плана free", env.Error.Message)
+
+	raw, err := json.Marshal(env.Error.Data)
+	require.NoError(t, err)
+	var hint quota.Exceeded
+	require.NoError(t, json.Unmarshal(raw, &hint))
+	assert.Equal(t, quota.Exceeded{
+		Resource: quota.ResourceDocuments, Plan: "free", Limit: 1, Used: 1, Requested: 1,
+		UpgradePlan: "standard", UpgradeURL: "https://tunduck.example/billing",
+	}, hint)
+	assert.Len(t, docs.docs, 1)
+}
@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+	"github.com/rusgainew/tunduck-app/pkg/validation"
+)
+
+// scheduleSendRequest время, к которому документ отправляется в ЭСФ
+type scheduleSendRequest struct {
+	SendAt time.Time `json:"sendAt" validate:"required"`
+}
+
+type DocumentScheduleController struct {
+	logger    *logger.Logger
+	service   services.DocumentScheduleService
+	authority rbac.Authority
+}
+
+// NewDocumentScheduleController регистрирует маршруты отложенной отправки документа в ЭСФ.
+// Планирование, как и отправка, требует права подписи; в момент отправки право проверяется снова.
+func NewDocumentScheduleController(app *fiber.App, log *logrus.Logger, service services.DocumentScheduleService, authority rbac.Authority) {
+	controller := &DocumentScheduleController{
+		logger:    logger.New(log),
+		service:   service,
+		authority: authority,
+	}
+
+	controller.logger.Info(context.Background(), "DocumentScheduleController инициализирован")
+	controller.registerRoutes(app)
+}
+
+func (c *DocumentScheduleController) registerRoutes(app *fiber.App) {
+	// JWT подключается к маршрутам, а не к группе: иначе он закрыл бы публичные маршруты документов
+	documents := app.Group("/api/esf-documents")
+	documents.Post("/:id/schedule-send", middleware.JWTMiddleware(),
+		rbac.RequireAuthority(rbac.PermissionSendDocument, c.authority, middleware.GetUserIDFromContext), c.scheduleSend)
+	documents.Delete("/:id/schedule-send", middleware.JWTMiddleware(), c.cancelSend)
+}
+
+// scheduleSend планирует отправку документа на sendAt; повторный запрос переносит время
+func (c *DocumentScheduleController) scheduleSend(ctx *fiber.Ctx) error {
+	orgID, docID, appErr := paymentTarget(ctx)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrUnauthorized, "unauthorized"))
+	}
+
+	var req scheduleSendRequest
+	if appErr := validation.ParseBody(ctx, &req); appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	scheduled, err := c.service.ScheduleSend(ctx.Context(), orgID, docID, userID, req.SendAt)
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Failed to schedule document send", err, logrus.Fields{"org_id": orgID.String(), "doc_id": docID.String()})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to schedule document send"))
+	}
+	return response.SuccessOK(ctx, "Document send scheduled", scheduled)
+}
+
+// cancelSend отменяет запланированную отправку документа
+func (c *DocumentScheduleController) cancelSend(ctx *fiber.Ctx) error {
+	orgID, docID, appErr := paymentTarget(ctx)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	if err := c.service.CancelSend(ctx.Context(), orgID, docID); err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to cancel document send"))
+	}
+	return response.SuccessNoContent(ctx)
+}
@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+	"github.com/rusgainew/tunduck-app/pkg/testutil"
+)
+
+// stubDocumentScheduleService хранит запланированные отправки в памяти
+type stubDocumentScheduleService struct {
+	services.DocumentScheduleService
+	scheduled map[uuid.UUID]services.ScheduledSend
+}
+
+func (s *stubDocumentScheduleService) ScheduleSend(ctx context.Context, orgID, id, userID uuid.UUID, sendAt time.Time) (*services.ScheduledSend, error) {
+	scheduled := services.ScheduledSend{DocumentID: id, SendAt: sendAt, ScheduledBy: userID}
+	s.scheduled[id] = scheduled
+	return &scheduled, nil
+}
+
+func (s *stubDocumentScheduleService) CancelSend(ctx context.Context, orgID, id uuid.UUID) error {
+	if _, ok := s.scheduled[id]; !ok {
+		return apperror.New(apperror.ErrNotFound, "document send is not scheduled")
+	}
+	delete(s.scheduled, id)
+	return nil
+}
+
+func TestDocumentScheduleController_ScheduleSend(t *testing.T) {
+	h := testutil.NewHarness(t)
+	signer, clerk := testutil.NewUser(), testutil.NewUser()
+	authority := &stubAuthority{grants: map[uuid.UUID]*rbac.Grant{
+		signer.ID: {UserID: signer.ID, Permission: rbac.PermissionSendDocument},
+	}}
+	svc := &stubDocumentScheduleService{scheduled: map[uuid.UUID]services.ScheduledSend{}}
+	NewDocumentScheduleController(h.App, h.Logger, svc, authority)
+
+	org := testutil.WithHeader("X-Org-Id", uuid.NewString())
+	docID := uuid.New()
+	path := "/api/esf-documents/" + docID.String() + "/schedule-send"
+	sendAt := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+	// Планировать отправку может только пользователь с правом подписи
+	resp := h.Do(http.MethodPost, path, fiber.Map{"sendAt": sendAt}, testutil.WithToken(h.Token(clerk.ID.String(), clerk.Email)), org)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+
+	token := testutil.WithToken(h.Token(signer.ID.String(), signer.Email))
+	resp = h.Do(http.MethodPost, path, fiber.Map{}, token, org)
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+
+	resp = h.Do(http.MethodPost, path, fiber.Map{"sendAt": sendAt}, token, org)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+	var scheduled services.ScheduledSend
+	resp.DecodeData(&scheduled)
+	assert.Equal(t, docID, scheduled.DocumentID)
+	assert.Equal(t, signer.ID, scheduled.ScheduledBy)
+	assert.True(t, sendAt.Equal(scheduled.SendAt))
+
+	resp = h.Do(http.MethodDelete, path, nil, token, org)
+	assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+	resp = h.Do(http.MethodDelete, path, nil, token, org)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+}
@@ -0,0 +1,25 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+)
+
+// sendDocument отправляет документ в ЭСФ. Маршрут защищен rbac.RequireAuthority:
+// отправить может пользователь с правом подписи или получивший его по делегированию
+func (c *EsfDocumentController) sendDocument(ctx *fiber.Ctx) error {
+	orgID, docID, appErr := paymentTarget(ctx)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	resp, err := c.service.SendDocument(ctx.Context(), orgID, docID)
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Failed to send document", err, logrus.Fields{"org_id": orgID.String(), "doc_id": docID.String()})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to send document"))
+	}
+	return response.SuccessOK(ctx, "Document sent to ESF", resp)
+}
@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+	"github.com/rusgainew/tunduck-app/pkg/validation"
+)
+
+// bulkDocumentsRequest документы для массового удаления или восстановления
+type bulkDocumentsRequest struct {
+	IDs []uuid.UUID `json:"ids" validate:"required,min=1,max=5000"`
+}
+
+type DocumentTrashController struct {
+	logger  *logger.Logger
+	service services.DocumentTrashService
+}
+
+// NewDocumentTrashController регистрирует маршруты корзины документов: массовое удаление,
+// список корзины и восстановление. Регистрируется до EsfDocumentController, чтобы
+// /api/esf-documents/trash не попадал в маршрут /:id
+func NewDocumentTrashController(app *fiber.App, log *logrus.Logger, service services.DocumentTrashService) {
+	controller := &DocumentTrashController{
+		logger:  logger.New(log),
+		service: service,
+	}
+
+	controller.logger.Info(context.Background(), "DocumentTrashController инициализирован")
+	controller.registerRoutes(app)
+}
+
+func (c *DocumentTrashController) registerRoutes(app *fiber.App) {
+	documents := app.Group("/api/esf-documents")
+	documents.Post("/bulk-delete", middleware.JWTMiddleware(), c.bulkDelete)
+
+	trash := documents.Group("/trash", middleware.JWTMiddleware())
+	trash.Get("/", c.listTrash)
+	trash.Post("/restore", c.bulkRestore)
+}
+
+// bulkDelete переносит документы в корзину; крупные пакеты обрабатываются фоновой операцией
+func (c *DocumentTrashController) bulkDelete(ctx *fiber.Ctx) error {
+	return c.bulk(ctx, "Documents moved to trash", "Bulk delete queued", "failed to delete documents", c.service.BulkDelete)
+}
+
+// bulkRestore восстанавливает документы из корзины в пределах срока хранения
+func (c *DocumentTrashController) bulkRestore(ctx *fiber.Ctx) error {
+	return c.bulk(ctx, "Documents restored", "Bulk restore queued", "failed to restore documents", c.service.BulkRestore)
+}
+
+// bulk разбирает запрос и отвечает 200 с итогом или 202 со ссылкой на операцию
+func (c *DocumentTrashController) bulk(
+	ctx *fiber.Ctx,
+	doneMsg, queuedMsg, failMsg string,
+	run func(context.Context, services.BulkDocumentsRequest) (*services.BulkDocumentsResult, error),
+) error {
+	orgID, err := resolveOrgID(ctx)
+	if err != nil {
+		c.logger.Warn(ctx.Context(), "Не удалось определить организацию", logrus.Fields{"error": err.Error()})
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "invalid organization ID"))
+	}
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrUnauthorized, "unauthorized"))
+	}
+
+	var req bulkDocumentsRequest
+	if appErr := validation.ParseBody(ctx, &req); appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	result, err := run(ctx.Context(), services.BulkDocumentsRequest{
+		OrganizationID: orgID,
+		IDs:            req.IDs,
+		RequestedBy:    userID.String(),
+	})
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка массовой обработки документов", err, logrus.Fields{"org_id": orgID.String(), "path": ctx.Path()})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, failMsg))
+	}
+
+	if result.Operation != nil {
+		ctx.Set(fiber.HeaderLocation, services.OperationLocation(result.Operation.ID))
+		return response.Success(ctx, fiber.StatusAccepted, queuedMsg, result)
+	}
+	return response.SuccessOK(ctx, doneMsg, result)
+}
+
+// listTrash возвращает документы корзины организации со временем окончательного удаления
+func (c *DocumentTrashController) listTrash(ctx *fiber.Ctx) error {
+	orgID, err := resolveOrgID(ctx)
+	if err != nil {
+		c.logger.Warn(ctx.Context(), "Не удалось определить организацию", logrus.Fields{"error": err.Error()})
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "invalid organization ID"))
+	}
+
+	params := pagination.ExtractPaginationParams(ctx)
+	documents, total, err := c.service.ListTrash(ctx.Context(), orgID, params)
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка получения корзины", err, logrus.Fields{"org_id": orgID.String()})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to fetch trash"))
+	}
+
+	return response.List(ctx, documents, pagination.NewPaginationInfo(params.Page, params.PageSize, total))
+}
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+	"github.com/rusgainew/tunduck-app/pkg/testutil"
+)
+
+// stubDocumentTrashService обрабатывает до async документов сразу, остальные — операцией
+type stubDocumentTrashService struct {
+	services.DocumentTrashService
+	async int
+	last  services.BulkDocumentsRequest
+	trash []services.TrashedDocument
+}
+
+func (s *stubDocumentTrashService) BulkDelete(ctx context.Context, req services.BulkDocumentsRequest) (*services.BulkDocumentsResult, error) {
+	s.last = req
+	if len(req.IDs) > s.async {
+		return &services.BulkDocumentsResult{Operation: &entity.Operation{ID: uuid.New(), Kind: services.OperationKindBulkDelete}}, nil
+	}
+	return &services.BulkDocumentsResult{Processed: len(req.IDs)}, nil
+}
+
+func (s *stubDocumentTrashService) ListTrash(ctx context.Context, orgID uuid.UUID, params pagination.PaginationParams) ([]services.TrashedDocument, int64, error) {
+	return s.trash, int64(len(s.trash)), nil
+}
+
+func TestDocumentTrashController_BulkDelete(t *testing.T) {
+	h := testutil.NewHarness(t)
+	svc := &stubDocumentTrashService{async: 2}
+	NewDocumentTrashController(h.App, h.Logger, svc)
+
+	user := testutil.NewUser()
+	token := testutil.WithToken(h.Token(user.ID.String(), user.Email))
+	orgID := uuid.New()
+	org := testutil.WithHeader("X-Org-Id", orgID.String())
+
+	resp := h.Do(http.MethodPost, "/api/esf-documents/bulk-delete", fiber.Map{"ids": []uuid.UUID{uuid.New()}}, org)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+	resp = h.Do(http.MethodPost, "/api/esf-documents/bulk-delete", fiber.Map{"ids": []uuid.UUID{}}, token, org)
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+
+	resp = h.Do(http.MethodPost, "/api/esf-documents/bulk-delete", fiber.Map{"ids": []uuid.UUID{uuid.New()}}, token)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, string(apperror.ErrInvalidRequest), resp.ErrorCode())
+
+	resp = h.Do(http.MethodPost, "/api/esf-documents/bulk-delete", fiber.Map{"ids": []uuid.UUID{uuid.New(), uuid.New()}}, token, org)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+	var result services.BulkDocumentsResult
+	resp.DecodeData(&result)
+	assert.Equal(t, 2, result.Processed)
+	assert.Equal(t, orgID, svc.last.OrganizationID)
+	assert.Equal(t, user.ID.String(), svc.last.RequestedBy)
+
+	// Крупный пакет ставится в очередь: 202 и ссылка на операцию
+	resp = h.Do(http.MethodPost, "/api/esf-documents/bulk-delete", fiber.Map{"ids": []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}}, token, org)
+	require.Equal(t, fiber.StatusAccepted, resp.StatusCode, string(resp.Body))
+	resp.DecodeData(&result)
+	require.NotNil(t, result.Operation)
+	assert.Equal(t, services.OperationLocation(result.Operation.ID), resp.Header.Get(fiber.HeaderLocation))
+}
+
+func TestDocumentTrashController_ListTrash(t *testing.T) {
+	h := testutil.NewHarness(t)
+	deletedAt := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	svc := &stubDocumentTrashService{trash: []services.TrashedDocument{
+		{ID: uuid.New(), DeletedAt: deletedAt, PurgeAt: deletedAt.Add(services.DefaultTrashRetention)},
+	}}
+	NewDocumentTrashController(h.App, h.Logger, svc)
+
+	user := testutil.NewUser()
+	token := testutil.WithToken(h.Token(user.ID.String(), user.Email))
+
+	resp := h.Do(http.MethodGet, "/api/esf-documents/trash?orgId="+uuid.NewString(), nil, token)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+	var trash []services.TrashedDocument
+	resp.DecodeData(&trash)
+	require.Len(t, trash, 1)
+	assert.Equal(t, svc.trash[0].ID, trash[0].ID)
+	assert.True(t, svc.trash[0].PurgeAt.Equal(trash[0].PurgeAt))
+}
@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+)
+
+// viewQueryParam параметр списка документов с ID сохраненного представления
+const viewQueryParam = "view"
+
+// documentCursorSorts поля сортировки курсорного списка документов (первое — по умолчанию)
+var documentCursorSorts = []string{"created_at", "updated_at", "delivery_date"}
+
+// requireViewAuth требует JWT только для списка с представлением (?view=):
+// представления личные, а сам список документов доступен без токена
+func requireViewAuth() fiber.Handler {
+	auth := middleware.JWTMiddleware()
+	return func(ctx *fiber.Ctx) error {
+		if ctx.Query(viewQueryParam) == "" {
+			return ctx.Next()
+		}
+		return auth(ctx)
+	}
+}
+
+// listView загружает представление из ?view= или возвращает nil, если оно не указано
+func (c *EsfDocumentController) listView(ctx *fiber.Ctx) (*services.SavedView, *apperror.AppError) {
+	raw := ctx.Query(viewQueryParam)
+	if raw == "" {
+		return nil, nil
+	}
+	if c.views == nil {
+		return nil, apperror.New(apperror.ErrInvalidRequest, "saved views are not available")
+	}
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return nil, apperror.New(apperror.ErrInvalidRequest, "invalid view ID format")
+	}
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return nil, apperror.From(err, apperror.ErrUnauthorized, "unauthorized")
+	}
+
+	view, err := c.views.Get(ctx.Context(), userID, id)
+	if err != nil {
+		return nil, apperror.From(err, apperror.ErrInternal, "failed to fetch view")
+	}
+	return view, nil
+}
+
+// documentFilters фильтры списка: фильтры представления, поверх них явные параметры запроса;
+// относительные даты ("-30d") считаются от текущего дня
+func documentFilters(ctx *fiber.Ctx, view *services.SavedView) pagination.DocumentFilterParams {
+	filters := pagination.ExtractDocumentFilters(ctx)
+	if view != nil {
+		filters = view.Filter.Merge(filters)
+	}
+	return filters.ResolveDates(time.Now())
+}
+
+// applyViewSort берет сортировку представления, если в запросе она не указана
+func applyViewSort(ctx *fiber.Ctx, view *services.SavedView, sort, order *string) {
+	if view == nil {
+		return
+	}
+	if ctx.Query("sort") == "" && view.Sort != "" {
+		*sort = view.Sort
+	}
+	if ctx.Query("order") == "" && view.Order != "" {
+		*order = view.Order
+	}
+}
@@ -3,39 +3,44 @@ package controllers
 import (
 	"context"
 	"fmt"
-	"net/http"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/rusgainew/tunduck-app/internal/models"
-	repositorypostgres "github.com/rusgainew/tunduck-app/internal/repository/repository_postgres"
 	"github.com/rusgainew/tunduck-app/internal/services"
-	serviceimpl "github.com/rusgainew/tunduck-app/internal/services/service_impl"
 	"github.com/rusgainew/tunduck-app/pkg/apperror"
 	"github.com/rusgainew/tunduck-app/pkg/logger"
 	"github.com/rusgainew/tunduck-app/pkg/middleware"
 	"github.com/rusgainew/tunduck-app/pkg/pagination"
+	"github.com/rusgainew/tunduck-app/pkg/quota"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+	"github.com/rusgainew/tunduck-app/pkg/validation"
 	"github.com/sirupsen/logrus"
-	"gorm.io/gorm"
 )
 
 type EsfDocumentController struct {
-	logger  *logger.Logger
-	service services.EsfDocumentService
-	db      *gorm.DB
+	logger    *logger.Logger
+	service   services.EsfDocumentService
+	authority rbac.Authority
+	views     services.SavedViewService
+	quotas    *quota.Enforcer
 }
 
-func NewEsfDocumentController(app *fiber.App, log *logrus.Logger, db *gorm.DB) {
-	// Инициализируем слои
-	repo := repositorypostgres.NewEsfDocumentRepositoryPostgres(db, log)
-	service := serviceimpl.NewEsfDocumentService(repo, db, log)
-
+// NewEsfDocumentController использует сервис из контейнера, чтобы запись шла через
+// общий репозиторий (кеш и поисковый outbox). authority проверяет право подписи при отправке,
+// views — личные представления, применяемые к спискам по ?view=, quotas — лимит документов
+// тарифного плана (nil — без ограничения)
+func NewEsfDocumentController(app *fiber.App, log *logrus.Logger, service services.EsfDocumentService, authority rbac.Authority, views services.SavedViewService, quotas *quota.Enforcer) {
 	l := logger.New(log)
 
 	controller := &EsfDocumentController{
-		logger:  l,
-		service: service,
-		db:      db,
+		logger:    l,
+		service:   service,
+		authority: authority,
+		views:     views,
+		quotas:    quotas,
 	}
 
 	l.Info(context.Background(), "EsfDocumentController initialized")
@@ -45,38 +50,48 @@ func NewEsfDocumentController(app *fiber.App, log *logrus.Logger, db *gorm.DB) {
 func (c *EsfDocumentController) registerRoutes(app *fiber.App) {
 	esfDocumentGroup := app.Group("/api/esf-documents")
 
-	// Публичные routes (без JWT)
-	esfDocumentGroup.Get("/", c.getEsfDocuments)
-	esfDocumentGroup.Get("/paginated", c.getEsfDocumentsPaginated)
-	esfDocumentGroup.Get("/:id", c.getByEsfDocument)
+	// Публичные routes (без JWT). Токен, если он передан, определяет ссылки на действия
+	optional := middleware.OptionalJWT()
+	esfDocumentGroup.Get("/", optional, c.getEsfDocuments)
+	esfDocumentGroup.Get("/paginated", optional, requireViewAuth(), c.getEsfDocumentsPaginated)
+	esfDocumentGroup.Get("/cursor", optional, requireViewAuth(), c.getEsfDocumentsCursor)
+	esfDocumentGroup.Get("/search", optional, c.searchEsfDocuments)
+	esfDocumentGroup.Get("/:id", optional, c.getByEsfDocument)
 
 	// Защищенные routes (с JWT)
 	protected := esfDocumentGroup.Group("")
 	protected.Use(middleware.JWTMiddleware())
-	protected.Post("/", c.createEsfDocument)
+	protected.Post("/", quota.Require(c.quotas, quota.ResourceDocuments, resolveOrgID, quota.One), c.createEsfDocument)
 	protected.Put("/:id", c.updateEsfDocument)
 	protected.Delete("/:id", c.deleteEsfDocument)
+	protected.Get("/:id/pdf", c.downloadPDF)
+	protected.Post("/:id/send", rbac.RequireAuthority(rbac.PermissionSendDocument, c.authority, middleware.GetUserIDFromContext), c.sendDocument)
+	protected.Get("/:id/payments", c.listPayments)
+	protected.Post("/:id/payments", c.recordPayment)
+	protected.Delete("/:id/payments/:paymentId", c.deletePayment)
 }
 
 // getEsfDocuments возвращает все документы ЭСФ
 func (c *EsfDocumentController) getEsfDocuments(ctx *fiber.Ctx) error {
 	c.logger.Info(ctx.Context(), "Fetching ESF documents")
 
-	orgID, err := c.resolveOrgID(ctx)
+	orgID, err := resolveOrgID(ctx)
 	if err != nil {
 		c.logger.Warn(ctx.Context(), "Failed to resolve org ID", logrus.Fields{"error": err.Error()})
 		appErr := apperror.New(apperror.ErrInvalidRequest, "invalid organization ID")
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
+	}
+
+	fields, appErr := requestedFields(ctx, documentFields)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
 	}
 
 	documents, err := c.service.GetAllDocuments(ctx.Context(), orgID)
 	if err != nil {
-		appErr, ok := err.(*apperror.AppError)
-		if !ok {
-			appErr = apperror.New(apperror.ErrInternal, "failed to fetch documents").WithError(err)
-		}
+		appErr := apperror.From(err, apperror.ErrInternal, "failed to fetch documents")
 		c.logger.Error(ctx.Context(), "Failed to fetch documents", err, logrus.Fields{"org_id": orgID.String()})
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
 	c.logger.Debug(ctx.Context(), "Documents fetched successfully", logrus.Fields{
@@ -84,40 +99,44 @@ func (c *EsfDocumentController) getEsfDocuments(ctx *fiber.Ctx) error {
 		"count":  len(documents),
 	})
 
-	return ctx.Status(http.StatusOK).JSON(fiber.Map{
-		"success": true,
-		"data":    documents,
-		"count":   len(documents),
-	})
+	return response.SparseList(ctx, fields, newDocumentResources(orgID, documents, c.linkAccess(ctx)), fiber.Map{"count": len(documents)})
 }
 
 // getEsfDocumentsPaginated возвращает документы ЭСФ с пагинацией
 func (c *EsfDocumentController) getEsfDocumentsPaginated(ctx *fiber.Ctx) error {
 	c.logger.Info(ctx.Context(), "Вибірка документів ЕСФ з пагінацією")
 
-	orgID, err := c.resolveOrgID(ctx)
+	orgID, err := resolveOrgID(ctx)
 	if err != nil {
 		c.logger.Warn(ctx.Context(), "Не вдалося визначити ID організації", logrus.Fields{"error": err.Error()})
 		appErr := apperror.New(apperror.ErrInvalidRequest, "invalid organization ID")
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
+	}
+
+	fields, appErr := requestedFields(ctx, documentFields)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	view, appErr := c.listView(ctx)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
 	}
 
-	// Витягуємо параметри пагінації та фільтрації
+	// Витягуємо параметри пагінації та фільтрації; збережене представлення доповнює їх
 	paginationParams := pagination.ExtractPaginationParams(ctx)
-	filterParams := pagination.ExtractDocumentFilters(ctx)
+	applyViewSort(ctx, view, &paginationParams.Sort, &paginationParams.Order)
+	filterParams := documentFilters(ctx, view)
 
 	documents, totalCount, err := c.service.GetAllDocumentsPaginated(ctx.Context(), orgID, paginationParams, filterParams)
 	if err != nil {
-		appErr, ok := err.(*apperror.AppError)
-		if !ok {
-			appErr = apperror.New(apperror.ErrInternal, "failed to fetch documents").WithError(err)
-		}
+		appErr := apperror.From(err, apperror.ErrInternal, "failed to fetch documents")
 		c.logger.Error(ctx.Context(), "Ошибка вибірки документів", err, logrus.Fields{"org_id": orgID.String()})
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
-	// Формуємо відповідь з пагінацією
-	response := pagination.NewPaginatedResponse(documents, paginationParams.Page, paginationParams.PageSize, totalCount)
+	// Формуємо метадані пагінації
+	meta := pagination.NewPaginationInfo(paginationParams.Page, paginationParams.PageSize, totalCount)
 
 	c.logger.Debug(ctx.Context(), "Документи успішно вибрані", logrus.Fields{
 		"org_id": orgID.String(),
@@ -126,7 +145,87 @@ func (c *EsfDocumentController) getEsfDocumentsPaginated(ctx *fiber.Ctx) error {
 		"page":   paginationParams.Page,
 	})
 
-	return ctx.Status(http.StatusOK).JSON(response)
+	return response.SparseList(ctx, fields, newDocumentResources(orgID, documents, c.linkAccess(ctx)), meta)
+}
+
+// searchEsfDocuments выполняет полнотекстовый поиск документов ЭСФ (OpenSearch или Postgres)
+func (c *EsfDocumentController) searchEsfDocuments(ctx *fiber.Ctx) error {
+	orgID, err := resolveOrgID(ctx)
+	if err != nil {
+		c.logger.Warn(ctx.Context(), "Failed to resolve org ID", logrus.Fields{"error": err.Error()})
+		appErr := apperror.New(apperror.ErrInvalidRequest, "invalid organization ID")
+		return response.Error(ctx, appErr)
+	}
+
+	fields, appErr := requestedFields(ctx, documentFields)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	text := strings.TrimSpace(ctx.Query("q"))
+	if text == "" {
+		return response.Error(ctx, apperror.ValidationError("search query is required"))
+	}
+
+	paginationParams := pagination.ExtractPaginationParams(ctx)
+
+	documents, totalCount, err := c.service.SearchDocuments(ctx.Context(), orgID, text, paginationParams)
+	if err != nil {
+		appErr := apperror.From(err, apperror.ErrInternal, "failed to search documents")
+		c.logger.Error(ctx.Context(), "Failed to search documents", err, logrus.Fields{"org_id": orgID.String()})
+		return response.Error(ctx, appErr)
+	}
+
+	meta := pagination.NewPaginationInfo(paginationParams.Page, paginationParams.PageSize, totalCount)
+	return response.SparseList(ctx, fields, newDocumentResources(orgID, documents, c.linkAccess(ctx)), meta)
+}
+
+// getEsfDocumentsCursor возвращает документы ЭСФ с курсорной пагинацией
+func (c *EsfDocumentController) getEsfDocumentsCursor(ctx *fiber.Ctx) error {
+	c.logger.Info(ctx.Context(), "Fetching ESF documents by cursor")
+
+	orgID, err := resolveOrgID(ctx)
+	if err != nil {
+		c.logger.Warn(ctx.Context(), "Failed to resolve org ID", logrus.Fields{"error": err.Error()})
+		appErr := apperror.New(apperror.ErrInvalidRequest, "invalid organization ID")
+		return response.Error(ctx, appErr)
+	}
+
+	fields, appErr := requestedFields(ctx, documentFields)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	view, appErr := c.listView(ctx)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	cursorParams := pagination.ExtractCursorParams(ctx, documentCursorSorts...)
+	sort := cursorParams.Sort
+	applyViewSort(ctx, view, &sort, &cursorParams.Order)
+	// Курсорный список сортируется только по одному полю из documentCursorSorts
+	for _, allowed := range documentCursorSorts {
+		if sort == allowed {
+			cursorParams.Sort = sort
+		}
+	}
+	filterParams := documentFilters(ctx, view)
+
+	documents, info, err := c.service.GetAllDocumentsCursor(ctx.Context(), orgID, cursorParams, filterParams)
+	if err != nil {
+		appErr := apperror.From(err, apperror.ErrInternal, "failed to fetch documents")
+		c.logger.Error(ctx.Context(), "Failed to fetch documents by cursor", err, logrus.Fields{"org_id": orgID.String()})
+		return response.Error(ctx, appErr)
+	}
+
+	c.logger.Debug(ctx.Context(), "Documents fetched by cursor successfully", logrus.Fields{
+		"org_id":   orgID.String(),
+		"count":    len(documents),
+		"has_next": info.HasNext,
+	})
+
+	return response.SparseList(ctx, fields, newDocumentResources(orgID, documents, c.linkAccess(ctx)), info)
 }
 
 // getByEsfDocument возвращает документ ЭСФ по ID
@@ -134,31 +233,33 @@ func (c *EsfDocumentController) getByEsfDocument(ctx *fiber.Ctx) error {
 	id := ctx.Params("id")
 	c.logger.Debug(ctx.Context(), "Fetching document by ID", logrus.Fields{"doc_id": id})
 
-	orgID, err := c.resolveOrgID(ctx)
+	orgID, err := resolveOrgID(ctx)
 	if err != nil {
 		c.logger.Warn(ctx.Context(), "Failed to resolve org ID", logrus.Fields{"error": err.Error()})
 		appErr := apperror.New(apperror.ErrInvalidRequest, "invalid organization ID")
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
+	}
+
+	fields, appErr := requestedFields(ctx, documentFields)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
 	}
 
 	docID, err := uuid.Parse(id)
 	if err != nil {
 		c.logger.Warn(ctx.Context(), "Invalid UUID format", logrus.Fields{"id": id})
 		appErr := apperror.New(apperror.ErrInvalidRequest, "invalid document ID format")
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
 	document, err := c.service.GetDocumentByID(ctx.Context(), orgID, docID)
 	if err != nil {
-		appErr, ok := err.(*apperror.AppError)
-		if !ok {
-			appErr = apperror.New(apperror.ErrInternal, "failed to fetch document").WithError(err)
-		}
+		appErr := apperror.From(err, apperror.ErrInternal, "failed to fetch document")
 		c.logger.Error(ctx.Context(), "Failed to fetch document", err, logrus.Fields{
 			"org_id": orgID.String(),
 			"doc_id": docID.String(),
 		})
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
 	if document == nil {
@@ -167,56 +268,46 @@ func (c *EsfDocumentController) getByEsfDocument(ctx *fiber.Ctx) error {
 			"doc_id": docID.String(),
 		})
 		appErr := apperror.New(apperror.ErrDocumentNotFound, "document not found")
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
-	return ctx.Status(http.StatusOK).JSON(fiber.Map{
-		"success": true,
-		"data":    document,
-	})
+	setETag(ctx, document.Version)
+	return response.SparseOK(ctx, fields, newDocumentResource(orgID, document, c.linkAccess(ctx)))
 }
 
 // createEsfDocument создает новый документ ЭСФ
 func (c *EsfDocumentController) createEsfDocument(ctx *fiber.Ctx) error {
 	c.logger.Info(ctx.Context(), "Creating new ESF document")
 
-	orgID, err := c.resolveOrgID(ctx)
+	orgID, err := resolveOrgID(ctx)
 	if err != nil {
 		c.logger.Warn(ctx.Context(), "Failed to resolve org ID", logrus.Fields{"error": err.Error()})
 		appErr := apperror.New(apperror.ErrInvalidRequest, "invalid organization ID")
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
 	var req models.EsfCreateDocumentRequest
 	if err := ctx.BodyParser(&req); err != nil {
 		c.logger.Warn(ctx.Context(), "Failed to parse request body", logrus.Fields{"error": err.Error()})
 		appErr := apperror.New(apperror.ErrInvalidRequest, "invalid request format")
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
 	// Валидируем запрос
-	if err := middleware.ValidateStruct(&req); err != nil {
-		c.logger.Warn(ctx.Context(), "Validation failed for create request", logrus.Fields{"error": err.Error()})
-		appErr := apperror.New(apperror.ErrValidation, "validation error").WithDetails(err.Error())
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+	if appErr := validation.Struct(&req); appErr != nil {
+		c.logger.Warn(ctx.Context(), "Validation failed for create request", logrus.Fields{"fields": len(appErr.Fields)})
+		return response.Error(ctx, appErr)
 	}
 
-	response, err := c.service.CreateDocument(ctx.Context(), orgID, &req)
+	created, err := c.service.CreateDocument(ctx.Context(), orgID, &req)
 	if err != nil {
-		appErr, ok := err.(*apperror.AppError)
-		if !ok {
-			appErr = apperror.New(apperror.ErrInternal, "failed to create document").WithError(err)
-		}
+		appErr := apperror.From(err, apperror.ErrInternal, "failed to create document")
 		c.logger.Error(ctx.Context(), "Failed to create document", err, logrus.Fields{"org_id": orgID.String()})
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
 	c.logger.Info(ctx.Context(), "Document created successfully", logrus.Fields{"org_id": orgID.String()})
-	return ctx.Status(http.StatusCreated).JSON(fiber.Map{
-		"success": true,
-		"data":    response,
-		"message": "Document created successfully",
-	})
+	return response.SuccessCreated(ctx, "Document created successfully", created)
 }
 
 // updateEsfDocument обновляет документ ЭСФ
@@ -224,50 +315,63 @@ func (c *EsfDocumentController) updateEsfDocument(ctx *fiber.Ctx) error {
 	id := ctx.Params("id")
 	c.logger.Info(ctx.Context(), "Updating ESF document", logrus.Fields{"doc_id": id})
 
-	orgID, err := c.resolveOrgID(ctx)
+	orgID, err := resolveOrgID(ctx)
 	if err != nil {
 		c.logger.Warn(ctx.Context(), "Failed to resolve org ID", logrus.Fields{"error": err.Error()})
 		appErr := apperror.New(apperror.ErrInvalidRequest, "invalid organization ID")
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
 	docID, err := uuid.Parse(id)
 	if err != nil {
 		c.logger.Warn(ctx.Context(), "Invalid UUID format", logrus.Fields{"id": id})
 		appErr := apperror.New(apperror.ErrInvalidRequest, "invalid document ID format")
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
 	var req models.EsfEditDocumentRequest
 	if err := ctx.BodyParser(&req); err != nil {
 		c.logger.Warn(ctx.Context(), "Failed to parse request body", logrus.Fields{"error": err.Error()})
 		appErr := apperror.New(apperror.ErrInvalidRequest, "invalid request format")
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
 	req.ID = docID
 
+	version, appErr := expectedVersion(ctx, req.Version)
+	if appErr != nil {
+		c.logger.Warn(ctx.Context(), "Missing or invalid document version", logrus.Fields{"doc_id": id})
+		return response.Error(ctx, appErr)
+	}
+	req.Version = version
+
 	// Валидируем запрос
-	if err := middleware.ValidateStruct(&req); err != nil {
-		c.logger.Warn(ctx.Context(), "Validation failed for update request", logrus.Fields{"error": err.Error()})
-		appErr := apperror.New(apperror.ErrValidation, "validation error").WithDetails(err.Error())
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+	if appErr := validation.Struct(&req); appErr != nil {
+		c.logger.Warn(ctx.Context(), "Validation failed for update request", logrus.Fields{"fields": len(appErr.Fields)})
+		return response.Error(ctx, appErr)
+	}
+
+	if appErr := ensureDocumentEditable(ctx.Context(), c.service, orgID, docID); appErr != nil {
+		c.logger.Warn(ctx.Context(), "Document cannot be updated", logrus.Fields{"doc_id": id, "error": appErr.Message})
+		return response.Error(ctx, appErr)
 	}
 
 	if err := c.service.UpdateDocument(ctx.Context(), orgID, &req); err != nil {
-		appErr, ok := err.(*apperror.AppError)
-		if !ok {
-			appErr = apperror.New(apperror.ErrInternal, "failed to update document").WithError(err)
-		}
+		appErr := apperror.From(err, apperror.ErrInternal, "failed to update document")
 		c.logger.Error(ctx.Context(), "Failed to update document", err, logrus.Fields{"org_id": orgID.String(), "doc_id": docID.String()})
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
 	c.logger.Info(ctx.Context(), "Document updated successfully", logrus.Fields{"org_id": orgID.String(), "doc_id": docID.String()})
-	return ctx.Status(http.StatusOK).JSON(fiber.Map{
-		"success": true,
-		"message": "Document updated successfully",
-	})
+	result := fiber.Map{"version": version + 1}
+	// Документ уже сохранен: ошибка расчета предупреждений не меняет ответ
+	if warnings, err := c.service.DocumentWarnings(ctx.Context(), orgID, docID); err != nil {
+		c.logger.Warn(ctx.Context(), "Failed to compute document warnings", logrus.Fields{"doc_id": id, "error": err.Error()})
+	} else if len(warnings) > 0 {
+		result["warnings"] = warnings
+	}
+	setETag(ctx, version+1)
+	return response.SuccessOK(ctx, "Document updated successfully", result)
 }
 
 // deleteEsfDocument удаляет документ ЭСФ
@@ -275,38 +379,38 @@ func (c *EsfDocumentController) deleteEsfDocument(ctx *fiber.Ctx) error {
 	id := ctx.Params("id")
 	c.logger.Info(ctx.Context(), "Deleting ESF document", logrus.Fields{"doc_id": id})
 
-	orgID, err := c.resolveOrgID(ctx)
+	orgID, err := resolveOrgID(ctx)
 	if err != nil {
 		c.logger.Warn(ctx.Context(), "Failed to resolve org ID", logrus.Fields{"error": err.Error()})
 		appErr := apperror.New(apperror.ErrInvalidRequest, "invalid organization ID")
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
 	docID, err := uuid.Parse(id)
 	if err != nil {
 		c.logger.Warn(ctx.Context(), "Invalid UUID format", logrus.Fields{"id": id})
 		appErr := apperror.New(apperror.ErrInvalidRequest, "invalid document ID format")
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
+	}
+
+	if appErr := ensureDocumentEditable(ctx.Context(), c.service, orgID, docID); appErr != nil {
+		c.logger.Warn(ctx.Context(), "Document cannot be deleted", logrus.Fields{"doc_id": id, "error": appErr.Message})
+		return response.Error(ctx, appErr)
 	}
 
 	if err := c.service.DeleteDocument(ctx.Context(), orgID, docID); err != nil {
-		appErr, ok := err.(*apperror.AppError)
-		if !ok {
-			appErr = apperror.New(apperror.ErrInternal, "failed to delete document").WithError(err)
-		}
+		appErr := apperror.From(err, apperror.ErrInternal, "failed to delete document")
 		c.logger.Error(ctx.Context(), "Failed to delete document", err, logrus.Fields{"org_id": orgID.String(), "doc_id": docID.String()})
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
 	c.logger.Info(ctx.Context(), "Document deleted successfully", logrus.Fields{"org_id": orgID.String(), "doc_id": docID.String()})
-	return ctx.Status(http.StatusOK).JSON(fiber.Map{
-		"success": true,
-		"message": "Document deleted successfully",
-	})
+	return response.SuccessOK(ctx, "Document deleted successfully", nil)
 }
 
-// resolveOrgID достает идентификатор организации из заголовка X-Org-Id или query orgId.
-func (c *EsfDocumentController) resolveOrgID(ctx *fiber.Ctx) (uuid.UUID, error) {
+// resolveOrgID достает идентификатор организации из заголовка X-Org-Id или query orgId
+// и сохраняет его в Locals, чтобы записи логгера запроса содержали org_id.
+func resolveOrgID(ctx *fiber.Ctx) (uuid.UUID, error) {
 	raw := ctx.Get("X-Org-Id")
 	if raw == "" {
 		raw = ctx.Query("orgId")
@@ -318,5 +422,6 @@ func (c *EsfDocumentController) resolveOrgID(ctx *fiber.Ctx) (uuid.UUID, error)
 	if err != nil {
 		return uuid.Nil, fmt.Errorf("invalid organization id: %w", err)
 	}
+	ctx.Locals(string(logger.OrganizationID), orgID.String())
 	return orgID, nil
 }
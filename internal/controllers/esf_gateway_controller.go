@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/esfgateway"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+	"github.com/rusgainew/tunduck-app/pkg/validation"
+)
+
+// gatewayEndpointRequest выбор адреса шлюза; пустое имя возвращает автоматическое переключение
+type gatewayEndpointRequest struct {
+	Endpoint string `json:"endpoint" validate:"max=100"`
+}
+
+type EsfGatewayController struct {
+	logger  *logger.Logger
+	gateway *esfgateway.Failover
+}
+
+// NewEsfGatewayController регистрирует маршруты администратора для адресов шлюза ЭСФ:
+// состояние переключения и принудительный выбор адреса без повторного развертывания
+func NewEsfGatewayController(app *fiber.App, log *logrus.Logger, gateway *esfgateway.Failover) {
+	controller := &EsfGatewayController{
+		logger:  logger.New(log),
+		gateway: gateway,
+	}
+
+	controller.logger.Info(context.Background(), "EsfGatewayController инициализирован")
+	controller.registerRoutes(app)
+}
+
+func (c *EsfGatewayController) registerRoutes(app *fiber.App) {
+	admin := app.Group("/api/admin/esf-gateway", middleware.JWTMiddleware(), rbac.RequireAdminRole())
+	admin.Get("/", c.getStatus)
+	admin.Put("/endpoint", c.setEndpoint)
+}
+
+// getStatus возвращает адреса шлюза и активный адрес
+func (c *EsfGatewayController) getStatus(ctx *fiber.Ctx) error {
+	return response.OK(ctx, c.gateway.Status())
+}
+
+// setEndpoint закрепляет адрес шлюза на всех инстансах или возвращает автоматическое переключение
+func (c *EsfGatewayController) setEndpoint(ctx *fiber.Ctx) error {
+	var req gatewayEndpointRequest
+	if appErr := validation.ParseBody(ctx, &req); appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	if err := c.gateway.Force(ctx.Context(), req.Endpoint); err != nil {
+		if errors.Is(err, esfgateway.ErrUnknownEndpoint) {
+			return response.Error(ctx, apperror.ValidationError("unknown gateway endpoint"))
+		}
+		c.logger.Error(ctx.Context(), "Ошибка выбора адреса шлюза ЭСФ", err, logrus.Fields{"endpoint": req.Endpoint})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to switch gateway endpoint"))
+	}
+	c.logger.Info(ctx.Context(), "Адрес шлюза ЭСФ выбран администратором", logrus.Fields{"endpoint": req.Endpoint})
+
+	return response.OK(ctx, c.gateway.Status())
+}
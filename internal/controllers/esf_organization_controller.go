@@ -2,7 +2,6 @@ package controllers
 
 import (
 	"context"
-	"net/http"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -14,29 +13,36 @@ import (
 	"github.com/rusgainew/tunduck-app/internal/services"
 	serviceimpl "github.com/rusgainew/tunduck-app/internal/services/service_impl"
 	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/cache"
 	"github.com/rusgainew/tunduck-app/pkg/logger"
 	"github.com/rusgainew/tunduck-app/pkg/middleware"
 	"github.com/rusgainew/tunduck-app/pkg/pagination"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+	"github.com/rusgainew/tunduck-app/pkg/validation"
 )
 
 type EsfOrganizationController struct {
-	logger  *logger.Logger
-	service services.EsfOrganizationService
-	db      *gorm.DB
+	logger    *logger.Logger
+	service   services.EsfOrganizationService
+	db        *gorm.DB
+	responses *middleware.ResponseCache
 }
 
-func NewEsfOrganizationController(app *fiber.App, log *logrus.Logger, db *gorm.DB) {
+// NewEsfOrganizationController регистрирует маршруты организаций ЭСФ. Публичные ответы GET
+// кешируются в responses (nil — без кеша) и сбрасываются после изменения организаций.
+func NewEsfOrganizationController(app *fiber.App, log *logrus.Logger, db *gorm.DB, responses *middleware.ResponseCache) {
 	// Инициализируем слои
 	repo := repositorypostgres.NewEsfOrganizationRepositoryPostgres(db, log)
 	service := serviceimpl.NewEsfOrganizationService(repo, log)
 
 	controller := &EsfOrganizationController{
-		logger:  logger.New(log),
-		service: service,
-		db:      db,
+		logger:    logger.New(log),
+		service:   service,
+		db:        db,
+		responses: responses,
 	}
 
-	controller.logger.Info(context.Background(), "EsfOrganizationController initialized", logrus.Fields{})
+	controller.logger.Info(context.Background(), "EsfOrganizationController initialized")
 	controller.registerRoutes(app)
 }
 
@@ -44,9 +50,13 @@ func (c *EsfOrganizationController) registerRoutes(app *fiber.App) {
 	esfOrganizationGroup := app.Group("/api/esf-organizations")
 
 	// Публичные routes (без JWT)
-	esfOrganizationGroup.Get("/", c.getEsfOrganizations)
-	esfOrganizationGroup.Get("/paginated", c.getEsfOrganizationsPaginated)
-	esfOrganizationGroup.Get("/:id", c.getByEsfOrganization)
+	lists := c.responses.Handler(middleware.StaticTags(cache.TagOrganizations))
+	esfOrganizationGroup.Get("/", lists, c.getEsfOrganizations)
+	esfOrganizationGroup.Get("/paginated", lists, c.getEsfOrganizationsPaginated)
+	esfOrganizationGroup.Get("/cursor", lists, c.getEsfOrganizationsCursor)
+	esfOrganizationGroup.Get("/:id", c.responses.Handler(func(ctx *fiber.Ctx) []string {
+		return []string{cache.OrgTag(ctx.Params("id"))}
+	}), c.getByEsfOrganization)
 
 	// Защищенные routes (с JWT)
 	protected := esfOrganizationGroup.Group("")
@@ -58,24 +68,31 @@ func (c *EsfOrganizationController) registerRoutes(app *fiber.App) {
 
 // getEsfOrganizations возвращает все организации ЭСФ
 func (c *EsfOrganizationController) getEsfOrganizations(ctx *fiber.Ctx) error {
-	c.logger.Info(ctx.Context(), "Fetching all ESF organizations", logrus.Fields{})
+	c.logger.Info(ctx.Context(), "Fetching all ESF organizations")
+
+	fields, appErr := requestedFields(ctx, organizationFields)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
 
 	organizations, err := c.service.GetAllOrganizations(ctx.Context())
 	if err != nil {
-		appErr, ok := err.(*apperror.AppError)
-		if !ok {
-			appErr = apperror.New(apperror.ErrInternal, "failed to fetch organizations").WithError(err)
-		}
-		c.logger.Error(ctx.Context(), "Failed to fetch organizations", err, logrus.Fields{})
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		appErr := apperror.From(err, apperror.ErrInternal, "failed to fetch organizations")
+		c.logger.Error(ctx.Context(), "Failed to fetch organizations", err)
+		return response.Error(ctx, appErr)
 	}
 
-	return ctx.Status(http.StatusOK).JSON(organizations)
+	return response.SparseList(ctx, fields, organizations, fiber.Map{"count": len(organizations)})
 }
 
 // getEsfOrganizationsPaginated возвращает организации ЭСФ с пагинацией
 func (c *EsfOrganizationController) getEsfOrganizationsPaginated(ctx *fiber.Ctx) error {
-	c.logger.Info(ctx.Context(), "Вибірка організацій ЕСФ з пагінацією", logrus.Fields{})
+	c.logger.Info(ctx.Context(), "Вибірка організацій ЕСФ з пагінацією")
+
+	fields, appErr := requestedFields(ctx, organizationFields)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
 
 	// Витягуємо параметри пагінації та фільтрації
 	paginationParams := pagination.ExtractPaginationParams(ctx)
@@ -83,16 +100,13 @@ func (c *EsfOrganizationController) getEsfOrganizationsPaginated(ctx *fiber.Ctx)
 
 	organizations, totalCount, err := c.service.GetAllOrganizationsPaginated(ctx.Context(), paginationParams, filterParams)
 	if err != nil {
-		appErr, ok := err.(*apperror.AppError)
-		if !ok {
-			appErr = apperror.New(apperror.ErrInternal, "не удалось получить организации").WithError(err)
-		}
-		c.logger.Error(ctx.Context(), "Ошибка вибірки організацій", err, logrus.Fields{})
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		appErr := apperror.From(err, apperror.ErrInternal, "failed to fetch organizations")
+		c.logger.Error(ctx.Context(), "Ошибка вибірки організацій", err)
+		return response.Error(ctx, appErr)
 	}
 
-	// Формуємо відповідь з пагінацією
-	response := pagination.NewPaginatedResponse(organizations, paginationParams.Page, paginationParams.PageSize, totalCount)
+	// Формуємо метадані пагінації
+	meta := pagination.NewPaginationInfo(paginationParams.Page, paginationParams.PageSize, totalCount)
 
 	c.logger.Debug(ctx.Context(), "Організації успішно вибрані", logrus.Fields{
 		"count": len(organizations),
@@ -100,43 +114,58 @@ func (c *EsfOrganizationController) getEsfOrganizationsPaginated(ctx *fiber.Ctx)
 		"page":  paginationParams.Page,
 	})
 
-	return ctx.Status(http.StatusOK).JSON(response)
+	return response.SparseList(ctx, fields, organizations, meta)
+}
+
+// getEsfOrganizationsCursor возвращает организации ЭСФ с курсорной пагинацией
+func (c *EsfOrganizationController) getEsfOrganizationsCursor(ctx *fiber.Ctx) error {
+	c.logger.Info(ctx.Context(), "Fetching ESF organizations by cursor")
+
+	fields, appErr := requestedFields(ctx, organizationFields)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	cursorParams := pagination.ExtractCursorParams(ctx, "created_at", "updated_at", "name")
+	filterParams := pagination.ExtractOrganizationFilters(ctx)
+
+	organizations, info, err := c.service.GetAllOrganizationsCursor(ctx.Context(), cursorParams, filterParams)
+	if err != nil {
+		appErr := apperror.From(err, apperror.ErrInternal, "failed to fetch organizations")
+		c.logger.Error(ctx.Context(), "Failed to fetch organizations by cursor", err)
+		return response.Error(ctx, appErr)
+	}
+
+	c.logger.Debug(ctx.Context(), "Organizations fetched by cursor successfully", logrus.Fields{
+		"count":    len(organizations),
+		"has_next": info.HasNext,
+	})
+
+	return response.SparseList(ctx, fields, organizations, info)
 }
 
 // createEsfOrganization создает новую организацию ЭСФ
 func (c *EsfOrganizationController) createEsfOrganization(ctx *fiber.Ctx) error {
-	c.logger.Info(ctx.Context(), "Створення нової ЕСФ організації", logrus.Fields{})
+	c.logger.Info(ctx.Context(), "Створення нової ЕСФ організації")
 
 	var req models.EsfOrganizationModel
-	if err := ctx.BodyParser(&req); err != nil {
-		c.logger.Warn(ctx.Context(), "Невірне тіло запиту", logrus.Fields{"error": err.Error()})
-		appErr := apperror.ValidationError("invalid request body")
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
-	}
-
-	// Валидация обязательных полей
-	if req.Name == "" {
-		c.logger.Warn(ctx.Context(), "Назва організації обов'язкова", logrus.Fields{})
-		appErr := apperror.ValidationError("organization name is required")
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+	if appErr := validation.ParseBody(ctx, &req); appErr != nil {
+		c.logger.Warn(ctx.Context(), "Невірне тіло запиту", logrus.Fields{"error": appErr.Error()})
+		return response.Error(ctx, appErr)
 	}
 
 	id, dbName, err := c.service.CreateOrganization(ctx.Context(), &req)
 	if err != nil {
-		appErr, ok := err.(*apperror.AppError)
-		if !ok {
-			appErr = apperror.New(apperror.ErrInternal, "failed to create organization").WithError(err)
-		}
+		appErr := apperror.From(err, apperror.ErrInternal, "failed to create organization")
 		c.logger.Error(ctx.Context(), "Помилка створення організації", err, logrus.Fields{"name": req.Name})
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
+	c.invalidateResponses(ctx, id.String())
 	c.logger.Info(ctx.Context(), "Організацію успішно створено", logrus.Fields{"id": id.String(), "dbName": dbName})
-	return ctx.Status(http.StatusCreated).JSON(fiber.Map{
-		"success": true,
-		"id":      id,
-		"dbName":  dbName,
-		"message": "Organization created and database initialized",
+	return response.SuccessCreated(ctx, "Organization created and database initialized", fiber.Map{
+		"id":     id,
+		"dbName": dbName,
 	})
 }
 
@@ -149,20 +178,25 @@ func (c *EsfOrganizationController) getByEsfOrganization(ctx *fiber.Ctx) error {
 	if err != nil {
 		c.logger.Warn(ctx.Context(), "Невірний формат UUID", logrus.Fields{"id": idParam})
 		appErr := apperror.ValidationError("invalid UUID format")
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
+	}
+
+	fields, appErr := requestedFields(ctx, organizationFields)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
 	}
 
 	organization, err := c.service.GetOrganizationByID(ctx.Context(), id)
 	if err != nil {
-		appErr, ok := err.(*apperror.AppError)
-		if !ok {
-			appErr = apperror.New(apperror.ErrInternal, "failed to fetch organization").WithError(err)
-		}
+		appErr := apperror.From(err, apperror.ErrInternal, "failed to fetch organization")
 		c.logger.Error(ctx.Context(), "Помилка вибірки організації", err, logrus.Fields{"id": id.String()})
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
-	return ctx.Status(http.StatusOK).JSON(organization)
+	if organization != nil {
+		setETag(ctx, organization.Version)
+	}
+	return response.SparseOK(ctx, fields, organization)
 }
 
 func (c *EsfOrganizationController) updateEsfOrganization(ctx *fiber.Ctx) error {
@@ -173,38 +207,34 @@ func (c *EsfOrganizationController) updateEsfOrganization(ctx *fiber.Ctx) error
 	if err != nil {
 		c.logger.Warn(ctx.Context(), "Invalid UUID format", logrus.Fields{"id": idParam})
 		appErr := apperror.ValidationError("invalid UUID format")
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
 	var req models.EsfOrganizationModel
-	if err := ctx.BodyParser(&req); err != nil {
-		c.logger.Warn(ctx.Context(), "Invalid request body", logrus.Fields{"error": err.Error()})
-		appErr := apperror.ValidationError("invalid request body")
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+	if appErr := validation.ParseBody(ctx, &req); appErr != nil {
+		c.logger.Warn(ctx.Context(), "Invalid request body", logrus.Fields{"error": appErr.Error()})
+		return response.Error(ctx, appErr)
 	}
 
-	// Валидация обязательных полей
-	if req.Name == "" {
-		c.logger.Warn(ctx.Context(), "Organization name is required", logrus.Fields{})
-		appErr := apperror.ValidationError("organization name is required")
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+	version, appErr := expectedVersion(ctx, req.Version)
+	if appErr != nil {
+		c.logger.Warn(ctx.Context(), "Missing or invalid organization version", logrus.Fields{"id": idParam})
+		return response.Error(ctx, appErr)
 	}
 
+	req.ID = id.String()
 	req.DBName = idParam
+	req.Version = version
 	if err := c.service.UpdateOrganization(ctx.Context(), &req); err != nil {
-		appErr, ok := err.(*apperror.AppError)
-		if !ok {
-			appErr = apperror.New(apperror.ErrInternal, "failed to update organization").WithError(err)
-		}
+		appErr := apperror.From(err, apperror.ErrInternal, "failed to update organization")
 		c.logger.Error(ctx.Context(), "Failed to update organization", err, logrus.Fields{"id": id.String()})
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
-	c.logger.Info(ctx.Context(), "Organization updated successfully", logrus.Fields{"id": id.String()})
-	return ctx.Status(http.StatusOK).JSON(fiber.Map{
-		"success": true,
-		"message": "Organization updated successfully",
-	})
+	c.invalidateResponses(ctx, id.String())
+	c.logger.Info(ctx.Context(), "Organization updated successfully", logrus.Fields{"id": id.String(), "version": req.Version})
+	setETag(ctx, req.Version)
+	return response.SuccessOK(ctx, "Organization updated successfully", fiber.Map{"version": req.Version})
 }
 
 func (c *EsfOrganizationController) deleteEsfOrganization(ctx *fiber.Ctx) error {
@@ -215,21 +245,23 @@ func (c *EsfOrganizationController) deleteEsfOrganization(ctx *fiber.Ctx) error
 	if err != nil {
 		c.logger.Warn(ctx.Context(), "Invalid UUID format", logrus.Fields{"id": idParam})
 		appErr := apperror.ValidationError("invalid UUID format")
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
 	if err := c.service.DeleteOrganization(ctx.Context(), id); err != nil {
-		appErr, ok := err.(*apperror.AppError)
-		if !ok {
-			appErr = apperror.New(apperror.ErrInternal, "failed to delete organization").WithError(err)
-		}
+		appErr := apperror.From(err, apperror.ErrInternal, "failed to delete organization")
 		c.logger.Error(ctx.Context(), "Failed to delete organization", err, logrus.Fields{"id": id.String()})
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
+	c.invalidateResponses(ctx, id.String())
 	c.logger.Info(ctx.Context(), "Organization deleted successfully", logrus.Fields{"id": id.String()})
-	return ctx.Status(http.StatusOK).JSON(fiber.Map{
-		"success": true,
-		"message": "Organization deleted successfully",
-	})
+	return response.SuccessOK(ctx, "Organization deleted successfully", nil)
+}
+
+// invalidateResponses сбрасывает кешированные ответы организации и списков организаций
+func (c *EsfOrganizationController) invalidateResponses(ctx *fiber.Ctx, orgID string) {
+	if err := c.responses.Invalidate(ctx.Context(), cache.OrgTag(orgID), cache.TagOrganizations); err != nil {
+		c.logger.Warn(ctx.Context(), "Failed to invalidate organization responses", logrus.Fields{"id": orgID, "error": err.Error()})
+	}
 }
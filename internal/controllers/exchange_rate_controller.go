@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/cache"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+)
+
+type ExchangeRateController struct {
+	logger    *logger.Logger
+	service   services.ExchangeRateService
+	responses *middleware.ResponseCache
+}
+
+// NewExchangeRateController регистрирует маршруты курсов валют; ответы кешируются в responses
+// (nil — без кеша) до обновления курсов, сбрасывающего тег cache.TagReference
+func NewExchangeRateController(app *fiber.App, log *logrus.Logger, service services.ExchangeRateService, responses *middleware.ResponseCache) {
+	controller := &ExchangeRateController{
+		logger:    logger.New(log),
+		service:   service,
+		responses: responses,
+	}
+
+	controller.logger.Info(context.Background(), "ExchangeRateController инициализирован")
+	controller.registerRoutes(app)
+}
+
+func (c *ExchangeRateController) registerRoutes(app *fiber.App) {
+	rates := app.Group("/api/rates")
+	rates.Use(middleware.JWTMiddleware())
+	rates.Get("/", c.responses.Handler(middleware.StaticTags(cache.TagReference)), c.getRates)
+	rates.Post("/refresh", rbac.RequireAdminRole(), c.refreshRates)
+}
+
+// getRates курсы валют, действовавшие на дату ?date=2006-01-02, без даты — на сегодня
+func (c *ExchangeRateController) getRates(ctx *fiber.Ctx) error {
+	date := time.Now()
+	if raw := ctx.Query("date"); raw != "" {
+		var err error
+		if date, err = time.Parse("2006-01-02", raw); err != nil {
+			return response.Error(ctx, apperror.ValidationError("date must be in YYYY-MM-DD format"))
+		}
+	}
+
+	rates, err := c.service.Rates(ctx.Context(), date)
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка получения курсов валют", err, logrus.Fields{"date": date.Format("2006-01-02")})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to fetch exchange rates"))
+	}
+	return response.OK(ctx, rates)
+}
+
+// refreshRates немедленно загружает последние курсы
+func (c *ExchangeRateController) refreshRates(ctx *fiber.Ctx) error {
+	if err := c.service.Refresh(ctx.Context()); err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrExternalService, "failed to refresh exchange rates"))
+	}
+	return response.SuccessOK(ctx, "Exchange rates refreshed", nil)
+}
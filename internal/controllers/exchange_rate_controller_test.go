@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/testutil"
+)
+
+// stubExchangeRateService запоминает запрошенную дату
+type stubExchangeRateService struct {
+	services.ExchangeRateService
+	date time.Time
+}
+
+func (s *stubExchangeRateService) Rates(ctx context.Context, date time.Time) ([]entity.ExchangeRate, error) {
+	s.date = date
+	return []entity.ExchangeRate{{CurrencyCode: "USD", Date: date, Rate: 87.45}}, nil
+}
+
+func TestExchangeRateController(t *testing.T) {
+	h := testutil.NewHarness(t)
+	svc := &stubExchangeRateService{}
+	NewExchangeRateController(h.App, h.Logger, svc, nil)
+	user := testutil.NewUser()
+	token := testutil.WithToken(h.Token(user.ID.String(), user.Email))
+
+	resp := h.Do(http.MethodGet, "/api/rates?date=2026-09-15", nil, token)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+	var rates []entity.ExchangeRate
+	resp.DecodeData(&rates)
+	require.Len(t, rates, 1)
+	assert.Equal(t, "2026-09-15", svc.date.Format("2006-01-02"))
+
+	resp = h.Do(http.MethodGet, "/api/rates?date=15.09.2026", nil, token)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	resp = h.Do(http.MethodPost, "/api/rates/refresh", nil, token)
+	assert.NotEqual(t, fiber.StatusOK, resp.StatusCode, "refresh requires the admin role")
+}
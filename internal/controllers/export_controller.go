@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+	"github.com/rusgainew/tunduck-app/pkg/signature"
+)
+
+type ExportController struct {
+	logger  *logger.Logger
+	service services.ExportService
+}
+
+// NewExportController регистрирует маршруты фоновых выгрузок
+func NewExportController(app *fiber.App, log *logrus.Logger, service services.ExportService) {
+	controller := &ExportController{
+		logger:  logger.New(log),
+		service: service,
+	}
+
+	controller.logger.Info(context.Background(), "ExportController инициализирован")
+	controller.registerRoutes(app)
+}
+
+func (c *ExportController) registerRoutes(app *fiber.App) {
+	exports := app.Group("/api/exports")
+
+	// Скачивание по подписанной ссылке (без JWT)
+	exports.Get("/:id/download", c.downloadExport)
+
+	protected := exports.Group("")
+	protected.Use(middleware.JWTMiddleware())
+	protected.Post("/documents", c.createDocumentExport)
+	protected.Post("/audit-logs", rbac.RequireAdminRole(), c.createAuditExport)
+	protected.Get("/:id", c.getExport)
+}
+
+// createDocumentExport ставит выгрузку документов организации с фильтрами списка документов
+func (c *ExportController) createDocumentExport(ctx *fiber.Ctx) error {
+	orgID, err := resolveOrgID(ctx)
+	if err != nil {
+		c.logger.Warn(ctx.Context(), "Не удалось определить организацию", logrus.Fields{"error": err.Error()})
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "invalid organization ID"))
+	}
+
+	return c.createExport(ctx, services.ExportRequest{
+		Type:           services.ExportTypeDocuments,
+		OrganizationID: orgID,
+		DocumentFilter: pagination.ExtractDocumentFilters(ctx),
+	})
+}
+
+// createAuditExport ставит выгрузку журнала аудита с фильтрами GET /api/admin/audit
+func (c *ExportController) createAuditExport(ctx *fiber.Ctx) error {
+	filter, appErr := auditFilter(ctx)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	return c.createExport(ctx, services.ExportRequest{
+		Type:        services.ExportTypeAuditLogs,
+		AuditFilter: filter,
+	})
+}
+
+func (c *ExportController) createExport(ctx *fiber.Ctx, req services.ExportRequest) error {
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrUnauthorized, "unauthorized"))
+	}
+	req.RequestedBy = userID.String()
+	req.Format = ctx.Query("format", services.ExportFormatCSV)
+
+	status, err := c.service.CreateExport(ctx.Context(), req)
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка создания выгрузки", err, logrus.Fields{"type": req.Type})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to create export"))
+	}
+
+	// Выгрузка отслеживается операцией с тем же идентификатором
+	ctx.Set(fiber.HeaderLocation, services.OperationLocation(status.ID))
+	return response.Success(ctx, fiber.StatusAccepted, "Export queued", status)
+}
+
+// getExport возвращает прогресс выгрузки и подписанную ссылку на готовый файл.
+// Выгрузку видит только ее автор или администратор.
+func (c *ExportController) getExport(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "invalid export ID"))
+	}
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrUnauthorized, "unauthorized"))
+	}
+
+	status, err := c.service.GetExport(ctx.Context(), id)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to fetch export"))
+	}
+
+	if status.RequestedBy != userID.String() {
+		if uc := rbac.ExtractUserContext(ctx); uc == nil || !uc.IsAdmin() {
+			// Чужая выгрузка не раскрывается
+			return response.Error(ctx, apperror.NotFoundError("export"))
+		}
+	}
+	return response.OK(ctx, status)
+}
+
+// downloadExport отдает файл выгрузки по подписанной ссылке
+func (c *ExportController) downloadExport(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "invalid export ID"))
+	}
+
+	r, export, err := c.service.OpenDownload(ctx.Context(), id, ctx.Query(signature.QueryExpires), ctx.Query(signature.QuerySignature))
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to download export"))
+	}
+
+	c.logger.Info(ctx.Context(), "Скачивание выгрузки", logrus.Fields{"export_id": id.String()})
+
+	ctx.Set(fiber.HeaderContentType, services.ExportContentType(export.Format))
+	ctx.Set(fiber.HeaderContentDisposition, `attachment; filename="`+export.Type+"-"+id.String()+"."+export.Format+`"`)
+	ctx.Set(fiber.HeaderCacheControl, "private, no-store")
+	// Поток закрывается fasthttp после отправки ответа
+	return ctx.SendStream(r, int(export.Size))
+}
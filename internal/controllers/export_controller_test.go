@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/testutil"
+)
+
+// stubExportService хранит выгрузки в памяти
+type stubExportService struct {
+	services.ExportService
+	exports map[uuid.UUID]*services.ExportStatus
+	last    services.ExportRequest
+}
+
+func (s *stubExportService) CreateExport(ctx context.Context, req services.ExportRequest) (*services.ExportStatus, error) {
+	s.last = req
+	status := &services.ExportStatus{ExportJob: &entity.ExportJob{
+		ID:          uuid.New(),
+		Type:        req.Type,
+		Format:      req.Format,
+		Status:      entity.ExportStatusPending,
+		RequestedBy: req.RequestedBy,
+	}}
+	s.exports[status.ID] = status
+	return status, nil
+}
+
+func (s *stubExportService) GetExport(ctx context.Context, id uuid.UUID) (*services.ExportStatus, error) {
+	status, ok := s.exports[id]
+	if !ok {
+		return nil, apperror.NotFoundError("export")
+	}
+	return status, nil
+}
+
+func TestExportController_CreateAndGet(t *testing.T) {
+	h := testutil.NewHarness(t)
+	svc := &stubExportService{exports: map[uuid.UUID]*services.ExportStatus{}}
+	NewExportController(h.App, h.Logger, svc)
+
+	owner, stranger := testutil.NewUser(), testutil.NewUser()
+	ownerToken := testutil.WithToken(h.Token(owner.ID.String(), owner.Email))
+	orgID := testutil.WithHeader("X-Org-Id", uuid.NewString())
+
+	resp := h.Do(http.MethodPost, "/api/exports/documents", nil, orgID)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+	resp = h.Do(http.MethodPost, "/api/exports/documents?format=ndjson", nil, ownerToken, orgID)
+	require.Equal(t, fiber.StatusAccepted, resp.StatusCode, string(resp.Body))
+
+	var created entity.ExportJob
+	resp.DecodeData(&created)
+	assert.Equal(t, services.ExportTypeDocuments, created.Type)
+	assert.Equal(t, services.ExportFormatNDJSON, created.Format)
+	assert.Equal(t, owner.ID.String(), created.RequestedBy)
+	assert.Equal(t, "/api/operations/"+created.ID.String(), resp.Header.Get(fiber.HeaderLocation))
+
+	resp = h.Do(http.MethodGet, "/api/exports/"+created.ID.String(), nil, ownerToken)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	// Чужая выгрузка выглядит как несуществующая
+	resp = h.Do(http.MethodGet, "/api/exports/"+created.ID.String(), nil,
+		testutil.WithToken(h.Token(stranger.ID.String(), stranger.Email)))
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, string(apperror.ErrNotFound), resp.ErrorCode())
+
+	resp = h.Do(http.MethodGet, "/api/exports/not-a-uuid", nil, ownerToken)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/featureflag"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+	"github.com/rusgainew/tunduck-app/pkg/validation"
+)
+
+// flagEvaluation значение флага для организации
+type flagEvaluation struct {
+	Key            string    `json:"key"`
+	OrganizationID uuid.UUID `json:"organizationId"`
+	Enabled        bool      `json:"enabled"`
+}
+
+type FeatureFlagController struct {
+	logger *logger.Logger
+	flags  *featureflag.Flags
+}
+
+// NewFeatureFlagController регистрирует маршруты флагов функций: значения флагов для
+// организации и управление флагами (только администраторы)
+func NewFeatureFlagController(app *fiber.App, log *logrus.Logger, flags *featureflag.Flags) {
+	controller := &FeatureFlagController{
+		logger: logger.New(log),
+		flags:  flags,
+	}
+
+	controller.logger.Info(context.Background(), "FeatureFlagController инициализирован")
+	controller.registerRoutes(app)
+}
+
+func (c *FeatureFlagController) registerRoutes(app *fiber.App) {
+	app.Get("/api/feature-flags", middleware.JWTMiddleware(), c.getOrganizationFlags)
+
+	admin := app.Group("/api/admin/feature-flags", middleware.JWTMiddleware(), rbac.RequireAdminRole())
+	admin.Get("/", c.listFlags)
+	admin.Put("/:key", c.setFlag)
+	admin.Delete("/:key", c.deleteFlag)
+	admin.Get("/:key/evaluate", c.evaluateFlag)
+}
+
+// getOrganizationFlags возвращает значения всех флагов для организации из X-Org-Id (или orgId)
+func (c *FeatureFlagController) getOrganizationFlags(ctx *fiber.Ctx) error {
+	orgID, err := resolveOrgID(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "invalid organization ID"))
+	}
+	return response.OK(ctx, c.flags.EnabledFor(ctx.Context(), orgID))
+}
+
+// listFlags возвращает все флаги с правилами
+func (c *FeatureFlagController) listFlags(ctx *fiber.Ctx) error {
+	flags := c.flags.List()
+	if flags == nil {
+		flags = []featureflag.Flag{}
+	}
+	return response.OK(ctx, flags)
+}
+
+// setFlag создает или заменяет флаг; ключ берется из пути
+func (c *FeatureFlagController) setFlag(ctx *fiber.Ctx) error {
+	var flag featureflag.Flag
+	if appErr := validation.ParseBody(ctx, &flag); appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+	flag.Key = ctx.Params("key")
+	if err := flag.Validate(); err != nil {
+		return response.Error(ctx, apperror.ValidationError(err.Error()))
+	}
+
+	if err := c.flags.Set(ctx.Context(), flag); err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка сохранения флага функции", err, logrus.Fields{"flag": flag.Key})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to save feature flag"))
+	}
+	c.logger.Info(ctx.Context(), "Флаг функции сохранен", logrus.Fields{"flag": flag.Key, "enabled": flag.Enabled})
+
+	saved, _ := c.flags.Get(flag.Key)
+	return response.OK(ctx, saved)
+}
+
+// deleteFlag удаляет флаг, выключая его для всех организаций
+func (c *FeatureFlagController) deleteFlag(ctx *fiber.Ctx) error {
+	key := ctx.Params("key")
+	if err := c.flags.Delete(ctx.Context(), key); err != nil {
+		if errors.Is(err, featureflag.ErrNotFound) {
+			return response.Error(ctx, apperror.NotFoundError("feature flag"))
+		}
+		c.logger.Error(ctx.Context(), "Ошибка удаления флага функции", err, logrus.Fields{"flag": key})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to delete feature flag"))
+	}
+	c.logger.Info(ctx.Context(), "Флаг функции удален", logrus.Fields{"flag": key})
+	return response.SuccessOK(ctx, "Feature flag deleted", nil)
+}
+
+// evaluateFlag показывает значение флага для организации из query orgId, чтобы проверить правила
+func (c *FeatureFlagController) evaluateFlag(ctx *fiber.Ctx) error {
+	key := ctx.Params("key")
+	if _, ok := c.flags.Get(key); !ok {
+		return response.Error(ctx, apperror.NotFoundError("feature flag"))
+	}
+	orgID, err := resolveOrgID(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "invalid organization ID"))
+	}
+	return response.OK(ctx, flagEvaluation{Key: key, OrganizationID: orgID, Enabled: c.flags.Enabled(ctx.Context(), key, orgID)})
+}
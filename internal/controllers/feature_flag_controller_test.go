@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/featureflag"
+	"github.com/rusgainew/tunduck-app/pkg/testutil"
+)
+
+func TestFeatureFlagController_OrganizationFlags(t *testing.T) {
+	h := testutil.NewHarness(t)
+	flags := featureflag.NewFlags(nil, featureflag.Config{}, nil, h.Logger)
+	NewFeatureFlagController(h.App, h.Logger, flags)
+
+	pinned, other := uuid.New(), uuid.New()
+	require.NoError(t, flags.Set(t.Context(), featureflag.Flag{Key: "gateway.v2", Enabled: true, Organizations: []uuid.UUID{pinned}}))
+	require.NoError(t, flags.Set(t.Context(), featureflag.Flag{Key: "bulk-send", Enabled: false}))
+
+	user := testutil.NewUser()
+	token := testutil.WithToken(h.Token(user.ID.String(), user.Email))
+
+	resp := h.Do(http.MethodGet, "/api/feature-flags", nil, token, testutil.WithHeader("X-Org-Id", pinned.String()))
+	require.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+	var values map[string]bool
+	resp.DecodeData(&values)
+	assert.Equal(t, map[string]bool{"gateway.v2": true, "bulk-send": false}, values)
+
+	resp = h.Do(http.MethodGet, "/api/feature-flags?orgId="+other.String(), nil, token)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+	resp.DecodeData(&values)
+	assert.False(t, values["gateway.v2"])
+
+	resp = h.Do(http.MethodGet, "/api/feature-flags", nil, token)
+	assert.Equal(t, string(apperror.ErrInvalidRequest), resp.ErrorCode())
+}
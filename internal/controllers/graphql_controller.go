@@ -0,0 +1,235 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/99designs/gqlgen/graphql/executor"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"gorm.io/gorm"
+
+	"github.com/rusgainew/tunduck-app/internal/graph"
+	repositorypostgres "github.com/rusgainew/tunduck-app/internal/repository/repository_postgres"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/i18n"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+)
+
+// graphqlMaxDepth максимальная вложенность выборки
+const graphqlMaxDepth = 10
+
+// graphqlUserIDKey ключ контекста с ID пользователя из JWT
+type graphqlUserIDKey struct{}
+
+// graphqlLangKey ключ контекста с языком запроса для локализации ошибок
+type graphqlLangKey struct{}
+
+// graphqlRequest тело запроса GraphQL
+type graphqlRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// GraphQLController обслуживает /graphql поверх тех же сервисов, что и REST API. Схема исполняется
+// кодом, сгенерированным gqlgen (internal/graph); HTTP-транспорт реализован на Fiber.
+type GraphQLController struct {
+	logger *logger.Logger
+	exec   *executor.Executor
+}
+
+// NewGraphQLController строит исполняемую схему и регистрирует маршруты /graphql
+func NewGraphQLController(app *fiber.App, log *logrus.Logger, db *gorm.DB, orgService services.EsfOrganizationService, documentService services.EsfDocumentService) {
+	l := logger.New(log)
+
+	resolvers := &graphqlResolvers{
+		userRepo:        repositorypostgres.NewUserRepositoryPostgres(db, log),
+		orgService:      orgService,
+		documentService: documentService,
+	}
+	controller := &GraphQLController{
+		logger: l,
+		exec:   executor.New(graph.NewExecutableSchema(graph.Config{Resolvers: resolvers})),
+	}
+	controller.exec.Use(extension.Introspection{})
+	controller.exec.Use(graphqlDepthLimit{max: graphqlMaxDepth})
+	controller.exec.SetErrorPresenter(controller.presentError)
+	controller.exec.SetRecoverFunc(func(ctx context.Context, r interface{}) error {
+		return fmt.Errorf("panic in resolver: %v", r)
+	})
+
+	l.Info(context.Background(), "GraphQLController initialized")
+	controller.registerRoutes(app)
+}
+
+func (c *GraphQLController) registerRoutes(app *fiber.App) {
+	// Авторизация та же, что у защищенных REST-маршрутов
+	group := app.Group("/graphql")
+	group.Use(middleware.JWTMiddleware())
+
+	group.Post("/", c.execute)
+	group.Get("/", c.executeGet)
+	group.Get("/schema", c.getSchema)
+}
+
+// execute исполняет запрос из JSON-тела {query, operationName, variables}
+func (c *GraphQLController) execute(ctx *fiber.Ctx) error {
+	var req graphqlRequest
+	if err := ctx.BodyParser(&req); err != nil {
+		c.logger.Warn(ctx.Context(), "Failed to parse GraphQL request", logrus.Fields{"error": err.Error()})
+		return c.requestError(ctx, apperror.New(apperror.ErrInvalidRequest, "invalid request format"))
+	}
+	return c.respond(ctx, req, false)
+}
+
+// executeGet исполняет запрос из query-параметров; мутации через GET запрещены
+func (c *GraphQLController) executeGet(ctx *fiber.Ctx) error {
+	req := graphqlRequest{
+		Query:         ctx.Query("query"),
+		OperationName: ctx.Query("operationName"),
+	}
+	if raw := ctx.Query("variables"); raw != "" {
+		if err := ctx.App().Config().JSONDecoder([]byte(raw), &req.Variables); err != nil {
+			return c.requestError(ctx, apperror.New(apperror.ErrInvalidRequest, "invalid request format"))
+		}
+	}
+	return c.respond(ctx, req, true)
+}
+
+// getSchema отдает схему в нотации SDL
+func (c *GraphQLController) getSchema(ctx *fiber.Ctx) error {
+	ctx.Set(fiber.HeaderContentType, fiber.MIMETextPlainCharsetUTF8)
+	return ctx.SendString(graph.SDL)
+}
+
+// respond исполняет запрос; readOnly разрешает только операции query
+func (c *GraphQLController) respond(ctx *fiber.Ctx, req graphqlRequest, readOnly bool) error {
+	if req.Query == "" {
+		return c.requestError(ctx, apperror.ValidationError("GraphQL query is required"))
+	}
+
+	gqlCtx := context.WithValue(ctx.Context(), graphqlLangKey{}, i18n.FromCtx(ctx))
+	if userID, err := middleware.GetUserIDFromContext(ctx); err == nil {
+		gqlCtx = context.WithValue(gqlCtx, graphqlUserIDKey{}, userID)
+	}
+
+	params := &graphql.RawParams{
+		Query:         req.Query,
+		OperationName: req.OperationName,
+		Variables:     req.Variables,
+	}
+	gqlCtx = graphql.StartOperationTrace(gqlCtx)
+
+	// Ошибки разбора и проверки запроса - 400; ошибки полей возвращаются вместе с данными
+	opCtx, errs := c.exec.CreateOperationContext(gqlCtx, params)
+	if errs != nil {
+		c.logger.Debug(ctx.Context(), "GraphQL request rejected", logrus.Fields{"errors": len(errs)})
+		resp := c.exec.DispatchError(graphql.WithOperationContext(gqlCtx, opCtx), errs)
+		return ctx.Status(fiber.StatusBadRequest).JSON(resp)
+	}
+	if readOnly && opCtx.Operation.Operation != ast.Query {
+		return c.requestError(ctx, apperror.New(apperror.ErrInvalidRequest, "GET requests only allow query operations"))
+	}
+
+	responses, respCtx := c.exec.DispatchOperation(gqlCtx, opCtx)
+	return ctx.JSON(responses(respCtx))
+}
+
+func (c *GraphQLController) requestError(ctx *fiber.Ctx, appErr *apperror.AppError) error {
+	localized := appErr.ToLocalizedResponse(i18n.FromCtx(ctx))
+	return ctx.Status(fiber.StatusBadRequest).JSON(graphql.Response{Errors: gqlerror.List{{
+		Message:    localized.Message,
+		Extensions: map[string]interface{}{"code": localized.Code},
+	}}})
+}
+
+// presentError заменяет ошибку резолвера или входного значения локализованной ошибкой с кодом AppError;
+// ошибки разбора, проверки и приведения значений gqlgen возвращаются как есть
+func (c *GraphQLController) presentError(ctx context.Context, err error) *gqlerror.Error {
+	gqlErr := graphql.DefaultErrorPresenter(ctx, err)
+
+	var appErr *apperror.AppError
+	if !errors.As(err, &appErr) {
+		var queryErr *gqlerror.Error
+		if errors.As(err, &queryErr) {
+			return gqlErr
+		}
+		appErr = apperror.From(err, apperror.ErrInternal, "Internal server error")
+	}
+	if appErr.Code == apperror.ErrInternal {
+		c.logger.Error(ctx, "GraphQL resolver failed", err)
+	}
+
+	lang, _ := ctx.Value(graphqlLangKey{}).(i18n.Language)
+	localized := appErr.ToLocalizedResponse(lang)
+
+	gqlErr.Message = localized.Message
+	gqlErr.Extensions = map[string]interface{}{"code": localized.Code}
+	if len(localized.Fields) > 0 {
+		gqlErr.Extensions["fields"] = localized.Fields
+	}
+	return gqlErr
+}
+
+// graphqlDepthLimit отклоняет операции с вложенностью выборки больше max; поля интроспекции не считаются
+type graphqlDepthLimit struct {
+	max int
+}
+
+func (graphqlDepthLimit) ExtensionName() string {
+	return "DepthLimit"
+}
+
+func (graphqlDepthLimit) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (l graphqlDepthLimit) MutateOperationContext(ctx context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	if depth := selectionDepth(opCtx.Operation.SelectionSet); depth > l.max {
+		err := gqlerror.Errorf("operation has depth %d, which exceeds the limit of %d", depth, l.max)
+		errcode.Set(err, errcode.ValidationFailed)
+		return err
+	}
+	return nil
+}
+
+func selectionDepth(set ast.SelectionSet) int {
+	depth := 0
+	for _, sel := range set {
+		d := 0
+		switch sel := sel.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(sel.Name, "__") {
+				continue
+			}
+			d = 1 + selectionDepth(sel.SelectionSet)
+		case *ast.InlineFragment:
+			d = selectionDepth(sel.SelectionSet)
+		case *ast.FragmentSpread:
+			if sel.Definition != nil {
+				d = selectionDepth(sel.Definition.SelectionSet)
+			}
+		}
+		if d > depth {
+			depth = d
+		}
+	}
+	return depth
+}
+
+// graphqlUserID возвращает ID пользователя, сохраненный контроллером в контексте
+func graphqlUserID(ctx context.Context) (uuid.UUID, bool) {
+	userID, ok := ctx.Value(graphqlUserIDKey{}).(uuid.UUID)
+	return userID, ok
+}
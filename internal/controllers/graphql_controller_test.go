@@ -0,0 +1,260 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/vektah/gqlparser/v2"
+
+	"github.com/rusgainew/tunduck-app/internal/graph"
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/dates"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+	"github.com/rusgainew/tunduck-app/pkg/testutil"
+)
+
+// stubDocumentService хранит документы одной организации в памяти
+type stubDocumentService struct {
+	services.EsfDocumentService
+	orgID   uuid.UUID
+	docs    map[uuid.UUID]*models.EsfCreateDocumentRequest
+	deleted []uuid.UUID
+}
+
+func (s *stubDocumentService) GetAllDocumentsPaginated(ctx context.Context, orgID uuid.UUID, params pagination.PaginationParams, filters pagination.DocumentFilterParams) ([]models.EsfCreateDocumentRequest, int64, error) {
+	if orgID != s.orgID {
+		return []models.EsfCreateDocumentRequest{}, 0, nil
+	}
+	var docs []models.EsfCreateDocumentRequest
+	for _, doc := range s.docs {
+		docs = append(docs, *doc)
+	}
+	return docs, int64(len(docs)), nil
+}
+
+func (s *stubDocumentService) GetDocumentByID(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*models.EsfCreateDocumentRequest, error) {
+	if orgID != s.orgID {
+		return nil, nil
+	}
+	return s.docs[id], nil
+}
+
+func (s *stubDocumentService) CreateDocument(ctx context.Context, orgID uuid.UUID, doc *models.EsfCreateDocumentRequest) (*models.EsfCreateDocumentResponse, error) {
+	id := uuid.New()
+	s.docs[id] = doc
+	return &models.EsfCreateDocumentResponse{ResponseId: "resp-1", DocumentUuid: id.String()}, nil
+}
+
+func (s *stubDocumentService) DeleteDocument(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error {
+	if _, ok := s.docs[id]; !ok {
+		return apperror.New(apperror.ErrDocumentNotFound, "document not found")
+	}
+	delete(s.docs, id)
+	s.deleted = append(s.deleted, id)
+	return nil
+}
+
+// stubOrganizationService возвращает одну организацию
+type stubOrganizationService struct {
+	services.EsfOrganizationService
+	org models.EsfOrganizationModel
+}
+
+func (s *stubOrganizationService) GetOrganizationByID(ctx context.Context, id uuid.UUID) (*models.EsfOrganizationModel, error) {
+	if id.String() != s.org.ID {
+		return nil, apperror.New(apperror.ErrOrgNotFound, "organization not found")
+	}
+	org := s.org
+	return &org, nil
+}
+
+type graphqlTestResponse struct {
+	Data   map[string]json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Path       []interface{}          `json:"path"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+func decodeGraphQL(t *testing.T, resp *testutil.Response) graphqlTestResponse {
+	t.Helper()
+	var out graphqlTestResponse
+	require.NoError(t, json.Unmarshal(resp.Body, &out), string(resp.Body))
+	return out
+}
+
+func newGraphQLHarness(t *testing.T) (*testutil.Harness, *stubDocumentService, uuid.UUID, testutil.RequestOption) {
+	t.Helper()
+	h := testutil.NewHarness(t)
+
+	orgID := uuid.New()
+	docID := uuid.New()
+	docs := &stubDocumentService{orgID: orgID, docs: map[uuid.UUID]*models.EsfCreateDocumentRequest{
+		docID: testutil.NewDocumentRequest(),
+	}}
+	orgs := &stubOrganizationService{org: models.EsfOrganizationModel{
+		ID:     orgID.String(),
+		Name:   "Tunduk",
+		Token:  "secret-token",
+		DBName: "org_db",
+	}}
+	NewGraphQLController(h.App, h.Logger, nil, orgs, docs)
+
+	user := testutil.NewUser()
+	return h, docs, orgID, testutil.WithToken(h.Token(user.ID.String(), user.Email))
+}
+
+func TestGraphQLController_Query(t *testing.T) {
+	h, _, orgID, token := newGraphQLHarness(t)
+	query := map[string]interface{}{
+		"query": `query Org($id: ID!) {
+			organization(id: $id) { name documents(pageSize: 5) { items { currencyCode catalogEntries { quantity } } pageInfo { totalItems } } }
+			missing: organization(id: "00000000-0000-0000-0000-000000000000") { name }
+		}`,
+		"variables": map[string]interface{}{"id": orgID.String()},
+	}
+
+	resp := h.Do(http.MethodPost, "/graphql", query)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+	resp = h.Do(http.MethodPost, "/graphql", query, token)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+	out := decodeGraphQL(t, resp)
+	assert.Empty(t, out.Errors)
+	assert.JSONEq(t, `{"name":"Tunduk","documents":{"items":[{"currencyCode":"KGS","catalogEntries":[{"quantity":10}]}],"pageInfo":{"totalItems":1}}}`,
+		string(out.Data["organization"]))
+	assert.JSONEq(t, `null`, string(out.Data["missing"]))
+
+	// Токен и имя базы организации не входят в схему
+	resp = h.Do(http.MethodPost, "/graphql", map[string]interface{}{"query": `{ organization(id: "` + orgID.String() + `") { token } }`}, token)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	assert.Contains(t, decodeGraphQL(t, resp).Errors[0].Message, `Cannot query field "token"`)
+
+	// Интроспекция доступна клиентам GraphQL
+	resp = h.Do(http.MethodPost, "/graphql", map[string]interface{}{"query": `{ __type(name: "Document") { name } }`}, token)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+	assert.JSONEq(t, `{"name":"Document"}`, string(decodeGraphQL(t, resp).Data["__type"]))
+}
+
+func TestGraphQLDepthLimit(t *testing.T) {
+	schema := graph.NewExecutableSchema(graph.Config{}).Schema()
+	doc, errs := gqlparser.LoadQuery(schema, `
+		query { organizations { items { ...Docs } } __schema { types { fields { type { name } } } } }
+		fragment Docs on Organization { documents { items { catalogEntries { price } } } }`)
+	require.Nil(t, errs)
+	op := &graphql.OperationContext{Operation: doc.Operations[0]}
+
+	// organizations > items > documents > items > catalogEntries > price; интроспекция не считается
+	assert.Nil(t, graphqlDepthLimit{max: 6}.MutateOperationContext(context.Background(), op))
+	err := graphqlDepthLimit{max: 5}.MutateOperationContext(context.Background(), op)
+	require.NotNil(t, err)
+	assert.Contains(t, err.Message, "depth 6")
+}
+
+func TestGraphQLController_DocumentMutations(t *testing.T) {
+	h, docs, orgID, token := newGraphQLHarness(t)
+
+	// Ошибки валидации возвращаются с кодом и полями AppError
+	resp := h.Do(http.MethodPost, "/graphql", map[string]interface{}{
+		"query":     `mutation($org: ID!) { createDocument(organizationId: $org, input: {currencyCode: "KGS"}) { documentUuid } }`,
+		"variables": map[string]interface{}{"org": orgID.String()},
+	}, token)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+	out := decodeGraphQL(t, resp)
+	require.Len(t, out.Errors, 1)
+	assert.Equal(t, string(apperror.ErrFieldValidation), out.Errors[0].Extensions["code"])
+	assert.NotEmpty(t, out.Errors[0].Extensions["fields"])
+	assert.Equal(t, []interface{}{"createDocument"}, out.Errors[0].Path)
+	assert.Len(t, docs.docs, 1)
+
+	doc := testutil.NewDocumentRequest()
+	input := map[string]interface{}{}
+	raw, _ := json.Marshal(doc)
+	require.NoError(t, json.Unmarshal(raw, &input))
+	delete(input, "id")
+	delete(input, "version")
+	for _, entry := range input["catalogEntries"].([]interface{}) {
+		delete(entry.(map[string]interface{}), "id")
+	}
+
+	resp = h.Do(http.MethodPost, "/graphql", map[string]interface{}{
+		"query":     `mutation($org: ID!, $input: DocumentInput!) { createDocument(organizationId: $org, input: $input) { responseId documentUuid } }`,
+		"variables": map[string]interface{}{"org": orgID.String(), "input": input},
+	}, token)
+	out = decodeGraphQL(t, resp)
+	require.Empty(t, out.Errors, string(resp.Body))
+
+	var created models.EsfCreateDocumentResponse
+	require.NoError(t, json.Unmarshal(out.Data["createDocument"], &created))
+	createdID := uuid.MustParse(created.DocumentUuid)
+	require.Contains(t, docs.docs, createdID)
+	assert.Equal(t, doc.DeliveryDate, docs.docs[createdID].DeliveryDate)
+
+	// Мутации через GET запрещены
+	mutation := `mutation { deleteDocument(organizationId: "` + orgID.String() + `", id: "` + createdID.String() + `") }`
+	resp = h.Do(http.MethodGet, "/graphql?query="+url.QueryEscape(mutation), nil, token)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	assert.Empty(t, docs.deleted)
+
+	resp = h.Do(http.MethodPost, "/graphql", map[string]interface{}{"query": mutation}, token)
+	out = decodeGraphQL(t, resp)
+	require.Empty(t, out.Errors, string(resp.Body))
+	assert.JSONEq(t, `true`, string(out.Data["deleteDocument"]))
+	assert.Equal(t, []uuid.UUID{createdID}, docs.deleted)
+}
+
+func TestGraphQLController_Schema(t *testing.T) {
+	h, _, _, token := newGraphQLHarness(t)
+
+	resp := h.Do(http.MethodGet, "/graphql/schema", nil, token)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	sdl := string(resp.Body)
+	assert.Contains(t, sdl, "scalar DateTime")
+	assert.Contains(t, sdl, "scalar Date\n")
+	assert.Contains(t, sdl, "documents(organizationId: ID!, page: Int = 1, pageSize: Int = 10): DocumentPage!")
+	assert.Contains(t, sdl, "input DocumentInput {")
+	assert.NotContains(t, sdl, "dbName")
+}
+
+func TestGraphQLController_Dates(t *testing.T) {
+	h, docs, orgID, token := newGraphQLHarness(t)
+	var doc *models.EsfCreateDocumentRequest
+	for _, d := range docs.docs {
+		doc = d
+	}
+
+	// Date отдается календарной датой, пустая дата договора — null
+	query := `query($org: ID!) { documents(organizationId: $org) { items { deliveryDate contractStartDate } } }`
+	vars := map[string]interface{}{"org": orgID.String()}
+	resp := h.Do(http.MethodPost, "/graphql", map[string]interface{}{"query": query, "variables": vars}, token)
+	out := decodeGraphQL(t, resp)
+	require.Empty(t, out.Errors, string(resp.Body))
+	assert.JSONEq(t, `{"items":[{"deliveryDate":"`+doc.DeliveryDate.String()+`","contractStartDate":"`+doc.ContractStartDate.String()+`"}]}`,
+		string(out.Data["documents"]))
+
+	doc.ContractStartDate = dates.Date{}
+	resp = h.Do(http.MethodPost, "/graphql", map[string]interface{}{"query": query, "variables": vars}, token)
+	assert.JSONEq(t, `{"items":[{"deliveryDate":"`+doc.DeliveryDate.String()+`","contractStartDate":null}]}`,
+		string(decodeGraphQL(t, resp).Data["documents"]))
+
+	// Время без часового пояса не дает календарный день и отклоняется
+	resp = h.Do(http.MethodPost, "/graphql", map[string]interface{}{
+		"query":     `mutation($org: ID!) { createDocument(organizationId: $org, input: {deliveryDate: "2024-01-15T10:00:00"}) { documentUuid } }`,
+		"variables": vars,
+	}, token)
+	out = decodeGraphQL(t, resp)
+	require.NotEmpty(t, out.Errors, string(resp.Body))
+	assert.Equal(t, string(apperror.ErrInvalidRequest), out.Errors[0].Extensions["code"])
+	assert.Len(t, docs.docs, 1)
+}
@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/internal/graph"
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+)
+
+// graphqlResolvers корневой резолвер схемы internal/graph поверх тех же сервисов, что и REST-контроллеры.
+// Методы полей сгенерированы gqlgen в graphql_schema.resolvers.go; вспомогательный код — здесь.
+type graphqlResolvers struct {
+	userRepo        repository.UserRepository
+	orgService      services.EsfOrganizationService
+	documentService services.EsfDocumentService
+}
+
+// paginationParams приводит аргументы страницы к тем же границам, что и REST
+func paginationParams(page, pageSize *int) pagination.PaginationParams {
+	params := pagination.PaginationParams{Sort: "created_at", Order: "desc"}
+	if page != nil {
+		params.Page = *page
+	}
+	if pageSize != nil {
+		params.PageSize = *pageSize
+	}
+	if params.Page < 1 {
+		params.Page = 1
+	}
+	params.PageSize = params.GetLimit()
+	return params
+}
+
+func parseGraphQLID(id string, message string) (uuid.UUID, error) {
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return uuid.Nil, apperror.New(apperror.ErrInvalidRequest, message)
+	}
+	return parsed, nil
+}
+
+// documentIDs разбирает ID организации и документа
+func documentIDs(organizationID, id string) (uuid.UUID, uuid.UUID, error) {
+	orgID, err := parseGraphQLID(organizationID, "invalid organization ID")
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	docID, err := parseGraphQLID(id, "invalid document ID format")
+	if err != nil {
+		return uuid.Nil, uuid.Nil, err
+	}
+	return orgID, docID, nil
+}
+
+func (r *graphqlResolvers) findUser(ctx context.Context, id uuid.UUID) (*entity.User, error) {
+	user, err := r.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperror.From(err, apperror.ErrInternal, "failed to fetch user")
+	}
+	return user, nil
+}
+
+func (r *graphqlResolvers) listDocuments(ctx context.Context, orgID uuid.UUID, page, pageSize *int) (*graph.DocumentPage, error) {
+	params := paginationParams(page, pageSize)
+	docs, total, err := r.documentService.GetAllDocumentsPaginated(ctx, orgID, params, pagination.DocumentFilterParams{})
+	if err != nil {
+		return nil, apperror.From(err, apperror.ErrInternal, "failed to fetch documents")
+	}
+	result := &graph.DocumentPage{Items: make([]*models.EsfCreateDocumentRequest, len(docs))}
+	for i := range docs {
+		result.Items[i] = &docs[i]
+	}
+	info := pagination.NewPaginationInfo(params.Page, params.PageSize, total)
+	result.PageInfo = &info
+	return result, nil
+}
+
+func (r *graphqlResolvers) findDocument(ctx context.Context, orgID, docID uuid.UUID) (*models.EsfCreateDocumentRequest, error) {
+	doc, err := r.documentService.GetDocumentByID(ctx, orgID, docID)
+	if err != nil {
+		return nil, apperror.From(err, apperror.ErrInternal, "failed to fetch document")
+	}
+	return doc, nil
+}
@@ -0,0 +1,197 @@
+package controllers
+
+// This file will be automatically regenerated based on the schema, any resolver
+// implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rusgainew/tunduck-app/internal/graph"
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/dates"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+	"github.com/rusgainew/tunduck-app/pkg/validation"
+)
+
+func (r *documentGraphqlResolvers) ContractStartDate(ctx context.Context, obj *models.EsfCreateDocumentRequest) (*dates.Date, error) {
+	if obj.ContractStartDate.IsZero() {
+		return nil, nil
+	}
+	return &obj.ContractStartDate, nil
+}
+
+func (r *mutationGraphqlResolvers) CreateDocument(ctx context.Context, organizationID string, input models.EsfCreateDocumentRequest) (*models.EsfCreateDocumentResponse, error) {
+	orgID, err := parseGraphQLID(organizationID, "invalid organization ID")
+	if err != nil {
+		return nil, err
+	}
+	if appErr := validation.Struct(&input); appErr != nil {
+		return nil, appErr
+	}
+
+	created, err := r.documentService.CreateDocument(ctx, orgID, &input)
+	if err != nil {
+		return nil, apperror.From(err, apperror.ErrInternal, "failed to create document")
+	}
+	return created, nil
+}
+
+func (r *mutationGraphqlResolvers) UpdateDocument(ctx context.Context, organizationID string, id string, version int, input models.EsfCreateDocumentRequest) (*models.EsfCreateDocumentRequest, error) {
+	orgID, docID, err := documentIDs(organizationID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	req := models.EsfEditDocumentRequest{ID: docID, EsfCreateDocumentRequest: input}
+	req.Version = int64(version)
+	if req.Version <= 0 {
+		return nil, apperror.ValidationError("record version must be a positive number")
+	}
+	if appErr := validation.Struct(&req); appErr != nil {
+		return nil, appErr
+	}
+
+	if appErr := ensureDocumentEditable(ctx, r.documentService, orgID, docID); appErr != nil {
+		return nil, appErr
+	}
+	if err := r.documentService.UpdateDocument(ctx, orgID, &req); err != nil {
+		return nil, apperror.From(err, apperror.ErrInternal, "failed to update document")
+	}
+	return r.findDocument(ctx, orgID, docID)
+}
+
+func (r *mutationGraphqlResolvers) DeleteDocument(ctx context.Context, organizationID string, id string) (bool, error) {
+	orgID, docID, err := documentIDs(organizationID, id)
+	if err != nil {
+		return false, err
+	}
+	if appErr := ensureDocumentEditable(ctx, r.documentService, orgID, docID); appErr != nil {
+		return false, appErr
+	}
+	if err := r.documentService.DeleteDocument(ctx, orgID, docID); err != nil {
+		return false, apperror.From(err, apperror.ErrInternal, "failed to delete document")
+	}
+	return true, nil
+}
+
+func (r *organizationGraphqlResolvers) Documents(ctx context.Context, obj *models.EsfOrganizationModel, page *int, pageSize *int) (*graph.DocumentPage, error) {
+	orgID, err := parseGraphQLID(obj.ID, "invalid organization ID")
+	if err != nil {
+		return nil, err
+	}
+	return r.listDocuments(ctx, orgID, page, pageSize)
+}
+
+func (r *queryGraphqlResolvers) Me(ctx context.Context) (*entity.User, error) {
+	userID, ok := graphqlUserID(ctx)
+	if !ok {
+		return nil, apperror.UnauthorizedError("user is not authenticated")
+	}
+	return r.findUser(ctx, userID)
+}
+
+func (r *queryGraphqlResolvers) User(ctx context.Context, id string) (*entity.User, error) {
+	userID, err := parseGraphQLID(id, "invalid UUID format")
+	if err != nil {
+		return nil, err
+	}
+	return r.findUser(ctx, userID)
+}
+
+func (r *queryGraphqlResolvers) Users(ctx context.Context, limit *int, cursor *string) (*graph.UserPage, error) {
+	params := pagination.CursorParams{Sort: "created_at", Order: "desc"}
+	if limit != nil {
+		params.Limit = *limit
+	}
+	if cursor != nil {
+		params.Cursor = *cursor
+	}
+	if params.Limit < 1 || params.Limit > 100 {
+		params.Limit = 10
+	}
+
+	users, info, err := r.userRepo.GetAllCursor(ctx, params, pagination.UserFilterParams{})
+	if err != nil {
+		return nil, apperror.From(err, apperror.ErrInternal, "failed to fetch users")
+	}
+	return &graph.UserPage{
+		Items:      users,
+		NextCursor: info.NextCursor,
+		PrevCursor: info.PrevCursor,
+		HasNext:    info.HasNext,
+		HasPrev:    info.HasPrev,
+	}, nil
+}
+
+func (r *queryGraphqlResolvers) Organization(ctx context.Context, id string) (*models.EsfOrganizationModel, error) {
+	orgID, err := parseGraphQLID(id, "invalid organization ID")
+	if err != nil {
+		return nil, err
+	}
+	org, err := r.orgService.GetOrganizationByID(ctx, orgID)
+	if err != nil {
+		if errors.Is(err, apperror.New(apperror.ErrOrgNotFound, "")) {
+			return nil, nil
+		}
+		return nil, apperror.From(err, apperror.ErrInternal, "failed to fetch organization")
+	}
+	return org, nil
+}
+
+func (r *queryGraphqlResolvers) Organizations(ctx context.Context, page *int, pageSize *int) (*graph.OrganizationPage, error) {
+	params := paginationParams(page, pageSize)
+	orgs, total, err := r.orgService.GetAllOrganizationsPaginated(ctx, params, pagination.OrganizationFilterParams{})
+	if err != nil {
+		return nil, apperror.From(err, apperror.ErrInternal, "failed to fetch organizations")
+	}
+	result := &graph.OrganizationPage{Items: make([]*models.EsfOrganizationModel, len(orgs))}
+	for i := range orgs {
+		result.Items[i] = &orgs[i]
+	}
+	info := pagination.NewPaginationInfo(params.Page, params.PageSize, total)
+	result.PageInfo = &info
+	return result, nil
+}
+
+func (r *queryGraphqlResolvers) Document(ctx context.Context, organizationID string, id string) (*models.EsfCreateDocumentRequest, error) {
+	orgID, docID, err := documentIDs(organizationID, id)
+	if err != nil {
+		return nil, err
+	}
+	return r.findDocument(ctx, orgID, docID)
+}
+
+func (r *queryGraphqlResolvers) Documents(ctx context.Context, organizationID string, page *int, pageSize *int) (*graph.DocumentPage, error) {
+	orgID, err := parseGraphQLID(organizationID, "invalid organization ID")
+	if err != nil {
+		return nil, err
+	}
+	return r.listDocuments(ctx, orgID, page, pageSize)
+}
+
+func (r *userGraphqlResolvers) Role(ctx context.Context, obj *entity.User) (string, error) {
+	return string(obj.Role), nil
+}
+
+func (r *graphqlResolvers) Document() graph.DocumentResolver { return &documentGraphqlResolvers{r} }
+
+func (r *graphqlResolvers) Mutation() graph.MutationResolver { return &mutationGraphqlResolvers{r} }
+
+func (r *graphqlResolvers) Organization() graph.OrganizationResolver {
+	return &organizationGraphqlResolvers{r}
+}
+
+func (r *graphqlResolvers) Query() graph.QueryResolver { return &queryGraphqlResolvers{r} }
+
+func (r *graphqlResolvers) User() graph.UserResolver { return &userGraphqlResolvers{r} }
+
+type documentGraphqlResolvers struct{ *graphqlResolvers }
+type mutationGraphqlResolvers struct{ *graphqlResolvers }
+type organizationGraphqlResolvers struct{ *graphqlResolvers }
+type queryGraphqlResolvers struct{ *graphqlResolvers }
+type userGraphqlResolvers struct{ *graphqlResolvers }
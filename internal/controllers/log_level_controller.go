@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+	"github.com/rusgainew/tunduck-app/pkg/validation"
+)
+
+// logLevelRequest новый уровень подсистемы
+type logLevelRequest struct {
+	Level string `json:"level" validate:"required,oneof=trace debug info warn warning error fatal panic"`
+}
+
+// logLevelsResponse уровни логирования инстанса, обработавшего запрос
+type logLevelsResponse struct {
+	Levels []logger.ModuleLevel `json:"levels"`
+	// SampledOut отладочных записей, отброшенных выборкой с запуска инстанса
+	SampledOut uint64 `json:"sampledOut"`
+}
+
+type LogLevelController struct {
+	logger *logger.Logger
+	levels *logger.LevelSync
+}
+
+// NewLogLevelController регистрирует маршруты администратора для уровней логирования подсистем
+// (gorm, gateway, http и т.п.): изменение сразу действует на всех инстансах и хранится в Redis
+// сутки, чтобы его получили и инстансы, запущенные позже
+func NewLogLevelController(app *fiber.App, log *logrus.Logger, levels *logger.LevelSync) {
+	controller := &LogLevelController{
+		logger: logger.New(log),
+		levels: levels,
+	}
+
+	controller.logger.Info(context.Background(), "LogLevelController инициализирован")
+	controller.registerRoutes(app)
+}
+
+func (c *LogLevelController) registerRoutes(app *fiber.App) {
+	admin := app.Group("/api/admin/log-levels", middleware.JWTMiddleware(), rbac.RequireAdminRole())
+	admin.Get("/", c.listLevels)
+	admin.Put("/:module", c.setLevel)
+	admin.Delete("/:module", c.resetLevel)
+}
+
+// listLevels возвращает общий уровень и уровни подсистем
+func (c *LogLevelController) listLevels(ctx *fiber.Ctx) error {
+	return response.OK(ctx, c.snapshot())
+}
+
+// setLevel задает уровень подсистемы; модуль root меняет общий уровень
+func (c *LogLevelController) setLevel(ctx *fiber.Ctx) error {
+	var req logLevelRequest
+	if appErr := validation.ParseBody(ctx, &req); appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+	level, err := logrus.ParseLevel(req.Level)
+	if err != nil {
+		return response.Error(ctx, apperror.ValidationError("invalid log level"))
+	}
+
+	module := ctx.Params("module")
+	if err := c.levels.Set(ctx.Context(), module, level); err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка сохранения уровня логирования", err, logrus.Fields{"module": module})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to save log level"))
+	}
+	c.logger.Info(ctx.Context(), "Уровень логирования изменен", logrus.Fields{"module": module, "level": level.String()})
+	return response.OK(ctx, c.snapshot())
+}
+
+// resetLevel возвращает подсистеме общий уровень
+func (c *LogLevelController) resetLevel(ctx *fiber.Ctx) error {
+	module := ctx.Params("module")
+	if module == logger.RootModule {
+		return response.Error(ctx, apperror.ValidationError("root log level cannot be reset"))
+	}
+
+	if err := c.levels.Reset(ctx.Context(), module); err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка сохранения уровня логирования", err, logrus.Fields{"module": module})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to save log level"))
+	}
+	c.logger.Info(ctx.Context(), "Уровень логирования сброшен", logrus.Fields{"module": module})
+	return response.OK(ctx, c.snapshot())
+}
+
+func (c *LogLevelController) snapshot() logLevelsResponse {
+	levels := c.levels.Levels()
+	return logLevelsResponse{Levels: levels.Snapshot(), SampledOut: levels.Dropped()}
+}
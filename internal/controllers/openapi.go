@@ -0,0 +1,807 @@
+package controllers
+
+import (
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/esfgateway"
+	"github.com/rusgainew/tunduck-app/pkg/featureflag"
+	"github.com/rusgainew/tunduck-app/pkg/health"
+	"github.com/rusgainew/tunduck-app/pkg/jobs"
+	"github.com/rusgainew/tunduck-app/pkg/openapi"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+	"github.com/rusgainew/tunduck-app/pkg/report"
+)
+
+// Структуры ниже описывают параметры строки запроса для спецификации OpenAPI;
+// обработчики читают те же параметры через ctx.Query.
+
+// orgQuery организация запроса, если не передан заголовок X-Org-Id
+type orgQuery struct {
+	OrgID uuid.UUID `query:"orgId"`
+}
+
+// viewQuery сохраненное представление списка; параметры запроса дополняют его фильтры
+type viewQuery struct {
+	View uuid.UUID `query:"view"`
+}
+
+// fieldsQuery поля ответа (sparse fieldset); в списках с пагинацией передается в PaginationParams
+type fieldsQuery struct {
+	Fields []string `query:"fields"`
+}
+
+// documentQuery документ или список документов без пагинации
+type documentQuery struct {
+	orgQuery
+	fieldsQuery
+}
+
+type documentListQuery struct {
+	orgQuery
+	viewQuery
+	pagination.PaginationParams
+	pagination.DocumentFilterParams
+}
+
+type documentCursorQuery struct {
+	orgQuery
+	viewQuery
+	fieldsQuery
+	pagination.CursorParams
+	pagination.DocumentFilterParams
+}
+
+type documentSearchQuery struct {
+	orgQuery
+	Q string `query:"q" validate:"required"`
+	pagination.PaginationParams
+}
+
+type trashListQuery struct {
+	orgQuery
+	pagination.PaginationParams
+}
+
+type contractListQuery struct {
+	orgQuery
+	ContractorTin string `query:"contractorTin"`
+}
+
+type ratesQuery struct {
+	Date string `query:"date"`
+}
+
+type priceQuery struct {
+	orgQuery
+	ContractorTin string `query:"contractorTin" validate:"required"`
+	Currency      string `query:"currency" validate:"required"`
+	Date          string `query:"date"`
+}
+
+type organizationListQuery struct {
+	pagination.PaginationParams
+	pagination.OrganizationFilterParams
+}
+
+type organizationCursorQuery struct {
+	fieldsQuery
+	pagination.CursorParams
+	pagination.OrganizationFilterParams
+}
+
+type userListQuery struct {
+	Page  int `query:"page" validate:"min=1"`
+	Limit int `query:"limit" validate:"min=1,max=100"`
+}
+
+type userCursorQuery struct {
+	pagination.CursorParams
+	pagination.UserFilterParams
+}
+
+type auditQuery struct {
+	ActorID        string `query:"actor_id"`
+	OrganizationID string `query:"organization_id"`
+	EntityType     string `query:"entity_type"`
+	EntityID       string `query:"entity_id"`
+	Action         string `query:"action"`
+	From           string `query:"from"`
+	To             string `query:"to"`
+}
+
+type auditListQuery struct {
+	auditQuery
+	pagination.PaginationParams
+}
+
+type exportFormatQuery struct {
+	Format string `query:"format" validate:"oneof=csv ndjson"`
+}
+
+type documentExportQuery struct {
+	orgQuery
+	exportFormatQuery
+	pagination.DocumentFilterParams
+}
+
+type auditExportQuery struct {
+	auditQuery
+	exportFormatQuery
+}
+
+type usagePeriodQuery struct {
+	From        string `query:"from"`
+	To          string `query:"to"`
+	Granularity string `query:"granularity" validate:"oneof=hour day month"`
+}
+
+type usageQuery struct {
+	usagePeriodQuery
+	Format string `query:"format" validate:"oneof=json csv ndjson"`
+}
+
+type usageExportQuery struct {
+	usagePeriodQuery
+	exportFormatQuery
+}
+
+type deadLetterQuery struct {
+	Source string `query:"source"`
+	Type   string `query:"type"`
+	Status string `query:"status"`
+	pagination.PaginationParams
+}
+
+type emailDeliveryQuery struct {
+	Status string `query:"status"`
+	pagination.PaginationParams
+}
+
+type deadJobsQuery struct {
+	Limit int `query:"limit" validate:"min=1,max=1000"`
+}
+
+type scheduledJobsQuery struct {
+	Limit  int `query:"limit" validate:"min=1,max=1000"`
+	Offset int `query:"offset" validate:"min=0"`
+}
+
+type migrationsQuery struct {
+	DryRun bool `query:"dry_run"`
+}
+
+type tenantHealthQuery struct {
+	Flagged bool `query:"flagged"`
+}
+
+type graphqlQuery struct {
+	Query         string `query:"query" validate:"required"`
+	OperationName string `query:"operationName"`
+	Variables     string `query:"variables"`
+}
+
+type downloadQuery struct {
+	Expires   int64  `query:"expires" validate:"required"`
+	Signature string `query:"signature" validate:"required"`
+}
+
+// wsQuery браузер не может передать заголовок Authorization при открытии WebSocket
+type wsQuery struct {
+	Token string `query:"token"`
+}
+
+// createdOrganization ответ на создание организации
+type createdOrganization struct {
+	ID     uuid.UUID `json:"id"`
+	DBName string    `json:"dbName"`
+}
+
+// versionResult ответ на изменение записи с оптимистичной блокировкой
+type versionResult struct {
+	Version int64 `json:"version"`
+}
+
+// documentUpdateResult ответ на изменение документа с предупреждениями по договору
+type documentUpdateResult struct {
+	Version  int64                    `json:"version"`
+	Warnings []models.DocumentWarning `json:"warnings,omitempty"`
+}
+
+// trashResult ответ на восстановление или окончательное удаление
+type trashResult struct {
+	ID string `json:"id"`
+}
+
+// DescribeRoutes описывает маршруты контроллеров для спецификации OpenAPI.
+// Маршрут без описания тоже попадает в спецификацию, но без типов запроса и ответа;
+// go run ./cmd/api openapi -check проверяет, что все маршруты описаны.
+func DescribeRoutes(reg *openapi.Registry) {
+	describeAuthRoutes(reg)
+	describeDocumentRoutes(reg)
+	describeOrganizationRoutes(reg)
+	describeUserRoutes(reg)
+	describeTrashRoutes(reg)
+	describeExportRoutes(reg)
+	describeReportRoutes(reg)
+	describeAttachmentRoutes(reg)
+	describeBankStatementRoutes(reg)
+	describeContractRoutes(reg)
+	describePriceListRoutes(reg)
+	describeReferenceRoutes(reg)
+	describeDelegationRoutes(reg)
+	describeSavedViewRoutes(reg)
+	describeUsageRoutes(reg)
+	describeFeatureFlagRoutes(reg)
+	describeEsfGatewayRoutes(reg)
+	describeLogLevelRoutes(reg)
+	describeTenantHealthRoutes(reg)
+	describeAdminRoutes(reg)
+
+	reg.Add(fiber.MethodGet, "/api/operations/:id", openapi.Operation{
+		Tags: []string{"Operations"}, Summary: "Состояние длительной операции", Secured: true,
+		Response: services.OperationStatus{},
+	})
+	reg.Add(fiber.MethodPost, "/api/callbacks/esf", openapi.Operation{
+		Tags: []string{"Callbacks"}, Summary: "Обратный вызов шлюза ЭСФ",
+		Description: "Запрос подписывается ключом API (X-API-Key, X-Signature); повторная доставка подтверждается с duplicate=true",
+		Request:     services.CallbackEvent{}, Response: callbackAck{},
+	})
+	reg.Add(fiber.MethodPost, "/graphql", openapi.Operation{
+		Tags: []string{"GraphQL"}, Summary: "Выполнить GraphQL запрос", Secured: true,
+		Request: graphqlRequest{}, Response: graphql.Response{}, Raw: true,
+	})
+	reg.Add(fiber.MethodGet, "/graphql", openapi.Operation{
+		Tags: []string{"GraphQL"}, Summary: "Выполнить GraphQL запрос на чтение", Secured: true,
+		Query: graphqlQuery{}, Response: graphql.Response{}, Raw: true,
+	})
+	reg.Add(fiber.MethodGet, "/graphql/schema", openapi.Operation{
+		Tags: []string{"GraphQL"}, Summary: "Схема GraphQL в SDL", Secured: true, ContentType: fiber.MIMETextPlainCharsetUTF8,
+	})
+	reg.Add(fiber.MethodGet, "/ws", openapi.Operation{
+		Tags: []string{"Realtime"}, Summary: "WebSocket уведомлений в реальном времени",
+		Query: wsQuery{}, Status: fiber.StatusSwitchingProtocols,
+	})
+}
+
+func describeAuthRoutes(reg *openapi.Registry) {
+	tags := []string{"Authentication"}
+	reg.Add(fiber.MethodPost, "/api/auth/register", openapi.Operation{
+		Tags: tags, Summary: "Регистрация нового пользователя",
+		Request: models.RegisterRequest{}, Response: models.AuthResponse{}, Status: fiber.StatusCreated,
+	})
+	reg.Add(fiber.MethodPost, "/api/auth/login", openapi.Operation{
+		Tags: tags, Summary: "Вход пользователя",
+		Request: models.LoginRequest{}, Response: models.AuthResponse{},
+	})
+	reg.Add(fiber.MethodGet, "/api/auth/me", openapi.Operation{
+		Tags: tags, Summary: "Текущий пользователь", Secured: true, Response: models.UserInfo{},
+	})
+	reg.Add(fiber.MethodPost, "/api/auth/logout", openapi.Operation{
+		Tags: tags, Summary: "Выход пользователя", Secured: true,
+	})
+}
+
+func describeDocumentRoutes(reg *openapi.Registry) {
+	tags := []string{"Documents"}
+	reg.Add(fiber.MethodGet, "/api/esf-documents", openapi.Operation{
+		Tags: tags, Summary: "Все ЭСФ документы организации",
+		Query: documentQuery{}, Response: []documentResource{},
+	})
+	reg.Add(fiber.MethodGet, "/api/esf-documents/paginated", openapi.Operation{
+		Tags: tags, Summary: "ЭСФ документы с пагинацией и фильтрами",
+		Query: documentListQuery{}, Response: []documentResource{}, Meta: pagination.PaginationInfo{},
+	})
+	reg.Add(fiber.MethodGet, "/api/esf-documents/cursor", openapi.Operation{
+		Tags: tags, Summary: "ЭСФ документы с курсорной пагинацией",
+		Query: documentCursorQuery{}, Response: []documentResource{}, Meta: pagination.CursorInfo{},
+	})
+	reg.Add(fiber.MethodGet, "/api/esf-documents/search", openapi.Operation{
+		Tags: tags, Summary: "Полнотекстовый поиск ЭСФ документов",
+		Query: documentSearchQuery{}, Response: []documentResource{}, Meta: pagination.PaginationInfo{},
+	})
+	reg.Add(fiber.MethodGet, "/api/esf-documents/:id", openapi.Operation{
+		Tags: tags, Summary: "ЭСФ документ по ID", Description: "Версия документа возвращается в заголовке ETag",
+		Query: documentQuery{}, Response: documentResource{},
+	})
+	reg.Add(fiber.MethodPost, "/api/esf-documents", openapi.Operation{
+		Tags: tags, Summary: "Создать ЭСФ документ", Secured: true,
+		Description: "При указании contractId номер и дата договора заполняются из справочника договоров; " +
+			"истекший договор, превышение лимита или другая валюта возвращаются в warnings. " +
+			"Позиции без цены заполняются из прайс-листа контрагента; цена ниже минимальной требует разрешения override:price (403). " +
+			"Суммы позиций со скидкой или наценкой и итоги документа пересчитываются. " +
+			"При исчерпании месячного лимита документов тарифного плана — 402 QUOTA_EXCEEDED с подсказкой в error.data",
+		Query: orgQuery{}, Request: models.EsfCreateDocumentRequest{}, Response: models.EsfCreateDocumentResponse{},
+		Status: fiber.StatusCreated,
+	})
+	reg.Add(fiber.MethodPut, "/api/esf-documents/:id", openapi.Operation{
+		Tags: tags, Summary: "Обновить ЭСФ документ", Secured: true,
+		Description: "Ожидаемая версия передается в поле version или заголовке If-Match. Документ, отправленный в ЭСФ, изменить нельзя (409)",
+		Query:       orgQuery{}, Request: models.EsfEditDocumentRequest{}, Response: documentUpdateResult{},
+	})
+	reg.Add(fiber.MethodDelete, "/api/esf-documents/:id", openapi.Operation{
+		Tags: tags, Summary: "Удалить ЭСФ документ в корзину", Secured: true, Query: orgQuery{},
+	})
+	reg.Add(fiber.MethodGet, "/api/esf-documents/:id/pdf", openapi.Operation{
+		Tags: tags, Summary: "Печатная форма документа в PDF", Secured: true,
+		Description: "Наименования покупателя и товаров выбираются на языке документа (language), затем foreignName",
+		Query:       orgQuery{}, ContentType: report.ContentTypePDF,
+	})
+	reg.Add(fiber.MethodPost, "/api/esf-documents/:id/send", openapi.Operation{
+		Tags: tags, Summary: "Отправить документ в ЭСФ", Secured: true,
+		Description: "Требует права подписи send:document по роли или по действующему делегированию (403). " +
+			"Документ подписывается токеном организации и получает статус sent; отправитель и делегирующий записываются в журнал аудита",
+		Query: orgQuery{}, Response: models.EsfCreateDocumentResponse{},
+	})
+	reg.Add(fiber.MethodPost, "/api/esf-documents/:id/schedule-send", openapi.Operation{
+		Tags: tags, Summary: "Запланировать отправку документа в ЭСФ", Secured: true,
+		Description: "Требует права подписи send:document (403); в момент отправки право проверяется снова. " +
+			"Повторный запрос переносит время отправки. sendAt в прошлом — 422, отправленный документ — 409",
+		Query: orgQuery{}, Request: scheduleSendRequest{}, Response: services.ScheduledSend{},
+	})
+	reg.Add(fiber.MethodDelete, "/api/esf-documents/:id/schedule-send", openapi.Operation{
+		Tags: tags, Summary: "Отменить запланированную отправку документа", Secured: true,
+		Description: "404, если отправка не запланирована или уже выполняется",
+		Query:       orgQuery{}, Status: fiber.StatusNoContent,
+	})
+	reg.Add(fiber.MethodGet, "/api/esf-documents/:id/payments", openapi.Operation{
+		Tags: tags, Summary: "Оплаты документа и остаток к оплате", Secured: true,
+		Query: orgQuery{}, Response: services.DocumentPayments{},
+	})
+	reg.Add(fiber.MethodPost, "/api/esf-documents/:id/payments", openapi.Operation{
+		Tags: tags, Summary: "Внести оплату по документу", Secured: true,
+		Description: "Оплаченная сумма документа увеличивается на сумму оплаты, версия документа меняется",
+		Query:       orgQuery{}, Request: models.PaymentRequest{}, Response: services.DocumentPayments{},
+		Status: fiber.StatusCreated,
+	})
+	reg.Add(fiber.MethodDelete, "/api/esf-documents/:id/payments/:paymentId", openapi.Operation{
+		Tags: tags, Summary: "Удалить ошибочную оплату", Secured: true,
+		Query: orgQuery{}, Response: services.DocumentPayments{},
+	})
+}
+
+func describeOrganizationRoutes(reg *openapi.Registry) {
+	tags := []string{"Organizations"}
+	reg.Add(fiber.MethodGet, "/api/esf-organizations", openapi.Operation{
+		Tags: tags, Summary: "Все организации", Query: fieldsQuery{}, Response: []models.EsfOrganizationModel{},
+	})
+	reg.Add(fiber.MethodGet, "/api/esf-organizations/paginated", openapi.Operation{
+		Tags: tags, Summary: "Организации с пагинацией и фильтрами",
+		Query: organizationListQuery{}, Response: []models.EsfOrganizationModel{}, Meta: pagination.PaginationInfo{},
+	})
+	reg.Add(fiber.MethodGet, "/api/esf-organizations/cursor", openapi.Operation{
+		Tags: tags, Summary: "Организации с курсорной пагинацией",
+		Query: organizationCursorQuery{}, Response: []models.EsfOrganizationModel{}, Meta: pagination.CursorInfo{},
+	})
+	reg.Add(fiber.MethodGet, "/api/esf-organizations/:id", openapi.Operation{
+		Tags: tags, Summary: "Организация по ID", Query: fieldsQuery{}, Response: models.EsfOrganizationModel{},
+	})
+	reg.Add(fiber.MethodPost, "/api/esf-organizations", openapi.Operation{
+		Tags: tags, Summary: "Создать организацию и ее базу данных", Secured: true,
+		Request: models.EsfOrganizationModel{}, Response: createdOrganization{}, Status: fiber.StatusCreated,
+	})
+	reg.Add(fiber.MethodPut, "/api/esf-organizations/:id", openapi.Operation{
+		Tags: tags, Summary: "Обновить организацию", Secured: true,
+		Request: models.EsfOrganizationModel{}, Response: versionResult{},
+	})
+	reg.Add(fiber.MethodDelete, "/api/esf-organizations/:id", openapi.Operation{
+		Tags: tags, Summary: "Удалить организацию в корзину", Secured: true,
+	})
+}
+
+func describeUserRoutes(reg *openapi.Registry) {
+	tags := []string{"Users"}
+	reg.Add(fiber.MethodGet, "/api/users", openapi.Operation{
+		Tags: tags, Summary: "Пользователи с пагинацией", Query: userListQuery{}, Response: []entity.User{},
+		Meta: pagination.PaginationInfo{},
+	})
+	reg.Add(fiber.MethodGet, "/api/users/cursor", openapi.Operation{
+		Tags: tags, Summary: "Пользователи с курсорной пагинацией",
+		Query: userCursorQuery{}, Response: []entity.User{}, Meta: pagination.CursorInfo{},
+	})
+	reg.Add(fiber.MethodGet, "/api/users/:id", openapi.Operation{
+		Tags: tags, Summary: "Пользователь по ID", Response: entity.User{},
+	})
+}
+
+func describeTrashRoutes(reg *openapi.Registry) {
+	tags := []string{"Documents"}
+	reg.Add(fiber.MethodPost, "/api/esf-documents/bulk-delete", openapi.Operation{
+		Tags: tags, Summary: "Удалить ЭСФ документы в корзину", Secured: true,
+		Description: "Документы, отправленные в ЭСФ, и не найденные документы возвращаются в skipped. " +
+			"Больше TRASH_ASYNC_THRESHOLD документов обрабатываются фоновой операцией: 202, прогресс — по заголовку Location",
+		Query: orgQuery{}, Request: bulkDocumentsRequest{}, Response: services.BulkDocumentsResult{},
+	})
+	reg.Add(fiber.MethodGet, "/api/esf-documents/trash", openapi.Operation{
+		Tags: tags, Summary: "Корзина документов организации", Secured: true,
+		Description: "Документы, удаленные не раньше TRASH_RETENTION; purgeAt — время окончательного удаления",
+		Query:       trashListQuery{}, Response: []services.TrashedDocument{}, Meta: pagination.PaginationInfo{},
+	})
+	reg.Add(fiber.MethodPost, "/api/esf-documents/trash/restore", openapi.Operation{
+		Tags: tags, Summary: "Восстановить ЭСФ документы из корзины", Secured: true,
+		Description: "Документы с истекшим сроком хранения возвращаются в skipped. Крупные пакеты — 202, как при удалении",
+		Query:       orgQuery{}, Request: bulkDocumentsRequest{}, Response: services.BulkDocumentsResult{},
+	})
+}
+
+func describeExportRoutes(reg *openapi.Registry) {
+	tags := []string{"Exports"}
+	reg.Add(fiber.MethodPost, "/api/exports/documents", openapi.Operation{
+		Tags: tags, Summary: "Поставить выгрузку документов организации", Secured: true,
+		Description: "Прогресс отслеживается по заголовку Location (/api/operations/{id})",
+		Query:       documentExportQuery{}, Response: services.ExportStatus{}, Status: fiber.StatusAccepted,
+	})
+	reg.Add(fiber.MethodPost, "/api/exports/audit-logs", openapi.Operation{
+		Tags: tags, Summary: "Поставить выгрузку журнала аудита (администратор)", Secured: true,
+		Query: auditExportQuery{}, Response: services.ExportStatus{}, Status: fiber.StatusAccepted,
+	})
+	reg.Add(fiber.MethodGet, "/api/exports/:id", openapi.Operation{
+		Tags: tags, Summary: "Состояние выгрузки и ссылка на файл", Secured: true, Response: services.ExportStatus{},
+	})
+	reg.Add(fiber.MethodGet, "/api/exports/:id/download", openapi.Operation{
+		Tags: tags, Summary: "Скачать файл выгрузки по подписанной ссылке",
+		Query: downloadQuery{}, ContentType: fiber.MIMEOctetStream,
+	})
+}
+
+func describeAttachmentRoutes(reg *openapi.Registry) {
+	tags := []string{"Attachments"}
+	reg.Add(fiber.MethodPost, "/api/attachments", openapi.Operation{
+		Tags: tags, Summary: "Загрузить вложение организации", Secured: true,
+		Description: "multipart/form-data: file — файл. Возвращает ключ и подписанную ссылку " +
+			"GET /api/files/{key}?expires=...&signature=..., которая открывается без JWT до expiresAt. " +
+			"При исчерпании места тарифного плана — 402 QUOTA_EXCEEDED с подсказкой в error.data",
+		Query: orgQuery{}, Response: services.AttachmentLink{}, Status: fiber.StatusCreated,
+	})
+	reg.Add(fiber.MethodPost, "/api/attachments/links", openapi.Operation{
+		Tags: tags, Summary: "Новая подписанная ссылка на вложение", Secured: true,
+		Description: "expiresIn — срок действия (Go duration, например 24h), не больше ATTACHMENT_LINK_MAX_TTL",
+		Query:       orgQuery{}, Request: models.AttachmentLinkRequest{}, Response: services.AttachmentLink{},
+	})
+}
+
+func describeReportRoutes(reg *openapi.Registry) {
+	tags := []string{"Reports"}
+	reg.Add(fiber.MethodGet, "/api/reports", openapi.Operation{
+		Tags: tags, Summary: "Доступные отчеты", Secured: true, Response: []services.ReportDefinition{},
+	})
+	reg.Add(fiber.MethodPost, "/api/reports", openapi.Operation{
+		Tags: tags, Summary: "Заказать отчет организации за период", Secured: true,
+		Description: "Отчет строится фоновой выгрузкой: файл доступен через /api/exports/{id}, прогресс — по заголовку Location",
+		Query:       orgQuery{}, Request: reportRequest{}, Response: services.ExportStatus{}, Status: fiber.StatusAccepted,
+	})
+}
+
+func describeUsageRoutes(reg *openapi.Registry) {
+	tags := []string{"Usage"}
+	reg.Add(fiber.MethodGet, "/api/organizations/:id/usage", openapi.Operation{
+		Tags: tags, Summary: "Потребление организации за период", Secured: true,
+		Description: "Суммы метрик documents_sent, storage_bytes и api_calls по часам, дням или месяцам (UTC). " +
+			"from и to — даты YYYY-MM-DD включительно, по умолчанию текущий месяц; format=csv|ndjson отдает выгрузку файлом",
+		Query: usageQuery{}, Response: services.UsageReport{},
+	})
+	reg.Add(fiber.MethodGet, "/api/admin/usage/export", openapi.Operation{
+		Tags: tags, Summary: "Выгрузить потребление всех организаций для выставления счетов", Secured: true,
+		Description: "Только для администраторов. По умолчанию CSV помесячно: organization_id, organization_name, period_start, metric, quantity",
+		Query:       usageExportQuery{}, ContentType: "text/csv",
+	})
+}
+
+func describeFeatureFlagRoutes(reg *openapi.Registry) {
+	tags := []string{"Feature flags"}
+	reg.Add(fiber.MethodGet, "/api/feature-flags", openapi.Operation{
+		Tags: tags, Summary: "Значения флагов функций для организации", Secured: true,
+		Query: orgQuery{}, Response: map[string]bool{},
+	})
+	reg.Add(fiber.MethodGet, "/api/admin/feature-flags", openapi.Operation{
+		Tags: tags, Summary: "Флаги функций с правилами", Secured: true, Response: []featureflag.Flag{},
+	})
+	reg.Add(fiber.MethodPut, "/api/admin/feature-flags/:key", openapi.Operation{
+		Tags: tags, Summary: "Создать или заменить флаг функции", Secured: true,
+		Description: "Флаг включен для организаций из organizations, а также для организаций, подходящих под все rules " +
+			"(plan, organization_id: in/not_in; created_at: before/after) и попавших в percentage (по умолчанию 0). Изменение применяется на всех инстансах",
+		Request: featureflag.Flag{}, Response: featureflag.Flag{},
+	})
+	reg.Add(fiber.MethodDelete, "/api/admin/feature-flags/:key", openapi.Operation{
+		Tags: tags, Summary: "Удалить флаг функции", Secured: true,
+	})
+	reg.Add(fiber.MethodGet, "/api/admin/feature-flags/:key/evaluate", openapi.Operation{
+		Tags: tags, Summary: "Значение флага для организации", Secured: true,
+		Query: orgQuery{}, Response: flagEvaluation{},
+	})
+}
+
+func describeEsfGatewayRoutes(reg *openapi.Registry) {
+	tags := []string{"ESF gateway"}
+	reg.Add(fiber.MethodGet, "/api/admin/esf-gateway", openapi.Operation{
+		Tags: tags, Summary: "Адреса шлюза ЭСФ и активный адрес", Secured: true,
+		Description: "Доступно, если шлюз настроен адресами (ESF_GATEWAY_URL или ESF_GATEWAY_ENDPOINTS)",
+		Response:    esfgateway.FailoverStatus{},
+	})
+	reg.Add(fiber.MethodPut, "/api/admin/esf-gateway/endpoint", openapi.Operation{
+		Tags: tags, Summary: "Закрепить адрес шлюза ЭСФ", Secured: true,
+		Description: "Адрес применяется на всех инстансах, автоматическое переключение отключается. Пустой endpoint возвращает автоматическое переключение",
+		Request:     gatewayEndpointRequest{}, Response: esfgateway.FailoverStatus{},
+	})
+}
+
+func describeLogLevelRoutes(reg *openapi.Registry) {
+	tags := []string{"Logging"}
+	reg.Add(fiber.MethodGet, "/api/admin/log-levels", openapi.Operation{
+		Tags: tags, Summary: "Уровни логирования подсистем", Secured: true,
+		Description: "sampledOut — отладочные записи, отброшенные выборкой (LOG_SAMPLING_INITIAL, LOG_SAMPLING_THEREAFTER)",
+		Response:    logLevelsResponse{},
+	})
+	reg.Add(fiber.MethodPut, "/api/admin/log-levels/:module", openapi.Operation{
+		Tags: tags, Summary: "Задать уровень логирования подсистемы", Secured: true,
+		Description: "Подсистемы: http, gorm, gateway; root меняет общий уровень и уровни подсистем без своего. " +
+			"Изменение применяется на всех инстансах через Redis и хранится сутки после последнего изменения",
+		Request: logLevelRequest{}, Response: logLevelsResponse{},
+	})
+	reg.Add(fiber.MethodDelete, "/api/admin/log-levels/:module", openapi.Operation{
+		Tags: tags, Summary: "Вернуть подсистеме общий уровень логирования", Secured: true,
+		Response: logLevelsResponse{},
+	})
+}
+
+func describeTenantHealthRoutes(reg *openapi.Registry) {
+	reg.Add(fiber.MethodGet, "/api/admin/tenant-health", openapi.Operation{
+		Tags: []string{"Admin"}, Summary: "Проверка БД организаций", Secured: true,
+		Description: "Последние результаты выборочной проверки на обработавшем запрос инстансе: доступность, размер и отставание реплики. " +
+			"flagged — БД недоступна TENANT_HEALTH_FAILURE_THRESHOLD проверок подряд",
+		Query: tenantHealthQuery{}, Response: []health.TenantStatus{},
+	})
+}
+
+func describeBankStatementRoutes(reg *openapi.Registry) {
+	tags := []string{"Bank statements"}
+	reg.Add(fiber.MethodGet, "/api/bank-statements", openapi.Operation{
+		Tags: tags, Summary: "Загруженные выписки организации", Secured: true,
+		Query: orgQuery{}, Response: []entity.BankStatement{},
+	})
+	reg.Add(fiber.MethodPost, "/api/bank-statements", openapi.Operation{
+		Tags: tags, Summary: "Загрузить банковскую выписку", Secured: true,
+		Description: "multipart/form-data: file — выписка MT940 или CSV, format — mt940 или csv (по умолчанию определяется по файлу). " +
+			"Поступления сопоставляются с неоплаченными документами по ИНН, сумме и ссылке платежа",
+		Query: orgQuery{}, Response: entity.BankStatement{}, Status: fiber.StatusCreated,
+	})
+	reg.Add(fiber.MethodGet, "/api/bank-statements/:id", openapi.Operation{
+		Tags: tags, Summary: "Выписка со строками", Secured: true,
+		Query: orgQuery{}, Response: entity.BankStatement{},
+	})
+	reg.Add(fiber.MethodGet, "/api/bank-statements/:id/unmatched", openapi.Operation{
+		Tags: tags, Summary: "Несопоставленные строки с предложенными документами", Secured: true,
+		Query: orgQuery{}, Response: []services.UnmatchedStatementLine{},
+	})
+	reg.Add(fiber.MethodPost, "/api/bank-statements/:id/lines/:lineId/match", openapi.Operation{
+		Tags: tags, Summary: "Сопоставить строку выписки с документом", Secured: true,
+		Description: "Вносит оплату по документу на сумму строки",
+		Query:       orgQuery{}, Request: statementMatchRequest{}, Response: entity.BankStatementLine{},
+	})
+}
+
+func describeContractRoutes(reg *openapi.Registry) {
+	tags := []string{"Contracts"}
+	reg.Add(fiber.MethodGet, "/api/contracts", openapi.Operation{
+		Tags: tags, Summary: "Договоры с контрагентами", Secured: true,
+		Query: contractListQuery{}, Response: []entity.Contract{},
+	})
+	reg.Add(fiber.MethodPost, "/api/contracts", openapi.Operation{
+		Tags: tags, Summary: "Добавить договор", Secured: true,
+		Description: "Номер договора уникален в пределах контрагента (409)",
+		Query:       orgQuery{}, Request: models.ContractRequest{}, Response: entity.Contract{}, Status: fiber.StatusCreated,
+	})
+	reg.Add(fiber.MethodGet, "/api/contracts/:id", openapi.Operation{
+		Tags: tags, Summary: "Договор с суммой документов и остатком лимита", Secured: true,
+		Query: orgQuery{}, Response: services.ContractDetails{},
+	})
+	reg.Add(fiber.MethodPut, "/api/contracts/:id", openapi.Operation{
+		Tags: tags, Summary: "Изменить договор", Secured: true,
+		Description: "Номер и дата договора в сохраненных документах не меняются",
+		Query:       orgQuery{}, Request: models.ContractRequest{}, Response: entity.Contract{},
+	})
+	reg.Add(fiber.MethodDelete, "/api/contracts/:id", openapi.Operation{
+		Tags: tags, Summary: "Удалить договор", Secured: true,
+		Description: "Договор, на который ссылаются документы, удалить нельзя (409)",
+		Query:       orgQuery{},
+	})
+}
+
+func describePriceListRoutes(reg *openapi.Registry) {
+	tags := []string{"Price lists"}
+	reg.Add(fiber.MethodGet, "/api/price-lists", openapi.Operation{
+		Tags: tags, Summary: "Прайс-листы организации", Secured: true,
+		Query: orgQuery{}, Response: []entity.PriceList{},
+	})
+	reg.Add(fiber.MethodPost, "/api/price-lists", openapi.Operation{
+		Tags: tags, Summary: "Добавить прайс-лист", Secured: true,
+		Description: "Прайс-лист относится либо к контрагенту (contractorTin), либо к группе покупателей (customerGroup)",
+		Query:       orgQuery{}, Request: models.PriceListRequest{}, Response: entity.PriceList{}, Status: fiber.StatusCreated,
+	})
+	reg.Add(fiber.MethodGet, "/api/price-lists/prices", openapi.Operation{
+		Tags: tags, Summary: "Цены для заполнения позиций документа", Secured: true,
+		Description: "Цена из прайс-листа контрагента важнее цены его группы; date в формате YYYY-MM-DD, по умолчанию сегодня",
+		Query:       priceQuery{}, Response: []services.ResolvedPrice{},
+	})
+	reg.Add(fiber.MethodGet, "/api/price-lists/:id", openapi.Operation{
+		Tags: tags, Summary: "Прайс-лист с ценами", Secured: true,
+		Query: orgQuery{}, Response: entity.PriceList{},
+	})
+	reg.Add(fiber.MethodPut, "/api/price-lists/:id", openapi.Operation{
+		Tags: tags, Summary: "Заменить прайс-лист вместе с ценами", Secured: true,
+		Query: orgQuery{}, Request: models.PriceListRequest{}, Response: entity.PriceList{},
+	})
+	reg.Add(fiber.MethodDelete, "/api/price-lists/:id", openapi.Operation{
+		Tags: tags, Summary: "Удалить прайс-лист", Secured: true, Query: orgQuery{},
+	})
+	reg.Add(fiber.MethodGet, "/api/customer-groups", openapi.Operation{
+		Tags: tags, Summary: "Группы покупателей", Secured: true,
+		Query: orgQuery{}, Response: []services.CustomerGroup{},
+	})
+	reg.Add(fiber.MethodPut, "/api/customer-groups/:name", openapi.Operation{
+		Tags: tags, Summary: "Заменить состав группы покупателей", Secured: true,
+		Description: "Пустой список контрагентов удаляет группу",
+		Query:       orgQuery{}, Request: models.CustomerGroupRequest{}, Response: services.CustomerGroup{},
+	})
+}
+
+func describeReferenceRoutes(reg *openapi.Registry) {
+	tags := []string{"Reference data"}
+	reg.Add(fiber.MethodGet, "/api/reference", openapi.Operation{
+		Tags: tags, Summary: "Коды, встречающиеся в документах", Secured: true,
+		Response: services.ReferenceData{},
+	})
+	reg.Add(fiber.MethodGet, "/api/reference/directories", openapi.Operation{
+		Tags: tags, Summary: "Официальные справочники ЭСФ", Secured: true,
+		Response: []string{},
+	})
+	reg.Add(fiber.MethodGet, "/api/reference/directories/:name", openapi.Operation{
+		Tags: tags, Summary: "Коды официального справочника", Secured: true,
+		Description: "Справочники синхронизируются со шлюзом налоговой службы ежедневно",
+		Response:    services.Directory{},
+	})
+	reg.Add(fiber.MethodPost, "/api/reference/directories/sync", openapi.Operation{
+		Tags: tags, Summary: "Синхронизировать справочники со шлюзом (администратор)", Secured: true,
+		Response: services.DirectorySyncResult{},
+	})
+	reg.Add(fiber.MethodGet, "/api/rates", openapi.Operation{
+		Tags: tags, Summary: "Курсы валют НБКР на дату", Secured: true,
+		Description: "Последний опубликованный курс каждой валюты не позже date (YYYY-MM-DD, по умолчанию сегодня)",
+		Query:       ratesQuery{}, Response: []entity.ExchangeRate{},
+	})
+	reg.Add(fiber.MethodPost, "/api/rates/refresh", openapi.Operation{
+		Tags: tags, Summary: "Загрузить последние курсы (администратор)", Secured: true,
+	})
+}
+
+func describeDelegationRoutes(reg *openapi.Registry) {
+	tags := []string{"Delegations"}
+	reg.Add(fiber.MethodGet, "/api/delegations", openapi.Operation{
+		Tags: tags, Summary: "Делегирования права подписи, выданные и полученные пользователем", Secured: true,
+		Response: []entity.SigningDelegation{},
+	})
+	reg.Add(fiber.MethodPost, "/api/delegations", openapi.Operation{
+		Tags: tags, Summary: "Передать право подписи на срок", Secured: true,
+		Description: "Передать можно только собственное право подписи другому активному пользователю; validFrom и validTo включительно",
+		Request:     models.DelegationRequest{}, Response: entity.SigningDelegation{}, Status: fiber.StatusCreated,
+	})
+	reg.Add(fiber.MethodDelete, "/api/delegations/:id", openapi.Operation{
+		Tags: tags, Summary: "Досрочно отозвать делегирование", Secured: true,
+		Response: entity.SigningDelegation{},
+	})
+}
+
+func describeSavedViewRoutes(reg *openapi.Registry) {
+	tags := []string{"Saved views"}
+	reg.Add(fiber.MethodGet, "/api/users/me/views", openapi.Operation{
+		Tags: tags, Summary: "Сохраненные представления списка документов", Secured: true,
+		Response: []services.SavedView{},
+	})
+	reg.Add(fiber.MethodPost, "/api/users/me/views", openapi.Operation{
+		Tags: tags, Summary: "Сохранить фильтры и сортировку под именем", Secured: true,
+		Description: "Даты фильтра могут быть относительными (today, -30d) и считаются в момент применения представления",
+		Request:     models.SavedViewRequest{}, Response: services.SavedView{}, Status: fiber.StatusCreated,
+	})
+	reg.Add(fiber.MethodGet, "/api/users/me/views/:id", openapi.Operation{
+		Tags: tags, Summary: "Сохраненное представление", Secured: true,
+		Response: services.SavedView{},
+	})
+	reg.Add(fiber.MethodPut, "/api/users/me/views/:id", openapi.Operation{
+		Tags: tags, Summary: "Заменить представление", Secured: true,
+		Request: models.SavedViewRequest{}, Response: services.SavedView{},
+	})
+	reg.Add(fiber.MethodDelete, "/api/users/me/views/:id", openapi.Operation{
+		Tags: tags, Summary: "Удалить представление", Secured: true,
+	})
+}
+
+func describeAdminRoutes(reg *openapi.Registry) {
+	tags := []string{"Admin"}
+	admin := func(method, path string, op openapi.Operation) {
+		op.Tags, op.Secured = tags, true
+		reg.Add(method, "/api/admin"+path, op)
+	}
+
+	admin(fiber.MethodGet, "/migrations", openapi.Operation{
+		Summary: "Статус миграций", Query: migrationsQuery{}, Response: services.MigrationReport{},
+	})
+	admin(fiber.MethodPost, "/migrations/tenants", openapi.Operation{
+		Summary:     "Мигрировать БД всех организаций",
+		Description: "Фоновая операция tenant_migration; concurrency — число БД, мигрируемых одновременно (по умолчанию TENANT_MIGRATION_CONCURRENCY)",
+		Request:     tenantMigrationRequest{}, Response: entity.Operation{}, Status: fiber.StatusAccepted,
+	})
+	admin(fiber.MethodGet, "/migrations/tenants/:id", openapi.Operation{
+		Summary: "Итог миграции БД организаций", Response: services.TenantMigrationSummary{},
+	})
+	admin(fiber.MethodPost, "/migrations/tenants/:id/resume", openapi.Operation{
+		Summary:     "Возобновить миграцию БД организаций",
+		Description: "Повторяет завершенную с ошибкой операцию только для БД, которые не удалось мигрировать",
+		Request:     tenantMigrationRequest{}, Response: entity.Operation{}, Status: fiber.StatusAccepted,
+	})
+	admin(fiber.MethodPost, "/tenant-credentials/rotate", openapi.Operation{
+		Summary:     "Ротация учетных данных БД организаций",
+		Description: "Фоновая операция tenant_credential_rotation; без organizationIds — все организации. Пулы переключаются без простоя",
+		Request:     services.TenantCredentialRotationRequest{}, Response: entity.Operation{}, Status: fiber.StatusAccepted,
+	})
+	admin(fiber.MethodGet, "/jobs", openapi.Operation{Summary: "Состояние очередей фоновых задач", Response: jobs.Stats{}})
+	admin(fiber.MethodGet, "/jobs/dead/:queue", openapi.Operation{
+		Summary: "Задачи очереди, исчерпавшие попытки", Query: deadJobsQuery{}, Response: []jobs.Job{},
+	})
+	admin(fiber.MethodGet, "/jobs/scheduled", openapi.Operation{
+		Summary: "Отложенные задачи и повторы в порядке запуска", Query: scheduledJobsQuery{}, Response: []jobs.Job{},
+		Meta: pagination.PaginationInfo{},
+	})
+	admin(fiber.MethodDelete, "/jobs/scheduled/:key", openapi.Operation{
+		Summary:     "Снять отложенную задачу с расписания",
+		Description: "Задача ищется по ключу WithKey; 404, если задачи нет или она уже в очереди",
+		Status:      fiber.StatusNoContent,
+	})
+	admin(fiber.MethodGet, "/dead-letters", openapi.Operation{
+		Summary: "Очередь недоставленных сообщений", Query: deadLetterQuery{},
+		Response: []entity.DeadLetter{}, Meta: pagination.PaginationInfo{},
+	})
+	admin(fiber.MethodGet, "/dead-letters/:id", openapi.Operation{
+		Summary: "Недоставленное сообщение с полезной нагрузкой", Response: entity.DeadLetter{},
+	})
+	admin(fiber.MethodPost, "/dead-letters/requeue", openapi.Operation{
+		Summary: "Повторно поставить сообщения в обработку",
+		Request: requeueDeadLettersRequest{}, Response: services.DeadLetterRequeueResult{},
+	})
+	admin(fiber.MethodPost, "/events/replay", openapi.Operation{
+		Summary: "Повторно опубликовать доменные события организации",
+		Request: services.EventReplayRequest{}, Response: services.EventReplayResult{},
+	})
+	admin(fiber.MethodGet, "/emails", openapi.Operation{
+		Summary: "Журнал доставки писем", Query: emailDeliveryQuery{},
+		Response: []entity.EmailDelivery{}, Meta: pagination.PaginationInfo{},
+	})
+	admin(fiber.MethodGet, "/emails/:id", openapi.Operation{Summary: "Статус доставки письма", Response: entity.EmailDelivery{}})
+	admin(fiber.MethodGet, "/audit", openapi.Operation{
+		Summary: "Журнал аудита", Query: auditListQuery{}, Response: []entity.AuditLog{}, Meta: pagination.PaginationInfo{},
+	})
+	admin(fiber.MethodGet, "/audit/export", openapi.Operation{
+		Summary: "Выгрузить журнал аудита потоком", Query: auditExportQuery{}, ContentType: "text/csv",
+	})
+
+	admin(fiber.MethodPut, "/organizations/:id/plan", openapi.Operation{
+		Summary:     "Назначить организации тарифный план",
+		Description: "План должен быть среди QUOTA_PLANS; требует QUOTA_ENABLED=true",
+		Request:     models.OrganizationPlanRequest{}, Response: organizationPlanResource{},
+	})
+
+	trash := []struct{ method, path, summary string }{
+		{fiber.MethodPost, "/users/:id/restore", "Восстановить пользователя"},
+		{fiber.MethodDelete, "/users/:id/purge", "Окончательно удалить пользователя"},
+		{fiber.MethodPost, "/organizations/:id/restore", "Восстановить организацию"},
+		{fiber.MethodDelete, "/organizations/:id/purge", "Окончательно удалить организацию"},
+		{fiber.MethodPost, "/organizations/:org_id/documents/:id/restore", "Восстановить документ"},
+		{fiber.MethodDelete, "/organizations/:org_id/documents/:id/purge", "Окончательно удалить документ"},
+	}
+	for _, route := range trash {
+		admin(route.method, route.path, openapi.Operation{Summary: route.summary, Response: trashResult{}})
+	}
+}
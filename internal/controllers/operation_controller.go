@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+)
+
+type OperationController struct {
+	logger  *logger.Logger
+	service services.OperationService
+}
+
+// NewOperationController регистрирует маршрут состояния длительных операций
+func NewOperationController(app *fiber.App, log *logrus.Logger, service services.OperationService) {
+	controller := &OperationController{
+		logger:  logger.New(log),
+		service: service,
+	}
+
+	controller.logger.Info(context.Background(), "OperationController инициализирован")
+
+	operations := app.Group("/api/operations")
+	operations.Use(middleware.JWTMiddleware())
+	operations.Get("/:id", controller.getOperation)
+}
+
+// getOperation возвращает состояние, прогресс, ссылку на результат или ошибку операции.
+// Операцию видит только ее инициатор или администратор.
+func (c *OperationController) getOperation(ctx *fiber.Ctx) error {
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "invalid operation ID"))
+	}
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrUnauthorized, "unauthorized"))
+	}
+
+	status, err := c.service.GetOperation(ctx.Context(), id)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to fetch operation"))
+	}
+
+	if status.RequestedBy != userID.String() {
+		if uc := rbac.ExtractUserContext(ctx); uc == nil || !uc.IsAdmin() {
+			// Чужая операция не раскрывается
+			return response.Error(ctx, apperror.NotFoundError("operation"))
+		}
+	}
+
+	// Пока операция не завершена, клиенту подсказывается интервал опроса
+	if !status.Done() {
+		ctx.Set(fiber.HeaderRetryAfter, operationRetryAfter)
+	}
+	return response.OK(ctx, status)
+}
+
+// operationRetryAfter рекомендуемый интервал опроса незавершенной операции, в секундах
+const operationRetryAfter = "2"
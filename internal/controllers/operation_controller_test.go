@@ -0,0 +1,71 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/testutil"
+)
+
+// stubOperationService хранит операции в памяти
+type stubOperationService struct {
+	services.OperationService
+	ops map[uuid.UUID]*services.OperationStatus
+}
+
+func (s *stubOperationService) GetOperation(ctx context.Context, id uuid.UUID) (*services.OperationStatus, error) {
+	status, ok := s.ops[id]
+	if !ok {
+		return nil, apperror.NotFoundError("operation")
+	}
+	return status, nil
+}
+
+func TestOperationController_Get(t *testing.T) {
+	h := testutil.NewHarness(t)
+	owner, stranger := testutil.NewUser(), testutil.NewUser()
+	running := &entity.Operation{ID: uuid.New(), Kind: services.OperationKindExport, State: entity.OperationStateRunning, RequestedBy: owner.ID.String()}
+	done := &entity.Operation{ID: uuid.New(), Kind: services.OperationKindExport, State: entity.OperationStateSucceeded, RequestedBy: owner.ID.String(), ResultURL: "/api/exports/1"}
+	svc := &stubOperationService{ops: map[uuid.UUID]*services.OperationStatus{
+		running.ID: {Operation: running, Progress: 40},
+		done.ID:    {Operation: done, Progress: 100},
+	}}
+	NewOperationController(h.App, h.Logger, svc)
+	ownerToken := testutil.WithToken(h.Token(owner.ID.String(), owner.Email))
+
+	resp := h.Do(http.MethodGet, "/api/operations/"+running.ID.String(), nil)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+	// Незавершенная операция подсказывает интервал опроса
+	resp = h.Do(http.MethodGet, "/api/operations/"+running.ID.String(), nil, ownerToken)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+	assert.Equal(t, operationRetryAfter, resp.Header.Get(fiber.HeaderRetryAfter))
+	var got services.OperationStatus
+	resp.DecodeData(&got)
+	assert.Equal(t, entity.OperationStateRunning, got.State)
+	assert.Equal(t, float64(40), got.Progress)
+
+	resp = h.Do(http.MethodGet, "/api/operations/"+done.ID.String(), nil, ownerToken)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get(fiber.HeaderRetryAfter))
+	resp.DecodeData(&got)
+	assert.Equal(t, "/api/exports/1", got.ResultURL)
+
+	// Чужая операция выглядит как несуществующая
+	resp = h.Do(http.MethodGet, "/api/operations/"+done.ID.String(), nil,
+		testutil.WithToken(h.Token(stranger.ID.String(), stranger.Email)))
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	assert.Equal(t, string(apperror.ErrNotFound), resp.ErrorCode())
+
+	resp = h.Do(http.MethodGet, "/api/operations/not-a-uuid", nil, ownerToken)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
@@ -0,0 +1,213 @@
+package controllers
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+	"github.com/rusgainew/tunduck-app/pkg/validation"
+)
+
+type PriceListController struct {
+	logger     *logger.Logger
+	priceLists services.PriceListService
+}
+
+// NewPriceListController регистрирует маршруты прайс-листов и групп покупателей
+func NewPriceListController(app *fiber.App, log *logrus.Logger, priceLists services.PriceListService) {
+	controller := &PriceListController{
+		logger:     logger.New(log),
+		priceLists: priceLists,
+	}
+
+	controller.logger.Info(context.Background(), "PriceListController инициализирован")
+	controller.registerRoutes(app)
+}
+
+func (c *PriceListController) registerRoutes(app *fiber.App) {
+	lists := app.Group("/api/price-lists")
+	lists.Use(middleware.JWTMiddleware())
+	lists.Get("/", c.listPriceLists)
+	lists.Post("/", c.createPriceList)
+	lists.Get("/prices", c.resolvePrices)
+	lists.Get("/:id", c.getPriceList)
+	lists.Put("/:id", c.updatePriceList)
+	lists.Delete("/:id", c.deletePriceList)
+
+	groups := app.Group("/api/customer-groups")
+	groups.Use(middleware.JWTMiddleware())
+	groups.Get("/", c.listGroups)
+	groups.Put("/:name", c.setGroup)
+}
+
+// listPriceLists возвращает прайс-листы организации без цен
+func (c *PriceListController) listPriceLists(ctx *fiber.Ctx) error {
+	orgID, err := resolveOrgID(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "invalid organization ID"))
+	}
+
+	lists, err := c.priceLists.List(ctx.Context(), orgID)
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка получения прайс-листов", err, logrus.Fields{"org_id": orgID.String()})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to fetch price lists"))
+	}
+	return response.OK(ctx, lists)
+}
+
+// createPriceList добавляет прайс-лист
+func (c *PriceListController) createPriceList(ctx *fiber.Ctx) error {
+	orgID, err := resolveOrgID(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "invalid organization ID"))
+	}
+
+	var req models.PriceListRequest
+	if appErr := validation.ParseBody(ctx, &req); appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	list, err := c.priceLists.Create(ctx.Context(), orgID, &req)
+	if err != nil {
+		c.logger.Warn(ctx.Context(), "Прайс-лист не создан", logrus.Fields{"org_id": orgID.String(), "error": err.Error()})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to create price list"))
+	}
+	return response.SuccessCreated(ctx, "Price list created", list)
+}
+
+// resolvePrices возвращает цены для заполнения позиций документа:
+// ?contractorTin=&currency=&date=2006-01-02, без даты — на сегодня
+func (c *PriceListController) resolvePrices(ctx *fiber.Ctx) error {
+	orgID, err := resolveOrgID(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "invalid organization ID"))
+	}
+
+	tin, currency := ctx.Query("contractorTin"), ctx.Query("currency")
+	if tin == "" || currency == "" {
+		return response.Error(ctx, apperror.ValidationError("contractorTin and currency are required"))
+	}
+	date := time.Now()
+	if raw := ctx.Query("date"); raw != "" {
+		if date, err = time.Parse("2006-01-02", raw); err != nil {
+			return response.Error(ctx, apperror.ValidationError("date must be in YYYY-MM-DD format"))
+		}
+	}
+
+	prices, err := c.priceLists.Prices(ctx.Context(), orgID, tin, currency, date)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to resolve prices"))
+	}
+	return response.OK(ctx, prices)
+}
+
+// getPriceList возвращает прайс-лист с ценами
+func (c *PriceListController) getPriceList(ctx *fiber.Ctx) error {
+	orgID, id, appErr := priceListTarget(ctx)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	list, err := c.priceLists.Get(ctx.Context(), orgID, id)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to fetch price list"))
+	}
+	return response.OK(ctx, list)
+}
+
+// updatePriceList заменяет прайс-лист вместе с ценами
+func (c *PriceListController) updatePriceList(ctx *fiber.Ctx) error {
+	orgID, id, appErr := priceListTarget(ctx)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	var req models.PriceListRequest
+	if appErr := validation.ParseBody(ctx, &req); appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	list, err := c.priceLists.Update(ctx.Context(), orgID, id, &req)
+	if err != nil {
+		c.logger.Warn(ctx.Context(), "Прайс-лист не изменен", logrus.Fields{"price_list_id": id.String(), "error": err.Error()})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to update price list"))
+	}
+	return response.SuccessOK(ctx, "Price list updated", list)
+}
+
+// deletePriceList удаляет прайс-лист
+func (c *PriceListController) deletePriceList(ctx *fiber.Ctx) error {
+	orgID, id, appErr := priceListTarget(ctx)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	if err := c.priceLists.Delete(ctx.Context(), orgID, id); err != nil {
+		c.logger.Warn(ctx.Context(), "Прайс-лист не удален", logrus.Fields{"price_list_id": id.String(), "error": err.Error()})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to delete price list"))
+	}
+	return response.SuccessOK(ctx, "Price list deleted", nil)
+}
+
+// listGroups возвращает группы покупателей
+func (c *PriceListController) listGroups(ctx *fiber.Ctx) error {
+	orgID, err := resolveOrgID(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "invalid organization ID"))
+	}
+
+	groups, err := c.priceLists.ListGroups(ctx.Context(), orgID)
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка получения групп покупателей", err, logrus.Fields{"org_id": orgID.String()})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to fetch customer groups"))
+	}
+	return response.OK(ctx, groups)
+}
+
+// setGroup заменяет состав группы покупателей; пустой список удаляет группу
+func (c *PriceListController) setGroup(ctx *fiber.Ctx) error {
+	orgID, err := resolveOrgID(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "invalid organization ID"))
+	}
+
+	name, err := url.PathUnescape(ctx.Params("name"))
+	if err != nil {
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "invalid customer group name"))
+	}
+
+	var req models.CustomerGroupRequest
+	if appErr := validation.ParseBody(ctx, &req); appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	group, err := c.priceLists.SetGroup(ctx.Context(), orgID, name, req.ContractorTins)
+	if err != nil {
+		c.logger.Warn(ctx.Context(), "Группа покупателей не изменена", logrus.Fields{"org_id": orgID.String(), "error": err.Error()})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to update customer group"))
+	}
+	return response.SuccessOK(ctx, "Customer group updated", group)
+}
+
+// priceListTarget разбирает организацию и ID прайс-листа из запроса
+func priceListTarget(ctx *fiber.Ctx) (uuid.UUID, uuid.UUID, *apperror.AppError) {
+	orgID, err := resolveOrgID(ctx)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, apperror.New(apperror.ErrInvalidRequest, "invalid organization ID")
+	}
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, apperror.New(apperror.ErrInvalidRequest, "invalid price list ID format")
+	}
+	return orgID, id, nil
+}
@@ -0,0 +1,83 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/testutil"
+)
+
+// stubPriceListService запоминает аргументы запросов цен и групп
+type stubPriceListService struct {
+	services.PriceListService
+	priceDate time.Time
+	groupName string
+}
+
+func (s *stubPriceListService) Create(ctx context.Context, orgID uuid.UUID, req *models.PriceListRequest) (*entity.PriceList, error) {
+	return &entity.PriceList{ID: uuid.New(), Name: req.Name, CustomerGroup: req.CustomerGroup, CurrencyCode: req.CurrencyCode}, nil
+}
+
+func (s *stubPriceListService) Prices(ctx context.Context, orgID uuid.UUID, contractorTin string, currency string, date time.Time) ([]services.ResolvedPrice, error) {
+	s.priceDate = date
+	return []services.ResolvedPrice{{SalesTaxCode: "1001", Price: 120, FloorPrice: 100}}, nil
+}
+
+func (s *stubPriceListService) SetGroup(ctx context.Context, orgID uuid.UUID, name string, tins []string) (*services.CustomerGroup, error) {
+	s.groupName = name
+	return &services.CustomerGroup{Name: name, ContractorTins: tins}, nil
+}
+
+func TestPriceListController(t *testing.T) {
+	h := testutil.NewHarness(t)
+	svc := &stubPriceListService{}
+	NewPriceListController(h.App, h.Logger, svc)
+	user := testutil.NewUser()
+	token := testutil.WithToken(h.Token(user.ID.String(), user.Email))
+	org := testutil.WithHeader("X-Org-Id", uuid.NewString())
+
+	body := map[string]interface{}{
+		"name": "Wholesale", "customerGroup": "wholesale", "currencyCode": "KGS",
+		"items": []map[string]interface{}{{"salesTaxCode": "1001", "price": 120, "floorPrice": 100}},
+	}
+	resp := h.Do(http.MethodPost, "/api/price-lists", body, org)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+	resp = h.Do(http.MethodPost, "/api/price-lists", body, org, token)
+	require.Equal(t, fiber.StatusCreated, resp.StatusCode, string(resp.Body))
+	var created entity.PriceList
+	resp.DecodeData(&created)
+	assert.Equal(t, "wholesale", created.CustomerGroup)
+
+	resp = h.Do(http.MethodPost, "/api/price-lists", map[string]interface{}{"name": "Bad", "currencyCode": "KGS",
+		"items": []map[string]interface{}{{"salesTaxCode": "1001", "price": 0}}}, org, token)
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+
+	resp = h.Do(http.MethodGet, "/api/price-lists/prices?contractorTin=01234567890123&currency=KGS&date=2026-03-01", nil, org, token)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+	var prices []services.ResolvedPrice
+	resp.DecodeData(&prices)
+	require.Len(t, prices, 1)
+	assert.Equal(t, 120.0, prices[0].Price)
+	assert.Equal(t, "2026-03-01", svc.priceDate.Format("2006-01-02"))
+
+	resp = h.Do(http.MethodGet, "/api/price-lists/prices?contractorTin=01234567890123&currency=KGS&date=01.03.2026", nil, org, token)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+
+	resp = h.Do(http.MethodPut, "/api/customer-groups/key%20accounts", map[string]interface{}{"contractorTins": []string{"01234567890123"}}, org, token)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+	assert.Equal(t, "key accounts", svc.groupName)
+
+	resp = h.Do(http.MethodGet, "/api/price-lists/not-a-uuid", nil, org, token)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+}
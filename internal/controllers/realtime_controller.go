@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/i18n"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/realtime"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+)
+
+const (
+	// realtimeMaxChannels сколько каналов может слушать одно соединение
+	realtimeMaxChannels = 50
+	// realtimeLookupTimeout таймаут проверки организации при подписке
+	realtimeLookupTimeout = 5 * time.Second
+)
+
+// Ключи Locals, через которые connect передает пользователя и язык соединению
+const (
+	realtimeUserKey = "realtime_user_id"
+	realtimeLangKey = "realtime_lang"
+)
+
+// realtimeCommand команда клиента: {"action": "subscribe", "channel": "org:<id>"}
+type realtimeCommand struct {
+	Action  string `json:"action"`
+	Channel string `json:"channel"`
+}
+
+// realtimeReply ответ на команду клиента; уведомления отправляются как realtime.Message
+type realtimeReply struct {
+	Type    string `json:"type"`
+	Channel string `json:"channel,omitempty"`
+	Code    string `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// RealtimeController обслуживает /ws: уведомления организаций и пользователя в реальном времени
+type RealtimeController struct {
+	logger     *logger.Logger
+	hub        *realtime.Hub
+	orgService services.EsfOrganizationService
+	cfg        realtime.ConnConfig
+}
+
+// NewRealtimeController регистрирует маршрут /ws
+func NewRealtimeController(app *fiber.App, log *logrus.Logger, hub *realtime.Hub, orgService services.EsfOrganizationService, cfg realtime.ConnConfig) {
+	controller := &RealtimeController{
+		logger:     logger.New(log),
+		hub:        hub,
+		orgService: orgService,
+		cfg:        cfg,
+	}
+
+	controller.logger.Info(context.Background(), "RealtimeController initialized")
+	// Браузер передает токен в ?token=, так как не может задать заголовок при рукопожатии
+	app.Get("/ws", middleware.JWTQueryMiddleware("token"), controller.connect, websocket.New(controller.serve))
+}
+
+// connect проверяет, что запрос — рукопожатие WebSocket, и передает пользователя обработчику соединения
+func (c *RealtimeController) connect(ctx *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrUnauthorized, "unauthorized"))
+	}
+	if !websocket.IsWebSocketUpgrade(ctx) {
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "WebSocket upgrade required").WithHTTPStatus(fiber.StatusUpgradeRequired))
+	}
+
+	// После рукопожатия контекст Fiber переиспользуется, поэтому пользователь и язык передаются через Locals
+	ctx.Locals(realtimeUserKey, userID)
+	ctx.Locals(realtimeLangKey, i18n.FromCtx(ctx))
+	return ctx.Next()
+}
+
+// serve подписывает соединение на личный канал пользователя и пересылает уведомления, пока оно открыто
+func (c *RealtimeController) serve(wsConn *websocket.Conn) {
+	userID, _ := wsConn.Locals(realtimeUserKey).(uuid.UUID)
+	lang, _ := wsConn.Locals(realtimeLangKey).(i18n.Language)
+	conn := realtime.NewConn(wsConn.Conn, c.cfg)
+	defer conn.Close()
+
+	ctx := context.Background()
+	fields := logrus.Fields{"user_id": userID.String(), "remote": conn.RemoteAddr().String()}
+	c.logger.Debug(ctx, "Realtime connection opened", fields)
+	defer c.logger.Debug(ctx, "Realtime connection closed", fields)
+
+	sub := c.hub.Subscribe()
+	defer sub.Close()
+	sub.Join(realtime.UserChannel(userID))
+
+	// Команды читаются в отдельной горутине; запись в соединение потокобезопасна
+	go c.readCommands(conn, sub, userID, lang)
+
+	for {
+		select {
+		case <-conn.Done():
+			return
+		case msg, ok := <-sub.Messages():
+			if !ok {
+				return
+			}
+			if err := conn.WriteJSON(msg); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (c *RealtimeController) readCommands(conn *realtime.Conn, sub *realtime.Subscription, userID uuid.UUID, lang i18n.Language) {
+	defer conn.Close()
+
+	for {
+		var cmd realtimeCommand
+		if err := conn.ReadJSON(&cmd); err != nil {
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if errors.As(err, &syntaxErr) || errors.As(err, &typeErr) {
+				c.replyError(conn, "", apperror.New(apperror.ErrInvalidRequest, "invalid request format"), lang)
+				continue
+			}
+			return
+		}
+
+		switch cmd.Action {
+		case "subscribe":
+			if appErr := c.authorize(userID, cmd.Channel); appErr != nil {
+				c.replyError(conn, cmd.Channel, appErr, lang)
+				continue
+			}
+			if len(sub.Channels()) >= realtimeMaxChannels {
+				c.replyError(conn, cmd.Channel, apperror.New(apperror.ErrRateLimitExceeded, "too many subscriptions"), lang)
+				continue
+			}
+			sub.Join(cmd.Channel)
+			_ = conn.WriteJSON(realtimeReply{Type: "subscribed", Channel: cmd.Channel})
+		case "unsubscribe":
+			sub.Leave(cmd.Channel)
+			_ = conn.WriteJSON(realtimeReply{Type: "unsubscribed", Channel: cmd.Channel})
+		case "ping":
+			_ = conn.WriteJSON(realtimeReply{Type: "pong"})
+		default:
+			c.replyError(conn, cmd.Channel, apperror.New(apperror.ErrInvalidRequest, "unknown action"), lang)
+		}
+	}
+}
+
+// authorize проверяет доступ к каналу: личный канал — только свой, канал организации — существующей организации
+func (c *RealtimeController) authorize(userID uuid.UUID, channel string) *apperror.AppError {
+	kind, rawID, _ := strings.Cut(channel, ":")
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return apperror.New(apperror.ErrInvalidRequest, "invalid channel")
+	}
+
+	switch kind {
+	case "user":
+		if id != userID {
+			return apperror.New(apperror.ErrForbidden, "access denied")
+		}
+		return nil
+	case "org":
+		ctx, cancel := context.WithTimeout(context.Background(), realtimeLookupTimeout)
+		defer cancel()
+		if _, err := c.orgService.GetOrganizationByID(ctx, id); err != nil {
+			appErr := apperror.From(err, apperror.ErrInternal, "failed to get organization")
+			if appErr.Code == apperror.ErrInternal {
+				c.logger.Error(ctx, "Failed to check organization for subscription", err, logrus.Fields{"org_id": id.String()})
+			}
+			return appErr
+		}
+		return nil
+	}
+	return apperror.New(apperror.ErrInvalidRequest, "invalid channel")
+}
+
+func (c *RealtimeController) replyError(conn *realtime.Conn, channel string, appErr *apperror.AppError, lang i18n.Language) {
+	localized := appErr.ToLocalizedResponse(lang)
+	_ = conn.WriteJSON(realtimeReply{Type: "error", Channel: channel, Code: localized.Code, Message: localized.Message})
+}
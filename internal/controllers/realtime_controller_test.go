@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/realtime"
+	"github.com/rusgainew/tunduck-app/pkg/testutil"
+)
+
+type realtimeTestEvent struct {
+	Type    string                 `json:"type"`
+	Channel string                 `json:"channel"`
+	Code    string                 `json:"code"`
+	Message string                 `json:"message"`
+	Data    map[string]interface{} `json:"data"`
+}
+
+func readRealtime(t *testing.T, conn *websocket.Conn) realtimeTestEvent {
+	t.Helper()
+	got := make(chan realtimeTestEvent, 1)
+	go func() {
+		var event realtimeTestEvent
+		_ = conn.ReadJSON(&event)
+		got <- event
+	}()
+	select {
+	case event := <-got:
+		return event
+	case <-time.After(2 * time.Second):
+		t.Fatal("no realtime message received")
+		return realtimeTestEvent{}
+	}
+}
+
+func TestRealtimeController(t *testing.T) {
+	h := testutil.NewHarness(t)
+	hub := realtime.NewHub(nil, realtime.Config{}, h.Logger)
+	orgID := uuid.New()
+	NewRealtimeController(h.App, h.Logger, hub, &stubOrganizationService{org: models.EsfOrganizationModel{ID: orgID.String(), Name: "Tunduk"}}, realtime.ConnConfig{})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = h.App.Listener(ln) }()
+	t.Cleanup(func() { _ = h.App.Shutdown() })
+	base := "ws://" + ln.Addr().String() + "/ws"
+	ctx := context.Background()
+
+	// Без токена рукопожатие отклоняется
+	_, resp, err := websocket.DefaultDialer.DialContext(ctx, base, nil)
+	require.ErrorIs(t, err, websocket.ErrBadHandshake)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	// Обычный HTTP-запрос с токеном получает 426
+	user := testutil.NewUser()
+	token := h.Token(user.ID.String(), user.Email)
+	plain := h.Do(http.MethodGet, "/ws", nil, testutil.WithToken(token))
+	assert.Equal(t, http.StatusUpgradeRequired, plain.StatusCode)
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, base+"?token="+url.QueryEscape(token), http.Header{"Accept-Language": {"ru"}})
+	require.NoError(t, err)
+	defer conn.Close()
+
+	require.NoError(t, conn.WriteJSON(map[string]string{"action": "subscribe", "channel": realtime.OrgChannel(orgID)}))
+	assert.Equal(t, realtimeTestEvent{Type: "subscribed", Channel: realtime.OrgChannel(orgID)}, readRealtime(t, conn))
+
+	// Чужой личный канал и несуществующая организация недоступны, ошибки локализуются
+	require.NoError(t, conn.WriteJSON(map[string]string{"action": "subscribe", "channel": realtime.UserChannel(uuid.New())}))
+	denied := readRealtime(t, conn)
+	assert.Equal(t, "error", denied.Type)
+	assert.Equal(t, string(apperror.ErrForbidden), denied.Code)
+	assert.Equal(t, "Доступ запрещен", denied.Message)
+
+	require.NoError(t, conn.WriteJSON(map[string]string{"action": "subscribe", "channel": realtime.OrgChannel(uuid.New())}))
+	assert.Equal(t, string(apperror.ErrOrgNotFound), readRealtime(t, conn).Code)
+
+	// Уведомления организации и личного канала доставляются без подписки на личный канал
+	require.NoError(t, hub.Publish(ctx, realtime.OrgChannel(orgID), realtime.TypeDocumentStatus, map[string]string{"status": "registered"}))
+	status := readRealtime(t, conn)
+	assert.Equal(t, realtime.TypeDocumentStatus, status.Type)
+	assert.Equal(t, "registered", status.Data["status"])
+
+	require.NoError(t, hub.Publish(ctx, realtime.UserChannel(user.ID), realtime.TypeMention, map[string]string{"comment": "c-1"}))
+	assert.Equal(t, realtime.TypeMention, readRealtime(t, conn).Type)
+
+	require.NoError(t, conn.WriteJSON(map[string]string{"action": "unsubscribe", "channel": realtime.OrgChannel(orgID)}))
+	assert.Equal(t, "unsubscribed", readRealtime(t, conn).Type)
+	require.NoError(t, conn.WriteJSON(map[string]string{"action": "ping"}))
+	assert.Equal(t, "pong", readRealtime(t, conn).Type)
+}
@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/cache"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+)
+
+type ReferenceDataController struct {
+	logger    *logger.Logger
+	service   services.ReferenceDataService
+	responses *middleware.ResponseCache
+}
+
+// NewReferenceDataController регистрирует маршруты справочников ЭСФ. Ответы GET кешируются
+// в responses (nil — без кеша) с тегом cache.TagReference, который сервис сбрасывает при синхронизации.
+func NewReferenceDataController(app *fiber.App, log *logrus.Logger, service services.ReferenceDataService, responses *middleware.ResponseCache) {
+	controller := &ReferenceDataController{
+		logger:    logger.New(log),
+		service:   service,
+		responses: responses,
+	}
+
+	controller.logger.Info(context.Background(), "ReferenceDataController инициализирован")
+	controller.registerRoutes(app)
+}
+
+func (c *ReferenceDataController) registerRoutes(app *fiber.App) {
+	reference := app.Group("/api/reference")
+	reference.Use(middleware.JWTMiddleware())
+	cached := c.responses.Handler(middleware.StaticTags(cache.TagReference))
+	reference.Get("/", cached, c.getReferenceData)
+	reference.Get("/directories", cached, c.listDirectories)
+	reference.Post("/directories/sync", rbac.RequireAdminRole(), c.syncDirectories)
+	reference.Get("/directories/:name", cached, c.getDirectory)
+}
+
+// getReferenceData коды, встречающиеся в документах организаций
+func (c *ReferenceDataController) getReferenceData(ctx *fiber.Ctx) error {
+	data, err := c.service.GetReferenceData(ctx.Context())
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка получения справочных кодов", err)
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to fetch reference data"))
+	}
+	return response.OK(ctx, data)
+}
+
+// listDirectories имена официальных справочников
+func (c *ReferenceDataController) listDirectories(ctx *fiber.Ctx) error {
+	return response.OK(ctx, c.service.ListDirectories())
+}
+
+// getDirectory коды официального справочника
+func (c *ReferenceDataController) getDirectory(ctx *fiber.Ctx) error {
+	dir, err := c.service.GetDirectory(ctx.Context(), ctx.Params("name"))
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to fetch directory"))
+	}
+	return response.OK(ctx, dir)
+}
+
+// syncDirectories немедленно синхронизирует справочники со шлюзом налоговой службы
+func (c *ReferenceDataController) syncDirectories(ctx *fiber.Ctx) error {
+	result, err := c.service.SyncDirectories(ctx.Context())
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка синхронизации справочников", err)
+		return response.Error(ctx, apperror.From(err, apperror.ErrExternalService, "failed to sync directories"))
+	}
+	return response.OK(ctx, result)
+}
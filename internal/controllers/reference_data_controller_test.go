@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/esfgateway"
+	"github.com/rusgainew/tunduck-app/pkg/testutil"
+)
+
+// stubReferenceDataService отдает один справочник валют
+type stubReferenceDataService struct {
+	services.ReferenceDataService
+	synced bool
+}
+
+func (s *stubReferenceDataService) ListDirectories() []string {
+	return esfgateway.Directories
+}
+
+func (s *stubReferenceDataService) GetDirectory(ctx context.Context, name string) (*services.Directory, error) {
+	if name != esfgateway.DirectoryCurrencies {
+		return nil, apperror.NotFoundError("directory")
+	}
+	return &services.Directory{Name: name, Codes: []esfgateway.DirectoryEntry{{Code: "KGS", Name: "Kyrgyz som"}}}, nil
+}
+
+func (s *stubReferenceDataService) SyncDirectories(ctx context.Context) (*services.DirectorySyncResult, error) {
+	s.synced = true
+	return &services.DirectorySyncResult{}, nil
+}
+
+func TestReferenceDataController(t *testing.T) {
+	h := testutil.NewHarness(t)
+	svc := &stubReferenceDataService{}
+	NewReferenceDataController(h.App, h.Logger, svc, nil)
+	user := testutil.NewUser()
+	token := testutil.WithToken(h.Token(user.ID.String(), user.Email))
+
+	resp := h.Do(http.MethodGet, "/api/reference/directories", nil)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+	resp = h.Do(http.MethodGet, "/api/reference/directories", nil, token)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+	var names []string
+	resp.DecodeData(&names)
+	assert.Equal(t, esfgateway.Directories, names)
+
+	resp = h.Do(http.MethodGet, "/api/reference/directories/currencies", nil, token)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+	var dir services.Directory
+	resp.DecodeData(&dir)
+	assert.Equal(t, "KGS", dir.Codes[0].Code)
+
+	resp = h.Do(http.MethodGet, "/api/reference/directories/planets", nil, token)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+
+	resp = h.Do(http.MethodPost, "/api/reference/directories/sync", nil, token)
+	assert.NotEqual(t, fiber.StatusOK, resp.StatusCode, "sync requires the admin role")
+	assert.False(t, svc.synced)
+}
@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+	"github.com/rusgainew/tunduck-app/pkg/validation"
+)
+
+// reportRequest заказ отчета за период; даты в формате YYYY-MM-DD включительно
+type reportRequest struct {
+	Report string `json:"report" validate:"required"`
+	From   string `json:"from" validate:"required"`
+	To     string `json:"to" validate:"required"`
+	Format string `json:"format" validate:"omitempty,oneof=xlsx pdf"`
+}
+
+type ReportController struct {
+	logger  *logger.Logger
+	exports services.ExportService
+}
+
+// NewReportController регистрирует маршруты отчетов. Отчеты строятся фоновыми
+// выгрузками: состояние и ссылка на файл доступны через /api/exports/{id}.
+func NewReportController(app *fiber.App, log *logrus.Logger, exports services.ExportService) {
+	controller := &ReportController{
+		logger:  logger.New(log),
+		exports: exports,
+	}
+
+	controller.logger.Info(context.Background(), "ReportController инициализирован")
+	controller.registerRoutes(app)
+}
+
+func (c *ReportController) registerRoutes(app *fiber.App) {
+	reports := app.Group("/api/reports")
+	reports.Use(middleware.JWTMiddleware())
+	reports.Get("/", c.listReports)
+	reports.Post("/", c.createReport)
+}
+
+// listReports возвращает доступные отчеты и их форматы
+func (c *ReportController) listReports(ctx *fiber.Ctx) error {
+	return response.OK(ctx, services.ReportDefinitions())
+}
+
+// createReport ставит построение отчета организации в очередь выгрузок
+func (c *ReportController) createReport(ctx *fiber.Ctx) error {
+	orgID, err := resolveOrgID(ctx)
+	if err != nil {
+		c.logger.Warn(ctx.Context(), "Не удалось определить организацию", logrus.Fields{"error": err.Error()})
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "invalid organization ID"))
+	}
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrUnauthorized, "unauthorized"))
+	}
+
+	var req reportRequest
+	if appErr := validation.ParseBody(ctx, &req); appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+	from, errFrom := time.Parse(time.DateOnly, req.From)
+	to, errTo := time.Parse(time.DateOnly, req.To)
+	if errFrom != nil || errTo != nil {
+		return response.Error(ctx, apperror.ValidationError("report period must use YYYY-MM-DD dates"))
+	}
+	if req.Format == "" {
+		req.Format = services.ExportFormatXLSX
+	}
+
+	status, err := c.exports.CreateExport(ctx.Context(), services.ExportRequest{
+		Type:           services.ExportTypeReport,
+		Format:         req.Format,
+		RequestedBy:    userID.String(),
+		OrganizationID: orgID,
+		Report:         services.ReportParams{Kind: req.Report, From: from, To: to},
+	})
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка заказа отчета", err, logrus.Fields{"report": req.Report})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to create report"))
+	}
+
+	ctx.Set(fiber.HeaderLocation, services.OperationLocation(status.ID))
+	return response.Success(ctx, fiber.StatusAccepted, "Report queued", status)
+}
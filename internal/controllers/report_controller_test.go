@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/testutil"
+)
+
+func TestReportController_ListAndCreate(t *testing.T) {
+	h := testutil.NewHarness(t)
+	svc := &stubExportService{exports: map[uuid.UUID]*services.ExportStatus{}}
+	NewReportController(h.App, h.Logger, svc)
+
+	user := testutil.NewUser()
+	token := testutil.WithToken(h.Token(user.ID.String(), user.Email))
+	orgID := uuid.New()
+	org := testutil.WithHeader("X-Org-Id", orgID.String())
+
+	resp := h.Do(http.MethodGet, "/api/reports", nil)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+	resp = h.Do(http.MethodGet, "/api/reports", nil, token)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+	var defs []services.ReportDefinition
+	resp.DecodeData(&defs)
+	require.Len(t, defs, 3)
+	assert.Equal(t, []string{services.ExportFormatXLSX, services.ExportFormatPDF}, defs[0].Formats)
+
+	body := map[string]string{"report": services.ReportSalesRegister, "from": "2025-01-01", "to": "2025-03-31", "format": "pdf"}
+	resp = h.Do(http.MethodPost, "/api/reports", body, token, org)
+	require.Equal(t, fiber.StatusAccepted, resp.StatusCode, string(resp.Body))
+	var created entity.ExportJob
+	resp.DecodeData(&created)
+	assert.Equal(t, services.ExportTypeReport, created.Type)
+	assert.Equal(t, "/api/operations/"+created.ID.String(), resp.Header.Get(fiber.HeaderLocation))
+	assert.Equal(t, orgID, svc.last.OrganizationID)
+	assert.Equal(t, services.ExportFormatPDF, svc.last.Format)
+	assert.Equal(t, services.ReportParams{
+		Kind: services.ReportSalesRegister,
+		From: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2025, 3, 31, 0, 0, 0, 0, time.UTC),
+	}, svc.last.Report)
+
+	body["from"] = "01.01.2025"
+	resp = h.Do(http.MethodPost, "/api/reports", body, token, org)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, string(apperror.ErrValidation), resp.ErrorCode())
+
+	resp = h.Do(http.MethodPost, "/api/reports", map[string]string{"from": "2025-01-01", "to": "2025-01-31"}, token, org)
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode, "report kind is required")
+}
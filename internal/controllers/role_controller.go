@@ -2,7 +2,6 @@ package controllers
 
 import (
 	"context"
-	"net/http"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -16,6 +15,8 @@ import (
 	"github.com/rusgainew/tunduck-app/pkg/logger"
 	"github.com/rusgainew/tunduck-app/pkg/middleware"
 	"github.com/rusgainew/tunduck-app/pkg/rbac"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+	"github.com/rusgainew/tunduck-app/pkg/validation"
 )
 
 type RoleController struct {
@@ -35,7 +36,7 @@ func NewRoleController(app *fiber.App, log *logrus.Logger, db *gorm.DB) {
 		db:          db,
 	}
 
-	controller.logger.Info(context.Background(), "RoleController инициализирован", logrus.Fields{})
+	controller.logger.Info(context.Background(), "RoleController инициализирован")
 	controller.registerRoutes(app)
 }
 
@@ -61,37 +62,33 @@ func (c *RoleController) registerRoutes(app *fiber.App) {
 
 // assignRole назначает роль пользователю (только для админов)
 func (c *RoleController) assignRole(ctx *fiber.Ctx) error {
-	c.logger.Info(ctx.Context(), "Назначение роли пользователю", logrus.Fields{})
+	c.logger.Info(ctx.Context(), "Назначение роли пользователю")
 
 	var req struct {
 		UserID uuid.UUID `json:"user_id" validate:"required"`
 		Role   string    `json:"role" validate:"required"`
 	}
 
-	if err := ctx.BodyParser(&req); err != nil {
-		c.logger.Warn(ctx.Context(), "Невалидное тело запроса", logrus.Fields{"error": err.Error()})
-		appErr := apperror.ValidationError("невалидное тело запроса")
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+	if appErr := validation.ParseBody(ctx, &req); appErr != nil {
+		c.logger.Warn(ctx.Context(), "Невалидное тело запроса", logrus.Fields{"error": appErr.Error()})
+		return response.Error(ctx, appErr)
 	}
 
 	// Валидируем роль
 	role := rbac.Role(req.Role)
 	if !role.IsValid() {
 		c.logger.Warn(ctx.Context(), "Невалидная роль", logrus.Fields{"role": req.Role})
-		appErr := apperror.ValidationError("невалидная роль: " + req.Role)
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		appErr := apperror.ValidationError("invalid role: {role}").WithParams(map[string]interface{}{"role": req.Role})
+		return response.Error(ctx, appErr)
 	}
 
 	if err := c.roleService.AssignRole(ctx.Context(), req.UserID, role); err != nil {
-		appErr, ok := err.(*apperror.AppError)
-		if !ok {
-			appErr = apperror.New(apperror.ErrInternal, "ошибка при назначении роли").WithError(err)
-		}
+		appErr := apperror.From(err, apperror.ErrInternal, "failed to assign role")
 		c.logger.Error(ctx.Context(), "Ошибка назначения роли", err, logrus.Fields{
 			"user_id": req.UserID.String(),
 			"role":    req.Role,
 		})
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
 	c.logger.Info(ctx.Context(), "Роль успешно назначена", logrus.Fields{
@@ -99,9 +96,7 @@ func (c *RoleController) assignRole(ctx *fiber.Ctx) error {
 		"role":    req.Role,
 	})
 
-	return ctx.Status(http.StatusOK).JSON(fiber.Map{
-		"success": true,
-		"message": "Роль успешно назначена",
+	return response.SuccessOK(ctx, "Роль успешно назначена", fiber.Map{
 		"user_id": req.UserID.String(),
 		"role":    req.Role,
 	})
@@ -117,38 +112,34 @@ func (c *RoleController) updateRole(ctx *fiber.Ctx) error {
 	userID, err := uuid.Parse(userIDParam)
 	if err != nil {
 		c.logger.Warn(ctx.Context(), "Невалидный формат UUID", logrus.Fields{"user_id": userIDParam})
-		appErr := apperror.ValidationError("невалидный формат UUID")
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		appErr := apperror.ValidationError("invalid UUID format")
+		return response.Error(ctx, appErr)
 	}
 
 	var req struct {
 		Role string `json:"role" validate:"required"`
 	}
 
-	if err := ctx.BodyParser(&req); err != nil {
-		c.logger.Warn(ctx.Context(), "Невалидное тело запроса", logrus.Fields{"error": err.Error()})
-		appErr := apperror.ValidationError("невалидное тело запроса")
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+	if appErr := validation.ParseBody(ctx, &req); appErr != nil {
+		c.logger.Warn(ctx.Context(), "Невалидное тело запроса", logrus.Fields{"error": appErr.Error()})
+		return response.Error(ctx, appErr)
 	}
 
 	// Валидируем роль
 	role := rbac.Role(req.Role)
 	if !role.IsValid() {
 		c.logger.Warn(ctx.Context(), "Невалидная роль", logrus.Fields{"role": req.Role})
-		appErr := apperror.ValidationError("невалидная роль: " + req.Role)
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		appErr := apperror.ValidationError("invalid role: {role}").WithParams(map[string]interface{}{"role": req.Role})
+		return response.Error(ctx, appErr)
 	}
 
 	if err := c.roleService.UpdateRole(ctx.Context(), userID, role); err != nil {
-		appErr, ok := err.(*apperror.AppError)
-		if !ok {
-			appErr = apperror.New(apperror.ErrInternal, "ошибка при обновлении роли").WithError(err)
-		}
+		appErr := apperror.From(err, apperror.ErrInternal, "failed to update role")
 		c.logger.Error(ctx.Context(), "Ошибка обновления роли", err, logrus.Fields{
 			"user_id": userID.String(),
 			"role":    req.Role,
 		})
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
 	c.logger.Info(ctx.Context(), "Роль успешно обновлена", logrus.Fields{
@@ -156,9 +147,7 @@ func (c *RoleController) updateRole(ctx *fiber.Ctx) error {
 		"role":    req.Role,
 	})
 
-	return ctx.Status(http.StatusOK).JSON(fiber.Map{
-		"success": true,
-		"message": "Роль успешно обновлена",
+	return response.SuccessOK(ctx, "Роль успешно обновлена", fiber.Map{
 		"user_id": userID.String(),
 		"role":    req.Role,
 	})
@@ -174,20 +163,17 @@ func (c *RoleController) getUserRole(ctx *fiber.Ctx) error {
 	userID, err := uuid.Parse(userIDParam)
 	if err != nil {
 		c.logger.Warn(ctx.Context(), "Невалидный формат UUID", logrus.Fields{"user_id": userIDParam})
-		appErr := apperror.ValidationError("невалидный формат UUID")
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		appErr := apperror.ValidationError("invalid UUID format")
+		return response.Error(ctx, appErr)
 	}
 
 	role, err := c.roleService.GetUserRole(ctx.Context(), userID)
 	if err != nil {
-		appErr, ok := err.(*apperror.AppError)
-		if !ok {
-			appErr = apperror.New(apperror.ErrInternal, "ошибка при получении роли").WithError(err)
-		}
+		appErr := apperror.From(err, apperror.ErrInternal, "failed to fetch role")
 		c.logger.Error(ctx.Context(), "Ошибка получения роли", err, logrus.Fields{
 			"user_id": userID.String(),
 		})
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
 	c.logger.Debug(ctx.Context(), "Роль успешно получена", logrus.Fields{
@@ -195,8 +181,7 @@ func (c *RoleController) getUserRole(ctx *fiber.Ctx) error {
 		"role":    role.String(),
 	})
 
-	return ctx.Status(http.StatusOK).JSON(fiber.Map{
-		"success": true,
+	return response.OK(ctx, fiber.Map{
 		"user_id": userID.String(),
 		"role":    role.String(),
 	})
@@ -212,20 +197,17 @@ func (c *RoleController) checkUserPermissions(ctx *fiber.Ctx) error {
 	userID, err := uuid.Parse(userIDParam)
 	if err != nil {
 		c.logger.Warn(ctx.Context(), "Невалидный формат UUID", logrus.Fields{"user_id": userIDParam})
-		appErr := apperror.ValidationError("невалидный формат UUID")
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		appErr := apperror.ValidationError("invalid UUID format")
+		return response.Error(ctx, appErr)
 	}
 
 	role, err := c.roleService.GetUserRole(ctx.Context(), userID)
 	if err != nil {
-		appErr, ok := err.(*apperror.AppError)
-		if !ok {
-			appErr = apperror.New(apperror.ErrInternal, "ошибка при получении роли").WithError(err)
-		}
+		appErr := apperror.From(err, apperror.ErrInternal, "failed to fetch role")
 		c.logger.Error(ctx.Context(), "Ошибка получения роли", err, logrus.Fields{
 			"user_id": userID.String(),
 		})
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
 	// Получаем все разрешения для роли
@@ -237,8 +219,7 @@ func (c *RoleController) checkUserPermissions(ctx *fiber.Ctx) error {
 		"permissions": len(permissions),
 	})
 
-	return ctx.Status(http.StatusOK).JSON(fiber.Map{
-		"success":     true,
+	return response.OK(ctx, fiber.Map{
 		"user_id":     userID.String(),
 		"role":        role.String(),
 		"permissions": permissions,
@@ -255,20 +236,17 @@ func (c *RoleController) listUsersByRole(ctx *fiber.Ctx) error {
 	role := rbac.Role(roleParam)
 	if !role.IsValid() {
 		c.logger.Warn(ctx.Context(), "Невалидная роль", logrus.Fields{"role": roleParam})
-		appErr := apperror.ValidationError("невалидная роль: " + roleParam)
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		appErr := apperror.ValidationError("invalid role: {role}").WithParams(map[string]interface{}{"role": roleParam})
+		return response.Error(ctx, appErr)
 	}
 
 	userIDs, err := c.roleService.ListUsersByRole(ctx.Context(), role)
 	if err != nil {
-		appErr, ok := err.(*apperror.AppError)
-		if !ok {
-			appErr = apperror.New(apperror.ErrInternal, "ошибка при получении списка пользователей").WithError(err)
-		}
+		appErr := apperror.From(err, apperror.ErrInternal, "failed to fetch users")
 		c.logger.Error(ctx.Context(), "Ошибка получения списка пользователей", err, logrus.Fields{
 			"role": role.String(),
 		})
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
 	c.logger.Debug(ctx.Context(), "Список пользователей успешно получен", logrus.Fields{
@@ -276,10 +254,8 @@ func (c *RoleController) listUsersByRole(ctx *fiber.Ctx) error {
 		"count": len(userIDs),
 	})
 
-	return ctx.Status(http.StatusOK).JSON(fiber.Map{
-		"success": true,
-		"role":    role.String(),
-		"users":   userIDs,
-		"count":   len(userIDs),
+	return response.List(ctx, userIDs, fiber.Map{
+		"role":  role.String(),
+		"count": len(userIDs),
 	})
 }
@@ -0,0 +1,136 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+	"github.com/rusgainew/tunduck-app/pkg/validation"
+)
+
+type SavedViewController struct {
+	logger *logger.Logger
+	views  services.SavedViewService
+}
+
+// NewSavedViewController регистрирует маршруты личных представлений списка документов
+func NewSavedViewController(app *fiber.App, log *logrus.Logger, views services.SavedViewService) {
+	controller := &SavedViewController{
+		logger: logger.New(log),
+		views:  views,
+	}
+
+	controller.logger.Info(context.Background(), "SavedViewController инициализирован")
+	controller.registerRoutes(app)
+}
+
+func (c *SavedViewController) registerRoutes(app *fiber.App) {
+	views := app.Group("/api/users/me/views")
+	views.Use(middleware.JWTMiddleware())
+	views.Get("/", c.listViews)
+	views.Post("/", c.createView)
+	views.Get("/:id", c.getView)
+	views.Put("/:id", c.updateView)
+	views.Delete("/:id", c.deleteView)
+}
+
+// viewTarget разбирает пользователя и ID представления из запроса
+func viewTarget(ctx *fiber.Ctx) (uuid.UUID, uuid.UUID, *apperror.AppError) {
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, apperror.From(err, apperror.ErrUnauthorized, "unauthorized")
+	}
+	id, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, apperror.New(apperror.ErrInvalidRequest, "invalid view ID format")
+	}
+	return userID, id, nil
+}
+
+// listViews возвращает представления текущего пользователя
+func (c *SavedViewController) listViews(ctx *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrUnauthorized, "unauthorized"))
+	}
+
+	views, err := c.views.List(ctx.Context(), userID)
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка получения представлений", err, logrus.Fields{"user_id": userID.String()})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to fetch views"))
+	}
+	return response.OK(ctx, views)
+}
+
+// createView сохраняет фильтры и сортировку под именем
+func (c *SavedViewController) createView(ctx *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromContext(ctx)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrUnauthorized, "unauthorized"))
+	}
+
+	var req models.SavedViewRequest
+	if appErr := validation.ParseBody(ctx, &req); appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	view, err := c.views.Create(ctx.Context(), userID, &req)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to save view"))
+	}
+	return response.SuccessCreated(ctx, "View saved", view)
+}
+
+// getView возвращает представление
+func (c *SavedViewController) getView(ctx *fiber.Ctx) error {
+	userID, id, appErr := viewTarget(ctx)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	view, err := c.views.Get(ctx.Context(), userID, id)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to fetch view"))
+	}
+	return response.OK(ctx, view)
+}
+
+// updateView заменяет представление
+func (c *SavedViewController) updateView(ctx *fiber.Ctx) error {
+	userID, id, appErr := viewTarget(ctx)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	var req models.SavedViewRequest
+	if appErr := validation.ParseBody(ctx, &req); appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	view, err := c.views.Update(ctx.Context(), userID, id, &req)
+	if err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to update view"))
+	}
+	return response.OK(ctx, view)
+}
+
+// deleteView удаляет представление
+func (c *SavedViewController) deleteView(ctx *fiber.Ctx) error {
+	userID, id, appErr := viewTarget(ctx)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	if err := c.views.Delete(ctx.Context(), userID, id); err != nil {
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to delete view"))
+	}
+	return response.SuccessOK(ctx, "View deleted", nil)
+}
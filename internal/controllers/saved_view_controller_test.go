@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+	"github.com/rusgainew/tunduck-app/pkg/testutil"
+)
+
+// stubSavedViewService хранит представления одного пользователя в памяти
+type stubSavedViewService struct {
+	services.SavedViewService
+	userID uuid.UUID
+	views  map[uuid.UUID]*services.SavedView
+}
+
+func (s *stubSavedViewService) Create(ctx context.Context, userID uuid.UUID, req *models.SavedViewRequest) (*services.SavedView, error) {
+	view := &services.SavedView{ID: uuid.New(), Name: req.Name, Filter: req.Filter, Sort: req.Sort, Order: req.Order}
+	s.views[view.ID] = view
+	return view, nil
+}
+
+func (s *stubSavedViewService) Get(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*services.SavedView, error) {
+	if view, ok := s.views[id]; ok && userID == s.userID {
+		return view, nil
+	}
+	return nil, apperror.NotFoundError("view")
+}
+
+// stubListService запоминает параметры, с которыми запрошен список документов
+type stubListService struct {
+	stubDocumentService
+	params  pagination.PaginationParams
+	filters pagination.DocumentFilterParams
+}
+
+func (s *stubListService) GetAllDocumentsPaginated(ctx context.Context, orgID uuid.UUID, params pagination.PaginationParams, filters pagination.DocumentFilterParams) ([]models.EsfCreateDocumentRequest, int64, error) {
+	s.params, s.filters = params, filters
+	return []models.EsfCreateDocumentRequest{}, 0, nil
+}
+
+func TestSavedViewController_ApplyView(t *testing.T) {
+	h := testutil.NewHarness(t)
+	user, stranger := testutil.NewUser(), testutil.NewUser()
+	views := &stubSavedViewService{userID: user.ID, views: map[uuid.UUID]*services.SavedView{}}
+	docs := &stubListService{stubDocumentService: stubDocumentService{orgID: uuid.New()}}
+	NewSavedViewController(h.App, h.Logger, views)
+	NewEsfDocumentController(h.App, h.Logger, docs, nil, views, nil)
+	token := testutil.WithToken(h.Token(user.ID.String(), user.Email))
+	org := testutil.WithHeader("X-Org-Id", docs.orgID.String())
+
+	resp := h.Do(http.MethodPost, "/api/users/me/views", fiber.Map{"name": "Unpaid", "order": "sideways"}, token)
+	assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode, "order must be asc or desc")
+
+	resp = h.Do(http.MethodPost, "/api/users/me/views", fiber.Map{
+		"name":   "Unpaid over 30 days",
+		"filter": fiber.Map{"payment": "unpaid", "delivery_before": "-30d", "search": "cement"},
+		"sort":   "delivery_date",
+		"order":  "asc",
+	}, token)
+	require.Equal(t, fiber.StatusCreated, resp.StatusCode, string(resp.Body))
+	var view services.SavedView
+	resp.DecodeData(&view)
+	path := "/api/esf-documents/paginated?view=" + view.ID.String()
+
+	resp = h.Do(http.MethodGet, path, nil, org)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode, "views are personal")
+
+	resp = h.Do(http.MethodGet, path, nil, org, testutil.WithToken(h.Token(stranger.ID.String(), stranger.Email)))
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+
+	resp = h.Do(http.MethodGet, path+"&search=steel", nil, org, token)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+	assert.Equal(t, "unpaid", docs.filters.Payment)
+	assert.Equal(t, "steel", docs.filters.Search, "query parameters override the view")
+	assert.Equal(t, time.Now().AddDate(0, 0, -30).Format("2006-01-02"), docs.filters.DeliveryBefore)
+	assert.Equal(t, "delivery_date", docs.params.Sort)
+	assert.Equal(t, "asc", docs.params.Order)
+}
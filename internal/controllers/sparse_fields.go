@@ -0,0 +1,25 @@
+package controllers
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+)
+
+// documentFields поля документа для параметра fields; status и amount — короткие имена,
+// как в filter и sort
+var documentFields = response.NewFieldSet(documentResource{}, map[string]string{
+	"status": "esfStatus",
+	"amount": "totalCurrencyValue",
+}, "id")
+
+// organizationFields поля организации для параметра fields
+var organizationFields = response.NewFieldSet(models.EsfOrganizationModel{}, nil, "id")
+
+// requestedFields ключи ответа из параметра fields (fields=id,status,amount); nil — полный ответ
+func requestedFields(ctx *fiber.Ctx, set response.FieldSet) ([]string, *apperror.AppError) {
+	return set.Resolve(pagination.SplitList(ctx.Query("fields")))
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/testutil"
+)
+
+func TestEsfDocumentController_SparseFields(t *testing.T) {
+	h := testutil.NewHarness(t)
+	orgID, docID := uuid.New(), uuid.New()
+	docs := &stubDocumentService{orgID: orgID, docs: map[uuid.UUID]*models.EsfCreateDocumentRequest{
+		docID: testutil.NewDocumentRequest(func(d *models.EsfCreateDocumentRequest) {
+			d.ID, d.EsfStatus, d.TotalCurrencyValue = docID, entity.EsfStatusSent, 1500
+		}),
+	}}
+	NewEsfDocumentController(h.App, h.Logger, docs, nil, nil, nil)
+	org := testutil.WithHeader("X-Org-Id", orgID.String())
+
+	resp := h.Do(http.MethodGet, "/api/esf-documents/paginated?fields=status,amount,delivery_date", nil, org)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+	var list []map[string]interface{}
+	resp.DecodeData(&list)
+	require.Len(t, list, 1)
+	assert.Equal(t, map[string]interface{}{
+		"id":                 docID.String(),
+		"esfStatus":          entity.EsfStatusSent,
+		"totalCurrencyValue": 1500.0,
+		"deliveryDate":       list[0]["deliveryDate"],
+	}, list[0])
+
+	resp = h.Do(http.MethodGet, "/api/esf-documents/"+docID.String()+"?fields=_links", nil, org)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+	var item map[string]interface{}
+	resp.DecodeData(&item)
+	assert.Len(t, item, 2)
+	assert.Contains(t, item, "_links")
+
+	// Без fields ответ полный
+	resp = h.Do(http.MethodGet, "/api/esf-documents/"+docID.String(), nil, org)
+	resp.DecodeData(&item)
+	assert.Contains(t, item, "contractorTin")
+
+	resp = h.Do(http.MethodGet, "/api/esf-documents/paginated?fields=id,password", nil, org)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, string(apperror.ErrValidation), resp.ErrorCode())
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/health"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+)
+
+type TenantHealthController struct {
+	logger  *logger.Logger
+	monitor *health.TenantMonitor
+}
+
+// NewTenantHealthController регистрирует маршрут администратора с результатами проверки БД организаций
+func NewTenantHealthController(app *fiber.App, log *logrus.Logger, monitor *health.TenantMonitor) {
+	controller := &TenantHealthController{
+		logger:  logger.New(log),
+		monitor: monitor,
+	}
+
+	controller.logger.Info(context.Background(), "TenantHealthController инициализирован")
+	controller.registerRoutes(app)
+}
+
+func (c *TenantHealthController) registerRoutes(app *fiber.App) {
+	admin := app.Group("/api/admin/tenant-health", middleware.JWTMiddleware(), rbac.RequireAdminRole())
+	admin.Get("/", c.listStatuses)
+}
+
+// listStatuses возвращает последние результаты проверки, недоступные БД первыми;
+// ?flagged=true оставляет только недоступные
+func (c *TenantHealthController) listStatuses(ctx *fiber.Ctx) error {
+	if c.monitor == nil {
+		return response.Error(ctx, apperror.New(apperror.ErrConfigError, "tenant database health monitoring is disabled"))
+	}
+
+	statuses := c.monitor.Statuses()
+	if ctx.QueryBool("flagged", false) {
+		flagged := make([]health.TenantStatus, 0, len(statuses))
+		for _, status := range statuses {
+			if status.Flagged {
+				flagged = append(flagged, status)
+			}
+		}
+		statuses = flagged
+	}
+	return response.OK(ctx, statuses)
+}
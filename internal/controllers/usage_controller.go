@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+)
+
+type UsageController struct {
+	logger *logger.Logger
+	usage  services.UsageService
+}
+
+// NewUsageController регистрирует маршруты отчетов о потреблении: по организации
+// и выгрузку по всем организациям для выставления счетов (только администраторы)
+func NewUsageController(app *fiber.App, log *logrus.Logger, usage services.UsageService) {
+	controller := &UsageController{
+		logger: logger.New(log),
+		usage:  usage,
+	}
+
+	controller.logger.Info(context.Background(), "UsageController инициализирован")
+	controller.registerRoutes(app)
+}
+
+func (c *UsageController) registerRoutes(app *fiber.App) {
+	app.Get("/api/organizations/:id/usage", middleware.JWTMiddleware(), c.getUsage)
+	app.Get("/api/admin/usage/export", middleware.JWTMiddleware(), rbac.RequireAdminRole(), c.exportUsage)
+}
+
+// getUsage возвращает потребление организации в JSON или выгрузку при format=csv|ndjson
+func (c *UsageController) getUsage(ctx *fiber.Ctx) error {
+	orgID, err := uuid.Parse(ctx.Params("id"))
+	if err != nil {
+		return response.Error(ctx, apperror.New(apperror.ErrInvalidRequest, "invalid organization ID"))
+	}
+	query, appErr := parseUsageQuery(ctx, services.UsageGranularityDay)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+
+	format := ctx.Query("format", "json")
+	if format != "json" {
+		return c.writeExport(ctx, orgID, query, format, "usage-"+orgID.String())
+	}
+
+	report, err := c.usage.Report(ctx.Context(), orgID, query)
+	if err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка получения отчета о потреблении", err, logrus.Fields{"org_id": orgID.String()})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to fetch usage"))
+	}
+	return response.OK(ctx, report)
+}
+
+// exportUsage выгружает потребление всех организаций, по умолчанию в CSV помесячно
+func (c *UsageController) exportUsage(ctx *fiber.Ctx) error {
+	query, appErr := parseUsageQuery(ctx, services.UsageGranularityMonth)
+	if appErr != nil {
+		return response.Error(ctx, appErr)
+	}
+	return c.writeExport(ctx, uuid.Nil, query, ctx.Query("format", services.ExportFormatCSV), "usage")
+}
+
+// writeExport отдает выгрузку файлом. Выгрузка собирается в памяти до ответа, чтобы
+// ошибки проверки периода вернулись обычным ответом с ошибкой, а не оборванным файлом.
+func (c *UsageController) writeExport(ctx *fiber.Ctx, orgID uuid.UUID, query services.UsageQuery, format, filename string) error {
+	contentType := "text/csv; charset=utf-8"
+	switch format {
+	case services.ExportFormatCSV:
+	case services.ExportFormatNDJSON:
+		contentType = "application/x-ndjson"
+	default:
+		return response.Error(ctx, apperror.ValidationError("invalid export format"))
+	}
+
+	var buf bytes.Buffer
+	if err := c.usage.Export(ctx.Context(), orgID, query, format, &buf); err != nil {
+		c.logger.Error(ctx.Context(), "Ошибка выгрузки потребления", err, logrus.Fields{"format": format})
+		return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to export usage"))
+	}
+
+	ctx.Set(fiber.HeaderContentType, contentType)
+	ctx.Set(fiber.HeaderContentDisposition, `attachment; filename="`+filename+"."+format+`"`)
+	return ctx.Send(buf.Bytes())
+}
+
+// parseUsageQuery читает период из query: from и to (YYYY-MM-DD включительно, по UTC; по умолчанию
+// текущий месяц по сегодняшний день) и granularity (hour, day или month)
+func parseUsageQuery(ctx *fiber.Ctx, granularity string) (services.UsageQuery, *apperror.AppError) {
+	now := time.Now().UTC()
+	query := services.UsageQuery{
+		From:        time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(now.Year(), now.Month(), now.Day()+1, 0, 0, 0, 0, time.UTC),
+		Granularity: ctx.Query("granularity", granularity),
+	}
+	if raw := ctx.Query("from"); raw != "" {
+		from, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			return query, apperror.ValidationError("from must use YYYY-MM-DD date")
+		}
+		query.From = from
+	}
+	if raw := ctx.Query("to"); raw != "" {
+		to, err := time.Parse(time.DateOnly, raw)
+		if err != nil {
+			return query, apperror.ValidationError("to must use YYYY-MM-DD date")
+		}
+		query.To = to.AddDate(0, 0, 1)
+	}
+	return query, nil
+}
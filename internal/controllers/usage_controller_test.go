@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/testutil"
+)
+
+// stubUsageService запоминает последний запрошенный период
+type stubUsageService struct {
+	query services.UsageQuery
+}
+
+func (s *stubUsageService) Report(ctx context.Context, orgID uuid.UUID, query services.UsageQuery) (*services.UsageReport, error) {
+	s.query = query
+	return &services.UsageReport{OrganizationID: orgID, From: query.From, To: query.To, Granularity: query.Granularity}, nil
+}
+
+func (s *stubUsageService) Export(ctx context.Context, orgID uuid.UUID, query services.UsageQuery, format string, w io.Writer) error {
+	s.query = query
+	_, err := io.WriteString(w, "organization_id,organization_name,period_start,metric,quantity\n")
+	return err
+}
+
+func TestUsageController_GetUsage(t *testing.T) {
+	h := testutil.NewHarness(t)
+	usage := &stubUsageService{}
+	NewUsageController(h.App, h.Logger, usage)
+
+	user := testutil.NewUser()
+	token := testutil.WithToken(h.Token(user.ID.String(), user.Email))
+	orgID := uuid.New()
+	path := "/api/organizations/" + orgID.String() + "/usage"
+
+	resp := h.Do(http.MethodGet, path+"?from=2026-03-01&to=2026-03-31&granularity=hour", nil, token)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+	var report services.UsageReport
+	resp.DecodeData(&report)
+	assert.Equal(t, orgID, report.OrganizationID)
+	assert.Equal(t, services.UsageQuery{
+		From:        time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC),
+		Granularity: services.UsageGranularityHour,
+	}, usage.query, "to is inclusive")
+
+	resp = h.Do(http.MethodGet, path+"?format=csv", nil, token)
+	require.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+	assert.Equal(t, `attachment; filename="usage-`+orgID.String()+`.csv"`, resp.Header.Get(fiber.HeaderContentDisposition))
+	assert.Equal(t, services.UsageGranularityDay, usage.query.Granularity)
+
+	resp = h.Do(http.MethodGet, path+"?from=March", nil, token)
+	assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, string(apperror.ErrValidation), resp.ErrorCode())
+
+	resp = h.Do(http.MethodGet, path, nil)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+}
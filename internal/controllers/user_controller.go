@@ -2,29 +2,34 @@ package controllers
 
 import (
 	"context"
-	"net/http"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	repositorypostgres "github.com/rusgainew/tunduck-app/internal/repository/repository_postgres"
 	"github.com/rusgainew/tunduck-app/pkg/apperror"
 	"github.com/rusgainew/tunduck-app/pkg/entity"
 	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+	"github.com/rusgainew/tunduck-app/pkg/response"
 )
 
 type UserController struct {
-	logger *logger.Logger
-	db     *gorm.DB
+	logger   *logger.Logger
+	db       *gorm.DB
+	userRepo repository.UserRepository
 }
 
 func NewUserController(app *fiber.App, log *logrus.Logger, db *gorm.DB) {
 	controller := &UserController{
-		logger: logger.New(log),
-		db:     db,
+		logger:   logger.New(log),
+		db:       db,
+		userRepo: repositorypostgres.NewUserRepositoryPostgres(db, log),
 	}
 
-	controller.logger.Info(context.Background(), "UserController initialized", logrus.Fields{})
+	controller.logger.Info(context.Background(), "UserController initialized")
 	controller.registerRoutes(app)
 }
 
@@ -33,12 +38,13 @@ func (c *UserController) registerRoutes(app *fiber.App) {
 
 	// Публичные routes (без JWT)
 	userGroup.Get("/", c.getAllUsers)
+	userGroup.Get("/cursor", c.getUsersCursor)
 	userGroup.Get("/:id", c.getUserByID)
 }
 
 // getAllUsers возвращает всех пользователей с пагинацией
 func (c *UserController) getAllUsers(ctx *fiber.Ctx) error {
-	c.logger.Info(ctx.Context(), "Fetching all users", logrus.Fields{})
+	c.logger.Info(ctx.Context(), "Fetching all users")
 
 	page := ctx.QueryInt("page", 1)
 	limit := ctx.QueryInt("limit", 10)
@@ -57,24 +63,36 @@ func (c *UserController) getAllUsers(ctx *fiber.Ctx) error {
 
 	// Получаем общее количество пользователей
 	if err := c.db.Model(&entity.User{}).Count(&total).Error; err != nil {
-		c.logger.Error(ctx.Context(), "Failed to count users", err, logrus.Fields{})
+		c.logger.Error(ctx.Context(), "Failed to count users", err)
 		appErr := apperror.New(apperror.ErrInternal, "failed to count users").WithError(err)
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
 	// Получаем пользователей с пагинацией
 	if err := c.db.Offset(offset).Limit(limit).Find(&users).Error; err != nil {
-		c.logger.Error(ctx.Context(), "Failed to fetch users", err, logrus.Fields{})
+		c.logger.Error(ctx.Context(), "Failed to fetch users", err)
 		appErr := apperror.New(apperror.ErrInternal, "failed to fetch users").WithError(err)
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
-	return ctx.Status(http.StatusOK).JSON(fiber.Map{
-		"data":  users,
-		"total": total,
-		"page":  page,
-		"limit": limit,
-	})
+	return response.List(ctx, users, pagination.NewPaginationInfo(page, limit, total))
+}
+
+// getUsersCursor возвращает пользователей с курсорной пагинацией
+func (c *UserController) getUsersCursor(ctx *fiber.Ctx) error {
+	c.logger.Info(ctx.Context(), "Fetching users by cursor")
+
+	cursorParams := pagination.ExtractCursorParams(ctx, "created_at", "username", "email")
+	filterParams := pagination.ExtractUserFilters(ctx)
+
+	users, info, err := c.userRepo.GetAllCursor(ctx.Context(), cursorParams, filterParams)
+	if err != nil {
+		appErr := apperror.From(err, apperror.ErrInternal, "failed to fetch users")
+		c.logger.Error(ctx.Context(), "Failed to fetch users by cursor", err)
+		return response.Error(ctx, appErr)
+	}
+
+	return response.List(ctx, users, info)
 }
 
 // getUserByID возвращает пользователя по ID
@@ -86,12 +104,12 @@ func (c *UserController) getUserByID(ctx *fiber.Ctx) error {
 	if err := c.db.Where("id = ?", id).First(&user).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			appErr := apperror.New(apperror.ErrNotFound, "user not found")
-			return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+			return response.Error(ctx, appErr)
 		}
 		c.logger.Error(ctx.Context(), "Failed to fetch user", err, logrus.Fields{"id": id})
 		appErr := apperror.New(apperror.ErrInternal, "failed to fetch user").WithError(err)
-		return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+		return response.Error(ctx, appErr)
 	}
 
-	return ctx.Status(http.StatusOK).JSON(user)
+	return response.OK(ctx, user)
 }
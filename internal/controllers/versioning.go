@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+)
+
+// expectedVersion возвращает ожидаемую версию записи для оптимистичной блокировки.
+// Заголовок If-Match ("3" или W/"3") имеет приоритет над полем version в теле запроса.
+func expectedVersion(ctx *fiber.Ctx, bodyVersion int64) (int64, *apperror.AppError) {
+	if header := strings.TrimSpace(ctx.Get(fiber.HeaderIfMatch)); header != "" {
+		raw := strings.Trim(strings.TrimPrefix(header, "W/"), `"`)
+		version, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || version <= 0 {
+			return 0, apperror.ValidationError("invalid If-Match header")
+		}
+		return version, nil
+	}
+
+	if bodyVersion > 0 {
+		return bodyVersion, nil
+	}
+
+	return 0, apperror.New(apperror.ErrPreconditionRequired, "record version is required: send If-Match header or version field")
+}
+
+// setETag отдает версию записи в заголовке ETag для последующего If-Match
+func setETag(ctx *fiber.Ctx, version int64) {
+	if version > 0 {
+		ctx.Set(fiber.HeaderETag, `"`+strconv.FormatInt(version, 10)+`"`)
+	}
+}
@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+)
+
+// resolveVersion выполняет expectedVersion в контексте запроса с заданным If-Match
+func resolveVersion(t *testing.T, ifMatch string, bodyVersion int64) (int64, *apperror.AppError) {
+	t.Helper()
+
+	var (
+		version int64
+		appErr  *apperror.AppError
+	)
+	app := fiber.New()
+	app.Put("/", func(ctx *fiber.Ctx) error {
+		version, appErr = expectedVersion(ctx, bodyVersion)
+		setETag(ctx, version)
+		return nil
+	})
+
+	req := httptest.NewRequest(fiber.MethodPut, "/", nil)
+	if ifMatch != "" {
+		req.Header.Set(fiber.HeaderIfMatch, ifMatch)
+	}
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	if appErr == nil {
+		assert.Equal(t, `"`+strconv.FormatInt(version, 10)+`"`, resp.Header.Get(fiber.HeaderETag))
+	}
+	return version, appErr
+}
+
+func TestExpectedVersion(t *testing.T) {
+	t.Run("If-Match overrides body", func(t *testing.T) {
+		version, appErr := resolveVersion(t, `"3"`, 1)
+		assert.Nil(t, appErr)
+		assert.Equal(t, int64(3), version)
+	})
+
+	t.Run("weak ETag", func(t *testing.T) {
+		version, appErr := resolveVersion(t, `W/"2"`, 0)
+		assert.Nil(t, appErr)
+		assert.Equal(t, int64(2), version)
+	})
+
+	t.Run("body version", func(t *testing.T) {
+		version, appErr := resolveVersion(t, "", 5)
+		assert.Nil(t, appErr)
+		assert.Equal(t, int64(5), version)
+	})
+
+	t.Run("missing version", func(t *testing.T) {
+		_, appErr := resolveVersion(t, "", 0)
+		require.NotNil(t, appErr)
+		assert.Equal(t, apperror.ErrPreconditionRequired, appErr.Code)
+		assert.Equal(t, fiber.StatusPreconditionRequired, appErr.HTTPStatus)
+	})
+
+	t.Run("invalid If-Match", func(t *testing.T) {
+		_, appErr := resolveVersion(t, `"abc"`, 0)
+		require.NotNil(t, appErr)
+		assert.Equal(t, apperror.ErrValidation, appErr.Code)
+	})
+}
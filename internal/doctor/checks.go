@@ -0,0 +1,168 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"github.com/rusgainew/tunduck-app/pkg/esfgateway"
+	"github.com/rusgainew/tunduck-app/pkg/mail"
+	"github.com/rusgainew/tunduck-app/pkg/storage"
+)
+
+// ProbePrefix префикс ключа пробного объекта, который проверка хранилища записывает и удаляет
+const ProbePrefix = "doctor/"
+
+// Postgres подключается к БД по dsn и возвращает версию сервера
+func Postgres(dsn string) Check {
+	return Check{Name: "postgres", Run: func(ctx context.Context) (string, error) {
+		db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{DisableAutomaticPing: true, Logger: gormlogger.Discard})
+		if err != nil {
+			return "", err
+		}
+		sqlDB, err := db.DB()
+		if err != nil {
+			return "", err
+		}
+		defer sqlDB.Close()
+
+		var version string
+		if err := sqlDB.QueryRowContext(ctx, "SHOW server_version").Scan(&version); err != nil {
+			return "", err
+		}
+		var database string
+		if err := sqlDB.QueryRowContext(ctx, "SELECT current_database()").Scan(&database); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("PostgreSQL %s, database %s", version, database), nil
+	}}
+}
+
+// Redis проверяет соединение командой PING и возвращает версию сервера. Подключение и команды
+// не повторяются, чтобы вместо таймаута была видна причина ошибки.
+func Redis(opts *redis.Options) Check {
+	return Check{Name: "redis", Run: func(ctx context.Context) (string, error) {
+		opts := *opts
+		opts.DialerRetries = 1
+		opts.MaxRetries = -1
+		client := redis.NewClient(&opts)
+		defer client.Close()
+
+		if err := client.Ping(ctx).Err(); err != nil {
+			return "", err
+		}
+		info, err := client.Info(ctx, "server").Result()
+		if err != nil {
+			return "", err
+		}
+		detail := client.Options().Addr
+		for _, line := range strings.Split(info, "\n") {
+			if version, ok := strings.CutPrefix(strings.TrimSpace(line), "redis_version:"); ok {
+				detail = fmt.Sprintf("Redis %s at %s", version, detail)
+				break
+			}
+		}
+		return detail, nil
+	}}
+}
+
+// Storage записывает, читает метаданные и удаляет пробный объект, проверяя права на запись
+func Storage(cfg storage.Config) Check {
+	return Check{Name: "storage", Run: func(ctx context.Context) (string, error) {
+		store, err := storage.New(cfg)
+		if err != nil {
+			return "", err
+		}
+
+		host, _ := os.Hostname()
+		key := fmt.Sprintf("%s%s-%d.txt", ProbePrefix, host, time.Now().UnixNano())
+		body := []byte("tunduck doctor probe\n")
+		if err := store.Put(ctx, key, bytes.NewReader(body), int64(len(body)), storage.PutOptions{ContentType: "text/plain"}); err != nil {
+			return "", fmt.Errorf("write: %w", err)
+		}
+		if _, err := store.Stat(ctx, key); err != nil {
+			return "", fmt.Errorf("read: %w", err)
+		}
+		if err := store.Delete(ctx, key); err != nil {
+			return "", fmt.Errorf("delete: %w", err)
+		}
+		return storageDetail(cfg), nil
+	}}
+}
+
+func storageDetail(cfg storage.Config) string {
+	if cfg.Backend == storage.BackendS3 {
+		endpoint := cfg.Endpoint
+		if endpoint == "" {
+			endpoint = "AWS " + cfg.Region
+		}
+		return fmt.Sprintf("s3 bucket %s at %s", cfg.Bucket, endpoint)
+	}
+	dir := cfg.LocalPath
+	if dir == "" {
+		dir = storage.DefaultLocalPath
+	}
+	return "local " + dir
+}
+
+// SMTP подключается к SMTP-серверу и проходит аутентификацию, не отправляя писем
+func SMTP(cfg mail.Config) Check {
+	return Check{Name: "smtp", Run: func(ctx context.Context) (string, error) {
+		if !cfg.Enabled() {
+			return "", Skip("SMTP_HOST is not set, emails are written to the log")
+		}
+		if err := mail.NewSMTPSender(cfg).Check(ctx); err != nil {
+			return "", err
+		}
+		port := cfg.Port
+		if port == 0 {
+			port = mail.DefaultPort
+		}
+		tls := cfg.TLS
+		if tls == "" {
+			tls = mail.TLSStartTLS
+		}
+		return fmt.Sprintf("%s (%s)", net.JoinHostPort(cfg.Host, strconv.Itoa(port)), tls), nil
+	}}
+}
+
+// ESFGateway запрашивает справочник у каждого адреса шлюза; справочники публичные, токен организации не нужен
+func ESFGateway(cfg esfgateway.Config) []Check {
+	endpoints := cfg.Endpoints
+	if len(endpoints) == 0 && cfg.URL != "" {
+		endpoints = []esfgateway.Endpoint{{Name: esfgateway.PrimaryEndpoint, URL: cfg.URL}}
+	}
+	if cfg.Backend == esfgateway.BackendMock || (cfg.Backend == "" && len(endpoints) == 0) {
+		return []Check{{Name: "esf gateway", Run: func(context.Context) (string, error) {
+			return "", Skip("mock backend, documents are not sent to the tax service")
+		}}}
+	}
+	if len(endpoints) == 0 {
+		return []Check{{Name: "esf gateway", Run: func(context.Context) (string, error) {
+			return "", fmt.Errorf("ESF_GATEWAY_URL or ESF_GATEWAY_ENDPOINTS is required for the %s backend", cfg.Backend)
+		}}}
+	}
+
+	directory := esfgateway.Directories[0]
+	checks := make([]Check, 0, len(endpoints))
+	for _, ep := range endpoints {
+		checks = append(checks, Check{Name: "esf gateway " + ep.Name, Run: func(ctx context.Context) (string, error) {
+			entries, err := esfgateway.NewClient(ep.URL, cfg.Timeout).GetDirectory(ctx, directory)
+			if err != nil {
+				return "", err
+			}
+			return fmt.Sprintf("%s, %d entries in %s", ep.URL, len(entries), directory), nil
+		}})
+	}
+	return checks
+}
@@ -0,0 +1,129 @@
+// Package doctor проверяет доступность внешних зависимостей с текущей конфигурацией: PostgreSQL,
+// Redis, объектного хранилища, SMTP-сервера и шлюза ЭСФ (команда doctor).
+//
+// Проверки выполняются по очереди, каждая со своим таймаутом, и не останавливаются на первой
+// ошибке, чтобы при развертывании сразу были видны все недоступные зависимости. Проверка
+// ненастроенной зависимости возвращает Skip и не считается ошибкой.
+package doctor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// DefaultTimeout таймаут одной проверки
+const DefaultTimeout = 10 * time.Second
+
+// Status результат проверки
+type Status string
+
+const (
+	StatusOK   Status = "OK"
+	StatusFail Status = "FAIL"
+	StatusSkip Status = "SKIP"
+)
+
+// Check проверка одной зависимости; Run возвращает краткое описание (версию, адрес) или ошибку
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) (string, error)
+}
+
+// Result результат проверки
+type Result struct {
+	Name     string
+	Status   Status
+	Duration time.Duration
+	Detail   string
+}
+
+type skipError struct {
+	reason string
+}
+
+func (e *skipError) Error() string {
+	return e.reason
+}
+
+// Skip возвращается из Check.Run, если зависимость не настроена
+func Skip(reason string) error {
+	return &skipError{reason: reason}
+}
+
+// Run выполняет проверки по очереди; timeout <= 0 — DefaultTimeout
+func Run(ctx context.Context, checks []Check, timeout time.Duration) []Result {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	results := make([]Result, 0, len(checks))
+	for _, check := range checks {
+		results = append(results, run(ctx, check, timeout))
+	}
+	return results
+}
+
+func run(ctx context.Context, check Check, timeout time.Duration) Result {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	start := time.Now()
+	detail, err := check.Run(ctx)
+	result := Result{Name: check.Name, Status: StatusOK, Duration: time.Since(start), Detail: detail}
+
+	var skip *skipError
+	switch {
+	case errors.As(err, &skip):
+		result.Status = StatusSkip
+		result.Detail = skip.reason
+	case err != nil:
+		result.Status = StatusFail
+		result.Detail = err.Error()
+		if errors.Is(err, context.DeadlineExceeded) || ctx.Err() != nil {
+			result.Detail = fmt.Sprintf("no answer within %s: %v", timeout, err)
+		}
+	}
+	return result
+}
+
+// Failed число проверок с ошибкой
+func Failed(results []Result) int {
+	failed := 0
+	for _, r := range results {
+		if r.Status == StatusFail {
+			failed++
+		}
+	}
+	return failed
+}
+
+// Write печатает результаты таблицей
+func Write(w io.Writer, results []Result) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CHECK\tSTATUS\tTIME\tDETAILS")
+	for _, r := range results {
+		elapsed := "-"
+		if r.Status != StatusSkip {
+			elapsed = r.Duration.Round(time.Millisecond).String()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Name, r.Status, elapsed, strings.ReplaceAll(r.Detail, "\n", " "))
+	}
+	tw.Flush()
+
+	skipped := 0
+	for _, r := range results {
+		if r.Status == StatusSkip {
+			skipped++
+		}
+	}
+	if failed := Failed(results); failed > 0 {
+		fmt.Fprintf(w, "\n%d of %d checks failed\n", failed, len(results))
+	} else {
+		fmt.Fprintf(w, "\nAll checks passed (%d skipped)\n", skipped)
+	}
+}
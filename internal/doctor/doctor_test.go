@@ -0,0 +1,87 @@
+package doctor
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/pkg/esfgateway"
+	"github.com/rusgainew/tunduck-app/pkg/mail"
+	"github.com/rusgainew/tunduck-app/pkg/storage"
+)
+
+func TestRun(t *testing.T) {
+	checks := []Check{
+		{Name: "ok", Run: func(context.Context) (string, error) { return "v1.0", nil }},
+		{Name: "skipped", Run: func(context.Context) (string, error) { return "", Skip("not configured") }},
+		{Name: "failed", Run: func(context.Context) (string, error) { return "", errors.New("connection refused") }},
+		{Name: "hanging", Run: func(ctx context.Context) (string, error) {
+			<-ctx.Done()
+			return "", ctx.Err()
+		}},
+	}
+
+	results := Run(context.Background(), checks, 20*time.Millisecond)
+	require.Len(t, results, 4)
+	assert.Equal(t, Result{Name: "ok", Status: StatusOK, Duration: results[0].Duration, Detail: "v1.0"}, results[0])
+	assert.Equal(t, StatusSkip, results[1].Status)
+	assert.Equal(t, "not configured", results[1].Detail)
+	assert.Equal(t, StatusFail, results[2].Status)
+	assert.Equal(t, "connection refused", results[2].Detail)
+	// Зависшая проверка не задерживает остальные дольше таймаута
+	assert.Equal(t, StatusFail, results[3].Status)
+	assert.Contains(t, results[3].Detail, "no answer within 20ms")
+	assert.Equal(t, 2, Failed(results))
+
+	var out strings.Builder
+	Write(&out, results)
+	assert.Contains(t, out.String(), "CHECK")
+	assert.Contains(t, out.String(), "skipped  SKIP    -")
+	assert.Contains(t, out.String(), "2 of 4 checks failed")
+
+	out.Reset()
+	Write(&out, results[:2])
+	assert.Contains(t, out.String(), "All checks passed (1 skipped)")
+}
+
+func TestStorage(t *testing.T) {
+	dir := t.TempDir()
+	result := Run(context.Background(), []Check{Storage(storage.Config{Backend: storage.BackendLocal, LocalPath: dir})}, 0)[0]
+	assert.Equal(t, StatusOK, result.Status, result.Detail)
+	assert.Equal(t, "local "+dir, result.Detail)
+
+	// Пробный объект удаляется
+	entries, err := os.ReadDir(filepath.Join(dir, strings.TrimSuffix(ProbePrefix, "/")))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestESFGateway(t *testing.T) {
+	srv := httptest.NewServer(esfgateway.NewMockHandler(esfgateway.NewMock(esfgateway.MockConfig{})))
+	defer srv.Close()
+
+	checks := ESFGateway(esfgateway.Config{Endpoints: []esfgateway.Endpoint{{Name: "main", URL: srv.URL}, {Name: "reserve", URL: "http://127.0.0.1:1"}}})
+	require.Len(t, checks, 2)
+	results := Run(context.Background(), checks, time.Second)
+	assert.Equal(t, "esf gateway main", results[0].Name)
+	assert.Equal(t, StatusOK, results[0].Status, results[0].Detail)
+	assert.Contains(t, results[0].Detail, srv.URL)
+	assert.Equal(t, StatusFail, results[1].Status)
+
+	results = Run(context.Background(), ESFGateway(esfgateway.Config{}), time.Second)
+	require.Len(t, results, 1)
+	assert.Equal(t, StatusSkip, results[0].Status)
+}
+
+func TestSMTP_NotConfigured(t *testing.T) {
+	result := Run(context.Background(), []Check{SMTP(mail.Config{})}, 0)[0]
+	assert.Equal(t, StatusSkip, result.Status)
+}
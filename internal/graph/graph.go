@@ -0,0 +1,39 @@
+// Package graph содержит схему GraphQL (schema.graphqls) и исполняемую схему, сгенерированную
+// gqlgen по gqlgen.yml (generated.go, models_gen.go). Типы схемы сопоставлены с моделями REST API;
+// резолверы реализованы в internal/controllers (graphql_schema.resolvers.go).
+package graph
+
+//go:generate go tool gqlgen generate
+
+import (
+	_ "embed"
+	"io"
+	"strconv"
+
+	"github.com/99designs/gqlgen/graphql"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/dates"
+)
+
+// SDL схема GraphQL в нотации SDL, из которой сгенерирован код пакета
+//
+//go:embed schema.graphqls
+var SDL string
+
+// MarshalDate пишет скаляр Date календарной датой YYYY-MM-DD
+func MarshalDate(d dates.Date) graphql.Marshaler {
+	return graphql.WriterFunc(func(w io.Writer) {
+		_, _ = io.WriteString(w, strconv.Quote(d.String()))
+	})
+}
+
+// UnmarshalDate разбирает скаляр Date теми же правилами, что и даты в теле REST-запроса
+func UnmarshalDate(v interface{}) (dates.Date, error) {
+	s, ok := v.(string)
+	d, err := dates.Parse(s)
+	if !ok || err != nil {
+		return dates.Date{}, apperror.New(apperror.ErrInvalidRequest, "invalid request format")
+	}
+	return d, nil
+}
@@ -0,0 +1,33 @@
+// Code generated by github.com/99designs/gqlgen, DO NOT EDIT.
+
+package graph
+
+import (
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+)
+
+type DocumentPage struct {
+	Items    []*models.EsfCreateDocumentRequest `json:"items"`
+	PageInfo *pagination.PaginationInfo         `json:"pageInfo"`
+}
+
+type Mutation struct {
+}
+
+type OrganizationPage struct {
+	Items    []*models.EsfOrganizationModel `json:"items"`
+	PageInfo *pagination.PaginationInfo     `json:"pageInfo"`
+}
+
+type Query struct {
+}
+
+type UserPage struct {
+	Items      []*entity.User `json:"items"`
+	NextCursor string         `json:"nextCursor"`
+	PrevCursor string         `json:"prevCursor"`
+	HasNext    bool           `json:"hasNext"`
+	HasPrev    bool           `json:"hasPrev"`
+}
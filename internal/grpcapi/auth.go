@@ -0,0 +1,56 @@
+package grpcapi
+
+import (
+	"context"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/rusgainew/tunduck-app/pkg/auth"
+)
+
+// userIDKey ключ контекста с ID пользователя из JWT
+type userIDKey struct{}
+
+// UserIDFromContext возвращает ID пользователя, установленный NewAuthInterceptor
+func UserIDFromContext(ctx context.Context) (string, bool) {
+	userID, ok := ctx.Value(userIDKey{}).(string)
+	return userID, ok
+}
+
+func userIDField(ctx context.Context) string {
+	userID, _ := UserIDFromContext(ctx)
+	return userID
+}
+
+// NewAuthInterceptor проверяет JWT из метаданных authorization теми же ключами подписи, что и REST API,
+// и сохраняет user_id в контексте вызова; без действующего токена вызов завершается с Unauthenticated
+func NewAuthInterceptor(keys *auth.KeyRing) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		var header string
+		if values := md.Get("authorization"); len(values) > 0 {
+			header = values[0]
+		}
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || token == "" {
+			return nil, status.Error(codes.Unauthenticated, "authorization metadata must be a Bearer token")
+		}
+
+		claims := jwt.MapClaims{}
+		parsed, err := keys.Parse(token, claims)
+		if err != nil || !parsed.Valid {
+			return nil, status.Error(codes.Unauthenticated, "invalid or expired JWT token")
+		}
+
+		userID, _ := claims["user_id"].(string)
+		if userID == "" {
+			return nil, status.Error(codes.Unauthenticated, "token has no user_id claim")
+		}
+		return handler(context.WithValue(ctx, userIDKey{}, userID), req)
+	}
+}
@@ -0,0 +1,150 @@
+package grpcapi
+
+import (
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/pkg/dates"
+)
+
+// DocumentFromModel переводит модель документа в сообщение
+func DocumentFromModel(doc *models.EsfCreateDocumentRequest) *Document {
+	entries := make([]*CatalogEntry, 0, len(doc.CatalogEntries))
+	for _, e := range doc.CatalogEntries {
+		entries = append(entries, &CatalogEntry{
+			UnitClassificationCode: e.UnitClassificationCode,
+			SalesTaxCode:           e.SalesTaxCode,
+			CustomsAuthorityCode:   e.CustomsAuthorityCode,
+			Quantity:               e.Quantity,
+			Price:                  e.Price,
+			VatAmount:              e.VatAmount,
+			SalesTaxAmount:         e.SalesTaxAmount,
+			AmountWithoutTaxes:     e.AmountWithoutTaxes,
+			TotalAmount:            e.TotalAmount,
+			DiscountAmount:         e.DiscountAmount,
+			SurchargeAmount:        e.SurchargeAmount,
+		})
+	}
+
+	return &Document{
+		Version:                        doc.Version,
+		EsfStatus:                      doc.EsfStatus,
+		ForeignName:                    doc.ForeignName,
+		IsBranchDataSent:               doc.IsBranchDataSent,
+		IsPriceWithoutTaxes:            doc.IsPriceWithoutTaxes,
+		AffiliateTin:                   doc.AffiliateTin,
+		IsIndustry:                     doc.IsIndustry,
+		OwnedCrmReceiptCode:            doc.OwnedCrmReceiptCode,
+		OperationTypeCode:              doc.OperationTypeCode,
+		DeliveryDate:                   timestampFromDate(doc.DeliveryDate),
+		DeliveryTypeCode:               doc.DeliveryTypeCode,
+		IsResident:                     doc.IsResident,
+		ContractorTin:                  doc.ContractorTin,
+		SupplierBankAccount:            doc.SupplierBankAccount,
+		ContractorBankAccount:          doc.ContractorBankAccount,
+		CurrencyCode:                   doc.CurrencyCode,
+		CountryCode:                    doc.CountryCode,
+		CurrencyRate:                   doc.CurrencyRate,
+		TotalCurrencyValue:             doc.TotalCurrencyValue,
+		TotalCurrencyValueWithoutTaxes: doc.TotalCurrencyValueWithoutTaxes,
+		SupplyContractNumber:           doc.SupplyContractNumber,
+		ContractStartDate:              timestampFromDate(doc.ContractStartDate),
+		Comment:                        doc.Comment,
+		DeliveryCode:                   doc.DeliveryCode,
+		PaymentCode:                    doc.PaymentCode,
+		TaxRateVatCode:                 doc.TaxRateVATCode,
+		CatalogEntries:                 entries,
+		OpeningBalances:                doc.OpeningBalances,
+		AssessedContributionsAmount:    doc.AssessedContributionsAmount,
+		PaidAmount:                     doc.PaidAmount,
+		PenaltiesAmount:                doc.PenaltiesAmount,
+		FinesAmount:                    doc.FinesAmount,
+		ClosingBalances:                doc.ClosingBalances,
+		AmountToBePaid:                 doc.AmountToBePaid,
+		PersonalAccountNumber:          doc.PersonalAccountNumber,
+	}
+}
+
+// DocumentToModel переводит сообщение в модель документа; версия и статус ЭСФ при создании игнорируются сервисом
+func DocumentToModel(d *Document) *models.EsfCreateDocumentRequest {
+	entries := make([]models.EsfEntriesModel, 0, len(d.GetCatalogEntries()))
+	for _, e := range d.GetCatalogEntries() {
+		entries = append(entries, models.EsfEntriesModel{
+			UnitClassificationCode: e.GetUnitClassificationCode(),
+			SalesTaxCode:           e.GetSalesTaxCode(),
+			CustomsAuthorityCode:   e.GetCustomsAuthorityCode(),
+			Quantity:               e.GetQuantity(),
+			Price:                  e.GetPrice(),
+			VatAmount:              e.GetVatAmount(),
+			SalesTaxAmount:         e.GetSalesTaxAmount(),
+			AmountWithoutTaxes:     e.GetAmountWithoutTaxes(),
+			TotalAmount:            e.GetTotalAmount(),
+			DiscountAmount:         e.GetDiscountAmount(),
+			SurchargeAmount:        e.GetSurchargeAmount(),
+		})
+	}
+
+	return &models.EsfCreateDocumentRequest{
+		Version:                        d.GetVersion(),
+		EsfStatus:                      d.GetEsfStatus(),
+		ForeignName:                    d.GetForeignName(),
+		IsBranchDataSent:               d.GetIsBranchDataSent(),
+		IsPriceWithoutTaxes:            d.GetIsPriceWithoutTaxes(),
+		AffiliateTin:                   d.GetAffiliateTin(),
+		IsIndustry:                     d.GetIsIndustry(),
+		OwnedCrmReceiptCode:            d.GetOwnedCrmReceiptCode(),
+		OperationTypeCode:              d.GetOperationTypeCode(),
+		DeliveryDate:                   dateFromTimestamp(d.GetDeliveryDate()),
+		DeliveryTypeCode:               d.GetDeliveryTypeCode(),
+		IsResident:                     d.GetIsResident(),
+		ContractorTin:                  d.GetContractorTin(),
+		SupplierBankAccount:            d.GetSupplierBankAccount(),
+		ContractorBankAccount:          d.GetContractorBankAccount(),
+		CurrencyCode:                   d.GetCurrencyCode(),
+		CountryCode:                    d.GetCountryCode(),
+		CurrencyRate:                   d.GetCurrencyRate(),
+		TotalCurrencyValue:             d.GetTotalCurrencyValue(),
+		TotalCurrencyValueWithoutTaxes: d.GetTotalCurrencyValueWithoutTaxes(),
+		SupplyContractNumber:           d.GetSupplyContractNumber(),
+		ContractStartDate:              dateFromTimestamp(d.GetContractStartDate()),
+		Comment:                        d.GetComment(),
+		DeliveryCode:                   d.GetDeliveryCode(),
+		PaymentCode:                    d.GetPaymentCode(),
+		TaxRateVATCode:                 d.GetTaxRateVatCode(),
+		CatalogEntries:                 entries,
+		OpeningBalances:                d.GetOpeningBalances(),
+		AssessedContributionsAmount:    d.GetAssessedContributionsAmount(),
+		PaidAmount:                     d.GetPaidAmount(),
+		PenaltiesAmount:                d.GetPenaltiesAmount(),
+		FinesAmount:                    d.GetFinesAmount(),
+		ClosingBalances:                d.GetClosingBalances(),
+		AmountToBePaid:                 d.GetAmountToBePaid(),
+		PersonalAccountNumber:          d.GetPersonalAccountNumber(),
+	}
+}
+
+// OrganizationFromModel переводит модель организации в сообщение; токен и имя базы не передаются
+func OrganizationFromModel(org *models.EsfOrganizationModel) *Organization {
+	return &Organization{
+		Id:          org.ID,
+		Name:        org.Name,
+		Description: org.Description,
+		Version:     org.Version,
+	}
+}
+
+// timestampFromDate переводит дату в Timestamp; пустая дата не передается
+func timestampFromDate(d dates.Date) *timestamppb.Timestamp {
+	if d.IsZero() {
+		return nil
+	}
+	return timestamppb.New(d.Time)
+}
+
+// dateFromTimestamp переводит Timestamp в дату; отсутствующее поле — пустая дата, а не 1970-01-01
+func dateFromTimestamp(ts *timestamppb.Timestamp) dates.Date {
+	if ts == nil {
+		return dates.Date{}
+	}
+	return dates.Of(ts.AsTime())
+}
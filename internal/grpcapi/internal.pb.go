@@ -0,0 +1,885 @@
+// Внутренний gRPC API для межсервисных вызовов.
+// Сервер: GRPC_ADDR того же бинарника, HTTP/2 без TLS, авторизация —
+// JWT в метаданных "authorization: Bearer <token>".
+// Код Go генерируется в internal/grpcapi: go generate ./internal/grpcapi (protoc, protoc-gen-go, protoc-gen-go-grpc).
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: tunduck/v1/internal.proto
+
+package grpcapi
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CatalogEntry struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	UnitClassificationCode string                 `protobuf:"bytes,1,opt,name=unit_classification_code,json=unitClassificationCode,proto3" json:"unit_classification_code,omitempty"`
+	SalesTaxCode           string                 `protobuf:"bytes,2,opt,name=sales_tax_code,json=salesTaxCode,proto3" json:"sales_tax_code,omitempty"`
+	CustomsAuthorityCode   string                 `protobuf:"bytes,3,opt,name=customs_authority_code,json=customsAuthorityCode,proto3" json:"customs_authority_code,omitempty"`
+	Quantity               float64                `protobuf:"fixed64,4,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Price                  float64                `protobuf:"fixed64,5,opt,name=price,proto3" json:"price,omitempty"`
+	VatAmount              float64                `protobuf:"fixed64,6,opt,name=vat_amount,json=vatAmount,proto3" json:"vat_amount,omitempty"`
+	SalesTaxAmount         float64                `protobuf:"fixed64,7,opt,name=sales_tax_amount,json=salesTaxAmount,proto3" json:"sales_tax_amount,omitempty"`
+	AmountWithoutTaxes     float64                `protobuf:"fixed64,8,opt,name=amount_without_taxes,json=amountWithoutTaxes,proto3" json:"amount_without_taxes,omitempty"`
+	TotalAmount            float64                `protobuf:"fixed64,9,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"`
+	DiscountAmount         float64                `protobuf:"fixed64,10,opt,name=discount_amount,json=discountAmount,proto3" json:"discount_amount,omitempty"`
+	SurchargeAmount        float64                `protobuf:"fixed64,11,opt,name=surcharge_amount,json=surchargeAmount,proto3" json:"surcharge_amount,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *CatalogEntry) Reset() {
+	*x = CatalogEntry{}
+	mi := &file_tunduck_v1_internal_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CatalogEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CatalogEntry) ProtoMessage() {}
+
+func (x *CatalogEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_tunduck_v1_internal_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CatalogEntry.ProtoReflect.Descriptor instead.
+func (*CatalogEntry) Descriptor() ([]byte, []int) {
+	return file_tunduck_v1_internal_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CatalogEntry) GetUnitClassificationCode() string {
+	if x != nil {
+		return x.UnitClassificationCode
+	}
+	return ""
+}
+
+func (x *CatalogEntry) GetSalesTaxCode() string {
+	if x != nil {
+		return x.SalesTaxCode
+	}
+	return ""
+}
+
+func (x *CatalogEntry) GetCustomsAuthorityCode() string {
+	if x != nil {
+		return x.CustomsAuthorityCode
+	}
+	return ""
+}
+
+func (x *CatalogEntry) GetQuantity() float64 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *CatalogEntry) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *CatalogEntry) GetVatAmount() float64 {
+	if x != nil {
+		return x.VatAmount
+	}
+	return 0
+}
+
+func (x *CatalogEntry) GetSalesTaxAmount() float64 {
+	if x != nil {
+		return x.SalesTaxAmount
+	}
+	return 0
+}
+
+func (x *CatalogEntry) GetAmountWithoutTaxes() float64 {
+	if x != nil {
+		return x.AmountWithoutTaxes
+	}
+	return 0
+}
+
+func (x *CatalogEntry) GetTotalAmount() float64 {
+	if x != nil {
+		return x.TotalAmount
+	}
+	return 0
+}
+
+func (x *CatalogEntry) GetDiscountAmount() float64 {
+	if x != nil {
+		return x.DiscountAmount
+	}
+	return 0
+}
+
+func (x *CatalogEntry) GetSurchargeAmount() float64 {
+	if x != nil {
+		return x.SurchargeAmount
+	}
+	return 0
+}
+
+type Document struct {
+	state                          protoimpl.MessageState `protogen:"open.v1"`
+	Version                        int64                  `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	EsfStatus                      string                 `protobuf:"bytes,2,opt,name=esf_status,json=esfStatus,proto3" json:"esf_status,omitempty"`
+	ForeignName                    string                 `protobuf:"bytes,3,opt,name=foreign_name,json=foreignName,proto3" json:"foreign_name,omitempty"`
+	IsBranchDataSent               bool                   `protobuf:"varint,4,opt,name=is_branch_data_sent,json=isBranchDataSent,proto3" json:"is_branch_data_sent,omitempty"`
+	IsPriceWithoutTaxes            bool                   `protobuf:"varint,5,opt,name=is_price_without_taxes,json=isPriceWithoutTaxes,proto3" json:"is_price_without_taxes,omitempty"`
+	AffiliateTin                   string                 `protobuf:"bytes,6,opt,name=affiliate_tin,json=affiliateTin,proto3" json:"affiliate_tin,omitempty"`
+	IsIndustry                     bool                   `protobuf:"varint,7,opt,name=is_industry,json=isIndustry,proto3" json:"is_industry,omitempty"`
+	OwnedCrmReceiptCode            string                 `protobuf:"bytes,8,opt,name=owned_crm_receipt_code,json=ownedCrmReceiptCode,proto3" json:"owned_crm_receipt_code,omitempty"`
+	OperationTypeCode              string                 `protobuf:"bytes,9,opt,name=operation_type_code,json=operationTypeCode,proto3" json:"operation_type_code,omitempty"`
+	DeliveryDate                   *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=delivery_date,json=deliveryDate,proto3" json:"delivery_date,omitempty"`
+	DeliveryTypeCode               string                 `protobuf:"bytes,11,opt,name=delivery_type_code,json=deliveryTypeCode,proto3" json:"delivery_type_code,omitempty"`
+	IsResident                     bool                   `protobuf:"varint,12,opt,name=is_resident,json=isResident,proto3" json:"is_resident,omitempty"`
+	ContractorTin                  string                 `protobuf:"bytes,13,opt,name=contractor_tin,json=contractorTin,proto3" json:"contractor_tin,omitempty"`
+	SupplierBankAccount            string                 `protobuf:"bytes,14,opt,name=supplier_bank_account,json=supplierBankAccount,proto3" json:"supplier_bank_account,omitempty"`
+	ContractorBankAccount          string                 `protobuf:"bytes,15,opt,name=contractor_bank_account,json=contractorBankAccount,proto3" json:"contractor_bank_account,omitempty"`
+	CurrencyCode                   string                 `protobuf:"bytes,16,opt,name=currency_code,json=currencyCode,proto3" json:"currency_code,omitempty"`
+	CountryCode                    string                 `protobuf:"bytes,17,opt,name=country_code,json=countryCode,proto3" json:"country_code,omitempty"`
+	CurrencyRate                   float64                `protobuf:"fixed64,18,opt,name=currency_rate,json=currencyRate,proto3" json:"currency_rate,omitempty"`
+	TotalCurrencyValue             float64                `protobuf:"fixed64,19,opt,name=total_currency_value,json=totalCurrencyValue,proto3" json:"total_currency_value,omitempty"`
+	TotalCurrencyValueWithoutTaxes float64                `protobuf:"fixed64,20,opt,name=total_currency_value_without_taxes,json=totalCurrencyValueWithoutTaxes,proto3" json:"total_currency_value_without_taxes,omitempty"`
+	SupplyContractNumber           string                 `protobuf:"bytes,21,opt,name=supply_contract_number,json=supplyContractNumber,proto3" json:"supply_contract_number,omitempty"`
+	ContractStartDate              *timestamppb.Timestamp `protobuf:"bytes,22,opt,name=contract_start_date,json=contractStartDate,proto3" json:"contract_start_date,omitempty"`
+	Comment                        string                 `protobuf:"bytes,23,opt,name=comment,proto3" json:"comment,omitempty"`
+	DeliveryCode                   string                 `protobuf:"bytes,24,opt,name=delivery_code,json=deliveryCode,proto3" json:"delivery_code,omitempty"`
+	PaymentCode                    string                 `protobuf:"bytes,25,opt,name=payment_code,json=paymentCode,proto3" json:"payment_code,omitempty"`
+	TaxRateVatCode                 string                 `protobuf:"bytes,26,opt,name=tax_rate_vat_code,json=taxRateVatCode,proto3" json:"tax_rate_vat_code,omitempty"`
+	CatalogEntries                 []*CatalogEntry        `protobuf:"bytes,27,rep,name=catalog_entries,json=catalogEntries,proto3" json:"catalog_entries,omitempty"`
+	OpeningBalances                float64                `protobuf:"fixed64,28,opt,name=opening_balances,json=openingBalances,proto3" json:"opening_balances,omitempty"`
+	AssessedContributionsAmount    float64                `protobuf:"fixed64,29,opt,name=assessed_contributions_amount,json=assessedContributionsAmount,proto3" json:"assessed_contributions_amount,omitempty"`
+	PaidAmount                     float64                `protobuf:"fixed64,30,opt,name=paid_amount,json=paidAmount,proto3" json:"paid_amount,omitempty"`
+	PenaltiesAmount                float64                `protobuf:"fixed64,31,opt,name=penalties_amount,json=penaltiesAmount,proto3" json:"penalties_amount,omitempty"`
+	FinesAmount                    float64                `protobuf:"fixed64,32,opt,name=fines_amount,json=finesAmount,proto3" json:"fines_amount,omitempty"`
+	ClosingBalances                float64                `protobuf:"fixed64,33,opt,name=closing_balances,json=closingBalances,proto3" json:"closing_balances,omitempty"`
+	AmountToBePaid                 float64                `protobuf:"fixed64,34,opt,name=amount_to_be_paid,json=amountToBePaid,proto3" json:"amount_to_be_paid,omitempty"`
+	PersonalAccountNumber          string                 `protobuf:"bytes,35,opt,name=personal_account_number,json=personalAccountNumber,proto3" json:"personal_account_number,omitempty"`
+	unknownFields                  protoimpl.UnknownFields
+	sizeCache                      protoimpl.SizeCache
+}
+
+func (x *Document) Reset() {
+	*x = Document{}
+	mi := &file_tunduck_v1_internal_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Document) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Document) ProtoMessage() {}
+
+func (x *Document) ProtoReflect() protoreflect.Message {
+	mi := &file_tunduck_v1_internal_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Document.ProtoReflect.Descriptor instead.
+func (*Document) Descriptor() ([]byte, []int) {
+	return file_tunduck_v1_internal_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Document) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Document) GetEsfStatus() string {
+	if x != nil {
+		return x.EsfStatus
+	}
+	return ""
+}
+
+func (x *Document) GetForeignName() string {
+	if x != nil {
+		return x.ForeignName
+	}
+	return ""
+}
+
+func (x *Document) GetIsBranchDataSent() bool {
+	if x != nil {
+		return x.IsBranchDataSent
+	}
+	return false
+}
+
+func (x *Document) GetIsPriceWithoutTaxes() bool {
+	if x != nil {
+		return x.IsPriceWithoutTaxes
+	}
+	return false
+}
+
+func (x *Document) GetAffiliateTin() string {
+	if x != nil {
+		return x.AffiliateTin
+	}
+	return ""
+}
+
+func (x *Document) GetIsIndustry() bool {
+	if x != nil {
+		return x.IsIndustry
+	}
+	return false
+}
+
+func (x *Document) GetOwnedCrmReceiptCode() string {
+	if x != nil {
+		return x.OwnedCrmReceiptCode
+	}
+	return ""
+}
+
+func (x *Document) GetOperationTypeCode() string {
+	if x != nil {
+		return x.OperationTypeCode
+	}
+	return ""
+}
+
+func (x *Document) GetDeliveryDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeliveryDate
+	}
+	return nil
+}
+
+func (x *Document) GetDeliveryTypeCode() string {
+	if x != nil {
+		return x.DeliveryTypeCode
+	}
+	return ""
+}
+
+func (x *Document) GetIsResident() bool {
+	if x != nil {
+		return x.IsResident
+	}
+	return false
+}
+
+func (x *Document) GetContractorTin() string {
+	if x != nil {
+		return x.ContractorTin
+	}
+	return ""
+}
+
+func (x *Document) GetSupplierBankAccount() string {
+	if x != nil {
+		return x.SupplierBankAccount
+	}
+	return ""
+}
+
+func (x *Document) GetContractorBankAccount() string {
+	if x != nil {
+		return x.ContractorBankAccount
+	}
+	return ""
+}
+
+func (x *Document) GetCurrencyCode() string {
+	if x != nil {
+		return x.CurrencyCode
+	}
+	return ""
+}
+
+func (x *Document) GetCountryCode() string {
+	if x != nil {
+		return x.CountryCode
+	}
+	return ""
+}
+
+func (x *Document) GetCurrencyRate() float64 {
+	if x != nil {
+		return x.CurrencyRate
+	}
+	return 0
+}
+
+func (x *Document) GetTotalCurrencyValue() float64 {
+	if x != nil {
+		return x.TotalCurrencyValue
+	}
+	return 0
+}
+
+func (x *Document) GetTotalCurrencyValueWithoutTaxes() float64 {
+	if x != nil {
+		return x.TotalCurrencyValueWithoutTaxes
+	}
+	return 0
+}
+
+func (x *Document) GetSupplyContractNumber() string {
+	if x != nil {
+		return x.SupplyContractNumber
+	}
+	return ""
+}
+
+func (x *Document) GetContractStartDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ContractStartDate
+	}
+	return nil
+}
+
+func (x *Document) GetComment() string {
+	if x != nil {
+		return x.Comment
+	}
+	return ""
+}
+
+func (x *Document) GetDeliveryCode() string {
+	if x != nil {
+		return x.DeliveryCode
+	}
+	return ""
+}
+
+func (x *Document) GetPaymentCode() string {
+	if x != nil {
+		return x.PaymentCode
+	}
+	return ""
+}
+
+func (x *Document) GetTaxRateVatCode() string {
+	if x != nil {
+		return x.TaxRateVatCode
+	}
+	return ""
+}
+
+func (x *Document) GetCatalogEntries() []*CatalogEntry {
+	if x != nil {
+		return x.CatalogEntries
+	}
+	return nil
+}
+
+func (x *Document) GetOpeningBalances() float64 {
+	if x != nil {
+		return x.OpeningBalances
+	}
+	return 0
+}
+
+func (x *Document) GetAssessedContributionsAmount() float64 {
+	if x != nil {
+		return x.AssessedContributionsAmount
+	}
+	return 0
+}
+
+func (x *Document) GetPaidAmount() float64 {
+	if x != nil {
+		return x.PaidAmount
+	}
+	return 0
+}
+
+func (x *Document) GetPenaltiesAmount() float64 {
+	if x != nil {
+		return x.PenaltiesAmount
+	}
+	return 0
+}
+
+func (x *Document) GetFinesAmount() float64 {
+	if x != nil {
+		return x.FinesAmount
+	}
+	return 0
+}
+
+func (x *Document) GetClosingBalances() float64 {
+	if x != nil {
+		return x.ClosingBalances
+	}
+	return 0
+}
+
+func (x *Document) GetAmountToBePaid() float64 {
+	if x != nil {
+		return x.AmountToBePaid
+	}
+	return 0
+}
+
+func (x *Document) GetPersonalAccountNumber() string {
+	if x != nil {
+		return x.PersonalAccountNumber
+	}
+	return ""
+}
+
+type SubmitDocumentRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	OrganizationId string                 `protobuf:"bytes,1,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	Document       *Document              `protobuf:"bytes,2,opt,name=document,proto3" json:"document,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SubmitDocumentRequest) Reset() {
+	*x = SubmitDocumentRequest{}
+	mi := &file_tunduck_v1_internal_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitDocumentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitDocumentRequest) ProtoMessage() {}
+
+func (x *SubmitDocumentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tunduck_v1_internal_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitDocumentRequest.ProtoReflect.Descriptor instead.
+func (*SubmitDocumentRequest) Descriptor() ([]byte, []int) {
+	return file_tunduck_v1_internal_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SubmitDocumentRequest) GetOrganizationId() string {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return ""
+}
+
+func (x *SubmitDocumentRequest) GetDocument() *Document {
+	if x != nil {
+		return x.Document
+	}
+	return nil
+}
+
+type SubmitDocumentResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ResponseId    string                 `protobuf:"bytes,1,opt,name=response_id,json=responseId,proto3" json:"response_id,omitempty"`
+	DocumentUuid  string                 `protobuf:"bytes,2,opt,name=document_uuid,json=documentUuid,proto3" json:"document_uuid,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubmitDocumentResponse) Reset() {
+	*x = SubmitDocumentResponse{}
+	mi := &file_tunduck_v1_internal_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubmitDocumentResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubmitDocumentResponse) ProtoMessage() {}
+
+func (x *SubmitDocumentResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_tunduck_v1_internal_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubmitDocumentResponse.ProtoReflect.Descriptor instead.
+func (*SubmitDocumentResponse) Descriptor() ([]byte, []int) {
+	return file_tunduck_v1_internal_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SubmitDocumentResponse) GetResponseId() string {
+	if x != nil {
+		return x.ResponseId
+	}
+	return ""
+}
+
+func (x *SubmitDocumentResponse) GetDocumentUuid() string {
+	if x != nil {
+		return x.DocumentUuid
+	}
+	return ""
+}
+
+type GetDocumentRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	OrganizationId string                 `protobuf:"bytes,1,opt,name=organization_id,json=organizationId,proto3" json:"organization_id,omitempty"`
+	Id             string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetDocumentRequest) Reset() {
+	*x = GetDocumentRequest{}
+	mi := &file_tunduck_v1_internal_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDocumentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDocumentRequest) ProtoMessage() {}
+
+func (x *GetDocumentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tunduck_v1_internal_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDocumentRequest.ProtoReflect.Descriptor instead.
+func (*GetDocumentRequest) Descriptor() ([]byte, []int) {
+	return file_tunduck_v1_internal_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetDocumentRequest) GetOrganizationId() string {
+	if x != nil {
+		return x.OrganizationId
+	}
+	return ""
+}
+
+func (x *GetDocumentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetOrganizationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrganizationRequest) Reset() {
+	*x = GetOrganizationRequest{}
+	mi := &file_tunduck_v1_internal_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrganizationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrganizationRequest) ProtoMessage() {}
+
+func (x *GetOrganizationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_tunduck_v1_internal_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrganizationRequest.ProtoReflect.Descriptor instead.
+func (*GetOrganizationRequest) Descriptor() ([]byte, []int) {
+	return file_tunduck_v1_internal_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *GetOrganizationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type Organization struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Version       int64                  `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Organization) Reset() {
+	*x = Organization{}
+	mi := &file_tunduck_v1_internal_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Organization) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Organization) ProtoMessage() {}
+
+func (x *Organization) ProtoReflect() protoreflect.Message {
+	mi := &file_tunduck_v1_internal_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Organization.ProtoReflect.Descriptor instead.
+func (*Organization) Descriptor() ([]byte, []int) {
+	return file_tunduck_v1_internal_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *Organization) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Organization) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Organization) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Organization) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+var File_tunduck_v1_internal_proto protoreflect.FileDescriptor
+
+const file_tunduck_v1_internal_proto_rawDesc = "" +
+	"\n" +
+	"\x19tunduck/v1/internal.proto\x12\n" +
+	"tunduck.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xc8\x03\n" +
+	"\fCatalogEntry\x128\n" +
+	"\x18unit_classification_code\x18\x01 \x01(\tR\x16unitClassificationCode\x12$\n" +
+	"\x0esales_tax_code\x18\x02 \x01(\tR\fsalesTaxCode\x124\n" +
+	"\x16customs_authority_code\x18\x03 \x01(\tR\x14customsAuthorityCode\x12\x1a\n" +
+	"\bquantity\x18\x04 \x01(\x01R\bquantity\x12\x14\n" +
+	"\x05price\x18\x05 \x01(\x01R\x05price\x12\x1d\n" +
+	"\n" +
+	"vat_amount\x18\x06 \x01(\x01R\tvatAmount\x12(\n" +
+	"\x10sales_tax_amount\x18\a \x01(\x01R\x0esalesTaxAmount\x120\n" +
+	"\x14amount_without_taxes\x18\b \x01(\x01R\x12amountWithoutTaxes\x12!\n" +
+	"\ftotal_amount\x18\t \x01(\x01R\vtotalAmount\x12'\n" +
+	"\x0fdiscount_amount\x18\n" +
+	" \x01(\x01R\x0ediscountAmount\x12)\n" +
+	"\x10surcharge_amount\x18\v \x01(\x01R\x0fsurchargeAmount\"\xc1\f\n" +
+	"\bDocument\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x03R\aversion\x12\x1d\n" +
+	"\n" +
+	"esf_status\x18\x02 \x01(\tR\tesfStatus\x12!\n" +
+	"\fforeign_name\x18\x03 \x01(\tR\vforeignName\x12-\n" +
+	"\x13is_branch_data_sent\x18\x04 \x01(\bR\x10isBranchDataSent\x123\n" +
+	"\x16is_price_without_taxes\x18\x05 \x01(\bR\x13isPriceWithoutTaxes\x12#\n" +
+	"\raffiliate_tin\x18\x06 \x01(\tR\faffiliateTin\x12\x1f\n" +
+	"\vis_industry\x18\a \x01(\bR\n" +
+	"isIndustry\x123\n" +
+	"\x16owned_crm_receipt_code\x18\b \x01(\tR\x13ownedCrmReceiptCode\x12.\n" +
+	"\x13operation_type_code\x18\t \x01(\tR\x11operationTypeCode\x12?\n" +
+	"\rdelivery_date\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\fdeliveryDate\x12,\n" +
+	"\x12delivery_type_code\x18\v \x01(\tR\x10deliveryTypeCode\x12\x1f\n" +
+	"\vis_resident\x18\f \x01(\bR\n" +
+	"isResident\x12%\n" +
+	"\x0econtractor_tin\x18\r \x01(\tR\rcontractorTin\x122\n" +
+	"\x15supplier_bank_account\x18\x0e \x01(\tR\x13supplierBankAccount\x126\n" +
+	"\x17contractor_bank_account\x18\x0f \x01(\tR\x15contractorBankAccount\x12#\n" +
+	"\rcurrency_code\x18\x10 \x01(\tR\fcurrencyCode\x12!\n" +
+	"\fcountry_code\x18\x11 \x01(\tR\vcountryCode\x12#\n" +
+	"\rcurrency_rate\x18\x12 \x01(\x01R\fcurrencyRate\x120\n" +
+	"\x14total_currency_value\x18\x13 \x01(\x01R\x12totalCurrencyValue\x12J\n" +
+	"\"total_currency_value_without_taxes\x18\x14 \x01(\x01R\x1etotalCurrencyValueWithoutTaxes\x124\n" +
+	"\x16supply_contract_number\x18\x15 \x01(\tR\x14supplyContractNumber\x12J\n" +
+	"\x13contract_start_date\x18\x16 \x01(\v2\x1a.google.protobuf.TimestampR\x11contractStartDate\x12\x18\n" +
+	"\acomment\x18\x17 \x01(\tR\acomment\x12#\n" +
+	"\rdelivery_code\x18\x18 \x01(\tR\fdeliveryCode\x12!\n" +
+	"\fpayment_code\x18\x19 \x01(\tR\vpaymentCode\x12)\n" +
+	"\x11tax_rate_vat_code\x18\x1a \x01(\tR\x0etaxRateVatCode\x12A\n" +
+	"\x0fcatalog_entries\x18\x1b \x03(\v2\x18.tunduck.v1.CatalogEntryR\x0ecatalogEntries\x12)\n" +
+	"\x10opening_balances\x18\x1c \x01(\x01R\x0fopeningBalances\x12B\n" +
+	"\x1dassessed_contributions_amount\x18\x1d \x01(\x01R\x1bassessedContributionsAmount\x12\x1f\n" +
+	"\vpaid_amount\x18\x1e \x01(\x01R\n" +
+	"paidAmount\x12)\n" +
+	"\x10penalties_amount\x18\x1f \x01(\x01R\x0fpenaltiesAmount\x12!\n" +
+	"\ffines_amount\x18  \x01(\x01R\vfinesAmount\x12)\n" +
+	"\x10closing_balances\x18! \x01(\x01R\x0fclosingBalances\x12)\n" +
+	"\x11amount_to_be_paid\x18\" \x01(\x01R\x0eamountToBePaid\x126\n" +
+	"\x17personal_account_number\x18# \x01(\tR\x15personalAccountNumber\"r\n" +
+	"\x15SubmitDocumentRequest\x12'\n" +
+	"\x0forganization_id\x18\x01 \x01(\tR\x0eorganizationId\x120\n" +
+	"\bdocument\x18\x02 \x01(\v2\x14.tunduck.v1.DocumentR\bdocument\"^\n" +
+	"\x16SubmitDocumentResponse\x12\x1f\n" +
+	"\vresponse_id\x18\x01 \x01(\tR\n" +
+	"responseId\x12#\n" +
+	"\rdocument_uuid\x18\x02 \x01(\tR\fdocumentUuid\"M\n" +
+	"\x12GetDocumentRequest\x12'\n" +
+	"\x0forganization_id\x18\x01 \x01(\tR\x0eorganizationId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\"(\n" +
+	"\x16GetOrganizationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"n\n" +
+	"\fOrganization\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\x03R\aversion2\xaf\x01\n" +
+	"\x0fDocumentService\x12W\n" +
+	"\x0eSubmitDocument\x12!.tunduck.v1.SubmitDocumentRequest\x1a\".tunduck.v1.SubmitDocumentResponse\x12C\n" +
+	"\vGetDocument\x12\x1e.tunduck.v1.GetDocumentRequest\x1a\x14.tunduck.v1.Document2f\n" +
+	"\x13OrganizationService\x12O\n" +
+	"\x0fGetOrganization\x12\".tunduck.v1.GetOrganizationRequest\x1a\x18.tunduck.v1.OrganizationB3Z1github.com/rusgainew/tunduck-app/internal/grpcapib\x06proto3"
+
+var (
+	file_tunduck_v1_internal_proto_rawDescOnce sync.Once
+	file_tunduck_v1_internal_proto_rawDescData []byte
+)
+
+func file_tunduck_v1_internal_proto_rawDescGZIP() []byte {
+	file_tunduck_v1_internal_proto_rawDescOnce.Do(func() {
+		file_tunduck_v1_internal_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_tunduck_v1_internal_proto_rawDesc), len(file_tunduck_v1_internal_proto_rawDesc)))
+	})
+	return file_tunduck_v1_internal_proto_rawDescData
+}
+
+var file_tunduck_v1_internal_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_tunduck_v1_internal_proto_goTypes = []any{
+	(*CatalogEntry)(nil),           // 0: tunduck.v1.CatalogEntry
+	(*Document)(nil),               // 1: tunduck.v1.Document
+	(*SubmitDocumentRequest)(nil),  // 2: tunduck.v1.SubmitDocumentRequest
+	(*SubmitDocumentResponse)(nil), // 3: tunduck.v1.SubmitDocumentResponse
+	(*GetDocumentRequest)(nil),     // 4: tunduck.v1.GetDocumentRequest
+	(*GetOrganizationRequest)(nil), // 5: tunduck.v1.GetOrganizationRequest
+	(*Organization)(nil),           // 6: tunduck.v1.Organization
+	(*timestamppb.Timestamp)(nil),  // 7: google.protobuf.Timestamp
+}
+var file_tunduck_v1_internal_proto_depIdxs = []int32{
+	7, // 0: tunduck.v1.Document.delivery_date:type_name -> google.protobuf.Timestamp
+	7, // 1: tunduck.v1.Document.contract_start_date:type_name -> google.protobuf.Timestamp
+	0, // 2: tunduck.v1.Document.catalog_entries:type_name -> tunduck.v1.CatalogEntry
+	1, // 3: tunduck.v1.SubmitDocumentRequest.document:type_name -> tunduck.v1.Document
+	2, // 4: tunduck.v1.DocumentService.SubmitDocument:input_type -> tunduck.v1.SubmitDocumentRequest
+	4, // 5: tunduck.v1.DocumentService.GetDocument:input_type -> tunduck.v1.GetDocumentRequest
+	5, // 6: tunduck.v1.OrganizationService.GetOrganization:input_type -> tunduck.v1.GetOrganizationRequest
+	3, // 7: tunduck.v1.DocumentService.SubmitDocument:output_type -> tunduck.v1.SubmitDocumentResponse
+	1, // 8: tunduck.v1.DocumentService.GetDocument:output_type -> tunduck.v1.Document
+	6, // 9: tunduck.v1.OrganizationService.GetOrganization:output_type -> tunduck.v1.Organization
+	7, // [7:10] is the sub-list for method output_type
+	4, // [4:7] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_tunduck_v1_internal_proto_init() }
+func file_tunduck_v1_internal_proto_init() {
+	if File_tunduck_v1_internal_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_tunduck_v1_internal_proto_rawDesc), len(file_tunduck_v1_internal_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   2,
+		},
+		GoTypes:           file_tunduck_v1_internal_proto_goTypes,
+		DependencyIndexes: file_tunduck_v1_internal_proto_depIdxs,
+		MessageInfos:      file_tunduck_v1_internal_proto_msgTypes,
+	}.Build()
+	File_tunduck_v1_internal_proto = out.File
+	file_tunduck_v1_internal_proto_goTypes = nil
+	file_tunduck_v1_internal_proto_depIdxs = nil
+}
@@ -0,0 +1,199 @@
+// Package grpcapi реализует внутренний gRPC API (api/proto/tunduck/v1/internal.proto)
+// поверх тех же сервисов контейнера, что и REST API. Сообщения, клиенты и интерфейсы серверов
+// сгенерированы protoc-gen-go и protoc-gen-go-grpc (internal.pb.go, internal_grpc.pb.go).
+package grpcapi
+
+//go:generate protoc -I ../../api/proto --go_out=../.. --go_opt=module=github.com/rusgainew/tunduck-app --go-grpc_out=../.. --go-grpc_opt=module=github.com/rusgainew/tunduck-app tunduck/v1/internal.proto
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/auth"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/validation"
+)
+
+// DefaultMaxMessageSize максимальный размер сообщения по умолчанию (как в grpc-go)
+const DefaultMaxMessageSize = 4 << 20
+
+// Config параметры сервера
+type Config struct {
+	// Addr адрес сервера, например ":9090"; пусто — сервер не запускается
+	Addr           string
+	MaxMessageSize int
+}
+
+// NewServer создает сервер gRPC с проверкой JWT (NewAuthInterceptor) и регистрирует
+// на нем DocumentService и OrganizationService
+func NewServer(cfg Config, keys *auth.KeyRing, documentService services.EsfDocumentService, orgService services.EsfOrganizationService, log *logrus.Logger) *grpc.Server {
+	if cfg.MaxMessageSize <= 0 {
+		cfg.MaxMessageSize = DefaultMaxMessageSize
+	}
+
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(NewAuthInterceptor(keys)),
+		grpc.MaxRecvMsgSize(cfg.MaxMessageSize),
+		grpc.MaxSendMsgSize(cfg.MaxMessageSize),
+	)
+	Register(srv, documentService, orgService, log)
+	return srv
+}
+
+// GracefulStop дожидается завершения текущих вызовов, но не дольше ctx; затем закрывает соединения
+func GracefulStop(ctx context.Context, srv *grpc.Server) error {
+	done := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		srv.Stop()
+		return ctx.Err()
+	}
+}
+
+// Register регистрирует DocumentService и OrganizationService на сервере
+func Register(srv grpc.ServiceRegistrar, documentService services.EsfDocumentService, orgService services.EsfOrganizationService, log *logrus.Logger) {
+	base := handlers{logger: logger.New(log)}
+	RegisterDocumentServiceServer(srv, &documentServer{handlers: base, documentService: documentService})
+	RegisterOrganizationServiceServer(srv, &organizationServer{handlers: base, orgService: orgService})
+}
+
+type handlers struct {
+	logger *logger.Logger
+}
+
+type documentServer struct {
+	UnimplementedDocumentServiceServer
+	handlers
+	documentService services.EsfDocumentService
+}
+
+type organizationServer struct {
+	UnimplementedOrganizationServiceServer
+	handlers
+	orgService services.EsfOrganizationService
+}
+
+func (h *documentServer) SubmitDocument(ctx context.Context, req *SubmitDocumentRequest) (*SubmitDocumentResponse, error) {
+	orgID, err := parseID(req.GetOrganizationId(), "organization_id")
+	if err != nil {
+		return nil, err
+	}
+	if req.GetDocument() == nil {
+		return nil, status.Error(codes.InvalidArgument, "document is required")
+	}
+
+	doc := DocumentToModel(req.GetDocument())
+	if appErr := validation.Struct(doc); appErr != nil {
+		return nil, h.status(ctx, appErr)
+	}
+
+	created, err := h.documentService.CreateDocument(ctx, orgID, doc)
+	if err != nil {
+		return nil, h.status(ctx, apperror.From(err, apperror.ErrInternal, "failed to create document"))
+	}
+
+	h.logger.Info(ctx, "Document submitted via gRPC", logrus.Fields{"org_id": orgID.String(), "user_id": userIDField(ctx)})
+	return &SubmitDocumentResponse{ResponseId: created.ResponseId, DocumentUuid: created.DocumentUuid}, nil
+}
+
+func (h *documentServer) GetDocument(ctx context.Context, req *GetDocumentRequest) (*Document, error) {
+	orgID, err := parseID(req.GetOrganizationId(), "organization_id")
+	if err != nil {
+		return nil, err
+	}
+	docID, err := parseID(req.GetId(), "id")
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := h.documentService.GetDocumentByID(ctx, orgID, docID)
+	if err != nil {
+		return nil, h.status(ctx, apperror.From(err, apperror.ErrInternal, "failed to get document"))
+	}
+	if doc == nil {
+		return nil, status.Error(codes.NotFound, "document not found")
+	}
+	return DocumentFromModel(doc), nil
+}
+
+func (h *organizationServer) GetOrganization(ctx context.Context, req *GetOrganizationRequest) (*Organization, error) {
+	orgID, err := parseID(req.GetId(), "id")
+	if err != nil {
+		return nil, err
+	}
+
+	org, err := h.orgService.GetOrganizationByID(ctx, orgID)
+	if err != nil {
+		return nil, h.status(ctx, apperror.From(err, apperror.ErrInternal, "failed to get organization"))
+	}
+	return OrganizationFromModel(org), nil
+}
+
+// status переводит AppError в статус gRPC; внутренние ошибки логируются и не раскрываются
+func (h *handlers) status(ctx context.Context, appErr *apperror.AppError) error {
+	code := CodeFromHTTPStatus(appErr.HTTPStatus)
+	if code == codes.Internal {
+		h.logger.Error(ctx, "gRPC call failed", appErr)
+		return status.Error(codes.Internal, "internal error")
+	}
+
+	message := appErr.Error()
+	if len(appErr.Fields) > 0 {
+		fields := make([]string, 0, len(appErr.Fields))
+		for _, f := range appErr.Fields {
+			fields = append(fields, f.Field+": "+f.Rule)
+		}
+		message += " (" + strings.Join(fields, ", ") + ")"
+	}
+	return status.Error(code, message)
+}
+
+// CodeFromHTTPStatus соответствие HTTP-статусов AppError кодам gRPC
+func CodeFromHTTPStatus(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.Aborted
+	case http.StatusPreconditionFailed, http.StatusPreconditionRequired:
+		return codes.FailedPrecondition
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	case http.StatusServiceUnavailable:
+		return codes.Unavailable
+	}
+	return codes.Internal
+}
+
+func parseID(raw, field string) (uuid.UUID, error) {
+	if raw == "" {
+		return uuid.Nil, status.Errorf(codes.InvalidArgument, "%s is required", field)
+	}
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, status.Errorf(codes.InvalidArgument, "invalid %s", field)
+	}
+	return id, nil
+}
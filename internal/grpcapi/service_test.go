@@ -0,0 +1,154 @@
+package grpcapi
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/auth"
+	"github.com/rusgainew/tunduck-app/pkg/dates"
+	"github.com/rusgainew/tunduck-app/pkg/testutil"
+)
+
+const testSecret = "grpc-test-secret"
+
+type stubDocumentService struct {
+	services.EsfDocumentService
+	docs map[uuid.UUID]*models.EsfCreateDocumentRequest
+}
+
+func (s *stubDocumentService) CreateDocument(ctx context.Context, orgID uuid.UUID, doc *models.EsfCreateDocumentRequest) (*models.EsfCreateDocumentResponse, error) {
+	id := uuid.New()
+	s.docs[id] = doc
+	return &models.EsfCreateDocumentResponse{ResponseId: "resp-1", DocumentUuid: id.String()}, nil
+}
+
+func (s *stubDocumentService) GetDocumentByID(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*models.EsfCreateDocumentRequest, error) {
+	return s.docs[id], nil
+}
+
+type stubOrganizationService struct {
+	services.EsfOrganizationService
+	org models.EsfOrganizationModel
+}
+
+func (s *stubOrganizationService) GetOrganizationByID(ctx context.Context, id uuid.UUID) (*models.EsfOrganizationModel, error) {
+	if id.String() != s.org.ID {
+		return nil, apperror.New(apperror.ErrOrgNotFound, "organization not found")
+	}
+	org := s.org
+	return &org, nil
+}
+
+func startServer(t *testing.T) (string, *stubDocumentService, models.EsfOrganizationModel) {
+	t.Helper()
+	log := logrus.New()
+	log.SetLevel(logrus.PanicLevel)
+
+	docs := &stubDocumentService{docs: map[uuid.UUID]*models.EsfCreateDocumentRequest{}}
+	org := models.EsfOrganizationModel{ID: uuid.NewString(), Name: "Tunduk", Token: "secret-token", DBName: "org_db", Version: 3}
+
+	keys, err := auth.NewKeyRing(auth.SigningKey{ID: auth.LegacyKeyID, Secret: testSecret})
+	require.NoError(t, err)
+	srv := NewServer(Config{}, keys, docs, &stubOrganizationService{org: org}, log)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = srv.Serve(l) }()
+	t.Cleanup(func() { _ = GracefulStop(context.Background(), srv) })
+
+	return l.Addr().String(), docs, org
+}
+
+func dial(t *testing.T, addr string, opts ...grpc.DialOption) *grpc.ClientConn {
+	t.Helper()
+	conn, err := grpc.NewClient(addr, append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))...)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func dialWithToken(t *testing.T, addr string) *grpc.ClientConn {
+	t.Helper()
+	token, err := auth.GenerateToken(uuid.NewString(), "service@example.com", testSecret, time.Hour)
+	require.NoError(t, err)
+	return dial(t, addr, grpc.WithPerRPCCredentials(BearerToken(token)))
+}
+
+func TestDocumentService(t *testing.T) {
+	addr, docs, org := startServer(t)
+	client := NewDocumentServiceClient(dialWithToken(t, addr))
+	ctx := context.Background()
+
+	doc := testutil.NewDocumentRequest()
+	doc.ContractStartDate = dates.New(2024, 1, 15)
+
+	created, err := client.SubmitDocument(ctx, &SubmitDocumentRequest{OrganizationId: org.ID, Document: DocumentFromModel(doc)})
+	require.NoError(t, err)
+	assert.Equal(t, "resp-1", created.GetResponseId())
+
+	stored := docs.docs[uuid.MustParse(created.GetDocumentUuid())]
+	require.NotNil(t, stored)
+	for i := range doc.CatalogEntries {
+		doc.CatalogEntries[i].ID = 0
+	}
+	assert.Equal(t, doc, stored)
+
+	fetched, err := client.GetDocument(ctx, &GetDocumentRequest{OrganizationId: org.ID, Id: created.GetDocumentUuid()})
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(DocumentFromModel(doc), fetched))
+
+	_, err = client.GetDocument(ctx, &GetDocumentRequest{OrganizationId: org.ID, Id: uuid.NewString()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// Ошибки валидации модели возвращаются с полями
+	_, err = client.SubmitDocument(ctx, &SubmitDocumentRequest{OrganizationId: org.ID, Document: &Document{CurrencyCode: "KGS"}})
+	st := status.Convert(err)
+	assert.Equal(t, codes.InvalidArgument, st.Code())
+	assert.Contains(t, st.Message(), "contractorTin: required")
+
+	_, err = client.SubmitDocument(ctx, &SubmitDocumentRequest{OrganizationId: "not-a-uuid", Document: &Document{}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Len(t, docs.docs, 1)
+}
+
+func TestOrganizationService(t *testing.T) {
+	addr, _, org := startServer(t)
+	ctx := context.Background()
+
+	// Без токена вызов отклоняется
+	_, err := NewOrganizationServiceClient(dial(t, addr)).GetOrganization(ctx, &GetOrganizationRequest{Id: org.ID})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	client := NewOrganizationServiceClient(dialWithToken(t, addr))
+	got, err := client.GetOrganization(ctx, &GetOrganizationRequest{Id: org.ID})
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(&Organization{Id: org.ID, Name: "Tunduk", Version: 3}, got))
+
+	_, err = client.GetOrganization(ctx, &GetOrganizationRequest{Id: uuid.NewString()})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestDocumentConversion_EmptyDates(t *testing.T) {
+	doc := testutil.NewDocumentRequest()
+	doc.ContractStartDate = dates.Date{}
+
+	// Пустая дата договора не передается и не превращается в 1970-01-01
+	msg := DocumentFromModel(doc)
+	assert.Nil(t, msg.GetContractStartDate())
+	assert.True(t, DocumentToModel(msg).ContractStartDate.IsZero())
+}
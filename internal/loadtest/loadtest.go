@@ -0,0 +1,433 @@
+// Package loadtest создает реалистичную нагрузку на развернутое окружение: вход, создание
+// документа, список документов и отправку в ЭСФ с заданной частотой запросов (RPS).
+// Отчет содержит перцентили задержек по операциям и число ответов 429, поэтому им
+// проверяют настройки ограничителя запросов и пулов соединений.
+//
+// Запросы отправляются с постоянной частотой независимо от скорости ответов. Если все
+// исполнители заняты, запрос не ставится в очередь, а учитывается как пропущенный.
+package loadtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/seed"
+)
+
+// Operation операция сценария нагрузки
+type Operation string
+
+const (
+	OpLogin  Operation = "login"
+	OpCreate Operation = "create"
+	OpList   Operation = "list"
+	OpSend   Operation = "send"
+)
+
+// Operations операции в порядке вывода отчета
+var Operations = []Operation{OpLogin, OpCreate, OpList, OpSend}
+
+// DefaultMix доли операций по умолчанию: чтение преобладает, вход и отправка редки
+var DefaultMix = map[Operation]int{OpLogin: 5, OpCreate: 25, OpList: 60, OpSend: 10}
+
+// Значения по умолчанию
+const (
+	DefaultRPS      = 20
+	DefaultDuration = 30 * time.Second
+	DefaultWorkers  = 50
+	DefaultTimeout  = 10 * time.Second
+	DefaultPageSize = 20
+)
+
+// Config параметры нагрузочного теста
+type Config struct {
+	// BaseURL адрес API, например https://staging.example.com
+	BaseURL string
+	// Username и Password пользователя, от имени которого идут запросы; для отправки нужно право подписи
+	Username string
+	Password string
+	// OrganizationID организация, в которой создаются и отправляются документы
+	OrganizationID uuid.UUID
+	// RPS число запросов в секунду
+	RPS int
+	// Duration длительность теста
+	Duration time.Duration
+	// Workers наибольшее число одновременных запросов
+	Workers int
+	// Mix доли операций; операции с нулевой долей не выполняются
+	Mix map[Operation]int
+	// Timeout таймаут одного запроса
+	Timeout time.Duration
+	// PageSize размер страницы списка документов
+	PageSize int
+	// RandSeed начальное значение генератора операций и документов
+	RandSeed int64
+	// Client HTTP-клиент; по умолчанию создается с Timeout и пулом на Workers соединений
+	Client *http.Client
+}
+
+func (c Config) withDefaults() Config {
+	if c.RPS <= 0 {
+		c.RPS = DefaultRPS
+	}
+	if c.Duration <= 0 {
+		c.Duration = DefaultDuration
+	}
+	if c.Workers <= 0 {
+		c.Workers = DefaultWorkers
+	}
+	if len(c.Mix) == 0 {
+		c.Mix = DefaultMix
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultTimeout
+	}
+	if c.PageSize <= 0 {
+		c.PageSize = DefaultPageSize
+	}
+	if c.Client == nil {
+		c.Client = &http.Client{
+			Timeout:   c.Timeout,
+			Transport: &http.Transport{MaxIdleConns: c.Workers, MaxIdleConnsPerHost: c.Workers},
+		}
+	}
+	c.BaseURL = strings.TrimRight(c.BaseURL, "/")
+	return c
+}
+
+// ParseMix разбирает доли операций вида "login=5,create=25,list=60,send=10"
+func ParseMix(raw string) (map[Operation]int, error) {
+	mix := map[Operation]int{}
+	total := 0
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid mix entry %q, expected operation=weight", part)
+		}
+		op := Operation(strings.TrimSpace(name))
+		if !isOperation(op) {
+			return nil, fmt.Errorf("unknown operation %q (available: login, create, list, send)", op)
+		}
+		weight, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid weight of %s: %q", op, value)
+		}
+		mix[op] = weight
+		total += weight
+	}
+	if total == 0 {
+		return nil, errors.New("mix has no operations")
+	}
+	return mix, nil
+}
+
+func isOperation(op Operation) bool {
+	for _, known := range Operations {
+		if op == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Runner выполняет нагрузочный тест
+type Runner struct {
+	cfg Config
+
+	mu      sync.Mutex
+	rng     *rand.Rand
+	token   string
+	created []uuid.UUID
+	docs    int
+	stats   map[Operation]*opStats
+}
+
+// New создает Runner; BaseURL, Username, Password и OrganizationID обязательны
+func New(cfg Config) (*Runner, error) {
+	cfg = cfg.withDefaults()
+	switch {
+	case cfg.BaseURL == "":
+		return nil, errors.New("base URL is required")
+	case cfg.Username == "" || cfg.Password == "":
+		return nil, errors.New("username and password are required")
+	case cfg.OrganizationID == uuid.Nil:
+		return nil, errors.New("organization ID is required")
+	}
+	stats := map[Operation]*opStats{}
+	for _, op := range Operations {
+		stats[op] = &opStats{statuses: map[int]int{}}
+	}
+	return &Runner{cfg: cfg, rng: rand.New(rand.NewSource(cfg.RandSeed)), stats: stats}, nil
+}
+
+// Run выполняет тест до истечения Duration или отмены ctx и возвращает отчет.
+// Ошибка возвращается, только если не удался первый вход: без токена тест бессмыслен.
+func (r *Runner) Run(ctx context.Context) (*Report, error) {
+	if err := r.execute(ctx, OpLogin); err != nil {
+		return nil, fmt.Errorf("initial login failed: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.cfg.Duration)
+	defer cancel()
+
+	work := make(chan Operation)
+	var wg sync.WaitGroup
+	for i := 0; i < r.cfg.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for op := range work {
+				_ = r.execute(ctx, op)
+			}
+		}()
+	}
+
+	started := time.Now()
+	ticker := time.NewTicker(time.Second / time.Duration(r.cfg.RPS))
+	dropped := 0
+loop:
+	for {
+		select {
+		case <-ctx.Done():
+			break loop
+		case <-ticker.C:
+			select {
+			case work <- r.next():
+			default:
+				dropped++
+			}
+		}
+	}
+	ticker.Stop()
+	close(work)
+	wg.Wait()
+
+	return r.report(time.Since(started), dropped), nil
+}
+
+// next выбирает операцию по долям Mix. Отправка без созданных документов заменяется созданием.
+func (r *Runner) next() Operation {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	total := 0
+	for _, op := range Operations {
+		total += r.cfg.Mix[op]
+	}
+	pick := r.rng.Intn(total)
+	op := OpList
+	for _, candidate := range Operations {
+		if pick < r.cfg.Mix[candidate] {
+			op = candidate
+			break
+		}
+		pick -= r.cfg.Mix[candidate]
+	}
+	if op == OpSend && len(r.created) == 0 {
+		op = OpCreate
+	}
+	return op
+}
+
+// execute выполняет операцию и записывает ее задержку и статус ответа
+func (r *Runner) execute(ctx context.Context, op Operation) error {
+	started := time.Now()
+	status, err := r.call(ctx, op)
+	elapsed := time.Since(started)
+	if ctx.Err() != nil && (errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)) {
+		// Запрос прерван окончанием теста, а не сервером
+		return err
+	}
+
+	r.mu.Lock()
+	r.stats[op].record(elapsed, status, err)
+	r.mu.Unlock()
+	return err
+}
+
+// call отправляет запрос операции и возвращает HTTP-статус ответа
+func (r *Runner) call(ctx context.Context, op Operation) (int, error) {
+	switch op {
+	case OpLogin:
+		var auth models.AuthResponse
+		status, err := r.do(ctx, http.MethodPost, "/api/auth/login", models.LoginRequest{Username: r.cfg.Username, Password: r.cfg.Password}, &auth)
+		if err == nil {
+			r.mu.Lock()
+			r.token = auth.Token
+			r.mu.Unlock()
+		}
+		return status, err
+
+	case OpCreate:
+		r.mu.Lock()
+		r.docs++
+		doc := seed.Document(r.rng, r.docs, time.Now())
+		r.mu.Unlock()
+
+		var created models.EsfCreateDocumentResponse
+		status, err := r.do(ctx, http.MethodPost, "/api/esf-documents", doc, &created)
+		if err == nil {
+			if id, parseErr := uuid.Parse(created.DocumentUuid); parseErr == nil {
+				r.mu.Lock()
+				r.created = append(r.created, id)
+				r.mu.Unlock()
+			}
+		}
+		return status, err
+
+	case OpList:
+		path := fmt.Sprintf("/api/esf-documents/paginated?page=1&page_size=%d", r.cfg.PageSize)
+		return r.do(ctx, http.MethodGet, path, nil, nil)
+
+	case OpSend:
+		// Каждый созданный документ отправляется один раз
+		r.mu.Lock()
+		if len(r.created) == 0 {
+			r.mu.Unlock()
+			return 0, errors.New("no documents to send")
+		}
+		id := r.created[len(r.created)-1]
+		r.created = r.created[:len(r.created)-1]
+		r.mu.Unlock()
+		return r.do(ctx, http.MethodPost, "/api/esf-documents/"+id.String()+"/send", nil, nil)
+	}
+	return 0, fmt.Errorf("unknown operation %q", op)
+}
+
+// statusError ответ сервера с кодом ошибки
+type statusError struct {
+	status int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("unexpected status %d", e.status)
+}
+
+// do отправляет JSON-запрос и разбирает поле data ответа в out
+func (r *Runner) do(ctx context.Context, method, path string, body, out interface{}) (int, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return 0, err
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, r.cfg.BaseURL+path, reader)
+	if err != nil {
+		return 0, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("X-Org-Id", r.cfg.OrganizationID.String())
+	r.mu.Lock()
+	if r.token != "" {
+		req.Header.Set("Authorization", "Bearer "+r.token)
+	}
+	r.mu.Unlock()
+
+	resp, err := r.cfg.Client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return resp.StatusCode, &statusError{status: resp.StatusCode}
+	}
+	if out == nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return resp.StatusCode, nil
+	}
+	envelope := struct {
+		Data interface{} `json:"data"`
+	}{Data: out}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return resp.StatusCode, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return resp.StatusCode, nil
+}
+
+// opStats задержки и статусы ответов одной операции
+type opStats struct {
+	latencies []time.Duration
+	statuses  map[int]int
+	errors    int
+}
+
+func (s *opStats) record(elapsed time.Duration, status int, err error) {
+	s.latencies = append(s.latencies, elapsed)
+	if status != 0 {
+		s.statuses[status]++
+	}
+	if err != nil {
+		s.errors++
+	}
+}
+
+// report собирает отчет по накопленной статистике
+func (r *Runner) report(elapsed time.Duration, dropped int) *Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	report := &Report{Duration: elapsed, TargetRPS: r.cfg.RPS, Dropped: dropped}
+	for _, op := range Operations {
+		stats := r.stats[op]
+		if len(stats.latencies) == 0 {
+			continue
+		}
+		latencies := append([]time.Duration(nil), stats.latencies...)
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+
+		result := OperationResult{
+			Operation:   op,
+			Requests:    len(latencies),
+			Errors:      stats.errors,
+			RateLimited: stats.statuses[http.StatusTooManyRequests],
+			Statuses:    stats.statuses,
+			P50:         percentile(latencies, 50),
+			P90:         percentile(latencies, 90),
+			P95:         percentile(latencies, 95),
+			P99:         percentile(latencies, 99),
+			Max:         latencies[len(latencies)-1],
+		}
+		report.Requests += result.Requests
+		report.Errors += result.Errors
+		report.RateLimited += result.RateLimited
+		report.Operations = append(report.Operations, result)
+	}
+	return report
+}
+
+// percentile возвращает перцентиль p (0–100) отсортированных задержек методом ближайшего ранга
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(float64(len(sorted))*p/100)) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	return sorted[rank]
+}
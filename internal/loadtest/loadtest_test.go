@@ -0,0 +1,158 @@
+package loadtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAPI отвечает как API на вход, создание, список и отправку документов
+type fakeAPI struct {
+	mu      sync.Mutex
+	created map[string]bool
+	sent    map[string]int
+	calls   int
+	limit   int
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+
+	if r.URL.Path != "/api/auth/login" && r.Header.Get("Authorization") != "Bearer token-1" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if f.limit > 0 && f.calls > f.limit {
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+
+	switch {
+	case r.URL.Path == "/api/auth/login":
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": map[string]string{"token": "token-1"}})
+	case r.Method == http.MethodPost && r.URL.Path == "/api/esf-documents":
+		id := uuid.NewString()
+		f.created[id] = true
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": map[string]string{"documentUuid": id}})
+	case r.URL.Path == "/api/esf-documents/paginated":
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"success": true, "data": []string{}})
+	case strings.HasSuffix(r.URL.Path, "/send"):
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/esf-documents/"), "/send")
+		if !f.created[id] {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		f.sent[id]++
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"success": true})
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestRunner_Run(t *testing.T) {
+	api := &fakeAPI{created: map[string]bool{}, sent: map[string]int{}}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	runner, err := New(Config{
+		BaseURL: server.URL, Username: "admin", Password: "secret", OrganizationID: uuid.New(),
+		RPS: 200, Duration: 300 * time.Millisecond, Workers: 10,
+		Mix: map[Operation]int{OpCreate: 1, OpList: 1, OpSend: 1},
+	})
+	require.NoError(t, err)
+
+	report, err := runner.Run(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, report.Errors)
+	assert.Greater(t, report.Requests, 10)
+
+	ops := map[Operation]OperationResult{}
+	for _, op := range report.Operations {
+		ops[op.Operation] = op
+		assert.LessOrEqual(t, op.P50, op.P99)
+		assert.LessOrEqual(t, op.P99, op.Max)
+	}
+	assert.Equal(t, 1, ops[OpLogin].Requests, "only the initial login")
+	assert.Positive(t, ops[OpCreate].Requests)
+	assert.Positive(t, ops[OpList].Statuses[http.StatusOK])
+
+	// Каждый документ отправлен не больше одного раза и только после создания
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	assert.Equal(t, ops[OpSend].Requests, len(api.sent))
+	for _, count := range api.sent {
+		assert.Equal(t, 1, count)
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, report.Write(&out))
+	assert.Contains(t, out.String(), "p99")
+}
+
+func TestRunner_CountsRateLimited(t *testing.T) {
+	api := &fakeAPI{created: map[string]bool{}, sent: map[string]int{}, limit: 5}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	runner, err := New(Config{
+		BaseURL: server.URL, Username: "admin", Password: "secret", OrganizationID: uuid.New(),
+		RPS: 200, Duration: 200 * time.Millisecond, Mix: map[Operation]int{OpList: 1},
+	})
+	require.NoError(t, err)
+
+	report, err := runner.Run(context.Background())
+	require.NoError(t, err)
+	assert.Positive(t, report.RateLimited)
+	assert.Equal(t, report.RateLimited, report.Errors)
+	assert.Equal(t, 5, report.Requests-report.Errors, "login and four list requests pass")
+}
+
+func TestRunner_FailsWithoutLogin(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	runner, err := New(Config{BaseURL: server.URL, Username: "admin", Password: "wrong", OrganizationID: uuid.New()})
+	require.NoError(t, err)
+	_, err = runner.Run(context.Background())
+	assert.ErrorContains(t, err, "initial login failed")
+}
+
+func TestParseMix(t *testing.T) {
+	mix, err := ParseMix("login=1, list=9,send=0")
+	require.NoError(t, err)
+	assert.Equal(t, map[Operation]int{OpLogin: 1, OpList: 9, OpSend: 0}, mix)
+
+	_, err = ParseMix("browse=1")
+	assert.Error(t, err)
+	_, err = ParseMix("list=-1")
+	assert.Error(t, err)
+	_, err = ParseMix("send=0")
+	assert.Error(t, err)
+}
+
+func TestPercentile(t *testing.T) {
+	var sorted []time.Duration
+	for i := 1; i <= 100; i++ {
+		sorted = append(sorted, time.Duration(i)*time.Millisecond)
+	}
+	assert.Equal(t, 50*time.Millisecond, percentile(sorted, 50))
+	assert.Equal(t, 99*time.Millisecond, percentile(sorted, 99))
+	assert.Equal(t, 100*time.Millisecond, percentile(sorted, 100))
+	assert.Equal(t, time.Millisecond, percentile(sorted[:1], 99))
+	assert.Zero(t, percentile(nil, 50))
+}
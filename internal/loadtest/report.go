@@ -0,0 +1,92 @@
+package loadtest
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// OperationResult итоги одной операции
+type OperationResult struct {
+	Operation Operation
+	Requests  int
+	// Errors запросы с ошибкой соединения или статусом 4xx/5xx (включая 429)
+	Errors int
+	// RateLimited ответы 429 Too Many Requests
+	RateLimited int
+	Statuses    map[int]int
+	P50         time.Duration
+	P90         time.Duration
+	P95         time.Duration
+	P99         time.Duration
+	Max         time.Duration
+}
+
+// Report итоги нагрузочного теста
+type Report struct {
+	Duration    time.Duration
+	TargetRPS   int
+	Requests    int
+	Errors      int
+	RateLimited int
+	// Dropped запросы, не отправленные из-за того, что все исполнители были заняты
+	Dropped    int
+	Operations []OperationResult
+}
+
+// ActualRPS фактическая частота выполненных запросов
+func (r *Report) ActualRPS() float64 {
+	if r.Duration <= 0 {
+		return 0
+	}
+	return float64(r.Requests) / r.Duration.Seconds()
+}
+
+// ErrorRate доля запросов с ошибкой
+func (r *Report) ErrorRate() float64 {
+	if r.Requests == 0 {
+		return 0
+	}
+	return float64(r.Errors) / float64(r.Requests)
+}
+
+// Write печатает отчет таблицей
+func (r *Report) Write(w io.Writer) error {
+	fmt.Fprintf(w, "Duration %s, target %d rps, actual %.1f rps\n", r.Duration.Round(time.Millisecond), r.TargetRPS, r.ActualRPS())
+	fmt.Fprintf(w, "Requests %d, errors %d (%.2f%%), rate limited %d, dropped %d\n\n",
+		r.Requests, r.Errors, r.ErrorRate()*100, r.RateLimited, r.Dropped)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "operation\trequests\terrors\t429\tp50\tp90\tp95\tp99\tmax\tstatuses\t")
+	for _, op := range r.Operations {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%s\t%s\t%s\t%s\t%s\t%s\t\n",
+			op.Operation, op.Requests, op.Errors, op.RateLimited,
+			formatLatency(op.P50), formatLatency(op.P90), formatLatency(op.P95), formatLatency(op.P99), formatLatency(op.Max),
+			formatStatuses(op.Statuses))
+	}
+	return tw.Flush()
+}
+
+func formatLatency(d time.Duration) string {
+	return fmt.Sprintf("%.1fms", float64(d)/float64(time.Millisecond))
+}
+
+// formatStatuses выводит статусы ответов по возрастанию кода: "200:95 429:5"
+func formatStatuses(statuses map[int]int) string {
+	codes := make([]int, 0, len(statuses))
+	for code := range statuses {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	parts := make([]string, 0, len(codes))
+	for _, code := range codes {
+		parts = append(parts, fmt.Sprintf("%d:%d", code, statuses[code]))
+	}
+	if len(parts) == 0 {
+		return "-"
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,9 @@
+package models
+
+// AttachmentLinkRequest запрос подписанной ссылки на вложение организации
+type AttachmentLinkRequest struct {
+	// Key ключ вложения из ответа загрузки
+	Key string `json:"key" validate:"required,max=300"`
+	// ExpiresIn срок действия ссылки в формате Go, например "24h"; пустой — срок по умолчанию
+	ExpiresIn string `json:"expiresIn" validate:"max=20"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "github.com/rusgainew/tunduck-app/pkg/dates"
+
+// ContractRequest договор поставки с контрагентом
+type ContractRequest struct {
+	// Номер договора
+	Number string `json:"number" validate:"required,max=100"`
+	// ИНН контрагента
+	ContractorTin string `json:"contractorTin" validate:"required,tin"`
+	// Дата начала действия
+	StartDate dates.Date `json:"startDate" validate:"required"`
+	// Дата окончания действия; без нее договор бессрочный
+	EndDate *dates.Date `json:"endDate"`
+	// Валюта договора; пустая — любая
+	CurrencyCode string `json:"currencyCode" validate:"omitempty,len=3"`
+	// Лимит суммы документов по договору; 0 — без лимита
+	AmountLimit float64 `json:"amountLimit" validate:"gte=0"`
+	Comment     string  `json:"comment" validate:"max=1000"`
+}
+
+// Коды предупреждений документа
+const (
+	WarningContractNotStarted       = "contract_not_started"
+	WarningContractExpired          = "contract_expired"
+	WarningContractLimitExceeded    = "contract_limit_exceeded"
+	WarningContractCurrencyMismatch = "contract_currency_mismatch"
+	WarningPriceBelowFloor          = "price_below_floor"
+)
+
+// DocumentWarning предупреждение о документе, которое не мешает его сохранению
+type DocumentWarning struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
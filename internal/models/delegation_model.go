@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DelegationRequest передача права подписи другому пользователю на срок
+type DelegationRequest struct {
+	// Пользователь, который будет отправлять документы от имени делегирующего
+	DelegateID uuid.UUID `json:"delegateId" validate:"required"`
+	// Срок действия, обе даты включительно
+	ValidFrom time.Time `json:"validFrom" validate:"required"`
+	ValidTo   time.Time `json:"validTo" validate:"required"`
+	// Причина, например «отпуск»
+	Reason string `json:"reason" validate:"max=255"`
+}
@@ -4,6 +4,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/pkg/dates"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
 )
 
 // Create document
@@ -11,14 +14,24 @@ import (
 // METHOD: POST
 // PATH: /api/command/invoice/create
 type EsfCreateDocumentRequest struct {
+	// Идентификатор документа; только для чтения, при создании игнорируется
+	ID uuid.UUID `json:"id"`
+	// Версия документа для оптимистичной блокировки; при создании игнорируется
+	Version int64 `json:"version,omitempty"`
+	// Статус документа в ЭСФ; только для чтения, устанавливается обратными вызовами шлюза
+	EsfStatus string `json:"esfStatus,omitempty"`
 	// false Наименование иностранца или Наименование на иностранном языке
 	ForeignName string `json:"foreignName"`
+	// false Наименование покупателя на языках ru, ky, en
+	ContractorNames entity.LocalizedNames `json:"contractorNames,omitempty" valid:"omitempty,dive,keys,oneof=ru ky en,endkeys,max=255"`
+	// false Язык печатной формы документа (ru, ky, en); по умолчанию ru
+	Language string `json:"language,omitempty" valid:"omitempty,oneof=ru ky en"`
 	// true Отправить от имени филиала
-	IsBranchDataSent bool `json:"isBranchDataSent" valid:"required"`
+	IsBranchDataSent bool `json:"isBranchDataSent"`
 	// true Цена без налогов
-	IsPriceWithoutTaxes bool `json:"isPriceWithoutTaxes" valid:"required"`
+	IsPriceWithoutTaxes bool `json:"isPriceWithoutTaxes"`
 	// false ИНН филиала
-	AffiliateTin string `json:"affiliateTin"`
+	AffiliateTin string `json:"affiliateTin" valid:"omitempty,tin"`
 	// false Отраслевые
 	IsIndustry bool `json:"isIndustry"`
 	// false Номер учетной системы
@@ -26,13 +39,13 @@ type EsfCreateDocumentRequest struct {
 	// true Код вида операции
 	OperationTypeCode string `json:"operationTypeCode" valid:"required"`
 	// true Дата поставки
-	DeliveryDate time.Time `json:"deliveryDate" valid:"required"`
+	DeliveryDate dates.Date `json:"deliveryDate" valid:"required"`
 	// true Код типа поставки
 	DeliveryTypeCode string `json:"deliveryTypeCode" valid:"required"`
 	// true Субъект Кыргызской Республики
-	IsResident bool `json:"isResident" valid:"required"`
+	IsResident bool `json:"isResident"`
 	// true ИНН покупателя
-	ContractorTin string `json:"contractorTin" valid:"required"`
+	ContractorTin string `json:"contractorTin" valid:"required,tin"`
 	// false Номер банковского счета поставщика
 	SupplierBankAccount string `json:"supplierBankAccount"`
 	// false Номер банковского счета покупателя
@@ -50,7 +63,9 @@ type EsfCreateDocumentRequest struct {
 	// false Номер договора на поставку
 	SupplyContractNumber string `json:"supplyContractNumber"`
 	// false Дата договора на поставку
-	ContractStartDate time.Time `json:"contractStartDate"`
+	ContractStartDate dates.Date `json:"contractStartDate"`
+	// false Договор из справочника договоров; номер и дата договора заполняются из него
+	ContractID *uuid.UUID `json:"contractId,omitempty"`
 	// false Дата окончания договора на поставку Комментарий
 	Comment string `json:"comment"`
 	// false Код способа доставки
@@ -60,7 +75,7 @@ type EsfCreateDocumentRequest struct {
 	// true Код ставки НДС
 	TaxRateVATCode string `json:"taxRateVATCode" valid:"required"`
 	// true Товары и услуги
-	CatalogEntries []EsfEntriesModel `json:"catalogEntries"`
+	CatalogEntries []EsfEntriesModel `json:"catalogEntries" valid:"required,dive"`
 	// false Начальные остатки,сальдо на начало периода
 	OpeningBalances float64 `json:"openingBalances"`
 	// false Начисленные взносы
@@ -77,10 +92,14 @@ type EsfCreateDocumentRequest struct {
 	AmountToBePaid float64 `json:"amountToBePaid"`
 	// false Лицевой счет
 	PersonalAccountNumber string `json:"personalAccountNumber"`
+	// false Срок оплаты
+	DueDate *time.Time `json:"dueDate,omitempty"`
 }
 type EsfCreateDocumentResponse struct {
 	ResponseId   string `json:"responseId"`
 	DocumentUuid string `json:"documentUuid"`
+	// Предупреждения, не мешающие сохранению документа (например, истекший договор)
+	Warnings []DocumentWarning `json:"warnings,omitempty"`
 }
 
 // Edit document
@@ -1,11 +1,13 @@
 package models
 
+import "github.com/rusgainew/tunduck-app/pkg/entity"
+
 // EsfEntriesModel представляет модель записи в электронной счет-фактуре (ЭСФ).
 // Содержит информацию о товаре или услуге, включая коды классификации,
 // количественные и стоимостные показатели, а также данные о налогах.
 type EsfEntriesModel struct {
 	// ID - уникальный идентификатор записи в базе данных
-	ID int `json:"id" valid:"required"`
+	ID int `json:"id"`
 
 	// UnitClassificationCode - код единицы измерения по классификатору
 	// (например: шт, кг, л и т.д.)
@@ -14,33 +16,59 @@ type EsfEntriesModel struct {
 	// SalesTaxCode - код товара или услуги по классификатору
 	// для целей налогообложения
 	SalesTaxCode string `json:"salesTaxCode" valid:"required"`
+	// ForeignName - наименование товара или услуги на иностранном языке
+	ForeignName string `json:"foreignName,omitempty" valid:"max=255"`
+
+	// Names - наименование товара или услуги на языках ru, ky, en
+	Names entity.LocalizedNames `json:"names,omitempty" valid:"omitempty,dive,keys,oneof=ru ky en,endkeys,max=255"`
+
 	// CustomsAuthorityCode - код таможенного органа, через который
 	// прошло оформление товара (если применимо)
-	CustomsAuthorityCode string `json:"customsAuthorityCode" valid:"required"`
+	CustomsAuthorityCode string `json:"customsAuthorityCode"`
 
 	// Quantity - количество товара или объем услуги
 	// в указанных единицах измерения
 	Quantity float64 `json:"quantity" valid:"required"`
 
 	// Price - цена за единицу товара или услуги
-	// без учета налогов
-	Price float64 `json:"price" valid:"required"`
+	// без учета налогов; 0 — взять цену из прайс-листа контрагента
+	Price float64 `json:"price" valid:"gte=0"`
 
 	// VatAmount - сумма налога на добавленную стоимость (НДС)
 	// для данной позиции
-	VatAmount float64 `json:"vatAmount" valid:"required"`
+	VatAmount float64 `json:"vatAmount"`
 
 	// SalesTaxAmount - сумма акцизного налога
 	// для подакцизных товаров
-	SalesTaxAmount float64 `json:"salesTaxAmount" valid:"required"`
+	SalesTaxAmount float64 `json:"salesTaxAmount"`
 
 	// AmountWithoutTaxes - общая сумма за позицию
-	// без учета НДС и акцизов
-	AmountWithoutTaxes float64 `json:"amountWithoutTaxes" valid:"required"`
+	// без учета НДС и акцизов; у позиции без цены считается по цене из прайс-листа
+	AmountWithoutTaxes float64 `json:"amountWithoutTaxes" valid:"required_unless=Price 0"`
 
 	// TotalAmount - итоговая сумма за позицию
-	// с учетом всех налогов
-	TotalAmount float64 `json:"totalAmount" valid:"required"`
+	// с учетом всех налогов; у позиции без цены считается по цене из прайс-листа
+	TotalAmount float64 `json:"totalAmount" valid:"required_unless=Price 0"`
+
+	// DiscountAmount - скидка на позицию в тех же единицах, что и цена
+	// (с налогами или без по IsPriceWithoutTaxes документа)
+	DiscountAmount float64 `json:"discountAmount,omitempty" valid:"gte=0"`
+
+	// SurchargeAmount - наценка на позицию в тех же единицах, что и цена
+	SurchargeAmount float64 `json:"surchargeAmount,omitempty" valid:"gte=0"`
+}
+
+// Adjusted сообщает, есть ли у позиции скидка или наценка
+func (e *EsfEntriesModel) Adjusted() bool {
+	return e.DiscountAmount != 0 || e.SurchargeAmount != 0
+}
+
+// NetPrice цена единицы с учетом скидки и наценки позиции
+func (e *EsfEntriesModel) NetPrice() float64 {
+	if e.Quantity == 0 {
+		return e.Price
+	}
+	return e.Price + (e.SurchargeAmount-e.DiscountAmount)/e.Quantity
 }
 
 // CatalogEntriesModels представляет список товаров и услуг
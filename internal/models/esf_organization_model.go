@@ -1,9 +1,26 @@
 package models
 
+import "time"
+
 type EsfOrganizationModel struct {
 	ID          string `json:"id"`
-	Name        string `json:"name"`
+	Name        string `json:"name" validate:"required,min=2,max=255"`
 	Description string `json:"description"`
 	Token       string `json:"token"`
 	DBName      string `json:"dbName"`
+	// Plan тарифный план; меняется только администратором через /api/admin/organizations/:id/plan
+	Plan string `json:"plan,omitempty"`
+	// Tin ИНН организации: 14 цифр (Кыргызстан) или 10/12 цифр (Россия)
+	Tin string `json:"tin,omitempty" validate:"omitempty,tin"`
+	// Timezone часовой пояс IANA, например Asia/Bishkek; пустой — часовой пояс по умолчанию
+	Timezone string `json:"timezone,omitempty" validate:"max=64"`
+	// Version версия для оптимистичной блокировки: обновление требует текущую версию
+	Version int64 `json:"version"`
+	// CreatedAt время создания; задается сервером
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// OrganizationPlanRequest назначение тарифного плана организации администратором
+type OrganizationPlanRequest struct {
+	Plan string `json:"plan" validate:"required,max=50"`
 }
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// PaymentRequest поступление оплаты по документу
+type PaymentRequest struct {
+	// Дата поступления
+	PaidAt time.Time `json:"paidAt" validate:"required"`
+	// Сумма оплаты
+	Amount float64 `json:"amount" validate:"required,gt=0"`
+	// Способ оплаты
+	Method string `json:"method" validate:"required,oneof=bank_transfer cash card other"`
+	// Номер платежного поручения или чека
+	Reference string `json:"reference" validate:"max=100"`
+	Comment   string `json:"comment" validate:"max=1000"`
+}
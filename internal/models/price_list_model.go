@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// PriceListRequest прайс-лист для одного контрагента или группы покупателей
+type PriceListRequest struct {
+	Name string `json:"name" validate:"required,max=255"`
+	// ИНН контрагента; указывается либо он, либо группа покупателей
+	ContractorTin string `json:"contractorTin" validate:"omitempty,tin"`
+	CustomerGroup string `json:"customerGroup" validate:"max=100"`
+	// Валюта цен
+	CurrencyCode string `json:"currencyCode" validate:"required,len=3"`
+	// Срок действия по дате поставки; пустые даты не ограничивают срок
+	ValidFrom *time.Time           `json:"validFrom"`
+	ValidTo   *time.Time           `json:"validTo"`
+	Items     []PriceListItemModel `json:"items" validate:"dive"`
+}
+
+// PriceListItemModel цена товара или услуги по коду позиции документа
+type PriceListItemModel struct {
+	SalesTaxCode string `json:"salesTaxCode" validate:"required,max=50"`
+	// Единица измерения; пустая подходит к любой
+	UnitClassificationCode string  `json:"unitClassificationCode" validate:"max=20"`
+	Price                  float64 `json:"price" validate:"gt=0"`
+	// Минимальная цена продажи; 0 — без ограничения
+	FloorPrice float64 `json:"floorPrice" validate:"gte=0"`
+}
+
+// CustomerGroupRequest состав группы покупателей
+type CustomerGroupRequest struct {
+	ContractorTins []string `json:"contractorTins" validate:"dive,tin"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "github.com/rusgainew/tunduck-app/pkg/pagination"
+
+// SavedViewRequest именованная комбинация фильтров и сортировки списка документов
+type SavedViewRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+	// Фильтры списка; даты можно задать относительно дня применения: "today", "-30d"
+	Filter pagination.DocumentFilterParams `json:"filter"`
+	// Сортировка как в параметре sort списка, например "-delivery_date,amount"
+	Sort  string `json:"sort" validate:"max=200"`
+	Order string `json:"order" validate:"omitempty,oneof=asc desc"`
+}
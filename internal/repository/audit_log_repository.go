@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+)
+
+// AuditLogFilter условия выборки журнала аудита; пустые поля не фильтруют
+type AuditLogFilter struct {
+	ActorID        string     `json:"actor_id,omitempty"`
+	OrganizationID string     `json:"organization_id,omitempty"`
+	EntityType     string     `json:"entity_type,omitempty"`
+	EntityID       string     `json:"entity_id,omitempty"`
+	Action         string     `json:"action,omitempty"`
+	From           *time.Time `json:"from,omitempty"`
+	To             *time.Time `json:"to,omitempty"`
+}
+
+// AuditLogRepository хранит журнал аудита в основной БД
+type AuditLogRepository interface {
+	Create(ctx context.Context, log *entity.AuditLog) error
+	// List возвращает записи от новых к старым
+	List(ctx context.Context, params pagination.PaginationParams, filter AuditLogFilter) ([]entity.AuditLog, int64, error)
+	// Each обходит записи от старых к новым пачками, не загружая журнал целиком
+	Each(ctx context.Context, filter AuditLogFilter, fn func(*entity.AuditLog) error) error
+}
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+)
+
+// DeadLetterFilter условия выборки очереди недоставленных сообщений; пустые поля не фильтруют
+type DeadLetterFilter struct {
+	Source string
+	Type   string
+	Status string
+}
+
+// DeadLetterRepository хранит сообщения, исчерпавшие попытки, в основной БД
+type DeadLetterRepository interface {
+	Create(ctx context.Context, letter *entity.DeadLetter) error
+	// GetByID возвращает запись или nil, если ее нет
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.DeadLetter, error)
+	// List возвращает записи от новых к старым
+	List(ctx context.Context, params pagination.PaginationParams, filter DeadLetterFilter) ([]entity.DeadLetter, int64, error)
+	// MarkRequeued переводит запись в статус requeued, если она еще в статусе dead.
+	// Возвращает false, если запись уже поставлена в обработку повторно.
+	MarkRequeued(ctx context.Context, id uuid.UUID) (bool, error)
+}
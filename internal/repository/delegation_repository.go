@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// DelegationRepository хранит делегирования права подписи в основной БД
+type DelegationRepository interface {
+	Create(ctx context.Context, delegation *entity.SigningDelegation) error
+	// GetByID возвращает делегирование или nil, если его нет
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.SigningDelegation, error)
+	// ListByUser возвращает делегирования, выданные пользователем или полученные им, новые первыми
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]entity.SigningDelegation, error)
+	// FindActive возвращает неотозванные делегирования пользователю, действующие в день at
+	FindActive(ctx context.Context, delegateID uuid.UUID, at time.Time) ([]entity.SigningDelegation, error)
+	// Revoke отзывает делегирование с момента at
+	Revoke(ctx context.Context, id uuid.UUID, at time.Time) error
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+)
+
+// EmailDeliveryRepository хранит журнал отправки писем в основной БД
+type EmailDeliveryRepository interface {
+	Create(ctx context.Context, delivery *entity.EmailDelivery) error
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.EmailDelivery, error)
+	// List возвращает записи от новых к старым; пустой status — все статусы
+	List(ctx context.Context, params pagination.PaginationParams, status string) ([]entity.EmailDelivery, int64, error)
+	// UpdateStatus фиксирует результат попытки отправки
+	UpdateStatus(ctx context.Context, id uuid.UUID, status string, attempts int, lastError string) error
+	// SetJobID сохраняет идентификатор фоновой задачи отправки
+	SetJobID(ctx context.Context, id uuid.UUID, jobID string) error
+}
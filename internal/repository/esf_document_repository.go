@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rusgainew/tunduck-app/pkg/entity"
@@ -15,6 +16,74 @@ type EsfDocumentRepository interface {
 	UpdateDocument(ctx context.Context, orgID uuid.UUID, doc *entity.EsfDocument) error
 	DeleteDocument(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error
 
+	// Корзина: восстановление и окончательное удаление мягко удаленных документов
+	RestoreDocument(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error
+	PurgeDocument(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error
+	// ListDeletedDocuments возвращает документы, удаленные не раньше since, последние удаленные первыми
+	ListDeletedDocuments(ctx context.Context, orgID uuid.UUID, since time.Time, params pagination.PaginationParams) ([]entity.EsfDocument, int64, error)
+	// GetDeletedDocumentsByIDs возвращает удаленные документы из списка ids (без позиций)
+	GetDeletedDocumentsByIDs(ctx context.Context, orgID uuid.UUID, ids []uuid.UUID) ([]entity.EsfDocument, error)
+	// ListDocumentsDeletedBefore возвращает не больше limit идентификаторов документов, удаленных раньше before
+	ListDocumentsDeletedBefore(ctx context.Context, orgID uuid.UUID, before time.Time, limit int) ([]uuid.UUID, error)
+
+	// CountDocumentsCreatedSince считает документы, созданные с момента since, включая удаленные
+	CountDocumentsCreatedSince(ctx context.Context, orgID uuid.UUID, since time.Time) (int64, error)
+
+	// UpdateDocumentStatus сохраняет статус документа в ЭСФ, полученный от шлюза
+	UpdateDocumentStatus(ctx context.Context, orgID uuid.UUID, id uuid.UUID, status string) error
+
+	// Оплаты документа: поступление увеличивает, а удаление уменьшает PaidAmount и версию документа
+	ListPayments(ctx context.Context, orgID uuid.UUID, documentID uuid.UUID) ([]entity.Payment, error)
+	AddPayment(ctx context.Context, orgID uuid.UUID, payment *entity.Payment) error
+	DeletePayment(ctx context.Context, orgID uuid.UUID, documentID uuid.UUID, paymentID uuid.UUID) error
+
+	// Банковские выписки. FindPaymentCandidates ищет неоплаченные документы по ИНН контрагента
+	// и остатку (пустой ИНН или нулевая сумма не ограничивают поиск); MatchStatementLine
+	// в одной транзакции вносит оплату и отмечает строку выписки сопоставленной
+	CreateBankStatement(ctx context.Context, orgID uuid.UUID, statement *entity.BankStatement) error
+	ListBankStatements(ctx context.Context, orgID uuid.UUID) ([]entity.BankStatement, error)
+	GetBankStatement(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*entity.BankStatement, error)
+	FindPaymentCandidates(ctx context.Context, orgID uuid.UUID, tin string, amount float64, limit int) ([]entity.EsfDocument, error)
+	MatchStatementLine(ctx context.Context, orgID uuid.UUID, statementID uuid.UUID, lineID uuid.UUID, payment *entity.Payment, matchedBy string) error
+
+	// Договоры с контрагентами. ContractUsage возвращает сумму и количество неаннулированных
+	// документов по договору без документа excludeDocID; DeleteContract отказывает, если на договор
+	// ссылаются документы
+	ListContracts(ctx context.Context, orgID uuid.UUID, contractorTin string) ([]entity.Contract, error)
+	GetContract(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*entity.Contract, error)
+	CreateContract(ctx context.Context, orgID uuid.UUID, contract *entity.Contract) error
+	UpdateContract(ctx context.Context, orgID uuid.UUID, contract *entity.Contract) error
+	DeleteContract(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error
+	ContractUsage(ctx context.Context, orgID uuid.UUID, contractID uuid.UUID, excludeDocID uuid.UUID) (float64, int64, error)
+
+	// Прайс-листы и группы покупателей. FindPriceLists возвращает прайс-листы с ценами в валюте
+	// currency, действующие на дату date для контрагента напрямую или через его группы:
+	// сначала прайс-листы контрагента, затем групп, в каждой части — начавшие действовать позже
+	ListPriceLists(ctx context.Context, orgID uuid.UUID) ([]entity.PriceList, error)
+	GetPriceList(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*entity.PriceList, error)
+	CreatePriceList(ctx context.Context, orgID uuid.UUID, list *entity.PriceList) error
+	UpdatePriceList(ctx context.Context, orgID uuid.UUID, list *entity.PriceList) error
+	DeletePriceList(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error
+	FindPriceLists(ctx context.Context, orgID uuid.UUID, contractorTin string, currency string, date time.Time) ([]entity.PriceList, error)
+	ListCustomerGroupMembers(ctx context.Context, orgID uuid.UUID) ([]entity.CustomerGroupMember, error)
+	SetCustomerGroupMembers(ctx context.Context, orgID uuid.UUID, group string, tins []string) error
+
 	// Пагіновані методи
 	GetAllDocumentsPaginated(ctx context.Context, orgID uuid.UUID, params pagination.PaginationParams, filters pagination.DocumentFilterParams) ([]entity.EsfDocument, int64, error)
+	GetAllDocumentsCursor(ctx context.Context, orgID uuid.UUID, params pagination.CursorParams, filters pagination.DocumentFilterParams) ([]entity.EsfDocument, pagination.CursorInfo, error)
+
+	// Полнотекстовый поиск Postgres (используется, если OpenSearch не настроен или недоступен)
+	SearchDocuments(ctx context.Context, orgID uuid.UUID, text string, params pagination.PaginationParams) ([]entity.EsfDocument, int64, error)
+	GetDocumentsByIDs(ctx context.Context, orgID uuid.UUID, ids []uuid.UUID) ([]entity.EsfDocument, error)
+
+	// Поисковый outbox: события индексации пишутся в транзакции изменения документа
+	SetSearchOutbox(enabled bool)
+	FetchSearchOutbox(ctx context.Context, orgID uuid.UUID, limit int) ([]entity.SearchOutboxEntry, error)
+	DeleteSearchOutbox(ctx context.Context, orgID uuid.UUID, ids []int64) error
+
+	// Outbox доменных событий документов в БД организации
+	RelayEvents(ctx context.Context, orgID uuid.UUID, limit int, publish PublishEventFunc) (int, error)
+	PurgePublishedEvents(ctx context.Context, orgID uuid.UUID, before time.Time) (int64, error)
+	// ReplayEvents повторно публикует уже опубликованные события документов организации
+	ReplayEvents(ctx context.Context, filter EventReplayFilter, publish PublishEventFunc) (int, error)
 }
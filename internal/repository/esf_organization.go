@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/rusgainew/tunduck-app/pkg/entity"
 	"github.com/rusgainew/tunduck-app/pkg/pagination"
@@ -16,6 +17,19 @@ type EsfOrganizationRepository interface {
 	Delete(ctx context.Context, id string) error
 	CreateDatabase(ctx context.Context, dbName string) error
 
+	// Корзина: восстановление и окончательное удаление мягко удаленных записей
+	Restore(ctx context.Context, id string) error
+	Purge(ctx context.Context, id string) error
+	// SetPlan назначает организации тарифный план
+	SetPlan(ctx context.Context, id, plan string) error
+
 	// Пагіновані методи
 	GetAllPaginated(ctx context.Context, params pagination.PaginationParams, filters pagination.OrganizationFilterParams) ([]*entity.EstOrganization, int64, error)
+	GetAllCursor(ctx context.Context, params pagination.CursorParams, filters pagination.OrganizationFilterParams) ([]*entity.EstOrganization, pagination.CursorInfo, error)
+
+	// Outbox доменных событий организаций в основной БД
+	RelayEvents(ctx context.Context, limit int, publish PublishEventFunc) (int, error)
+	PurgePublishedEvents(ctx context.Context, before time.Time) (int64, error)
+	// ReplayEvents повторно публикует уже опубликованные события организации filter.OrganizationID
+	ReplayEvents(ctx context.Context, filter EventReplayFilter, publish PublishEventFunc) (int, error)
 }
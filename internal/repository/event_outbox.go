@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// PublishEventFunc публикует событие outbox. Ошибка прерывает пакет: событие и следующие за ним
+// остаются неопубликованными и будут отправлены повторно.
+type PublishEventFunc func(ctx context.Context, event entity.OutboxEvent) error
+
+// EventReplayFilter выборка опубликованных событий outbox для повторной отправки.
+// Диапазон полуоткрытый: From <= occurred_at < To; пустой Types — все типы.
+type EventReplayFilter struct {
+	OrganizationID uuid.UUID
+	From           time.Time
+	To             time.Time
+	Types          []string
+}
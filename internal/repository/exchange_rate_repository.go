@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// ExchangeRateRepository хранит историю официальных курсов валют в основной БД
+type ExchangeRateRepository interface {
+	// SaveRates сохраняет курсы; курс валюты за уже сохраненный день перезаписывается
+	SaveRates(ctx context.Context, rates []entity.ExchangeRate) error
+	// FindRate возвращает последний курс валюты не позже date или nil, если курсов нет
+	FindRate(ctx context.Context, currency string, date time.Time) (*entity.ExchangeRate, error)
+	// ListRates возвращает последний курс каждой валюты не позже date
+	ListRates(ctx context.Context, date time.Time) ([]entity.ExchangeRate, error)
+}
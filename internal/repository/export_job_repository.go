@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// ExportJobRepository хранит фоновые выгрузки в основной БД
+type ExportJobRepository interface {
+	Create(ctx context.Context, job *entity.ExportJob) error
+	// GetByID возвращает выгрузку или nil, если ее нет
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.ExportJob, error)
+	// Update сохраняет изменения полей выгрузки из updates (имена колонок)
+	Update(ctx context.Context, id uuid.UUID, updates map[string]interface{}) error
+}
@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// InboundEventRepository хранит входящие события для дедупликации повторных доставок
+type InboundEventRepository interface {
+	// Claim регистрирует событие для обработки. Возвращает false, если событие с тем же
+	// (Source, EventID) уже обработано или обрабатывается. Событие, обработка которого
+	// завершилась ошибкой или зависла дольше staleAfter, захватывается повторно.
+	Claim(ctx context.Context, event *entity.InboundEvent, staleAfter time.Duration) (bool, error)
+	MarkProcessed(ctx context.Context, id uuid.UUID) error
+	MarkFailed(ctx context.Context, id uuid.UUID, reason string) error
+}
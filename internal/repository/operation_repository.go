@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// OperationRepository хранит длительные операции в основной БД
+type OperationRepository interface {
+	Create(ctx context.Context, op *entity.Operation) error
+	// GetByID возвращает операцию или nil, если ее нет
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Operation, error)
+	// Update сохраняет изменения полей операции из updates (имена колонок)
+	Update(ctx context.Context, id uuid.UUID, updates map[string]interface{}) error
+}
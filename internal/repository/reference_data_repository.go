@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// ReferenceDataRepository предоставляет справочные коды, используемые в документах ЭСФ
+type ReferenceDataRepository interface {
+	// GetCurrencyCodes возвращает коды валют
+	GetCurrencyCodes(ctx context.Context) ([]string, error)
+	// GetVATRateCodes возвращает коды ставок НДС
+	GetVATRateCodes(ctx context.Context) ([]string, error)
+	// GetSalesTaxCodes возвращает коды налога с продаж
+	GetSalesTaxCodes(ctx context.Context) ([]string, error)
+
+	// ListCodes возвращает коды официального справочника, упорядоченные по коду
+	ListCodes(ctx context.Context, directory string) ([]entity.ReferenceCode, error)
+	// ReplaceCodes заменяет содержимое справочника полученными из шлюза кодами
+	ReplaceCodes(ctx context.Context, directory string, codes []entity.ReferenceCode) error
+}
@@ -0,0 +1,120 @@
+package repositorypostgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+	"github.com/rusgainew/tunduck-app/pkg/transaction"
+)
+
+// auditExportBatchSize количество записей, читаемых за один запрос при выгрузке
+const auditExportBatchSize = 500
+
+// auditLogPostgres реализует AuditLogRepository
+type auditLogPostgres struct {
+	db     *gorm.DB
+	logger *logger.Logger
+}
+
+// NewAuditLogRepositoryPostgres создает репозиторий журнала аудита
+func NewAuditLogRepositoryPostgres(db *gorm.DB, log *logrus.Logger) repository.AuditLogRepository {
+	return &auditLogPostgres{
+		db:     db,
+		logger: logger.New(log),
+	}
+}
+
+// Create сохраняет запись журнала
+func (r *auditLogPostgres) Create(ctx context.Context, log *entity.AuditLog) error {
+	if log.ID == uuid.Nil {
+		log.ID = uuid.New()
+	}
+
+	if err := transaction.FromContext(ctx, r.db).Create(log).Error; err != nil {
+		r.logger.Error(ctx, "Failed to create audit log", err, logrus.Fields{"path": log.Path})
+		return apperror.DatabaseError("creating audit log", err)
+	}
+	return nil
+}
+
+// List возвращает страницу журнала
+func (r *auditLogPostgres) List(ctx context.Context, params pagination.PaginationParams, filter repository.AuditLogFilter) ([]entity.AuditLog, int64, error) {
+	db := applyAuditFilter(transaction.FromContext(ctx, r.db).Model(&entity.AuditLog{}), filter)
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		r.logger.Error(ctx, "Failed to count audit logs", err)
+		return nil, 0, apperror.DatabaseError("counting audit logs", err)
+	}
+
+	logs := make([]entity.AuditLog, 0)
+	if err := db.Order("created_at DESC").
+		Offset(params.GetOffset()).
+		Limit(params.GetLimit()).
+		Find(&logs).Error; err != nil {
+		r.logger.Error(ctx, "Failed to fetch audit logs", err)
+		return nil, 0, apperror.DatabaseError("fetching audit logs", err)
+	}
+	return logs, total, nil
+}
+
+// Each читает журнал пачками по ключу (created_at, id), чтобы порядок был хронологическим
+// и новые записи не сдвигали страницы
+func (r *auditLogPostgres) Each(ctx context.Context, filter repository.AuditLogFilter, fn func(*entity.AuditLog) error) error {
+	var last *entity.AuditLog
+	for {
+		db := applyAuditFilter(transaction.FromContext(ctx, r.db).Model(&entity.AuditLog{}), filter)
+		if last != nil {
+			db = db.Where("(created_at, id) > (?, ?)", last.CreatedAt, last.ID)
+		}
+
+		batch := make([]entity.AuditLog, 0, auditExportBatchSize)
+		if err := db.Order("created_at, id").Limit(auditExportBatchSize).Find(&batch).Error; err != nil {
+			r.logger.Error(ctx, "Failed to export audit logs", err)
+			return apperror.DatabaseError("exporting audit logs", err)
+		}
+
+		for i := range batch {
+			if err := fn(&batch[i]); err != nil {
+				return err
+			}
+		}
+		if len(batch) < auditExportBatchSize {
+			return nil
+		}
+		last = &batch[len(batch)-1]
+	}
+}
+
+func applyAuditFilter(db *gorm.DB, filter repository.AuditLogFilter) *gorm.DB {
+	if filter.ActorID != "" {
+		db = db.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.OrganizationID != "" {
+		db = db.Where("organization_id = ?", filter.OrganizationID)
+	}
+	if filter.EntityType != "" {
+		db = db.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		db = db.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.Action != "" {
+		db = db.Where("action = ?", filter.Action)
+	}
+	if filter.From != nil {
+		db = db.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		db = db.Where("created_at < ?", *filter.To)
+	}
+	return db
+}
@@ -0,0 +1,144 @@
+package repositorypostgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// CreateBankStatement сохраняет выписку вместе со строками
+func (edrp *esfDocumentRepositoryPostgres) CreateBankStatement(ctx context.Context, orgID uuid.UUID, statement *entity.BankStatement) error {
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to get organization database", err, logrus.Fields{"org_id": orgID.String()})
+		return apperror.DatabaseError("getting organization database", err)
+	}
+
+	if err := orgDB.WithContext(ctx).Create(statement).Error; err != nil {
+		edrp.logger.Error(ctx, "Failed to create bank statement", err, logrus.Fields{"org_id": orgID.String()})
+		return apperror.DatabaseError("creating bank statement", err)
+	}
+	return nil
+}
+
+// ListBankStatements возвращает выписки организации без строк, новые первыми
+func (edrp *esfDocumentRepositoryPostgres) ListBankStatements(ctx context.Context, orgID uuid.UUID) ([]entity.BankStatement, error) {
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to get organization database", err, logrus.Fields{"org_id": orgID.String()})
+		return nil, apperror.DatabaseError("getting organization database", err)
+	}
+
+	var statements []entity.BankStatement
+	if err := orgDB.WithContext(ctx).Order("created_at DESC").Find(&statements).Error; err != nil {
+		edrp.logger.Error(ctx, "Failed to fetch bank statements", err, logrus.Fields{"org_id": orgID.String()})
+		return nil, apperror.DatabaseError("fetching bank statements", err)
+	}
+	return statements, nil
+}
+
+// GetBankStatement возвращает выписку со строками в порядке файла
+func (edrp *esfDocumentRepositoryPostgres) GetBankStatement(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*entity.BankStatement, error) {
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to get organization database", err, logrus.Fields{"org_id": orgID.String()})
+		return nil, apperror.DatabaseError("getting organization database", err)
+	}
+
+	var statement entity.BankStatement
+	err = orgDB.WithContext(ctx).
+		Preload("Lines", func(db *gorm.DB) *gorm.DB { return db.Order("line_no") }).
+		Where("id = ?", id).
+		First(&statement).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFoundError("bank statement")
+		}
+		edrp.logger.Error(ctx, "Failed to fetch bank statement", err, logrus.Fields{"org_id": orgID.String(), "statement_id": id.String()})
+		return nil, apperror.DatabaseError("fetching bank statement", err)
+	}
+	return &statement, nil
+}
+
+// FindPaymentCandidates ищет неоплаченные документы, которым может соответствовать платеж
+func (edrp *esfDocumentRepositoryPostgres) FindPaymentCandidates(ctx context.Context, orgID uuid.UUID, tin string, amount float64, limit int) ([]entity.EsfDocument, error) {
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to get organization database", err, logrus.Fields{"org_id": orgID.String()})
+		return nil, apperror.DatabaseError("getting organization database", err)
+	}
+
+	db := orgDB.WithContext(ctx).Where(documentRemaining+" >= ?", entity.PaymentTolerance)
+	if tin != "" {
+		db = db.Where("contractor_tin = ?", tin)
+	}
+	if amount > 0 {
+		db = db.Where("ABS("+documentRemaining+" - ?) < ?", amount, entity.PaymentTolerance)
+	}
+
+	var docs []entity.EsfDocument
+	if err := db.Order("delivery_date, created_at").Limit(limit).Find(&docs).Error; err != nil {
+		edrp.logger.Error(ctx, "Failed to fetch payment candidates", err, logrus.Fields{"org_id": orgID.String()})
+		return nil, apperror.DatabaseError("fetching payment candidates", err)
+	}
+	return docs, nil
+}
+
+// MatchStatementLine вносит оплату по строке выписки. Строка блокируется, поэтому одну операцию
+// нельзя зачесть дважды при одновременном сопоставлении
+func (edrp *esfDocumentRepositoryPostgres) MatchStatementLine(ctx context.Context, orgID uuid.UUID, statementID uuid.UUID, lineID uuid.UUID, payment *entity.Payment, matchedBy string) error {
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to get organization database", err, logrus.Fields{"org_id": orgID.String()})
+		return apperror.DatabaseError("getting organization database", err)
+	}
+
+	err = orgDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var line entity.BankStatementLine
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND statement_id = ?", lineID, statementID).
+			First(&line).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.NotFoundError("statement line")
+			}
+			return apperror.DatabaseError("fetching statement line", err)
+		}
+		if line.Status == entity.StatementLineMatched {
+			return apperror.ConflictError("statement line is already matched")
+		}
+
+		if err := edrp.addPayment(tx, orgID, payment); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := tx.Model(&line).Updates(map[string]interface{}{
+			"status":      entity.StatementLineMatched,
+			"document_id": payment.DocumentID,
+			"payment_id":  payment.ID,
+			"matched_by":  matchedBy,
+			"matched_at":  now,
+		}).Error; err != nil {
+			return apperror.DatabaseError("updating statement line", err)
+		}
+		if err := tx.Model(&entity.BankStatement{}).
+			Where("id = ?", statementID).
+			Update("matched_count", gorm.Expr("matched_count + 1")).Error; err != nil {
+			return apperror.DatabaseError("updating bank statement", err)
+		}
+		return nil
+	})
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to match statement line", err, logrus.Fields{"org_id": orgID.String(), "line_id": lineID.String()})
+		return apperror.DatabaseErrorFrom("matching statement line", err)
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package repositorypostgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// ListContracts возвращает договоры организации, при непустом contractorTin — только этого контрагента
+func (edrp *esfDocumentRepositoryPostgres) ListContracts(ctx context.Context, orgID uuid.UUID, contractorTin string) ([]entity.Contract, error) {
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to get organization database", err, logrus.Fields{"org_id": orgID.String()})
+		return nil, apperror.DatabaseError("getting organization database", err)
+	}
+
+	db := orgDB.WithContext(ctx)
+	if contractorTin != "" {
+		db = db.Where("contractor_tin = ?", contractorTin)
+	}
+	var contracts []entity.Contract
+	if err := db.Order("start_date DESC, number").Find(&contracts).Error; err != nil {
+		edrp.logger.Error(ctx, "Failed to fetch contracts", err, logrus.Fields{"org_id": orgID.String()})
+		return nil, apperror.DatabaseError("fetching contracts", err)
+	}
+	return contracts, nil
+}
+
+// GetContract возвращает договор по ID
+func (edrp *esfDocumentRepositoryPostgres) GetContract(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*entity.Contract, error) {
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to get organization database", err, logrus.Fields{"org_id": orgID.String()})
+		return nil, apperror.DatabaseError("getting organization database", err)
+	}
+
+	var contract entity.Contract
+	if err := orgDB.WithContext(ctx).Where("id = ?", id).First(&contract).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFoundError("contract")
+		}
+		edrp.logger.Error(ctx, "Failed to fetch contract", err, logrus.Fields{"org_id": orgID.String(), "contract_id": id.String()})
+		return nil, apperror.DatabaseError("fetching contract", err)
+	}
+	return &contract, nil
+}
+
+// CreateContract сохраняет договор; номер договора уникален в пределах контрагента
+func (edrp *esfDocumentRepositoryPostgres) CreateContract(ctx context.Context, orgID uuid.UUID, contract *entity.Contract) error {
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to get organization database", err, logrus.Fields{"org_id": orgID.String()})
+		return apperror.DatabaseError("getting organization database", err)
+	}
+
+	err = orgDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureContractNumberFree(tx, contract); err != nil {
+			return err
+		}
+		if err := tx.Create(contract).Error; err != nil {
+			return apperror.DatabaseError("creating contract", err)
+		}
+		return nil
+	})
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to create contract", err, logrus.Fields{"org_id": orgID.String()})
+		return apperror.DatabaseErrorFrom("creating contract", err)
+	}
+	return nil
+}
+
+// UpdateContract сохраняет изменения договора
+func (edrp *esfDocumentRepositoryPostgres) UpdateContract(ctx context.Context, orgID uuid.UUID, contract *entity.Contract) error {
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to get organization database", err, logrus.Fields{"org_id": orgID.String()})
+		return apperror.DatabaseError("getting organization database", err)
+	}
+
+	err = orgDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := ensureContractNumberFree(tx, contract); err != nil {
+			return err
+		}
+		result := tx.Model(&entity.Contract{}).Where("id = ?", contract.ID).Updates(map[string]interface{}{
+			"number":         contract.Number,
+			"contractor_tin": contract.ContractorTin,
+			"start_date":     contract.StartDate,
+			"end_date":       contract.EndDate,
+			"currency_code":  contract.CurrencyCode,
+			"amount_limit":   contract.AmountLimit,
+			"comment":        contract.Comment,
+		})
+		if result.Error != nil {
+			return apperror.DatabaseError("updating contract", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return apperror.NotFoundError("contract")
+		}
+		return nil
+	})
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to update contract", err, logrus.Fields{"org_id": orgID.String(), "contract_id": contract.ID.String()})
+		return apperror.DatabaseErrorFrom("updating contract", err)
+	}
+	return nil
+}
+
+// DeleteContract удаляет договор, на который не ссылается ни один документ, включая документы в корзине
+func (edrp *esfDocumentRepositoryPostgres) DeleteContract(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error {
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to get organization database", err, logrus.Fields{"org_id": orgID.String()})
+		return apperror.DatabaseError("getting organization database", err)
+	}
+
+	err = orgDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Unscoped().Model(&entity.EsfDocument{}).Where("contract_id = ?", id).Count(&count).Error; err != nil {
+			return apperror.DatabaseError("counting contract documents", err)
+		}
+		if count > 0 {
+			return apperror.ConflictError("contract is referenced by documents")
+		}
+		result := tx.Where("id = ?", id).Delete(&entity.Contract{})
+		if result.Error != nil {
+			return apperror.DatabaseError("deleting contract", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return apperror.NotFoundError("contract")
+		}
+		return nil
+	})
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to delete contract", err, logrus.Fields{"org_id": orgID.String(), "contract_id": id.String()})
+		return apperror.DatabaseErrorFrom("deleting contract", err)
+	}
+	return nil
+}
+
+// ContractUsage возвращает сумму и количество документов по договору без аннулированных
+// и без документа excludeDocID
+func (edrp *esfDocumentRepositoryPostgres) ContractUsage(ctx context.Context, orgID uuid.UUID, contractID uuid.UUID, excludeDocID uuid.UUID) (float64, int64, error) {
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to get organization database", err, logrus.Fields{"org_id": orgID.String()})
+		return 0, 0, apperror.DatabaseError("getting organization database", err)
+	}
+
+	var usage struct {
+		Amount float64
+		Count  int64
+	}
+	err = orgDB.WithContext(ctx).Model(&entity.EsfDocument{}).
+		Select("COALESCE(SUM(total_currency_value), 0) AS amount, COUNT(*) AS count").
+		Where("contract_id = ? AND id <> ? AND esf_status IS DISTINCT FROM ?", contractID, excludeDocID, entity.EsfStatusCancelled).
+		Scan(&usage).Error
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to fetch contract usage", err, logrus.Fields{"org_id": orgID.String(), "contract_id": contractID.String()})
+		return 0, 0, apperror.DatabaseError("fetching contract usage", err)
+	}
+	return usage.Amount, usage.Count, nil
+}
+
+// ensureContractNumberFree проверяет, что у контрагента нет другого договора с тем же номером
+func ensureContractNumberFree(tx *gorm.DB, contract *entity.Contract) error {
+	var count int64
+	err := tx.Model(&entity.Contract{}).
+		Where("contractor_tin = ? AND number = ? AND id <> ?", contract.ContractorTin, contract.Number, contract.ID).
+		Count(&count).Error
+	if err != nil {
+		return apperror.DatabaseError("checking contract number", err)
+	}
+	if count > 0 {
+		return apperror.ConflictError("contract with this number already exists for the contractor")
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package repositorypostgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+	"github.com/rusgainew/tunduck-app/pkg/transaction"
+)
+
+// deadLetterPostgres реализует DeadLetterRepository
+type deadLetterPostgres struct {
+	db     *gorm.DB
+	logger *logger.Logger
+}
+
+// NewDeadLetterRepositoryPostgres создает репозиторий очереди недоставленных сообщений
+func NewDeadLetterRepositoryPostgres(db *gorm.DB, log *logrus.Logger) repository.DeadLetterRepository {
+	return &deadLetterPostgres{
+		db:     db,
+		logger: logger.New(log),
+	}
+}
+
+// Create сохраняет новую запись
+func (r *deadLetterPostgres) Create(ctx context.Context, letter *entity.DeadLetter) error {
+	if letter.ID == uuid.Nil {
+		letter.ID = uuid.New()
+	}
+	if letter.Status == "" {
+		letter.Status = entity.DeadLetterStatusDead
+	}
+
+	if err := transaction.FromContext(ctx, r.db).Create(letter).Error; err != nil {
+		r.logger.Error(ctx, "Failed to create dead letter", err, logrus.Fields{
+			"source":    letter.Source,
+			"reference": letter.Reference,
+		})
+		return apperror.DatabaseError("creating dead letter", err)
+	}
+	return nil
+}
+
+// GetByID возвращает запись или nil, если ее нет
+func (r *deadLetterPostgres) GetByID(ctx context.Context, id uuid.UUID) (*entity.DeadLetter, error) {
+	var letter entity.DeadLetter
+	err := transaction.FromContext(ctx, r.db).Where("id = ?", id).First(&letter).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logger.Error(ctx, "Failed to fetch dead letter", err, logrus.Fields{"dead_letter_id": id.String()})
+		return nil, apperror.DatabaseError("fetching dead letter", err)
+	}
+	return &letter, nil
+}
+
+// List возвращает страницу записей
+func (r *deadLetterPostgres) List(ctx context.Context, params pagination.PaginationParams, filter repository.DeadLetterFilter) ([]entity.DeadLetter, int64, error) {
+	db := transaction.FromContext(ctx, r.db).Model(&entity.DeadLetter{})
+	if filter.Source != "" {
+		db = db.Where("source = ?", filter.Source)
+	}
+	if filter.Type != "" {
+		db = db.Where("type = ?", filter.Type)
+	}
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		r.logger.Error(ctx, "Failed to count dead letters", err)
+		return nil, 0, apperror.DatabaseError("counting dead letters", err)
+	}
+
+	letters := make([]entity.DeadLetter, 0)
+	if err := db.Order("created_at DESC").
+		Offset(params.GetOffset()).
+		Limit(params.GetLimit()).
+		Find(&letters).Error; err != nil {
+		r.logger.Error(ctx, "Failed to fetch dead letters", err)
+		return nil, 0, apperror.DatabaseError("fetching dead letters", err)
+	}
+	return letters, total, nil
+}
+
+// MarkRequeued переводит запись из dead в requeued; условие по статусу защищает
+// от повторной постановки при параллельных запросах
+func (r *deadLetterPostgres) MarkRequeued(ctx context.Context, id uuid.UUID) (bool, error) {
+	now := time.Now()
+	result := transaction.FromContext(ctx, r.db).Model(&entity.DeadLetter{}).
+		Where("id = ? AND status = ?", id, entity.DeadLetterStatusDead).
+		Updates(map[string]interface{}{
+			"status":        entity.DeadLetterStatusRequeued,
+			"requeue_count": gorm.Expr("requeue_count + 1"),
+			"requeued_at":   now,
+			"updated_at":    now,
+		})
+	if result.Error != nil {
+		r.logger.Error(ctx, "Failed to mark dead letter as requeued", result.Error, logrus.Fields{"dead_letter_id": id.String()})
+		return false, apperror.DatabaseError("updating dead letter", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -0,0 +1,97 @@
+package repositorypostgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/transaction"
+)
+
+// delegationPostgres реализует DelegationRepository
+type delegationPostgres struct {
+	db     *gorm.DB
+	logger *logger.Logger
+}
+
+// NewDelegationRepositoryPostgres создает репозиторий делегирований права подписи
+func NewDelegationRepositoryPostgres(db *gorm.DB, log *logrus.Logger) repository.DelegationRepository {
+	return &delegationPostgres{
+		db:     db,
+		logger: logger.New(log),
+	}
+}
+
+// Create сохраняет делегирование
+func (r *delegationPostgres) Create(ctx context.Context, delegation *entity.SigningDelegation) error {
+	if err := transaction.FromContext(ctx, r.db).Create(delegation).Error; err != nil {
+		r.logger.Error(ctx, "Failed to create signing delegation", err, logrus.Fields{"delegator_id": delegation.DelegatorID.String()})
+		return apperror.DatabaseError("creating signing delegation", err)
+	}
+	return nil
+}
+
+// GetByID возвращает делегирование или nil
+func (r *delegationPostgres) GetByID(ctx context.Context, id uuid.UUID) (*entity.SigningDelegation, error) {
+	var delegation entity.SigningDelegation
+	err := transaction.FromContext(ctx, r.db).Where("id = ?", id).First(&delegation).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		r.logger.Error(ctx, "Failed to fetch signing delegation", err, logrus.Fields{"delegation_id": id.String()})
+		return nil, apperror.DatabaseError("fetching signing delegation", err)
+	}
+	return &delegation, nil
+}
+
+// ListByUser возвращает делегирования, выданные пользователем или полученные им
+func (r *delegationPostgres) ListByUser(ctx context.Context, userID uuid.UUID) ([]entity.SigningDelegation, error) {
+	var delegations []entity.SigningDelegation
+	err := transaction.FromContext(ctx, r.db).
+		Where("delegator_id = ? OR delegate_id = ?", userID, userID).
+		Order("created_at DESC").
+		Find(&delegations).Error
+	if err != nil {
+		r.logger.Error(ctx, "Failed to list signing delegations", err, logrus.Fields{"user_id": userID.String()})
+		return nil, apperror.DatabaseError("listing signing delegations", err)
+	}
+	return delegations, nil
+}
+
+// FindActive возвращает неотозванные делегирования пользователю, срок которых включает день at
+func (r *delegationPostgres) FindActive(ctx context.Context, delegateID uuid.UUID, at time.Time) ([]entity.SigningDelegation, error) {
+	day := at.Format("2006-01-02")
+	var delegations []entity.SigningDelegation
+	err := transaction.FromContext(ctx, r.db).
+		Where("delegate_id = ? AND valid_from <= ? AND valid_to >= ?", delegateID, day, day).
+		Where("revoked_at IS NULL OR revoked_at > ?", at).
+		Order("valid_from").
+		Find(&delegations).Error
+	if err != nil {
+		r.logger.Error(ctx, "Failed to find active signing delegations", err, logrus.Fields{"delegate_id": delegateID.String()})
+		return nil, apperror.DatabaseError("finding signing delegations", err)
+	}
+	return delegations, nil
+}
+
+// Revoke отзывает делегирование, если оно еще не отозвано
+func (r *delegationPostgres) Revoke(ctx context.Context, id uuid.UUID, at time.Time) error {
+	err := transaction.FromContext(ctx, r.db).
+		Model(&entity.SigningDelegation{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", at).Error
+	if err != nil {
+		r.logger.Error(ctx, "Failed to revoke signing delegation", err, logrus.Fields{"delegation_id": id.String()})
+		return apperror.DatabaseError("revoking signing delegation", err)
+	}
+	return nil
+}
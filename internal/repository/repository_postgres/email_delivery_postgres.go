@@ -0,0 +1,118 @@
+package repositorypostgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+	"github.com/rusgainew/tunduck-app/pkg/transaction"
+)
+
+// emailDeliveryPostgres реализует EmailDeliveryRepository
+type emailDeliveryPostgres struct {
+	db     *gorm.DB
+	logger *logger.Logger
+}
+
+// NewEmailDeliveryRepositoryPostgres создает репозиторий журнала отправки писем
+func NewEmailDeliveryRepositoryPostgres(db *gorm.DB, log *logrus.Logger) repository.EmailDeliveryRepository {
+	return &emailDeliveryPostgres{
+		db:     db,
+		logger: logger.New(log),
+	}
+}
+
+// Create сохраняет новую запись о доставке
+func (r *emailDeliveryPostgres) Create(ctx context.Context, delivery *entity.EmailDelivery) error {
+	if delivery.ID == uuid.Nil {
+		delivery.ID = uuid.New()
+	}
+
+	if err := transaction.FromContext(ctx, r.db).Create(delivery).Error; err != nil {
+		r.logger.Error(ctx, "Failed to create email delivery", err, logrus.Fields{"template": delivery.Template})
+		return apperror.DatabaseError("creating email delivery", err)
+	}
+	return nil
+}
+
+// GetByID возвращает запись о доставке или nil, если ее нет
+func (r *emailDeliveryPostgres) GetByID(ctx context.Context, id uuid.UUID) (*entity.EmailDelivery, error) {
+	var delivery entity.EmailDelivery
+	err := transaction.FromContext(ctx, r.db).Where("id = ?", id).First(&delivery).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logger.Error(ctx, "Failed to fetch email delivery", err, logrus.Fields{"delivery_id": id.String()})
+		return nil, apperror.DatabaseError("fetching email delivery", err)
+	}
+	return &delivery, nil
+}
+
+// List возвращает страницу журнала доставки
+func (r *emailDeliveryPostgres) List(ctx context.Context, params pagination.PaginationParams, status string) ([]entity.EmailDelivery, int64, error) {
+	db := transaction.FromContext(ctx, r.db).Model(&entity.EmailDelivery{})
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		r.logger.Error(ctx, "Failed to count email deliveries", err, logrus.Fields{"status": status})
+		return nil, 0, apperror.DatabaseError("counting email deliveries", err)
+	}
+
+	deliveries := make([]entity.EmailDelivery, 0)
+	if err := db.Order("created_at DESC").
+		Offset(params.GetOffset()).
+		Limit(params.GetLimit()).
+		Find(&deliveries).Error; err != nil {
+		r.logger.Error(ctx, "Failed to fetch email deliveries", err, logrus.Fields{"status": status})
+		return nil, 0, apperror.DatabaseError("fetching email deliveries", err)
+	}
+	return deliveries, total, nil
+}
+
+// UpdateStatus обновляет статус, счетчик попыток и последнюю ошибку; для "sent" проставляет sent_at
+func (r *emailDeliveryPostgres) UpdateStatus(ctx context.Context, id uuid.UUID, status string, attempts int, lastError string) error {
+	updates := map[string]interface{}{
+		"status":     status,
+		"attempts":   attempts,
+		"last_error": lastError,
+		"updated_at": time.Now(),
+	}
+	if status == entity.EmailStatusSent {
+		updates["sent_at"] = time.Now()
+	}
+
+	if err := transaction.FromContext(ctx, r.db).Model(&entity.EmailDelivery{}).
+		Where("id = ?", id).
+		Updates(updates).Error; err != nil {
+		r.logger.Error(ctx, "Failed to update email delivery status", err, logrus.Fields{
+			"delivery_id": id.String(),
+			"status":      status,
+		})
+		return apperror.DatabaseError("updating email delivery status", err)
+	}
+	return nil
+}
+
+// SetJobID сохраняет идентификатор задачи отправки
+func (r *emailDeliveryPostgres) SetJobID(ctx context.Context, id uuid.UUID, jobID string) error {
+	if err := transaction.FromContext(ctx, r.db).Model(&entity.EmailDelivery{}).
+		Where("id = ?", id).
+		Update("job_id", jobID).Error; err != nil {
+		r.logger.Error(ctx, "Failed to set email delivery job", err, logrus.Fields{"delivery_id": id.String()})
+		return apperror.DatabaseError("updating email delivery job", err)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package repositorypostgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/events"
+)
+
+// ListPayments возвращает оплаты документа по дате поступления
+func (edrp *esfDocumentRepositoryPostgres) ListPayments(ctx context.Context, orgID uuid.UUID, documentID uuid.UUID) ([]entity.Payment, error) {
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to get organization database", err, logrus.Fields{"org_id": orgID.String()})
+		return nil, apperror.DatabaseError("getting organization database", err)
+	}
+
+	var payments []entity.Payment
+	if err := orgDB.WithContext(ctx).
+		Where("document_id = ?", documentID).
+		Order("paid_at, created_at").
+		Find(&payments).Error; err != nil {
+		edrp.logger.Error(ctx, "Failed to fetch payments", err, logrus.Fields{"org_id": orgID.String(), "doc_id": documentID.String()})
+		return nil, apperror.DatabaseError("fetching payments", err)
+	}
+	return payments, nil
+}
+
+// AddPayment сохраняет оплату и увеличивает оплаченную сумму документа в одной транзакции
+func (edrp *esfDocumentRepositoryPostgres) AddPayment(ctx context.Context, orgID uuid.UUID, payment *entity.Payment) error {
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to get organization database", err, logrus.Fields{"org_id": orgID.String()})
+		return apperror.DatabaseError("getting organization database", err)
+	}
+
+	err = orgDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return edrp.addPayment(tx, orgID, payment)
+	})
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to add payment", err, logrus.Fields{"org_id": orgID.String(), "doc_id": payment.DocumentID.String()})
+		return apperror.DatabaseErrorFrom("adding payment", err)
+	}
+	return nil
+}
+
+// addPayment сохраняет оплату в транзакции tx
+func (edrp *esfDocumentRepositoryPostgres) addPayment(tx *gorm.DB, orgID uuid.UUID, payment *entity.Payment) error {
+	paid, err := edrp.changePaidAmount(tx, payment.DocumentID, payment.Amount)
+	if err != nil {
+		return err
+	}
+	if err := tx.Create(payment).Error; err != nil {
+		return apperror.DatabaseError("creating payment", err)
+	}
+	return recordEvent(tx, events.DocumentPaymentRecorded, events.AggregateDocument, payment.DocumentID, orgID, events.PaymentPayload{
+		ID: payment.DocumentID, PaymentID: payment.ID, Amount: payment.Amount, PaidAmount: paid,
+	})
+}
+
+// DeletePayment удаляет оплату и уменьшает оплаченную сумму документа
+func (edrp *esfDocumentRepositoryPostgres) DeletePayment(ctx context.Context, orgID uuid.UUID, documentID uuid.UUID, paymentID uuid.UUID) error {
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to get organization database", err, logrus.Fields{"org_id": orgID.String()})
+		return apperror.DatabaseError("getting organization database", err)
+	}
+
+	err = orgDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var payment entity.Payment
+		if err := tx.Where("id = ? AND document_id = ?", paymentID, documentID).First(&payment).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.NotFoundError("payment")
+			}
+			return apperror.DatabaseError("fetching payment", err)
+		}
+		paid, err := edrp.changePaidAmount(tx, documentID, -payment.Amount)
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(&payment).Error; err != nil {
+			return apperror.DatabaseError("deleting payment", err)
+		}
+		return recordEvent(tx, events.DocumentPaymentDeleted, events.AggregateDocument, documentID, orgID, events.PaymentPayload{
+			ID: documentID, PaymentID: paymentID, Amount: payment.Amount, PaidAmount: paid,
+		})
+	})
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to delete payment", err, logrus.Fields{"org_id": orgID.String(), "doc_id": documentID.String()})
+		return apperror.DatabaseErrorFrom("deleting payment", err)
+	}
+	return nil
+}
+
+// changePaidAmount изменяет оплаченную сумму документа на delta и увеличивает версию:
+// клиент, редактирующий документ со старой оплатой, получит конфликт версий
+func (edrp *esfDocumentRepositoryPostgres) changePaidAmount(tx *gorm.DB, documentID uuid.UUID, delta float64) (float64, error) {
+	var doc entity.EsfDocument
+	result := tx.Model(&doc).
+		Where("id = ?", documentID).
+		Updates(map[string]interface{}{
+			"paid_amount": gorm.Expr("paid_amount + ?", delta),
+			"version":     gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return 0, apperror.DatabaseError("updating paid amount", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return 0, apperror.New(apperror.ErrDocumentNotFound, "document not found")
+	}
+	if err := tx.Select("paid_amount").Where("id = ?", documentID).First(&doc).Error; err != nil {
+		return 0, apperror.DatabaseError("fetching paid amount", err)
+	}
+	return doc.PaidAmount, nil
+}
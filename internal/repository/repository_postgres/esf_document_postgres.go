@@ -2,9 +2,11 @@ package repositorypostgres
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
@@ -14,8 +16,10 @@ import (
 	"github.com/rusgainew/tunduck-app/internal/repository"
 	"github.com/rusgainew/tunduck-app/pkg/apperror"
 	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/events"
 	"github.com/rusgainew/tunduck-app/pkg/logger"
 	"github.com/rusgainew/tunduck-app/pkg/pagination"
+	"github.com/rusgainew/tunduck-app/pkg/query"
 )
 
 type esfDocumentRepositoryPostgres struct {
@@ -23,6 +27,9 @@ type esfDocumentRepositoryPostgres struct {
 	baseDB  *gorm.DB
 	dbCache map[string]*gorm.DB
 	cacheMu sync.RWMutex
+
+	// searchOutbox включает запись событий индексации в search_outbox
+	searchOutbox bool
 }
 
 func NewEsfDocumentRepositoryPostgres(db *gorm.DB, log *logrus.Logger) repository.EsfDocumentRepository {
@@ -33,7 +40,7 @@ func NewEsfDocumentRepositoryPostgres(db *gorm.DB, log *logrus.Logger) repositor
 	}
 }
 
-// GetAllDocuments возвращает все документы ЭСФ
+// GetAllDocuments возвращает все документы ЭСФ, новые первыми
 func (edrp *esfDocumentRepositoryPostgres) GetAllDocuments(ctx context.Context, orgID uuid.UUID) ([]entity.EsfDocument, error) {
 	edrp.logger.Debug(ctx, "Fetching all documents from organization database", logrus.Fields{"org_id": orgID.String()})
 
@@ -47,6 +54,7 @@ func (edrp *esfDocumentRepositoryPostgres) GetAllDocuments(ctx context.Context,
 
 	err = orgDB.WithContext(ctx).
 		Preload("CatalogEntries").
+		Order("created_at desc").Order("id desc").
 		Find(&documents).Error
 
 	if err != nil {
@@ -98,7 +106,15 @@ func (edrp *esfDocumentRepositoryPostgres) CreateDocument(ctx context.Context, o
 		return apperror.DatabaseError("getting organization database", err)
 	}
 
-	err = orgDB.WithContext(ctx).Create(doc).Error
+	err = orgDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(doc).Error; err != nil {
+			return err
+		}
+		if err := recordEvent(tx, events.DocumentCreated, events.AggregateDocument, doc.ID, orgID, doc); err != nil {
+			return err
+		}
+		return edrp.enqueueSearch(tx, doc.ID, entity.SearchOpIndex)
+	})
 
 	if err != nil {
 		edrp.logger.Error(ctx, "Failed to create document in database", err, logrus.Fields{"org_id": orgID.String(), "doc_id": doc.ID.String()})
@@ -119,13 +135,20 @@ func (edrp *esfDocumentRepositoryPostgres) UpdateDocument(ctx context.Context, o
 		return apperror.DatabaseError("getting organization database", err)
 	}
 
+	expectedVersion := doc.Version
+	doc.Version = expectedVersion + 1
+
 	err = orgDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
-		// Обновляем основной документ
-		if err := tx.Model(&entity.EsfDocument{}).
-			Where("id = ?", doc.ID).
-			Updates(doc).Error; err != nil {
-			edrp.logger.Error(ctx, "Failed to update document model", err, logrus.Fields{"doc_id": doc.ID.String()})
-			return err
+		// Обновляем основной документ, только если версия не изменилась с момента чтения
+		result := tx.Model(&entity.EsfDocument{}).
+			Where("id = ? AND version = ?", doc.ID, expectedVersion).
+			Updates(doc)
+		if result.Error != nil {
+			edrp.logger.Error(ctx, "Failed to update document model", result.Error, logrus.Fields{"doc_id": doc.ID.String()})
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return edrp.updateMissError(ctx, tx, doc.ID)
 		}
 
 		// Удаляем старые записи CatalogEntries
@@ -143,18 +166,36 @@ func (edrp *esfDocumentRepositoryPostgres) UpdateDocument(ctx context.Context, o
 			}
 		}
 
-		return nil
+		if err := recordEvent(tx, events.DocumentUpdated, events.AggregateDocument, doc.ID, orgID, doc); err != nil {
+			return err
+		}
+		return edrp.enqueueSearch(tx, doc.ID, entity.SearchOpIndex)
 	})
 
 	if err != nil {
+		doc.Version = expectedVersion
 		edrp.logger.Error(ctx, "Failed to update document in database (transaction failed)", err, logrus.Fields{"org_id": orgID.String(), "doc_id": doc.ID.String()})
-		return apperror.DatabaseError("updating document", err)
+		return apperror.DatabaseErrorFrom("updating document", err)
 	}
 
 	edrp.logger.Debug(ctx, "Document updated successfully", logrus.Fields{"org_id": orgID.String(), "doc_id": doc.ID.String()})
 	return nil
 }
 
+// updateMissError различает отсутствующий документ и конфликт версий
+func (edrp *esfDocumentRepositoryPostgres) updateMissError(ctx context.Context, tx *gorm.DB, id uuid.UUID) error {
+	var count int64
+	if err := tx.Model(&entity.EsfDocument{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return apperror.DatabaseError("checking document version", err)
+	}
+	if count == 0 {
+		return apperror.New(apperror.ErrDocumentNotFound, "document not found")
+	}
+
+	edrp.logger.Warn(ctx, "Document version conflict", logrus.Fields{"doc_id": id.String()})
+	return apperror.New(apperror.ErrVersionConflict, "document was modified by another user, reload it and retry")
+}
+
 // DeleteDocument удаляет документ ЭСФ (soft delete)
 func (edrp *esfDocumentRepositoryPostgres) DeleteDocument(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error {
 	edrp.logger.Debug(ctx, "Deleting document from organization database", logrus.Fields{"org_id": orgID.String(), "doc_id": id.String()})
@@ -165,7 +206,15 @@ func (edrp *esfDocumentRepositoryPostgres) DeleteDocument(ctx context.Context, o
 		return apperror.DatabaseError("getting organization database", err)
 	}
 
-	err = orgDB.WithContext(ctx).Delete(&entity.EsfDocument{}, id).Error
+	err = orgDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&entity.EsfDocument{}, id).Error; err != nil {
+			return err
+		}
+		if err := recordEvent(tx, events.DocumentDeleted, events.AggregateDocument, id, orgID, events.Ref{ID: id}); err != nil {
+			return err
+		}
+		return edrp.enqueueSearch(tx, id, entity.SearchOpDelete)
+	})
 
 	if err != nil {
 		edrp.logger.Error(ctx, "Failed to delete document from database", err, logrus.Fields{"org_id": orgID.String(), "doc_id": id.String()})
@@ -176,6 +225,139 @@ func (edrp *esfDocumentRepositoryPostgres) DeleteDocument(ctx context.Context, o
 	return nil
 }
 
+// RestoreDocument восстанавливает мягко удаленный документ ЭСФ
+func (edrp *esfDocumentRepositoryPostgres) RestoreDocument(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error {
+	edrp.logger.Debug(ctx, "Restoring document in organization database", logrus.Fields{"org_id": orgID.String(), "doc_id": id.String()})
+
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to get organization database", err, logrus.Fields{"org_id": orgID.String()})
+		return apperror.DatabaseError("getting organization database", err)
+	}
+
+	err = orgDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Model(&entity.EsfDocument{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Update("deleted_at", nil)
+		if result.Error != nil {
+			return apperror.DatabaseError("restoring document", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return apperror.New(apperror.ErrDocumentNotFound, "deleted document not found")
+		}
+		if err := recordEvent(tx, events.DocumentRestored, events.AggregateDocument, id, orgID, events.Ref{ID: id}); err != nil {
+			return apperror.DatabaseError("restoring document", err)
+		}
+		if err := edrp.enqueueSearch(tx, id, entity.SearchOpIndex); err != nil {
+			return apperror.DatabaseError("restoring document", err)
+		}
+		return nil
+	})
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to restore document", err, logrus.Fields{"org_id": orgID.String(), "doc_id": id.String()})
+		return err
+	}
+
+	edrp.logger.Debug(ctx, "Document restored successfully", logrus.Fields{"org_id": orgID.String(), "doc_id": id.String()})
+	return nil
+}
+
+// PurgeDocument окончательно удаляет мягко удаленный документ ЭСФ вместе с его позициями
+func (edrp *esfDocumentRepositoryPostgres) PurgeDocument(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error {
+	edrp.logger.Debug(ctx, "Purging document from organization database", logrus.Fields{"org_id": orgID.String(), "doc_id": id.String()})
+
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to get organization database", err, logrus.Fields{"org_id": orgID.String()})
+		return apperror.DatabaseError("getting organization database", err)
+	}
+
+	err = orgDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).Delete(&entity.EsfDocument{})
+		if result.Error != nil {
+			return apperror.DatabaseError("purging document", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return apperror.New(apperror.ErrDocumentNotFound, "deleted document not found")
+		}
+
+		if err := tx.Unscoped().Where("document_id = ?", id).Delete(&entity.EsfEntries{}).Error; err != nil {
+			return apperror.DatabaseError("purging document entries", err)
+		}
+		if err := recordEvent(tx, events.DocumentPurged, events.AggregateDocument, id, orgID, events.Ref{ID: id}); err != nil {
+			return apperror.DatabaseError("purging document", err)
+		}
+		if err := edrp.enqueueSearch(tx, id, entity.SearchOpDelete); err != nil {
+			return apperror.DatabaseError("purging document", err)
+		}
+		return nil
+	})
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to purge document", err, logrus.Fields{"org_id": orgID.String(), "doc_id": id.String()})
+		return err
+	}
+
+	edrp.logger.Debug(ctx, "Document purged successfully", logrus.Fields{"org_id": orgID.String(), "doc_id": id.String()})
+	return nil
+}
+
+// UpdateDocumentStatus сохраняет статус документа в ЭСФ. Версия документа не меняется:
+// статус приходит от шлюза и не должен конфликтовать с правками пользователя.
+// Повторная установка того же статуса не создает события.
+func (edrp *esfDocumentRepositoryPostgres) UpdateDocumentStatus(ctx context.Context, orgID uuid.UUID, id uuid.UUID, status string) error {
+	edrp.logger.Debug(ctx, "Updating document status in organization database", logrus.Fields{"org_id": orgID.String(), "doc_id": id.String(), "status": status})
+
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to get organization database", err, logrus.Fields{"org_id": orgID.String()})
+		return apperror.DatabaseError("getting organization database", err)
+	}
+
+	err = orgDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.EsfDocument{}).
+			Where("id = ? AND esf_status IS DISTINCT FROM ?", id, status).
+			Update("esf_status", status)
+		if result.Error != nil {
+			return apperror.DatabaseError("updating document status", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			var count int64
+			if err := tx.Model(&entity.EsfDocument{}).Where("id = ?", id).Count(&count).Error; err != nil {
+				return apperror.DatabaseError("updating document status", err)
+			}
+			if count == 0 {
+				return apperror.New(apperror.ErrDocumentNotFound, "document not found")
+			}
+			return nil
+		}
+		if err := recordEvent(tx, events.DocumentStatusChanged, events.AggregateDocument, id, orgID, events.StatusPayload{ID: id, Status: status}); err != nil {
+			return apperror.DatabaseError("updating document status", err)
+		}
+		return nil
+	})
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to update document status", err, logrus.Fields{"org_id": orgID.String(), "doc_id": id.String()})
+		return err
+	}
+	return nil
+}
+
+// CountDocumentsCreatedSince считает документы, созданные с момента since. Удаленные документы
+// учитываются, чтобы удаление не возвращало квоту тарифного плана.
+func (edrp *esfDocumentRepositoryPostgres) CountDocumentsCreatedSince(ctx context.Context, orgID uuid.UUID, since time.Time) (int64, error) {
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		return 0, apperror.DatabaseError("getting organization database", err)
+	}
+
+	var count int64
+	if err := orgDB.WithContext(ctx).Unscoped().Model(&entity.EsfDocument{}).Where("created_at >= ?", since).Count(&count).Error; err != nil {
+		edrp.logger.Error(ctx, "Failed to count documents", err, logrus.Fields{"org_id": orgID.String()})
+		return 0, apperror.DatabaseError("counting documents", err)
+	}
+	return count, nil
+}
+
 // getOrgDB возвращает подключение к БД организации по ее ID, кэшируя соединения.
 func (edrp *esfDocumentRepositoryPostgres) getOrgDB(ctx context.Context, orgID uuid.UUID) (*gorm.DB, error) {
 	if orgID == uuid.Nil {
@@ -210,7 +392,8 @@ func (edrp *esfDocumentRepositoryPostgres) getOrgDB(ctx context.Context, orgID u
 	}
 
 	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s", host, user, password, org.DBName, port, sslmode)
-	orgDB, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	// Логгер основной БД: запросы к БД организации пишутся с ID запроса так же, как остальные
+	orgDB, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: edrp.baseDB.Logger})
 	if err != nil {
 		edrp.logger.Error(ctx, "Failed to connect to organization database", err, logrus.Fields{"dbName": org.DBName})
 		return nil, apperror.DatabaseError("connecting to organization database", err)
@@ -234,51 +417,22 @@ func (edrp *esfDocumentRepositoryPostgres) GetAllDocumentsPaginated(ctx context.
 		"has_filters": filters.HasFilters(),
 	})
 
-	var documents []entity.EsfDocument
-	var totalCount int64
-
 	orgDB, err := edrp.getOrgDB(ctx, orgID)
 	if err != nil {
 		edrp.logger.Error(ctx, "Failed to get organization database", err, logrus.Fields{"org_id": orgID.String()})
 		return nil, 0, apperror.DatabaseError("getting organization database", err)
 	}
 
-	query := orgDB.WithContext(ctx)
+	spec := documentFilterSpec(filters).
+		OrderBy(query.ParseSort(params.Sort, params.Order)...)
 
-	// Применяем фильтры
-	if filters.Status != "" {
-		edrp.logger.Debug(ctx, "Applying status filter", logrus.Fields{"status": filters.Status})
-		query = query.Where("status = ?", filters.Status)
-	}
-
-	if filters.Search != "" {
-		edrp.logger.Debug(ctx, "Applying search filter", logrus.Fields{"search": filters.Search})
-		query = query.Where("name ILIKE ? OR description ILIKE ?", "%"+filters.Search+"%", "%"+filters.Search+"%")
-	}
-
-	if filters.CreatedAfter != "" {
-		edrp.logger.Debug(ctx, "Applying created_after filter", logrus.Fields{"created_after": filters.CreatedAfter})
-		query = query.Where("created_at >= ?", filters.CreatedAfter)
-	}
-
-	if filters.CreatedBefore != "" {
-		edrp.logger.Debug(ctx, "Applying created_before filter", logrus.Fields{"created_before": filters.CreatedBefore})
-		query = query.Where("created_at <= ?", filters.CreatedBefore)
-	}
-
-	// Получаем общее количество
-	if err := query.Model(&entity.EsfDocument{}).Count(&totalCount).Error; err != nil {
-		edrp.logger.Error(ctx, "Failed to count documents", err, logrus.Fields{"org_id": orgID.String()})
-		return nil, 0, apperror.DatabaseError("counting documents", err)
-	}
-
-	// Применяем сортировку и пагинацию
-	if err := query.
-		Preload("CatalogEntries").
-		Order(params.Sort + " " + params.Order).
-		Offset(params.GetOffset()).
-		Limit(params.GetLimit()).
-		Find(&documents).Error; err != nil {
+	documents, totalCount, err := query.Page[entity.EsfDocument](orgDB.WithContext(ctx), spec, params.GetOffset(), params.GetLimit(),
+		func(db *gorm.DB) *gorm.DB { return db.Preload("CatalogEntries") })
+	if err != nil {
+		if isInvalidQuery(err) {
+			edrp.logger.Warn(ctx, "Invalid document query field", logrus.Fields{"org_id": orgID.String(), "error": err.Error()})
+			return nil, 0, invalidQueryError(err)
+		}
 		edrp.logger.Error(ctx, "Failed to fetch paginated documents", err, logrus.Fields{
 			"org_id":    orgID.String(),
 			"page":      params.Page,
@@ -296,3 +450,157 @@ func (edrp *esfDocumentRepositoryPostgres) GetAllDocumentsPaginated(ctx context.
 
 	return documents, totalCount, nil
 }
+
+// GetAllDocumentsCursor возвращает документы ЭСФ с курсорной (keyset) пагинацией и фильтрацией
+func (edrp *esfDocumentRepositoryPostgres) GetAllDocumentsCursor(ctx context.Context, orgID uuid.UUID, params pagination.CursorParams, filters pagination.DocumentFilterParams) ([]entity.EsfDocument, pagination.CursorInfo, error) {
+	edrp.logger.Debug(ctx, "Fetching documents with cursor", logrus.Fields{
+		"org_id":      orgID.String(),
+		"limit":       params.Limit,
+		"sort":        params.Sort,
+		"order":       params.Order,
+		"has_cursor":  params.Cursor != "",
+		"has_filters": filters.HasFilters(),
+	})
+
+	var documents []entity.EsfDocument
+
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to get organization database", err, logrus.Fields{"org_id": orgID.String()})
+		return nil, pagination.CursorInfo{}, apperror.DatabaseError("getting organization database", err)
+	}
+
+	filtered, err := documentFilterSpec(filters).Filter(orgDB.WithContext(ctx))
+	if err != nil {
+		edrp.logger.Warn(ctx, "Invalid document query field", logrus.Fields{"org_id": orgID.String(), "error": err.Error()})
+		return nil, pagination.CursorInfo{}, invalidQueryError(err)
+	}
+
+	q, err := params.Apply(filtered)
+	if err != nil {
+		edrp.logger.Warn(ctx, "Invalid cursor", logrus.Fields{"org_id": orgID.String()})
+		return nil, pagination.CursorInfo{}, apperror.ValidationError("invalid cursor")
+	}
+
+	if err := q.Preload("CatalogEntries").Find(&documents).Error; err != nil {
+		edrp.logger.Error(ctx, "Failed to fetch documents by cursor", err, logrus.Fields{"org_id": orgID.String()})
+		return nil, pagination.CursorInfo{}, apperror.DatabaseError("fetching documents by cursor", err)
+	}
+
+	documents, info := pagination.NewCursorPage(documents, params, func(doc entity.EsfDocument) (interface{}, string) {
+		switch params.Sort {
+		case "updated_at":
+			return doc.UpdatedAt, doc.ID.String()
+		case "delivery_date":
+			return doc.DeliveryDate, doc.ID.String()
+		default:
+			return doc.CreatedAt, doc.ID.String()
+		}
+	})
+
+	edrp.logger.Debug(ctx, "Documents fetched successfully", logrus.Fields{
+		"org_id":   orgID.String(),
+		"count":    len(documents),
+		"has_next": info.HasNext,
+	})
+
+	return documents, info, nil
+}
+
+// documentSchema перечисляет поля документов, доступные для фильтрации,
+// сортировки и проекции
+var documentSchema = query.Schema{
+	"id":                     "id",
+	"created_at":             "created_at",
+	"updated_at":             "updated_at",
+	"version":                "version",
+	"status":                 "status",
+	"name":                   "name",
+	"description":            "description",
+	"delivery_date":          "delivery_date",
+	"operation_type_code":    "operation_type_code",
+	"delivery_type_code":     "delivery_type_code",
+	"contractor_tin":         "contractor_tin",
+	"currency_code":          "currency_code",
+	"amount":                 "total_currency_value",
+	"amount_to_be_paid":      "amount_to_be_paid",
+	"supply_contract_number": "supply_contract_number",
+	"payment_code":           "payment_code",
+}
+
+// documentFilterFields поля документов, доступные в выражении filter; status — статус в ЭСФ
+var documentFilterFields = query.FilterFields{
+	"id":                     {Column: "id", Type: query.TypeUUID},
+	"created_at":             {Column: "created_at", Type: query.TypeDate},
+	"updated_at":             {Column: "updated_at", Type: query.TypeDate},
+	"status":                 {Column: "esf_status", Type: query.TypeString},
+	"delivery_date":          {Column: "delivery_date", Type: query.TypeDate},
+	"due_date":               {Column: "due_date", Type: query.TypeDate},
+	"operation_type_code":    {Column: "operation_type_code", Type: query.TypeString},
+	"delivery_type_code":     {Column: "delivery_type_code", Type: query.TypeString},
+	"payment_code":           {Column: "payment_code", Type: query.TypeString},
+	"contractor_tin":         {Column: "contractor_tin", Type: query.TypeString},
+	"currency_code":          {Column: "currency_code", Type: query.TypeString},
+	"country_code":           {Column: "country_code", Type: query.TypeString},
+	"is_resident":            {Column: "is_resident", Type: query.TypeBool},
+	"amount":                 {Column: "total_currency_value", Type: query.TypeNumber},
+	"amount_to_be_paid":      {Column: "amount_to_be_paid", Type: query.TypeNumber},
+	"paid_amount":            {Column: "paid_amount", Type: query.TypeNumber},
+	"supply_contract_number": {Column: "supply_contract_number", Type: query.TypeString},
+	"contract_id":            {Column: "contract_id", Type: query.TypeUUID},
+}
+
+// documentFilterSpec строит спецификацию запроса из фильтров документов
+func documentFilterSpec(filters pagination.DocumentFilterParams) *query.Spec {
+	return query.New(documentSchema).Where(
+		query.In("status", filters.Statuses()),
+		query.Search(filters.Search, "name", "description"),
+		query.Between("created_at", optional(filters.CreatedAfter), optional(filters.CreatedBefore)),
+		query.Between("delivery_date", optional(filters.DeliveryAfter), optional(filters.DeliveryBefore)),
+		query.Between("amount", filters.AmountGte, filters.AmountLte),
+		paymentCondition(filters.Payment, time.Now()),
+		query.Filter(filters.Filter, documentFilterFields),
+	)
+}
+
+// documentRemaining неоплаченный остаток документа, как в entity.EsfDocument.RemainingAmount
+const documentRemaining = "(opening_balances + total_currency_value - paid_amount)"
+
+// paymentCondition отбирает документы по состоянию оплаты (entity.EsfDocument.PaymentStatus);
+// unpaid включает и просроченные документы
+func paymentCondition(status string, now time.Time) query.Condition {
+	unpaid := query.Expr(documentRemaining+" >= ?", entity.PaymentTolerance)
+	switch status {
+	case "":
+		return query.And()
+	case entity.PaymentStatusPaid:
+		return query.Expr(documentRemaining+" < ?", entity.PaymentTolerance)
+	case entity.PaymentStatusUnpaid:
+		return unpaid
+	case entity.PaymentStatusOverdue:
+		return query.And(unpaid, query.Expr("due_date < ?", now))
+	default:
+		return query.Invalid(fmt.Errorf("%w: payment=%s", query.ErrUnknownField, status))
+	}
+}
+
+// isInvalidQuery сообщает, вызвана ли ошибка спецификации параметрами запроса
+func isInvalidQuery(err error) bool {
+	return errors.Is(err, query.ErrUnknownField) || errors.Is(err, query.ErrInvalidFilter)
+}
+
+// invalidQueryError ошибка проверки параметров списка; для filter в details — место ошибки
+func invalidQueryError(err error) *apperror.AppError {
+	if errors.Is(err, query.ErrInvalidFilter) {
+		return apperror.ValidationError("invalid filter").WithDetails(err.Error())
+	}
+	return apperror.ValidationError("invalid query field")
+}
+
+// optional возвращает nil для пустой строки, чтобы условие фильтра было пропущено
+func optional(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
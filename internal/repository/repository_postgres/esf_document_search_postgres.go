@@ -0,0 +1,126 @@
+package repositorypostgres
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/migrations"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+)
+
+// SetSearchOutbox включает запись событий индексации документов в search_outbox.
+// Включается только при настроенном OpenSearch, иначе outbox некому разбирать.
+func (edrp *esfDocumentRepositoryPostgres) SetSearchOutbox(enabled bool) {
+	edrp.searchOutbox = enabled
+}
+
+// enqueueSearch записывает событие индексации в транзакции изменения документа
+func (edrp *esfDocumentRepositoryPostgres) enqueueSearch(tx *gorm.DB, id uuid.UUID, op string) error {
+	if !edrp.searchOutbox {
+		return nil
+	}
+	return tx.Create(&entity.SearchOutboxEntry{DocumentID: id, Operation: op}).Error
+}
+
+// FetchSearchOutbox возвращает самые старые события индексации организации
+func (edrp *esfDocumentRepositoryPostgres) FetchSearchOutbox(ctx context.Context, orgID uuid.UUID, limit int) ([]entity.SearchOutboxEntry, error) {
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		return nil, apperror.DatabaseError("getting organization database", err)
+	}
+
+	var entries []entity.SearchOutboxEntry
+	if err := orgDB.WithContext(ctx).Order("id").Limit(limit).Find(&entries).Error; err != nil {
+		edrp.logger.Error(ctx, "Failed to fetch search outbox", err, logrus.Fields{"org_id": orgID.String()})
+		return nil, apperror.DatabaseError("fetching search outbox", err)
+	}
+	return entries, nil
+}
+
+// DeleteSearchOutbox удаляет обработанные события индексации
+func (edrp *esfDocumentRepositoryPostgres) DeleteSearchOutbox(ctx context.Context, orgID uuid.UUID, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		return apperror.DatabaseError("getting organization database", err)
+	}
+
+	if err := orgDB.WithContext(ctx).Where("id IN ?", ids).Delete(&entity.SearchOutboxEntry{}).Error; err != nil {
+		edrp.logger.Error(ctx, "Failed to delete search outbox entries", err, logrus.Fields{"org_id": orgID.String()})
+		return apperror.DatabaseError("deleting search outbox entries", err)
+	}
+	return nil
+}
+
+// GetDocumentsByIDs возвращает документы с позициями; отсутствующие и удаленные документы пропускаются
+func (edrp *esfDocumentRepositoryPostgres) GetDocumentsByIDs(ctx context.Context, orgID uuid.UUID, ids []uuid.UUID) ([]entity.EsfDocument, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		return nil, apperror.DatabaseError("getting organization database", err)
+	}
+
+	var documents []entity.EsfDocument
+	if err := orgDB.WithContext(ctx).Preload("CatalogEntries").Where("id IN ?", ids).Find(&documents).Error; err != nil {
+		edrp.logger.Error(ctx, "Failed to fetch documents by IDs", err, logrus.Fields{"org_id": orgID.String(), "count": len(ids)})
+		return nil, apperror.DatabaseError("fetching documents by IDs", err)
+	}
+	return documents, nil
+}
+
+// SearchDocuments выполняет полнотекстовый поиск документов средствами Postgres
+func (edrp *esfDocumentRepositoryPostgres) SearchDocuments(ctx context.Context, orgID uuid.UUID, text string, params pagination.PaginationParams) ([]entity.EsfDocument, int64, error) {
+	edrp.logger.Debug(ctx, "Searching documents with Postgres FTS", logrus.Fields{
+		"org_id":    orgID.String(),
+		"page":      params.Page,
+		"page_size": params.PageSize,
+	})
+
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to get organization database", err, logrus.Fields{"org_id": orgID.String()})
+		return nil, 0, apperror.DatabaseError("getting organization database", err)
+	}
+
+	tsquery := "plainto_tsquery('simple', ?)"
+	text = strings.TrimSpace(text)
+	query := orgDB.WithContext(ctx).Model(&entity.EsfDocument{}).
+		Where(migrations.DocumentSearchVector+" @@ "+tsquery, text).
+		Session(&gorm.Session{})
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		edrp.logger.Error(ctx, "Failed to count search results", err, logrus.Fields{"org_id": orgID.String()})
+		return nil, 0, apperror.DatabaseError("counting search results", err)
+	}
+
+	var documents []entity.EsfDocument
+	if err := query.
+		Preload("CatalogEntries").
+		Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "ts_rank(" + migrations.DocumentSearchVector + ", " + tsquery + ") DESC, created_at DESC",
+			Vars:               []interface{}{text},
+			WithoutParentheses: true,
+		}}).
+		Offset(params.GetOffset()).
+		Limit(params.GetLimit()).
+		Find(&documents).Error; err != nil {
+		edrp.logger.Error(ctx, "Failed to search documents", err, logrus.Fields{"org_id": orgID.String()})
+		return nil, 0, apperror.DatabaseError("searching documents", err)
+	}
+
+	return documents, total, nil
+}
@@ -0,0 +1,74 @@
+package repositorypostgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+)
+
+// ListDeletedDocuments возвращает страницу документов корзины: удаленных не раньше since
+func (edrp *esfDocumentRepositoryPostgres) ListDeletedDocuments(ctx context.Context, orgID uuid.UUID, since time.Time, params pagination.PaginationParams) ([]entity.EsfDocument, int64, error) {
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		return nil, 0, apperror.DatabaseError("getting organization database", err)
+	}
+
+	query := orgDB.WithContext(ctx).Unscoped().Model(&entity.EsfDocument{}).Where("deleted_at >= ?", since)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		edrp.logger.Error(ctx, "Failed to count deleted documents", err, logrus.Fields{"org_id": orgID.String()})
+		return nil, 0, apperror.DatabaseError("counting deleted documents", err)
+	}
+
+	var documents []entity.EsfDocument
+	if err := query.Order("deleted_at DESC, id").Offset(params.GetOffset()).Limit(params.GetLimit()).Find(&documents).Error; err != nil {
+		edrp.logger.Error(ctx, "Failed to list deleted documents", err, logrus.Fields{"org_id": orgID.String()})
+		return nil, 0, apperror.DatabaseError("listing deleted documents", err)
+	}
+	return documents, total, nil
+}
+
+// GetDeletedDocumentsByIDs возвращает удаленные документы из списка ids; остальные пропускаются
+func (edrp *esfDocumentRepositoryPostgres) GetDeletedDocumentsByIDs(ctx context.Context, orgID uuid.UUID, ids []uuid.UUID) ([]entity.EsfDocument, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		return nil, apperror.DatabaseError("getting organization database", err)
+	}
+
+	var documents []entity.EsfDocument
+	if err := orgDB.WithContext(ctx).Unscoped().Where("id IN ? AND deleted_at IS NOT NULL", ids).Find(&documents).Error; err != nil {
+		edrp.logger.Error(ctx, "Failed to fetch deleted documents by IDs", err, logrus.Fields{"org_id": orgID.String(), "count": len(ids)})
+		return nil, apperror.DatabaseError("fetching deleted documents", err)
+	}
+	return documents, nil
+}
+
+// ListDocumentsDeletedBefore возвращает идентификаторы документов, удаленных раньше before, самые старые первыми
+func (edrp *esfDocumentRepositoryPostgres) ListDocumentsDeletedBefore(ctx context.Context, orgID uuid.UUID, before time.Time, limit int) ([]uuid.UUID, error) {
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		return nil, apperror.DatabaseError("getting organization database", err)
+	}
+
+	var ids []uuid.UUID
+	err = orgDB.WithContext(ctx).Unscoped().Model(&entity.EsfDocument{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", before).
+		Order("deleted_at").Limit(limit).
+		Pluck("id", &ids).Error
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to list expired deleted documents", err, logrus.Fields{"org_id": orgID.String()})
+		return nil, apperror.DatabaseError("listing expired deleted documents", err)
+	}
+	return ids, nil
+}
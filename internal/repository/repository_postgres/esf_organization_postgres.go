@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -12,8 +13,13 @@ import (
 	"github.com/rusgainew/tunduck-app/internal/repository"
 	"github.com/rusgainew/tunduck-app/pkg/apperror"
 	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/events"
+	"github.com/rusgainew/tunduck-app/pkg/fieldcrypt"
 	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/migrations"
 	"github.com/rusgainew/tunduck-app/pkg/pagination"
+	"github.com/rusgainew/tunduck-app/pkg/query"
+	"github.com/rusgainew/tunduck-app/pkg/transaction"
 )
 
 // esfOrganizationPostgres реализует интерфейс EsfOrganizationRepository для PostgreSQL
@@ -30,14 +36,14 @@ func NewEsfOrganizationRepositoryPostgres(db *gorm.DB, log *logrus.Logger) repos
 	}
 }
 
-// GetAll возвращает все организации из БД
+// GetAll возвращает все организации из БД, новые первыми
 func (eop *esfOrganizationPostgres) GetAll(ctx context.Context) ([]*entity.EstOrganization, error) {
-	eop.logger.Debug(ctx, "Fetching all organizations from database", logrus.Fields{})
+	eop.logger.Debug(ctx, "Fetching all organizations from database")
 
 	var organizations []*entity.EstOrganization
 
-	if err := eop.db.WithContext(ctx).Find(&organizations).Error; err != nil {
-		eop.logger.Error(ctx, "Failed to fetch organizations from database", err, logrus.Fields{})
+	if err := transaction.FromContext(ctx, eop.db).Order("created_at desc").Order("id desc").Find(&organizations).Error; err != nil {
+		eop.logger.Error(ctx, "Failed to fetch organizations from database", err)
 		return nil, apperror.DatabaseError("fetching organizations", err)
 	}
 
@@ -51,7 +57,7 @@ func (eop *esfOrganizationPostgres) GetByID(ctx context.Context, id string) (*en
 
 	var organization entity.EstOrganization
 
-	if err := eop.db.WithContext(ctx).Where("id = ?", id).First(&organization).Error; err != nil {
+	if err := transaction.FromContext(ctx, eop.db).Where("id = ?", id).First(&organization).Error; err != nil {
 		if err == gorm.ErrRecordNotFound {
 			eop.logger.Debug(ctx, "Organization not found", logrus.Fields{"id": id})
 			return nil, nil
@@ -68,7 +74,13 @@ func (eop *esfOrganizationPostgres) GetByID(ctx context.Context, id string) (*en
 func (eop *esfOrganizationPostgres) Insert(ctx context.Context, org *entity.EstOrganization) error {
 	eop.logger.Debug(ctx, "Inserting organization into database", logrus.Fields{"name": org.Name, "id": org.ID.String()})
 
-	if err := eop.db.WithContext(ctx).Create(org).Error; err != nil {
+	err := transaction.FromContext(ctx, eop.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(org).Error; err != nil {
+			return err
+		}
+		return recordEvent(tx, events.OrgCreated, events.AggregateOrganization, org.ID, org.ID, events.NewOrganizationPayload(org))
+	})
+	if err != nil {
 		eop.logger.Error(ctx, "Failed to insert organization into database", err, logrus.Fields{"name": org.Name})
 		return apperror.DatabaseError("inserting organization", err)
 	}
@@ -81,28 +93,161 @@ func (eop *esfOrganizationPostgres) Insert(ctx context.Context, org *entity.EstO
 func (eop *esfOrganizationPostgres) Update(ctx context.Context, org *entity.EstOrganization) error {
 	eop.logger.Debug(ctx, "Updating organization in database", logrus.Fields{"id": org.ID.String()})
 
-	if err := eop.db.WithContext(ctx).Save(org).Error; err != nil {
-		eop.logger.Error(ctx, "Failed to update organization in database", err, logrus.Fields{"id": org.ID.String()})
-		return apperror.DatabaseError("updating organization", err)
+	err := transaction.FromContext(ctx, eop.db).Transaction(func(tx *gorm.DB) error {
+		// Обновление проходит только если версия не изменилась с момента чтения
+		result := tx.Model(&entity.EstOrganization{}).
+			Where("id = ? AND version = ?", org.ID, org.Version).
+			Updates(map[string]interface{}{
+				"name":        org.Name,
+				"description": org.Description,
+				"token":       fieldcrypt.Text(org.Token),
+				"db_name":     org.DBName,
+				"tin":         org.Tin,
+				"timezone":    org.Timezone,
+				"version":     gorm.Expr("version + 1"),
+			})
+		if result.Error != nil {
+			eop.logger.Error(ctx, "Failed to update organization in database", result.Error, logrus.Fields{"id": org.ID.String()})
+			return apperror.DatabaseError("updating organization", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return eop.updateMissError(ctx, tx, org.ID.String())
+		}
+
+		payload := events.NewOrganizationPayload(org)
+		payload.Version++
+		if err := recordEvent(tx, events.OrgUpdated, events.AggregateOrganization, org.ID, org.ID, payload); err != nil {
+			return apperror.DatabaseError("updating organization", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	eop.logger.Debug(ctx, "Organization updated successfully", logrus.Fields{"id": org.ID.String()})
+	org.Version++
+	eop.logger.Debug(ctx, "Organization updated successfully", logrus.Fields{"id": org.ID.String(), "version": org.Version})
 	return nil
 }
 
+// updateMissError различает отсутствующую организацию и конфликт версий
+func (eop *esfOrganizationPostgres) updateMissError(ctx context.Context, db *gorm.DB, id string) error {
+	var count int64
+	if err := db.Model(&entity.EstOrganization{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return apperror.DatabaseError("checking organization version", err)
+	}
+	if count == 0 {
+		return apperror.New(apperror.ErrOrgNotFound, "organization not found")
+	}
+
+	eop.logger.Warn(ctx, "Organization version conflict", logrus.Fields{"id": id})
+	return apperror.New(apperror.ErrVersionConflict, "organization was modified by another user, reload it and retry")
+}
+
 // Delete удаляет организацию из БД по ID
 func (eop *esfOrganizationPostgres) Delete(ctx context.Context, id string) error {
 	eop.logger.Debug(ctx, "Deleting organization from database", logrus.Fields{"id": id})
 
-	if err := eop.db.WithContext(ctx).Where("id = ?", id).Delete(&entity.EstOrganization{}).Error; err != nil {
+	err := eop.withEvent(ctx, events.OrgDeleted, id, func(tx *gorm.DB) error {
+		return tx.Where("id = ?", id).Delete(&entity.EstOrganization{}).Error
+	})
+	if err != nil {
 		eop.logger.Error(ctx, "Failed to delete organization from database", err, logrus.Fields{"id": id})
-		return apperror.DatabaseError("deleting organization", err)
+		return apperror.DatabaseErrorFrom("deleting organization", err)
 	}
 
 	eop.logger.Debug(ctx, "Organization deleted successfully", logrus.Fields{"id": id})
 	return nil
 }
 
+// Restore восстанавливает мягко удаленную организацию
+func (eop *esfOrganizationPostgres) Restore(ctx context.Context, id string) error {
+	eop.logger.Debug(ctx, "Restoring organization", logrus.Fields{"id": id})
+
+	err := eop.withEvent(ctx, events.OrgRestored, id, func(tx *gorm.DB) error {
+		result := tx.Unscoped().Model(&entity.EstOrganization{}).
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Update("deleted_at", nil)
+		if result.Error != nil {
+			eop.logger.Error(ctx, "Failed to restore organization", result.Error, logrus.Fields{"id": id})
+			return apperror.DatabaseError("restoring organization", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return apperror.New(apperror.ErrOrgNotFound, "deleted organization not found")
+		}
+		return nil
+	})
+	if err != nil {
+		return apperror.DatabaseErrorFrom("restoring organization", err)
+	}
+
+	eop.logger.Debug(ctx, "Organization restored successfully", logrus.Fields{"id": id})
+	return nil
+}
+
+// Purge окончательно удаляет мягко удаленную организацию. БД организации не удаляется.
+func (eop *esfOrganizationPostgres) Purge(ctx context.Context, id string) error {
+	eop.logger.Debug(ctx, "Purging organization", logrus.Fields{"id": id})
+
+	err := eop.withEvent(ctx, events.OrgPurged, id, func(tx *gorm.DB) error {
+		result := tx.Unscoped().
+			Where("id = ? AND deleted_at IS NOT NULL", id).
+			Delete(&entity.EstOrganization{})
+		if result.Error != nil {
+			eop.logger.Error(ctx, "Failed to purge organization", result.Error, logrus.Fields{"id": id})
+			return apperror.DatabaseError("purging organization", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return apperror.New(apperror.ErrOrgNotFound, "deleted organization not found")
+		}
+		return nil
+	})
+	if err != nil {
+		return apperror.DatabaseErrorFrom("purging organization", err)
+	}
+
+	eop.logger.Debug(ctx, "Organization purged successfully", logrus.Fields{"id": id})
+	return nil
+}
+
+// SetPlan назначает организации тарифный план
+func (eop *esfOrganizationPostgres) SetPlan(ctx context.Context, id, plan string) error {
+	eop.logger.Debug(ctx, "Setting organization plan", logrus.Fields{"id": id, "plan": plan})
+
+	err := eop.withEvent(ctx, events.OrgUpdated, id, func(tx *gorm.DB) error {
+		result := tx.Model(&entity.EstOrganization{}).
+			Where("id = ?", id).
+			Updates(map[string]interface{}{"plan": plan, "version": gorm.Expr("version + 1")})
+		if result.Error != nil {
+			eop.logger.Error(ctx, "Failed to set organization plan", result.Error, logrus.Fields{"id": id})
+			return apperror.DatabaseError("setting organization plan", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return apperror.New(apperror.ErrOrgNotFound, "organization not found")
+		}
+		return nil
+	})
+	if err != nil {
+		return apperror.DatabaseErrorFrom("setting organization plan", err)
+	}
+	return nil
+}
+
+// withEvent выполняет изменение организации и записывает событие в одной транзакции
+func (eop *esfOrganizationPostgres) withEvent(ctx context.Context, eventType, id string, fn func(tx *gorm.DB) error) error {
+	orgID, err := uuid.Parse(id)
+	if err != nil {
+		return apperror.ValidationError("invalid UUID format")
+	}
+
+	return transaction.FromContext(ctx, eop.db).Transaction(func(tx *gorm.DB) error {
+		if err := fn(tx); err != nil {
+			return err
+		}
+		return recordEvent(tx, eventType, events.AggregateOrganization, orgID, orgID, events.Ref{ID: orgID})
+	})
+}
+
 // CreateDatabase создает новую базу данных для организации и применяет миграции
 func (eop *esfOrganizationPostgres) CreateDatabase(ctx context.Context, dbName string) error {
 	eop.logger.Debug(ctx, "Creating database for organization", logrus.Fields{"dbName": dbName})
@@ -111,7 +256,7 @@ func (eop *esfOrganizationPostgres) CreateDatabase(ctx context.Context, dbName s
 	// Используем Exec вместо параметризованного запроса, так как имя БД не может быть параметром
 	sql := fmt.Sprintf("CREATE DATABASE %s", dbName)
 
-	if err := eop.db.WithContext(ctx).Exec(sql).Error; err != nil {
+	if err := transaction.FromContext(ctx, eop.db).Exec(sql).Error; err != nil {
 		eop.logger.Error(ctx, "Failed to create database", err, logrus.Fields{"dbName": dbName})
 		return apperror.DatabaseError("creating database", err)
 	}
@@ -144,7 +289,7 @@ func (eop *esfOrganizationPostgres) CreateDatabase(ctx context.Context, dbName s
 	// не прерываем, так как возможно миграции пройдут, если расширение уже есть/не требуется
 
 	// Применяем миграции для пустых таблиц EsfDocument и EsfEntries
-	if err := newDB.WithContext(ctx).AutoMigrate(&entity.EsfDocument{}, &entity.EsfEntries{}); err != nil {
+	if _, err := migrations.Tenant().Up(ctx, newDB); err != nil {
 		eop.logger.Error(ctx, "Failed to run migrations in new database", err, logrus.Fields{"dbName": dbName})
 		return apperror.DatabaseError("running migrations", err)
 	}
@@ -163,34 +308,15 @@ func (eop *esfOrganizationPostgres) GetAllPaginated(ctx context.Context, params
 		"has_filters": filters.HasFilters(),
 	})
 
-	var organizations []*entity.EstOrganization
-	var totalCount int64
-
-	query := eop.db.WithContext(ctx)
-
-	// Применяем фильтры
-	if filters.Status != "" {
-		eop.logger.Debug(ctx, "Applying status filter", logrus.Fields{"status": filters.Status})
-		query = query.Where("status = ?", filters.Status)
-	}
-
-	if filters.Search != "" {
-		eop.logger.Debug(ctx, "Applying search filter", logrus.Fields{"search": filters.Search})
-		query = query.Where("name ILIKE ? OR description ILIKE ?", "%"+filters.Search+"%", "%"+filters.Search+"%")
-	}
-
-	// Получаем общее количество
-	if err := query.Model(&entity.EstOrganization{}).Count(&totalCount).Error; err != nil {
-		eop.logger.Error(ctx, "Failed to count organizations", err, logrus.Fields{})
-		return nil, 0, apperror.DatabaseError("counting organizations", err)
-	}
+	spec := organizationFilterSpec(filters).
+		OrderBy(query.ParseSort(params.Sort, params.Order)...)
 
-	// Применяем сортировку и пагинацию
-	if err := query.
-		Order(params.Sort + " " + params.Order).
-		Offset(params.GetOffset()).
-		Limit(params.GetLimit()).
-		Find(&organizations).Error; err != nil {
+	organizations, totalCount, err := query.Page[*entity.EstOrganization](transaction.FromContext(ctx, eop.db), spec, params.GetOffset(), params.GetLimit())
+	if err != nil {
+		if isInvalidQuery(err) {
+			eop.logger.Warn(ctx, "Invalid organization query field", logrus.Fields{"error": err.Error()})
+			return nil, 0, invalidQueryError(err)
+		}
 		eop.logger.Error(ctx, "Failed to fetch paginated organizations", err, logrus.Fields{
 			"page":      params.Page,
 			"page_size": params.PageSize,
@@ -206,3 +332,84 @@ func (eop *esfOrganizationPostgres) GetAllPaginated(ctx context.Context, params
 
 	return organizations, totalCount, nil
 }
+
+// GetAllCursor возвращает организации с курсорной (keyset) пагинацией и фильтрацией
+func (eop *esfOrganizationPostgres) GetAllCursor(ctx context.Context, params pagination.CursorParams, filters pagination.OrganizationFilterParams) ([]*entity.EstOrganization, pagination.CursorInfo, error) {
+	eop.logger.Debug(ctx, "Fetching organizations with cursor", logrus.Fields{
+		"limit":       params.Limit,
+		"sort":        params.Sort,
+		"order":       params.Order,
+		"has_cursor":  params.Cursor != "",
+		"has_filters": filters.HasFilters(),
+	})
+
+	var organizations []*entity.EstOrganization
+
+	filtered, err := organizationFilterSpec(filters).Filter(transaction.FromContext(ctx, eop.db))
+	if err != nil {
+		eop.logger.Warn(ctx, "Invalid organization query field", logrus.Fields{"error": err.Error()})
+		return nil, pagination.CursorInfo{}, invalidQueryError(err)
+	}
+
+	q, err := params.Apply(filtered)
+	if err != nil {
+		eop.logger.Warn(ctx, "Invalid cursor")
+		return nil, pagination.CursorInfo{}, apperror.ValidationError("invalid cursor")
+	}
+
+	if err := q.Find(&organizations).Error; err != nil {
+		eop.logger.Error(ctx, "Failed to fetch organizations by cursor", err)
+		return nil, pagination.CursorInfo{}, apperror.DatabaseError("fetching organizations by cursor", err)
+	}
+
+	organizations, info := pagination.NewCursorPage(organizations, params, func(org *entity.EstOrganization) (interface{}, string) {
+		switch params.Sort {
+		case "name":
+			return org.Name, org.ID.String()
+		case "updated_at":
+			return org.UpdatedAt, org.ID.String()
+		default:
+			return org.CreatedAt, org.ID.String()
+		}
+	})
+
+	eop.logger.Debug(ctx, "Organizations fetched successfully", logrus.Fields{
+		"count":    len(organizations),
+		"has_next": info.HasNext,
+	})
+
+	return organizations, info, nil
+}
+
+// organizationSchema перечисляет поля организаций, доступные для фильтрации,
+// сортировки и проекции
+var organizationSchema = query.Schema{
+	"id":          "id",
+	"name":        "name",
+	"description": "description",
+	"status":      "status",
+	"db_name":     "db_name",
+	"created_at":  "created_at",
+	"updated_at":  "updated_at",
+	"version":     "version",
+}
+
+// organizationFilterFields поля организаций, доступные в выражении filter
+var organizationFilterFields = query.FilterFields{
+	"id":          {Column: "id", Type: query.TypeUUID},
+	"name":        {Column: "name", Type: query.TypeString},
+	"description": {Column: "description", Type: query.TypeString},
+	"plan":        {Column: "plan", Type: query.TypeString},
+	"created_at":  {Column: "created_at", Type: query.TypeDate},
+	"updated_at":  {Column: "updated_at", Type: query.TypeDate},
+}
+
+// organizationFilterSpec строит спецификацию запроса из фильтров организаций
+func organizationFilterSpec(filters pagination.OrganizationFilterParams) *query.Spec {
+	return query.New(organizationSchema).Where(
+		query.In("status", filters.Statuses()),
+		query.Search(filters.Search, "name", "description"),
+		query.Between("created_at", optional(filters.CreatedAfter), optional(filters.CreatedBefore)),
+		query.Filter(filters.Filter, organizationFilterFields),
+	)
+}
@@ -0,0 +1,159 @@
+package repositorypostgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/events"
+)
+
+// recordEvent записывает доменное событие в outbox в транзакции изменения
+func recordEvent(tx *gorm.DB, eventType, aggregateType string, aggregateID, orgID uuid.UUID, payload interface{}) error {
+	event, err := events.NewOutboxEvent(eventType, aggregateType, aggregateID, orgID, payload)
+	if err != nil {
+		return err
+	}
+	return tx.Create(event).Error
+}
+
+// relayEvents публикует неопубликованные события по порядку и отмечает отправленные.
+// Строки блокируются FOR UPDATE SKIP LOCKED, поэтому ретрансляторы нескольких реплик
+// не отправляют одно событие одновременно.
+func relayEvents(ctx context.Context, db *gorm.DB, limit int, publish repository.PublishEventFunc) (int, error) {
+	var published []int64
+	var publishErr error
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var pending []entity.OutboxEvent
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("published_at IS NULL").
+			Order("id").
+			Limit(limit).
+			Find(&pending).Error; err != nil {
+			return err
+		}
+
+		for _, event := range pending {
+			if publishErr = publish(ctx, event); publishErr != nil {
+				break
+			}
+			published = append(published, event.ID)
+		}
+		if len(published) == 0 {
+			return nil
+		}
+
+		return tx.Model(&entity.OutboxEvent{}).
+			Where("id IN ?", published).
+			Update("published_at", time.Now().UTC()).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(published), publishErr
+}
+
+// purgePublishedEvents удаляет опубликованные события старше before
+func purgePublishedEvents(ctx context.Context, db *gorm.DB, before time.Time) (int64, error) {
+	result := db.WithContext(ctx).
+		Where("published_at IS NOT NULL AND published_at < ?", before).
+		Delete(&entity.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
+
+// eventReplayBatchSize размер страницы при повторной отправке событий
+const eventReplayBatchSize = 500
+
+// replayEvents повторно публикует опубликованные события из диапазона по порядку id.
+// Страницы читаются по ключу id без блокировок: отметки published_at не меняются,
+// поэтому повтор не мешает ретранслятору. Ошибка публикации прерывает повтор.
+func replayEvents(ctx context.Context, db *gorm.DB, filter repository.EventReplayFilter, publish repository.PublishEventFunc) (int, error) {
+	replayed := 0
+	var lastID int64
+	for {
+		query := db.WithContext(ctx).
+			Where("id > ? AND published_at IS NOT NULL", lastID).
+			Where("organization_id = ?", filter.OrganizationID).
+			Where("occurred_at >= ? AND occurred_at < ?", filter.From, filter.To)
+		if len(filter.Types) > 0 {
+			query = query.Where("type IN ?", filter.Types)
+		}
+
+		var batch []entity.OutboxEvent
+		if err := query.Order("id").Limit(eventReplayBatchSize).Find(&batch).Error; err != nil {
+			return replayed, err
+		}
+
+		for _, event := range batch {
+			if err := publish(ctx, event); err != nil {
+				return replayed, err
+			}
+			replayed++
+			lastID = event.ID
+		}
+		if len(batch) < eventReplayBatchSize {
+			return replayed, nil
+		}
+	}
+}
+
+// RelayEvents публикует события документов из outbox БД организации
+func (edrp *esfDocumentRepositoryPostgres) RelayEvents(ctx context.Context, orgID uuid.UUID, limit int, publish repository.PublishEventFunc) (int, error) {
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		return 0, apperror.DatabaseError("getting organization database", err)
+	}
+	return relayEvents(ctx, orgDB, limit, publish)
+}
+
+// PurgePublishedEvents удаляет опубликованные события документов старше before
+func (edrp *esfDocumentRepositoryPostgres) PurgePublishedEvents(ctx context.Context, orgID uuid.UUID, before time.Time) (int64, error) {
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		return 0, apperror.DatabaseError("getting organization database", err)
+	}
+
+	purged, err := purgePublishedEvents(ctx, orgDB, before)
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to purge published events", err, logrus.Fields{"org_id": orgID.String()})
+		return 0, apperror.DatabaseError("purging published events", err)
+	}
+	return purged, nil
+}
+
+// ReplayEvents повторно публикует события документов из outbox БД организации
+func (edrp *esfDocumentRepositoryPostgres) ReplayEvents(ctx context.Context, filter repository.EventReplayFilter, publish repository.PublishEventFunc) (int, error) {
+	orgDB, err := edrp.getOrgDB(ctx, filter.OrganizationID)
+	if err != nil {
+		return 0, apperror.DatabaseError("getting organization database", err)
+	}
+	return replayEvents(ctx, orgDB, filter, publish)
+}
+
+// RelayEvents публикует события организаций из outbox основной БД
+func (eop *esfOrganizationPostgres) RelayEvents(ctx context.Context, limit int, publish repository.PublishEventFunc) (int, error) {
+	return relayEvents(ctx, eop.db, limit, publish)
+}
+
+// PurgePublishedEvents удаляет опубликованные события организаций старше before
+func (eop *esfOrganizationPostgres) PurgePublishedEvents(ctx context.Context, before time.Time) (int64, error) {
+	purged, err := purgePublishedEvents(ctx, eop.db, before)
+	if err != nil {
+		eop.logger.Error(ctx, "Failed to purge published events", err)
+		return 0, apperror.DatabaseError("purging published events", err)
+	}
+	return purged, nil
+}
+
+// ReplayEvents повторно публикует события организации из outbox основной БД
+func (eop *esfOrganizationPostgres) ReplayEvents(ctx context.Context, filter repository.EventReplayFilter, publish repository.PublishEventFunc) (int, error) {
+	return replayEvents(ctx, eop.db, filter, publish)
+}
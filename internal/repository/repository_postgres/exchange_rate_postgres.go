@@ -0,0 +1,78 @@
+package repositorypostgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/transaction"
+)
+
+// exchangeRatePostgres реализует ExchangeRateRepository
+type exchangeRatePostgres struct {
+	db     *gorm.DB
+	logger *logger.Logger
+}
+
+// NewExchangeRateRepositoryPostgres создает репозиторий курсов валют
+func NewExchangeRateRepositoryPostgres(db *gorm.DB, log *logrus.Logger) repository.ExchangeRateRepository {
+	return &exchangeRatePostgres{
+		db:     db,
+		logger: logger.New(log),
+	}
+}
+
+// SaveRates сохраняет курсы с перезаписью курса того же дня
+func (r *exchangeRatePostgres) SaveRates(ctx context.Context, rates []entity.ExchangeRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+
+	err := transaction.FromContext(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "currency_code"}, {Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "source"}),
+	}).Create(&rates).Error
+	if err != nil {
+		r.logger.Error(ctx, "Failed to save exchange rates", err, logrus.Fields{"count": len(rates)})
+		return apperror.DatabaseError("saving exchange rates", err)
+	}
+	return nil
+}
+
+// FindRate возвращает последний курс валюты не позже date
+func (r *exchangeRatePostgres) FindRate(ctx context.Context, currency string, date time.Time) (*entity.ExchangeRate, error) {
+	var rate entity.ExchangeRate
+	err := transaction.FromContext(ctx, r.db).
+		Where("currency_code = ? AND date <= ?", currency, date).
+		Order("date DESC").
+		First(&rate).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		r.logger.Error(ctx, "Failed to fetch exchange rate", err, logrus.Fields{"currency": currency})
+		return nil, apperror.DatabaseError("fetching exchange rate", err)
+	}
+	return &rate, nil
+}
+
+// ListRates возвращает последний курс каждой валюты не позже date
+func (r *exchangeRatePostgres) ListRates(ctx context.Context, date time.Time) ([]entity.ExchangeRate, error) {
+	var rates []entity.ExchangeRate
+	err := transaction.FromContext(ctx, r.db).
+		Raw("SELECT DISTINCT ON (currency_code) * FROM exchange_rates WHERE date <= ? ORDER BY currency_code, date DESC", date).
+		Scan(&rates).Error
+	if err != nil {
+		r.logger.Error(ctx, "Failed to fetch exchange rates", err)
+		return nil, apperror.DatabaseError("fetching exchange rates", err)
+	}
+	return rates, nil
+}
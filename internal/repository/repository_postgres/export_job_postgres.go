@@ -0,0 +1,71 @@
+package repositorypostgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/transaction"
+)
+
+// exportJobPostgres реализует ExportJobRepository
+type exportJobPostgres struct {
+	db     *gorm.DB
+	logger *logger.Logger
+}
+
+// NewExportJobRepositoryPostgres создает репозиторий фоновых выгрузок
+func NewExportJobRepositoryPostgres(db *gorm.DB, log *logrus.Logger) repository.ExportJobRepository {
+	return &exportJobPostgres{
+		db:     db,
+		logger: logger.New(log),
+	}
+}
+
+// Create сохраняет новую выгрузку
+func (r *exportJobPostgres) Create(ctx context.Context, job *entity.ExportJob) error {
+	if job.ID == uuid.Nil {
+		job.ID = uuid.New()
+	}
+
+	if err := transaction.FromContext(ctx, r.db).Create(job).Error; err != nil {
+		r.logger.Error(ctx, "Failed to create export job", err, logrus.Fields{"type": job.Type})
+		return apperror.DatabaseError("creating export job", err)
+	}
+	return nil
+}
+
+// GetByID возвращает выгрузку или nil, если ее нет
+func (r *exportJobPostgres) GetByID(ctx context.Context, id uuid.UUID) (*entity.ExportJob, error) {
+	var job entity.ExportJob
+	err := transaction.FromContext(ctx, r.db).Where("id = ?", id).First(&job).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logger.Error(ctx, "Failed to fetch export job", err, logrus.Fields{"export_id": id.String()})
+		return nil, apperror.DatabaseError("fetching export job", err)
+	}
+	return &job, nil
+}
+
+// Update обновляет указанные колонки и updated_at
+func (r *exportJobPostgres) Update(ctx context.Context, id uuid.UUID, updates map[string]interface{}) error {
+	updates["updated_at"] = time.Now()
+
+	if err := transaction.FromContext(ctx, r.db).Model(&entity.ExportJob{}).
+		Where("id = ?", id).
+		Updates(updates).Error; err != nil {
+		r.logger.Error(ctx, "Failed to update export job", err, logrus.Fields{"export_id": id.String()})
+		return apperror.DatabaseError("updating export job", err)
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package repositorypostgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/transaction"
+)
+
+// inboundEventPostgres реализует InboundEventRepository
+type inboundEventPostgres struct {
+	db     *gorm.DB
+	logger *logger.Logger
+}
+
+// NewInboundEventRepositoryPostgres создает репозиторий входящих событий
+func NewInboundEventRepositoryPostgres(db *gorm.DB, log *logrus.Logger) repository.InboundEventRepository {
+	return &inboundEventPostgres{
+		db:     db,
+		logger: logger.New(log),
+	}
+}
+
+// Claim вставляет событие; при конфликте по (source, event_id) повторно захватывает только
+// неудачное или зависшее событие. Уникальный индекс гарантирует, что одновременные доставки
+// одного события не будут обработаны дважды.
+func (r *inboundEventPostgres) Claim(ctx context.Context, event *entity.InboundEvent, staleAfter time.Duration) (bool, error) {
+	now := time.Now()
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	event.Status = entity.InboundStatusProcessing
+	event.Attempts = 1
+	event.ReceivedAt = now
+	event.UpdatedAt = now
+
+	db := transaction.FromContext(ctx, r.db)
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(event)
+	if result.Error != nil {
+		r.logger.Error(ctx, "Failed to store inbound event", result.Error, logrus.Fields{"source": event.Source, "event_id": event.EventID})
+		return false, apperror.DatabaseError("storing inbound event", result.Error)
+	}
+	if result.RowsAffected == 1 {
+		return true, nil
+	}
+
+	var existing entity.InboundEvent
+	result = db.Model(&existing).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "id"}, {Name: "attempts"}}}).
+		Where("source = ? AND event_id = ?", event.Source, event.EventID).
+		Where("status = ? OR (status = ? AND updated_at < ?)",
+			entity.InboundStatusFailed, entity.InboundStatusProcessing, now.Add(-staleAfter)).
+		Updates(map[string]interface{}{
+			"status":     entity.InboundStatusProcessing,
+			"attempts":   gorm.Expr("attempts + 1"),
+			"error":      "",
+			"payload":    event.Payload,
+			"updated_at": now,
+		})
+	if result.Error != nil {
+		r.logger.Error(ctx, "Failed to reclaim inbound event", result.Error, logrus.Fields{"source": event.Source, "event_id": event.EventID})
+		return false, apperror.DatabaseError("reclaiming inbound event", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	event.ID, event.Attempts = existing.ID, existing.Attempts
+	return true, nil
+}
+
+// MarkProcessed отмечает событие обработанным
+func (r *inboundEventPostgres) MarkProcessed(ctx context.Context, id uuid.UUID) error {
+	now := time.Now()
+	return r.update(ctx, id, map[string]interface{}{
+		"status":       entity.InboundStatusProcessed,
+		"processed_at": now,
+		"updated_at":   now,
+	})
+}
+
+// MarkFailed отмечает событие неудачным; следующая доставка обработает его снова
+func (r *inboundEventPostgres) MarkFailed(ctx context.Context, id uuid.UUID, reason string) error {
+	return r.update(ctx, id, map[string]interface{}{
+		"status":     entity.InboundStatusFailed,
+		"error":      reason,
+		"updated_at": time.Now(),
+	})
+}
+
+func (r *inboundEventPostgres) update(ctx context.Context, id uuid.UUID, updates map[string]interface{}) error {
+	if err := transaction.FromContext(ctx, r.db).Model(&entity.InboundEvent{}).
+		Where("id = ?", id).
+		Updates(updates).Error; err != nil {
+		r.logger.Error(ctx, "Failed to update inbound event", err, logrus.Fields{"event_id": id.String()})
+		return apperror.DatabaseError("updating inbound event", err)
+	}
+	return nil
+}
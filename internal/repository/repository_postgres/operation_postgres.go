@@ -0,0 +1,71 @@
+package repositorypostgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/transaction"
+)
+
+// operationPostgres реализует OperationRepository
+type operationPostgres struct {
+	db     *gorm.DB
+	logger *logger.Logger
+}
+
+// NewOperationRepositoryPostgres создает репозиторий длительных операций
+func NewOperationRepositoryPostgres(db *gorm.DB, log *logrus.Logger) repository.OperationRepository {
+	return &operationPostgres{
+		db:     db,
+		logger: logger.New(log),
+	}
+}
+
+// Create сохраняет новую операцию
+func (r *operationPostgres) Create(ctx context.Context, op *entity.Operation) error {
+	if op.ID == uuid.Nil {
+		op.ID = uuid.New()
+	}
+
+	if err := transaction.FromContext(ctx, r.db).Create(op).Error; err != nil {
+		r.logger.Error(ctx, "Failed to create operation", err, logrus.Fields{"kind": op.Kind})
+		return apperror.DatabaseError("creating operation", err)
+	}
+	return nil
+}
+
+// GetByID возвращает операцию или nil, если ее нет
+func (r *operationPostgres) GetByID(ctx context.Context, id uuid.UUID) (*entity.Operation, error) {
+	var op entity.Operation
+	err := transaction.FromContext(ctx, r.db).Where("id = ?", id).First(&op).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logger.Error(ctx, "Failed to fetch operation", err, logrus.Fields{"operation_id": id.String()})
+		return nil, apperror.DatabaseError("fetching operation", err)
+	}
+	return &op, nil
+}
+
+// Update обновляет указанные колонки и updated_at
+func (r *operationPostgres) Update(ctx context.Context, id uuid.UUID, updates map[string]interface{}) error {
+	updates["updated_at"] = time.Now()
+
+	if err := transaction.FromContext(ctx, r.db).Model(&entity.Operation{}).
+		Where("id = ?", id).
+		Updates(updates).Error; err != nil {
+		r.logger.Error(ctx, "Failed to update operation", err, logrus.Fields{"operation_id": id.String()})
+		return apperror.DatabaseError("updating operation", err)
+	}
+	return nil
+}
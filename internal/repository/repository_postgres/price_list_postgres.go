@@ -0,0 +1,215 @@
+package repositorypostgres
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// ListPriceLists возвращает прайс-листы организации без цен
+func (edrp *esfDocumentRepositoryPostgres) ListPriceLists(ctx context.Context, orgID uuid.UUID) ([]entity.PriceList, error) {
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to get organization database", err, logrus.Fields{"org_id": orgID.String()})
+		return nil, apperror.DatabaseError("getting organization database", err)
+	}
+
+	var lists []entity.PriceList
+	if err := orgDB.WithContext(ctx).Order("name").Find(&lists).Error; err != nil {
+		edrp.logger.Error(ctx, "Failed to fetch price lists", err, logrus.Fields{"org_id": orgID.String()})
+		return nil, apperror.DatabaseError("fetching price lists", err)
+	}
+	return lists, nil
+}
+
+// GetPriceList возвращает прайс-лист с ценами
+func (edrp *esfDocumentRepositoryPostgres) GetPriceList(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*entity.PriceList, error) {
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to get organization database", err, logrus.Fields{"org_id": orgID.String()})
+		return nil, apperror.DatabaseError("getting organization database", err)
+	}
+
+	var list entity.PriceList
+	err = orgDB.WithContext(ctx).
+		Preload("Items", func(db *gorm.DB) *gorm.DB { return db.Order("sales_tax_code, unit_classification_code") }).
+		Where("id = ?", id).
+		First(&list).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFoundError("price list")
+		}
+		edrp.logger.Error(ctx, "Failed to fetch price list", err, logrus.Fields{"org_id": orgID.String(), "price_list_id": id.String()})
+		return nil, apperror.DatabaseError("fetching price list", err)
+	}
+	return &list, nil
+}
+
+// CreatePriceList сохраняет прайс-лист вместе с ценами
+func (edrp *esfDocumentRepositoryPostgres) CreatePriceList(ctx context.Context, orgID uuid.UUID, list *entity.PriceList) error {
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to get organization database", err, logrus.Fields{"org_id": orgID.String()})
+		return apperror.DatabaseError("getting organization database", err)
+	}
+
+	if err := orgDB.WithContext(ctx).Create(list).Error; err != nil {
+		edrp.logger.Error(ctx, "Failed to create price list", err, logrus.Fields{"org_id": orgID.String()})
+		return apperror.DatabaseError("creating price list", err)
+	}
+	return nil
+}
+
+// UpdatePriceList сохраняет прайс-лист и заменяет его цены в одной транзакции
+func (edrp *esfDocumentRepositoryPostgres) UpdatePriceList(ctx context.Context, orgID uuid.UUID, list *entity.PriceList) error {
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to get organization database", err, logrus.Fields{"org_id": orgID.String()})
+		return apperror.DatabaseError("getting organization database", err)
+	}
+
+	err = orgDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&entity.PriceList{}).Where("id = ?", list.ID).Updates(map[string]interface{}{
+			"name":           list.Name,
+			"contractor_tin": list.ContractorTin,
+			"customer_group": list.CustomerGroup,
+			"currency_code":  list.CurrencyCode,
+			"valid_from":     list.ValidFrom,
+			"valid_to":       list.ValidTo,
+		})
+		if result.Error != nil {
+			return apperror.DatabaseError("updating price list", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return apperror.NotFoundError("price list")
+		}
+		if err := tx.Where("price_list_id = ?", list.ID).Delete(&entity.PriceListItem{}).Error; err != nil {
+			return apperror.DatabaseError("deleting price list items", err)
+		}
+		for i := range list.Items {
+			list.Items[i].PriceListID = list.ID
+		}
+		if len(list.Items) > 0 {
+			if err := tx.Create(&list.Items).Error; err != nil {
+				return apperror.DatabaseError("creating price list items", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to update price list", err, logrus.Fields{"org_id": orgID.String(), "price_list_id": list.ID.String()})
+		return apperror.DatabaseErrorFrom("updating price list", err)
+	}
+	return nil
+}
+
+// DeletePriceList удаляет прайс-лист вместе с ценами
+func (edrp *esfDocumentRepositoryPostgres) DeletePriceList(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error {
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to get organization database", err, logrus.Fields{"org_id": orgID.String()})
+		return apperror.DatabaseError("getting organization database", err)
+	}
+
+	result := orgDB.WithContext(ctx).Where("id = ?", id).Delete(&entity.PriceList{})
+	if result.Error != nil {
+		edrp.logger.Error(ctx, "Failed to delete price list", result.Error, logrus.Fields{"org_id": orgID.String(), "price_list_id": id.String()})
+		return apperror.DatabaseError("deleting price list", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperror.NotFoundError("price list")
+	}
+	return nil
+}
+
+// FindPriceLists ищет прайс-листы контрагента и его групп, действующие на дату date
+func (edrp *esfDocumentRepositoryPostgres) FindPriceLists(ctx context.Context, orgID uuid.UUID, contractorTin string, currency string, date time.Time) ([]entity.PriceList, error) {
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to get organization database", err, logrus.Fields{"org_id": orgID.String()})
+		return nil, apperror.DatabaseError("getting organization database", err)
+	}
+
+	db := orgDB.WithContext(ctx)
+	groups := db.Model(&entity.CustomerGroupMember{}).Select("group_name").Where("contractor_tin = ?", contractorTin)
+	var lists []entity.PriceList
+	err = db.Preload("Items").
+		Where("currency_code = ?", currency).
+		Where("contractor_tin = ? OR customer_group IN (?)", contractorTin, groups).
+		Find(&lists).Error
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to find price lists", err, logrus.Fields{"org_id": orgID.String(), "contractor_tin": contractorTin})
+		return nil, apperror.DatabaseError("finding price lists", err)
+	}
+
+	// Срок действия сравнивается по календарным дням, как у договоров
+	valid := lists[:0]
+	for _, list := range lists {
+		if list.ValidOn(date) {
+			valid = append(valid, list)
+		}
+	}
+	sort.SliceStable(valid, func(i, j int) bool {
+		a, b := valid[i], valid[j]
+		if own := a.ContractorTin == contractorTin; own != (b.ContractorTin == contractorTin) {
+			return own
+		}
+		if a.ValidFrom == nil || b.ValidFrom == nil {
+			return b.ValidFrom == nil && a.ValidFrom != nil
+		}
+		return a.ValidFrom.After(*b.ValidFrom)
+	})
+	return valid, nil
+}
+
+// ListCustomerGroupMembers возвращает состав всех групп покупателей
+func (edrp *esfDocumentRepositoryPostgres) ListCustomerGroupMembers(ctx context.Context, orgID uuid.UUID) ([]entity.CustomerGroupMember, error) {
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to get organization database", err, logrus.Fields{"org_id": orgID.String()})
+		return nil, apperror.DatabaseError("getting organization database", err)
+	}
+
+	var members []entity.CustomerGroupMember
+	if err := orgDB.WithContext(ctx).Order("group_name, contractor_tin").Find(&members).Error; err != nil {
+		edrp.logger.Error(ctx, "Failed to fetch customer groups", err, logrus.Fields{"org_id": orgID.String()})
+		return nil, apperror.DatabaseError("fetching customer groups", err)
+	}
+	return members, nil
+}
+
+// SetCustomerGroupMembers заменяет состав группы покупателей; пустой список удаляет группу
+func (edrp *esfDocumentRepositoryPostgres) SetCustomerGroupMembers(ctx context.Context, orgID uuid.UUID, group string, tins []string) error {
+	orgDB, err := edrp.getOrgDB(ctx, orgID)
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to get organization database", err, logrus.Fields{"org_id": orgID.String()})
+		return apperror.DatabaseError("getting organization database", err)
+	}
+
+	err = orgDB.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("group_name = ?", group).Delete(&entity.CustomerGroupMember{}).Error; err != nil {
+			return err
+		}
+		if len(tins) == 0 {
+			return nil
+		}
+		members := make([]entity.CustomerGroupMember, len(tins))
+		for i, tin := range tins {
+			members[i] = entity.CustomerGroupMember{GroupName: group, ContractorTin: tin}
+		}
+		return tx.Create(&members).Error
+	})
+	if err != nil {
+		edrp.logger.Error(ctx, "Failed to update customer group", err, logrus.Fields{"org_id": orgID.String(), "group": group})
+		return apperror.DatabaseError("updating customer group", err)
+	}
+	return nil
+}
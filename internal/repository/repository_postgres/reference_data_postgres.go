@@ -0,0 +1,95 @@
+package repositorypostgres
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/transaction"
+)
+
+// referenceDataPostgres реализует ReferenceDataRepository по кодам из сохраненных документов
+type referenceDataPostgres struct {
+	logger *logger.Logger
+	db     *gorm.DB
+}
+
+// NewReferenceDataRepositoryPostgres создает новый экземпляр репозитория справочных данных
+func NewReferenceDataRepositoryPostgres(db *gorm.DB, log *logrus.Logger) repository.ReferenceDataRepository {
+	return &referenceDataPostgres{
+		logger: logger.New(log),
+		db:     db,
+	}
+}
+
+// GetCurrencyCodes возвращает коды валют из документов
+func (r *referenceDataPostgres) GetCurrencyCodes(ctx context.Context) ([]string, error) {
+	return r.distinct(ctx, &entity.EsfDocument{}, "currency_code")
+}
+
+// GetVATRateCodes возвращает коды ставок НДС из документов
+func (r *referenceDataPostgres) GetVATRateCodes(ctx context.Context) ([]string, error) {
+	return r.distinct(ctx, &entity.EsfDocument{}, "tax_rate_vat_code")
+}
+
+// GetSalesTaxCodes возвращает коды налога с продаж из позиций документов
+func (r *referenceDataPostgres) GetSalesTaxCodes(ctx context.Context) ([]string, error) {
+	return r.distinct(ctx, &entity.EsfEntries{}, "sales_tax_code")
+}
+
+// ListCodes возвращает коды официального справочника
+func (r *referenceDataPostgres) ListCodes(ctx context.Context, directory string) ([]entity.ReferenceCode, error) {
+	var codes []entity.ReferenceCode
+	if err := transaction.FromContext(ctx, r.db).
+		Where("directory = ?", directory).
+		Order("code").
+		Find(&codes).Error; err != nil {
+		r.logger.Error(ctx, "Failed to fetch directory codes", err, logrus.Fields{"directory": directory})
+		return nil, apperror.DatabaseError("fetching directory codes", err)
+	}
+	return codes, nil
+}
+
+// ReplaceCodes удаляет коды справочника и сохраняет новые в одной транзакции
+func (r *referenceDataPostgres) ReplaceCodes(ctx context.Context, directory string, codes []entity.ReferenceCode) error {
+	err := transaction.FromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("directory = ?", directory).Delete(&entity.ReferenceCode{}).Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+	if err != nil {
+		r.logger.Error(ctx, "Failed to replace directory codes", err, logrus.Fields{"directory": directory})
+		return apperror.DatabaseError("replacing directory codes", err)
+	}
+	return nil
+}
+
+// distinct возвращает уникальные непустые значения колонки.
+// Если таблица еще не создана (документы хранятся в БД организаций), возвращает пустой список.
+func (r *referenceDataPostgres) distinct(ctx context.Context, model interface{}, column string) ([]string, error) {
+	db := transaction.FromContext(ctx, r.db)
+	if !db.Migrator().HasTable(model) {
+		return []string{}, nil
+	}
+
+	var codes []string
+	if err := db.Model(model).
+		Where(column+" <> ''").
+		Distinct(column).
+		Order(column).
+		Pluck(column, &codes).Error; err != nil {
+		r.logger.Error(ctx, "Failed to fetch reference codes from database", err, logrus.Fields{"column": column})
+		return nil, apperror.DatabaseError("fetching reference codes", err)
+	}
+
+	return codes, nil
+}
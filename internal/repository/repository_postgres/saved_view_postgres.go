@@ -0,0 +1,89 @@
+package repositorypostgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/transaction"
+)
+
+// savedViewPostgres реализует SavedViewRepository
+type savedViewPostgres struct {
+	db     *gorm.DB
+	logger *logger.Logger
+}
+
+// NewSavedViewRepositoryPostgres создает репозиторий представлений списков
+func NewSavedViewRepositoryPostgres(db *gorm.DB, log *logrus.Logger) repository.SavedViewRepository {
+	return &savedViewPostgres{
+		db:     db,
+		logger: logger.New(log),
+	}
+}
+
+// List возвращает представления пользователя по имени
+func (r *savedViewPostgres) List(ctx context.Context, userID uuid.UUID) ([]entity.SavedView, error) {
+	var views []entity.SavedView
+	err := transaction.FromContext(ctx, r.db).Where("user_id = ?", userID).Order("name").Find(&views).Error
+	if err != nil {
+		r.logger.Error(ctx, "Failed to list saved views", err, logrus.Fields{"user_id": userID.String()})
+		return nil, apperror.DatabaseError("listing saved views", err)
+	}
+	return views, nil
+}
+
+// Get возвращает представление пользователя или nil
+func (r *savedViewPostgres) Get(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*entity.SavedView, error) {
+	var view entity.SavedView
+	err := transaction.FromContext(ctx, r.db).Where("id = ? AND user_id = ?", id, userID).First(&view).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		r.logger.Error(ctx, "Failed to fetch saved view", err, logrus.Fields{"view_id": id.String()})
+		return nil, apperror.DatabaseError("fetching saved view", err)
+	}
+	return &view, nil
+}
+
+// Save создает или обновляет представление, проверяя уникальность имени у пользователя
+func (r *savedViewPostgres) Save(ctx context.Context, view *entity.SavedView) error {
+	return transaction.FromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		err := tx.Model(&entity.SavedView{}).
+			Where("user_id = ? AND name = ? AND id <> ?", view.UserID, view.Name, view.ID).
+			Count(&count).Error
+		if err != nil {
+			return apperror.DatabaseError("checking saved view name", err)
+		}
+		if count > 0 {
+			return apperror.ConflictError("view with this name already exists")
+		}
+		if err := tx.Save(view).Error; err != nil {
+			r.logger.Error(ctx, "Failed to save view", err, logrus.Fields{"view_id": view.ID.String()})
+			return apperror.DatabaseError("saving view", err)
+		}
+		return nil
+	})
+}
+
+// Delete удаляет представление пользователя
+func (r *savedViewPostgres) Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	result := transaction.FromContext(ctx, r.db).Where("id = ? AND user_id = ?", id, userID).Delete(&entity.SavedView{})
+	if result.Error != nil {
+		r.logger.Error(ctx, "Failed to delete saved view", result.Error, logrus.Fields{"view_id": id.String()})
+		return apperror.DatabaseError("deleting saved view", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperror.NotFoundError("view")
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package repositorypostgres
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/transaction"
+)
+
+// tenantCredentialPostgres реализует TenantCredentialRepository
+type tenantCredentialPostgres struct {
+	db     *gorm.DB
+	logger *logger.Logger
+}
+
+// NewTenantCredentialRepositoryPostgres создает репозиторий учетных данных БД организаций
+func NewTenantCredentialRepositoryPostgres(db *gorm.DB, log *logrus.Logger) repository.TenantCredentialRepository {
+	return &tenantCredentialPostgres{
+		db:     db,
+		logger: logger.New(log),
+	}
+}
+
+// Get возвращает учетные данные организации или nil
+func (r *tenantCredentialPostgres) Get(ctx context.Context, organizationID uuid.UUID) (*entity.TenantDBCredential, error) {
+	var credential entity.TenantDBCredential
+	err := transaction.FromContext(ctx, r.db).Where("organization_id = ?", organizationID).First(&credential).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		r.logger.Error(ctx, "Failed to fetch tenant credential", err, logrus.Fields{"org_id": organizationID.String()})
+		return nil, apperror.DatabaseError("fetching tenant credential", err)
+	}
+	return &credential, nil
+}
+
+// Save сохраняет учетные данные одним INSERT ... ON CONFLICT; пароль шифруется сериализатором
+func (r *tenantCredentialPostgres) Save(ctx context.Context, credential *entity.TenantDBCredential) error {
+	err := transaction.FromContext(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "organization_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"database", "username", "password", "rotated_at", "updated_at"}),
+	}).Create(credential).Error
+	if err != nil {
+		r.logger.Error(ctx, "Failed to save tenant credential", err, logrus.Fields{"org_id": credential.OrganizationID.String()})
+		return apperror.DatabaseError("saving tenant credential", err)
+	}
+	return nil
+}
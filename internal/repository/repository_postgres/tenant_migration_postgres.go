@@ -0,0 +1,60 @@
+package repositorypostgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/transaction"
+)
+
+// tenantMigrationPostgres реализует TenantMigrationRepository
+type tenantMigrationPostgres struct {
+	db     *gorm.DB
+	logger *logger.Logger
+}
+
+// NewTenantMigrationRepositoryPostgres создает репозиторий результатов миграции БД организаций
+func NewTenantMigrationRepositoryPostgres(db *gorm.DB, log *logrus.Logger) repository.TenantMigrationRepository {
+	return &tenantMigrationPostgres{
+		db:     db,
+		logger: logger.New(log),
+	}
+}
+
+// Save сохраняет результат одним INSERT ... ON CONFLICT: при возобновлении результат заменяется
+func (r *tenantMigrationPostgres) Save(ctx context.Context, result *entity.TenantMigration) error {
+	err := transaction.FromContext(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "operation_id"}, {Name: "organization_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"database", "state", "applied", "error", "updated_at"}),
+	}).Create(result).Error
+	if err != nil {
+		r.logger.Error(ctx, "Failed to save tenant migration result", err, logrus.Fields{
+			"operation_id": result.OperationID.String(),
+			"org_id":       result.OrganizationID.String(),
+		})
+		return apperror.DatabaseError("saving tenant migration result", err)
+	}
+	return nil
+}
+
+// ListByOperation возвращает результаты операции
+func (r *tenantMigrationPostgres) ListByOperation(ctx context.Context, operationID uuid.UUID) ([]entity.TenantMigration, error) {
+	var results []entity.TenantMigration
+	err := transaction.FromContext(ctx, r.db).
+		Where("operation_id = ?", operationID).
+		Order("database").
+		Find(&results).Error
+	if err != nil {
+		r.logger.Error(ctx, "Failed to list tenant migration results", err, logrus.Fields{"operation_id": operationID.String()})
+		return nil, apperror.DatabaseError("listing tenant migration results", err)
+	}
+	return results, nil
+}
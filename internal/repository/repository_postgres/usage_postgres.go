@@ -0,0 +1,79 @@
+package repositorypostgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/metering"
+	"github.com/rusgainew/tunduck-app/pkg/transaction"
+)
+
+// usagePostgres реализует UsageRepository
+type usagePostgres struct {
+	db     *gorm.DB
+	logger *logger.Logger
+}
+
+// NewUsageRepositoryPostgres создает репозиторий учета тарифицируемых метрик
+func NewUsageRepositoryPostgres(db *gorm.DB, log *logrus.Logger) repository.UsageRepository {
+	return &usagePostgres{
+		db:     db,
+		logger: logger.New(log),
+	}
+}
+
+// AddUsage прибавляет приращения к суммам за час одним INSERT ... ON CONFLICT
+func (r *usagePostgres) AddUsage(ctx context.Context, records []metering.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	rows := make([]entity.UsageRecord, 0, len(records))
+	for _, rec := range records {
+		rows = append(rows, entity.UsageRecord{
+			OrganizationID: rec.OrganizationID,
+			Metric:         string(rec.Metric),
+			Hour:           rec.Hour.UTC(),
+			Quantity:       rec.Quantity,
+			UpdatedAt:      now,
+		})
+	}
+
+	err := transaction.FromContext(ctx, r.db).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "organization_id"}, {Name: "metric"}, {Name: "hour"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"quantity":   gorm.Expr("usage_records.quantity + excluded.quantity"),
+			"updated_at": gorm.Expr("excluded.updated_at"),
+		}),
+	}).Create(&rows).Error
+	if err != nil {
+		r.logger.Error(ctx, "Failed to add usage records", err, logrus.Fields{"count": len(rows)})
+		return apperror.DatabaseError("adding usage records", err)
+	}
+	return nil
+}
+
+// ListUsage возвращает суммы по организации, часу и метрике
+func (r *usagePostgres) ListUsage(ctx context.Context, filter repository.UsageFilter) ([]entity.UsageRecord, error) {
+	q := transaction.FromContext(ctx, r.db).Where("hour >= ? AND hour < ?", filter.From.UTC(), filter.To.UTC())
+	if filter.OrganizationID != uuid.Nil {
+		q = q.Where("organization_id = ?", filter.OrganizationID)
+	}
+
+	var records []entity.UsageRecord
+	if err := q.Order("organization_id, hour, metric").Find(&records).Error; err != nil {
+		r.logger.Error(ctx, "Failed to list usage records", err, logrus.Fields{"org_id": filter.OrganizationID.String()})
+		return nil, apperror.DatabaseError("listing usage records", err)
+	}
+	return records, nil
+}
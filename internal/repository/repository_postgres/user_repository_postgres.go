@@ -12,6 +12,9 @@ import (
 	"github.com/rusgainew/tunduck-app/pkg/apperror"
 	"github.com/rusgainew/tunduck-app/pkg/entity"
 	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+	"github.com/rusgainew/tunduck-app/pkg/query"
+	"github.com/rusgainew/tunduck-app/pkg/transaction"
 )
 
 type UserRepositoryPostgres struct {
@@ -29,7 +32,7 @@ func NewUserRepositoryPostgres(db *gorm.DB, log *logrus.Logger) repository.UserR
 func (r *UserRepositoryPostgres) Create(ctx context.Context, user *entity.User) error {
 	r.logger.Debug(ctx, "Creating user in database", logrus.Fields{"username": user.Username, "email": user.Email})
 
-	if err := r.db.WithContext(ctx).Create(user).Error; err != nil {
+	if err := transaction.FromContext(ctx, r.db).Create(user).Error; err != nil {
 		r.logger.Error(ctx, "Failed to create user in database", err, logrus.Fields{"username": user.Username})
 		return apperror.DatabaseError("creating user", err)
 	}
@@ -42,7 +45,7 @@ func (r *UserRepositoryPostgres) GetByID(ctx context.Context, id uuid.UUID) (*en
 	r.logger.Debug(ctx, "Fetching user by ID", logrus.Fields{"user_id": id.String()})
 
 	var user entity.User
-	err := r.db.WithContext(ctx).Where("id = ?", id).First(&user).Error
+	err := transaction.FromContext(ctx, r.db).Where("id = ?", id).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			r.logger.Debug(ctx, "User not found", logrus.Fields{"user_id": id.String()})
@@ -60,7 +63,7 @@ func (r *UserRepositoryPostgres) GetByUsername(ctx context.Context, username str
 	r.logger.Debug(ctx, "Fetching user by username", logrus.Fields{"username": username})
 
 	var user entity.User
-	err := r.db.WithContext(ctx).Where("username = ?", username).First(&user).Error
+	err := transaction.FromContext(ctx, r.db).Where("username = ?", username).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			r.logger.Debug(ctx, "User not found by username", logrus.Fields{"username": username})
@@ -78,7 +81,7 @@ func (r *UserRepositoryPostgres) GetByEmail(ctx context.Context, email string) (
 	r.logger.Debug(ctx, "Fetching user by email", logrus.Fields{"email": email})
 
 	var user entity.User
-	err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
+	err := transaction.FromContext(ctx, r.db).Where("email = ?", email).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			r.logger.Debug(ctx, "User not found by email", logrus.Fields{"email": email})
@@ -96,7 +99,7 @@ func (r *UserRepositoryPostgres) GetAll(ctx context.Context, limit int) ([]*enti
 	r.logger.Debug(ctx, "Fetching all users", logrus.Fields{"limit": limit})
 
 	var users []*entity.User
-	query := r.db.WithContext(ctx)
+	query := transaction.FromContext(ctx, r.db)
 
 	if limit > 0 {
 		query = query.Limit(limit)
@@ -112,10 +115,85 @@ func (r *UserRepositoryPostgres) GetAll(ctx context.Context, limit int) ([]*enti
 	return users, nil
 }
 
+// userFilterFields поля пользователей, доступные в выражении filter
+var userFilterFields = query.FilterFields{
+	"id":         {Column: "id", Type: query.TypeUUID},
+	"username":   {Column: "username", Type: query.TypeString},
+	"email":      {Column: "email", Type: query.TypeString},
+	"full_name":  {Column: "full_name", Type: query.TypeString},
+	"role":       {Column: "role", Type: query.TypeString},
+	"is_active":  {Column: "is_active", Type: query.TypeBool},
+	"created_at": {Column: "created_at", Type: query.TypeDate},
+	"updated_at": {Column: "updated_at", Type: query.TypeDate},
+}
+
+// GetAllCursor возвращает пользователей с курсорной (keyset) пагинацией и фильтрацией
+func (r *UserRepositoryPostgres) GetAllCursor(ctx context.Context, params pagination.CursorParams, filters pagination.UserFilterParams) ([]*entity.User, pagination.CursorInfo, error) {
+	r.logger.Debug(ctx, "Fetching users with cursor", logrus.Fields{
+		"limit":       params.Limit,
+		"sort":        params.Sort,
+		"order":       params.Order,
+		"has_cursor":  params.Cursor != "",
+		"has_filters": filters.HasFilters(),
+	})
+
+	var users []*entity.User
+	db, err := query.New(nil).Where(query.Filter(filters.Filter, userFilterFields)).Filter(transaction.FromContext(ctx, r.db))
+	if err != nil {
+		r.logger.Warn(ctx, "Invalid user query field", logrus.Fields{"error": err.Error()})
+		return nil, pagination.CursorInfo{}, invalidQueryError(err)
+	}
+
+	switch filters.Status {
+	case "active":
+		db = db.Where("is_active = ?", true)
+	case "inactive":
+		db = db.Where("is_active = ?", false)
+	}
+
+	if filters.RoleID != "" {
+		db = db.Where("role = ?", filters.RoleID)
+	}
+
+	if filters.Search != "" {
+		search := "%" + filters.Search + "%"
+		db = db.Where("username ILIKE ? OR email ILIKE ? OR full_name ILIKE ?", search, search, search)
+	}
+
+	db, err = params.Apply(db)
+	if err != nil {
+		r.logger.Warn(ctx, "Invalid cursor")
+		return nil, pagination.CursorInfo{}, apperror.ValidationError("invalid cursor")
+	}
+
+	if err := db.Find(&users).Error; err != nil {
+		r.logger.Error(ctx, "Failed to fetch users by cursor", err)
+		return nil, pagination.CursorInfo{}, apperror.DatabaseError("fetching users by cursor", err)
+	}
+
+	users, info := pagination.NewCursorPage(users, params, func(u *entity.User) (interface{}, string) {
+		switch params.Sort {
+		case "username":
+			return u.Username, u.ID.String()
+		case "email":
+			return u.Email, u.ID.String()
+		default:
+			return u.CreatedAt, u.ID.String()
+		}
+	})
+
+	r.logger.Debug(ctx, "Users fetched successfully", logrus.Fields{
+		"count":    len(users),
+		"has_next": info.HasNext,
+	})
+
+	return users, info, nil
+}
+
 func (r *UserRepositoryPostgres) Update(ctx context.Context, user *entity.User) error {
 	r.logger.Debug(ctx, "Updating user in database", logrus.Fields{"user_id": user.ID.String()})
 
-	if err := r.db.WithContext(ctx).Save(user).Error; err != nil {
+	if err := transaction.FromContext(ctx, r.db).Save(user).Error; err != nil {
 		r.logger.Error(ctx, "Failed to update user in database", err, logrus.Fields{"user_id": user.ID.String()})
 		return apperror.DatabaseError("updating user", err)
 	}
@@ -127,7 +205,7 @@ func (r *UserRepositoryPostgres) Update(ctx context.Context, user *entity.User)
 func (r *UserRepositoryPostgres) Delete(ctx context.Context, id uuid.UUID) error {
 	r.logger.Debug(ctx, "Deleting user from database", logrus.Fields{"user_id": id.String()})
 
-	if err := r.db.WithContext(ctx).Delete(&entity.User{}, "id = ?", id).Error; err != nil {
+	if err := transaction.FromContext(ctx, r.db).Delete(&entity.User{}, "id = ?", id).Error; err != nil {
 		r.logger.Error(ctx, "Failed to delete user from database", err, logrus.Fields{"user_id": id.String()})
 		return apperror.DatabaseError("deleting user", err)
 	}
@@ -135,3 +213,41 @@ func (r *UserRepositoryPostgres) Delete(ctx context.Context, id uuid.UUID) error
 	r.logger.Debug(ctx, "User deleted successfully", logrus.Fields{"user_id": id.String()})
 	return nil
 }
+
+// Restore восстанавливает мягко удаленного пользователя
+func (r *UserRepositoryPostgres) Restore(ctx context.Context, id uuid.UUID) error {
+	r.logger.Debug(ctx, "Restoring user", logrus.Fields{"user_id": id.String()})
+
+	result := transaction.FromContext(ctx, r.db).Unscoped().Model(&entity.User{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Update("deleted_at", nil)
+	if result.Error != nil {
+		r.logger.Error(ctx, "Failed to restore user", result.Error, logrus.Fields{"user_id": id.String()})
+		return apperror.DatabaseError("restoring user", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperror.New(apperror.ErrUserNotFound, "deleted user not found")
+	}
+
+	r.logger.Debug(ctx, "User restored successfully", logrus.Fields{"user_id": id.String()})
+	return nil
+}
+
+// Purge окончательно удаляет мягко удаленного пользователя
+func (r *UserRepositoryPostgres) Purge(ctx context.Context, id uuid.UUID) error {
+	r.logger.Debug(ctx, "Purging user", logrus.Fields{"user_id": id.String()})
+
+	result := transaction.FromContext(ctx, r.db).Unscoped().
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		Delete(&entity.User{})
+	if result.Error != nil {
+		r.logger.Error(ctx, "Failed to purge user", result.Error, logrus.Fields{"user_id": id.String()})
+		return apperror.DatabaseError("purging user", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return apperror.New(apperror.ErrUserNotFound, "deleted user not found")
+	}
+
+	r.logger.Debug(ctx, "User purged successfully", logrus.Fields{"user_id": id.String()})
+	return nil
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// SavedViewRepository хранит представления списков пользователей в основной БД
+type SavedViewRepository interface {
+	// List возвращает представления пользователя по имени
+	List(ctx context.Context, userID uuid.UUID) ([]entity.SavedView, error)
+	// Get возвращает представление пользователя или nil, если его нет
+	Get(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*entity.SavedView, error)
+	// Save создает или обновляет представление; имя уникально в пределах пользователя (ErrConflict)
+	Save(ctx context.Context, view *entity.SavedView) error
+	Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
+}
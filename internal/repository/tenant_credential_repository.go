@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// TenantCredentialRepository хранит учетные данные подключения к БД организаций
+type TenantCredentialRepository interface {
+	// Get возвращает учетные данные организации или nil, если их еще не выдавали
+	Get(ctx context.Context, organizationID uuid.UUID) (*entity.TenantDBCredential, error)
+	// Save создает или заменяет учетные данные организации
+	Save(ctx context.Context, credential *entity.TenantDBCredential) error
+}
@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// TenantMigrationRepository хранит результаты миграции БД организаций в основной БД
+type TenantMigrationRepository interface {
+	// Save создает или заменяет результат организации в операции
+	Save(ctx context.Context, result *entity.TenantMigration) error
+	// ListByOperation возвращает результаты операции, упорядоченные по имени БД
+	ListByOperation(ctx context.Context, operationID uuid.UUID) ([]entity.TenantMigration, error)
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/metering"
+)
+
+// UsageFilter отбор почасовых сумм: часы в [From, To); пустой OrganizationID — все организации
+type UsageFilter struct {
+	OrganizationID uuid.UUID
+	From           time.Time
+	To             time.Time
+}
+
+// UsageRepository хранит почасовые суммы тарифицируемых метрик в основной БД
+type UsageRepository interface {
+	// AddUsage прибавляет приращения к суммам за час (metering.Store)
+	AddUsage(ctx context.Context, records []metering.Record) error
+	// ListUsage возвращает суммы по организации, часу и метрике
+	ListUsage(ctx context.Context, filter UsageFilter) ([]entity.UsageRecord, error)
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
 )
 
 // UserRepository интерфейс для работы с пользователями
@@ -14,6 +15,11 @@ type UserRepository interface {
 	GetByUsername(ctx context.Context, username string) (*entity.User, error)
 	GetByEmail(ctx context.Context, email string) (*entity.User, error)
 	GetAll(ctx context.Context, limit int) ([]*entity.User, error)
+	GetAllCursor(ctx context.Context, params pagination.CursorParams, filters pagination.UserFilterParams) ([]*entity.User, pagination.CursorInfo, error)
 	Update(ctx context.Context, user *entity.User) error
 	Delete(ctx context.Context, id uuid.UUID) error
+
+	// Корзина: восстановление и окончательное удаление мягко удаленных записей
+	Restore(ctx context.Context, id uuid.UUID) error
+	Purge(ctx context.Context, id uuid.UUID) error
 }
@@ -0,0 +1,163 @@
+package seed
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/pkg/dates"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+)
+
+// userFixture демо-пользователь
+type userFixture struct {
+	Username string
+	Email    string
+	FullName string
+	Phone    string
+	Role     rbac.Role
+}
+
+// contractorFixture контрагент (покупатель), на которого выписываются документы
+type contractorFixture struct {
+	Name string
+	TIN  string
+}
+
+// currencyFixture валюта с курсом к сому
+type currencyFixture struct {
+	Code string
+	Rate float64
+}
+
+// itemFixture товар или услуга
+type itemFixture struct {
+	Name     string
+	UnitCode string
+	Price    float64
+}
+
+var demoUsers = []userFixture{
+	{Username: "admin", Email: "admin@tunduck.local", FullName: "Администратор Системы", Phone: "+996700000001", Role: rbac.RoleAdmin},
+	{Username: "accountant", Email: "accountant@tunduck.local", FullName: "Айгуль Бекова", Phone: "+996700000002", Role: rbac.RoleUser},
+	{Username: "manager", Email: "manager@tunduck.local", FullName: "Нурлан Асанов", Phone: "+996700000003", Role: rbac.RoleUser},
+	{Username: "auditor", Email: "auditor@tunduck.local", FullName: "Елена Ким", Phone: "+996700000004", Role: rbac.RoleViewer},
+}
+
+var demoOrganizations = []string{
+	"Demo Ala-Too Trade",
+	"Demo Issyk-Kul Logistics",
+	"Demo Bishkek Services",
+	"Demo Osh Agro",
+	"Demo Naryn Energy",
+}
+
+var demoContractors = []contractorFixture{
+	{Name: "ОсОО «Бай-Тушум Маркет»", TIN: "01203201910123"},
+	{Name: "ОсОО «Ак-Кеме Строй»", TIN: "02105201810456"},
+	{Name: "ИП Жумабаев А.", TIN: "21203199500789"},
+	{Name: "ОАО «Кыргызтелеком Сервис»", TIN: "00407199310234"},
+	{Name: "ОсОО «Нур Фарм»", TIN: "01511202010567"},
+	{Name: "ОсОО «Тянь-Шань Агро»", TIN: "03009201710890"},
+}
+
+var demoCurrencies = []currencyFixture{
+	{Code: "KGS", Rate: 1},
+	{Code: "KGS", Rate: 1},
+	{Code: "USD", Rate: 87.45},
+	{Code: "RUB", Rate: 0.95},
+	{Code: "KZT", Rate: 0.17},
+}
+
+var demoItems = []itemFixture{
+	{Name: "Цемент М400, мешок 50 кг", UnitCode: "796", Price: 520},
+	{Name: "Мука высшего сорта", UnitCode: "166", Price: 48.5},
+	{Name: "Транспортные услуги", UnitCode: "356", Price: 1500},
+	{Name: "Консультационные услуги", UnitCode: "356", Price: 3200},
+	{Name: "Ноутбук", UnitCode: "796", Price: 54000},
+	{Name: "Бумага офисная A4", UnitCode: "778", Price: 390},
+}
+
+// Коды справочников ЭСФ, из которых выбираются значения документов
+var (
+	operationTypeCodes = []string{"10", "10", "10", "20", "30"}
+	deliveryTypeCodes  = []string{"101", "102", "201"}
+	paymentCodes       = []string{"1", "2", "2"}
+	vatRateCodes       = []string{"12", "12", "0"}
+	salesTaxCodes      = []string{"0", "2"}
+)
+
+// documentFixture формирует документ с 1–4 позициями; суммы документа равны сумме позиций
+func documentFixture(rng *rand.Rand, n int, now time.Time) *models.EsfCreateDocumentRequest {
+	contractor := demoContractors[rng.Intn(len(demoContractors))]
+	currency := demoCurrencies[rng.Intn(len(demoCurrencies))]
+	vatCode := vatRateCodes[rng.Intn(len(vatRateCodes))]
+	delivery := now.AddDate(0, 0, -rng.Intn(180)).Truncate(24 * time.Hour)
+
+	vatRate := 0.0
+	if vatCode == "12" {
+		vatRate = 0.12
+	}
+
+	doc := &models.EsfCreateDocumentRequest{
+		IsPriceWithoutTaxes:  true,
+		OwnedCrmReceiptCode:  fmt.Sprintf("DEMO-%05d", n),
+		OperationTypeCode:    operationTypeCodes[rng.Intn(len(operationTypeCodes))],
+		DeliveryDate:         dates.Of(delivery),
+		DeliveryTypeCode:     deliveryTypeCodes[rng.Intn(len(deliveryTypeCodes))],
+		IsResident:           currency.Code == "KGS",
+		ContractorTin:        contractor.TIN,
+		SupplierBankAccount:  "1280016011" + fmt.Sprintf("%06d", rng.Intn(1000000)),
+		CurrencyCode:         currency.Code,
+		CurrencyRate:         currency.Rate,
+		SupplyContractNumber: fmt.Sprintf("Д-%d/%03d", delivery.Year(), rng.Intn(1000)),
+		ContractStartDate:    dates.Of(delivery.AddDate(0, -rng.Intn(12)-1, 0)),
+		Comment:              "Поставка для " + contractor.Name,
+		PaymentCode:          paymentCodes[rng.Intn(len(paymentCodes))],
+		TaxRateVATCode:       vatCode,
+	}
+	if !doc.IsResident {
+		doc.CountryCode = "KZ"
+		doc.ForeignName = contractor.Name
+	}
+
+	lines := 1 + rng.Intn(4)
+	for i := 0; i < lines; i++ {
+		item := demoItems[rng.Intn(len(demoItems))]
+		quantity := float64(1 + rng.Intn(50))
+		price := round2(item.Price / currency.Rate)
+		amount := round2(price * quantity)
+		salesTaxCode := salesTaxCodes[rng.Intn(len(salesTaxCodes))]
+		salesTax := 0.0
+		if salesTaxCode == "2" {
+			salesTax = round2(amount * 0.02)
+		}
+		vat := round2(amount * vatRate)
+
+		doc.CatalogEntries = append(doc.CatalogEntries, models.EsfEntriesModel{
+			UnitClassificationCode: item.UnitCode,
+			SalesTaxCode:           salesTaxCode,
+			Quantity:               quantity,
+			Price:                  price,
+			VatAmount:              vat,
+			SalesTaxAmount:         salesTax,
+			AmountWithoutTaxes:     amount,
+			TotalAmount:            round2(amount + vat + salesTax),
+		})
+		doc.TotalCurrencyValueWithoutTaxes = round2(doc.TotalCurrencyValueWithoutTaxes + amount)
+		doc.TotalCurrencyValue = round2(doc.TotalCurrencyValue + amount + vat + salesTax)
+	}
+	doc.AmountToBePaid = doc.TotalCurrencyValue
+	return doc
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// Document формирует n-й демо-документ так же, как Seeder; используется нагрузочным тестом (loadtest)
+func Document(rng *rand.Rand, n int, now time.Time) *models.EsfCreateDocumentRequest {
+	return documentFixture(rng, n, now)
+}
@@ -0,0 +1,221 @@
+// Package seed заполняет пустую БД демонстрационными данными для локальной разработки и e2e-тестов:
+// пользователями с разными ролями, организациями (с отдельными БД) и документами ЭСФ.
+//
+// Данные детерминированы (Options.RandSeed), повторный запуск пропускает существующих
+// пользователей и организации.
+package seed
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/auth"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+)
+
+// Значения по умолчанию
+const (
+	DefaultOrganizations = 2
+	DefaultDocuments     = 30
+	DefaultPassword      = "demo12345"
+	DefaultRandSeed      = 1
+)
+
+// Доли документов в разных состояниях
+const (
+	// deletedEvery каждый N-й документ перемещается в корзину
+	deletedEvery = 10
+	// paidEvery каждый N-й документ редактируется: отмечается оплата (версия 2)
+	paidEvery = 4
+)
+
+// Options параметры заполнения
+type Options struct {
+	// Organizations количество демо-организаций (не больше len(demoOrganizations))
+	Organizations int
+	// Documents количество документов в каждой новой организации
+	Documents int
+	// Password пароль всех демо-пользователей
+	Password string
+	// RandSeed начальное значение генератора; одинаковое значение дает одинаковые документы
+	RandSeed int64
+}
+
+// Summary итог заполнения
+type Summary struct {
+	Users         int
+	Organizations int
+	Documents     int
+	Paid          int
+	Deleted       int
+	Skipped       int
+}
+
+// Seeder создает демо-данные через сервисы приложения, чтобы срабатывали кеш, outbox и аудит
+type Seeder struct {
+	users  repository.UserRepository
+	orgs   services.EsfOrganizationService
+	docs   services.EsfDocumentService
+	logger *logger.Logger
+	now    func() time.Time
+}
+
+// New создает Seeder
+func New(users repository.UserRepository, orgs services.EsfOrganizationService, docs services.EsfDocumentService, log *logrus.Logger) *Seeder {
+	return &Seeder{
+		users:  users,
+		orgs:   orgs,
+		docs:   docs,
+		logger: logger.New(log),
+		now:    time.Now,
+	}
+}
+
+// Run создает пользователей, организации и документы
+func (s *Seeder) Run(ctx context.Context, opts Options) (Summary, error) {
+	opts = withDefaults(opts)
+	var summary Summary
+
+	if err := s.seedUsers(ctx, opts, &summary); err != nil {
+		return summary, err
+	}
+
+	existing, err := s.orgs.GetAllOrganizations(ctx)
+	if err != nil {
+		return summary, fmt.Errorf("listing organizations: %w", err)
+	}
+	names := make(map[string]bool, len(existing))
+	for _, org := range existing {
+		names[org.Name] = true
+	}
+
+	rng := rand.New(rand.NewSource(opts.RandSeed))
+	for _, name := range demoOrganizations[:opts.Organizations] {
+		if names[name] {
+			s.logger.Info(ctx, "Demo organization already exists, skipping", logrus.Fields{"name": name})
+			summary.Skipped++
+			continue
+		}
+
+		orgID, _, err := s.orgs.CreateOrganization(ctx, &models.EsfOrganizationModel{
+			Name:        name,
+			Description: "Демонстрационная организация",
+			Token:       fmt.Sprintf("demo-%016x", rng.Int63()),
+		})
+		if err != nil {
+			return summary, fmt.Errorf("creating organization %q: %w", name, err)
+		}
+		summary.Organizations++
+
+		if err := s.seedDocuments(ctx, rng, orgID, opts.Documents, &summary); err != nil {
+			return summary, fmt.Errorf("seeding documents of %q: %w", name, err)
+		}
+	}
+
+	s.logger.Info(ctx, "Seeding completed", logrus.Fields{
+		"users":         summary.Users,
+		"organizations": summary.Organizations,
+		"documents":     summary.Documents,
+		"skipped":       summary.Skipped,
+	})
+	return summary, nil
+}
+
+// seedUsers создает демо-пользователей с ролями admin, user и viewer
+func (s *Seeder) seedUsers(ctx context.Context, opts Options, summary *Summary) error {
+	hash, err := auth.HashPassword(opts.Password)
+	if err != nil {
+		return err
+	}
+
+	for _, fixture := range demoUsers {
+		existing, err := s.users.GetByUsername(ctx, fixture.Username)
+		if err != nil {
+			return fmt.Errorf("looking up user %q: %w", fixture.Username, err)
+		}
+		if existing != nil {
+			summary.Skipped++
+			continue
+		}
+
+		user := &entity.User{
+			ID:       uuid.New(),
+			Username: fixture.Username,
+			Email:    fixture.Email,
+			FullName: fixture.FullName,
+			Phone:    fixture.Phone,
+			Password: hash,
+			Role:     fixture.Role,
+			IsActive: true,
+		}
+		if err := s.users.Create(ctx, user); err != nil {
+			return fmt.Errorf("creating user %q: %w", fixture.Username, err)
+		}
+		summary.Users++
+	}
+	return nil
+}
+
+// seedDocuments создает документы организации: часть из них оплачена (отредактирована), часть в корзине
+func (s *Seeder) seedDocuments(ctx context.Context, rng *rand.Rand, orgID uuid.UUID, count int, summary *Summary) error {
+	now := s.now()
+	for i := 1; i <= count; i++ {
+		req := documentFixture(rng, summary.Documents+1, now)
+		resp, err := s.docs.CreateDocument(ctx, orgID, req)
+		if err != nil {
+			return err
+		}
+		summary.Documents++
+
+		id, err := uuid.Parse(resp.DocumentUuid)
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case i%deletedEvery == 0:
+			if err := s.docs.DeleteDocument(ctx, orgID, id); err != nil {
+				return err
+			}
+			summary.Deleted++
+		case i%paidEvery == 0:
+			paid := *req
+			paid.Version = 1
+			paid.PaidAmount = req.AmountToBePaid
+			paid.AmountToBePaid = 0
+			if err := s.docs.UpdateDocument(ctx, orgID, &models.EsfEditDocumentRequest{ID: id, EsfCreateDocumentRequest: paid}); err != nil {
+				return err
+			}
+			summary.Paid++
+		}
+	}
+	return nil
+}
+
+func withDefaults(opts Options) Options {
+	if opts.Organizations <= 0 {
+		opts.Organizations = DefaultOrganizations
+	}
+	if opts.Organizations > len(demoOrganizations) {
+		opts.Organizations = len(demoOrganizations)
+	}
+	if opts.Documents < 0 {
+		opts.Documents = 0
+	}
+	if opts.Password == "" {
+		opts.Password = DefaultPassword
+	}
+	if opts.RandSeed == 0 {
+		opts.RandSeed = DefaultRandSeed
+	}
+	return opts
+}
@@ -0,0 +1,118 @@
+package seed
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+type memoryUserRepository struct {
+	repository.UserRepository
+	users map[string]*entity.User
+}
+
+func (r *memoryUserRepository) GetByUsername(ctx context.Context, username string) (*entity.User, error) {
+	return r.users[username], nil
+}
+
+func (r *memoryUserRepository) Create(ctx context.Context, user *entity.User) error {
+	r.users[user.Username] = user
+	return nil
+}
+
+type memoryOrganizationService struct {
+	services.EsfOrganizationService
+	orgs []models.EsfOrganizationModel
+}
+
+func (s *memoryOrganizationService) GetAllOrganizations(ctx context.Context) ([]models.EsfOrganizationModel, error) {
+	return s.orgs, nil
+}
+
+func (s *memoryOrganizationService) CreateOrganization(ctx context.Context, org *models.EsfOrganizationModel) (uuid.UUID, string, error) {
+	s.orgs = append(s.orgs, *org)
+	return uuid.New(), "demo_db", nil
+}
+
+type countingDocumentService struct {
+	services.EsfDocumentService
+	created, updated, deleted int
+}
+
+func (s *countingDocumentService) CreateDocument(ctx context.Context, orgID uuid.UUID, doc *models.EsfCreateDocumentRequest) (*models.EsfCreateDocumentResponse, error) {
+	s.created++
+	return &models.EsfCreateDocumentResponse{DocumentUuid: uuid.NewString()}, nil
+}
+
+func (s *countingDocumentService) UpdateDocument(ctx context.Context, orgID uuid.UUID, doc *models.EsfEditDocumentRequest) error {
+	s.updated++
+	return nil
+}
+
+func (s *countingDocumentService) DeleteDocument(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error {
+	s.deleted++
+	return nil
+}
+
+func TestDocumentFixture_Deterministic(t *testing.T) {
+	now := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+
+	first := documentFixture(rand.New(rand.NewSource(42)), 1, now)
+	second := documentFixture(rand.New(rand.NewSource(42)), 1, now)
+	assert.Equal(t, first, second)
+}
+
+func TestDocumentFixture_TotalsMatchEntries(t *testing.T) {
+	rng := rand.New(rand.NewSource(DefaultRandSeed))
+	now := time.Now()
+
+	for n := 1; n <= 50; n++ {
+		doc := documentFixture(rng, n, now)
+		require.NotEmpty(t, doc.CatalogEntries)
+
+		var total, withoutTaxes float64
+		for _, entry := range doc.CatalogEntries {
+			total += entry.TotalAmount
+			withoutTaxes += entry.AmountWithoutTaxes
+		}
+		assert.InDelta(t, total, doc.TotalCurrencyValue, 0.01)
+		assert.InDelta(t, withoutTaxes, doc.TotalCurrencyValueWithoutTaxes, 0.01)
+		assert.Equal(t, doc.TotalCurrencyValue, doc.AmountToBePaid)
+		assert.Equal(t, doc.CurrencyCode == "KGS", doc.IsResident)
+	}
+}
+
+func TestSeeder_Run(t *testing.T) {
+	users := &memoryUserRepository{users: map[string]*entity.User{}}
+	orgs := &memoryOrganizationService{orgs: []models.EsfOrganizationModel{{Name: demoOrganizations[0]}}}
+	docs := &countingDocumentService{}
+	seeder := New(users, orgs, docs, logrus.New())
+
+	summary, err := seeder.Run(context.Background(), Options{Organizations: 2, Documents: 20})
+	require.NoError(t, err)
+
+	assert.Equal(t, len(demoUsers), summary.Users)
+	assert.Equal(t, 1, summary.Organizations)
+	assert.Equal(t, 1, summary.Skipped)
+	assert.Equal(t, 20, docs.created)
+	assert.Equal(t, 2, docs.deleted)
+	assert.Equal(t, 4, docs.updated)
+
+	// Повторный запуск ничего не создает
+	summary, err = seeder.Run(context.Background(), Options{Organizations: 2, Documents: 20})
+	require.NoError(t, err)
+	assert.Zero(t, summary.Users)
+	assert.Zero(t, summary.Organizations)
+	assert.Equal(t, len(demoUsers)+2, summary.Skipped)
+}
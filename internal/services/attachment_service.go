@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/pkg/metering"
+	"github.com/rusgainew/tunduck-app/pkg/storage"
+)
+
+// FilesPath префикс пути скачивания вложений по подписанной ссылке: /api/files/<ключ>
+const FilesPath = "/api/files/"
+
+// Значения по умолчанию для вложений
+const (
+	DefaultAttachmentLinkTTL    = time.Hour
+	DefaultAttachmentMaxLinkTTL = 7 * 24 * time.Hour
+	DefaultAttachmentMaxSize    = 4 << 20 // предел тела запроса Fiber по умолчанию
+)
+
+// AttachmentKeyPrefix префикс ключей вложений организации в хранилище
+func AttachmentKeyPrefix(orgID uuid.UUID) string {
+	return "attachments/" + orgID.String() + "/"
+}
+
+// AttachmentConfig параметры вложений и ссылок на их скачивание
+type AttachmentConfig struct {
+	// URLSecret ключ подписи ссылок на скачивание
+	URLSecret string
+	// BaseURL внешний адрес API для ссылок в письмах; пустой — ссылки относительные
+	BaseURL string
+	// LinkTTL срок действия ссылки по умолчанию, MaxLinkTTL — наибольший запрашиваемый
+	LinkTTL    time.Duration
+	MaxLinkTTL time.Duration
+	// MaxSize наибольший размер загружаемого файла в байтах
+	MaxSize int64
+}
+
+// AttachmentUpload файл, загружаемый пользователем
+type AttachmentUpload struct {
+	FileName    string
+	ContentType string
+	Size        int64
+	Body        io.Reader
+}
+
+// AttachmentLink подписанная ссылка на скачивание вложения без JWT
+type AttachmentLink struct {
+	Key       string    `json:"key"`
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// AttachmentService хранит файлы пользователей и выдает на них подписанные ссылки с ограниченным сроком
+type AttachmentService interface {
+	// Upload сохраняет файл в хранилище вложений организации и возвращает ссылку на него
+	Upload(ctx context.Context, orgID uuid.UUID, file AttachmentUpload) (*AttachmentLink, error)
+	// CreateLink подписывает ссылку на вложение организации; ttl <= 0 — срок по умолчанию
+	CreateLink(ctx context.Context, orgID uuid.UUID, key string, ttl time.Duration) (*AttachmentLink, error)
+	// Open проверяет подписанную ссылку и открывает вложение; вызывающий закрывает reader
+	Open(ctx context.Context, key, expires, signature string) (io.ReadCloser, *storage.ObjectInfo, error)
+	// SetMeter включает учет загруженных байт для выставления счетов
+	SetMeter(*metering.Meter)
+}
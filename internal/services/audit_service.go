@@ -0,0 +1,22 @@
+package services
+
+import (
+	"context"
+	"io"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/audit"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+)
+
+// AuditService ведет журнал аудита и отдает его администраторам
+type AuditService interface {
+	// Record сохраняет запись (реализует audit.Recorder для AuditMiddleware)
+	Record(ctx context.Context, log *entity.AuditLog) error
+	ListLogs(ctx context.Context, params pagination.PaginationParams, filter repository.AuditLogFilter) ([]entity.AuditLog, int64, error)
+	// ExportLogs пишет выборку в w в формате ExportFormatCSV или ExportFormatNDJSON
+	ExportLogs(ctx context.Context, filter repository.AuditLogFilter, format string, w io.Writer) error
+	// SetExporter дублирует сохраненные записи во внешний получатель (SIEM); nil выключает
+	SetExporter(exporter audit.Recorder)
+}
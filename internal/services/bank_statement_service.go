@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"io"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/pkg/dates"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// BankStatementUpload загружаемая выписка; пустой Format определяется по имени файла и содержимому
+type BankStatementUpload struct {
+	FileName   string
+	Format     string
+	UploadedBy string
+	Body       io.Reader
+}
+
+// PaymentCandidate документ, которому может соответствовать строка выписки
+type PaymentCandidate struct {
+	DocumentID           uuid.UUID  `json:"documentId"`
+	ContractorTin        string     `json:"contractorTin"`
+	DeliveryDate         dates.Date `json:"deliveryDate"`
+	SupplyContractNumber string     `json:"supplyContractNumber,omitempty"`
+	AmountDue            float64    `json:"amountDue"`
+	RemainingAmount      float64    `json:"remainingAmount"`
+	// ExactAmount остаток документа равен сумме строки
+	ExactAmount bool `json:"exactAmount"`
+	// ReferenceMatch номер договора, учетный номер или ID документа есть в ссылке или назначении платежа
+	ReferenceMatch bool `json:"referenceMatch"`
+}
+
+// UnmatchedStatementLine несопоставленная строка выписки с предложенными документами
+type UnmatchedStatementLine struct {
+	entity.BankStatementLine
+	Candidates []PaymentCandidate `json:"candidates"`
+}
+
+// BankStatementService сверяет банковские выписки с документами. При загрузке поступление
+// сопоставляется с документом автоматически, если ИНН и остаток указывают на один документ,
+// а при нескольких таких документах — если ссылка платежа указывает на один из них.
+// Сопоставление вносит оплату по документу.
+type BankStatementService interface {
+	Import(ctx context.Context, orgID uuid.UUID, upload BankStatementUpload) (*entity.BankStatement, error)
+	List(ctx context.Context, orgID uuid.UUID) ([]entity.BankStatement, error)
+	Get(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*entity.BankStatement, error)
+	// Unmatched возвращает несопоставленные строки с документами для ручного сопоставления
+	Unmatched(ctx context.Context, orgID uuid.UUID, id uuid.UUID) ([]UnmatchedStatementLine, error)
+	// MatchLine вручную сопоставляет строку выписки с документом
+	MatchLine(ctx context.Context, orgID uuid.UUID, statementID uuid.UUID, lineID uuid.UUID, documentID uuid.UUID, matchedBy string) (*entity.BankStatementLine, error)
+}
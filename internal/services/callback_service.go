@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Типы обратных вызовов шлюза ЭСФ
+const (
+	CallbackInvoiceStatusChanged = "invoice.status_changed"
+)
+
+// CallbackEvent входящее событие. ID назначает отправитель и сохраняет его при повторной доставке.
+type CallbackEvent struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// InvoiceStatusData данные события invoice.status_changed
+type InvoiceStatusData struct {
+	OrganizationID uuid.UUID `json:"organizationId"`
+	DocumentID     uuid.UUID `json:"documentId"`
+	Status         string    `json:"status"`
+}
+
+// CallbackService принимает обратные вызовы внешних сервисов ровно один раз
+type CallbackService interface {
+	// Consume применяет событие source. Повторно доставленное событие не применяется
+	// и возвращает duplicate=true; событие, обработка которого завершилась ошибкой, применяется снова.
+	Consume(ctx context.Context, source string, event CallbackEvent) (duplicate bool, err error)
+}
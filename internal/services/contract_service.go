@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// ContractDetails договор и его использование документами. Аннулированные документы не учитываются
+type ContractDetails struct {
+	entity.Contract
+	DocumentCount int64   `json:"documentCount"`
+	UsedAmount    float64 `json:"usedAmount"`
+	// RemainingLimit остаток лимита; nil — договор без лимита
+	RemainingLimit *float64 `json:"remainingLimit,omitempty"`
+	Expired        bool     `json:"expired"`
+}
+
+// ContractService справочник договоров с контрагентами
+type ContractService interface {
+	List(ctx context.Context, orgID uuid.UUID, contractorTin string) ([]entity.Contract, error)
+	Get(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*ContractDetails, error)
+	Create(ctx context.Context, orgID uuid.UUID, req *models.ContractRequest) (*entity.Contract, error)
+	Update(ctx context.Context, orgID uuid.UUID, id uuid.UUID, req *models.ContractRequest) (*entity.Contract, error)
+	Delete(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error
+}
@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/jobs"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+)
+
+// MaxDeadLetterRequeue максимальное количество записей в одном запросе повторной постановки
+const MaxDeadLetterRequeue = 100
+
+// DeadLetterRequeueFailure запись, которую не удалось поставить в обработку повторно
+type DeadLetterRequeueFailure struct {
+	ID    uuid.UUID `json:"id"`
+	Error string    `json:"error"`
+}
+
+// DeadLetterRequeueResult итог повторной постановки
+type DeadLetterRequeueResult struct {
+	Requeued []uuid.UUID                `json:"requeued"`
+	Failed   []DeadLetterRequeueFailure `json:"failed"`
+}
+
+// DeadLetterService ведет очередь недоставленных сообщений: задачи, исчерпавшие попытки,
+// сохраняются в БД, администратор изучает их и ставит выбранные в обработку повторно
+type DeadLetterService interface {
+	// RecordJob сохраняет задачу, исчерпавшую попытки (подключается как jobs.Config.OnDead)
+	RecordJob(ctx context.Context, job jobs.Job)
+	GetDeadLetter(ctx context.Context, id uuid.UUID) (*entity.DeadLetter, error)
+	ListDeadLetters(ctx context.Context, params pagination.PaginationParams, filter repository.DeadLetterFilter) ([]entity.DeadLetter, int64, error)
+	// Requeue ставит записи в статусе dead обратно в их очереди; ошибки по отдельным записям
+	// возвращаются в результате, а не прерывают обработку остальных
+	Requeue(ctx context.Context, ids []uuid.UUID) (*DeadLetterRequeueResult, error)
+	// SetJobManager подключает менеджер фоновых задач для повторной постановки задач
+	SetJobManager(manager *jobs.Manager)
+}
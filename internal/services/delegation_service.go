@@ -0,0 +1,25 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+)
+
+// DelegationService делегирование права подписи (rbac.PermissionSendDocument) на срок.
+// Передать право может только пользователь, имеющий его по роли; полученное право
+// дальше не передается. Служит rbac.Authority для маршрутов отправки документов
+type DelegationService interface {
+	rbac.Authority
+
+	// List возвращает делегирования, выданные пользователем или полученные им
+	List(ctx context.Context, userID uuid.UUID) ([]entity.SigningDelegation, error)
+	// Create передает право пользователя delegatorID пользователю из запроса
+	Create(ctx context.Context, delegatorID uuid.UUID, req *models.DelegationRequest) (*entity.SigningDelegation, error)
+	// Revoke досрочно отзывает делегирование; отозвать может делегирующий или администратор
+	Revoke(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*entity.SigningDelegation, error)
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultDraftReminderAfter через сколько после последнего изменения черновика приходит напоминание
+const DefaultDraftReminderAfter = 72 * time.Hour
+
+// DocumentScheduleConfig параметры отложенной отправки документов и напоминаний о черновиках
+type DocumentScheduleConfig struct {
+	// DraftReminderAfter срок после создания или изменения черновика, через который организация
+	// получает напоминание о неотправленном документе; 0 отключает напоминания
+	DraftReminderAfter time.Duration
+	// Queue очередь фоновых задач отправки и напоминаний
+	Queue string
+}
+
+// ScheduledSend запланированная отправка документа в ЭСФ
+type ScheduledSend struct {
+	DocumentID uuid.UUID `json:"documentId"`
+	SendAt     time.Time `json:"sendAt"`
+	// ScheduledBy пользователь, чьим правом подписи документ будет отправлен
+	ScheduledBy uuid.UUID `json:"scheduledBy"`
+}
+
+// DraftReminders планирует напоминания о черновиках; сервис документов вызывает его
+// при создании, изменении, отправке и удалении документа
+type DraftReminders interface {
+	// RemindDraft переносит напоминание о черновике на DraftReminderAfter от текущего момента
+	RemindDraft(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error
+	// ForgetDocument снимает с расписания напоминание и отложенную отправку документа,
+	// который отправлен или удален
+	ForgetDocument(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error
+}
+
+// DocumentScheduleService отложенная отправка документов в ЭСФ к заданному времени и напоминания
+// о неотправленных черновиках. Оба действия — отложенные задачи с ключом документа, поэтому
+// повторное планирование заменяет прежнее время, а не добавляет вторую задачу.
+type DocumentScheduleService interface {
+	DraftReminders
+	// ScheduleSend планирует отправку документа на sendAt правом подписи пользователя userID;
+	// право проверяется еще раз в момент отправки
+	ScheduleSend(ctx context.Context, orgID uuid.UUID, id uuid.UUID, userID uuid.UUID, sendAt time.Time) (*ScheduledSend, error)
+	// CancelSend отменяет запланированную отправку; 404, если отправка не запланирована
+	CancelSend(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/pkg/dates"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+)
+
+// MaxBulkDocuments наибольшее число документов в одном запросе массового удаления или восстановления
+const MaxBulkDocuments = 5000
+
+// Значения по умолчанию для корзины документов
+const (
+	DefaultTrashRetention      = 30 * 24 * time.Hour
+	DefaultTrashAsyncThreshold = 100
+)
+
+// TrashConfig параметры корзины документов
+type TrashConfig struct {
+	// Retention срок, в течение которого удаленный документ можно восстановить; затем он удаляется окончательно
+	Retention time.Duration
+	// AsyncThreshold наибольшее число документов, обрабатываемых в самом запросе; больше — фоновой задачей
+	AsyncThreshold int
+	// Queue очередь фоновых задач массовой обработки
+	Queue string
+}
+
+// BulkDocumentsRequest документы организации для массового удаления или восстановления
+type BulkDocumentsRequest struct {
+	OrganizationID uuid.UUID
+	IDs            []uuid.UUID
+	RequestedBy    string
+}
+
+// BulkSkipped документ, пропущенный при массовой обработке, с кодом ошибки и причиной
+type BulkSkipped struct {
+	ID     uuid.UUID `json:"id"`
+	Code   string    `json:"code"`
+	Reason string    `json:"reason"`
+}
+
+// BulkDocumentsResult итог массовой обработки. Небольшие пакеты обрабатываются сразу (Processed),
+// крупные — фоновой задачей (Operation); пропущенные документы определяются до начала обработки.
+type BulkDocumentsResult struct {
+	Processed int               `json:"processed"`
+	Skipped   []BulkSkipped     `json:"skipped"`
+	Operation *entity.Operation `json:"operation,omitempty"`
+}
+
+// TrashedDocument документ в корзине
+type TrashedDocument struct {
+	ID                 uuid.UUID  `json:"id"`
+	ContractorTin      string     `json:"contractorTin"`
+	CurrencyCode       string     `json:"currencyCode"`
+	TotalCurrencyValue float64    `json:"totalCurrencyValue"`
+	DeliveryDate       dates.Date `json:"deliveryDate"`
+	CreatedAt          time.Time  `json:"createdAt"`
+	DeletedAt          time.Time  `json:"deletedAt"`
+	// PurgeAt время окончательного удаления; до него документ можно восстановить
+	PurgeAt time.Time `json:"purgeAt"`
+}
+
+// DocumentTrashService массовое удаление документов в корзину, восстановление в пределах срока
+// хранения и окончательное удаление документов с истекшим сроком
+type DocumentTrashService interface {
+	// BulkDelete переносит в корзину документы, которые еще можно изменять
+	BulkDelete(ctx context.Context, req BulkDocumentsRequest) (*BulkDocumentsResult, error)
+	// BulkRestore восстанавливает документы, удаленные не раньше срока хранения
+	BulkRestore(ctx context.Context, req BulkDocumentsRequest) (*BulkDocumentsResult, error)
+	// ListTrash возвращает документы корзины организации, последние удаленные первыми
+	ListTrash(ctx context.Context, orgID uuid.UUID, params pagination.PaginationParams) ([]TrashedDocument, int64, error)
+	// PurgeExpired окончательно удаляет документы всех организаций с истекшим сроком хранения
+	PurgeExpired(ctx context.Context) (int64, error)
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/i18n"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+)
+
+// EmailRequest письмо по шаблону pkg/mail
+type EmailRequest struct {
+	Template string
+	To       string
+	// Language язык шаблона; пустой — i18n.DefaultLanguage
+	Language i18n.Language
+	Data     map[string]interface{}
+}
+
+// EmailService ставит письма в очередь фоновых задач и ведет журнал доставки
+type EmailService interface {
+	// Send формирует письмо, записывает его в журнал со статусом queued и ставит задачу отправки
+	Send(ctx context.Context, req EmailRequest) (*entity.EmailDelivery, error)
+	GetDelivery(ctx context.Context, id uuid.UUID) (*entity.EmailDelivery, error)
+	ListDeliveries(ctx context.Context, params pagination.PaginationParams, status string) ([]entity.EmailDelivery, int64, error)
+}
@@ -2,22 +2,74 @@ package services
 
 import (
 	"context"
+	"io"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/repository"
 	"github.com/rusgainew/tunduck-app/pkg/cache"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/esfgateway"
+	"github.com/rusgainew/tunduck-app/pkg/metering"
 	"github.com/rusgainew/tunduck-app/pkg/pagination"
+	"github.com/rusgainew/tunduck-app/pkg/search"
 )
 
+// DocumentPayments оплаты документа и расчет остатка: AmountDue — начальное сальдо и общая
+// стоимость, RemainingAmount — AmountDue за вычетом PaidAmount (отрицательный при переплате)
+type DocumentPayments struct {
+	DocumentID      uuid.UUID        `json:"documentId"`
+	AmountDue       float64          `json:"amountDue"`
+	PaidAmount      float64          `json:"paidAmount"`
+	RemainingAmount float64          `json:"remainingAmount"`
+	DueDate         *time.Time       `json:"dueDate,omitempty"`
+	Status          string           `json:"status"`
+	Payments        []entity.Payment `json:"payments"`
+}
+
 type EsfDocumentService interface {
 	GetAllDocuments(ctx context.Context, orgID uuid.UUID) ([]models.EsfCreateDocumentRequest, error)
 	GetDocumentByID(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*models.EsfCreateDocumentRequest, error)
 	CreateDocument(ctx context.Context, orgID uuid.UUID, doc *models.EsfCreateDocumentRequest) (*models.EsfCreateDocumentResponse, error)
 	UpdateDocument(ctx context.Context, orgID uuid.UUID, doc *models.EsfEditDocumentRequest) error
 	DeleteDocument(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error
+	RestoreDocument(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error
+	PurgeDocument(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error
+	// UpdateDocumentStatus сохраняет статус документа в ЭСФ из обратного вызова шлюза
+	UpdateDocumentStatus(ctx context.Context, orgID uuid.UUID, id uuid.UUID, status string) error
+
+	// DocumentWarnings предупреждения о сохраненном документе, например об истекшем договоре
+	DocumentWarnings(ctx context.Context, orgID uuid.UUID, id uuid.UUID) ([]models.DocumentWarning, error)
+
+	// WriteDocumentPDF пишет печатную форму документа с наименованиями на языке документа
+	WriteDocumentPDF(ctx context.Context, orgID uuid.UUID, id uuid.UUID, w io.Writer) error
+
+	// Оплаты документа; каждый метод возвращает оплаты и пересчитанный остаток
+	ListPayments(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*DocumentPayments, error)
+	RecordPayment(ctx context.Context, orgID uuid.UUID, id uuid.UUID, createdBy string, req *models.PaymentRequest) (*DocumentPayments, error)
+	DeletePayment(ctx context.Context, orgID uuid.UUID, id uuid.UUID, paymentID uuid.UUID) (*DocumentPayments, error)
 
 	// Пагіновані методи
 	GetAllDocumentsPaginated(ctx context.Context, orgID uuid.UUID, params pagination.PaginationParams, filters pagination.DocumentFilterParams) ([]models.EsfCreateDocumentRequest, int64, error)
+	GetAllDocumentsCursor(ctx context.Context, orgID uuid.UUID, params pagination.CursorParams, filters pagination.DocumentFilterParams) ([]models.EsfCreateDocumentRequest, pagination.CursorInfo, error)
+
+	// Поиск: OpenSearch, если настроен для организации, иначе полнотекстовый поиск Postgres
+	SearchDocuments(ctx context.Context, orgID uuid.UUID, text string, params pagination.PaginationParams) ([]models.EsfCreateDocumentRequest, int64, error)
+	SetSearchClient(*search.Client)
+
+	// SendDocument отправляет документ в ЭСФ; маршрут проверяет право подписи с учетом делегирования
+	SendDocument(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*models.EsfCreateDocumentResponse, error)
+	SetGateway(gw esfgateway.Gateway, orgs repository.EsfOrganizationRepository)
+	// SetMeter включает учет отправленных документов для выставления счетов
+	SetMeter(*metering.Meter)
+
+	// Курс валюты документа без курса подставляется на дату поставки
+	SetExchangeRates(ExchangeRateService)
+	// Дата поставки проверяется по текущей дате в часовом поясе организации
+	SetTimezones(Timezones)
+	// Напоминания о черновиках переносятся при изменении документа и снимаются при отправке и удалении
+	SetDraftReminders(DraftReminders)
 
 	// Cache management
 	SetCacheManager(cache.CacheManager)
@@ -15,9 +15,14 @@ type EsfOrganizationService interface {
 	CreateOrganization(ctx context.Context, org *models.EsfOrganizationModel) (uuid.UUID, string, error)
 	UpdateOrganization(ctx context.Context, org *models.EsfOrganizationModel) error
 	DeleteOrganization(ctx context.Context, id uuid.UUID) error
+	RestoreOrganization(ctx context.Context, id uuid.UUID) error
+	PurgeOrganization(ctx context.Context, id uuid.UUID) error
+	// SetPlan назначает организации тарифный план, определяющий ее квоты
+	SetPlan(ctx context.Context, id uuid.UUID, plan string) error
 
 	// Пагіновані методи
 	GetAllOrganizationsPaginated(ctx context.Context, params pagination.PaginationParams, filters pagination.OrganizationFilterParams) ([]models.EsfOrganizationModel, int64, error)
+	GetAllOrganizationsCursor(ctx context.Context, params pagination.CursorParams, filters pagination.OrganizationFilterParams) ([]models.EsfOrganizationModel, pagination.CursorInfo, error)
 
 	// Кеширование
 	CacheWarmOrganizations(ctx context.Context) error
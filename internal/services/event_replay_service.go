@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventReplayRequest повторная отправка событий организации за период [From, To)
+type EventReplayRequest struct {
+	OrganizationID uuid.UUID `json:"organizationId"`
+	From           time.Time `json:"from"`
+	To             time.Time `json:"to"`
+	// Types ограничивает типы событий; пустой — все типы
+	Types []string `json:"types,omitempty"`
+}
+
+// EventReplayResult итог повторной отправки
+type EventReplayResult struct {
+	EventReplayRequest
+	Replayed int `json:"replayed"`
+}
+
+// EventReplayService повторно публикует уже отправленные доменные события в шину,
+// например после сбоя или исправления ошибки у потребителя
+type EventReplayService interface {
+	ReplayEvents(ctx context.Context, req EventReplayRequest) (*EventReplayResult, error)
+}
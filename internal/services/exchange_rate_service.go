@@ -0,0 +1,23 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/rusgainew/tunduck-app/pkg/cache"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/exchangerates"
+)
+
+// ExchangeRateService официальные курсы валют с историей по дням
+type ExchangeRateService interface {
+	// Refresh загружает последние курсы из источника и сохраняет их
+	Refresh(ctx context.Context) error
+	// Rates возвращает курсы всех валют, действовавшие на дату
+	Rates(ctx context.Context, date time.Time) ([]entity.ExchangeRate, error)
+	// Rate возвращает курс валюты на дату; для сома — 1, без курса — nil
+	Rate(ctx context.Context, currency string, date time.Time) (*entity.ExchangeRate, error)
+
+	SetSource(source exchangerates.Source)
+	SetCacheManager(cacheManager cache.CacheManager)
+}
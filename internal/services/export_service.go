@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+	"github.com/rusgainew/tunduck-app/pkg/report"
+)
+
+// Форматы выгрузки данных
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+)
+
+// Форматы отчетов
+const (
+	ExportFormatXLSX = "xlsx"
+	ExportFormatPDF  = "pdf"
+)
+
+// Типы фоновых выгрузок
+const (
+	ExportTypeDocuments = "documents"
+	ExportTypeAuditLogs = "audit_logs"
+	ExportTypeReport    = "report"
+)
+
+// ExportContentType возвращает Content-Type файла выгрузки по формату
+func ExportContentType(format string) string {
+	switch format {
+	case ExportFormatNDJSON:
+		return "application/x-ndjson"
+	case ExportFormatXLSX:
+		return report.ContentTypeXLSX
+	case ExportFormatPDF:
+		return report.ContentTypePDF
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// ExportConfig параметры фоновых выгрузок
+type ExportConfig struct {
+	// URLSecret ключ подписи ссылок на скачивание
+	URLSecret string
+	// BaseURL внешний адрес API для абсолютных ссылок; пустой — ссылки относительные
+	BaseURL string
+	// LinkTTL срок действия одной ссылки на скачивание
+	LinkTTL time.Duration
+	// Retention срок хранения готового файла
+	Retention time.Duration
+	// Queue очередь фоновых задач выгрузки
+	Queue string
+}
+
+// ExportRequest параметры новой выгрузки; фильтр используется в зависимости от Type
+type ExportRequest struct {
+	Type           string
+	Format         string
+	RequestedBy    string
+	OrganizationID uuid.UUID
+	DocumentFilter pagination.DocumentFilterParams
+	AuditFilter    repository.AuditLogFilter
+	Report         ReportParams
+}
+
+// ExportStatus состояние выгрузки с прогрессом и ссылкой на скачивание готового файла
+type ExportStatus struct {
+	*entity.ExportJob
+	// Progress доля выгруженных записей в процентах
+	Progress             float64    `json:"progress"`
+	DownloadURL          string     `json:"downloadUrl,omitempty"`
+	DownloadURLExpiresAt *time.Time `json:"downloadUrlExpiresAt,omitempty"`
+}
+
+// ExportService выполняет крупные выгрузки фоновыми задачами и выдает подписанные ссылки на файлы
+type ExportService interface {
+	CreateExport(ctx context.Context, req ExportRequest) (*ExportStatus, error)
+	GetExport(ctx context.Context, id uuid.UUID) (*ExportStatus, error)
+	// OpenDownload проверяет подписанную ссылку и открывает файл выгрузки; вызывающий закрывает reader
+	OpenDownload(ctx context.Context, id uuid.UUID, expires, signature string) (io.ReadCloser, *entity.ExportJob, error)
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"context"
+
+	"github.com/rusgainew/tunduck-app/pkg/migrations"
+)
+
+// DatabaseMigrationStatus состояние миграций одной БД
+type DatabaseMigrationStatus struct {
+	Database       string              `json:"database"`
+	OrganizationID string              `json:"organizationId,omitempty"`
+	Migrations     []migrations.Status `json:"migrations"`
+	Pending        []string            `json:"pending"`
+	DryRun         []migrations.Plan   `json:"dryRun,omitempty"`
+	Error          string              `json:"error,omitempty"`
+}
+
+// MigrationReport состояние миграций основной БД и БД организаций
+type MigrationReport struct {
+	Main    DatabaseMigrationStatus   `json:"main"`
+	Tenants []DatabaseMigrationStatus `json:"tenants"`
+}
+
+type MigrationService interface {
+	// GetStatus возвращает примененные и ожидающие миграции; при dryRun добавляет SQL неприменных миграций
+	GetStatus(ctx context.Context, dryRun bool) (*MigrationReport, error)
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// Виды длительных операций
+const (
+	OperationKindExport             = "export"
+	OperationKindReport             = "report"
+	OperationKindBulkDelete         = "bulk_delete"
+	OperationKindBulkRestore        = "bulk_restore"
+	OperationKindTenantMigration    = "tenant_migration"
+	OperationKindCredentialRotation = "tenant_credential_rotation"
+)
+
+// OperationLocation путь ресурса операции для заголовка Location ответа 202
+func OperationLocation(id uuid.UUID) string {
+	return "/api/operations/" + id.String()
+}
+
+// NewOperation параметры новой операции. ID задается, если операция отслеживает
+// ресурс с собственным идентификатором (например, выгрузку), иначе генерируется.
+type NewOperation struct {
+	ID             uuid.UUID
+	Kind           string
+	RequestedBy    string
+	OrganizationID *uuid.UUID
+}
+
+// OperationStatus состояние операции с прогрессом в процентах
+type OperationStatus struct {
+	*entity.Operation
+	Progress float64 `json:"progress"`
+}
+
+// OperationService ведет состояние длительных операций. Методы обновления вызываются
+// исполнителем операции (фоновой задачей); их ошибки только логируются, чтобы сбой учета
+// не прерывал саму работу.
+type OperationService interface {
+	// Start создает операцию в состоянии pending
+	Start(ctx context.Context, req NewOperation) (*entity.Operation, error)
+	GetOperation(ctx context.Context, id uuid.UUID) (*OperationStatus, error)
+	// Running переводит операцию в running и сбрасывает прогресс и ошибку прошлой попытки
+	Running(ctx context.Context, id uuid.UUID)
+	// Progress сохраняет число обработанных записей; total <= 0 оставляет общее число без изменений
+	Progress(ctx context.Context, id uuid.UUID, processed, total int64)
+	// Succeed завершает операцию и сохраняет путь ресурса с результатом
+	Succeed(ctx context.Context, id uuid.UUID, resultURL string)
+	// Retry возвращает операцию в pending после неудачной попытки, которая будет повторена
+	Retry(ctx context.Context, id uuid.UUID, cause error)
+	// Fail завершает операцию с ошибкой
+	Fail(ctx context.Context, id uuid.UUID, cause error)
+}
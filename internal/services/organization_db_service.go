@@ -15,6 +15,9 @@ type OrganizationDBService interface {
 	// GetOrganizationDatabase получает подключение к БД организации
 	GetOrganizationDatabase(ctx context.Context, organizationID uuid.UUID) (*gorm.DB, error)
 
+	// Reconnect пересоздает пул подключений к БД организации с ее текущими учетными данными
+	Reconnect(ctx context.Context, organizationID uuid.UUID) error
+
 	// DeleteOrganizationDatabase удаляет БД организации
 	DeleteOrganizationDatabase(ctx context.Context, organizationID uuid.UUID) error
 }
@@ -0,0 +1,44 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// ResolvedPrice цена товара для контрагента и прайс-лист, из которого она взята
+type ResolvedPrice struct {
+	SalesTaxCode           string    `json:"salesTaxCode"`
+	UnitClassificationCode string    `json:"unitClassificationCode,omitempty"`
+	Price                  float64   `json:"price"`
+	FloorPrice             float64   `json:"floorPrice"`
+	PriceListID            uuid.UUID `json:"priceListId"`
+	PriceListName          string    `json:"priceListName"`
+}
+
+// CustomerGroup группа покупателей с общими прайс-листами
+type CustomerGroup struct {
+	Name           string   `json:"name"`
+	ContractorTins []string `json:"contractorTins"`
+}
+
+// PriceListService прайс-листы по контрагентам и группам покупателей. Цена контрагента
+// важнее цены его группы; минимальные цены проверяются при сохранении документа
+type PriceListService interface {
+	List(ctx context.Context, orgID uuid.UUID) ([]entity.PriceList, error)
+	Get(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*entity.PriceList, error)
+	Create(ctx context.Context, orgID uuid.UUID, req *models.PriceListRequest) (*entity.PriceList, error)
+	Update(ctx context.Context, orgID uuid.UUID, id uuid.UUID, req *models.PriceListRequest) (*entity.PriceList, error)
+	Delete(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error
+
+	// Prices возвращает цены для заполнения позиций документа контрагента на дату поставки
+	Prices(ctx context.Context, orgID uuid.UUID, contractorTin string, currency string, date time.Time) ([]ResolvedPrice, error)
+
+	ListGroups(ctx context.Context, orgID uuid.UUID) ([]CustomerGroup, error)
+	// SetGroup заменяет состав группы; пустой список удаляет группу
+	SetGroup(ctx context.Context, orgID uuid.UUID, name string, tins []string) (*CustomerGroup, error)
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/rusgainew/tunduck-app/pkg/cache"
+	"github.com/rusgainew/tunduck-app/pkg/esfgateway"
+)
+
+// ReferenceData справочные коды для документов ЭСФ
+type ReferenceData struct {
+	Currencies    []string `json:"currencies"`
+	VATRateCodes  []string `json:"vatRateCodes"`
+	SalesTaxCodes []string `json:"salesTaxCodes"`
+}
+
+// Directory официальный справочник ЭСФ (esfgateway.Directories)
+type Directory struct {
+	Name  string                      `json:"name"`
+	Codes []esfgateway.DirectoryEntry `json:"codes"`
+	// SyncedAt время последней синхронизации; пусто, если справочник еще не загружался
+	SyncedAt *time.Time `json:"syncedAt,omitempty"`
+}
+
+// DirectorySyncResult итог синхронизации справочников со шлюзом
+type DirectorySyncResult struct {
+	// Synced число кодов в каждом обновленном справочнике
+	Synced map[string]int `json:"synced"`
+	// Failed ошибки справочников, которые обновить не удалось; прежние коды сохраняются
+	Failed map[string]string `json:"failed,omitempty"`
+}
+
+type ReferenceDataService interface {
+	GetReferenceData(ctx context.Context) (*ReferenceData, error)
+
+	// Официальные справочники ЭСФ
+	ListDirectories() []string
+	GetDirectory(ctx context.Context, name string) (*Directory, error)
+	SyncDirectories(ctx context.Context) (*DirectorySyncResult, error)
+	SetGateway(gw esfgateway.Gateway)
+
+	// Кеширование
+	CacheWarmReferenceData(ctx context.Context) error
+	SetCacheManager(cacheManager cache.CacheManager)
+}
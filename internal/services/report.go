@@ -0,0 +1,70 @@
+package services
+
+import "time"
+
+// Виды отчетов
+const (
+	ReportSalesRegister        = "sales_register"
+	ReportPurchasesRegister    = "purchases_register"
+	ReportCounterpartyTurnover = "counterparty_turnover"
+)
+
+// Коды вида операции документа, по которым строятся реестры
+const (
+	OperationTypeSale     = "10"
+	OperationTypePurchase = "20"
+)
+
+// ReportDefinition описание предопределенного отчета
+type ReportDefinition struct {
+	Kind        string   `json:"kind"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Formats     []string `json:"formats"`
+}
+
+// reportDefinitions отчеты, доступные для заказа
+var reportDefinitions = []ReportDefinition{
+	{
+		Kind:        ReportSalesRegister,
+		Title:       "Sales register",
+		Description: "Documents with operation type 10 delivered in the period, with VAT and sales tax",
+	},
+	{
+		Kind:        ReportPurchasesRegister,
+		Title:       "Purchases register",
+		Description: "Documents with operation type 20 delivered in the period, with VAT and sales tax",
+	},
+	{
+		Kind:        ReportCounterpartyTurnover,
+		Title:       "Counterparty turnover",
+		Description: "Sales and purchases per counterparty TIN for the period",
+	},
+}
+
+// ReportDefinitions возвращает описания всех отчетов
+func ReportDefinitions() []ReportDefinition {
+	defs := make([]ReportDefinition, len(reportDefinitions))
+	for i, def := range reportDefinitions {
+		def.Formats = []string{ExportFormatXLSX, ExportFormatPDF}
+		defs[i] = def
+	}
+	return defs
+}
+
+// FindReportDefinition ищет описание отчета по виду
+func FindReportDefinition(kind string) (ReportDefinition, bool) {
+	for _, def := range ReportDefinitions() {
+		if def.Kind == kind {
+			return def, true
+		}
+	}
+	return ReportDefinition{}, false
+}
+
+// ReportParams параметры отчета: вид и период по дате поставки, обе границы включительно
+type ReportParams struct {
+	Kind string    `json:"kind"`
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
@@ -0,0 +1,33 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+)
+
+// SavedView сохраненное представление списка документов
+type SavedView struct {
+	ID        uuid.UUID                       `json:"id"`
+	Name      string                          `json:"name"`
+	Filter    pagination.DocumentFilterParams `json:"filter"`
+	Sort      string                          `json:"sort,omitempty"`
+	Order     string                          `json:"order,omitempty"`
+	CreatedAt time.Time                       `json:"createdAt"`
+	UpdatedAt time.Time                       `json:"updatedAt"`
+}
+
+// SavedViewService личные представления списка документов: пользователь сохраняет
+// фильтры и сортировку под именем и применяет их по ID (?view= в списке документов)
+type SavedViewService interface {
+	List(ctx context.Context, userID uuid.UUID) ([]SavedView, error)
+	// Get возвращает представление пользователя; чужое представление не найдено (404)
+	Get(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*SavedView, error)
+	Create(ctx context.Context, userID uuid.UUID, req *models.SavedViewRequest) (*SavedView, error)
+	Update(ctx context.Context, userID uuid.UUID, id uuid.UUID, req *models.SavedViewRequest) (*SavedView, error)
+	Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
+}
@@ -0,0 +1,187 @@
+package service_impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/metering"
+	"github.com/rusgainew/tunduck-app/pkg/signature"
+	"github.com/rusgainew/tunduck-app/pkg/storage"
+)
+
+// attachmentRoot общий префикс ключей вложений всех организаций
+const attachmentRoot = "attachments/"
+
+// maxAttachmentNameLength ограничивает имя файла в ключе
+const maxAttachmentNameLength = 100
+
+type attachmentService struct {
+	store  storage.Storage
+	signer *signature.URLSigner
+	cfg    services.AttachmentConfig
+	meter  *metering.Meter
+	now    func() time.Time
+	logger *logger.Logger
+}
+
+// NewAttachmentService создает сервис вложений поверх хранилища файлов пользователей
+// (с проверкой антивирусом, если она настроена). Без cfg.URLSecret возвращает ошибку:
+// ссылки с пустым ключом подписи может подделать кто угодно.
+func NewAttachmentService(store storage.Storage, cfg services.AttachmentConfig, log *logrus.Logger) (services.AttachmentService, error) {
+	signer, err := signature.NewURLSigner(cfg.URLSecret)
+	if err != nil {
+		return nil, fmt.Errorf("attachment links: %w", err)
+	}
+
+	if cfg.LinkTTL <= 0 {
+		cfg.LinkTTL = services.DefaultAttachmentLinkTTL
+	}
+	if cfg.MaxLinkTTL <= 0 {
+		cfg.MaxLinkTTL = services.DefaultAttachmentMaxLinkTTL
+	}
+	if cfg.MaxSize <= 0 {
+		cfg.MaxSize = services.DefaultAttachmentMaxSize
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+	return &attachmentService{
+		store:  store,
+		signer: signer,
+		cfg:    cfg,
+		now:    time.Now,
+		logger: logger.New(log),
+	}, nil
+}
+
+// Upload сохраняет файл под ключом attachments/<организация>/<uuid>/<имя файла>
+func (s *attachmentService) Upload(ctx context.Context, orgID uuid.UUID, file services.AttachmentUpload) (*services.AttachmentLink, error) {
+	if file.Size > s.cfg.MaxSize {
+		return nil, apperror.ValidationError("file is too large")
+	}
+
+	key := services.AttachmentKeyPrefix(orgID) + uuid.NewString() + "/" + attachmentName(file.FileName)
+	contentType := file.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	if err := s.store.Put(ctx, key, file.Body, file.Size, storage.PutOptions{ContentType: contentType}); err != nil {
+		s.logger.Warn(ctx, "Attachment not stored", logrus.Fields{"org_id": orgID.String(), "error": err.Error()})
+		return nil, apperror.From(err, apperror.ErrExternalService, "failed to store attachment")
+	}
+
+	s.meter.Add(orgID, metering.MetricStorageBytes, file.Size)
+	s.logger.Info(ctx, "Attachment stored", logrus.Fields{"org_id": orgID.String(), "key": key, "size": file.Size})
+	return s.link(key, s.cfg.LinkTTL), nil
+}
+
+// CreateLink подписывает ссылку на существующее вложение организации
+func (s *attachmentService) CreateLink(ctx context.Context, orgID uuid.UUID, key string, ttl time.Duration) (*services.AttachmentLink, error) {
+	if !validAttachmentKey(key) || !strings.HasPrefix(key, services.AttachmentKeyPrefix(orgID)) {
+		return nil, apperror.NotFoundError("attachment")
+	}
+	if ttl <= 0 {
+		ttl = s.cfg.LinkTTL
+	}
+	if ttl > s.cfg.MaxLinkTTL {
+		return nil, apperror.ValidationError("link lifetime exceeds " + s.cfg.MaxLinkTTL.String())
+	}
+
+	if _, err := s.store.Stat(ctx, key); err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, apperror.NotFoundError("attachment")
+		}
+		return nil, apperror.From(err, apperror.ErrExternalService, "failed to read attachment")
+	}
+	return s.link(key, ttl), nil
+}
+
+// Open проверяет подпись и срок ссылки и открывает вложение
+func (s *attachmentService) Open(ctx context.Context, key, expires, sig string) (io.ReadCloser, *storage.ObjectInfo, error) {
+	if !validAttachmentKey(key) {
+		return nil, nil, apperror.NotFoundError("attachment")
+	}
+	if err := s.signer.Verify(services.FilesPath+key, expires, sig); err != nil {
+		if errors.Is(err, signature.ErrURLExpired) {
+			return nil, nil, apperror.New(apperror.ErrForbidden, "download link has expired")
+		}
+		return nil, nil, apperror.New(apperror.ErrForbidden, "invalid download link")
+	}
+
+	r, info, err := s.store.Get(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, apperror.NotFoundError("attachment")
+		}
+		s.logger.Error(ctx, "Failed to open attachment", err, logrus.Fields{"key": key})
+		return nil, nil, apperror.From(err, apperror.ErrExternalService, "failed to download attachment")
+	}
+	return r, info, nil
+}
+
+// SetMeter включает учет загруженных байт
+func (s *attachmentService) SetMeter(meter *metering.Meter) {
+	s.meter = meter
+}
+
+// link подписывает путь скачивания вложения на ttl
+func (s *attachmentService) link(key string, ttl time.Duration) *services.AttachmentLink {
+	// Срок округляется до секунды: в подписи он хранится Unix-временем
+	expires := s.now().Add(ttl).Truncate(time.Second)
+	return &services.AttachmentLink{
+		Key:       key,
+		URL:       s.cfg.BaseURL + s.signer.Sign(services.FilesPath+key, expires),
+		ExpiresAt: expires,
+	}
+}
+
+// validAttachmentKey допускает в ключе только безопасные для пути символы: ключ входит в подписанный URL
+func validAttachmentKey(key string) bool {
+	if !strings.HasPrefix(key, attachmentRoot) || strings.Contains(key, "..") || strings.Contains(key, "//") {
+		return false
+	}
+	for _, r := range key {
+		if !attachmentNameChar(r) && r != '/' {
+			return false
+		}
+	}
+	return true
+}
+
+// attachmentName приводит имя файла к символам [A-Za-z0-9._-], сохраняя расширение
+func attachmentName(name string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	var b strings.Builder
+	for _, r := range name {
+		if attachmentNameChar(r) {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	clean := strings.Trim(strings.ReplaceAll(b.String(), "..", "_"), ".")
+	if len(clean) > maxAttachmentNameLength {
+		ext := path.Ext(clean)
+		if len(ext) > 10 {
+			ext = ""
+		}
+		clean = clean[:maxAttachmentNameLength-len(ext)] + ext
+	}
+	if clean == "" || clean == "_" {
+		return "file"
+	}
+	return clean
+}
+
+func attachmentNameChar(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || r == '_' || r == '-'
+}
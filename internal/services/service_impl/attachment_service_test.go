@@ -0,0 +1,130 @@
+package service_impl
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/signature"
+	"github.com/rusgainew/tunduck-app/pkg/storage"
+)
+
+func newTestAttachmentService(t *testing.T) *attachmentService {
+	t.Helper()
+	store, err := storage.New(storage.Config{Backend: storage.BackendLocal, LocalPath: t.TempDir()})
+	require.NoError(t, err)
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	svc, err := NewAttachmentService(store, services.AttachmentConfig{
+		URLSecret: "attachment-secret",
+		BaseURL:   "https://api.tunduck.kg/",
+	}, log)
+	require.NoError(t, err)
+	return svc.(*attachmentService)
+}
+
+// linkQuery разбирает ключ и параметры подписи из ссылки
+func linkQuery(t *testing.T, link *services.AttachmentLink) (string, string, string) {
+	t.Helper()
+	u, err := url.Parse(link.URL)
+	require.NoError(t, err)
+	return strings.TrimPrefix(u.Path, services.FilesPath), u.Query().Get(signature.QueryExpires), u.Query().Get(signature.QuerySignature)
+}
+
+func TestAttachmentService_UploadAndOpen(t *testing.T) {
+	svc := newTestAttachmentService(t)
+	ctx := context.Background()
+	orgID := uuid.New()
+
+	link, err := svc.Upload(ctx, orgID, services.AttachmentUpload{
+		FileName: `C:\Users\Акт сверки 2026.pdf`, ContentType: "application/pdf", Size: 5, Body: strings.NewReader("%PDF-"),
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(link.Key, services.AttachmentKeyPrefix(orgID)))
+	assert.True(t, strings.HasSuffix(link.Key, "/___________2026.pdf"), link.Key)
+	assert.True(t, strings.HasPrefix(link.URL, "https://api.tunduck.kg/api/files/attachments/"))
+
+	key, expires, sig := linkQuery(t, link)
+	assert.Equal(t, link.Key, key)
+	r, info, err := svc.Open(ctx, key, expires, sig)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(r)
+	r.Close()
+	assert.Equal(t, "%PDF-", string(body))
+	assert.Equal(t, "application/pdf", info.ContentType)
+
+	_, _, err = svc.Open(ctx, key, expires, strings.Repeat("0", len(sig)))
+	assertAppErrorCode(t, err, apperror.ErrForbidden)
+
+	// Подпись не переносится на другой ключ
+	other := services.AttachmentKeyPrefix(uuid.New()) + "x/file.pdf"
+	_, _, err = svc.Open(ctx, other, expires, sig)
+	assertAppErrorCode(t, err, apperror.ErrForbidden)
+}
+
+func TestAttachmentService_LinkExpires(t *testing.T) {
+	svc := newTestAttachmentService(t)
+	ctx := context.Background()
+	orgID := uuid.New()
+	link, err := svc.Upload(ctx, orgID, services.AttachmentUpload{FileName: "a.txt", Size: 1, Body: strings.NewReader("a")})
+	require.NoError(t, err)
+
+	long, err := svc.CreateLink(ctx, orgID, link.Key, 48*time.Hour)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now().Add(48*time.Hour), long.ExpiresAt, 2*time.Second)
+
+	_, err = svc.CreateLink(ctx, orgID, link.Key, 30*24*time.Hour)
+	assertAppErrorCode(t, err, apperror.ErrValidation)
+
+	// Ключ другой организации не раскрывается
+	_, err = svc.CreateLink(ctx, uuid.New(), link.Key, 0)
+	assertAppErrorCode(t, err, apperror.ErrNotFound)
+
+	key, expires, sig := linkQuery(t, link)
+	past := time.Now().Add(-time.Minute)
+	expired := svc.signer.Sign(services.FilesPath+key, past)
+	u, _ := url.Parse(expired)
+	_, _, err = svc.Open(ctx, key, u.Query().Get(signature.QueryExpires), u.Query().Get(signature.QuerySignature))
+	assertAppErrorCode(t, err, apperror.ErrForbidden)
+
+	_, _, err = svc.Open(ctx, key, expires, sig)
+	assert.NoError(t, err)
+}
+
+func TestAttachmentService_RejectsLargeFilesAndUnsafeKeys(t *testing.T) {
+	svc := newTestAttachmentService(t)
+	ctx := context.Background()
+
+	_, err := svc.Upload(ctx, uuid.New(), services.AttachmentUpload{FileName: "big.bin", Size: services.DefaultAttachmentMaxSize + 1, Body: strings.NewReader("")})
+	assertAppErrorCode(t, err, apperror.ErrValidation)
+
+	for _, key := range []string{"exports/report.csv", "attachments/../exports/report.csv", "attachments/a b.pdf"} {
+		_, _, err := svc.Open(ctx, key, "0", "sig")
+		assertAppErrorCode(t, err, apperror.ErrNotFound)
+	}
+}
+
+func TestNewAttachmentService_RequiresURLSecret(t *testing.T) {
+	store, err := storage.NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	_, err = NewAttachmentService(store, services.AttachmentConfig{}, logrus.New())
+	assert.ErrorIs(t, err, signature.ErrEmptyURLSecret)
+}
+
+func assertAppErrorCode(t *testing.T, err error, code apperror.ErrorCode) {
+	t.Helper()
+	var appErr *apperror.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, code, appErr.Code)
+}
@@ -0,0 +1,101 @@
+package service_impl
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/audit"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+)
+
+// auditCSVHeader колонки выгрузки журнала в CSV
+var auditCSVHeader = []string{
+	"id", "created_at", "actor_id", "actor_name", "organization_id", "action", "entity_type", "entity_id",
+	"method", "path", "status_code", "ip", "user_agent", "request_id", "diff",
+}
+
+// auditService реализует AuditService
+type auditService struct {
+	repo     repository.AuditLogRepository
+	exporter audit.Recorder
+	logger   *logger.Logger
+}
+
+// NewAuditService создает сервис журнала аудита
+func NewAuditService(repo repository.AuditLogRepository, log *logrus.Logger) services.AuditService {
+	return &auditService{
+		repo:   repo,
+		logger: logger.New(log),
+	}
+}
+
+// Record сохраняет запись журнала и передает ее экспортеру. Ошибка экспорта только логируется:
+// запись уже сохранена, а потерянные SIEM события учитываются в метриках экспортера
+func (s *auditService) Record(ctx context.Context, log *entity.AuditLog) error {
+	if log.CreatedAt.IsZero() {
+		log.CreatedAt = time.Now()
+	}
+	if err := s.repo.Create(ctx, log); err != nil {
+		return err
+	}
+	if s.exporter != nil {
+		if err := s.exporter.Record(ctx, log); err != nil {
+			s.logger.Warn(ctx, "Failed to export audit log", logrus.Fields{"audit_id": log.ID.String(), "error": err.Error()})
+		}
+	}
+	return nil
+}
+
+// SetExporter подключает экспорт записей во внешний получатель
+func (s *auditService) SetExporter(exporter audit.Recorder) {
+	s.exporter = exporter
+}
+
+// ListLogs возвращает страницу журнала
+func (s *auditService) ListLogs(ctx context.Context, params pagination.PaginationParams, filter repository.AuditLogFilter) ([]entity.AuditLog, int64, error) {
+	return s.repo.List(ctx, params, filter)
+}
+
+// ExportLogs выгружает журнал потоком
+func (s *auditService) ExportLogs(ctx context.Context, filter repository.AuditLogFilter, format string, w io.Writer) error {
+	rw, err := newRecordWriter(format, w, auditCSVHeader)
+	if err != nil {
+		return err
+	}
+	err = s.repo.Each(ctx, filter, func(log *entity.AuditLog) error {
+		return rw.Write(auditCSVRow(log), log)
+	})
+	if err != nil {
+		return err
+	}
+	return rw.Close()
+}
+
+// auditCSVRow колонки записи журнала в порядке auditCSVHeader
+func auditCSVRow(log *entity.AuditLog) []string {
+	return []string{
+		log.ID.String(),
+		log.CreatedAt.UTC().Format(time.RFC3339),
+		log.ActorID,
+		log.ActorName,
+		log.OrganizationID,
+		log.Action,
+		log.EntityType,
+		log.EntityID,
+		log.Method,
+		log.Path,
+		strconv.Itoa(log.StatusCode),
+		log.IP,
+		log.UserAgent,
+		log.RequestID,
+		string(log.Diff),
+	}
+}
@@ -0,0 +1,294 @@
+package service_impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/bankstatement"
+	"github.com/rusgainew/tunduck-app/pkg/cache"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+)
+
+// statementCandidateLimit сколько документов-кандидатов рассматривается для одной строки
+const statementCandidateLimit = 20
+
+// minReferenceLength короткие номера («1», «А5») не сравниваются со ссылкой платежа
+const minReferenceLength = 3
+
+type bankStatementService struct {
+	repo         repository.EsfDocumentRepository
+	cacheManager cache.CacheManager
+	logger       *logger.Logger
+}
+
+// NewBankStatementService создает сервис сверки выписок; cacheManager может быть nil
+func NewBankStatementService(repo repository.EsfDocumentRepository, cacheManager cache.CacheManager, log *logrus.Logger) services.BankStatementService {
+	return &bankStatementService{
+		repo:         repo,
+		cacheManager: cacheManager,
+		logger:       logger.New(log),
+	}
+}
+
+// Import разбирает выписку, сохраняет ее строки и сопоставляет поступления с документами
+func (s *bankStatementService) Import(ctx context.Context, orgID uuid.UUID, upload services.BankStatementUpload) (*entity.BankStatement, error) {
+	parsed, err := bankstatement.Parse(upload.Format, upload.FileName, upload.Body)
+	if err != nil {
+		if errors.Is(err, bankstatement.ErrUnknownFormat) {
+			return nil, apperror.ValidationError("unknown bank statement format, expected mt940 or csv")
+		}
+		return nil, apperror.ValidationError("invalid bank statement: {error}").WithParams(map[string]interface{}{"error": err.Error()})
+	}
+
+	statement := &entity.BankStatement{
+		FileName:   upload.FileName,
+		Format:     parsed.Format,
+		Account:    parsed.Account,
+		Currency:   parsed.Currency,
+		LineCount:  len(parsed.Transactions),
+		UploadedBy: upload.UploadedBy,
+	}
+	for i, tx := range parsed.Transactions {
+		statement.Lines = append(statement.Lines, entity.BankStatementLine{
+			LineNo:           i + 1,
+			ValueDate:        tx.Date,
+			Amount:           tx.Amount,
+			Currency:         truncate(tx.Currency, 3),
+			CounterpartyTin:  truncate(tx.CounterpartyTin, 14),
+			CounterpartyName: truncate(tx.CounterpartyName, 255),
+			Reference:        truncate(tx.Reference, 100),
+			Description:      tx.Description,
+			Status:           entity.StatementLineUnmatched,
+		})
+	}
+	if err := s.repo.CreateBankStatement(ctx, orgID, statement); err != nil {
+		return nil, apperror.DatabaseErrorFrom("saving bank statement", err)
+	}
+
+	for i := range statement.Lines {
+		line := &statement.Lines[i]
+		doc, err := s.autoMatchDocument(ctx, orgID, line)
+		if err != nil {
+			s.logger.Warn(ctx, "Failed to find documents for statement line", logrus.Fields{"statement_id": statement.ID.String(), "line": line.LineNo, "error": err.Error()})
+			continue
+		}
+		if doc == nil {
+			continue
+		}
+		if err := s.match(ctx, orgID, statement, line, doc.ID, entity.StatementMatchedAuto, upload.UploadedBy); err != nil {
+			s.logger.Warn(ctx, "Failed to match statement line", logrus.Fields{"statement_id": statement.ID.String(), "line": line.LineNo, "error": err.Error()})
+		}
+	}
+
+	s.logger.Info(ctx, "Bank statement imported", logrus.Fields{
+		"org_id":       orgID.String(),
+		"statement_id": statement.ID.String(),
+		"lines":        statement.LineCount,
+		"matched":      statement.MatchedCount,
+	})
+	return statement, nil
+}
+
+// autoMatchDocument выбирает документ для поступления: ИНН и остаток должны совпасть,
+// а если таких документов несколько — ссылка платежа должна указывать ровно на один
+func (s *bankStatementService) autoMatchDocument(ctx context.Context, orgID uuid.UUID, line *entity.BankStatementLine) (*entity.EsfDocument, error) {
+	if line.Amount <= 0 || line.CounterpartyTin == "" {
+		return nil, nil
+	}
+	docs, err := s.repo.FindPaymentCandidates(ctx, orgID, line.CounterpartyTin, line.Amount, statementCandidateLimit)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 1 {
+		return &docs[0], nil
+	}
+
+	var found *entity.EsfDocument
+	for i := range docs {
+		if !referenceMatches(&docs[i], line) {
+			continue
+		}
+		if found != nil {
+			return nil, nil
+		}
+		found = &docs[i]
+	}
+	return found, nil
+}
+
+// referenceMatches проверяет, упомянут ли документ в ссылке или назначении платежа
+func referenceMatches(doc *entity.EsfDocument, line *entity.BankStatementLine) bool {
+	text := strings.ToUpper(line.Reference + " " + line.Description)
+	for _, key := range []string{doc.SupplyContractNumber, doc.OwnedCrmReceiptCode, doc.ID.String()} {
+		key = strings.ToUpper(strings.TrimSpace(key))
+		if len(key) >= minReferenceLength && strings.Contains(text, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// match вносит оплату по строке выписки и обновляет строку и счетчик выписки в памяти
+func (s *bankStatementService) match(ctx context.Context, orgID uuid.UUID, statement *entity.BankStatement, line *entity.BankStatementLine, documentID uuid.UUID, matchedBy, createdBy string) error {
+	payment := &entity.Payment{
+		DocumentID: documentID,
+		PaidAt:     line.ValueDate,
+		Amount:     math.Abs(line.Amount),
+		Method:     entity.PaymentMethodBankTransfer,
+		Reference:  line.Reference,
+		Comment:    fmt.Sprintf("Bank statement %s, line %d", statement.FileName, line.LineNo),
+		CreatedBy:  createdBy,
+	}
+	if err := s.repo.MatchStatementLine(ctx, orgID, statement.ID, line.ID, payment, matchedBy); err != nil {
+		return err
+	}
+	if s.cacheManager != nil {
+		_ = s.cacheManager.Document().Delete(ctx, documentCacheKey(orgID, documentID))
+	}
+
+	now := time.Now()
+	line.Status = entity.StatementLineMatched
+	line.DocumentID = &documentID
+	line.PaymentID = &payment.ID
+	line.MatchedBy = matchedBy
+	line.MatchedAt = &now
+	statement.MatchedCount++
+	return nil
+}
+
+// List возвращает выписки организации
+func (s *bankStatementService) List(ctx context.Context, orgID uuid.UUID) ([]entity.BankStatement, error) {
+	statements, err := s.repo.ListBankStatements(ctx, orgID)
+	if err != nil {
+		return nil, apperror.DatabaseErrorFrom("fetching bank statements", err)
+	}
+	if statements == nil {
+		statements = []entity.BankStatement{}
+	}
+	return statements, nil
+}
+
+// Get возвращает выписку со строками
+func (s *bankStatementService) Get(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*entity.BankStatement, error) {
+	statement, err := s.repo.GetBankStatement(ctx, orgID, id)
+	if err != nil {
+		return nil, apperror.DatabaseErrorFrom("fetching bank statement", err)
+	}
+	return statement, nil
+}
+
+// Unmatched предлагает документы для несопоставленных строк: неоплаченные документы того же ИНН,
+// а для строк без ИНН — документы с остатком, равным сумме строки. Совпадения суммы и ссылки идут первыми.
+func (s *bankStatementService) Unmatched(ctx context.Context, orgID uuid.UUID, id uuid.UUID) ([]services.UnmatchedStatementLine, error) {
+	statement, err := s.Get(ctx, orgID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := []services.UnmatchedStatementLine{}
+	for i := range statement.Lines {
+		line := &statement.Lines[i]
+		if line.Status != entity.StatementLineUnmatched {
+			continue
+		}
+		amount := 0.0
+		if line.CounterpartyTin == "" {
+			amount = math.Abs(line.Amount)
+		}
+		docs, err := s.repo.FindPaymentCandidates(ctx, orgID, line.CounterpartyTin, amount, statementCandidateLimit)
+		if err != nil {
+			return nil, apperror.DatabaseErrorFrom("fetching payment candidates", err)
+		}
+		result = append(result, services.UnmatchedStatementLine{BankStatementLine: *line, Candidates: paymentCandidates(docs, line)})
+	}
+	return result, nil
+}
+
+func paymentCandidates(docs []entity.EsfDocument, line *entity.BankStatementLine) []services.PaymentCandidate {
+	candidates := make([]services.PaymentCandidate, len(docs))
+	for i := range docs {
+		doc := &docs[i]
+		candidates[i] = services.PaymentCandidate{
+			DocumentID:           doc.ID,
+			ContractorTin:        doc.ContractorTin,
+			DeliveryDate:         doc.DeliveryDate,
+			SupplyContractNumber: doc.SupplyContractNumber,
+			AmountDue:            doc.AmountDue(),
+			RemainingAmount:      doc.RemainingAmount(),
+			ExactAmount:          math.Abs(doc.RemainingAmount()-math.Abs(line.Amount)) < entity.PaymentTolerance,
+			ReferenceMatch:       referenceMatches(doc, line),
+		}
+	}
+	score := func(c services.PaymentCandidate) int {
+		n := 0
+		if c.ExactAmount {
+			n += 2
+		}
+		if c.ReferenceMatch {
+			n++
+		}
+		return n
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return score(candidates[i]) > score(candidates[j]) })
+	return candidates
+}
+
+// MatchLine сопоставляет строку с документом по выбору пользователя. Сумма оплаты — сумма строки,
+// даже если она не равна остатку документа (частичная оплата или переплата)
+func (s *bankStatementService) MatchLine(ctx context.Context, orgID uuid.UUID, statementID uuid.UUID, lineID uuid.UUID, documentID uuid.UUID, matchedBy string) (*entity.BankStatementLine, error) {
+	statement, err := s.Get(ctx, orgID, statementID)
+	if err != nil {
+		return nil, err
+	}
+	var line *entity.BankStatementLine
+	for i := range statement.Lines {
+		if statement.Lines[i].ID == lineID {
+			line = &statement.Lines[i]
+			break
+		}
+	}
+	if line == nil {
+		return nil, apperror.NotFoundError("statement line")
+	}
+	if line.Status == entity.StatementLineMatched {
+		return nil, apperror.ConflictError("statement line is already matched")
+	}
+	if line.Amount == 0 {
+		return nil, apperror.ValidationError("statement line has zero amount")
+	}
+	if _, err := s.repo.GetDocumentByID(ctx, orgID, documentID); err != nil {
+		return nil, apperror.DatabaseErrorFrom("fetching document", err)
+	}
+
+	if err := s.match(ctx, orgID, statement, line, documentID, matchedBy, matchedBy); err != nil {
+		return nil, apperror.DatabaseErrorFrom("matching statement line", err)
+	}
+	s.logger.Info(ctx, "Statement line matched", logrus.Fields{
+		"org_id":       orgID.String(),
+		"statement_id": statementID.String(),
+		"line_id":      lineID.String(),
+		"doc_id":       documentID.String(),
+	})
+	return line, nil
+}
+
+// truncate обрезает строку до n символов под размер колонки
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n])
+}
@@ -0,0 +1,187 @@
+package service_impl
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// memoryStatementRepository хранит документы и выписки одной организации в памяти
+type memoryStatementRepository struct {
+	repository.EsfDocumentRepository
+	docs       []entity.EsfDocument
+	statements map[uuid.UUID]*entity.BankStatement
+	payments   []entity.Payment
+}
+
+func (m *memoryStatementRepository) CreateBankStatement(ctx context.Context, orgID uuid.UUID, statement *entity.BankStatement) error {
+	statement.ID = uuid.New()
+	for i := range statement.Lines {
+		statement.Lines[i].ID = uuid.New()
+		statement.Lines[i].StatementID = statement.ID
+	}
+	copied := *statement
+	copied.Lines = append([]entity.BankStatementLine(nil), statement.Lines...)
+	m.statements[statement.ID] = &copied
+	return nil
+}
+
+func (m *memoryStatementRepository) GetBankStatement(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*entity.BankStatement, error) {
+	st, ok := m.statements[id]
+	if !ok {
+		return nil, apperror.NotFoundError("bank statement")
+	}
+	copied := *st
+	copied.Lines = append([]entity.BankStatementLine(nil), st.Lines...)
+	return &copied, nil
+}
+
+func (m *memoryStatementRepository) GetDocumentByID(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*entity.EsfDocument, error) {
+	for i := range m.docs {
+		if m.docs[i].ID == id {
+			return &m.docs[i], nil
+		}
+	}
+	return nil, apperror.New(apperror.ErrDocumentNotFound, "document not found")
+}
+
+func (m *memoryStatementRepository) FindPaymentCandidates(ctx context.Context, orgID uuid.UUID, tin string, amount float64, limit int) ([]entity.EsfDocument, error) {
+	var docs []entity.EsfDocument
+	for _, doc := range m.docs {
+		remaining := doc.RemainingAmount()
+		if remaining < entity.PaymentTolerance ||
+			(tin != "" && doc.ContractorTin != tin) ||
+			(amount > 0 && math.Abs(remaining-amount) >= entity.PaymentTolerance) {
+			continue
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+func (m *memoryStatementRepository) MatchStatementLine(ctx context.Context, orgID uuid.UUID, statementID uuid.UUID, lineID uuid.UUID, payment *entity.Payment, matchedBy string) error {
+	st := m.statements[statementID]
+	for i := range st.Lines {
+		line := &st.Lines[i]
+		if line.ID != lineID {
+			continue
+		}
+		if line.Status == entity.StatementLineMatched {
+			return apperror.ConflictError("statement line is already matched")
+		}
+		doc, err := m.GetDocumentByID(ctx, orgID, payment.DocumentID)
+		if err != nil {
+			return err
+		}
+		payment.ID = uuid.New()
+		doc.PaidAmount += payment.Amount
+		m.payments = append(m.payments, *payment)
+		line.Status, line.DocumentID, line.PaymentID, line.MatchedBy = entity.StatementLineMatched, &payment.DocumentID, &payment.ID, matchedBy
+		st.MatchedCount++
+		return nil
+	}
+	return apperror.NotFoundError("statement line")
+}
+
+func statementDocument(tin, contract string, total float64) entity.EsfDocument {
+	return entity.EsfDocument{ID: uuid.New(), ContractorTin: tin, SupplyContractNumber: contract, TotalCurrencyValue: total}
+}
+
+func TestBankStatementService_ImportAutoMatches(t *testing.T) {
+	repo := &memoryStatementRepository{
+		docs: []entity.EsfDocument{
+			statementDocument("01234567890123", "D-15", 1500),
+			// Два документа одного контрагента на одинаковую сумму различаются номером договора
+			statementDocument("20101199000123", "D-20", 700),
+			statementDocument("20101199000123", "D-21", 700),
+			statementDocument("30101199000555", "D-30", 900),
+			statementDocument("30101199000555", "D-31", 900),
+		},
+		statements: map[uuid.UUID]*entity.BankStatement{},
+	}
+	svc := NewBankStatementService(repo, nil, logrus.New())
+	orgID := uuid.New()
+
+	csv := "date;amount;tin;reference;description\n" +
+		"2026-02-10;1500,00;01234567890123;PP-118;Invoice payment\n" +
+		"2026-02-11;700,00;20101199000123;PP-119;Payment under contract d-21\n" +
+		"2026-02-12;900,00;30101199000555;PP-120;No contract number\n" +
+		"2026-02-13;-50,00;;;Bank fee\n" +
+		"2026-02-14;333,00;01234567890123;PP-121;Unknown amount\n"
+	st, err := svc.Import(context.Background(), orgID, services.BankStatementUpload{
+		FileName: "feb.csv", UploadedBy: "user-1", Body: strings.NewReader(csv),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "csv", st.Format)
+	assert.Equal(t, 5, st.LineCount)
+	assert.Equal(t, 2, st.MatchedCount)
+
+	assert.Equal(t, entity.StatementLineMatched, st.Lines[0].Status)
+	assert.Equal(t, repo.docs[0].ID, *st.Lines[0].DocumentID)
+	assert.Equal(t, entity.StatementMatchedAuto, st.Lines[0].MatchedBy)
+	assert.Equal(t, repo.docs[2].ID, *st.Lines[1].DocumentID, "reference picks one of the equal documents")
+	assert.Equal(t, entity.StatementLineUnmatched, st.Lines[2].Status, "ambiguous without reference")
+	assert.Equal(t, entity.StatementLineUnmatched, st.Lines[3].Status, "debits are not matched automatically")
+	assert.Equal(t, entity.StatementLineUnmatched, st.Lines[4].Status)
+
+	require.Len(t, repo.payments, 2)
+	assert.Equal(t, entity.PaymentMethodBankTransfer, repo.payments[0].Method)
+	assert.Equal(t, "PP-118", repo.payments[0].Reference)
+	assert.Equal(t, time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC), repo.payments[0].PaidAt)
+	assert.Equal(t, 1500.0, repo.docs[0].PaidAmount)
+
+	_, err = svc.Import(context.Background(), orgID, services.BankStatementUpload{FileName: "x.bin", Body: strings.NewReader("??")})
+	assert.Equal(t, apperror.ErrValidation, err.(*apperror.AppError).Code)
+}
+
+func TestBankStatementService_UnmatchedAndManualMatch(t *testing.T) {
+	exact := statementDocument("30101199000555", "D-30", 900)
+	other := statementDocument("30101199000555", "D-31", 1200)
+	repo := &memoryStatementRepository{docs: []entity.EsfDocument{other, exact}, statements: map[uuid.UUID]*entity.BankStatement{}}
+	svc := NewBankStatementService(repo, nil, logrus.New())
+	orgID := uuid.New()
+	ctx := context.Background()
+
+	st, err := svc.Import(ctx, orgID, services.BankStatementUpload{
+		FileName: "feb.csv", UploadedBy: "user-1",
+		Body: strings.NewReader("date,amount,tin\n2026-02-12,900.00,30101199000555\n2026-02-12,900.00,30101199000555\n"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, st.MatchedCount, "the second line no longer has a document with this remaining amount")
+
+	// Остаток документа exact уже оплачен первой строкой, поэтому кандидат остается один
+	unmatched, err := svc.Unmatched(ctx, orgID, st.ID)
+	require.NoError(t, err)
+	require.Len(t, unmatched, 1)
+	lineID := unmatched[0].ID
+	require.Len(t, unmatched[0].Candidates, 1)
+	assert.Equal(t, repo.docs[0].ID, unmatched[0].Candidates[0].DocumentID)
+	assert.False(t, unmatched[0].Candidates[0].ExactAmount)
+
+	line, err := svc.MatchLine(ctx, orgID, st.ID, lineID, repo.docs[0].ID, "user-2")
+	require.NoError(t, err)
+	assert.Equal(t, entity.StatementLineMatched, line.Status)
+	assert.Equal(t, "user-2", line.MatchedBy)
+	assert.Equal(t, 900.0, repo.docs[0].PaidAmount, "partial payment of the chosen document")
+
+	_, err = svc.MatchLine(ctx, orgID, st.ID, lineID, repo.docs[0].ID, "user-2")
+	assert.Equal(t, apperror.ErrConflict, err.(*apperror.AppError).Code)
+	_, err = svc.MatchLine(ctx, orgID, st.ID, uuid.New(), repo.docs[0].ID, "user-2")
+	assert.Equal(t, apperror.ErrNotFound, err.(*apperror.AppError).Code)
+
+	unmatched, err = svc.Unmatched(ctx, orgID, st.ID)
+	require.NoError(t, err)
+	assert.Empty(t, unmatched)
+}
@@ -0,0 +1,103 @@
+package service_impl
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+)
+
+// callbackStaleAfter через сколько событие, зависшее в обработке (например, после падения инстанса),
+// можно захватить повторно
+const callbackStaleAfter = 5 * time.Minute
+
+// maxCallbackEventID ограничение длины ID события (колонка event_id)
+const maxCallbackEventID = 128
+
+// callbackHandler применяет данные события
+type callbackHandler func(ctx context.Context, data json.RawMessage) error
+
+// callbackService реализует CallbackService
+type callbackService struct {
+	repo     repository.InboundEventRepository
+	docs     services.EsfDocumentService
+	handlers map[string]callbackHandler
+	logger   *logger.Logger
+}
+
+// NewCallbackService создает сервис обратных вызовов с обработчиками событий шлюза ЭСФ
+func NewCallbackService(repo repository.InboundEventRepository, docs services.EsfDocumentService, log *logrus.Logger) services.CallbackService {
+	s := &callbackService{
+		repo:   repo,
+		docs:   docs,
+		logger: logger.New(log),
+	}
+	s.handlers = map[string]callbackHandler{
+		services.CallbackInvoiceStatusChanged: s.handleInvoiceStatus,
+	}
+	return s
+}
+
+// Consume захватывает событие по (source, ID) и применяет его обработчик
+func (s *callbackService) Consume(ctx context.Context, source string, event services.CallbackEvent) (bool, error) {
+	if event.ID == "" || event.Type == "" || len(event.ID) > maxCallbackEventID {
+		return false, apperror.ValidationError("invalid event")
+	}
+	handler, ok := s.handlers[event.Type]
+	if !ok {
+		return false, apperror.ValidationError("unsupported event type")
+	}
+
+	record := &entity.InboundEvent{
+		Source:  source,
+		EventID: event.ID,
+		Type:    event.Type,
+		Payload: event.Data,
+	}
+	claimed, err := s.repo.Claim(ctx, record, callbackStaleAfter)
+	if err != nil {
+		return false, err
+	}
+	fields := logrus.Fields{"source": source, "event_id": event.ID, "type": event.Type}
+	if !claimed {
+		s.logger.Info(ctx, "Duplicate callback ignored", fields)
+		return true, nil
+	}
+
+	if err := handler(ctx, event.Data); err != nil {
+		s.logger.Warn(ctx, "Callback processing failed", logrus.Fields{
+			"source": source, "event_id": event.ID, "type": event.Type, "attempt": record.Attempts, "error": err.Error(),
+		})
+		if markErr := s.repo.MarkFailed(ctx, record.ID, err.Error()); markErr != nil {
+			s.logger.Error(ctx, "Failed to mark callback as failed", markErr, fields)
+		}
+		return false, err
+	}
+
+	if err := s.repo.MarkProcessed(ctx, record.ID); err != nil {
+		// Изменение уже применено; при повторной доставке событие будет захвачено только после callbackStaleAfter
+		s.logger.Error(ctx, "Failed to mark callback as processed", err, fields)
+	}
+	s.logger.Info(ctx, "Callback processed", fields)
+	return false, nil
+}
+
+// handleInvoiceStatus сохраняет статус документа в ЭСФ
+func (s *callbackService) handleInvoiceStatus(ctx context.Context, data json.RawMessage) error {
+	var payload services.InvoiceStatusData
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return apperror.ValidationError("invalid event")
+	}
+	if payload.OrganizationID == uuid.Nil || payload.DocumentID == uuid.Nil {
+		return apperror.ValidationError("invalid event")
+	}
+	return s.docs.UpdateDocumentStatus(ctx, payload.OrganizationID, payload.DocumentID, payload.Status)
+}
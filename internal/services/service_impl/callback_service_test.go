@@ -0,0 +1,149 @@
+package service_impl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// memoryInboundEventRepository повторяет семантику Claim репозитория Postgres
+type memoryInboundEventRepository struct {
+	mu     sync.Mutex
+	events map[string]*entity.InboundEvent
+}
+
+func newMemoryInboundEventRepository() *memoryInboundEventRepository {
+	return &memoryInboundEventRepository{events: map[string]*entity.InboundEvent{}}
+}
+
+func (r *memoryInboundEventRepository) Claim(ctx context.Context, event *entity.InboundEvent, staleAfter time.Duration) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := event.Source + "/" + event.EventID
+	existing, ok := r.events[key]
+	if !ok {
+		event.ID, event.Status, event.Attempts, event.UpdatedAt = uuid.New(), entity.InboundStatusProcessing, 1, time.Now()
+		r.events[key] = event
+		return true, nil
+	}
+	stale := existing.Status == entity.InboundStatusProcessing && existing.UpdatedAt.Before(time.Now().Add(-staleAfter))
+	if existing.Status != entity.InboundStatusFailed && !stale {
+		return false, nil
+	}
+	existing.Status, existing.UpdatedAt = entity.InboundStatusProcessing, time.Now()
+	existing.Attempts++
+	event.ID, event.Attempts = existing.ID, existing.Attempts
+	return true, nil
+}
+
+func (r *memoryInboundEventRepository) set(id uuid.UUID, status, reason string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, event := range r.events {
+		if event.ID == id {
+			event.Status, event.Error = status, reason
+		}
+	}
+}
+
+func (r *memoryInboundEventRepository) MarkProcessed(ctx context.Context, id uuid.UUID) error {
+	r.set(id, entity.InboundStatusProcessed, "")
+	return nil
+}
+
+func (r *memoryInboundEventRepository) MarkFailed(ctx context.Context, id uuid.UUID, reason string) error {
+	r.set(id, entity.InboundStatusFailed, reason)
+	return nil
+}
+
+// statusRecordingDocumentService считает примененные изменения статуса
+type statusRecordingDocumentService struct {
+	services.EsfDocumentService
+	err     error
+	applied []string
+}
+
+func (s *statusRecordingDocumentService) UpdateDocumentStatus(ctx context.Context, orgID uuid.UUID, id uuid.UUID, status string) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.applied = append(s.applied, status)
+	return nil
+}
+
+func statusEvent(t *testing.T, id, status string) services.CallbackEvent {
+	data, err := json.Marshal(services.InvoiceStatusData{
+		OrganizationID: uuid.New(),
+		DocumentID:     uuid.New(),
+		Status:         status,
+	})
+	require.NoError(t, err)
+	return services.CallbackEvent{ID: id, Type: services.CallbackInvoiceStatusChanged, Data: data}
+}
+
+func TestCallbackService_DeduplicatesRedelivery(t *testing.T) {
+	docs := &statusRecordingDocumentService{}
+	svc := NewCallbackService(newMemoryInboundEventRepository(), docs, logrus.New())
+	ctx := context.Background()
+
+	duplicate, err := svc.Consume(ctx, "gateway", statusEvent(t, "evt-1", entity.EsfStatusRegistered))
+	require.NoError(t, err)
+	assert.False(t, duplicate)
+
+	duplicate, err = svc.Consume(ctx, "gateway", statusEvent(t, "evt-1", entity.EsfStatusRegistered))
+	require.NoError(t, err)
+	assert.True(t, duplicate)
+
+	// Тот же ID от другого источника — другое событие
+	duplicate, err = svc.Consume(ctx, "other", statusEvent(t, "evt-1", entity.EsfStatusRejected))
+	require.NoError(t, err)
+	assert.False(t, duplicate)
+
+	assert.Equal(t, []string{entity.EsfStatusRegistered, entity.EsfStatusRejected}, docs.applied)
+}
+
+func TestCallbackService_RetriesFailedEvent(t *testing.T) {
+	docs := &statusRecordingDocumentService{err: errors.New("tenant database unavailable")}
+	repo := newMemoryInboundEventRepository()
+	svc := NewCallbackService(repo, docs, logrus.New())
+	ctx := context.Background()
+
+	_, err := svc.Consume(ctx, "gateway", statusEvent(t, "evt-2", entity.EsfStatusCancelled))
+	require.Error(t, err)
+	assert.Equal(t, entity.InboundStatusFailed, repo.events["gateway/evt-2"].Status)
+
+	docs.err = nil
+	duplicate, err := svc.Consume(ctx, "gateway", statusEvent(t, "evt-2", entity.EsfStatusCancelled))
+	require.NoError(t, err)
+	assert.False(t, duplicate)
+	assert.Equal(t, []string{entity.EsfStatusCancelled}, docs.applied)
+	assert.Equal(t, 2, repo.events["gateway/evt-2"].Attempts)
+	assert.Equal(t, entity.InboundStatusProcessed, repo.events["gateway/evt-2"].Status)
+}
+
+func TestCallbackService_Validation(t *testing.T) {
+	svc := NewCallbackService(newMemoryInboundEventRepository(), &statusRecordingDocumentService{}, logrus.New())
+	ctx := context.Background()
+
+	_, err := svc.Consume(ctx, "gateway", services.CallbackEvent{Type: services.CallbackInvoiceStatusChanged})
+	assertErrorCode(t, err, apperror.ErrValidation)
+
+	_, err = svc.Consume(ctx, "gateway", services.CallbackEvent{ID: "evt-3", Type: "invoice.unknown"})
+	assertErrorCode(t, err, apperror.ErrValidation)
+
+	_, err = svc.Consume(ctx, "gateway", services.CallbackEvent{ID: "evt-4", Type: services.CallbackInvoiceStatusChanged, Data: json.RawMessage(`{}`)})
+	assertErrorCode(t, err, apperror.ErrValidation)
+}
@@ -0,0 +1,146 @@
+package service_impl
+
+import (
+	"context"
+	"io"
+
+	"github.com/google/uuid"
+	models "github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/cache"
+	"github.com/rusgainew/tunduck-app/pkg/canary"
+	"github.com/rusgainew/tunduck-app/pkg/esfgateway"
+	"github.com/rusgainew/tunduck-app/pkg/metering"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+	"github.com/rusgainew/tunduck-app/pkg/search"
+)
+
+// canaryEsfDocumentService передает вызовы основной или канареечной реализации сервиса документов
+// по варианту запроса (canary.FromContext); вызовы вне выбранных маршрутов идут в основную
+type canaryEsfDocumentService struct {
+	primary services.EsfDocumentService
+	canary  services.EsfDocumentService
+}
+
+// NewCanaryEsfDocumentService создает сервис документов для канареечного выката. Обе реализации
+// работают с одними данными, поэтому организация может переходить между ними без миграции.
+func NewCanaryEsfDocumentService(primary, canaryService services.EsfDocumentService) services.EsfDocumentService {
+	return &canaryEsfDocumentService{primary: primary, canary: canaryService}
+}
+
+func (s *canaryEsfDocumentService) pick(ctx context.Context) services.EsfDocumentService {
+	if canary.FromContext(ctx) == canary.VariantCanary {
+		return s.canary
+	}
+	return s.primary
+}
+
+func (s *canaryEsfDocumentService) GetAllDocuments(ctx context.Context, orgID uuid.UUID) ([]models.EsfCreateDocumentRequest, error) {
+	return s.pick(ctx).GetAllDocuments(ctx, orgID)
+}
+
+func (s *canaryEsfDocumentService) GetDocumentByID(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*models.EsfCreateDocumentRequest, error) {
+	return s.pick(ctx).GetDocumentByID(ctx, orgID, id)
+}
+
+func (s *canaryEsfDocumentService) CreateDocument(ctx context.Context, orgID uuid.UUID, doc *models.EsfCreateDocumentRequest) (*models.EsfCreateDocumentResponse, error) {
+	return s.pick(ctx).CreateDocument(ctx, orgID, doc)
+}
+
+func (s *canaryEsfDocumentService) UpdateDocument(ctx context.Context, orgID uuid.UUID, doc *models.EsfEditDocumentRequest) error {
+	return s.pick(ctx).UpdateDocument(ctx, orgID, doc)
+}
+
+func (s *canaryEsfDocumentService) DeleteDocument(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error {
+	return s.pick(ctx).DeleteDocument(ctx, orgID, id)
+}
+
+func (s *canaryEsfDocumentService) RestoreDocument(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error {
+	return s.pick(ctx).RestoreDocument(ctx, orgID, id)
+}
+
+func (s *canaryEsfDocumentService) PurgeDocument(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error {
+	return s.pick(ctx).PurgeDocument(ctx, orgID, id)
+}
+
+func (s *canaryEsfDocumentService) UpdateDocumentStatus(ctx context.Context, orgID uuid.UUID, id uuid.UUID, status string) error {
+	return s.pick(ctx).UpdateDocumentStatus(ctx, orgID, id, status)
+}
+
+func (s *canaryEsfDocumentService) DocumentWarnings(ctx context.Context, orgID uuid.UUID, id uuid.UUID) ([]models.DocumentWarning, error) {
+	return s.pick(ctx).DocumentWarnings(ctx, orgID, id)
+}
+
+func (s *canaryEsfDocumentService) WriteDocumentPDF(ctx context.Context, orgID uuid.UUID, id uuid.UUID, w io.Writer) error {
+	return s.pick(ctx).WriteDocumentPDF(ctx, orgID, id, w)
+}
+
+func (s *canaryEsfDocumentService) ListPayments(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*services.DocumentPayments, error) {
+	return s.pick(ctx).ListPayments(ctx, orgID, id)
+}
+
+func (s *canaryEsfDocumentService) RecordPayment(ctx context.Context, orgID uuid.UUID, id uuid.UUID, createdBy string, req *models.PaymentRequest) (*services.DocumentPayments, error) {
+	return s.pick(ctx).RecordPayment(ctx, orgID, id, createdBy, req)
+}
+
+func (s *canaryEsfDocumentService) DeletePayment(ctx context.Context, orgID uuid.UUID, id uuid.UUID, paymentID uuid.UUID) (*services.DocumentPayments, error) {
+	return s.pick(ctx).DeletePayment(ctx, orgID, id, paymentID)
+}
+
+func (s *canaryEsfDocumentService) GetAllDocumentsPaginated(ctx context.Context, orgID uuid.UUID, params pagination.PaginationParams, filters pagination.DocumentFilterParams) ([]models.EsfCreateDocumentRequest, int64, error) {
+	return s.pick(ctx).GetAllDocumentsPaginated(ctx, orgID, params, filters)
+}
+
+func (s *canaryEsfDocumentService) GetAllDocumentsCursor(ctx context.Context, orgID uuid.UUID, params pagination.CursorParams, filters pagination.DocumentFilterParams) ([]models.EsfCreateDocumentRequest, pagination.CursorInfo, error) {
+	return s.pick(ctx).GetAllDocumentsCursor(ctx, orgID, params, filters)
+}
+
+func (s *canaryEsfDocumentService) SearchDocuments(ctx context.Context, orgID uuid.UUID, text string, params pagination.PaginationParams) ([]models.EsfCreateDocumentRequest, int64, error) {
+	return s.pick(ctx).SearchDocuments(ctx, orgID, text, params)
+}
+
+func (s *canaryEsfDocumentService) SendDocument(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*models.EsfCreateDocumentResponse, error) {
+	return s.pick(ctx).SendDocument(ctx, orgID, id)
+}
+
+func (s *canaryEsfDocumentService) CacheWarmDocuments(ctx context.Context, orgID uuid.UUID, limit int) error {
+	return s.pick(ctx).CacheWarmDocuments(ctx, orgID, limit)
+}
+
+// Зависимости передаются обеим реализациям, чтобы канареечная работала в тех же условиях
+
+func (s *canaryEsfDocumentService) SetSearchClient(client *search.Client) {
+	s.primary.SetSearchClient(client)
+	s.canary.SetSearchClient(client)
+}
+
+func (s *canaryEsfDocumentService) SetGateway(gw esfgateway.Gateway, orgs repository.EsfOrganizationRepository) {
+	s.primary.SetGateway(gw, orgs)
+	s.canary.SetGateway(gw, orgs)
+}
+
+func (s *canaryEsfDocumentService) SetMeter(meter *metering.Meter) {
+	s.primary.SetMeter(meter)
+	s.canary.SetMeter(meter)
+}
+
+func (s *canaryEsfDocumentService) SetDraftReminders(reminders services.DraftReminders) {
+	s.primary.SetDraftReminders(reminders)
+	s.canary.SetDraftReminders(reminders)
+}
+
+func (s *canaryEsfDocumentService) SetExchangeRates(rates services.ExchangeRateService) {
+	s.primary.SetExchangeRates(rates)
+	s.canary.SetExchangeRates(rates)
+}
+
+func (s *canaryEsfDocumentService) SetTimezones(timezones services.Timezones) {
+	s.primary.SetTimezones(timezones)
+	s.canary.SetTimezones(timezones)
+}
+
+func (s *canaryEsfDocumentService) SetCacheManager(manager cache.CacheManager) {
+	s.primary.SetCacheManager(manager)
+	s.canary.SetCacheManager(manager)
+}
@@ -0,0 +1,125 @@
+package service_impl
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+)
+
+type contractService struct {
+	repo      repository.EsfDocumentRepository
+	timezones services.Timezones
+	logger    *logger.Logger
+}
+
+// NewContractService создает сервис справочника договоров; срок действия договора проверяется
+// по текущей дате в часовом поясе организации (timezones, nil — dates.DefaultTimezone)
+func NewContractService(repo repository.EsfDocumentRepository, timezones services.Timezones, log *logrus.Logger) services.ContractService {
+	return &contractService{
+		repo:      repo,
+		timezones: timezones,
+		logger:    logger.New(log),
+	}
+}
+
+// List возвращает договоры организации, при непустом contractorTin — одного контрагента
+func (s *contractService) List(ctx context.Context, orgID uuid.UUID, contractorTin string) ([]entity.Contract, error) {
+	contracts, err := s.repo.ListContracts(ctx, orgID, contractorTin)
+	if err != nil {
+		return nil, apperror.DatabaseErrorFrom("fetching contracts", err)
+	}
+	if contracts == nil {
+		contracts = []entity.Contract{}
+	}
+	return contracts, nil
+}
+
+// Get возвращает договор с суммой документов по нему и остатком лимита
+func (s *contractService) Get(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*services.ContractDetails, error) {
+	contract, err := s.repo.GetContract(ctx, orgID, id)
+	if err != nil {
+		return nil, apperror.DatabaseErrorFrom("fetching contract", err)
+	}
+	used, count, err := s.repo.ContractUsage(ctx, orgID, id, uuid.Nil)
+	if err != nil {
+		return nil, apperror.DatabaseErrorFrom("fetching contract usage", err)
+	}
+
+	details := &services.ContractDetails{
+		Contract:      *contract,
+		DocumentCount: count,
+		UsedAmount:    used,
+		Expired:       contract.Expired(organizationToday(ctx, s.timezones, orgID)),
+	}
+	if contract.AmountLimit > 0 {
+		remaining := contract.AmountLimit - used
+		details.RemainingLimit = &remaining
+	}
+	return details, nil
+}
+
+// Create сохраняет новый договор
+func (s *contractService) Create(ctx context.Context, orgID uuid.UUID, req *models.ContractRequest) (*entity.Contract, error) {
+	contract, appErr := contractFromRequest(req)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if err := s.repo.CreateContract(ctx, orgID, contract); err != nil {
+		return nil, apperror.DatabaseErrorFrom("creating contract", err)
+	}
+
+	s.logger.Info(ctx, "Contract created", logrus.Fields{"org_id": orgID.String(), "contract_id": contract.ID.String()})
+	return contract, nil
+}
+
+// Update изменяет договор. Номер и дата договора в уже сохраненных документах не меняются
+func (s *contractService) Update(ctx context.Context, orgID uuid.UUID, id uuid.UUID, req *models.ContractRequest) (*entity.Contract, error) {
+	contract, appErr := contractFromRequest(req)
+	if appErr != nil {
+		return nil, appErr
+	}
+	contract.ID = id
+	if err := s.repo.UpdateContract(ctx, orgID, contract); err != nil {
+		return nil, apperror.DatabaseErrorFrom("updating contract", err)
+	}
+
+	s.logger.Info(ctx, "Contract updated", logrus.Fields{"org_id": orgID.String(), "contract_id": id.String()})
+	return s.repo.GetContract(ctx, orgID, id)
+}
+
+// Delete удаляет договор, по которому нет документов
+func (s *contractService) Delete(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error {
+	if err := s.repo.DeleteContract(ctx, orgID, id); err != nil {
+		return apperror.DatabaseErrorFrom("deleting contract", err)
+	}
+
+	s.logger.Info(ctx, "Contract deleted", logrus.Fields{"org_id": orgID.String(), "contract_id": id.String()})
+	return nil
+}
+
+func contractFromRequest(req *models.ContractRequest) (*entity.Contract, *apperror.AppError) {
+	if req.EndDate != nil && req.EndDate.Before(req.StartDate.Time) {
+		return nil, apperror.ValidationError("contract end date is before its start date")
+	}
+	if req.AmountLimit < 0 {
+		return nil, apperror.ValidationError("contract amount limit must not be negative")
+	}
+	return &entity.Contract{
+		Number:        strings.TrimSpace(req.Number),
+		ContractorTin: req.ContractorTin,
+		StartDate:     req.StartDate,
+		EndDate:       req.EndDate,
+		CurrencyCode:  strings.ToUpper(req.CurrencyCode),
+		AmountLimit:   req.AmountLimit,
+		Comment:       req.Comment,
+	}, nil
+}
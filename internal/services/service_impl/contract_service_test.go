@@ -0,0 +1,172 @@
+package service_impl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/dates"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// memoryContractRepository хранит договоры и документы одной организации в памяти
+type memoryContractRepository struct {
+	repository.EsfDocumentRepository
+	contracts map[uuid.UUID]*entity.Contract
+	docs      map[uuid.UUID]*entity.EsfDocument
+}
+
+func newMemoryContractRepository() *memoryContractRepository {
+	return &memoryContractRepository{contracts: map[uuid.UUID]*entity.Contract{}, docs: map[uuid.UUID]*entity.EsfDocument{}}
+}
+
+func (m *memoryContractRepository) CreateContract(ctx context.Context, orgID uuid.UUID, contract *entity.Contract) error {
+	for _, c := range m.contracts {
+		if c.ContractorTin == contract.ContractorTin && c.Number == contract.Number {
+			return apperror.ConflictError("contract with this number already exists for the contractor")
+		}
+	}
+	contract.ID = uuid.New()
+	copied := *contract
+	m.contracts[contract.ID] = &copied
+	return nil
+}
+
+func (m *memoryContractRepository) GetContract(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*entity.Contract, error) {
+	c, ok := m.contracts[id]
+	if !ok {
+		return nil, apperror.NotFoundError("contract")
+	}
+	copied := *c
+	return &copied, nil
+}
+
+func (m *memoryContractRepository) ContractUsage(ctx context.Context, orgID uuid.UUID, contractID uuid.UUID, excludeDocID uuid.UUID) (float64, int64, error) {
+	var amount float64
+	var count int64
+	for _, doc := range m.docs {
+		if doc.ContractID != nil && *doc.ContractID == contractID && doc.ID != excludeDocID && doc.EsfStatus != entity.EsfStatusCancelled {
+			amount += doc.TotalCurrencyValue
+			count++
+		}
+	}
+	return amount, count, nil
+}
+
+func (m *memoryContractRepository) CreateDocument(ctx context.Context, orgID uuid.UUID, doc *entity.EsfDocument) error {
+	copied := *doc
+	m.docs[doc.ID] = &copied
+	return nil
+}
+
+func (m *memoryContractRepository) GetDocumentByID(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*entity.EsfDocument, error) {
+	doc, ok := m.docs[id]
+	if !ok {
+		return nil, apperror.New(apperror.ErrDocumentNotFound, "document not found")
+	}
+	return doc, nil
+}
+
+func contractDate(day string) time.Time {
+	t, _ := time.Parse("2006-01-02", day)
+	return t
+}
+
+func calendarDate(day string) dates.Date {
+	return dates.Of(contractDate(day))
+}
+
+func warningCodes(warnings []models.DocumentWarning) []string {
+	codes := []string{}
+	for _, w := range warnings {
+		codes = append(codes, w.Code)
+	}
+	return codes
+}
+
+func TestContractService_CreateAndDetails(t *testing.T) {
+	repo := newMemoryContractRepository()
+	svc := NewContractService(repo, nil, logrus.New())
+	ctx, orgID := context.Background(), uuid.New()
+
+	end := calendarDate("2026-01-31")
+	_, err := svc.Create(ctx, orgID, &models.ContractRequest{Number: "D-1", ContractorTin: "01234567890123", StartDate: calendarDate("2026-02-01"), EndDate: &end})
+	assert.Equal(t, apperror.ErrValidation, err.(*apperror.AppError).Code, "end date before start date")
+
+	contract, err := svc.Create(ctx, orgID, &models.ContractRequest{Number: " D-1 ", ContractorTin: "01234567890123", StartDate: calendarDate("2026-01-01"), CurrencyCode: "kgs", AmountLimit: 1000})
+	require.NoError(t, err)
+	assert.Equal(t, "D-1", contract.Number)
+	assert.Equal(t, "KGS", contract.CurrencyCode)
+
+	_, err = svc.Create(ctx, orgID, &models.ContractRequest{Number: "D-1", ContractorTin: "01234567890123", StartDate: calendarDate("2026-01-01")})
+	assert.Equal(t, apperror.ErrConflict, err.(*apperror.AppError).Code)
+
+	for _, doc := range []entity.EsfDocument{
+		{ID: uuid.New(), ContractID: &contract.ID, TotalCurrencyValue: 300},
+		{ID: uuid.New(), ContractID: &contract.ID, TotalCurrencyValue: 900, EsfStatus: entity.EsfStatusCancelled},
+	} {
+		require.NoError(t, repo.CreateDocument(ctx, orgID, &doc))
+	}
+
+	details, err := svc.Get(ctx, orgID, contract.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), details.DocumentCount)
+	assert.Equal(t, 300.0, details.UsedAmount)
+	require.NotNil(t, details.RemainingLimit)
+	assert.Equal(t, 700.0, *details.RemainingLimit)
+	assert.False(t, details.Expired)
+}
+
+func TestEsfDocumentService_ContractWarnings(t *testing.T) {
+	repo := newMemoryContractRepository()
+	svc := NewEsfDocumentService(repo, &gorm.DB{}, logrus.New())
+	ctx, orgID := context.Background(), uuid.New()
+
+	end := calendarDate("2026-03-31")
+	contract := &entity.Contract{Number: "D-7", ContractorTin: "01234567890123", StartDate: calendarDate("2026-01-15"), EndDate: &end, CurrencyCode: "KGS", AmountLimit: 1000}
+	require.NoError(t, repo.CreateContract(ctx, orgID, contract))
+
+	req := &models.EsfCreateDocumentRequest{
+		ContractorTin: "01234567890123", ContractID: &contract.ID, CurrencyCode: "KGS",
+		DeliveryDate: calendarDate("2026-03-31"), TotalCurrencyValue: 600,
+	}
+	created, err := svc.CreateDocument(ctx, orgID, req)
+	require.NoError(t, err)
+	assert.Empty(t, created.Warnings, "the end date is still within the contract")
+	doc := repo.docs[uuid.MustParse(created.DocumentUuid)]
+	assert.Equal(t, "D-7", doc.SupplyContractNumber)
+	assert.Equal(t, contract.StartDate, doc.ContractStartDate)
+
+	req.DeliveryDate, req.CurrencyCode = calendarDate("2026-04-01"), "USD"
+	created, err = svc.CreateDocument(ctx, orgID, req)
+	require.NoError(t, err)
+	assert.Equal(t, []string{models.WarningContractExpired, models.WarningContractLimitExceeded, models.WarningContractCurrencyMismatch}, warningCodes(created.Warnings))
+
+	warnings, err := svc.DocumentWarnings(ctx, orgID, uuid.MustParse(created.DocumentUuid))
+	require.NoError(t, err)
+	assert.Equal(t, warningCodes(created.Warnings), warningCodes(warnings))
+
+	req.DeliveryDate = calendarDate("2026-01-10")
+	req.CurrencyCode, req.TotalCurrencyValue = "KGS", 0
+	created, err = svc.CreateDocument(ctx, orgID, req)
+	require.NoError(t, err)
+	assert.Equal(t, []string{models.WarningContractNotStarted, models.WarningContractLimitExceeded}, warningCodes(created.Warnings))
+
+	req.ContractorTin = "99999999999999"
+	_, err = svc.CreateDocument(ctx, orgID, req)
+	assert.Equal(t, apperror.ErrValidation, err.(*apperror.AppError).Code, "contract of another contractor")
+
+	missing := uuid.New()
+	req.ContractorTin, req.ContractID = "01234567890123", &missing
+	_, err = svc.CreateDocument(ctx, orgID, req)
+	assert.Equal(t, apperror.ErrValidation, err.(*apperror.AppError).Code)
+}
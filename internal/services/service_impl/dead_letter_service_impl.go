@@ -0,0 +1,159 @@
+package service_impl
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/jobs"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+)
+
+// jobRequeuer ставит задачу обратно в очередь (*jobs.Manager)
+type jobRequeuer interface {
+	Requeue(ctx context.Context, job jobs.Job) (*jobs.Job, error)
+}
+
+// deadLetterService реализует DeadLetterService
+type deadLetterService struct {
+	repo   repository.DeadLetterRepository
+	jobs   jobRequeuer
+	logger *logger.Logger
+}
+
+// NewDeadLetterService создает сервис очереди недоставленных сообщений
+func NewDeadLetterService(repo repository.DeadLetterRepository, log *logrus.Logger) services.DeadLetterService {
+	return &deadLetterService{
+		repo:   repo,
+		logger: logger.New(log),
+	}
+}
+
+// SetJobManager подключает менеджер фоновых задач
+func (s *deadLetterService) SetJobManager(manager *jobs.Manager) {
+	if manager != nil {
+		s.jobs = manager
+	}
+}
+
+// RecordJob сохраняет задачу; ошибка только логируется, задача остается в dead-списке Redis
+func (s *deadLetterService) RecordJob(ctx context.Context, job jobs.Job) {
+	letter := &entity.DeadLetter{
+		Source:     entity.DeadLetterSourceJob,
+		Type:       job.Type,
+		Queue:      job.Queue,
+		Reference:  job.ID,
+		Payload:    job.Payload,
+		Attempts:   job.Attempt,
+		MaxRetries: job.MaxRetries,
+		Error:      job.LastError,
+		Status:     entity.DeadLetterStatusDead,
+	}
+	if err := s.repo.Create(ctx, letter); err != nil {
+		s.logger.Error(ctx, "Failed to record dead job", err, logrus.Fields{"job_id": job.ID, "type": job.Type})
+	}
+}
+
+// GetDeadLetter возвращает запись очереди
+func (s *deadLetterService) GetDeadLetter(ctx context.Context, id uuid.UUID) (*entity.DeadLetter, error) {
+	letter, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if letter == nil {
+		return nil, apperror.NotFoundError("dead letter")
+	}
+	return letter, nil
+}
+
+// ListDeadLetters возвращает страницу очереди
+func (s *deadLetterService) ListDeadLetters(ctx context.Context, params pagination.PaginationParams, filter repository.DeadLetterFilter) ([]entity.DeadLetter, int64, error) {
+	switch filter.Status {
+	case "", entity.DeadLetterStatusDead, entity.DeadLetterStatusRequeued:
+	default:
+		return nil, 0, apperror.ValidationError("invalid dead letter status")
+	}
+	return s.repo.List(ctx, params, filter)
+}
+
+// Requeue ставит записи обратно в обработку. Запись помечается requeued только после успешной
+// постановки, поэтому при сбое Redis ее можно повторить.
+func (s *deadLetterService) Requeue(ctx context.Context, ids []uuid.UUID) (*services.DeadLetterRequeueResult, error) {
+	if len(ids) == 0 || len(ids) > services.MaxDeadLetterRequeue {
+		return nil, apperror.ValidationError("invalid dead letter ids")
+	}
+
+	result := &services.DeadLetterRequeueResult{
+		Requeued: make([]uuid.UUID, 0, len(ids)),
+		Failed:   make([]services.DeadLetterRequeueFailure, 0),
+	}
+	seen := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if err := s.requeueOne(ctx, id); err != nil {
+			reason := err.Error()
+			var appErr *apperror.AppError
+			if errors.As(err, &appErr) {
+				reason = appErr.Message
+			}
+			result.Failed = append(result.Failed, services.DeadLetterRequeueFailure{ID: id, Error: reason})
+			continue
+		}
+		result.Requeued = append(result.Requeued, id)
+	}
+
+	s.logger.Info(ctx, "Dead letters requeued", logrus.Fields{
+		"requeued": len(result.Requeued),
+		"failed":   len(result.Failed),
+	})
+	return result, nil
+}
+
+func (s *deadLetterService) requeueOne(ctx context.Context, id uuid.UUID) error {
+	letter, err := s.GetDeadLetter(ctx, id)
+	if err != nil {
+		return err
+	}
+	if letter.Status != entity.DeadLetterStatusDead {
+		return apperror.ConflictError("dead letter is already requeued")
+	}
+
+	switch letter.Source {
+	case entity.DeadLetterSourceJob:
+		if s.jobs == nil {
+			return apperror.New(apperror.ErrConfigError, "background jobs are not configured")
+		}
+		if _, err := s.jobs.Requeue(ctx, jobs.Job{
+			ID:         letter.Reference,
+			Type:       letter.Type,
+			Queue:      letter.Queue,
+			Payload:    letter.Payload,
+			MaxRetries: letter.MaxRetries,
+		}); err != nil {
+			s.logger.Error(ctx, "Failed to requeue dead job", err, logrus.Fields{"dead_letter_id": id.String()})
+			return apperror.From(err, apperror.ErrInternal, "failed to requeue job")
+		}
+	default:
+		return apperror.ValidationError("unsupported dead letter source")
+	}
+
+	ok, err := s.repo.MarkRequeued(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return apperror.ConflictError("dead letter is already requeued")
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package service_impl
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/jobs"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+)
+
+type memoryDeadLetterRepository struct {
+	mu      sync.Mutex
+	letters map[uuid.UUID]*entity.DeadLetter
+}
+
+func newMemoryDeadLetterRepository() *memoryDeadLetterRepository {
+	return &memoryDeadLetterRepository{letters: map[uuid.UUID]*entity.DeadLetter{}}
+}
+
+func (r *memoryDeadLetterRepository) Create(ctx context.Context, letter *entity.DeadLetter) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	letter.ID = uuid.New()
+	r.letters[letter.ID] = letter
+	return nil
+}
+
+func (r *memoryDeadLetterRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.DeadLetter, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if letter, ok := r.letters[id]; ok {
+		copied := *letter
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (r *memoryDeadLetterRepository) List(ctx context.Context, params pagination.PaginationParams, filter repository.DeadLetterFilter) ([]entity.DeadLetter, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	letters := make([]entity.DeadLetter, 0)
+	for _, letter := range r.letters {
+		if filter.Status == "" || letter.Status == filter.Status {
+			letters = append(letters, *letter)
+		}
+	}
+	return letters, int64(len(letters)), nil
+}
+
+func (r *memoryDeadLetterRepository) MarkRequeued(ctx context.Context, id uuid.UUID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	letter, ok := r.letters[id]
+	if !ok || letter.Status != entity.DeadLetterStatusDead {
+		return false, nil
+	}
+	letter.Status = entity.DeadLetterStatusRequeued
+	letter.RequeueCount++
+	return true, nil
+}
+
+type recordingRequeuer struct {
+	err  error
+	jobs []jobs.Job
+}
+
+func (r *recordingRequeuer) Requeue(ctx context.Context, job jobs.Job) (*jobs.Job, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	r.jobs = append(r.jobs, job)
+	return &job, nil
+}
+
+func newTestDeadLetterService() (*deadLetterService, *memoryDeadLetterRepository, *recordingRequeuer) {
+	repo := newMemoryDeadLetterRepository()
+	requeuer := &recordingRequeuer{}
+	svc := NewDeadLetterService(repo, logrus.New()).(*deadLetterService)
+	svc.jobs = requeuer
+	return svc, repo, requeuer
+}
+
+func TestDeadLetterService_RecordAndRequeueJob(t *testing.T) {
+	svc, repo, requeuer := newTestDeadLetterService()
+	ctx := context.Background()
+
+	svc.RecordJob(ctx, jobs.Job{
+		ID:         "job-1",
+		Type:       EmailSendJob,
+		Queue:      jobs.DefaultQueue,
+		Payload:    []byte(`{"to":"user@example.com"}`),
+		Attempt:    6,
+		MaxRetries: 6,
+		LastError:  "smtp: 451 try again later",
+	})
+
+	letters, total, err := svc.ListDeadLetters(ctx, pagination.PaginationParams{Page: 1, PageSize: 20}, repository.DeadLetterFilter{})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+	letter := letters[0]
+	assert.Equal(t, entity.DeadLetterSourceJob, letter.Source)
+	assert.Equal(t, "job-1", letter.Reference)
+	assert.Equal(t, "smtp: 451 try again later", letter.Error)
+	assert.Equal(t, entity.DeadLetterStatusDead, letter.Status)
+
+	result, err := svc.Requeue(ctx, []uuid.UUID{letter.ID, letter.ID})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{letter.ID}, result.Requeued)
+	assert.Empty(t, result.Failed)
+
+	require.Len(t, requeuer.jobs, 1)
+	assert.Equal(t, "job-1", requeuer.jobs[0].ID)
+	assert.Equal(t, EmailSendJob, requeuer.jobs[0].Type)
+	assert.JSONEq(t, `{"to":"user@example.com"}`, string(requeuer.jobs[0].Payload))
+	assert.Equal(t, entity.DeadLetterStatusRequeued, repo.letters[letter.ID].Status)
+
+	// Повторная постановка той же записи отклоняется
+	result, err = svc.Requeue(ctx, []uuid.UUID{letter.ID})
+	require.NoError(t, err)
+	assert.Empty(t, result.Requeued)
+	require.Len(t, result.Failed, 1)
+	assert.Equal(t, "dead letter is already requeued", result.Failed[0].Error)
+	assert.Len(t, requeuer.jobs, 1)
+}
+
+func TestDeadLetterService_RequeueFailuresArePerItem(t *testing.T) {
+	svc, repo, requeuer := newTestDeadLetterService()
+	ctx := context.Background()
+
+	svc.RecordJob(ctx, jobs.Job{ID: "job-1", Type: "export.run"})
+	var id uuid.UUID
+	for id = range repo.letters {
+	}
+	missing := uuid.New()
+
+	requeuer.err = errors.New("redis: connection refused")
+	result, err := svc.Requeue(ctx, []uuid.UUID{id, missing})
+	require.NoError(t, err)
+	assert.Empty(t, result.Requeued)
+	require.Len(t, result.Failed, 2)
+	assert.Equal(t, "failed to requeue job", result.Failed[0].Error)
+	assert.Equal(t, "dead letter not found", result.Failed[1].Error)
+
+	// После сбоя запись остается в dead и может быть поставлена снова
+	assert.Equal(t, entity.DeadLetterStatusDead, repo.letters[id].Status)
+	requeuer.err = nil
+	result, err = svc.Requeue(ctx, []uuid.UUID{id})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{id}, result.Requeued)
+}
+
+func TestDeadLetterService_Validation(t *testing.T) {
+	svc, _, _ := newTestDeadLetterService()
+	ctx := context.Background()
+
+	_, err := svc.Requeue(ctx, nil)
+	assert.Equal(t, apperror.ErrValidation, err.(*apperror.AppError).Code)
+
+	_, _, err = svc.ListDeadLetters(ctx, pagination.PaginationParams{Page: 1, PageSize: 20}, repository.DeadLetterFilter{Status: "lost"})
+	assert.Equal(t, apperror.ErrValidation, err.(*apperror.AppError).Code)
+
+	svc.jobs = nil
+	svc.RecordJob(ctx, jobs.Job{ID: "job-2", Type: "export.run"})
+	letters, _, err := svc.ListDeadLetters(ctx, pagination.PaginationParams{Page: 1, PageSize: 20}, repository.DeadLetterFilter{})
+	require.NoError(t, err)
+	result, err := svc.Requeue(ctx, []uuid.UUID{letters[0].ID})
+	require.NoError(t, err)
+	require.Len(t, result.Failed, 1)
+	assert.Equal(t, "background jobs are not configured", result.Failed[0].Error)
+}
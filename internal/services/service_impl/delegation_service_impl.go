@@ -0,0 +1,200 @@
+package service_impl
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/audit"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+)
+
+// auditEntityDelegation тип делегирований в журнале аудита
+const auditEntityDelegation = "signing-delegations"
+
+// delegationService реализует DelegationService
+type delegationService struct {
+	repo   repository.DelegationRepository
+	users  repository.UserRepository
+	logger *logger.Logger
+	now    func() time.Time
+}
+
+// NewDelegationService создает сервис делегирования права подписи
+func NewDelegationService(repo repository.DelegationRepository, users repository.UserRepository, log *logrus.Logger) services.DelegationService {
+	return &delegationService{
+		repo:   repo,
+		users:  users,
+		logger: logger.New(log),
+		now:    time.Now,
+	}
+}
+
+// List возвращает делегирования пользователя
+func (s *delegationService) List(ctx context.Context, userID uuid.UUID) ([]entity.SigningDelegation, error) {
+	delegations, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if delegations == nil {
+		delegations = []entity.SigningDelegation{}
+	}
+	return delegations, nil
+}
+
+// Create передает право подписи на срок. Делегирующий должен иметь право по роли,
+// получатель — быть другим активным пользователем
+func (s *delegationService) Create(ctx context.Context, delegatorID uuid.UUID, req *models.DelegationRequest) (*entity.SigningDelegation, error) {
+	if req.DelegateID == uuid.Nil || req.DelegateID == delegatorID {
+		return nil, apperror.ValidationError("delegate must be another user")
+	}
+	from, to := delegationDay(req.ValidFrom), delegationDay(req.ValidTo)
+	if from.IsZero() || to.Before(from) {
+		return nil, apperror.ValidationError("invalid delegation period")
+	}
+	if to.Before(delegationDay(s.now())) {
+		return nil, apperror.ValidationError("delegation period has already ended")
+	}
+
+	delegator, err := s.activeUser(ctx, delegatorID)
+	if err != nil {
+		return nil, err
+	}
+	if delegator == nil || !delegator.Role.HasPermission(rbac.PermissionSendDocument) {
+		return nil, apperror.ForbiddenError("only a user with signing rights can delegate them")
+	}
+	delegate, err := s.activeUser(ctx, req.DelegateID)
+	if err != nil {
+		return nil, err
+	}
+	if delegate == nil {
+		return nil, apperror.NotFoundError("delegate")
+	}
+
+	delegation := &entity.SigningDelegation{
+		ID:          uuid.New(),
+		DelegatorID: delegatorID,
+		DelegateID:  req.DelegateID,
+		ValidFrom:   from,
+		ValidTo:     to,
+		Reason:      strings.TrimSpace(req.Reason),
+	}
+	if err := s.repo.Create(ctx, delegation); err != nil {
+		return nil, err
+	}
+
+	entry := audit.FromContext(ctx)
+	entry.SetEntity(auditEntityDelegation, delegation.ID.String())
+	entry.SetChange(nil, delegation)
+
+	s.logger.Info(ctx, "Signing rights delegated", logrus.Fields{
+		"delegation_id": delegation.ID.String(),
+		"delegator_id":  delegatorID.String(),
+		"delegate_id":   req.DelegateID.String(),
+		"valid_from":    from.Format(reportDateLayout),
+		"valid_to":      to.Format(reportDateLayout),
+	})
+	return delegation, nil
+}
+
+// Revoke отзывает делегирование с текущего момента
+func (s *delegationService) Revoke(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*entity.SigningDelegation, error) {
+	delegation, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if delegation == nil {
+		return nil, apperror.NotFoundError("delegation")
+	}
+	if delegation.DelegatorID != userID && !rbac.FromContext(ctx).IsAdmin() {
+		return nil, apperror.ForbiddenError("only the delegator can revoke a delegation")
+	}
+	if delegation.RevokedAt != nil {
+		return delegation, nil
+	}
+
+	before := *delegation
+	now := s.now()
+	if err := s.repo.Revoke(ctx, id, now); err != nil {
+		return nil, err
+	}
+	delegation.RevokedAt = &now
+
+	entry := audit.FromContext(ctx)
+	entry.SetEntity(auditEntityDelegation, id.String())
+	entry.SetAction("revoke")
+	entry.SetChange(&before, delegation)
+
+	s.logger.Info(ctx, "Signing delegation revoked", logrus.Fields{"delegation_id": id.String(), "user_id": userID.String()})
+	return delegation, nil
+}
+
+// Authorize подтверждает право пользователя: по роли или, для права подписи,
+// по действующему делегированию от пользователя, который сам еще имеет это право
+func (s *delegationService) Authorize(ctx context.Context, userID uuid.UUID, permission rbac.Permission) (*rbac.Grant, error) {
+	user, err := s.activeUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, apperror.ForbiddenError("user is not active")
+	}
+	if user.Role.HasPermission(permission) {
+		return &rbac.Grant{UserID: userID, Permission: permission}, nil
+	}
+	if permission != rbac.PermissionSendDocument {
+		return nil, apperror.ForbiddenError("insufficient permissions to perform this action")
+	}
+
+	delegations, err := s.repo.FindActive(ctx, userID, s.now())
+	if err != nil {
+		return nil, err
+	}
+	for i := range delegations {
+		delegation := &delegations[i]
+		delegator, err := s.activeUser(ctx, delegation.DelegatorID)
+		if err != nil {
+			return nil, err
+		}
+		// Право, отобранное у делегирующего, перестает действовать и у получателя
+		if delegator == nil || !delegator.Role.HasPermission(permission) {
+			continue
+		}
+		return &rbac.Grant{
+			UserID:       userID,
+			Permission:   permission,
+			DelegatorID:  &delegation.DelegatorID,
+			DelegationID: &delegation.ID,
+		}, nil
+	}
+	return nil, apperror.ForbiddenError("no signing rights or active delegation")
+}
+
+// activeUser возвращает пользователя или nil, если его нет или он отключен
+func (s *delegationService) activeUser(ctx context.Context, id uuid.UUID) (*entity.User, error) {
+	user, err := s.users.GetByID(ctx, id)
+	if err != nil {
+		return nil, apperror.DatabaseErrorFrom("fetching user", err)
+	}
+	if user == nil || !user.IsActive {
+		return nil, nil
+	}
+	return user, nil
+}
+
+// delegationDay отбрасывает время: срок делегирования задается календарными днями
+func delegationDay(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
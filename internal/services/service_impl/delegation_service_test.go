@@ -0,0 +1,207 @@
+package service_impl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/audit"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/esfgateway"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+	"github.com/rusgainew/tunduck-app/pkg/testutil"
+)
+
+// memoryUserRepository отдает пользователей по ID
+type memoryUserRepository struct {
+	repository.UserRepository
+	users map[uuid.UUID]*entity.User
+}
+
+func (m *memoryUserRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.User, error) {
+	return m.users[id], nil
+}
+
+// memoryDelegationRepository хранит делегирования в памяти
+type memoryDelegationRepository struct {
+	delegations []entity.SigningDelegation
+}
+
+func (r *memoryDelegationRepository) Create(ctx context.Context, delegation *entity.SigningDelegation) error {
+	r.delegations = append(r.delegations, *delegation)
+	return nil
+}
+
+func (r *memoryDelegationRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.SigningDelegation, error) {
+	for i := range r.delegations {
+		if r.delegations[i].ID == id {
+			copied := r.delegations[i]
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryDelegationRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]entity.SigningDelegation, error) {
+	var found []entity.SigningDelegation
+	for _, d := range r.delegations {
+		if d.DelegatorID == userID || d.DelegateID == userID {
+			found = append(found, d)
+		}
+	}
+	return found, nil
+}
+
+func (r *memoryDelegationRepository) FindActive(ctx context.Context, delegateID uuid.UUID, at time.Time) ([]entity.SigningDelegation, error) {
+	var found []entity.SigningDelegation
+	for _, d := range r.delegations {
+		if d.DelegateID == delegateID && d.ActiveOn(at) {
+			found = append(found, d)
+		}
+	}
+	return found, nil
+}
+
+func (r *memoryDelegationRepository) Revoke(ctx context.Context, id uuid.UUID, at time.Time) error {
+	for i := range r.delegations {
+		if r.delegations[i].ID == id {
+			r.delegations[i].RevokedAt = &at
+		}
+	}
+	return nil
+}
+
+func newDelegationFixture(now time.Time) (*delegationService, *memoryDelegationRepository, *entity.User, *entity.User) {
+	admin := testutil.NewUser(func(u *entity.User) { u.Role = rbac.RoleAdmin })
+	clerk := testutil.NewUser()
+	users := &memoryUserRepository{users: map[uuid.UUID]*entity.User{admin.ID: admin, clerk.ID: clerk}}
+	repo := &memoryDelegationRepository{}
+	svc := NewDelegationService(repo, users, logrus.New()).(*delegationService)
+	svc.now = func() time.Time { return now }
+	return svc, repo, admin, clerk
+}
+
+func TestDelegationService_CreateAndAuthorize(t *testing.T) {
+	now := contractDate("2026-07-05").Add(10 * time.Hour)
+	svc, repo, admin, clerk := newDelegationFixture(now)
+	entry := audit.NewEntry()
+	ctx := context.WithValue(context.Background(), audit.LocalsKey, entry)
+
+	_, err := svc.Authorize(ctx, clerk.ID, rbac.PermissionSendDocument)
+	assert.Equal(t, apperror.ErrForbidden, err.(*apperror.AppError).Code, "a clerk has no signing rights of their own")
+
+	vacation := &models.DelegationRequest{DelegateID: clerk.ID, ValidFrom: contractDate("2026-07-01"), ValidTo: contractDate("2026-07-14"), Reason: "vacation"}
+	_, err = svc.Create(ctx, clerk.ID, &models.DelegationRequest{DelegateID: admin.ID, ValidFrom: vacation.ValidFrom, ValidTo: vacation.ValidTo})
+	assert.Equal(t, apperror.ErrForbidden, err.(*apperror.AppError).Code, "only a user with signing rights can delegate")
+	_, err = svc.Create(ctx, admin.ID, &models.DelegationRequest{DelegateID: clerk.ID, ValidFrom: vacation.ValidTo, ValidTo: vacation.ValidFrom})
+	assert.Equal(t, apperror.ErrValidation, err.(*apperror.AppError).Code)
+	_, err = svc.Create(ctx, admin.ID, &models.DelegationRequest{DelegateID: admin.ID, ValidFrom: vacation.ValidFrom, ValidTo: vacation.ValidTo})
+	assert.Equal(t, apperror.ErrValidation, err.(*apperror.AppError).Code, "delegating to oneself")
+
+	delegation, err := svc.Create(ctx, admin.ID, vacation)
+	require.NoError(t, err)
+	var log entity.AuditLog
+	require.NoError(t, entry.Apply(&log))
+	assert.Equal(t, auditEntityDelegation, log.EntityType)
+	assert.Equal(t, delegation.ID.String(), log.EntityID)
+
+	grant, err := svc.Authorize(ctx, clerk.ID, rbac.PermissionSendDocument)
+	require.NoError(t, err)
+	assert.True(t, grant.Delegated())
+	assert.Equal(t, admin.ID, *grant.DelegatorID)
+	_, err = svc.Authorize(ctx, clerk.ID, rbac.PermissionDeleteOrganization)
+	assert.Error(t, err, "only the signing right is delegated")
+
+	// Право, отобранное у делегирующего, пропадает и у получателя
+	admin.Role = rbac.RoleUser
+	_, err = svc.Authorize(ctx, clerk.ID, rbac.PermissionSendDocument)
+	assert.Error(t, err)
+	admin.Role = rbac.RoleAdmin
+
+	svc.now = func() time.Time { return contractDate("2026-07-15") }
+	_, err = svc.Authorize(ctx, clerk.ID, rbac.PermissionSendDocument)
+	assert.Error(t, err, "the delegation period is over")
+
+	svc.now = func() time.Time { return now }
+	_, err = svc.Revoke(ctx, clerk.ID, delegation.ID)
+	assert.Equal(t, apperror.ErrForbidden, err.(*apperror.AppError).Code, "the delegate can not revoke")
+	revoked, err := svc.Revoke(ctx, admin.ID, delegation.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, revoked.RevokedAt)
+	_, err = svc.Authorize(ctx, clerk.ID, rbac.PermissionSendDocument)
+	assert.Error(t, err)
+
+	list, err := svc.List(ctx, clerk.ID)
+	require.NoError(t, err)
+	assert.Len(t, repo.delegations, 1)
+	assert.Len(t, list, 1)
+}
+
+// memorySendRepository хранит один документ организации
+type memorySendRepository struct {
+	repository.EsfDocumentRepository
+	doc *entity.EsfDocument
+}
+
+// memoryOrganizationRepository отдает одну организацию с токеном шлюза
+type memoryOrganizationRepository struct {
+	repository.EsfOrganizationRepository
+	org *entity.EstOrganization
+}
+
+func (m *memorySendRepository) GetDocumentByID(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*entity.EsfDocument, error) {
+	if m.doc.ID != id {
+		return nil, nil
+	}
+	return m.doc, nil
+}
+
+func (m *memorySendRepository) UpdateDocumentStatus(ctx context.Context, orgID uuid.UUID, id uuid.UUID, status string) error {
+	m.doc.EsfStatus = status
+	return nil
+}
+
+func (m *memoryOrganizationRepository) GetByID(ctx context.Context, id string) (*entity.EstOrganization, error) {
+	return m.org, nil
+}
+
+func TestEsfDocumentService_SendDocumentOnBehalf(t *testing.T) {
+	orgID := uuid.New()
+	repo := &memorySendRepository{doc: &entity.EsfDocument{
+		ID: uuid.New(), ContractorTin: "01234567890123", CurrencyCode: "KGS", DeliveryDate: calendarDate("2026-07-05"),
+		CatalogEntries: []entity.EsfEntries{{SalesTaxCode: "1001", Quantity: 1, Price: 100}},
+	}}
+	orgs := &memoryOrganizationRepository{org: &entity.EstOrganization{ID: orgID, Token: "org-token-123"}}
+	svc := NewEsfDocumentService(repo, &gorm.DB{}, logrus.New())
+
+	_, err := svc.SendDocument(context.Background(), orgID, repo.doc.ID)
+	assert.Equal(t, apperror.ErrExternalService, err.(*apperror.AppError).Code, "no gateway configured")
+
+	svc.SetGateway(esfgateway.NewMock(esfgateway.MockConfig{}), orgs)
+	delegatorID, delegationID := uuid.New(), uuid.New()
+	entry := audit.NewEntry()
+	ctx := context.WithValue(context.Background(), audit.LocalsKey, entry)
+	ctx = context.WithValue(ctx, rbac.GrantKey, &rbac.Grant{UserID: uuid.New(), Permission: rbac.PermissionSendDocument, DelegatorID: &delegatorID, DelegationID: &delegationID})
+
+	resp, err := svc.SendDocument(ctx, orgID, repo.doc.ID)
+	require.NoError(t, err)
+	assert.NotEmpty(t, resp.DocumentUuid)
+	assert.Equal(t, entity.EsfStatusSent, repo.doc.EsfStatus)
+
+	var log entity.AuditLog
+	require.NoError(t, entry.Apply(&log))
+	assert.Equal(t, "send", log.Action)
+	assert.Contains(t, string(log.After), delegatorID.String(), "the audit log records on whose behalf the document was sent")
+
+	_, err = svc.SendDocument(ctx, orgID, repo.doc.ID)
+	assert.Equal(t, apperror.ErrConflict, err.(*apperror.AppError).Code, "a sent document is not sent twice")
+}
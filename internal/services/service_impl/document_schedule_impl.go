@@ -0,0 +1,273 @@
+package service_impl
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/jobs"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+	"github.com/rusgainew/tunduck-app/pkg/realtime"
+)
+
+// Типы отложенных задач документов; ключ задачи — тип, организация и документ,
+// поэтому у документа в расписании не больше одной задачи каждого типа
+const (
+	DocumentAutoSendJob = "esf.auto_send"
+	DraftReminderJob    = "esf.draft_reminder"
+)
+
+// autoSendRetryPolicy отправка повторяется, пока шлюз ЭСФ недоступен; отклонение документа
+// и отсутствие права подписи не повторяются
+var autoSendRetryPolicy = jobs.RetryPolicy{
+	MaxRetries: 5,
+	Backoff:    jobs.ExponentialBackoff(30*time.Second, 10*time.Minute),
+}
+
+// draftReminderRetryPolicy напоминание повторяется, пока недоступны БД или Redis
+var draftReminderRetryPolicy = jobs.RetryPolicy{
+	MaxRetries: 3,
+	Backoff:    jobs.ExponentialBackoff(time.Minute, 10*time.Minute),
+}
+
+// documentJobPayload полезная нагрузка задач отправки и напоминания; UserID — пользователь,
+// запланировавший отправку, его право подписи проверяется в момент отправки
+type documentJobPayload struct {
+	OrganizationID uuid.UUID `json:"organization_id"`
+	DocumentID     uuid.UUID `json:"document_id"`
+	UserID         uuid.UUID `json:"user_id"`
+}
+
+// notificationPublisher публикует уведомления в реальном времени (realtime.Hub)
+type notificationPublisher interface {
+	Publish(ctx context.Context, channel, msgType string, data interface{}) error
+}
+
+// documentScheduleService реализует DocumentScheduleService отложенными задачами с ключом
+type documentScheduleService struct {
+	repo      repository.EsfDocumentRepository
+	documents services.EsfDocumentService
+	authority rbac.Authority
+	notifier  notificationPublisher
+	jobs      *jobs.Manager
+	cfg       services.DocumentScheduleConfig
+	now       func() time.Time
+	logger    *logger.Logger
+}
+
+// NewDocumentScheduleService создает сервис и регистрирует обработчики DocumentAutoSendJob
+// и DraftReminderJob; менеджер задач должен быть запущен после вызова. hub может быть nil:
+// тогда напоминания только пишутся в лог.
+func NewDocumentScheduleService(
+	repo repository.EsfDocumentRepository,
+	documents services.EsfDocumentService,
+	authority rbac.Authority,
+	hub *realtime.Hub,
+	manager *jobs.Manager,
+	cfg services.DocumentScheduleConfig,
+	log *logrus.Logger,
+) services.DocumentScheduleService {
+	if cfg.Queue == "" {
+		cfg.Queue = jobs.DefaultQueue
+	}
+
+	s := &documentScheduleService{
+		repo:      repo,
+		documents: documents,
+		authority: authority,
+		jobs:      manager,
+		cfg:       cfg,
+		now:       time.Now,
+		logger:    logger.New(log),
+	}
+	if hub != nil {
+		s.notifier = hub
+	}
+	if manager != nil {
+		manager.Register(DocumentAutoSendJob, s.handleAutoSend, autoSendRetryPolicy)
+		manager.Register(DraftReminderJob, s.handleDraftReminder, draftReminderRetryPolicy)
+	}
+	return s
+}
+
+// documentJobKey ключ задачи документа в расписании
+func documentJobKey(jobType string, orgID, id uuid.UUID) string {
+	return jobType + ":" + orgID.String() + ":" + id.String()
+}
+
+// ScheduleSend планирует отправку документа, который еще можно отправить; повторный вызов переносит время
+func (s *documentScheduleService) ScheduleSend(ctx context.Context, orgID, id, userID uuid.UUID, sendAt time.Time) (*services.ScheduledSend, error) {
+	if !sendAt.After(s.now()) {
+		return nil, apperror.ValidationError("sendAt must be in the future")
+	}
+	if _, err := s.editableDocument(ctx, orgID, id); err != nil {
+		return nil, err
+	}
+
+	payload := documentJobPayload{OrganizationID: orgID, DocumentID: id, UserID: userID}
+	if _, err := s.jobs.Enqueue(ctx, DocumentAutoSendJob, payload,
+		jobs.At(sendAt), jobs.WithKey(documentJobKey(DocumentAutoSendJob, orgID, id)), jobs.WithQueue(s.cfg.Queue)); err != nil {
+		return nil, apperror.New(apperror.ErrInternal, "failed to schedule document send").WithError(err)
+	}
+
+	s.logger.Info(ctx, "Document send scheduled", logrus.Fields{
+		"org_id":  orgID.String(),
+		"doc_id":  id.String(),
+		"user_id": userID.String(),
+		"send_at": sendAt,
+	})
+	return &services.ScheduledSend{DocumentID: id, SendAt: sendAt, ScheduledBy: userID}, nil
+}
+
+// CancelSend снимает запланированную отправку с расписания
+func (s *documentScheduleService) CancelSend(ctx context.Context, orgID, id uuid.UUID) error {
+	cancelled, err := s.jobs.Cancel(ctx, documentJobKey(DocumentAutoSendJob, orgID, id))
+	if err != nil {
+		return apperror.New(apperror.ErrInternal, "failed to cancel document send").WithError(err)
+	}
+	if !cancelled {
+		return apperror.New(apperror.ErrNotFound, "document send is not scheduled")
+	}
+
+	s.logger.Info(ctx, "Scheduled document send cancelled", logrus.Fields{"org_id": orgID.String(), "doc_id": id.String()})
+	return nil
+}
+
+// RemindDraft переносит напоминание о черновике; при DraftReminderAfter <= 0 ничего не делает
+func (s *documentScheduleService) RemindDraft(ctx context.Context, orgID, id uuid.UUID) error {
+	if s.cfg.DraftReminderAfter <= 0 {
+		return nil
+	}
+
+	payload := documentJobPayload{OrganizationID: orgID, DocumentID: id}
+	_, err := s.jobs.Enqueue(ctx, DraftReminderJob, payload,
+		jobs.At(s.now().Add(s.cfg.DraftReminderAfter)), jobs.WithKey(documentJobKey(DraftReminderJob, orgID, id)), jobs.WithQueue(s.cfg.Queue))
+	return err
+}
+
+// ForgetDocument снимает с расписания напоминание и отправку документа
+func (s *documentScheduleService) ForgetDocument(ctx context.Context, orgID, id uuid.UUID) error {
+	var errs []error
+	for _, jobType := range []string{DraftReminderJob, DocumentAutoSendJob} {
+		if _, err := s.jobs.Cancel(ctx, documentJobKey(jobType, orgID, id)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// editableDocument возвращает документ, который еще не отправлен в ЭСФ: 404, если его нет, 409, если отправлен
+func (s *documentScheduleService) editableDocument(ctx context.Context, orgID, id uuid.UUID) (*entity.EsfDocument, error) {
+	doc, err := s.repo.GetDocumentByID(ctx, orgID, id)
+	if err != nil {
+		return nil, apperror.DatabaseErrorFrom("fetching document", err)
+	}
+	if doc == nil {
+		return nil, apperror.New(apperror.ErrDocumentNotFound, "document not found")
+	}
+	if !entity.IsEsfDocumentEditable(doc.EsfStatus) {
+		return nil, apperror.New(apperror.ErrConflict, "document has ESF status "+doc.EsfStatus+" and can not be sent again")
+	}
+	return doc, nil
+}
+
+// handleAutoSend отправляет документ правом подписи пользователя, запланировавшего отправку.
+// Право проверяется заново: отозванное делегирование отменяет отправку. Отмененная или
+// перенесенная отправка (в том числе ожидавшая повтора) не выполняется.
+func (s *documentScheduleService) handleAutoSend(ctx context.Context, job *jobs.Job) error {
+	var payload documentJobPayload
+	if err := job.Decode(&payload); err != nil {
+		return jobs.Permanent(err)
+	}
+	fields := logrus.Fields{
+		"org_id":        payload.OrganizationID.String(),
+		"doc_id":        payload.DocumentID.String(),
+		"user_id":       payload.UserID.String(),
+		"scheduled_for": job.RunAt,
+	}
+
+	grant, err := s.authority.Authorize(ctx, payload.UserID, rbac.PermissionSendDocument)
+	if err != nil {
+		s.logger.Error(ctx, "Scheduled document send is not authorized", err, fields)
+		return permanentIfClientError(err)
+	}
+
+	// Отправка могла быть отменена или перенесена, пока задача ждала в очереди или между повторами
+	current, err := s.jobs.Current(ctx, job)
+	if err != nil {
+		return err
+	}
+	if !current {
+		s.logger.Info(ctx, "Scheduled document send skipped: the schedule was cancelled or moved", fields)
+		return nil
+	}
+
+	ctx = context.WithValue(ctx, rbac.GrantKey, grant)
+	resp, err := s.documents.SendDocument(ctx, payload.OrganizationID, payload.DocumentID)
+	if err != nil {
+		var appErr *apperror.AppError
+		if errors.As(err, &appErr) && appErr.Code == apperror.ErrConflict {
+			// Документ уже отправлен вручную: повторять нечего
+			s.logger.Info(ctx, "Scheduled document send skipped: "+appErr.Message, fields)
+			return nil
+		}
+		s.logger.Error(ctx, "Scheduled document send failed", err, fields)
+		return permanentIfClientError(err)
+	}
+
+	fields["esf_uuid"] = resp.DocumentUuid
+	s.logger.Info(ctx, "Scheduled document sent to ESF", fields)
+	return nil
+}
+
+// handleDraftReminder публикует организации напоминание, если документ все еще не отправлен
+func (s *documentScheduleService) handleDraftReminder(ctx context.Context, job *jobs.Job) error {
+	var payload documentJobPayload
+	if err := job.Decode(&payload); err != nil {
+		return jobs.Permanent(err)
+	}
+	fields := logrus.Fields{"org_id": payload.OrganizationID.String(), "doc_id": payload.DocumentID.String()}
+
+	doc, err := s.editableDocument(ctx, payload.OrganizationID, payload.DocumentID)
+	if err != nil {
+		if isClientError(err) {
+			// Документ удален или уже отправлен
+			return nil
+		}
+		return err
+	}
+
+	if s.notifier == nil {
+		s.logger.Info(ctx, "Draft document is still not sent", fields)
+		return nil
+	}
+	return s.notifier.Publish(ctx, realtime.OrgChannel(payload.OrganizationID), realtime.TypeDraftReminder, realtime.DraftReminder{
+		DocumentID:    doc.ID,
+		ContractorTin: doc.ContractorTin,
+		EsfStatus:     doc.EsfStatus,
+		UpdatedAt:     doc.UpdatedAt,
+	})
+}
+
+// permanentIfClientError не повторяет задачу, если ошибка — ошибка запроса (4xx): повтор даст тот же результат
+func permanentIfClientError(err error) error {
+	if isClientError(err) {
+		return jobs.Permanent(err)
+	}
+	return err
+}
+
+// isClientError сообщает, что ошибка — AppError с кодом ответа 4xx
+func isClientError(err error) bool {
+	var appErr *apperror.AppError
+	return errors.As(err, &appErr) && appErr.HTTPStatus < http.StatusInternalServerError
+}
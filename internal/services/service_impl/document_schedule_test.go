@@ -0,0 +1,189 @@
+package service_impl
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/jobs"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+	"github.com/rusgainew/tunduck-app/pkg/realtime"
+)
+
+// scheduleAuthority подтверждает право подписи только пользователям из grants
+type scheduleAuthority struct {
+	grants map[uuid.UUID]*rbac.Grant
+}
+
+func (a *scheduleAuthority) Authorize(ctx context.Context, userID uuid.UUID, permission rbac.Permission) (*rbac.Grant, error) {
+	if grant, ok := a.grants[userID]; ok {
+		return grant, nil
+	}
+	return nil, apperror.ForbiddenError("no signing rights or active delegation")
+}
+
+// scheduleDocumentService отправляет документ и запоминает Grant из контекста; err — ошибка отправки
+type scheduleDocumentService struct {
+	services.EsfDocumentService
+	sent  []uuid.UUID
+	grant *rbac.Grant
+	err   error
+}
+
+func (s *scheduleDocumentService) SendDocument(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*models.EsfCreateDocumentResponse, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.sent = append(s.sent, id)
+	s.grant = rbac.GrantFromContext(ctx)
+	return &models.EsfCreateDocumentResponse{DocumentUuid: id.String()}, nil
+}
+
+// notificationRecorder запоминает опубликованные уведомления
+type notificationRecorder struct {
+	channels []string
+	types    []string
+	data     []interface{}
+}
+
+func (r *notificationRecorder) Publish(ctx context.Context, channel, msgType string, data interface{}) error {
+	r.channels = append(r.channels, channel)
+	r.types = append(r.types, msgType)
+	r.data = append(r.data, data)
+	return nil
+}
+
+func newTestScheduleService(t *testing.T, repo *tenantDocumentRepository, docs services.EsfDocumentService, authority rbac.Authority, now time.Time) *documentScheduleService {
+	// Redis не используется: обработчики задач вызываются напрямую
+	client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	t.Cleanup(func() { client.Close() })
+	manager := jobs.NewManager(client, jobs.Config{Registerer: prometheus.NewRegistry()}, logrus.New())
+
+	cfg := services.DocumentScheduleConfig{DraftReminderAfter: services.DefaultDraftReminderAfter}
+	s := NewDocumentScheduleService(repo, docs, authority, nil, manager, cfg, logrus.New()).(*documentScheduleService)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func documentJob(t *testing.T, jobType string, payload documentJobPayload) *jobs.Job {
+	raw, err := json.Marshal(payload)
+	require.NoError(t, err)
+	return &jobs.Job{Type: jobType, Payload: raw, MaxRetries: 3}
+}
+
+func TestDocumentSchedule_ScheduleSendRejects(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	orgID := uuid.New()
+	repo := &tenantDocumentRepository{orgID: orgID, doc: &entity.EsfDocument{ID: uuid.New(), EsfStatus: entity.EsfStatusSent}}
+	s := newTestScheduleService(t, repo, &scheduleDocumentService{}, &scheduleAuthority{}, now)
+	ctx := context.Background()
+
+	var appErr *apperror.AppError
+	_, err := s.ScheduleSend(ctx, orgID, repo.doc.ID, uuid.New(), now)
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, apperror.ErrValidation, appErr.Code)
+
+	// Отправленный документ нельзя запланировать снова, чужой документ не найден
+	_, err = s.ScheduleSend(ctx, orgID, repo.doc.ID, uuid.New(), now.Add(time.Hour))
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, apperror.ErrConflict, appErr.Code)
+
+	_, err = s.ScheduleSend(ctx, uuid.New(), repo.doc.ID, uuid.New(), now.Add(time.Hour))
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, apperror.ErrDocumentNotFound, appErr.Code)
+}
+
+func TestDocumentSchedule_AutoSendChecksAuthorityAgain(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	orgID, signer, revoked := uuid.New(), uuid.New(), uuid.New()
+	repo := &tenantDocumentRepository{orgID: orgID, doc: &entity.EsfDocument{ID: uuid.New()}}
+	docs := &scheduleDocumentService{}
+	authority := &scheduleAuthority{grants: map[uuid.UUID]*rbac.Grant{
+		signer: {UserID: signer, Permission: rbac.PermissionSendDocument},
+	}}
+	s := newTestScheduleService(t, repo, docs, authority, now)
+	ctx := context.Background()
+
+	// Право подписи пользователя проверяется в момент отправки и попадает в контекст для аудита
+	job := documentJob(t, DocumentAutoSendJob, documentJobPayload{OrganizationID: orgID, DocumentID: repo.doc.ID, UserID: signer})
+	require.NoError(t, s.handleAutoSend(ctx, job))
+	assert.Equal(t, []uuid.UUID{repo.doc.ID}, docs.sent)
+	require.NotNil(t, docs.grant)
+	assert.Equal(t, signer, docs.grant.UserID)
+
+	// Делегирование отозвано до срока: отправка не повторяется
+	job = documentJob(t, DocumentAutoSendJob, documentJobPayload{OrganizationID: orgID, DocumentID: repo.doc.ID, UserID: revoked})
+	err := s.handleAutoSend(ctx, job)
+	assert.True(t, jobs.IsPermanent(err))
+	assert.Len(t, docs.sent, 1)
+}
+
+func TestDocumentSchedule_AutoSendErrors(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	orgID, signer := uuid.New(), uuid.New()
+	repo := &tenantDocumentRepository{orgID: orgID, doc: &entity.EsfDocument{ID: uuid.New()}}
+	docs := &scheduleDocumentService{}
+	authority := &scheduleAuthority{grants: map[uuid.UUID]*rbac.Grant{signer: {UserID: signer}}}
+	s := newTestScheduleService(t, repo, docs, authority, now)
+	ctx := context.Background()
+	job := documentJob(t, DocumentAutoSendJob, documentJobPayload{OrganizationID: orgID, DocumentID: repo.doc.ID, UserID: signer})
+
+	// Документ уже отправлен вручную — задача завершается без ошибки
+	docs.err = apperror.New(apperror.ErrConflict, "document has ESF status sent and can not be sent again")
+	assert.NoError(t, s.handleAutoSend(ctx, job))
+
+	// Отклонение документа шлюзом не повторяется, недоступность шлюза — повторяется
+	docs.err = apperror.ValidationError("ESF gateway rejected the document")
+	assert.True(t, jobs.IsPermanent(s.handleAutoSend(ctx, job)))
+
+	docs.err = apperror.New(apperror.ErrExternalService, "failed to send document to ESF")
+	err := s.handleAutoSend(ctx, job)
+	require.Error(t, err)
+	assert.False(t, jobs.IsPermanent(err))
+}
+
+func TestDocumentSchedule_DraftReminder(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	orgID := uuid.New()
+	updatedAt := now.Add(-services.DefaultDraftReminderAfter)
+	repo := &tenantDocumentRepository{orgID: orgID, doc: &entity.EsfDocument{ID: uuid.New(), ContractorTin: "01234567890123", UpdatedAt: updatedAt}}
+	s := newTestScheduleService(t, repo, &scheduleDocumentService{}, &scheduleAuthority{}, now)
+	notifier := &notificationRecorder{}
+	s.notifier = notifier
+	ctx := context.Background()
+
+	job := documentJob(t, DraftReminderJob, documentJobPayload{OrganizationID: orgID, DocumentID: repo.doc.ID})
+	require.NoError(t, s.handleDraftReminder(ctx, job))
+	require.Len(t, notifier.types, 1)
+	assert.Equal(t, realtime.TypeDraftReminder, notifier.types[0])
+	assert.Equal(t, realtime.OrgChannel(orgID), notifier.channels[0])
+	assert.Equal(t, realtime.DraftReminder{DocumentID: repo.doc.ID, ContractorTin: "01234567890123", UpdatedAt: updatedAt}, notifier.data[0])
+
+	// Документ отправлен или удален до срока напоминания — уведомления нет
+	repo.doc.EsfStatus = entity.EsfStatusSent
+	require.NoError(t, s.handleDraftReminder(ctx, job))
+	job = documentJob(t, DraftReminderJob, documentJobPayload{OrganizationID: orgID, DocumentID: uuid.New()})
+	require.NoError(t, s.handleDraftReminder(ctx, job))
+	assert.Len(t, notifier.types, 1)
+}
+
+func TestDocumentSchedule_RemindersDisabled(t *testing.T) {
+	repo := &tenantDocumentRepository{orgID: uuid.New(), doc: &entity.EsfDocument{ID: uuid.New()}}
+	s := newTestScheduleService(t, repo, &scheduleDocumentService{}, &scheduleAuthority{}, time.Now())
+	s.cfg.DraftReminderAfter = 0
+
+	// Без DRAFT_REMINDER_AFTER задача не ставится, поэтому Redis не нужен
+	assert.NoError(t, s.RemindDraft(context.Background(), repo.orgID, repo.doc.ID))
+}
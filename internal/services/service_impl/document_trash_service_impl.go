@@ -0,0 +1,366 @@
+package service_impl
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/jobs"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+)
+
+// DocumentsBulkJob тип фоновой задачи массового удаления или восстановления документов
+const DocumentsBulkJob = "documents.bulk"
+
+// trashBatchSize сколько документов проверяется одним запросом к БД и обрабатывается между сохранениями прогресса
+const trashBatchSize = 200
+
+// Действия массовой обработки документов
+const (
+	trashActionDelete  = "delete"
+	trashActionRestore = "restore"
+)
+
+// trashRetryPolicy обработка повторяется с начала: уже обработанные документы пропускаются
+var trashRetryPolicy = jobs.RetryPolicy{
+	MaxRetries: 3,
+	Backoff:    jobs.ExponentialBackoff(30*time.Second, 5*time.Minute),
+}
+
+// documentsBulkPayload полезная нагрузка задачи массовой обработки
+type documentsBulkPayload struct {
+	OperationID    uuid.UUID   `json:"operation_id"`
+	OrganizationID uuid.UUID   `json:"organization_id"`
+	Action         string      `json:"action"`
+	IDs            []uuid.UUID `json:"ids"`
+}
+
+// documentTrashService реализует DocumentTrashService поверх EsfDocumentService, чтобы каждое
+// удаление и восстановление проходило обычным путем: кеш, журнал аудита, outbox событий и поиска
+type documentTrashService struct {
+	repo       repository.EsfDocumentRepository
+	orgRepo    repository.EsfOrganizationRepository
+	documents  services.EsfDocumentService
+	operations services.OperationService
+	jobs       *jobs.Manager
+	cfg        services.TrashConfig
+	now        func() time.Time
+	logger     *logger.Logger
+}
+
+// NewDocumentTrashService создает сервис корзины и регистрирует обработчик задачи DocumentsBulkJob.
+// Менеджер задач должен быть запущен после вызова, чтобы воркеры знали обработчик.
+func NewDocumentTrashService(
+	repo repository.EsfDocumentRepository,
+	orgRepo repository.EsfOrganizationRepository,
+	documents services.EsfDocumentService,
+	operations services.OperationService,
+	manager *jobs.Manager,
+	cfg services.TrashConfig,
+	log *logrus.Logger,
+) services.DocumentTrashService {
+	if cfg.Retention <= 0 {
+		cfg.Retention = services.DefaultTrashRetention
+	}
+	if cfg.AsyncThreshold <= 0 {
+		cfg.AsyncThreshold = services.DefaultTrashAsyncThreshold
+	}
+	if cfg.Queue == "" {
+		cfg.Queue = jobs.DefaultQueue
+	}
+
+	s := &documentTrashService{
+		repo:       repo,
+		orgRepo:    orgRepo,
+		documents:  documents,
+		operations: operations,
+		jobs:       manager,
+		cfg:        cfg,
+		now:        time.Now,
+		logger:     logger.New(log),
+	}
+	if manager != nil {
+		manager.Register(DocumentsBulkJob, s.handleBulk, trashRetryPolicy)
+	}
+	return s
+}
+
+// TrashPath путь списка корзины организации; служит ссылкой на результат массового удаления
+func TrashPath(orgID uuid.UUID) string {
+	return "/api/esf-documents/trash?orgId=" + orgID.String()
+}
+
+// BulkDelete переносит документы в корзину
+func (s *documentTrashService) BulkDelete(ctx context.Context, req services.BulkDocumentsRequest) (*services.BulkDocumentsResult, error) {
+	return s.bulk(ctx, trashActionDelete, req)
+}
+
+// BulkRestore восстанавливает документы из корзины
+func (s *documentTrashService) BulkRestore(ctx context.Context, req services.BulkDocumentsRequest) (*services.BulkDocumentsResult, error) {
+	return s.bulk(ctx, trashActionRestore, req)
+}
+
+// bulk отбирает документы, которые можно обработать, и обрабатывает их сразу или ставит фоновую задачу
+func (s *documentTrashService) bulk(ctx context.Context, action string, req services.BulkDocumentsRequest) (*services.BulkDocumentsResult, error) {
+	if req.OrganizationID == uuid.Nil {
+		return nil, apperror.ValidationError("invalid organization ID")
+	}
+	ids := uniqueIDs(req.IDs)
+	if len(ids) == 0 {
+		return nil, apperror.ValidationError("document IDs are required")
+	}
+	if len(ids) > services.MaxBulkDocuments {
+		return nil, apperror.ValidationError("too many document IDs").
+			WithDetails(fmt.Sprintf("at most %d documents per request", services.MaxBulkDocuments))
+	}
+
+	result := &services.BulkDocumentsResult{Skipped: []services.BulkSkipped{}}
+	var accepted []uuid.UUID
+	for start := 0; start < len(ids); start += trashBatchSize {
+		batch, skipped, err := s.partition(ctx, action, req.OrganizationID, ids[start:min(start+trashBatchSize, len(ids))])
+		if err != nil {
+			return nil, err
+		}
+		accepted = append(accepted, batch...)
+		result.Skipped = append(result.Skipped, skipped...)
+	}
+
+	fields := logrus.Fields{
+		"org_id":   req.OrganizationID.String(),
+		"action":   action,
+		"accepted": len(accepted),
+		"skipped":  len(result.Skipped),
+	}
+	if len(accepted) <= s.cfg.AsyncThreshold {
+		processed, err := s.apply(ctx, action, req.OrganizationID, accepted, nil)
+		if err != nil {
+			return nil, err
+		}
+		result.Processed = processed
+		s.logger.Info(ctx, "Bulk document action completed", fields)
+		return result, nil
+	}
+
+	kind := services.OperationKindBulkDelete
+	if action == trashActionRestore {
+		kind = services.OperationKindBulkRestore
+	}
+	orgID := req.OrganizationID
+	op, err := s.operations.Start(ctx, services.NewOperation{Kind: kind, RequestedBy: req.RequestedBy, OrganizationID: &orgID})
+	if err != nil {
+		return nil, err
+	}
+	s.operations.Progress(ctx, op.ID, 0, int64(len(accepted)))
+	op.Total = int64(len(accepted))
+
+	payload := documentsBulkPayload{OperationID: op.ID, OrganizationID: orgID, Action: action, IDs: accepted}
+	if _, err := s.jobs.Enqueue(ctx, DocumentsBulkJob, payload, jobs.WithQueue(s.cfg.Queue)); err != nil {
+		s.logger.Error(ctx, "Failed to enqueue bulk document action", err, fields)
+		s.operations.Fail(ctx, op.ID, err)
+		return nil, apperror.From(err, apperror.ErrInternal, "failed to queue bulk action")
+	}
+
+	fields["operation_id"] = op.ID.String()
+	s.logger.Info(ctx, "Bulk document action queued", fields)
+	result.Operation = op
+	return result, nil
+}
+
+// partition делит документы на те, что можно обработать, и пропущенные с причиной
+func (s *documentTrashService) partition(ctx context.Context, action string, orgID uuid.UUID, ids []uuid.UUID) ([]uuid.UUID, []services.BulkSkipped, error) {
+	var docs []entity.EsfDocument
+	var err error
+	if action == trashActionDelete {
+		docs, err = s.repo.GetDocumentsByIDs(ctx, orgID, ids)
+	} else {
+		docs, err = s.repo.GetDeletedDocumentsByIDs(ctx, orgID, ids)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	found := make(map[uuid.UUID]*entity.EsfDocument, len(docs))
+	for i := range docs {
+		found[docs[i].ID] = &docs[i]
+	}
+
+	cutoff := s.now().Add(-s.cfg.Retention)
+	accepted := make([]uuid.UUID, 0, len(ids))
+	var skipped []services.BulkSkipped
+	for _, id := range ids {
+		doc, ok := found[id]
+		switch {
+		case !ok && action == trashActionDelete:
+			skipped = append(skipped, services.BulkSkipped{ID: id, Code: string(apperror.ErrDocumentNotFound), Reason: "document not found"})
+		case !ok:
+			skipped = append(skipped, services.BulkSkipped{ID: id, Code: string(apperror.ErrDocumentNotFound), Reason: "deleted document not found"})
+		case action == trashActionDelete && !entity.IsEsfDocumentEditable(doc.EsfStatus):
+			skipped = append(skipped, services.BulkSkipped{ID: id, Code: string(apperror.ErrConflict), Reason: "document has ESF status " + doc.EsfStatus})
+		case action == trashActionRestore && doc.DeletedAt.Time.Before(cutoff):
+			skipped = append(skipped, services.BulkSkipped{ID: id, Code: string(apperror.ErrConflict), Reason: "retention period expired"})
+		default:
+			accepted = append(accepted, id)
+		}
+	}
+	return accepted, skipped, nil
+}
+
+// apply обрабатывает документы пачками, заново проверяя каждую пачку: между запросом и фоновой
+// задачей документ могли удалить, восстановить или отправить. Возвращает число обработанных документов.
+func (s *documentTrashService) apply(ctx context.Context, action string, orgID uuid.UUID, ids []uuid.UUID, progress func(done int)) (int, error) {
+	processed := 0
+	for start := 0; start < len(ids); start += trashBatchSize {
+		end := min(start+trashBatchSize, len(ids))
+		accepted, _, err := s.partition(ctx, action, orgID, ids[start:end])
+		if err != nil {
+			return processed, err
+		}
+
+		for _, id := range accepted {
+			if action == trashActionDelete {
+				err = s.documents.DeleteDocument(ctx, orgID, id)
+			} else {
+				err = s.documents.RestoreDocument(ctx, orgID, id)
+			}
+			if err != nil {
+				return processed, err
+			}
+			processed++
+		}
+		if progress != nil {
+			progress(end)
+		}
+	}
+	return processed, nil
+}
+
+// handleBulk выполняет массовую обработку, поставленную bulk
+func (s *documentTrashService) handleBulk(ctx context.Context, job *jobs.Job) error {
+	var payload documentsBulkPayload
+	if err := job.Decode(&payload); err != nil {
+		return jobs.Permanent(err)
+	}
+	if payload.Action != trashActionDelete && payload.Action != trashActionRestore {
+		return jobs.Permanent(fmt.Errorf("unknown bulk action %q", payload.Action))
+	}
+
+	opID := payload.OperationID
+	s.operations.Running(ctx, opID)
+	total := int64(len(payload.IDs))
+	processed, err := s.apply(ctx, payload.Action, payload.OrganizationID, payload.IDs, func(done int) {
+		s.operations.Progress(ctx, opID, int64(done), total)
+	})
+
+	fields := logrus.Fields{
+		"operation_id": opID.String(),
+		"org_id":       payload.OrganizationID.String(),
+		"action":       payload.Action,
+		"processed":    processed,
+	}
+	if err != nil {
+		if job.Attempt >= job.MaxRetries || jobs.IsPermanent(err) {
+			s.operations.Fail(ctx, opID, err)
+		} else {
+			s.operations.Retry(ctx, opID, err)
+		}
+		s.logger.Error(ctx, "Bulk document action failed", err, fields)
+		return err
+	}
+
+	resultURL := ""
+	if payload.Action == trashActionDelete {
+		resultURL = TrashPath(payload.OrganizationID)
+	}
+	s.operations.Succeed(ctx, opID, resultURL)
+	s.logger.Info(ctx, "Bulk document action completed", fields)
+	return nil
+}
+
+// ListTrash возвращает документы корзины со временем окончательного удаления
+func (s *documentTrashService) ListTrash(ctx context.Context, orgID uuid.UUID, params pagination.PaginationParams) ([]services.TrashedDocument, int64, error) {
+	docs, total, err := s.repo.ListDeletedDocuments(ctx, orgID, s.now().Add(-s.cfg.Retention), params)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]services.TrashedDocument, len(docs))
+	for i, doc := range docs {
+		result[i] = services.TrashedDocument{
+			ID:                 doc.ID,
+			ContractorTin:      doc.ContractorTin,
+			CurrencyCode:       doc.CurrencyCode,
+			TotalCurrencyValue: doc.TotalCurrencyValue,
+			DeliveryDate:       doc.DeliveryDate,
+			CreatedAt:          doc.CreatedAt,
+			DeletedAt:          doc.DeletedAt.Time,
+			PurgeAt:            doc.DeletedAt.Time.Add(s.cfg.Retention),
+		}
+	}
+	return result, total, nil
+}
+
+// PurgeExpired окончательно удаляет документы, пролежавшие в корзине дольше срока хранения.
+// Ошибка одной организации не останавливает остальные.
+func (s *documentTrashService) PurgeExpired(ctx context.Context) (int64, error) {
+	orgs, err := s.orgRepo.GetAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	cutoff := s.now().Add(-s.cfg.Retention)
+	var purged int64
+	for _, org := range orgs {
+		n, err := s.purgeOrganization(ctx, org.ID, cutoff)
+		purged += n
+		if err != nil {
+			if ctx.Err() != nil {
+				return purged, ctx.Err()
+			}
+			s.logger.Warn(ctx, "Failed to purge expired documents", logrus.Fields{"org_id": org.ID.String(), "error": err.Error()})
+		}
+	}
+
+	s.logger.Info(ctx, "Expired trash purged", logrus.Fields{"purged": purged, "before": cutoff.Format(time.RFC3339)})
+	return purged, nil
+}
+
+func (s *documentTrashService) purgeOrganization(ctx context.Context, orgID uuid.UUID, cutoff time.Time) (int64, error) {
+	var purged int64
+	for {
+		ids, err := s.repo.ListDocumentsDeletedBefore(ctx, orgID, cutoff, trashBatchSize)
+		if err != nil {
+			return purged, err
+		}
+		for _, id := range ids {
+			if err := s.documents.PurgeDocument(ctx, orgID, id); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+		if len(ids) < trashBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// uniqueIDs убирает повторы и пустые идентификаторы, сохраняя порядок
+func uniqueIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	result := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if id == uuid.Nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+	}
+	return result
+}
@@ -0,0 +1,204 @@
+package service_impl
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/jobs"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+)
+
+// memoryTrashRepository хранит документы одной организации в памяти вместе с удаленными
+type memoryTrashRepository struct {
+	repository.EsfDocumentRepository
+	docs map[uuid.UUID]*entity.EsfDocument
+}
+
+func (m *memoryTrashRepository) GetDocumentsByIDs(ctx context.Context, orgID uuid.UUID, ids []uuid.UUID) ([]entity.EsfDocument, error) {
+	return m.find(ids, false), nil
+}
+
+func (m *memoryTrashRepository) GetDeletedDocumentsByIDs(ctx context.Context, orgID uuid.UUID, ids []uuid.UUID) ([]entity.EsfDocument, error) {
+	return m.find(ids, true), nil
+}
+
+func (m *memoryTrashRepository) find(ids []uuid.UUID, deleted bool) []entity.EsfDocument {
+	var result []entity.EsfDocument
+	for _, id := range ids {
+		if doc, ok := m.docs[id]; ok && doc.DeletedAt.Valid == deleted {
+			result = append(result, *doc)
+		}
+	}
+	return result
+}
+
+func (m *memoryTrashRepository) ListDeletedDocuments(ctx context.Context, orgID uuid.UUID, since time.Time, params pagination.PaginationParams) ([]entity.EsfDocument, int64, error) {
+	var result []entity.EsfDocument
+	for _, doc := range m.docs {
+		if doc.DeletedAt.Valid && !doc.DeletedAt.Time.Before(since) {
+			result = append(result, *doc)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].DeletedAt.Time.After(result[j].DeletedAt.Time) })
+	return result, int64(len(result)), nil
+}
+
+func (m *memoryTrashRepository) ListDocumentsDeletedBefore(ctx context.Context, orgID uuid.UUID, before time.Time, limit int) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	for id, doc := range m.docs {
+		if doc.DeletedAt.Valid && doc.DeletedAt.Time.Before(before) && len(ids) < limit {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// trashDocumentService выполняет удаление, восстановление и очистку над memoryTrashRepository
+type trashDocumentService struct {
+	services.EsfDocumentService
+	repo *memoryTrashRepository
+	now  time.Time
+}
+
+func (s *trashDocumentService) DeleteDocument(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error {
+	s.repo.docs[id].DeletedAt = gorm.DeletedAt{Time: s.now, Valid: true}
+	return nil
+}
+
+func (s *trashDocumentService) RestoreDocument(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error {
+	s.repo.docs[id].DeletedAt = gorm.DeletedAt{}
+	return nil
+}
+
+func (s *trashDocumentService) PurgeDocument(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error {
+	delete(s.repo.docs, id)
+	return nil
+}
+
+func newTestTrashService(t *testing.T, repo *memoryTrashRepository, ops *memoryOperationRepository, now time.Time) *documentTrashService {
+	// Redis не используется: фоновая задача вызывается напрямую
+	client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	t.Cleanup(func() { client.Close() })
+	manager := jobs.NewManager(client, jobs.Config{Registerer: prometheus.NewRegistry()}, logrus.New())
+
+	docs := &trashDocumentService{repo: repo, now: now}
+	orgs := &stubOrganizationRepository{orgs: []*entity.EstOrganization{{ID: uuid.New()}}}
+	cfg := services.TrashConfig{Retention: 30 * 24 * time.Hour, AsyncThreshold: 3}
+	s := NewDocumentTrashService(repo, orgs, docs, NewOperationService(ops, logrus.New()), manager, cfg, logrus.New()).(*documentTrashService)
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestDocumentTrashService_BulkDeleteSkipsSentAndMissing(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	draft, rejected, sent, missing := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	repo := &memoryTrashRepository{docs: map[uuid.UUID]*entity.EsfDocument{
+		draft:    {ID: draft},
+		rejected: {ID: rejected, EsfStatus: entity.EsfStatusRejected},
+		sent:     {ID: sent, EsfStatus: entity.EsfStatusSent},
+	}}
+	s := newTestTrashService(t, repo, newMemoryOperationRepository(), now)
+
+	result, err := s.BulkDelete(context.Background(), services.BulkDocumentsRequest{
+		OrganizationID: uuid.New(),
+		IDs:            []uuid.UUID{draft, sent, draft, missing, rejected, uuid.Nil},
+		RequestedBy:    uuid.NewString(),
+	})
+	require.NoError(t, err)
+	assert.Nil(t, result.Operation)
+	assert.Equal(t, 2, result.Processed)
+	assert.Equal(t, []services.BulkSkipped{
+		{ID: sent, Code: string(apperror.ErrConflict), Reason: "document has ESF status " + entity.EsfStatusSent},
+		{ID: missing, Code: string(apperror.ErrDocumentNotFound), Reason: "document not found"},
+	}, result.Skipped)
+	assert.True(t, repo.docs[draft].DeletedAt.Valid)
+	assert.True(t, repo.docs[rejected].DeletedAt.Valid)
+	assert.False(t, repo.docs[sent].DeletedAt.Valid)
+
+	_, err = s.BulkDelete(context.Background(), services.BulkDocumentsRequest{OrganizationID: uuid.New()})
+	assertErrorCode(t, err, apperror.ErrValidation)
+}
+
+func TestDocumentTrashService_RestoreWithinRetention(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	recent, expired := uuid.New(), uuid.New()
+	repo := &memoryTrashRepository{docs: map[uuid.UUID]*entity.EsfDocument{
+		recent:  {ID: recent, DeletedAt: gorm.DeletedAt{Time: now.Add(-24 * time.Hour), Valid: true}},
+		expired: {ID: expired, DeletedAt: gorm.DeletedAt{Time: now.Add(-31 * 24 * time.Hour), Valid: true}},
+	}}
+	s := newTestTrashService(t, repo, newMemoryOperationRepository(), now)
+	orgID := uuid.New()
+
+	trash, total, err := s.ListTrash(context.Background(), orgID, pagination.PaginationParams{Page: 1, PageSize: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	require.Len(t, trash, 1)
+	assert.Equal(t, recent, trash[0].ID)
+	assert.Equal(t, now.Add(29*24*time.Hour), trash[0].PurgeAt)
+
+	result, err := s.BulkRestore(context.Background(), services.BulkDocumentsRequest{OrganizationID: orgID, IDs: []uuid.UUID{recent, expired}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Processed)
+	assert.Equal(t, []services.BulkSkipped{{ID: expired, Code: string(apperror.ErrConflict), Reason: "retention period expired"}}, result.Skipped)
+	assert.False(t, repo.docs[recent].DeletedAt.Valid)
+
+	purged, err := s.PurgeExpired(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+	assert.NotContains(t, repo.docs, expired)
+	assert.Contains(t, repo.docs, recent)
+}
+
+func TestDocumentTrashService_LargeBatchRunsAsOperation(t *testing.T) {
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	repo := &memoryTrashRepository{docs: map[uuid.UUID]*entity.EsfDocument{}}
+	var ids []uuid.UUID
+	for i := 0; i < 5; i++ {
+		id := uuid.New()
+		repo.docs[id] = &entity.EsfDocument{ID: id}
+		ids = append(ids, id)
+	}
+	ops := newMemoryOperationRepository()
+	s := newTestTrashService(t, repo, ops, now)
+	orgID := uuid.New()
+
+	// Очередь недоступна: операция завершается ошибкой, документы не трогаются
+	_, err := s.BulkDelete(context.Background(), services.BulkDocumentsRequest{OrganizationID: orgID, IDs: ids, RequestedBy: uuid.NewString()})
+	require.Error(t, err)
+	require.Len(t, ops.ops, 1)
+	for _, op := range ops.ops {
+		assert.Equal(t, services.OperationKindBulkDelete, op.Kind)
+		assert.Equal(t, entity.OperationStateFailed, op.State)
+	}
+	assert.False(t, repo.docs[ids[0]].DeletedAt.Valid)
+
+	// Задача обрабатывает документы и завершает операцию ссылкой на корзину
+	opID := uuid.New()
+	ops.ops[opID] = &entity.Operation{ID: opID, Kind: services.OperationKindBulkDelete, State: entity.OperationStatePending}
+	repo.docs[ids[1]].EsfStatus = entity.EsfStatusSent // отправлен после постановки задачи
+	payload, err := json.Marshal(documentsBulkPayload{OperationID: opID, OrganizationID: orgID, Action: trashActionDelete, IDs: ids})
+	require.NoError(t, err)
+
+	require.NoError(t, s.handleBulk(context.Background(), &jobs.Job{Type: DocumentsBulkJob, Payload: payload, MaxRetries: trashRetryPolicy.MaxRetries}))
+	op := ops.ops[opID]
+	assert.Equal(t, entity.OperationStateSucceeded, op.State)
+	assert.Equal(t, int64(5), op.Processed)
+	assert.Equal(t, TrashPath(orgID), op.ResultURL)
+	assert.True(t, repo.docs[ids[0]].DeletedAt.Valid)
+	assert.False(t, repo.docs[ids[1]].DeletedAt.Valid)
+}
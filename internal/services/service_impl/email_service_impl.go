@@ -0,0 +1,181 @@
+package service_impl
+
+import (
+	"context"
+	"errors"
+	netmail "net/mail"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/i18n"
+	"github.com/rusgainew/tunduck-app/pkg/jobs"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/mail"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+)
+
+// EmailSendJob тип фоновой задачи отправки письма
+const EmailSendJob = "email.send"
+
+// emailRetryPolicy SMTP-серверы часто отвечают временными ошибками, поэтому повторы растянуты на час
+var emailRetryPolicy = jobs.RetryPolicy{
+	MaxRetries: 6,
+	Backoff:    jobs.ExponentialBackoff(30*time.Second, 30*time.Minute),
+}
+
+// emailSendPayload полезная нагрузка задачи отправки
+type emailSendPayload struct {
+	DeliveryID uuid.UUID `json:"delivery_id"`
+	To         string    `json:"to"`
+	Subject    string    `json:"subject"`
+	HTML       string    `json:"html"`
+}
+
+// emailService реализует EmailService
+type emailService struct {
+	repo     repository.EmailDeliveryRepository
+	renderer *mail.Renderer
+	sender   mail.Sender
+	jobs     *jobs.Manager
+	logger   *logger.Logger
+}
+
+// NewEmailService создает сервис писем и регистрирует обработчик задачи EmailSendJob.
+// Менеджер задач должен быть запущен после вызова, чтобы воркеры знали обработчик.
+func NewEmailService(repo repository.EmailDeliveryRepository, renderer *mail.Renderer, sender mail.Sender, manager *jobs.Manager, log *logrus.Logger) services.EmailService {
+	s := &emailService{
+		repo:     repo,
+		renderer: renderer,
+		sender:   sender,
+		jobs:     manager,
+		logger:   logger.New(log),
+	}
+	manager.Register(EmailSendJob, s.handleSend, emailRetryPolicy)
+	return s
+}
+
+// Send формирует письмо и ставит задачу отправки
+func (s *emailService) Send(ctx context.Context, req services.EmailRequest) (*entity.EmailDelivery, error) {
+	if !s.renderer.Has(req.Template) {
+		return nil, apperror.ValidationError("unknown email template")
+	}
+	if _, err := netmail.ParseAddress(req.To); err != nil {
+		return nil, apperror.ValidationError("invalid email recipient")
+	}
+
+	lang := req.Language
+	if lang == "" {
+		lang = i18n.DefaultLanguage
+	}
+
+	subject, html, err := s.renderer.Render(req.Template, lang, req.Data)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to render email template", err, logrus.Fields{"template": req.Template})
+		return nil, apperror.From(err, apperror.ErrInternal, "failed to render email")
+	}
+
+	delivery := &entity.EmailDelivery{
+		Template:  req.Template,
+		Recipient: req.To,
+		Language:  string(lang),
+		Subject:   subject,
+		Status:    entity.EmailStatusQueued,
+	}
+	if err := s.repo.Create(ctx, delivery); err != nil {
+		return nil, err
+	}
+
+	job, err := s.jobs.Enqueue(ctx, EmailSendJob, emailSendPayload{
+		DeliveryID: delivery.ID,
+		To:         req.To,
+		Subject:    subject,
+		HTML:       html,
+	})
+	if err != nil {
+		s.logger.Error(ctx, "Failed to enqueue email", err, logrus.Fields{"delivery_id": delivery.ID.String()})
+		if updErr := s.repo.UpdateStatus(ctx, delivery.ID, entity.EmailStatusFailed, 0, err.Error()); updErr != nil {
+			s.logger.Warn(ctx, "Failed to mark email delivery as failed", logrus.Fields{"error": updErr.Error()})
+		}
+		return nil, apperror.From(err, apperror.ErrInternal, "failed to enqueue email")
+	}
+
+	delivery.JobID = job.ID
+	if err := s.repo.SetJobID(ctx, delivery.ID, job.ID); err != nil {
+		// Письмо уже в очереди; без идентификатора задачи теряется только связь в журнале
+		s.logger.Warn(ctx, "Failed to store email job id", logrus.Fields{"delivery_id": delivery.ID.String(), "error": err.Error()})
+	}
+
+	s.logger.Info(ctx, "Email queued", logrus.Fields{
+		"delivery_id": delivery.ID.String(),
+		"template":    req.Template,
+		"job_id":      job.ID,
+	})
+	return delivery, nil
+}
+
+// GetDelivery возвращает запись журнала доставки
+func (s *emailService) GetDelivery(ctx context.Context, id uuid.UUID) (*entity.EmailDelivery, error) {
+	delivery, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if delivery == nil {
+		return nil, apperror.NotFoundError("email delivery")
+	}
+	return delivery, nil
+}
+
+// ListDeliveries возвращает страницу журнала доставки
+func (s *emailService) ListDeliveries(ctx context.Context, params pagination.PaginationParams, status string) ([]entity.EmailDelivery, int64, error) {
+	switch status {
+	case "", entity.EmailStatusQueued, entity.EmailStatusSent, entity.EmailStatusFailed:
+	default:
+		return nil, 0, apperror.ValidationError("invalid email status")
+	}
+	return s.repo.List(ctx, params, status)
+}
+
+// handleSend отправляет письмо и записывает результат попытки. Ошибка отправки возвращается
+// менеджеру задач для повтора; на последней попытке доставка помечается как failed.
+func (s *emailService) handleSend(ctx context.Context, job *jobs.Job) error {
+	var payload emailSendPayload
+	if err := job.Decode(&payload); err != nil {
+		return jobs.Permanent(err)
+	}
+
+	attempts := job.Attempt + 1
+	sendErr := s.sender.Send(ctx, mail.Message{
+		To:      []string{payload.To},
+		Subject: payload.Subject,
+		HTML:    payload.HTML,
+	})
+
+	status := entity.EmailStatusSent
+	lastError := ""
+	if sendErr != nil {
+		lastError = sendErr.Error()
+		status = entity.EmailStatusQueued
+		if job.Attempt >= job.MaxRetries || jobs.IsPermanent(sendErr) {
+			status = entity.EmailStatusFailed
+		}
+	}
+
+	if err := s.repo.UpdateStatus(ctx, payload.DeliveryID, status, attempts, lastError); err != nil {
+		if sendErr == nil {
+			// Письмо уже отправлено: повтор задачи привел бы к дублю
+			s.logger.Warn(ctx, "Failed to record email delivery", logrus.Fields{
+				"delivery_id": payload.DeliveryID.String(),
+				"error":       err.Error(),
+			})
+			return nil
+		}
+		return errors.Join(sendErr, err)
+	}
+	return sendErr
+}
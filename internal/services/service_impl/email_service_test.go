@@ -0,0 +1,138 @@
+package service_impl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/jobs"
+	"github.com/rusgainew/tunduck-app/pkg/mail"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+)
+
+// stubEmailDeliveryRepository запоминает последнее обновление статуса
+type stubEmailDeliveryRepository struct {
+	repository.EmailDeliveryRepository
+	status    string
+	attempts  int
+	lastError string
+}
+
+func (s *stubEmailDeliveryRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status string, attempts int, lastError string) error {
+	s.status, s.attempts, s.lastError = status, attempts, lastError
+	return nil
+}
+
+func (s *stubEmailDeliveryRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.EmailDelivery, error) {
+	return nil, nil
+}
+
+func (s *stubEmailDeliveryRepository) List(ctx context.Context, params pagination.PaginationParams, status string) ([]entity.EmailDelivery, int64, error) {
+	return []entity.EmailDelivery{}, 0, nil
+}
+
+// fakeSender возвращает заданную ошибку
+type fakeSender struct {
+	err  error
+	sent []mail.Message
+}
+
+func (f *fakeSender) Send(ctx context.Context, msg mail.Message) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+func newTestEmailService(t *testing.T, repo repository.EmailDeliveryRepository, sender mail.Sender) *emailService {
+	renderer, err := mail.NewRenderer()
+	require.NoError(t, err)
+
+	// Redis не используется: тесты вызывают обработчик напрямую
+	client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	t.Cleanup(func() { client.Close() })
+	manager := jobs.NewManager(client, jobs.Config{Registerer: prometheus.NewRegistry()}, logrus.New())
+
+	return NewEmailService(repo, renderer, sender, manager, logrus.New()).(*emailService)
+}
+
+func sendJob(t *testing.T, attempt, maxRetries int) *jobs.Job {
+	payload, err := json.Marshal(emailSendPayload{
+		DeliveryID: uuid.New(),
+		To:         "user@example.kg",
+		Subject:    "Test",
+		HTML:       "<p>ok</p>",
+	})
+	require.NoError(t, err)
+	return &jobs.Job{Type: EmailSendJob, Payload: payload, Attempt: attempt, MaxRetries: maxRetries}
+}
+
+func TestEmailService_HandleSend(t *testing.T) {
+	tests := []struct {
+		name       string
+		sendErr    error
+		attempt    int
+		wantStatus string
+		wantErr    bool
+	}{
+		{name: "sent", wantStatus: entity.EmailStatusSent},
+		{name: "retry", sendErr: errors.New("421 try later"), attempt: 1, wantStatus: entity.EmailStatusQueued, wantErr: true},
+		{name: "last attempt", sendErr: errors.New("421 try later"), attempt: 3, wantStatus: entity.EmailStatusFailed, wantErr: true},
+		{name: "permanent", sendErr: jobs.Permanent(errors.New("550 no such user")), wantStatus: entity.EmailStatusFailed, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &stubEmailDeliveryRepository{}
+			svc := newTestEmailService(t, repo, &fakeSender{err: tt.sendErr})
+
+			err := svc.handleSend(context.Background(), sendJob(t, tt.attempt, 3))
+			assert.Equal(t, tt.wantErr, err != nil)
+			assert.Equal(t, tt.wantStatus, repo.status)
+			assert.Equal(t, tt.attempt+1, repo.attempts)
+			if tt.sendErr != nil {
+				assert.NotEmpty(t, repo.lastError)
+			}
+		})
+	}
+}
+
+func TestEmailService_SendValidation(t *testing.T) {
+	svc := newTestEmailService(t, &stubEmailDeliveryRepository{}, &fakeSender{})
+
+	_, err := svc.Send(context.Background(), services.EmailRequest{Template: "unknown", To: "user@example.kg"})
+	assertErrorCode(t, err, apperror.ErrValidation)
+
+	_, err = svc.Send(context.Background(), services.EmailRequest{Template: mail.TemplatePasswordReset, To: "not-an-email"})
+	assertErrorCode(t, err, apperror.ErrValidation)
+}
+
+func TestEmailService_GetDeliveryNotFound(t *testing.T) {
+	svc := newTestEmailService(t, &stubEmailDeliveryRepository{}, &fakeSender{})
+
+	_, err := svc.GetDelivery(context.Background(), uuid.New())
+	assertErrorCode(t, err, apperror.ErrNotFound)
+
+	_, _, err = svc.ListDeliveries(context.Background(), pagination.PaginationParams{}, "bogus")
+	assertErrorCode(t, err, apperror.ErrValidation)
+}
+
+func assertErrorCode(t *testing.T, err error, code apperror.ErrorCode) {
+	t.Helper()
+	var appErr *apperror.AppError
+	require.True(t, errors.As(err, &appErr), "expected AppError, got %v", err)
+	assert.Equal(t, code, appErr.Code)
+}
@@ -0,0 +1,105 @@
+package service_impl
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+)
+
+// applyAdjustments пересчитывает суммы позиций со скидкой или наценкой и итоги документа.
+// Скидка и наценка указываются в единицах цены: без налогов или с налогами по IsPriceWithoutTaxes.
+// Ставка НДС берется из кода ставки документа, ставка налога с продаж — из сумм позиции,
+// поэтому повторный пересчет уже пересчитанной позиции ее не меняет
+func applyAdjustments(req *models.EsfCreateDocumentRequest) error {
+	adjusted := false
+	for i := range req.CatalogEntries {
+		entry := &req.CatalogEntries[i]
+		if !entry.Adjusted() {
+			continue
+		}
+		adjusted = true
+
+		gross := roundAmount(entry.Price * entry.Quantity)
+		amount := roundAmount(gross - entry.DiscountAmount + entry.SurchargeAmount)
+		if amount < 0 {
+			return apperror.ValidationError("discount of line {line} exceeds its amount").WithParams(map[string]interface{}{"line": i + 1})
+		}
+		if err := recomputeEntry(req, i, amount); err != nil {
+			return err
+		}
+	}
+	if adjusted {
+		recomputeTotals(req)
+	}
+	return nil
+}
+
+// recomputeEntry пересчитывает налоги и итог позиции line по ее сумме amount
+// (без налогов или с налогами по IsPriceWithoutTaxes документа)
+func recomputeEntry(req *models.EsfCreateDocumentRequest, line int, amount float64) error {
+	entry := &req.CatalogEntries[line]
+	vatRate, err := vatRate(req.TaxRateVATCode)
+	if err != nil {
+		return err
+	}
+	salesTaxRate, err := salesTaxRate(entry, line)
+	if err != nil {
+		return err
+	}
+
+	if req.IsPriceWithoutTaxes {
+		entry.AmountWithoutTaxes = amount
+		entry.VatAmount = roundAmount(amount * vatRate)
+		entry.SalesTaxAmount = roundAmount(amount * salesTaxRate)
+		entry.TotalAmount = roundAmount(amount + entry.VatAmount + entry.SalesTaxAmount)
+		return nil
+	}
+	// Цена с налогами: итог позиции равен сумме, НДС получает остаток округления
+	entry.AmountWithoutTaxes = roundAmount(amount / (1 + vatRate + salesTaxRate))
+	entry.SalesTaxAmount = roundAmount(entry.AmountWithoutTaxes * salesTaxRate)
+	entry.VatAmount = roundAmount(amount - entry.AmountWithoutTaxes - entry.SalesTaxAmount)
+	entry.TotalAmount = amount
+	return nil
+}
+
+// recomputeTotals пересчитывает итоги документа по суммам позиций
+func recomputeTotals(req *models.EsfCreateDocumentRequest) {
+	var withoutTaxes, total float64
+	for _, entry := range req.CatalogEntries {
+		withoutTaxes += entry.AmountWithoutTaxes
+		total += entry.TotalAmount
+	}
+	req.TotalCurrencyValueWithoutTaxes = roundAmount(withoutTaxes)
+	req.TotalCurrencyValue = roundAmount(total)
+}
+
+// vatRate ставка НДС по коду справочника ставок (esfgateway.DirectoryVATRates):
+// код — ставка в процентах, "12" — 12%, "0" — без НДС
+func vatRate(code string) (float64, error) {
+	percent, err := strconv.ParseFloat(strings.TrimSpace(code), 64)
+	if err != nil || percent < 0 || percent >= 100 {
+		return 0, apperror.ValidationError("unknown VAT rate code {code}").WithParams(map[string]interface{}{"code": code})
+	}
+	return percent / 100, nil
+}
+
+// salesTaxRate ставка налога с продаж позиции. Справочника ставок налога с продаж нет, поэтому
+// ставка берется из сумм позиции; налог без суммы без налогов ставки не дает, и позиция отклоняется
+func salesTaxRate(entry *models.EsfEntriesModel, line int) (float64, error) {
+	if entry.SalesTaxAmount == 0 {
+		return 0, nil
+	}
+	if entry.AmountWithoutTaxes <= 0 {
+		return 0, apperror.ValidationError("line {line} has sales tax but no amount without taxes to derive its rate from").
+			WithParams(map[string]interface{}{"line": line + 1})
+	}
+	return entry.SalesTaxAmount / entry.AmountWithoutTaxes, nil
+}
+
+// roundAmount округляет сумму до копеек
+func roundAmount(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,85 @@
+package service_impl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+)
+
+func TestApplyAdjustments_PriceWithoutTaxes(t *testing.T) {
+	req := &models.EsfCreateDocumentRequest{
+		IsPriceWithoutTaxes: true,
+		TaxRateVATCode:      "12",
+		CatalogEntries: []models.EsfEntriesModel{
+			{Quantity: 10, Price: 100, AmountWithoutTaxes: 1000, VatAmount: 120, SalesTaxAmount: 20, TotalAmount: 1140, DiscountAmount: 150},
+			{Quantity: 1, Price: 50, AmountWithoutTaxes: 50, VatAmount: 6, TotalAmount: 56, SurchargeAmount: 10},
+			{Quantity: 2, Price: 10, AmountWithoutTaxes: 20, VatAmount: 2.4, TotalAmount: 22.4},
+		},
+	}
+	require.NoError(t, applyAdjustments(req))
+
+	first := req.CatalogEntries[0]
+	assert.Equal(t, 850.0, first.AmountWithoutTaxes)
+	assert.Equal(t, 102.0, first.VatAmount)
+	assert.Equal(t, 17.0, first.SalesTaxAmount)
+	assert.Equal(t, 969.0, first.TotalAmount)
+	assert.Equal(t, 67.2, req.CatalogEntries[1].TotalAmount)
+	assert.Equal(t, 22.4, req.CatalogEntries[2].TotalAmount, "lines without adjustments are kept")
+	assert.Equal(t, 930.0, req.TotalCurrencyValueWithoutTaxes)
+	assert.Equal(t, 1058.6, req.TotalCurrencyValue)
+
+	// Пересчет уже пересчитанного документа ничего не меняет
+	before := *req
+	before.CatalogEntries = append([]models.EsfEntriesModel(nil), req.CatalogEntries...)
+	require.NoError(t, applyAdjustments(req))
+	assert.Equal(t, before, *req)
+}
+
+func TestApplyAdjustments_PriceWithTaxes(t *testing.T) {
+	req := &models.EsfCreateDocumentRequest{
+		TaxRateVATCode: "12",
+		CatalogEntries: []models.EsfEntriesModel{
+			{Quantity: 1, Price: 112, AmountWithoutTaxes: 100, VatAmount: 12, TotalAmount: 112, DiscountAmount: 11.2},
+		},
+	}
+	require.NoError(t, applyAdjustments(req))
+
+	entry := req.CatalogEntries[0]
+	assert.Equal(t, 100.8, entry.TotalAmount)
+	assert.Equal(t, 90.0, entry.AmountWithoutTaxes)
+	assert.Equal(t, 10.8, entry.VatAmount)
+	assert.Equal(t, 100.8, req.TotalCurrencyValue)
+
+	req.CatalogEntries[0].DiscountAmount = 200
+	err := applyAdjustments(req)
+	assert.Equal(t, apperror.ErrValidation, err.(*apperror.AppError).Code)
+}
+
+func TestApplyAdjustments_RatesFromCodes(t *testing.T) {
+	// Клиент не заполнил суммы налогов: ставка НДС берется из кода, а не из нулевого VatAmount
+	req := &models.EsfCreateDocumentRequest{
+		IsPriceWithoutTaxes: true,
+		TaxRateVATCode:      "12",
+		CatalogEntries: []models.EsfEntriesModel{
+			{Quantity: 2, Price: 50, AmountWithoutTaxes: 100, TotalAmount: 100, DiscountAmount: 10},
+		},
+	}
+	require.NoError(t, applyAdjustments(req))
+	assert.Equal(t, 90.0, req.CatalogEntries[0].AmountWithoutTaxes)
+	assert.Equal(t, 10.8, req.CatalogEntries[0].VatAmount)
+	assert.Equal(t, 100.8, req.CatalogEntries[0].TotalAmount)
+
+	req.TaxRateVATCode = "standard"
+	err := applyAdjustments(req)
+	assert.Equal(t, apperror.ErrValidation, err.(*apperror.AppError).Code)
+
+	// Налог с продаж без суммы без налогов не дает ставки
+	req.TaxRateVATCode = "12"
+	req.CatalogEntries[0] = models.EsfEntriesModel{Quantity: 1, Price: 100, SalesTaxAmount: 2, TotalAmount: 114, SurchargeAmount: 5}
+	err = applyAdjustments(req)
+	assert.Equal(t, apperror.ErrValidation, err.(*apperror.AppError).Code)
+}
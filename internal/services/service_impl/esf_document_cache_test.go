@@ -0,0 +1,50 @@
+package service_impl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/cache"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// tenantDocumentRepository отдает документ только его организации, как БД организации
+type tenantDocumentRepository struct {
+	repository.EsfDocumentRepository
+	orgID uuid.UUID
+	doc   *entity.EsfDocument
+}
+
+func (m *tenantDocumentRepository) GetDocumentByID(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*entity.EsfDocument, error) {
+	if orgID != m.orgID || id != m.doc.ID {
+		return nil, apperror.New(apperror.ErrDocumentNotFound, "document not found")
+	}
+	return m.doc, nil
+}
+
+func TestEsfDocumentService_CachedDocumentStaysInOrganization(t *testing.T) {
+	owner, other := uuid.New(), uuid.New()
+	repo := &tenantDocumentRepository{orgID: owner, doc: &entity.EsfDocument{ID: uuid.New(), ContractorTin: "01234567890123"}}
+	svc := NewEsfDocumentService(repo, &gorm.DB{}, logrus.New())
+	svc.SetCacheManager(cache.NewMemoryCacheManager())
+	ctx := context.Background()
+
+	doc, err := svc.GetDocumentByID(ctx, owner, repo.doc.ID)
+	require.NoError(t, err)
+	assert.Equal(t, repo.doc.ContractorTin, doc.ContractorTin)
+
+	// Документ уже в кеше, но для другой организации его нет: 404, а не чужой документ или 500
+	doc, err = svc.GetDocumentByID(ctx, other, repo.doc.ID)
+	assert.Nil(t, doc)
+	var appErr *apperror.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, apperror.ErrDocumentNotFound, appErr.Code)
+}
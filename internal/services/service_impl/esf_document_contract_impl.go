@@ -0,0 +1,96 @@
+package service_impl
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// applyContract проверяет договор документа и заполняет из него номер и дату договора.
+// Договор должен принадлежать контрагенту документа; сроки, лимит и валюта договора
+// только порождают предупреждения
+func (s *esfDocumentService) applyContract(ctx context.Context, orgID uuid.UUID, docID uuid.UUID, req *models.EsfCreateDocumentRequest) ([]models.DocumentWarning, error) {
+	if req.ContractID == nil {
+		return nil, nil
+	}
+	contract, err := s.repo.GetContract(ctx, orgID, *req.ContractID)
+	if err != nil {
+		if errors.Is(err, apperror.New(apperror.ErrNotFound, "")) {
+			return nil, apperror.ValidationError("contract not found")
+		}
+		return nil, apperror.DatabaseErrorFrom("fetching contract", err)
+	}
+	if contract.ContractorTin != req.ContractorTin {
+		return nil, apperror.ValidationError("contract belongs to another contractor")
+	}
+	req.SupplyContractNumber = contract.Number
+	req.ContractStartDate = contract.StartDate
+
+	used, _, err := s.repo.ContractUsage(ctx, orgID, contract.ID, docID)
+	if err != nil {
+		return nil, apperror.DatabaseErrorFrom("fetching contract usage", err)
+	}
+	return contractWarnings(contract, req, used), nil
+}
+
+// DocumentWarnings возвращает предупреждения о сохраненном документе
+func (s *esfDocumentService) DocumentWarnings(ctx context.Context, orgID uuid.UUID, id uuid.UUID) ([]models.DocumentWarning, error) {
+	doc, err := s.repo.GetDocumentByID(ctx, orgID, id)
+	if err != nil {
+		return nil, apperror.DatabaseErrorFrom("fetching document", err)
+	}
+	model := s.toModel(doc)
+	lists, err := s.documentPriceLists(ctx, orgID, &model)
+	if err != nil {
+		return nil, err
+	}
+	if doc.ContractID == nil {
+		return floorWarnings(lists, model.CatalogEntries), nil
+	}
+	contract, err := s.repo.GetContract(ctx, orgID, *doc.ContractID)
+	if err != nil {
+		return nil, apperror.DatabaseErrorFrom("fetching contract", err)
+	}
+	used, _, err := s.repo.ContractUsage(ctx, orgID, contract.ID, doc.ID)
+	if err != nil {
+		return nil, apperror.DatabaseErrorFrom("fetching contract usage", err)
+	}
+	return append(contractWarnings(contract, &model, used), floorWarnings(lists, model.CatalogEntries)...), nil
+}
+
+// contractWarnings сравнивает документ с договором; used — сумма других документов по договору
+func contractWarnings(contract *entity.Contract, req *models.EsfCreateDocumentRequest, used float64) []models.DocumentWarning {
+	var warnings []models.DocumentWarning
+	if !contract.Started(req.DeliveryDate) {
+		warnings = append(warnings, models.DocumentWarning{
+			Code:    models.WarningContractNotStarted,
+			Message: fmt.Sprintf("contract %s starts on %s, after the delivery date", contract.Number, contract.StartDate.Format("2006-01-02")),
+		})
+	}
+	if contract.Expired(req.DeliveryDate) {
+		warnings = append(warnings, models.DocumentWarning{
+			Code:    models.WarningContractExpired,
+			Message: fmt.Sprintf("contract %s expired on %s", contract.Number, contract.EndDate.Format("2006-01-02")),
+		})
+	}
+	if contract.AmountLimit > 0 && used+req.TotalCurrencyValue > contract.AmountLimit+entity.PaymentTolerance {
+		warnings = append(warnings, models.DocumentWarning{
+			Code:    models.WarningContractLimitExceeded,
+			Message: fmt.Sprintf("contract %s limit %.2f is exceeded: %.2f with this document", contract.Number, contract.AmountLimit, used+req.TotalCurrencyValue),
+		})
+	}
+	if contract.CurrencyCode != "" && !strings.EqualFold(contract.CurrencyCode, req.CurrencyCode) {
+		warnings = append(warnings, models.DocumentWarning{
+			Code:    models.WarningContractCurrencyMismatch,
+			Message: fmt.Sprintf("contract %s is in %s, the document is in %s", contract.Number, contract.CurrencyCode, req.CurrencyCode),
+		})
+	}
+	return warnings
+}
@@ -0,0 +1,38 @@
+package service_impl
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/dates"
+)
+
+// SetTimezones включает проверку даты поставки по текущей дате в часовом поясе организации
+func (s *esfDocumentService) SetTimezones(timezones services.Timezones) {
+	s.timezones = timezones
+}
+
+// validateDeliveryDate отклоняет документ с датой поставки позже сегодняшней даты организации.
+// Сегодня определяется в ее часовом поясе: в 01:00 по Бишкеку документ текущего дня допустим,
+// хотя по UTC эта дата еще не наступила.
+func (s *esfDocumentService) validateDeliveryDate(ctx context.Context, orgID uuid.UUID, req *models.EsfCreateDocumentRequest) error {
+	if s.timezones == nil || req.DeliveryDate.IsZero() {
+		return nil
+	}
+
+	loc := s.timezones.Location(ctx, orgID)
+	today := dates.Today(loc)
+	if req.DeliveryDate.After(today.Time) {
+		return apperror.ValidationError("delivery date {date} is later than today ({today}) in time zone {timezone}").
+			WithParams(map[string]interface{}{
+				"date":     req.DeliveryDate.String(),
+				"today":    today.String(),
+				"timezone": loc.String(),
+			})
+	}
+	return nil
+}
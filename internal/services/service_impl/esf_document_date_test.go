@@ -0,0 +1,52 @@
+package service_impl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/dates"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// fixedTimezones один часовой пояс для всех организаций
+type fixedTimezones struct {
+	loc *time.Location
+}
+
+func (f fixedTimezones) Location(ctx context.Context, orgID uuid.UUID) *time.Location {
+	return f.loc
+}
+
+func TestEsfDocumentService_DeliveryDateInOrganizationTimezone(t *testing.T) {
+	// Между UTC+14 и UTC-11 всегда разные календарные дни
+	ahead, err := dates.LoadLocation("Pacific/Kiritimati")
+	require.NoError(t, err)
+	behind, err := dates.LoadLocation("Pacific/Pago_Pago")
+	require.NoError(t, err)
+
+	repo := &memoryPriceListRepository{docs: map[uuid.UUID]*entity.EsfDocument{}}
+	svc := NewEsfDocumentService(repo, &gorm.DB{}, logrus.New())
+	ctx, orgID := context.Background(), uuid.New()
+	today := dates.Today(ahead)
+
+	svc.SetTimezones(fixedTimezones{loc: ahead})
+	_, err = svc.CreateDocument(ctx, orgID, &models.EsfCreateDocumentRequest{CurrencyCode: "KGS", DeliveryDate: today})
+	require.NoError(t, err)
+
+	svc.SetTimezones(fixedTimezones{loc: behind})
+	_, err = svc.CreateDocument(ctx, orgID, &models.EsfCreateDocumentRequest{CurrencyCode: "KGS", DeliveryDate: today})
+	require.Error(t, err)
+	appErr := err.(*apperror.AppError)
+	assert.Equal(t, apperror.ErrValidation, appErr.Code)
+	assert.Equal(t, today.String(), appErr.Params["date"])
+	assert.Equal(t, "Pacific/Pago_Pago", appErr.Params["timezone"])
+}
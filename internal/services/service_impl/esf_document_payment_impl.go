@@ -0,0 +1,100 @@
+package service_impl
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// ListPayments возвращает оплаты документа и остаток к оплате
+func (s *esfDocumentService) ListPayments(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*services.DocumentPayments, error) {
+	return s.documentPayments(ctx, orgID, id)
+}
+
+// RecordPayment сохраняет поступление оплаты; оплаченная сумма документа увеличивается на сумму оплаты
+func (s *esfDocumentService) RecordPayment(ctx context.Context, orgID uuid.UUID, id uuid.UUID, createdBy string, req *models.PaymentRequest) (*services.DocumentPayments, error) {
+	if req.Amount <= 0 {
+		return nil, apperror.ValidationError("payment amount must be positive")
+	}
+	if !entity.IsValidPaymentMethod(req.Method) {
+		return nil, apperror.ValidationError("invalid payment method")
+	}
+
+	payment := &entity.Payment{
+		DocumentID: id,
+		PaidAt:     req.PaidAt,
+		Amount:     req.Amount,
+		Method:     req.Method,
+		Reference:  req.Reference,
+		Comment:    req.Comment,
+		CreatedBy:  createdBy,
+	}
+	if err := s.repo.AddPayment(ctx, orgID, payment); err != nil {
+		s.logger.Error(ctx, "Failed to record payment", err, logrus.Fields{"org_id": orgID.String(), "doc_id": id.String()})
+		return nil, apperror.DatabaseErrorFrom("recording payment", err)
+	}
+	s.invalidateDocument(ctx, orgID, id)
+
+	s.logger.Info(ctx, "Payment recorded", logrus.Fields{
+		"org_id":     orgID.String(),
+		"doc_id":     id.String(),
+		"payment_id": payment.ID.String(),
+		"amount":     payment.Amount,
+	})
+	return s.documentPayments(ctx, orgID, id)
+}
+
+// DeletePayment удаляет ошибочно внесенную оплату
+func (s *esfDocumentService) DeletePayment(ctx context.Context, orgID uuid.UUID, id uuid.UUID, paymentID uuid.UUID) (*services.DocumentPayments, error) {
+	if err := s.repo.DeletePayment(ctx, orgID, id, paymentID); err != nil {
+		s.logger.Error(ctx, "Failed to delete payment", err, logrus.Fields{"org_id": orgID.String(), "doc_id": id.String()})
+		return nil, apperror.DatabaseErrorFrom("deleting payment", err)
+	}
+	s.invalidateDocument(ctx, orgID, id)
+
+	s.logger.Info(ctx, "Payment deleted", logrus.Fields{"org_id": orgID.String(), "doc_id": id.String(), "payment_id": paymentID.String()})
+	return s.documentPayments(ctx, orgID, id)
+}
+
+// documentPayments читает документ из репозитория (не из кеша), чтобы остаток был актуальным
+func (s *esfDocumentService) documentPayments(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*services.DocumentPayments, error) {
+	doc, err := s.repo.GetDocumentByID(ctx, orgID, id)
+	if err != nil {
+		return nil, apperror.DatabaseErrorFrom("fetching document", err)
+	}
+	if doc == nil {
+		return nil, apperror.New(apperror.ErrDocumentNotFound, "document not found")
+	}
+
+	payments, err := s.repo.ListPayments(ctx, orgID, id)
+	if err != nil {
+		return nil, apperror.DatabaseErrorFrom("fetching payments", err)
+	}
+	if payments == nil {
+		payments = []entity.Payment{}
+	}
+
+	return &services.DocumentPayments{
+		DocumentID:      doc.ID,
+		AmountDue:       doc.AmountDue(),
+		PaidAmount:      doc.PaidAmount,
+		RemainingAmount: doc.RemainingAmount(),
+		DueDate:         doc.DueDate,
+		Status:          doc.PaymentStatus(time.Now()),
+		Payments:        payments,
+	}, nil
+}
+
+// invalidateDocument удаляет документ из кеша после изменения оплаты
+func (s *esfDocumentService) invalidateDocument(ctx context.Context, orgID, id uuid.UUID) {
+	if s.cacheManager != nil {
+		_ = s.cacheManager.Document().Delete(ctx, documentCacheKey(orgID, id))
+	}
+}
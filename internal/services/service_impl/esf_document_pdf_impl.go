@@ -0,0 +1,88 @@
+package service_impl
+
+import (
+	"context"
+	"io"
+	"strconv"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/report"
+)
+
+// WriteDocumentPDF пишет печатную форму документа. Наименования покупателя и товаров
+// выбираются на языке документа
+func (s *esfDocumentService) WriteDocumentPDF(ctx context.Context, orgID uuid.UUID, id uuid.UUID, w io.Writer) error {
+	doc, err := s.GetDocumentByID(ctx, orgID, id)
+	if err != nil {
+		return err
+	}
+	return report.WritePDF(w, documentTable(doc))
+}
+
+// documentLanguage язык печатной формы; по умолчанию русский
+func documentLanguage(doc *models.EsfCreateDocumentRequest) string {
+	if doc.Language == "" {
+		return entity.LanguageRussian
+	}
+	return doc.Language
+}
+
+// documentTable строит таблицу позиций документа
+func documentTable(doc *models.EsfCreateDocumentRequest) *report.Table {
+	lang := documentLanguage(doc)
+
+	contractor := "TIN " + doc.ContractorTin
+	if name := doc.ContractorNames.Pick(lang, doc.ForeignName); name != "" {
+		contractor = name + " (" + contractor + ")"
+	}
+
+	table := &report.Table{
+		Title: "Invoice " + doc.ID.String(),
+		Subtitle: "Contractor: " + contractor + "   Delivery date: " + doc.DeliveryDate.Format(reportDateLayout) +
+			"   Currency: " + doc.CurrencyCode,
+		Columns: []report.Column{
+			{Title: "No", Width: 0.4},
+			{Title: "Item", Width: 3},
+			{Title: "Code"},
+			{Title: "Unit", Width: 0.6},
+			{Title: "Quantity", Numeric: true},
+			{Title: "Price", Numeric: true},
+			{Title: "Discounts", Numeric: true},
+			{Title: "Without taxes", Numeric: true},
+			{Title: "VAT", Numeric: true},
+			{Title: "Sales tax", Numeric: true},
+			{Title: "Total", Numeric: true},
+		},
+	}
+
+	var discounts, vat, salesTax float64
+	for i, e := range doc.CatalogEntries {
+		name := e.Names.Pick(lang, e.ForeignName)
+		if name == "" {
+			name = e.SalesTaxCode
+		}
+		table.AddRow(
+			strconv.Itoa(i+1),
+			name,
+			e.SalesTaxCode,
+			e.UnitClassificationCode,
+			strconv.FormatFloat(e.Quantity, 'f', -1, 64),
+			report.Amount(e.Price),
+			report.Amount(e.DiscountAmount-e.SurchargeAmount),
+			report.Amount(e.AmountWithoutTaxes),
+			report.Amount(e.VatAmount),
+			report.Amount(e.SalesTaxAmount),
+			report.Amount(e.TotalAmount),
+		)
+		discounts += e.DiscountAmount - e.SurchargeAmount
+		vat += e.VatAmount
+		salesTax += e.SalesTaxAmount
+	}
+	table.Totals = []string{"Total", "", "", "", "", "",
+		report.Amount(discounts), report.Amount(doc.TotalCurrencyValueWithoutTaxes), report.Amount(vat),
+		report.Amount(salesTax), report.Amount(doc.TotalCurrencyValue)}
+	return table
+}
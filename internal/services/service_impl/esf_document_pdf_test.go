@@ -0,0 +1,82 @@
+package service_impl
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/report"
+	"github.com/rusgainew/tunduck-app/pkg/testutil"
+	"github.com/rusgainew/tunduck-app/pkg/validation"
+)
+
+func localizedDocument(lang string) *models.EsfCreateDocumentRequest {
+	return &models.EsfCreateDocumentRequest{
+		ContractorTin:   "01234567890123",
+		ForeignName:     "Acme Trading LLC",
+		ContractorNames: entity.LocalizedNames{"ru": "ОсОО Акме", "ky": "Акме ЖЧК"},
+		Language:        lang,
+		CurrencyCode:    "KGS",
+		DeliveryDate:    calendarDate("2026-03-01"),
+		CatalogEntries: []models.EsfEntriesModel{
+			{SalesTaxCode: "1001", UnitClassificationCode: "796", Quantity: 2, Price: 50, AmountWithoutTaxes: 100, TotalAmount: 100,
+				ForeignName: "Flour", Names: entity.LocalizedNames{"ru": "Мука", "en": "Wheat flour"}},
+			{SalesTaxCode: "1002", UnitClassificationCode: "796", Quantity: 1, Price: 10, AmountWithoutTaxes: 10, TotalAmount: 10},
+		},
+	}
+}
+
+func TestDocumentTable_LocalizedNames(t *testing.T) {
+	table := documentTable(localizedDocument(""))
+	assert.Contains(t, table.Subtitle, "ОсОО Акме (TIN 01234567890123)", "Russian by default")
+	assert.Equal(t, "Мука", table.Rows[0][1])
+	assert.Equal(t, "1002", table.Rows[1][1], "an item without names falls back to its code")
+
+	table = documentTable(localizedDocument(entity.LanguageKyrgyz))
+	assert.Contains(t, table.Subtitle, "Акме ЖЧК")
+	assert.Equal(t, "Flour", table.Rows[0][1], "the foreign name is used when there is no Kyrgyz name")
+
+	table = documentTable(localizedDocument(entity.LanguageEnglish))
+	assert.Contains(t, table.Subtitle, "Acme Trading LLC")
+	assert.Equal(t, "Wheat flour", table.Rows[0][1])
+	require.Len(t, table.Totals, len(table.Columns))
+}
+
+func TestDocumentPDF_RendersCyrillic(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, report.WritePDF(&buf, documentTable(localizedDocument(entity.LanguageKyrgyz))))
+	require.True(t, bytes.HasPrefix(buf.Bytes(), []byte("%PDF-")))
+
+	pdf := buf.String()
+	assert.Contains(t, pdf, "/FontFile2", "the Unicode font is embedded")
+	content := testutil.PDFContent(t, buf.Bytes())
+	assert.Contains(t, content, testutil.PDFText("Акме ЖЧК"), "names are printed in Cyrillic, not transliterated")
+	assert.NotContains(t, content, testutil.PDFText("Akme"))
+	assert.Contains(t, content, testutil.PDFText("Flour"))
+}
+
+func TestLocalizedNames_Validation(t *testing.T) {
+	invalidFields := func(doc *models.EsfCreateDocumentRequest) []string {
+		var fields []string
+		if err := validation.Struct(doc); err != nil {
+			for _, f := range err.Fields {
+				fields = append(fields, f.Field)
+			}
+		}
+		return fields
+	}
+
+	assert.NotContains(t, invalidFields(localizedDocument(entity.LanguageKyrgyz)), "language")
+	assert.Contains(t, invalidFields(localizedDocument("de")), "language")
+
+	doc := localizedDocument(entity.LanguageKyrgyz)
+	doc.ContractorNames["fr"] = "Acme"
+	doc.CatalogEntries[0].Names["de"] = "Mehl"
+	fields := invalidFields(doc)
+	assert.Contains(t, fields, "contractorNames[fr]", "only ru, ky and en names are accepted")
+	assert.Contains(t, fields, "catalogEntries[0].names[de]")
+}
@@ -0,0 +1,82 @@
+package service_impl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+)
+
+// applyPrices заполняет позиции без цены ценами из прайс-листов контрагента, пересчитывает
+// их суммы и итоги документа и проверяет минимальные цены. Позиция без цены, которой нет
+// в прайс-листах, отклоняется. Продажа ниже минимальной цены доступна только с разрешением
+// PermissionOverridePrice и порождает предупреждение
+func (s *esfDocumentService) applyPrices(ctx context.Context, orgID uuid.UUID, req *models.EsfCreateDocumentRequest) ([]models.DocumentWarning, error) {
+	lists, err := s.documentPriceLists(ctx, orgID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	filled := false
+	for i := range req.CatalogEntries {
+		entry := &req.CatalogEntries[i]
+		if entry.Price != 0 {
+			continue
+		}
+		item := listPrice(lists, entry.SalesTaxCode, entry.UnitClassificationCode)
+		if item == nil {
+			return nil, apperror.ValidationError("line {line} has no price and no price list price for {code}").
+				WithParams(map[string]interface{}{"line": i + 1, "code": entry.SalesTaxCode})
+		}
+		entry.Price = item.Price
+		if err := recomputeEntry(req, i, roundAmount(entry.Price*entry.Quantity)); err != nil {
+			return nil, err
+		}
+		filled = true
+	}
+	if filled {
+		recomputeTotals(req)
+	}
+
+	warnings := floorWarnings(lists, req.CatalogEntries)
+	if len(warnings) > 0 && !rbac.FromContext(ctx).HasPermission(rbac.PermissionOverridePrice) {
+		return nil, apperror.ForbiddenError("selling below the floor price requires the override:price permission").WithDetails(warnings[0].Message)
+	}
+	return warnings, nil
+}
+
+// documentPriceLists находит прайс-листы контрагента документа на дату поставки
+func (s *esfDocumentService) documentPriceLists(ctx context.Context, orgID uuid.UUID, req *models.EsfCreateDocumentRequest) ([]entity.PriceList, error) {
+	if len(req.CatalogEntries) == 0 {
+		return nil, nil
+	}
+	lists, err := s.repo.FindPriceLists(ctx, orgID, req.ContractorTin, strings.ToUpper(req.CurrencyCode), req.DeliveryDate.Time)
+	if err != nil {
+		return nil, apperror.DatabaseErrorFrom("finding price lists", err)
+	}
+	return lists, nil
+}
+
+// floorWarnings сравнивает цены позиций с минимальными ценами прайс-листов
+func floorWarnings(lists []entity.PriceList, entries []models.EsfEntriesModel) []models.DocumentWarning {
+	var warnings []models.DocumentWarning
+	for _, entry := range entries {
+		item := listPrice(lists, entry.SalesTaxCode, entry.UnitClassificationCode)
+		// Скидка позиции тоже снижает цену продажи
+		price := entry.NetPrice()
+		if item == nil || item.FloorPrice == 0 || price >= item.FloorPrice-entity.PaymentTolerance {
+			continue
+		}
+		warnings = append(warnings, models.DocumentWarning{
+			Code:    models.WarningPriceBelowFloor,
+			Message: fmt.Sprintf("price %.2f of %s is below the floor price %.2f", price, entry.SalesTaxCode, item.FloorPrice),
+		})
+	}
+	return warnings
+}
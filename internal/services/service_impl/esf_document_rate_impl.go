@@ -0,0 +1,37 @@
+package service_impl
+
+import (
+	"context"
+	"math"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+)
+
+// SetExchangeRates включает подстановку курса валюты в документы
+func (s *esfDocumentService) SetExchangeRates(rates services.ExchangeRateService) {
+	s.rates = rates
+}
+
+// applyExchangeRate подставляет официальный курс валюты на дату поставки, если курс не указан.
+// Для документа за прошлую дату берется курс того дня, а не текущий
+func (s *esfDocumentService) applyExchangeRate(ctx context.Context, req *models.EsfCreateDocumentRequest) error {
+	if s.rates == nil || req.CurrencyRate != 0 || req.CurrencyCode == "" || req.DeliveryDate.IsZero() {
+		return nil
+	}
+
+	rate, err := s.rates.Rate(ctx, req.CurrencyCode, req.DeliveryDate.Time)
+	if err != nil {
+		return err
+	}
+	if rate == nil {
+		return apperror.ValidationError("no exchange rate for {currency} on {date}").WithParams(map[string]interface{}{
+			"currency": req.CurrencyCode,
+			"date":     req.DeliveryDate.Format(reportDateLayout),
+		})
+	}
+	// Курс документа хранится с точностью до 4 знаков
+	req.CurrencyRate = math.Round(rate.Rate*10000) / 10000
+	return nil
+}
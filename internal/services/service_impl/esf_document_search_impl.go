@@ -0,0 +1,120 @@
+package service_impl
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	models "github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/pkg/cache"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+	"github.com/rusgainew/tunduck-app/pkg/search"
+)
+
+// SetSearchClient injects the OpenSearch client into the service
+func (s *esfDocumentService) SetSearchClient(client *search.Client) {
+	s.searchClient = client
+}
+
+// SearchDocuments ищет документы в OpenSearch, если индекс включен для организации.
+// При ошибке OpenSearch и без него используется полнотекстовый поиск Postgres.
+func (s *esfDocumentService) SearchDocuments(ctx context.Context, orgID uuid.UUID, text string, params pagination.PaginationParams) ([]models.EsfCreateDocumentRequest, int64, error) {
+	s.logger.Info(ctx, "Searching documents", logrus.Fields{
+		"org_id":    orgID.String(),
+		"page":      params.Page,
+		"page_size": params.PageSize,
+	})
+
+	useIndex := s.searchClient != nil && s.searchClient.Enabled(orgID)
+	if useIndex {
+		result, total, err := s.searchIndex(ctx, orgID, text, params)
+		if err == nil {
+			return result, total, nil
+		}
+		s.logger.Warn(ctx, "OpenSearch query failed, falling back to Postgres full-text search", logrus.Fields{
+			"org_id": orgID.String(),
+			"error":  err.Error(),
+		})
+	}
+
+	docs, total, err := s.repo.SearchDocuments(ctx, orgID, text, params)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to search documents", err, logrus.Fields{"org_id": orgID.String()})
+		return nil, 0, err
+	}
+
+	result := make([]models.EsfCreateDocumentRequest, len(docs))
+	for i := range docs {
+		result[i] = s.toModel(&docs[i])
+	}
+
+	return result, total, nil
+}
+
+// searchIndex получает идентификаторы из OpenSearch и возвращает документы в порядке релевантности.
+// Документы из кеша читаются одним MGET, из БД организации загружаются только промахи.
+func (s *esfDocumentService) searchIndex(ctx context.Context, orgID uuid.UUID, text string, params pagination.PaginationParams) ([]models.EsfCreateDocumentRequest, int64, error) {
+	hits, err := s.searchClient.Search(ctx, orgID, text, params.GetOffset(), params.GetLimit())
+	if err != nil {
+		return nil, 0, err
+	}
+
+	byID := s.cachedDocuments(ctx, orgID, hits.IDs)
+	missing := make([]uuid.UUID, 0, len(hits.IDs))
+	for _, id := range hits.IDs {
+		if _, ok := byID[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+
+	if len(missing) > 0 {
+		found, err := s.repo.GetDocumentsByIDs(ctx, orgID, missing)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		batchData := make(map[string]interface{}, len(found))
+		for i := range found {
+			model := s.toModel(&found[i])
+			byID[found[i].ID] = model
+			batchData[documentCacheKey(orgID, found[i].ID)] = &model
+		}
+		if s.cacheManager != nil && len(batchData) > 0 {
+			_ = s.cacheManager.Document().SetMultiple(ctx, batchData, 30*time.Minute)
+		}
+	}
+
+	// Документ мог быть удален после индексации: такие попадания пропускаются
+	docs := make([]models.EsfCreateDocumentRequest, 0, len(hits.IDs))
+	for _, id := range hits.IDs {
+		if doc, ok := byID[id]; ok {
+			docs = append(docs, doc)
+		}
+	}
+	return docs, hits.Total, nil
+}
+
+// cachedDocuments читает закешированные документы одним запросом; ошибка кеша означает промах по всем
+func (s *esfDocumentService) cachedDocuments(ctx context.Context, orgID uuid.UUID, ids []uuid.UUID) map[uuid.UUID]models.EsfCreateDocumentRequest {
+	byID := make(map[uuid.UUID]models.EsfCreateDocumentRequest, len(ids))
+	if s.cacheManager == nil || len(ids) == 0 {
+		return byID
+	}
+
+	keys := make([]string, len(ids))
+	for i, id := range ids {
+		keys[i] = documentCacheKey(orgID, id)
+	}
+	cached, err := cache.GetMany[models.EsfCreateDocumentRequest](ctx, s.cacheManager.Document(), keys)
+	if err != nil {
+		return byID
+	}
+	for i, id := range ids {
+		if doc, ok := cached[keys[i]]; ok {
+			byID[id] = doc
+		}
+	}
+	return byID
+}
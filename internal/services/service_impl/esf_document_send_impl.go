@@ -0,0 +1,111 @@
+package service_impl
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/audit"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/esfgateway"
+	"github.com/rusgainew/tunduck-app/pkg/metering"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+)
+
+// sentDocument состояние документа после отправки для журнала аудита: кто отправил
+// и, при отправке по делегированию, от чьего имени
+type sentDocument struct {
+	EsfStatus    string     `json:"esfStatus"`
+	DocumentUuid string     `json:"documentUuid"`
+	SentBy       *uuid.UUID `json:"sentBy,omitempty"`
+	OnBehalfOf   *uuid.UUID `json:"onBehalfOf,omitempty"`
+	DelegationID *uuid.UUID `json:"delegationId,omitempty"`
+}
+
+// SetGateway подключает шлюз ЭСФ и организации, токенами которых подписываются документы
+func (s *esfDocumentService) SetGateway(gw esfgateway.Gateway, orgs repository.EsfOrganizationRepository) {
+	s.gateway, s.orgs = gw, orgs
+}
+
+// SetMeter включает учет отправленных документов
+func (s *esfDocumentService) SetMeter(meter *metering.Meter) {
+	s.meter = meter
+}
+
+// SendDocument отправляет документ в ЭСФ токеном организации и переводит его в статус sent.
+// Право подписи проверяется маршрутом (rbac.RequireAuthority); подтвержденное право
+// из контекста попадает в журнал аудита
+func (s *esfDocumentService) SendDocument(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*models.EsfCreateDocumentResponse, error) {
+	if s.gateway == nil || s.orgs == nil {
+		return nil, apperror.New(apperror.ErrExternalService, "ESF gateway is not configured")
+	}
+
+	doc, err := s.repo.GetDocumentByID(ctx, orgID, id)
+	if err != nil {
+		return nil, apperror.DatabaseErrorFrom("fetching document", err)
+	}
+	if doc == nil {
+		return nil, apperror.New(apperror.ErrDocumentNotFound, "document not found")
+	}
+	if !entity.IsEsfDocumentEditable(doc.EsfStatus) {
+		return nil, apperror.New(apperror.ErrConflict, "document has ESF status "+doc.EsfStatus+" and can not be sent again")
+	}
+	org, err := s.orgs.GetByID(ctx, orgID.String())
+	if err != nil {
+		return nil, apperror.DatabaseErrorFrom("fetching organization", err)
+	}
+	if org == nil {
+		return nil, apperror.New(apperror.ErrOrgNotFound, "organization not found")
+	}
+
+	model := s.toModel(doc)
+	resp, err := s.gateway.CreateInvoice(ctx, org.Token, &model)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to send document to ESF", err, logrus.Fields{"org_id": orgID.String(), "doc_id": id.String()})
+		return nil, gatewayError(err)
+	}
+
+	if err := s.repo.UpdateDocumentStatus(ctx, orgID, id, entity.EsfStatusSent); err != nil {
+		s.logger.Error(ctx, "Failed to store sent status", err, logrus.Fields{"org_id": orgID.String(), "doc_id": id.String()})
+		return nil, err
+	}
+	s.invalidateDocumentCache(ctx, orgID, id)
+	s.forgetDocument(ctx, orgID, id)
+	s.meter.Add(orgID, metering.MetricDocumentsSent, 1)
+
+	after := sentDocument{EsfStatus: entity.EsfStatusSent, DocumentUuid: resp.DocumentUuid}
+	fields := logrus.Fields{"org_id": orgID.String(), "doc_id": id.String(), "esf_uuid": resp.DocumentUuid}
+	if grant := rbac.GrantFromContext(ctx); grant != nil {
+		after.SentBy, after.OnBehalfOf, after.DelegationID = &grant.UserID, grant.DelegatorID, grant.DelegationID
+		fields["sent_by"] = grant.UserID.String()
+		if grant.Delegated() {
+			fields["on_behalf_of"] = grant.DelegatorID.String()
+		}
+	}
+	entry := audit.FromContext(ctx)
+	entry.SetEntity(auditEntityDocument, id.String())
+	entry.SetOrganization(orgID.String())
+	entry.SetAction("send")
+	entry.SetChange(map[string]string{"esfStatus": doc.EsfStatus}, after)
+
+	s.logger.Info(ctx, "Document sent to ESF", fields)
+	return resp, nil
+}
+
+// gatewayError переводит ошибку шлюза в ответ API: отклонение — ошибка данных документа,
+// остальное — сбой внешнего сервиса
+func gatewayError(err error) *apperror.AppError {
+	var rejected *esfgateway.RejectedError
+	if errors.As(err, &rejected) {
+		return apperror.ValidationError("ESF gateway rejected the document").WithDetails(rejected.Message)
+	}
+	if errors.Is(err, esfgateway.ErrUnauthorized) {
+		return apperror.New(apperror.ErrExternalService, "ESF gateway rejected the organization token").WithError(err)
+	}
+	return apperror.New(apperror.ErrExternalService, "failed to send document to ESF").WithError(err)
+}
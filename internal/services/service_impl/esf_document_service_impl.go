@@ -9,10 +9,14 @@ import (
 	"github.com/rusgainew/tunduck-app/internal/repository"
 	"github.com/rusgainew/tunduck-app/internal/services"
 	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/audit"
 	"github.com/rusgainew/tunduck-app/pkg/cache"
 	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/esfgateway"
 	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/metering"
 	"github.com/rusgainew/tunduck-app/pkg/pagination"
+	"github.com/rusgainew/tunduck-app/pkg/search"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
@@ -22,6 +26,13 @@ type esfDocumentService struct {
 	db           *gorm.DB
 	logger       *logger.Logger
 	cacheManager cache.CacheManager
+	searchClient *search.Client
+	rates        services.ExchangeRateService
+	gateway      esfgateway.Gateway
+	orgs         repository.EsfOrganizationRepository
+	meter        *metering.Meter
+	timezones    services.Timezones
+	reminders    services.DraftReminders
 }
 
 // NewEsfDocumentService создает новый document service с обязательными зависимостями
@@ -38,6 +49,11 @@ func NewEsfDocumentService(repo repository.EsfDocumentRepository, db *gorm.DB, l
 	}
 }
 
+// SetDraftReminders подключает напоминания о неотправленных черновиках
+func (s *esfDocumentService) SetDraftReminders(reminders services.DraftReminders) {
+	s.reminders = reminders
+}
+
 // SetCacheManager injects the cache manager into the service
 func (s *esfDocumentService) SetCacheManager(cacheManager cache.CacheManager) {
 	s.cacheManager = cacheManager
@@ -66,18 +82,20 @@ func (s *esfDocumentService) GetDocumentByID(ctx context.Context, orgID uuid.UUI
 
 	// Try to get from cache first
 	if s.cacheManager != nil {
-		cacheKey := "doc:id:" + id.String()
+		cacheKey := documentCacheKey(orgID, id)
 		if cached, _ := s.cacheManager.Document().Get(ctx, cacheKey); cached != nil {
-			s.logger.Debug(ctx, "Document found in cache", logrus.Fields{"doc_id": id.String()})
-			doc := cached.(*models.EsfCreateDocumentRequest)
-			return doc, nil
+			var doc models.EsfCreateDocumentRequest
+			if err := cache.Decode(cached, &doc); err == nil {
+				s.logger.Debug(ctx, "Document found in cache", logrus.Fields{"doc_id": id.String()})
+				return &doc, nil
+			}
 		}
 	}
 
 	doc, err := s.repo.GetDocumentByID(ctx, orgID, id)
 	if err != nil {
 		s.logger.Error(ctx, "Failed to fetch document", err, logrus.Fields{"org_id": orgID.String(), "doc_id": id.String()})
-		return nil, apperror.DatabaseError("fetching document", err)
+		return nil, apperror.DatabaseErrorFrom("fetching document", err)
 	}
 
 	if doc == nil {
@@ -91,7 +109,7 @@ func (s *esfDocumentService) GetDocumentByID(ctx context.Context, orgID uuid.UUI
 
 	// Cache the document (30 minutes TTL)
 	if s.cacheManager != nil {
-		cacheKey := "doc:id:" + id.String()
+		cacheKey := documentCacheKey(orgID, id)
 		_ = s.cacheManager.Document().Set(ctx, cacheKey, &model, 30*time.Minute)
 	}
 
@@ -101,38 +119,88 @@ func (s *esfDocumentService) GetDocumentByID(ctx context.Context, orgID uuid.UUI
 func (s *esfDocumentService) CreateDocument(ctx context.Context, orgID uuid.UUID, req *models.EsfCreateDocumentRequest) (*models.EsfCreateDocumentResponse, error) {
 	s.logger.Info(ctx, "Creating new document", logrus.Fields{"org_id": orgID.String()})
 
+	docID := uuid.New()
+	if err := s.validateDeliveryDate(ctx, orgID, req); err != nil {
+		return nil, err
+	}
+	if err := s.applyExchangeRate(ctx, req); err != nil {
+		return nil, err
+	}
+	priceWarnings, err := s.applyPrices(ctx, orgID, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyAdjustments(req); err != nil {
+		return nil, err
+	}
+	warnings, err := s.applyContract(ctx, orgID, docID, req)
+	if err != nil {
+		return nil, err
+	}
+	warnings = append(warnings, priceWarnings...)
+
 	doc := s.toEntity(req)
-	doc.ID = uuid.New()
+	doc.ID = docID
 
 	if err := s.repo.CreateDocument(ctx, orgID, &doc); err != nil {
 		s.logger.Error(ctx, "Failed to create document", err, logrus.Fields{"org_id": orgID.String(), "doc_id": doc.ID.String()})
 		return nil, apperror.DatabaseError("creating document", err)
 	}
 
+	entry := audit.FromContext(ctx)
+	entry.SetEntity(auditEntityDocument, doc.ID.String())
+	entry.SetOrganization(orgID.String())
+	entry.SetChange(nil, req)
+
+	s.remindDraft(ctx, orgID, doc.ID)
+
 	s.logger.Info(ctx, "Document created successfully", logrus.Fields{"org_id": orgID.String(), "doc_id": doc.ID.String()})
 
 	return &models.EsfCreateDocumentResponse{
 		ResponseId:   "success",
 		DocumentUuid: doc.ID.String(),
+		Warnings:     warnings,
 	}, nil
 }
 
 func (s *esfDocumentService) UpdateDocument(ctx context.Context, orgID uuid.UUID, req *models.EsfEditDocumentRequest) error {
 	s.logger.Info(ctx, "Updating document", logrus.Fields{"org_id": orgID.String(), "doc_id": req.ID.String()})
 
+	if err := s.validateDeliveryDate(ctx, orgID, &req.EsfCreateDocumentRequest); err != nil {
+		return err
+	}
+	if err := s.applyExchangeRate(ctx, &req.EsfCreateDocumentRequest); err != nil {
+		return err
+	}
+	if _, err := s.applyPrices(ctx, orgID, &req.EsfCreateDocumentRequest); err != nil {
+		return err
+	}
+	if err := applyAdjustments(&req.EsfCreateDocumentRequest); err != nil {
+		return err
+	}
+	if _, err := s.applyContract(ctx, orgID, req.ID, &req.EsfCreateDocumentRequest); err != nil {
+		return err
+	}
+
 	doc := s.toEntity(&req.EsfCreateDocumentRequest)
 	doc.ID = req.ID
+	doc.Version = req.Version
+
+	entry := audit.FromContext(ctx)
+	before := s.auditSnapshot(ctx, entry, orgID, req.ID)
 
 	if err := s.repo.UpdateDocument(ctx, orgID, &doc); err != nil {
 		s.logger.Error(ctx, "Failed to update document", err, logrus.Fields{"org_id": orgID.String(), "doc_id": req.ID.String()})
-		return apperror.DatabaseError("updating document", err)
+		return apperror.DatabaseErrorFrom("updating document", err)
 	}
+	entry.SetChange(before, &req.EsfCreateDocumentRequest)
 
 	// Invalidate cache
 	if s.cacheManager != nil {
-		cacheKey := "doc:id:" + req.ID.String()
+		cacheKey := documentCacheKey(orgID, req.ID)
 		_ = s.cacheManager.Document().Delete(ctx, cacheKey)
 	}
+	s.remindDraft(ctx, orgID, req.ID)
 
 	s.logger.Info(ctx, "Document updated successfully", logrus.Fields{"org_id": orgID.String(), "doc_id": req.ID.String()})
 	return nil
@@ -141,21 +209,106 @@ func (s *esfDocumentService) UpdateDocument(ctx context.Context, orgID uuid.UUID
 func (s *esfDocumentService) DeleteDocument(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error {
 	s.logger.Info(ctx, "Deleting document", logrus.Fields{"org_id": orgID.String(), "doc_id": id.String()})
 
+	entry := audit.FromContext(ctx)
+	before := s.auditSnapshot(ctx, entry, orgID, id)
+
 	if err := s.repo.DeleteDocument(ctx, orgID, id); err != nil {
 		s.logger.Error(ctx, "Failed to delete document", err, logrus.Fields{"org_id": orgID.String(), "doc_id": id.String()})
 		return apperror.DatabaseError("deleting document", err)
 	}
+	entry.SetChange(before, nil)
 
 	// Invalidate cache
 	if s.cacheManager != nil {
-		cacheKey := "doc:id:" + id.String()
+		cacheKey := documentCacheKey(orgID, id)
 		_ = s.cacheManager.Document().Delete(ctx, cacheKey)
 	}
+	s.forgetDocument(ctx, orgID, id)
 
 	s.logger.Info(ctx, "Document deleted successfully", logrus.Fields{"org_id": orgID.String(), "doc_id": id.String()})
 	return nil
 }
 
+// auditEntityDocument is the entity type of documents in the audit log
+const auditEntityDocument = "esf-documents"
+
+// auditSnapshot loads the current document state for the audit log.
+// It skips the extra query when the request is not audited.
+func (s *esfDocumentService) auditSnapshot(ctx context.Context, entry *audit.Entry, orgID, id uuid.UUID) interface{} {
+	if entry == nil {
+		return nil
+	}
+	entry.SetOrganization(orgID.String())
+
+	doc, err := s.repo.GetDocumentByID(ctx, orgID, id)
+	if err != nil || doc == nil {
+		return nil
+	}
+	model := s.toModel(doc)
+	return &model
+}
+
+// RestoreDocument restores a soft-deleted document
+func (s *esfDocumentService) RestoreDocument(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error {
+	s.logger.Info(ctx, "Restoring document", logrus.Fields{"org_id": orgID.String(), "doc_id": id.String()})
+
+	if err := s.repo.RestoreDocument(ctx, orgID, id); err != nil {
+		s.logger.Error(ctx, "Failed to restore document", err, logrus.Fields{"org_id": orgID.String(), "doc_id": id.String()})
+		return err
+	}
+
+	s.invalidateDocumentCache(ctx, orgID, id)
+	s.remindDraft(ctx, orgID, id)
+
+	s.logger.Info(ctx, "Document restored successfully", logrus.Fields{"org_id": orgID.String(), "doc_id": id.String()})
+	return nil
+}
+
+// PurgeDocument permanently removes a soft-deleted document
+func (s *esfDocumentService) PurgeDocument(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error {
+	s.logger.Info(ctx, "Purging document", logrus.Fields{"org_id": orgID.String(), "doc_id": id.String()})
+
+	if err := s.repo.PurgeDocument(ctx, orgID, id); err != nil {
+		s.logger.Error(ctx, "Failed to purge document", err, logrus.Fields{"org_id": orgID.String(), "doc_id": id.String()})
+		return err
+	}
+
+	s.invalidateDocumentCache(ctx, orgID, id)
+
+	s.logger.Info(ctx, "Document purged successfully", logrus.Fields{"org_id": orgID.String(), "doc_id": id.String()})
+	return nil
+}
+
+// UpdateDocumentStatus stores the ESF status reported by the gateway
+func (s *esfDocumentService) UpdateDocumentStatus(ctx context.Context, orgID uuid.UUID, id uuid.UUID, status string) error {
+	if !entity.IsValidEsfStatus(status) {
+		return apperror.ValidationError("invalid document status")
+	}
+
+	if err := s.repo.UpdateDocumentStatus(ctx, orgID, id, status); err != nil {
+		s.logger.Error(ctx, "Failed to update document status", err, logrus.Fields{"org_id": orgID.String(), "doc_id": id.String()})
+		return err
+	}
+
+	s.invalidateDocumentCache(ctx, orgID, id)
+
+	s.logger.Info(ctx, "Document status updated", logrus.Fields{"org_id": orgID.String(), "doc_id": id.String(), "status": status})
+	return nil
+}
+
+// documentCacheKey returns the Document cache key of a single document. The key includes the organization,
+// so a document ID requested with another organization is never served from the cache.
+func documentCacheKey(orgID, id uuid.UUID) string {
+	return "doc:id:" + orgID.String() + ":" + id.String()
+}
+
+// invalidateDocumentCache removes a cached document
+func (s *esfDocumentService) invalidateDocumentCache(ctx context.Context, orgID, id uuid.UUID) {
+	if s.cacheManager != nil {
+		_ = s.cacheManager.Document().Delete(ctx, documentCacheKey(orgID, id))
+	}
+}
+
 // CacheWarmDocuments preloads frequently accessed documents
 func (s *esfDocumentService) CacheWarmDocuments(ctx context.Context, orgID uuid.UUID, limit int) error {
 	if s.cacheManager == nil {
@@ -179,7 +332,7 @@ func (s *esfDocumentService) CacheWarmDocuments(ctx context.Context, orgID uuid.
 	batchData := make(map[string]interface{})
 	for _, doc := range docs {
 		model := s.toModel(&doc)
-		cacheKey := "doc:id:" + doc.ID.String()
+		cacheKey := documentCacheKey(orgID, doc.ID)
 		batchData[cacheKey] = &model
 	}
 
@@ -202,6 +355,8 @@ func (s *esfDocumentService) toEntity(m *models.EsfCreateDocumentRequest) entity
 		entries[i] = entity.EsfEntries{
 			UnitClassificationCode: catalogEntry.UnitClassificationCode,
 			SalesTaxCode:           catalogEntry.SalesTaxCode,
+			ForeignName:            catalogEntry.ForeignName,
+			Names:                  catalogEntry.Names,
 			CustomsAuthorityCode:   catalogEntry.CustomsAuthorityCode,
 			Quantity:               catalogEntry.Quantity,
 			Price:                  catalogEntry.Price,
@@ -209,11 +364,15 @@ func (s *esfDocumentService) toEntity(m *models.EsfCreateDocumentRequest) entity
 			SalesTaxAmount:         catalogEntry.SalesTaxAmount,
 			AmountWithoutTaxes:     catalogEntry.AmountWithoutTaxes,
 			TotalAmount:            catalogEntry.TotalAmount,
+			DiscountAmount:         catalogEntry.DiscountAmount,
+			SurchargeAmount:        catalogEntry.SurchargeAmount,
 		}
 	}
 
 	return entity.EsfDocument{
 		ForeignName:                    m.ForeignName,
+		ContractorNames:                m.ContractorNames,
+		Language:                       m.Language,
 		IsBranchDataSent:               m.IsBranchDataSent,
 		IsPriceWithoutTaxes:            m.IsPriceWithoutTaxes,
 		AffiliateTin:                   m.AffiliateTin,
@@ -233,6 +392,7 @@ func (s *esfDocumentService) toEntity(m *models.EsfCreateDocumentRequest) entity
 		TotalCurrencyValueWithoutTaxes: m.TotalCurrencyValueWithoutTaxes,
 		SupplyContractNumber:           m.SupplyContractNumber,
 		ContractStartDate:              m.ContractStartDate,
+		ContractID:                     m.ContractID,
 		Comment:                        m.Comment,
 		DeliveryCode:                   m.DeliveryCode,
 		PaymentCode:                    m.PaymentCode,
@@ -246,6 +406,7 @@ func (s *esfDocumentService) toEntity(m *models.EsfCreateDocumentRequest) entity
 		ClosingBalances:                m.ClosingBalances,
 		AmountToBePaid:                 m.AmountToBePaid,
 		PersonalAccountNumber:          m.PersonalAccountNumber,
+		DueDate:                        m.DueDate,
 	}
 }
 
@@ -255,6 +416,8 @@ func (s *esfDocumentService) toModel(e *entity.EsfDocument) models.EsfCreateDocu
 		entries[i] = models.EsfEntriesModel{
 			UnitClassificationCode: ent.UnitClassificationCode,
 			SalesTaxCode:           ent.SalesTaxCode,
+			ForeignName:            ent.ForeignName,
+			Names:                  ent.Names,
 			CustomsAuthorityCode:   ent.CustomsAuthorityCode,
 			Quantity:               ent.Quantity,
 			Price:                  ent.Price,
@@ -262,11 +425,18 @@ func (s *esfDocumentService) toModel(e *entity.EsfDocument) models.EsfCreateDocu
 			SalesTaxAmount:         ent.SalesTaxAmount,
 			AmountWithoutTaxes:     ent.AmountWithoutTaxes,
 			TotalAmount:            ent.TotalAmount,
+			DiscountAmount:         ent.DiscountAmount,
+			SurchargeAmount:        ent.SurchargeAmount,
 		}
 	}
 
 	return models.EsfCreateDocumentRequest{
+		ID:                             e.ID,
+		Version:                        e.Version,
+		EsfStatus:                      e.EsfStatus,
 		ForeignName:                    e.ForeignName,
+		ContractorNames:                e.ContractorNames,
+		Language:                       e.Language,
 		IsBranchDataSent:               e.IsBranchDataSent,
 		IsPriceWithoutTaxes:            e.IsPriceWithoutTaxes,
 		AffiliateTin:                   e.AffiliateTin,
@@ -286,6 +456,7 @@ func (s *esfDocumentService) toModel(e *entity.EsfDocument) models.EsfCreateDocu
 		TotalCurrencyValueWithoutTaxes: e.TotalCurrencyValueWithoutTaxes,
 		SupplyContractNumber:           e.SupplyContractNumber,
 		ContractStartDate:              e.ContractStartDate,
+		ContractID:                     e.ContractID,
 		Comment:                        e.Comment,
 		DeliveryCode:                   e.DeliveryCode,
 		PaymentCode:                    e.PaymentCode,
@@ -299,6 +470,7 @@ func (s *esfDocumentService) toModel(e *entity.EsfDocument) models.EsfCreateDocu
 		ClosingBalances:                e.ClosingBalances,
 		AmountToBePaid:                 e.AmountToBePaid,
 		PersonalAccountNumber:          e.PersonalAccountNumber,
+		DueDate:                        e.DueDate,
 	}
 }
 
@@ -330,3 +502,50 @@ func (s *esfDocumentService) GetAllDocumentsPaginated(ctx context.Context, orgID
 
 	return result, totalCount, nil
 }
+
+// GetAllDocumentsCursor возвращает документы с курсорной пагинацией
+func (s *esfDocumentService) GetAllDocumentsCursor(ctx context.Context, orgID uuid.UUID, params pagination.CursorParams, filters pagination.DocumentFilterParams) ([]models.EsfCreateDocumentRequest, pagination.CursorInfo, error) {
+	s.logger.Info(ctx, "Fetching documents with cursor", logrus.Fields{
+		"org_id": orgID.String(),
+		"limit":  params.Limit,
+	})
+
+	docs, info, err := s.repo.GetAllDocumentsCursor(ctx, orgID, params, filters)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to fetch documents by cursor", err, logrus.Fields{"org_id": orgID.String()})
+		return nil, pagination.CursorInfo{}, err
+	}
+
+	result := make([]models.EsfCreateDocumentRequest, len(docs))
+	for i := range docs {
+		result[i] = s.toModel(&docs[i])
+	}
+
+	s.logger.Debug(ctx, "Documents fetched by cursor successfully", logrus.Fields{
+		"org_id":   orgID.String(),
+		"count":    len(result),
+		"has_next": info.HasNext,
+	})
+
+	return result, info, nil
+}
+
+// remindDraft переносит напоминание о черновике. Ошибка планирования не отменяет сохранение документа.
+func (s *esfDocumentService) remindDraft(ctx context.Context, orgID, id uuid.UUID) {
+	if s.reminders == nil {
+		return
+	}
+	if err := s.reminders.RemindDraft(ctx, orgID, id); err != nil {
+		s.logger.Error(ctx, "Failed to schedule draft reminder", err, logrus.Fields{"org_id": orgID.String(), "doc_id": id.String()})
+	}
+}
+
+// forgetDocument снимает с расписания напоминание и отложенную отправку отправленного или удаленного документа
+func (s *esfDocumentService) forgetDocument(ctx context.Context, orgID, id uuid.UUID) {
+	if s.reminders == nil {
+		return
+	}
+	if err := s.reminders.ForgetDocument(ctx, orgID, id); err != nil {
+		s.logger.Error(ctx, "Failed to cancel scheduled document jobs", err, logrus.Fields{"org_id": orgID.String(), "doc_id": id.String()})
+	}
+}
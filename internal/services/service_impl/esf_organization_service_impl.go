@@ -11,8 +11,11 @@ import (
 	"github.com/rusgainew/tunduck-app/internal/repository"
 	"github.com/rusgainew/tunduck-app/internal/services"
 	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/audit"
 	"github.com/rusgainew/tunduck-app/pkg/cache"
+	"github.com/rusgainew/tunduck-app/pkg/dates"
 	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/events"
 	"github.com/rusgainew/tunduck-app/pkg/logger"
 	"github.com/rusgainew/tunduck-app/pkg/pagination"
 	"github.com/sirupsen/logrus"
@@ -44,23 +47,35 @@ func (s *esfOrganizationServiceImpl) SetCacheManager(cacheManager cache.CacheMan
 
 // GetAllOrganizations возвращает все организации
 func (s *esfOrganizationServiceImpl) GetAllOrganizations(ctx context.Context) ([]models.EsfOrganizationModel, error) {
-	s.logger.Info(ctx, "Fetching all organizations", logrus.Fields{})
+	s.logger.Info(ctx, "Fetching all organizations")
 
+	if s.cacheManager == nil {
+		return s.loadAllOrganizations(ctx)
+	}
+
+	// Список инвалидируется по тегу при любом изменении организаций
+	var result []models.EsfOrganizationModel
+	err := s.cacheManager.Organization().GetOrLoad(ctx, "all", &result, 2*time.Hour, func(ctx context.Context) (interface{}, error) {
+		return s.loadAllOrganizations(ctx)
+	}, cache.TagOrganizations)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// loadAllOrganizations загружает все организации из репозитория
+func (s *esfOrganizationServiceImpl) loadAllOrganizations(ctx context.Context) ([]models.EsfOrganizationModel, error) {
 	orgs, err := s.repo.GetAll(ctx)
 	if err != nil {
-		s.logger.Error(ctx, "Failed to fetch organizations", err, logrus.Fields{})
+		s.logger.Error(ctx, "Failed to fetch organizations", err)
 		return nil, apperror.DatabaseError("fetching organizations", err)
 	}
 
 	result := make([]models.EsfOrganizationModel, len(orgs))
 	for i, org := range orgs {
-		result[i] = models.EsfOrganizationModel{
-			ID:          org.ID.String(),
-			Name:        org.Name,
-			Description: org.Description,
-			Token:       org.Token,
-			DBName:      org.DBName,
-		}
+		result[i] = organizationToModel(org)
 	}
 
 	s.logger.Debug(ctx, "Organizations fetched successfully", logrus.Fields{"count": len(result)})
@@ -71,24 +86,30 @@ func (s *esfOrganizationServiceImpl) GetAllOrganizations(ctx context.Context) ([
 func (s *esfOrganizationServiceImpl) GetOrganizationByID(ctx context.Context, id uuid.UUID) (*models.EsfOrganizationModel, error) {
 	s.logger.Info(ctx, "Fetching organization by ID", logrus.Fields{"org_id": id.String()})
 
-	// Проверяем кеш
-	if s.cacheManager != nil {
-		cacheKey := "id:" + id.String()
-		cached, _ := s.cacheManager.Organization().Get(ctx, cacheKey)
-		if cached != nil {
-			if org, ok := cached.(*entity.EstOrganization); ok {
-				s.logger.Debug(ctx, "Organization retrieved from cache", logrus.Fields{"org_id": id.String()})
-				return &models.EsfOrganizationModel{
-					ID:          org.ID.String(),
-					Name:        org.Name,
-					Description: org.Description,
-					Token:       org.Token,
-					DBName:      org.DBName,
-				}, nil
-			}
+	if s.cacheManager == nil {
+		org, err := s.loadOrganization(ctx, id)
+		if err != nil {
+			return nil, err
 		}
+		result := organizationToModel(org)
+		return &result, nil
+	}
+
+	// Кешируем результат на 2 часа; одновременные промахи загружают организацию один раз
+	var org entity.EstOrganization
+	err := s.cacheManager.Organization().GetOrLoad(ctx, "id:"+id.String(), &org, 2*time.Hour, func(ctx context.Context) (interface{}, error) {
+		return s.loadOrganization(ctx, id)
+	}, cache.OrgTag(id.String()))
+	if err != nil {
+		return nil, err
 	}
 
+	result := organizationToModel(&org)
+	return &result, nil
+}
+
+// loadOrganization загружает организацию по ID из репозитория
+func (s *esfOrganizationServiceImpl) loadOrganization(ctx context.Context, id uuid.UUID) (*entity.EstOrganization, error) {
 	org, err := s.repo.GetByID(ctx, id.String())
 	if err != nil {
 		s.logger.Error(ctx, "Failed to fetch organization", err, logrus.Fields{"org_id": id.String()})
@@ -100,22 +121,24 @@ func (s *esfOrganizationServiceImpl) GetOrganizationByID(ctx context.Context, id
 		return nil, apperror.New(apperror.ErrOrgNotFound, "organization not found")
 	}
 
-	// Кешируем результат на 2 часа
-	if s.cacheManager != nil {
-		cacheKey := "id:" + org.ID.String()
-		_ = s.cacheManager.Organization().Set(ctx, cacheKey, org, 2*time.Hour)
-	}
+	s.logger.Debug(ctx, "Organization fetched successfully", logrus.Fields{"org_id": id.String()})
+	return org, nil
+}
 
-	result := &models.EsfOrganizationModel{
+// organizationToModel конвертирует entity организации в модель API
+func organizationToModel(org *entity.EstOrganization) models.EsfOrganizationModel {
+	return models.EsfOrganizationModel{
 		ID:          org.ID.String(),
 		Name:        org.Name,
 		Description: org.Description,
 		Token:       org.Token,
 		DBName:      org.DBName,
+		Plan:        org.Plan,
+		Tin:         org.Tin,
+		Timezone:    org.Timezone,
+		Version:     org.Version,
+		CreatedAt:   org.CreatedAt,
 	}
-
-	s.logger.Debug(ctx, "Organization fetched successfully", logrus.Fields{"org_id": id.String()})
-	return result, nil
 }
 
 // CreateOrganization создает новую организацию и отдельную базу данных для неё
@@ -124,9 +147,12 @@ func (s *esfOrganizationServiceImpl) CreateOrganization(ctx context.Context, org
 
 	// Валидация
 	if org.Name == "" {
-		s.logger.Warn(ctx, "Organization name is required", logrus.Fields{})
+		s.logger.Warn(ctx, "Organization name is required")
 		return uuid.Nil, "", apperror.ValidationError("organization name is required")
 	}
+	if err := validateTimezone(org.Timezone); err != nil {
+		return uuid.Nil, "", err
+	}
 
 	// Формируем имя базы данных
 	dbName := sanitizeDatabaseName(org.Name) + "_db"
@@ -145,6 +171,8 @@ func (s *esfOrganizationServiceImpl) CreateOrganization(ctx context.Context, org
 		Description: org.Description,
 		Token:       org.Token,
 		DBName:      dbName,
+		Tin:         org.Tin,
+		Timezone:    org.Timezone,
 	}
 
 	if err := s.repo.Insert(ctx, entity); err != nil {
@@ -152,10 +180,27 @@ func (s *esfOrganizationServiceImpl) CreateOrganization(ctx context.Context, org
 		return uuid.Nil, "", apperror.DatabaseError("creating organization", err)
 	}
 
+	s.invalidateOrgCache(ctx, entity.ID.String())
+
+	entry := audit.FromContext(ctx)
+	entry.SetEntity(auditEntityOrganization, entity.ID.String())
+	entry.SetChange(nil, events.NewOrganizationPayload(entity))
+
 	s.logger.Info(ctx, "Organization created successfully", logrus.Fields{"id": entity.ID.String(), "dbName": dbName})
 	return entity.ID, dbName, nil
 }
 
+// validateTimezone проверяет часовой пояс организации; пустой означает часовой пояс по умолчанию
+func validateTimezone(name string) *apperror.AppError {
+	if name == "" {
+		return nil
+	}
+	if _, err := dates.LoadLocation(name); err != nil {
+		return apperror.ValidationError("invalid time zone, expected an IANA name such as Asia/Bishkek")
+	}
+	return nil
+}
+
 // sanitizeDatabaseName очищает имя от недопустимых символов для имени БД
 // Оставляет только буквы, цифры и подчеркивания, приводит к нижнему регистру
 func sanitizeDatabaseName(name string) string {
@@ -178,29 +223,62 @@ func sanitizeDatabaseName(name string) string {
 	return name
 }
 
+// auditEntityOrganization тип сущности организации в журнале аудита
+const auditEntityOrganization = "esf-organizations"
+
+// auditSnapshot возвращает организацию без токена и имени БД для журнала аудита;
+// без записи аудита в контексте лишний запрос не выполняется
+func (s *esfOrganizationServiceImpl) auditSnapshot(ctx context.Context, entry *audit.Entry, id string) interface{} {
+	if entry == nil {
+		return nil
+	}
+	org, err := s.repo.GetByID(ctx, id)
+	if err != nil || org == nil {
+		return nil
+	}
+	return events.NewOrganizationPayload(org)
+}
+
 // UpdateOrganization обновляет данные организации
 func (s *esfOrganizationServiceImpl) UpdateOrganization(ctx context.Context, org *models.EsfOrganizationModel) error {
 	s.logger.Info(ctx, "Updating organization", logrus.Fields{"name": org.Name})
 
 	// Валидация
 	if org.Name == "" {
-		s.logger.Warn(ctx, "Organization name is required", logrus.Fields{})
+		s.logger.Warn(ctx, "Organization name is required")
 		return apperror.ValidationError("organization name is required")
 	}
+	if err := validateTimezone(org.Timezone); err != nil {
+		return err
+	}
+
+	orgID, _ := uuid.Parse(org.ID)
 
 	// Конвертируем model в entity для обновления
 	entity := &entity.EstOrganization{
+		ID:          orgID,
 		Name:        org.Name,
 		Description: org.Description,
 		Token:       org.Token,
 		DBName:      org.DBName,
+		Tin:         org.Tin,
+		Timezone:    org.Timezone,
+		Version:     org.Version,
 	}
 
+	// Состояние до изменения нужно только для журнала аудита
+	entry := audit.FromContext(ctx)
+	before := s.auditSnapshot(ctx, entry, org.ID)
+
 	// Обновляем в репозитории
 	if err := s.repo.Update(ctx, entity); err != nil {
 		s.logger.Error(ctx, "Failed to update organization", err, logrus.Fields{"name": org.Name})
-		return apperror.DatabaseError("updating organization", err)
+		return apperror.DatabaseErrorFrom("updating organization", err)
 	}
+	org.Version = entity.Version
+	entry.SetChange(before, events.NewOrganizationPayload(entity))
+
+	s.invalidateOrgCache(ctx, orgID.String())
 
 	s.logger.Info(ctx, "Organization updated successfully", logrus.Fields{"name": org.Name})
 	return nil
@@ -210,15 +288,74 @@ func (s *esfOrganizationServiceImpl) UpdateOrganization(ctx context.Context, org
 func (s *esfOrganizationServiceImpl) DeleteOrganization(ctx context.Context, id uuid.UUID) error {
 	s.logger.Info(ctx, "Deleting organization", logrus.Fields{"id": id.String()})
 
+	entry := audit.FromContext(ctx)
+	before := s.auditSnapshot(ctx, entry, id.String())
+
 	if err := s.repo.Delete(ctx, id.String()); err != nil {
 		s.logger.Error(ctx, "Failed to delete organization", err, logrus.Fields{"id": id.String()})
 		return apperror.DatabaseError("deleting organization", err)
 	}
+	entry.SetChange(before, nil)
+
+	s.invalidateOrgCache(ctx, id.String())
 
 	s.logger.Info(ctx, "Organization deleted successfully", logrus.Fields{"id": id.String()})
 	return nil
 }
 
+// RestoreOrganization восстанавливает мягко удаленную организацию
+func (s *esfOrganizationServiceImpl) RestoreOrganization(ctx context.Context, id uuid.UUID) error {
+	s.logger.Info(ctx, "Restoring organization", logrus.Fields{"id": id.String()})
+
+	if err := s.repo.Restore(ctx, id.String()); err != nil {
+		s.logger.Error(ctx, "Failed to restore organization", err, logrus.Fields{"id": id.String()})
+		return err
+	}
+
+	s.invalidateOrgCache(ctx, id.String())
+
+	s.logger.Info(ctx, "Organization restored successfully", logrus.Fields{"id": id.String()})
+	return nil
+}
+
+// PurgeOrganization окончательно удаляет мягко удаленную организацию
+func (s *esfOrganizationServiceImpl) PurgeOrganization(ctx context.Context, id uuid.UUID) error {
+	s.logger.Info(ctx, "Purging organization", logrus.Fields{"id": id.String()})
+
+	if err := s.repo.Purge(ctx, id.String()); err != nil {
+		s.logger.Error(ctx, "Failed to purge organization", err, logrus.Fields{"id": id.String()})
+		return err
+	}
+
+	s.invalidateOrgCache(ctx, id.String())
+
+	s.logger.Info(ctx, "Organization purged successfully", logrus.Fields{"id": id.String()})
+	return nil
+}
+
+// SetPlan назначает организации тарифный план
+func (s *esfOrganizationServiceImpl) SetPlan(ctx context.Context, id uuid.UUID, plan string) error {
+	s.logger.Info(ctx, "Setting organization plan", logrus.Fields{"id": id.String(), "plan": plan})
+
+	if err := s.repo.SetPlan(ctx, id.String(), plan); err != nil {
+		s.logger.Error(ctx, "Failed to set organization plan", err, logrus.Fields{"id": id.String()})
+		return err
+	}
+
+	s.invalidateOrgCache(ctx, id.String())
+	return nil
+}
+
+// invalidateOrgCache удаляет из кеша данные организации и все списки организаций
+func (s *esfOrganizationServiceImpl) invalidateOrgCache(ctx context.Context, orgID string) {
+	if s.cacheHelper == nil {
+		return
+	}
+	if err := s.cacheHelper.InvalidateOrgTags(ctx, orgID); err != nil {
+		s.logger.Warn(ctx, "Failed to invalidate organization cache", logrus.Fields{"id": orgID, "error": err.Error()})
+	}
+}
+
 // GetAllOrganizationsPaginated возвращает организации с пагинацией
 func (s *esfOrganizationServiceImpl) GetAllOrganizationsPaginated(ctx context.Context, params pagination.PaginationParams, filters pagination.OrganizationFilterParams) ([]models.EsfOrganizationModel, int64, error) {
 	s.logger.Info(ctx, "Fetching organizations with pagination", logrus.Fields{
@@ -228,7 +365,7 @@ func (s *esfOrganizationServiceImpl) GetAllOrganizationsPaginated(ctx context.Co
 
 	orgs, totalCount, err := s.repo.GetAllPaginated(ctx, params, filters)
 	if err != nil {
-		s.logger.Error(ctx, "Failed to fetch paginated organizations", err, logrus.Fields{})
+		s.logger.Error(ctx, "Failed to fetch paginated organizations", err)
 		return nil, 0, err
 	}
 
@@ -252,6 +389,37 @@ func (s *esfOrganizationServiceImpl) GetAllOrganizationsPaginated(ctx context.Co
 	return result, totalCount, nil
 }
 
+// GetAllOrganizationsCursor возвращает организации с курсорной пагинацией
+func (s *esfOrganizationServiceImpl) GetAllOrganizationsCursor(ctx context.Context, params pagination.CursorParams, filters pagination.OrganizationFilterParams) ([]models.EsfOrganizationModel, pagination.CursorInfo, error) {
+	s.logger.Info(ctx, "Fetching organizations with cursor", logrus.Fields{
+		"limit": params.Limit,
+	})
+
+	orgs, info, err := s.repo.GetAllCursor(ctx, params, filters)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to fetch organizations by cursor", err)
+		return nil, pagination.CursorInfo{}, err
+	}
+
+	result := make([]models.EsfOrganizationModel, len(orgs))
+	for i, org := range orgs {
+		result[i] = models.EsfOrganizationModel{
+			ID:          org.ID.String(),
+			Name:        org.Name,
+			Description: org.Description,
+			Token:       org.Token,
+			DBName:      org.DBName,
+		}
+	}
+
+	s.logger.Debug(ctx, "Organizations fetched by cursor successfully", logrus.Fields{
+		"count":    len(result),
+		"has_next": info.HasNext,
+	})
+
+	return result, info, nil
+}
+
 // CacheWarmOrganizations предварительно загружает организации в кеш
 func (s *esfOrganizationServiceImpl) CacheWarmOrganizations(ctx context.Context) error {
 	if s.cacheManager == nil {
@@ -266,17 +434,17 @@ func (s *esfOrganizationServiceImpl) CacheWarmOrganizations(ctx context.Context)
 		return err
 	}
 
-	// Подготавливаем данные для пакетного кеширования
-	batchData := make(map[string]interface{})
+	// Каждая запись связывается с тегом своей организации, чтобы инвалидироваться при изменении
 	for _, org := range orgs {
-		batchData["id:"+org.ID.String()] = org
-		batchData["name:"+org.Name] = org
-	}
-
-	// Кешируем все сразу (более эффективно)
-	if err := s.cacheManager.Organization().SetMultiple(ctx, batchData, 2*time.Hour); err != nil {
-		s.logger.Error(ctx, "Failed to warm organizations cache", err)
-		return err
+		tag := cache.OrgTag(org.ID.String())
+		if err := s.cacheManager.Organization().SetWithTags(ctx, "id:"+org.ID.String(), org, 2*time.Hour, tag); err != nil {
+			s.logger.Error(ctx, "Failed to warm organizations cache", err)
+			return err
+		}
+		if err := s.cacheManager.Organization().SetWithTags(ctx, "name:"+org.Name, org, 2*time.Hour, tag); err != nil {
+			s.logger.Error(ctx, "Failed to warm organizations cache", err)
+			return err
+		}
 	}
 
 	s.logger.Info(ctx, "Organizations cache warming completed", logrus.Fields{"count": len(orgs)})
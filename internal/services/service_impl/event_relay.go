@@ -0,0 +1,181 @@
+package service_impl
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/events"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+)
+
+// eventRelayBatchSize количество событий outbox, публикуемых в одной транзакции
+const eventRelayBatchSize = 100
+
+// EventRelay публикует доменные события из outbox основной БД и БД организаций в шину.
+// Событие отмечается опубликованным только после успешной отправки, поэтому при
+// недоступности шины оно будет отправлено на следующем проходе (at-least-once).
+type EventRelay struct {
+	docRepo   repository.EsfDocumentRepository
+	orgRepo   repository.EsfOrganizationRepository
+	publisher events.Publisher
+	interval  time.Duration
+	logger    *logger.Logger
+}
+
+// NewEventRelay создает ретранслятор outbox
+func NewEventRelay(docRepo repository.EsfDocumentRepository, orgRepo repository.EsfOrganizationRepository, publisher events.Publisher, interval time.Duration, log *logrus.Logger) *EventRelay {
+	if interval <= 0 {
+		interval = events.DefaultRelayInterval
+	}
+	return &EventRelay{
+		docRepo:   docRepo,
+		orgRepo:   orgRepo,
+		publisher: publisher,
+		interval:  interval,
+		logger:    logger.New(log),
+	}
+}
+
+// Run опрашивает outbox до отмены контекста
+func (r *EventRelay) Run(ctx context.Context) {
+	r.logger.Info(ctx, "Event relay started", logrus.Fields{"interval": r.interval.String()})
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.ProcessOnce(ctx); err != nil && ctx.Err() == nil {
+			r.logger.Warn(ctx, "Event relay pass failed", logrus.Fields{"error": err.Error()})
+		}
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info(context.Background(), "Event relay stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessOnce публикует накопленные события основной БД и всех организаций и возвращает
+// их количество. Ошибка одной БД не останавливает остальные.
+func (r *EventRelay) ProcessOnce(ctx context.Context) (int, error) {
+	published := r.drain(ctx, uuid.Nil, func(publish repository.PublishEventFunc) (int, error) {
+		return r.orgRepo.RelayEvents(ctx, eventRelayBatchSize, publish)
+	})
+
+	orgs, err := r.orgRepo.GetAll(ctx)
+	if err != nil {
+		return published, err
+	}
+
+	for _, org := range orgs {
+		orgID := org.ID
+		published += r.drain(ctx, orgID, func(publish repository.PublishEventFunc) (int, error) {
+			return r.docRepo.RelayEvents(ctx, orgID, eventRelayBatchSize, publish)
+		})
+	}
+
+	return published, nil
+}
+
+// PurgePublished удаляет опубликованные события старше before во всех БД
+func (r *EventRelay) PurgePublished(ctx context.Context, before time.Time) error {
+	purged, err := r.orgRepo.PurgePublishedEvents(ctx, before)
+	if err != nil {
+		return err
+	}
+
+	orgs, err := r.orgRepo.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	for _, org := range orgs {
+		n, err := r.docRepo.PurgePublishedEvents(ctx, org.ID, before)
+		if err != nil {
+			r.logger.Warn(ctx, "Failed to purge organization events", logrus.Fields{"org_id": org.ID.String(), "error": err.Error()})
+			continue
+		}
+		purged += n
+	}
+
+	r.logger.Info(ctx, "Published events purged", logrus.Fields{"purged": purged, "before": before.Format(time.RFC3339)})
+	return nil
+}
+
+// ReplayEvents повторно публикует опубликованные события организации за период: сначала события
+// организации из основной БД, затем события документов из ее БД. События сохраняют исходные ID.
+func (r *EventRelay) ReplayEvents(ctx context.Context, req services.EventReplayRequest) (*services.EventReplayResult, error) {
+	if req.OrganizationID == uuid.Nil {
+		return nil, apperror.ValidationError("organization id is required")
+	}
+	if req.From.IsZero() || req.To.IsZero() || !req.From.Before(req.To) {
+		return nil, apperror.ValidationError("invalid date range")
+	}
+	for _, eventType := range req.Types {
+		if !events.IsKnownType(eventType) {
+			return nil, apperror.ValidationError("unknown event type")
+		}
+	}
+
+	org, err := r.orgRepo.GetByID(ctx, req.OrganizationID.String())
+	if err != nil {
+		return nil, err
+	}
+	if org == nil {
+		return nil, apperror.NotFoundError("organization")
+	}
+
+	filter := repository.EventReplayFilter{
+		OrganizationID: req.OrganizationID,
+		From:           req.From,
+		To:             req.To,
+		Types:          req.Types,
+	}
+	fields := logrus.Fields{
+		"org_id": req.OrganizationID.String(),
+		"from":   req.From.Format(time.RFC3339),
+		"to":     req.To.Format(time.RFC3339),
+		"types":  req.Types,
+	}
+
+	replayed, err := r.orgRepo.ReplayEvents(ctx, filter, r.publisher.Publish)
+	if err == nil {
+		var n int
+		n, err = r.docRepo.ReplayEvents(ctx, filter, r.publisher.Publish)
+		replayed += n
+	}
+	fields["replayed"] = replayed
+	if err != nil {
+		r.logger.Error(ctx, "Event replay failed", err, fields)
+		return nil, apperror.From(err, apperror.ErrInternal, "failed to replay events")
+	}
+
+	r.logger.Info(ctx, "Events replayed", fields)
+	return &services.EventReplayResult{EventReplayRequest: req, Replayed: replayed}, nil
+}
+
+// drain публикует пачки событий одной БД (uuid.Nil — основная БД), пока outbox не опустеет или не случится ошибка
+func (r *EventRelay) drain(ctx context.Context, orgID uuid.UUID, relay func(repository.PublishEventFunc) (int, error)) int {
+	total := 0
+	for {
+		n, err := relay(r.publisher.Publish)
+		total += n
+		if err != nil {
+			r.logger.Warn(ctx, "Failed to publish outbox events", logrus.Fields{
+				"org_id": orgID.String(),
+				"error":  err.Error(),
+			})
+			return total
+		}
+		if n < eventRelayBatchSize {
+			return total
+		}
+	}
+}
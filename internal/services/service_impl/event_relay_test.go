@@ -0,0 +1,161 @@
+package service_impl
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/events"
+)
+
+// eventOrgRepository отдает события outbox основной БД
+type eventOrgRepository struct {
+	stubOrganizationRepository
+	pending []entity.OutboxEvent
+}
+
+func (s *eventOrgRepository) RelayEvents(ctx context.Context, limit int, publish repository.PublishEventFunc) (int, error) {
+	for i, event := range s.pending {
+		if err := publish(ctx, event); err != nil {
+			return i, err
+		}
+	}
+	return len(s.pending), nil
+}
+
+// recordingPublisher запоминает опубликованные события и отказывает на событиях из failOn
+type recordingPublisher struct {
+	published []string
+	failOn    map[string]bool
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event entity.OutboxEvent) error {
+	if p.failOn[event.Type] {
+		return errors.New("bus unavailable")
+	}
+	p.published = append(p.published, event.Type)
+	return nil
+}
+
+func TestEventRelay_ProcessOnce(t *testing.T) {
+	orgA, orgB := uuid.New(), uuid.New()
+
+	orgRepo := &eventOrgRepository{
+		stubOrganizationRepository: stubOrganizationRepository{orgs: []*entity.EstOrganization{{ID: orgA}, {ID: orgB}}},
+		pending:                    []entity.OutboxEvent{{Type: events.OrgUpdated}},
+	}
+
+	docRepo := new(MockDocumentRepository)
+	docRepo.On("RelayEvents", mock.Anything, orgA, eventRelayBatchSize).Return([]entity.OutboxEvent{
+		{Type: events.DocumentCreated},
+		{Type: events.DocumentDeleted},
+		{Type: events.DocumentUpdated},
+	}, nil)
+	docRepo.On("RelayEvents", mock.Anything, orgB, eventRelayBatchSize).Return([]entity.OutboxEvent{
+		{Type: events.DocumentRestored},
+	}, nil)
+
+	// Отказ шины на document.deleted прерывает пачку организации A, но не организацию B
+	publisher := &recordingPublisher{failOn: map[string]bool{events.DocumentDeleted: true}}
+	relay := NewEventRelay(docRepo, orgRepo, publisher, 0, logrus.New())
+
+	published, err := relay.ProcessOnce(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, published)
+	assert.Equal(t, []string{events.OrgUpdated, events.DocumentCreated, events.DocumentRestored}, publisher.published)
+	docRepo.AssertExpectations(t)
+}
+
+// replayOrgRepository отдает опубликованные события организации для повторной отправки
+type replayOrgRepository struct {
+	stubOrganizationRepository
+	published []entity.OutboxEvent
+	filter    repository.EventReplayFilter
+}
+
+func (s *replayOrgRepository) GetByID(ctx context.Context, id string) (*entity.EstOrganization, error) {
+	for _, org := range s.orgs {
+		if org.ID.String() == id {
+			return org, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *replayOrgRepository) ReplayEvents(ctx context.Context, filter repository.EventReplayFilter, publish repository.PublishEventFunc) (int, error) {
+	s.filter = filter
+	for i, event := range s.published {
+		if err := publish(ctx, event); err != nil {
+			return i, err
+		}
+	}
+	return len(s.published), nil
+}
+
+func TestEventRelay_ReplayEvents(t *testing.T) {
+	orgID := uuid.New()
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	orgRepo := &replayOrgRepository{
+		stubOrganizationRepository: stubOrganizationRepository{orgs: []*entity.EstOrganization{{ID: orgID}}},
+		published:                  []entity.OutboxEvent{{Type: events.OrgUpdated}},
+	}
+	filter := repository.EventReplayFilter{OrganizationID: orgID, From: from, To: to}
+	docRepo := new(MockDocumentRepository)
+	docRepo.On("ReplayEvents", mock.Anything, filter).Return([]entity.OutboxEvent{
+		{Type: events.DocumentCreated},
+		{Type: events.DocumentUpdated},
+	}, nil)
+
+	publisher := &recordingPublisher{}
+	relay := NewEventRelay(docRepo, orgRepo, publisher, 0, logrus.New())
+
+	result, err := relay.ReplayEvents(context.Background(), services.EventReplayRequest{OrganizationID: orgID, From: from, To: to})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Replayed)
+	assert.Equal(t, filter, orgRepo.filter)
+	assert.Equal(t, []string{events.OrgUpdated, events.DocumentCreated, events.DocumentUpdated}, publisher.published)
+	docRepo.AssertExpectations(t)
+
+	// Сбой шины прерывает повтор и не доходит до событий документов
+	publisher.failOn = map[string]bool{events.OrgUpdated: true}
+	_, err = relay.ReplayEvents(context.Background(), services.EventReplayRequest{OrganizationID: orgID, From: from, To: to})
+	require.Error(t, err)
+	docRepo.AssertNumberOfCalls(t, "ReplayEvents", 1)
+}
+
+func TestEventRelay_ReplayEventsValidation(t *testing.T) {
+	orgID := uuid.New()
+	now := time.Now()
+	relay := NewEventRelay(new(MockDocumentRepository), &replayOrgRepository{}, &recordingPublisher{}, 0, logrus.New())
+
+	cases := map[string]services.EventReplayRequest{
+		"no organization": {From: now.Add(-time.Hour), To: now},
+		"empty range":     {OrganizationID: orgID, From: now, To: now},
+		"unknown type":    {OrganizationID: orgID, From: now.Add(-time.Hour), To: now, Types: []string{"document.lost"}},
+	}
+	for name, req := range cases {
+		_, err := relay.ReplayEvents(context.Background(), req)
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr, name)
+		assert.Equal(t, apperror.ErrValidation, appErr.Code, name)
+	}
+
+	_, err := relay.ReplayEvents(context.Background(), services.EventReplayRequest{OrganizationID: orgID, From: now.Add(-time.Hour), To: now})
+	var appErr *apperror.AppError
+	require.ErrorAs(t, err, &appErr)
+	assert.Equal(t, apperror.ErrNotFound, appErr.Code)
+}
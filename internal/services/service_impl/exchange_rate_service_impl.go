@@ -0,0 +1,136 @@
+package service_impl
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/cache"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/exchangerates"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/retry"
+)
+
+// exchangeRateSourceNBKR источник курсов в сохраненных записях
+const exchangeRateSourceNBKR = "nbkr"
+
+// exchangeRatesCacheKeyPrefix префикс ключей курсов на дату в общем кеше
+const exchangeRatesCacheKeyPrefix = "rates:"
+
+// exchangeRatesTTL курсы на дату меняются только при очередном обновлении
+const exchangeRatesTTL = time.Hour
+
+// exchangeRatesRetry повторы запроса к НБКР: сайт банка периодически отвечает ошибками
+var exchangeRatesRetry = retry.Policy{
+	Name:            "nbkr exchange rates",
+	MaxAttempts:     3,
+	InitialInterval: time.Second,
+	MaxInterval:     10 * time.Second,
+	Jitter:          0.2,
+	MaxElapsed:      time.Minute,
+}
+
+// exchangeRateService реализует ExchangeRateService
+type exchangeRateService struct {
+	repo         repository.ExchangeRateRepository
+	source       exchangerates.Source
+	logger       *logger.Logger
+	cacheManager cache.CacheManager
+}
+
+// NewExchangeRateService создает сервис курсов валют
+func NewExchangeRateService(repo repository.ExchangeRateRepository, source exchangerates.Source, log *logrus.Logger) services.ExchangeRateService {
+	return &exchangeRateService{
+		repo:   repo,
+		source: source,
+		logger: logger.New(log),
+	}
+}
+
+// SetCacheManager устанавливает CacheManager для использования кеша
+func (s *exchangeRateService) SetCacheManager(cacheManager cache.CacheManager) {
+	s.cacheManager = cacheManager
+}
+
+// SetSource заменяет источник курсов
+func (s *exchangeRateService) SetSource(source exchangerates.Source) {
+	s.source = source
+}
+
+// Refresh загружает последние курсы НБКР (с повторами при сбоях) и сохраняет их за дату публикации
+func (s *exchangeRateService) Refresh(ctx context.Context) error {
+	var latest *exchangerates.Rates
+	policy := exchangeRatesRetry
+	policy.Logger = s.logger
+	err := retry.Do(ctx, policy, func(ctx context.Context) error {
+		var err error
+		latest, err = s.source.Latest(ctx)
+		return err
+	})
+	if err != nil {
+		s.logger.Error(ctx, "Failed to fetch exchange rates", err)
+		return apperror.From(err, apperror.ErrExternalService, "failed to fetch exchange rates")
+	}
+
+	date := rateDate(latest.Date)
+	rates := make([]entity.ExchangeRate, 0, len(latest.Values))
+	for code, value := range latest.Values {
+		if code == exchangerates.BaseCurrency || value <= 0 {
+			continue
+		}
+		rates = append(rates, entity.ExchangeRate{CurrencyCode: code, Date: date, Rate: value, Source: exchangeRateSourceNBKR})
+	}
+	if err := s.repo.SaveRates(ctx, rates); err != nil {
+		return err
+	}
+
+	if s.cacheManager != nil {
+		if err := s.cacheManager.InvalidateTags(ctx, cache.TagReference); err != nil {
+			s.logger.Error(ctx, "Failed to invalidate exchange rates cache", err)
+		}
+	}
+
+	s.logger.Info(ctx, "Exchange rates refreshed", logrus.Fields{
+		"date":       date.Format(reportDateLayout),
+		"currencies": len(rates),
+	})
+	return nil
+}
+
+// Rates возвращает курсы на дату (из кеша, если доступен)
+func (s *exchangeRateService) Rates(ctx context.Context, date time.Time) ([]entity.ExchangeRate, error) {
+	date = rateDate(date)
+	if s.cacheManager == nil {
+		return s.repo.ListRates(ctx, date)
+	}
+
+	var rates []entity.ExchangeRate
+	err := s.cacheManager.Generic().GetOrLoad(ctx, exchangeRatesCacheKeyPrefix+date.Format(reportDateLayout), &rates, exchangeRatesTTL, func(ctx context.Context) (interface{}, error) {
+		return s.repo.ListRates(ctx, date)
+	}, cache.TagReference)
+	if err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// Rate возвращает последний курс валюты не позже даты
+func (s *exchangeRateService) Rate(ctx context.Context, currency string, date time.Time) (*entity.ExchangeRate, error) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	date = rateDate(date)
+	if currency == exchangerates.BaseCurrency {
+		return &entity.ExchangeRate{CurrencyCode: currency, Date: date, Rate: 1}, nil
+	}
+	return s.repo.FindRate(ctx, currency, date)
+}
+
+// rateDate отбрасывает время: курсы действуют на календарный день
+func rateDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,119 @@
+package service_impl
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/exchangerates"
+)
+
+// memoryExchangeRateRepository хранит курсы в памяти
+type memoryExchangeRateRepository struct {
+	rates map[string]entity.ExchangeRate
+}
+
+func (m *memoryExchangeRateRepository) SaveRates(ctx context.Context, rates []entity.ExchangeRate) error {
+	for _, r := range rates {
+		m.rates[r.CurrencyCode+r.Date.Format(reportDateLayout)] = r
+	}
+	return nil
+}
+
+func (m *memoryExchangeRateRepository) FindRate(ctx context.Context, currency string, date time.Time) (*entity.ExchangeRate, error) {
+	var found *entity.ExchangeRate
+	for _, r := range m.rates {
+		if r.CurrencyCode == currency && !r.Date.After(date) && (found == nil || r.Date.After(found.Date)) {
+			r := r
+			found = &r
+		}
+	}
+	return found, nil
+}
+
+func (m *memoryExchangeRateRepository) ListRates(ctx context.Context, date time.Time) ([]entity.ExchangeRate, error) {
+	latest := map[string]entity.ExchangeRate{}
+	for _, r := range m.rates {
+		if prev, ok := latest[r.CurrencyCode]; !r.Date.After(date) && (!ok || r.Date.After(prev.Date)) {
+			latest[r.CurrencyCode] = r
+		}
+	}
+	rates := make([]entity.ExchangeRate, 0, len(latest))
+	for _, r := range latest {
+		rates = append(rates, r)
+	}
+	sort.Slice(rates, func(i, j int) bool { return rates[i].CurrencyCode < rates[j].CurrencyCode })
+	return rates, nil
+}
+
+// staticRateSource отдает заданные курсы
+type staticRateSource struct {
+	rates *exchangerates.Rates
+}
+
+func (s *staticRateSource) Latest(ctx context.Context) (*exchangerates.Rates, error) {
+	return s.rates, nil
+}
+
+func TestExchangeRateService_History(t *testing.T) {
+	repo := &memoryExchangeRateRepository{rates: map[string]entity.ExchangeRate{}}
+	source := &staticRateSource{rates: &exchangerates.Rates{Date: contractDate("2026-10-01"), Values: map[string]float64{"USD": 87.1, "KGS": 1}}}
+	svc := NewExchangeRateService(repo, source, logrus.New())
+	ctx := context.Background()
+
+	require.NoError(t, svc.Refresh(ctx))
+	source.rates = &exchangerates.Rates{Date: contractDate("2026-10-05"), Values: map[string]float64{"USD": 87.45, "EUR": 101.8}}
+	require.NoError(t, svc.Refresh(ctx))
+
+	rates, err := svc.Rates(ctx, contractDate("2026-10-03"))
+	require.NoError(t, err)
+	require.Len(t, rates, 1, "the base currency is not stored")
+	assert.Equal(t, 87.1, rates[0].Rate)
+
+	rates, err = svc.Rates(ctx, time.Date(2026, 10, 5, 15, 30, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Len(t, rates, 2)
+
+	rate, err := svc.Rate(ctx, "kgs", contractDate("2020-01-01"))
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, rate.Rate)
+	rate, err = svc.Rate(ctx, "EUR", contractDate("2026-10-04"))
+	require.NoError(t, err)
+	assert.Nil(t, rate)
+}
+
+func TestEsfDocumentService_HistoricalRate(t *testing.T) {
+	repo := &memoryPriceListRepository{docs: map[uuid.UUID]*entity.EsfDocument{}}
+	rates := &memoryExchangeRateRepository{rates: map[string]entity.ExchangeRate{}}
+	rates.SaveRates(context.Background(), []entity.ExchangeRate{
+		{CurrencyCode: "USD", Date: contractDate("2026-09-01"), Rate: 86.98765},
+		{CurrencyCode: "USD", Date: contractDate("2026-10-01"), Rate: 87.45},
+	})
+	svc := NewEsfDocumentService(repo, &gorm.DB{}, logrus.New())
+	svc.SetExchangeRates(NewExchangeRateService(rates, &staticRateSource{}, logrus.New()))
+	ctx, orgID := context.Background(), uuid.New()
+
+	req := &models.EsfCreateDocumentRequest{CurrencyCode: "USD", DeliveryDate: calendarDate("2026-09-15")}
+	_, err := svc.CreateDocument(ctx, orgID, req)
+	require.NoError(t, err)
+	assert.Equal(t, 86.9877, req.CurrencyRate, "the rate of the delivery date, not today's")
+
+	req = &models.EsfCreateDocumentRequest{CurrencyCode: "USD", CurrencyRate: 90, DeliveryDate: calendarDate("2026-09-15")}
+	_, err = svc.CreateDocument(ctx, orgID, req)
+	require.NoError(t, err)
+	assert.Equal(t, 90.0, req.CurrencyRate, "an explicit rate is kept")
+
+	req = &models.EsfCreateDocumentRequest{CurrencyCode: "USD", DeliveryDate: calendarDate("2026-08-15")}
+	_, err = svc.CreateDocument(ctx, orgID, req)
+	assert.Equal(t, apperror.ErrValidation, err.(*apperror.AppError).Code)
+}
@@ -0,0 +1,499 @@
+package service_impl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/jobs"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+	"github.com/rusgainew/tunduck-app/pkg/signature"
+	"github.com/rusgainew/tunduck-app/pkg/storage"
+)
+
+// ExportRunJob тип фоновой задачи выгрузки
+const ExportRunJob = "export.run"
+
+// ExportKeyPrefix префикс файлов выгрузок в объектном хранилище
+const ExportKeyPrefix = "exports/"
+
+const (
+	// exportDocumentBatch размер страницы документов при выгрузке
+	exportDocumentBatch = 100
+	// exportProgressStep как часто (в записях) сохраняется прогресс
+	exportProgressStep = 500
+)
+
+// exportRetryPolicy выгрузка перезапускается целиком, поэтому повторов немного
+var exportRetryPolicy = jobs.RetryPolicy{
+	MaxRetries: 3,
+	Backoff:    jobs.ExponentialBackoff(time.Minute, 10*time.Minute),
+}
+
+// documentCSVHeader колонки выгрузки документов в CSV
+var documentCSVHeader = []string{
+	"id", "created_at", "delivery_date", "operation_type_code", "delivery_type_code", "contractor_tin",
+	"currency_code", "total_currency_value", "total_currency_value_without_taxes", "discount_amount", "surcharge_amount", "payment_code",
+	"tax_rate_vat_code", "supply_contract_number", "entries", "comment",
+}
+
+// exportRunPayload полезная нагрузка задачи выгрузки
+type exportRunPayload struct {
+	ExportID uuid.UUID `json:"export_id"`
+}
+
+// exportService реализует ExportService
+type exportService struct {
+	repo       repository.ExportJobRepository
+	operations services.OperationService
+	docRepo    repository.EsfDocumentRepository
+	auditRepo  repository.AuditLogRepository
+	store      storage.Storage
+	jobs       *jobs.Manager
+	signer     *signature.URLSigner
+	cfg        services.ExportConfig
+	logger     *logger.Logger
+}
+
+// NewExportService создает сервис выгрузок и регистрирует обработчик задачи ExportRunJob.
+// Менеджер задач должен быть запущен после вызова, чтобы воркеры знали обработчик.
+// Каждая выгрузка отслеживается длительной операцией с тем же идентификатором.
+// Без cfg.URLSecret возвращает ошибку: ссылки на результат подписываются этим ключом.
+func NewExportService(
+	repo repository.ExportJobRepository,
+	operations services.OperationService,
+	docRepo repository.EsfDocumentRepository,
+	auditRepo repository.AuditLogRepository,
+	store storage.Storage,
+	manager *jobs.Manager,
+	cfg services.ExportConfig,
+	log *logrus.Logger,
+) (services.ExportService, error) {
+	signer, err := signature.NewURLSigner(cfg.URLSecret)
+	if err != nil {
+		return nil, fmt.Errorf("export links: %w", err)
+	}
+
+	s := &exportService{
+		repo:       repo,
+		operations: operations,
+		docRepo:    docRepo,
+		auditRepo:  auditRepo,
+		store:      store,
+		jobs:       manager,
+		signer:     signer,
+		cfg:        cfg,
+		logger:     logger.New(log),
+	}
+	manager.Register(ExportRunJob, s.handleRun, exportRetryPolicy)
+	return s, nil
+}
+
+// ExportPath путь ресурса выгрузки; после завершения операции он же служит ссылкой на результат
+func ExportPath(id uuid.UUID) string {
+	return "/api/exports/" + id.String()
+}
+
+// ExportDownloadPath путь скачивания файла выгрузки (подписывается вместе со сроком действия)
+func ExportDownloadPath(id uuid.UUID) string {
+	return ExportPath(id) + "/download"
+}
+
+// CreateExport сохраняет выгрузку и ставит задачу ее выполнения
+func (s *exportService) CreateExport(ctx context.Context, req services.ExportRequest) (*services.ExportStatus, error) {
+	if !validExportFormat(req.Type, req.Format) {
+		return nil, apperror.ValidationError("invalid export format")
+	}
+
+	export := &entity.ExportJob{
+		Type:        req.Type,
+		Format:      req.Format,
+		Status:      entity.ExportStatusPending,
+		RequestedBy: req.RequestedBy,
+	}
+
+	var filter interface{}
+	switch req.Type {
+	case services.ExportTypeDocuments:
+		if req.OrganizationID == uuid.Nil {
+			return nil, apperror.ValidationError("invalid organization ID")
+		}
+		orgID := req.OrganizationID
+		export.OrganizationID = &orgID
+		filter = req.DocumentFilter
+	case services.ExportTypeAuditLogs:
+		filter = req.AuditFilter
+	case services.ExportTypeReport:
+		if req.OrganizationID == uuid.Nil {
+			return nil, apperror.ValidationError("invalid organization ID")
+		}
+		if err := validateReportParams(req.Report); err != nil {
+			return nil, err
+		}
+		orgID := req.OrganizationID
+		export.OrganizationID = &orgID
+		filter = req.Report
+	default:
+		return nil, apperror.ValidationError("invalid export type")
+	}
+
+	raw, err := json.Marshal(filter)
+	if err != nil {
+		return nil, apperror.From(err, apperror.ErrInternal, "failed to create export")
+	}
+	export.Filter = raw
+
+	kind := services.OperationKindExport
+	if req.Type == services.ExportTypeReport {
+		kind = services.OperationKindReport
+	}
+	op, err := s.operations.Start(ctx, services.NewOperation{
+		Kind:           kind,
+		RequestedBy:    req.RequestedBy,
+		OrganizationID: export.OrganizationID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	export.ID = op.ID
+
+	if err := s.repo.Create(ctx, export); err != nil {
+		s.operations.Fail(ctx, op.ID, err)
+		return nil, err
+	}
+
+	if _, err := s.jobs.Enqueue(ctx, ExportRunJob, exportRunPayload{ExportID: export.ID}, jobs.WithQueue(s.cfg.Queue)); err != nil {
+		s.logger.Error(ctx, "Failed to enqueue export", err, logrus.Fields{"export_id": export.ID.String()})
+		s.finish(ctx, export.ID, entity.ExportStatusFailed, err)
+		return nil, apperror.From(err, apperror.ErrInternal, "failed to create export")
+	}
+
+	s.logger.Info(ctx, "Export queued", logrus.Fields{
+		"export_id": export.ID.String(),
+		"type":      export.Type,
+		"format":    export.Format,
+	})
+	return s.status(export), nil
+}
+
+// GetExport возвращает состояние выгрузки; для готовой выгрузки выдается новая подписанная ссылка
+func (s *exportService) GetExport(ctx context.Context, id uuid.UUID) (*services.ExportStatus, error) {
+	export, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if export == nil {
+		return nil, apperror.NotFoundError("export")
+	}
+	return s.status(export), nil
+}
+
+// OpenDownload проверяет подпись ссылки и открывает файл готовой выгрузки
+func (s *exportService) OpenDownload(ctx context.Context, id uuid.UUID, expires, sig string) (io.ReadCloser, *entity.ExportJob, error) {
+	if err := s.signer.Verify(ExportDownloadPath(id), expires, sig); err != nil {
+		if errors.Is(err, signature.ErrURLExpired) {
+			return nil, nil, apperror.New(apperror.ErrForbidden, "download link has expired")
+		}
+		return nil, nil, apperror.New(apperror.ErrForbidden, "invalid download link")
+	}
+
+	export, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if export == nil || export.Status != entity.ExportStatusCompleted || expired(export, time.Now()) {
+		return nil, nil, apperror.NotFoundError("export")
+	}
+
+	r, _, err := s.store.Get(ctx, export.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, apperror.NotFoundError("export")
+		}
+		s.logger.Error(ctx, "Failed to open export file", err, logrus.Fields{"export_id": id.String()})
+		return nil, nil, apperror.From(err, apperror.ErrExternalService, "failed to download export")
+	}
+	return r, export, nil
+}
+
+// status дополняет выгрузку прогрессом и ссылкой на скачивание
+func (s *exportService) status(export *entity.ExportJob) *services.ExportStatus {
+	st := &services.ExportStatus{ExportJob: export}
+	switch {
+	case export.Status == entity.ExportStatusCompleted:
+		st.Progress = 100
+	case export.Total > 0:
+		st.Progress = float64(export.Processed) * 100 / float64(export.Total)
+	}
+
+	now := time.Now()
+	if export.Status != entity.ExportStatusCompleted || expired(export, now) {
+		return st
+	}
+
+	linkExpires := now.Add(s.cfg.LinkTTL)
+	if export.ExpiresAt != nil && export.ExpiresAt.Before(linkExpires) {
+		linkExpires = *export.ExpiresAt
+	}
+	st.DownloadURL = strings.TrimRight(s.cfg.BaseURL, "/") + s.signer.Sign(ExportDownloadPath(export.ID), linkExpires)
+	st.DownloadURLExpiresAt = &linkExpires
+	return st
+}
+
+// expired сообщает, что срок хранения файла выгрузки истек
+func expired(export *entity.ExportJob, now time.Time) bool {
+	return export.ExpiresAt != nil && !now.Before(*export.ExpiresAt)
+}
+
+// handleRun выполняет выгрузку. Ошибка возвращается менеджеру задач для повтора;
+// на последней попытке выгрузка помечается как failed.
+func (s *exportService) handleRun(ctx context.Context, job *jobs.Job) error {
+	var payload exportRunPayload
+	if err := job.Decode(&payload); err != nil {
+		return jobs.Permanent(err)
+	}
+
+	export, err := s.repo.GetByID(ctx, payload.ExportID)
+	if err != nil {
+		return err
+	}
+	if export == nil {
+		return jobs.Permanent(fmt.Errorf("export %s not found", payload.ExportID))
+	}
+	if export.Status == entity.ExportStatusCompleted {
+		// Повторная доставка задачи после успешной выгрузки
+		return nil
+	}
+
+	runErr := s.run(ctx, export)
+	if runErr == nil {
+		return nil
+	}
+
+	status := entity.ExportStatusPending
+	if job.Attempt >= job.MaxRetries || jobs.IsPermanent(runErr) {
+		status = entity.ExportStatusFailed
+	}
+	s.finish(ctx, export.ID, status, runErr)
+	return runErr
+}
+
+// run выгружает данные во временный файл и загружает его в хранилище
+func (s *exportService) run(ctx context.Context, export *entity.ExportJob) error {
+	if err := s.repo.Update(ctx, export.ID, map[string]interface{}{
+		"status":    entity.ExportStatusRunning,
+		"processed": 0,
+		"error":     "",
+	}); err != nil {
+		return err
+	}
+	s.operations.Running(ctx, export.ID)
+
+	tmp, err := os.CreateTemp("", "export-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	progress := func(processed int64) {
+		if processed%exportProgressStep != 0 {
+			return
+		}
+		if err := s.repo.Update(ctx, export.ID, map[string]interface{}{"processed": processed}); err != nil {
+			s.logger.Warn(ctx, "Failed to store export progress", logrus.Fields{"export_id": export.ID.String(), "error": err.Error()})
+		}
+		s.operations.Progress(ctx, export.ID, processed, 0)
+	}
+
+	var processed int64
+	switch export.Type {
+	case services.ExportTypeDocuments:
+		processed, err = s.writeDocuments(ctx, export, tmp, progress)
+	case services.ExportTypeAuditLogs:
+		processed, err = s.writeAuditLogs(ctx, export, tmp, progress)
+	case services.ExportTypeReport:
+		processed, err = s.writeReport(ctx, export, tmp, progress)
+	default:
+		err = jobs.Permanent(fmt.Errorf("unknown export type %q", export.Type))
+	}
+	if err != nil {
+		return err
+	}
+
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	key := ExportKeyPrefix + export.ID.String() + "." + export.Format
+	if err := s.store.Put(ctx, key, tmp, size, storage.PutOptions{ContentType: services.ExportContentType(export.Format)}); err != nil {
+		return fmt.Errorf("uploading export file: %w", err)
+	}
+
+	now := time.Now()
+	if err := s.repo.Update(ctx, export.ID, map[string]interface{}{
+		"status":       entity.ExportStatusCompleted,
+		"processed":    processed,
+		"storage_key":  key,
+		"size":         size,
+		"completed_at": now,
+		"expires_at":   now.Add(s.cfg.Retention),
+	}); err != nil {
+		return err
+	}
+	s.operations.Progress(ctx, export.ID, processed, 0)
+	s.operations.Succeed(ctx, export.ID, ExportPath(export.ID))
+
+	s.logger.Info(ctx, "Export completed", logrus.Fields{
+		"export_id": export.ID.String(),
+		"records":   processed,
+		"size":      size,
+	})
+	return nil
+}
+
+// writeDocuments выгружает документы организации страницами по курсору
+func (s *exportService) writeDocuments(ctx context.Context, export *entity.ExportJob, w io.Writer, progress func(int64)) (int64, error) {
+	var filter pagination.DocumentFilterParams
+	if err := json.Unmarshal(export.Filter, &filter); err != nil {
+		return 0, jobs.Permanent(err)
+	}
+	if export.OrganizationID == nil {
+		return 0, jobs.Permanent(errors.New("document export without organization"))
+	}
+	orgID := *export.OrganizationID
+
+	_, total, err := s.docRepo.GetAllDocumentsPaginated(ctx, orgID, pagination.PaginationParams{Page: 1, PageSize: 1}, filter)
+	if err != nil {
+		return 0, err
+	}
+	s.setTotal(ctx, export.ID, total)
+
+	rw, err := newRecordWriter(export.Format, w, documentCSVHeader)
+	if err != nil {
+		return 0, jobs.Permanent(err)
+	}
+
+	var processed int64
+	params := pagination.CursorParams{Limit: exportDocumentBatch, Sort: "created_at", Order: "asc"}
+	for {
+		docs, info, err := s.docRepo.GetAllDocumentsCursor(ctx, orgID, params, filter)
+		if err != nil {
+			return processed, err
+		}
+		for i := range docs {
+			if err := rw.Write(documentCSVRow(&docs[i]), &docs[i]); err != nil {
+				return processed, err
+			}
+			processed++
+			progress(processed)
+		}
+		if !info.HasNext {
+			break
+		}
+		params.Cursor = info.NextCursor
+	}
+	return processed, rw.Close()
+}
+
+// writeAuditLogs выгружает журнал аудита
+func (s *exportService) writeAuditLogs(ctx context.Context, export *entity.ExportJob, w io.Writer, progress func(int64)) (int64, error) {
+	var filter repository.AuditLogFilter
+	if err := json.Unmarshal(export.Filter, &filter); err != nil {
+		return 0, jobs.Permanent(err)
+	}
+
+	_, total, err := s.auditRepo.List(ctx, pagination.PaginationParams{Page: 1, PageSize: 1}, filter)
+	if err != nil {
+		return 0, err
+	}
+	s.setTotal(ctx, export.ID, total)
+
+	rw, err := newRecordWriter(export.Format, w, auditCSVHeader)
+	if err != nil {
+		return 0, jobs.Permanent(err)
+	}
+
+	var processed int64
+	err = s.auditRepo.Each(ctx, filter, func(log *entity.AuditLog) error {
+		if err := rw.Write(auditCSVRow(log), log); err != nil {
+			return err
+		}
+		processed++
+		progress(processed)
+		return nil
+	})
+	if err != nil {
+		return processed, err
+	}
+	return processed, rw.Close()
+}
+
+// setTotal сохраняет ожидаемое число записей для расчета прогресса
+func (s *exportService) setTotal(ctx context.Context, id uuid.UUID, total int64) {
+	if err := s.repo.Update(ctx, id, map[string]interface{}{"total": total}); err != nil {
+		s.logger.Warn(ctx, "Failed to store export total", logrus.Fields{"export_id": id.String(), "error": err.Error()})
+	}
+	s.operations.Progress(ctx, id, 0, total)
+}
+
+// finish фиксирует неудачную попытку выгрузки; pending означает, что задача будет повторена
+func (s *exportService) finish(ctx context.Context, id uuid.UUID, status string, cause error) {
+	if err := s.repo.Update(ctx, id, map[string]interface{}{
+		"status": status,
+		"error":  cause.Error(),
+	}); err != nil {
+		s.logger.Warn(ctx, "Failed to store export status", logrus.Fields{"export_id": id.String(), "error": err.Error()})
+	}
+
+	if status == entity.ExportStatusFailed {
+		s.operations.Fail(ctx, id, cause)
+	} else {
+		s.operations.Retry(ctx, id, cause)
+	}
+}
+
+// documentCSVRow колонки документа в порядке documentCSVHeader
+func documentCSVRow(doc *entity.EsfDocument) []string {
+	var discount, surcharge float64
+	for _, e := range doc.CatalogEntries {
+		discount += e.DiscountAmount
+		surcharge += e.SurchargeAmount
+	}
+	return []string{
+		doc.ID.String(),
+		doc.CreatedAt.UTC().Format(time.RFC3339),
+		doc.DeliveryDate.Format("2006-01-02"),
+		doc.OperationTypeCode,
+		doc.DeliveryTypeCode,
+		doc.ContractorTin,
+		doc.CurrencyCode,
+		strconv.FormatFloat(doc.TotalCurrencyValue, 'f', 2, 64),
+		strconv.FormatFloat(doc.TotalCurrencyValueWithoutTaxes, 'f', 2, 64),
+		strconv.FormatFloat(discount, 'f', 2, 64),
+		strconv.FormatFloat(surcharge, 'f', 2, 64),
+		doc.PaymentCode,
+		doc.TaxRateVATCode,
+		doc.SupplyContractNumber,
+		strconv.Itoa(len(doc.CatalogEntries)),
+		doc.Comment,
+	}
+}
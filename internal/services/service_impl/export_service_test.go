@@ -0,0 +1,212 @@
+package service_impl
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/jobs"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+	"github.com/rusgainew/tunduck-app/pkg/signature"
+	"github.com/rusgainew/tunduck-app/pkg/storage"
+)
+
+// memoryExportJobRepository хранит выгрузки в памяти
+type memoryExportJobRepository struct {
+	jobs map[uuid.UUID]*entity.ExportJob
+}
+
+func (m *memoryExportJobRepository) Create(ctx context.Context, job *entity.ExportJob) error {
+	if job.ID == uuid.Nil {
+		job.ID = uuid.New()
+	}
+	m.jobs[job.ID] = job
+	return nil
+}
+
+func (m *memoryExportJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.ExportJob, error) {
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *job
+	return &copied, nil
+}
+
+func (m *memoryExportJobRepository) Update(ctx context.Context, id uuid.UUID, updates map[string]interface{}) error {
+	job := m.jobs[id]
+	for column, value := range updates {
+		switch column {
+		case "status":
+			job.Status = value.(string)
+		case "error":
+			job.Error = value.(string)
+		case "total":
+			job.Total = value.(int64)
+		case "processed":
+			switch v := value.(type) {
+			case int:
+				job.Processed = int64(v)
+			case int64:
+				job.Processed = v
+			}
+		case "storage_key":
+			job.StorageKey = value.(string)
+		case "size":
+			job.Size = value.(int64)
+		case "completed_at":
+			t := value.(time.Time)
+			job.CompletedAt = &t
+		case "expires_at":
+			t := value.(time.Time)
+			job.ExpiresAt = &t
+		}
+	}
+	return nil
+}
+
+// stubAuditLogRepository отдает заранее заданные записи журнала
+type stubAuditLogRepository struct {
+	repository.AuditLogRepository
+	logs []entity.AuditLog
+}
+
+func (s *stubAuditLogRepository) List(ctx context.Context, params pagination.PaginationParams, filter repository.AuditLogFilter) ([]entity.AuditLog, int64, error) {
+	return s.logs, int64(len(s.logs)), nil
+}
+
+func (s *stubAuditLogRepository) Each(ctx context.Context, filter repository.AuditLogFilter, fn func(*entity.AuditLog) error) error {
+	for i := range s.logs {
+		if err := fn(&s.logs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newTestExportService(t *testing.T, repo repository.ExportJobRepository, ops *memoryOperationRepository, auditRepo repository.AuditLogRepository) (*exportService, storage.Storage) {
+	store, err := storage.NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	// Redis не используется: тесты вызывают обработчик напрямую
+	client := redis.NewClient(&redis.Options{Addr: "localhost:0"})
+	t.Cleanup(func() { client.Close() })
+	manager := jobs.NewManager(client, jobs.Config{Registerer: prometheus.NewRegistry()}, logrus.New())
+
+	cfg := services.ExportConfig{URLSecret: "secret", LinkTTL: time.Hour, Retention: 24 * time.Hour}
+	svc, err := NewExportService(repo, NewOperationService(ops, logrus.New()), nil, auditRepo, store, manager, cfg, logrus.New())
+	require.NoError(t, err)
+	return svc.(*exportService), store
+}
+
+func runJob(t *testing.T, id uuid.UUID) *jobs.Job {
+	payload, err := json.Marshal(exportRunPayload{ExportID: id})
+	require.NoError(t, err)
+	return &jobs.Job{Type: ExportRunJob, Payload: payload, MaxRetries: exportRetryPolicy.MaxRetries}
+}
+
+func TestExportService_AuditLogsCSV(t *testing.T) {
+	id := uuid.New()
+	repo := &memoryExportJobRepository{jobs: map[uuid.UUID]*entity.ExportJob{
+		id: {ID: id, Type: services.ExportTypeAuditLogs, Format: services.ExportFormatCSV, Status: entity.ExportStatusPending, Filter: json.RawMessage(`{}`)},
+	}}
+	auditRepo := &stubAuditLogRepository{logs: []entity.AuditLog{
+		{ID: uuid.New(), Action: "create", Method: "POST", Path: "/api/esf-documents", ActorName: "=cmd()"},
+		{ID: uuid.New(), Action: "delete", Method: "DELETE", Path: "/api/esf-documents/1"},
+	}}
+	ops := newMemoryOperationRepository()
+	ops.ops[id] = &entity.Operation{ID: id, Kind: services.OperationKindExport, State: entity.OperationStatePending}
+	s, store := newTestExportService(t, repo, ops, auditRepo)
+
+	require.NoError(t, s.handleRun(context.Background(), runJob(t, id)))
+
+	export := repo.jobs[id]
+	assert.Equal(t, entity.ExportStatusCompleted, export.Status)
+	assert.Equal(t, int64(2), export.Total)
+	assert.Equal(t, int64(2), export.Processed)
+	require.NotNil(t, export.ExpiresAt)
+
+	r, _, err := store.Get(context.Background(), export.StorageKey)
+	require.NoError(t, err)
+	content, err := io.ReadAll(r)
+	r.Close()
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 3)
+	assert.True(t, strings.HasPrefix(lines[0], "id,created_at,actor_id"))
+	// Формулы экранируются
+	assert.Contains(t, lines[1], "'=cmd()")
+	assert.Equal(t, int64(len(content)), export.Size)
+
+	// Операция завершена и ссылается на выгрузку
+	op := ops.ops[id]
+	assert.Equal(t, entity.OperationStateSucceeded, op.State)
+	assert.Equal(t, int64(2), op.Processed)
+	assert.Equal(t, int64(2), op.Total)
+	assert.Equal(t, ExportPath(id), op.ResultURL)
+
+	// Готовая выгрузка отдается по подписанной ссылке
+	status, err := s.GetExport(context.Background(), id)
+	require.NoError(t, err)
+	assert.Equal(t, float64(100), status.Progress)
+	require.NotEmpty(t, status.DownloadURL)
+
+	link, err := url.Parse(status.DownloadURL)
+	require.NoError(t, err)
+	assert.Equal(t, ExportDownloadPath(id), link.Path)
+
+	rc, downloaded, err := s.OpenDownload(context.Background(), id, link.Query().Get(signature.QueryExpires), link.Query().Get(signature.QuerySignature))
+	require.NoError(t, err)
+	rc.Close()
+	assert.Equal(t, id, downloaded.ID)
+
+	_, _, err = s.OpenDownload(context.Background(), id, link.Query().Get(signature.QueryExpires), "deadbeef")
+	assertErrorCode(t, err, apperror.ErrForbidden)
+}
+
+func TestExportService_FailsOnLastAttempt(t *testing.T) {
+	id := uuid.New()
+	repo := &memoryExportJobRepository{jobs: map[uuid.UUID]*entity.ExportJob{
+		id: {ID: id, Type: "unknown", Format: services.ExportFormatCSV, Status: entity.ExportStatusPending},
+	}}
+	ops := newMemoryOperationRepository()
+	ops.ops[id] = &entity.Operation{ID: id, Kind: services.OperationKindExport, State: entity.OperationStatePending}
+	s, _ := newTestExportService(t, repo, ops, &stubAuditLogRepository{})
+
+	err := s.handleRun(context.Background(), runJob(t, id))
+	require.Error(t, err)
+	assert.True(t, jobs.IsPermanent(err))
+	assert.Equal(t, entity.ExportStatusFailed, repo.jobs[id].Status)
+	assert.Equal(t, entity.OperationStateFailed, ops.ops[id].State)
+	assert.Equal(t, string(apperror.ErrInternal), ops.ops[id].ErrorCode)
+
+	// Ссылка на незавершенную выгрузку не выдается
+	status, err := s.GetExport(context.Background(), id)
+	require.NoError(t, err)
+	assert.Empty(t, status.DownloadURL)
+}
+
+func TestExportService_RejectsInvalidRequest(t *testing.T) {
+	s, _ := newTestExportService(t, &memoryExportJobRepository{jobs: map[uuid.UUID]*entity.ExportJob{}}, newMemoryOperationRepository(), &stubAuditLogRepository{})
+
+	_, err := s.CreateExport(context.Background(), services.ExportRequest{Type: services.ExportTypeDocuments, Format: "xlsx"})
+	assertErrorCode(t, err, apperror.ErrValidation)
+
+	_, err = s.CreateExport(context.Background(), services.ExportRequest{Type: services.ExportTypeDocuments, Format: services.ExportFormatCSV})
+	assertErrorCode(t, err, apperror.ErrValidation)
+}
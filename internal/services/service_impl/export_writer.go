@@ -0,0 +1,80 @@
+package service_impl
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strings"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+)
+
+// recordWriter пишет записи выгрузки в выбранном формате
+type recordWriter interface {
+	// Write пишет запись: row — колонки CSV, value — объект для NDJSON
+	Write(row []string, value interface{}) error
+	// Close дописывает буферизованные данные
+	Close() error
+}
+
+// newRecordWriter создает writer формата services.ExportFormatCSV (с заголовком header)
+// или services.ExportFormatNDJSON
+func newRecordWriter(format string, w io.Writer, header []string) (recordWriter, error) {
+	switch format {
+	case services.ExportFormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(header); err != nil {
+			return nil, err
+		}
+		return &csvRecordWriter{w: cw}, nil
+	case services.ExportFormatNDJSON:
+		return &ndjsonRecordWriter{enc: json.NewEncoder(w)}, nil
+	default:
+		return nil, apperror.ValidationError("invalid export format")
+	}
+}
+
+// validExportFormat проверяет формат для типа выгрузки: данные выгружаются в CSV и NDJSON,
+// отчеты — в XLSX и PDF
+func validExportFormat(exportType, format string) bool {
+	if exportType == services.ExportTypeReport {
+		return format == services.ExportFormatXLSX || format == services.ExportFormatPDF
+	}
+	return format == services.ExportFormatCSV || format == services.ExportFormatNDJSON
+}
+
+type csvRecordWriter struct {
+	w *csv.Writer
+}
+
+func (c *csvRecordWriter) Write(row []string, _ interface{}) error {
+	return c.w.Write(csvSafe(row))
+}
+
+func (c *csvRecordWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type ndjsonRecordWriter struct {
+	enc *json.Encoder
+}
+
+func (n *ndjsonRecordWriter) Write(_ []string, value interface{}) error {
+	return n.enc.Encode(value)
+}
+
+func (n *ndjsonRecordWriter) Close() error {
+	return nil
+}
+
+// csvSafe экранирует значения, которые табличные редакторы выполнили бы как формулу
+func csvSafe(values []string) []string {
+	for i, v := range values {
+		if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+			values[i] = "'" + v
+		}
+	}
+	return values
+}
@@ -0,0 +1,29 @@
+package service_impl
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/featureflag"
+)
+
+// FlagSubjects источник атрибутов организаций (план, дата создания) для правил флагов функций
+type FlagSubjects struct {
+	orgs services.EsfOrganizationService
+}
+
+// NewFlagSubjects создает источник атрибутов поверх сервиса организаций (с его кешем)
+func NewFlagSubjects(orgs services.EsfOrganizationService) *FlagSubjects {
+	return &FlagSubjects{orgs: orgs}
+}
+
+// Subject возвращает атрибуты организации
+func (s *FlagSubjects) Subject(ctx context.Context, orgID uuid.UUID) (featureflag.Subject, error) {
+	org, err := s.orgs.GetOrganizationByID(ctx, orgID)
+	if err != nil {
+		return featureflag.Subject{}, err
+	}
+	return featureflag.Subject{OrganizationID: orgID, Plan: org.Plan, CreatedAt: org.CreatedAt}, nil
+}
@@ -0,0 +1,134 @@
+package service_impl
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/dbresolver"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/migrations"
+)
+
+// mainDatabaseName имя основной БД в отчете о миграциях
+const mainDatabaseName = "main"
+
+// migrationService реализует MigrationService
+type migrationService struct {
+	db      *gorm.DB
+	orgRepo repository.EsfOrganizationRepository
+	logger  *logger.Logger
+}
+
+// NewMigrationService создает новый экземпляр сервиса миграций
+func NewMigrationService(db *gorm.DB, orgRepo repository.EsfOrganizationRepository, log *logrus.Logger) services.MigrationService {
+	return &migrationService{
+		db:      db,
+		orgRepo: orgRepo,
+		logger:  logger.New(log),
+	}
+}
+
+// GetStatus собирает состояние миграций основной БД и БД всех организаций.
+// Ошибка отдельной БД организации попадает в отчет и не прерывает обход.
+func (s *migrationService) GetStatus(ctx context.Context, dryRun bool) (*services.MigrationReport, error) {
+	// Статус основной БД читается с primary: реплика может отставать
+	report := &services.MigrationReport{
+		Main: s.databaseStatus(dbresolver.WithPrimary(ctx), migrations.Main(), s.db, mainDatabaseName, dryRun),
+	}
+
+	orgs, err := s.orgRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report.Tenants = make([]services.DatabaseMigrationStatus, 0, len(orgs))
+	for _, org := range orgs {
+		status := s.tenantStatus(ctx, org.DBName, dryRun)
+		status.OrganizationID = org.ID.String()
+		report.Tenants = append(report.Tenants, status)
+	}
+
+	return report, nil
+}
+
+// tenantStatus подключается к БД организации и возвращает состояние ее миграций
+func (s *migrationService) tenantStatus(ctx context.Context, dbName string, dryRun bool) services.DatabaseMigrationStatus {
+	tenantDB, err := openTenantDatabase(dbName)
+	if err != nil {
+		s.logger.Warn(ctx, "Не удалось подключиться к БД организации", logrus.Fields{"dbName": dbName, "error": err.Error()})
+		return services.DatabaseMigrationStatus{Database: dbName, Error: err.Error()}
+	}
+	defer closeDatabase(tenantDB)
+
+	return s.databaseStatus(ctx, migrations.Tenant(), tenantDB, dbName, dryRun)
+}
+
+// databaseStatus возвращает состояние миграций одной БД
+func (s *migrationService) databaseStatus(ctx context.Context, migrator *migrations.Migrator, db *gorm.DB, name string, dryRun bool) services.DatabaseMigrationStatus {
+	result := services.DatabaseMigrationStatus{Database: name, Pending: []string{}}
+
+	statuses, err := migrator.Status(ctx, db)
+	if err != nil {
+		s.logger.Error(ctx, "Ошибка получения статуса миграций", err, logrus.Fields{"database": name})
+		result.Error = err.Error()
+		return result
+	}
+	result.Migrations = statuses
+
+	for _, status := range statuses {
+		if !status.Applied {
+			result.Pending = append(result.Pending, status.Version)
+		}
+	}
+
+	if dryRun && len(result.Pending) > 0 {
+		plans, err := migrator.DryRun(ctx, db)
+		if err != nil {
+			s.logger.Error(ctx, "Ошибка пробного запуска миграций", err, logrus.Fields{"database": name})
+			result.Error = err.Error()
+			return result
+		}
+		result.DryRun = plans
+	}
+
+	return result
+}
+
+// openTenantDatabase подключается к БД организации с параметрами из DB_* переменных
+func openTenantDatabase(dbName string) (*gorm.DB, error) {
+	return openDatabaseAs(dbName, os.Getenv("DB_USER"), os.Getenv("DB_PASSWORD"))
+}
+
+// openDatabaseAs подключается к БД на сервере из DB_* переменных с заданной ролью
+func openDatabaseAs(dbName, user, password string) (*gorm.DB, error) {
+	sslmode := os.Getenv("DB_SSLMODE")
+	if sslmode == "" {
+		sslmode = "disable"
+	}
+
+	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
+		os.Getenv("DB_HOST"), user, password, dbName, os.Getenv("DB_PORT"), sslmode)
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+		Logger: gormlogger.Default.LogMode(gormlogger.Silent),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to database %s: %w", dbName, err)
+	}
+	return db, nil
+}
+
+// closeDatabase закрывает пул соединений временного подключения
+func closeDatabase(db *gorm.DB) {
+	if sqlDB, err := db.DB(); err == nil {
+		_ = sqlDB.Close()
+	}
+}
@@ -0,0 +1,127 @@
+package service_impl
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+)
+
+// operationService реализует OperationService
+type operationService struct {
+	repo   repository.OperationRepository
+	logger *logger.Logger
+}
+
+// NewOperationService создает сервис длительных операций
+func NewOperationService(repo repository.OperationRepository, log *logrus.Logger) services.OperationService {
+	return &operationService{
+		repo:   repo,
+		logger: logger.New(log),
+	}
+}
+
+// Start создает операцию в состоянии pending
+func (s *operationService) Start(ctx context.Context, req services.NewOperation) (*entity.Operation, error) {
+	if req.Kind == "" || req.RequestedBy == "" {
+		return nil, apperror.ValidationError("invalid operation")
+	}
+
+	op := &entity.Operation{
+		ID:             req.ID,
+		Kind:           req.Kind,
+		State:          entity.OperationStatePending,
+		RequestedBy:    req.RequestedBy,
+		OrganizationID: req.OrganizationID,
+	}
+	if err := s.repo.Create(ctx, op); err != nil {
+		return nil, err
+	}
+	return op, nil
+}
+
+// GetOperation возвращает операцию с прогрессом
+func (s *operationService) GetOperation(ctx context.Context, id uuid.UUID) (*services.OperationStatus, error) {
+	op, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if op == nil {
+		return nil, apperror.NotFoundError("operation")
+	}
+
+	status := &services.OperationStatus{Operation: op}
+	switch {
+	case op.State == entity.OperationStateSucceeded:
+		status.Progress = 100
+	case op.Total > 0:
+		status.Progress = float64(op.Processed) * 100 / float64(op.Total)
+	}
+	return status, nil
+}
+
+// Running отмечает начало очередной попытки
+func (s *operationService) Running(ctx context.Context, id uuid.UUID) {
+	s.update(ctx, id, map[string]interface{}{
+		"state":         entity.OperationStateRunning,
+		"processed":     int64(0),
+		"error_code":    "",
+		"error_message": "",
+		"started_at":    time.Now(),
+	})
+}
+
+// Progress сохраняет прогресс операции
+func (s *operationService) Progress(ctx context.Context, id uuid.UUID, processed, total int64) {
+	updates := map[string]interface{}{"processed": processed}
+	if total > 0 {
+		updates["total"] = total
+	}
+	s.update(ctx, id, updates)
+}
+
+// Succeed завершает операцию успешно
+func (s *operationService) Succeed(ctx context.Context, id uuid.UUID, resultURL string) {
+	s.update(ctx, id, map[string]interface{}{
+		"state":       entity.OperationStateSucceeded,
+		"result_url":  resultURL,
+		"finished_at": time.Now(),
+	})
+}
+
+// Retry сохраняет ошибку попытки, операция ожидает повтора
+func (s *operationService) Retry(ctx context.Context, id uuid.UUID, cause error) {
+	updates := operationError(cause)
+	updates["state"] = entity.OperationStatePending
+	s.update(ctx, id, updates)
+}
+
+// Fail завершает операцию с ошибкой
+func (s *operationService) Fail(ctx context.Context, id uuid.UUID, cause error) {
+	updates := operationError(cause)
+	updates["state"] = entity.OperationStateFailed
+	updates["finished_at"] = time.Now()
+	s.update(ctx, id, updates)
+}
+
+func (s *operationService) update(ctx context.Context, id uuid.UUID, updates map[string]interface{}) {
+	if err := s.repo.Update(ctx, id, updates); err != nil {
+		s.logger.Warn(ctx, "Failed to store operation state", logrus.Fields{"operation_id": id.String(), "error": err.Error()})
+	}
+}
+
+// operationError колонки с кодом и текстом ошибки; для ошибок без кода используется INTERNAL_SERVER_ERROR
+func operationError(cause error) map[string]interface{} {
+	appErr := apperror.From(cause, apperror.ErrInternal, cause.Error())
+	return map[string]interface{}{
+		"error_code":    string(appErr.Code),
+		"error_message": appErr.Message,
+	}
+}
@@ -0,0 +1,123 @@
+package service_impl
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// memoryOperationRepository хранит операции в памяти
+type memoryOperationRepository struct {
+	ops map[uuid.UUID]*entity.Operation
+}
+
+func newMemoryOperationRepository() *memoryOperationRepository {
+	return &memoryOperationRepository{ops: map[uuid.UUID]*entity.Operation{}}
+}
+
+func (m *memoryOperationRepository) Create(ctx context.Context, op *entity.Operation) error {
+	if op.ID == uuid.Nil {
+		op.ID = uuid.New()
+	}
+	m.ops[op.ID] = op
+	return nil
+}
+
+func (m *memoryOperationRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Operation, error) {
+	op, ok := m.ops[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *op
+	return &copied, nil
+}
+
+func (m *memoryOperationRepository) Update(ctx context.Context, id uuid.UUID, updates map[string]interface{}) error {
+	op, ok := m.ops[id]
+	if !ok {
+		return errors.New("operation not found")
+	}
+	for column, value := range updates {
+		switch column {
+		case "state":
+			op.State = value.(string)
+		case "total":
+			op.Total = value.(int64)
+		case "processed":
+			op.Processed = value.(int64)
+		case "result_url":
+			op.ResultURL = value.(string)
+		case "error_code":
+			op.ErrorCode = value.(string)
+		case "error_message":
+			op.ErrorMessage = value.(string)
+		case "started_at":
+			t := value.(time.Time)
+			op.StartedAt = &t
+		case "finished_at":
+			t := value.(time.Time)
+			op.FinishedAt = &t
+		}
+	}
+	return nil
+}
+
+func TestOperationService_Lifecycle(t *testing.T) {
+	repo := newMemoryOperationRepository()
+	s := NewOperationService(repo, logrus.New())
+	ctx := context.Background()
+
+	_, err := s.Start(ctx, services.NewOperation{Kind: services.OperationKindExport})
+	assertErrorCode(t, err, apperror.ErrValidation)
+
+	op, err := s.Start(ctx, services.NewOperation{Kind: services.OperationKindExport, RequestedBy: uuid.NewString()})
+	require.NoError(t, err)
+	assert.Equal(t, entity.OperationStatePending, op.State)
+
+	s.Running(ctx, op.ID)
+	s.Progress(ctx, op.ID, 0, 200)
+	s.Progress(ctx, op.ID, 50, 0)
+	status, err := s.GetOperation(ctx, op.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.OperationStateRunning, status.State)
+	assert.Equal(t, float64(25), status.Progress)
+	assert.False(t, status.Done())
+
+	// Ошибка попытки сохраняет код, следующая попытка его сбрасывает
+	s.Retry(ctx, op.ID, apperror.New(apperror.ErrExternalService, "storage unavailable"))
+	status, _ = s.GetOperation(ctx, op.ID)
+	assert.Equal(t, entity.OperationStatePending, status.State)
+	assert.Equal(t, string(apperror.ErrExternalService), status.ErrorCode)
+	assert.Equal(t, "storage unavailable", status.ErrorMessage)
+
+	s.Running(ctx, op.ID)
+	s.Succeed(ctx, op.ID, "/api/exports/"+op.ID.String())
+	status, _ = s.GetOperation(ctx, op.ID)
+	assert.Equal(t, entity.OperationStateSucceeded, status.State)
+	assert.Equal(t, float64(100), status.Progress)
+	assert.Empty(t, status.ErrorCode)
+	assert.Equal(t, "/api/exports/"+op.ID.String(), status.ResultURL)
+	assert.NotNil(t, status.FinishedAt)
+
+	failed, err := s.Start(ctx, services.NewOperation{Kind: services.OperationKindExport, RequestedBy: uuid.NewString()})
+	require.NoError(t, err)
+	s.Fail(ctx, failed.ID, errors.New("disk full"))
+	status, _ = s.GetOperation(ctx, failed.ID)
+	assert.Equal(t, entity.OperationStateFailed, status.State)
+	assert.Equal(t, string(apperror.ErrInternal), status.ErrorCode)
+	assert.Equal(t, "disk full", status.ErrorMessage)
+	assert.True(t, status.Done())
+
+	_, err = s.GetOperation(ctx, uuid.New())
+	assertErrorCode(t, err, apperror.ErrNotFound)
+}
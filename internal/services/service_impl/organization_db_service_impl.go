@@ -2,7 +2,10 @@ package service_impl
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
@@ -10,10 +13,39 @@ import (
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
 
-	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/lock"
+	"github.com/rusgainew/tunduck-app/pkg/migrations"
 )
 
-// OrganizationDBServiceImpl реализация сервиса для управления динамическими БД организаций
+// credentialRefresh как часто пул сверяет учетные данные с сохраненными: после ротации
+// на другом экземпляре пул переподключается не позже чем через этот интервал
+const credentialRefresh = time.Minute
+
+// Блокировка создания БД организации: несколько экземпляров не создают и не мигрируют одну БД одновременно
+const (
+	provisionLockTTL  = 30 * time.Second
+	provisionLockWait = 2 * time.Minute
+)
+
+// tenantPool пул подключений к БД организации и роль, с которой он открыт
+type tenantPool struct {
+	db        *gorm.DB
+	username  string
+	checkedAt time.Time
+}
+
+// tenantConnection параметры подключения к БД организации
+type tenantConnection struct {
+	database string
+	username string
+	password string
+}
+
+// OrganizationDBServiceImpl реализация сервиса для управления динамическими БД организаций.
+// Подключения к БД организаций переиспользуются и закрываются при остановке (Stop).
+// С SetCredentials пулы открываются с учетными данными организации, если они выданы.
+// С SetLocker создание БД организации выполняется под распределенной блокировкой.
 type OrganizationDBServiceImpl struct {
 	mainDB     *gorm.DB
 	logger     *logrus.Logger
@@ -22,6 +54,12 @@ type OrganizationDBServiceImpl struct {
 	dbUser     string
 	dbPassword string
 	dbSSLMode  string
+
+	credentials repository.TenantCredentialRepository
+	locker      *lock.Locker
+
+	mu    sync.Mutex
+	pools map[uuid.UUID]*tenantPool
 }
 
 // NewOrganizationDBService создает новый сервис управления БД организаций
@@ -38,17 +76,54 @@ func NewOrganizationDBService(
 		dbUser:     dbUser,
 		dbPassword: dbPassword,
 		dbSSLMode:  dbSSLMode,
+		pools:      map[uuid.UUID]*tenantPool{},
 	}
 }
 
+// SetCredentials включает подключение к БД организаций с учетными данными из репозитория;
+// вызывается до первого подключения
+func (s *OrganizationDBServiceImpl) SetCredentials(repo repository.TenantCredentialRepository) {
+	s.credentials = repo
+}
+
+// SetLocker включает распределенную блокировку создания БД организации; вызывается до первого создания
+func (s *OrganizationDBServiceImpl) SetLocker(locker *lock.Locker) {
+	s.locker = locker
+}
+
 // getOrganizationDBName возвращает имя БД для организации
 func (s *OrganizationDBServiceImpl) getOrganizationDBName(organizationID uuid.UUID) string {
 	// Заменяем дефисы на подчеркивание для корректности имени БД
 	return fmt.Sprintf("org_%s", organizationID.String()[:8])
 }
 
-// CreateOrganizationDatabase создает отдельную БД для организации с таблицами EsfDocument и EsfEntries
+// CreateOrganizationDatabase создает отдельную БД для организации с таблицами EsfDocument и EsfEntries.
+// С SetLocker другой экземпляр, создающий ту же БД, дожидается завершения, а не выполняет миграции параллельно;
+// при недоступном Redis БД создается без блокировки.
 func (s *OrganizationDBServiceImpl) CreateOrganizationDatabase(ctx context.Context, organizationID uuid.UUID) error {
+	if s.locker == nil {
+		return s.createOrganizationDatabase(ctx, organizationID)
+	}
+
+	var ran bool
+	name := "tenant:provision:" + organizationID.String()
+	err := s.locker.Do(ctx, name, provisionLockTTL, func(ctx context.Context) error {
+		ran = true
+		return s.createOrganizationDatabase(ctx, organizationID)
+	}, lock.WithWait(provisionLockWait))
+	switch {
+	case ran || err == nil || ctx.Err() != nil:
+		return err
+	case errors.Is(err, lock.ErrNotAcquired):
+		return fmt.Errorf("organization database %s is being provisioned by another instance: %w", organizationID, err)
+	default:
+		// CREATE DATABASE и миграции идемпотентны, поэтому недоступность Redis не блокирует создание организаций
+		s.logger.WithError(err).WithField("organization_id", organizationID).Warn("Provisioning lock unavailable, creating organization database without it")
+		return s.createOrganizationDatabase(ctx, organizationID)
+	}
+}
+
+func (s *OrganizationDBServiceImpl) createOrganizationDatabase(ctx context.Context, organizationID uuid.UUID) error {
 	dbName := s.getOrganizationDBName(organizationID)
 
 	s.logger.WithFields(logrus.Fields{
@@ -92,7 +167,7 @@ func (s *OrganizationDBServiceImpl) CreateOrganizationDatabase(ctx context.Conte
 	}
 
 	// Выполняем миграции для новой БД (создаем таблицы EsfDocument и EsfEntries)
-	if err := newDB.AutoMigrate(&entity.EsfDocument{}, &entity.EsfEntries{}); err != nil {
+	if _, err := migrations.Tenant().Up(ctx, newDB); err != nil {
 		s.logger.WithError(err).Error("Failed to migrate organization database")
 		return fmt.Errorf("failed to migrate organization database: %w", err)
 	}
@@ -105,12 +180,71 @@ func (s *OrganizationDBServiceImpl) CreateOrganizationDatabase(ctx context.Conte
 	return nil
 }
 
-// GetOrganizationDatabase получает подключение к БД организации
+// GetOrganizationDatabase получает подключение к БД организации; пул создается при первом обращении
+// и пересоздается, если учетные данные организации сменились
 func (s *OrganizationDBServiceImpl) GetOrganizationDatabase(ctx context.Context, organizationID uuid.UUID) (*gorm.DB, error) {
-	dbName := s.getOrganizationDBName(organizationID)
+	s.mu.Lock()
+	pool, ok := s.pools[organizationID]
+	fresh := ok && (s.credentials == nil || time.Since(pool.checkedAt) < credentialRefresh)
+	s.mu.Unlock()
+	if fresh {
+		return pool.db, nil
+	}
+
+	conn, err := s.connection(ctx, organizationID)
+	if err != nil {
+		if ok {
+			// Текущий пул продолжает работать, проверка повторится при следующем обращении
+			return pool.db, nil
+		}
+		return nil, err
+	}
+	if ok && pool.username == conn.username {
+		s.mu.Lock()
+		pool.checkedAt = time.Now()
+		s.mu.Unlock()
+		return pool.db, nil
+	}
+	return s.replacePool(organizationID, conn)
+}
+
+// Reconnect открывает новый пул с текущими учетными данными организации и закрывает старый после
+// завершения начатых на нем запросов. Без открытого пула ничего не делает.
+func (s *OrganizationDBServiceImpl) Reconnect(ctx context.Context, organizationID uuid.UUID) error {
+	s.mu.Lock()
+	_, ok := s.pools[organizationID]
+	s.mu.Unlock()
+	if !ok {
+		return nil
+	}
 
+	conn, err := s.connection(ctx, organizationID)
+	if err != nil {
+		return err
+	}
+	_, err = s.replacePool(organizationID, conn)
+	return err
+}
+
+// connection возвращает сохраненные учетные данные организации или общие DB_USER и DB_PASSWORD
+func (s *OrganizationDBServiceImpl) connection(ctx context.Context, organizationID uuid.UUID) (tenantConnection, error) {
+	if s.credentials != nil {
+		credential, err := s.credentials.Get(ctx, organizationID)
+		if err != nil {
+			return tenantConnection{}, err
+		}
+		if credential != nil {
+			return tenantConnection{database: credential.Database, username: credential.Username, password: credential.Password}, nil
+		}
+	}
+	return tenantConnection{database: s.getOrganizationDBName(organizationID), username: s.dbUser, password: s.dbPassword}, nil
+}
+
+// replacePool открывает пул и подменяет им текущий. Старый пул закрывается в фоне: Close ждет
+// запросы, уже выполняющиеся на нем, а новые запросы идут в новый пул.
+func (s *OrganizationDBServiceImpl) replacePool(organizationID uuid.UUID, conn tenantConnection) (*gorm.DB, error) {
 	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
-		s.dbHost, s.dbUser, s.dbPassword, dbName, s.dbPort, s.dbSSLMode)
+		s.dbHost, conn.username, conn.password, conn.database, s.dbPort, s.dbSSLMode)
 
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
@@ -120,9 +254,57 @@ func (s *OrganizationDBServiceImpl) GetOrganizationDatabase(ctx context.Context,
 		return nil, fmt.Errorf("failed to connect to organization database: %w", err)
 	}
 
+	s.mu.Lock()
+	old := s.pools[organizationID]
+	if old != nil && old.username == conn.username {
+		// Параллельный вызов уже переподключился с теми же учетными данными
+		old.checkedAt = time.Now()
+		s.mu.Unlock()
+		closeDatabase(db)
+		return old.db, nil
+	}
+	s.pools[organizationID] = &tenantPool{db: db, username: conn.username, checkedAt: time.Now()}
+	s.mu.Unlock()
+
+	if old != nil {
+		s.logger.WithFields(logrus.Fields{"organization_id": organizationID, "username": conn.username}).Info("Organization database pool reconnected")
+		go closeDatabase(old.db)
+	}
 	return db, nil
 }
 
+// Stop закрывает пулы подключений к БД организаций
+func (s *OrganizationDBServiceImpl) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []error
+	for id := range s.pools {
+		if err := s.closePool(id); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// closePool закрывает и забывает пул БД организации; вызывается под s.mu
+func (s *OrganizationDBServiceImpl) closePool(organizationID uuid.UUID) error {
+	pool, ok := s.pools[organizationID]
+	if !ok {
+		return nil
+	}
+	delete(s.pools, organizationID)
+
+	sqlDB, err := pool.db.DB()
+	if err != nil {
+		return err
+	}
+	if err := sqlDB.Close(); err != nil {
+		return fmt.Errorf("close organization %s database: %w", organizationID, err)
+	}
+	return nil
+}
+
 // DeleteOrganizationDatabase удаляет БД организации
 func (s *OrganizationDBServiceImpl) DeleteOrganizationDatabase(ctx context.Context, organizationID uuid.UUID) error {
 	dbName := s.getOrganizationDBName(organizationID)
@@ -132,6 +314,13 @@ func (s *OrganizationDBServiceImpl) DeleteOrganizationDatabase(ctx context.Conte
 		"database_name":   dbName,
 	}).Info("Deleting organization database")
 
+	// Открытые подключения не дают удалить БД
+	s.mu.Lock()
+	if err := s.closePool(organizationID); err != nil {
+		s.logger.WithError(err).Warn("Failed to close organization database pool")
+	}
+	s.mu.Unlock()
+
 	// Подключаемся к главной БД для удаления БД организации
 	dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=postgres port=%s sslmode=%s",
 		s.dbHost, s.dbUser, s.dbPassword, s.dbPort, s.dbSSLMode)
@@ -0,0 +1,226 @@
+package service_impl
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+)
+
+type priceListService struct {
+	repo   repository.EsfDocumentRepository
+	logger *logger.Logger
+}
+
+// NewPriceListService создает сервис прайс-листов
+func NewPriceListService(repo repository.EsfDocumentRepository, log *logrus.Logger) services.PriceListService {
+	return &priceListService{
+		repo:   repo,
+		logger: logger.New(log),
+	}
+}
+
+// List возвращает прайс-листы организации без цен
+func (s *priceListService) List(ctx context.Context, orgID uuid.UUID) ([]entity.PriceList, error) {
+	lists, err := s.repo.ListPriceLists(ctx, orgID)
+	if err != nil {
+		return nil, apperror.DatabaseErrorFrom("fetching price lists", err)
+	}
+	if lists == nil {
+		lists = []entity.PriceList{}
+	}
+	return lists, nil
+}
+
+// Get возвращает прайс-лист с ценами
+func (s *priceListService) Get(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*entity.PriceList, error) {
+	list, err := s.repo.GetPriceList(ctx, orgID, id)
+	if err != nil {
+		return nil, apperror.DatabaseErrorFrom("fetching price list", err)
+	}
+	return list, nil
+}
+
+// Create сохраняет прайс-лист
+func (s *priceListService) Create(ctx context.Context, orgID uuid.UUID, req *models.PriceListRequest) (*entity.PriceList, error) {
+	list, appErr := priceListFromRequest(req)
+	if appErr != nil {
+		return nil, appErr
+	}
+	if err := s.repo.CreatePriceList(ctx, orgID, list); err != nil {
+		return nil, apperror.DatabaseErrorFrom("creating price list", err)
+	}
+
+	s.logger.Info(ctx, "Price list created", logrus.Fields{"org_id": orgID.String(), "price_list_id": list.ID.String(), "items": len(list.Items)})
+	return list, nil
+}
+
+// Update заменяет прайс-лист вместе с ценами
+func (s *priceListService) Update(ctx context.Context, orgID uuid.UUID, id uuid.UUID, req *models.PriceListRequest) (*entity.PriceList, error) {
+	list, appErr := priceListFromRequest(req)
+	if appErr != nil {
+		return nil, appErr
+	}
+	list.ID = id
+	if err := s.repo.UpdatePriceList(ctx, orgID, list); err != nil {
+		return nil, apperror.DatabaseErrorFrom("updating price list", err)
+	}
+
+	s.logger.Info(ctx, "Price list updated", logrus.Fields{"org_id": orgID.String(), "price_list_id": id.String(), "items": len(list.Items)})
+	return s.Get(ctx, orgID, id)
+}
+
+// Delete удаляет прайс-лист
+func (s *priceListService) Delete(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error {
+	if err := s.repo.DeletePriceList(ctx, orgID, id); err != nil {
+		return apperror.DatabaseErrorFrom("deleting price list", err)
+	}
+
+	s.logger.Info(ctx, "Price list deleted", logrus.Fields{"org_id": orgID.String(), "price_list_id": id.String()})
+	return nil
+}
+
+// Prices возвращает по одной цене на товар: из прайс-листа контрагента, а если там товара нет —
+// из прайс-листа его группы
+func (s *priceListService) Prices(ctx context.Context, orgID uuid.UUID, contractorTin string, currency string, date time.Time) ([]services.ResolvedPrice, error) {
+	lists, err := s.repo.FindPriceLists(ctx, orgID, contractorTin, strings.ToUpper(currency), date)
+	if err != nil {
+		return nil, apperror.DatabaseErrorFrom("finding price lists", err)
+	}
+
+	prices := []services.ResolvedPrice{}
+	seen := map[string]bool{}
+	for _, list := range lists {
+		var added []string
+		for _, item := range list.Items {
+			// Цена для любой единицы из более важного прайс-листа перекрывает все цены товара ниже
+			key := item.SalesTaxCode + "|" + item.UnitClassificationCode
+			if seen[key] || seen[item.SalesTaxCode+"|"] {
+				continue
+			}
+			added = append(added, key)
+			prices = append(prices, services.ResolvedPrice{
+				SalesTaxCode:           item.SalesTaxCode,
+				UnitClassificationCode: item.UnitClassificationCode,
+				Price:                  item.Price,
+				FloorPrice:             item.FloorPrice,
+				PriceListID:            list.ID,
+				PriceListName:          list.Name,
+			})
+		}
+		for _, key := range added {
+			seen[key] = true
+		}
+	}
+	sort.SliceStable(prices, func(i, j int) bool { return prices[i].SalesTaxCode < prices[j].SalesTaxCode })
+	return prices, nil
+}
+
+// ListGroups возвращает группы покупателей с их контрагентами
+func (s *priceListService) ListGroups(ctx context.Context, orgID uuid.UUID) ([]services.CustomerGroup, error) {
+	members, err := s.repo.ListCustomerGroupMembers(ctx, orgID)
+	if err != nil {
+		return nil, apperror.DatabaseErrorFrom("fetching customer groups", err)
+	}
+
+	groups := []services.CustomerGroup{}
+	for _, m := range members {
+		if len(groups) == 0 || groups[len(groups)-1].Name != m.GroupName {
+			groups = append(groups, services.CustomerGroup{Name: m.GroupName})
+		}
+		last := &groups[len(groups)-1]
+		last.ContractorTins = append(last.ContractorTins, m.ContractorTin)
+	}
+	return groups, nil
+}
+
+// SetGroup заменяет состав группы покупателей
+func (s *priceListService) SetGroup(ctx context.Context, orgID uuid.UUID, name string, tins []string) (*services.CustomerGroup, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, apperror.ValidationError("customer group name is required")
+	}
+	unique := []string{}
+	seen := map[string]bool{}
+	for _, tin := range tins {
+		if !seen[tin] {
+			seen[tin] = true
+			unique = append(unique, tin)
+		}
+	}
+	if err := s.repo.SetCustomerGroupMembers(ctx, orgID, name, unique); err != nil {
+		return nil, apperror.DatabaseErrorFrom("updating customer group", err)
+	}
+
+	s.logger.Info(ctx, "Customer group updated", logrus.Fields{"org_id": orgID.String(), "group": name, "members": len(unique)})
+	return &services.CustomerGroup{Name: name, ContractorTins: unique}, nil
+}
+
+func priceListFromRequest(req *models.PriceListRequest) (*entity.PriceList, *apperror.AppError) {
+	group := strings.TrimSpace(req.CustomerGroup)
+	if (req.ContractorTin == "") == (group == "") {
+		return nil, apperror.ValidationError("price list needs either contractorTin or customerGroup")
+	}
+	if req.ValidFrom != nil && req.ValidTo != nil && req.ValidTo.Before(*req.ValidFrom) {
+		return nil, apperror.ValidationError("price list validTo is before validFrom")
+	}
+
+	list := &entity.PriceList{
+		Name:          strings.TrimSpace(req.Name),
+		ContractorTin: req.ContractorTin,
+		CustomerGroup: group,
+		CurrencyCode:  strings.ToUpper(req.CurrencyCode),
+		ValidFrom:     req.ValidFrom,
+		ValidTo:       req.ValidTo,
+	}
+	seen := map[string]bool{}
+	for _, item := range req.Items {
+		key := item.SalesTaxCode + "|" + item.UnitClassificationCode
+		if seen[key] {
+			return nil, apperror.ValidationError("duplicate price for {code}").WithParams(map[string]interface{}{"code": item.SalesTaxCode})
+		}
+		seen[key] = true
+		if item.FloorPrice > item.Price {
+			return nil, apperror.ValidationError("floor price of {code} is above its price").WithParams(map[string]interface{}{"code": item.SalesTaxCode})
+		}
+		list.Items = append(list.Items, entity.PriceListItem{
+			SalesTaxCode:           item.SalesTaxCode,
+			UnitClassificationCode: item.UnitClassificationCode,
+			Price:                  item.Price,
+			FloorPrice:             item.FloorPrice,
+		})
+	}
+	return list, nil
+}
+
+// listPrice находит цену позиции в прайс-листах, упорядоченных по приоритету.
+// В одном прайс-листе цена с той же единицей измерения важнее цены для любой единицы
+func listPrice(lists []entity.PriceList, code, unit string) *entity.PriceListItem {
+	for i := range lists {
+		var any *entity.PriceListItem
+		for j := range lists[i].Items {
+			item := &lists[i].Items[j]
+			if !item.Matches(code, unit) {
+				continue
+			}
+			if item.UnitClassificationCode != "" {
+				return item
+			}
+			any = item
+		}
+		if any != nil {
+			return any
+		}
+	}
+	return nil
+}
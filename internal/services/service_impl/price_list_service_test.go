@@ -0,0 +1,142 @@
+package service_impl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+)
+
+// memoryPriceListRepository отдает заранее упорядоченные прайс-листы и хранит документы в памяти
+type memoryPriceListRepository struct {
+	repository.EsfDocumentRepository
+	lists   []entity.PriceList
+	members []entity.CustomerGroupMember
+	docs    map[uuid.UUID]*entity.EsfDocument
+}
+
+func (m *memoryPriceListRepository) CreatePriceList(ctx context.Context, orgID uuid.UUID, list *entity.PriceList) error {
+	list.ID = uuid.New()
+	m.lists = append(m.lists, *list)
+	return nil
+}
+
+func (m *memoryPriceListRepository) FindPriceLists(ctx context.Context, orgID uuid.UUID, contractorTin string, currency string, date time.Time) ([]entity.PriceList, error) {
+	var found []entity.PriceList
+	for _, list := range m.lists {
+		if list.CurrencyCode == currency && list.ValidOn(date) {
+			found = append(found, list)
+		}
+	}
+	return found, nil
+}
+
+func (m *memoryPriceListRepository) ListCustomerGroupMembers(ctx context.Context, orgID uuid.UUID) ([]entity.CustomerGroupMember, error) {
+	return m.members, nil
+}
+
+func (m *memoryPriceListRepository) SetCustomerGroupMembers(ctx context.Context, orgID uuid.UUID, group string, tins []string) error {
+	for _, tin := range tins {
+		m.members = append(m.members, entity.CustomerGroupMember{GroupName: group, ContractorTin: tin})
+	}
+	return nil
+}
+
+func (m *memoryPriceListRepository) CreateDocument(ctx context.Context, orgID uuid.UUID, doc *entity.EsfDocument) error {
+	copied := *doc
+	m.docs[doc.ID] = &copied
+	return nil
+}
+
+func TestPriceListService_CreateAndPrices(t *testing.T) {
+	repo := &memoryPriceListRepository{}
+	svc := NewPriceListService(repo, logrus.New())
+	ctx, orgID := context.Background(), uuid.New()
+
+	_, err := svc.Create(ctx, orgID, &models.PriceListRequest{Name: "Both", ContractorTin: "01234567890123", CustomerGroup: "wholesale", CurrencyCode: "KGS"})
+	assert.Equal(t, apperror.ErrValidation, err.(*apperror.AppError).Code, "contractor and group at once")
+
+	_, err = svc.Create(ctx, orgID, &models.PriceListRequest{Name: "Floor", ContractorTin: "01234567890123", CurrencyCode: "KGS",
+		Items: []models.PriceListItemModel{{SalesTaxCode: "1001", Price: 90, FloorPrice: 100}}})
+	assert.Equal(t, apperror.ErrValidation, err.(*apperror.AppError).Code, "floor above price")
+
+	_, err = svc.Create(ctx, orgID, &models.PriceListRequest{Name: "Own", ContractorTin: "01234567890123", CurrencyCode: "kgs",
+		Items: []models.PriceListItemModel{{SalesTaxCode: "1001", UnitClassificationCode: "796", Price: 110}}})
+	require.NoError(t, err)
+	_, err = svc.Create(ctx, orgID, &models.PriceListRequest{Name: "Wholesale", CustomerGroup: "wholesale", CurrencyCode: "KGS",
+		Items: []models.PriceListItemModel{
+			{SalesTaxCode: "1001", UnitClassificationCode: "796", Price: 120, FloorPrice: 100},
+			{SalesTaxCode: "1002", Price: 45},
+		}})
+	require.NoError(t, err)
+
+	prices, err := svc.Prices(ctx, orgID, "01234567890123", "kgs", contractDate("2026-03-01"))
+	require.NoError(t, err)
+	require.Len(t, prices, 2)
+	assert.Equal(t, 110.0, prices[0].Price, "the contractor's own price wins over the group price")
+	assert.Equal(t, "Own", prices[0].PriceListName)
+	assert.Equal(t, "1002", prices[1].SalesTaxCode)
+
+	group, err := svc.SetGroup(ctx, orgID, " wholesale ", []string{"01234567890123", "01234567890123"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"01234567890123"}, group.ContractorTins)
+	groups, err := svc.ListGroups(ctx, orgID)
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, "wholesale", groups[0].Name)
+}
+
+func TestEsfDocumentService_PriceListFloor(t *testing.T) {
+	repo := &memoryPriceListRepository{docs: map[uuid.UUID]*entity.EsfDocument{}, lists: []entity.PriceList{{
+		ID: uuid.New(), Name: "Wholesale", CurrencyCode: "KGS",
+		Items: []entity.PriceListItem{{SalesTaxCode: "1001", Price: 120, FloorPrice: 100}},
+	}}}
+	svc := NewEsfDocumentService(repo, &gorm.DB{}, logrus.New())
+	ctx, orgID := context.Background(), uuid.New()
+
+	req := &models.EsfCreateDocumentRequest{
+		ContractorTin: "01234567890123", CurrencyCode: "KGS", DeliveryDate: calendarDate("2026-03-01"),
+		IsPriceWithoutTaxes: true, TaxRateVATCode: "12",
+		CatalogEntries: []models.EsfEntriesModel{{SalesTaxCode: "1001", UnitClassificationCode: "796", Quantity: 2}},
+	}
+	created, err := svc.CreateDocument(ctx, orgID, req)
+	require.NoError(t, err)
+	assert.Empty(t, created.Warnings)
+	entry := req.CatalogEntries[0]
+	assert.Equal(t, 120.0, entry.Price, "an empty price is taken from the price list")
+	assert.Equal(t, 240.0, entry.AmountWithoutTaxes, "amounts are computed from the filled price")
+	assert.Equal(t, 28.8, entry.VatAmount)
+	assert.Equal(t, 268.8, entry.TotalAmount)
+	assert.Equal(t, 240.0, req.TotalCurrencyValueWithoutTaxes)
+	assert.Equal(t, 268.8, req.TotalCurrencyValue)
+
+	unknown := *req
+	unknown.CatalogEntries = []models.EsfEntriesModel{{SalesTaxCode: "9999", UnitClassificationCode: "796", Quantity: 1}}
+	_, err = svc.CreateDocument(ctx, orgID, &unknown)
+	assert.Equal(t, apperror.ErrValidation, err.(*apperror.AppError).Code, "a line without a price needs a price list price")
+
+	req.CatalogEntries[0].DiscountAmount = 50
+	_, err = svc.CreateDocument(ctx, orgID, req)
+	assert.Equal(t, apperror.ErrForbidden, err.(*apperror.AppError).Code, "a line discount lowers the selling price too")
+
+	req.CatalogEntries[0].Price, req.CatalogEntries[0].DiscountAmount = 80, 0
+	_, err = svc.CreateDocument(ctx, orgID, req)
+	assert.Equal(t, apperror.ErrForbidden, err.(*apperror.AppError).Code)
+
+	admin := context.WithValue(ctx, rbac.ContextKey, rbac.NewUserContext(uuid.New(), rbac.RoleAdmin))
+	created, err = svc.CreateDocument(admin, orgID, req)
+	require.NoError(t, err)
+	assert.Equal(t, []string{models.WarningPriceBelowFloor}, warningCodes(created.Warnings))
+	assert.Equal(t, 80.0, repo.docs[uuid.MustParse(created.DocumentUuid)].CatalogEntries[0].Price)
+}
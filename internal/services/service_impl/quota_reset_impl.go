@@ -0,0 +1,63 @@
+package service_impl
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/pkg/jobs"
+	"github.com/rusgainew/tunduck-app/pkg/quota"
+)
+
+// QuotaResetJob тип отложенной задачи сброса месячного расхода квот; она же ключ расписания,
+// поэтому в расписании всегда одна такая задача
+const QuotaResetJob = "quota.reset"
+
+// quotaResetRetryPolicy сброс идемпотентен и повторяется, пока Redis недоступен
+var quotaResetRetryPolicy = jobs.RetryPolicy{
+	MaxRetries: 10,
+	Backoff:    jobs.ExponentialBackoff(5*time.Second, time.Minute),
+}
+
+// QuotaReset сбрасывает кеш месячного расхода в начале расчетного периода и планирует следующий сброс
+type QuotaReset struct {
+	enforcer *quota.Enforcer
+	jobs     *jobs.Manager
+	now      func() time.Time
+	logger   *logrus.Logger
+}
+
+// NewQuotaReset регистрирует обработчик задачи QuotaResetJob; менеджер задач должен быть запущен
+// после вызова. Первый сброс планируется Schedule.
+func NewQuotaReset(enforcer *quota.Enforcer, manager *jobs.Manager, log *logrus.Logger) *QuotaReset {
+	r := &QuotaReset{enforcer: enforcer, jobs: manager, now: time.Now, logger: log}
+	manager.Register(QuotaResetJob, r.handle, quotaResetRetryPolicy)
+	return r
+}
+
+// Schedule планирует сброс на начало следующего месяца (UTC). Вызов на каждом экземпляре
+// при старте заменяет ранее запланированную задачу, а не добавляет новую.
+func (r *QuotaReset) Schedule(ctx context.Context) (time.Time, error) {
+	at := quota.NextPeriodStart(r.now())
+	if _, err := r.jobs.Enqueue(ctx, QuotaResetJob, nil, jobs.At(at), jobs.WithKey(QuotaResetJob)); err != nil {
+		return time.Time{}, err
+	}
+	return at, nil
+}
+
+func (r *QuotaReset) handle(ctx context.Context, job *jobs.Job) error {
+	if err := r.enforcer.ResetPeriod(ctx); err != nil {
+		return err
+	}
+
+	at, err := r.Schedule(ctx)
+	if err != nil {
+		return err
+	}
+	r.logger.WithFields(logrus.Fields{
+		"scheduled_for": job.RunAt,
+		"next_reset":    at,
+	}).Info("Monthly quota usage reset")
+	return nil
+}
@@ -0,0 +1,59 @@
+package service_impl
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/quota"
+	"github.com/rusgainew/tunduck-app/pkg/storage"
+)
+
+// QuotaUsage источник расхода и тарифного плана организаций для quota.Enforcer
+type QuotaUsage struct {
+	docs  repository.EsfDocumentRepository
+	orgs  services.EsfOrganizationService
+	store storage.Storage
+	now   func() time.Time
+}
+
+// NewQuotaUsage создает источник расхода; без хранилища (store == nil) расход места равен нулю
+func NewQuotaUsage(docs repository.EsfDocumentRepository, orgs services.EsfOrganizationService, store storage.Storage) *QuotaUsage {
+	return &QuotaUsage{docs: docs, orgs: orgs, store: store, now: time.Now}
+}
+
+// Plan возвращает план организации из ее карточки (кешируется сервисом организаций)
+func (u *QuotaUsage) Plan(ctx context.Context, orgID uuid.UUID) (string, error) {
+	org, err := u.orgs.GetOrganizationByID(ctx, orgID)
+	if err != nil {
+		return "", err
+	}
+	return org.Plan, nil
+}
+
+// Usage возвращает число документов за текущий месяц (UTC) или суммарный размер вложений
+func (u *QuotaUsage) Usage(ctx context.Context, orgID uuid.UUID, resource quota.Resource) (int64, error) {
+	switch resource {
+	case quota.ResourceDocuments:
+		now := u.now().UTC()
+		monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return u.docs.CountDocumentsCreatedSince(ctx, orgID, monthStart)
+	case quota.ResourceStorage:
+		if u.store == nil {
+			return 0, nil
+		}
+		objects, err := u.store.List(ctx, services.AttachmentKeyPrefix(orgID))
+		if err != nil {
+			return 0, err
+		}
+		var total int64
+		for _, obj := range objects {
+			total += obj.Size
+		}
+		return total, nil
+	}
+	return 0, nil
+}
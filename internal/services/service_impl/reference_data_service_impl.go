@@ -0,0 +1,215 @@
+package service_impl
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/cache"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/esfgateway"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+)
+
+// referenceDataCacheKey ключ справочных данных в общем кеше
+const referenceDataCacheKey = "reference:codes"
+
+// directoryCacheKeyPrefix префикс ключей официальных справочников в общем кеше
+const directoryCacheKeyPrefix = "reference:directory:"
+
+// referenceDataTTL справочники меняются редко
+const referenceDataTTL = 12 * time.Hour
+
+// referenceDataService реализует ReferenceDataService
+type referenceDataService struct {
+	repo         repository.ReferenceDataRepository
+	logger       *logger.Logger
+	cacheManager cache.CacheManager
+	gateway      esfgateway.Gateway
+}
+
+// NewReferenceDataService создает новый экземпляр сервиса справочных данных
+func NewReferenceDataService(repo repository.ReferenceDataRepository, log *logrus.Logger) services.ReferenceDataService {
+	return &referenceDataService{
+		repo:   repo,
+		logger: logger.New(log),
+	}
+}
+
+// SetCacheManager устанавливает CacheManager для использования кеша
+func (s *referenceDataService) SetCacheManager(cacheManager cache.CacheManager) {
+	s.cacheManager = cacheManager
+}
+
+// SetGateway устанавливает шлюз ЭСФ, из которого синхронизируются справочники
+func (s *referenceDataService) SetGateway(gw esfgateway.Gateway) {
+	s.gateway = gw
+}
+
+// GetReferenceData возвращает справочные коды (из кеша, если доступен)
+func (s *referenceDataService) GetReferenceData(ctx context.Context) (*services.ReferenceData, error) {
+	if s.cacheManager == nil {
+		return s.loadReferenceData(ctx)
+	}
+
+	var data services.ReferenceData
+	err := s.cacheManager.Generic().GetOrLoad(ctx, referenceDataCacheKey, &data, referenceDataTTL, func(ctx context.Context) (interface{}, error) {
+		return s.loadReferenceData(ctx)
+	}, cache.TagReference)
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+// CacheWarmReferenceData загружает справочные коды в кеш
+func (s *referenceDataService) CacheWarmReferenceData(ctx context.Context) error {
+	if s.cacheManager == nil {
+		return nil
+	}
+
+	data, err := s.loadReferenceData(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := s.cacheManager.Generic().SetWithTags(ctx, referenceDataCacheKey, data, referenceDataTTL, cache.TagReference); err != nil {
+		s.logger.Error(ctx, "Failed to warm reference data cache", err)
+		return err
+	}
+
+	s.logger.Info(ctx, "Reference data cache warming completed", logrus.Fields{
+		"currencies":      len(data.Currencies),
+		"vat_rate_codes":  len(data.VATRateCodes),
+		"sales_tax_codes": len(data.SalesTaxCodes),
+	})
+	return nil
+}
+
+// loadReferenceData загружает справочные коды из репозитория
+func (s *referenceDataService) loadReferenceData(ctx context.Context) (*services.ReferenceData, error) {
+	currencies, err := s.repo.GetCurrencyCodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	vatRateCodes, err := s.repo.GetVATRateCodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	salesTaxCodes, err := s.repo.GetSalesTaxCodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &services.ReferenceData{
+		Currencies:    currencies,
+		VATRateCodes:  vatRateCodes,
+		SalesTaxCodes: salesTaxCodes,
+	}, nil
+}
+
+// ListDirectories возвращает имена официальных справочников
+func (s *referenceDataService) ListDirectories() []string {
+	return append([]string(nil), esfgateway.Directories...)
+}
+
+// GetDirectory возвращает коды официального справочника (из кеша, если доступен)
+func (s *referenceDataService) GetDirectory(ctx context.Context, name string) (*services.Directory, error) {
+	if !esfgateway.IsDirectory(name) {
+		return nil, apperror.NotFoundError("directory")
+	}
+	if s.cacheManager == nil {
+		return s.loadDirectory(ctx, name)
+	}
+
+	var dir services.Directory
+	err := s.cacheManager.Generic().GetOrLoad(ctx, directoryCacheKeyPrefix+name, &dir, referenceDataTTL, func(ctx context.Context) (interface{}, error) {
+		return s.loadDirectory(ctx, name)
+	}, cache.TagReference)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dir, nil
+}
+
+// SyncDirectories загружает справочники из шлюза ЭСФ и заменяет сохраненные коды.
+// Ошибка одного справочника не прерывает синхронизацию остальных
+func (s *referenceDataService) SyncDirectories(ctx context.Context) (*services.DirectorySyncResult, error) {
+	if s.gateway == nil {
+		return nil, apperror.New(apperror.ErrExternalService, "ESF gateway is not configured")
+	}
+
+	result := &services.DirectorySyncResult{Synced: map[string]int{}}
+	now := time.Now().UTC()
+	for _, name := range esfgateway.Directories {
+		entries, err := s.gateway.GetDirectory(ctx, name)
+		if err == nil && len(entries) == 0 {
+			// Пустой ответ не затирает ранее загруженные коды
+			err = apperror.New(apperror.ErrExternalService, "empty directory")
+		}
+		if err == nil {
+			err = s.repo.ReplaceCodes(ctx, name, directoryCodes(name, entries, now))
+		}
+		if err != nil {
+			if result.Failed == nil {
+				result.Failed = map[string]string{}
+			}
+			result.Failed[name] = err.Error()
+			s.logger.Error(ctx, "Failed to sync reference directory", err, logrus.Fields{"directory": name})
+			continue
+		}
+		result.Synced[name] = len(entries)
+	}
+
+	if s.cacheManager != nil && len(result.Synced) > 0 {
+		if err := s.cacheManager.InvalidateTags(ctx, cache.TagReference); err != nil {
+			s.logger.Error(ctx, "Failed to invalidate reference data cache", err)
+		}
+	}
+
+	s.logger.Info(ctx, "Reference directories synchronized", logrus.Fields{
+		"synced": len(result.Synced),
+		"failed": len(result.Failed),
+	})
+	return result, nil
+}
+
+// loadDirectory загружает коды справочника из репозитория
+func (s *referenceDataService) loadDirectory(ctx context.Context, name string) (*services.Directory, error) {
+	codes, err := s.repo.ListCodes(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := &services.Directory{Name: name, Codes: make([]esfgateway.DirectoryEntry, 0, len(codes))}
+	for _, code := range codes {
+		dir.Codes = append(dir.Codes, esfgateway.DirectoryEntry{Code: code.Code, Name: code.Name})
+		if dir.SyncedAt == nil || code.SyncedAt.After(*dir.SyncedAt) {
+			synced := code.SyncedAt
+			dir.SyncedAt = &synced
+		}
+	}
+	return dir, nil
+}
+
+// directoryCodes преобразует ответ шлюза в записи справочника, отбрасывая пустые и повторные коды
+func directoryCodes(name string, entries []esfgateway.DirectoryEntry, syncedAt time.Time) []entity.ReferenceCode {
+	seen := make(map[string]bool, len(entries))
+	codes := make([]entity.ReferenceCode, 0, len(entries))
+	for _, e := range entries {
+		if e.Code == "" || seen[e.Code] {
+			continue
+		}
+		seen[e.Code] = true
+		codes = append(codes, entity.ReferenceCode{Directory: name, Code: e.Code, Name: e.Name, SyncedAt: syncedAt})
+	}
+	return codes
+}
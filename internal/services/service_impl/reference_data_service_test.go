@@ -0,0 +1,91 @@
+package service_impl
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/esfgateway"
+)
+
+// memoryReferenceDataRepository хранит коды справочников в памяти
+type memoryReferenceDataRepository struct {
+	repository.ReferenceDataRepository
+	codes map[string][]entity.ReferenceCode
+}
+
+func (m *memoryReferenceDataRepository) ListCodes(ctx context.Context, directory string) ([]entity.ReferenceCode, error) {
+	return m.codes[directory], nil
+}
+
+func (m *memoryReferenceDataRepository) ReplaceCodes(ctx context.Context, directory string, codes []entity.ReferenceCode) error {
+	m.codes[directory] = codes
+	return nil
+}
+
+// flakyDirectoryGateway отдает справочники имитации шлюза, кроме одного недоступного
+type flakyDirectoryGateway struct {
+	esfgateway.Gateway
+	broken string
+}
+
+func (g *flakyDirectoryGateway) GetDirectory(ctx context.Context, name string) ([]esfgateway.DirectoryEntry, error) {
+	if name == g.broken {
+		return nil, esfgateway.ErrUnavailable
+	}
+	return g.Gateway.GetDirectory(ctx, name)
+}
+
+func TestReferenceDataService_SyncDirectories(t *testing.T) {
+	repo := &memoryReferenceDataRepository{codes: map[string][]entity.ReferenceCode{}}
+	svc := NewReferenceDataService(repo, logrus.New())
+	ctx := context.Background()
+
+	_, err := svc.SyncDirectories(ctx)
+	assert.Equal(t, apperror.ErrExternalService, err.(*apperror.AppError).Code, "no gateway configured")
+
+	dir, err := svc.GetDirectory(ctx, esfgateway.DirectoryCurrencies)
+	require.NoError(t, err)
+	assert.Empty(t, dir.Codes)
+	assert.Nil(t, dir.SyncedAt)
+
+	svc.SetGateway(esfgateway.NewMock(esfgateway.MockConfig{}))
+	result, err := svc.SyncDirectories(ctx)
+	require.NoError(t, err)
+	assert.Len(t, result.Synced, len(esfgateway.Directories))
+	assert.Empty(t, result.Failed)
+
+	dir, err = svc.GetDirectory(ctx, esfgateway.DirectoryCurrencies)
+	require.NoError(t, err)
+	assert.Contains(t, dir.Codes, esfgateway.DirectoryEntry{Code: "KGS", Name: "Kyrgyz som"})
+	require.NotNil(t, dir.SyncedAt)
+
+	svc.SetGateway(&flakyDirectoryGateway{Gateway: esfgateway.NewMock(esfgateway.MockConfig{}), broken: esfgateway.DirectoryCountries})
+	countries := repo.codes[esfgateway.DirectoryCountries]
+	result, err = svc.SyncDirectories(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, result.Failed, esfgateway.DirectoryCountries)
+	assert.Len(t, result.Synced, len(esfgateway.Directories)-1)
+	assert.Equal(t, countries, repo.codes[esfgateway.DirectoryCountries], "a failed directory keeps its codes")
+
+	_, err = svc.GetDirectory(ctx, "planets")
+	var appErr *apperror.AppError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, apperror.ErrNotFound, appErr.Code)
+}
+
+func TestDirectoryCodes_SkipsEmptyAndDuplicates(t *testing.T) {
+	codes := directoryCodes(esfgateway.DirectoryPaymentCodes, []esfgateway.DirectoryEntry{
+		{Code: "1", Name: "Cash"}, {Code: "", Name: "Blank"}, {Code: "1", Name: "Cash again"}, {Code: "2", Name: "Bank transfer"},
+	}, contractDate("2026-10-16"))
+	require.Len(t, codes, 2)
+	assert.Equal(t, "Cash", codes[0].Name)
+	assert.Equal(t, esfgateway.DirectoryPaymentCodes, codes[1].Directory)
+}
@@ -0,0 +1,274 @@
+package service_impl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/jobs"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+	"github.com/rusgainew/tunduck-app/pkg/report"
+)
+
+// reportDateLayout формат дат в отчетах и фильтре периода
+const reportDateLayout = "2006-01-02"
+
+// reportBuilder накапливает документы периода и строит таблицу отчета
+type reportBuilder interface {
+	Add(doc *entity.EsfDocument)
+	Table() *report.Table
+}
+
+// validateReportParams проверяет вид отчета и период
+func validateReportParams(params services.ReportParams) error {
+	if _, ok := services.FindReportDefinition(params.Kind); !ok {
+		return apperror.ValidationError("unknown report: {report}").WithParams(map[string]interface{}{"report": params.Kind})
+	}
+	if params.From.IsZero() || params.To.IsZero() || params.To.Before(params.From) {
+		return apperror.ValidationError("invalid report period")
+	}
+	return nil
+}
+
+// newReportBuilder создает построитель отчета по его виду
+func newReportBuilder(params services.ReportParams) (reportBuilder, error) {
+	def, ok := services.FindReportDefinition(params.Kind)
+	if !ok {
+		return nil, fmt.Errorf("unknown report %q", params.Kind)
+	}
+	subtitle := "Period: " + params.From.Format(reportDateLayout) + " - " + params.To.Format(reportDateLayout)
+
+	switch params.Kind {
+	case services.ReportSalesRegister:
+		return newRegisterBuilder(def.Title, subtitle, services.OperationTypeSale), nil
+	case services.ReportPurchasesRegister:
+		return newRegisterBuilder(def.Title, subtitle, services.OperationTypePurchase), nil
+	case services.ReportCounterpartyTurnover:
+		return &turnoverBuilder{title: def.Title, subtitle: subtitle, byTin: map[string]*counterpartyTurnover{}}, nil
+	default:
+		return nil, fmt.Errorf("report %q has no builder", params.Kind)
+	}
+}
+
+// documentTaxes суммы НДС и налога с продаж по позициям документа
+func documentTaxes(doc *entity.EsfDocument) (vat, salesTax float64) {
+	for _, e := range doc.CatalogEntries {
+		vat += e.VatAmount
+		salesTax += e.SalesTaxAmount
+	}
+	return vat, salesTax
+}
+
+// documentDiscounts скидки по позициям документа за вычетом наценок
+func documentDiscounts(doc *entity.EsfDocument) float64 {
+	var discounts float64
+	for _, e := range doc.CatalogEntries {
+		discounts += e.DiscountAmount - e.SurchargeAmount
+	}
+	return discounts
+}
+
+// registerBuilder реестр документов одного вида операции
+type registerBuilder struct {
+	operationType string
+	table         *report.Table
+	discounts     float64
+	withoutTaxes  float64
+	vat           float64
+	salesTax      float64
+	total         float64
+}
+
+func newRegisterBuilder(title, subtitle, operationType string) *registerBuilder {
+	return &registerBuilder{
+		operationType: operationType,
+		table: &report.Table{
+			Title:    title,
+			Subtitle: subtitle,
+			Columns: []report.Column{
+				{Title: "No"},
+				{Title: "Delivery date"},
+				{Title: "Document", Width: 2.2},
+				{Title: "Contractor TIN", Width: 1.2},
+				{Title: "Contract", Width: 1.2},
+				{Title: "Currency", Width: 0.7},
+				{Title: "Discounts", Numeric: true},
+				{Title: "Without taxes", Numeric: true},
+				{Title: "VAT", Numeric: true},
+				{Title: "Sales tax", Numeric: true},
+				{Title: "Total", Numeric: true},
+			},
+		},
+	}
+}
+
+func (b *registerBuilder) Add(doc *entity.EsfDocument) {
+	if doc.OperationTypeCode != b.operationType {
+		return
+	}
+	vat, salesTax := documentTaxes(doc)
+	discounts := documentDiscounts(doc)
+	b.table.AddRow(
+		strconv.Itoa(len(b.table.Rows)+1),
+		doc.DeliveryDate.Format(reportDateLayout),
+		doc.ID.String(),
+		doc.ContractorTin,
+		doc.SupplyContractNumber,
+		doc.CurrencyCode,
+		report.Amount(discounts),
+		report.Amount(doc.TotalCurrencyValueWithoutTaxes),
+		report.Amount(vat),
+		report.Amount(salesTax),
+		report.Amount(doc.TotalCurrencyValue),
+	)
+	b.discounts += discounts
+	b.withoutTaxes += doc.TotalCurrencyValueWithoutTaxes
+	b.vat += vat
+	b.salesTax += salesTax
+	b.total += doc.TotalCurrencyValue
+}
+
+func (b *registerBuilder) Table() *report.Table {
+	b.table.Totals = []string{
+		"Total", "", strconv.Itoa(len(b.table.Rows)) + " documents", "", "", "",
+		report.Amount(b.discounts), report.Amount(b.withoutTaxes), report.Amount(b.vat), report.Amount(b.salesTax), report.Amount(b.total),
+	}
+	return b.table
+}
+
+// counterpartyTurnover обороты с одним контрагентом
+type counterpartyTurnover struct {
+	tin       string
+	documents int
+	sales     float64
+	purchases float64
+	vat       float64
+}
+
+// turnoverBuilder обороты по ИНН контрагентов; документы прочих видов операций
+// учитываются в количестве и НДС, но не в продажах и закупках
+type turnoverBuilder struct {
+	title    string
+	subtitle string
+	byTin    map[string]*counterpartyTurnover
+}
+
+func (b *turnoverBuilder) Add(doc *entity.EsfDocument) {
+	t, ok := b.byTin[doc.ContractorTin]
+	if !ok {
+		t = &counterpartyTurnover{tin: doc.ContractorTin}
+		b.byTin[doc.ContractorTin] = t
+	}
+	t.documents++
+	switch doc.OperationTypeCode {
+	case services.OperationTypeSale:
+		t.sales += doc.TotalCurrencyValue
+	case services.OperationTypePurchase:
+		t.purchases += doc.TotalCurrencyValue
+	}
+	vat, _ := documentTaxes(doc)
+	t.vat += vat
+}
+
+func (b *turnoverBuilder) Table() *report.Table {
+	table := &report.Table{
+		Title:    b.title,
+		Subtitle: b.subtitle,
+		Columns: []report.Column{
+			{Title: "Contractor TIN", Width: 1.5},
+			{Title: "Documents", Numeric: true},
+			{Title: "Sales", Numeric: true},
+			{Title: "Purchases", Numeric: true},
+			{Title: "Balance", Numeric: true},
+			{Title: "VAT", Numeric: true},
+		},
+	}
+
+	rows := make([]*counterpartyTurnover, 0, len(b.byTin))
+	for _, t := range b.byTin {
+		rows = append(rows, t)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].tin < rows[j].tin })
+
+	var total counterpartyTurnover
+	for _, t := range rows {
+		table.AddRow(t.tin, strconv.Itoa(t.documents), report.Amount(t.sales), report.Amount(t.purchases),
+			report.Amount(t.sales-t.purchases), report.Amount(t.vat))
+		total.documents += t.documents
+		total.sales += t.sales
+		total.purchases += t.purchases
+		total.vat += t.vat
+	}
+	table.Totals = []string{"Total", strconv.Itoa(total.documents), report.Amount(total.sales),
+		report.Amount(total.purchases), report.Amount(total.sales - total.purchases), report.Amount(total.vat)}
+	return table
+}
+
+// writeReport строит отчет по документам организации за период и пишет его в XLSX или PDF
+func (s *exportService) writeReport(ctx context.Context, export *entity.ExportJob, w io.Writer, progress func(int64)) (int64, error) {
+	var params services.ReportParams
+	if err := json.Unmarshal(export.Filter, &params); err != nil {
+		return 0, jobs.Permanent(err)
+	}
+	if export.OrganizationID == nil {
+		return 0, jobs.Permanent(errors.New("report without organization"))
+	}
+	orgID := *export.OrganizationID
+
+	builder, err := newReportBuilder(params)
+	if err != nil {
+		return 0, jobs.Permanent(err)
+	}
+
+	// Граница «до» включает весь последний день периода
+	end := params.To.AddDate(0, 0, 1)
+	filter := pagination.DocumentFilterParams{
+		DeliveryAfter:  params.From.Format(reportDateLayout),
+		DeliveryBefore: end.Format(reportDateLayout),
+	}
+
+	_, total, err := s.docRepo.GetAllDocumentsPaginated(ctx, orgID, pagination.PaginationParams{Page: 1, PageSize: 1}, filter)
+	if err != nil {
+		return 0, err
+	}
+	s.setTotal(ctx, export.ID, total)
+
+	var processed int64
+	cursor := pagination.CursorParams{Limit: exportDocumentBatch, Sort: "delivery_date", Order: "asc"}
+	for {
+		docs, info, err := s.docRepo.GetAllDocumentsCursor(ctx, orgID, cursor, filter)
+		if err != nil {
+			return processed, err
+		}
+		for i := range docs {
+			doc := &docs[i]
+			if !doc.DeliveryDate.Before(params.From) && doc.DeliveryDate.Before(end) {
+				builder.Add(doc)
+			}
+			processed++
+			progress(processed)
+		}
+		if !info.HasNext {
+			break
+		}
+		cursor.Cursor = info.NextCursor
+	}
+
+	table := builder.Table()
+	switch export.Format {
+	case services.ExportFormatXLSX:
+		err = report.WriteXLSX(w, table)
+	case services.ExportFormatPDF:
+		err = report.WritePDF(w, table)
+	default:
+		err = jobs.Permanent(fmt.Errorf("unsupported report format %q", export.Format))
+	}
+	return processed, err
+}
@@ -0,0 +1,158 @@
+package service_impl
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/dates"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+)
+
+// stubReportDocumentRepository отдает документы одной страницей курсора
+type stubReportDocumentRepository struct {
+	repository.EsfDocumentRepository
+	docs   []entity.EsfDocument
+	filter pagination.DocumentFilterParams
+}
+
+func (s *stubReportDocumentRepository) GetAllDocumentsPaginated(ctx context.Context, orgID uuid.UUID, params pagination.PaginationParams, filters pagination.DocumentFilterParams) ([]entity.EsfDocument, int64, error) {
+	return nil, int64(len(s.docs)), nil
+}
+
+func (s *stubReportDocumentRepository) GetAllDocumentsCursor(ctx context.Context, orgID uuid.UUID, params pagination.CursorParams, filters pagination.DocumentFilterParams) ([]entity.EsfDocument, pagination.CursorInfo, error) {
+	s.filter = filters
+	return s.docs, pagination.CursorInfo{}, nil
+}
+
+func reportDocument(opType, tin string, delivery time.Time, total, vat float64) entity.EsfDocument {
+	return entity.EsfDocument{
+		ID:                             uuid.New(),
+		OperationTypeCode:              opType,
+		ContractorTin:                  tin,
+		DeliveryDate:                   dates.Of(delivery),
+		CurrencyCode:                   "KGS",
+		TotalCurrencyValue:             total,
+		TotalCurrencyValueWithoutTaxes: total - vat,
+		CatalogEntries:                 []entity.EsfEntries{{VatAmount: vat}},
+	}
+}
+
+func TestReportBuilders(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2025, 1, d, 12, 0, 0, 0, time.UTC) }
+	docs := []entity.EsfDocument{
+		reportDocument(services.OperationTypeSale, "222", day(3), 112, 12),
+		reportDocument(services.OperationTypeSale, "111", day(5), 224, 24),
+		reportDocument(services.OperationTypePurchase, "111", day(7), 56, 6),
+	}
+	docs[1].CatalogEntries[0].DiscountAmount = 20
+	docs[1].CatalogEntries = append(docs[1].CatalogEntries, entity.EsfEntries{SurchargeAmount: 5})
+	params := services.ReportParams{Kind: services.ReportSalesRegister, From: day(1), To: day(31)}
+
+	b, err := newReportBuilder(params)
+	require.NoError(t, err)
+	for i := range docs {
+		b.Add(&docs[i])
+	}
+	sales := b.Table()
+	require.Len(t, sales.Rows, 2)
+	assert.Equal(t, "Period: 2025-01-01 - 2025-01-31", sales.Subtitle)
+	assert.Equal(t, "2 documents", sales.Totals[2])
+	assert.Equal(t, []string{"15.00", "300.00", "36.00", "0.00", "336.00"}, sales.Totals[6:], "discounts are net of surcharges")
+
+	params.Kind = services.ReportCounterpartyTurnover
+	b, err = newReportBuilder(params)
+	require.NoError(t, err)
+	for i := range docs {
+		b.Add(&docs[i])
+	}
+	turnover := b.Table()
+	require.Len(t, turnover.Rows, 2)
+	assert.Equal(t, []string{"111", "2", "224.00", "56.00", "168.00", "30.00"}, turnover.Rows[0], "rows are sorted by TIN")
+	assert.Equal(t, []string{"Total", "3", "336.00", "56.00", "280.00", "42.00"}, turnover.Totals)
+}
+
+func TestExportService_ReportXLSX(t *testing.T) {
+	id, orgID := uuid.New(), uuid.New()
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+	filter, err := json.Marshal(services.ReportParams{Kind: services.ReportPurchasesRegister, From: from, To: to})
+	require.NoError(t, err)
+
+	repo := &memoryExportJobRepository{jobs: map[uuid.UUID]*entity.ExportJob{
+		id: {ID: id, Type: services.ExportTypeReport, Format: services.ExportFormatXLSX, Status: entity.ExportStatusPending, OrganizationID: &orgID, Filter: filter},
+	}}
+	ops := newMemoryOperationRepository()
+	ops.ops[id] = &entity.Operation{ID: id, Kind: services.OperationKindReport, State: entity.OperationStatePending}
+	s, store := newTestExportService(t, repo, ops, &stubAuditLogRepository{})
+	docs := &stubReportDocumentRepository{docs: []entity.EsfDocument{
+		reportDocument(services.OperationTypePurchase, "111", to.Add(23*time.Hour), 112, 12),
+		// Граница фильтра захватывает полночь следующего дня: такой документ отбрасывается
+		reportDocument(services.OperationTypePurchase, "222", to.AddDate(0, 0, 1), 999, 99),
+	}}
+	s.docRepo = docs
+
+	require.NoError(t, s.handleRun(context.Background(), runJob(t, id)))
+	assert.Equal(t, "2025-01-01", docs.filter.DeliveryAfter)
+	assert.Equal(t, "2025-02-01", docs.filter.DeliveryBefore)
+
+	export := repo.jobs[id]
+	require.Equal(t, entity.ExportStatusCompleted, export.Status, export.Error)
+	assert.Equal(t, ExportKeyPrefix+id.String()+".xlsx", export.StorageKey)
+
+	r, info, err := store.Get(context.Background(), export.StorageKey)
+	require.NoError(t, err)
+	content, err := io.ReadAll(r)
+	r.Close()
+	require.NoError(t, err)
+	assert.Equal(t, services.ExportContentType(services.ExportFormatXLSX), info.ContentType)
+
+	book, err := excelize.OpenReader(bytes.NewReader(content))
+	require.NoError(t, err)
+	defer book.Close()
+	rows, err := book.GetRows(book.GetSheetName(0))
+	require.NoError(t, err)
+	var cells []string
+	for _, row := range rows {
+		cells = append(cells, row...)
+	}
+	assert.Contains(t, cells, "Purchases register")
+	assert.Contains(t, cells, "112.00")
+	assert.NotContains(t, cells, "999.00")
+	assert.Equal(t, entity.OperationStateSucceeded, ops.ops[id].State)
+}
+
+func TestExportService_RejectsInvalidReport(t *testing.T) {
+	s, _ := newTestExportService(t, &memoryExportJobRepository{jobs: map[uuid.UUID]*entity.ExportJob{}}, newMemoryOperationRepository(), &stubAuditLogRepository{})
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	req := services.ExportRequest{
+		Type:           services.ExportTypeReport,
+		Format:         services.ExportFormatCSV,
+		OrganizationID: uuid.New(),
+		Report:         services.ReportParams{Kind: services.ReportSalesRegister, From: from, To: from.AddDate(0, 1, 0)},
+	}
+
+	_, err := s.CreateExport(context.Background(), req)
+	assertErrorCode(t, err, apperror.ErrValidation)
+
+	req.Format = services.ExportFormatPDF
+	req.Report.Kind = "balance_sheet"
+	_, err = s.CreateExport(context.Background(), req)
+	assertErrorCode(t, err, apperror.ErrValidation)
+
+	req.Report = services.ReportParams{Kind: services.ReportSalesRegister, From: from, To: from.AddDate(0, 0, -1)}
+	_, err = s.CreateExport(context.Background(), req)
+	assertErrorCode(t, err, apperror.ErrValidation)
+}
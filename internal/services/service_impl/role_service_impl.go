@@ -40,7 +40,7 @@ func (rs *roleServiceImpl) AssignRole(ctx context.Context, userID uuid.UUID, rol
 			"user_id": userID.String(),
 			"role":    role.String(),
 		})
-		return apperror.ValidationError("невалидная роль: " + role.String())
+		return apperror.ValidationError("invalid role: {role}").WithParams(map[string]interface{}{"role": role.String()})
 	}
 
 	// Получаем пользователя
@@ -49,14 +49,14 @@ func (rs *roleServiceImpl) AssignRole(ctx context.Context, userID uuid.UUID, rol
 		rs.logger.Error(ctx, "Ошибка получения пользователя", err, logrus.Fields{
 			"user_id": userID.String(),
 		})
-		return apperror.DatabaseError("получение пользователя", err)
+		return apperror.DatabaseError("fetching user", err)
 	}
 
 	if user == nil {
 		rs.logger.Warn(ctx, "Пользователь не найден", logrus.Fields{
 			"user_id": userID.String(),
 		})
-		return apperror.New(apperror.ErrUserNotFound, "пользователь не найден")
+		return apperror.New(apperror.ErrUserNotFound, "user not found")
 	}
 
 	// Обновляем роль
@@ -66,7 +66,7 @@ func (rs *roleServiceImpl) AssignRole(ctx context.Context, userID uuid.UUID, rol
 			"user_id": userID.String(),
 			"role":    role.String(),
 		})
-		return apperror.DatabaseError("обновление роли", err)
+		return apperror.DatabaseError("updating role", err)
 	}
 
 	rs.logger.Info(ctx, "Роль успешно назначена", logrus.Fields{
@@ -88,14 +88,14 @@ func (rs *roleServiceImpl) GetUserRole(ctx context.Context, userID uuid.UUID) (r
 		rs.logger.Error(ctx, "Ошибка получения пользователя", err, logrus.Fields{
 			"user_id": userID.String(),
 		})
-		return "", apperror.DatabaseError("получение пользователя", err)
+		return "", apperror.DatabaseError("fetching user", err)
 	}
 
 	if user == nil {
 		rs.logger.Warn(ctx, "Пользователь не найден", logrus.Fields{
 			"user_id": userID.String(),
 		})
-		return "", apperror.New(apperror.ErrUserNotFound, "пользователь не найден")
+		return "", apperror.New(apperror.ErrUserNotFound, "user not found")
 	}
 
 	rs.logger.Debug(ctx, "Роль пользователя получена", logrus.Fields{
@@ -119,7 +119,7 @@ func (rs *roleServiceImpl) UpdateRole(ctx context.Context, userID uuid.UUID, new
 			"user_id":  userID.String(),
 			"new_role": newRole.String(),
 		})
-		return apperror.ValidationError("невалидная роль: " + newRole.String())
+		return apperror.ValidationError("invalid role: {role}").WithParams(map[string]interface{}{"role": newRole.String()})
 	}
 
 	// Получаем пользователя
@@ -128,14 +128,14 @@ func (rs *roleServiceImpl) UpdateRole(ctx context.Context, userID uuid.UUID, new
 		rs.logger.Error(ctx, "Ошибка получения пользователя", err, logrus.Fields{
 			"user_id": userID.String(),
 		})
-		return apperror.DatabaseError("получение пользователя", err)
+		return apperror.DatabaseError("fetching user", err)
 	}
 
 	if user == nil {
 		rs.logger.Warn(ctx, "Пользователь не найден", logrus.Fields{
 			"user_id": userID.String(),
 		})
-		return apperror.New(apperror.ErrUserNotFound, "пользователь не найден")
+		return apperror.New(apperror.ErrUserNotFound, "user not found")
 	}
 
 	oldRole := user.Role
@@ -148,7 +148,7 @@ func (rs *roleServiceImpl) UpdateRole(ctx context.Context, userID uuid.UUID, new
 			"old_role": oldRole.String(),
 			"new_role": newRole.String(),
 		})
-		return apperror.DatabaseError("обновление роли", err)
+		return apperror.DatabaseError("updating role", err)
 	}
 
 	rs.logger.Info(ctx, "Роль успешно обновлена", logrus.Fields{
@@ -171,7 +171,7 @@ func (rs *roleServiceImpl) ListUsersByRole(ctx context.Context, role rbac.Role)
 		rs.logger.Warn(ctx, "Попытка получить пользователей с невалидной ролью", logrus.Fields{
 			"role": role.String(),
 		})
-		return nil, apperror.ValidationError("невалидная роль: " + role.String())
+		return nil, apperror.ValidationError("invalid role: {role}").WithParams(map[string]interface{}{"role": role.String()})
 	}
 
 	// Получаем всех пользователей и фильтруем по роли
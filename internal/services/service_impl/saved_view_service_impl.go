@@ -0,0 +1,155 @@
+package service_impl
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+)
+
+// savedViewService реализует SavedViewService
+type savedViewService struct {
+	repo   repository.SavedViewRepository
+	logger *logger.Logger
+}
+
+// NewSavedViewService создает сервис представлений списка документов
+func NewSavedViewService(repo repository.SavedViewRepository, log *logrus.Logger) services.SavedViewService {
+	return &savedViewService{
+		repo:   repo,
+		logger: logger.New(log),
+	}
+}
+
+// List возвращает представления пользователя
+func (s *savedViewService) List(ctx context.Context, userID uuid.UUID) ([]services.SavedView, error) {
+	views, err := s.repo.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]services.SavedView, 0, len(views))
+	for i := range views {
+		view, err := savedViewModel(&views[i])
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, *view)
+	}
+	return result, nil
+}
+
+// Get возвращает представление пользователя
+func (s *savedViewService) Get(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*services.SavedView, error) {
+	view, err := s.find(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return savedViewModel(view)
+}
+
+// Create сохраняет новое представление
+func (s *savedViewService) Create(ctx context.Context, userID uuid.UUID, req *models.SavedViewRequest) (*services.SavedView, error) {
+	view := &entity.SavedView{ID: uuid.New(), UserID: userID}
+	return s.save(ctx, view, req)
+}
+
+// Update заменяет имя, фильтры и сортировку представления
+func (s *savedViewService) Update(ctx context.Context, userID uuid.UUID, id uuid.UUID, req *models.SavedViewRequest) (*services.SavedView, error) {
+	view, err := s.find(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.save(ctx, view, req)
+}
+
+// Delete удаляет представление
+func (s *savedViewService) Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	if err := s.repo.Delete(ctx, userID, id); err != nil {
+		return err
+	}
+	s.logger.Info(ctx, "Saved view deleted", logrus.Fields{"user_id": userID.String(), "view_id": id.String()})
+	return nil
+}
+
+func (s *savedViewService) find(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*entity.SavedView, error) {
+	view, err := s.repo.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if view == nil {
+		return nil, apperror.NotFoundError("view")
+	}
+	return view, nil
+}
+
+func (s *savedViewService) save(ctx context.Context, view *entity.SavedView, req *models.SavedViewRequest) (*services.SavedView, error) {
+	if err := validateViewFilter(req.Filter); err != nil {
+		return nil, err
+	}
+	filter, err := json.Marshal(req.Filter)
+	if err != nil {
+		return nil, apperror.New(apperror.ErrInternal, "failed to encode view filter").WithError(err)
+	}
+
+	view.Name = strings.TrimSpace(req.Name)
+	view.Filter = filter
+	view.Sort = strings.TrimSpace(req.Sort)
+	view.Order = req.Order
+	if view.Name == "" {
+		return nil, apperror.ValidationError("view name is required")
+	}
+	if err := s.repo.Save(ctx, view); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info(ctx, "Saved view stored", logrus.Fields{"user_id": view.UserID.String(), "view_id": view.ID.String(), "name": view.Name})
+	return savedViewModel(view)
+}
+
+// validateViewFilter проверяет даты фильтра: календарные или относительные ("today", "-30d")
+func validateViewFilter(filter pagination.DocumentFilterParams) error {
+	for _, value := range []string{filter.CreatedAfter, filter.CreatedBefore, filter.DeliveryAfter, filter.DeliveryBefore} {
+		if value == "" || pagination.IsRelativeDate(value) {
+			continue
+		}
+		if _, err := time.Parse(reportDateLayout, value); err != nil {
+			if _, err := time.Parse(time.RFC3339, value); err != nil {
+				return apperror.ValidationError("invalid filter date {date}").WithParams(map[string]interface{}{"date": value})
+			}
+		}
+	}
+	switch filter.Payment {
+	case "", entity.PaymentStatusPaid, entity.PaymentStatusUnpaid, entity.PaymentStatusOverdue:
+		return nil
+	}
+	return apperror.ValidationError("invalid payment filter")
+}
+
+// savedViewModel разбирает сохраненные фильтры представления
+func savedViewModel(view *entity.SavedView) (*services.SavedView, error) {
+	model := &services.SavedView{
+		ID:        view.ID,
+		Name:      view.Name,
+		Sort:      view.Sort,
+		Order:     view.Order,
+		CreatedAt: view.CreatedAt,
+		UpdatedAt: view.UpdatedAt,
+	}
+	if len(view.Filter) > 0 {
+		if err := json.Unmarshal(view.Filter, &model.Filter); err != nil {
+			return nil, apperror.New(apperror.ErrInternal, "invalid stored view filter").WithError(err)
+		}
+	}
+	return model, nil
+}
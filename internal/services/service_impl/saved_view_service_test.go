@@ -0,0 +1,97 @@
+package service_impl
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/pagination"
+)
+
+// memorySavedViewRepository хранит представления в памяти
+type memorySavedViewRepository struct {
+	views map[uuid.UUID]entity.SavedView
+}
+
+func (m *memorySavedViewRepository) List(ctx context.Context, userID uuid.UUID) ([]entity.SavedView, error) {
+	var views []entity.SavedView
+	for _, view := range m.views {
+		if view.UserID == userID {
+			views = append(views, view)
+		}
+	}
+	return views, nil
+}
+
+func (m *memorySavedViewRepository) Get(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*entity.SavedView, error) {
+	view, ok := m.views[id]
+	if !ok || view.UserID != userID {
+		return nil, nil
+	}
+	return &view, nil
+}
+
+func (m *memorySavedViewRepository) Save(ctx context.Context, view *entity.SavedView) error {
+	for _, existing := range m.views {
+		if existing.UserID == view.UserID && existing.Name == view.Name && existing.ID != view.ID {
+			return apperror.ConflictError("a view with this name already exists")
+		}
+	}
+	m.views[view.ID] = *view
+	return nil
+}
+
+func (m *memorySavedViewRepository) Delete(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	if view, ok := m.views[id]; !ok || view.UserID != userID {
+		return apperror.NotFoundError("view")
+	}
+	delete(m.views, id)
+	return nil
+}
+
+func TestSavedViewService_CreateAndGet(t *testing.T) {
+	repo := &memorySavedViewRepository{views: map[uuid.UUID]entity.SavedView{}}
+	svc := NewSavedViewService(repo, logrus.New())
+	ctx, userID := context.Background(), uuid.New()
+
+	_, err := svc.Create(ctx, userID, &models.SavedViewRequest{Name: "Bad", Filter: pagination.DocumentFilterParams{DeliveryBefore: "last month"}})
+	assert.Equal(t, apperror.ErrValidation, err.(*apperror.AppError).Code, "an unparseable date")
+	_, err = svc.Create(ctx, userID, &models.SavedViewRequest{Name: "Bad", Filter: pagination.DocumentFilterParams{Payment: "late"}})
+	assert.Equal(t, apperror.ErrValidation, err.(*apperror.AppError).Code, "an unknown payment status")
+
+	created, err := svc.Create(ctx, userID, &models.SavedViewRequest{
+		Name:   " Unpaid over 30 days ",
+		Filter: pagination.DocumentFilterParams{Payment: entity.PaymentStatusUnpaid, DeliveryBefore: "-30d"},
+		Sort:   "delivery_date",
+		Order:  "asc",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Unpaid over 30 days", created.Name)
+
+	_, err = svc.Create(ctx, userID, &models.SavedViewRequest{Name: "Unpaid over 30 days"})
+	assert.Equal(t, apperror.ErrConflict, err.(*apperror.AppError).Code)
+
+	view, err := svc.Get(ctx, userID, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "-30d", view.Filter.DeliveryBefore, "relative dates are stored as is")
+	assert.Equal(t, entity.PaymentStatusUnpaid, view.Filter.Payment)
+
+	_, err = svc.Get(ctx, uuid.New(), created.ID)
+	assert.Equal(t, apperror.ErrNotFound, err.(*apperror.AppError).Code, "another user's view is not visible")
+
+	updated, err := svc.Update(ctx, userID, created.ID, &models.SavedViewRequest{Name: "Overdue", Filter: pagination.DocumentFilterParams{Payment: entity.PaymentStatusOverdue}})
+	require.NoError(t, err)
+	assert.Empty(t, updated.Filter.DeliveryBefore)
+
+	views, err := svc.List(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, views, 1)
+	require.NoError(t, svc.Delete(ctx, userID, created.ID))
+}
@@ -0,0 +1,160 @@
+package service_impl
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/search"
+)
+
+// searchIndexerBatchSize количество событий outbox, обрабатываемых за один bulk-запрос
+const searchIndexerBatchSize = 200
+
+// SearchIndexer разбирает search_outbox организаций и переносит изменения документов
+// в OpenSearch. Событие удаляется из outbox только после успешного bulk-запроса,
+// поэтому при недоступности OpenSearch индексация повторится на следующем проходе.
+type SearchIndexer struct {
+	docRepo  repository.EsfDocumentRepository
+	orgRepo  repository.EsfOrganizationRepository
+	client   *search.Client
+	interval time.Duration
+	logger   *logger.Logger
+}
+
+// NewSearchIndexer создает индексатор документов
+func NewSearchIndexer(docRepo repository.EsfDocumentRepository, orgRepo repository.EsfOrganizationRepository, client *search.Client, interval time.Duration, log *logrus.Logger) *SearchIndexer {
+	if interval <= 0 {
+		interval = search.DefaultIndexInterval
+	}
+	return &SearchIndexer{
+		docRepo:  docRepo,
+		orgRepo:  orgRepo,
+		client:   client,
+		interval: interval,
+		logger:   logger.New(log),
+	}
+}
+
+// Run опрашивает outbox до отмены контекста
+func (i *SearchIndexer) Run(ctx context.Context) {
+	i.logger.Info(ctx, "Search indexer started", logrus.Fields{"interval": i.interval.String()})
+
+	ticker := time.NewTicker(i.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := i.ProcessOnce(ctx); err != nil && ctx.Err() == nil {
+			i.logger.Warn(ctx, "Search indexing pass failed", logrus.Fields{"error": err.Error()})
+		}
+
+		select {
+		case <-ctx.Done():
+			i.logger.Info(context.Background(), "Search indexer stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessOnce выполняет один проход по всем индексируемым организациям и возвращает
+// количество обработанных событий. Ошибка одной организации не останавливает остальные.
+func (i *SearchIndexer) ProcessOnce(ctx context.Context) (int, error) {
+	orgs, err := i.orgRepo.GetAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	processed := 0
+	for _, org := range orgs {
+		if !i.client.Enabled(org.ID) {
+			continue
+		}
+
+		for {
+			n, err := i.processBatch(ctx, org.ID)
+			processed += n
+			if err != nil {
+				i.logger.Warn(ctx, "Failed to index organization documents", logrus.Fields{
+					"org_id": org.ID.String(),
+					"error":  err.Error(),
+				})
+				break
+			}
+			if n < searchIndexerBatchSize {
+				break
+			}
+		}
+	}
+
+	return processed, nil
+}
+
+// processBatch переносит в индекс одну пачку событий организации
+func (i *SearchIndexer) processBatch(ctx context.Context, orgID uuid.UUID) (int, error) {
+	entries, err := i.docRepo.FetchSearchOutbox(ctx, orgID, searchIndexerBatchSize)
+	if err != nil || len(entries) == 0 {
+		return 0, err
+	}
+
+	// Для документа важна только последняя операция в пачке
+	latest := make(map[uuid.UUID]string, len(entries))
+	order := make([]uuid.UUID, 0, len(entries))
+	ids := make([]int64, 0, len(entries))
+	for _, e := range entries {
+		if _, seen := latest[e.DocumentID]; !seen {
+			order = append(order, e.DocumentID)
+		}
+		latest[e.DocumentID] = e.Operation
+		ids = append(ids, e.ID)
+	}
+
+	var toLoad []uuid.UUID
+	for _, id := range order {
+		if latest[id] == entity.SearchOpIndex {
+			toLoad = append(toLoad, id)
+		}
+	}
+
+	byID := make(map[uuid.UUID]*entity.EsfDocument, len(toLoad))
+	if len(toLoad) > 0 {
+		docs, err := i.docRepo.GetDocumentsByIDs(ctx, orgID, toLoad)
+		if err != nil {
+			return 0, err
+		}
+		for idx := range docs {
+			byID[docs[idx].ID] = &docs[idx]
+		}
+	}
+
+	ops := make([]search.Operation, 0, len(order))
+	for _, id := range order {
+		// Документ, удаленный после записи события, тоже убирается из индекса
+		doc, ok := byID[id]
+		if latest[id] == entity.SearchOpDelete || !ok {
+			ops = append(ops, search.Operation{ID: id.String(), Delete: true})
+			continue
+		}
+		indexed := search.NewDocument(doc)
+		ops = append(ops, search.Operation{ID: id.String(), Document: &indexed})
+	}
+
+	if err := i.client.Bulk(ctx, orgID, ops); err != nil {
+		return 0, err
+	}
+	if err := i.docRepo.DeleteSearchOutbox(ctx, orgID, ids); err != nil {
+		return 0, err
+	}
+
+	i.logger.Debug(ctx, "Documents indexed", logrus.Fields{
+		"org_id":     orgID.String(),
+		"events":     len(entries),
+		"operations": len(ops),
+	})
+	return len(entries), nil
+}
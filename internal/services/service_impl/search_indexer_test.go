@@ -0,0 +1,102 @@
+package service_impl
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/search"
+)
+
+// stubOrganizationRepository возвращает фиксированный список организаций
+type stubOrganizationRepository struct {
+	repository.EsfOrganizationRepository
+	orgs []*entity.EstOrganization
+}
+
+func (s *stubOrganizationRepository) GetAll(ctx context.Context) ([]*entity.EstOrganization, error) {
+	return s.orgs, nil
+}
+
+func TestSearchIndexer_ProcessOnce(t *testing.T) {
+	orgID := uuid.New()
+	updated, deleted, vanished := uuid.New(), uuid.New(), uuid.New()
+
+	var actions []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var line map[string]json.RawMessage
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+			for _, action := range []string{"index", "delete"} {
+				if raw, ok := line[action]; ok {
+					var meta struct {
+						ID string `json:"_id"`
+					}
+					require.NoError(t, json.Unmarshal(raw, &meta))
+					actions = append(actions, action+":"+meta.ID)
+				}
+			}
+		}
+		_, _ = w.Write([]byte(`{"errors":false,"items":[]}`))
+	}))
+	defer srv.Close()
+
+	docRepo := new(MockDocumentRepository)
+	docRepo.On("FetchSearchOutbox", mock.Anything, orgID, searchIndexerBatchSize).Return([]entity.SearchOutboxEntry{
+		{ID: 1, DocumentID: updated, Operation: entity.SearchOpIndex},
+		{ID: 2, DocumentID: deleted, Operation: entity.SearchOpIndex},
+		{ID: 3, DocumentID: deleted, Operation: entity.SearchOpDelete},
+		{ID: 4, DocumentID: vanished, Operation: entity.SearchOpIndex},
+	}, nil)
+	docRepo.On("GetDocumentsByIDs", mock.Anything, orgID, []uuid.UUID{updated, vanished}).
+		Return([]entity.EsfDocument{{ID: updated, ContractorTin: "01234567890123"}}, nil)
+	docRepo.On("DeleteSearchOutbox", mock.Anything, orgID, []int64{1, 2, 3, 4}).Return(nil)
+
+	orgRepo := &stubOrganizationRepository{orgs: []*entity.EstOrganization{{ID: orgID}}}
+	indexer := NewSearchIndexer(docRepo, orgRepo, search.NewClient(search.Config{URL: srv.URL}), 0, logrus.New())
+
+	processed, err := indexer.ProcessOnce(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, processed)
+	assert.Equal(t, []string{
+		"index:" + updated.String(),
+		"delete:" + deleted.String(),
+		"delete:" + vanished.String(),
+	}, actions)
+	docRepo.AssertExpectations(t)
+}
+
+func TestSearchIndexer_KeepsOutboxOnBulkFailure(t *testing.T) {
+	orgID := uuid.New()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	docRepo := new(MockDocumentRepository)
+	docRepo.On("FetchSearchOutbox", mock.Anything, orgID, searchIndexerBatchSize).Return([]entity.SearchOutboxEntry{
+		{ID: 1, DocumentID: uuid.New(), Operation: entity.SearchOpDelete},
+	}, nil)
+
+	orgRepo := &stubOrganizationRepository{orgs: []*entity.EstOrganization{{ID: orgID}}}
+	indexer := NewSearchIndexer(docRepo, orgRepo, search.NewClient(search.Config{URL: srv.URL}), 0, logrus.New())
+
+	processed, err := indexer.ProcessOnce(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 0, processed)
+	docRepo.AssertNotCalled(t, "DeleteSearchOutbox", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/rusgainew/tunduck-app/internal/models"
 	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
 	"github.com/rusgainew/tunduck-app/pkg/entity"
 	"github.com/rusgainew/tunduck-app/pkg/pagination"
 	"github.com/sirupsen/logrus"
@@ -55,6 +56,50 @@ func (m *MockDocumentRepository) DeleteDocument(ctx context.Context, orgID uuid.
 	return args.Error(0)
 }
 
+func (m *MockDocumentRepository) RestoreDocument(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error {
+	args := m.Called(ctx, orgID, id)
+	return args.Error(0)
+}
+
+func (m *MockDocumentRepository) PurgeDocument(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error {
+	args := m.Called(ctx, orgID, id)
+	return args.Error(0)
+}
+
+func (m *MockDocumentRepository) ListDeletedDocuments(ctx context.Context, orgID uuid.UUID, since time.Time, params pagination.PaginationParams) ([]entity.EsfDocument, int64, error) {
+	args := m.Called(ctx, orgID, since, params)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]entity.EsfDocument), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockDocumentRepository) GetDeletedDocumentsByIDs(ctx context.Context, orgID uuid.UUID, ids []uuid.UUID) ([]entity.EsfDocument, error) {
+	args := m.Called(ctx, orgID, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.EsfDocument), args.Error(1)
+}
+
+func (m *MockDocumentRepository) ListDocumentsDeletedBefore(ctx context.Context, orgID uuid.UUID, before time.Time, limit int) ([]uuid.UUID, error) {
+	args := m.Called(ctx, orgID, before, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockDocumentRepository) CountDocumentsCreatedSince(ctx context.Context, orgID uuid.UUID, since time.Time) (int64, error) {
+	args := m.Called(ctx, orgID, since)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDocumentRepository) UpdateDocumentStatus(ctx context.Context, orgID uuid.UUID, id uuid.UUID, status string) error {
+	args := m.Called(ctx, orgID, id, status)
+	return args.Error(0)
+}
+
 func (m *MockDocumentRepository) GetAllDocumentsPaginated(ctx context.Context, orgID uuid.UUID, params pagination.PaginationParams, filters pagination.DocumentFilterParams) ([]entity.EsfDocument, int64, error) {
 	args := m.Called(ctx, orgID, params, filters)
 	if args.Get(0) == nil {
@@ -63,6 +108,215 @@ func (m *MockDocumentRepository) GetAllDocumentsPaginated(ctx context.Context, o
 	return args.Get(0).([]entity.EsfDocument), args.Get(1).(int64), args.Error(2)
 }
 
+func (m *MockDocumentRepository) GetAllDocumentsCursor(ctx context.Context, orgID uuid.UUID, params pagination.CursorParams, filters pagination.DocumentFilterParams) ([]entity.EsfDocument, pagination.CursorInfo, error) {
+	args := m.Called(ctx, orgID, params, filters)
+	if args.Get(0) == nil {
+		return nil, pagination.CursorInfo{}, args.Error(2)
+	}
+	return args.Get(0).([]entity.EsfDocument), args.Get(1).(pagination.CursorInfo), args.Error(2)
+}
+
+func (m *MockDocumentRepository) SearchDocuments(ctx context.Context, orgID uuid.UUID, text string, params pagination.PaginationParams) ([]entity.EsfDocument, int64, error) {
+	args := m.Called(ctx, orgID, text, params)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]entity.EsfDocument), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockDocumentRepository) GetDocumentsByIDs(ctx context.Context, orgID uuid.UUID, ids []uuid.UUID) ([]entity.EsfDocument, error) {
+	args := m.Called(ctx, orgID, ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.EsfDocument), args.Error(1)
+}
+
+func (m *MockDocumentRepository) SetSearchOutbox(enabled bool) {
+	m.Called(enabled)
+}
+
+func (m *MockDocumentRepository) FetchSearchOutbox(ctx context.Context, orgID uuid.UUID, limit int) ([]entity.SearchOutboxEntry, error) {
+	args := m.Called(ctx, orgID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.SearchOutboxEntry), args.Error(1)
+}
+
+func (m *MockDocumentRepository) DeleteSearchOutbox(ctx context.Context, orgID uuid.UUID, ids []int64) error {
+	args := m.Called(ctx, orgID, ids)
+	return args.Error(0)
+}
+
+// RelayEvents передает publish события, заданные в On(...).Return(events, err)
+func (m *MockDocumentRepository) RelayEvents(ctx context.Context, orgID uuid.UUID, limit int, publish repository.PublishEventFunc) (int, error) {
+	args := m.Called(ctx, orgID, limit)
+	pending, _ := args.Get(0).([]entity.OutboxEvent)
+	for i, event := range pending {
+		if err := publish(ctx, event); err != nil {
+			return i, err
+		}
+	}
+	return len(pending), args.Error(1)
+}
+
+func (m *MockDocumentRepository) ReplayEvents(ctx context.Context, filter repository.EventReplayFilter, publish repository.PublishEventFunc) (int, error) {
+	args := m.Called(ctx, filter)
+	events, _ := args.Get(0).([]entity.OutboxEvent)
+	for i, event := range events {
+		if err := publish(ctx, event); err != nil {
+			return i, err
+		}
+	}
+	return len(events), args.Error(1)
+}
+
+func (m *MockDocumentRepository) PurgePublishedEvents(ctx context.Context, orgID uuid.UUID, before time.Time) (int64, error) {
+	args := m.Called(ctx, orgID, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockDocumentRepository) ListPayments(ctx context.Context, orgID uuid.UUID, documentID uuid.UUID) ([]entity.Payment, error) {
+	args := m.Called(ctx, orgID, documentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Payment), args.Error(1)
+}
+
+func (m *MockDocumentRepository) AddPayment(ctx context.Context, orgID uuid.UUID, payment *entity.Payment) error {
+	args := m.Called(ctx, orgID, payment)
+	return args.Error(0)
+}
+
+func (m *MockDocumentRepository) DeletePayment(ctx context.Context, orgID uuid.UUID, documentID uuid.UUID, paymentID uuid.UUID) error {
+	args := m.Called(ctx, orgID, documentID, paymentID)
+	return args.Error(0)
+}
+
+func (m *MockDocumentRepository) CreateBankStatement(ctx context.Context, orgID uuid.UUID, statement *entity.BankStatement) error {
+	args := m.Called(ctx, orgID, statement)
+	return args.Error(0)
+}
+
+func (m *MockDocumentRepository) ListBankStatements(ctx context.Context, orgID uuid.UUID) ([]entity.BankStatement, error) {
+	args := m.Called(ctx, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.BankStatement), args.Error(1)
+}
+
+func (m *MockDocumentRepository) GetBankStatement(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*entity.BankStatement, error) {
+	args := m.Called(ctx, orgID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.BankStatement), args.Error(1)
+}
+
+func (m *MockDocumentRepository) FindPaymentCandidates(ctx context.Context, orgID uuid.UUID, tin string, amount float64, limit int) ([]entity.EsfDocument, error) {
+	args := m.Called(ctx, orgID, tin, amount, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.EsfDocument), args.Error(1)
+}
+
+func (m *MockDocumentRepository) MatchStatementLine(ctx context.Context, orgID uuid.UUID, statementID uuid.UUID, lineID uuid.UUID, payment *entity.Payment, matchedBy string) error {
+	args := m.Called(ctx, orgID, statementID, lineID, payment, matchedBy)
+	return args.Error(0)
+}
+
+func (m *MockDocumentRepository) ListContracts(ctx context.Context, orgID uuid.UUID, contractorTin string) ([]entity.Contract, error) {
+	args := m.Called(ctx, orgID, contractorTin)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.Contract), args.Error(1)
+}
+
+func (m *MockDocumentRepository) GetContract(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*entity.Contract, error) {
+	args := m.Called(ctx, orgID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.Contract), args.Error(1)
+}
+
+func (m *MockDocumentRepository) CreateContract(ctx context.Context, orgID uuid.UUID, contract *entity.Contract) error {
+	args := m.Called(ctx, orgID, contract)
+	return args.Error(0)
+}
+
+func (m *MockDocumentRepository) UpdateContract(ctx context.Context, orgID uuid.UUID, contract *entity.Contract) error {
+	args := m.Called(ctx, orgID, contract)
+	return args.Error(0)
+}
+
+func (m *MockDocumentRepository) DeleteContract(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error {
+	args := m.Called(ctx, orgID, id)
+	return args.Error(0)
+}
+
+func (m *MockDocumentRepository) ContractUsage(ctx context.Context, orgID uuid.UUID, contractID uuid.UUID, excludeDocID uuid.UUID) (float64, int64, error) {
+	args := m.Called(ctx, orgID, contractID, excludeDocID)
+	return args.Get(0).(float64), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockDocumentRepository) ListPriceLists(ctx context.Context, orgID uuid.UUID) ([]entity.PriceList, error) {
+	args := m.Called(ctx, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.PriceList), args.Error(1)
+}
+
+func (m *MockDocumentRepository) GetPriceList(ctx context.Context, orgID uuid.UUID, id uuid.UUID) (*entity.PriceList, error) {
+	args := m.Called(ctx, orgID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*entity.PriceList), args.Error(1)
+}
+
+func (m *MockDocumentRepository) CreatePriceList(ctx context.Context, orgID uuid.UUID, list *entity.PriceList) error {
+	args := m.Called(ctx, orgID, list)
+	return args.Error(0)
+}
+
+func (m *MockDocumentRepository) UpdatePriceList(ctx context.Context, orgID uuid.UUID, list *entity.PriceList) error {
+	args := m.Called(ctx, orgID, list)
+	return args.Error(0)
+}
+
+func (m *MockDocumentRepository) DeletePriceList(ctx context.Context, orgID uuid.UUID, id uuid.UUID) error {
+	args := m.Called(ctx, orgID, id)
+	return args.Error(0)
+}
+
+func (m *MockDocumentRepository) FindPriceLists(ctx context.Context, orgID uuid.UUID, contractorTin string, currency string, date time.Time) ([]entity.PriceList, error) {
+	args := m.Called(ctx, orgID, contractorTin, currency, date)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.PriceList), args.Error(1)
+}
+
+func (m *MockDocumentRepository) ListCustomerGroupMembers(ctx context.Context, orgID uuid.UUID) ([]entity.CustomerGroupMember, error) {
+	args := m.Called(ctx, orgID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]entity.CustomerGroupMember), args.Error(1)
+}
+
+func (m *MockDocumentRepository) SetCustomerGroupMembers(ctx context.Context, orgID uuid.UUID, group string, tins []string) error {
+	args := m.Called(ctx, orgID, group, tins)
+	return args.Error(0)
+}
+
 var _ repository.EsfDocumentRepository = (*MockDocumentRepository)(nil)
 
 // ========== GetAllDocuments Tests ==========
@@ -234,6 +488,38 @@ func TestEsfDocumentDelete_Error(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+// ========== Restore/Purge Tests ==========
+
+func TestEsfDocumentRestore_Success(t *testing.T) {
+	mockRepo := new(MockDocumentRepository)
+	orgID := uuid.New()
+	docID := uuid.New()
+
+	mockRepo.On("RestoreDocument", mock.Anything, orgID, docID).Return(nil)
+
+	service := NewEsfDocumentService(mockRepo, nil, logrus.New())
+	err := service.RestoreDocument(context.Background(), orgID, docID)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestEsfDocumentPurge_NotInTrash(t *testing.T) {
+	mockRepo := new(MockDocumentRepository)
+	orgID := uuid.New()
+	docID := uuid.New()
+	notFound := apperror.New(apperror.ErrDocumentNotFound, "deleted document not found")
+
+	mockRepo.On("PurgeDocument", mock.Anything, orgID, docID).Return(notFound)
+
+	service := NewEsfDocumentService(mockRepo, nil, logrus.New())
+	err := service.PurgeDocument(context.Background(), orgID, docID)
+
+	// Ошибка репозитория возвращается без обертки, чтобы сохранить код NOT_FOUND
+	assert.ErrorIs(t, err, notFound)
+	mockRepo.AssertExpectations(t)
+}
+
 // ========== GetAllDocumentsPaginated Tests ==========
 
 func TestEsfDocumentGetAllPaginated_Success(t *testing.T) {
@@ -0,0 +1,327 @@
+package service_impl
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/jobs"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+)
+
+// TenantCredentialRotationJob тип фоновой задачи ротации учетных данных БД организаций
+const TenantCredentialRotationJob = "tenants.rotate_credentials"
+
+// tenantPasswordBytes длина пароля роли до кодирования base64url
+const tenantPasswordBytes = 32
+
+// pgIdentifier имя роли или БД, которое можно подставить в SQL в двойных кавычках
+var pgIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,62}$`)
+
+// credentialRotationRetryPolicy повторяется только попытка, прерванная целиком; повторная
+// ротация организации безопасна и лишь еще раз меняет роль
+var credentialRotationRetryPolicy = jobs.RetryPolicy{
+	MaxRetries: 3,
+	Backoff:    jobs.ExponentialBackoff(30*time.Second, 5*time.Minute),
+}
+
+// credentialRotationPayload полезная нагрузка задачи ротации
+type credentialRotationPayload struct {
+	OperationID     uuid.UUID   `json:"operation_id"`
+	OrganizationIDs []uuid.UUID `json:"organization_ids"`
+}
+
+// tenantCredentialService реализует TenantCredentialService
+type tenantCredentialService struct {
+	repo       repository.TenantCredentialRepository
+	orgRepo    repository.EsfOrganizationRepository
+	pools      services.OrganizationDBService
+	operations services.OperationService
+	jobs       *jobs.Manager
+	cfg        services.TenantCredentialConfig
+	// apply создает роль или задает ей пароль и права на БД организации
+	apply func(ctx context.Context, database string, conn tenantConnection) error
+	// verify подключается к БД организации с новыми учетными данными
+	verify func(ctx context.Context, conn tenantConnection) error
+	now    func() time.Time
+	logger *logger.Logger
+}
+
+// NewTenantCredentialService создает сервис ротации и регистрирует обработчик задачи
+// TenantCredentialRotationJob. pools переподключаются после ротации; nil — пулов нет.
+func NewTenantCredentialService(
+	repo repository.TenantCredentialRepository,
+	orgRepo repository.EsfOrganizationRepository,
+	pools services.OrganizationDBService,
+	operations services.OperationService,
+	manager *jobs.Manager,
+	cfg services.TenantCredentialConfig,
+	log *logrus.Logger,
+) services.TenantCredentialService {
+	if cfg.Queue == "" {
+		cfg.Queue = jobs.DefaultQueue
+	}
+
+	s := &tenantCredentialService{
+		repo:       repo,
+		orgRepo:    orgRepo,
+		pools:      pools,
+		operations: operations,
+		jobs:       manager,
+		cfg:        cfg,
+		apply:      applyTenantRole,
+		verify:     verifyTenantConnection,
+		now:        time.Now,
+		logger:     logger.New(log),
+	}
+	if manager != nil {
+		manager.Register(TenantCredentialRotationJob, s.handleRotation, credentialRotationRetryPolicy)
+	}
+	return s
+}
+
+// Rotate проверяет организации и ставит задачу ротации
+func (s *tenantCredentialService) Rotate(ctx context.Context, req services.TenantCredentialRotationRequest) (*entity.Operation, error) {
+	ids := uniqueIDs(req.OrganizationIDs)
+	if len(ids) > 0 {
+		orgs, err := s.targets(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		if len(orgs) < len(ids) {
+			return nil, apperror.ValidationError("unknown organization ID").
+				WithDetails(fmt.Sprintf("%d of %d organizations not found", len(ids)-len(orgs), len(ids)))
+		}
+	}
+
+	op, err := s.operations.Start(ctx, services.NewOperation{Kind: services.OperationKindCredentialRotation, RequestedBy: req.RequestedBy})
+	if err != nil {
+		return nil, err
+	}
+
+	payload := credentialRotationPayload{OperationID: op.ID, OrganizationIDs: ids}
+	if _, err := s.jobs.Enqueue(ctx, TenantCredentialRotationJob, payload, jobs.WithQueue(s.cfg.Queue)); err != nil {
+		s.logger.Error(ctx, "Failed to enqueue tenant credential rotation", err, logrus.Fields{"operation_id": op.ID.String()})
+		s.operations.Fail(ctx, op.ID, err)
+		return nil, apperror.From(err, apperror.ErrInternal, "failed to queue credential rotation")
+	}
+
+	s.logger.Info(ctx, "Tenant credential rotation queued", logrus.Fields{"operation_id": op.ID.String(), "organizations": len(ids)})
+	return op, nil
+}
+
+// targets возвращает организации из списка; пустой список — все организации
+func (s *tenantCredentialService) targets(ctx context.Context, ids []uuid.UUID) ([]*entity.EstOrganization, error) {
+	orgs, err := s.orgRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return orgs, nil
+	}
+
+	wanted := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	selected := make([]*entity.EstOrganization, 0, len(ids))
+	for _, org := range orgs {
+		if wanted[org.ID] {
+			selected = append(selected, org)
+		}
+	}
+	return selected, nil
+}
+
+// handleRotation меняет учетные данные организаций по очереди. Сбой одной организации
+// не останавливает остальные: ее текущие учетные данные остаются в силе.
+func (s *tenantCredentialService) handleRotation(ctx context.Context, job *jobs.Job) error {
+	var payload credentialRotationPayload
+	if err := job.Decode(&payload); err != nil {
+		return jobs.Permanent(err)
+	}
+
+	opID := payload.OperationID
+	fields := logrus.Fields{"operation_id": opID.String()}
+	s.operations.Running(ctx, opID)
+
+	failed, total, err := s.rotateAll(ctx, opID, payload.OrganizationIDs)
+	if err == nil && failed > 0 {
+		err = jobs.Permanent(fmt.Errorf("%d of %d tenant credentials failed to rotate", failed, total))
+	}
+	fields["tenants"] = total
+	fields["failed"] = failed
+	if err != nil {
+		if job.Attempt >= job.MaxRetries || jobs.IsPermanent(err) {
+			s.operations.Fail(ctx, opID, err)
+		} else {
+			s.operations.Retry(ctx, opID, err)
+		}
+		s.logger.Error(ctx, "Tenant credential rotation failed", err, fields)
+		return err
+	}
+
+	s.operations.Succeed(ctx, opID, "")
+	s.logger.Info(ctx, "Tenant credential rotation completed", fields)
+	return nil
+}
+
+// rotateAll возвращает число неудачных организаций и общее число; ошибка означает, что обход прерван
+func (s *tenantCredentialService) rotateAll(ctx context.Context, opID uuid.UUID, ids []uuid.UUID) (int, int, error) {
+	orgs, err := s.targets(ctx, ids)
+	if err != nil {
+		return 0, 0, err
+	}
+	total := int64(len(orgs))
+	s.operations.Progress(ctx, opID, 0, total)
+
+	failed := 0
+	for i, org := range orgs {
+		if err := s.rotateTenant(ctx, org); err != nil {
+			if ctx.Err() != nil {
+				return failed, len(orgs), ctx.Err()
+			}
+			failed++
+			s.logger.Warn(ctx, "Failed to rotate tenant credentials", logrus.Fields{
+				"operation_id": opID.String(),
+				"org_id":       org.ID.String(),
+				"dbName":       org.DBName,
+				"error":        err.Error(),
+			})
+		}
+		s.operations.Progress(ctx, opID, int64(i+1), total)
+	}
+	return failed, len(orgs), nil
+}
+
+// rotateTenant задает новый пароль роли, не используемой сейчас, проверяет подключение с ним,
+// сохраняет учетные данные и переподключает пул. До сохранения организация работает с прежней ролью.
+func (s *tenantCredentialService) rotateTenant(ctx context.Context, org *entity.EstOrganization) error {
+	current, err := s.repo.Get(ctx, org.ID)
+	if err != nil {
+		return err
+	}
+
+	password, err := generateTenantPassword()
+	if err != nil {
+		return err
+	}
+	conn := tenantConnection{database: org.DBName, username: nextTenantRole(org.DBName, current), password: password}
+
+	if err := s.apply(ctx, org.DBName, conn); err != nil {
+		return fmt.Errorf("applying role %s: %w", conn.username, err)
+	}
+	if err := s.verify(ctx, conn); err != nil {
+		return fmt.Errorf("connecting as %s: %w", conn.username, err)
+	}
+
+	credential := &entity.TenantDBCredential{
+		OrganizationID: org.ID,
+		Database:       org.DBName,
+		Username:       conn.username,
+		Password:       conn.password,
+		RotatedAt:      s.now(),
+	}
+	if err := s.repo.Save(ctx, credential); err != nil {
+		return err
+	}
+
+	fields := logrus.Fields{"org_id": org.ID.String(), "dbName": org.DBName, "username": conn.username}
+	if s.pools != nil {
+		// Новые учетные данные уже сохранены: при ошибке пул переподключится при следующей сверке
+		if err := s.pools.Reconnect(ctx, org.ID); err != nil {
+			fields["error"] = err.Error()
+			s.logger.Warn(ctx, "Failed to reconnect tenant pool after rotation", fields)
+			delete(fields, "error")
+		}
+	}
+	s.logger.Info(ctx, "Tenant credentials rotated", fields)
+	return nil
+}
+
+// nextTenantRole возвращает роль, не используемую сейчас: <БД>_a и <БД>_b чередуются
+func nextTenantRole(dbName string, current *entity.TenantDBCredential) string {
+	base := dbName[:min(len(dbName), 61)]
+	if current != nil && current.Username == base+"_a" {
+		return base + "_b"
+	}
+	return base + "_a"
+}
+
+// generateTenantPassword создает случайный пароль из символов base64url, безопасных в SQL-литерале и DSN
+func generateTenantPassword() (string, error) {
+	buf := make([]byte, tenantPasswordBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generating password: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// applyTenantRole создает роль или меняет ее пароль и выдает права на данные БД организации.
+// Выполняется владельцем БД (DB_USER), которому нужна привилегия CREATEROLE; схема по-прежнему
+// меняется миграциями от имени владельца, поэтому права на будущие таблицы выдаются по умолчанию.
+func applyTenantRole(ctx context.Context, database string, conn tenantConnection) error {
+	if !pgIdentifier.MatchString(database) || !pgIdentifier.MatchString(conn.username) {
+		return fmt.Errorf("invalid identifier: database %q, role %q", database, conn.username)
+	}
+
+	db, err := openTenantDatabase(database)
+	if err != nil {
+		return err
+	}
+	defer closeDatabase(db)
+
+	role := `"` + conn.username + `"`
+	return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var exists bool
+		if err := tx.Raw("SELECT EXISTS (SELECT 1 FROM pg_roles WHERE rolname = ?)", conn.username).Scan(&exists).Error; err != nil {
+			return err
+		}
+		action := "CREATE ROLE"
+		if exists {
+			action = "ALTER ROLE"
+		}
+
+		statements := []string{
+			fmt.Sprintf("%s %s WITH LOGIN PASSWORD '%s'", action, role, conn.password),
+			fmt.Sprintf(`GRANT CONNECT, TEMPORARY ON DATABASE "%s" TO %s`, database, role),
+			"GRANT USAGE ON SCHEMA public TO " + role,
+			"GRANT SELECT, INSERT, UPDATE, DELETE ON ALL TABLES IN SCHEMA public TO " + role,
+			"GRANT USAGE, SELECT, UPDATE ON ALL SEQUENCES IN SCHEMA public TO " + role,
+			"ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT SELECT, INSERT, UPDATE, DELETE ON TABLES TO " + role,
+			"ALTER DEFAULT PRIVILEGES IN SCHEMA public GRANT USAGE, SELECT, UPDATE ON SEQUENCES TO " + role,
+		}
+		for _, stmt := range statements {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// verifyTenantConnection подключается с новыми учетными данными до того, как они будут сохранены
+func verifyTenantConnection(ctx context.Context, conn tenantConnection) error {
+	db, err := openDatabaseAs(conn.database, conn.username, conn.password)
+	if err != nil {
+		return err
+	}
+	defer closeDatabase(db)
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
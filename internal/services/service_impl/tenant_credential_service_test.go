@@ -0,0 +1,155 @@
+package service_impl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/jobs"
+)
+
+// memoryTenantCredentialRepository хранит учетные данные в памяти
+type memoryTenantCredentialRepository struct {
+	mu    sync.Mutex
+	creds map[uuid.UUID]entity.TenantDBCredential
+}
+
+func (m *memoryTenantCredentialRepository) Get(ctx context.Context, organizationID uuid.UUID) (*entity.TenantDBCredential, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cred, ok := m.creds[organizationID]
+	if !ok {
+		return nil, nil
+	}
+	return &cred, nil
+}
+
+func (m *memoryTenantCredentialRepository) Save(ctx context.Context, credential *entity.TenantDBCredential) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.creds[credential.OrganizationID] = *credential
+	return nil
+}
+
+// stubTenantPools запоминает переподключенные организации
+type stubTenantPools struct {
+	reconnected []uuid.UUID
+}
+
+func (s *stubTenantPools) CreateOrganizationDatabase(ctx context.Context, organizationID uuid.UUID) error {
+	return nil
+}
+
+func (s *stubTenantPools) GetOrganizationDatabase(ctx context.Context, organizationID uuid.UUID) (*gorm.DB, error) {
+	return nil, nil
+}
+
+func (s *stubTenantPools) Reconnect(ctx context.Context, organizationID uuid.UUID) error {
+	s.reconnected = append(s.reconnected, organizationID)
+	return nil
+}
+
+func (s *stubTenantPools) DeleteOrganizationDatabase(ctx context.Context, organizationID uuid.UUID) error {
+	return nil
+}
+
+func TestTenantCredentialRotation_AlternatesRoles(t *testing.T) {
+	orgs := []*entity.EstOrganization{
+		{ID: uuid.New(), DBName: "alpha_db"},
+		{ID: uuid.New(), DBName: "beta_db"},
+	}
+	operations := NewOperationService(newMemoryOperationRepository(), logrus.New())
+	repo := &memoryTenantCredentialRepository{creds: map[uuid.UUID]entity.TenantDBCredential{}}
+	pools := &stubTenantPools{}
+	s := NewTenantCredentialService(repo, &stubOrganizationRepository{orgs: orgs}, pools, operations, nil, services.TenantCredentialConfig{}, logrus.New()).(*tenantCredentialService)
+
+	applied := map[string]string{}
+	broken := ""
+	s.apply = func(ctx context.Context, database string, conn tenantConnection) error {
+		applied[conn.username] = conn.password
+		return nil
+	}
+	s.verify = func(ctx context.Context, conn tenantConnection) error {
+		if conn.database == broken {
+			return errors.New("password authentication failed")
+		}
+		if applied[conn.username] != conn.password {
+			return errors.New("password was not applied")
+		}
+		return nil
+	}
+
+	ctx := context.Background()
+	rotate := func() error {
+		op, err := operations.Start(ctx, services.NewOperation{Kind: services.OperationKindCredentialRotation, RequestedBy: "admin"})
+		require.NoError(t, err)
+		payload, err := json.Marshal(credentialRotationPayload{OperationID: op.ID})
+		require.NoError(t, err)
+		return s.handleRotation(ctx, &jobs.Job{Type: TenantCredentialRotationJob, Payload: payload, MaxRetries: credentialRotationRetryPolicy.MaxRetries})
+	}
+
+	require.NoError(t, rotate())
+	first, err := repo.Get(ctx, orgs[0].ID)
+	require.NoError(t, err)
+	require.NotNil(t, first)
+	assert.Equal(t, "alpha_db_a", first.Username)
+	assert.Equal(t, "alpha_db", first.Database)
+	assert.Len(t, first.Password, 43)
+	assert.Equal(t, []uuid.UUID{orgs[0].ID, orgs[1].ID}, pools.reconnected)
+
+	// Следующая ротация переключает на вторую роль, прежняя остается рабочей до нее
+	require.NoError(t, rotate())
+	second, err := repo.Get(ctx, orgs[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "alpha_db_b", second.Username)
+	assert.NotEqual(t, first.Password, second.Password)
+
+	// Неудачная проверка подключения оставляет прежние учетные данные
+	broken = "beta_db"
+	err = rotate()
+	require.Error(t, err)
+	assert.True(t, jobs.IsPermanent(err))
+	assert.Equal(t, "1 of 2 tenant credentials failed to rotate", err.Error())
+
+	beta, err := repo.Get(ctx, orgs[1].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "beta_db_b", beta.Username)
+	alpha, err := repo.Get(ctx, orgs[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "alpha_db_a", alpha.Username)
+	assert.Len(t, pools.reconnected, 5)
+}
+
+func TestTenantCredentialRotation_RejectsUnknownOrganizations(t *testing.T) {
+	operations := NewOperationService(newMemoryOperationRepository(), logrus.New())
+	repo := &memoryTenantCredentialRepository{creds: map[uuid.UUID]entity.TenantDBCredential{}}
+	orgs := []*entity.EstOrganization{{ID: uuid.New(), DBName: "alpha_db"}}
+	s := NewTenantCredentialService(repo, &stubOrganizationRepository{orgs: orgs}, nil, operations, nil, services.TenantCredentialConfig{}, logrus.New())
+
+	_, err := s.Rotate(context.Background(), services.TenantCredentialRotationRequest{
+		RequestedBy:     "admin",
+		OrganizationIDs: []uuid.UUID{orgs[0].ID, uuid.New()},
+	})
+	var appErr *apperror.AppError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, apperror.ErrValidation, appErr.Code)
+}
+
+func TestNextTenantRole_TruncatesLongNames(t *testing.T) {
+	long := "org_" + strings.Repeat("x", 59)
+	role := nextTenantRole(long, &entity.TenantDBCredential{Username: long[:61] + "_a"})
+	assert.Equal(t, long[:61]+"_b", role)
+	assert.LessOrEqual(t, len(role), 63)
+}
@@ -0,0 +1,40 @@
+package service_impl
+
+import (
+	"context"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/pkg/health"
+)
+
+// TenantHealthTargets возвращает список БД организаций для health.TenantMonitor
+func TenantHealthTargets(orgRepo repository.EsfOrganizationRepository) func(ctx context.Context) ([]health.TenantTarget, error) {
+	return func(ctx context.Context) ([]health.TenantTarget, error) {
+		orgs, err := orgRepo.GetAll(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		targets := make([]health.TenantTarget, 0, len(orgs))
+		for _, org := range orgs {
+			targets = append(targets, health.TenantTarget{
+				OrganizationID: org.ID.String(),
+				Name:           org.Name,
+				Database:       org.DBName,
+			})
+		}
+		return targets, nil
+	}
+}
+
+// CheckTenantDatabase подключается к БД организации отдельным подключением, как и миграции,
+// чтобы проверка не занимала и не оставляла открытым пул запросов организации
+func CheckTenantDatabase(ctx context.Context, target health.TenantTarget) (health.TenantDBStats, error) {
+	db, err := openTenantDatabase(target.Database)
+	if err != nil {
+		return health.TenantDBStats{}, err
+	}
+	defer closeDatabase(db)
+
+	return health.PostgresTenantStats(ctx, db)
+}
@@ -0,0 +1,366 @@
+package service_impl
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/jobs"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/migrations"
+)
+
+// TenantMigrationJob тип фоновой задачи миграции БД организаций
+const TenantMigrationJob = "migrations.tenants"
+
+// tenantMigrationRetryPolicy повторяется только попытка, прерванная целиком (например, ошибкой
+// основной БД); сбой отдельной БД организации завершает операцию, и ее возобновляет администратор
+var tenantMigrationRetryPolicy = jobs.RetryPolicy{
+	MaxRetries: 3,
+	Backoff:    jobs.ExponentialBackoff(30*time.Second, 5*time.Minute),
+}
+
+// tenantMigrationPayload полезная нагрузка задачи миграции
+type tenantMigrationPayload struct {
+	OperationID uuid.UUID `json:"operation_id"`
+	Concurrency int       `json:"concurrency"`
+}
+
+// tenantMigrationService реализует TenantMigrationService
+type tenantMigrationService struct {
+	repo       repository.TenantMigrationRepository
+	orgRepo    repository.EsfOrganizationRepository
+	operations services.OperationService
+	jobs       *jobs.Manager
+	cfg        services.TenantMigrationConfig
+	// migrate применяет миграции к БД организации и возвращает примененные версии
+	migrate func(ctx context.Context, dbName string) ([]string, error)
+	logger  *logger.Logger
+}
+
+// NewTenantMigrationService создает сервис миграции БД организаций и регистрирует обработчик
+// задачи TenantMigrationJob. Менеджер задач должен быть запущен после вызова.
+func NewTenantMigrationService(
+	repo repository.TenantMigrationRepository,
+	orgRepo repository.EsfOrganizationRepository,
+	operations services.OperationService,
+	manager *jobs.Manager,
+	cfg services.TenantMigrationConfig,
+	log *logrus.Logger,
+) services.TenantMigrationService {
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = services.DefaultTenantMigrationConcurrency
+	}
+	cfg.Concurrency = min(cfg.Concurrency, services.MaxTenantMigrationConcurrency)
+	if cfg.Queue == "" {
+		cfg.Queue = jobs.DefaultQueue
+	}
+
+	s := &tenantMigrationService{
+		repo:       repo,
+		orgRepo:    orgRepo,
+		operations: operations,
+		jobs:       manager,
+		cfg:        cfg,
+		migrate:    migrateTenantDatabase,
+		logger:     logger.New(log),
+	}
+	if manager != nil {
+		manager.Register(TenantMigrationJob, s.handleMigration, tenantMigrationRetryPolicy)
+	}
+	return s
+}
+
+// TenantMigrationPath путь итога операции миграции; служит ссылкой на результат операции
+func TenantMigrationPath(operationID uuid.UUID) string {
+	return "/api/admin/migrations/tenants/" + operationID.String()
+}
+
+// Start создает операцию и ставит задачу миграции
+func (s *tenantMigrationService) Start(ctx context.Context, req services.TenantMigrationRequest) (*entity.Operation, error) {
+	concurrency, err := s.concurrency(req.Concurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := s.operations.Start(ctx, services.NewOperation{Kind: services.OperationKindTenantMigration, RequestedBy: req.RequestedBy})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.enqueue(ctx, op.ID, concurrency); err != nil {
+		s.operations.Fail(ctx, op.ID, err)
+		return nil, err
+	}
+
+	s.logger.Info(ctx, "Tenant migration queued", logrus.Fields{"operation_id": op.ID.String(), "concurrency": concurrency})
+	return op, nil
+}
+
+// Resume возвращает завершенную с ошибкой операцию в очередь. Ошибка прошлой попытки
+// остается видна, пока задача не начнет выполняться.
+func (s *tenantMigrationService) Resume(ctx context.Context, operationID uuid.UUID, req services.TenantMigrationRequest) (*entity.Operation, error) {
+	concurrency, err := s.concurrency(req.Concurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := s.getOperation(ctx, operationID)
+	if err != nil {
+		return nil, err
+	}
+	op := status.Operation
+	if op.State != entity.OperationStateFailed {
+		return nil, apperror.ConflictError("only failed tenant migrations can be resumed").
+			WithDetails("operation is " + op.State)
+	}
+
+	s.operations.Retry(ctx, op.ID, apperror.New(apperror.ErrorCode(op.ErrorCode), op.ErrorMessage))
+	if err := s.enqueue(ctx, op.ID, concurrency); err != nil {
+		s.operations.Fail(ctx, op.ID, err)
+		return nil, err
+	}
+	op.State = entity.OperationStatePending
+
+	s.logger.Info(ctx, "Tenant migration resumed", logrus.Fields{
+		"operation_id": op.ID.String(),
+		"requested_by": req.RequestedBy,
+		"concurrency":  concurrency,
+	})
+	return op, nil
+}
+
+// GetSummary собирает итог операции из сохраненных результатов
+func (s *tenantMigrationService) GetSummary(ctx context.Context, operationID uuid.UUID) (*services.TenantMigrationSummary, error) {
+	status, err := s.getOperation(ctx, operationID)
+	if err != nil {
+		return nil, err
+	}
+	records, err := s.repo.ListByOperation(ctx, operationID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &services.TenantMigrationSummary{
+		Operation: status,
+		Tenants:   make([]services.TenantMigrationResult, 0, len(records)),
+	}
+	for _, record := range records {
+		if record.State == entity.TenantMigrationSucceeded {
+			summary.Succeeded++
+		} else {
+			summary.Failed++
+		}
+		summary.Tenants = append(summary.Tenants, services.TenantMigrationResult{
+			OrganizationID: record.OrganizationID,
+			Database:       record.Database,
+			State:          record.State,
+			Applied:        splitVersions(record.Applied),
+			Error:          record.Error,
+			FinishedAt:     record.UpdatedAt,
+		})
+	}
+	summary.Remaining = max(status.Total-int64(len(records)), 0)
+	return summary, nil
+}
+
+// getOperation возвращает операцию миграции; операции другого вида считаются ненайденными
+func (s *tenantMigrationService) getOperation(ctx context.Context, operationID uuid.UUID) (*services.OperationStatus, error) {
+	status, err := s.operations.GetOperation(ctx, operationID)
+	if err != nil {
+		return nil, err
+	}
+	if status.Kind != services.OperationKindTenantMigration {
+		return nil, apperror.NotFoundError("tenant migration")
+	}
+	return status, nil
+}
+
+// concurrency проверяет число одновременных миграций из запроса
+func (s *tenantMigrationService) concurrency(requested int) (int, error) {
+	switch {
+	case requested == 0:
+		return s.cfg.Concurrency, nil
+	case requested < 0 || requested > services.MaxTenantMigrationConcurrency:
+		return 0, apperror.ValidationError("invalid concurrency").
+			WithDetails(fmt.Sprintf("concurrency must be from 1 to %d", services.MaxTenantMigrationConcurrency))
+	}
+	return requested, nil
+}
+
+func (s *tenantMigrationService) enqueue(ctx context.Context, operationID uuid.UUID, concurrency int) error {
+	payload := tenantMigrationPayload{OperationID: operationID, Concurrency: concurrency}
+	if _, err := s.jobs.Enqueue(ctx, TenantMigrationJob, payload, jobs.WithQueue(s.cfg.Queue)); err != nil {
+		s.logger.Error(ctx, "Failed to enqueue tenant migration", err, logrus.Fields{"operation_id": operationID.String()})
+		return apperror.From(err, apperror.ErrInternal, "failed to queue tenant migration")
+	}
+	return nil
+}
+
+// handleMigration мигрирует БД организаций пулом из Concurrency воркеров. БД, уже мигрированные
+// в этой операции, пропускаются, поэтому повтор задачи и возобновление продолжают с места сбоя.
+func (s *tenantMigrationService) handleMigration(ctx context.Context, job *jobs.Job) error {
+	var payload tenantMigrationPayload
+	if err := job.Decode(&payload); err != nil {
+		return jobs.Permanent(err)
+	}
+	concurrency := payload.Concurrency
+	if concurrency <= 0 || concurrency > services.MaxTenantMigrationConcurrency {
+		concurrency = s.cfg.Concurrency
+	}
+
+	opID := payload.OperationID
+	fields := logrus.Fields{"operation_id": opID.String(), "concurrency": concurrency}
+	s.operations.Running(ctx, opID)
+
+	failed, total, err := s.migrateAll(ctx, opID, concurrency)
+	if err == nil && failed > 0 {
+		err = jobs.Permanent(fmt.Errorf("%d of %d tenant databases failed to migrate", failed, total))
+	}
+	fields["tenants"] = total
+	fields["failed"] = failed
+	if err != nil {
+		if job.Attempt >= job.MaxRetries || jobs.IsPermanent(err) {
+			s.operations.Fail(ctx, opID, err)
+		} else {
+			s.operations.Retry(ctx, opID, err)
+		}
+		s.logger.Error(ctx, "Tenant migration failed", err, fields)
+		return err
+	}
+
+	s.operations.Succeed(ctx, opID, TenantMigrationPath(opID))
+	s.logger.Info(ctx, "Tenant migration completed", fields)
+	return nil
+}
+
+// migrateAll мигрирует БД, еще не мигрированные в операции. Возвращает число неудачных БД
+// и общее число организаций; ошибка означает, что обход прерван.
+func (s *tenantMigrationService) migrateAll(ctx context.Context, opID uuid.UUID, concurrency int) (int, int, error) {
+	orgs, err := s.orgRepo.GetAll(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	previous, err := s.repo.ListByOperation(ctx, opID)
+	if err != nil {
+		return 0, len(orgs), err
+	}
+	succeeded := make(map[uuid.UUID]bool, len(previous))
+	for _, record := range previous {
+		if record.State == entity.TenantMigrationSucceeded {
+			succeeded[record.OrganizationID] = true
+		}
+	}
+
+	pending := make([]*entity.EstOrganization, 0, len(orgs))
+	for _, org := range orgs {
+		if !succeeded[org.ID] {
+			pending = append(pending, org)
+		}
+	}
+	total := int64(len(orgs))
+	processed := total - int64(len(pending))
+	s.operations.Progress(ctx, opID, processed, total)
+
+	var (
+		mu       sync.Mutex
+		failed   int
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	sem := make(chan struct{}, concurrency)
+	for _, org := range pending {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(org *entity.EstOrganization) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			record, err := s.migrateTenant(ctx, opID, org)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			if record.State == entity.TenantMigrationFailed {
+				failed++
+			}
+			processed++
+			s.operations.Progress(ctx, opID, processed, 0)
+		}(org)
+	}
+	wg.Wait()
+
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+	return failed, len(orgs), firstErr
+}
+
+// migrateTenant мигрирует БД одной организации и сохраняет результат. Ошибка возвращается,
+// только если результат не удалось сохранить или задача остановлена.
+func (s *tenantMigrationService) migrateTenant(ctx context.Context, opID uuid.UUID, org *entity.EstOrganization) (*entity.TenantMigration, error) {
+	applied, err := s.migrate(ctx, org.DBName)
+	if err != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	record := &entity.TenantMigration{
+		OperationID:    opID,
+		OrganizationID: org.ID,
+		Database:       org.DBName,
+		State:          entity.TenantMigrationSucceeded,
+		Applied:        strings.Join(applied, ","),
+	}
+	fields := logrus.Fields{"operation_id": opID.String(), "org_id": org.ID.String(), "dbName": org.DBName, "applied": len(applied)}
+	if err != nil {
+		record.State = entity.TenantMigrationFailed
+		record.Error = err.Error()
+		fields["error"] = err.Error()
+		s.logger.Warn(ctx, "Failed to migrate tenant database", fields)
+	} else {
+		s.logger.Info(ctx, "Tenant database migrated", fields)
+	}
+
+	if err := s.repo.Save(ctx, record); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// migrateTenantDatabase применяет миграции к БД организации отдельным подключением
+func migrateTenantDatabase(ctx context.Context, dbName string) ([]string, error) {
+	db, err := openTenantDatabase(dbName)
+	if err != nil {
+		return nil, err
+	}
+	defer closeDatabase(db)
+
+	return migrations.Tenant().Up(ctx, db)
+}
+
+// splitVersions разбирает список версий, сохраненный через запятую
+func splitVersions(raw string) []string {
+	if raw == "" {
+		return []string{}
+	}
+	return strings.Split(raw, ",")
+}
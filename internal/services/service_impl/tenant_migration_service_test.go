@@ -0,0 +1,129 @@
+package service_impl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/jobs"
+)
+
+// memoryTenantMigrationRepository хранит результаты миграции в памяти
+type memoryTenantMigrationRepository struct {
+	mu      sync.Mutex
+	results map[uuid.UUID]entity.TenantMigration
+}
+
+func (m *memoryTenantMigrationRepository) Save(ctx context.Context, result *entity.TenantMigration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.results[result.OrganizationID] = *result
+	return nil
+}
+
+func (m *memoryTenantMigrationRepository) ListByOperation(ctx context.Context, operationID uuid.UUID) ([]entity.TenantMigration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var results []entity.TenantMigration
+	for _, result := range m.results {
+		if result.OperationID == operationID {
+			results = append(results, result)
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Database < results[j].Database })
+	return results, nil
+}
+
+func TestTenantMigration_PartialFailureAndResume(t *testing.T) {
+	orgs := []*entity.EstOrganization{
+		{ID: uuid.New(), DBName: "alpha_db"},
+		{ID: uuid.New(), DBName: "beta_db"},
+		{ID: uuid.New(), DBName: "gamma_db"},
+	}
+	operations := NewOperationService(newMemoryOperationRepository(), logrus.New())
+	repo := &memoryTenantMigrationRepository{results: map[uuid.UUID]entity.TenantMigration{}}
+	s := NewTenantMigrationService(repo, &stubOrganizationRepository{orgs: orgs}, operations, nil, services.TenantMigrationConfig{Concurrency: 2}, logrus.New()).(*tenantMigrationService)
+
+	var mu sync.Mutex
+	calls := map[string]int{}
+	broken := "beta_db"
+	s.migrate = func(ctx context.Context, dbName string) ([]string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls[dbName]++
+		if dbName == broken {
+			return []string{"0001"}, errors.New("migration 0002 failed: column exists")
+		}
+		return []string{"0001", "0002"}, nil
+	}
+
+	ctx := context.Background()
+	op, err := operations.Start(ctx, services.NewOperation{Kind: services.OperationKindTenantMigration, RequestedBy: "admin"})
+	require.NoError(t, err)
+	payload, err := json.Marshal(tenantMigrationPayload{OperationID: op.ID, Concurrency: 2})
+	require.NoError(t, err)
+	job := &jobs.Job{Type: TenantMigrationJob, Payload: payload, MaxRetries: tenantMigrationRetryPolicy.MaxRetries}
+
+	// Сбой одной БД завершает операцию с ошибкой, остальные мигрированы
+	err = s.handleMigration(ctx, job)
+	require.Error(t, err)
+	assert.True(t, jobs.IsPermanent(err))
+
+	summary, err := s.GetSummary(ctx, op.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.OperationStateFailed, summary.Operation.State)
+	assert.Equal(t, "1 of 3 tenant databases failed to migrate", summary.Operation.ErrorMessage)
+	assert.Equal(t, 2, summary.Succeeded)
+	assert.Equal(t, 1, summary.Failed)
+	assert.Equal(t, int64(0), summary.Remaining)
+	require.Len(t, summary.Tenants, 3)
+	assert.Equal(t, "beta_db", summary.Tenants[1].Database)
+	assert.Equal(t, []string{"0001"}, summary.Tenants[1].Applied)
+	assert.Contains(t, summary.Tenants[1].Error, "column exists")
+
+	// Возобновление мигрирует только БД, которые не удалось мигрировать
+	broken = ""
+	require.NoError(t, s.handleMigration(ctx, job))
+	assert.Equal(t, map[string]int{"alpha_db": 1, "beta_db": 2, "gamma_db": 1}, calls)
+
+	summary, err = s.GetSummary(ctx, op.ID)
+	require.NoError(t, err)
+	assert.Equal(t, entity.OperationStateSucceeded, summary.Operation.State)
+	assert.Equal(t, TenantMigrationPath(op.ID), summary.Operation.ResultURL)
+	assert.Equal(t, 3, summary.Succeeded)
+	assert.Equal(t, int64(3), summary.Operation.Processed)
+
+	// Успешную операцию возобновить нельзя
+	_, err = s.Resume(ctx, op.ID, services.TenantMigrationRequest{RequestedBy: "admin"})
+	var appErr *apperror.AppError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, apperror.ErrConflict, appErr.Code)
+}
+
+func TestTenantMigration_RejectsOtherOperations(t *testing.T) {
+	operations := NewOperationService(newMemoryOperationRepository(), logrus.New())
+	repo := &memoryTenantMigrationRepository{results: map[uuid.UUID]entity.TenantMigration{}}
+	s := NewTenantMigrationService(repo, &stubOrganizationRepository{}, operations, nil, services.TenantMigrationConfig{}, logrus.New())
+
+	op, err := operations.Start(context.Background(), services.NewOperation{Kind: services.OperationKindExport, RequestedBy: "admin"})
+	require.NoError(t, err)
+
+	_, err = s.GetSummary(context.Background(), op.ID)
+	var appErr *apperror.AppError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, apperror.ErrNotFound, appErr.Code)
+
+	_, err = s.Start(context.Background(), services.TenantMigrationRequest{RequestedBy: "admin", Concurrency: services.MaxTenantMigrationConcurrency + 1})
+	assert.Error(t, err)
+}
@@ -0,0 +1,54 @@
+package service_impl
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/dates"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+)
+
+// organizationTimezones часовые пояса из карточек организаций (кешируются сервисом организаций)
+type organizationTimezones struct {
+	orgs     services.EsfOrganizationService
+	fallback *time.Location
+	logger   *logger.Logger
+}
+
+// NewOrganizationTimezones создает источник часовых поясов; fallback — пояс организаций без
+// заданного, nil — dates.DefaultTimezone
+func NewOrganizationTimezones(orgs services.EsfOrganizationService, fallback *time.Location, log *logrus.Logger) services.Timezones {
+	if fallback == nil {
+		fallback, _ = dates.LoadLocation(dates.DefaultTimezone)
+	}
+	return &organizationTimezones{orgs: orgs, fallback: fallback, logger: logger.New(log)}
+}
+
+func (t *organizationTimezones) Location(ctx context.Context, orgID uuid.UUID) *time.Location {
+	org, err := t.orgs.GetOrganizationByID(ctx, orgID)
+	if err != nil || org == nil || org.Timezone == "" {
+		return t.fallback
+	}
+	loc, err := dates.LoadLocation(org.Timezone)
+	if err != nil {
+		t.logger.Warn(ctx, "Invalid organization time zone, using default", logrus.Fields{
+			"org_id":   orgID.String(),
+			"timezone": org.Timezone,
+		})
+		return t.fallback
+	}
+	return loc
+}
+
+// organizationToday текущая дата организации; без источника часовых поясов — в dates.DefaultTimezone
+func organizationToday(ctx context.Context, timezones services.Timezones, orgID uuid.UUID) dates.Date {
+	if timezones == nil {
+		loc, _ := dates.LoadLocation(dates.DefaultTimezone)
+		return dates.Today(loc)
+	}
+	return dates.Today(timezones.Location(ctx, orgID))
+}
@@ -0,0 +1,185 @@
+package service_impl
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/metering"
+)
+
+// usageExportHeader колонки выгрузки потребления для выставления счетов
+var usageExportHeader = []string{"organization_id", "organization_name", "period_start", "metric", "quantity"}
+
+// usageRow строка выгрузки потребления в NDJSON
+type usageRow struct {
+	OrganizationID   uuid.UUID `json:"organizationId"`
+	OrganizationName string    `json:"organizationName,omitempty"`
+	PeriodStart      time.Time `json:"periodStart"`
+	Metric           string    `json:"metric"`
+	Quantity         int64     `json:"quantity"`
+}
+
+// usageService реализует UsageService
+type usageService struct {
+	repo   repository.UsageRepository
+	orgs   repository.EsfOrganizationRepository
+	logger *logger.Logger
+}
+
+// NewUsageService создает сервис отчетов о потреблении; orgs дает названия организаций в выгрузке
+func NewUsageService(repo repository.UsageRepository, orgs repository.EsfOrganizationRepository, log *logrus.Logger) services.UsageService {
+	return &usageService{
+		repo:   repo,
+		orgs:   orgs,
+		logger: logger.New(log),
+	}
+}
+
+// Report возвращает суммы метрик организации по периодам и итоги
+func (s *usageService) Report(ctx context.Context, orgID uuid.UUID, query services.UsageQuery) (*services.UsageReport, error) {
+	if err := validateUsageQuery(&query); err != nil {
+		return nil, err
+	}
+	records, err := s.repo.ListUsage(ctx, repository.UsageFilter{OrganizationID: orgID, From: query.From, To: query.To})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &services.UsageReport{
+		OrganizationID: orgID,
+		From:           query.From,
+		To:             query.To,
+		Granularity:    query.Granularity,
+		Totals:         make(map[string]int64, len(metering.Metrics)),
+		Periods:        []services.UsagePeriod{},
+	}
+	for _, m := range metering.Metrics {
+		report.Totals[string(m)] = 0
+	}
+
+	// Записи упорядочены по часу, поэтому периоды идут по возрастанию
+	for _, rec := range records {
+		start := usagePeriodStart(rec.Hour, query.Granularity)
+		if n := len(report.Periods); n == 0 || !report.Periods[n-1].Start.Equal(start) {
+			report.Periods = append(report.Periods, services.UsagePeriod{Start: start, Metrics: map[string]int64{}})
+		}
+		report.Periods[len(report.Periods)-1].Metrics[rec.Metric] += rec.Quantity
+		report.Totals[rec.Metric] += rec.Quantity
+	}
+	return report, nil
+}
+
+// Export пишет суммы по организации, периоду и метрике
+func (s *usageService) Export(ctx context.Context, orgID uuid.UUID, query services.UsageQuery, format string, w io.Writer) error {
+	if err := validateUsageQuery(&query); err != nil {
+		return err
+	}
+	records, err := s.repo.ListUsage(ctx, repository.UsageFilter{OrganizationID: orgID, From: query.From, To: query.To})
+	if err != nil {
+		return err
+	}
+	names, err := s.organizationNames(ctx)
+	if err != nil {
+		return err
+	}
+
+	out, err := newRecordWriter(format, w, usageExportHeader)
+	if err != nil {
+		return err
+	}
+	for _, row := range aggregateUsage(records, query.Granularity) {
+		row.OrganizationName = names[row.OrganizationID]
+		err := out.Write([]string{
+			row.OrganizationID.String(),
+			row.OrganizationName,
+			row.PeriodStart.Format(time.RFC3339),
+			row.Metric,
+			strconv.FormatInt(row.Quantity, 10),
+		}, row)
+		if err != nil {
+			return err
+		}
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+
+	s.logger.Info(ctx, "Usage exported", logrus.Fields{"org_id": orgID.String(), "format": format, "records": len(records)})
+	return nil
+}
+
+// organizationNames возвращает названия организаций по ID
+func (s *usageService) organizationNames(ctx context.Context) (map[uuid.UUID]string, error) {
+	orgs, err := s.orgs.GetAll(ctx)
+	if err != nil {
+		return nil, apperror.DatabaseErrorFrom("fetching organizations", err)
+	}
+	names := make(map[uuid.UUID]string, len(orgs))
+	for _, org := range orgs {
+		names[org.ID] = org.Name
+	}
+	return names, nil
+}
+
+// aggregateUsage складывает почасовые суммы в периоды; порядок — организация, период, метрика
+func aggregateUsage(records []entity.UsageRecord, granularity string) []usageRow {
+	type rowKey struct {
+		orgID  uuid.UUID
+		start  time.Time
+		metric string
+	}
+	var rows []usageRow
+	index := make(map[rowKey]int)
+	for _, rec := range records {
+		key := rowKey{orgID: rec.OrganizationID, start: usagePeriodStart(rec.Hour, granularity), metric: rec.Metric}
+		if i, ok := index[key]; ok {
+			rows[i].Quantity += rec.Quantity
+			continue
+		}
+		index[key] = len(rows)
+		rows = append(rows, usageRow{OrganizationID: key.orgID, PeriodStart: key.start, Metric: key.metric, Quantity: rec.Quantity})
+	}
+	return rows
+}
+
+// validateUsageQuery проверяет период и подставляет шаг по умолчанию (день)
+func validateUsageQuery(query *services.UsageQuery) error {
+	if query.Granularity == "" {
+		query.Granularity = services.UsageGranularityDay
+	}
+	switch query.Granularity {
+	case services.UsageGranularityHour, services.UsageGranularityDay, services.UsageGranularityMonth:
+	default:
+		return apperror.ValidationError("granularity must be hour, day or month")
+	}
+	if !query.From.Before(query.To) {
+		return apperror.ValidationError("from must be before to")
+	}
+	if query.To.Sub(query.From) > services.MaxUsageRange {
+		return apperror.ValidationError("usage period must not exceed 366 days")
+	}
+	return nil
+}
+
+// usagePeriodStart начало часа, дня или месяца по UTC, в который попадает hour
+func usagePeriodStart(hour time.Time, granularity string) time.Time {
+	hour = hour.UTC()
+	switch granularity {
+	case services.UsageGranularityHour:
+		return hour.Truncate(time.Hour)
+	case services.UsageGranularityMonth:
+		return time.Date(hour.Year(), hour.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return time.Date(hour.Year(), hour.Month(), hour.Day(), 0, 0, 0, 0, time.UTC)
+	}
+}
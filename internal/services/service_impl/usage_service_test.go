@@ -0,0 +1,103 @@
+package service_impl
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/metering"
+)
+
+// memoryUsageRepository отдает почасовые суммы, отфильтрованные как в БД
+type memoryUsageRepository struct {
+	records []entity.UsageRecord
+}
+
+func (m *memoryUsageRepository) AddUsage(ctx context.Context, records []metering.Record) error {
+	return nil
+}
+
+func (m *memoryUsageRepository) ListUsage(ctx context.Context, filter repository.UsageFilter) ([]entity.UsageRecord, error) {
+	var out []entity.UsageRecord
+	for _, rec := range m.records {
+		if filter.OrganizationID != uuid.Nil && rec.OrganizationID != filter.OrganizationID {
+			continue
+		}
+		if rec.Hour.Before(filter.From) || !rec.Hour.Before(filter.To) {
+			continue
+		}
+		out = append(out, rec)
+	}
+	return out, nil
+}
+
+func usageHour(day, hour int) time.Time {
+	return time.Date(2026, 3, day, hour, 0, 0, 0, time.UTC)
+}
+
+func TestUsageService_ReportGroupsByDay(t *testing.T) {
+	orgID := uuid.New()
+	repo := &memoryUsageRepository{records: []entity.UsageRecord{
+		{OrganizationID: orgID, Metric: string(metering.MetricAPICalls), Hour: usageHour(1, 9), Quantity: 10},
+		{OrganizationID: orgID, Metric: string(metering.MetricDocumentsSent), Hour: usageHour(1, 9), Quantity: 1},
+		{OrganizationID: orgID, Metric: string(metering.MetricAPICalls), Hour: usageHour(1, 15), Quantity: 5},
+		{OrganizationID: orgID, Metric: string(metering.MetricAPICalls), Hour: usageHour(3, 0), Quantity: 2},
+		{OrganizationID: uuid.New(), Metric: string(metering.MetricAPICalls), Hour: usageHour(1, 9), Quantity: 100},
+	}}
+	svc := NewUsageService(repo, &stubOrganizationRepository{}, logrus.New())
+
+	report, err := svc.Report(context.Background(), orgID, services.UsageQuery{From: usageHour(1, 0), To: usageHour(4, 0)})
+	require.NoError(t, err)
+	assert.Equal(t, services.UsageGranularityDay, report.Granularity)
+	assert.Equal(t, map[string]int64{"documents_sent": 1, "storage_bytes": 0, "api_calls": 17}, report.Totals)
+	assert.Equal(t, []services.UsagePeriod{
+		{Start: usageHour(1, 0), Metrics: map[string]int64{"api_calls": 15, "documents_sent": 1}},
+		{Start: usageHour(3, 0), Metrics: map[string]int64{"api_calls": 2}},
+	}, report.Periods)
+}
+
+func TestUsageService_ReportValidatesPeriod(t *testing.T) {
+	svc := NewUsageService(&memoryUsageRepository{}, &stubOrganizationRepository{}, logrus.New())
+	ctx, orgID := context.Background(), uuid.New()
+
+	for name, query := range map[string]services.UsageQuery{
+		"reversed":    {From: usageHour(2, 0), To: usageHour(1, 0)},
+		"too long":    {From: usageHour(1, 0), To: usageHour(1, 0).AddDate(2, 0, 0)},
+		"granularity": {From: usageHour(1, 0), To: usageHour(2, 0), Granularity: "week"},
+	} {
+		_, err := svc.Report(ctx, orgID, query)
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr, name)
+		assert.Equal(t, apperror.ErrValidation, appErr.Code, name)
+	}
+}
+
+func TestUsageService_ExportCSVByMonth(t *testing.T) {
+	first, second := uuid.New(), uuid.New()
+	repo := &memoryUsageRepository{records: []entity.UsageRecord{
+		{OrganizationID: first, Metric: string(metering.MetricDocumentsSent), Hour: usageHour(1, 9), Quantity: 2},
+		{OrganizationID: first, Metric: string(metering.MetricDocumentsSent), Hour: usageHour(20, 9), Quantity: 3},
+		{OrganizationID: second, Metric: string(metering.MetricStorageBytes), Hour: usageHour(5, 12), Quantity: 1024},
+	}}
+	orgs := &stubOrganizationRepository{orgs: []*entity.EstOrganization{{ID: first, Name: "=Alpha"}, {ID: second, Name: "Beta"}}}
+	svc := NewUsageService(repo, orgs, logrus.New())
+
+	var buf bytes.Buffer
+	err := svc.Export(context.Background(), uuid.Nil, services.UsageQuery{
+		From: usageHour(1, 0), To: usageHour(31, 0), Granularity: services.UsageGranularityMonth,
+	}, services.ExportFormatCSV, &buf)
+	require.NoError(t, err)
+	assert.Equal(t, "organization_id,organization_name,period_start,metric,quantity\n"+
+		first.String()+",'=Alpha,2026-03-01T00:00:00Z,documents_sent,5\n"+
+		second.String()+",Beta,2026-03-01T00:00:00Z,storage_bytes,1024\n", buf.String())
+}
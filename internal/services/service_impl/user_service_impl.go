@@ -2,7 +2,6 @@ package service_impl
 
 import (
 	"context"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -11,6 +10,7 @@ import (
 	"github.com/rusgainew/tunduck-app/internal/repository"
 	"github.com/rusgainew/tunduck-app/internal/services"
 	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/auth"
 	"github.com/rusgainew/tunduck-app/pkg/cache"
 	"github.com/rusgainew/tunduck-app/pkg/entity"
 	"github.com/rusgainew/tunduck-app/pkg/logger"
@@ -23,6 +23,7 @@ import (
 type userService struct {
 	repo         repository.UserRepository
 	db           *gorm.DB
+	txManager    transaction.TxManager
 	logger       *logger.Logger
 	cacheManager cache.CacheManager
 	cacheHelper  *cache.CacheHelper
@@ -37,6 +38,7 @@ func NewUserService(repo repository.UserRepository, db *gorm.DB, log *logrus.Log
 	return &userService{
 		repo:         repo,
 		db:           db,
+		txManager:    transaction.NewTxManager(db, log),
 		logger:       logger.New(log),
 		cacheManager: nil, // CacheManager будет установлен позже
 		cacheHelper:  nil, // CacheHelper будет установлен позже
@@ -54,8 +56,10 @@ func (s *userService) Register(ctx context.Context, req *models.RegisterRequest)
 
 	var response *models.AuthResponse
 
-	// Используем транзакцию для регистрации
-	err := transaction.Execute(ctx, s.db, &logrus.Logger{}, func(txDB *gorm.DB) error {
+	// Используем транзакцию для регистрации: репозитории внутри WithinTx работают в ней же
+	err := s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		txDB := transaction.FromContext(ctx, s.db)
+
 		// Проверяем, существует ли пользователь с таким username
 		var existingUserCount int64
 		if err := txDB.Model(&entity.User{}).Where("username = ?", req.Username).Count(&existingUserCount).Error; err != nil {
@@ -96,9 +100,9 @@ func (s *userService) Register(ctx context.Context, req *models.RegisterRequest)
 			IsActive: true,
 		}
 
-		if err := txDB.Create(user).Error; err != nil {
+		if err := s.repo.Create(ctx, user); err != nil {
 			s.logger.Error(ctx, "Failed to create user", err, logrus.Fields{"user_id": user.ID})
-			return apperror.DatabaseError("creating user", err)
+			return err
 		}
 
 		s.logger.Info(ctx, "User registered successfully", logrus.Fields{"user_id": user.ID, "username": user.Username})
@@ -204,17 +208,12 @@ func (s *userService) Login(ctx context.Context, req *models.LoginRequest) (*mod
 }
 
 func (s *userService) ValidateToken(tokenString string) (*models.UserInfo, error) {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		return nil, apperror.New(apperror.ErrConfigError, "JWT_SECRET is not configured")
+	keys, err := auth.KeyRingFromEnv()
+	if err != nil {
+		return nil, apperror.New(apperror.ErrConfigError, "JWT signing keys are not configured").WithError(err)
 	}
 
-	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, apperror.New(apperror.ErrInvalidToken, "invalid token signing method")
-		}
-		return []byte(secret), nil
-	})
+	token, err := keys.Parse(tokenString, jwt.MapClaims{})
 
 	if err != nil {
 		return nil, apperror.New(apperror.ErrInvalidToken, "failed to parse token").WithError(err)
@@ -243,10 +242,10 @@ func (s *userService) ValidateToken(tokenString string) (*models.UserInfo, error
 }
 
 func (s *userService) generateToken(ctx context.Context, user *entity.User) (string, error) {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		s.logger.Error(ctx, "JWT_SECRET is not configured", nil)
-		return "", apperror.New(apperror.ErrConfigError, "JWT_SECRET is not configured")
+	keys, err := auth.KeyRingFromEnv()
+	if err != nil {
+		s.logger.Error(ctx, "JWT signing keys are not configured", err)
+		return "", apperror.New(apperror.ErrConfigError, "JWT signing keys are not configured").WithError(err)
 	}
 
 	claims := jwt.MapClaims{
@@ -258,8 +257,8 @@ func (s *userService) generateToken(ctx context.Context, user *entity.User) (str
 		"iat":       time.Now().Unix(),
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenString, err := token.SignedString([]byte(secret))
+	// Подписываем текущим по расписанию ключом; kid в заголовке выбирает ключ при проверке
+	tokenString, err := keys.Sign(claims)
 	if err != nil {
 		s.logger.Error(ctx, "Failed to sign token", err, logrus.Fields{"user_id": user.ID})
 		return "", apperror.New(apperror.ErrInternal, "failed to generate token").WithError(err)
@@ -326,6 +325,44 @@ func (s *userService) GetByEmail(ctx context.Context, email string) (*entity.Use
 }
 
 // CacheWarmUsers предварительно загружает пользователей в кеш
+// RestoreUser восстанавливает мягко удаленного пользователя
+func (s *userService) RestoreUser(ctx context.Context, id uuid.UUID) error {
+	s.logger.Info(ctx, "Restoring user", logrus.Fields{"user_id": id.String()})
+
+	if err := s.repo.Restore(ctx, id); err != nil {
+		s.logger.Error(ctx, "Failed to restore user", err, logrus.Fields{"user_id": id.String()})
+		return err
+	}
+
+	s.invalidateUsersCache(ctx)
+	s.logger.Info(ctx, "User restored successfully", logrus.Fields{"user_id": id.String()})
+	return nil
+}
+
+// PurgeUser окончательно удаляет мягко удаленного пользователя
+func (s *userService) PurgeUser(ctx context.Context, id uuid.UUID) error {
+	s.logger.Info(ctx, "Purging user", logrus.Fields{"user_id": id.String()})
+
+	if err := s.repo.Purge(ctx, id); err != nil {
+		s.logger.Error(ctx, "Failed to purge user", err, logrus.Fields{"user_id": id.String()})
+		return err
+	}
+
+	s.invalidateUsersCache(ctx)
+	s.logger.Info(ctx, "User purged successfully", logrus.Fields{"user_id": id.String()})
+	return nil
+}
+
+// invalidateUsersCache очищает кеш пользователей: записи кешируются по id, username и email
+func (s *userService) invalidateUsersCache(ctx context.Context) {
+	if s.cacheHelper == nil {
+		return
+	}
+	if err := s.cacheHelper.InvalidateAllUsersCache(ctx); err != nil {
+		s.logger.Warn(ctx, "Failed to invalidate users cache", logrus.Fields{"error": err.Error()})
+	}
+}
+
 func (s *userService) CacheWarmUsers(ctx context.Context, limit int) error {
 	if s.cacheManager == nil {
 		return nil // Кеш не настроен, пропускаем
@@ -7,40 +7,18 @@ import (
 	"github.com/google/uuid"
 	"github.com/rusgainew/tunduck-app/internal/models"
 	repositorypostgres "github.com/rusgainew/tunduck-app/internal/repository/repository_postgres"
+	"github.com/rusgainew/tunduck-app/pkg/testutil"
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
 // Integration test для UserService с реальной БД
-// Примечание: Требует запущенного PostgreSQL для полноценного тестирования
-// Используйте testcontainers-go для автоматического управления контейнерами в CI/CD
+// Примечание: Требует запущенного PostgreSQL (DSN в TEST_DATABASE_DSN), иначе тесты пропускаются
 
 func setupTestDB(t *testing.T) *gorm.DB {
-	// TODO: Использовать testcontainers-go для создания контейнера PostgreSQL
-	// Пример конфигурации:
-	// req := testcontainers.ContainerRequest{
-	//     Image:        "postgres:15",
-	//     ExposedPorts: []string{"5432/tcp"},
-	//     Env: map[string]string{
-	//         "POSTGRES_USER":     "test",
-	//         "POSTGRES_PASSWORD": "test",
-	//         "POSTGRES_DB":       "testdb",
-	//     },
-	// }
-
-	// На данный момент, создаем in-memory database (SQLite)
-	// или используем тестовую БД если она настроена
-	dsn := "user=postgres password=postgres dbname=tunduct_test host=localhost port=5432 sslmode=disable"
-
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
-	if err != nil {
-		t.Skipf("Could not connect to test database: %v. Skipping integration tests.", err)
-	}
-
-	return db
+	return testutil.Postgres(t)
 }
 
 // TestUserServiceRegisterIntegration тестирует регистрацию пользователя
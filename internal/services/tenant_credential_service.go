@@ -0,0 +1,29 @@
+package services
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// TenantCredentialConfig параметры ротации учетных данных БД организаций
+type TenantCredentialConfig struct {
+	// Queue очередь фоновых задач ротации
+	Queue string
+}
+
+// TenantCredentialRotationRequest ротация учетных данных выбранных организаций; пустой список — всех
+type TenantCredentialRotationRequest struct {
+	RequestedBy     string      `json:"-"`
+	OrganizationIDs []uuid.UUID `json:"organizationIds"`
+}
+
+// TenantCredentialService выдает БД каждой организации собственную роль и меняет ее пароль без
+// простоя. У организации две роли, которые чередуются: новый пароль задается роли, не используемой
+// сейчас, поэтому открытые подключения и пулы других экземпляров продолжают работать до следующей ротации.
+type TenantCredentialService interface {
+	// Rotate создает операцию tenant_credential_rotation и ставит задачу ротации
+	Rotate(ctx context.Context, req TenantCredentialRotationRequest) (*entity.Operation, error)
+}
@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// Число БД организаций, мигрируемых одновременно
+const (
+	DefaultTenantMigrationConcurrency = 4
+	MaxTenantMigrationConcurrency     = 16
+)
+
+// TenantMigrationConfig параметры миграции БД организаций
+type TenantMigrationConfig struct {
+	// Concurrency число БД, мигрируемых одновременно, если в запросе не задано другое
+	Concurrency int
+	// Queue очередь фоновых задач миграции
+	Queue string
+}
+
+// TenantMigrationRequest запуск миграции БД всех организаций
+type TenantMigrationRequest struct {
+	RequestedBy string
+	// Concurrency число БД, мигрируемых одновременно; 0 — значение из конфигурации
+	Concurrency int
+}
+
+// TenantMigrationResult результат миграции БД одной организации
+type TenantMigrationResult struct {
+	OrganizationID uuid.UUID `json:"organizationId"`
+	Database       string    `json:"database"`
+	State          string    `json:"state"`
+	Applied        []string  `json:"applied"`
+	Error          string    `json:"error,omitempty"`
+	FinishedAt     time.Time `json:"finishedAt"`
+}
+
+// TenantMigrationSummary итог операции миграции: общее состояние и результат каждой БД
+type TenantMigrationSummary struct {
+	Operation *OperationStatus        `json:"operation"`
+	Succeeded int                     `json:"succeeded"`
+	Failed    int                     `json:"failed"`
+	Remaining int64                   `json:"remaining"`
+	Tenants   []TenantMigrationResult `json:"tenants"`
+}
+
+// TenantMigrationService применяет неприменные миграции к БД всех организаций фоновой задачей.
+// Операция завершается с ошибкой, если не удалось мигрировать хотя бы одну БД; возобновленная
+// операция пропускает уже мигрированные БД.
+type TenantMigrationService interface {
+	// Start создает операцию tenant_migration и ставит задачу миграции
+	Start(ctx context.Context, req TenantMigrationRequest) (*entity.Operation, error)
+	// Resume повторяет завершенную с ошибкой операцию только для БД, которые не удалось мигрировать
+	Resume(ctx context.Context, operationID uuid.UUID, req TenantMigrationRequest) (*entity.Operation, error)
+	// GetSummary возвращает состояние операции и результаты по организациям
+	GetSummary(ctx context.Context, operationID uuid.UUID) (*TenantMigrationSummary, error)
+}
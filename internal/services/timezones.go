@@ -0,0 +1,16 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Timezones часовые пояса организаций. По часовому поясу организации определяется текущая дата
+// при проверке дат документов и сроков договоров.
+type Timezones interface {
+	// Location возвращает часовой пояс организации; если он не задан или организация не найдена —
+	// часовой пояс по умолчанию
+	Location(ctx context.Context, orgID uuid.UUID) *time.Location
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Шаг периодов отчета о потреблении
+const (
+	UsageGranularityHour  = "hour"
+	UsageGranularityDay   = "day"
+	UsageGranularityMonth = "month"
+)
+
+// MaxUsageRange наибольший период отчета о потреблении
+const MaxUsageRange = 366 * 24 * time.Hour
+
+// UsageQuery период отчета: часы в [From, To) по UTC, сгруппированные по Granularity
+type UsageQuery struct {
+	From        time.Time
+	To          time.Time
+	Granularity string
+}
+
+// UsagePeriod суммы метрик за период, начинающийся в Start
+type UsagePeriod struct {
+	Start   time.Time        `json:"start"`
+	Metrics map[string]int64 `json:"metrics"`
+}
+
+// UsageReport потребление организации: итоги за весь период и суммы по периодам
+type UsageReport struct {
+	OrganizationID uuid.UUID        `json:"organizationId"`
+	From           time.Time        `json:"from"`
+	To             time.Time        `json:"to"`
+	Granularity    string           `json:"granularity"`
+	Totals         map[string]int64 `json:"totals"`
+	Periods        []UsagePeriod    `json:"periods"`
+}
+
+// UsageService отчеты о тарифицируемом потреблении организаций (pkg/metering)
+type UsageService interface {
+	// Report возвращает потребление организации; периоды без потребления не выводятся
+	Report(ctx context.Context, orgID uuid.UUID, query UsageQuery) (*UsageReport, error)
+	// Export пишет выгрузку для выставления счетов (ExportFormatCSV или ExportFormatNDJSON):
+	// организация, начало периода, метрика, количество. orgID == uuid.Nil — все организации
+	Export(ctx context.Context, orgID uuid.UUID, query UsageQuery, format string, w io.Writer) error
+}
@@ -3,6 +3,8 @@ package services
 import (
 	"context"
 
+	"github.com/google/uuid"
+
 	"github.com/rusgainew/tunduck-app/internal/models"
 	"github.com/rusgainew/tunduck-app/pkg/cache"
 	"github.com/rusgainew/tunduck-app/pkg/entity"
@@ -15,6 +17,8 @@ type UserService interface {
 	ValidateToken(token string) (*models.UserInfo, error)
 	GetByUsername(ctx context.Context, username string) (*entity.User, error)
 	GetByEmail(ctx context.Context, email string) (*entity.User, error)
+	RestoreUser(ctx context.Context, id uuid.UUID) error
+	PurgeUser(ctx context.Context, id uuid.UUID) error
 	CacheWarmUsers(ctx context.Context, limit int) error
 	SetCacheManager(cacheManager cache.CacheManager)
 }
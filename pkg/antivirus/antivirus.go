@@ -0,0 +1,48 @@
+// Package antivirus проверяет загружаемые пользователями файлы антивирусом ClamAV (clamd).
+//
+// Guard оборачивает storage.Storage: перед сохранением файл проверяется, зараженный файл
+// перемещается в карантин (префикс quarantine/) и не попадает по исходному ключу,
+// а загрузка завершается ошибкой FILE_INFECTED.
+package antivirus
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+// Параметры по умолчанию
+const (
+	DefaultTimeout          = 30 * time.Second
+	DefaultQuarantinePrefix = "quarantine/"
+)
+
+// ErrUnavailable возвращается, если антивирус недоступен или не смог проверить файл
+var ErrUnavailable = errors.New("antivirus unavailable")
+
+// Result результат проверки
+type Result struct {
+	Infected bool
+	// Signature название найденной сигнатуры
+	Signature string
+}
+
+// Scanner проверяет поток данных
+type Scanner interface {
+	Scan(ctx context.Context, r io.Reader) (Result, error)
+}
+
+// Config параметры подключения к clamd
+type Config struct {
+	// Address адрес clamd: "host:3310", "tcp://host:3310" или "unix:///run/clamav/clamd.ctl"
+	Address string
+	Timeout time.Duration
+	// QuarantinePrefix префикс ключей зараженных файлов в хранилище
+	QuarantinePrefix string
+}
+
+// Enabled сообщает, настроен ли clamd
+func (c Config) Enabled() bool {
+	return c.Address != ""
+}
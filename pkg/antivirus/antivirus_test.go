@@ -0,0 +1,148 @@
+package antivirus
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/storage"
+)
+
+const eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// fakeClamd отвечает на PING и INSTREAM, находя в потоке тестовую сигнатуру EICAR
+func fakeClamd(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go serveClamd(conn)
+		}
+	}()
+	return ln.Addr().String()
+}
+
+func serveClamd(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+
+	cmd, err := r.ReadString(0)
+	if err != nil {
+		return
+	}
+	switch cmd {
+	case "zPING\x00":
+		conn.Write([]byte("PONG\x00"))
+	case "zINSTREAM\x00":
+		var data bytes.Buffer
+		for {
+			var size uint32
+			if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+				return
+			}
+			if size == 0 {
+				break
+			}
+			if _, err := io.CopyN(&data, r, int64(size)); err != nil {
+				return
+			}
+		}
+		if strings.Contains(data.String(), "EICAR-STANDARD-ANTIVIRUS-TEST-FILE") {
+			conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+			return
+		}
+		conn.Write([]byte("stream: OK\x00"))
+	}
+}
+
+func TestClamdScanner(t *testing.T) {
+	scanner := NewClamdScanner(Config{Address: "tcp://" + fakeClamd(t)})
+	ctx := context.Background()
+
+	require.NoError(t, scanner.Ping(ctx))
+
+	result, err := scanner.Scan(ctx, strings.NewReader(strings.Repeat("clean data ", 20000)))
+	require.NoError(t, err)
+	assert.False(t, result.Infected)
+
+	result, err = scanner.Scan(ctx, strings.NewReader(eicar))
+	require.NoError(t, err)
+	assert.True(t, result.Infected)
+	assert.Equal(t, "Eicar-Test-Signature", result.Signature)
+}
+
+func TestClamdScanner_Unavailable(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := ln.Addr().String()
+	ln.Close()
+
+	_, err = NewClamdScanner(Config{Address: addr}).Scan(context.Background(), strings.NewReader("x"))
+	assert.ErrorIs(t, err, ErrUnavailable)
+}
+
+func TestParseReply(t *testing.T) {
+	_, err := parseReply("INSTREAM size limit exceeded. ERROR")
+	assert.ErrorIs(t, err, ErrUnavailable)
+}
+
+func TestGuard(t *testing.T) {
+	store, err := storage.NewLocal(t.TempDir())
+	require.NoError(t, err)
+	guard := NewGuard(store, NewClamdScanner(Config{Address: fakeClamd(t)}), Config{}, logrus.New())
+	ctx := context.Background()
+
+	require.NoError(t, guard.Put(ctx, "attachments/invoice.pdf", strings.NewReader("%PDF-1.7"), -1, storage.PutOptions{}))
+	info, err := store.Stat(ctx, "attachments/invoice.pdf")
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("%PDF-1.7")), info.Size)
+
+	err = guard.Put(ctx, "attachments/virus.com", strings.NewReader(eicar), int64(len(eicar)), storage.PutOptions{})
+	var appErr *apperror.AppError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, apperror.ErrFileInfected, appErr.Code)
+	assert.Equal(t, "Eicar-Test-Signature", appErr.Params["signature"])
+
+	_, err = store.Stat(ctx, "attachments/virus.com")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = store.Stat(ctx, DefaultQuarantinePrefix+"attachments/virus.com")
+	assert.NoError(t, err, "infected file is quarantined")
+}
+
+// failingScanner имитирует недоступный clamd
+type failingScanner struct{}
+
+func (failingScanner) Scan(ctx context.Context, r io.Reader) (Result, error) {
+	return Result{}, ErrUnavailable
+}
+
+func TestGuard_FailsClosed(t *testing.T) {
+	store, err := storage.NewLocal(t.TempDir())
+	require.NoError(t, err)
+	guard := NewGuard(store, failingScanner{}, Config{}, logrus.New())
+
+	err = guard.Put(context.Background(), "attachments/a.txt", strings.NewReader("x"), 1, storage.PutOptions{})
+	var appErr *apperror.AppError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, apperror.ErrExternalService, appErr.Code)
+
+	_, err = store.Stat(context.Background(), "attachments/a.txt")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
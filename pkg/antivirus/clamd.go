@@ -0,0 +1,134 @@
+package antivirus
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// chunkSize размер блока команды INSTREAM
+const chunkSize = 64 * 1024
+
+// ClamdScanner проверяет файлы через clamd по протоколу INSTREAM
+type ClamdScanner struct {
+	network string
+	address string
+	timeout time.Duration
+}
+
+// NewClamdScanner создает клиент clamd
+func NewClamdScanner(cfg Config) *ClamdScanner {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+
+	network, address := "tcp", cfg.Address
+	switch {
+	case strings.HasPrefix(address, "unix://"):
+		network, address = "unix", strings.TrimPrefix(address, "unix://")
+	case strings.HasPrefix(address, "tcp://"):
+		address = strings.TrimPrefix(address, "tcp://")
+	}
+
+	return &ClamdScanner{network: network, address: address, timeout: cfg.Timeout}
+}
+
+// Ping проверяет, что clamd отвечает
+func (s *ClamdScanner) Ping(ctx context.Context) error {
+	reply, err := s.command(ctx, func(conn net.Conn) error {
+		_, err := conn.Write([]byte("zPING\x00"))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if reply != "PONG" {
+		return fmt.Errorf("%w: unexpected PING reply %q", ErrUnavailable, reply)
+	}
+	return nil
+}
+
+// Scan передает поток в clamd блоками и разбирает ответ "stream: OK" или "stream: <сигнатура> FOUND"
+func (s *ClamdScanner) Scan(ctx context.Context, r io.Reader) (Result, error) {
+	reply, err := s.command(ctx, func(conn net.Conn) error {
+		if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+			return err
+		}
+
+		buf := make([]byte, chunkSize)
+		var size [4]byte
+		for {
+			n, readErr := r.Read(buf)
+			if n > 0 {
+				binary.BigEndian.PutUint32(size[:], uint32(n))
+				if _, err := conn.Write(size[:]); err != nil {
+					return err
+				}
+				if _, err := conn.Write(buf[:n]); err != nil {
+					return err
+				}
+			}
+			if readErr == io.EOF {
+				break
+			}
+			if readErr != nil {
+				return readErr
+			}
+		}
+
+		binary.BigEndian.PutUint32(size[:], 0)
+		_, err := conn.Write(size[:])
+		return err
+	})
+	if err != nil {
+		return Result{}, err
+	}
+
+	return parseReply(reply)
+}
+
+// command выполняет одну команду clamd и возвращает ответ без завершающего нуля
+func (s *ClamdScanner) command(ctx context.Context, send func(net.Conn) error) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, s.network, s.address)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if err := send(conn); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadBytes(0)
+	if err != nil && len(reply) == 0 {
+		return "", fmt.Errorf("%w: reading reply: %v", ErrUnavailable, err)
+	}
+	return string(bytes.TrimRight(reply, "\x00\n")), nil
+}
+
+func parseReply(reply string) (Result, error) {
+	body := strings.TrimPrefix(reply, "stream: ")
+	switch {
+	case body == "OK":
+		return Result{}, nil
+	case strings.HasSuffix(body, " FOUND"):
+		return Result{Infected: true, Signature: strings.TrimSuffix(body, " FOUND")}, nil
+	default:
+		// Например "INSTREAM size limit exceeded. ERROR": файл не проверен
+		return Result{}, fmt.Errorf("%w: %s", ErrUnavailable, reply)
+	}
+}
@@ -0,0 +1,79 @@
+package antivirus
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/storage"
+)
+
+// Guard хранилище, проверяющее каждый загружаемый файл антивирусом.
+// Чтение, удаление и список делегируются исходному хранилищу.
+type Guard struct {
+	storage.Storage
+	scanner    Scanner
+	quarantine string
+	logger     *logger.Logger
+}
+
+// NewGuard оборачивает хранилище проверкой загрузок
+func NewGuard(store storage.Storage, scanner Scanner, cfg Config, log *logrus.Logger) *Guard {
+	if cfg.QuarantinePrefix == "" {
+		cfg.QuarantinePrefix = DefaultQuarantinePrefix
+	}
+	return &Guard{
+		Storage:    store,
+		scanner:    scanner,
+		quarantine: cfg.QuarantinePrefix,
+		logger:     logger.New(log),
+	}
+}
+
+// Put проверяет файл и сохраняет его по ключу. Поток один раз передается антивирусу
+// и одновременно пишется во временный файл, из которого затем загружается.
+// Зараженный файл сохраняется в карантин, а вызывающий получает ошибку FILE_INFECTED.
+// Если антивирус недоступен, файл не сохраняется.
+func (g *Guard) Put(ctx context.Context, key string, r io.Reader, size int64, opts storage.PutOptions) error {
+	tmp, err := os.CreateTemp("", "antivirus-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	result, err := g.scanner.Scan(ctx, io.TeeReader(r, tmp))
+	if err != nil {
+		g.logger.Error(ctx, "Antivirus scan failed", err, logrus.Fields{"key": key})
+		return apperror.From(err, apperror.ErrExternalService, "antivirus scan failed")
+	}
+
+	written, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	if !result.Infected {
+		return g.Storage.Put(ctx, key, tmp, written, opts)
+	}
+
+	quarantineKey := g.quarantine + key
+	g.logger.Warn(ctx, "Infected file quarantined", logrus.Fields{
+		"key":            key,
+		"quarantine_key": quarantineKey,
+		"signature":      result.Signature,
+	})
+	if err := g.Storage.Put(ctx, quarantineKey, tmp, written, opts); err != nil {
+		g.logger.Error(ctx, "Failed to quarantine infected file", err, logrus.Fields{"key": key})
+	}
+
+	return apperror.New(apperror.ErrFileInfected, "file is infected: {signature}").
+		WithParams(map[string]interface{}{"signature": result.Signature})
+}
@@ -1,8 +1,11 @@
 package apperror
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+
+	"github.com/rusgainew/tunduck-app/pkg/i18n"
 )
 
 // ErrorCode определяет тип ошибки приложения
@@ -29,6 +32,10 @@ const (
 	ErrAlreadyExists ErrorCode = "ALREADY_EXISTS"
 	ErrConflict      ErrorCode = "CONFLICT"
 
+	// Optimistic locking errors
+	ErrVersionConflict      ErrorCode = "VERSION_CONFLICT"
+	ErrPreconditionRequired ErrorCode = "PRECONDITION_REQUIRED"
+
 	// User errors
 	ErrUserNotFound     ErrorCode = "USER_NOT_FOUND"
 	ErrUserExists       ErrorCode = "USER_ALREADY_EXISTS"
@@ -45,6 +52,9 @@ const (
 	ErrOrgNotFound ErrorCode = "ORGANIZATION_NOT_FOUND"
 	ErrOrgExists   ErrorCode = "ORGANIZATION_ALREADY_EXISTS"
 
+	// File errors
+	ErrFileInfected ErrorCode = "FILE_INFECTED"
+
 	// Database errors
 	ErrDatabase        ErrorCode = "DATABASE_ERROR"
 	ErrDatabaseTimeout ErrorCode = "DATABASE_TIMEOUT"
@@ -52,9 +62,22 @@ const (
 	// External service errors
 	ErrExternalService ErrorCode = "EXTERNAL_SERVICE_ERROR"
 
+	// Rate limiting errors
+	ErrRateLimitExceeded ErrorCode = "RATE_LIMIT_EXCEEDED"
+
+	// Plan errors
+	ErrQuotaExceeded ErrorCode = "QUOTA_EXCEEDED"
+
+	// Transport errors (ошибки фреймворка с собственным HTTP статусом)
+	ErrHTTP ErrorCode = "HTTP_ERROR"
+
 	// Server errors
 	ErrInternal    ErrorCode = "INTERNAL_SERVER_ERROR"
 	ErrConfigError ErrorCode = "CONFIG_ERROR"
+	// ErrReadOnlyMode основная БД недоступна, изменения временно отклоняются
+	ErrReadOnlyMode ErrorCode = "READ_ONLY_MODE"
+	// ErrFaultInjected ошибка, внедренная для проверки клиентов (FAULT_INJECTION_RULES); статус задается правилом
+	ErrFaultInjected ErrorCode = "FAULT_INJECTED"
 )
 
 // AppError представляет структурированную ошибку приложения
@@ -65,14 +88,29 @@ type AppError struct {
 	HTTPStatus int       `json:"-"`
 	Err        error     `json:"-"` // Оригинальная ошибка для логирования
 	StackTrace string    `json:"-"`
+	// Params параметры для подстановки в сообщение вида "invalid role: {role}"
+	Params map[string]interface{} `json:"-"`
+	// Fields ошибки валидации отдельных полей запроса
+	Fields []FieldError `json:"fields,omitempty"`
+	// Data машиночитаемые подробности ошибки, например лимит тарифа и подсказка о его повышении
+	Data interface{} `json:"data,omitempty"`
+}
+
+// FieldError описывает ошибку валидации одного поля запроса
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
 }
 
 // Error реализует интерфейс error
 func (e *AppError) Error() string {
+	message := i18n.Interpolate(e.Message, e.Params)
 	if e.Details != "" {
-		return fmt.Sprintf("[%s] %s: %s", e.Code, e.Message, e.Details)
+		return fmt.Sprintf("[%s] %s: %s", e.Code, message, e.Details)
 	}
-	return fmt.Sprintf("[%s] %s", e.Code, e.Message)
+	return fmt.Sprintf("[%s] %s", e.Code, message)
 }
 
 // New создает новую ошибку приложения
@@ -94,6 +132,16 @@ func NewWithDetails(code ErrorCode, message, details string) *AppError {
 	}
 }
 
+// From приводит произвольную ошибку к AppError.
+// Если err уже содержит AppError - возвращает его, иначе создает новую ошибку с указанным кодом.
+func From(err error, code ErrorCode, message string) *AppError {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return New(code, message).WithError(err)
+}
+
 // WithError обертывает оригинальную ошибку
 func (e *AppError) WithError(err error) *AppError {
 	e.Err = err
@@ -106,6 +154,23 @@ func (e *AppError) WithDetails(details string) *AppError {
 	return e
 }
 
+// WithParams добавляет параметры для подстановки в сообщение
+func (e *AppError) WithParams(params map[string]interface{}) *AppError {
+	if e.Params == nil {
+		e.Params = make(map[string]interface{}, len(params))
+	}
+	for k, v := range params {
+		e.Params[k] = v
+	}
+	return e
+}
+
+// WithData добавляет машиночитаемые подробности ошибки
+func (e *AppError) WithData(data interface{}) *AppError {
+	e.Data = data
+	return e
+}
+
 // WithHTTPStatus устанавливает HTTP статус
 func (e *AppError) WithHTTPStatus(status int) *AppError {
 	e.HTTPStatus = status
@@ -116,10 +181,14 @@ func (e *AppError) WithHTTPStatus(status int) *AppError {
 func getHTTPStatus(code ErrorCode) int {
 	switch code {
 	// 400 Bad Request
-	case ErrValidation, ErrInvalidRequest, ErrFieldValidation,
+	case ErrValidation, ErrInvalidRequest,
 		ErrPasswordMismatch, ErrInvalidDocument:
 		return http.StatusBadRequest
 
+	// 422 Unprocessable Entity
+	case ErrFieldValidation, ErrFileInfected:
+		return http.StatusUnprocessableEntity
+
 	// 401 Unauthorized
 	case ErrUnauthorized, ErrInvalidToken, ErrExpiredToken, ErrInvalidCredentials:
 		return http.StatusUnauthorized
@@ -132,11 +201,27 @@ func getHTTPStatus(code ErrorCode) int {
 	case ErrNotFound, ErrUserNotFound, ErrDocumentNotFound, ErrOrgNotFound:
 		return http.StatusNotFound
 
+	// 402 Payment Required
+	case ErrQuotaExceeded:
+		return http.StatusPaymentRequired
+
+	// 429 Too Many Requests
+	case ErrRateLimitExceeded:
+		return http.StatusTooManyRequests
+
 	// 409 Conflict
 	case ErrAlreadyExists, ErrConflict, ErrUserExists, ErrEmailExists,
-		ErrUsernameExists, ErrOrgExists, ErrAccountBlocked:
+		ErrUsernameExists, ErrOrgExists, ErrAccountBlocked, ErrVersionConflict:
 		return http.StatusConflict
 
+	// 428 Precondition Required
+	case ErrPreconditionRequired:
+		return http.StatusPreconditionRequired
+
+	// 503 Service Unavailable
+	case ErrReadOnlyMode, ErrFaultInjected:
+		return http.StatusServiceUnavailable
+
 	// 500 Internal Server Error
 	case ErrDatabase, ErrDatabaseTimeout, ErrExternalService,
 		ErrInternal, ErrConfigError:
@@ -158,20 +243,58 @@ func (e *AppError) Is(target error) bool {
 	return false
 }
 
+// Unwrap возвращает оригинальную ошибку для errors.Is/errors.As
+func (e *AppError) Unwrap() error {
+	return e.Err
+}
+
 // ErrorResponse структура для отправки ошибки в HTTP ответе
 type ErrorResponse struct {
-	Code    string `json:"code"`
-	Message string `json:"message"`
-	Details string `json:"details,omitempty"`
+	Code    string       `json:"code"`
+	Message string       `json:"message"`
+	Details string       `json:"details,omitempty"`
+	Fields  []FieldError `json:"fields,omitempty"`
+	Data    interface{}  `json:"data,omitempty"`
 }
 
 // ToResponse преобразует AppError в ErrorResponse
 func (e *AppError) ToResponse() *ErrorResponse {
 	return &ErrorResponse{
 		Code:    string(e.Code),
-		Message: e.Message,
+		Message: i18n.Interpolate(e.Message, e.Params),
+		Details: e.Details,
+		Fields:  e.localizeFields(i18n.SourceLanguage),
+		Data:    e.Data,
+	}
+}
+
+// ToLocalizedResponse преобразует AppError в ErrorResponse с сообщением на указанном языке.
+// Код ошибки не переводится и остается стабильным для клиентов.
+func (e *AppError) ToLocalizedResponse(lang i18n.Language) *ErrorResponse {
+	return &ErrorResponse{
+		Code:    string(e.Code),
+		Message: i18n.Localize(lang, string(e.Code), e.Message, e.Params),
 		Details: e.Details,
+		Fields:  e.localizeFields(lang),
+		Data:    e.Data,
+	}
+}
+
+// localizeFields переводит сообщения ошибок полей и подставляет имя поля и параметр правила
+func (e *AppError) localizeFields(lang i18n.Language) []FieldError {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+
+	fields := make([]FieldError, len(e.Fields))
+	for i, f := range e.Fields {
+		fields[i] = f
+		fields[i].Message = i18n.Localize(lang, "", f.Message, map[string]interface{}{
+			"field": f.Field,
+			"param": f.Param,
+		})
 	}
+	return fields
 }
 
 // Common error constructors for convenience
@@ -180,6 +303,13 @@ func ValidationError(message string) *AppError {
 	return New(ErrValidation, message)
 }
 
+// ValidationFailed создает ошибку 422 со списком ошибок по полям
+func ValidationFailed(fields []FieldError) *AppError {
+	appErr := New(ErrFieldValidation, "Request validation failed")
+	appErr.Fields = fields
+	return appErr
+}
+
 func UnauthorizedError(message string) *AppError {
 	return New(ErrUnauthorized, message)
 }
@@ -192,6 +322,16 @@ func NotFoundError(resource string) *AppError {
 	return New(ErrNotFound, fmt.Sprintf("%s not found", resource))
 }
 
+// DatabaseErrorFrom возвращает AppError из цепочки err, иначе оборачивает err как ошибку БД.
+// Сохраняет коды вроде VERSION_CONFLICT, которые возвращают нижние слои.
+func DatabaseErrorFrom(operation string, err error) *AppError {
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return DatabaseError(operation, err)
+}
+
 func ConflictError(message string) *AppError {
 	return New(ErrConflict, message)
 }
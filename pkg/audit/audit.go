@@ -0,0 +1,181 @@
+// Package audit собирает журнал изменяющих вызовов API.
+//
+// AuditMiddleware создает Entry для каждого POST/PUT/PATCH/DELETE запроса и после обработки
+// сохраняет его через Recorder вместе с автором, организацией, IP и request ID.
+// Сервисы дополняют запись состоянием сущности до и после изменения:
+//
+//	audit.FromContext(ctx).SetChange(before, after)
+//
+// Методы Entry безопасно вызывать на nil (вне HTTP-запроса журнал не ведется).
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync"
+
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// LocalsKey ключ записи в fiber.Ctx.Locals; сервисы получают ее из ctx.Context() запроса
+const LocalsKey = "audit"
+
+// Recorder сохраняет записи журнала
+type Recorder interface {
+	Record(ctx context.Context, log *entity.AuditLog) error
+}
+
+// Entry данные о сущности, которые сервис добавляет к записи текущего запроса
+type Entry struct {
+	mu             sync.Mutex
+	entityType     string
+	entityID       string
+	organizationID string
+	action         string
+	before         interface{}
+	after          interface{}
+}
+
+// NewEntry создает пустую запись
+func NewEntry() *Entry {
+	return &Entry{}
+}
+
+// FromContext возвращает запись текущего запроса или nil
+func FromContext(ctx context.Context) *Entry {
+	if ctx == nil {
+		return nil
+	}
+	entry, _ := ctx.Value(LocalsKey).(*Entry)
+	return entry
+}
+
+// SetEntity уточняет тип и идентификатор сущности (по умолчанию берутся из маршрута)
+func (e *Entry) SetEntity(entityType, entityID string) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.entityType, e.entityID = entityType, entityID
+}
+
+// SetOrganization задает организацию, к которой относится изменение
+func (e *Entry) SetOrganization(orgID string) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.organizationID = orgID
+}
+
+// SetAction переопределяет действие, выведенное из HTTP-метода (например "restore")
+func (e *Entry) SetAction(action string) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.action = action
+}
+
+// SetChange запоминает состояние сущности до и после изменения; nil — сущности не было или ее удалили.
+// Значения сериализуются в JSON, поэтому секреты должны быть исключены тегами json:"-".
+func (e *Entry) SetChange(before, after interface{}) {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.before, e.after = before, after
+}
+
+// Apply переносит данные записи в журнал, не затирая уже заполненные поля пустыми значениями
+func (e *Entry) Apply(log *entity.AuditLog) error {
+	if e == nil {
+		return nil
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.entityType != "" {
+		log.EntityType = e.entityType
+	}
+	if e.entityID != "" {
+		log.EntityID = e.entityID
+	}
+	if e.organizationID != "" {
+		log.OrganizationID = e.organizationID
+	}
+	if e.action != "" {
+		log.Action = e.action
+	}
+	if e.before == nil && e.after == nil {
+		return nil
+	}
+
+	before, err := toMap(e.before)
+	if err != nil {
+		return err
+	}
+	after, err := toMap(e.after)
+	if err != nil {
+		return err
+	}
+	if log.Before, err = marshalNonNil(before); err != nil {
+		return err
+	}
+	if log.After, err = marshalNonNil(after); err != nil {
+		return err
+	}
+	log.Diff, err = json.Marshal(Diff(before, after))
+	return err
+}
+
+// Change изменение одного поля
+type Change struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// Diff сравнивает JSON-представления сущности по полям верхнего уровня
+func Diff(before, after map[string]interface{}) map[string]Change {
+	diff := make(map[string]Change)
+	for key, from := range before {
+		to, ok := after[key]
+		if !ok || !reflect.DeepEqual(from, to) {
+			diff[key] = Change{From: from, To: to}
+		}
+	}
+	for key, to := range after {
+		if _, ok := before[key]; !ok {
+			diff[key] = Change{To: to}
+		}
+	}
+	return diff
+}
+
+// toMap приводит значение к JSON-объекту; nil остается nil
+func toMap(v interface{}) (map[string]interface{}, error) {
+	if v == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func marshalNonNil(m map[string]interface{}) (json.RawMessage, error) {
+	if m == nil {
+		return nil, nil
+	}
+	return json.Marshal(m)
+}
@@ -0,0 +1,88 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+type snapshot struct {
+	Name   string  `json:"name"`
+	Status string  `json:"status"`
+	Amount float64 `json:"amount"`
+	Secret string  `json:"-"`
+}
+
+func TestDiff(t *testing.T) {
+	diff := Diff(
+		map[string]interface{}{"name": "A", "status": "draft", "removed": true},
+		map[string]interface{}{"name": "A", "status": "sent", "added": 1.0},
+	)
+
+	assert.Equal(t, map[string]Change{
+		"status":  {From: "draft", To: "sent"},
+		"removed": {From: true, To: nil},
+		"added":   {From: nil, To: 1.0},
+	}, diff)
+}
+
+func TestEntry_Apply(t *testing.T) {
+	entry := NewEntry()
+	entry.SetEntity("esf-documents", "doc-1")
+	entry.SetOrganization("org-1")
+	entry.SetChange(
+		snapshot{Name: "A", Status: "draft", Amount: 10, Secret: "x"},
+		&snapshot{Name: "A", Status: "sent", Amount: 10, Secret: "y"},
+	)
+
+	log := &entity.AuditLog{Action: "update", EntityType: "route-type"}
+	require.NoError(t, entry.Apply(log))
+
+	assert.Equal(t, "esf-documents", log.EntityType)
+	assert.Equal(t, "doc-1", log.EntityID)
+	assert.Equal(t, "org-1", log.OrganizationID)
+	assert.Equal(t, "update", log.Action)
+	assert.JSONEq(t, `{"name":"A","status":"draft","amount":10}`, string(log.Before))
+	assert.JSONEq(t, `{"name":"A","status":"sent","amount":10}`, string(log.After))
+	assert.JSONEq(t, `{"status":{"from":"draft","to":"sent"}}`, string(log.Diff))
+}
+
+func TestEntry_ApplyCreate(t *testing.T) {
+	entry := NewEntry()
+	entry.SetAction("restore")
+	entry.SetChange(nil, snapshot{Name: "A"})
+
+	log := &entity.AuditLog{Action: "create"}
+	require.NoError(t, entry.Apply(log))
+
+	assert.Equal(t, "restore", log.Action)
+	assert.Nil(t, log.Before)
+
+	var diff map[string]Change
+	require.NoError(t, json.Unmarshal(log.Diff, &diff))
+	assert.Equal(t, Change{From: nil, To: "A"}, diff["name"])
+}
+
+func TestEntry_NilSafe(t *testing.T) {
+	// Вне HTTP-запроса записи нет, вызовы сервисов не должны паниковать
+	entry := FromContext(context.Background())
+	require.Nil(t, entry)
+
+	entry.SetEntity("users", "1")
+	entry.SetOrganization("org")
+	entry.SetAction("restore")
+	entry.SetChange(nil, snapshot{})
+	assert.NoError(t, entry.Apply(&entity.AuditLog{}))
+}
+
+func TestFromContext(t *testing.T) {
+	entry := NewEntry()
+	ctx := context.WithValue(context.Background(), LocalsKey, entry)
+
+	assert.Same(t, entry, FromContext(ctx))
+}
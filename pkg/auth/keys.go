@@ -0,0 +1,170 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// LegacyKeyID kid ключа из JWT_SECRET; токены, выпущенные до ротации, не содержат kid
+const LegacyKeyID = "default"
+
+var (
+	// ErrNoSigningKeys возвращается, если не задан ни JWT_SECRET, ни JWT_KEYS
+	ErrNoSigningKeys = errors.New("no JWT signing keys configured")
+	// ErrUnknownKey возвращается для токена с неизвестным или истекшим kid
+	ErrUnknownKey = errors.New("unknown or expired JWT signing key")
+)
+
+// SigningKey ключ подписи JWT (HS256).
+// С NotBefore ключ подписывает новые токены (пустое — сразу), после ExpiresAt
+// подписанные им токены отклоняются (пустое — бессрочно).
+type SigningKey struct {
+	ID        string    `json:"kid"`
+	Secret    string    `json:"secret"`
+	NotBefore time.Time `json:"not_before,omitempty"`
+	ExpiresAt time.Time `json:"expires_at,omitempty"`
+}
+
+// KeyRing набор ключей подписи JWT. Новые токены подписываются последним вступившим
+// в действие ключом, а проверяются любым неистекшим, поэтому ключ можно сменить
+// по расписанию, не разлогинивая пользователей.
+type KeyRing struct {
+	keys []SigningKey
+	now  func() time.Time
+}
+
+// NewKeyRing создает набор ключей; kid должны быть уникальны
+func NewKeyRing(keys ...SigningKey) (*KeyRing, error) {
+	if len(keys) == 0 {
+		return nil, ErrNoSigningKeys
+	}
+	seen := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		if k.ID == "" {
+			return nil, fmt.Errorf("JWT signing key without kid")
+		}
+		if k.Secret == "" {
+			return nil, fmt.Errorf("JWT signing key %q has empty secret", k.ID)
+		}
+		if seen[k.ID] {
+			return nil, fmt.Errorf("duplicate JWT signing key %q", k.ID)
+		}
+		if !k.ExpiresAt.IsZero() && !k.NotBefore.IsZero() && !k.ExpiresAt.After(k.NotBefore) {
+			return nil, fmt.Errorf("JWT signing key %q expires before it becomes active", k.ID)
+		}
+		seen[k.ID] = true
+	}
+	return &KeyRing{keys: keys, now: time.Now}, nil
+}
+
+// ParseKeys разбирает JSON-массив ключей из JWT_KEYS:
+//
+//	[{"kid":"2026-10","secret":"...","not_before":"2026-10-20T00:00:00Z","expires_at":"2027-01-01T00:00:00Z"}]
+func ParseKeys(raw string) ([]SigningKey, error) {
+	var keys []SigningKey
+	if err := json.Unmarshal([]byte(raw), &keys); err != nil {
+		return nil, fmt.Errorf("invalid JWT_KEYS: %w", err)
+	}
+	return keys, nil
+}
+
+// KeyRingFromEnv собирает ключи из JWT_SECRET (kid "default") и JWT_KEYS
+func KeyRingFromEnv() (*KeyRing, error) {
+	var keys []SigningKey
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		keys = append(keys, SigningKey{ID: LegacyKeyID, Secret: secret})
+	}
+	if raw := os.Getenv("JWT_KEYS"); raw != "" {
+		extra, err := ParseKeys(raw)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, extra...)
+	}
+	return NewKeyRing(keys...)
+}
+
+// Keys возвращает ключи набора
+func (r *KeyRing) Keys() []SigningKey {
+	return append([]SigningKey(nil), r.keys...)
+}
+
+// Current возвращает ключ для подписи новых токенов: из действующих ключей — с самым
+// поздним NotBefore, при равенстве — последний в списке
+func (r *KeyRing) Current() (SigningKey, error) {
+	now := r.now()
+	var current *SigningKey
+	for i := range r.keys {
+		k := &r.keys[i]
+		if !k.active(now) {
+			continue
+		}
+		if current == nil || !k.NotBefore.Before(current.NotBefore) {
+			current = k
+		}
+	}
+	if current == nil {
+		return SigningKey{}, ErrNoSigningKeys
+	}
+	return *current, nil
+}
+
+// Sign подписывает claims текущим ключом и записывает его kid в заголовок токена
+func (r *KeyRing) Sign(claims jwt.Claims) (string, error) {
+	key, err := r.Current()
+	if err != nil {
+		return "", err
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString([]byte(key.Secret))
+}
+
+// Keyfunc выбирает ключ проверки по kid токена. Ключ, еще не вступивший в действие,
+// принимается: другой экземпляр с расходящимися часами уже может им подписывать.
+// Токены без kid проверяются всеми неистекшими ключами.
+func (r *KeyRing) Keyfunc(token *jwt.Token) (interface{}, error) {
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, fmt.Errorf("unexpected signing method %v", token.Header["alg"])
+	}
+	now := r.now()
+	kid, _ := token.Header["kid"].(string)
+	if kid != "" {
+		for _, k := range r.keys {
+			if k.ID == kid && !k.expired(now) {
+				return []byte(k.Secret), nil
+			}
+		}
+		return nil, ErrUnknownKey
+	}
+
+	set := jwt.VerificationKeySet{}
+	for _, k := range r.keys {
+		if !k.expired(now) {
+			set.Keys = append(set.Keys, []byte(k.Secret))
+		}
+	}
+	if len(set.Keys) == 0 {
+		return nil, ErrUnknownKey
+	}
+	return set, nil
+}
+
+// Parse проверяет подпись и срок токена и заполняет claims
+func (r *KeyRing) Parse(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	return jwt.ParseWithClaims(tokenString, claims, r.Keyfunc,
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodHS384.Alg(), jwt.SigningMethodHS512.Alg()}))
+}
+
+func (k SigningKey) active(now time.Time) bool {
+	return !now.Before(k.NotBefore) && !k.expired(now)
+}
+
+func (k SigningKey) expired(now time.Time) bool {
+	return !k.ExpiresAt.IsZero() && !now.Before(k.ExpiresAt)
+}
@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	oldSecret = "old-secret-old-secret-old-secret"
+	newSecret = "new-secret-new-secret-new-secret"
+)
+
+func userClaims() jwt.MapClaims {
+	return jwt.MapClaims{"user_id": "u1", "exp": time.Now().Add(time.Hour).Unix()}
+}
+
+func TestKeyRing_Rotation(t *testing.T) {
+	start := time.Now()
+	ring, err := NewKeyRing(
+		SigningKey{ID: "old", Secret: oldSecret, ExpiresAt: start.Add(48 * time.Hour)},
+		SigningKey{ID: "new", Secret: newSecret, NotBefore: start.Add(24 * time.Hour)},
+	)
+	require.NoError(t, err)
+	ring.now = func() time.Time { return start }
+
+	oldToken, err := ring.Sign(userClaims())
+	require.NoError(t, err)
+	parsed, err := ring.Parse(oldToken, jwt.MapClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "old", parsed.Header["kid"])
+
+	// После NotBefore подписывает новый ключ, выпущенные старым токены остаются действительными
+	ring.now = func() time.Time { return start.Add(25 * time.Hour) }
+	newToken, err := ring.Sign(userClaims())
+	require.NoError(t, err)
+	parsed, err = ring.Parse(newToken, jwt.MapClaims{})
+	require.NoError(t, err)
+	assert.Equal(t, "new", parsed.Header["kid"])
+	_, err = ring.Parse(oldToken, jwt.MapClaims{})
+	assert.NoError(t, err)
+
+	// После ExpiresAt токены старого ключа отклоняются
+	ring.now = func() time.Time { return start.Add(49 * time.Hour) }
+	_, err = ring.Parse(oldToken, jwt.MapClaims{})
+	assert.ErrorIs(t, err, ErrUnknownKey)
+	_, err = ring.Parse(newToken, jwt.MapClaims{})
+	assert.NoError(t, err)
+}
+
+func TestKeyRing_LegacyTokenWithoutKid(t *testing.T) {
+	ring, err := NewKeyRing(
+		SigningKey{ID: LegacyKeyID, Secret: oldSecret},
+		SigningKey{ID: "new", Secret: newSecret},
+	)
+	require.NoError(t, err)
+
+	token, err := GenerateToken("u1", "user@example.com", oldSecret, time.Hour)
+	require.NoError(t, err)
+	_, err = ring.Parse(token, jwt.MapClaims{})
+	assert.NoError(t, err)
+
+	forged, err := GenerateToken("u1", "user@example.com", "another-secret-another-secret-00", time.Hour)
+	require.NoError(t, err)
+	_, err = ring.Parse(forged, jwt.MapClaims{})
+	assert.Error(t, err)
+}
+
+func TestKeyRing_RejectsUnknownKidAndAlgorithm(t *testing.T) {
+	ring, err := NewKeyRing(SigningKey{ID: "current", Secret: newSecret})
+	require.NoError(t, err)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, userClaims())
+	token.Header["kid"] = "missing"
+	signed, err := token.SignedString([]byte(newSecret))
+	require.NoError(t, err)
+	_, err = ring.Parse(signed, jwt.MapClaims{})
+	assert.ErrorIs(t, err, ErrUnknownKey)
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, userClaims()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+	_, err = ring.Parse(unsigned, jwt.MapClaims{})
+	assert.Error(t, err)
+}
+
+func TestNewKeyRing_Validation(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name string
+		keys []SigningKey
+	}{
+		{"no keys", nil},
+		{"empty kid", []SigningKey{{Secret: newSecret}}},
+		{"empty secret", []SigningKey{{ID: "a"}}},
+		{"duplicate kid", []SigningKey{{ID: "a", Secret: oldSecret}, {ID: "a", Secret: newSecret}}},
+		{"expires before active", []SigningKey{{ID: "a", Secret: newSecret, NotBefore: now, ExpiresAt: now.Add(-time.Hour)}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewKeyRing(tt.keys...)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestKeyRingFromEnv(t *testing.T) {
+	t.Setenv("JWT_SECRET", oldSecret)
+	t.Setenv("JWT_KEYS", `[{"kid":"2026-10","secret":"`+newSecret+`","not_before":"2026-01-01T00:00:00Z"}]`)
+
+	ring, err := KeyRingFromEnv()
+	require.NoError(t, err)
+	require.Len(t, ring.Keys(), 2)
+	current, err := ring.Current()
+	require.NoError(t, err)
+	assert.Equal(t, "2026-10", current.ID)
+
+	t.Setenv("JWT_KEYS", `not json`)
+	_, err = KeyRingFromEnv()
+	assert.Error(t, err)
+
+	t.Setenv("JWT_SECRET", "")
+	t.Setenv("JWT_KEYS", "")
+	_, err = KeyRingFromEnv()
+	assert.ErrorIs(t, err, ErrNoSigningKeys)
+}
@@ -0,0 +1,95 @@
+// Package bankstatement разбирает банковские выписки в форматах MT940 и CSV.
+// Поступления возвращаются с положительной суммой, списания — с отрицательной.
+package bankstatement
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Форматы выписок
+const (
+	FormatMT940 = "mt940"
+	FormatCSV   = "csv"
+)
+
+// maxStatementSize ограничение размера выписки при разборе
+const maxStatementSize = 10 << 20
+
+// ErrUnknownFormat формат выписки не указан и не определяется по файлу
+var ErrUnknownFormat = errors.New("unknown bank statement format")
+
+// Transaction операция по счету
+type Transaction struct {
+	Date     time.Time
+	Amount   float64
+	Currency string
+	// CounterpartyTin ИНН плательщика или получателя, если указан в выписке
+	CounterpartyTin  string
+	CounterpartyName string
+	// Reference номер платежного поручения или назначение платежа в коротком виде
+	Reference   string
+	Description string
+}
+
+// Statement выписка по одному счету
+type Statement struct {
+	Format       string
+	Account      string
+	Currency     string
+	Transactions []Transaction
+}
+
+// Parse разбирает выписку в формате format; пустой формат определяется по имени файла и содержимому
+func Parse(format, fileName string, r io.Reader) (*Statement, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxStatementSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxStatementSize {
+		return nil, fmt.Errorf("bank statement exceeds %d bytes", maxStatementSize)
+	}
+	if format == "" {
+		format = DetectFormat(fileName, data)
+	}
+
+	switch format {
+	case FormatMT940:
+		return ParseMT940(bytes.NewReader(data))
+	case FormatCSV:
+		return ParseCSV(bytes.NewReader(data))
+	default:
+		return nil, ErrUnknownFormat
+	}
+}
+
+// DetectFormat определяет формат по расширению файла, а при неизвестном расширении — по тегам MT940
+func DetectFormat(fileName string, data []byte) string {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		return FormatCSV
+	case ".sta", ".940", ".mt940":
+		return FormatMT940
+	}
+	if bytes.Contains(data, []byte(":61:")) {
+		return FormatMT940
+	}
+	if bytes.ContainsAny(data, ",;") {
+		return FormatCSV
+	}
+	return ""
+}
+
+// tinPattern ИНН Кыргызской Республики: 14 цифр (12 для старых ИНН физических лиц)
+var tinPattern = regexp.MustCompile(`\b\d{14}\b|\b\d{12}\b`)
+
+// findTin ищет ИНН в тексте назначения платежа
+func findTin(s string) string {
+	return tinPattern.FindString(s)
+}
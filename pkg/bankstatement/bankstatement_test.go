@@ -0,0 +1,104 @@
+package bankstatement
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleMT940 = `{1:F01KICBKG22AXXX0000000000}{2:I940KICBKG22XXXXN}{4:
+:20:STMT-2026-02
+:25:1280016000123456
+:28C:00012/001
+:60F:C260201KGS100000,00
+:61:2602100210C1500,00NTRFPP-118//B26021000001
+:86:/INN/01234567890123/NAME/ОсОО Пример/REF/PP-118/REMI/Оплата по договору
+ Д-15 от 01.02.2026/
+:61:260211D250,50NCHGNONREF//B26021100002
+:86:Комиссия банка за обслуживание
+:61:260212C3200,00NTRFNONREF
+:86:Оплата ИНН 20101199000123 по счету 77
+:62F:C260212KGS104449,50
+-}`
+
+func TestParseMT940(t *testing.T) {
+	st, err := ParseMT940(strings.NewReader(sampleMT940))
+	require.NoError(t, err)
+	assert.Equal(t, "1280016000123456", st.Account)
+	assert.Equal(t, "KGS", st.Currency)
+	require.Len(t, st.Transactions, 3)
+
+	first := st.Transactions[0]
+	assert.Equal(t, time.Date(2026, 2, 10, 0, 0, 0, 0, time.UTC), first.Date)
+	assert.Equal(t, 1500.0, first.Amount)
+	assert.Equal(t, "KGS", first.Currency)
+	assert.Equal(t, "01234567890123", first.CounterpartyTin)
+	assert.Equal(t, "ОсОО Пример", first.CounterpartyName)
+	assert.Equal(t, "PP-118", first.Reference)
+	assert.Equal(t, "Оплата по договору Д-15 от 01.02.2026", first.Description)
+
+	fee := st.Transactions[1]
+	assert.Equal(t, -250.5, fee.Amount, "debits are negative")
+	assert.Empty(t, fee.Reference, "NONREF is not a reference")
+	assert.Empty(t, fee.CounterpartyTin)
+
+	unstructured := st.Transactions[2]
+	assert.Equal(t, "20101199000123", unstructured.CounterpartyTin, "TIN is found in free text")
+	assert.Equal(t, "Оплата ИНН 20101199000123 по счету 77", unstructured.Description)
+}
+
+func TestParseMT940_Errors(t *testing.T) {
+	_, err := ParseMT940(strings.NewReader(":20:X\n:25:123\n"))
+	assert.Error(t, err, "statement without transactions")
+
+	_, err = ParseMT940(strings.NewReader(":20:X\n:61:26XX10C1,00NTRF\n"))
+	assert.Error(t, err)
+}
+
+func TestParseCSV(t *testing.T) {
+	data := "\ufeffДата;Сумма;ИНН;Контрагент;Номер;Назначение\n" +
+		"10.02.2026;1 500,00;01234567890123;ОсОО Пример;PP-118;Оплата по договору Д-15\n" +
+		"\n" +
+		"11.02.2026;-250,50;;Банк;;Комиссия\n"
+	st, err := ParseCSV(strings.NewReader(data))
+	require.NoError(t, err)
+	require.Len(t, st.Transactions, 2)
+	assert.Equal(t, 1500.0, st.Transactions[0].Amount)
+	assert.Equal(t, "01234567890123", st.Transactions[0].CounterpartyTin)
+	assert.Equal(t, "PP-118", st.Transactions[0].Reference)
+	assert.Equal(t, time.Date(2026, 2, 11, 0, 0, 0, 0, time.UTC), st.Transactions[1].Date)
+	assert.Equal(t, -250.5, st.Transactions[1].Amount)
+}
+
+func TestParseCSV_CreditDebitColumns(t *testing.T) {
+	data := "date,credit,debit,currency,description\n" +
+		"2026-02-10,1500.00,,kgs,\"Payment, TIN 01234567890123\"\n" +
+		"2026-02-11,,99.90,KGS,Fee\n"
+	st, err := ParseCSV(strings.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, "KGS", st.Currency)
+	assert.Equal(t, 1500.0, st.Transactions[0].Amount)
+	assert.Equal(t, "01234567890123", st.Transactions[0].CounterpartyTin)
+	assert.Equal(t, -99.9, st.Transactions[1].Amount)
+
+	_, err = ParseCSV(strings.NewReader("date,comment\n2026-02-10,x\n"))
+	assert.Error(t, err, "amount column is required")
+	_, err = ParseCSV(strings.NewReader("date,amount\n2026-13-40,1\n"))
+	assert.Error(t, err)
+}
+
+func TestParse_DetectsFormat(t *testing.T) {
+	st, err := Parse("", "statement.txt", strings.NewReader(sampleMT940))
+	require.NoError(t, err)
+	assert.Len(t, st.Transactions, 3)
+
+	st, err = Parse("", "statement.csv", strings.NewReader("date;amount\n2026-02-10;5\n"))
+	require.NoError(t, err)
+	assert.Len(t, st.Transactions, 1)
+
+	_, err = Parse("", "statement.bin", strings.NewReader("binary"))
+	assert.ErrorIs(t, err, ErrUnknownFormat)
+}
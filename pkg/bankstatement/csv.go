@@ -0,0 +1,164 @@
+package bankstatement
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// csvColumns названия колонок CSV (без учета регистра) и поле операции, в которое они читаются
+var csvColumns = map[string]string{
+	"date": "date", "value_date": "date", "дата": "date",
+	"amount": "amount", "сумма": "amount",
+	"credit": "credit", "приход": "credit", "поступление": "credit",
+	"debit": "debit", "расход": "debit", "списание": "debit",
+	"currency": "currency", "валюта": "currency",
+	"tin": "tin", "inn": "tin", "инн": "tin", "counterparty_tin": "tin",
+	"name": "name", "counterparty": "name", "контрагент": "name", "плательщик": "name",
+	"reference": "reference", "ref": "reference", "номер": "reference", "document_number": "reference",
+	"description": "description", "purpose": "description", "назначение": "description", "назначение платежа": "description",
+}
+
+// csvDateLayouts форматы дат, принимаемые в CSV
+var csvDateLayouts = []string{"2006-01-02", "02.01.2006", "02/01/2006"}
+
+// ParseCSV разбирает выписку CSV с заголовком. Разделитель «;» или «,» определяется по заголовку.
+// Сумма задается колонкой amount со знаком либо парой колонок credit и debit.
+// Если колонки ИНН нет, ИНН ищется в назначении платежа.
+func ParseCSV(r io.Reader) (*Statement, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(4096)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return nil, err
+	}
+	// Метка порядка байтов, которую добавляют выгрузки Excel
+	bom := []byte("\ufeff")
+	hasBOM := bytes.HasPrefix(header, bom)
+	header = bytes.TrimPrefix(header, bom)
+	if len(bytes.TrimSpace(header)) == 0 {
+		return nil, fmt.Errorf("csv: empty statement")
+	}
+	firstLine, _, _ := bytes.Cut(header, []byte("\n"))
+	semicolon := bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(","))
+	if hasBOM {
+		_, _ = br.Discard(len(bom))
+	}
+
+	cr := csv.NewReader(br)
+	cr.Comma = ','
+	if semicolon {
+		cr.Comma = ';'
+	}
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	names, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("csv: reading header: %w", err)
+	}
+	columns := make(map[string]int, len(names))
+	for i, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if field, ok := csvColumns[name]; ok {
+			columns[field] = i
+		}
+	}
+	if _, ok := columns["date"]; !ok {
+		return nil, fmt.Errorf("csv: date column is required")
+	}
+	_, hasAmount := columns["amount"]
+	_, hasCredit := columns["credit"]
+	_, hasDebit := columns["debit"]
+	if !hasAmount && !hasCredit && !hasDebit {
+		return nil, fmt.Errorf("csv: amount or credit/debit columns are required")
+	}
+
+	st := &Statement{Format: FormatCSV}
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("csv: %w", err)
+		}
+		value := func(field string) string {
+			if i, ok := columns[field]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		tx := Transaction{
+			Currency:         strings.ToUpper(value("currency")),
+			CounterpartyTin:  value("tin"),
+			CounterpartyName: value("name"),
+			Reference:        value("reference"),
+			Description:      value("description"),
+		}
+		if tx.Date, err = parseCSVDate(value("date")); err != nil {
+			return nil, fmt.Errorf("csv: line %d: %w", line, err)
+		}
+		if tx.Amount, err = csvAmount(value("amount"), value("credit"), value("debit")); err != nil {
+			return nil, fmt.Errorf("csv: line %d: %w", line, err)
+		}
+		if tx.CounterpartyTin == "" {
+			tx.CounterpartyTin = findTin(tx.Description)
+		}
+		if st.Currency == "" {
+			st.Currency = tx.Currency
+		}
+		st.Transactions = append(st.Transactions, tx)
+	}
+	if len(st.Transactions) == 0 {
+		return nil, fmt.Errorf("csv: no transactions found")
+	}
+	return st, nil
+}
+
+func parseCSVDate(s string) (time.Time, error) {
+	for _, layout := range csvDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", s)
+}
+
+// csvAmount возвращает сумму со знаком: amount как есть, иначе credit минус debit
+func csvAmount(amount, credit, debit string) (float64, error) {
+	if amount != "" {
+		v, err := parseAmount(amount)
+		if err != nil {
+			return 0, fmt.Errorf("invalid amount %q", amount)
+		}
+		return v, nil
+	}
+	var total float64
+	if credit != "" {
+		v, err := parseAmount(credit)
+		if err != nil {
+			return 0, fmt.Errorf("invalid credit %q", credit)
+		}
+		total += v
+	}
+	if debit != "" {
+		v, err := parseAmount(debit)
+		if err != nil {
+			return 0, fmt.Errorf("invalid debit %q", debit)
+		}
+		total -= v
+	}
+	if credit == "" && debit == "" {
+		return 0, fmt.Errorf("amount is empty")
+	}
+	return total, nil
+}
@@ -0,0 +1,183 @@
+package bankstatement
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// mt940Line поле :61: — дата валютирования, необязательная дата проводки, признак
+// дебет/кредит (R — сторно), код средств, сумма, тип операции, ссылка клиента и ссылка банка
+var mt940Line = regexp.MustCompile(`^(\d{6})(\d{4})?(RC|RD|C|D)([A-Z])?(\d+,\d*)([NF][A-Z0-9]{3})([^/]*)(?://(.*))?$`)
+
+// mt940Field поле выписки: тег и значение с продолжениями
+type mt940Field struct {
+	tag   string
+	value string
+}
+
+// ParseMT940 разбирает выписку SWIFT MT940. Файл может содержать несколько выписок подряд;
+// счет и валюта берутся из первой. ИНН и наименование контрагента берутся из структурированного
+// поля :86: (/INN/, /NAME/, /REF/), а при его отсутствии ИНН ищется в тексте назначения платежа.
+func ParseMT940(r io.Reader) (*Statement, error) {
+	fields, err := mt940Fields(r)
+	if err != nil {
+		return nil, err
+	}
+
+	st := &Statement{Format: FormatMT940}
+	for _, f := range fields {
+		switch f.tag {
+		case "25":
+			if st.Account == "" {
+				st.Account = strings.TrimSpace(f.value)
+			}
+		case "60F", "60M":
+			// C250101KGS1000,00: признак, дата, валюта, сумма
+			if st.Currency == "" && len(f.value) >= 10 {
+				st.Currency = f.value[7:10]
+			}
+		case "61":
+			tx, err := parseMT940Transaction(f.value)
+			if err != nil {
+				return nil, err
+			}
+			tx.Currency = st.Currency
+			st.Transactions = append(st.Transactions, tx)
+		case "86":
+			if n := len(st.Transactions); n > 0 {
+				applyMT940Details(&st.Transactions[n-1], f.value)
+			}
+		}
+	}
+	if len(st.Transactions) == 0 {
+		return nil, fmt.Errorf("mt940: no transactions (:61:) found")
+	}
+	return st, nil
+}
+
+// mt940Fields делит текст на поля; строки без тега продолжают предыдущее поле,
+// заголовки блоков SWIFT ({1:...}) и завершающий «-}» пропускаются
+func mt940Fields(r io.Reader) ([]mt940Field, error) {
+	var fields []mt940Field
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxStatementSize)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r ")
+		switch {
+		case line == "" || line == "-" || line == "-}" || strings.HasPrefix(line, "{"):
+			continue
+		case strings.HasPrefix(line, ":"):
+			end := strings.Index(line[1:], ":")
+			if end <= 0 {
+				return nil, fmt.Errorf("mt940: malformed field %q", line)
+			}
+			fields = append(fields, mt940Field{tag: line[1 : end+1], value: line[end+2:]})
+		case len(fields) > 0:
+			fields[len(fields)-1].value += "\n" + line
+		}
+	}
+	return fields, scanner.Err()
+}
+
+func parseMT940Transaction(value string) (Transaction, error) {
+	first, rest, _ := strings.Cut(value, "\n")
+	m := mt940Line.FindStringSubmatch(first)
+	if m == nil {
+		return Transaction{}, fmt.Errorf("mt940: malformed :61: field %q", first)
+	}
+
+	date, err := time.Parse("060102", m[1])
+	if err != nil {
+		return Transaction{}, fmt.Errorf("mt940: invalid value date %q", m[1])
+	}
+	amount, err := parseAmount(m[5])
+	if err != nil {
+		return Transaction{}, fmt.Errorf("mt940: invalid amount %q", m[5])
+	}
+	// Списание и сторно поступления уменьшают остаток
+	if m[3] == "D" || m[3] == "RC" {
+		amount = -amount
+	}
+
+	tx := Transaction{Date: date, Amount: amount, Description: strings.TrimSpace(rest)}
+	if ref := strings.TrimSpace(m[7]); ref != "" && ref != "NONREF" {
+		tx.Reference = ref
+	}
+	return tx, nil
+}
+
+// applyMT940Details переносит данные контрагента из поля :86:
+func applyMT940Details(tx *Transaction, value string) {
+	text := strings.ReplaceAll(value, "\n", "")
+	tags := mt940Tags(text)
+	if len(tags) == 0 {
+		tx.Description = joinText(tx.Description, strings.ReplaceAll(value, "\n", " "))
+		tx.CounterpartyTin = findTin(text)
+		return
+	}
+
+	for _, t := range tags {
+		switch t.tag {
+		case "INN", "TIN":
+			tx.CounterpartyTin = t.value
+		case "NAME", "ORDP", "BENM":
+			tx.CounterpartyName = t.value
+		case "REF", "EREF":
+			tx.Reference = t.value
+		case "REMI", "PURP":
+			tx.Description = joinText(tx.Description, t.value)
+		}
+	}
+	if tx.CounterpartyTin == "" {
+		tx.CounterpartyTin = findTin(tx.Description)
+	}
+}
+
+// mt940TagPattern подполя структурированного поля :86: вида /INN/01234567890123/NAME/ОсОО Пример/
+var mt940TagPattern = regexp.MustCompile(`/(INN|TIN|NAME|ORDP|BENM|REF|EREF|REMI|PURP)/`)
+
+// mt940Tags разбирает подполя :86: в порядке следования; nil — поле не структурировано
+func mt940Tags(text string) []mt940Field {
+	if !strings.HasPrefix(text, "/") {
+		return nil
+	}
+	idx := mt940TagPattern.FindAllStringSubmatchIndex(text, -1)
+	if len(idx) == 0 || idx[0][0] != 0 {
+		return nil
+	}
+	tags := make([]mt940Field, len(idx))
+	for i, m := range idx {
+		end := len(text)
+		if i+1 < len(idx) {
+			end = idx[i+1][0]
+		}
+		tags[i] = mt940Field{tag: text[m[2]:m[3]], value: strings.TrimSpace(strings.TrimSuffix(text[m[1]:end], "/"))}
+	}
+	return tags
+}
+
+// parseAmount разбирает сумму с запятой или точкой в качестве десятичного разделителя
+func parseAmount(s string) (float64, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), " ", "")
+	s = strings.ReplaceAll(s, "\u00a0", "")
+	if strings.Contains(s, ",") {
+		s = strings.ReplaceAll(s, ".", "")
+		s = strings.Replace(s, ",", ".", 1)
+	}
+	return strconv.ParseFloat(s, 64)
+}
+
+func joinText(a, b string) string {
+	switch {
+	case a == "":
+		return strings.TrimSpace(b)
+	case b == "":
+		return a
+	}
+	return a + " " + strings.TrimSpace(b)
+}
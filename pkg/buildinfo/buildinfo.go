@@ -0,0 +1,138 @@
+// Package buildinfo хранит сведения о сборке: версию, коммит, время сборки и функции, включенные
+// при сборке. Значения задаются через -ldflags:
+//
+//	go build -ldflags "\
+//	  -X github.com/rusgainew/tunduck-app/pkg/buildinfo.Version=1.4.0 \
+//	  -X github.com/rusgainew/tunduck-app/pkg/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/rusgainew/tunduck-app/pkg/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ) \
+//	  -X github.com/rusgainew/tunduck-app/pkg/buildinfo.Features=grpc,graphql" ./cmd/api
+//
+// Без -ldflags коммит и время берутся из сведений о VCS, которые go build встраивает при сборке
+// в рабочей копии git.
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Name имя приложения в идентификаторе релиза
+const Name = "tunduck-api"
+
+// Значения, задаваемые через -ldflags -X
+var (
+	// Version версия релиза; "dev" для локальных сборок
+	Version = "dev"
+	// Commit хеш коммита
+	Commit = ""
+	// BuildTime время сборки в RFC 3339
+	BuildTime = ""
+	// Features функции, включенные при сборке, через запятую
+	Features = ""
+)
+
+// Info сведения о сборке
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"buildTime"`
+	GoVersion string `json:"goVersion"`
+	// Modified сборка из рабочей копии с незакоммиченными изменениями (только по сведениям о VCS)
+	Modified bool     `json:"modified,omitempty"`
+	Features []string `json:"features"`
+	// Release идентификатор релиза для систем отслеживания ошибок: tunduck-api@<версия>+<коммит>
+	Release string `json:"release"`
+}
+
+var (
+	once sync.Once
+	info Info
+)
+
+// Get возвращает сведения о сборке; значения вычисляются один раз
+func Get() Info {
+	once.Do(func() {
+		bi, _ := debug.ReadBuildInfo()
+		info = newInfo(Version, Commit, BuildTime, Features, bi)
+	})
+	return info
+}
+
+func newInfo(version, commit, buildTime, features string, bi *debug.BuildInfo) Info {
+	if version == "" {
+		version = "dev"
+	}
+	result := Info{
+		Version:   version,
+		Commit:    commit,
+		BuildTime: buildTime,
+		GoVersion: runtime.Version(),
+		Features:  parseFeatures(features),
+	}
+
+	if bi != nil {
+		if bi.GoVersion != "" {
+			result.GoVersion = bi.GoVersion
+		}
+		vcsCommit := result.Commit == ""
+		for _, setting := range bi.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				if vcsCommit {
+					result.Commit = setting.Value
+				}
+			case "vcs.time":
+				if result.BuildTime == "" {
+					result.BuildTime = setting.Value
+				}
+			case "vcs.modified":
+				result.Modified = vcsCommit && setting.Value == "true"
+			}
+		}
+	}
+
+	result.Release = release(result.Version, result.Commit, result.Modified)
+	return result
+}
+
+// ShortCommit первые 7 символов коммита
+func (i Info) ShortCommit() string {
+	return shortCommit(i.Commit)
+}
+
+func shortCommit(commit string) string {
+	if len(commit) > 7 {
+		return commit[:7]
+	}
+	return commit
+}
+
+func release(version, commit string, modified bool) string {
+	release := Name + "@" + version
+	if commit != "" {
+		release += "+" + shortCommit(commit)
+		if modified {
+			release += ".dirty"
+		}
+	}
+	return release
+}
+
+// parseFeatures разбирает список функций: без пустых значений и повторов, по алфавиту
+func parseFeatures(raw string) []string {
+	features := []string{}
+	seen := make(map[string]bool)
+	for _, feature := range strings.Split(raw, ",") {
+		feature = strings.TrimSpace(feature)
+		if feature == "" || seen[feature] {
+			continue
+		}
+		seen[feature] = true
+		features = append(features, feature)
+	}
+	sort.Strings(features)
+	return features
+}
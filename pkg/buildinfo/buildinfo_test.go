@@ -0,0 +1,52 @@
+package buildinfo
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func vcsBuildInfo(modified string) *debug.BuildInfo {
+	return &debug.BuildInfo{
+		GoVersion: "go1.25.5",
+		Settings: []debug.BuildSetting{
+			{Key: "vcs", Value: "git"},
+			{Key: "vcs.revision", Value: "0123456789abcdef0123456789abcdef01234567"},
+			{Key: "vcs.time", Value: "2026-10-01T12:00:00Z"},
+			{Key: "vcs.modified", Value: modified},
+		},
+	}
+}
+
+func TestNewInfo_LdflagsTakePrecedence(t *testing.T) {
+	info := newInfo("1.4.0", "fedcba9876543210", "2026-10-02T08:30:00Z", "graphql, grpc,,graphql", vcsBuildInfo("true"))
+
+	assert.Equal(t, "1.4.0", info.Version)
+	assert.Equal(t, "fedcba9876543210", info.Commit)
+	assert.Equal(t, "2026-10-02T08:30:00Z", info.BuildTime)
+	assert.Equal(t, "go1.25.5", info.GoVersion)
+	assert.False(t, info.Modified, "modified applies only to the VCS revision")
+	assert.Equal(t, []string{"graphql", "grpc"}, info.Features)
+	assert.Equal(t, "tunduck-api@1.4.0+fedcba9", info.Release)
+	assert.Equal(t, "fedcba9", info.ShortCommit())
+}
+
+func TestNewInfo_FallsBackToVCS(t *testing.T) {
+	info := newInfo("", "", "", "", vcsBuildInfo("true"))
+
+	assert.Equal(t, "dev", info.Version)
+	assert.Equal(t, "0123456789abcdef0123456789abcdef01234567", info.Commit)
+	assert.Equal(t, "2026-10-01T12:00:00Z", info.BuildTime)
+	assert.True(t, info.Modified)
+	assert.Equal(t, []string{}, info.Features)
+	assert.Equal(t, "tunduck-api@dev+0123456.dirty", info.Release)
+}
+
+func TestNewInfo_WithoutBuildInfo(t *testing.T) {
+	info := newInfo("1.4.0", "", "", "", nil)
+
+	assert.Empty(t, info.Commit)
+	assert.NotEmpty(t, info.GoVersion)
+	assert.Equal(t, "tunduck-api@1.4.0", info.Release)
+}
@@ -0,0 +1,33 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// GetMany читает несколько ключей за один запрос (MGET) и декодирует значения в T.
+// В результате только найденные ключи; значение, которое не удалось декодировать, считается промахом.
+func GetMany[T any](ctx context.Context, c Cache, keys []string) (map[string]T, error) {
+	result := make(map[string]T, len(keys))
+	if len(keys) == 0 {
+		return result, nil
+	}
+
+	raw, err := c.GetMultiple(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, val := range raw {
+		s, ok := val.(string)
+		if !ok {
+			continue
+		}
+		var v T
+		if err := json.Unmarshal([]byte(s), &v); err != nil {
+			continue
+		}
+		result[key] = v
+	}
+	return result, nil
+}
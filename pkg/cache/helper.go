@@ -1,6 +1,9 @@
 package cache
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+)
 
 // CacheHelper вспомогательный класс для работы с кешем в сервисах
 type CacheHelper struct {
@@ -44,6 +47,11 @@ func (h *CacheHelper) InvalidateAllOrgCache(ctx context.Context) error {
 	return h.cacheManager.Organization().Clear(ctx, "*")
 }
 
+// InvalidateOrgTags инвалидирует все данные организации и списки организаций
+func (h *CacheHelper) InvalidateOrgTags(ctx context.Context, orgID string) error {
+	return h.cacheManager.InvalidateTags(ctx, OrgTag(orgID), TagOrganizations)
+}
+
 // InvalidateDocumentCache инвалидирует кеш документа
 func (h *CacheHelper) InvalidateDocumentCache(ctx context.Context, docID string) error {
 	return h.cacheManager.Document().Delete(ctx, docID)
@@ -74,3 +82,12 @@ func (h *CacheHelper) IsTokenBlacklisted(ctx context.Context, token string) (boo
 func (h *CacheHelper) FlushAllCaches(ctx context.Context) error {
 	return h.cacheManager.Flush(ctx)
 }
+
+// Decode преобразует значение, полученное из кеша (результат json.Unmarshal), в out
+func Decode(cached interface{}, out interface{}) error {
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
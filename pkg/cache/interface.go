@@ -27,8 +27,21 @@ type Cache interface {
 
 	// SetMultiple устанавливает несколько значений одновременно
 	SetMultiple(ctx context.Context, data map[string]interface{}, ttl time.Duration) error
+
+	// DeleteMultiple удаляет несколько значений за один запрос
+	DeleteMultiple(ctx context.Context, keys []string) error
+
+	// SetWithTags устанавливает значение и связывает ключ с тегами для групповой инвалидации
+	SetWithTags(ctx context.Context, key string, value interface{}, ttl time.Duration, tags ...string) error
+
+	// GetOrLoad читает значение в out, а при промахе вызывает load и кеширует результат.
+	// Одновременные промахи по одному ключу выполняют load только один раз.
+	GetOrLoad(ctx context.Context, key string, out interface{}, ttl time.Duration, load LoadFunc, tags ...string) error
 }
 
+// LoadFunc загружает значение из источника данных при промахе кеша
+type LoadFunc func(ctx context.Context) (interface{}, error)
+
 // CacheManager управляет различными кешами приложения
 type CacheManager interface {
 	// User кеш для пользователей
@@ -49,6 +62,9 @@ type CacheManager interface {
 	// Generic общий кеш
 	Generic() Cache
 
+	// InvalidateTags удаляет все ключи, связанные с тегами (во всех кешах)
+	InvalidateTags(ctx context.Context, tags ...string) error
+
 	// Flush очищает все кеши
 	Flush(ctx context.Context) error
 }
@@ -0,0 +1,52 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/sirupsen/logrus"
+)
+
+// GetOrLoad читает значение из кеша в out. При промахе только один вызов на ключ
+// выполняет load и записывает результат в кеш, остальные ждут и получают тот же результат.
+// Ошибки Redis не прерывают запрос: значение загружается из источника.
+func (r *RedisCache) GetOrLoad(ctx context.Context, key string, out interface{}, ttl time.Duration, load LoadFunc, tags ...string) error {
+	if cached, err := r.Get(ctx, key); err == nil && cached != nil {
+		if err := Decode(cached, out); err == nil {
+			return nil
+		}
+	}
+
+	data, err, shared := r.loads.Do(r.getFullKey(key), func() (interface{}, error) {
+		// Отмена запроса-лидера не должна прерывать загрузку для ожидающих запросов
+		loadCtx := context.WithoutCancel(ctx)
+
+		value, err := load(loadCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		jsonValue, err := json.Marshal(value)
+		if err != nil {
+			r.logger.WithError(err).WithField("key", r.getFullKey(key)).Error("Failed to marshal loaded value")
+			return nil, apperror.New(apperror.ErrInternal, "cache marshal error")
+		}
+
+		_ = r.setEncoded(loadCtx, key, jsonValue, ttl, tags...)
+		return jsonValue, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if shared {
+		r.logger.WithFields(logrus.Fields{
+			"key":    key,
+			"prefix": r.prefix,
+		}).Debug("Cache load shared between concurrent requests")
+	}
+
+	return json.Unmarshal(data.([]byte), out)
+}
@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Redis недоступен: каждый запрос - промах, значение берется из load
+func newUnreachableCache() *RedisCache {
+	client := redis.NewClient(&redis.Options{
+		Addr:        "127.0.0.1:1",
+		MaxRetries:  -1,
+		DialTimeout: 50 * time.Millisecond,
+	})
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+	return NewRedisCache(client, logger, "test")
+}
+
+func TestRedisCache_GetOrLoad_Singleflight(t *testing.T) {
+	c := newUnreachableCache()
+	defer c.client.Close()
+
+	var calls int32
+	release := make(chan struct{})
+	load := func(ctx context.Context) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release
+		return map[string]string{"name": "org"}, nil
+	}
+
+	const workers = 20
+	var started, done sync.WaitGroup
+	results := make([]map[string]string, workers)
+	errs := make([]error, workers)
+	started.Add(workers)
+	done.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func(i int) {
+			defer done.Done()
+			started.Done()
+			errs[i] = c.GetOrLoad(context.Background(), "hot", &results[i], time.Minute, load)
+		}(i)
+	}
+
+	started.Wait()
+	// Даем запросам дойти до singleflight, пока загрузка заблокирована
+	time.Sleep(200 * time.Millisecond)
+	close(release)
+	done.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	for i := 0; i < workers; i++ {
+		require.NoError(t, errs[i])
+		assert.Equal(t, "org", results[i]["name"])
+	}
+}
+
+func TestRedisCache_GetOrLoad_Error(t *testing.T) {
+	c := newUnreachableCache()
+	defer c.client.Close()
+
+	var out string
+	err := c.GetOrLoad(context.Background(), "broken", &out, time.Minute, func(ctx context.Context) (interface{}, error) {
+		return nil, assert.AnError
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+}
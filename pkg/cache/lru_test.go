@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUCache_Eviction(t *testing.T) {
+	c := newLRUCache(2, time.Minute)
+
+	c.set("a", []byte("1"), 0)
+	c.set("b", []byte("2"), 0)
+	_, _ = c.get("a") // a становится самым свежим
+	c.set("c", []byte("3"), 0)
+
+	_, ok := c.get("b")
+	assert.False(t, ok, "least recently used entry must be evicted")
+	_, ok = c.get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, c.len())
+}
+
+func TestLRUCache_TTL(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	c := newLRUCache(10, time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.set("short", []byte("1"), time.Second)
+	c.set("capped", []byte("2"), time.Hour) // ограничивается TTL локального кеша
+
+	now = now.Add(2 * time.Second)
+	_, ok := c.get("short")
+	assert.False(t, ok)
+	_, ok = c.get("capped")
+	assert.True(t, ok)
+
+	now = now.Add(time.Minute)
+	_, ok = c.get("capped")
+	assert.False(t, ok)
+}
+
+func TestLocalInvalidator_Apply(t *testing.T) {
+	local := &localInvalidator{origin: "self", lru: newLRUCache(10, time.Minute)}
+	local.lru.set("org:id:1", []byte("1"), 0)
+	local.lru.set("org:id:2", []byte("2"), 0)
+	local.lru.set("cache:reference:codes", []byte("3"), 0)
+
+	local.apply(invalidationMessage{Keys: []string{"org:id:1"}})
+	_, ok := local.lru.get("org:id:1")
+	assert.False(t, ok)
+
+	local.apply(invalidationMessage{Pattern: "org:*"})
+	_, ok = local.lru.get("org:id:2")
+	assert.False(t, ok)
+	assert.Equal(t, 1, local.lru.len())
+
+	local.apply(invalidationMessage{All: true})
+	assert.Equal(t, 0, local.lru.len())
+}
+
+func TestTieredCache_LocalHit(t *testing.T) {
+	remote := newUnreachableCache()
+	defer remote.client.Close()
+
+	local := &localInvalidator{client: remote.client, logger: remote.logger, origin: "self", lru: newLRUCache(10, time.Minute)}
+	tiered := &TieredCache{remote: remote, local: local}
+
+	// Значение в локальном кеше отдается без обращения к Redis
+	local.lru.set("test:ref", []byte(`{"code":"KGS"}`), 0)
+	val, err := tiered.Get(context.Background(), "ref")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"code": "KGS"}, val)
+
+	var out map[string]string
+	require.NoError(t, tiered.GetOrLoad(context.Background(), "ref", &out, time.Minute, nil))
+	assert.Equal(t, "KGS", out["code"])
+
+	// Промах локального кеша загружает значение и сохраняет его локально
+	err = tiered.GetOrLoad(context.Background(), "loaded", &out, time.Minute, func(ctx context.Context) (interface{}, error) {
+		return map[string]string{"code": "USD"}, nil
+	})
+	require.NoError(t, err)
+	cached, ok := local.lru.get("test:loaded")
+	require.True(t, ok)
+	assert.JSONEq(t, `{"code":"USD"}`, string(cached))
+}
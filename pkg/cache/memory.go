@@ -0,0 +1,281 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"path"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+)
+
+// memoryItem значение в памяти; нулевой expiresAt — без TTL
+type memoryItem struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// memoryStore общее хранилище кешей MemoryCacheManager: ключи с префиксами и множества тегов, как в Redis
+type memoryStore struct {
+	mu    sync.Mutex
+	items map[string]memoryItem
+	tags  map[string]map[string]struct{}
+	now   func() time.Time
+}
+
+func (s *memoryStore) get(key string) ([]byte, bool) {
+	item, ok := s.items[key]
+	if !ok {
+		return nil, false
+	}
+	if !item.expiresAt.IsZero() && !s.now().Before(item.expiresAt) {
+		delete(s.items, key)
+		return nil, false
+	}
+	return item.value, true
+}
+
+func (s *memoryStore) set(key string, value []byte, ttl time.Duration) {
+	item := memoryItem{value: value}
+	if ttl > 0 {
+		item.expiresAt = s.now().Add(ttl)
+	}
+	s.items[key] = item
+}
+
+// MemoryCache реализация Cache в памяти процесса с той же сериализацией, что у RedisCache.
+// Подходит для тестов и развертывания одним инстансом без Redis: данные не разделяются между процессами.
+type MemoryCache struct {
+	store  *memoryStore
+	prefix string
+	loads  singleflight.Group
+}
+
+// Get получает значение из кеша
+func (m *MemoryCache) Get(ctx context.Context, key string) (interface{}, error) {
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+
+	val, ok := m.store.get(m.fullKey(key))
+	if !ok {
+		return nil, nil
+	}
+	return decodeValue(val), nil
+}
+
+// Set устанавливает значение в кеш с TTL
+func (m *MemoryCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return m.SetWithTags(ctx, key, value, ttl)
+}
+
+// Delete удаляет значение из кеша
+func (m *MemoryCache) Delete(ctx context.Context, key string) error {
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+
+	delete(m.store.items, m.fullKey(key))
+	return nil
+}
+
+// DeleteMultiple удаляет несколько значений
+func (m *MemoryCache) DeleteMultiple(ctx context.Context, keys []string) error {
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+
+	for _, key := range keys {
+		delete(m.store.items, m.fullKey(key))
+	}
+	return nil
+}
+
+// Exists проверяет наличие ключа в кеше
+func (m *MemoryCache) Exists(ctx context.Context, key string) (bool, error) {
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+
+	_, ok := m.store.get(m.fullKey(key))
+	return ok, nil
+}
+
+// Clear удаляет все значения по glob-паттерну
+func (m *MemoryCache) Clear(ctx context.Context, pattern string) error {
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+
+	fullPattern := m.fullKey(pattern)
+	for key := range m.store.items {
+		if matched, _ := path.Match(fullPattern, key); matched {
+			delete(m.store.items, key)
+		}
+	}
+	return nil
+}
+
+// GetMultiple получает несколько значений; как и RedisCache, возвращает их сериализованными
+func (m *MemoryCache) GetMultiple(ctx context.Context, keys []string) (map[string]interface{}, error) {
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+
+	result := make(map[string]interface{})
+	for _, key := range keys {
+		if val, ok := m.store.get(m.fullKey(key)); ok {
+			result[key] = string(val)
+		}
+	}
+	return result, nil
+}
+
+// SetMultiple устанавливает несколько значений одновременно
+func (m *MemoryCache) SetMultiple(ctx context.Context, data map[string]interface{}, ttl time.Duration) error {
+	for key, value := range data {
+		if err := m.Set(ctx, key, value, ttl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetWithTags устанавливает значение и связывает ключ с тегами
+func (m *MemoryCache) SetWithTags(ctx context.Context, key string, value interface{}, ttl time.Duration, tags ...string) error {
+	jsonValue, err := json.Marshal(value)
+	if err != nil {
+		return apperror.New(apperror.ErrInternal, "cache marshal error")
+	}
+
+	m.setEncoded(key, jsonValue, ttl, tags...)
+	return nil
+}
+
+func (m *MemoryCache) setEncoded(key string, jsonValue []byte, ttl time.Duration, tags ...string) {
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+
+	fullKey := m.fullKey(key)
+	m.store.set(fullKey, jsonValue, ttl)
+	for _, tag := range tags {
+		if m.store.tags[tag] == nil {
+			m.store.tags[tag] = map[string]struct{}{}
+		}
+		m.store.tags[tag][fullKey] = struct{}{}
+	}
+}
+
+// GetOrLoad читает значение в out, а при промахе вызывает load один раз на ключ и кеширует результат
+func (m *MemoryCache) GetOrLoad(ctx context.Context, key string, out interface{}, ttl time.Duration, load LoadFunc, tags ...string) error {
+	if cached, err := m.Get(ctx, key); err == nil && cached != nil {
+		if err := Decode(cached, out); err == nil {
+			return nil
+		}
+	}
+
+	data, err, _ := m.loads.Do(m.fullKey(key), func() (interface{}, error) {
+		value, err := load(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, err
+		}
+
+		jsonValue, err := json.Marshal(value)
+		if err != nil {
+			return nil, apperror.New(apperror.ErrInternal, "cache marshal error")
+		}
+		m.setEncoded(key, jsonValue, ttl, tags...)
+		return jsonValue, nil
+	})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data.([]byte), out)
+}
+
+func (m *MemoryCache) fullKey(key string) string {
+	return m.prefix + ":" + key
+}
+
+// MemoryCacheManager реализация CacheManager в памяти процесса (см. MemoryCache)
+type MemoryCacheManager struct {
+	store        *memoryStore
+	userCache    *MemoryCache
+	orgCache     *MemoryCache
+	docCache     *MemoryCache
+	sessionCache *MemoryCache
+	tokenCache   *MemoryCache
+	genericCache *MemoryCache
+}
+
+// NewMemoryCacheManager создает менеджер кешей в памяти с теми же префиксами, что у RedisCacheManager
+func NewMemoryCacheManager() *MemoryCacheManager {
+	store := &memoryStore{
+		items: map[string]memoryItem{},
+		tags:  map[string]map[string]struct{}{},
+		now:   time.Now,
+	}
+	newCache := func(prefix string) *MemoryCache {
+		return &MemoryCache{store: store, prefix: prefix}
+	}
+	return &MemoryCacheManager{
+		store:        store,
+		userCache:    newCache("user"),
+		orgCache:     newCache("org"),
+		docCache:     newCache("doc"),
+		sessionCache: newCache("session"),
+		tokenCache:   newCache("token"),
+		genericCache: newCache("cache"),
+	}
+}
+
+// User возвращает кеш пользователей
+func (m *MemoryCacheManager) User() Cache {
+	return m.userCache
+}
+
+// Organization возвращает кеш организаций
+func (m *MemoryCacheManager) Organization() Cache {
+	return m.orgCache
+}
+
+// Document возвращает кеш документов
+func (m *MemoryCacheManager) Document() Cache {
+	return m.docCache
+}
+
+// Session возвращает кеш сессий
+func (m *MemoryCacheManager) Session() Cache {
+	return m.sessionCache
+}
+
+// Token возвращает кеш токенов
+func (m *MemoryCacheManager) Token() Cache {
+	return m.tokenCache
+}
+
+// Generic возвращает общий кеш
+func (m *MemoryCacheManager) Generic() Cache {
+	return m.genericCache
+}
+
+// InvalidateTags удаляет все ключи, связанные с тегами, во всех кешах
+func (m *MemoryCacheManager) InvalidateTags(ctx context.Context, tags ...string) error {
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+
+	for _, tag := range tags {
+		for key := range m.store.tags[tag] {
+			delete(m.store.items, key)
+		}
+		delete(m.store.tags, tag)
+	}
+	return nil
+}
+
+// Flush очищает все кеши
+func (m *MemoryCacheManager) Flush(ctx context.Context) error {
+	m.store.mu.Lock()
+	defer m.store.mu.Unlock()
+
+	m.store.items = map[string]memoryItem{}
+	m.store.tags = map[string]map[string]struct{}{}
+	return nil
+}
@@ -0,0 +1,90 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCacheManager(t *testing.T) {
+	ctx := context.Background()
+	m := NewMemoryCacheManager()
+	now := time.Unix(1700000000, 0)
+	m.store.now = func() time.Time { return now }
+
+	require.NoError(t, m.User().Set(ctx, "1", map[string]string{"name": "alice"}, time.Minute))
+	require.NoError(t, m.Organization().SetWithTags(ctx, "1", "org", 0, OrgTag("1"), TagOrganizations))
+	require.NoError(t, m.Generic().SetWithTags(ctx, "list", []int{1, 2}, 0, TagOrganizations))
+
+	// Значения возвращаются так же, как из Redis: распакованный JSON
+	value, err := m.User().Get(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"name": "alice"}, value)
+
+	// Префиксы разделяют кеши
+	value, err = m.Document().Get(ctx, "1")
+	require.NoError(t, err)
+	assert.Nil(t, value)
+
+	// Инвалидация по тегу затрагивает все кеши
+	require.NoError(t, m.InvalidateTags(ctx, TagOrganizations))
+	exists, _ := m.Organization().Exists(ctx, "1")
+	assert.False(t, exists)
+	exists, _ = m.Generic().Exists(ctx, "list")
+	assert.False(t, exists)
+
+	now = now.Add(2 * time.Minute)
+	value, err = m.User().Get(ctx, "1")
+	require.NoError(t, err)
+	assert.Nil(t, value, "expired entry must not be returned")
+}
+
+func TestMemoryCache_GetOrLoadAndClear(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCacheManager().Generic()
+
+	loads := 0
+	load := func(ctx context.Context) (interface{}, error) {
+		loads++
+		return []string{"KGS", "USD"}, nil
+	}
+
+	var out []string
+	require.NoError(t, c.GetOrLoad(ctx, "currencies", &out, time.Minute, load, TagReference))
+	require.NoError(t, c.GetOrLoad(ctx, "currencies", &out, time.Minute, load, TagReference))
+	assert.Equal(t, []string{"KGS", "USD"}, out)
+	assert.Equal(t, 1, loads)
+
+	require.NoError(t, c.Set(ctx, "rates:KGS", 1, 0))
+	require.NoError(t, c.Clear(ctx, "rates:*"))
+	exists, _ := c.Exists(ctx, "rates:KGS")
+	assert.False(t, exists)
+	exists, _ = c.Exists(ctx, "currencies")
+	assert.True(t, exists)
+}
+
+func TestGetMany_DecodesFoundKeys(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCacheManager().Document()
+
+	type doc struct {
+		Number string `json:"number"`
+	}
+	require.NoError(t, c.SetMultiple(ctx, map[string]interface{}{
+		"doc:id:1": doc{Number: "A-1"},
+		"doc:id:2": doc{Number: "A-2"},
+	}, time.Minute))
+	require.NoError(t, c.Set(ctx, "doc:id:3", "not a document", time.Minute))
+
+	docs, err := GetMany[doc](ctx, c, []string{"doc:id:1", "doc:id:2", "doc:id:3", "doc:id:4"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]doc{"doc:id:1": {Number: "A-1"}, "doc:id:2": {Number: "A-2"}}, docs)
+
+	require.NoError(t, c.DeleteMultiple(ctx, []string{"doc:id:1", "doc:id:2"}))
+	docs, err = GetMany[doc](ctx, c, []string{"doc:id:1", "doc:id:2"})
+	require.NoError(t, err)
+	assert.Empty(t, docs)
+}
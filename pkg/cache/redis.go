@@ -9,6 +9,7 @@ import (
 	"github.com/redis/go-redis/v9"
 	"github.com/rusgainew/tunduck-app/pkg/apperror"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/sync/singleflight"
 )
 
 // RedisCache реализация Cache с использованием Redis
@@ -16,6 +17,8 @@ type RedisCache struct {
 	client *redis.Client
 	logger *logrus.Logger
 	prefix string
+	// loads объединяет одновременные загрузки одного ключа (защита от cache stampede)
+	loads singleflight.Group
 }
 
 // NewRedisCache создает новый Redis кеш
@@ -29,9 +32,19 @@ func NewRedisCache(client *redis.Client, logger *logrus.Logger, prefix string) *
 
 // Get получает значение из кеша
 func (r *RedisCache) Get(ctx context.Context, key string) (interface{}, error) {
+	val, err := r.getRaw(ctx, key)
+	if err != nil || val == nil {
+		return nil, err
+	}
+
+	return decodeValue(val), nil
+}
+
+// getRaw получает сериализованное значение из кеша (nil при промахе)
+func (r *RedisCache) getRaw(ctx context.Context, key string) ([]byte, error) {
 	fullKey := r.getFullKey(key)
 
-	val, err := r.client.Get(ctx, fullKey).Result()
+	val, err := r.client.Get(ctx, fullKey).Bytes()
 	if err != nil {
 		if err == redis.Nil {
 			r.logger.WithFields(logrus.Fields{
@@ -49,14 +62,16 @@ func (r *RedisCache) Get(ctx context.Context, key string) (interface{}, error) {
 		"prefix": r.prefix,
 	}).Debug("Cache hit")
 
-	// Пытаемся распарсить JSON
+	return val, nil
+}
+
+// decodeValue распаковывает JSON; значение, не являющееся JSON, возвращается строкой
+func decodeValue(val []byte) interface{} {
 	var data interface{}
-	if err := json.Unmarshal([]byte(val), &data); err != nil {
-		// Если не JSON, возвращаем строку
-		return val, nil
+	if err := json.Unmarshal(val, &data); err != nil {
+		return string(val)
 	}
-
-	return data, nil
+	return data
 }
 
 // Set устанавливает значение в кеш с TTL
@@ -98,6 +113,27 @@ func (r *RedisCache) Delete(ctx context.Context, key string) error {
 	return nil
 }
 
+// DeleteMultiple удаляет несколько значений одной командой DEL
+func (r *RedisCache) DeleteMultiple(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	fullKeys := make([]string, len(keys))
+	for i, key := range keys {
+		fullKeys[i] = r.getFullKey(key)
+	}
+
+	if err := r.client.Del(ctx, fullKeys...).Err(); err != nil {
+		r.logger.WithError(err).WithField("count", len(keys)).Error("Failed to delete multiple values from cache")
+		return apperror.New(apperror.ErrInternal, "cache delete error")
+	}
+
+	r.logger.WithField("count", len(keys)).Debug("Multiple values deleted from cache")
+
+	return nil
+}
+
 // Exists проверяет наличие ключа в кеше
 func (r *RedisCache) Exists(ctx context.Context, key string) (bool, error) {
 	fullKey := r.getFullKey(key)
@@ -214,12 +250,14 @@ func (r *RedisCache) getFullKey(key string) string {
 type RedisCacheManager struct {
 	client       *redis.Client
 	logger       *logrus.Logger
-	userCache    *RedisCache
-	orgCache     *RedisCache
-	docCache     *RedisCache
-	sessionCache *RedisCache
-	tokenCache   *RedisCache
-	genericCache *RedisCache
+	userCache    Cache
+	orgCache     Cache
+	docCache     Cache
+	sessionCache Cache
+	tokenCache   Cache
+	genericCache Cache
+	// local локальные кеши (LRU) по префиксу, если включены
+	local *localInvalidator
 }
 
 // NewRedisCacheManager создает новый менеджер кешей
@@ -273,6 +311,10 @@ func (m *RedisCacheManager) Flush(ctx context.Context) error {
 		return apperror.New(apperror.ErrInternal, "cache flush error")
 	}
 
+	if m.local != nil {
+		m.local.invalidate(ctx, invalidationMessage{All: true})
+	}
+
 	m.logger.Info("All caches flushed")
 	return nil
 }
@@ -274,3 +274,44 @@ func TestCacheHelper_InvalidateUserCache(t *testing.T) {
 	require.NoError(t, err)
 	assert.False(t, exists)
 }
+
+func TestRedisCacheManager_InvalidateTags(t *testing.T) {
+	client, logger := setupTestRedis(t)
+	defer client.Close()
+
+	manager := NewRedisCacheManager(client, logger)
+	ctx := context.Background()
+
+	// Данные организации и списки в разных кешах связаны тегом org:1
+	err := manager.Organization().SetWithTags(ctx, "id:1", "org", 1*time.Hour, OrgTag("1"))
+	require.NoError(t, err)
+	err = manager.Organization().SetWithTags(ctx, "all", "list", 1*time.Hour, TagOrganizations)
+	require.NoError(t, err)
+	err = manager.Document().SetWithTags(ctx, "org:1:list", "docs", 1*time.Hour, OrgTag("1"), TagDocuments)
+	require.NoError(t, err)
+	err = manager.Organization().SetWithTags(ctx, "id:2", "other", 1*time.Hour, OrgTag("2"))
+	require.NoError(t, err)
+
+	err = manager.InvalidateTags(ctx, OrgTag("1"), TagOrganizations)
+	require.NoError(t, err)
+
+	for _, check := range []struct {
+		cache Cache
+		key   string
+	}{
+		{manager.Organization(), "id:1"},
+		{manager.Organization(), "all"},
+		{manager.Document(), "org:1:list"},
+	} {
+		exists, err := check.cache.Exists(ctx, check.key)
+		require.NoError(t, err)
+		assert.False(t, exists, check.key)
+	}
+
+	// Ключи других тегов не затронуты
+	exists, err := manager.Organization().Exists(ctx, "id:2")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	_ = manager.InvalidateTags(ctx, OrgTag("2"), TagDocuments)
+}
@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/sirupsen/logrus"
+)
+
+// Теги кеша, общие для сервисов
+const (
+	// TagOrganizations списки организаций
+	TagOrganizations = "organizations"
+	// TagDocuments списки документов
+	TagDocuments = "documents"
+	// TagReference справочные данные (валюты, налоговые коды)
+	TagReference = "reference"
+)
+
+// tagKeyPrefix префикс ключей Redis, хранящих множества ключей тега
+const tagKeyPrefix = "tag:"
+
+// OrgTag возвращает тег данных организации (org:{id})
+func OrgTag(orgID string) string {
+	return "org:" + orgID
+}
+
+// setWithTagsScript записывает значение и добавляет ключ в множества тегов.
+// TTL множества тега продлевается до TTL самого долгоживущего ключа.
+// KEYS[1] - ключ значения, KEYS[2..] - ключи тегов; ARGV[1] - значение, ARGV[2] - TTL в мс (0 - без TTL).
+var setWithTagsScript = redis.NewScript(`
+local ttl = tonumber(ARGV[2])
+if ttl > 0 then
+	redis.call("SET", KEYS[1], ARGV[1], "PX", ttl)
+else
+	redis.call("SET", KEYS[1], ARGV[1])
+end
+for i = 2, #KEYS do
+	local existed = redis.call("EXISTS", KEYS[i])
+	local current = redis.call("PTTL", KEYS[i])
+	redis.call("SADD", KEYS[i], KEYS[1])
+	if ttl == 0 then
+		redis.call("PERSIST", KEYS[i])
+	elseif existed == 0 or (current ~= -1 and current < ttl) then
+		redis.call("PEXPIRE", KEYS[i], ttl)
+	end
+end
+return 1
+`)
+
+// invalidateTagsScript атомарно удаляет все ключи тегов и сами теги.
+// KEYS - ключи тегов; возвращает список удаленных ключей значений.
+var invalidateTagsScript = redis.NewScript(`
+local deleted = {}
+for i = 1, #KEYS do
+	local members = redis.call("SMEMBERS", KEYS[i])
+	for j = 1, #members, 500 do
+		redis.call("DEL", unpack(members, j, math.min(j + 499, #members)))
+	end
+	for _, member in ipairs(members) do
+		table.insert(deleted, member)
+	end
+	redis.call("DEL", KEYS[i])
+end
+return deleted
+`)
+
+// SetWithTags устанавливает значение в кеш и связывает ключ с тегами
+func (r *RedisCache) SetWithTags(ctx context.Context, key string, value interface{}, ttl time.Duration, tags ...string) error {
+	if len(tags) == 0 {
+		return r.Set(ctx, key, value, ttl)
+	}
+
+	jsonValue, err := json.Marshal(value)
+	if err != nil {
+		r.logger.WithError(err).WithField("key", r.getFullKey(key)).Error("Failed to marshal cache value")
+		return apperror.New(apperror.ErrInternal, "cache marshal error")
+	}
+
+	return r.setEncoded(ctx, key, jsonValue, ttl, tags...)
+}
+
+// setEncoded записывает уже сериализованное значение и связывает ключ с тегами
+func (r *RedisCache) setEncoded(ctx context.Context, key string, jsonValue []byte, ttl time.Duration, tags ...string) error {
+	fullKey := r.getFullKey(key)
+
+	if len(tags) == 0 {
+		if err := r.client.Set(ctx, fullKey, jsonValue, ttl).Err(); err != nil {
+			r.logger.WithError(err).WithField("key", fullKey).Error("Failed to set cache")
+			return apperror.New(apperror.ErrInternal, "cache set error")
+		}
+		return nil
+	}
+
+	keys := make([]string, 0, len(tags)+1)
+	keys = append(keys, fullKey)
+	for _, tag := range tags {
+		keys = append(keys, tagKeyPrefix+tag)
+	}
+
+	if err := setWithTagsScript.Run(ctx, r.client, keys, jsonValue, ttl.Milliseconds()).Err(); err != nil {
+		r.logger.WithError(err).WithField("key", fullKey).Error("Failed to set tagged cache value")
+		return apperror.New(apperror.ErrInternal, "cache set error")
+	}
+
+	r.logger.WithFields(logrus.Fields{
+		"key":    key,
+		"prefix": r.prefix,
+		"ttl":    ttl,
+		"tags":   tags,
+	}).Debug("Tagged value set to cache")
+
+	return nil
+}
+
+// InvalidateTags атомарно удаляет все ключи, связанные с тегами, во всех кешах
+func (m *RedisCacheManager) InvalidateTags(ctx context.Context, tags ...string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+
+	keys := make([]string, len(tags))
+	for i, tag := range tags {
+		keys[i] = tagKeyPrefix + tag
+	}
+
+	deleted, err := invalidateTagsScript.Run(ctx, m.client, keys).StringSlice()
+	if err != nil {
+		m.logger.WithError(err).WithField("tags", tags).Error("Failed to invalidate cache tags")
+		return apperror.New(apperror.ErrInternal, "cache tag invalidation error")
+	}
+
+	if m.local != nil && len(deleted) > 0 {
+		m.local.invalidate(ctx, invalidationMessage{Keys: deleted})
+	}
+
+	m.logger.WithFields(logrus.Fields{
+		"tags":    tags,
+		"deleted": len(deleted),
+	}).Debug("Cache invalidated by tags")
+
+	return nil
+}
@@ -0,0 +1,271 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// invalidationChannel канал Redis pub/sub для инвалидации локальных кешей всех экземпляров
+const invalidationChannel = "cache:invalidate"
+
+// invalidationMessage сообщение об изменении ключей
+type invalidationMessage struct {
+	Origin  string   `json:"origin"`
+	Keys    []string `json:"keys,omitempty"`
+	Pattern string   `json:"pattern,omitempty"`
+	All     bool     `json:"all,omitempty"`
+}
+
+// localInvalidator хранит локальный LRU экземпляра и синхронизирует его
+// с другими экземплярами через Redis pub/sub
+type localInvalidator struct {
+	client *redis.Client
+	logger *logrus.Logger
+	origin string
+	lru    *lruCache
+}
+
+// invalidate удаляет ключи локально и оповещает другие экземпляры
+func (l *localInvalidator) invalidate(ctx context.Context, msg invalidationMessage) {
+	l.apply(msg)
+
+	msg.Origin = l.origin
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	if err := l.client.Publish(ctx, invalidationChannel, payload).Err(); err != nil {
+		l.logger.WithError(err).Warn("Failed to publish local cache invalidation")
+	}
+}
+
+// apply применяет сообщение к локальному LRU
+func (l *localInvalidator) apply(msg invalidationMessage) {
+	switch {
+	case msg.All:
+		l.lru.purge()
+	case msg.Pattern != "":
+		l.lru.removeMatching(msg.Pattern)
+	default:
+		l.lru.remove(msg.Keys...)
+	}
+}
+
+// listen получает сообщения об инвалидации от других экземпляров до отмены ctx
+func (l *localInvalidator) listen(ctx context.Context) {
+	pubsub := l.client.Subscribe(ctx, invalidationChannel)
+	defer pubsub.Close()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case m, ok := <-pubsub.Channel():
+			if !ok {
+				return
+			}
+			var msg invalidationMessage
+			if err := json.Unmarshal([]byte(m.Payload), &msg); err != nil {
+				l.logger.WithError(err).Warn("Invalid cache invalidation message")
+				continue
+			}
+			if msg.Origin == l.origin {
+				continue
+			}
+			l.apply(msg)
+		}
+	}
+}
+
+// TieredCache двухуровневый кеш: локальный LRU с коротким TTL перед Redis.
+// Изменения ключей через любой экземпляр инвалидируют локальные копии на всех экземплярах.
+type TieredCache struct {
+	remote *RedisCache
+	local  *localInvalidator
+}
+
+// Get получает значение из локального кеша, при промахе - из Redis
+func (t *TieredCache) Get(ctx context.Context, key string) (interface{}, error) {
+	fullKey := t.remote.getFullKey(key)
+	if val, ok := t.local.lru.get(fullKey); ok {
+		return decodeValue(val), nil
+	}
+
+	val, err := t.remote.getRaw(ctx, key)
+	if err != nil || val == nil {
+		return nil, err
+	}
+
+	t.local.lru.set(fullKey, val, 0)
+	return decodeValue(val), nil
+}
+
+// Set устанавливает значение в Redis и инвалидирует локальные копии
+func (t *TieredCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	if err := t.remote.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+	t.invalidateKeys(ctx, key)
+	return nil
+}
+
+// Delete удаляет значение из Redis и локальных кешей
+func (t *TieredCache) Delete(ctx context.Context, key string) error {
+	if err := t.remote.Delete(ctx, key); err != nil {
+		return err
+	}
+	t.invalidateKeys(ctx, key)
+	return nil
+}
+
+// Exists проверяет наличие ключа в локальном кеше или Redis
+func (t *TieredCache) Exists(ctx context.Context, key string) (bool, error) {
+	if _, ok := t.local.lru.get(t.remote.getFullKey(key)); ok {
+		return true, nil
+	}
+	return t.remote.Exists(ctx, key)
+}
+
+// Clear удаляет все значения по паттерну в Redis и локальных кешах
+func (t *TieredCache) Clear(ctx context.Context, pattern string) error {
+	if err := t.remote.Clear(ctx, pattern); err != nil {
+		return err
+	}
+	t.local.invalidate(ctx, invalidationMessage{Pattern: t.remote.getFullKey(pattern)})
+	return nil
+}
+
+// GetMultiple получает значения из локального кеша, а промахи - одним MGET из Redis
+func (t *TieredCache) GetMultiple(ctx context.Context, keys []string) (map[string]interface{}, error) {
+	result := make(map[string]interface{}, len(keys))
+	missing := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if val, ok := t.local.lru.get(t.remote.getFullKey(key)); ok {
+			result[key] = string(val)
+		} else {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	remote, err := t.remote.GetMultiple(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	for key, val := range remote {
+		result[key] = val
+		if s, ok := val.(string); ok {
+			t.local.lru.set(t.remote.getFullKey(key), []byte(s), 0)
+		}
+	}
+	return result, nil
+}
+
+// SetMultiple устанавливает несколько значений в Redis и инвалидирует локальные копии
+func (t *TieredCache) SetMultiple(ctx context.Context, data map[string]interface{}, ttl time.Duration) error {
+	if err := t.remote.SetMultiple(ctx, data, ttl); err != nil {
+		return err
+	}
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	t.invalidateKeys(ctx, keys...)
+	return nil
+}
+
+// DeleteMultiple удаляет значения из Redis и локальных кешей
+func (t *TieredCache) DeleteMultiple(ctx context.Context, keys []string) error {
+	if err := t.remote.DeleteMultiple(ctx, keys); err != nil {
+		return err
+	}
+	t.invalidateKeys(ctx, keys...)
+	return nil
+}
+
+// SetWithTags устанавливает значение с тегами в Redis и инвалидирует локальные копии
+func (t *TieredCache) SetWithTags(ctx context.Context, key string, value interface{}, ttl time.Duration, tags ...string) error {
+	if err := t.remote.SetWithTags(ctx, key, value, ttl, tags...); err != nil {
+		return err
+	}
+	t.invalidateKeys(ctx, key)
+	return nil
+}
+
+// GetOrLoad читает значение из локального кеша, при промахе - через RedisCache.GetOrLoad
+func (t *TieredCache) GetOrLoad(ctx context.Context, key string, out interface{}, ttl time.Duration, load LoadFunc, tags ...string) error {
+	fullKey := t.remote.getFullKey(key)
+	if val, ok := t.local.lru.get(fullKey); ok {
+		if err := json.Unmarshal(val, out); err == nil {
+			return nil
+		}
+	}
+
+	if err := t.remote.GetOrLoad(ctx, key, out, ttl, load, tags...); err != nil {
+		return err
+	}
+
+	if val, err := json.Marshal(out); err == nil {
+		t.local.lru.set(fullKey, val, ttl)
+	}
+	return nil
+}
+
+// invalidateKeys инвалидирует локальные копии ключей на всех экземплярах
+func (t *TieredCache) invalidateKeys(ctx context.Context, keys ...string) {
+	fullKeys := make([]string, len(keys))
+	for i, key := range keys {
+		fullKeys[i] = t.remote.getFullKey(key)
+	}
+	t.local.invalidate(ctx, invalidationMessage{Keys: fullKeys})
+}
+
+// EnableLocalCache включает локальный LRU перед Redis для кешей с указанными префиксами
+// (например "cache" для справочных данных, "org" для организаций).
+// size - максимальное число записей, ttl - максимальное время жизни локальной копии.
+// Подписка на инвалидацию работает до отмены ctx.
+func (m *RedisCacheManager) EnableLocalCache(ctx context.Context, size int, ttl time.Duration, prefixes ...string) {
+	if size <= 0 || ttl <= 0 || len(prefixes) == 0 {
+		return
+	}
+
+	m.local = &localInvalidator{
+		client: m.client,
+		logger: m.logger,
+		origin: uuid.NewString(),
+		lru:    newLRUCache(size, ttl),
+	}
+
+	caches := map[string]*Cache{
+		"user":    &m.userCache,
+		"org":     &m.orgCache,
+		"doc":     &m.docCache,
+		"session": &m.sessionCache,
+		"token":   &m.tokenCache,
+		"cache":   &m.genericCache,
+	}
+	for _, prefix := range prefixes {
+		c, ok := caches[prefix]
+		if !ok {
+			continue
+		}
+		if remote, ok := (*c).(*RedisCache); ok {
+			*c = &TieredCache{remote: remote, local: m.local}
+		}
+	}
+
+	go m.local.listen(ctx)
+
+	m.logger.WithFields(logrus.Fields{
+		"size":     size,
+		"ttl":      ttl,
+		"prefixes": prefixes,
+	}).Info("Local cache enabled")
+}
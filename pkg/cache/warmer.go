@@ -0,0 +1,171 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/pkg/lock"
+)
+
+// Warmer предварительно загружает данные в кеш
+type Warmer interface {
+	// Name имя warmer для логов и метрик
+	Name() string
+	// Warm загружает данные в кеш
+	Warm(ctx context.Context) error
+}
+
+// warmerFunc адаптер функции к интерфейсу Warmer
+type warmerFunc struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+// NewWarmer создает Warmer из функции
+func NewWarmer(name string, fn func(ctx context.Context) error) Warmer {
+	return &warmerFunc{name: name, fn: fn}
+}
+
+func (w *warmerFunc) Name() string {
+	return w.name
+}
+
+func (w *warmerFunc) Warm(ctx context.Context) error {
+	return w.fn(ctx)
+}
+
+// WarmupRunner параллельно выполняет зарегистрированные warmers
+type WarmupRunner struct {
+	warmers []Warmer
+	logger  *logrus.Logger
+	timeout time.Duration
+	// duration опционально, длительность прогрева по warmer и статусу
+	duration *prometheus.HistogramVec
+	// locker опционально, прогрев выполняет только экземпляр, захвативший блокировку lockName
+	locker   *lock.Locker
+	lockName string
+	lockTTL  time.Duration
+}
+
+// NewWarmupRunner создает WarmupRunner. timeout ограничивает время работы каждого warmer (0 - без ограничения).
+func NewWarmupRunner(logger *logrus.Logger, timeout time.Duration) *WarmupRunner {
+	return &WarmupRunner{
+		logger:  logger,
+		timeout: timeout,
+	}
+}
+
+// WithDurationMetric включает метрику длительности прогрева (labels: warmer, status)
+func (r *WarmupRunner) WithDurationMetric(duration *prometheus.HistogramVec) *WarmupRunner {
+	r.duration = duration
+	return r
+}
+
+// WithLock включает распределенную блокировку прогрева: пока один экземпляр прогревает общий кеш,
+// остальные пропускают прогрев. ttl — срок блокировки, она продлевается, пока прогрев идет.
+func (r *WarmupRunner) WithLock(locker *lock.Locker, name string, ttl time.Duration) *WarmupRunner {
+	r.locker = locker
+	r.lockName = name
+	r.lockTTL = ttl
+	return r
+}
+
+// Register добавляет warmers
+func (r *WarmupRunner) Register(warmers ...Warmer) {
+	r.warmers = append(r.warmers, warmers...)
+}
+
+// Run запускает все warmers одновременно и ждет их завершения.
+// Ошибка одного warmer не прерывает остальные; возвращается объединение всех ошибок.
+// С WithLock прогрев пропускается, если его уже выполняет другой экземпляр.
+func (r *WarmupRunner) Run(ctx context.Context) error {
+	if r.locker == nil {
+		return r.runAll(ctx)
+	}
+
+	var ran bool
+	err := r.locker.Do(ctx, r.lockName, r.lockTTL, func(ctx context.Context) error {
+		ran = true
+		return r.runAll(ctx)
+	})
+	switch {
+	case ran || err == nil:
+		return err
+	case errors.Is(err, lock.ErrNotAcquired):
+		r.logger.WithField("lock", r.lockName).Info("Cache warming skipped: another instance is warming the cache")
+		return nil
+	default:
+		r.logger.WithError(err).Warn("Cache warming lock unavailable, warming without it")
+		return r.runAll(ctx)
+	}
+}
+
+// runAll выполняет все warmers и пишет итог в лог
+func (r *WarmupRunner) runAll(ctx context.Context) error {
+	start := time.Now()
+	errs := make([]error, len(r.warmers))
+
+	var wg sync.WaitGroup
+	for i, w := range r.warmers {
+		wg.Add(1)
+		go func(i int, w Warmer) {
+			defer wg.Done()
+			errs[i] = r.runOne(ctx, w)
+		}(i, w)
+	}
+	wg.Wait()
+
+	err := errors.Join(errs...)
+	r.logger.WithFields(logrus.Fields{
+		"warmers":  len(r.warmers),
+		"duration": time.Since(start).String(),
+		"failed":   err != nil,
+	}).Info("Cache warming completed")
+
+	return err
+}
+
+// runOne выполняет один warmer с таймаутом и записывает метрику
+func (r *WarmupRunner) runOne(ctx context.Context, w Warmer) (err error) {
+	if r.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	defer func() {
+		// Паника в warmer не должна останавливать запуск приложения
+		if p := recover(); p != nil {
+			err = fmt.Errorf("warmer panicked: %v", p)
+		}
+
+		duration := time.Since(start)
+		status := "success"
+		if err != nil {
+			status = "error"
+			err = fmt.Errorf("warmer %s: %w", w.Name(), err)
+		}
+		if r.duration != nil {
+			r.duration.WithLabelValues(w.Name(), status).Observe(duration.Seconds())
+		}
+
+		entry := r.logger.WithFields(logrus.Fields{
+			"warmer":   w.Name(),
+			"duration": duration.String(),
+		})
+		if err != nil {
+			entry.WithError(err).Warn("Cache warmer failed")
+		} else {
+			entry.Debug("Cache warmer completed")
+		}
+	}()
+
+	return w.Warm(ctx)
+}
@@ -0,0 +1,93 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/pkg/lock"
+)
+
+func TestWarmupRunner_RunsConcurrently(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+
+	duration := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "cache_warming_duration_seconds"}, []string{"warmer", "status"})
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(duration)
+
+	runner := NewWarmupRunner(logger, time.Second).WithDurationMetric(duration)
+
+	var running, maxRunning int32
+	slow := func(ctx context.Context) error {
+		n := atomic.AddInt32(&running, 1)
+		for {
+			current := atomic.LoadInt32(&maxRunning)
+			if n <= current || atomic.CompareAndSwapInt32(&maxRunning, current, n) {
+				break
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil
+	}
+
+	runner.Register(
+		NewWarmer("a", slow),
+		NewWarmer("b", slow),
+		NewWarmer("failing", func(ctx context.Context) error { return errors.New("db down") }),
+		NewWarmer("panicking", func(ctx context.Context) error { panic("boom") }),
+	)
+
+	err := runner.Run(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "warmer failing: db down")
+	assert.Contains(t, err.Error(), "warmer panicking: warmer panicked: boom")
+	assert.Equal(t, int32(2), atomic.LoadInt32(&maxRunning))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+	assert.Len(t, families[0].GetMetric(), 4)
+}
+
+func TestWarmupRunner_Timeout(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+
+	runner := NewWarmupRunner(logger, 10*time.Millisecond)
+	runner.Register(NewWarmer("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}))
+
+	err := runner.Run(context.Background())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWarmupRunner_WarmsWithoutUnavailableLock(t *testing.T) {
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	defer client.Close()
+	runner := NewWarmupRunner(logger, time.Second).WithLock(lock.New(lock.Config{}, client), "cache:warmup", time.Second)
+
+	var warmed int32
+	runner.Register(NewWarmer("a", func(ctx context.Context) error {
+		atomic.AddInt32(&warmed, 1)
+		return nil
+	}))
+
+	// Недоступный Redis не отменяет прогрев
+	require.NoError(t, runner.Run(context.Background()))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&warmed))
+}
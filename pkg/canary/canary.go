@@ -0,0 +1,170 @@
+// Package canary направляет часть запросов на альтернативную реализацию сервиса (канареечный выкат).
+//
+// Router выбирает реализацию для каждого запроса: заголовок X-Canary: 1 принудительно выбирает
+// канареечную, X-Canary: 0 — основную, иначе канареечную получают Percent процентов организаций.
+// Процент считается по хешу ID организации, поэтому организация не переключается между реализациями
+// от запроса к запросу, на всех инстансах обслуживается одной и той же и остается в выкате при
+// увеличении процента. Запросы без организации распределяются случайно.
+//
+// Выбранный вариант хранится в Locals запроса (LocalsKey), реализации выбираются по нему в сервисах:
+//
+//	if canary.FromContext(ctx) == canary.VariantCanary { ... }
+package canary
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"math/rand/v2"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/rusgainew/tunduck-app/pkg/metrics"
+)
+
+const (
+	// HeaderCanary заголовок запроса: 1 — канареечная реализация, 0 — основная
+	HeaderCanary = "X-Canary"
+	// HeaderVariant заголовок ответа с вариантом, обслужившим запрос
+	HeaderVariant = "X-Canary-Variant"
+	// LocalsKey ключ варианта в fiber.Ctx.Locals; сервисы получают его из ctx.Context() запроса
+	LocalsKey = "canary_variant"
+)
+
+// Variant реализация, выбранная для запроса
+type Variant string
+
+const (
+	VariantPrimary Variant = "primary"
+	VariantCanary  Variant = "canary"
+)
+
+// DefaultRoutes маршруты сервиса документов, который выкатывается первым
+var DefaultRoutes = []string{"/api/esf-documents"}
+
+// Config параметры канареечного выката
+type Config struct {
+	Enabled bool
+	// Percent доля организаций на канареечной реализации от 0 до 100; 0 — только по заголовку
+	Percent float64
+	// Routes префиксы путей, для которых выбирается реализация
+	Routes     []string
+	Registerer prometheus.Registerer
+	// Rand возвращает число в [0, 1) для запросов без организации; по умолчанию rand.Float64
+	Rand func() float64
+}
+
+// Validate проверяет долю канареечного трафика
+func (c Config) Validate() error {
+	if c.Percent < 0 || c.Percent > 100 {
+		return fmt.Errorf("canary: percent must be between 0 and 100, got %g", c.Percent)
+	}
+	return nil
+}
+
+// Router выбирает реализацию для запросов
+type Router struct {
+	cfg      Config
+	requests *prometheus.CounterVec
+}
+
+// New создает Router
+func New(cfg Config) (*Router, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if len(cfg.Routes) == 0 {
+		cfg.Routes = DefaultRoutes
+	}
+	if cfg.Rand == nil {
+		cfg.Rand = rand.Float64
+	}
+	if cfg.Registerer == nil {
+		cfg.Registerer = prometheus.DefaultRegisterer
+	}
+
+	return &Router{
+		cfg: cfg,
+		requests: metrics.Register(cfg.Registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "canary_requests_total",
+			Help: "Total number of requests routed by canary rollout, by variant (primary, canary)",
+		}, []string{"variant"})),
+	}, nil
+}
+
+// Config возвращает параметры с подставленными значениями по умолчанию
+func (r *Router) Config() Config {
+	return r.cfg
+}
+
+// Matches сообщает, что для пути выбирается реализация
+func (r *Router) Matches(path string) bool {
+	for _, prefix := range r.cfg.Routes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Choose выбирает реализацию по значению заголовка X-Canary и ID организации (пустой — случайно)
+func (r *Router) Choose(header, orgID string) Variant {
+	variant := r.choose(header, orgID)
+	r.requests.WithLabelValues(string(variant)).Inc()
+	return variant
+}
+
+func (r *Router) choose(header, orgID string) Variant {
+	switch strings.TrimSpace(header) {
+	case "1", "true":
+		return VariantCanary
+	case "0", "false":
+		return VariantPrimary
+	}
+	if r.cfg.Percent <= 0 {
+		return VariantPrimary
+	}
+	if r.cfg.Percent >= 100 {
+		return VariantCanary
+	}
+
+	var point float64
+	if orgID == "" {
+		point = r.cfg.Rand() * 100
+	} else {
+		point = float64(bucket(orgID)) / 100
+	}
+	if point < r.cfg.Percent {
+		return VariantCanary
+	}
+	return VariantPrimary
+}
+
+// bucket номер организации в выкате от 0 до 9999: сотые доли процента
+func bucket(orgID string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte("canary:" + strings.ToLower(orgID)))
+	return h.Sum32() % 10000
+}
+
+type variantKey struct{}
+
+// WithVariant задает вариант вне HTTP-запроса, например в тестах и фоновых задачах
+func WithVariant(ctx context.Context, variant Variant) context.Context {
+	return context.WithValue(ctx, variantKey{}, variant)
+}
+
+// FromContext возвращает вариант запроса; без выбора — основная реализация
+func FromContext(ctx context.Context) Variant {
+	if ctx == nil {
+		return VariantPrimary
+	}
+	if variant, ok := ctx.Value(variantKey{}).(Variant); ok {
+		return variant
+	}
+	if variant, ok := ctx.Value(LocalsKey).(Variant); ok {
+		return variant
+	}
+	return VariantPrimary
+}
@@ -0,0 +1,79 @@
+package canary
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRouter_Header(t *testing.T) {
+	r, err := New(Config{Percent: 100, Registerer: prometheus.NewRegistry()})
+	require.NoError(t, err)
+
+	assert.Equal(t, VariantPrimary, r.Choose("0", "org-1"))
+	assert.Equal(t, VariantCanary, r.Choose("", "org-1"))
+
+	r, err = New(Config{Registerer: prometheus.NewRegistry()})
+	require.NoError(t, err)
+	assert.Equal(t, VariantCanary, r.Choose("1", ""))
+	assert.Equal(t, VariantPrimary, r.Choose("", "org-1"))
+	assert.Equal(t, float64(1), testutil.ToFloat64(r.requests.WithLabelValues("canary")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(r.requests.WithLabelValues("primary")))
+}
+
+func TestRouter_Percent(t *testing.T) {
+	r, err := New(Config{Percent: 20, Registerer: prometheus.NewRegistry()})
+	require.NoError(t, err)
+
+	canaries := 0
+	for i := 0; i < 1000; i++ {
+		orgID := fmt.Sprintf("org-%d", i)
+		variant := r.Choose("", orgID)
+		// Организация не переключается между реализациями от запроса к запросу
+		assert.Equal(t, variant, r.Choose("", orgID))
+		if variant == VariantCanary {
+			canaries++
+		}
+	}
+	assert.InDelta(t, 200, canaries, 50)
+
+	// Организация остается в выкате при увеличении процента
+	wider, err := New(Config{Percent: 50, Registerer: prometheus.NewRegistry()})
+	require.NoError(t, err)
+	for i := 0; i < 1000; i++ {
+		orgID := fmt.Sprintf("org-%d", i)
+		if r.Choose("", orgID) == VariantCanary {
+			assert.Equal(t, VariantCanary, wider.Choose("", orgID), orgID)
+		}
+	}
+}
+
+func TestRouter_WithoutOrganization(t *testing.T) {
+	point := 0.1
+	r, err := New(Config{Percent: 20, Rand: func() float64 { return point }, Registerer: prometheus.NewRegistry()})
+	require.NoError(t, err)
+
+	assert.Equal(t, VariantCanary, r.Choose("", ""))
+	point = 0.5
+	assert.Equal(t, VariantPrimary, r.Choose("", ""))
+}
+
+func TestRouter_Matches(t *testing.T) {
+	r, err := New(Config{Registerer: prometheus.NewRegistry()})
+	require.NoError(t, err)
+	assert.True(t, r.Matches("/api/esf-documents/1"))
+	assert.False(t, r.Matches("/api/contracts"))
+
+	_, err = New(Config{Percent: 101})
+	assert.Error(t, err)
+}
+
+func TestFromContext(t *testing.T) {
+	assert.Equal(t, VariantPrimary, FromContext(context.Background()))
+	assert.Equal(t, VariantCanary, FromContext(WithVariant(context.Background(), VariantCanary)))
+}
@@ -1,7 +1,10 @@
 package container
 
 import (
-	"github.com/go-playground/validator/v10"
+	"context"
+	"errors"
+	"time"
+
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
@@ -10,9 +13,30 @@ import (
 	repositorypostgres "github.com/rusgainew/tunduck-app/internal/repository/repository_postgres"
 	"github.com/rusgainew/tunduck-app/internal/services"
 	"github.com/rusgainew/tunduck-app/internal/services/service_impl"
+	"github.com/rusgainew/tunduck-app/pkg/antivirus"
 	"github.com/rusgainew/tunduck-app/pkg/cache"
+	"github.com/rusgainew/tunduck-app/pkg/esfgateway"
+	"github.com/rusgainew/tunduck-app/pkg/events"
+	"github.com/rusgainew/tunduck-app/pkg/exchangerates"
+	"github.com/rusgainew/tunduck-app/pkg/featureflag"
+	"github.com/rusgainew/tunduck-app/pkg/health"
+	"github.com/rusgainew/tunduck-app/pkg/jobs"
+	"github.com/rusgainew/tunduck-app/pkg/leader"
+	"github.com/rusgainew/tunduck-app/pkg/lifecycle"
+	"github.com/rusgainew/tunduck-app/pkg/lock"
 	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/mail"
+	"github.com/rusgainew/tunduck-app/pkg/metering"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/quota"
 	"github.com/rusgainew/tunduck-app/pkg/ratelimit"
+	"github.com/rusgainew/tunduck-app/pkg/realtime"
+	"github.com/rusgainew/tunduck-app/pkg/scheduler"
+	"github.com/rusgainew/tunduck-app/pkg/search"
+	"github.com/rusgainew/tunduck-app/pkg/siem"
+	"github.com/rusgainew/tunduck-app/pkg/storage"
+	"github.com/rusgainew/tunduck-app/pkg/transaction"
+	"github.com/rusgainew/tunduck-app/pkg/validation"
 )
 
 // Container управляет всеми зависимостями приложения
@@ -22,87 +46,841 @@ type Container struct {
 	logrus *logrus.Logger
 
 	// Database
-	db *gorm.DB
+	db        *gorm.DB
+	txManager lazy[transaction.TxManager]
 
 	// Redis
 	redisClient *redis.Client
 
 	// Cache
-	cacheManager cache.CacheManager
+	cacheManager lazy[cache.CacheManager]
 
 	// Rate Limiter
-	rateLimiter *ratelimit.RateLimiter
+	rateLimiter lazy[*ratelimit.RateLimiter]
+
+	// Распределенные блокировки
+	locker lazy[*lock.Locker]
+
+	// Repositories; создаются при первом обращении, WithXxxRepository подменяет реализацию
+	userRepository          lazy[repository.UserRepository]
+	docRepository           lazy[repository.EsfDocumentRepository]
+	orgRepository           lazy[repository.EsfOrganizationRepository]
+	referenceDataRepository lazy[repository.ReferenceDataRepository]
+	exchangeRateRepository  lazy[repository.ExchangeRateRepository]
+	auditLogRepository      lazy[repository.AuditLogRepository]
+	exportJobRepository     lazy[repository.ExportJobRepository]
+	inboundEventRepository  lazy[repository.InboundEventRepository]
+	deadLetterRepository    lazy[repository.DeadLetterRepository]
+	operationRepository     lazy[repository.OperationRepository]
+	delegationRepository    lazy[repository.DelegationRepository]
+	savedViewRepository     lazy[repository.SavedViewRepository]
+	usageRepository         lazy[repository.UsageRepository]
+	tenantMigrationRepo     lazy[repository.TenantMigrationRepository]
+	tenantCredentialRepo    lazy[repository.TenantCredentialRepository]
+
+	// Services; создаются при первом обращении
+	userService          lazy[services.UserService]
+	documentService      lazy[services.EsfDocumentService]
+	canaryDocuments      DocumentServiceFactory
+	orgService           lazy[services.EsfOrganizationService]
+	referenceDataService lazy[services.ReferenceDataService]
+	exchangeRateService  lazy[services.ExchangeRateService]
+	migrationService     lazy[services.MigrationService]
+	auditService         lazy[services.AuditService]
+	callbackService      lazy[services.CallbackService]
+	deadLetterService    lazy[services.DeadLetterService]
+	operationService     lazy[services.OperationService]
+	bankStatementService lazy[services.BankStatementService]
+	contractService      lazy[services.ContractService]
+	priceListService     lazy[services.PriceListService]
+	delegationService    lazy[services.DelegationService]
+	savedViewService     lazy[services.SavedViewService]
+	usageService         lazy[services.UsageService]
+	timezones            lazy[services.Timezones]
+	defaultTimezone      *time.Location
+
+	// Search (nil без OPENSEARCH_URL)
+	searchIndexer *service_impl.SearchIndexer
+
+	// Domain events
+	eventRelay *service_impl.EventRelay
+
+	// Realtime notifications (nil до EnableRealtime)
+	realtimeHub *realtime.Hub
+	wsConfig    realtime.ConnConfig
+
+	// Background jobs
+	jobManager *jobs.Manager
+	scheduler  *scheduler.Scheduler
+	elector    *leader.Elector
+
+	// Object storage; attachmentStorage проверяет загрузки антивирусом, если он настроен
+	storage           storage.Storage
+	attachmentStorage storage.Storage
+
+	// Email
+	emailDeliveryRepository lazy[repository.EmailDeliveryRepository]
+	emailService            services.EmailService
+	exportService           services.ExportService
+	trashService            services.DocumentTrashService
+	documentSchedule        services.DocumentScheduleService
+	tenantMigrationService  services.TenantMigrationService
+	tenantCredentials       services.TenantCredentialService
+	attachmentService       services.AttachmentService
+
+	// Квоты тарифных планов (nil до EnableQuotas)
+	quotaEnforcer *quota.Enforcer
+
+	// Учет потребления для выставления счетов (nil до EnableMetering)
+	meter *metering.Meter
+
+	// Наблюдение за БД организаций (nil до EnableTenantMonitor)
+	tenantMonitor *health.TenantMonitor
 
-	// Repositories
-	userRepository repository.UserRepository
-	docRepository  repository.EsfDocumentRepository
+	// Флаги функций (nil до EnableFeatureFlags)
+	featureFlags *featureflag.Flags
 
-	// Services
-	userService     services.UserService
-	documentService services.EsfDocumentService
+	// ESF gateway (nil до EnableESFGateway или WithESFGateway)
+	esfGateway esfgateway.Gateway
+
+	// Уровни логирования подсистем и их распространение на все инстансы
+	logLevels    lazy[*logger.Levels]
+	logLevelSync lazy[*logger.LevelSync]
+
+	// Кеш ответов GET (nil до EnableResponseCache)
+	responseCache *middleware.ResponseCache
+
+	// Компоненты с запуском и остановкой; останавливаются в обратном порядке регистрации
+	lifecycle *lifecycle.Manager
 
 	// Validators
-	validator *validator.Validate
+	validator *validation.Validator
 }
 
-// NewContainer создает и инициализирует контейнер зависимостей
-func NewContainer(db *gorm.DB, log *logrus.Logger, redisClient *redis.Client) *Container {
+// NewContainer создает контейнер зависимостей. Репозитории и сервисы создаются при первом
+// обращении, поэтому контейнер без БД и Redis (тесты, вспомогательные команды) не открывает соединений.
+//
+//	container.NewContainer(container.WithDatabase(db), container.WithLogger(log), container.WithRedis(client))
+//	container.NewContainer(container.WithLogger(log), container.WithCacheManager(cache.NewMemoryCacheManager()))
+func NewContainer(opts ...Option) *Container {
 	c := &Container{
-		db:           db,
-		logrus:       log,
-		logger:       logger.New(log),
-		validator:    validator.New(),
-		redisClient:  redisClient,
-		cacheManager: cache.NewRedisCacheManager(redisClient, log),
-		rateLimiter:  ratelimit.NewRateLimiter(redisClient),
+		validator: validation.Default(),
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.logrus == nil {
+		c.logrus = logrus.New()
+	}
+	c.logger = logger.New(c.logrus)
+	c.lifecycle = lifecycle.NewManager(c.logrus)
 
-	// Инициализируем repositories
-	c.initRepositories()
-
-	// Инициализируем services
-	c.initServices()
+	// БД и Redis регистрируются первыми, чтобы закрываться после всех использующих их компонентов
+	c.lifecycle.Register("postgres", lifecycle.Hook{OnStop: c.closeDatabase})
+	c.lifecycle.Register("redis", lifecycle.Hook{OnStop: c.closeRedis})
 
 	return c
 }
 
-// initRepositories инициализирует все repositories
-func (c *Container) initRepositories() {
-	c.userRepository = repositorypostgres.NewUserRepositoryPostgres(c.db, c.logrus)
-	c.docRepository = repositorypostgres.NewEsfDocumentRepositoryPostgres(c.db, c.logrus)
+// Manage регистрирует компонент (lifecycle.Starter и/или lifecycle.Stopper). Компоненты
+// запускаются в порядке регистрации и останавливаются в обратном, поэтому компонент
+// регистрируется после своих зависимостей.
+func (c *Container) Manage(name string, component interface{}) {
+	c.lifecycle.Register(name, component)
 }
 
-// initServices инициализирует все services
-func (c *Container) initServices() {
-	// Передаем DB через конструктор
-	c.userService = service_impl.NewUserService(c.userRepository, c.db, c.logrus)
-	c.documentService = service_impl.NewEsfDocumentService(c.docRepository, c.db, c.logrus)
+// Start запускает зарегистрированные компоненты
+func (c *Container) Start(ctx context.Context) error {
+	return c.lifecycle.Start(ctx)
+}
+
+// Stop останавливает компоненты в обратном порядке регистрации и закрывает Redis и БД последними
+func (c *Container) Stop(ctx context.Context) error {
+	return c.lifecycle.Stop(ctx)
+}
+
+func (c *Container) closeDatabase(ctx context.Context) error {
+	if c.db == nil {
+		return nil
+	}
+	sqlDB, err := c.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
 
-	// Установляем CacheManager в сервисы
-	if c.cacheManager != nil {
-		c.userService.SetCacheManager(c.cacheManager)
-		c.documentService.SetCacheManager(c.cacheManager)
-		// OrgService would be initialized here if added to container
-		// orgService.SetCacheManager(c.cacheManager)
+func (c *Container) closeRedis(ctx context.Context) error {
+	if c.redisClient == nil {
+		return nil
 	}
+	return c.redisClient.Close()
+}
+
+// EnableSearch включает индексацию документов в OpenSearch: запись событий в outbox,
+// поиск через индекс и фоновый индексатор (запускается вызывающей стороной)
+func (c *Container) EnableSearch(client *search.Client, interval time.Duration) {
+	c.GetEsfDocumentRepository().SetSearchOutbox(true)
+	c.GetEsfDocumentService().SetSearchClient(client)
+	c.searchIndexer = service_impl.NewSearchIndexer(c.GetEsfDocumentRepository(), c.GetEsfOrganizationRepository(), client, interval, c.logrus)
+}
+
+// EnableSIEM дублирует записи журнала аудита в SIEM по syslog; экспортер запускается вызывающей стороной
+func (c *Container) EnableSIEM(cfg siem.Config) (*siem.Exporter, error) {
+	exporter, err := siem.NewExporter(cfg, c.logrus)
+	if err != nil {
+		return nil, err
+	}
+	c.GetAuditService().SetExporter(exporter)
+	return exporter, nil
+}
+
+// EnableEvents создает ретранслятор outbox доменных событий в поток Redis (запускается вызывающей стороной)
+func (c *Container) EnableEvents(cfg events.Config) *service_impl.EventRelay {
+	var bus events.Publisher = events.NewRedisStreamBus(c.redisClient, cfg.Stream, cfg.StreamMaxLen)
+	if c.realtimeHub != nil {
+		// Смены статусов документов дополнительно рассылаются подписчикам /ws
+		bus = realtime.NewEventForwarder(bus, c.realtimeHub, c.logrus)
+	}
+	c.eventRelay = service_impl.NewEventRelay(c.GetEsfDocumentRepository(), c.GetEsfOrganizationRepository(), bus, cfg.RelayInterval, c.logrus)
+	return c.eventRelay
+}
+
+// EnableRealtime создает хаб уведомлений в реальном времени поверх Redis pub/sub
+// (запускается вызывающей стороной). Вызывается до EnableEvents.
+func (c *Container) EnableRealtime(cfg realtime.Config, wsCfg realtime.ConnConfig) *realtime.Hub {
+	c.realtimeHub = realtime.NewHub(c.redisClient, cfg, c.logrus)
+	c.wsConfig = wsCfg
+	return c.realtimeHub
+}
+
+// EnableJobs создает менеджер фоновых задач поверх Redis; воркеры запускаются вызывающей стороной.
+// Задачи, исчерпавшие попытки, сохраняются в очередь недоставленных сообщений, если cfg.OnDead не задан.
+func (c *Container) EnableJobs(cfg jobs.Config) *jobs.Manager {
+	if cfg.OnDead == nil {
+		cfg.OnDead = c.GetDeadLetterService().RecordJob
+	}
+	c.jobManager = jobs.NewManager(c.redisClient, cfg, c.logrus)
+	c.GetDeadLetterService().SetJobManager(c.jobManager)
+	return c.jobManager
+}
+
+// EnableScheduler создает планировщик периодических задач с блокировками в Redis;
+// задачи регистрируются и планировщик запускается вызывающей стороной
+func (c *Container) EnableScheduler(cfg scheduler.Config) *scheduler.Scheduler {
+	c.scheduler = scheduler.New(c.redisClient, cfg, c.logrus)
+	return c.scheduler
+}
+
+// EnableLeaderElection создает участника выборов лидера для работы в единственном экземпляре.
+// Лидерство хранится в Redis, а с usePostgres или без Redis — в advisory-блокировке основной БД.
+func (c *Container) EnableLeaderElection(cfg leader.Config, usePostgres bool) (*leader.Elector, error) {
+	var backend leader.Backend
+	if usePostgres || c.redisClient == nil {
+		if c.db == nil {
+			return nil, errors.New("leader election requires Redis or the main database")
+		}
+		sqlDB, err := c.db.DB()
+		if err != nil {
+			return nil, err
+		}
+		backend = leader.NewPostgresBackend(sqlDB)
+	} else {
+		backend = leader.NewRedisBackend(c.GetLocker())
+	}
+	c.elector = leader.New(backend, cfg, c.logrus)
+	return c.elector, nil
+}
+
+// EnableStorage создает объектное хранилище для вложений, выгрузок и резервных копий;
+// хранилище из WithStorage используется вместо cfg
+func (c *Container) EnableStorage(cfg storage.Config) (storage.Storage, error) {
+	if c.storage != nil {
+		return c.storage, nil
+	}
+	store, err := storage.New(cfg)
+	if err != nil {
+		return nil, err
+	}
+	c.storage = store
+	c.attachmentStorage = store
+	return store, nil
+}
+
+// EnableAntivirus включает проверку файлов, загружаемых пользователями, через clamd;
+// вызывается после EnableStorage
+func (c *Container) EnableAntivirus(cfg antivirus.Config) *antivirus.ClamdScanner {
+	scanner := antivirus.NewClamdScanner(cfg)
+	c.attachmentStorage = antivirus.NewGuard(c.storage, scanner, cfg, c.logrus)
+	return scanner
+}
+
+// EnableMail создает сервис писем поверх очереди фоновых задач; вызывается после EnableJobs
+// и до запуска воркеров, чтобы обработчик отправки был зарегистрирован
+func (c *Container) EnableMail(cfg mail.Config) (services.EmailService, error) {
+	renderer, err := mail.NewRenderer()
+	if err != nil {
+		return nil, err
+	}
+	sender := mail.NewSender(cfg, c.logrus)
+	c.emailService = service_impl.NewEmailService(c.GetEmailDeliveryRepository(), renderer, sender, c.jobManager, c.logrus)
+	return c.emailService, nil
+}
+
+// EnableExports создает сервис фоновых выгрузок; вызывается после EnableStorage и EnableJobs
+// и до запуска воркеров, чтобы обработчик выгрузки был зарегистрирован
+func (c *Container) EnableExports(cfg services.ExportConfig) (services.ExportService, error) {
+	exports, err := service_impl.NewExportService(
+		c.GetExportJobRepository(),
+		c.GetOperationService(),
+		c.GetEsfDocumentRepository(),
+		c.GetAuditLogRepository(),
+		c.storage,
+		c.jobManager,
+		cfg,
+		c.logrus,
+	)
+	if err != nil {
+		return nil, err
+	}
+	c.exportService = exports
+	return c.exportService, nil
+}
+
+// EnableDocumentSchedule создает сервис отложенной отправки документов и напоминаний о черновиках
+// и подключает напоминания к сервису документов. Вызывается после EnableJobs и EnableRealtime
+// и до запуска воркеров, чтобы обработчики задач были зарегистрированы.
+func (c *Container) EnableDocumentSchedule(cfg services.DocumentScheduleConfig) services.DocumentScheduleService {
+	c.documentSchedule = service_impl.NewDocumentScheduleService(
+		c.GetEsfDocumentRepository(),
+		c.GetEsfDocumentService(),
+		c.GetDelegationService(),
+		c.realtimeHub,
+		c.jobManager,
+		cfg,
+		c.logrus,
+	)
+	c.GetEsfDocumentService().SetDraftReminders(c.documentSchedule)
+	return c.documentSchedule
+}
+
+// EnableTrash создает сервис корзины документов; вызывается после EnableJobs
+// и до запуска воркеров, чтобы обработчик массовой обработки был зарегистрирован
+func (c *Container) EnableTrash(cfg services.TrashConfig) services.DocumentTrashService {
+	c.trashService = service_impl.NewDocumentTrashService(
+		c.GetEsfDocumentRepository(),
+		c.GetEsfOrganizationRepository(),
+		c.GetEsfDocumentService(),
+		c.GetOperationService(),
+		c.jobManager,
+		cfg,
+		c.logrus,
+	)
+	return c.trashService
+}
+
+// EnableTenantMigrations создает сервис миграции БД организаций; вызывается после EnableJobs
+// и до запуска воркеров, чтобы обработчик миграции был зарегистрирован
+func (c *Container) EnableTenantMigrations(cfg services.TenantMigrationConfig) services.TenantMigrationService {
+	c.tenantMigrationService = service_impl.NewTenantMigrationService(
+		c.GetTenantMigrationRepository(),
+		c.GetEsfOrganizationRepository(),
+		c.GetOperationService(),
+		c.jobManager,
+		cfg,
+		c.logrus,
+	)
+	return c.tenantMigrationService
+}
+
+// EnableTenantCredentials создает сервис ротации учетных данных БД организаций; вызывается после
+// EnableJobs и до запуска воркеров. pools переподключаются с новыми учетными данными.
+func (c *Container) EnableTenantCredentials(cfg services.TenantCredentialConfig, pools services.OrganizationDBService) services.TenantCredentialService {
+	c.tenantCredentials = service_impl.NewTenantCredentialService(
+		c.GetTenantCredentialRepository(),
+		c.GetEsfOrganizationRepository(),
+		pools,
+		c.GetOperationService(),
+		c.jobManager,
+		cfg,
+		c.logrus,
+	)
+	return c.tenantCredentials
+}
+
+// EnableAttachments создает сервис вложений с подписанными ссылками на скачивание;
+// вызывается после EnableStorage и EnableAntivirus, чтобы загрузки проверялись антивирусом
+func (c *Container) EnableAttachments(cfg services.AttachmentConfig) (services.AttachmentService, error) {
+	attachments, err := service_impl.NewAttachmentService(c.attachmentStorage, cfg, c.logrus)
+	if err != nil {
+		return nil, err
+	}
+	c.attachmentService = attachments
+	return c.attachmentService, nil
+}
+
+// EnableQuotas включает проверку квот тарифных планов при создании документов и загрузке
+// вложений; вызывается после EnableStorage, чтобы учитывался размер вложений
+func (c *Container) EnableQuotas(cfg quota.Config) (*quota.Enforcer, error) {
+	usage := service_impl.NewQuotaUsage(c.GetEsfDocumentRepository(), c.GetEsfOrganizationService(), c.attachmentStorage)
+	// Расход кешируется в Redis, чтобы реплики не превышали лимит, не видя расход друг друга
+	if cfg.Cache == nil && c.redisClient != nil {
+		cfg.Cache = quota.NewRedisUsageCache(c.redisClient, "")
+	}
+	enforcer, err := quota.NewEnforcer(cfg, usage, usage)
+	if err != nil {
+		return nil, err
+	}
+	c.quotaEnforcer = enforcer
+	return enforcer, nil
+}
+
+// EnableMetering включает учет отправленных документов, загруженных байт и запросов API;
+// вызывается после EnableAttachments. Запись в БД (Run) запускается вызывающей стороной.
+func (c *Container) EnableMetering(cfg metering.Config) *metering.Meter {
+	c.meter = metering.NewMeter(c.GetUsageRepository(), cfg, c.logrus)
+	// Накопленный учет записывается в БД при остановке
+	c.lifecycle.Register("metering", lifecycle.Hook{OnStop: c.meter.Flush})
+	c.GetEsfDocumentService().SetMeter(c.meter)
+	if c.attachmentService != nil {
+		c.attachmentService.SetMeter(c.meter)
+	}
+	return c.meter
+}
+
+// EnableFeatureFlags создает набор флагов функций, синхронизируемый через Redis
+// (запускается вызывающей стороной)
+func (c *Container) EnableFeatureFlags(cfg featureflag.Config) *featureflag.Flags {
+	c.featureFlags = featureflag.NewFlags(c.redisClient, cfg, service_impl.NewFlagSubjects(c.GetEsfOrganizationService()), c.logrus)
+	return c.featureFlags
+}
+
+// EnableTenantMonitor создает наблюдение за БД организаций (запускается вызывающей стороной)
+func (c *Container) EnableTenantMonitor(cfg health.TenantMonitorConfig) *health.TenantMonitor {
+	c.tenantMonitor = health.NewTenantMonitor(
+		service_impl.TenantHealthTargets(c.GetEsfOrganizationRepository()),
+		service_impl.CheckTenantDatabase,
+		cfg,
+		c.logrus,
+	)
+	return c.tenantMonitor
+}
+
+// EnableResponseCache создает кеш ответов GET поверх общего кеша; записи инвалидируются
+// по тем же тегам, что и данные сервисов
+func (c *Container) EnableResponseCache(cfg middleware.ResponseCacheConfig) *middleware.ResponseCache {
+	c.responseCache = middleware.NewResponseCache(c.GetCacheManager(), cfg, c.logrus)
+	return c.responseCache
+}
+
+// EnableESFGateway создает клиент шлюза ЭСФ (или его имитацию для разработки); шлюз из
+// WithESFGateway используется вместо cfg. Адрес, выбранный администратором, хранится в Redis
+// и применяется на всех инстансах (Failover.Run).
+func (c *Container) EnableESFGateway(cfg esfgateway.Config) (esfgateway.Gateway, error) {
+	gw := c.esfGateway
+	if gw == nil {
+		var err error
+		if gw, err = esfgateway.New(cfg); err != nil {
+			return nil, err
+		}
+	}
+	if failover, ok := gw.(*esfgateway.Failover); ok && c.redisClient != nil {
+		failover.SetOverrideStore(esfgateway.NewRedisOverrideStore(c.redisClient, ""))
+	}
+	c.esfGateway = gw
+	c.GetReferenceDataService().SetGateway(gw)
+	c.GetEsfDocumentService().SetGateway(gw, c.GetEsfOrganizationRepository())
+	return gw, nil
 }
 
 // Getters для repositories
 func (c *Container) GetUserRepository() repository.UserRepository {
-	return c.userRepository
+	return c.userRepository.get(func() repository.UserRepository {
+		return repositorypostgres.NewUserRepositoryPostgres(c.db, c.logrus)
+	})
 }
 
 func (c *Container) GetEsfDocumentRepository() repository.EsfDocumentRepository {
-	return c.docRepository
+	return c.docRepository.get(func() repository.EsfDocumentRepository {
+		return repositorypostgres.NewEsfDocumentRepositoryPostgres(c.db, c.logrus)
+	})
+}
+
+// GetEsfOrganizationRepository возвращает репозиторий организаций
+func (c *Container) GetEsfOrganizationRepository() repository.EsfOrganizationRepository {
+	return c.orgRepository.get(func() repository.EsfOrganizationRepository {
+		return repositorypostgres.NewEsfOrganizationRepositoryPostgres(c.db, c.logrus)
+	})
+}
+
+// GetReferenceDataRepository возвращает репозиторий справочников
+func (c *Container) GetReferenceDataRepository() repository.ReferenceDataRepository {
+	return c.referenceDataRepository.get(func() repository.ReferenceDataRepository {
+		return repositorypostgres.NewReferenceDataRepositoryPostgres(c.db, c.logrus)
+	})
+}
+
+// GetExchangeRateRepository возвращает репозиторий курсов валют
+func (c *Container) GetExchangeRateRepository() repository.ExchangeRateRepository {
+	return c.exchangeRateRepository.get(func() repository.ExchangeRateRepository {
+		return repositorypostgres.NewExchangeRateRepositoryPostgres(c.db, c.logrus)
+	})
+}
+
+// GetEmailDeliveryRepository возвращает репозиторий доставки писем
+func (c *Container) GetEmailDeliveryRepository() repository.EmailDeliveryRepository {
+	return c.emailDeliveryRepository.get(func() repository.EmailDeliveryRepository {
+		return repositorypostgres.NewEmailDeliveryRepositoryPostgres(c.db, c.logrus)
+	})
+}
+
+// GetAuditLogRepository возвращает репозиторий журнала аудита
+func (c *Container) GetAuditLogRepository() repository.AuditLogRepository {
+	return c.auditLogRepository.get(func() repository.AuditLogRepository {
+		return repositorypostgres.NewAuditLogRepositoryPostgres(c.db, c.logrus)
+	})
+}
+
+// GetExportJobRepository возвращает репозиторий выгрузок
+func (c *Container) GetExportJobRepository() repository.ExportJobRepository {
+	return c.exportJobRepository.get(func() repository.ExportJobRepository {
+		return repositorypostgres.NewExportJobRepositoryPostgres(c.db, c.logrus)
+	})
+}
+
+// GetInboundEventRepository возвращает репозиторий входящих событий шлюза
+func (c *Container) GetInboundEventRepository() repository.InboundEventRepository {
+	return c.inboundEventRepository.get(func() repository.InboundEventRepository {
+		return repositorypostgres.NewInboundEventRepositoryPostgres(c.db, c.logrus)
+	})
+}
+
+// GetDeadLetterRepository возвращает репозиторий недоставленных сообщений
+func (c *Container) GetDeadLetterRepository() repository.DeadLetterRepository {
+	return c.deadLetterRepository.get(func() repository.DeadLetterRepository {
+		return repositorypostgres.NewDeadLetterRepositoryPostgres(c.db, c.logrus)
+	})
+}
+
+// GetOperationRepository возвращает репозиторий длительных операций
+func (c *Container) GetOperationRepository() repository.OperationRepository {
+	return c.operationRepository.get(func() repository.OperationRepository {
+		return repositorypostgres.NewOperationRepositoryPostgres(c.db, c.logrus)
+	})
+}
+
+// GetTenantMigrationRepository возвращает репозиторий результатов миграции БД организаций
+func (c *Container) GetTenantMigrationRepository() repository.TenantMigrationRepository {
+	return c.tenantMigrationRepo.get(func() repository.TenantMigrationRepository {
+		return repositorypostgres.NewTenantMigrationRepositoryPostgres(c.db, c.logrus)
+	})
+}
+
+// GetTenantCredentialRepository возвращает репозиторий учетных данных БД организаций
+func (c *Container) GetTenantCredentialRepository() repository.TenantCredentialRepository {
+	return c.tenantCredentialRepo.get(func() repository.TenantCredentialRepository {
+		return repositorypostgres.NewTenantCredentialRepositoryPostgres(c.db, c.logrus)
+	})
+}
+
+// GetDelegationRepository возвращает репозиторий делегирования права подписи
+func (c *Container) GetDelegationRepository() repository.DelegationRepository {
+	return c.delegationRepository.get(func() repository.DelegationRepository {
+		return repositorypostgres.NewDelegationRepositoryPostgres(c.db, c.logrus)
+	})
+}
+
+// GetSavedViewRepository возвращает репозиторий представлений списка документов
+func (c *Container) GetSavedViewRepository() repository.SavedViewRepository {
+	return c.savedViewRepository.get(func() repository.SavedViewRepository {
+		return repositorypostgres.NewSavedViewRepositoryPostgres(c.db, c.logrus)
+	})
+}
+
+// GetUsageRepository возвращает репозиторий учета потребления
+func (c *Container) GetUsageRepository() repository.UsageRepository {
+	return c.usageRepository.get(func() repository.UsageRepository {
+		return repositorypostgres.NewUsageRepositoryPostgres(c.db, c.logrus)
+	})
 }
 
 // Getters для services
 func (c *Container) GetUserService() services.UserService {
-	return c.userService
+	return c.userService.get(func() services.UserService {
+		svc := service_impl.NewUserService(c.GetUserRepository(), c.db, c.logrus)
+		svc.SetCacheManager(c.GetCacheManager())
+		return svc
+	})
 }
 
+// GetEsfDocumentService возвращает сервис документов; с WithCanaryDocumentService — сервис, передающий
+// запросы канареечного варианта (canary.FromContext) новой реализации
 func (c *Container) GetEsfDocumentService() services.EsfDocumentService {
-	return c.documentService
+	return c.documentService.get(func() services.EsfDocumentService {
+		svc := c.newEsfDocumentService(service_impl.NewEsfDocumentService)
+		if c.canaryDocuments == nil {
+			return svc
+		}
+		return service_impl.NewCanaryEsfDocumentService(svc, c.newEsfDocumentService(c.canaryDocuments))
+	})
+}
+
+// HasCanaryDocumentService сообщает, что зарегистрирована канареечная реализация сервиса документов
+func (c *Container) HasCanaryDocumentService() bool {
+	return c.canaryDocuments != nil
+}
+
+func (c *Container) newEsfDocumentService(factory DocumentServiceFactory) services.EsfDocumentService {
+	svc := factory(c.GetEsfDocumentRepository(), c.db, c.logrus)
+	svc.SetCacheManager(c.GetCacheManager())
+	svc.SetExchangeRates(c.GetExchangeRateService())
+	svc.SetTimezones(c.GetTimezones())
+	if c.esfGateway != nil {
+		svc.SetGateway(c.esfGateway, c.GetEsfOrganizationRepository())
+	}
+	return svc
+}
+
+func (c *Container) GetEsfOrganizationService() services.EsfOrganizationService {
+	return c.orgService.get(func() services.EsfOrganizationService {
+		svc := service_impl.NewEsfOrganizationService(c.GetEsfOrganizationRepository(), c.logrus)
+		svc.SetCacheManager(c.GetCacheManager())
+		return svc
+	})
+}
+
+func (c *Container) GetReferenceDataService() services.ReferenceDataService {
+	return c.referenceDataService.get(func() services.ReferenceDataService {
+		svc := service_impl.NewReferenceDataService(c.GetReferenceDataRepository(), c.logrus)
+		svc.SetCacheManager(c.GetCacheManager())
+		if c.esfGateway != nil {
+			svc.SetGateway(c.esfGateway)
+		}
+		return svc
+	})
+}
+
+// GetExchangeRateService возвращает сервис курсов валют
+func (c *Container) GetExchangeRateService() services.ExchangeRateService {
+	return c.exchangeRateService.get(func() services.ExchangeRateService {
+		svc := service_impl.NewExchangeRateService(c.GetExchangeRateRepository(), exchangerates.NewClient("", 0), c.logrus)
+		svc.SetCacheManager(c.GetCacheManager())
+		return svc
+	})
+}
+
+func (c *Container) GetMigrationService() services.MigrationService {
+	return c.migrationService.get(func() services.MigrationService {
+		return service_impl.NewMigrationService(c.db, c.GetEsfOrganizationRepository(), c.logrus)
+	})
+}
+
+func (c *Container) GetAuditService() services.AuditService {
+	return c.auditService.get(func() services.AuditService {
+		return service_impl.NewAuditService(c.GetAuditLogRepository(), c.logrus)
+	})
+}
+
+func (c *Container) GetCallbackService() services.CallbackService {
+	return c.callbackService.get(func() services.CallbackService {
+		return service_impl.NewCallbackService(c.GetInboundEventRepository(), c.GetEsfDocumentService(), c.logrus)
+	})
+}
+
+func (c *Container) GetDeadLetterService() services.DeadLetterService {
+	return c.deadLetterService.get(func() services.DeadLetterService {
+		return service_impl.NewDeadLetterService(c.GetDeadLetterRepository(), c.logrus)
+	})
+}
+
+// GetOperationService возвращает сервис длительных операций
+func (c *Container) GetOperationService() services.OperationService {
+	return c.operationService.get(func() services.OperationService {
+		return service_impl.NewOperationService(c.GetOperationRepository(), c.logrus)
+	})
+}
+
+// GetBankStatementService возвращает сервис сверки банковских выписок
+func (c *Container) GetBankStatementService() services.BankStatementService {
+	return c.bankStatementService.get(func() services.BankStatementService {
+		return service_impl.NewBankStatementService(c.GetEsfDocumentRepository(), c.GetCacheManager(), c.logrus)
+	})
+}
+
+// GetContractService возвращает сервис справочника договоров
+func (c *Container) GetContractService() services.ContractService {
+	return c.contractService.get(func() services.ContractService {
+		return service_impl.NewContractService(c.GetEsfDocumentRepository(), c.GetTimezones(), c.logrus)
+	})
+}
+
+// GetTimezones возвращает часовые пояса организаций
+func (c *Container) GetTimezones() services.Timezones {
+	return c.timezones.get(func() services.Timezones {
+		return service_impl.NewOrganizationTimezones(c.GetEsfOrganizationService(), c.defaultTimezone, c.logrus)
+	})
+}
+
+// GetPriceListService возвращает сервис прайс-листов
+func (c *Container) GetPriceListService() services.PriceListService {
+	return c.priceListService.get(func() services.PriceListService {
+		return service_impl.NewPriceListService(c.GetEsfDocumentRepository(), c.logrus)
+	})
+}
+
+// GetDelegationService возвращает сервис делегирования права подписи
+func (c *Container) GetDelegationService() services.DelegationService {
+	return c.delegationService.get(func() services.DelegationService {
+		return service_impl.NewDelegationService(c.GetDelegationRepository(), c.GetUserRepository(), c.logrus)
+	})
+}
+
+// GetSavedViewService возвращает сервис представлений списка документов
+func (c *Container) GetSavedViewService() services.SavedViewService {
+	return c.savedViewService.get(func() services.SavedViewService {
+		return service_impl.NewSavedViewService(c.GetSavedViewRepository(), c.logrus)
+	})
+}
+
+// GetSearchIndexer возвращает индексатор документов или nil, если OpenSearch не настроен
+func (c *Container) GetSearchIndexer() *service_impl.SearchIndexer {
+	return c.searchIndexer
+}
+
+// GetEventRelay возвращает ретранслятор доменных событий или nil до вызова EnableEvents
+func (c *Container) GetEventRelay() *service_impl.EventRelay {
+	return c.eventRelay
+}
+
+// GetEventReplayService возвращает повторную отправку доменных событий или nil до вызова EnableEvents
+func (c *Container) GetEventReplayService() services.EventReplayService {
+	if c.eventRelay == nil {
+		return nil
+	}
+	return c.eventRelay
+}
+
+// GetRealtimeHub возвращает хаб уведомлений или nil до вызова EnableRealtime
+func (c *Container) GetRealtimeHub() *realtime.Hub {
+	return c.realtimeHub
+}
+
+// GetWebSocketConfig возвращает параметры соединений /ws
+func (c *Container) GetWebSocketConfig() realtime.ConnConfig {
+	return c.wsConfig
+}
+
+// GetJobManager возвращает менеджер фоновых задач или nil до вызова EnableJobs
+func (c *Container) GetJobManager() *jobs.Manager {
+	return c.jobManager
+}
+
+// GetScheduler возвращает планировщик периодических задач или nil до вызова EnableScheduler
+func (c *Container) GetScheduler() *scheduler.Scheduler {
+	return c.scheduler
+}
+
+// GetLeaderElector возвращает участника выборов лидера; nil до EnableLeaderElection
+func (c *Container) GetLeaderElector() *leader.Elector {
+	return c.elector
+}
+
+// GetStorage возвращает объектное хранилище или nil до вызова EnableStorage
+func (c *Container) GetStorage() storage.Storage {
+	return c.storage
+}
+
+// GetAttachmentStorage возвращает хранилище для файлов пользователей: с проверкой
+// антивирусом после EnableAntivirus, иначе то же, что GetStorage
+func (c *Container) GetAttachmentStorage() storage.Storage {
+	return c.attachmentStorage
+}
+
+// GetEmailService возвращает сервис писем или nil до вызова EnableMail
+func (c *Container) GetEmailService() services.EmailService {
+	return c.emailService
+}
+
+// GetExportService возвращает сервис выгрузок или nil до вызова EnableExports
+func (c *Container) GetExportService() services.ExportService {
+	return c.exportService
+}
+
+// GetDocumentScheduleService возвращает сервис отложенной отправки документов или nil до вызова EnableDocumentSchedule
+func (c *Container) GetDocumentScheduleService() services.DocumentScheduleService {
+	return c.documentSchedule
+}
+
+// GetDocumentTrashService возвращает сервис корзины документов или nil до вызова EnableTrash
+func (c *Container) GetDocumentTrashService() services.DocumentTrashService {
+	return c.trashService
+}
+
+// GetTenantMigrationService возвращает сервис миграции БД организаций или nil до вызова EnableTenantMigrations
+func (c *Container) GetTenantMigrationService() services.TenantMigrationService {
+	return c.tenantMigrationService
+}
+
+// GetTenantCredentialService возвращает сервис ротации учетных данных или nil до вызова EnableTenantCredentials
+func (c *Container) GetTenantCredentialService() services.TenantCredentialService {
+	return c.tenantCredentials
+}
+
+// GetAttachmentService возвращает сервис вложений или nil до вызова EnableAttachments
+func (c *Container) GetAttachmentService() services.AttachmentService {
+	return c.attachmentService
+}
+
+// GetQuotaEnforcer возвращает проверку квот или nil, если квоты выключены
+func (c *Container) GetQuotaEnforcer() *quota.Enforcer {
+	return c.quotaEnforcer
+}
+
+// GetMeter возвращает учет потребления или nil, если он выключен
+func (c *Container) GetMeter() *metering.Meter {
+	return c.meter
+}
+
+// GetUsageService возвращает сервис отчетов о потреблении
+func (c *Container) GetUsageService() services.UsageService {
+	return c.usageService.get(func() services.UsageService {
+		return service_impl.NewUsageService(c.GetUsageRepository(), c.GetEsfOrganizationRepository(), c.logrus)
+	})
+}
+
+// GetTenantMonitor возвращает наблюдение за БД организаций или nil до вызова EnableTenantMonitor
+func (c *Container) GetTenantMonitor() *health.TenantMonitor {
+	return c.tenantMonitor
+}
+
+// GetFeatureFlags возвращает флаги функций или nil, если они не созданы
+func (c *Container) GetFeatureFlags() *featureflag.Flags {
+	return c.featureFlags
+}
+
+// GetLogLevels возвращает уровни логирования подсистем; без WithLogLevels — реестр поверх
+// общего логгера без выборки отладочных записей
+func (c *Container) GetLogLevels() *logger.Levels {
+	return c.logLevels.get(func() *logger.Levels {
+		return logger.NewLevels(c.logrus, logger.Config{Level: c.logrus.GetLevel()})
+	})
+}
+
+// GetLogLevelSync возвращает общие для инстансов уровни логирования; без Redis — только этого инстанса
+func (c *Container) GetLogLevelSync() *logger.LevelSync {
+	return c.logLevelSync.get(func() *logger.LevelSync {
+		return logger.NewLevelSync(c.redisClient, c.GetLogLevels(), logger.LevelSyncConfig{}, c.logrus)
+	})
+}
+
+// GetResponseCache возвращает кеш ответов или nil до вызова EnableResponseCache
+func (c *Container) GetResponseCache() *middleware.ResponseCache {
+	return c.responseCache
+}
+
+// GetESFGateway возвращает шлюз ЭСФ или nil до вызова EnableESFGateway
+func (c *Container) GetESFGateway() esfgateway.Gateway {
+	return c.esfGateway
 }
 
 // Getters для других компонентов
@@ -118,16 +896,41 @@ func (c *Container) GetDatabase() *gorm.DB {
 	return c.db
 }
 
-func (c *Container) GetValidator() *validator.Validate {
+// GetTxManager возвращает менеджер транзакций для атомарных операций нескольких репозиториев
+func (c *Container) GetTxManager() transaction.TxManager {
+	return c.txManager.get(func() transaction.TxManager {
+		return transaction.NewTxManager(c.db, c.logrus)
+	})
+}
+
+func (c *Container) GetValidator() *validation.Validator {
 	return c.validator
 }
 
+// GetCacheManager возвращает кеш сервисов: из WithCacheManager, иначе в Redis, а без Redis — в памяти процесса
 func (c *Container) GetCacheManager() cache.CacheManager {
-	return c.cacheManager
+	return c.cacheManager.get(func() cache.CacheManager {
+		if c.redisClient == nil {
+			return cache.NewMemoryCacheManager()
+		}
+		return cache.NewRedisCacheManager(c.redisClient, c.logrus)
+	})
 }
 
 func (c *Container) GetRateLimiter() *ratelimit.RateLimiter {
-	return c.rateLimiter
+	return c.rateLimiter.get(func() *ratelimit.RateLimiter {
+		return ratelimit.NewRateLimiter(c.redisClient)
+	})
+}
+
+// GetLocker возвращает распределенные блокировки в Redis; nil без Redis
+func (c *Container) GetLocker() *lock.Locker {
+	return c.locker.get(func() *lock.Locker {
+		if c.redisClient == nil {
+			return nil
+		}
+		return lock.New(lock.Config{}, c.redisClient)
+	})
 }
 
 func (c *Container) GetRedisClient() *redis.Client {
@@ -0,0 +1,131 @@
+package container
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/cache"
+	"github.com/rusgainew/tunduck-app/pkg/canary"
+	"github.com/rusgainew/tunduck-app/pkg/esfgateway"
+)
+
+// stubUserRepository подменяет репозиторий пользователей без БД
+type stubUserRepository struct {
+	repository.UserRepository
+}
+
+// offlineDB дескриптор БД без соединения: сервисы создаются, но запросы не выполняются
+func offlineDB(t *testing.T) *gorm.DB {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               gormlogger.Discard,
+	})
+	require.NoError(t, err)
+	return db
+}
+
+func TestNewContainer_Options(t *testing.T) {
+	memory := cache.NewMemoryCacheManager()
+	mock := esfgateway.NewMock(esfgateway.MockConfig{})
+	users := &stubUserRepository{}
+
+	c := NewContainer(
+		WithDatabase(offlineDB(t)),
+		WithLogger(logrus.New()),
+		WithCacheManager(memory),
+		WithESFGateway(mock),
+		WithUserRepository(users),
+	)
+
+	assert.Same(t, memory, c.GetCacheManager())
+	assert.Same(t, users, c.GetUserRepository())
+
+	// Шлюз из опции имеет приоритет над конфигурацией
+	gw, err := c.EnableESFGateway(esfgateway.Config{URL: "http://localhost"})
+	require.NoError(t, err)
+	assert.Same(t, mock, gw)
+	assert.Same(t, mock, c.GetESFGateway())
+}
+
+func TestNewContainer_Defaults(t *testing.T) {
+	c := NewContainer()
+
+	assert.NotNil(t, c.GetLogrus())
+	// Без Redis кеш сервисов хранится в памяти процесса
+	assert.IsType(t, &cache.MemoryCacheManager{}, c.GetCacheManager())
+	// Контейнер без БД и Redis останавливается без ошибок
+	assert.NoError(t, c.Stop(context.Background()))
+}
+
+func TestContainer_LazySingletons(t *testing.T) {
+	c := NewContainer(WithLogger(logrus.New()))
+
+	const callers = 16
+	results := make([]services.EsfOrganizationService, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = c.GetEsfOrganizationService()
+		}(i)
+	}
+	wg.Wait()
+
+	for _, svc := range results {
+		assert.Same(t, results[0], svc)
+	}
+	assert.Same(t, c.GetEsfOrganizationRepository(), c.GetEsfOrganizationRepository())
+}
+
+// stubDocumentService канареечная реализация сервиса документов в тестах
+type stubDocumentService struct {
+	services.EsfDocumentService
+	cache cache.CacheManager
+	calls int
+}
+
+func (s *stubDocumentService) SetCacheManager(manager cache.CacheManager) { s.cache = manager }
+
+func (s *stubDocumentService) SetExchangeRates(services.ExchangeRateService) {}
+
+func (s *stubDocumentService) SetTimezones(services.Timezones) {}
+
+func (s *stubDocumentService) GetAllDocuments(context.Context, uuid.UUID) ([]models.EsfCreateDocumentRequest, error) {
+	s.calls++
+	return nil, nil
+}
+
+func TestContainer_CanaryDocumentService(t *testing.T) {
+	memory := cache.NewMemoryCacheManager()
+	stub := &stubDocumentService{}
+	c := NewContainer(
+		WithDatabase(offlineDB(t)),
+		WithLogger(logrus.New()),
+		WithCacheManager(memory),
+		WithCanaryDocumentService(func(repository.EsfDocumentRepository, *gorm.DB, *logrus.Logger) services.EsfDocumentService {
+			return stub
+		}),
+	)
+	require.True(t, c.HasCanaryDocumentService())
+
+	svc := c.GetEsfDocumentService()
+	// Канареечная реализация получает те же зависимости, что и основная
+	assert.Same(t, memory, stub.cache)
+
+	_, err := svc.GetAllDocuments(canary.WithVariant(context.Background(), canary.VariantCanary), uuid.New())
+	require.NoError(t, err)
+	assert.Equal(t, 1, stub.calls)
+}
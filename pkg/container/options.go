@@ -0,0 +1,133 @@
+package container
+
+import (
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/rusgainew/tunduck-app/internal/repository"
+	"github.com/rusgainew/tunduck-app/internal/services"
+	"github.com/rusgainew/tunduck-app/pkg/cache"
+	"github.com/rusgainew/tunduck-app/pkg/esfgateway"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/storage"
+)
+
+// Option настраивает контейнер в NewContainer
+type Option func(*Container)
+
+// WithDatabase задает основную БД (PostgreSQL)
+func WithDatabase(db *gorm.DB) Option {
+	return func(c *Container) {
+		c.db = db
+	}
+}
+
+// WithLogger задает логгер; без него используется logrus.New()
+func WithLogger(log *logrus.Logger) Option {
+	return func(c *Container) {
+		c.logrus = log
+	}
+}
+
+// WithLogLevels задает уровни логирования подсистем, созданные поверх логгера из WithLogger
+func WithLogLevels(levels *logger.Levels) Option {
+	return func(c *Container) {
+		c.logLevels.set(levels)
+	}
+}
+
+// WithRedis задает клиент Redis для кеша, ограничения запросов, фоновых задач и блокировок
+func WithRedis(client *redis.Client) Option {
+	return func(c *Container) {
+		c.redisClient = client
+	}
+}
+
+// WithCacheManager подменяет кеш сервисов, например cache.NewMemoryCacheManager() в тестах
+// и при развертывании одним инстансом. По умолчанию кеш в Redis, а без Redis — в памяти.
+func WithCacheManager(manager cache.CacheManager) Option {
+	return func(c *Container) {
+		c.cacheManager.set(manager)
+	}
+}
+
+// WithESFGateway подменяет шлюз ЭСФ (например esfgateway.NewMock); EnableESFGateway
+// тогда подключает его вместо шлюза из конфигурации
+func WithESFGateway(gw esfgateway.Gateway) Option {
+	return func(c *Container) {
+		c.esfGateway = gw
+	}
+}
+
+// DocumentServiceFactory создает реализацию сервиса документов поверх общего репозитория,
+// например service_impl.NewEsfDocumentService
+type DocumentServiceFactory func(repo repository.EsfDocumentRepository, db *gorm.DB, log *logrus.Logger) services.EsfDocumentService
+
+// WithCanaryDocumentService регистрирует новую реализацию сервиса документов для канареечного выката:
+// ее получают запросы, для которых canary.Router выбрал канареечный вариант, остальные — основную
+func WithCanaryDocumentService(factory DocumentServiceFactory) Option {
+	return func(c *Container) {
+		c.canaryDocuments = factory
+	}
+}
+
+// WithDefaultTimezone задает часовой пояс организаций, для которых он не задан; без него — dates.DefaultTimezone
+func WithDefaultTimezone(loc *time.Location) Option {
+	return func(c *Container) {
+		c.defaultTimezone = loc
+	}
+}
+
+// WithStorage подменяет объектное хранилище; EnableStorage тогда использует его вместо хранилища из конфигурации
+func WithStorage(store storage.Storage) Option {
+	return func(c *Container) {
+		c.storage = store
+		c.attachmentStorage = store
+	}
+}
+
+// WithUserRepository подменяет репозиторий пользователей
+func WithUserRepository(repo repository.UserRepository) Option {
+	return func(c *Container) {
+		c.userRepository.set(repo)
+	}
+}
+
+// WithEsfDocumentRepository подменяет репозиторий документов
+func WithEsfDocumentRepository(repo repository.EsfDocumentRepository) Option {
+	return func(c *Container) {
+		c.docRepository.set(repo)
+	}
+}
+
+// WithEsfOrganizationRepository подменяет репозиторий организаций
+func WithEsfOrganizationRepository(repo repository.EsfOrganizationRepository) Option {
+	return func(c *Container) {
+		c.orgRepository.set(repo)
+	}
+}
+
+// lazy компонент контейнера, создаваемый при первом обращении (один раз, потокобезопасно)
+type lazy[T any] struct {
+	once  sync.Once
+	value T
+}
+
+// get возвращает компонент, создавая его через build при первом вызове
+func (l *lazy[T]) get(build func() T) T {
+	l.once.Do(func() {
+		l.value = build()
+	})
+	return l.value
+}
+
+// set задает компонент вместо создания по умолчанию; действует только до первого get
+func (l *lazy[T]) set(value T) {
+	l.once.Do(func() {
+		l.value = value
+	})
+}
@@ -0,0 +1,156 @@
+// Package dates календарные даты без времени суток и часовые пояса организаций.
+//
+// Дата поставки, даты договоров и другие календарные поля хранятся как Date: в JSON это строка
+// "2006-01-02", в БД — полночь UTC этого дня. Поэтому дата не сдвигается на день при чтении
+// в другом часовом поясе, а "сегодня" для организации определяется ее часовым поясом (Today).
+package dates
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+	// База часовых поясов встраивается в бинарник: в минимальном образе ее может не быть
+	_ "time/tzdata"
+)
+
+// Layout формат даты в JSON, запросах и отчетах
+const Layout = time.DateOnly
+
+// DefaultTimezone часовой пояс организаций, для которых он не задан
+const DefaultTimezone = "Asia/Bishkek"
+
+// ErrInvalid дата не в формате YYYY-MM-DD и не метка времени RFC 3339 с часовым поясом
+var ErrInvalid = errors.New("date must be YYYY-MM-DD or an RFC 3339 timestamp with a time zone")
+
+// Date календарный день: полночь UTC. Методы time.Time (Before, After, Format и другие) доступны
+// через встроенное поле.
+type Date struct {
+	time.Time
+}
+
+// New создает дату
+func New(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// Of возвращает календарный день момента t в его часовом поясе
+func Of(t time.Time) Date {
+	if t.IsZero() {
+		return Date{}
+	}
+	return New(t.Year(), t.Month(), t.Day())
+}
+
+// Today возвращает текущую дату в часовом поясе loc
+func Today(loc *time.Location) Date {
+	return Of(time.Now().In(loc))
+}
+
+// Parse разбирает дату "2006-01-02". Метка времени RFC 3339 принимается для совместимости и дает
+// календарный день в указанном в ней часовом поясе; время без часового пояса отклоняется, так как
+// день по нему определить нельзя.
+func Parse(s string) (Date, error) {
+	if t, err := time.Parse(Layout, s); err == nil {
+		return Of(t), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return Date{}, ErrInvalid
+	}
+	return Of(t), nil
+}
+
+// String возвращает дату в формате Layout; нулевая дата — пустая строка
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(Layout)
+}
+
+// Start возвращает начало дня в часовом поясе loc, например для сравнения с метками времени
+func (d Date) Start(loc *time.Location) time.Time {
+	return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc)
+}
+
+// MarshalJSON сериализует дату строкой "2006-01-02"; нулевая дата — null
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON читает дату в формате Parse; null и пустая строка — нулевая дата
+func (d *Date) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*d = Date{}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return ErrInvalid
+	}
+	return d.UnmarshalText([]byte(s))
+}
+
+// MarshalText сериализует дату в формате Layout
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText читает дату в формате Parse; пустая строка — нулевая дата
+func (d *Date) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*d = Date{}
+		return nil
+	}
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*d = parsed
+	return nil
+}
+
+// Value сохраняет дату как полночь UTC, поэтому колонки с меткой времени не требуют миграции
+func (d Date) Value() (driver.Value, error) {
+	return d.Time, nil
+}
+
+// Scan читает дату; метка времени из БД приводится к UTC, в котором дата сохранена
+func (d *Date) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*d = Date{}
+		return nil
+	case time.Time:
+		*d = Of(v.UTC())
+		return nil
+	case string:
+		return d.UnmarshalText([]byte(v))
+	case []byte:
+		return d.UnmarshalText(v)
+	}
+	return fmt.Errorf("cannot scan %T into dates.Date", src)
+}
+
+// GormDataType сохраняет тип колонки меткой времени, как у time.Time
+func (Date) GormDataType() string {
+	return "time"
+}
+
+// LoadLocation загружает часовой пояс IANA, например "Asia/Bishkek". Пустое имя и "Local"
+// отклоняются: дата организации не должна зависеть от настроек сервера.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" || name == "Local" {
+		return nil, fmt.Errorf("invalid time zone %q", name)
+	}
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid time zone %q", name)
+	}
+	return loc, nil
+}
@@ -0,0 +1,95 @@
+package dates
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tests := map[string]struct {
+		in   string
+		want Date
+	}{
+		"date only":         {"2026-03-02", New(2026, 3, 2)},
+		"utc midnight":      {"2026-03-02T00:00:00Z", New(2026, 3, 2)},
+		"day in given zone": {"2026-03-01T22:00:00-05:00", New(2026, 3, 1)},
+		"ahead of utc":      {"2026-03-02T01:00:00+06:00", New(2026, 3, 2)},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	for _, in := range []string{"2026-03-02T00:00:00", "02.03.2026", "2026-02-30", "tomorrow"} {
+		_, err := Parse(in)
+		assert.ErrorIs(t, err, ErrInvalid, in)
+	}
+}
+
+func TestDate_JSON(t *testing.T) {
+	var v struct {
+		Delivery Date  `json:"delivery"`
+		End      *Date `json:"end"`
+		Start    Date  `json:"start"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(`{"delivery":"2026-03-02T01:00:00+06:00","end":"2026-12-31","start":""}`), &v))
+	assert.Equal(t, New(2026, 3, 2), v.Delivery)
+	require.NotNil(t, v.End)
+	assert.Equal(t, New(2026, 12, 31), *v.End)
+	assert.True(t, v.Start.IsZero())
+
+	raw, err := json.Marshal(v)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"delivery":"2026-03-02","end":"2026-12-31","start":null}`, string(raw))
+
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"delivery":20260302}`), &v), ErrInvalid)
+}
+
+func TestDate_Scan(t *testing.T) {
+	// Драйвер может вернуть сохраненную полночь UTC в часовом поясе сессии БД
+	bishkek, err := LoadLocation("Asia/Bishkek")
+	require.NoError(t, err)
+
+	var d Date
+	require.NoError(t, d.Scan(time.Date(2026, 3, 2, 6, 0, 0, 0, bishkek)))
+	assert.Equal(t, New(2026, 3, 2), d)
+
+	value, err := d.Value()
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), value)
+
+	require.NoError(t, d.Scan("2026-04-01"))
+	assert.Equal(t, New(2026, 4, 1), d)
+	require.NoError(t, d.Scan(nil))
+	assert.True(t, d.IsZero())
+	assert.Error(t, d.Scan(42))
+}
+
+func TestToday(t *testing.T) {
+	// В 23:00 UTC в Бишкеке (UTC+6) уже следующий день
+	bishkek, err := LoadLocation("Asia/Bishkek")
+	require.NoError(t, err)
+	late := time.Date(2026, 3, 1, 23, 0, 0, 0, time.UTC)
+	assert.Equal(t, New(2026, 3, 1), Of(late))
+	assert.Equal(t, New(2026, 3, 2), Of(late.In(bishkek)))
+	assert.Equal(t, Of(time.Now().In(bishkek)), Today(bishkek))
+	assert.Equal(t, time.Date(2026, 3, 2, 0, 0, 0, 0, bishkek), New(2026, 3, 2).Start(bishkek))
+}
+
+func TestLoadLocation(t *testing.T) {
+	loc, err := LoadLocation(DefaultTimezone)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultTimezone, loc.String())
+
+	for _, name := range []string{"", "Local", "Mars/Olympus"} {
+		_, err := LoadLocation(name)
+		assert.Error(t, err, name)
+	}
+}
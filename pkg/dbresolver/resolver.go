@@ -0,0 +1,287 @@
+package dbresolver
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+const (
+	// DefaultMaxLag допустимое отставание реплики, после которого чтение уходит на primary
+	DefaultMaxLag = 10 * time.Second
+	// DefaultCheckInterval период проверки отставания реплик
+	DefaultCheckInterval = 5 * time.Second
+
+	pluginName = "tunduck:dbresolver"
+
+	// lagQuery возвращает отставание реплики в секундах; на primary - 0
+	lagQuery = `SELECT CASE WHEN pg_is_in_recovery()
+		THEN COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0)
+		ELSE 0 END`
+)
+
+type primaryKey struct{}
+
+// WithPrimary помечает контекст: все запросы с ним идут на primary.
+// Нужен для чтения сразу после записи (read-your-writes).
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+func usePrimary(ctx context.Context) bool {
+	if ctx == nil {
+		return false
+	}
+	forced, _ := ctx.Value(primaryKey{}).(bool)
+	return forced
+}
+
+// LagFunc измеряет отставание реплики
+type LagFunc func(ctx context.Context) (time.Duration, error)
+
+// Replica реплика для чтения
+type Replica struct {
+	Name string
+	Pool gorm.ConnPool
+	Lag  LagFunc
+
+	close   func() error
+	healthy atomic.Bool
+	lag     atomic.Int64
+}
+
+// NewReplica создает реплику из подключения GORM; отставание измеряется запросом к pg_last_xact_replay_timestamp
+func NewReplica(name string, db *gorm.DB) (*Replica, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("replica %s: %w", name, err)
+	}
+
+	return &Replica{
+		Name: name,
+		Pool: sqlDB,
+		Lag: func(ctx context.Context) (time.Duration, error) {
+			var seconds float64
+			if err := sqlDB.QueryRowContext(ctx, lagQuery).Scan(&seconds); err != nil {
+				return 0, err
+			}
+			return time.Duration(seconds * float64(time.Second)), nil
+		},
+		close: sqlDB.Close,
+	}, nil
+}
+
+// ReplicaStatus состояние реплики
+type ReplicaStatus struct {
+	Name    string        `json:"name"`
+	Healthy bool          `json:"healthy"`
+	Lag     time.Duration `json:"lag"`
+}
+
+// Config параметры Resolver
+type Config struct {
+	// MaxLag реплики с большим отставанием исключаются из чтения
+	MaxLag time.Duration
+	// CheckInterval период проверки отставания
+	CheckInterval time.Duration
+	Logger        *logrus.Logger
+}
+
+// Resolver плагин GORM: запросы чтения вне транзакций уходят на здоровые реплики (round-robin),
+// запись, транзакции и SELECT ... FOR UPDATE - на primary.
+// Если ни одна реплика не укладывается в MaxLag, чтение тоже уходит на primary.
+type Resolver struct {
+	replicas []*Replica
+	config   Config
+	next     atomic.Uint64
+
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+// New создает Resolver. Реплики считаются здоровыми до первой проверки.
+func New(config Config, replicas ...*Replica) *Resolver {
+	if config.MaxLag <= 0 {
+		config.MaxLag = DefaultMaxLag
+	}
+	if config.CheckInterval <= 0 {
+		config.CheckInterval = DefaultCheckInterval
+	}
+	if config.Logger == nil {
+		config.Logger = logrus.StandardLogger()
+	}
+
+	for _, r := range replicas {
+		r.healthy.Store(true)
+	}
+
+	return &Resolver{
+		replicas: replicas,
+		config:   config,
+		stop:     make(chan struct{}),
+	}
+}
+
+// Name реализует gorm.Plugin
+func (r *Resolver) Name() string {
+	return pluginName
+}
+
+// Initialize реализует gorm.Plugin: регистрирует переключение пула перед запросами чтения
+func (r *Resolver) Initialize(db *gorm.DB) error {
+	if err := db.Callback().Query().Before("gorm:query").Register(pluginName+":query", r.route); err != nil {
+		return err
+	}
+	return db.Callback().Row().Before("gorm:row").Register(pluginName+":row", r.route)
+}
+
+// route подменяет пул соединений запроса на реплику
+func (r *Resolver) route(db *gorm.DB) {
+	stmt := db.Statement
+	if stmt == nil || len(r.replicas) == 0 {
+		return
+	}
+
+	// Внутри транзакции остаемся на ее соединении
+	if _, inTx := stmt.ConnPool.(gorm.TxCommitter); inTx {
+		return
+	}
+	// Блокирующее чтение должно выполняться на primary
+	if _, locking := stmt.Clauses["FOR"]; locking {
+		return
+	}
+	if usePrimary(stmt.Context) {
+		return
+	}
+	// Raw-запрос уже собран: на реплику уходит только чтение
+	if stmt.SQL.Len() > 0 && !isReadQuery(stmt.SQL.String()) {
+		return
+	}
+
+	if replica := r.pick(); replica != nil {
+		stmt.ConnPool = replica.Pool
+	}
+}
+
+// isReadQuery проверяет, что SQL не изменяет данные
+func isReadQuery(sql string) bool {
+	sql = strings.ToUpper(strings.TrimSpace(sql))
+	return (strings.HasPrefix(sql, "SELECT") || strings.HasPrefix(sql, "WITH")) &&
+		!strings.Contains(sql, "FOR UPDATE") && !strings.Contains(sql, "FOR SHARE")
+}
+
+// pick возвращает следующую здоровую реплику или nil
+func (r *Resolver) pick() *Replica {
+	n := len(r.replicas)
+	start := r.next.Add(1)
+	for i := 0; i < n; i++ {
+		replica := r.replicas[(start+uint64(i))%uint64(n)]
+		if replica.healthy.Load() {
+			return replica
+		}
+	}
+	return nil
+}
+
+// Start запускает периодическую проверку отставания реплик
+func (r *Resolver) Start(ctx context.Context) {
+	r.checkAll(ctx)
+
+	go func() {
+		ticker := time.NewTicker(r.config.CheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-r.stop:
+				return
+			case <-ticker.C:
+				r.checkAll(ctx)
+			}
+		}
+	}()
+}
+
+// checkAll обновляет состояние всех реплик
+func (r *Resolver) checkAll(ctx context.Context) {
+	for _, replica := range r.replicas {
+		r.check(ctx, replica)
+	}
+}
+
+func (r *Resolver) check(ctx context.Context, replica *Replica) {
+	if replica.Lag == nil {
+		return
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, r.config.CheckInterval)
+	defer cancel()
+
+	lag, err := replica.Lag(checkCtx)
+	healthy := err == nil && lag <= r.config.MaxLag
+	replica.lag.Store(int64(lag))
+
+	if was := replica.healthy.Swap(healthy); was != healthy {
+		entry := r.config.Logger.WithFields(logrus.Fields{
+			"replica": replica.Name,
+			"lag":     lag.String(),
+			"max_lag": r.config.MaxLag.String(),
+		})
+		if err != nil {
+			entry = entry.WithError(err)
+		}
+		if healthy {
+			entry.Info("Read replica is back in rotation")
+		} else {
+			entry.Warn("Read replica removed from rotation, reads fall back to primary")
+		}
+	}
+}
+
+// Status возвращает состояние реплик
+func (r *Resolver) Status() []ReplicaStatus {
+	statuses := make([]ReplicaStatus, len(r.replicas))
+	for i, replica := range r.replicas {
+		statuses[i] = ReplicaStatus{
+			Name:    replica.Name,
+			Healthy: replica.healthy.Load(),
+			Lag:     time.Duration(replica.lag.Load()),
+		}
+	}
+	return statuses
+}
+
+// Pools возвращает пулы соединений реплик по имени
+func (r *Resolver) Pools() map[string]gorm.ConnPool {
+	pools := make(map[string]gorm.ConnPool, len(r.replicas))
+	for _, replica := range r.replicas {
+		pools[replica.Name] = replica.Pool
+	}
+	return pools
+}
+
+// Close останавливает проверки и закрывает соединения реплик
+func (r *Resolver) Close() error {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+	})
+
+	var firstErr error
+	for _, replica := range r.replicas {
+		if replica.close == nil {
+			continue
+		}
+		if err := replica.close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
@@ -0,0 +1,150 @@
+package dbresolver
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// fakePool пул-заглушка, различаемый по имени
+type fakePool struct {
+	gorm.ConnPool
+	name string
+}
+
+// fakeTx пул транзакции
+type fakeTx struct {
+	fakePool
+}
+
+func (fakeTx) Commit() error   { return nil }
+func (fakeTx) Rollback() error { return nil }
+
+func newTestResolver(replicas ...*Replica) *Resolver {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return New(Config{MaxLag: time.Second, Logger: log}, replicas...)
+}
+
+func newStatement(ctx context.Context, pool gorm.ConnPool) *gorm.DB {
+	return &gorm.DB{Statement: &gorm.Statement{
+		ConnPool: pool,
+		Context:  ctx,
+		Clauses:  map[string]clause.Clause{},
+	}}
+}
+
+func poolName(db *gorm.DB) string {
+	switch pool := db.Statement.ConnPool.(type) {
+	case *fakePool:
+		return pool.name
+	case *fakeTx:
+		return pool.name
+	}
+	return ""
+}
+
+func TestRoute_RoundRobinAcrossReplicas(t *testing.T) {
+	r := newTestResolver(
+		&Replica{Name: "r1", Pool: &fakePool{name: "r1"}},
+		&Replica{Name: "r2", Pool: &fakePool{name: "r2"}},
+	)
+	primary := &fakePool{name: "primary"}
+
+	seen := map[string]int{}
+	for i := 0; i < 4; i++ {
+		db := newStatement(context.Background(), primary)
+		r.route(db)
+		seen[poolName(db)]++
+	}
+
+	assert.Equal(t, map[string]int{"r1": 2, "r2": 2}, seen)
+}
+
+func TestRoute_StaysOnPrimary(t *testing.T) {
+	r := newTestResolver(&Replica{Name: "r1", Pool: &fakePool{name: "r1"}})
+
+	t.Run("transaction", func(t *testing.T) {
+		db := newStatement(context.Background(), &fakeTx{fakePool{name: "tx"}})
+		r.route(db)
+		assert.Equal(t, "tx", poolName(db))
+	})
+
+	t.Run("forced primary", func(t *testing.T) {
+		db := newStatement(WithPrimary(context.Background()), &fakePool{name: "primary"})
+		r.route(db)
+		assert.Equal(t, "primary", poolName(db))
+	})
+
+	t.Run("locking clause", func(t *testing.T) {
+		db := newStatement(context.Background(), &fakePool{name: "primary"})
+		db.Statement.Clauses["FOR"] = clause.Clause{Name: "FOR"}
+		r.route(db)
+		assert.Equal(t, "primary", poolName(db))
+	})
+
+	t.Run("raw write", func(t *testing.T) {
+		db := newStatement(context.Background(), &fakePool{name: "primary"})
+		db.Statement.SQL.WriteString("UPDATE users SET name = 'x' RETURNING id")
+		r.route(db)
+		assert.Equal(t, "primary", poolName(db))
+	})
+}
+
+func TestCheck_LaggingReplicaFallsBackToPrimary(t *testing.T) {
+	lag := 5 * time.Second
+	replica := &Replica{
+		Name: "r1",
+		Pool: &fakePool{name: "r1"},
+		Lag:  func(context.Context) (time.Duration, error) { return lag, nil },
+	}
+	r := newTestResolver(replica)
+
+	r.checkAll(context.Background())
+	db := newStatement(context.Background(), &fakePool{name: "primary"})
+	r.route(db)
+	assert.Equal(t, "primary", poolName(db))
+	assert.False(t, r.Status()[0].Healthy)
+
+	lag = 100 * time.Millisecond
+	r.checkAll(context.Background())
+	db = newStatement(context.Background(), &fakePool{name: "primary"})
+	r.route(db)
+	assert.Equal(t, "r1", poolName(db))
+	assert.Equal(t, lag, r.Status()[0].Lag)
+}
+
+func TestCheck_UnreachableReplicaIsUnhealthy(t *testing.T) {
+	replica := &Replica{
+		Name: "r1",
+		Pool: &fakePool{name: "r1"},
+		Lag:  func(context.Context) (time.Duration, error) { return 0, sql.ErrConnDone },
+	}
+	r := newTestResolver(replica)
+
+	r.checkAll(context.Background())
+	assert.False(t, r.Status()[0].Healthy)
+}
+
+func TestIsReadQuery(t *testing.T) {
+	assert.True(t, isReadQuery("  select * from users"))
+	assert.True(t, isReadQuery("WITH x AS (SELECT 1) SELECT * FROM x"))
+	assert.False(t, isReadQuery("SELECT * FROM users FOR UPDATE"))
+	assert.False(t, isReadQuery("DELETE FROM users"))
+}
+
+func TestClose_ReturnsFirstError(t *testing.T) {
+	errClose := errors.New("close failed")
+	r := newTestResolver(&Replica{Name: "r1", close: func() error { return errClose }})
+
+	assert.ErrorIs(t, r.Close(), errClose)
+	assert.NotPanics(t, func() { _ = r.Close() })
+}
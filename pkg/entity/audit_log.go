@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditLog запись журнала аудита об одном изменяющем вызове API.
+// Хранится в основной БД; записи не изменяются и не удаляются приложением.
+type AuditLog struct {
+	ID             uuid.UUID       `gorm:"type:uuid;primary_key" json:"id"`
+	ActorID        string          `gorm:"size:36;index" json:"actorId,omitempty"`
+	ActorName      string          `gorm:"size:255" json:"actorName,omitempty"`
+	OrganizationID string          `gorm:"size:36;index" json:"organizationId,omitempty"`
+	Action         string          `gorm:"size:32;not null" json:"action"`
+	EntityType     string          `gorm:"size:64;index:idx_audit_logs_entity" json:"entityType,omitempty"`
+	EntityID       string          `gorm:"size:64;index:idx_audit_logs_entity" json:"entityId,omitempty"`
+	Method         string          `gorm:"size:8;not null" json:"method"`
+	Path           string          `gorm:"size:512;not null" json:"path"`
+	StatusCode     int             `gorm:"not null" json:"statusCode"`
+	Before         json.RawMessage `gorm:"type:jsonb" json:"before,omitempty"`
+	After          json.RawMessage `gorm:"type:jsonb" json:"after,omitempty"`
+	Diff           json.RawMessage `gorm:"type:jsonb" json:"diff,omitempty"`
+	IP             string          `gorm:"size:64" json:"ip"`
+	UserAgent      string          `gorm:"size:512" json:"userAgent,omitempty"`
+	RequestID      string          `gorm:"size:64;index" json:"requestId,omitempty"`
+	CreatedAt      time.Time       `gorm:"not null;index" json:"createdAt"`
+}
+
+// TableName возвращает имя таблицы для GORM
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
@@ -0,0 +1,62 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Состояние строки выписки
+const (
+	StatementLineMatched   = "matched"
+	StatementLineUnmatched = "unmatched"
+)
+
+// StatementMatchedAuto MatchedBy строки, сопоставленной при загрузке выписки
+const StatementMatchedAuto = "auto"
+
+// BankStatement загруженная банковская выписка. Хранится в БД организации
+type BankStatement struct {
+	ID           uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	FileName     string    `gorm:"size:255" json:"fileName"`
+	Format       string    `gorm:"size:16;not null" json:"format"`
+	Account      string    `gorm:"type:text;serializer:encrypted" json:"account,omitempty"`
+	Currency     string    `gorm:"size:3" json:"currency,omitempty"`
+	LineCount    int       `gorm:"not null;default:0" json:"lineCount"`
+	MatchedCount int       `gorm:"not null;default:0" json:"matchedCount"`
+	UploadedBy   string    `gorm:"size:36" json:"uploadedBy,omitempty"`
+	CreatedAt    time.Time `gorm:"autoCreateTime;index" json:"createdAt"`
+
+	Lines []BankStatementLine `gorm:"foreignKey:StatementID;constraint:OnDelete:CASCADE" json:"lines,omitempty"`
+}
+
+// TableName возвращает имя таблицы для GORM
+func (BankStatement) TableName() string {
+	return "bank_statements"
+}
+
+// BankStatementLine операция выписки. Сопоставленная строка ссылается на документ
+// и на оплату, созданную при сопоставлении
+type BankStatementLine struct {
+	ID               uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	StatementID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"statementId"`
+	LineNo           int        `gorm:"not null" json:"lineNo"`
+	ValueDate        time.Time  `gorm:"not null" json:"valueDate"`
+	Amount           float64    `gorm:"type:decimal(15,2);not null" json:"amount"`
+	Currency         string     `gorm:"size:3" json:"currency,omitempty"`
+	CounterpartyTin  string     `gorm:"size:14;index" json:"counterpartyTin,omitempty"`
+	CounterpartyName string     `gorm:"size:255" json:"counterpartyName,omitempty"`
+	Reference        string     `gorm:"size:100" json:"reference,omitempty"`
+	Description      string     `gorm:"type:text" json:"description,omitempty"`
+	Status           string     `gorm:"size:16;not null;index" json:"status"`
+	DocumentID       *uuid.UUID `gorm:"type:uuid" json:"documentId,omitempty"`
+	PaymentID        *uuid.UUID `gorm:"type:uuid" json:"paymentId,omitempty"`
+	// MatchedBy пользователь, сопоставивший строку вручную, или StatementMatchedAuto
+	MatchedBy string     `gorm:"size:36" json:"matchedBy,omitempty"`
+	MatchedAt *time.Time `json:"matchedAt,omitempty"`
+}
+
+// TableName возвращает имя таблицы для GORM
+func (BankStatementLine) TableName() string {
+	return "bank_statement_lines"
+}
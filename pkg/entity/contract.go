@@ -0,0 +1,47 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/pkg/dates"
+)
+
+// Contract договор поставки с контрагентом. Хранится в БД организации; документы ссылаются
+// на договор через EsfDocument.ContractID
+type Contract struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Number        string     `gorm:"size:100;not null;uniqueIndex:idx_contracts_tin_number" json:"number"`
+	ContractorTin string     `gorm:"size:14;not null;uniqueIndex:idx_contracts_tin_number" json:"contractorTin"`
+	StartDate     dates.Date `gorm:"not null" json:"startDate"`
+	// EndDate дата окончания; договор без даты окончания действует бессрочно
+	EndDate *dates.Date `json:"endDate,omitempty"`
+	// CurrencyCode валюта договора; пустая — любая
+	CurrencyCode string `gorm:"size:3" json:"currencyCode,omitempty"`
+	// AmountLimit лимит суммы документов по договору; 0 — без лимита
+	AmountLimit float64   `gorm:"type:decimal(15,2);not null;default:0" json:"amountLimit"`
+	Comment     string    `gorm:"type:text" json:"comment,omitempty"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt   time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// TableName возвращает имя таблицы для GORM
+func (Contract) TableName() string {
+	return "contracts"
+}
+
+// Started сообщает, начал ли договор действовать к дате date
+func (c *Contract) Started(date dates.Date) bool {
+	return !date.Before(c.StartDate.Time)
+}
+
+// Expired сообщает, истек ли договор к дате date; день окончания еще входит в срок действия
+func (c *Contract) Expired(date dates.Date) bool {
+	return c.EndDate != nil && date.After(c.EndDate.Time)
+}
+
+// dateOnly отбрасывает время: сроки сравниваются по календарным дням
+func dateOnly(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
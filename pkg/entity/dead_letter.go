@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Источники записей очереди недоставленных сообщений
+const (
+	DeadLetterSourceJob = "job"
+)
+
+// Статусы записи очереди недоставленных сообщений
+const (
+	DeadLetterStatusDead     = "dead"
+	DeadLetterStatusRequeued = "requeued"
+)
+
+// DeadLetter сообщение, исчерпавшее попытки обработки. Хранит полезную нагрузку целиком,
+// чтобы администратор мог изучить ее и поставить сообщение в обработку повторно.
+type DeadLetter struct {
+	ID     uuid.UUID `gorm:"type:uuid;primary_key" json:"id"`
+	Source string    `gorm:"size:16;not null;index" json:"source"`
+	// Type тип задачи или события
+	Type  string `gorm:"size:64;not null;index" json:"type"`
+	Queue string `gorm:"size:64" json:"queue,omitempty"`
+	// Reference идентификатор исходного сообщения (ID задачи)
+	Reference    string          `gorm:"size:64;index" json:"reference"`
+	Payload      json.RawMessage `gorm:"type:jsonb" json:"payload,omitempty"`
+	Attempts     int             `gorm:"not null;default:0" json:"attempts"`
+	MaxRetries   int             `gorm:"not null;default:0" json:"maxRetries"`
+	Error        string          `gorm:"type:text" json:"error,omitempty"`
+	Status       string          `gorm:"size:16;not null;index" json:"status"`
+	RequeueCount int             `gorm:"not null;default:0" json:"requeueCount"`
+	CreatedAt    time.Time       `gorm:"index" json:"createdAt"`
+	UpdatedAt    time.Time       `json:"updatedAt"`
+	RequeuedAt   *time.Time      `json:"requeuedAt,omitempty"`
+}
+
+// TableName возвращает имя таблицы для GORM
+func (DeadLetter) TableName() string {
+	return "dead_letters"
+}
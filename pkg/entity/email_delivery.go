@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Статусы доставки письма
+const (
+	EmailStatusQueued = "queued"
+	EmailStatusSent   = "sent"
+	EmailStatusFailed = "failed"
+)
+
+// EmailDelivery запись об отправке письма: создается при постановке в очередь
+// и обновляется фоновой задачей после каждой попытки
+type EmailDelivery struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	Template  string     `gorm:"size:64;not null" json:"template"`
+	Recipient string     `gorm:"size:255;not null;index" json:"recipient"`
+	Language  string     `gorm:"size:8;not null" json:"language"`
+	Subject   string     `gorm:"size:255;not null" json:"subject"`
+	Status    string     `gorm:"size:16;not null;index" json:"status"`
+	Attempts  int        `gorm:"not null;default:0" json:"attempts"`
+	LastError string     `gorm:"type:text" json:"lastError,omitempty"`
+	JobID     string     `gorm:"size:64" json:"jobId,omitempty"`
+	CreatedAt time.Time  `gorm:"index" json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+	SentAt    *time.Time `json:"sentAt,omitempty"`
+}
+
+// TableName возвращает имя таблицы для GORM
+func (EmailDelivery) TableName() string {
+	return "email_deliveries"
+}
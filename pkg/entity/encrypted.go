@@ -0,0 +1,5 @@
+package entity
+
+// Поля с тегом gorm:"serializer:encrypted" (банковские и лицевые счета, учетные данные шлюза)
+// шифруются в БД; сериализатор регистрируется пакетом fieldcrypt
+import _ "github.com/rusgainew/tunduck-app/pkg/fieldcrypt"
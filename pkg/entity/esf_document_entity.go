@@ -5,6 +5,8 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+
+	"github.com/rusgainew/tunduck-app/pkg/dates"
 )
 
 // Create document
@@ -14,9 +16,17 @@ type EsfDocument struct {
 	CreatedAt time.Time      `gorm:"autoCreateTime" json:"createdAt"`
 	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updatedAt"`
 	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	// Version версия документа для оптимистичной блокировки
+	Version int64 `gorm:"not null;default:1" json:"version"`
+	// EsfStatus статус документа в ЭСФ, приходит из обратных вызовов шлюза; пустой — не отправлялся
+	EsfStatus string `gorm:"size:32;index" json:"esfStatus,omitempty"`
 
 	// false Наименование иностранца или Наименование на иностранном языке
 	ForeignName string `gorm:"size:255" json:"foreignName"`
+	// false Наименование покупателя на языках ru, ky, en
+	ContractorNames LocalizedNames `gorm:"type:jsonb" json:"contractorNames,omitempty"`
+	// false Язык печатной формы документа (ru, ky, en)
+	Language string `gorm:"size:2" json:"language,omitempty"`
 	// true Отправить от имени филиала
 	IsBranchDataSent bool `gorm:"not null" json:"isBranchDataSent" valid:"required"`
 	// true Цена без налогов
@@ -30,7 +40,7 @@ type EsfDocument struct {
 	// true Код вида операции
 	OperationTypeCode string `gorm:"size:20;not null" json:"operationTypeCode" valid:"required"`
 	// true Дата поставки
-	DeliveryDate time.Time `gorm:"not null" json:"deliveryDate" valid:"required"`
+	DeliveryDate dates.Date `gorm:"not null" json:"deliveryDate" valid:"required"`
 	// true Код типа поставки
 	DeliveryTypeCode string `gorm:"size:20;not null" json:"deliveryTypeCode" valid:"required"`
 	// true Субъект Кыргызской Республики
@@ -38,9 +48,9 @@ type EsfDocument struct {
 	// true ИНН покупателя
 	ContractorTin string `gorm:"size:14;not null" json:"contractorTin" valid:"required"`
 	// false Номер банковского счета поставщика
-	SupplierBankAccount string `gorm:"size:50" json:"supplierBankAccount"`
+	SupplierBankAccount string `gorm:"type:text;serializer:encrypted" json:"supplierBankAccount"`
 	// false Номер банковского счета покупателя
-	ContractorBankAccount string `gorm:"size:50" json:"contractorBankAccount"`
+	ContractorBankAccount string `gorm:"type:text;serializer:encrypted" json:"contractorBankAccount"`
 	// true Код валюты
 	CurrencyCode string `gorm:"size:3;not null" json:"currencyCode" valid:"required"`
 	// false Код страны
@@ -54,7 +64,9 @@ type EsfDocument struct {
 	// false Номер договора на поставку
 	SupplyContractNumber string `gorm:"size:100" json:"supplyContractNumber"`
 	// false Дата договора на поставку
-	ContractStartDate time.Time `json:"contractStartDate"`
+	ContractStartDate dates.Date `json:"contractStartDate"`
+	// false Договор из справочника договоров; номер и дата договора заполняются из него
+	ContractID *uuid.UUID `gorm:"type:uuid;index" json:"contractId,omitempty"`
 	// false Дата окончания договора на поставку Комментарий
 	Comment string `gorm:"type:text" json:"comment"`
 	// false Код способа доставки
@@ -80,7 +92,54 @@ type EsfDocument struct {
 	// false Сумма к оплате
 	AmountToBePaid float64 `gorm:"type:decimal(15,2);default:0" json:"amountToBePaid"`
 	// false Лицевой счет
-	PersonalAccountNumber string `gorm:"size:50" json:"personalAccountNumber"`
+	PersonalAccountNumber string `gorm:"type:text;serializer:encrypted" json:"personalAccountNumber"`
+	// false Срок оплаты; документ без срока не считается просроченным
+	DueDate *time.Time `gorm:"index" json:"dueDate,omitempty"`
+}
+
+// Статусы документа в ЭСФ
+const (
+	EsfStatusSent       = "sent"
+	EsfStatusRegistered = "registered"
+	EsfStatusRejected   = "rejected"
+	EsfStatusCancelled  = "cancelled"
+)
+
+// IsValidEsfStatus проверяет статус, полученный от шлюза
+func IsValidEsfStatus(status string) bool {
+	switch status {
+	case EsfStatusSent, EsfStatusRegistered, EsfStatusRejected, EsfStatusCancelled:
+		return true
+	}
+	return false
+}
+
+// IsEsfDocumentEditable сообщает, можно ли изменять и удалять документ: он еще не отправлялся
+// в ЭСФ или был отклонен. Отправленный, зарегистрированный и аннулированный документ меняет только шлюз.
+func IsEsfDocumentEditable(status string) bool {
+	return status == "" || status == EsfStatusRejected
+}
+
+// AmountDue сумма к оплате по документу: начальное сальдо и общая стоимость
+func (d *EsfDocument) AmountDue() float64 {
+	return d.OpeningBalances + d.TotalCurrencyValue
+}
+
+// RemainingAmount неоплаченный остаток; отрицательный означает переплату
+func (d *EsfDocument) RemainingAmount() float64 {
+	return d.AmountDue() - d.PaidAmount
+}
+
+// PaymentStatus состояние оплаты на момент now: paid, unpaid или overdue
+// (не оплачен, а срок оплаты прошел)
+func (d *EsfDocument) PaymentStatus(now time.Time) string {
+	if d.RemainingAmount() < PaymentTolerance {
+		return PaymentStatusPaid
+	}
+	if d.DueDate != nil && d.DueDate.Before(now) {
+		return PaymentStatusOverdue
+	}
+	return PaymentStatusUnpaid
 }
 
 func (EsfDocument) TableName() string {
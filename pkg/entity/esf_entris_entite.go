@@ -22,6 +22,12 @@ type EsfEntries struct {
 	// для целей налогообложения
 	SalesTaxCode string `gorm:"size:50;not null" json:"salesTaxCode" valid:"required"`
 
+	// ForeignName - наименование товара или услуги на иностранном языке
+	ForeignName string `gorm:"size:255" json:"foreignName,omitempty"`
+
+	// Names - наименование товара или услуги на языках ru, ky, en
+	Names LocalizedNames `gorm:"type:jsonb" json:"names,omitempty"`
+
 	// CustomsAuthorityCode - код таможенного органа, через который
 	// прошло оформление товара (если применимо)
 	CustomsAuthorityCode string `gorm:"size:50" json:"customsAuthorityCode"`
@@ -49,6 +55,12 @@ type EsfEntries struct {
 	// TotalAmount - итоговая сумма за позицию
 	// с учетом всех налогов
 	TotalAmount float64 `gorm:"type:decimal(15,2);not null" json:"totalAmount" valid:"required"`
+
+	// DiscountAmount - скидка на позицию; суммы позиции указаны с ее учетом
+	DiscountAmount float64 `gorm:"type:decimal(15,2);not null;default:0" json:"discountAmount"`
+
+	// SurchargeAmount - наценка на позицию; суммы позиции указаны с ее учетом
+	SurchargeAmount float64 `gorm:"type:decimal(15,2);not null;default:0" json:"surchargeAmount"`
 }
 
 func (EsfEntries) TableName() string {
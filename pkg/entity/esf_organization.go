@@ -5,17 +5,28 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type EstOrganization struct {
 	ID          uuid.UUID `gorm:"type:uuid;primaryKey"`
 	Name        string
 	Description string
-	Token       string
-	DBName      string `gorm:"column:db_name"`
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	DeletedAt   *time.Time `gorm:"index"`
+	// Token учетные данные организации в шлюзе ЭСФ; хранятся зашифрованными
+	Token  string `gorm:"serializer:encrypted"`
+	DBName string `gorm:"column:db_name"`
+	// Plan тарифный план организации; пустой — план по умолчанию (QUOTA_DEFAULT_PLAN)
+	Plan string `gorm:"size:50"`
+	// Tin ИНН организации (Кыргызстан или Россия, pkg/tin); необязателен
+	Tin string `gorm:"size:14"`
+	// Timezone часовой пояс IANA; пустой — DEFAULT_TIMEZONE. Определяет текущую дату организации
+	// при проверке дат документов и сроков договоров
+	Timezone  string `gorm:"size:64"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt gorm.DeletedAt `gorm:"index"`
+	// Version версия записи для оптимистичной блокировки
+	Version int64 `gorm:"not null;default:1"`
 }
 
 // Validate проверяет валидность данных организации
@@ -0,0 +1,27 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxEvent доменное событие, записанное в той же транзакции, что и изменение.
+// Документы пишут события в БД организации, организации — в основную БД.
+// Опубликованные события хранятся до очистки для повторной отправки.
+type OutboxEvent struct {
+	ID             int64           `gorm:"primaryKey;autoIncrement" json:"-"`
+	EventID        uuid.UUID       `gorm:"type:uuid;not null;uniqueIndex" json:"id"`
+	Type           string          `gorm:"size:64;not null" json:"type"`
+	AggregateType  string          `gorm:"size:32;not null" json:"aggregateType"`
+	AggregateID    uuid.UUID       `gorm:"type:uuid;not null" json:"aggregateId"`
+	OrganizationID uuid.UUID       `gorm:"type:uuid;not null;index:idx_event_outbox_org_time" json:"organizationId"`
+	Payload        json.RawMessage `gorm:"type:jsonb" json:"payload,omitempty"`
+	OccurredAt     time.Time       `gorm:"not null;index:idx_event_outbox_org_time" json:"occurredAt"`
+	PublishedAt    *time.Time      `gorm:"index" json:"publishedAt,omitempty"`
+}
+
+func (OutboxEvent) TableName() string {
+	return "event_outbox"
+}
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// ExchangeRate официальный курс валюты на дату: стоимость единицы валюты в сомах.
+// Курсы хранятся в основной БД за все дни, чтобы документы за прошлые даты поставки
+// получали курс своего дня.
+type ExchangeRate struct {
+	CurrencyCode string    `gorm:"size:3;primaryKey" json:"currencyCode"`
+	Date         time.Time `gorm:"type:date;primaryKey" json:"date"`
+	Rate         float64   `gorm:"type:decimal(15,6);not null" json:"rate"`
+	Source       string    `gorm:"size:16;not null" json:"source"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
@@ -0,0 +1,42 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Статусы выгрузки
+const (
+	ExportStatusPending   = "pending"
+	ExportStatusRunning   = "running"
+	ExportStatusCompleted = "completed"
+	ExportStatusFailed    = "failed"
+)
+
+// ExportJob фоновая выгрузка данных в файл объектного хранилища.
+// Создается по запросу пользователя и обновляется фоновой задачей по мере выгрузки.
+type ExportJob struct {
+	ID             uuid.UUID       `gorm:"type:uuid;primary_key" json:"id"`
+	Type           string          `gorm:"size:32;not null" json:"type"`
+	Format         string          `gorm:"size:16;not null" json:"format"`
+	Status         string          `gorm:"size:16;not null;index" json:"status"`
+	RequestedBy    string          `gorm:"size:36;not null;index" json:"requestedBy"`
+	OrganizationID *uuid.UUID      `gorm:"type:uuid" json:"organizationId,omitempty"`
+	Filter         json.RawMessage `gorm:"type:jsonb" json:"filter,omitempty"`
+	Total          int64           `gorm:"not null;default:0" json:"total"`
+	Processed      int64           `gorm:"not null;default:0" json:"processed"`
+	StorageKey     string          `gorm:"size:255" json:"-"`
+	Size           int64           `gorm:"not null;default:0" json:"size"`
+	Error          string          `gorm:"type:text" json:"error,omitempty"`
+	CreatedAt      time.Time       `gorm:"index" json:"createdAt"`
+	UpdatedAt      time.Time       `json:"updatedAt"`
+	CompletedAt    *time.Time      `json:"completedAt,omitempty"`
+	ExpiresAt      *time.Time      `json:"expiresAt,omitempty"`
+}
+
+// TableName возвращает имя таблицы для GORM
+func (ExportJob) TableName() string {
+	return "export_jobs"
+}
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Статусы обработки входящего события
+const (
+	InboundStatusProcessing = "processing"
+	InboundStatusProcessed  = "processed"
+	InboundStatusFailed     = "failed"
+)
+
+// InboundEvent входящее событие (обратный вызов шлюза ЭСФ или другого сервиса).
+// Пара (Source, EventID) уникальна: повторная доставка того же события не обрабатывается второй раз.
+type InboundEvent struct {
+	ID          uuid.UUID       `gorm:"type:uuid;primary_key" json:"id"`
+	Source      string          `gorm:"size:64;not null;uniqueIndex:idx_inbound_events_source_event" json:"source"`
+	EventID     string          `gorm:"size:128;not null;uniqueIndex:idx_inbound_events_source_event" json:"eventId"`
+	Type        string          `gorm:"size:64;not null" json:"type"`
+	Payload     json.RawMessage `gorm:"type:jsonb" json:"payload,omitempty"`
+	Status      string          `gorm:"size:16;not null;index" json:"status"`
+	Attempts    int             `gorm:"not null;default:1" json:"attempts"`
+	Error       string          `gorm:"type:text" json:"error,omitempty"`
+	ReceivedAt  time.Time       `gorm:"not null" json:"receivedAt"`
+	UpdatedAt   time.Time       `json:"updatedAt"`
+	ProcessedAt *time.Time      `json:"processedAt,omitempty"`
+}
+
+// TableName возвращает имя таблицы для GORM
+func (InboundEvent) TableName() string {
+	return "inbound_events"
+}
@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// Языки локализованных наименований
+const (
+	LanguageRussian = "ru"
+	LanguageKyrgyz  = "ky"
+	LanguageEnglish = "en"
+)
+
+// nameLanguages порядок выбора наименования, если на нужном языке его нет
+var nameLanguages = []string{LanguageRussian, LanguageKyrgyz, LanguageEnglish}
+
+// LocalizedNames наименования контрагента или товара по языкам (ru, ky, en); хранится в jsonb
+type LocalizedNames map[string]string
+
+// Pick возвращает наименование на языке lang. Если его нет — foreign (наименование на иностранном
+// языке из ЭСФ), затем наименование на первом доступном языке в порядке ru, ky, en
+func (n LocalizedNames) Pick(lang, foreign string) string {
+	if name := n[lang]; name != "" {
+		return name
+	}
+	if foreign != "" {
+		return foreign
+	}
+	for _, l := range nameLanguages {
+		if name := n[l]; name != "" {
+			return name
+		}
+	}
+	return ""
+}
+
+// Value сохраняет наименования в jsonb; пустой набор хранится как NULL
+func (n LocalizedNames) Value() (driver.Value, error) {
+	if len(n) == 0 {
+		return nil, nil
+	}
+	raw, err := json.Marshal(map[string]string(n))
+	if err != nil {
+		return nil, err
+	}
+	return string(raw), nil
+}
+
+// Scan читает наименования из jsonb
+func (n *LocalizedNames) Scan(src interface{}) error {
+	var raw []byte
+	switch v := src.(type) {
+	case nil:
+		*n = nil
+		return nil
+	case []byte:
+		raw = v
+	case string:
+		raw = []byte(v)
+	default:
+		return fmt.Errorf("entity: cannot scan %T into LocalizedNames", src)
+	}
+	return json.Unmarshal(raw, (*map[string]string)(n))
+}
@@ -0,0 +1,46 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Состояния длительной операции
+const (
+	OperationStatePending   = "pending"
+	OperationStateRunning   = "running"
+	OperationStateSucceeded = "succeeded"
+	OperationStateFailed    = "failed"
+)
+
+// Operation длительная асинхронная операция (выгрузка, массовая обработка и т.п.).
+// Инициирующий запрос отвечает 202 со ссылкой на операцию, клиент опрашивает ее до завершения.
+// Кроме общего состояния операция хранит ссылку на ресурс с результатом.
+type Operation struct {
+	ID             uuid.UUID  `gorm:"type:uuid;primary_key" json:"id"`
+	Kind           string     `gorm:"size:32;not null;index" json:"kind"`
+	State          string     `gorm:"size:16;not null;index" json:"state"`
+	RequestedBy    string     `gorm:"size:36;not null;index" json:"requestedBy"`
+	OrganizationID *uuid.UUID `gorm:"type:uuid" json:"organizationId,omitempty"`
+	Total          int64      `gorm:"not null;default:0" json:"total"`
+	Processed      int64      `gorm:"not null;default:0" json:"processed"`
+	// ResultURL путь ресурса с результатом операции (например, выгрузки со ссылкой на файл)
+	ResultURL    string     `gorm:"size:255" json:"resultUrl,omitempty"`
+	ErrorCode    string     `gorm:"size:64" json:"errorCode,omitempty"`
+	ErrorMessage string     `gorm:"type:text" json:"errorMessage,omitempty"`
+	CreatedAt    time.Time  `gorm:"index" json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+	StartedAt    *time.Time `json:"startedAt,omitempty"`
+	FinishedAt   *time.Time `json:"finishedAt,omitempty"`
+}
+
+// TableName возвращает имя таблицы для GORM
+func (Operation) TableName() string {
+	return "operations"
+}
+
+// Done сообщает, что операция завершена успешно или с ошибкой
+func (o *Operation) Done() bool {
+	return o.State == OperationStateSucceeded || o.State == OperationStateFailed
+}
@@ -0,0 +1,54 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Способы оплаты
+const (
+	PaymentMethodBankTransfer = "bank_transfer"
+	PaymentMethodCash         = "cash"
+	PaymentMethodCard         = "card"
+	PaymentMethodOther        = "other"
+)
+
+// IsValidPaymentMethod проверяет способ оплаты
+func IsValidPaymentMethod(method string) bool {
+	switch method {
+	case PaymentMethodBankTransfer, PaymentMethodCash, PaymentMethodCard, PaymentMethodOther:
+		return true
+	}
+	return false
+}
+
+// Состояние оплаты документа
+const (
+	PaymentStatusPaid    = "paid"
+	PaymentStatusUnpaid  = "unpaid"
+	PaymentStatusOverdue = "overdue"
+)
+
+// PaymentTolerance погрешность сравнения сумм: остаток меньше копейки считается оплаченным
+const PaymentTolerance = 0.005
+
+// Payment поступление оплаты по документу ЭСФ. Хранится в БД организации;
+// сумма поступлений учитывается в EsfDocument.PaidAmount.
+type Payment struct {
+	ID         uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	DocumentID uuid.UUID `gorm:"type:uuid;not null;index" json:"documentId"`
+	PaidAt     time.Time `gorm:"not null" json:"paidAt"`
+	Amount     float64   `gorm:"type:decimal(15,2);not null" json:"amount"`
+	Method     string    `gorm:"size:32;not null" json:"method"`
+	// Reference номер платежного поручения или чека
+	Reference string    `gorm:"size:100" json:"reference,omitempty"`
+	Comment   string    `gorm:"type:text" json:"comment,omitempty"`
+	CreatedBy string    `gorm:"size:36" json:"createdBy,omitempty"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+// TableName возвращает имя таблицы для GORM
+func (Payment) TableName() string {
+	return "esf_payments"
+}
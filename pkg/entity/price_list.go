@@ -0,0 +1,73 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PriceList прайс-лист организации для одного контрагента (ContractorTin) или группы
+// покупателей (CustomerGroup). Хранится в БД организации
+type PriceList struct {
+	ID            uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	Name          string    `gorm:"size:255;not null" json:"name"`
+	ContractorTin string    `gorm:"size:14;index" json:"contractorTin,omitempty"`
+	CustomerGroup string    `gorm:"size:100;index" json:"customerGroup,omitempty"`
+	// CurrencyCode валюта цен; прайс-лист применяется к документам в той же валюте
+	CurrencyCode string `gorm:"size:3;not null" json:"currencyCode"`
+	// ValidFrom и ValidTo ограничивают срок действия по дате поставки; пустые — без ограничения
+	ValidFrom *time.Time `json:"validFrom,omitempty"`
+	ValidTo   *time.Time `json:"validTo,omitempty"`
+	CreatedAt time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt time.Time  `gorm:"autoUpdateTime" json:"updatedAt"`
+
+	Items []PriceListItem `gorm:"foreignKey:PriceListID;constraint:OnDelete:CASCADE" json:"items,omitempty"`
+}
+
+// TableName возвращает имя таблицы для GORM
+func (PriceList) TableName() string {
+	return "price_lists"
+}
+
+// ValidOn сообщает, действует ли прайс-лист на дату date
+func (p *PriceList) ValidOn(date time.Time) bool {
+	day := dateOnly(date)
+	if p.ValidFrom != nil && day.Before(dateOnly(*p.ValidFrom)) {
+		return false
+	}
+	return p.ValidTo == nil || !day.After(dateOnly(*p.ValidTo))
+}
+
+// PriceListItem цена товара или услуги. Товар определяется кодом SalesTaxCode позиции документа;
+// пустой UnitClassificationCode подходит к любой единице измерения
+type PriceListItem struct {
+	ID                     uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	PriceListID            uuid.UUID `gorm:"type:uuid;not null;index" json:"priceListId"`
+	SalesTaxCode           string    `gorm:"size:50;not null" json:"salesTaxCode"`
+	UnitClassificationCode string    `gorm:"size:20" json:"unitClassificationCode,omitempty"`
+	Price                  float64   `gorm:"type:decimal(15,2);not null" json:"price"`
+	// FloorPrice минимальная цена продажи; 0 — без ограничения
+	FloorPrice float64 `gorm:"type:decimal(15,2);not null;default:0" json:"floorPrice"`
+}
+
+// TableName возвращает имя таблицы для GORM
+func (PriceListItem) TableName() string {
+	return "price_list_items"
+}
+
+// Matches сообщает, относится ли цена к позиции с кодом code и единицей измерения unit
+func (i *PriceListItem) Matches(code, unit string) bool {
+	return i.SalesTaxCode == code && (i.UnitClassificationCode == "" || i.UnitClassificationCode == unit)
+}
+
+// CustomerGroupMember контрагент в группе покупателей
+type CustomerGroupMember struct {
+	GroupName     string    `gorm:"size:100;primaryKey" json:"groupName"`
+	ContractorTin string    `gorm:"size:14;primaryKey;index" json:"contractorTin"`
+	CreatedAt     time.Time `gorm:"autoCreateTime" json:"createdAt"`
+}
+
+// TableName возвращает имя таблицы для GORM
+func (CustomerGroupMember) TableName() string {
+	return "customer_group_members"
+}
@@ -0,0 +1,13 @@
+package entity
+
+import "time"
+
+// ReferenceCode код официального справочника ЭСФ (виды операций, поставок, коды оплаты,
+// ставки НДС, страны, валюты). Справочники общие для всех организаций и синхронизируются
+// со шлюзом налоговой службы.
+type ReferenceCode struct {
+	Directory string    `gorm:"size:32;primaryKey" json:"directory"`
+	Code      string    `gorm:"size:16;primaryKey" json:"code"`
+	Name      string    `gorm:"size:255;not null" json:"name"`
+	SyncedAt  time.Time `gorm:"not null" json:"syncedAt"`
+}
@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SavedView именованное представление списка документов пользователя: фильтры и сортировка.
+// Хранится в основной БД — пользователь применяет свои представления в любой организации
+type SavedView struct {
+	ID     uuid.UUID `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	UserID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_saved_views_user_name" json:"userId"`
+	Name   string    `gorm:"size:100;not null;uniqueIndex:idx_saved_views_user_name" json:"name"`
+	// Filter фильтры списка документов (pagination.DocumentFilterParams)
+	Filter json.RawMessage `gorm:"type:jsonb" json:"filter,omitempty"`
+	// Sort и Order параметры sort и order списка; пустые — сортировка по умолчанию
+	Sort      string    `gorm:"size:200" json:"sort,omitempty"`
+	Order     string    `gorm:"size:4" json:"order,omitempty"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"createdAt"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// TableName возвращает имя таблицы для GORM
+func (SavedView) TableName() string {
+	return "saved_views"
+}
@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Операции поискового outbox
+const (
+	SearchOpIndex  = "index"
+	SearchOpDelete = "delete"
+)
+
+// SearchOutboxEntry событие индексации документа, записанное в БД организации
+// в той же транзакции, что и изменение документа
+type SearchOutboxEntry struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement"`
+	DocumentID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Operation  string    `gorm:"size:16;not null"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+}
+
+func (SearchOutboxEntry) TableName() string {
+	return "search_outbox"
+}
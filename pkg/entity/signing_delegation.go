@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SigningDelegation передача права подписи (отправки документов в ЭСФ) другому пользователю
+// на срок, например на время отпуска. ValidFrom и ValidTo — даты включительно.
+// Хранится в основной БД, так как пользователи общие для всех организаций
+type SigningDelegation struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:uuid_generate_v4()" json:"id"`
+	DelegatorID uuid.UUID  `gorm:"type:uuid;not null;index" json:"delegatorId"`
+	DelegateID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"delegateId"`
+	ValidFrom   time.Time  `gorm:"type:date;not null" json:"validFrom"`
+	ValidTo     time.Time  `gorm:"type:date;not null" json:"validTo"`
+	Reason      string     `gorm:"size:255" json:"reason,omitempty"`
+	CreatedAt   time.Time  `gorm:"autoCreateTime" json:"createdAt"`
+	RevokedAt   *time.Time `json:"revokedAt,omitempty"`
+}
+
+// TableName возвращает имя таблицы для GORM
+func (SigningDelegation) TableName() string {
+	return "signing_delegations"
+}
+
+// ActiveOn сообщает, действует ли делегирование в момент at
+func (d *SigningDelegation) ActiveOn(at time.Time) bool {
+	if d.RevokedAt != nil && !at.Before(*d.RevokedAt) {
+		return false
+	}
+	day := dateOnly(at)
+	return !day.Before(dateOnly(d.ValidFrom)) && !day.After(dateOnly(d.ValidTo))
+}
@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TenantDBCredential учетные данные, с которыми приложение подключается к БД организации.
+// Пока записи нет, используются общие DB_USER и DB_PASSWORD.
+type TenantDBCredential struct {
+	OrganizationID uuid.UUID `gorm:"type:uuid;primaryKey" json:"organizationId"`
+	Database       string    `gorm:"size:63;not null" json:"database"`
+	// Username текущая роль; роли организации чередуются при каждой ротации
+	Username string `gorm:"size:63;not null" json:"username"`
+	// Password хранится зашифрованным
+	Password  string    `gorm:"type:text;not null;serializer:encrypted" json:"-"`
+	RotatedAt time.Time `gorm:"not null" json:"rotatedAt"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// TableName возвращает имя таблицы для GORM
+func (TenantDBCredential) TableName() string {
+	return "tenant_db_credentials"
+}
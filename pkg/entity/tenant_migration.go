@@ -0,0 +1,31 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Результат миграции БД организации
+const (
+	TenantMigrationSucceeded = "succeeded"
+	TenantMigrationFailed    = "failed"
+)
+
+// TenantMigration результат миграции БД одной организации в рамках операции tenant_migration.
+// При возобновлении операции организации с результатом succeeded пропускаются.
+type TenantMigration struct {
+	OperationID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"operationId"`
+	OrganizationID uuid.UUID `gorm:"type:uuid;primaryKey" json:"organizationId"`
+	Database       string    `gorm:"size:63;not null" json:"database"`
+	State          string    `gorm:"size:16;not null" json:"state"`
+	// Applied версии миграций, примененных этой попыткой, через запятую
+	Applied   string    `gorm:"type:text" json:"applied"`
+	Error     string    `gorm:"type:text" json:"error,omitempty"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// TableName возвращает имя таблицы для GORM
+func (TenantMigration) TableName() string {
+	return "tenant_migrations"
+}
@@ -0,0 +1,23 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UsageRecord почасовая сумма тарифицируемой метрики организации (pkg/metering).
+// Хранится в основной БД: по ней выставляются счета организациям
+type UsageRecord struct {
+	OrganizationID uuid.UUID `gorm:"type:uuid;primaryKey" json:"organizationId"`
+	Metric         string    `gorm:"size:50;primaryKey" json:"metric"`
+	// Hour начало часа в UTC
+	Hour      time.Time `gorm:"primaryKey;index" json:"hour"`
+	Quantity  int64     `gorm:"not null;default:0" json:"quantity"`
+	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updatedAt"`
+}
+
+// TableName возвращает имя таблицы для GORM
+func (UsageRecord) TableName() string {
+	return "usage_records"
+}
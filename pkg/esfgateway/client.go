@@ -0,0 +1,185 @@
+package esfgateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/pkg/retry"
+)
+
+// maxErrorBody ограничивает чтение тела ответа с ошибкой
+const maxErrorBody = 4 << 10
+
+// RetryPolicy повторы идемпотентных запросов к шлюзу (справочники, изменение документа)
+// при его недоступности. Регистрация документа не повторяется: шлюз мог принять документ
+// до обрыва соединения, и повтор создал бы дубликат.
+var RetryPolicy = retry.Policy{
+	Name:            "esf gateway",
+	MaxAttempts:     3,
+	InitialInterval: 500 * time.Millisecond,
+	MaxInterval:     5 * time.Second,
+	Jitter:          0.2,
+	MaxElapsed:      time.Minute,
+	Retryable:       func(err error) bool { return errors.Is(err, ErrUnavailable) },
+}
+
+// Client HTTP-клиент шлюза ЭСФ
+type Client struct {
+	baseURL string
+	http    *http.Client
+	retry   retry.Policy
+	logger  *logrus.Logger
+}
+
+// NewClient создает клиент шлюза по базовому URL
+func NewClient(baseURL string, timeout time.Duration) *Client {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Client{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		http:    &http.Client{Timeout: timeout},
+		retry:   RetryPolicy,
+	}
+}
+
+// SetLogger включает запись запросов к шлюзу на уровне debug
+func (c *Client) SetLogger(log *logrus.Logger) {
+	c.logger = log
+}
+
+// CreateInvoice отправляет POST /api/command/invoice/create без повторов
+func (c *Client) CreateInvoice(ctx context.Context, token string, doc *models.EsfCreateDocumentRequest) (*models.EsfCreateDocumentResponse, error) {
+	var resp models.EsfCreateDocumentResponse
+	if err := c.do(ctx, http.MethodPost, CreateInvoicePath, token, newInvoicePayload(doc), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// EditInvoice отправляет PUT /api/command/invoice/edit/{id}, повторяя его при недоступности шлюза
+func (c *Client) EditInvoice(ctx context.Context, token string, id uuid.UUID, doc *models.EsfCreateDocumentRequest) error {
+	return retry.Do(ctx, c.retry, func(ctx context.Context) error {
+		return c.do(ctx, http.MethodPut, EditInvoicePath+id.String(), token, newInvoicePayload(doc), nil)
+	})
+}
+
+// invoicePayload документ в формате шлюза: даты передаются меткой времени RFC 3339 (полночь UTC),
+// а не строкой "2006-01-02", как в API приложения
+type invoicePayload struct {
+	*models.EsfCreateDocumentRequest
+	DeliveryDate      time.Time `json:"deliveryDate"`
+	ContractStartDate time.Time `json:"contractStartDate"`
+}
+
+func newInvoicePayload(doc *models.EsfCreateDocumentRequest) invoicePayload {
+	return invoicePayload{
+		EsfCreateDocumentRequest: doc,
+		DeliveryDate:             doc.DeliveryDate.Time,
+		ContractStartDate:        doc.ContractStartDate.Time,
+	}
+}
+
+// GetDirectory отправляет GET /api/directory/{name}, повторяя его при недоступности шлюза
+func (c *Client) GetDirectory(ctx context.Context, name string) ([]DirectoryEntry, error) {
+	var entries []DirectoryEntry
+	err := retry.Do(ctx, c.retry, func(ctx context.Context) error {
+		entries = nil
+		return c.do(ctx, http.MethodGet, DirectoryPath+name, "", nil, &entries)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path, token string, body, out interface{}) error {
+	var payload io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		payload = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, payload)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	start := time.Now()
+	resp, err := c.http.Do(req)
+	if err != nil {
+		c.trace(method, path, 0, start, err)
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+	c.trace(method, path, resp.StatusCode, start, nil)
+
+	if err := statusError(resp); err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%w: invalid response: %v", ErrUnavailable, err)
+	}
+	return nil
+}
+
+// trace пишет запрос к шлюзу на уровне debug
+func (c *Client) trace(method, path string, status int, start time.Time, err error) {
+	if c.logger == nil || !c.logger.IsLevelEnabled(logrus.DebugLevel) {
+		return
+	}
+	entry := c.logger.WithFields(logrus.Fields{
+		"endpoint":    c.baseURL,
+		"method":      method,
+		"path":        path,
+		"status":      status,
+		"duration_ms": time.Since(start).Milliseconds(),
+	})
+	if err != nil {
+		entry = entry.WithError(err)
+	}
+	entry.Debug("ESF gateway request")
+}
+
+// statusError преобразует код ответа шлюза в ошибку пакета
+func statusError(resp *http.Response) error {
+	if resp.StatusCode < 300 {
+		return nil
+	}
+
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return ErrUnauthorized
+	case resp.StatusCode == http.StatusNotFound:
+		return ErrNotFound
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return fmt.Errorf("%w: %s returned %d: %s", ErrUnavailable, resp.Request.URL.Path, resp.StatusCode, strings.TrimSpace(string(msg)))
+	default:
+		return &RejectedError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(msg))}
+	}
+}
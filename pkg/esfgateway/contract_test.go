@@ -0,0 +1,338 @@
+package esfgateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/pkg/dates"
+)
+
+// Контрактные тесты клиента шлюза: запросы клиента и разбор ответов сверяются с эталонными
+// взаимодействиями из testdata/contract, записанными в песочнице шлюза. Изменение сериализации
+// документа у нас или формата ответов шлюза ломает эти тесты до выкладки.
+//
+// Перезапись эталонов по песочнице (токен в файлы не попадает):
+//
+//	ESF_SANDBOX_URL=https://... ESF_SANDBOX_TOKEN=... go test ./pkg/esfgateway -run TestContract -update
+
+var updateContracts = flag.Bool("update", false, "record contract fixtures against ESF_SANDBOX_URL")
+
+// contractDir каталог эталонных взаимодействий
+const contractDir = "testdata/contract"
+
+// redactedToken заменяет токен организации в записанных заголовках
+const redactedToken = "<token>"
+
+// interaction запрос клиента к шлюзу и ответ шлюза
+type interaction struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+}
+
+// recordedRequest запрос; сравниваются метод, путь, заголовки из contractHeaders и тело как JSON
+type recordedRequest struct {
+	Method  string            `json:"method"`
+	Path    string            `json:"path"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+// recordedResponse ответ; тело в JSON хранится объектом (Body), иначе текстом (Text)
+type recordedResponse struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+	Text    string            `json:"text,omitempty"`
+}
+
+// contractHeaders заголовки, входящие в контракт
+var contractHeaders = []string{"Accept", "Authorization", "Content-Type"}
+
+// contractDocument документ с заполненными полями всех видов: строки, даты, локализованные
+// наименования, позиции, необязательные указатели
+func contractDocument() *models.EsfCreateDocumentRequest {
+	delivery := dates.New(2026, 3, 2)
+	due := delivery.AddDate(0, 0, 30)
+	return &models.EsfCreateDocumentRequest{
+		ContractorNames:                map[string]string{"ru": "ОсОО Альфа", "ky": "Альфа ЖЧК"},
+		Language:                       "ru",
+		IsPriceWithoutTaxes:            true,
+		OwnedCrmReceiptCode:            "CONTRACT-00001",
+		OperationTypeCode:              "10",
+		DeliveryDate:                   delivery,
+		DeliveryTypeCode:               "101",
+		IsResident:                     true,
+		ContractorTin:                  "01234567890123",
+		SupplierBankAccount:            "1280016011000001",
+		CurrencyCode:                   "KGS",
+		CurrencyRate:                   1,
+		TotalCurrencyValue:             1120,
+		TotalCurrencyValueWithoutTaxes: 1000,
+		SupplyContractNumber:           "Д-2026/001",
+		ContractStartDate:              dates.Of(delivery.AddDate(0, -1, 0)),
+		Comment:                        "Поставка по договору",
+		PaymentCode:                    "2",
+		TaxRateVATCode:                 "12",
+		AmountToBePaid:                 1120,
+		DueDate:                        &due,
+		CatalogEntries: []models.EsfEntriesModel{{
+			UnitClassificationCode: "796",
+			SalesTaxCode:           "0",
+			Names:                  map[string]string{"ru": "Бумага офисная A4"},
+			Quantity:               10,
+			Price:                  100,
+			VatAmount:              120,
+			AmountWithoutTaxes:     1000,
+			TotalAmount:            1120,
+		}},
+	}
+}
+
+// contractState данные, которые следующие взаимодействия берут из предыдущих
+type contractState struct {
+	created uuid.UUID
+}
+
+// contract вызов клиента и проверка результата для одного эталона
+type contract struct {
+	name string
+	call func(ctx context.Context, c *Client, token string, state *contractState) error
+}
+
+var contracts = []contract{
+	{name: "create_invoice", call: func(ctx context.Context, c *Client, token string, state *contractState) error {
+		resp, err := c.CreateInvoice(ctx, token, contractDocument())
+		if err != nil {
+			return err
+		}
+		id, err := uuid.Parse(resp.DocumentUuid)
+		if err != nil {
+			return err
+		}
+		if resp.ResponseId == "" {
+			return errors.New("empty responseId")
+		}
+		state.created = id
+		return nil
+	}},
+	{name: "edit_invoice", call: func(ctx context.Context, c *Client, token string, state *contractState) error {
+		doc := contractDocument()
+		doc.Comment = "Исправлена сумма"
+		return c.EditInvoice(ctx, token, state.created, doc)
+	}},
+	{name: "create_invoice_rejected", call: func(ctx context.Context, c *Client, token string, state *contractState) error {
+		doc := contractDocument()
+		doc.ContractorTin = MockRejectTIN
+		_, err := c.CreateInvoice(ctx, token, doc)
+		var rejected *RejectedError
+		if !errors.As(err, &rejected) {
+			return errors.New("expected RejectedError, got " + errString(err))
+		}
+		if rejected.StatusCode < 400 || rejected.StatusCode >= 500 || rejected.Message == "" {
+			return errors.New("unexpected rejection " + rejected.Error())
+		}
+		return nil
+	}},
+	{name: "create_invoice_unauthorized", call: func(ctx context.Context, c *Client, token string, state *contractState) error {
+		_, err := c.CreateInvoice(ctx, "", contractDocument())
+		return expectError(err, ErrUnauthorized)
+	}},
+	{name: "edit_invoice_not_found", call: func(ctx context.Context, c *Client, token string, state *contractState) error {
+		id := uuid.MustParse("3d0f6a2e-0000-4000-8000-000000000000")
+		return expectError(c.EditInvoice(ctx, token, id, contractDocument()), ErrNotFound)
+	}},
+	{name: "directory_unknown", call: func(ctx context.Context, c *Client, token string, state *contractState) error {
+		_, err := c.GetDirectory(ctx, "planets")
+		return expectError(err, ErrNotFound)
+	}},
+}
+
+func init() {
+	for _, name := range Directories {
+		name := name
+		contracts = append(contracts, contract{name: "directory_" + name, call: func(ctx context.Context, c *Client, token string, state *contractState) error {
+			entries, err := c.GetDirectory(ctx, name)
+			if err != nil {
+				return err
+			}
+			if len(entries) == 0 {
+				return errors.New("empty directory " + name)
+			}
+			for _, entry := range entries {
+				if entry.Code == "" || entry.Name == "" {
+					return errors.New("directory entry without code or name")
+				}
+			}
+			return nil
+		}})
+	}
+}
+
+func expectError(err, want error) error {
+	if !errors.Is(err, want) {
+		return errors.New("expected " + want.Error() + ", got " + errString(err))
+	}
+	return nil
+}
+
+func errString(err error) string {
+	if err == nil {
+		return "no error"
+	}
+	return err.Error()
+}
+
+func TestContract(t *testing.T) {
+	if *updateContracts {
+		recordContracts(t)
+		return
+	}
+
+	ctx := context.Background()
+	state := &contractState{}
+	for _, c := range contracts {
+		t.Run(c.name, func(t *testing.T) {
+			recorded := loadInteraction(t, c.name)
+			server := httptest.NewServer(replayHandler(t, recorded))
+			defer server.Close()
+
+			client := NewClient(server.URL, time.Second)
+			client.retry.MaxAttempts = 1
+			require.NoError(t, c.call(ctx, client, "replay-token", state))
+		})
+	}
+}
+
+// replayHandler проверяет запрос клиента по эталону и отвечает записанным ответом
+func replayHandler(t *testing.T, recorded interaction) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		actual := captureRequest(t, r, bearerToken(r))
+		assert.Equal(t, recorded.Request.Method, actual.Method, "method")
+		assert.Equal(t, recorded.Request.Path, actual.Path, "path")
+		assert.Equal(t, recorded.Request.Headers, actual.Headers, "headers")
+		if len(recorded.Request.Body) > 0 || len(actual.Body) > 0 {
+			assert.JSONEq(t, string(recorded.Request.Body), string(actual.Body), "request body")
+		}
+
+		for key, value := range recorded.Response.Headers {
+			w.Header().Set(key, value)
+		}
+		w.WriteHeader(recorded.Response.Status)
+		if len(recorded.Response.Body) > 0 {
+			w.Write(recorded.Response.Body)
+		} else {
+			io.WriteString(w, recorded.Response.Text)
+		}
+	}
+}
+
+// captureRequest переводит запрос в эталонный вид; токен заменяется на redactedToken
+func captureRequest(t *testing.T, r *http.Request, token string) recordedRequest {
+	t.Helper()
+	req := recordedRequest{Method: r.Method, Path: r.URL.Path, Headers: map[string]string{}}
+	for _, key := range contractHeaders {
+		if value := r.Header.Get(key); value != "" {
+			if token != "" {
+				value = strings.ReplaceAll(value, token, redactedToken)
+			}
+			req.Headers[key] = value
+		}
+	}
+	if r.Body != nil {
+		raw, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		r.Body = io.NopCloser(bytes.NewReader(raw))
+		if len(raw) > 0 {
+			req.Body = json.RawMessage(raw)
+		}
+	}
+	return req
+}
+
+func loadInteraction(t *testing.T, name string) interaction {
+	t.Helper()
+	raw, err := os.ReadFile(filepath.Join(contractDir, name+".json"))
+	require.NoError(t, err, "fixture missing: record it with -update")
+	var recorded interaction
+	require.NoError(t, json.Unmarshal(raw, &recorded))
+	return recorded
+}
+
+// recordingTransport записывает последнее взаимодействие клиента с песочницей
+type recordingTransport struct {
+	t     *testing.T
+	token string
+	last  *interaction
+}
+
+func (rt *recordingTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	recorded := &interaction{Request: captureRequest(rt.t, r, rt.token)}
+	resp, err := http.DefaultTransport.RoundTrip(r)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(raw))
+
+	recorded.Response = recordedResponse{Status: resp.StatusCode, Headers: map[string]string{}}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" {
+		recorded.Response.Headers["Content-Type"] = contentType
+	}
+	var compact bytes.Buffer
+	if json.Valid(raw) && json.Compact(&compact, raw) == nil && compact.Len() > 0 {
+		recorded.Response.Body = json.RawMessage(raw)
+	} else {
+		recorded.Response.Text = string(raw)
+	}
+	rt.last = recorded
+	return resp, nil
+}
+
+// recordContracts выполняет контракты против песочницы и перезаписывает эталоны
+func recordContracts(t *testing.T) {
+	baseURL, token := os.Getenv("ESF_SANDBOX_URL"), os.Getenv("ESF_SANDBOX_TOKEN")
+	if baseURL == "" || token == "" {
+		t.Fatal("ESF_SANDBOX_URL and ESF_SANDBOX_TOKEN are required to record contract fixtures")
+	}
+	require.NoError(t, os.MkdirAll(contractDir, 0o755))
+
+	ctx := context.Background()
+	state := &contractState{}
+	for _, c := range contracts {
+		transport := &recordingTransport{t: t, token: token}
+		client := NewClient(baseURL, DefaultTimeout)
+		client.http.Transport = transport
+		client.retry.MaxAttempts = 1
+
+		require.NoError(t, c.call(ctx, client, token, state), c.name)
+		require.NotNil(t, transport.last, c.name)
+
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		require.NoError(t, enc.Encode(transport.last))
+		require.NoError(t, os.WriteFile(filepath.Join(contractDir, c.name+".json"), buf.Bytes(), 0o644))
+		t.Logf("recorded %s", c.name)
+	}
+}
@@ -0,0 +1,74 @@
+package esfgateway
+
+// Справочники ЭСФ, коды из которых указываются в EsfCreateDocumentRequest
+const (
+	DirectoryOperationTypes = "operation-types"
+	DirectoryDeliveryTypes  = "delivery-types"
+	DirectoryPaymentCodes   = "payment-codes"
+	DirectoryVATRates       = "vat-rates"
+	DirectoryCountries      = "countries"
+	DirectoryCurrencies     = "currencies"
+)
+
+// Directories все справочники шлюза в порядке синхронизации
+var Directories = []string{
+	DirectoryOperationTypes,
+	DirectoryDeliveryTypes,
+	DirectoryPaymentCodes,
+	DirectoryVATRates,
+	DirectoryCountries,
+	DirectoryCurrencies,
+}
+
+// IsDirectory проверяет, что name — известный справочник
+func IsDirectory(name string) bool {
+	for _, d := range Directories {
+		if d == name {
+			return true
+		}
+	}
+	return false
+}
+
+// DirectoryEntry код справочника и его наименование
+type DirectoryEntry struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+// mockDirectories справочники, которые отдает имитация шлюза
+var mockDirectories = map[string][]DirectoryEntry{
+	DirectoryOperationTypes: {
+		{Code: "10", Name: "Sale"},
+		{Code: "20", Name: "Purchase"},
+		{Code: "30", Name: "Return"},
+	},
+	DirectoryDeliveryTypes: {
+		{Code: "101", Name: "Delivery of goods"},
+		{Code: "102", Name: "Provision of services"},
+		{Code: "201", Name: "Export"},
+	},
+	DirectoryPaymentCodes: {
+		{Code: "1", Name: "Cash"},
+		{Code: "2", Name: "Bank transfer"},
+	},
+	DirectoryVATRates: {
+		{Code: "0", Name: "0%"},
+		{Code: "12", Name: "12%"},
+	},
+	DirectoryCountries: {
+		{Code: "CN", Name: "China"},
+		{Code: "KG", Name: "Kyrgyzstan"},
+		{Code: "KZ", Name: "Kazakhstan"},
+		{Code: "RU", Name: "Russia"},
+		{Code: "UZ", Name: "Uzbekistan"},
+	},
+	DirectoryCurrencies: {
+		{Code: "CNY", Name: "Chinese yuan"},
+		{Code: "EUR", Name: "Euro"},
+		{Code: "KGS", Name: "Kyrgyz som"},
+		{Code: "KZT", Name: "Kazakh tenge"},
+		{Code: "RUB", Name: "Russian ruble"},
+		{Code: "USD", Name: "US dollar"},
+	},
+}
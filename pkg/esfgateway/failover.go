@@ -0,0 +1,320 @@
+package esfgateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+)
+
+// Параметры переключения адресов шлюза по умолчанию
+const (
+	DefaultFailoverThreshold = 5
+	DefaultFailbackAfter     = 10 * time.Minute
+	DefaultOverrideInterval  = 15 * time.Second
+)
+
+// ErrUnknownEndpoint адрес шлюза с таким именем не настроен
+var ErrUnknownEndpoint = errors.New("esfgateway: unknown endpoint")
+
+// Endpoint адрес шлюза (например prod, sandbox или mirror)
+type Endpoint struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// ParseEndpoints разбирает список "name=url,name=url"; первый адрес основной
+func ParseEndpoints(raw string) ([]Endpoint, error) {
+	var endpoints []Endpoint
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		name, url, ok := strings.Cut(item, "=")
+		name, url = strings.TrimSpace(name), strings.TrimSpace(url)
+		if !ok || name == "" || url == "" {
+			return nil, fmt.Errorf("esfgateway: invalid endpoint %q, expected name=url", item)
+		}
+		endpoints = append(endpoints, Endpoint{Name: name, URL: url})
+	}
+	return endpoints, nil
+}
+
+// OverrideStore хранит принудительно выбранный адрес, общий для всех инстансов
+type OverrideStore interface {
+	// Forced возвращает имя выбранного адреса; пустая строка — автоматический выбор
+	Forced(ctx context.Context) (string, error)
+	SetForced(ctx context.Context, name string) error
+}
+
+// EndpointStatus состояние адреса шлюза
+type EndpointStatus struct {
+	Endpoint
+	Active bool `json:"active"`
+}
+
+// FailoverStatus состояние переключения адресов шлюза
+type FailoverStatus struct {
+	Active string `json:"active"`
+	// Forced адрес, выбранный администратором; пустой — автоматическое переключение
+	Forced string `json:"forced,omitempty"`
+	// Failures ошибки доступности активного адреса подряд
+	Failures   int              `json:"failures"`
+	Threshold  int              `json:"threshold"`
+	SwitchedAt time.Time        `json:"switchedAt,omitempty"`
+	LastError  string           `json:"lastError,omitempty"`
+	Endpoints  []EndpointStatus `json:"endpoints"`
+}
+
+// Failover шлюз с несколькими адресами. Запросы идут на активный адрес; после Threshold
+// ошибок доступности (ErrUnavailable) подряд активным становится следующий адрес, а через
+// FailbackAfter — снова основной. Неудачный запрос не повторяется на другом адресе: шлюз мог
+// зарегистрировать документ до обрыва соединения. Администратор может закрепить адрес (Force),
+// тогда автоматическое переключение не выполняется.
+type Failover struct {
+	endpoints     []Endpoint
+	clients       []*Client
+	threshold     int
+	failbackAfter time.Duration
+	now           func() time.Time
+
+	store    OverrideStore
+	onSwitch func(from, to Endpoint, reason string)
+
+	mu         sync.Mutex
+	active     int
+	forced     int
+	failures   int
+	switchedAt time.Time
+	lastError  string
+}
+
+// NewFailover создает шлюз с адресами endpoints; threshold <= 0 — DefaultFailoverThreshold,
+// failbackAfter <= 0 — без возврата на основной адрес
+func NewFailover(endpoints []Endpoint, timeout time.Duration, threshold int, failbackAfter time.Duration) (*Failover, error) {
+	if len(endpoints) == 0 {
+		return nil, errors.New("esfgateway: at least one endpoint is required")
+	}
+	seen := make(map[string]bool, len(endpoints))
+	clients := make([]*Client, len(endpoints))
+	for i, ep := range endpoints {
+		if ep.Name == "" || ep.URL == "" {
+			return nil, fmt.Errorf("esfgateway: endpoint %d needs a name and a URL", i+1)
+		}
+		if seen[ep.Name] {
+			return nil, fmt.Errorf("esfgateway: duplicate endpoint %q", ep.Name)
+		}
+		seen[ep.Name] = true
+		clients[i] = NewClient(ep.URL, timeout)
+	}
+	if threshold <= 0 {
+		threshold = DefaultFailoverThreshold
+	}
+	return &Failover{
+		endpoints:     endpoints,
+		clients:       clients,
+		threshold:     threshold,
+		failbackAfter: failbackAfter,
+		now:           time.Now,
+		forced:        -1,
+	}, nil
+}
+
+// SetLogger включает запись запросов ко всем адресам шлюза на уровне debug
+func (f *Failover) SetLogger(log *logrus.Logger) {
+	for _, client := range f.clients {
+		client.SetLogger(log)
+	}
+}
+
+// SetOverrideStore включает общий для инстансов выбор адреса; вызывается до Run
+func (f *Failover) SetOverrideStore(store OverrideStore) {
+	f.store = store
+}
+
+// OnSwitch задает обработчик смены активного адреса (например, для журнала)
+func (f *Failover) OnSwitch(fn func(from, to Endpoint, reason string)) {
+	f.onSwitch = fn
+}
+
+// Run применяет выбор адреса, сделанный на других инстансах, каждые interval до отмены ctx
+func (f *Failover) Run(ctx context.Context, interval time.Duration) {
+	if f.store == nil {
+		return
+	}
+	if interval <= 0 {
+		interval = DefaultOverrideInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		f.syncOverride(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (f *Failover) syncOverride(ctx context.Context) {
+	name, err := f.store.Forced(ctx)
+	if err != nil {
+		return
+	}
+	index := -1
+	if name != "" {
+		if index = f.indexOf(name); index < 0 {
+			return
+		}
+	}
+	f.applyForced(index)
+}
+
+// Force закрепляет адрес name на всех инстансах; пустое имя возвращает автоматическое переключение
+func (f *Failover) Force(ctx context.Context, name string) error {
+	index := -1
+	if name != "" {
+		if index = f.indexOf(name); index < 0 {
+			return fmt.Errorf("%w: %s", ErrUnknownEndpoint, name)
+		}
+	}
+	if f.store != nil {
+		if err := f.store.SetForced(ctx, name); err != nil {
+			return err
+		}
+	}
+	f.applyForced(index)
+	return nil
+}
+
+func (f *Failover) applyForced(index int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.forced == index {
+		return
+	}
+	f.forced = index
+	if index >= 0 {
+		f.switchTo(index, "forced")
+	}
+}
+
+// Status возвращает состояние адресов
+func (f *Failover) Status() FailoverStatus {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	status := FailoverStatus{
+		Active:     f.endpoints[f.active].Name,
+		Failures:   f.failures,
+		Threshold:  f.threshold,
+		SwitchedAt: f.switchedAt,
+		LastError:  f.lastError,
+	}
+	if f.forced >= 0 {
+		status.Forced = f.endpoints[f.forced].Name
+	}
+	for i, ep := range f.endpoints {
+		status.Endpoints = append(status.Endpoints, EndpointStatus{Endpoint: ep, Active: i == f.active})
+	}
+	return status
+}
+
+// Check возвращает ошибку, если последние запросы к активному адресу не дошли до шлюза.
+// Шлюз не опрашивается: состояние берется из результатов рабочих запросов (для /health).
+func (f *Failover) Check(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.failures == 0 {
+		return nil
+	}
+	return fmt.Errorf("endpoint %s: %d consecutive failures, last: %s", f.endpoints[f.active].Name, f.failures, f.lastError)
+}
+
+// CreateInvoice регистрирует счет-фактуру через активный адрес
+func (f *Failover) CreateInvoice(ctx context.Context, token string, doc *models.EsfCreateDocumentRequest) (*models.EsfCreateDocumentResponse, error) {
+	i, client := f.pick()
+	resp, err := client.CreateInvoice(ctx, token, doc)
+	f.record(ctx, i, err)
+	return resp, err
+}
+
+// EditInvoice изменяет документ через активный адрес
+func (f *Failover) EditInvoice(ctx context.Context, token string, id uuid.UUID, doc *models.EsfCreateDocumentRequest) error {
+	i, client := f.pick()
+	err := client.EditInvoice(ctx, token, id, doc)
+	f.record(ctx, i, err)
+	return err
+}
+
+// GetDirectory возвращает справочник через активный адрес
+func (f *Failover) GetDirectory(ctx context.Context, name string) ([]DirectoryEntry, error) {
+	i, client := f.pick()
+	entries, err := client.GetDirectory(ctx, name)
+	f.record(ctx, i, err)
+	return entries, err
+}
+
+// pick возвращает активный адрес, возвращаясь на основной по истечении failbackAfter
+func (f *Failover) pick() (int, *Client) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.forced < 0 && f.active != 0 && f.failbackAfter > 0 && f.now().Sub(f.switchedAt) >= f.failbackAfter {
+		f.switchTo(0, "failback")
+	}
+	return f.active, f.clients[f.active]
+}
+
+// record учитывает результат запроса к адресу i. Любой ответ шлюза, кроме ErrUnavailable,
+// означает, что адрес доступен; запросы, отмененные вызывающей стороной, не учитываются.
+func (f *Failover) record(ctx context.Context, i int, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if i != f.active {
+		return
+	}
+	if !errors.Is(err, ErrUnavailable) {
+		f.failures = 0
+		return
+	}
+	f.failures++
+	f.lastError = err.Error()
+	if f.forced < 0 && len(f.endpoints) > 1 && f.failures >= f.threshold {
+		f.switchTo((f.active+1)%len(f.endpoints), "unavailable")
+	}
+}
+
+// switchTo делает адрес index активным; вызывается под f.mu
+func (f *Failover) switchTo(index int, reason string) {
+	from := f.endpoints[f.active]
+	f.active = index
+	f.failures = 0
+	f.switchedAt = f.now()
+	if f.onSwitch != nil && from.Name != f.endpoints[index].Name {
+		f.onSwitch(from, f.endpoints[index], reason)
+	}
+}
+
+func (f *Failover) indexOf(name string) int {
+	for i, ep := range f.endpoints {
+		if ep.Name == name {
+			return i
+		}
+	}
+	return -1
+}
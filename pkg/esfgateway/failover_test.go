@@ -0,0 +1,163 @@
+package esfgateway
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// switchableServer отвечает 503, пока down, иначе отдает пустой справочник
+type switchableServer struct {
+	*httptest.Server
+	down  atomic.Bool
+	calls atomic.Int64
+}
+
+func newSwitchableServer(t *testing.T) *switchableServer {
+	s := &switchableServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.calls.Add(1)
+		if s.down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`[]`))
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+// newTestFailover создает Failover без повторов внутри клиента, чтобы каждый вызов был одной попыткой
+func newTestFailover(t *testing.T, endpoints []Endpoint, threshold int, failbackAfter time.Duration) *Failover {
+	t.Helper()
+	gw, err := NewFailover(endpoints, time.Second, threshold, failbackAfter)
+	require.NoError(t, err)
+	for _, c := range gw.clients {
+		c.retry.MaxAttempts = 1
+	}
+	return gw
+}
+
+// memoryOverrideStore общий выбор адреса для нескольких Failover в тесте
+type memoryOverrideStore struct {
+	name atomic.Value
+}
+
+func (s *memoryOverrideStore) Forced(ctx context.Context) (string, error) {
+	name, _ := s.name.Load().(string)
+	return name, nil
+}
+
+func (s *memoryOverrideStore) SetForced(ctx context.Context, name string) error {
+	s.name.Store(name)
+	return nil
+}
+
+func TestParseEndpoints(t *testing.T) {
+	endpoints, err := ParseEndpoints(" prod=https://esf.example.kg , mirror=https://mirror.example.kg,")
+	require.NoError(t, err)
+	assert.Equal(t, []Endpoint{{Name: "prod", URL: "https://esf.example.kg"}, {Name: "mirror", URL: "https://mirror.example.kg"}}, endpoints)
+
+	_, err = ParseEndpoints("https://esf.example.kg")
+	assert.Error(t, err)
+}
+
+func TestFailover_SwitchesOnSustainedErrorsAndFailsBack(t *testing.T) {
+	prod, mirror := newSwitchableServer(t), newSwitchableServer(t)
+	gw := newTestFailover(t, []Endpoint{{Name: "prod", URL: prod.URL}, {Name: "mirror", URL: mirror.URL}}, 2, time.Minute)
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	gw.now = func() time.Time { return now }
+	var switches []string
+	gw.OnSwitch(func(from, to Endpoint, reason string) { switches = append(switches, from.Name+">"+to.Name+":"+reason) })
+	ctx := context.Background()
+
+	prod.down.Store(true)
+	_, err := gw.GetDirectory(ctx, DirectoryCurrencies)
+	assert.ErrorIs(t, err, ErrUnavailable, "the failed request is not retried on the mirror")
+	assert.Equal(t, "prod", gw.Status().Active)
+	_, err = gw.GetDirectory(ctx, DirectoryCurrencies)
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.Equal(t, "mirror", gw.Status().Active)
+
+	_, err = gw.GetDirectory(ctx, DirectoryCurrencies)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), mirror.calls.Load())
+
+	// Через FailbackAfter запросы снова идут на основной адрес
+	prod.down.Store(false)
+	now = now.Add(time.Minute)
+	_, err = gw.GetDirectory(ctx, DirectoryCurrencies)
+	require.NoError(t, err)
+	assert.Equal(t, "prod", gw.Status().Active)
+	assert.Equal(t, []string{"prod>mirror:unavailable", "mirror>prod:failback"}, switches)
+}
+
+func TestFailover_SuccessResetsFailures(t *testing.T) {
+	prod, mirror := newSwitchableServer(t), newSwitchableServer(t)
+	gw := newTestFailover(t, []Endpoint{{Name: "prod", URL: prod.URL}, {Name: "mirror", URL: mirror.URL}}, 2, 0)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		prod.down.Store(true)
+		_, _ = gw.GetDirectory(ctx, DirectoryCurrencies)
+		prod.down.Store(false)
+		_, err := gw.GetDirectory(ctx, DirectoryCurrencies)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, "prod", gw.Status().Active)
+	assert.Zero(t, mirror.calls.Load())
+}
+
+func TestFailover_Check(t *testing.T) {
+	prod := newSwitchableServer(t)
+	gw := newTestFailover(t, []Endpoint{{Name: "prod", URL: prod.URL}}, 2, 0)
+	ctx := context.Background()
+	require.NoError(t, gw.Check(ctx))
+
+	prod.down.Store(true)
+	_, _ = gw.GetDirectory(ctx, DirectoryCurrencies)
+	err := gw.Check(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "endpoint prod: 1 consecutive failures")
+
+	prod.down.Store(false)
+	_, err = gw.GetDirectory(ctx, DirectoryCurrencies)
+	require.NoError(t, err)
+	assert.NoError(t, gw.Check(ctx))
+}
+
+func TestFailover_ForceAppliesOnAllInstances(t *testing.T) {
+	prod, sandbox := newSwitchableServer(t), newSwitchableServer(t)
+	endpoints := []Endpoint{{Name: "prod", URL: prod.URL}, {Name: "sandbox", URL: sandbox.URL}}
+	store := &memoryOverrideStore{}
+	first := newTestFailover(t, endpoints, 1, 0)
+	second := newTestFailover(t, endpoints, 1, 0)
+	first.SetOverrideStore(store)
+	second.SetOverrideStore(store)
+	ctx := context.Background()
+
+	assert.ErrorIs(t, first.Force(ctx, "staging"), ErrUnknownEndpoint)
+	require.NoError(t, first.Force(ctx, "sandbox"))
+	second.syncOverride(ctx)
+	assert.Equal(t, FailoverStatus{
+		Active: "sandbox", Forced: "sandbox", Threshold: 1, SwitchedAt: second.Status().SwitchedAt,
+		Endpoints: []EndpointStatus{{Endpoint: endpoints[0]}, {Endpoint: endpoints[1], Active: true}},
+	}, second.Status())
+
+	// Закрепленный адрес не меняется при ошибках
+	sandbox.down.Store(true)
+	_, _ = second.GetDirectory(ctx, DirectoryCurrencies)
+	assert.Equal(t, "sandbox", second.Status().Active)
+
+	require.NoError(t, first.Force(ctx, ""))
+	second.syncOverride(ctx)
+	assert.Empty(t, second.Status().Forced)
+	_, _ = second.GetDirectory(ctx, DirectoryCurrencies)
+	assert.Equal(t, "prod", second.Status().Active, "automatic failover resumes")
+}
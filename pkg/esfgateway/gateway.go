@@ -0,0 +1,121 @@
+// Package esfgateway отправляет счета-фактуры во внешний шлюз ЭСФ налоговой службы.
+//
+// Бэкенды:
+//   - http — настоящий шлюз (или песочница) по ESF_GATEWAY_URL либо несколько адресов
+//     ESF_GATEWAY_ENDPOINTS с автоматическим переключением при недоступности (см. Failover);
+//   - mock — встроенная имитация шлюза для разработки и интеграционных тестов: не требует учетных
+//     данных песочницы, выдает детерминированные UUID документов, умеет добавлять задержку и ошибки.
+//
+// Организация авторизуется в шлюзе своим токеном (EsfOrganizationModel.Token).
+package esfgateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+)
+
+// Бэкенды шлюза
+const (
+	BackendHTTP = "http"
+	BackendMock = "mock"
+)
+
+// Пути API шлюза
+const (
+	CreateInvoicePath = "/api/command/invoice/create"
+	EditInvoicePath   = "/api/command/invoice/edit/"
+	DirectoryPath     = "/api/directory/"
+)
+
+// PrimaryEndpoint имя адреса ESF_GATEWAY_URL, если ESF_GATEWAY_ENDPOINTS не задан
+const PrimaryEndpoint = "primary"
+
+// DefaultTimeout таймаут запроса к шлюзу
+const DefaultTimeout = 30 * time.Second
+
+var (
+	// ErrUnavailable шлюз недоступен или вернул 5xx; запрос можно повторить
+	ErrUnavailable = errors.New("esf gateway unavailable")
+	// ErrUnauthorized токен организации отклонен
+	ErrUnauthorized = errors.New("esf gateway: invalid organization token")
+	// ErrNotFound документ или справочник не найден в шлюзе
+	ErrNotFound = errors.New("esf gateway: document not found")
+)
+
+// RejectedError шлюз отклонил документ (ошибка в данных); повтор не поможет
+type RejectedError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("esf gateway rejected document (%d): %s", e.StatusCode, e.Message)
+}
+
+// Gateway клиент шлюза ЭСФ
+type Gateway interface {
+	// CreateInvoice регистрирует счет-фактуру и возвращает UUID документа в ЭСФ
+	CreateInvoice(ctx context.Context, token string, doc *models.EsfCreateDocumentRequest) (*models.EsfCreateDocumentResponse, error)
+	// EditInvoice изменяет ранее зарегистрированный документ
+	EditInvoice(ctx context.Context, token string, id uuid.UUID, doc *models.EsfCreateDocumentRequest) error
+	// GetDirectory возвращает коды справочника name (см. Directories); справочники публичные, токен не нужен
+	GetDirectory(ctx context.Context, name string) ([]DirectoryEntry, error)
+}
+
+// Config параметры шлюза
+type Config struct {
+	// Backend http или mock; пустое значение — mock, если не задан ни URL, ни Endpoints, иначе http
+	Backend string
+	URL     string
+	// Endpoints адреса шлюза в порядке переключения; без них используется единственный адрес URL
+	Endpoints []Endpoint
+	Timeout   time.Duration
+	// FailoverThreshold ошибок доступности подряд до переключения на следующий адрес
+	FailoverThreshold int
+	// FailbackAfter время до возврата на основной адрес; 0 — без возврата
+	FailbackAfter time.Duration
+
+	Mock MockConfig
+
+	// Logger пишет запросы к шлюзу на уровне debug; nil — без логирования
+	Logger *logrus.Logger
+}
+
+// New создает шлюз по конфигурации
+func New(cfg Config) (Gateway, error) {
+	backend := cfg.Backend
+	if backend == "" {
+		backend = BackendHTTP
+		if cfg.URL == "" && len(cfg.Endpoints) == 0 {
+			backend = BackendMock
+		}
+	}
+
+	switch backend {
+	case BackendHTTP:
+		endpoints := cfg.Endpoints
+		if len(endpoints) == 0 {
+			if cfg.URL == "" {
+				return nil, errors.New("esfgateway: URL or endpoints are required for the http backend")
+			}
+			endpoints = []Endpoint{{Name: PrimaryEndpoint, URL: cfg.URL}}
+		}
+		failover, err := NewFailover(endpoints, cfg.Timeout, cfg.FailoverThreshold, cfg.FailbackAfter)
+		if err != nil {
+			return nil, err
+		}
+		failover.SetLogger(cfg.Logger)
+		return failover, nil
+	case BackendMock:
+		return NewMock(cfg.Mock), nil
+	default:
+		return nil, fmt.Errorf("esfgateway: unknown backend %q", cfg.Backend)
+	}
+}
@@ -0,0 +1,143 @@
+package esfgateway
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+)
+
+func testDocument() *models.EsfCreateDocumentRequest {
+	return &models.EsfCreateDocumentRequest{
+		OperationTypeCode: "10",
+		DeliveryTypeCode:  "101",
+		ContractorTin:     "01234567890123",
+		CurrencyCode:      "KGS",
+		CatalogEntries:    []models.EsfEntriesModel{{Quantity: 1, Price: 100, TotalAmount: 100}},
+	}
+}
+
+func TestMock_DeterministicUUID(t *testing.T) {
+	first, err := NewMock(MockConfig{}).CreateInvoice(context.Background(), "token-a", testDocument())
+	require.NoError(t, err)
+	second, err := NewMock(MockConfig{}).CreateInvoice(context.Background(), "token-a", testDocument())
+	require.NoError(t, err)
+	other, err := NewMock(MockConfig{}).CreateInvoice(context.Background(), "token-b", testDocument())
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.NotEqual(t, first.DocumentUuid, other.DocumentUuid)
+	assert.Equal(t, MockDocumentUUID("token-a", testDocument()).String(), first.DocumentUuid)
+}
+
+func TestMock_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := NewMock(MockConfig{ErrorRate: 1}).CreateInvoice(ctx, "token", testDocument())
+	assert.ErrorIs(t, err, ErrUnavailable)
+
+	_, err = NewMock(MockConfig{}).CreateInvoice(ctx, "", testDocument())
+	assert.ErrorIs(t, err, ErrUnauthorized)
+
+	doc := testDocument()
+	doc.ContractorTin = MockRejectTIN
+	_, err = NewMock(MockConfig{}).CreateInvoice(ctx, "token", doc)
+	var rejected *RejectedError
+	require.True(t, errors.As(err, &rejected))
+	assert.Equal(t, http.StatusUnprocessableEntity, rejected.StatusCode)
+
+	err = NewMock(MockConfig{}).EditInvoice(ctx, "token", uuid.New(), testDocument())
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestMock_LatencyRespectsContext(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := NewMock(MockConfig{Latency: time.Minute}).CreateInvoice(ctx, "token", testDocument())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestClient_AgainstMockHandler(t *testing.T) {
+	server := httptest.NewServer(NewMockHandler(NewMock(MockConfig{})))
+	defer server.Close()
+
+	client := NewClient(server.URL, time.Second)
+	ctx := context.Background()
+
+	resp, err := client.CreateInvoice(ctx, "token", testDocument())
+	require.NoError(t, err)
+	assert.Equal(t, MockDocumentUUID("token", testDocument()).String(), resp.DocumentUuid)
+
+	id := uuid.MustParse(resp.DocumentUuid)
+	require.NoError(t, client.EditInvoice(ctx, "token", id, testDocument()))
+	assert.ErrorIs(t, client.EditInvoice(ctx, "other-token", id, testDocument()), ErrNotFound)
+
+	_, err = client.CreateInvoice(ctx, "", testDocument())
+	assert.ErrorIs(t, err, ErrUnauthorized)
+
+	doc := testDocument()
+	doc.ContractorTin = MockRejectTIN
+	_, err = client.CreateInvoice(ctx, "token", doc)
+	var rejected *RejectedError
+	require.True(t, errors.As(err, &rejected))
+	assert.Equal(t, "contractor TIN is not registered", rejected.Message)
+}
+
+func TestClient_GetDirectory(t *testing.T) {
+	server := httptest.NewServer(NewMockHandler(NewMock(MockConfig{})))
+	defer server.Close()
+
+	client := NewClient(server.URL, time.Second)
+	ctx := context.Background()
+
+	for _, name := range Directories {
+		entries, err := client.GetDirectory(ctx, name)
+		require.NoError(t, err, name)
+		assert.NotEmpty(t, entries, name)
+	}
+
+	currencies, err := client.GetDirectory(ctx, DirectoryCurrencies)
+	require.NoError(t, err)
+	assert.Contains(t, currencies, DirectoryEntry{Code: "KGS", Name: "Kyrgyz som"})
+
+	_, err = client.GetDirectory(ctx, "planets")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestClient_Unavailable(t *testing.T) {
+	server := httptest.NewServer(NewMockHandler(NewMock(MockConfig{ErrorRate: 1})))
+	defer server.Close()
+
+	_, err := NewClient(server.URL, time.Second).CreateInvoice(context.Background(), "token", testDocument())
+	assert.ErrorIs(t, err, ErrUnavailable)
+}
+
+func TestNew_Backends(t *testing.T) {
+	gw, err := New(Config{})
+	require.NoError(t, err)
+	assert.IsType(t, &Mock{}, gw)
+
+	gw, err = New(Config{URL: "https://esf.example.kg"})
+	require.NoError(t, err)
+	require.IsType(t, &Failover{}, gw)
+	assert.Equal(t, []EndpointStatus{{Endpoint: Endpoint{Name: PrimaryEndpoint, URL: "https://esf.example.kg"}, Active: true}},
+		gw.(*Failover).Status().Endpoints)
+
+	_, err = New(Config{Endpoints: []Endpoint{{Name: "prod", URL: "https://esf.example.kg"}, {Name: "prod", URL: "https://mirror.example.kg"}}})
+	assert.Error(t, err, "duplicate endpoint names")
+
+	_, err = New(Config{Backend: BackendHTTP})
+	assert.Error(t, err)
+
+	_, err = New(Config{Backend: "soap"})
+	assert.Error(t, err)
+}
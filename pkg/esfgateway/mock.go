@@ -0,0 +1,215 @@
+package esfgateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+)
+
+// MockRejectTIN ИНН покупателя, документы с которым имитация шлюза отклоняет как ошибочные
+const MockRejectTIN = "00000000000000"
+
+// mockNamespace пространство имен UUID документов имитации шлюза
+var mockNamespace = uuid.MustParse("6f1c2a4e-5b7d-4e2f-9a3c-8d0e1f2a3b4c")
+
+// MockConfig параметры имитации шлюза
+type MockConfig struct {
+	// Latency задержка каждого ответа
+	Latency time.Duration
+	// ErrorRate доля запросов (0..1), на которые возвращается ErrUnavailable
+	ErrorRate float64
+	// Seed начальное значение генератора для внедрения ошибок
+	Seed int64
+}
+
+// Mock имитация шлюза ЭСФ в памяти процесса
+type Mock struct {
+	cfg MockConfig
+
+	mu       sync.Mutex
+	rng      *rand.Rand
+	invoices map[uuid.UUID]string // UUID документа -> токен организации
+}
+
+// NewMock создает имитацию шлюза
+func NewMock(cfg MockConfig) *Mock {
+	return &Mock{
+		cfg:      cfg,
+		rng:      rand.New(rand.NewSource(cfg.Seed)),
+		invoices: make(map[uuid.UUID]string),
+	}
+}
+
+// MockDocumentUUID UUID, который имитация выдает документу: зависит только от токена и содержимого,
+// поэтому повторная отправка того же документа дает тот же UUID
+func MockDocumentUUID(token string, doc *models.EsfCreateDocumentRequest) uuid.UUID {
+	raw, _ := json.Marshal(doc)
+	return uuid.NewSHA1(mockNamespace, append([]byte(token+"\x00"), raw...))
+}
+
+// CreateInvoice регистрирует документ в памяти
+func (m *Mock) CreateInvoice(ctx context.Context, token string, doc *models.EsfCreateDocumentRequest) (*models.EsfCreateDocumentResponse, error) {
+	if err := m.simulate(ctx, token, doc); err != nil {
+		return nil, err
+	}
+
+	id := MockDocumentUUID(token, doc)
+	m.mu.Lock()
+	m.invoices[id] = token
+	m.mu.Unlock()
+
+	return &models.EsfCreateDocumentResponse{
+		ResponseId:   uuid.NewSHA1(mockNamespace, []byte("response:"+id.String())).String(),
+		DocumentUuid: id.String(),
+	}, nil
+}
+
+// EditInvoice принимает изменение документа, ранее созданного этой организацией
+func (m *Mock) EditInvoice(ctx context.Context, token string, id uuid.UUID, doc *models.EsfCreateDocumentRequest) error {
+	if err := m.simulate(ctx, token, doc); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	owner, ok := m.invoices[id]
+	m.mu.Unlock()
+	if !ok || owner != token {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// GetDirectory отдает встроенные коды справочника
+func (m *Mock) GetDirectory(ctx context.Context, name string) ([]DirectoryEntry, error) {
+	if err := m.inject(ctx); err != nil {
+		return nil, err
+	}
+	entries, ok := mockDirectories[name]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]DirectoryEntry(nil), entries...), nil
+}
+
+// simulate выдерживает задержку, внедряет сбои и проверяет запрос
+func (m *Mock) simulate(ctx context.Context, token string, doc *models.EsfCreateDocumentRequest) error {
+	if err := m.inject(ctx); err != nil {
+		return err
+	}
+
+	switch {
+	case token == "":
+		return ErrUnauthorized
+	case doc == nil || len(doc.CatalogEntries) == 0:
+		return &RejectedError{StatusCode: http.StatusBadRequest, Message: "catalogEntries is required"}
+	case doc.ContractorTin == MockRejectTIN:
+		return &RejectedError{StatusCode: http.StatusUnprocessableEntity, Message: "contractor TIN is not registered"}
+	}
+	return nil
+}
+
+// inject выдерживает задержку и внедряет сбои
+func (m *Mock) inject(ctx context.Context) error {
+	if m.cfg.Latency > 0 {
+		timer := time.NewTimer(m.cfg.Latency)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if m.cfg.ErrorRate > 0 {
+		m.mu.Lock()
+		fail := m.rng.Float64() < m.cfg.ErrorRate
+		m.mu.Unlock()
+		if fail {
+			return fmt.Errorf("%w: injected failure", ErrUnavailable)
+		}
+	}
+	return nil
+}
+
+// NewMockHandler отдает API шлюза поверх gw, чтобы фронтенд и внешние тесты могли обращаться
+// к имитации по HTTP (ESF_GATEWAY_URL=http://localhost:3000/mock-esf)
+func NewMockHandler(gw Gateway) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST "+CreateInvoicePath, func(w http.ResponseWriter, r *http.Request) {
+		var doc models.EsfCreateDocumentRequest
+		if err := json.NewDecoder(r.Body).Decode(&doc); err != nil {
+			http.Error(w, "invalid JSON body", http.StatusBadRequest)
+			return
+		}
+		resp, err := gw.CreateInvoice(r.Context(), bearerToken(r), &doc)
+		if err != nil {
+			writeMockError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(resp)
+	})
+
+	mux.HandleFunc("PUT "+EditInvoicePath+"{id}", func(w http.ResponseWriter, r *http.Request) {
+		id, err := uuid.Parse(r.PathValue("id"))
+		if err != nil {
+			http.Error(w, "invalid document id", http.StatusBadRequest)
+			return
+		}
+		var doc models.EsfCreateDocumentRequest
+		if err := json.NewDecoder(r.Body).Decode(&doc); err != nil {
+			http.Error(w, "invalid JSON body", http.StatusBadRequest)
+			return
+		}
+		if err := gw.EditInvoice(r.Context(), bearerToken(r), id, &doc); err != nil {
+			writeMockError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	mux.HandleFunc("GET "+DirectoryPath+"{name}", func(w http.ResponseWriter, r *http.Request) {
+		entries, err := gw.GetDirectory(r.Context(), r.PathValue("name"))
+		if err != nil {
+			writeMockError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(entries)
+	})
+
+	return mux
+}
+
+func bearerToken(r *http.Request) string {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+func writeMockError(w http.ResponseWriter, err error) {
+	var rejected *RejectedError
+	switch {
+	case errors.As(err, &rejected):
+		http.Error(w, rejected.Message, rejected.StatusCode)
+	case errors.Is(err, ErrUnauthorized):
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+	case errors.Is(err, ErrNotFound):
+		http.Error(w, "not found", http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+	}
+}
@@ -0,0 +1,42 @@
+package esfgateway
+
+import (
+	"context"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultOverrideKey ключ Redis с адресом шлюза, выбранным администратором
+const DefaultOverrideKey = "esf_gateway:forced_endpoint"
+
+// RedisOverrideStore хранит выбранный адрес шлюза в Redis
+type RedisOverrideStore struct {
+	client *redis.Client
+	key    string
+}
+
+// NewRedisOverrideStore создает хранилище выбора адреса; пустой key — DefaultOverrideKey
+func NewRedisOverrideStore(client *redis.Client, key string) *RedisOverrideStore {
+	if key == "" {
+		key = DefaultOverrideKey
+	}
+	return &RedisOverrideStore{client: client, key: key}
+}
+
+// Forced возвращает выбранный адрес или пустую строку
+func (s *RedisOverrideStore) Forced(ctx context.Context) (string, error) {
+	name, err := s.client.Get(ctx, s.key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	return name, err
+}
+
+// SetForced сохраняет выбранный адрес; пустое имя удаляет выбор
+func (s *RedisOverrideStore) SetForced(ctx context.Context, name string) error {
+	if name == "" {
+		return s.client.Del(ctx, s.key).Err()
+	}
+	return s.client.Set(ctx, s.key, name, 0).Err()
+}
@@ -0,0 +1,78 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// Параметры шины событий по умолчанию
+const (
+	DefaultStream        = "events"
+	DefaultStreamMaxLen  = 100000
+	DefaultRelayInterval = time.Second
+	DefaultRetention     = 30 * 24 * time.Hour
+)
+
+// Publisher публикует доменные события в шину
+type Publisher interface {
+	Publish(ctx context.Context, event entity.OutboxEvent) error
+}
+
+// RedisStreamBus публикует события в поток Redis. Каждое сообщение содержит поля
+// id, type, organization_id и event (JSON события); потребители читают поток через consumer group.
+type RedisStreamBus struct {
+	client *redis.Client
+	stream string
+	maxLen int64
+}
+
+// NewRedisStreamBus создает шину поверх потока Redis; поток обрезается примерно до maxLen сообщений
+func NewRedisStreamBus(client *redis.Client, stream string, maxLen int64) *RedisStreamBus {
+	if stream == "" {
+		stream = DefaultStream
+	}
+	if maxLen <= 0 {
+		maxLen = DefaultStreamMaxLen
+	}
+	return &RedisStreamBus{client: client, stream: stream, maxLen: maxLen}
+}
+
+// Publish добавляет событие в поток
+func (b *RedisStreamBus) Publish(ctx context.Context, event entity.OutboxEvent) error {
+	raw, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return b.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: b.stream,
+		MaxLen: b.maxLen,
+		Approx: true,
+		Values: map[string]interface{}{
+			"id":              event.EventID.String(),
+			"type":            event.Type,
+			"organization_id": event.OrganizationID.String(),
+			"event":           string(raw),
+		},
+	}).Err()
+}
+
+// Stream возвращает имя потока событий
+func (b *RedisStreamBus) Stream() string {
+	return b.stream
+}
+
+// Config параметры шины и ретранслятора outbox
+type Config struct {
+	Stream       string
+	StreamMaxLen int64
+	// RelayInterval период опроса outbox ретранслятором
+	RelayInterval time.Duration
+	// Retention срок хранения опубликованных событий для повторной отправки
+	Retention time.Duration
+}
@@ -0,0 +1,114 @@
+// Package events описывает доменные события приложения и их публикацию в шину событий.
+//
+// События пишутся в таблицу event_outbox в транзакции изменения (transactional outbox),
+// а EventRelay публикует их в шину. Доставка — at-least-once: потребители должны
+// дедуплицировать события по ID.
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// Типы агрегатов
+const (
+	AggregateDocument     = "document"
+	AggregateOrganization = "organization"
+)
+
+// Типы доменных событий
+const (
+	DocumentCreated  = "document.created"
+	DocumentUpdated  = "document.updated"
+	DocumentDeleted  = "document.deleted"
+	DocumentRestored = "document.restored"
+	DocumentPurged   = "document.purged"
+	// DocumentStatusChanged статус документа в ЭСФ изменен обратным вызовом шлюза
+	DocumentStatusChanged = "document.status_changed"
+	// DocumentPaymentRecorded и DocumentPaymentDeleted изменили оплату документа
+	DocumentPaymentRecorded = "document.payment_recorded"
+	DocumentPaymentDeleted  = "document.payment_deleted"
+
+	OrgCreated  = "org.created"
+	OrgUpdated  = "org.updated"
+	OrgDeleted  = "org.deleted"
+	OrgRestored = "org.restored"
+	OrgPurged   = "org.purged"
+)
+
+// knownTypes все типы доменных событий
+var knownTypes = map[string]bool{
+	DocumentCreated: true, DocumentUpdated: true, DocumentDeleted: true,
+	DocumentRestored: true, DocumentPurged: true, DocumentStatusChanged: true,
+	DocumentPaymentRecorded: true, DocumentPaymentDeleted: true,
+	OrgCreated: true, OrgUpdated: true, OrgDeleted: true, OrgRestored: true, OrgPurged: true,
+}
+
+// IsKnownType проверяет, что тип события объявлен в пакете
+func IsKnownType(eventType string) bool {
+	return knownTypes[eventType]
+}
+
+// Ref полезная нагрузка событий удаления и восстановления
+type Ref struct {
+	ID uuid.UUID `json:"id"`
+}
+
+// StatusPayload полезная нагрузка события изменения статуса документа в ЭСФ
+type StatusPayload struct {
+	ID     uuid.UUID `json:"id"`
+	Status string    `json:"status"`
+}
+
+// PaymentPayload полезная нагрузка событий оплаты документа; PaidAmount — оплачено после изменения
+type PaymentPayload struct {
+	ID         uuid.UUID `json:"id"`
+	PaymentID  uuid.UUID `json:"paymentId"`
+	Amount     float64   `json:"amount"`
+	PaidAmount float64   `json:"paidAmount"`
+}
+
+// OrganizationPayload полезная нагрузка событий организации (без токена и имени БД)
+type OrganizationPayload struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Tin         string    `json:"tin,omitempty"`
+	Timezone    string    `json:"timezone,omitempty"`
+	Version     int64     `json:"version"`
+}
+
+// NewOrganizationPayload формирует нагрузку события из организации
+func NewOrganizationPayload(org *entity.EstOrganization) OrganizationPayload {
+	return OrganizationPayload{
+		ID:          org.ID,
+		Name:        org.Name,
+		Description: org.Description,
+		Tin:         org.Tin,
+		Timezone:    org.Timezone,
+		Version:     org.Version,
+	}
+}
+
+// NewOutboxEvent создает запись outbox для события агрегата
+func NewOutboxEvent(eventType, aggregateType string, aggregateID, orgID uuid.UUID, payload interface{}) (*entity.OutboxEvent, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("encoding %s payload: %w", eventType, err)
+	}
+
+	return &entity.OutboxEvent{
+		EventID:        uuid.New(),
+		Type:           eventType,
+		AggregateType:  aggregateType,
+		AggregateID:    aggregateID,
+		OrganizationID: orgID,
+		Payload:        raw,
+		OccurredAt:     time.Now().UTC(),
+	}, nil
+}
@@ -0,0 +1,60 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+func TestNewOutboxEvent(t *testing.T) {
+	orgID := uuid.New()
+	org := &entity.EstOrganization{ID: orgID, Name: "Tunduck", Token: "secret-token", DBName: "tunduck_db", Version: 3}
+
+	event, err := NewOutboxEvent(OrgUpdated, AggregateOrganization, orgID, orgID, NewOrganizationPayload(org))
+	require.NoError(t, err)
+
+	assert.NotEqual(t, uuid.Nil, event.EventID)
+	assert.Equal(t, OrgUpdated, event.Type)
+	assert.Equal(t, orgID, event.OrganizationID)
+	assert.WithinDuration(t, time.Now(), event.OccurredAt, time.Second)
+
+	// Токен и имя БД не попадают в событие
+	assert.NotContains(t, string(event.Payload), "secret-token")
+	assert.NotContains(t, string(event.Payload), "tunduck_db")
+	assert.JSONEq(t, `{"id":"`+orgID.String()+`","name":"Tunduck","description":"","version":3}`, string(event.Payload))
+}
+
+func TestRedisStreamBus_Publish(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		t.Skip("Redis not running, skipping tests")
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	stream := "events-test-" + uuid.NewString()[:8]
+	defer client.Del(ctx, stream)
+
+	event, err := NewOutboxEvent(DocumentDeleted, AggregateDocument, uuid.New(), uuid.New(), Ref{})
+	require.NoError(t, err)
+
+	bus := NewRedisStreamBus(client, stream, 10)
+	require.NoError(t, bus.Publish(ctx, *event))
+
+	msgs, err := client.XRange(ctx, stream, "-", "+").Result()
+	require.NoError(t, err)
+	require.Len(t, msgs, 1)
+	assert.Equal(t, DocumentDeleted, msgs[0].Values["type"])
+
+	var published entity.OutboxEvent
+	require.NoError(t, json.Unmarshal([]byte(msgs[0].Values["event"].(string)), &published))
+	assert.Equal(t, event.EventID, published.EventID)
+}
@@ -0,0 +1,130 @@
+// Package exchangerates загружает официальные курсы валют Национального банка Кыргызской Республики.
+//
+// НБКР публикует курсы на день в XML (https://www.nbkr.kg/XML/daily.xml):
+//
+//	<CurrencyRates Name="Daily Exchange Rates" Date="16.10.2026">
+//	  <Currency ISOCode="USD"><Nominal>1</Nominal><Value>87,4500</Value></Currency>
+//	</CurrencyRates>
+//
+// Курс — стоимость единицы валюты в сомах (Value / Nominal).
+package exchangerates
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BaseCurrency национальная валюта, к которой приводятся курсы
+const BaseCurrency = "KGS"
+
+// DefaultURL ежедневные курсы НБКР
+const DefaultURL = "https://www.nbkr.kg/XML/daily.xml"
+
+// DefaultTimeout таймаут запроса курсов
+const DefaultTimeout = 30 * time.Second
+
+// dateLayout формат даты курсов НБКР
+const dateLayout = "02.01.2006"
+
+// Rates курсы валют на дату
+type Rates struct {
+	Date time.Time
+	// Values курс единицы валюты в сомах по коду ISO 4217
+	Values map[string]float64
+}
+
+// Source источник официальных курсов
+type Source interface {
+	// Latest возвращает последние опубликованные курсы
+	Latest(ctx context.Context) (*Rates, error)
+}
+
+// Client загружает курсы НБКР по HTTP
+type Client struct {
+	url  string
+	http *http.Client
+}
+
+// NewClient создает клиент курсов по адресу XML; пустой url — DefaultURL
+func NewClient(url string, timeout time.Duration) *Client {
+	if url == "" {
+		url = DefaultURL
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Client{url: url, http: &http.Client{Timeout: timeout}}
+}
+
+// Latest загружает и разбирает ежедневные курсы
+func (c *Client) Latest(ctx context.Context) (*Rates, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/xml")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("exchangerates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("exchangerates: %s returned %d", c.url, resp.StatusCode)
+	}
+	return Parse(resp.Body)
+}
+
+type xmlRates struct {
+	Date       string `xml:"Date,attr"`
+	Currencies []struct {
+		Code    string `xml:"ISOCode,attr"`
+		Nominal string `xml:"Nominal"`
+		Value   string `xml:"Value"`
+	} `xml:"Currency"`
+}
+
+// Parse разбирает XML курсов НБКР
+func Parse(r io.Reader) (*Rates, error) {
+	dec := xml.NewDecoder(r)
+	// Файл объявлен в windows-1251, но содержит только ASCII
+	dec.CharsetReader = func(charset string, input io.Reader) (io.Reader, error) { return input, nil }
+
+	var doc xmlRates
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("exchangerates: invalid XML: %w", err)
+	}
+
+	date, err := time.Parse(dateLayout, doc.Date)
+	if err != nil {
+		return nil, fmt.Errorf("exchangerates: invalid date %q", doc.Date)
+	}
+
+	rates := &Rates{Date: date, Values: make(map[string]float64, len(doc.Currencies))}
+	for _, cur := range doc.Currencies {
+		value, err := parseNumber(cur.Value)
+		if err != nil {
+			return nil, fmt.Errorf("exchangerates: invalid rate of %s: %w", cur.Code, err)
+		}
+		nominal := 1.0
+		if cur.Nominal != "" {
+			if nominal, err = parseNumber(cur.Nominal); err != nil || nominal <= 0 {
+				return nil, fmt.Errorf("exchangerates: invalid nominal of %s", cur.Code)
+			}
+		}
+		rates.Values[strings.ToUpper(strings.TrimSpace(cur.Code))] = value / nominal
+	}
+	return rates, nil
+}
+
+// parseNumber разбирает число с десятичной запятой
+func parseNumber(s string) (float64, error) {
+	return strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(s), ",", "."), 64)
+}
@@ -0,0 +1,49 @@
+package exchangerates
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const dailyXML = `<?xml version="1.0" encoding="windows-1251"?>
+<CurrencyRates Name="Daily Exchange Rates" Date="16.10.2026">
+  <Currency ISOCode="USD"><Nominal>1</Nominal><Value>87,4500</Value></Currency>
+  <Currency ISOCode="KZT"><Nominal>100</Nominal><Value>17,2300</Value></Currency>
+</CurrencyRates>`
+
+func TestParse(t *testing.T) {
+	rates, err := Parse(strings.NewReader(dailyXML))
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), rates.Date)
+	assert.Equal(t, 87.45, rates.Values["USD"])
+	assert.InDelta(t, 0.1723, rates.Values["KZT"], 1e-9, "the rate is per unit of currency")
+
+	_, err = Parse(strings.NewReader(`<CurrencyRates Date="2026-10-16"></CurrencyRates>`))
+	assert.Error(t, err)
+}
+
+func TestClient_Latest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/daily.xml" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(dailyXML))
+	}))
+	defer server.Close()
+
+	rates, err := NewClient(server.URL+"/daily.xml", time.Second).Latest(context.Background())
+	require.NoError(t, err)
+	assert.Len(t, rates.Values, 2)
+
+	_, err = NewClient(server.URL+"/missing.xml", time.Second).Latest(context.Background())
+	assert.Error(t, err)
+}
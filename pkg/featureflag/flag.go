@@ -0,0 +1,191 @@
+// Package featureflag вычисляет флаги функций для организаций внутри процесса.
+//
+// Флаг включается для организации, если она указана в списке Organizations, либо если
+// она подходит под все правила Rules и попадает в процент выката Percentage (по умолчанию 0,
+// то есть новый флаг включен только для перечисленных организаций). Процент
+// считается по хешу ключа флага и ID организации, поэтому организация остается в
+// выкате при увеличении процента и на всех инстансах получает одно и то же значение.
+// Набор флагов хранится в Redis и рассылается инстансам через pub/sub (см. Flags).
+package featureflag
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Атрибуты организации для правил
+const (
+	AttrOrganization = "organization_id"
+	AttrPlan         = "plan"
+	AttrCreatedAt    = "created_at"
+)
+
+// Operator сравнение атрибута со значениями правила
+type Operator string
+
+const (
+	// OpIn значение атрибута среди Values
+	OpIn Operator = "in"
+	// OpNotIn значение атрибута не среди Values
+	OpNotIn Operator = "not_in"
+	// OpBefore дата атрибута раньше Values[0]
+	OpBefore Operator = "before"
+	// OpAfter дата атрибута не раньше Values[0]
+	OpAfter Operator = "after"
+)
+
+// keyPattern допустимый ключ флага: строчные латинские буквы, цифры, точка, дефис и подчеркивание
+var keyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]{0,99}$`)
+
+// Subject организация, для которой вычисляется флаг
+type Subject struct {
+	OrganizationID uuid.UUID
+	Plan           string
+	CreatedAt      time.Time
+}
+
+// Rule условие на атрибут организации. Даты в Values — YYYY-MM-DD или RFC 3339.
+type Rule struct {
+	Attribute string   `json:"attribute"`
+	Operator  Operator `json:"operator"`
+	Values    []string `json:"values"`
+}
+
+// Flag флаг функции
+type Flag struct {
+	Key         string `json:"key"`
+	Description string `json:"description,omitempty"`
+	// Enabled выключенный флаг выключен для всех, включая Organizations
+	Enabled bool `json:"enabled"`
+	// Organizations организации, для которых флаг включен без правил и процента
+	Organizations []uuid.UUID `json:"organizations,omitempty"`
+	// Rules условия, которым должна удовлетворять организация (все одновременно)
+	Rules []Rule `json:"rules,omitempty"`
+	// Percentage доля подходящих под правила организаций от 0 до 100
+	Percentage int       `json:"percentage"`
+	UpdatedAt  time.Time `json:"updatedAt"`
+}
+
+// Validate проверяет ключ, правила и процент выката
+func (f *Flag) Validate() error {
+	if !keyPattern.MatchString(f.Key) {
+		return fmt.Errorf("invalid flag key %q: use lowercase letters, digits, '.', '-' and '_'", f.Key)
+	}
+	if f.Percentage < 0 || f.Percentage > 100 {
+		return fmt.Errorf("flag %s: percentage must be between 0 and 100", f.Key)
+	}
+	for i, rule := range f.Rules {
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("flag %s: rule %d: %w", f.Key, i+1, err)
+		}
+	}
+	return nil
+}
+
+func (r Rule) validate() error {
+	if len(r.Values) == 0 {
+		return fmt.Errorf("values are required")
+	}
+	switch r.Attribute {
+	case AttrOrganization, AttrPlan:
+		if r.Operator != OpIn && r.Operator != OpNotIn {
+			return fmt.Errorf("attribute %s supports only in and not_in", r.Attribute)
+		}
+	case AttrCreatedAt:
+		if r.Operator != OpBefore && r.Operator != OpAfter {
+			return fmt.Errorf("attribute %s supports only before and after", r.Attribute)
+		}
+		if len(r.Values) != 1 {
+			return fmt.Errorf("attribute %s takes exactly one date", r.Attribute)
+		}
+		if _, err := parseDate(r.Values[0]); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown attribute %q", r.Attribute)
+	}
+	return nil
+}
+
+// Evaluate возвращает значение флага для организации
+func (f *Flag) Evaluate(subject Subject) bool {
+	if !f.Enabled {
+		return false
+	}
+	if f.listed(subject.OrganizationID) {
+		return true
+	}
+	for _, rule := range f.Rules {
+		if !rule.matches(subject) {
+			return false
+		}
+	}
+	return bucket(f.Key, subject.OrganizationID) < f.Percentage
+}
+
+// listed проверяет, указана ли организация в Organizations
+func (f *Flag) listed(orgID uuid.UUID) bool {
+	for _, id := range f.Organizations {
+		if id == orgID {
+			return true
+		}
+	}
+	return false
+}
+
+func (r Rule) matches(subject Subject) bool {
+	switch r.Attribute {
+	case AttrOrganization:
+		return r.contains(subject.OrganizationID.String())
+	case AttrPlan:
+		return r.contains(subject.Plan)
+	case AttrCreatedAt:
+		date, err := parseDate(r.Values[0])
+		if err != nil || subject.CreatedAt.IsZero() {
+			return false
+		}
+		if r.Operator == OpBefore {
+			return subject.CreatedAt.Before(date)
+		}
+		return !subject.CreatedAt.Before(date)
+	}
+	return false
+}
+
+// contains проверяет in/not_in без учета регистра
+func (r Rule) contains(value string) bool {
+	found := false
+	for _, v := range r.Values {
+		if strings.EqualFold(v, value) {
+			found = true
+			break
+		}
+	}
+	if r.Operator == OpNotIn {
+		return !found
+	}
+	return found
+}
+
+// bucket номер организации в выкате флага от 0 до 99
+func bucket(key string, orgID uuid.UUID) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key + ":" + orgID.String()))
+	return int(h.Sum32() % 100)
+}
+
+func parseDate(raw string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, raw); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q: use YYYY-MM-DD or RFC 3339", raw)
+	}
+	return t, nil
+}
@@ -0,0 +1,139 @@
+package featureflag
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubResolver атрибуты организаций в памяти
+type stubResolver map[uuid.UUID]Subject
+
+func (r stubResolver) Subject(ctx context.Context, orgID uuid.UUID) (Subject, error) {
+	subject, ok := r[orgID]
+	if !ok {
+		return Subject{}, errors.New("organization not found")
+	}
+	return subject, nil
+}
+
+func TestFlag_EvaluateRules(t *testing.T) {
+	pinned := uuid.New()
+	flag := Flag{
+		Key:           "gateway.v2",
+		Enabled:       true,
+		Organizations: []uuid.UUID{pinned},
+		Rules: []Rule{
+			{Attribute: AttrPlan, Operator: OpIn, Values: []string{"standard", "enterprise"}},
+			{Attribute: AttrCreatedAt, Operator: OpAfter, Values: []string{"2026-01-01"}},
+		},
+		Percentage: 100,
+	}
+	require.NoError(t, flag.Validate())
+
+	newOrg := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	oldOrg := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	assert.True(t, flag.Evaluate(Subject{OrganizationID: uuid.New(), Plan: "Standard", CreatedAt: newOrg}))
+	assert.False(t, flag.Evaluate(Subject{OrganizationID: uuid.New(), Plan: "free", CreatedAt: newOrg}), "plan does not match")
+	assert.False(t, flag.Evaluate(Subject{OrganizationID: uuid.New(), Plan: "standard", CreatedAt: oldOrg}), "created too early")
+	assert.False(t, flag.Evaluate(Subject{OrganizationID: uuid.New(), Plan: "standard"}), "unknown creation date")
+	assert.True(t, flag.Evaluate(Subject{OrganizationID: pinned}), "listed organizations skip rules")
+
+	flag.Enabled = false
+	assert.False(t, flag.Evaluate(Subject{OrganizationID: pinned}), "a disabled flag is off for everyone")
+}
+
+func TestFlag_PercentageIsStable(t *testing.T) {
+	flag := Flag{Key: "gateway.v2", Enabled: true, Percentage: 20}
+	orgs := make([]uuid.UUID, 1000)
+	in := map[uuid.UUID]bool{}
+	for i := range orgs {
+		orgs[i] = uuid.New()
+		if flag.Evaluate(Subject{OrganizationID: orgs[i]}) {
+			in[orgs[i]] = true
+		}
+	}
+	assert.InDelta(t, 200, len(in), 60)
+
+	// Увеличение процента не исключает уже попавшие в выкат организации
+	flag.Percentage = 50
+	for id := range in {
+		assert.True(t, flag.Evaluate(Subject{OrganizationID: id}))
+	}
+
+	flag.Percentage = 0
+	assert.False(t, flag.Evaluate(Subject{OrganizationID: orgs[0]}))
+}
+
+func TestFlag_Validate(t *testing.T) {
+	for name, flag := range map[string]Flag{
+		"key":        {Key: "Gateway V2"},
+		"percentage": {Key: "a", Percentage: 101},
+		"attribute":  {Key: "a", Rules: []Rule{{Attribute: "region", Operator: OpIn, Values: []string{"kg"}}}},
+		"operator":   {Key: "a", Rules: []Rule{{Attribute: AttrPlan, Operator: OpBefore, Values: []string{"free"}}}},
+		"date":       {Key: "a", Rules: []Rule{{Attribute: AttrCreatedAt, Operator: OpBefore, Values: []string{"yesterday"}}}},
+		"values":     {Key: "a", Rules: []Rule{{Attribute: AttrPlan, Operator: OpIn}}},
+	} {
+		assert.Error(t, flag.Validate(), name)
+	}
+}
+
+func TestFlags_EnabledResolvesSubject(t *testing.T) {
+	ctx := context.Background()
+	paid, free := uuid.New(), uuid.New()
+	flags := NewFlags(nil, Config{}, stubResolver{
+		paid: {OrganizationID: paid, Plan: "enterprise"},
+		free: {OrganizationID: free, Plan: "free"},
+	}, logrus.New())
+
+	require.NoError(t, flags.Set(ctx, Flag{
+		Key: "bulk-send", Enabled: true,
+		Rules:      []Rule{{Attribute: AttrPlan, Operator: OpNotIn, Values: []string{"free"}}},
+		Percentage: 100,
+	}))
+	assert.True(t, flags.Enabled(ctx, "bulk-send", paid))
+	assert.False(t, flags.Enabled(ctx, "bulk-send", free))
+	assert.False(t, flags.Enabled(ctx, "bulk-send", uuid.New()), "rules are not evaluated without organization attributes")
+	assert.False(t, flags.Enabled(ctx, "missing", paid))
+	assert.Equal(t, map[string]bool{"bulk-send": true}, flags.EnabledFor(ctx, paid))
+
+	require.NoError(t, flags.Delete(ctx, "bulk-send"))
+	assert.False(t, flags.Enabled(ctx, "bulk-send", paid))
+	assert.ErrorIs(t, flags.Delete(ctx, "bulk-send"), ErrNotFound)
+
+	var disabled *Flags
+	assert.False(t, disabled.Enabled(ctx, "bulk-send", paid))
+}
+
+func TestFlags_RedisSync(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		t.Skip("Redis not running, skipping tests")
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Два набора с общим префиксом имитируют два инстанса приложения
+	cfg := Config{Prefix: "feature-flags-test-" + uuid.NewString()[:8], RefreshInterval: time.Hour}
+	defer client.Del(context.Background(), cfg.Prefix)
+	first := NewFlags(client, cfg, nil, logrus.New())
+	second := NewFlags(client, cfg, nil, logrus.New())
+	go second.Run(ctx)
+	time.Sleep(100 * time.Millisecond)
+
+	orgID := uuid.New()
+	require.NoError(t, first.Set(ctx, Flag{Key: "gateway.v2", Enabled: true, Organizations: []uuid.UUID{orgID}}))
+	require.Eventually(t, func() bool { return second.Enabled(ctx, "gateway.v2", orgID) }, 2*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, first.Delete(ctx, "gateway.v2"))
+	require.Eventually(t, func() bool { _, ok := second.Get("gateway.v2"); return !ok }, 2*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,242 @@
+package featureflag
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+)
+
+// Параметры по умолчанию
+const (
+	DefaultPrefix          = "feature_flags"
+	DefaultRefreshInterval = 30 * time.Second
+)
+
+// ErrNotFound флаг не найден
+var ErrNotFound = errors.New("feature flag not found")
+
+// Config параметры набора флагов
+type Config struct {
+	// Prefix ключ хеша флагов в Redis; изменения публикуются в канал "<prefix>:updated"
+	Prefix string
+	// RefreshInterval период перечитывания флагов на случай пропущенного уведомления
+	RefreshInterval time.Duration
+}
+
+// SubjectResolver дает атрибуты организации для правил флагов
+type SubjectResolver interface {
+	Subject(ctx context.Context, orgID uuid.UUID) (Subject, error)
+}
+
+// Flags набор флагов инстанса. Флаги вычисляются по копии в памяти, которую Run обновляет
+// из Redis при каждом изменении на любом инстансе. Без Redis набор живет в пределах процесса.
+// Методы nil-Flags считают все флаги выключенными.
+type Flags struct {
+	client   *redis.Client
+	cfg      Config
+	resolver SubjectResolver
+	logger   *logger.Logger
+
+	mu    sync.RWMutex
+	flags map[string]Flag
+}
+
+// NewFlags создает набор флагов; client может быть nil
+func NewFlags(client *redis.Client, cfg Config, resolver SubjectResolver, log *logrus.Logger) *Flags {
+	if cfg.Prefix == "" {
+		cfg.Prefix = DefaultPrefix
+	}
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = DefaultRefreshInterval
+	}
+	return &Flags{
+		client:   client,
+		cfg:      cfg,
+		resolver: resolver,
+		logger:   logger.New(log),
+		flags:    make(map[string]Flag),
+	}
+}
+
+// Run загружает флаги и обновляет их по уведомлениям других инстансов и каждые
+// RefreshInterval до отмены ctx
+func (f *Flags) Run(ctx context.Context) {
+	if f.client == nil {
+		return
+	}
+	if err := f.Load(ctx); err != nil {
+		f.logger.Warn(ctx, "Failed to load feature flags", logrus.Fields{"error": err.Error()})
+	}
+
+	pubsub := f.client.Subscribe(ctx, f.channel())
+	defer pubsub.Close()
+	updates := pubsub.Channel()
+
+	ticker := time.NewTicker(f.cfg.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-updates:
+			if !ok {
+				return
+			}
+		case <-ticker.C:
+		}
+		if err := f.Load(ctx); err != nil {
+			f.logger.Warn(ctx, "Failed to reload feature flags", logrus.Fields{"error": err.Error()})
+		}
+	}
+}
+
+// Load перечитывает флаги из Redis. Некорректные записи пропускаются.
+func (f *Flags) Load(ctx context.Context) error {
+	if f == nil || f.client == nil {
+		return nil
+	}
+	raw, err := f.client.HGetAll(ctx, f.cfg.Prefix).Result()
+	if err != nil {
+		return err
+	}
+
+	flags := make(map[string]Flag, len(raw))
+	for key, data := range raw {
+		var flag Flag
+		if err := json.Unmarshal([]byte(data), &flag); err != nil {
+			f.logger.Warn(ctx, "Invalid feature flag in Redis", logrus.Fields{"key": key, "error": err.Error()})
+			continue
+		}
+		if err := flag.Validate(); err != nil {
+			f.logger.Warn(ctx, "Invalid feature flag in Redis", logrus.Fields{"key": key, "error": err.Error()})
+			continue
+		}
+		flags[key] = flag
+	}
+
+	f.mu.Lock()
+	f.flags = flags
+	f.mu.Unlock()
+	return nil
+}
+
+// List возвращает флаги, упорядоченные по ключу
+func (f *Flags) List() []Flag {
+	if f == nil {
+		return nil
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	flags := make([]Flag, 0, len(f.flags))
+	for _, flag := range f.flags {
+		flags = append(flags, flag)
+	}
+	sort.Slice(flags, func(i, j int) bool { return flags[i].Key < flags[j].Key })
+	return flags
+}
+
+// Get возвращает флаг по ключу
+func (f *Flags) Get(key string) (Flag, bool) {
+	if f == nil {
+		return Flag{}, false
+	}
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	flag, ok := f.flags[key]
+	return flag, ok
+}
+
+// Set создает или заменяет флаг и уведомляет остальные инстансы
+func (f *Flags) Set(ctx context.Context, flag Flag) error {
+	if err := flag.Validate(); err != nil {
+		return err
+	}
+	flag.UpdatedAt = time.Now().UTC()
+
+	if f.client != nil {
+		data, err := json.Marshal(flag)
+		if err != nil {
+			return err
+		}
+		if err := f.client.HSet(ctx, f.cfg.Prefix, flag.Key, data).Err(); err != nil {
+			return err
+		}
+		f.notify(ctx)
+	}
+
+	f.mu.Lock()
+	f.flags[flag.Key] = flag
+	f.mu.Unlock()
+	return nil
+}
+
+// Delete удаляет флаг; после удаления флаг выключен для всех
+func (f *Flags) Delete(ctx context.Context, key string) error {
+	if _, ok := f.Get(key); !ok {
+		return ErrNotFound
+	}
+	if f.client != nil {
+		if err := f.client.HDel(ctx, f.cfg.Prefix, key).Err(); err != nil {
+			return err
+		}
+		f.notify(ctx)
+	}
+
+	f.mu.Lock()
+	delete(f.flags, key)
+	f.mu.Unlock()
+	return nil
+}
+
+// Enabled возвращает значение флага для организации. Неизвестный флаг выключен. Если атрибуты
+// организации для правил получить не удалось, флаг включен только для организаций из списка.
+func (f *Flags) Enabled(ctx context.Context, key string, orgID uuid.UUID) bool {
+	flag, ok := f.Get(key)
+	if !ok || !flag.Enabled {
+		return false
+	}
+
+	subject := Subject{OrganizationID: orgID}
+	if len(flag.Rules) > 0 && f.resolver != nil {
+		resolved, err := f.resolver.Subject(ctx, orgID)
+		if err != nil {
+			f.logger.Warn(ctx, "Failed to resolve feature flag subject", logrus.Fields{
+				"flag": key, "org_id": orgID.String(), "error": err.Error(),
+			})
+			return flag.listed(orgID)
+		}
+		subject = resolved
+	}
+	return flag.Evaluate(subject)
+}
+
+// EnabledFor возвращает значения всех флагов для организации
+func (f *Flags) EnabledFor(ctx context.Context, orgID uuid.UUID) map[string]bool {
+	result := make(map[string]bool)
+	for _, flag := range f.List() {
+		result[flag.Key] = f.Enabled(ctx, flag.Key, orgID)
+	}
+	return result
+}
+
+// notify сообщает остальным инстансам об изменении; при ошибке они перечитают флаги по таймеру
+func (f *Flags) notify(ctx context.Context) {
+	if err := f.client.Publish(ctx, f.channel(), "1").Err(); err != nil {
+		f.logger.Warn(ctx, "Failed to publish feature flag update", logrus.Fields{"error": err.Error()})
+	}
+}
+
+func (f *Flags) channel() string {
+	return f.cfg.Prefix + ":updated"
+}
@@ -0,0 +1,252 @@
+// Package fieldcrypt шифрует отдельные поля БД конвертным шифрованием.
+//
+// Значение шифруется AES-256-GCM ключом данных (DEK), который создается в приложении
+// и хранится рядом с шифротекстом в виде, зашифрованном мастер-ключом KMS:
+//
+//	enc:v1:<ID мастер-ключа>:<base64 зашифрованного DEK>:<base64 nonce+шифротекст>
+//
+// Мастер-ключ не покидает KMS (Vault Transit) или задается в конфигурации (LocalKMS).
+// Поля моделей помечаются тегом gorm:"serializer:encrypted" и шифруются прозрачно для
+// кода приложения; значения, сохраненные до включения шифрования, читаются как есть
+// и шифруются при следующей записи.
+package fieldcrypt
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Prefix признак зашифрованного значения
+const Prefix = "enc:v1:"
+
+// DefaultKeyRotation срок использования одного ключа данных для новых значений
+const DefaultKeyRotation = 24 * time.Hour
+
+// maxCachedKeys ограничивает кеш расшифрованных ключей данных
+const maxCachedKeys = 1024
+
+var (
+	// ErrNotConfigured возвращается при чтении зашифрованного значения без настроенного шифрования
+	ErrNotConfigured = errors.New("field encryption is not configured")
+	// ErrMalformed возвращается для поврежденного зашифрованного значения
+	ErrMalformed = errors.New("malformed encrypted value")
+)
+
+// KMS шифрует ключи данных мастер-ключом
+type KMS interface {
+	// KeyID идентификатор текущего мастер-ключа, записывается в каждое значение
+	KeyID() string
+	WrapKey(ctx context.Context, dek []byte) ([]byte, error)
+	UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error)
+}
+
+// Cipher шифрует и расшифровывает значения полей. Новые значения шифруются текущим
+// ключом данных, который заменяется раз в rotation; расшифрованные ключи кешируются,
+// чтобы не обращаться к KMS на каждую строку.
+type Cipher struct {
+	kms      KMS
+	rotation time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	dek     []byte
+	wrapped string
+	created time.Time
+	keys    map[string][]byte
+}
+
+// NewCipher создает шифр поверх KMS; rotation <= 0 — DefaultKeyRotation
+func NewCipher(kms KMS, rotation time.Duration) *Cipher {
+	if rotation <= 0 {
+		rotation = DefaultKeyRotation
+	}
+	return &Cipher{
+		kms:      kms,
+		rotation: rotation,
+		now:      time.Now,
+		keys:     make(map[string][]byte),
+	}
+}
+
+// Encrypt шифрует значение; пустая строка остается пустой
+func (c *Cipher) Encrypt(ctx context.Context, plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+	dek, wrapped, err := c.currentKey(ctx)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(dek, []byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return Prefix + c.kms.KeyID() + ":" + wrapped + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt расшифровывает значение; незашифрованное значение возвращается без изменений
+func (c *Cipher) Decrypt(ctx context.Context, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	keyID, wrapped, sealed, err := parse(value)
+	if err != nil {
+		return "", err
+	}
+	dek, err := c.dataKey(ctx, keyID, wrapped)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := open(dek, sealed)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// IsEncrypted сообщает, зашифровано ли значение
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, Prefix)
+}
+
+// currentKey возвращает ключ данных для новых значений, создавая новый по истечении срока
+func (c *Cipher) currentKey(ctx context.Context) ([]byte, string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.dek != nil && c.now().Sub(c.created) < c.rotation {
+		return c.dek, c.wrapped, nil
+	}
+
+	dek := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dek); err != nil {
+		return nil, "", err
+	}
+	wrapped, err := c.kms.WrapKey(ctx, dek)
+	if err != nil {
+		return nil, "", fmt.Errorf("wrapping data key: %w", err)
+	}
+	c.dek, c.wrapped, c.created = dek, base64.StdEncoding.EncodeToString(wrapped), c.now()
+	c.cacheKey(c.kms.KeyID(), c.wrapped, dek)
+	return c.dek, c.wrapped, nil
+}
+
+// dataKey расшифровывает ключ данных значения через KMS или берет его из кеша
+func (c *Cipher) dataKey(ctx context.Context, keyID, wrapped string) ([]byte, error) {
+	cacheKey := keyCacheKey(keyID, wrapped)
+	c.mu.Lock()
+	dek, ok := c.keys[cacheKey]
+	c.mu.Unlock()
+	if ok {
+		return dek, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(wrapped)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	dek, err = c.kms.UnwrapKey(ctx, keyID, raw)
+	if err != nil {
+		return nil, fmt.Errorf("unwrapping data key: %w", err)
+	}
+
+	c.mu.Lock()
+	c.cacheKey(keyID, wrapped, dek)
+	c.mu.Unlock()
+	return dek, nil
+}
+
+// cacheKey запоминает ключ данных; вызывается под c.mu
+func (c *Cipher) cacheKey(keyID, wrapped string, dek []byte) {
+	if len(c.keys) >= maxCachedKeys {
+		c.keys = make(map[string][]byte)
+	}
+	c.keys[keyCacheKey(keyID, wrapped)] = dek
+}
+
+func keyCacheKey(keyID, wrapped string) string {
+	sum := sha256.Sum256([]byte(keyID + ":" + wrapped))
+	return string(sum[:])
+}
+
+// parse разбирает значение справа налево: ID мастер-ключа может содержать двоеточия
+func parse(value string) (keyID, wrapped string, sealed []byte, err error) {
+	rest := strings.TrimPrefix(value, Prefix)
+	i := strings.LastIndexByte(rest, ':')
+	if i < 0 {
+		return "", "", nil, ErrMalformed
+	}
+	if sealed, err = base64.StdEncoding.DecodeString(rest[i+1:]); err != nil {
+		return "", "", nil, ErrMalformed
+	}
+	rest = rest[:i]
+	j := strings.LastIndexByte(rest, ':')
+	if j <= 0 {
+		return "", "", nil, ErrMalformed
+	}
+	return rest[:j], rest[j+1:], sealed, nil
+}
+
+// seal шифрует AES-GCM; nonce записывается перед шифротекстом
+func seal(key, plaintext []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(key, sealed []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < gcm.NonceSize() {
+		return nil, ErrMalformed
+	}
+	plaintext, err := gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+var (
+	defaultMu     sync.RWMutex
+	defaultCipher *Cipher
+)
+
+// SetDefault задает шифр для сериализатора GORM; nil выключает шифрование новых значений
+func SetDefault(c *Cipher) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultCipher = c
+}
+
+// Default возвращает шифр сериализатора или nil, если шифрование не настроено
+func Default() *Cipher {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultCipher
+}
@@ -0,0 +1,158 @@
+package fieldcrypt
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/schema"
+)
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, 32)
+}
+
+func TestCipher_EncryptDecrypt(t *testing.T) {
+	ctx := context.Background()
+	kms, err := NewLocalKMS("k1", testKey(1), nil)
+	require.NoError(t, err)
+	c := NewCipher(kms, time.Hour)
+
+	enc, err := c.Encrypt(ctx, "1280016011123456")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(enc, "enc:v1:k1:"), enc)
+	assert.NotContains(t, enc, "1280016011123456")
+
+	again, err := c.Encrypt(ctx, "1280016011123456")
+	require.NoError(t, err)
+	assert.NotEqual(t, enc, again, "every value gets its own nonce")
+
+	plain, err := c.Decrypt(ctx, enc)
+	require.NoError(t, err)
+	assert.Equal(t, "1280016011123456", plain)
+
+	plain, err = c.Decrypt(ctx, "legacy plaintext")
+	require.NoError(t, err)
+	assert.Equal(t, "legacy plaintext", plain, "values stored before encryption are read as is")
+
+	empty, err := c.Encrypt(ctx, "")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	_, err = c.Decrypt(ctx, enc[:len(enc)-4]+"AAAA")
+	assert.ErrorIs(t, err, ErrMalformed)
+}
+
+func TestCipher_MasterKeyRotation(t *testing.T) {
+	ctx := context.Background()
+	oldKMS, err := NewLocalKMS("k1", testKey(1), nil)
+	require.NoError(t, err)
+	enc, err := NewCipher(oldKMS, 0).Encrypt(ctx, "secret-token")
+	require.NoError(t, err)
+
+	newKMS, err := NewLocalKMS("k2", testKey(2), map[string][]byte{"k1": testKey(1)})
+	require.NoError(t, err)
+	c := NewCipher(newKMS, 0)
+	plain, err := c.Decrypt(ctx, enc)
+	require.NoError(t, err)
+	assert.Equal(t, "secret-token", plain, "data under the previous master key stays readable")
+
+	fresh, err := c.Encrypt(ctx, "secret-token")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(fresh, "enc:v1:k2:"))
+
+	withoutOld, err := NewLocalKMS("k2", testKey(2), nil)
+	require.NoError(t, err)
+	_, err = NewCipher(withoutOld, 0).Decrypt(ctx, enc)
+	assert.Error(t, err)
+}
+
+func TestVaultKMS(t *testing.T) {
+	var mu sync.Mutex
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls++
+		mu.Unlock()
+		assert.Equal(t, "root", r.Header.Get("X-Vault-Token"))
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		switch r.URL.Path {
+		case "/v1/transit/encrypt/fields":
+			raw, _ := base64.StdEncoding.DecodeString(body["plaintext"])
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]string{"ciphertext": "vault:v1:" + base64.StdEncoding.EncodeToString(reverse(raw))}})
+		case "/v1/transit/decrypt/fields":
+			raw, _ := base64.StdEncoding.DecodeString(strings.TrimPrefix(body["ciphertext"], "vault:v1:"))
+			_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]string{"plaintext": base64.StdEncoding.EncodeToString(reverse(raw))}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c, err := New(Config{KMS: KMSVault, VaultAddress: srv.URL, VaultToken: "root", VaultKey: "fields"})
+	require.NoError(t, err)
+	ctx := context.Background()
+	enc, err := c.Encrypt(ctx, "KG12345")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(enc, "enc:v1:vault/fields:"), enc)
+
+	// Расшифровка в другом процессе обращается к Vault один раз на ключ данных
+	other, err := New(Config{KMS: KMSVault, VaultAddress: srv.URL, VaultToken: "root", VaultKey: "fields"})
+	require.NoError(t, err)
+	for i := 0; i < 3; i++ {
+		plain, err := other.Decrypt(ctx, enc)
+		require.NoError(t, err)
+		assert.Equal(t, "KG12345", plain)
+	}
+	assert.Equal(t, 2, calls)
+}
+
+func TestSerializer(t *testing.T) {
+	type account struct {
+		Number string `gorm:"serializer:encrypted"`
+	}
+	s, err := schema.Parse(&account{}, &sync.Map{}, schema.NamingStrategy{})
+	require.NoError(t, err)
+	field := s.LookUpField("Number")
+	require.NotNil(t, field)
+
+	ctx := context.Background()
+	kms, err := NewLocalKMS("k1", testKey(1), nil)
+	require.NoError(t, err)
+	SetDefault(NewCipher(kms, 0))
+	defer SetDefault(nil)
+
+	value, err := Serializer{}.Value(ctx, field, reflect.Value{}, "KG-001")
+	require.NoError(t, err)
+	require.True(t, IsEncrypted(value.(string)))
+
+	var dst account
+	require.NoError(t, Serializer{}.Scan(ctx, field, reflect.ValueOf(&dst).Elem(), []byte(value.(string))))
+	assert.Equal(t, "KG-001", dst.Number)
+
+	text, err := Text("token-1234567890").Value()
+	require.NoError(t, err)
+	assert.True(t, IsEncrypted(text.(string)))
+
+	SetDefault(nil)
+	assert.ErrorIs(t, Serializer{}.Scan(ctx, field, reflect.ValueOf(&dst).Elem(), value), ErrNotConfigured)
+}
+
+func reverse(b []byte) []byte {
+	out := make([]byte, len(b))
+	for i := range b {
+		out[len(b)-1-i] = b[i]
+	}
+	return out
+}
@@ -0,0 +1,216 @@
+package fieldcrypt
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Поставщики мастер-ключа
+const (
+	KMSLocal = "local"
+	KMSVault = "vault"
+)
+
+// DefaultVaultTimeout ограничивает запрос к Vault
+const DefaultVaultTimeout = 5 * time.Second
+
+// Config параметры шифрования полей
+type Config struct {
+	// KMS поставщик мастер-ключа: local или vault; пустой — шифрование выключено
+	KMS string
+	// Rotation срок использования одного ключа данных
+	Rotation time.Duration
+
+	// LocalKeyID и LocalKey (base64, 32 байта) текущий мастер-ключ LocalKMS;
+	// LocalOldKeys прежние ключи ("id:base64,..."), которыми еще зашифрованы данные
+	LocalKeyID   string
+	LocalKey     string
+	LocalOldKeys string
+
+	// VaultAddress, VaultToken и VaultKey ключ Vault Transit
+	VaultAddress string
+	VaultToken   string
+	VaultKey     string
+	VaultTimeout time.Duration
+}
+
+// Enabled сообщает, настроено ли шифрование
+func (c Config) Enabled() bool {
+	return c.KMS != ""
+}
+
+// New создает шифр по конфигурации
+func New(cfg Config) (*Cipher, error) {
+	var kms KMS
+	var err error
+	switch cfg.KMS {
+	case KMSLocal:
+		kms, err = newLocalKMSFromConfig(cfg)
+	case KMSVault:
+		kms, err = NewVaultKMS(cfg.VaultAddress, cfg.VaultToken, cfg.VaultKey, cfg.VaultTimeout)
+	default:
+		return nil, fmt.Errorf("unsupported field encryption KMS %q", cfg.KMS)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return NewCipher(kms, cfg.Rotation), nil
+}
+
+// LocalKMS хранит мастер-ключи в памяти процесса; для разработки и установок без внешнего KMS
+type LocalKMS struct {
+	current string
+	keys    map[string][]byte
+}
+
+// NewLocalKMS создает KMS с текущим ключом keyID; прежние ключи нужны только для расшифровки
+func NewLocalKMS(keyID string, key []byte, old map[string][]byte) (*LocalKMS, error) {
+	if keyID == "" {
+		return nil, fmt.Errorf("field encryption key ID is required")
+	}
+	keys := make(map[string][]byte, len(old)+1)
+	for id, k := range old {
+		keys[id] = k
+	}
+	keys[keyID] = key
+	for id, k := range keys {
+		if len(k) != 32 {
+			return nil, fmt.Errorf("field encryption key %q must be 32 bytes", id)
+		}
+	}
+	return &LocalKMS{current: keyID, keys: keys}, nil
+}
+
+func newLocalKMSFromConfig(cfg Config) (*LocalKMS, error) {
+	key, err := base64.StdEncoding.DecodeString(cfg.LocalKey)
+	if err != nil || cfg.LocalKey == "" {
+		return nil, fmt.Errorf("field encryption key must be base64 of 32 bytes")
+	}
+	old := make(map[string][]byte)
+	for _, pair := range strings.Split(cfg.LocalOldKeys, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(pair, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid old field encryption key %q, expected id:base64", pair)
+		}
+		if old[id], err = base64.StdEncoding.DecodeString(encoded); err != nil {
+			return nil, fmt.Errorf("old field encryption key %q is not base64", id)
+		}
+	}
+	keyID := cfg.LocalKeyID
+	if keyID == "" {
+		keyID = KMSLocal
+	}
+	return NewLocalKMS(keyID, key, old)
+}
+
+// KeyID возвращает ID текущего ключа
+func (k *LocalKMS) KeyID() string {
+	return k.current
+}
+
+// WrapKey шифрует ключ данных текущим мастер-ключом
+func (k *LocalKMS) WrapKey(ctx context.Context, dek []byte) ([]byte, error) {
+	return seal(k.keys[k.current], dek)
+}
+
+// UnwrapKey расшифровывает ключ данных мастер-ключом keyID
+func (k *LocalKMS) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	key, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("unknown field encryption key %q", keyID)
+	}
+	return open(key, wrapped)
+}
+
+// VaultKMS шифрует ключи данных ключом Vault Transit; мастер-ключ не покидает Vault,
+// а его ротация выполняется в Vault (версия ключа записана в шифротексте)
+type VaultKMS struct {
+	address string
+	token   string
+	key     string
+	http    *http.Client
+}
+
+// NewVaultKMS создает KMS поверх Vault Transit (POST /v1/transit/encrypt|decrypt/<key>)
+func NewVaultKMS(address, token, key string, timeout time.Duration) (*VaultKMS, error) {
+	if address == "" || token == "" || key == "" {
+		return nil, fmt.Errorf("vault address, token and transit key are required")
+	}
+	if timeout <= 0 {
+		timeout = DefaultVaultTimeout
+	}
+	return &VaultKMS{
+		address: strings.TrimRight(address, "/"),
+		token:   token,
+		key:     key,
+		http:    &http.Client{Timeout: timeout},
+	}, nil
+}
+
+// KeyID возвращает ID ключа в формате vault/<ключ>
+func (v *VaultKMS) KeyID() string {
+	return KMSVault + "/" + v.key
+}
+
+// WrapKey шифрует ключ данных в Vault
+func (v *VaultKMS) WrapKey(ctx context.Context, dek []byte) ([]byte, error) {
+	var resp struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	body := map[string]string{"plaintext": base64.StdEncoding.EncodeToString(dek)}
+	if err := v.call(ctx, "/v1/transit/encrypt/"+v.key, body, &resp); err != nil {
+		return nil, err
+	}
+	return []byte(resp.Data.Ciphertext), nil
+}
+
+// UnwrapKey расшифровывает ключ данных в Vault
+func (v *VaultKMS) UnwrapKey(ctx context.Context, keyID string, wrapped []byte) ([]byte, error) {
+	key := strings.TrimPrefix(keyID, KMSVault+"/")
+	var resp struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	if err := v.call(ctx, "/v1/transit/decrypt/"+key, map[string]string{"ciphertext": string(wrapped)}, &resp); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(resp.Data.Plaintext)
+}
+
+func (v *VaultKMS) call(ctx context.Context, path string, body interface{}, out interface{}) error {
+	raw, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.address+path, bytes.NewReader(raw))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", v.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := v.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("vault request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("vault %s returned %d: %s", path, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
@@ -0,0 +1,79 @@
+package fieldcrypt
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+)
+
+// SerializerName имя сериализатора в теге gorm:"serializer:encrypted"
+const SerializerName = "encrypted"
+
+// Serializer шифрует строковые поля при записи и расшифровывает при чтении шифром Default.
+// Без настроенного шифра значения пишутся как есть.
+type Serializer struct{}
+
+// Scan расшифровывает значение из БД
+func (Serializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var value string
+	switch v := dbValue.(type) {
+	case nil:
+	case string:
+		value = v
+	case []byte:
+		value = string(v)
+	default:
+		return fmt.Errorf("unsupported encrypted value type %T", dbValue)
+	}
+
+	plaintext, err := decrypt(ctx, value)
+	if err != nil {
+		return fmt.Errorf("decrypting %s: %w", field.Name, err)
+	}
+	return field.Set(ctx, dst, plaintext)
+}
+
+// Value шифрует значение перед записью
+func (Serializer) Value(ctx context.Context, field *schema.Field, dst reflect.Value, fieldValue interface{}) (interface{}, error) {
+	value, ok := fieldValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("encrypted field %s must be a string", field.Name)
+	}
+	return encrypt(ctx, value)
+}
+
+// Text значение для обновлений через map, которые GORM не пропускает через сериализатор:
+//
+//	Updates(map[string]interface{}{"token": fieldcrypt.Text(org.Token)})
+type Text string
+
+// Value шифрует значение шифром Default
+func (t Text) Value() (driver.Value, error) {
+	return encrypt(context.Background(), string(t))
+}
+
+func encrypt(ctx context.Context, value string) (string, error) {
+	c := Default()
+	if c == nil {
+		return value, nil
+	}
+	return c.Encrypt(ctx, value)
+}
+
+func decrypt(ctx context.Context, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+	c := Default()
+	if c == nil {
+		return "", ErrNotConfigured
+	}
+	return c.Decrypt(ctx, value)
+}
+
+func init() {
+	schema.RegisterSerializer(SerializerName, Serializer{})
+}
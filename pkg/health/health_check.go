@@ -2,140 +2,273 @@ package health
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/sirupsen/logrus"
 	"gorm.io/gorm"
+
+	"github.com/rusgainew/tunduck-app/pkg/metrics"
 )
 
+// DefaultCheckTimeout время на проверку одного компонента, если Probe.Timeout не задан
+const DefaultCheckTimeout = 2 * time.Second
+
 // Status представляет статус компонента
 type Status string
 
 const (
-	StatusUp   Status = "UP"
-	StatusDown Status = "DOWN"
+	StatusUp Status = "UP"
+	// StatusDegraded недоступны необязательные компоненты (Redis, шлюз ЭСФ), основной API работает
+	StatusDegraded Status = "DEGRADED"
+	StatusDown     Status = "DOWN"
 )
 
+// Statuses все статусы системы (значения метрики health_status)
+var Statuses = []Status{StatusUp, StatusDegraded, StatusDown}
+
 // ComponentHealth содержит статус компонента системы
 type ComponentHealth struct {
 	Name         string    `json:"name"`
 	Status       Status    `json:"status"`
+	Optional     bool      `json:"optional,omitempty"`
 	ResponseTime string    `json:"response_time"`
+	DurationMs   int64     `json:"duration_ms"`
 	Message      string    `json:"message,omitempty"`
 	LastChecked  time.Time `json:"last_checked"`
 }
 
 // HealthCheck содержит информацию о здоровье всей системы
 type HealthCheck struct {
-	Status     Status            `json:"status"`
+	Status Status `json:"status"`
+	// Reasons причины статуса DEGRADED или DOWN: недоступные компоненты и ошибки их проверок
+	Reasons    []string          `json:"reasons,omitempty"`
 	Timestamp  time.Time         `json:"timestamp"`
 	Components []ComponentHealth `json:"components"`
 	Uptime     string            `json:"uptime,omitempty"`
+	// ReadOnly API отвечает на чтение из кеша и отклоняет запись, пока основная БД недоступна
+	ReadOnly bool `json:"read_only,omitempty"`
+}
+
+// Probe проверка одного компонента системы
+type Probe struct {
+	Name string
+	// Timeout ограничивает проверку; 0 — таймаут HealthChecker
+	Timeout time.Duration
+	// Run возвращает nil, если компонент работает
+	Run func(ctx context.Context) error
+	// Message сообщение для работающего компонента
+	Message string
+	// Optional компонент без которого основной API продолжает работать: его отказ дает DEGRADED, а не DOWN
+	Optional bool
 }
 
-// HealthChecker проверяет здоровье системы
+// HealthChecker проверяет здоровье системы. Проверки компонентов выполняются параллельно,
+// каждая со своим таймаутом, поэтому один медленный компонент не задерживает /health дольше таймаута.
 type HealthChecker struct {
 	db          *gorm.DB
 	redisClient *redis.Client
 	logger      *logrus.Logger
 	startTime   time.Time
+	timeout     time.Duration
+	probes      []Probe
+	metrics     *metrics.Metrics
+	// readOnly режим только для чтения; с ним отказ основной БД дает DEGRADED, а не DOWN
+	readOnly *ReadOnlyMode
+	// statusPage публичная сводка; отслеживает инциденты по каждой проверке
+	statusPage *StatusPage
 }
 
-// NewHealthChecker создает новый health checker
+// NewHealthChecker создает health checker с проверками PostgreSQL и Redis
 func NewHealthChecker(db *gorm.DB, redisClient *redis.Client, logger *logrus.Logger) *HealthChecker {
-	return &HealthChecker{
+	hc := &HealthChecker{
 		db:          db,
 		redisClient: redisClient,
 		logger:      logger,
 		startTime:   time.Now(),
+		timeout:     DefaultCheckTimeout,
 	}
+
+	hc.Register(Probe{Name: PrimaryDatabaseProbe, Run: hc.checkDatabase, Message: "Database connected successfully"})
+	// Без Redis кеш, ограничение запросов и фоновые задачи недоступны, но запросы к API обслуживаются
+	hc.Register(Probe{Name: "Redis", Run: hc.checkRedis, Message: "Redis connected successfully", Optional: true})
+	return hc
+}
+
+// SetTimeout задает таймаут проверок, для которых Probe.Timeout не задан
+func (hc *HealthChecker) SetTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		hc.timeout = timeout
+	}
+}
+
+// SetMetrics включает метрики health_status и health_component_up, обновляемые при каждой проверке
+func (hc *HealthChecker) SetMetrics(m *metrics.Metrics) {
+	hc.metrics = m
+}
+
+// Register добавляет проверку компонента; регистрируется до приема запросов
+func (hc *HealthChecker) Register(probe Probe) {
+	hc.probes = append(hc.probes, probe)
 }
 
 // Check проверяет здоровье всей системы
 func (hc *HealthChecker) Check(ctx context.Context) *HealthCheck {
-	components := []ComponentHealth{}
-
-	// Проверяем PostgreSQL
-	components = append(components, hc.checkDatabase(ctx))
+	components := make([]ComponentHealth, len(hc.probes))
 
-	// Проверяем Redis
-	components = append(components, hc.checkRedis(ctx))
+	var wg sync.WaitGroup
+	for i, probe := range hc.probes {
+		wg.Add(1)
+		go func(i int, probe Probe) {
+			defer wg.Done()
+			components[i] = hc.run(ctx, probe)
+		}(i, probe)
+	}
+	wg.Wait()
 
-	// Определяем общий статус
+	// Определяем общий статус: отказ обязательного компонента — DOWN, необязательного — DEGRADED.
+	// В режиме только для чтения инстанс без основной БД продолжает отвечать на чтение.
 	overallStatus := StatusUp
+	var reasons []string
 	for _, comp := range components {
-		if comp.Status == StatusDown {
-			overallStatus = StatusDown
-			break
+		if hc.readOnly != nil && comp.Name == PrimaryDatabaseProbe {
+			hc.readOnly.observe(comp)
 		}
+		if comp.Status != StatusDown {
+			continue
+		}
+		if hc.readOnly != nil && comp.Name == PrimaryDatabaseProbe {
+			reasons = append(reasons, fmt.Sprintf("%s is down, writes are rejected in read-only mode: %s", comp.Name, comp.Message))
+			if overallStatus == StatusUp {
+				overallStatus = StatusDegraded
+			}
+			continue
+		}
+		if comp.Optional {
+			reasons = append(reasons, fmt.Sprintf("%s (optional) is down: %s", comp.Name, comp.Message))
+			if overallStatus == StatusUp {
+				overallStatus = StatusDegraded
+			}
+			continue
+		}
+		reasons = append(reasons, fmt.Sprintf("%s is down: %s", comp.Name, comp.Message))
+		overallStatus = StatusDown
 	}
 
-	return &HealthCheck{
+	result := &HealthCheck{
 		Status:     overallStatus,
+		Reasons:    reasons,
 		Timestamp:  time.Now(),
 		Components: components,
 		Uptime:     time.Since(hc.startTime).String(),
+		ReadOnly:   hc.readOnly.Active(),
+	}
+	hc.record(result)
+	if hc.statusPage != nil {
+		hc.statusPage.observe(result)
 	}
+	return result
 }
 
-// checkDatabase проверяет статус PostgreSQL
-func (hc *HealthChecker) checkDatabase(ctx context.Context) ComponentHealth {
+// CheckComponent выполняет одну зарегистрированную проверку; false — проверки с таким именем нет
+func (hc *HealthChecker) CheckComponent(ctx context.Context, name string) (ComponentHealth, bool) {
+	for _, probe := range hc.probes {
+		if probe.Name == name {
+			return hc.run(ctx, probe), true
+		}
+	}
+	return ComponentHealth{}, false
+}
+
+// record обновляет метрики состояния: 1 у текущего статуса и работающих компонентов
+func (hc *HealthChecker) record(result *HealthCheck) {
+	if hc.metrics == nil || hc.metrics.HealthStatus == nil {
+		return
+	}
+	for _, status := range Statuses {
+		value := 0.0
+		if status == result.Status {
+			value = 1
+		}
+		hc.metrics.HealthStatus.WithLabelValues(string(status)).Set(value)
+	}
+	for _, comp := range result.Components {
+		value := 0.0
+		if comp.Status == StatusUp {
+			value = 1
+		}
+		hc.metrics.HealthComponentUp.WithLabelValues(comp.Name, strconv.FormatBool(comp.Optional)).Set(value)
+	}
+}
+
+// run выполняет проверку с таймаутом. Проверка, не реагирующая на отмену контекста,
+// продолжает выполняться в фоне, но компонент по истечении таймаута считается недоступным.
+func (hc *HealthChecker) run(ctx context.Context, probe Probe) ComponentHealth {
+	timeout := probe.Timeout
+	if timeout <= 0 {
+		timeout = hc.timeout
+	}
+
 	start := time.Now()
 	component := ComponentHealth{
-		Name:        "PostgreSQL",
-		LastChecked: time.Now(),
+		Name:        probe.Name,
+		Optional:    probe.Optional,
+		LastChecked: start,
 	}
 
-	// Добавляем timeout для проверки
-	checkCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	checkCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	// Пытаемся выполнить простой запрос
-	var version string
-	if err := hc.db.WithContext(checkCtx).Raw("SELECT version()").Scan(&version).Error; err != nil {
+	done := make(chan error, 1)
+	go func() { done <- probe.Run(checkCtx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-checkCtx.Done():
+		err = checkCtx.Err()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = fmt.Errorf("check timed out after %s", timeout)
+	}
+
+	if err != nil {
 		component.Status = StatusDown
 		component.Message = err.Error()
-		hc.logger.WithError(err).Warn("Database health check failed")
+		hc.logger.WithError(err).WithField("component", probe.Name).Warn("Health check failed")
 	} else {
 		component.Status = StatusUp
-		component.Message = "Database connected successfully"
+		component.Message = probe.Message
 	}
 
-	component.ResponseTime = time.Since(start).String()
+	elapsed := time.Since(start)
+	component.ResponseTime = elapsed.String()
+	component.DurationMs = elapsed.Milliseconds()
 	return component
 }
 
-// checkRedis проверяет статус Redis
-func (hc *HealthChecker) checkRedis(ctx context.Context) ComponentHealth {
-	start := time.Now()
-	component := ComponentHealth{
-		Name:        "Redis",
-		LastChecked: time.Now(),
+// checkDatabase проверяет статус PostgreSQL
+func (hc *HealthChecker) checkDatabase(ctx context.Context) error {
+	if hc.db == nil {
+		return errors.New("database not configured")
 	}
 
+	// Пытаемся выполнить простой запрос
+	var version string
+	return hc.db.WithContext(ctx).Raw("SELECT version()").Scan(&version).Error
+}
+
+// checkRedis проверяет статус Redis
+func (hc *HealthChecker) checkRedis(ctx context.Context) error {
 	// Если redisClient nil, значит Redis не настроен
 	if hc.redisClient == nil {
-		component.Status = StatusDown
-		component.Message = "Redis client not configured"
-		component.ResponseTime = time.Since(start).String()
-		return component
+		return errors.New("Redis client not configured")
 	}
 
-	// Добавляем timeout для проверки
-	checkCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
-
 	// Пытаемся выполнить PING
-	if err := hc.redisClient.Ping(checkCtx).Err(); err != nil {
-		component.Status = StatusDown
-		component.Message = err.Error()
-		hc.logger.WithError(err).Warn("Redis health check failed")
-	} else {
-		component.Status = StatusUp
-		component.Message = "Redis connected successfully"
-	}
-
-	component.ResponseTime = time.Since(start).String()
-	return component
+	return hc.redisClient.Ping(ctx).Err()
 }
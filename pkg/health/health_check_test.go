@@ -0,0 +1,122 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/pkg/metrics"
+)
+
+func newTestChecker(probes ...Probe) *HealthChecker {
+	hc := &HealthChecker{logger: logrus.New(), startTime: time.Now(), timeout: 50 * time.Millisecond}
+	for _, probe := range probes {
+		hc.Register(probe)
+	}
+	return hc
+}
+
+func TestHealthChecker_RunsProbesConcurrently(t *testing.T) {
+	sleep := func(d time.Duration) func(context.Context) error {
+		return func(ctx context.Context) error {
+			select {
+			case <-time.After(d):
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+	hc := newTestChecker(
+		Probe{Name: "a", Run: sleep(30 * time.Millisecond), Message: "ok"},
+		Probe{Name: "b", Run: sleep(30 * time.Millisecond)},
+		Probe{Name: "c", Run: sleep(30 * time.Millisecond)},
+	)
+
+	start := time.Now()
+	result := hc.Check(context.Background())
+	assert.Less(t, time.Since(start), 80*time.Millisecond)
+
+	assert.Equal(t, StatusUp, result.Status)
+	require.Len(t, result.Components, 3)
+	assert.Equal(t, "a", result.Components[0].Name)
+	assert.Equal(t, "ok", result.Components[0].Message)
+	assert.GreaterOrEqual(t, result.Components[0].DurationMs, int64(30))
+}
+
+func TestHealthChecker_TimesOutSlowProbe(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+
+	hc := newTestChecker(
+		// Проверка игнорирует контекст: результат все равно ограничен таймаутом
+		Probe{Name: "stuck", Run: func(ctx context.Context) error { <-block; return nil }},
+		Probe{Name: "slow", Timeout: 200 * time.Millisecond, Run: func(ctx context.Context) error {
+			time.Sleep(100 * time.Millisecond)
+			return nil
+		}},
+		Probe{Name: "broken", Run: func(ctx context.Context) error { return errors.New("connection refused") }},
+	)
+
+	start := time.Now()
+	result := hc.Check(context.Background())
+	assert.Less(t, time.Since(start), 180*time.Millisecond)
+
+	assert.Equal(t, StatusDown, result.Status)
+	assert.Equal(t, StatusDown, result.Components[0].Status)
+	assert.Equal(t, "check timed out after 50ms", result.Components[0].Message)
+	assert.Equal(t, StatusUp, result.Components[1].Status)
+	assert.Equal(t, "connection refused", result.Components[2].Message)
+}
+
+func TestNewHealthChecker_NotConfigured(t *testing.T) {
+	result := NewHealthChecker(nil, nil, logrus.New()).Check(context.Background())
+
+	assert.Equal(t, StatusDown, result.Status)
+	require.Len(t, result.Components, 2)
+	assert.Equal(t, "PostgreSQL", result.Components[0].Name)
+	assert.Equal(t, "database not configured", result.Components[0].Message)
+	assert.Equal(t, "Redis client not configured", result.Components[1].Message)
+	assert.True(t, result.Components[1].Optional)
+	assert.Equal(t, []string{
+		"PostgreSQL is down: database not configured",
+		"Redis (optional) is down: Redis client not configured",
+	}, result.Reasons)
+}
+
+func TestHealthChecker_DegradedWhenOptionalDown(t *testing.T) {
+	up := func(ctx context.Context) error { return nil }
+	down := func(ctx context.Context) error { return errors.New("connection refused") }
+	gateway := down
+
+	hc := newTestChecker(
+		Probe{Name: "PostgreSQL", Run: up},
+		Probe{Name: "Redis", Run: up, Optional: true},
+		Probe{Name: "ESF gateway", Run: func(ctx context.Context) error { return gateway(ctx) }, Optional: true},
+	)
+	status := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "health_status"}, []string{"status"})
+	components := prometheus.NewGaugeVec(prometheus.GaugeOpts{Name: "health_component_up"}, []string{"component", "optional"})
+	hc.SetMetrics(&metrics.Metrics{HealthStatus: status, HealthComponentUp: components})
+
+	result := hc.Check(context.Background())
+	assert.Equal(t, StatusDegraded, result.Status)
+	assert.Equal(t, []string{"ESF gateway (optional) is down: connection refused"}, result.Reasons)
+	assert.Equal(t, 1.0, testutil.ToFloat64(status.WithLabelValues("DEGRADED")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(status.WithLabelValues("UP")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(components.WithLabelValues("ESF gateway", "true")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(components.WithLabelValues("PostgreSQL", "false")))
+
+	gateway = up
+	result = hc.Check(context.Background())
+	assert.Equal(t, StatusUp, result.Status)
+	assert.Empty(t, result.Reasons)
+	assert.Equal(t, 1.0, testutil.ToFloat64(status.WithLabelValues("UP")))
+	assert.Equal(t, 0.0, testutil.ToFloat64(status.WithLabelValues("DEGRADED")))
+}
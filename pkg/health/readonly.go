@@ -0,0 +1,176 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/pkg/metrics"
+)
+
+// Параметры режима только для чтения по умолчанию
+const (
+	DefaultReadOnlyInterval         = 5 * time.Second
+	DefaultReadOnlyFailureThreshold = 2
+)
+
+// PrimaryDatabaseProbe имя проверки основной БД, по которой включается режим только для чтения
+const PrimaryDatabaseProbe = "PostgreSQL"
+
+// ReadOnlyConfig параметры режима только для чтения
+type ReadOnlyConfig struct {
+	// Interval пауза между проверками основной БД
+	Interval time.Duration
+	// FailureThreshold число неудачных проверок подряд, после которого запись запрещается
+	FailureThreshold int
+	// Registerer регистрирует метрику read_only_mode; nil — prometheus.DefaultRegisterer
+	Registerer prometheus.Registerer
+}
+
+// ReadOnlyStatus состояние режима только для чтения
+type ReadOnlyStatus struct {
+	Active bool      `json:"active"`
+	Since  time.Time `json:"since,omitempty"`
+	Reason string    `json:"reason,omitempty"`
+}
+
+// ReadOnlyMode переключает API в режим только для чтения, пока основная БД недоступна.
+// Состояние обновляется фоновыми проверками и каждой проверкой /health; запись снова
+// разрешается после первой успешной проверки.
+type ReadOnlyMode struct {
+	checker *HealthChecker
+	cfg     ReadOnlyConfig
+	logger  *logrus.Logger
+	gauge   prometheus.Gauge
+
+	active   atomic.Bool
+	mu       sync.Mutex
+	failures int
+	status   ReadOnlyStatus
+	cancel   context.CancelFunc
+	done     chan struct{}
+}
+
+// NewReadOnlyMode создает режим только для чтения и подключает его к checker
+func NewReadOnlyMode(checker *HealthChecker, cfg ReadOnlyConfig, logger *logrus.Logger) *ReadOnlyMode {
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultReadOnlyInterval
+	}
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = DefaultReadOnlyFailureThreshold
+	}
+	if cfg.Registerer == nil {
+		cfg.Registerer = prometheus.DefaultRegisterer
+	}
+
+	m := &ReadOnlyMode{
+		checker: checker,
+		cfg:     cfg,
+		logger:  logger,
+		gauge: metrics.Register(cfg.Registerer, prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "read_only_mode",
+			Help: "1 while writes are rejected because the primary database is unavailable",
+		})),
+	}
+	checker.readOnly = m
+	return m
+}
+
+// Active сообщает, запрещена ли сейчас запись
+func (m *ReadOnlyMode) Active() bool {
+	return m != nil && m.active.Load()
+}
+
+// Status возвращает состояние режима
+func (m *ReadOnlyMode) Status() ReadOnlyStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.status
+}
+
+// Start запускает периодическую проверку основной БД
+func (m *ReadOnlyMode) Start(context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.done != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.done = make(chan struct{})
+	go m.run(ctx)
+	return nil
+}
+
+// Stop прекращает проверки, ожидая текущую не дольше ctx
+func (m *ReadOnlyMode) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	cancel, done := m.cancel, m.done
+	m.mu.Unlock()
+	if done == nil {
+		return nil
+	}
+
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("read-only mode: %w", ctx.Err())
+	}
+}
+
+func (m *ReadOnlyMode) run(ctx context.Context) {
+	defer close(m.done)
+
+	ticker := time.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		if component, ok := m.checker.CheckComponent(ctx, PrimaryDatabaseProbe); ok && ctx.Err() == nil {
+			m.observe(component)
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// observe учитывает результат проверки основной БД
+func (m *ReadOnlyMode) observe(component ComponentHealth) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if component.Status != StatusDown {
+		m.failures = 0
+		if m.status.Active {
+			m.logger.WithField("duration", time.Since(m.status.Since).String()).Warn("Primary database is back, leaving read-only mode")
+			m.status = ReadOnlyStatus{}
+			m.active.Store(false)
+			m.gauge.Set(0)
+		}
+		return
+	}
+
+	m.failures++
+	if m.status.Active {
+		m.status.Reason = component.Message
+		return
+	}
+	if m.failures >= m.cfg.FailureThreshold {
+		m.status = ReadOnlyStatus{Active: true, Since: component.LastChecked, Reason: component.Message}
+		m.active.Store(true)
+		m.gauge.Set(1)
+		m.logger.WithFields(logrus.Fields{
+			"failures": m.failures,
+			"reason":   component.Message,
+		}).Error("Primary database is unavailable, switching to read-only mode")
+	}
+}
@@ -0,0 +1,48 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadOnlyMode_FollowsPrimaryDatabase(t *testing.T) {
+	var dbErr error
+	hc := newTestChecker(
+		Probe{Name: PrimaryDatabaseProbe, Run: func(ctx context.Context) error { return dbErr }},
+		Probe{Name: "Redis", Run: func(ctx context.Context) error { return nil }, Optional: true},
+	)
+	mode := NewReadOnlyMode(hc, ReadOnlyConfig{Registerer: prometheus.NewRegistry()}, logrus.New())
+
+	dbErr = errors.New("connection refused")
+	result := hc.Check(context.Background())
+	// Без основной БД инстанс остается в балансировке, но запись еще разрешена до порога
+	assert.Equal(t, StatusDegraded, result.Status)
+	assert.False(t, result.ReadOnly)
+	assert.False(t, mode.Active())
+
+	component, ok := hc.CheckComponent(context.Background(), PrimaryDatabaseProbe)
+	require.True(t, ok)
+	mode.observe(component)
+	assert.True(t, mode.Active())
+	assert.Equal(t, "connection refused", mode.Status().Reason)
+	assert.Equal(t, float64(1), testutil.ToFloat64(mode.gauge))
+	assert.True(t, hc.Check(context.Background()).ReadOnly)
+
+	// Первая успешная проверка снова разрешает запись
+	dbErr = nil
+	result = hc.Check(context.Background())
+	assert.Equal(t, StatusUp, result.Status)
+	assert.False(t, result.ReadOnly)
+	assert.False(t, mode.Active())
+	assert.Equal(t, float64(0), testutil.ToFloat64(mode.gauge))
+
+	_, ok = hc.CheckComponent(context.Background(), "missing")
+	assert.False(t, ok)
+}
@@ -0,0 +1,218 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Параметры публичной страницы состояния по умолчанию
+const (
+	DefaultStatusCacheTTL       = 15 * time.Second
+	DefaultStatusIncidentWindow = 24 * time.Hour
+	DefaultStatusMaxIncidents   = 20
+)
+
+// StatusConfig параметры публичной сводки состояния
+type StatusConfig struct {
+	// CacheTTL как долго сводка отдается без повторных проверок компонентов
+	CacheTTL time.Duration
+	// IncidentWindow как долго завершенный инцидент остается в сводке
+	IncidentWindow time.Duration
+	// MaxIncidents сколько последних инцидентов хранится
+	MaxIncidents int
+}
+
+// ComponentStatus состояние компонента в публичной сводке, без сообщений об ошибках
+type ComponentStatus struct {
+	Name   string `json:"name"`
+	Status Status `json:"status"`
+}
+
+// Incident отказ компонента. Impact — DEGRADED для необязательных компонентов и основной БД
+// в режиме только для чтения, иначе DOWN.
+type Incident struct {
+	Component  string     `json:"component"`
+	Impact     Status     `json:"impact"`
+	StartedAt  time.Time  `json:"started_at"`
+	ResolvedAt *time.Time `json:"resolved_at,omitempty"`
+	Ongoing    bool       `json:"ongoing"`
+}
+
+// StatusSummary сводка для публичной страницы состояния
+type StatusSummary struct {
+	Status        Status            `json:"status"`
+	StartedAt     time.Time         `json:"started_at"`
+	UptimeSeconds int64             `json:"uptime_seconds"`
+	UpdatedAt     time.Time         `json:"updated_at"`
+	Components    []ComponentStatus `json:"components"`
+	// ReadOnly запись временно отклоняется, пока основная БД недоступна
+	ReadOnly bool `json:"read_only"`
+	// Incidents текущие и недавние отказы компонентов, новые первыми
+	Incidents []Incident `json:"incidents"`
+}
+
+// StatusPage собирает публичную сводку состояния. Сводка кешируется на CacheTTL, поэтому частые
+// запросы страницы состояния не нагружают зависимости проверками. Инциденты отслеживаются по каждой
+// проверке HealthChecker, включая /health, и хранятся в памяти инстанса.
+type StatusPage struct {
+	checker *HealthChecker
+	cfg     StatusConfig
+	now     func() time.Time
+
+	refresh sync.Mutex
+	mu      sync.Mutex
+	cached  *StatusSummary
+	expires time.Time
+	// incidents в порядке начала; open индекс текущего инцидента по имени компонента
+	incidents []Incident
+	open      map[string]int
+}
+
+// NewStatusPage создает сводку состояния и подключает ее к checker
+func NewStatusPage(checker *HealthChecker, cfg StatusConfig) *StatusPage {
+	if cfg.CacheTTL <= 0 {
+		cfg.CacheTTL = DefaultStatusCacheTTL
+	}
+	if cfg.IncidentWindow <= 0 {
+		cfg.IncidentWindow = DefaultStatusIncidentWindow
+	}
+	if cfg.MaxIncidents <= 0 {
+		cfg.MaxIncidents = DefaultStatusMaxIncidents
+	}
+
+	p := &StatusPage{
+		checker: checker,
+		cfg:     cfg,
+		now:     time.Now,
+		open:    make(map[string]int),
+	}
+	checker.statusPage = p
+	return p
+}
+
+// Config возвращает параметры сводки с примененными значениями по умолчанию
+func (p *StatusPage) Config() StatusConfig {
+	return p.cfg
+}
+
+// Summary возвращает сводку, проверяя компоненты не чаще раза в CacheTTL. Одновременные запросы
+// после истечения кеша ждут одну проверку.
+func (p *StatusPage) Summary(ctx context.Context) StatusSummary {
+	if summary, ok := p.fresh(); ok {
+		return summary
+	}
+
+	p.refresh.Lock()
+	defer p.refresh.Unlock()
+	if summary, ok := p.fresh(); ok {
+		return summary
+	}
+
+	result := p.checker.Check(ctx)
+	components := make([]ComponentStatus, 0, len(result.Components))
+	for _, comp := range result.Components {
+		components = append(components, ComponentStatus{Name: comp.Name, Status: comp.Status})
+	}
+
+	now := p.now()
+	summary := &StatusSummary{
+		Status:        result.Status,
+		StartedAt:     p.checker.startTime,
+		UptimeSeconds: int64(now.Sub(p.checker.startTime).Seconds()),
+		UpdatedAt:     now,
+		Components:    components,
+		ReadOnly:      result.ReadOnly,
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	summary.Incidents = p.recentIncidents(now)
+	p.cached = summary
+	p.expires = now.Add(p.cfg.CacheTTL)
+	return *summary
+}
+
+func (p *StatusPage) fresh() (StatusSummary, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cached == nil || !p.now().Before(p.expires) {
+		return StatusSummary{}, false
+	}
+	return *p.cached, true
+}
+
+// observe открывает инцидент при отказе компонента и закрывает его при восстановлении
+func (p *StatusPage) observe(result *HealthCheck) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for _, comp := range result.Components {
+		idx, ongoing := p.open[comp.Name]
+		switch {
+		case comp.Status == StatusDown && !ongoing:
+			impact := StatusDown
+			if comp.Optional || (comp.Name == PrimaryDatabaseProbe && p.checker.readOnly != nil) {
+				impact = StatusDegraded
+			}
+			p.incidents = append(p.incidents, Incident{
+				Component: comp.Name,
+				Impact:    impact,
+				StartedAt: comp.LastChecked,
+				Ongoing:   true,
+			})
+			p.open[comp.Name] = len(p.incidents) - 1
+		case comp.Status != StatusDown && ongoing:
+			resolvedAt := comp.LastChecked
+			p.incidents[idx].ResolvedAt = &resolvedAt
+			p.incidents[idx].Ongoing = false
+			delete(p.open, comp.Name)
+		}
+	}
+	p.prune(p.now())
+}
+
+// prune удаляет завершенные инциденты старше IncidentWindow и самые старые сверх MaxIncidents
+func (p *StatusPage) prune(now time.Time) {
+	kept := p.incidents[:0]
+	for _, incident := range p.incidents {
+		if incident.ResolvedAt != nil && now.Sub(*incident.ResolvedAt) > p.cfg.IncidentWindow {
+			continue
+		}
+		kept = append(kept, incident)
+	}
+	if excess := len(kept) - p.cfg.MaxIncidents; excess > 0 {
+		trimmed := kept[:0]
+		for _, incident := range kept {
+			if excess > 0 && !incident.Ongoing {
+				excess--
+				continue
+			}
+			trimmed = append(trimmed, incident)
+		}
+		kept = trimmed
+	}
+	p.incidents = kept
+
+	p.open = make(map[string]int)
+	for i, incident := range p.incidents {
+		if incident.Ongoing {
+			p.open[incident.Component] = i
+		}
+	}
+}
+
+// recentIncidents копия инцидентов, новые первыми
+func (p *StatusPage) recentIncidents(now time.Time) []Incident {
+	p.prune(now)
+	incidents := make([]Incident, 0, len(p.incidents))
+	for i := len(p.incidents) - 1; i >= 0; i-- {
+		incident := p.incidents[i]
+		if incident.ResolvedAt != nil {
+			resolvedAt := *incident.ResolvedAt
+			incident.ResolvedAt = &resolvedAt
+		}
+		incidents = append(incidents, incident)
+	}
+	return incidents
+}
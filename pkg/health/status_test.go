@@ -0,0 +1,132 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatusPage_CachesSummary(t *testing.T) {
+	var runs atomic.Int32
+	hc := newTestChecker(
+		Probe{Name: PrimaryDatabaseProbe, Run: func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		}},
+		Probe{Name: "Redis", Run: func(ctx context.Context) error { return errors.New("dial tcp 10.0.0.5:6379: refused") }, Optional: true},
+	)
+	page := NewStatusPage(hc, StatusConfig{CacheTTL: time.Minute})
+	now := time.Now()
+	page.now = func() time.Time { return now }
+
+	summary := page.Summary(context.Background())
+	assert.Equal(t, StatusDegraded, summary.Status)
+	assert.Equal(t, []ComponentStatus{
+		{Name: PrimaryDatabaseProbe, Status: StatusUp},
+		{Name: "Redis", Status: StatusDown},
+	}, summary.Components)
+	assert.Equal(t, now, summary.UpdatedAt)
+	assert.GreaterOrEqual(t, summary.UptimeSeconds, int64(0))
+
+	page.Summary(context.Background())
+	assert.Equal(t, int32(1), runs.Load())
+
+	now = now.Add(time.Minute)
+	page.Summary(context.Background())
+	assert.Equal(t, int32(2), runs.Load())
+}
+
+func TestStatusPage_TracksIncidents(t *testing.T) {
+	var dbErr, redisErr error
+	hc := newTestChecker(
+		Probe{Name: PrimaryDatabaseProbe, Run: func(ctx context.Context) error { return dbErr }},
+		Probe{Name: "Redis", Run: func(ctx context.Context) error { return redisErr }, Optional: true},
+	)
+	page := NewStatusPage(hc, StatusConfig{CacheTTL: time.Nanosecond, IncidentWindow: time.Hour})
+	now := time.Now()
+	page.now = func() time.Time { return now }
+
+	// Инциденты открываются и по проверкам /health
+	redisErr = errors.New("refused")
+	hc.Check(context.Background())
+	dbErr = errors.New("refused")
+	hc.Check(context.Background())
+
+	now = now.Add(time.Second)
+	summary := page.Summary(context.Background())
+	assert.Equal(t, StatusDown, summary.Status)
+	require.Len(t, summary.Incidents, 2)
+	assert.Equal(t, PrimaryDatabaseProbe, summary.Incidents[0].Component)
+	assert.Equal(t, StatusDown, summary.Incidents[0].Impact)
+	assert.True(t, summary.Incidents[0].Ongoing)
+	assert.Equal(t, "Redis", summary.Incidents[1].Component)
+	assert.Equal(t, StatusDegraded, summary.Incidents[1].Impact)
+
+	// Восстановление закрывает инцидент, повторный отказ открывает новый
+	dbErr = nil
+	hc.Check(context.Background())
+	now = now.Add(time.Second)
+	summary = page.Summary(context.Background())
+	assert.Equal(t, StatusDegraded, summary.Status)
+	require.Len(t, summary.Incidents, 2)
+	assert.False(t, summary.Incidents[0].Ongoing)
+	require.NotNil(t, summary.Incidents[0].ResolvedAt)
+	assert.True(t, summary.Incidents[1].Ongoing)
+
+	// Завершенные инциденты пропадают из сводки через IncidentWindow, текущие остаются
+	now = now.Add(2 * time.Hour)
+	summary = page.Summary(context.Background())
+	require.Len(t, summary.Incidents, 1)
+	assert.Equal(t, "Redis", summary.Incidents[0].Component)
+}
+
+func TestStatusPage_LimitsIncidents(t *testing.T) {
+	var redisErr error
+	hc := newTestChecker(
+		Probe{Name: "Redis", Run: func(ctx context.Context) error { return redisErr }, Optional: true},
+	)
+	page := NewStatusPage(hc, StatusConfig{MaxIncidents: 2})
+
+	for range 3 {
+		redisErr = errors.New("refused")
+		hc.Check(context.Background())
+		redisErr = nil
+		hc.Check(context.Background())
+	}
+	redisErr = errors.New("refused")
+	hc.Check(context.Background())
+
+	page.mu.Lock()
+	incidents := page.recentIncidents(time.Now())
+	page.mu.Unlock()
+	require.Len(t, incidents, 2)
+	assert.True(t, incidents[0].Ongoing)
+	assert.False(t, incidents[1].Ongoing)
+
+	// Текущий инцидент по-прежнему закрывается после сокращения списка
+	redisErr = nil
+	hc.Check(context.Background())
+	page.mu.Lock()
+	incidents = page.recentIncidents(time.Now())
+	page.mu.Unlock()
+	assert.False(t, incidents[0].Ongoing)
+}
+
+func TestStatusPage_PrimaryDatabaseInReadOnlyModeIsDegraded(t *testing.T) {
+	hc := newTestChecker(
+		Probe{Name: PrimaryDatabaseProbe, Run: func(ctx context.Context) error { return errors.New("refused") }},
+	)
+	NewReadOnlyMode(hc, ReadOnlyConfig{Registerer: prometheus.NewRegistry()}, logrus.New())
+	page := NewStatusPage(hc, StatusConfig{})
+
+	summary := page.Summary(context.Background())
+	require.Len(t, summary.Incidents, 1)
+	assert.Equal(t, StatusDegraded, summary.Incidents[0].Impact)
+}
@@ -0,0 +1,434 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+
+	"github.com/rusgainew/tunduck-app/pkg/metrics"
+)
+
+// Параметры наблюдения за БД организаций по умолчанию
+const (
+	DefaultTenantInterval          = time.Minute
+	DefaultTenantSampleSize        = 10
+	DefaultTenantFailureThreshold  = 2
+	DefaultTenantMaxReplicationLag = 5 * time.Minute
+)
+
+// tenantProbeListed сколько БД перечисляется в сообщении проверки /health
+const tenantProbeListed = 5
+
+// TenantTarget БД организации, за которой наблюдает TenantMonitor
+type TenantTarget struct {
+	OrganizationID string
+	Name           string
+	Database       string
+}
+
+// TenantDBStats состояние БД организации, собранное проверкой
+type TenantDBStats struct {
+	// SizeBytes размер БД на диске
+	SizeBytes int64
+	// InRecovery БД обслуживается репликой
+	InRecovery bool
+	// ReplicationLag отставание реплики от primary; 0 на primary
+	ReplicationLag time.Duration
+}
+
+// TenantStatus последний результат проверки БД организации
+type TenantStatus struct {
+	OrganizationID string `json:"organization_id"`
+	Name           string `json:"name,omitempty"`
+	Database       string `json:"database"`
+	// Status UP, DEGRADED (отставание реплики больше допустимого) или DOWN
+	Status Status `json:"status"`
+	// Flagged БД недоступна FailureThreshold проверок подряд
+	Flagged             bool      `json:"flagged"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	SizeBytes           int64     `json:"size_bytes"`
+	InRecovery          bool      `json:"in_recovery"`
+	ReplicationLag      string    `json:"replication_lag,omitempty"`
+	Message             string    `json:"message,omitempty"`
+	DurationMs          int64     `json:"duration_ms"`
+	LastChecked         time.Time `json:"last_checked"`
+
+	lag time.Duration
+}
+
+// TenantMonitorConfig параметры наблюдения за БД организаций
+type TenantMonitorConfig struct {
+	// Interval пауза между проверками очередной группы БД
+	Interval time.Duration
+	// SampleSize число БД, проверяемых за один раз; группы сменяют друг друга по кругу
+	SampleSize int
+	// FailureThreshold число неудачных проверок подряд, после которого БД отмечается недоступной
+	FailureThreshold int
+	// MaxReplicationLag допустимое отставание реплики; больше — статус DEGRADED
+	MaxReplicationLag time.Duration
+	// Timeout проверки одной БД
+	Timeout    time.Duration
+	Registerer prometheus.Registerer
+}
+
+// TenantMonitor проверяет доступность, размер и отставание реплики БД организаций. Каждые
+// Interval проверяется следующая группа из SampleSize БД, поэтому при тысячах организаций
+// нагрузка остается постоянной, а каждая БД проверяется раз в Interval * (число БД / SampleSize).
+// Результаты хранятся в памяти экземпляра; Probe превращает недоступные БД в статус DEGRADED /health.
+type TenantMonitor struct {
+	cfg    TenantMonitorConfig
+	list   func(ctx context.Context) ([]TenantTarget, error)
+	check  func(ctx context.Context, target TenantTarget) (TenantDBStats, error)
+	logger *logrus.Logger
+	now    func() time.Time
+
+	mu       sync.Mutex
+	statuses map[string]*TenantStatus
+	// cursor идентификатор организации, после которой начинается следующая группа
+	cursor string
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	checks      *prometheus.CounterVec
+	unreachable prometheus.Gauge
+	lagging     prometheus.Gauge
+	maxLag      prometheus.Gauge
+}
+
+// NewTenantMonitor создает наблюдение за БД организаций. list возвращает все БД,
+// check подключается к одной БД и собирает ее состояние (см. PostgresTenantStats).
+func NewTenantMonitor(
+	list func(ctx context.Context) ([]TenantTarget, error),
+	check func(ctx context.Context, target TenantTarget) (TenantDBStats, error),
+	cfg TenantMonitorConfig,
+	logger *logrus.Logger,
+) *TenantMonitor {
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultTenantInterval
+	}
+	if cfg.SampleSize <= 0 {
+		cfg.SampleSize = DefaultTenantSampleSize
+	}
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = DefaultTenantFailureThreshold
+	}
+	if cfg.MaxReplicationLag <= 0 {
+		cfg.MaxReplicationLag = DefaultTenantMaxReplicationLag
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultCheckTimeout
+	}
+	if cfg.Registerer == nil {
+		cfg.Registerer = prometheus.DefaultRegisterer
+	}
+
+	return &TenantMonitor{
+		cfg:      cfg,
+		list:     list,
+		check:    check,
+		logger:   logger,
+		now:      time.Now,
+		statuses: map[string]*TenantStatus{},
+		checks: metrics.Register(cfg.Registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "tenant_db_checks_total",
+			Help: "Total number of organization database health checks by result (up, degraded, down)",
+		}, []string{"result"})),
+		unreachable: metrics.Register(cfg.Registerer, prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "tenant_db_unreachable",
+			Help: "Number of organization databases that failed consecutive health checks",
+		})),
+		lagging: metrics.Register(cfg.Registerer, prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "tenant_db_replication_lagging",
+			Help: "Number of organization databases whose replica lags behind more than allowed",
+		})),
+		maxLag: metrics.Register(cfg.Registerer, prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "tenant_db_max_replication_lag_seconds",
+			Help: "Largest replication lag among checked organization databases",
+		})),
+	}
+}
+
+// Start запускает проверки в фоне; первая группа проверяется сразу
+func (m *TenantMonitor) Start(context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.done != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancel = cancel
+	m.done = make(chan struct{})
+	go m.run(ctx)
+	return nil
+}
+
+// Stop прекращает проверки, ожидая текущую не дольше ctx
+func (m *TenantMonitor) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	cancel, done := m.cancel, m.done
+	m.mu.Unlock()
+	if done == nil {
+		return nil
+	}
+
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("tenant database monitor: %w", ctx.Err())
+	}
+}
+
+func (m *TenantMonitor) run(ctx context.Context) {
+	defer close(m.done)
+
+	ticker := time.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		if err := m.CheckNext(ctx); err != nil && ctx.Err() == nil {
+			m.logger.WithError(err).Warn("Tenant database health check failed")
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// CheckNext проверяет следующую группу БД. Ошибка означает, что не удалось получить список БД.
+func (m *TenantMonitor) CheckNext(ctx context.Context) error {
+	targets, err := m.list(ctx)
+	if err != nil {
+		return err
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i].OrganizationID < targets[j].OrganizationID })
+
+	m.mu.Lock()
+	m.prune(targets)
+	sample := m.sample(targets)
+	m.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, target := range sample {
+		wg.Add(1)
+		go func(target TenantTarget) {
+			defer wg.Done()
+			m.checkOne(ctx, target)
+		}(target)
+	}
+	wg.Wait()
+
+	m.mu.Lock()
+	m.record()
+	m.mu.Unlock()
+	return nil
+}
+
+// sample выбирает SampleSize БД после курсора, продолжая с начала списка; вызывается под m.mu
+func (m *TenantMonitor) sample(targets []TenantTarget) []TenantTarget {
+	if len(targets) <= m.cfg.SampleSize {
+		return targets
+	}
+
+	start := sort.Search(len(targets), func(i int) bool { return targets[i].OrganizationID > m.cursor })
+	sample := make([]TenantTarget, 0, m.cfg.SampleSize)
+	for i := 0; i < m.cfg.SampleSize; i++ {
+		sample = append(sample, targets[(start+i)%len(targets)])
+	}
+	m.cursor = sample[len(sample)-1].OrganizationID
+	return sample
+}
+
+// prune забывает удаленные организации; вызывается под m.mu
+func (m *TenantMonitor) prune(targets []TenantTarget) {
+	known := make(map[string]bool, len(targets))
+	for _, target := range targets {
+		known[target.OrganizationID] = true
+	}
+	for id := range m.statuses {
+		if !known[id] {
+			delete(m.statuses, id)
+		}
+	}
+}
+
+// checkOne проверяет одну БД и отмечает ее недоступной после FailureThreshold неудач подряд
+func (m *TenantMonitor) checkOne(ctx context.Context, target TenantTarget) {
+	start := m.now()
+	checkCtx, cancel := context.WithTimeout(ctx, m.cfg.Timeout)
+	defer cancel()
+
+	// Как и в HealthChecker.run, подключение, не реагирующее на отмену, не задерживает проверку дольше таймаута
+	type result struct {
+		stats TenantDBStats
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		stats, err := m.check(checkCtx, target)
+		done <- result{stats, err}
+	}()
+
+	var stats TenantDBStats
+	var err error
+	select {
+	case r := <-done:
+		stats, err = r.stats, r.err
+	case <-checkCtx.Done():
+		err = checkCtx.Err()
+	}
+	if err != nil && ctx.Err() != nil {
+		// Остановка приложения не считается отказом БД
+		return
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		err = fmt.Errorf("check timed out after %s", m.cfg.Timeout)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	status, ok := m.statuses[target.OrganizationID]
+	if !ok {
+		status = &TenantStatus{OrganizationID: target.OrganizationID}
+		m.statuses[target.OrganizationID] = status
+	}
+	status.Name = target.Name
+	status.Database = target.Database
+	status.LastChecked = start
+	status.DurationMs = m.now().Sub(start).Milliseconds()
+
+	fields := logrus.Fields{"org_id": target.OrganizationID, "database": target.Database}
+	if err != nil {
+		status.Status = StatusDown
+		status.Message = err.Error()
+		status.ConsecutiveFailures++
+		m.checks.WithLabelValues("down").Inc()
+		if !status.Flagged && status.ConsecutiveFailures >= m.cfg.FailureThreshold {
+			status.Flagged = true
+			fields["failures"] = status.ConsecutiveFailures
+			m.logger.WithError(err).WithFields(fields).Warn("Tenant database unreachable")
+		}
+		return
+	}
+
+	if status.Flagged {
+		m.logger.WithFields(fields).Info("Tenant database reachable again")
+	}
+	status.Flagged = false
+	status.ConsecutiveFailures = 0
+	status.SizeBytes = stats.SizeBytes
+	status.InRecovery = stats.InRecovery
+	status.lag = stats.ReplicationLag
+	status.ReplicationLag = ""
+	if stats.InRecovery {
+		status.ReplicationLag = stats.ReplicationLag.String()
+	}
+
+	if stats.ReplicationLag > m.cfg.MaxReplicationLag {
+		status.Status = StatusDegraded
+		status.Message = fmt.Sprintf("replication lag %s exceeds %s", stats.ReplicationLag.Round(time.Second), m.cfg.MaxReplicationLag)
+		m.checks.WithLabelValues("degraded").Inc()
+		return
+	}
+	status.Status = StatusUp
+	status.Message = ""
+	m.checks.WithLabelValues("up").Inc()
+}
+
+// record обновляет сводные метрики; вызывается под m.mu
+func (m *TenantMonitor) record() {
+	var unreachable, lagging int
+	var maxLag time.Duration
+	for _, status := range m.statuses {
+		if status.Flagged {
+			unreachable++
+		}
+		if status.Status == StatusDegraded {
+			lagging++
+		}
+		maxLag = max(maxLag, status.lag)
+	}
+	m.unreachable.Set(float64(unreachable))
+	m.lagging.Set(float64(lagging))
+	m.maxLag.Set(maxLag.Seconds())
+}
+
+// Statuses возвращает последние результаты: сначала недоступные БД, затем по имени БД
+func (m *TenantMonitor) Statuses() []TenantStatus {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	result := make([]TenantStatus, 0, len(m.statuses))
+	for _, status := range m.statuses {
+		result = append(result, *status)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Flagged != result[j].Flagged {
+			return result[i].Flagged
+		}
+		return result[i].Database < result[j].Database
+	})
+	return result
+}
+
+// Probe проверка /health по последним результатам без обращения к БД. Недоступные БД
+// организаций дают статус DEGRADED: остальные организации продолжают работать.
+func (m *TenantMonitor) Probe() Probe {
+	return Probe{
+		Name:     "Tenant databases",
+		Optional: true,
+		Message:  "All checked tenant databases reachable",
+		Run: func(ctx context.Context) error {
+			var flagged []string
+			for _, status := range m.Statuses() {
+				if status.Flagged {
+					flagged = append(flagged, status.Database)
+				}
+			}
+			if len(flagged) == 0 {
+				return nil
+			}
+
+			listed := flagged[:min(len(flagged), tenantProbeListed)]
+			msg := fmt.Sprintf("%d tenant databases unreachable: %s", len(flagged), strings.Join(listed, ", "))
+			if len(flagged) > len(listed) {
+				msg += fmt.Sprintf(" (+%d more)", len(flagged)-len(listed))
+			}
+			return errors.New(msg)
+		},
+	}
+}
+
+// PostgresTenantStats собирает размер БД и состояние репликации одним запросом
+func PostgresTenantStats(ctx context.Context, db *gorm.DB) (TenantDBStats, error) {
+	var row struct {
+		SizeBytes  int64
+		InRecovery bool
+		LagSeconds float64
+	}
+	err := db.WithContext(ctx).Raw(`SELECT pg_database_size(current_database()) AS size_bytes,
+		pg_is_in_recovery() AS in_recovery,
+		COALESCE(EXTRACT(EPOCH FROM now() - pg_last_xact_replay_timestamp()), 0) AS lag_seconds`).
+		Scan(&row).Error
+	if err != nil {
+		return TenantDBStats{}, err
+	}
+
+	stats := TenantDBStats{SizeBytes: row.SizeBytes, InRecovery: row.InRecovery}
+	if row.InRecovery {
+		stats.ReplicationLag = time.Duration(row.LagSeconds * float64(time.Second))
+	}
+	return stats, nil
+}
@@ -0,0 +1,100 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tenantTargets(n int) []TenantTarget {
+	targets := make([]TenantTarget, n)
+	for i := range targets {
+		targets[i] = TenantTarget{OrganizationID: fmt.Sprintf("org-%d", i), Database: fmt.Sprintf("db_%d", i)}
+	}
+	return targets
+}
+
+func TestTenantMonitor_RotatesSample(t *testing.T) {
+	targets := tenantTargets(5)
+	var mu sync.Mutex
+	var checked []string
+	monitor := NewTenantMonitor(
+		func(ctx context.Context) ([]TenantTarget, error) { return append([]TenantTarget(nil), targets...), nil },
+		func(ctx context.Context, target TenantTarget) (TenantDBStats, error) {
+			mu.Lock()
+			checked = append(checked, target.Database)
+			mu.Unlock()
+			return TenantDBStats{}, nil
+		},
+		TenantMonitorConfig{SampleSize: 2, Registerer: prometheus.NewRegistry()},
+		logrus.New(),
+	)
+
+	for i := 0; i < 3; i++ {
+		require.NoError(t, monitor.CheckNext(context.Background()))
+	}
+	// Третья группа доходит до конца списка и продолжается с начала
+	assert.ElementsMatch(t, []string{"db_0", "db_1", "db_2", "db_3", "db_4", "db_0"}, checked)
+	assert.Len(t, monitor.Statuses(), 5)
+
+	// Удаленная организация забывается
+	targets = targets[1:]
+	require.NoError(t, monitor.CheckNext(context.Background()))
+	assert.Len(t, monitor.Statuses(), 4)
+}
+
+func TestTenantMonitor_FlagsUnreachable(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	down := map[string]bool{"db_1": true}
+	monitor := NewTenantMonitor(
+		func(ctx context.Context) ([]TenantTarget, error) { return tenantTargets(3), nil },
+		func(ctx context.Context, target TenantTarget) (TenantDBStats, error) {
+			if down[target.Database] {
+				return TenantDBStats{}, errors.New("connection refused")
+			}
+			if target.Database == "db_2" {
+				return TenantDBStats{SizeBytes: 1 << 20, InRecovery: true, ReplicationLag: 10 * time.Minute}, nil
+			}
+			return TenantDBStats{SizeBytes: 1 << 20}, nil
+		},
+		TenantMonitorConfig{Registerer: reg},
+		logrus.New(),
+	)
+	probe := monitor.Probe()
+
+	// Одна неудача еще не отмечает БД недоступной
+	require.NoError(t, monitor.CheckNext(context.Background()))
+	assert.NoError(t, probe.Run(context.Background()))
+
+	require.NoError(t, monitor.CheckNext(context.Background()))
+	statuses := monitor.Statuses()
+	require.Len(t, statuses, 3)
+	assert.Equal(t, "db_1", statuses[0].Database)
+	assert.True(t, statuses[0].Flagged)
+	assert.Equal(t, StatusDown, statuses[0].Status)
+	assert.Equal(t, 2, statuses[0].ConsecutiveFailures)
+	assert.Equal(t, StatusDegraded, statuses[2].Status)
+	assert.Equal(t, "10m0s", statuses[2].ReplicationLag)
+
+	err := probe.Run(context.Background())
+	require.Error(t, err)
+	assert.Equal(t, "1 tenant databases unreachable: db_1", err.Error())
+	assert.Equal(t, float64(1), testutil.ToFloat64(monitor.unreachable))
+	assert.Equal(t, float64(1), testutil.ToFloat64(monitor.lagging))
+	assert.Equal(t, float64(600), testutil.ToFloat64(monitor.maxLag))
+
+	// Восстановившаяся БД снимается с отметки
+	down["db_1"] = false
+	require.NoError(t, monitor.CheckNext(context.Background()))
+	assert.NoError(t, probe.Run(context.Background()))
+	assert.Equal(t, float64(0), testutil.ToFloat64(monitor.unreachable))
+}
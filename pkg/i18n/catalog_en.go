@@ -0,0 +1,38 @@
+package i18n
+
+// catalogEN содержит сообщения по умолчанию для кодов ошибок.
+// Используется, если у ошибки нет собственного сообщения.
+var catalogEN = map[string]string{
+	"VALIDATION_ERROR":            "validation error",
+	"INVALID_REQUEST":             "invalid request",
+	"FIELD_VALIDATION":            "field validation error",
+	"UNAUTHORIZED":                "unauthorized",
+	"INVALID_TOKEN":               "invalid token",
+	"EXPIRED_TOKEN":               "token has expired",
+	"INVALID_CREDENTIALS":         "invalid credentials",
+	"FORBIDDEN":                   "forbidden",
+	"ACCESS_DENIED":               "access denied",
+	"NOT_FOUND":                   "resource not found",
+	"ALREADY_EXISTS":              "resource already exists",
+	"CONFLICT":                    "conflict",
+	"USER_NOT_FOUND":              "user not found",
+	"USER_ALREADY_EXISTS":         "user already exists",
+	"EMAIL_ALREADY_EXISTS":        "email already exists",
+	"USERNAME_ALREADY_EXISTS":     "username already exists",
+	"ACCOUNT_BLOCKED":             "account is blocked",
+	"PASSWORD_MISMATCH":           "passwords do not match",
+	"DOCUMENT_NOT_FOUND":          "document not found",
+	"INVALID_DOCUMENT":            "invalid document",
+	"ORGANIZATION_NOT_FOUND":      "organization not found",
+	"ORGANIZATION_ALREADY_EXISTS": "organization already exists",
+	"DATABASE_ERROR":              "database error",
+	"DATABASE_TIMEOUT":            "database timeout",
+	"EXTERNAL_SERVICE_ERROR":      "external service error",
+	"RATE_LIMIT_EXCEEDED":         "rate limit exceeded, please try again later",
+	"QUOTA_EXCEEDED":              "quota exceeded, upgrade your plan",
+	"HTTP_ERROR":                  "request error",
+	"INTERNAL_SERVER_ERROR":       "internal server error",
+	"CONFIG_ERROR":                "configuration error",
+	"READ_ONLY_MODE":              "service is in read-only mode, please retry later",
+	"FAULT_INJECTED":              "injected failure for testing",
+}
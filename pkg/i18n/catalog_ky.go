@@ -0,0 +1,208 @@
+package i18n
+
+// catalogKY содержит кыргызские переводы сообщений и кодов ошибок
+var catalogKY = map[string]string{
+	// Коды ошибок
+	"VALIDATION_ERROR":            "Текшерүү катасы",
+	"INVALID_REQUEST":             "Туура эмес суроо-талап",
+	"FIELD_VALIDATION":            "Талааны текшерүү катасы",
+	"UNAUTHORIZED":                "Авторизация талап кылынат",
+	"INVALID_TOKEN":               "Жараксыз токен",
+	"EXPIRED_TOKEN":               "Токендин мөөнөтү бүттү",
+	"INVALID_CREDENTIALS":         "Каттоо маалыматтары туура эмес",
+	"FORBIDDEN":                   "Кирүүгө тыюу салынган",
+	"ACCESS_DENIED":               "Кирүүгө тыюу салынган",
+	"NOT_FOUND":                   "Ресурс табылган жок",
+	"ALREADY_EXISTS":              "Ресурс мурунтан эле бар",
+	"CONFLICT":                    "Маалыматтар карама-каршылыгы",
+	"USER_NOT_FOUND":              "Колдонуучу табылган жок",
+	"USER_ALREADY_EXISTS":         "Колдонуучу мурунтан эле бар",
+	"EMAIL_ALREADY_EXISTS":        "Email мурунтан эле колдонулууда",
+	"USERNAME_ALREADY_EXISTS":     "Колдонуучунун аты бош эмес",
+	"ACCOUNT_BLOCKED":             "Эсеп бөгөттөлгөн",
+	"PASSWORD_MISMATCH":           "Сырсөздөр дал келбейт",
+	"DOCUMENT_NOT_FOUND":          "Документ табылган жок",
+	"INVALID_DOCUMENT":            "Туура эмес документ",
+	"ORGANIZATION_NOT_FOUND":      "Уюм табылган жок",
+	"ORGANIZATION_ALREADY_EXISTS": "Уюм мурунтан эле бар",
+	"DATABASE_ERROR":              "Маалымат базасынын катасы",
+	"DATABASE_TIMEOUT":            "Маалымат базасын күтүү убактысы өттү",
+	"EXTERNAL_SERVICE_ERROR":      "Тышкы кызматтын катасы",
+	"RATE_LIMIT_EXCEEDED":         "Суроо-талаптардын чеги ашты, кийинчерээк кайталаңыз",
+	"QUOTA_EXCEEDED":              "Тарифтик пландын квотасы түгөндү",
+	"HTTP_ERROR":                  "Суроо-талап катасы",
+	"INTERNAL_SERVER_ERROR":       "Сервердин ички катасы",
+	"CONFIG_ERROR":                "Конфигурация катасы",
+	"READ_ONLY_MODE":              "Кызмат убактылуу окуу режиминде гана иштейт, кийинчерээк кайталаңыз",
+	"FAULT_INJECTED":              "Кардарды текшерүү үчүн киргизилген сыноо катасы",
+
+	// Сообщения
+	"invalid request format":         "Суроо-талаптын форматы туура эмес",
+	"invalid request body":           "Суроо-талаптын мазмуну туура эмес",
+	"validation error":               "Текшерүү катасы",
+	"passwords do not match":         "Сырсөздөр дал келбейт",
+	"invalid username or password":   "Колдонуучунун аты же сырсөз туура эмес",
+	"username already exists":        "Колдонуучунун аты бош эмес",
+	"email already exists":           "Email мурунтан эле колдонулууда",
+	"account is blocked":             "Эсеп бөгөттөлгөн",
+	"registration failed":            "Катталуу ишке ашкан жок",
+	"login failed":                   "Системага кирүү ишке ашкан жок",
+	"user not found":                 "Колдонуучу табылган жок",
+	"invalid organization ID":        "Уюмдун идентификатору туура эмес",
+	"invalid document ID format":     "Документтин идентификаторунун форматы туура эмес",
+	"invalid UUID format":            "UUID форматы туура эмес",
+	"invalid cursor":                 "Пагинация курсору туура эмес",
+	"invalid query field":            "Чыпкалоо, иреттөө же тандоо талаасы жараксыз",
+	"invalid filter":                 "Чыпкалоо туюнтмасы туура эмес",
+	"unknown field {field}":          "Белгисиз талаа {field}",
+	"failed to encode response":      "Жоопту түзүү мүмкүн болгон жок",
+	"organization name is required":  "Уюмдун аталышы милдеттүү",
+	"organization not found":         "Уюм табылган жок",
+	"document not found":             "Документ табылган жок",
+	"failed to fetch documents":      "Документтерди алуу мүмкүн болгон жок",
+	"failed to search documents":     "Документтерди издөө мүмкүн болгон жок",
+	"search query is required":       "Издөө суроосун көрсөтүңүз",
+	"failed to fetch document":       "Документти алуу мүмкүн болгон жок",
+	"failed to create document":      "Документти түзүү мүмкүн болгон жок",
+	"failed to update document":      "Документти жаңылоо мүмкүн болгон жок",
+	"failed to delete document":      "Документти өчүрүү мүмкүн болгон жок",
+	"failed to fetch organizations":  "Уюмдарды алуу мүмкүн болгон жок",
+	"failed to fetch organization":   "Уюмду алуу мүмкүн болгон жок",
+	"failed to create organization":  "Уюмду түзүү мүмкүн болгон жок",
+	"failed to update organization":  "Уюмду жаңылоо мүмкүн болгон жок",
+	"failed to delete organization":  "Уюмду өчүрүү мүмкүн болгон жок",
+	"failed to fetch users":          "Колдонуучуларды алуу мүмкүн болгон жок",
+	"failed to fetch user":           "Колдонуучуну алуу мүмкүн болгон жок",
+	"failed to count users":          "Колдонуучуларды эсептөө мүмкүн болгон жок",
+	"invalid role: {role}":           "Жол берилбеген роль: {role}",
+	"failed to assign role":          "Ролду дайындоо мүмкүн болгон жок",
+	"failed to update role":          "Ролду жаңылоо мүмкүн болгон жок",
+	"failed to fetch role":           "Ролду алуу мүмкүн болгон жок",
+	"failed to get migration status": "Миграциялардын абалын алуу мүмкүн болгон жок",
+	"deleted user not found":         "Өчүрүлгөн колдонуучу табылган жок",
+	"deleted organization not found": "Өчүрүлгөн уюм табылган жок",
+	"deleted document not found":     "Өчүрүлгөн документ табылган жок",
+	"failed to restore user":         "Колдонуучуну калыбына келтирүү мүмкүн болгон жок",
+	"failed to purge user":           "Колдонуучуну биротоло өчүрүү мүмкүн болгон жок",
+	"failed to restore organization": "Уюмду калыбына келтирүү мүмкүн болгон жок",
+	"failed to purge organization":   "Уюмду биротоло өчүрүү мүмкүн болгон жок",
+	"failed to restore document":     "Документти калыбына келтирүү мүмкүн болгон жок",
+	"failed to purge document":       "Документти биротоло өчүрүү мүмкүн болгон жок",
+	"organization was modified by another user, reload it and retry":    "Уюм башка колдонуучу тарабынан өзгөртүлгөн, маалыматты жаңылап, кайра аракет кылыңыз",
+	"document was modified by another user, reload it and retry":        "Документ башка колдонуучу тарабынан өзгөртүлгөн, маалыматты жаңылап, кайра аракет кылыңыз",
+	"record version is required: send If-Match header or version field": "Жазуунун версиясы талап кылынат: If-Match аталышын же version талаасын жөнөтүңүз",
+	"invalid If-Match header":                         "If-Match аталышы туура эмес",
+	"user is not authenticated":                       "Колдонуучу авторизациядан өткөн жок",
+	"insufficient access rights":                      "Кирүү укуктары жетишсиз",
+	"insufficient permissions to perform this action": "Бул аракетти аткарууга укуктар жетишсиз",
+	"Authorization header required":                   "Authorization аталышы талап кылынат",
+	"Authorization header must be Bearer token":       "Authorization аталышында Bearer токен болушу керек",
+	"Invalid or expired JWT token":                    "JWT токен жараксыз же мөөнөтү бүткөн",
+	"Invalid or expired JWT":                          "JWT токен жараксыз же мөөнөтү бүткөн",
+	"Token has been revoked":                          "Токен жокко чыгарылды",
+	"Failed to logout":                                "Системадан чыгуу мүмкүн болгон жок",
+	"Rate limit exceeded. Please try again later.":    "Суроо-талаптардын чеги ашты, кийинчерээк кайталаңыз",
+	"Request validation failed":                       "Суроо-талап текшерүүдөн өткөн жок",
+	"Failed to parse request body":                    "Суроо-талаптын мазмунун талдоо мүмкүн болгон жок",
+	"Internal server error":                           "Сервердин ички катасы",
+	"An unexpected error occurred":                    "Күтүлбөгөн ката кетти",
+	"JWT middleware configuration error":              "JWT конфигурациясынын катасы",
+	"signed request headers are required":             "Кол коюлган суроо-талап үчүн X-Timestamp, X-Nonce жана X-Signature аталыштары талап кылынат",
+	"invalid request signature":                       "Суроо-талаптын колу жараксыз",
+	"request timestamp is outside the allowed window": "Суроо-талаптын убактысы жол берилген аралыктан тышкары",
+	"request has already been processed":              "Суроо-талап мурунтан эле иштетилген",
+	"failed to verify request signature":              "Суроо-талаптын колун текшерүү мүмкүн болгон жок",
+	"JWT_SECRET environment variable is not set":      "JWT_SECRET чөйрө өзгөрмөсү коюлган эмес",
+	"failed to get job stats":                         "Фондук тапшырмалардын статистикасын алуу мүмкүн болгон жок",
+	"background jobs are not configured":              "Фондук тапшырмалар жөндөлгөн эмес",
+	"invalid limit":                                   "limit мааниси туура эмес",
+	"unknown email template":                          "Белгисиз кат шаблону",
+	"invalid email recipient":                         "Алуучунун дареги туура эмес",
+	"invalid email status":                            "Каттын статусу туура эмес",
+	"failed to render email":                          "Катты түзүү мүмкүн болгон жок",
+	"failed to enqueue email":                         "Катты кезекке коюу мүмкүн болгон жок",
+	"failed to fetch email deliveries":                "Каттардын журналын алуу мүмкүн болгон жок",
+	"email delivery is not configured":                "Кат жөнөтүү жөндөлгөн эмес",
+	"email delivery not found":                        "Кат табылган жок",
+	"file is infected: {signature}":                   "Файл вирус жуктурган: {signature}",
+	"antivirus scan failed":                           "Файлды антивирус менен текшерүү мүмкүн болгон жок",
+	"invalid export format":                           "Жүктөп алуу форматы жараксыз",
+	"invalid date range":                              "Мезгил жараксыз: даталар RFC 3339 форматында көрсөтүлөт",
+	"failed to fetch audit logs":                      "Аудит журналын алуу мүмкүн болгон жок",
+	"invalid export type":                             "Жүктөп алуунун түрү жараксыз",
+	"invalid export ID":                               "Жүктөп алуунун ID туура эмес",
+	"export not found":                                "Жүктөп алуу табылган жок",
+	"failed to create export":                         "Жүктөп алууну түзүү мүмкүн болгон жок",
+	"failed to fetch export":                          "Жүктөп алууну алуу мүмкүн болгон жок",
+	"failed to download export":                       "Жүктөп алууну жүктөө мүмкүн болгон жок",
+	"invalid download link":                           "Жүктөө шилтемеси жараксыз",
+	"download link has expired":                       "Жүктөө шилтемесинин мөөнөтү бүттү",
+	"signed request is required":                      "Кол коюлган суроо талап кылынат",
+	"invalid event":                                   "Туура эмес окуя",
+	"unsupported event type":                          "Колдоого алынбаган окуянын түрү",
+	"failed to process event":                         "Окуяны иштетүү мүмкүн болгон жок",
+	"invalid document status":                         "Документтин туура эмес статусу",
+	"failed to fetch dead letters":                    "Жеткирилбеген билдирүүлөрдүн кезегин алуу мүмкүн болгон жок",
+	"invalid dead letter status":                      "Жеткирилбеген билдирүүнүн статусу туура эмес",
+	"invalid dead letter ids":                         "Билдирүүлөрдүн 1ден 100гө чейинки идентификаторун көрсөтүңүз",
+	"dead letter not found":                           "Жеткирилбеген билдирүү табылган жок",
+	"dead letter is already requeued":                 "Билдирүү иштетүүгө кайра коюлган",
+	"unsupported dead letter source":                  "Билдирүүнүн булагы колдоого алынбайт",
+	"failed to requeue job":                           "Тапшырманы кезекке кайра коюу мүмкүн болгон жок",
+	"failed to requeue dead letters":                  "Билдирүүлөрдү иштетүүгө кайра коюу мүмкүн болгон жок",
+	"organization id is required":                     "Уюмдун идентификаторун көрсөтүңүз",
+	"unknown event type":                              "Окуянын белгисиз түрү",
+	"domain events are not configured":                "Домендик окуялар жөндөлгөн эмес",
+	"failed to replay events":                         "Окуяларды кайра жөнөтүү мүмкүн болгон жок",
+	"GraphQL query is required":                       "GraphQL-суроо талап кылынат",
+	"GET requests only allow query operations":        "GET-суроо окуу суроолорун гана аткара алат",
+	"record version must be a positive number":        "Жазуунун версиясы оң сан болушу керек",
+	"WebSocket upgrade required":                      "WebSocket аркылуу туташуу талап кылынат",
+	"unknown action":                                  "Белгисиз аракет",
+	"invalid channel":                                 "Канал туура эмес",
+	"too many subscriptions":                          "Жазылуулар өтө көп",
+	"access denied":                                   "Кирүүгө тыюу салынган",
+	"failed to get organization":                      "Уюмду алуу мүмкүн болгон жок",
+	"invalid operation ID":                            "Операциянын ID туура эмес",
+	"operation not found":                             "Операция табылган жок",
+	"failed to fetch operation":                       "Операцияны алуу мүмкүн болгон жок",
+	"invalid operation":                               "Операция туура эмес",
+
+	// Корзина документов
+	"document IDs are required":   "Документтердин ID көрсөтүлгөн жок",
+	"too many document IDs":       "Документтердин ID өтө көп",
+	"failed to queue bulk action": "Массалык иштетүүнү кезекке коюу мүмкүн болгон жок",
+	"failed to delete documents":  "Документтерди өчүрүү мүмкүн болгон жок",
+	"failed to restore documents": "Документтерди калыбына келтирүү мүмкүн болгон жок",
+	"failed to fetch trash":       "Себетти алуу мүмкүн болгон жок",
+
+	// Режим только для чтения
+	"service is in read-only mode, please retry later": "Кызмат убактылуу окуу режиминде гана иштейт, кийинчерээк кайталаңыз",
+
+	// Квоты тарифного плана ({resource} - ресурс, {plan} - план)
+	"{resource} quota exceeded on plan {plan}": "{plan} тарифтик планынын {resource} квотасы түгөндү",
+	"unknown plan: {plan}":                     "Белгисиз тарифтик план: {plan}",
+
+	// Даты и часовые пояса ({date}, {today} - даты, {timezone} - часовой пояс)
+	"delivery date {date} is later than today ({today}) in time zone {timezone}": "Жеткирүү күнү {date} {timezone} саат алкагындагы бүгүнкү күндөн ({today}) кеч",
+	"invalid time zone, expected an IANA name such as Asia/Bishkek":              "Саат алкагы туура эмес, IANA аталышы күтүлөт, мисалы Asia/Bishkek",
+
+	// Ошибки валидации полей ({field} - имя поля, {param} - параметр правила)
+	"{field} is required":                  "{field} талаасы милдеттүү",
+	"{field} must be a valid email":        "{field} талаасында туура email болушу керек",
+	"{field} must be at least {param}":     "{field} талаасы {param} кем болбошу керек",
+	"{field} must be at most {param}":      "{field} талаасы {param} ашпашы керек",
+	"{field} must have length {param}":     "{field} талаасынын узундугу {param} болушу керек",
+	"{field} must match {param}":           "{field} талаасы {param} менен дал келиши керек",
+	"{field} must be one of: {param}":      "{field} талаасы төмөнкүлөрдүн бири болушу керек: {param}",
+	"{field} must be a valid UUID":         "{field} талаасында туура UUID болушу керек",
+	"{field} must be greater than {param}": "{field} талаасы {param} чоң болушу керек",
+	"{field} must be less than {param}":    "{field} талаасы {param} кичине болушу керек",
+	"{field} is invalid":                   "{field} талаасы туура эмес толтурулган",
+
+	// Ошибки проверки ИНН (pkg/tin)
+	"{field} does not match the TIN format: 14 digits (Kyrgyzstan) or 10 or 12 digits (Russia)":              "{field} талаасы ИНН форматына туура келбейт: 14 сан (Кыргызстан) же 10 же 12 сан (Россия)",
+	"{field} does not match the Kyrgyz TIN format: it must start with 0 (organization), 1 or 2 (individual)": "{field} талаасы Кыргызстандын ИНН форматына туура келбейт: уюм үчүн 0дөн, жеке адам үчүн 1 же 2ден башталышы керек",
+	"{field} does not match the Kyrgyz TIN format: it must contain a valid birth date":                       "{field} талаасы Кыргызстандын ИНН форматына туура келбейт: туура туулган күн болушу керек",
+	"{field} has invalid check digits": "{field} талаасынын текшерүү сандары туура эмес",
+}
@@ -0,0 +1,208 @@
+package i18n
+
+// catalogRU содержит русские переводы сообщений и кодов ошибок
+var catalogRU = map[string]string{
+	// Коды ошибок
+	"VALIDATION_ERROR":            "Ошибка валидации",
+	"INVALID_REQUEST":             "Некорректный запрос",
+	"FIELD_VALIDATION":            "Ошибка валидации поля",
+	"UNAUTHORIZED":                "Требуется авторизация",
+	"INVALID_TOKEN":               "Недействительный токен",
+	"EXPIRED_TOKEN":               "Срок действия токена истек",
+	"INVALID_CREDENTIALS":         "Неверные учетные данные",
+	"FORBIDDEN":                   "Доступ запрещен",
+	"ACCESS_DENIED":               "Доступ запрещен",
+	"NOT_FOUND":                   "Ресурс не найден",
+	"ALREADY_EXISTS":              "Ресурс уже существует",
+	"CONFLICT":                    "Конфликт данных",
+	"USER_NOT_FOUND":              "Пользователь не найден",
+	"USER_ALREADY_EXISTS":         "Пользователь уже существует",
+	"EMAIL_ALREADY_EXISTS":        "Email уже используется",
+	"USERNAME_ALREADY_EXISTS":     "Имя пользователя уже занято",
+	"ACCOUNT_BLOCKED":             "Учетная запись заблокирована",
+	"PASSWORD_MISMATCH":           "Пароли не совпадают",
+	"DOCUMENT_NOT_FOUND":          "Документ не найден",
+	"INVALID_DOCUMENT":            "Некорректный документ",
+	"ORGANIZATION_NOT_FOUND":      "Организация не найдена",
+	"ORGANIZATION_ALREADY_EXISTS": "Организация уже существует",
+	"DATABASE_ERROR":              "Ошибка базы данных",
+	"DATABASE_TIMEOUT":            "Превышено время ожидания базы данных",
+	"EXTERNAL_SERVICE_ERROR":      "Ошибка внешнего сервиса",
+	"RATE_LIMIT_EXCEEDED":         "Превышен лимит запросов, повторите попытку позже",
+	"QUOTA_EXCEEDED":              "Исчерпана квота тарифного плана",
+	"HTTP_ERROR":                  "Ошибка запроса",
+	"INTERNAL_SERVER_ERROR":       "Внутренняя ошибка сервера",
+	"CONFIG_ERROR":                "Ошибка конфигурации",
+	"READ_ONLY_MODE":              "Сервис временно работает только на чтение, повторите попытку позже",
+	"FAULT_INJECTED":              "Тестовая ошибка, внедренная для проверки клиента",
+
+	// Сообщения
+	"invalid request format":         "Некорректный формат запроса",
+	"invalid request body":           "Некорректное тело запроса",
+	"validation error":               "Ошибка валидации",
+	"passwords do not match":         "Пароли не совпадают",
+	"invalid username or password":   "Неверное имя пользователя или пароль",
+	"username already exists":        "Имя пользователя уже занято",
+	"email already exists":           "Email уже используется",
+	"account is blocked":             "Учетная запись заблокирована",
+	"registration failed":            "Не удалось зарегистрироваться",
+	"login failed":                   "Не удалось войти в систему",
+	"user not found":                 "Пользователь не найден",
+	"invalid organization ID":        "Некорректный идентификатор организации",
+	"invalid document ID format":     "Некорректный формат идентификатора документа",
+	"invalid UUID format":            "Некорректный формат UUID",
+	"invalid cursor":                 "Некорректный курсор пагинации",
+	"invalid query field":            "Недопустимое поле фильтрации, сортировки или выборки",
+	"invalid filter":                 "Некорректное выражение фильтра",
+	"unknown field {field}":          "Неизвестное поле {field}",
+	"failed to encode response":      "Не удалось сформировать ответ",
+	"organization name is required":  "Название организации обязательно",
+	"organization not found":         "Организация не найдена",
+	"document not found":             "Документ не найден",
+	"failed to fetch documents":      "Не удалось получить документы",
+	"failed to search documents":     "Не удалось найти документы",
+	"search query is required":       "Укажите поисковый запрос",
+	"failed to fetch document":       "Не удалось получить документ",
+	"failed to create document":      "Не удалось создать документ",
+	"failed to update document":      "Не удалось обновить документ",
+	"failed to delete document":      "Не удалось удалить документ",
+	"failed to fetch organizations":  "Не удалось получить организации",
+	"failed to fetch organization":   "Не удалось получить организацию",
+	"failed to create organization":  "Не удалось создать организацию",
+	"failed to update organization":  "Не удалось обновить организацию",
+	"failed to delete organization":  "Не удалось удалить организацию",
+	"failed to fetch users":          "Не удалось получить пользователей",
+	"failed to fetch user":           "Не удалось получить пользователя",
+	"failed to count users":          "Не удалось подсчитать пользователей",
+	"invalid role: {role}":           "Недопустимая роль: {role}",
+	"failed to assign role":          "Не удалось назначить роль",
+	"failed to update role":          "Не удалось обновить роль",
+	"failed to fetch role":           "Не удалось получить роль",
+	"failed to get migration status": "Не удалось получить статус миграций",
+	"deleted user not found":         "Удаленный пользователь не найден",
+	"deleted organization not found": "Удаленная организация не найдена",
+	"deleted document not found":     "Удаленный документ не найден",
+	"failed to restore user":         "Не удалось восстановить пользователя",
+	"failed to purge user":           "Не удалось окончательно удалить пользователя",
+	"failed to restore organization": "Не удалось восстановить организацию",
+	"failed to purge organization":   "Не удалось окончательно удалить организацию",
+	"failed to restore document":     "Не удалось восстановить документ",
+	"failed to purge document":       "Не удалось окончательно удалить документ",
+	"organization was modified by another user, reload it and retry":    "Организация была изменена другим пользователем, обновите данные и повторите попытку",
+	"document was modified by another user, reload it and retry":        "Документ был изменен другим пользователем, обновите данные и повторите попытку",
+	"record version is required: send If-Match header or version field": "Требуется версия записи: передайте заголовок If-Match или поле version",
+	"invalid If-Match header":                         "Некорректный заголовок If-Match",
+	"user is not authenticated":                       "Пользователь не авторизован",
+	"insufficient access rights":                      "Недостаточно прав доступа",
+	"insufficient permissions to perform this action": "Недостаточно прав для выполнения этого действия",
+	"Authorization header required":                   "Требуется заголовок Authorization",
+	"Authorization header must be Bearer token":       "Заголовок Authorization должен содержать Bearer токен",
+	"Invalid or expired JWT token":                    "Недействительный или просроченный JWT токен",
+	"Invalid or expired JWT":                          "Недействительный или просроченный JWT токен",
+	"Token has been revoked":                          "Токен отозван",
+	"Failed to logout":                                "Не удалось выйти из системы",
+	"Rate limit exceeded. Please try again later.":    "Превышен лимит запросов, повторите попытку позже",
+	"Request validation failed":                       "Запрос не прошел валидацию",
+	"Failed to parse request body":                    "Не удалось разобрать тело запроса",
+	"Internal server error":                           "Внутренняя ошибка сервера",
+	"An unexpected error occurred":                    "Произошла непредвиденная ошибка",
+	"JWT middleware configuration error":              "Ошибка конфигурации JWT",
+	"signed request headers are required":             "Для подписанного запроса требуются заголовки X-Timestamp, X-Nonce и X-Signature",
+	"invalid request signature":                       "Недействительная подпись запроса",
+	"request timestamp is outside the allowed window": "Время запроса выходит за допустимый интервал",
+	"request has already been processed":              "Запрос уже был обработан",
+	"failed to verify request signature":              "Не удалось проверить подпись запроса",
+	"JWT_SECRET environment variable is not set":      "Не задана переменная окружения JWT_SECRET",
+	"failed to get job stats":                         "Не удалось получить статистику фоновых задач",
+	"background jobs are not configured":              "Фоновые задачи не настроены",
+	"invalid limit":                                   "Некорректное значение limit",
+	"unknown email template":                          "Неизвестный шаблон письма",
+	"invalid email recipient":                         "Некорректный адрес получателя",
+	"invalid email status":                            "Некорректный статус письма",
+	"failed to render email":                          "Не удалось сформировать письмо",
+	"failed to enqueue email":                         "Не удалось поставить письмо в очередь",
+	"failed to fetch email deliveries":                "Не удалось получить журнал писем",
+	"email delivery is not configured":                "Отправка писем не настроена",
+	"email delivery not found":                        "Письмо не найдено",
+	"file is infected: {signature}":                   "Файл заражен: {signature}",
+	"antivirus scan failed":                           "Не удалось проверить файл антивирусом",
+	"invalid export format":                           "Недопустимый формат выгрузки",
+	"invalid date range":                              "Недопустимый период: даты указываются в формате RFC 3339",
+	"failed to fetch audit logs":                      "Не удалось получить журнал аудита",
+	"invalid export type":                             "Недопустимый тип выгрузки",
+	"invalid export ID":                               "Некорректный ID выгрузки",
+	"export not found":                                "Выгрузка не найдена",
+	"failed to create export":                         "Не удалось создать выгрузку",
+	"failed to fetch export":                          "Не удалось получить выгрузку",
+	"failed to download export":                       "Не удалось скачать выгрузку",
+	"invalid download link":                           "Недействительная ссылка на скачивание",
+	"download link has expired":                       "Срок действия ссылки на скачивание истек",
+	"signed request is required":                      "Требуется подписанный запрос",
+	"invalid event":                                   "Некорректное событие",
+	"unsupported event type":                          "Неподдерживаемый тип события",
+	"failed to process event":                         "Не удалось обработать событие",
+	"invalid document status":                         "Некорректный статус документа",
+	"failed to fetch dead letters":                    "Не удалось получить очередь недоставленных сообщений",
+	"invalid dead letter status":                      "Некорректный статус недоставленного сообщения",
+	"invalid dead letter ids":                         "Укажите от 1 до 100 идентификаторов сообщений",
+	"dead letter not found":                           "Недоставленное сообщение не найдено",
+	"dead letter is already requeued":                 "Сообщение уже поставлено в обработку повторно",
+	"unsupported dead letter source":                  "Неподдерживаемый источник сообщения",
+	"failed to requeue job":                           "Не удалось поставить задачу в очередь повторно",
+	"failed to requeue dead letters":                  "Не удалось поставить сообщения в обработку повторно",
+	"organization id is required":                     "Укажите идентификатор организации",
+	"unknown event type":                              "Неизвестный тип события",
+	"domain events are not configured":                "Доменные события не настроены",
+	"failed to replay events":                         "Не удалось повторно отправить события",
+	"GraphQL query is required":                       "Требуется GraphQL-запрос",
+	"GET requests only allow query operations":        "GET-запрос может выполнять только запросы на чтение",
+	"record version must be a positive number":        "Версия записи должна быть положительным числом",
+	"WebSocket upgrade required":                      "Требуется подключение по WebSocket",
+	"unknown action":                                  "Неизвестное действие",
+	"invalid channel":                                 "Некорректный канал",
+	"too many subscriptions":                          "Слишком много подписок",
+	"access denied":                                   "Доступ запрещен",
+	"failed to get organization":                      "Не удалось получить организацию",
+	"invalid operation ID":                            "Некорректный ID операции",
+	"operation not found":                             "Операция не найдена",
+	"failed to fetch operation":                       "Не удалось получить операцию",
+	"invalid operation":                               "Некорректная операция",
+
+	// Корзина документов
+	"document IDs are required":   "Не указаны ID документов",
+	"too many document IDs":       "Слишком много ID документов",
+	"failed to queue bulk action": "Не удалось поставить массовую обработку в очередь",
+	"failed to delete documents":  "Не удалось удалить документы",
+	"failed to restore documents": "Не удалось восстановить документы",
+	"failed to fetch trash":       "Не удалось получить корзину",
+
+	// Режим только для чтения
+	"service is in read-only mode, please retry later": "Сервис временно работает только на чтение, повторите попытку позже",
+
+	// Квоты тарифного плана ({resource} - ресурс, {plan} - план)
+	"{resource} quota exceeded on plan {plan}": "Исчерпана квота {resource} тарифного плана {plan}",
+	"unknown plan: {plan}":                     "Неизвестный тарифный план: {plan}",
+
+	// Даты и часовые пояса ({date}, {today} - даты, {timezone} - часовой пояс)
+	"delivery date {date} is later than today ({today}) in time zone {timezone}": "Дата поставки {date} позже текущей даты ({today}) в часовом поясе {timezone}",
+	"invalid time zone, expected an IANA name such as Asia/Bishkek":              "Некорректный часовой пояс, ожидается имя IANA, например Asia/Bishkek",
+
+	// Ошибки валидации полей ({field} - имя поля, {param} - параметр правила)
+	"{field} is required":                  "Поле {field} обязательно",
+	"{field} must be a valid email":        "Поле {field} должно содержать корректный email",
+	"{field} must be at least {param}":     "Поле {field} должно быть не меньше {param}",
+	"{field} must be at most {param}":      "Поле {field} должно быть не больше {param}",
+	"{field} must have length {param}":     "Длина поля {field} должна быть {param}",
+	"{field} must match {param}":           "Поле {field} должно совпадать с {param}",
+	"{field} must be one of: {param}":      "Поле {field} должно быть одним из: {param}",
+	"{field} must be a valid UUID":         "Поле {field} должно содержать корректный UUID",
+	"{field} must be greater than {param}": "Поле {field} должно быть больше {param}",
+	"{field} must be less than {param}":    "Поле {field} должно быть меньше {param}",
+	"{field} is invalid":                   "Поле {field} заполнено некорректно",
+
+	// Ошибки проверки ИНН (pkg/tin)
+	"{field} does not match the TIN format: 14 digits (Kyrgyzstan) or 10 or 12 digits (Russia)":              "Поле {field} не соответствует формату ИНН: 14 цифр (Кыргызстан) или 10 либо 12 цифр (Россия)",
+	"{field} does not match the Kyrgyz TIN format: it must start with 0 (organization), 1 or 2 (individual)": "Поле {field} не соответствует формату ИНН Кыргызстана: первая цифра 0 (организация), 1 или 2 (физическое лицо)",
+	"{field} does not match the Kyrgyz TIN format: it must contain a valid birth date":                       "Поле {field} не соответствует формату ИНН Кыргызстана: нет корректной даты рождения",
+	"{field} has invalid check digits": "Поле {field} содержит неверные контрольные цифры",
+}
@@ -0,0 +1,132 @@
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Language код языка (ISO 639-1)
+type Language string
+
+const (
+	Russian Language = "ru"
+	Kyrgyz  Language = "ky"
+	English Language = "en"
+)
+
+// LocalsKey ключ, под которым язык запроса хранится в fiber.Ctx.Locals
+const LocalsKey = "lang"
+
+// DefaultLanguage язык, используемый если клиент не указал поддерживаемый язык
+var DefaultLanguage = Russian
+
+// SourceLanguage язык исходных сообщений в коде (msgid)
+const SourceLanguage = English
+
+// catalogs содержит переводы сообщений по языкам.
+// Ключом является либо исходное (английское) сообщение, либо код ошибки.
+var catalogs = map[Language]map[string]string{
+	Russian: catalogRU,
+	Kyrgyz:  catalogKY,
+}
+
+// IsSupported проверяет, поддерживается ли язык
+func IsSupported(lang Language) bool {
+	if lang == SourceLanguage {
+		return true
+	}
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// ParseAcceptLanguage выбирает наиболее предпочтительный поддерживаемый язык
+// из заголовка Accept-Language (например "ky-KG,ky;q=0.9,ru;q=0.8")
+func ParseAcceptLanguage(header string) Language {
+	type candidate struct {
+		lang Language
+		q    float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		tag, q := part, 1.0
+		if idx := strings.Index(part, ";"); idx >= 0 {
+			tag = strings.TrimSpace(part[:idx])
+			param := strings.TrimSpace(part[idx+1:])
+			if strings.HasPrefix(param, "q=") {
+				if v, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+					q = v
+				}
+			}
+		}
+
+		primary := strings.ToLower(strings.SplitN(tag, "-", 2)[0])
+		lang := Language(primary)
+		if q <= 0 || !IsSupported(lang) {
+			continue
+		}
+		candidates = append(candidates, candidate{lang: lang, q: q})
+	}
+
+	if len(candidates) == 0 {
+		return DefaultLanguage
+	}
+
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return candidates[a].q > candidates[b].q
+	})
+	return candidates[0].lang
+}
+
+// FromCtx возвращает язык текущего запроса.
+// Использует значение, установленное I18nMiddleware, иначе разбирает Accept-Language.
+func FromCtx(c *fiber.Ctx) Language {
+	if lang, ok := c.Locals(LocalsKey).(Language); ok && lang != "" {
+		return lang
+	}
+	return ParseAcceptLanguage(c.Get(fiber.HeaderAcceptLanguage))
+}
+
+// Localize переводит сообщение на указанный язык и подставляет параметры.
+// Порядок поиска: перевод сообщения -> перевод кода ошибки -> исходное сообщение.
+func Localize(lang Language, code, message string, params map[string]interface{}) string {
+	if lang != SourceLanguage {
+		catalog := catalogs[lang]
+		if tr, ok := catalog[message]; ok {
+			return Interpolate(tr, params)
+		}
+		if tr, ok := catalog[code]; ok {
+			return Interpolate(tr, params)
+		}
+	}
+
+	if message == "" {
+		if tr, ok := catalogEN[code]; ok {
+			return Interpolate(tr, params)
+		}
+	}
+
+	return Interpolate(message, params)
+}
+
+// Interpolate подставляет параметры вида {name} в шаблон сообщения
+func Interpolate(tmpl string, params map[string]interface{}) string {
+	if len(params) == 0 || !strings.Contains(tmpl, "{") {
+		return tmpl
+	}
+
+	pairs := make([]string, 0, len(params)*2)
+	for k, v := range params {
+		pairs = append(pairs, "{"+k+"}", fmt.Sprint(v))
+	}
+	return strings.NewReplacer(pairs...).Replace(tmpl)
+}
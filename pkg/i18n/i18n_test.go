@@ -0,0 +1,41 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header string
+		want   Language
+	}{
+		{"", DefaultLanguage},
+		{"en-US,en;q=0.9", English},
+		{"ky-KG,ky;q=0.9,ru;q=0.8", Kyrgyz},
+		{"de-DE,ru;q=0.5,en;q=0.7", English},
+		{"fr, de", DefaultLanguage},
+		{"en;q=0, ky", Kyrgyz},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, ParseAcceptLanguage(tt.header), tt.header)
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	params := map[string]interface{}{"role": "superuser"}
+
+	// Перевод по исходному сообщению с подстановкой параметров
+	assert.Equal(t, "Недопустимая роль: superuser", Localize(Russian, "VALIDATION_ERROR", "invalid role: {role}", params))
+	assert.Equal(t, "Жол берилбеген роль: superuser", Localize(Kyrgyz, "VALIDATION_ERROR", "invalid role: {role}", params))
+	assert.Equal(t, "invalid role: superuser", Localize(English, "VALIDATION_ERROR", "invalid role: {role}", params))
+
+	// Перевод по коду ошибки, если сообщение отсутствует в каталоге
+	assert.Equal(t, "Ошибка базы данных", Localize(Russian, "DATABASE_ERROR", "database error during fetching users", nil))
+	assert.Equal(t, "database error during fetching users", Localize(English, "DATABASE_ERROR", "database error during fetching users", nil))
+
+	// Неизвестный код и сообщение возвращаются как есть
+	assert.Equal(t, "something odd", Localize(Russian, "SOMETHING", "something odd", nil))
+}
@@ -0,0 +1,73 @@
+// Package jobs реализует фоновую очередь задач поверх Redis Streams: пул воркеров,
+// политики повторов, отложенные задачи и Prometheus-метрики.
+//
+// Раскладка ключей Redis (prefix по умолчанию "jobs"):
+//
+//	<prefix>:stream:<queue>  поток готовых к выполнению задач (consumer group "workers")
+//	<prefix>:scheduled       отложенные задачи и повторы, score — время запуска (unix ms)
+//	<prefix>:scheduled:keys  ключ задачи (WithKey) -> ее текущая запись: в <prefix>:scheduled
+//	                         или, пока задача выполняется, в потоке очереди
+//	<prefix>:dead:<queue>    задачи, исчерпавшие попытки (последние deadLimit штук)
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// DefaultQueue очередь по умолчанию
+const DefaultQueue = "default"
+
+// Job задача очереди
+type Job struct {
+	ID         string          `json:"id"`
+	Type       string          `json:"type"`
+	Queue      string          `json:"queue"`
+	Payload    json.RawMessage `json:"payload,omitempty"`
+	Attempt    int             `json:"attempt"` // количество неудачных попыток
+	MaxRetries int             `json:"max_retries"`
+	EnqueuedAt time.Time       `json:"enqueued_at"`
+	RunAt      time.Time       `json:"run_at,omitempty"`
+	Key        string          `json:"key,omitempty"` // ключ отложенной задачи (WithKey)
+	LastError  string          `json:"last_error,omitempty"`
+}
+
+// Decode декодирует полезную нагрузку задачи
+func (j *Job) Decode(v interface{}) error {
+	if len(j.Payload) == 0 {
+		return errors.New("job has no payload")
+	}
+	return json.Unmarshal(j.Payload, v)
+}
+
+// EnqueueOption настраивает постановку задачи
+type EnqueueOption func(*Job)
+
+// WithQueue ставит задачу в указанную очередь
+func WithQueue(queue string) EnqueueOption {
+	return func(j *Job) { j.Queue = queue }
+}
+
+// WithDelay откладывает выполнение задачи
+func WithDelay(d time.Duration) EnqueueOption {
+	return func(j *Job) { j.RunAt = time.Now().Add(d) }
+}
+
+// At назначает время выполнения задачи
+func At(t time.Time) EnqueueOption {
+	return func(j *Job) { j.RunAt = t }
+}
+
+// WithKey задает ключ задачи. Отложенная задача с ключом заменяет ранее запланированную с тем же
+// ключом, а немедленная снимает ее с расписания, поэтому перенос срока не создает дублей, даже если
+// задачу планируют несколько экземпляров. Ключ сохраняется у повторов до завершения задачи: отмена
+// по ключу (Manager.Cancel) снимает с расписания и ожидающий повтор.
+func WithKey(key string) EnqueueOption {
+	return func(j *Job) { j.Key = key }
+}
+
+// WithMaxRetries переопределяет количество повторов из политики обработчика
+func WithMaxRetries(n int) EnqueueOption {
+	return func(j *Job) { j.MaxRetries = n }
+}
@@ -0,0 +1,806 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+)
+
+// Параметры менеджера по умолчанию
+const (
+	DefaultPrefix       = "jobs"
+	DefaultConcurrency  = 10
+	DefaultPollInterval = time.Second
+	DefaultClaimAfter   = 5 * time.Minute
+	// DefaultScheduleInterval наибольшая задержка запуска отложенной задачи
+	DefaultScheduleInterval = 250 * time.Millisecond
+
+	consumerGroup = "workers"
+	deadLimit     = 1000
+	moveBatch     = 100
+)
+
+// ErrNoHandler возвращается, если для типа задачи не зарегистрирован обработчик
+var ErrNoHandler = errors.New("no handler registered for job type")
+
+// Handler обрабатывает задачу. Ошибка приводит к повтору по политике обработчика,
+// ошибка, обернутая в Permanent, — сразу в dead.
+type Handler func(ctx context.Context, job *Job) error
+
+// Config параметры менеджера очереди
+type Config struct {
+	Prefix string
+	// Queues количество воркеров по очередям; пустая карта — DefaultQueue с DefaultConcurrency
+	Queues       map[string]int
+	PollInterval time.Duration
+	// ClaimAfter время, после которого задача упавшего воркера забирается другим
+	ClaimAfter time.Duration
+	// ScheduleInterval наибольшая пауза между проверками расписания. Задачи, поставленные этим
+	// экземпляром, переносятся в очередь точно в срок, поставленные другими — не позже ScheduleInterval.
+	ScheduleInterval time.Duration
+	Registerer       prometheus.Registerer
+	// OnDead вызывается для задачи, исчерпавшей попытки, после записи в <prefix>:dead:<queue>;
+	// используется для сохранения задачи в постоянную очередь недоставленных сообщений
+	OnDead func(ctx context.Context, job Job)
+}
+
+type registration struct {
+	handler Handler
+	policy  RetryPolicy
+}
+
+// Manager ставит задачи в очередь и выполняет их пулом воркеров
+type Manager struct {
+	client   *redis.Client
+	cfg      Config
+	logger   *logger.Logger
+	metrics  *jobMetrics
+	consumer string
+
+	mu       sync.RWMutex
+	handlers map[string]registration
+
+	// wake будит перенос отложенных задач, когда этот экземпляр планирует задачу раньше ближайшей
+	wake chan struct{}
+
+	cancel  context.CancelFunc
+	running atomic.Bool
+	wg      sync.WaitGroup
+}
+
+// QueueStats состояние очереди
+type QueueStats struct {
+	Queue   string `json:"queue"`
+	Workers int    `json:"workers"`
+	Ready   int64  `json:"ready"`
+	Pending int64  `json:"pending"`
+	Dead    int64  `json:"dead"`
+}
+
+// Stats состояние подсистемы задач для дашборда
+type Stats struct {
+	Running   bool         `json:"running"`
+	Queues    []QueueStats `json:"queues"`
+	Scheduled int64        `json:"scheduled"`
+	// NextRunAt время запуска ближайшей отложенной задачи
+	NextRunAt *time.Time `json:"next_run_at,omitempty"`
+	Types     []string   `json:"types"`
+}
+
+// NewManager создает менеджер очереди. Воркеры запускаются через Start;
+// без Start менеджер только ставит задачи (например, в API-инстансе без воркеров).
+func NewManager(client *redis.Client, cfg Config, log *logrus.Logger) *Manager {
+	if cfg.Prefix == "" {
+		cfg.Prefix = DefaultPrefix
+	}
+	if len(cfg.Queues) == 0 {
+		cfg.Queues = map[string]int{DefaultQueue: DefaultConcurrency}
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultPollInterval
+	}
+	if cfg.ClaimAfter <= 0 {
+		cfg.ClaimAfter = DefaultClaimAfter
+	}
+	if cfg.ScheduleInterval <= 0 {
+		cfg.ScheduleInterval = DefaultScheduleInterval
+	}
+	if cfg.Registerer == nil {
+		cfg.Registerer = prometheus.DefaultRegisterer
+	}
+
+	host, _ := os.Hostname()
+
+	return &Manager{
+		client:   client,
+		cfg:      cfg,
+		logger:   logger.New(log),
+		metrics:  newJobMetrics(cfg.Registerer),
+		consumer: fmt.Sprintf("%s-%d-%s", host, os.Getpid(), uuid.NewString()[:8]),
+		handlers: make(map[string]registration),
+		wake:     make(chan struct{}, 1),
+	}
+}
+
+// Register регистрирует обработчик типа задачи; без policy используется DefaultRetryPolicy
+func (m *Manager) Register(jobType string, handler Handler, policy ...RetryPolicy) {
+	reg := registration{handler: handler, policy: DefaultRetryPolicy}
+	if len(policy) > 0 {
+		reg.policy = policy[0]
+		if reg.policy.Backoff == nil {
+			reg.policy.Backoff = DefaultRetryPolicy.Backoff
+		}
+	}
+
+	m.mu.Lock()
+	m.handlers[jobType] = reg
+	m.mu.Unlock()
+}
+
+// Enqueue ставит задачу в очередь. payload сериализуется в JSON.
+func (m *Manager) Enqueue(ctx context.Context, jobType string, payload interface{}, opts ...EnqueueOption) (*Job, error) {
+	job := &Job{
+		ID:         uuid.NewString(),
+		Type:       jobType,
+		Queue:      DefaultQueue,
+		MaxRetries: -1,
+		EnqueuedAt: time.Now().UTC(),
+	}
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("encoding job payload: %w", err)
+		}
+		job.Payload = raw
+	}
+	for _, opt := range opts {
+		opt(job)
+	}
+	if job.MaxRetries < 0 {
+		job.MaxRetries = m.registration(jobType).policy.MaxRetries
+	}
+
+	var err error
+	if !job.RunAt.IsZero() && job.RunAt.After(time.Now()) {
+		err = m.schedule(ctx, job, job.RunAt)
+	} else {
+		job.RunAt = time.Time{}
+		err = m.push(ctx, job)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	m.metrics.enqueued.WithLabelValues(job.Queue, job.Type).Inc()
+	return job, nil
+}
+
+// Requeue ставит задачу, исчерпавшую попытки, обратно в очередь с тем же ID и обнуленным счетчиком попыток
+func (m *Manager) Requeue(ctx context.Context, job Job) (*Job, error) {
+	if job.ID == "" || job.Type == "" {
+		return nil, errors.New("job id and type are required")
+	}
+	if job.Queue == "" {
+		job.Queue = DefaultQueue
+	}
+	if job.MaxRetries < 0 {
+		job.MaxRetries = m.registration(job.Type).policy.MaxRetries
+	}
+	job.Attempt = 0
+	job.LastError = ""
+	job.RunAt = time.Time{}
+	job.EnqueuedAt = time.Now().UTC()
+
+	if err := m.push(ctx, &job); err != nil {
+		return nil, err
+	}
+
+	m.metrics.enqueued.WithLabelValues(job.Queue, job.Type).Inc()
+	m.logger.Info(ctx, "Dead job requeued", logrus.Fields{"job_id": job.ID, "type": job.Type, "queue": job.Queue})
+	return &job, nil
+}
+
+// Start запускает воркеры, перенос отложенных задач и возврат зависших задач
+func (m *Manager) Start(ctx context.Context) {
+	ctx, m.cancel = context.WithCancel(ctx)
+	m.running.Store(true)
+
+	for queue, workers := range m.cfg.Queues {
+		if err := m.ensureGroup(ctx, queue); err != nil {
+			m.logger.Error(ctx, "Failed to create job consumer group", err, logrus.Fields{"queue": queue})
+		}
+		for i := 0; i < workers; i++ {
+			m.wg.Add(1)
+			go m.work(ctx, queue)
+		}
+	}
+
+	m.wg.Add(2)
+	go m.scheduleLoop(ctx)
+	go m.loop(ctx, m.cfg.ClaimAfter/2, m.reclaim)
+
+	m.logger.Info(ctx, "Job workers started", logrus.Fields{"queues": m.cfg.Queues, "consumer": m.consumer})
+}
+
+// Stop останавливает воркеры и ждет завершения выполняющихся задач
+func (m *Manager) Stop() {
+	if !m.running.CompareAndSwap(true, false) {
+		return
+	}
+	m.cancel()
+	m.wg.Wait()
+	m.logger.Info(context.Background(), "Job workers stopped")
+}
+
+// Stats возвращает размеры очередей и обновляет gauge jobs_queue_depth и jobs_scheduled
+func (m *Manager) Stats(ctx context.Context) (Stats, error) {
+	stats := Stats{Running: m.running.Load(), Types: []string{}}
+
+	for _, queue := range m.queueNames() {
+		qs := QueueStats{Queue: queue, Workers: m.cfg.Queues[queue]}
+
+		length, err := m.client.XLen(ctx, m.streamKey(queue)).Result()
+		if err != nil {
+			return Stats{}, err
+		}
+		if pending, err := m.client.XPending(ctx, m.streamKey(queue), consumerGroup).Result(); err == nil {
+			qs.Pending = pending.Count
+		}
+		qs.Ready = length - qs.Pending
+		if qs.Dead, err = m.client.LLen(ctx, m.deadKey(queue)).Result(); err != nil {
+			return Stats{}, err
+		}
+
+		m.metrics.depth.WithLabelValues(queue, "ready").Set(float64(qs.Ready))
+		m.metrics.depth.WithLabelValues(queue, "pending").Set(float64(qs.Pending))
+		m.metrics.depth.WithLabelValues(queue, "dead").Set(float64(qs.Dead))
+		stats.Queues = append(stats.Queues, qs)
+	}
+
+	scheduled, err := m.client.ZCard(ctx, m.scheduledKey()).Result()
+	if err != nil {
+		return Stats{}, err
+	}
+	stats.Scheduled = scheduled
+	m.metrics.scheduled.Set(float64(scheduled))
+	if next, ok, err := m.nextRunAt(ctx); err != nil {
+		return Stats{}, err
+	} else if ok {
+		stats.NextRunAt = &next
+	}
+
+	m.mu.RLock()
+	for t := range m.handlers {
+		stats.Types = append(stats.Types, t)
+	}
+	m.mu.RUnlock()
+	sort.Strings(stats.Types)
+
+	return stats, nil
+}
+
+// DeadJobs возвращает последние задачи очереди, исчерпавшие попытки
+func (m *Manager) DeadJobs(ctx context.Context, queue string, limit int64) ([]Job, error) {
+	raw, err := m.client.LRange(ctx, m.deadKey(queue), 0, limit-1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]Job, 0, len(raw))
+	for _, r := range raw {
+		var job Job
+		if err := json.Unmarshal([]byte(r), &job); err == nil {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, nil
+}
+
+// Scheduled возвращает отложенные задачи и повторы в порядке запуска, начиная с offset,
+// и общее число задач в расписании
+func (m *Manager) Scheduled(ctx context.Context, offset, limit int64) ([]Job, int64, error) {
+	pipe := m.client.Pipeline()
+	rangeCmd := pipe.ZRange(ctx, m.scheduledKey(), offset, offset+limit-1)
+	countCmd := pipe.ZCard(ctx, m.scheduledKey())
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, 0, err
+	}
+
+	raw := rangeCmd.Val()
+	jobs := make([]Job, 0, len(raw))
+	for _, r := range raw {
+		var job Job
+		if err := json.Unmarshal([]byte(r), &job); err == nil {
+			jobs = append(jobs, job)
+		}
+	}
+	return jobs, countCmd.Val(), nil
+}
+
+// cancelScript освобождает ключ задачи и снимает с расписания ее запись, если она еще не в очереди
+var cancelScript = redis.NewScript(`
+local member = redis.call('HGET', KEYS[2], ARGV[1])
+if not member then
+	return 0
+end
+redis.call('HDEL', KEYS[2], ARGV[1])
+redis.call('ZREM', KEYS[1], member)
+return 1
+`)
+
+// Cancel отменяет задачу с ключом key (WithKey): снимает ее с расписания вместе с ожидающим
+// повтором, а у выполняемой задачи отменяет повторы. false — задачи с таким ключом нет.
+func (m *Manager) Cancel(ctx context.Context, key string) (bool, error) {
+	removed, err := cancelScript.Run(ctx, m.client, []string{m.scheduledKey(), m.scheduledKeysKey()}, key).Int()
+	if err != nil {
+		return false, err
+	}
+	if removed > 0 {
+		m.logger.Info(ctx, "Keyed job cancelled", logrus.Fields{"key": key})
+	}
+	return removed > 0, nil
+}
+
+// Current сообщает, что задача с ключом все еще актуальна: не отменена через Cancel и не заменена
+// новой задачей с тем же ключом. Задача без ключа актуальна всегда. Обработчик вызывает Current
+// перед действием, которое нельзя выполнять после отмены.
+func (m *Manager) Current(ctx context.Context, job *Job) (bool, error) {
+	if job.Key == "" {
+		return true, nil
+	}
+	raw, err := m.client.HGet(ctx, m.scheduledKeysKey(), job.Key).Result()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	var current Job
+	if err := json.Unmarshal([]byte(raw), &current); err != nil {
+		return false, err
+	}
+	return current.ID == job.ID, nil
+}
+
+func (m *Manager) work(ctx context.Context, queue string) {
+	defer m.wg.Done()
+
+	for ctx.Err() == nil {
+		streams, err := m.client.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    consumerGroup,
+			Consumer: m.consumer,
+			Streams:  []string{m.streamKey(queue), ">"},
+			Count:    1,
+			Block:    m.cfg.PollInterval,
+		}).Result()
+		if err != nil {
+			if errors.Is(err, redis.Nil) || ctx.Err() != nil {
+				continue
+			}
+			if strings.HasPrefix(err.Error(), "NOGROUP") {
+				_ = m.ensureGroup(ctx, queue)
+			} else {
+				m.logger.Warn(ctx, "Failed to read jobs", logrus.Fields{"queue": queue, "error": err.Error()})
+			}
+			m.sleep(ctx, m.cfg.PollInterval)
+			continue
+		}
+
+		for _, stream := range streams {
+			for _, msg := range stream.Messages {
+				m.handleMessage(ctx, queue, msg)
+			}
+		}
+	}
+}
+
+func (m *Manager) handleMessage(ctx context.Context, queue string, msg redis.XMessage) {
+	raw, _ := msg.Values["job"].(string)
+
+	var job Job
+	if err := json.Unmarshal([]byte(raw), &job); err != nil {
+		m.logger.Error(ctx, "Dropping malformed job", err, logrus.Fields{"queue": queue, "message_id": msg.ID})
+		m.ack(ctx, queue, msg.ID)
+		return
+	}
+
+	if !job.RunAt.IsZero() {
+		m.metrics.lag.WithLabelValues(job.Queue, job.Type).Observe(time.Since(job.RunAt).Seconds())
+	}
+
+	err := m.run(ctx, &job)
+
+	// Задача, прерванная остановкой, остается в pending и будет забрана после ClaimAfter
+	if err != nil && ctx.Err() != nil {
+		return
+	}
+
+	switch {
+	case err == nil:
+		m.release(ctx, &job)
+		m.metrics.processed.WithLabelValues(job.Queue, job.Type, statusSuccess).Inc()
+	case !IsPermanent(err) && !errors.Is(err, ErrNoHandler) && job.Attempt < job.MaxRetries:
+		m.retry(ctx, &job, err)
+	default:
+		m.release(ctx, &job)
+		m.bury(ctx, &job, err)
+	}
+
+	m.ack(ctx, queue, msg.ID)
+}
+
+// run выполняет обработчик, превращая панику в ошибку
+func (m *Manager) run(ctx context.Context, job *Job) (err error) {
+	reg, ok := m.lookup(job.Type)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNoHandler, job.Type)
+	}
+
+	start := time.Now()
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job handler panic: %v", r)
+		}
+		m.metrics.duration.WithLabelValues(job.Queue, job.Type).Observe(time.Since(start).Seconds())
+	}()
+
+	return reg.handler(ctx, job)
+}
+
+func (m *Manager) retry(ctx context.Context, job *Job, cause error) {
+	job.Attempt++
+	job.LastError = cause.Error()
+	delay := m.registration(job.Type).policy.Backoff(job.Attempt)
+
+	m.logger.Warn(ctx, "Job failed, scheduling retry", logrus.Fields{
+		"job_id":  job.ID,
+		"type":    job.Type,
+		"attempt": job.Attempt,
+		"delay":   delay.String(),
+		"error":   cause.Error(),
+	})
+
+	scheduled, err := m.scheduleRetry(ctx, job, time.Now().Add(delay))
+	if err != nil {
+		m.logger.Error(ctx, "Failed to schedule job retry", err, logrus.Fields{"job_id": job.ID})
+		return
+	}
+	if !scheduled {
+		m.logger.Info(ctx, "Job retry dropped: the job was cancelled or replaced by its key", logrus.Fields{
+			"job_id": job.ID,
+			"type":   job.Type,
+			"key":    job.Key,
+		})
+		return
+	}
+	m.metrics.processed.WithLabelValues(job.Queue, job.Type, statusRetry).Inc()
+}
+
+// releaseKeyScript освобождает ключ завершенной задачи, если он еще принадлежит ей
+var releaseKeyScript = redis.NewScript(`
+local current = redis.call('HGET', KEYS[1], ARGV[1])
+if current and cjson.decode(current)['id'] == ARGV[2] then
+	return redis.call('HDEL', KEYS[1], ARGV[1])
+end
+return 0
+`)
+
+// release освобождает ключ задачи после успешного выполнения или исчерпания попыток
+func (m *Manager) release(ctx context.Context, job *Job) {
+	if job.Key == "" {
+		return
+	}
+	if err := releaseKeyScript.Run(ctx, m.client, []string{m.scheduledKeysKey()}, job.Key, job.ID).Err(); err != nil {
+		m.logger.Warn(ctx, "Failed to release job key", logrus.Fields{"job_id": job.ID, "key": job.Key, "error": err.Error()})
+	}
+}
+
+func (m *Manager) bury(ctx context.Context, job *Job, cause error) {
+	job.LastError = cause.Error()
+
+	m.logger.Error(ctx, "Job failed permanently", cause, logrus.Fields{
+		"job_id":  job.ID,
+		"type":    job.Type,
+		"attempt": job.Attempt,
+	})
+
+	raw, err := json.Marshal(job)
+	if err == nil {
+		pipe := m.client.TxPipeline()
+		pipe.LPush(ctx, m.deadKey(job.Queue), raw)
+		pipe.LTrim(ctx, m.deadKey(job.Queue), 0, deadLimit-1)
+		_, err = pipe.Exec(ctx)
+	}
+	if err != nil {
+		m.logger.Error(ctx, "Failed to store dead job", err, logrus.Fields{"job_id": job.ID})
+	}
+	if m.cfg.OnDead != nil {
+		m.cfg.OnDead(ctx, *job)
+	}
+	m.metrics.processed.WithLabelValues(job.Queue, job.Type, statusDead).Inc()
+}
+
+func (m *Manager) ack(ctx context.Context, queue, id string) {
+	pipe := m.client.TxPipeline()
+	pipe.XAck(ctx, m.streamKey(queue), consumerGroup, id)
+	pipe.XDel(ctx, m.streamKey(queue), id)
+	if _, err := pipe.Exec(ctx); err != nil {
+		m.logger.Warn(ctx, "Failed to acknowledge job", logrus.Fields{"queue": queue, "message_id": id, "error": err.Error()})
+	}
+}
+
+// moveDueScript атомарно переносит наступившие отложенные задачи в потоки их очередей.
+// Ключ остается за задачей, пока она выполняется, и освобождается после завершения (release).
+var moveDueScript = redis.NewScript(`
+local due = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, tonumber(ARGV[2]))
+for _, member in ipairs(due) do
+	if redis.call('ZREM', KEYS[1], member) == 1 then
+		local job = cjson.decode(member)
+		redis.call('XADD', ARGV[3] .. job['queue'], '*', 'job', member)
+	end
+end
+return #due
+`)
+
+// moveDue переносит наступившие задачи; false — ошибка Redis
+func (m *Manager) moveDue(ctx context.Context) bool {
+	for {
+		moved, err := moveDueScript.Run(ctx, m.client,
+			[]string{m.scheduledKey(), m.scheduledKeysKey()},
+			time.Now().UnixMilli(), moveBatch, m.cfg.Prefix+":stream:",
+		).Int()
+		if err != nil {
+			if ctx.Err() == nil {
+				m.logger.Warn(ctx, "Failed to move scheduled jobs", logrus.Fields{"error": err.Error()})
+			}
+			return false
+		}
+		if moved < moveBatch {
+			return true
+		}
+	}
+}
+
+// scheduleLoop переносит отложенные задачи в очереди. Между проверками цикл ждет до срока
+// ближайшей задачи, но не дольше ScheduleInterval, поэтому задача попадает в очередь в пределах
+// миллисекунд от срока, а задача, поставленная другим экземпляром, — не позже ScheduleInterval.
+func (m *Manager) scheduleLoop(ctx context.Context) {
+	defer m.wg.Done()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		case <-m.wake:
+		}
+
+		wait := m.cfg.ScheduleInterval
+		if m.moveDue(ctx) {
+			if next, ok, err := m.nextRunAt(ctx); err == nil && ok {
+				wait = min(wait, max(time.Until(next), 0))
+			}
+		}
+		timer.Reset(wait)
+	}
+}
+
+// nextRunAt время запуска ближайшей отложенной задачи; false — расписание пусто
+func (m *Manager) nextRunAt(ctx context.Context) (time.Time, bool, error) {
+	next, err := m.client.ZRangeWithScores(ctx, m.scheduledKey(), 0, 0).Result()
+	if err != nil || len(next) == 0 {
+		return time.Time{}, false, err
+	}
+	return time.UnixMilli(int64(next[0].Score)).UTC(), true, nil
+}
+
+// reclaim возвращает в поток задачи, зависшие у упавших воркеров дольше ClaimAfter
+func (m *Manager) reclaim(ctx context.Context) {
+	for queue := range m.cfg.Queues {
+		msgs, _, err := m.client.XAutoClaim(ctx, &redis.XAutoClaimArgs{
+			Stream:   m.streamKey(queue),
+			Group:    consumerGroup,
+			Consumer: m.consumer,
+			MinIdle:  m.cfg.ClaimAfter,
+			Start:    "0",
+			Count:    moveBatch,
+		}).Result()
+		if err != nil {
+			if ctx.Err() == nil && !errors.Is(err, redis.Nil) {
+				m.logger.Warn(ctx, "Failed to reclaim stale jobs", logrus.Fields{"queue": queue, "error": err.Error()})
+			}
+			continue
+		}
+
+		for _, msg := range msgs {
+			m.logger.Warn(ctx, "Reclaimed stale job", logrus.Fields{"queue": queue, "message_id": msg.ID})
+			m.handleMessage(ctx, queue, msg)
+		}
+	}
+
+	if _, err := m.Stats(ctx); err != nil && ctx.Err() == nil {
+		m.logger.Warn(ctx, "Failed to refresh job queue metrics", logrus.Fields{"error": err.Error()})
+	}
+}
+
+func (m *Manager) loop(ctx context.Context, interval time.Duration, fn func(context.Context)) {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fn(ctx)
+		}
+	}
+}
+
+// pushKeyedScript ставит задачу с ключом в поток, снимая с расписания прежнюю задачу с этим ключом
+var pushKeyedScript = redis.NewScript(`
+local previous = redis.call('HGET', KEYS[2], ARGV[2])
+if previous then
+	redis.call('ZREM', KEYS[1], previous)
+end
+redis.call('HSET', KEYS[2], ARGV[2], ARGV[1])
+return redis.call('XADD', KEYS[3], '*', 'job', ARGV[1])
+`)
+
+func (m *Manager) push(ctx context.Context, job *Job) error {
+	raw, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	if job.Key != "" {
+		return pushKeyedScript.Run(ctx, m.client,
+			[]string{m.scheduledKey(), m.scheduledKeysKey(), m.streamKey(job.Queue)},
+			string(raw), job.Key,
+		).Err()
+	}
+	return m.client.XAdd(ctx, &redis.XAddArgs{
+		Stream: m.streamKey(job.Queue),
+		Values: map[string]interface{}{"job": string(raw)},
+	}).Err()
+}
+
+// scheduleKeyedScript планирует задачу с ключом, снимая с расписания прежнюю задачу с этим ключом
+var scheduleKeyedScript = redis.NewScript(`
+local previous = redis.call('HGET', KEYS[2], ARGV[3])
+if previous then
+	redis.call('ZREM', KEYS[1], previous)
+end
+redis.call('ZADD', KEYS[1], ARGV[1], ARGV[2])
+redis.call('HSET', KEYS[2], ARGV[3], ARGV[2])
+return 1
+`)
+
+func (m *Manager) schedule(ctx context.Context, job *Job, at time.Time) error {
+	job.RunAt = at.UTC()
+	raw, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	if job.Key != "" {
+		err = scheduleKeyedScript.Run(ctx, m.client,
+			[]string{m.scheduledKey(), m.scheduledKeysKey()},
+			at.UnixMilli(), string(raw), job.Key,
+		).Err()
+	} else {
+		err = m.client.ZAdd(ctx, m.scheduledKey(), redis.Z{
+			Score:  float64(at.UnixMilli()),
+			Member: string(raw),
+		}).Err()
+	}
+	if err != nil {
+		return err
+	}
+	m.wakeScheduler()
+	return nil
+}
+
+// scheduleRetryScript планирует повтор задачи с ключом, только если ключ все еще принадлежит ей:
+// после Cancel или постановки новой задачи с тем же ключом повтор не нужен
+var scheduleRetryScript = redis.NewScript(`
+local current = redis.call('HGET', KEYS[2], ARGV[3])
+if not current or cjson.decode(current)['id'] ~= ARGV[4] then
+	return 0
+end
+redis.call('ZADD', KEYS[1], ARGV[1], ARGV[2])
+redis.call('HSET', KEYS[2], ARGV[3], ARGV[2])
+return 1
+`)
+
+// scheduleRetry планирует повтор; false — задача с ключом отменена или заменена, повтор не поставлен
+func (m *Manager) scheduleRetry(ctx context.Context, job *Job, at time.Time) (bool, error) {
+	if job.Key == "" {
+		return true, m.schedule(ctx, job, at)
+	}
+
+	job.RunAt = at.UTC()
+	raw, err := json.Marshal(job)
+	if err != nil {
+		return false, err
+	}
+	scheduled, err := scheduleRetryScript.Run(ctx, m.client,
+		[]string{m.scheduledKey(), m.scheduledKeysKey()},
+		at.UnixMilli(), string(raw), job.Key, job.ID,
+	).Int()
+	if err != nil || scheduled == 0 {
+		return false, err
+	}
+	m.wakeScheduler()
+	return true, nil
+}
+
+// wakeScheduler будит цикл переноса: он может ждать более позднюю задачу и без этого
+// пересчитал бы паузу только через ScheduleInterval
+func (m *Manager) wakeScheduler() {
+	select {
+	case m.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (m *Manager) ensureGroup(ctx context.Context, queue string) error {
+	err := m.client.XGroupCreateMkStream(ctx, m.streamKey(queue), consumerGroup, "0").Err()
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil
+	}
+	return err
+}
+
+func (m *Manager) lookup(jobType string) (registration, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	reg, ok := m.handlers[jobType]
+	return reg, ok
+}
+
+// registration возвращает регистрацию типа или политику по умолчанию
+func (m *Manager) registration(jobType string) registration {
+	if reg, ok := m.lookup(jobType); ok {
+		return reg
+	}
+	return registration{policy: DefaultRetryPolicy}
+}
+
+func (m *Manager) queueNames() []string {
+	names := make([]string, 0, len(m.cfg.Queues))
+	for q := range m.cfg.Queues {
+		names = append(names, q)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (m *Manager) sleep(ctx context.Context, d time.Duration) {
+	select {
+	case <-ctx.Done():
+	case <-time.After(d):
+	}
+}
+
+func (m *Manager) streamKey(queue string) string { return m.cfg.Prefix + ":stream:" + queue }
+func (m *Manager) deadKey(queue string) string   { return m.cfg.Prefix + ":dead:" + queue }
+func (m *Manager) scheduledKey() string          { return m.cfg.Prefix + ":scheduled" }
+func (m *Manager) scheduledKeysKey() string      { return m.cfg.Prefix + ":scheduled:keys" }
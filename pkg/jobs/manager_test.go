@@ -0,0 +1,323 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Note: These tests require Redis to be running on localhost:6379
+// Run with: go test -v ./pkg/jobs/
+
+func setupTestManager(t *testing.T) (*Manager, *redis.Client) {
+	client := redis.NewClient(&redis.Options{
+		Addr: "localhost:6379",
+	})
+
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		t.Skip("Redis not running, skipping tests")
+	}
+
+	prefix := "jobs-test-" + uuid.NewString()[:8]
+	t.Cleanup(func() {
+		ctx := context.Background()
+		keys, _ := client.Keys(ctx, prefix+":*").Result()
+		if len(keys) > 0 {
+			client.Del(ctx, keys...)
+		}
+		client.Close()
+	})
+
+	manager := NewManager(client, Config{
+		Prefix:       prefix,
+		Queues:       map[string]int{DefaultQueue: 2},
+		PollInterval: 50 * time.Millisecond,
+		Registerer:   prometheus.NewRegistry(),
+	}, logrus.New())
+
+	return manager, client
+}
+
+func TestManager_ProcessesJob(t *testing.T) {
+	manager, _ := setupTestManager(t)
+
+	done := make(chan string, 1)
+	manager.Register("greet", func(ctx context.Context, job *Job) error {
+		var payload struct {
+			Name string `json:"name"`
+		}
+		if err := job.Decode(&payload); err != nil {
+			return Permanent(err)
+		}
+		done <- payload.Name
+		return nil
+	})
+
+	manager.Start(context.Background())
+	defer manager.Stop()
+
+	_, err := manager.Enqueue(context.Background(), "greet", map[string]string{"name": "tunduck"})
+	require.NoError(t, err)
+
+	select {
+	case name := <-done:
+		assert.Equal(t, "tunduck", name)
+	case <-time.After(5 * time.Second):
+		t.Fatal("job was not processed")
+	}
+}
+
+func TestManager_RetriesThenDead(t *testing.T) {
+	manager, _ := setupTestManager(t)
+
+	var attempts int32
+	manager.Register("flaky", func(ctx context.Context, job *Job) error {
+		atomic.AddInt32(&attempts, 1)
+		return errors.New("gateway unavailable")
+	}, RetryPolicy{MaxRetries: 2, Backoff: ConstantBackoff(10 * time.Millisecond)})
+
+	manager.Start(context.Background())
+	defer manager.Stop()
+
+	_, err := manager.Enqueue(context.Background(), "flaky", nil)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		dead, err := manager.DeadJobs(context.Background(), DefaultQueue, 10)
+		return err == nil && len(dead) == 1
+	}, 5*time.Second, 50*time.Millisecond)
+
+	dead, err := manager.DeadJobs(context.Background(), DefaultQueue, 10)
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&attempts))
+	assert.Equal(t, 2, dead[0].Attempt)
+	assert.Equal(t, "gateway unavailable", dead[0].LastError)
+}
+
+func TestManager_DelayedJob(t *testing.T) {
+	manager, _ := setupTestManager(t)
+	ctx := context.Background()
+
+	job, err := manager.Enqueue(ctx, "report", nil, WithDelay(time.Hour))
+	require.NoError(t, err)
+	assert.False(t, job.RunAt.IsZero())
+
+	stats, err := manager.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.Scheduled)
+	require.Len(t, stats.Queues, 1)
+	assert.Equal(t, int64(0), stats.Queues[0].Ready)
+}
+
+func TestManager_DelayedJobRunsOnTime(t *testing.T) {
+	manager, _ := setupTestManager(t)
+	ctx := context.Background()
+
+	started := make(chan time.Time, 1)
+	manager.Register("remind", func(ctx context.Context, job *Job) error {
+		started <- time.Now()
+		return nil
+	})
+
+	manager.Start(ctx)
+	defer manager.Stop()
+
+	runAt := time.Now().Add(1500 * time.Millisecond)
+	_, err := manager.Enqueue(ctx, "remind", nil, At(runAt))
+	require.NoError(t, err)
+
+	select {
+	case at := <-started:
+		assert.False(t, at.Before(runAt.Truncate(time.Millisecond)), "job must not start early")
+		assert.Less(t, at.Sub(runAt), time.Second)
+	case <-time.After(5 * time.Second):
+		t.Fatal("delayed job was not processed")
+	}
+}
+
+func TestManager_KeyedScheduleReplacesAndCancels(t *testing.T) {
+	manager, _ := setupTestManager(t)
+	ctx := context.Background()
+
+	_, err := manager.Enqueue(ctx, "send", nil, WithDelay(2*time.Hour), WithKey("doc-1"))
+	require.NoError(t, err)
+	_, err = manager.Enqueue(ctx, "send", nil, WithDelay(time.Hour), WithKey("doc-1"))
+	require.NoError(t, err)
+	_, err = manager.Enqueue(ctx, "report", nil, WithDelay(3*time.Hour))
+	require.NoError(t, err)
+
+	// Повторная постановка с тем же ключом переносит задачу, а не добавляет вторую
+	scheduled, total, err := manager.Scheduled(ctx, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, scheduled, 2)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, "doc-1", scheduled[0].Key)
+	assert.Equal(t, "report", scheduled[1].Type)
+
+	stats, err := manager.Stats(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, stats.NextRunAt)
+	assert.True(t, stats.NextRunAt.Equal(scheduled[0].RunAt.Truncate(time.Millisecond)))
+
+	cancelled, err := manager.Cancel(ctx, "doc-1")
+	require.NoError(t, err)
+	assert.True(t, cancelled)
+	cancelled, err = manager.Cancel(ctx, "doc-1")
+	require.NoError(t, err)
+	assert.False(t, cancelled)
+
+	scheduled, _, err = manager.Scheduled(ctx, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, scheduled, 1)
+	assert.Equal(t, "report", scheduled[0].Type)
+}
+
+func TestManager_KeyedRetryKeepsKey(t *testing.T) {
+	manager, _ := setupTestManager(t)
+	ctx := context.Background()
+
+	var attempts int32
+	manager.Register("send", func(ctx context.Context, job *Job) error {
+		atomic.AddInt32(&attempts, 1)
+		return errors.New("gateway unavailable")
+	}, RetryPolicy{MaxRetries: 3, Backoff: ConstantBackoff(time.Hour)})
+
+	manager.Start(ctx)
+	defer manager.Stop()
+
+	job, err := manager.Enqueue(ctx, "send", nil, WithKey("doc-1"))
+	require.NoError(t, err)
+
+	// Повтор после неудачи остается в расписании под тем же ключом и актуален
+	var scheduled []Job
+	require.Eventually(t, func() bool {
+		scheduled, _, err = manager.Scheduled(ctx, 0, 10)
+		return err == nil && len(scheduled) == 1
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.Equal(t, job.ID, scheduled[0].ID)
+	assert.Equal(t, "doc-1", scheduled[0].Key)
+	assert.Equal(t, 1, scheduled[0].Attempt)
+	current, err := manager.Current(ctx, &scheduled[0])
+	require.NoError(t, err)
+	assert.True(t, current)
+
+	// Отмена по ключу снимает ожидающий повтор
+	cancelled, err := manager.Cancel(ctx, "doc-1")
+	require.NoError(t, err)
+	assert.True(t, cancelled)
+	scheduled, _, err = manager.Scheduled(ctx, 0, 10)
+	require.NoError(t, err)
+	assert.Empty(t, scheduled)
+	current, err = manager.Current(ctx, job)
+	require.NoError(t, err)
+	assert.False(t, current)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+}
+
+func TestManager_KeyedRetryDroppedWhenReplaced(t *testing.T) {
+	manager, _ := setupTestManager(t)
+	ctx := context.Background()
+
+	replaced := make(chan struct{})
+	var once sync.Once
+	manager.Register("send", func(ctx context.Context, job *Job) error {
+		// Пока задача выполняется, отправку переносят на другое время
+		once.Do(func() {
+			_, err := manager.Enqueue(ctx, "send", nil, WithDelay(2*time.Hour), WithKey("doc-1"))
+			assert.NoError(t, err)
+			close(replaced)
+		})
+		return errors.New("gateway unavailable")
+	}, RetryPolicy{MaxRetries: 3, Backoff: ConstantBackoff(time.Hour)})
+
+	manager.Start(ctx)
+	defer manager.Stop()
+
+	first, err := manager.Enqueue(ctx, "send", nil, WithKey("doc-1"))
+	require.NoError(t, err)
+	<-replaced
+
+	// Повтор заменой не ставится: в расписании только новая задача
+	require.Never(t, func() bool {
+		scheduled, _, err := manager.Scheduled(ctx, 0, 10)
+		return err != nil || len(scheduled) != 1
+	}, 500*time.Millisecond, 50*time.Millisecond)
+	scheduled, _, err := manager.Scheduled(ctx, 0, 10)
+	require.NoError(t, err)
+	require.Len(t, scheduled, 1)
+	assert.NotEqual(t, first.ID, scheduled[0].ID)
+	assert.Equal(t, 0, scheduled[0].Attempt)
+}
+
+func TestManager_UnknownTypeGoesToDead(t *testing.T) {
+	manager, _ := setupTestManager(t)
+	ctx := context.Background()
+
+	manager.Start(ctx)
+	defer manager.Stop()
+
+	_, err := manager.Enqueue(ctx, "missing", nil)
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		dead, err := manager.DeadJobs(ctx, DefaultQueue, 10)
+		return err == nil && len(dead) == 1 && dead[0].Attempt == 0
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func TestManager_OnDeadAndRequeue(t *testing.T) {
+	manager, _ := setupTestManager(t)
+	ctx := context.Background()
+
+	buried := make(chan Job, 1)
+	manager.cfg.OnDead = func(ctx context.Context, job Job) { buried <- job }
+
+	var fail atomic.Bool
+	fail.Store(true)
+	done := make(chan string, 1)
+	manager.Register("sync", func(ctx context.Context, job *Job) error {
+		if fail.Load() {
+			return Permanent(errors.New("rejected"))
+		}
+		done <- job.ID
+		return nil
+	})
+
+	manager.Start(ctx)
+	defer manager.Stop()
+
+	enqueued, err := manager.Enqueue(ctx, "sync", map[string]string{"id": "42"})
+	require.NoError(t, err)
+
+	var dead Job
+	select {
+	case dead = <-buried:
+	case <-time.After(5 * time.Second):
+		t.Fatal("job was not buried")
+	}
+	assert.Equal(t, enqueued.ID, dead.ID)
+	assert.Equal(t, "rejected", dead.LastError)
+
+	fail.Store(false)
+	requeued, err := manager.Requeue(ctx, dead)
+	require.NoError(t, err)
+	assert.Equal(t, 0, requeued.Attempt)
+	assert.Empty(t, requeued.LastError)
+
+	select {
+	case id := <-done:
+		assert.Equal(t, enqueued.ID, id)
+	case <-time.After(5 * time.Second):
+		t.Fatal("requeued job was not processed")
+	}
+}
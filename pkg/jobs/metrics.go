@@ -0,0 +1,54 @@
+package jobs
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/rusgainew/tunduck-app/pkg/metrics"
+)
+
+// Статусы обработки задачи в метриках
+const (
+	statusSuccess = "success"
+	statusRetry   = "retry"
+	statusDead    = "dead"
+)
+
+type jobMetrics struct {
+	enqueued  *prometheus.CounterVec
+	processed *prometheus.CounterVec
+	duration  *prometheus.HistogramVec
+	depth     *prometheus.GaugeVec
+	lag       *prometheus.HistogramVec
+	scheduled prometheus.Gauge
+}
+
+func newJobMetrics(reg prometheus.Registerer) *jobMetrics {
+	return &jobMetrics{
+		enqueued: metrics.Register(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "jobs_enqueued_total",
+			Help: "Total number of enqueued background jobs",
+		}, []string{"queue", "type"})),
+		processed: metrics.Register(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "jobs_processed_total",
+			Help: "Total number of processed background jobs by outcome (success, retry, dead)",
+		}, []string{"queue", "type", "status"})),
+		duration: metrics.Register(reg, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "jobs_duration_seconds",
+			Help:    "Background job handler duration in seconds",
+			Buckets: []float64{0.01, 0.05, 0.1, 0.5, 1, 5, 15, 60, 300},
+		}, []string{"queue", "type"})),
+		depth: metrics.Register(reg, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "jobs_queue_depth",
+			Help: "Number of jobs by queue and state (ready, pending, dead)",
+		}, []string{"queue", "state"})),
+		lag: metrics.Register(reg, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "jobs_schedule_lag_seconds",
+			Help:    "Delay between the scheduled run time of a delayed job or retry and the start of its handler",
+			Buckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2, 5, 30, 300},
+		}, []string{"queue", "type"})),
+		scheduled: metrics.Register(reg, prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "jobs_scheduled",
+			Help: "Number of delayed jobs and retries waiting for their run time",
+		})),
+	}
+}
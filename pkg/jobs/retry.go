@@ -0,0 +1,60 @@
+package jobs
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// RetryPolicy политика повторов обработчика
+type RetryPolicy struct {
+	MaxRetries int
+	// Backoff возвращает задержку перед повтором; attempt начинается с 1
+	Backoff func(attempt int) time.Duration
+}
+
+// DefaultRetryPolicy 5 повторов с экспоненциальной задержкой от 1 секунды до 10 минут
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 5,
+	Backoff:    ExponentialBackoff(time.Second, 10*time.Minute),
+}
+
+// ExponentialBackoff удваивает задержку с каждой попыткой, не превышая max
+func ExponentialBackoff(base, max time.Duration) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		if attempt < 1 {
+			attempt = 1
+		}
+		d := float64(base) * math.Pow(2, float64(attempt-1))
+		if d > float64(max) {
+			return max
+		}
+		return time.Duration(d)
+	}
+}
+
+// ConstantBackoff всегда возвращает одинаковую задержку
+func ConstantBackoff(d time.Duration) func(attempt int) time.Duration {
+	return func(int) time.Duration { return d }
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent помечает ошибку как неустранимую: задача сразу уходит в dead без повторов
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent сообщает, помечена ли ошибка как неустранимая
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
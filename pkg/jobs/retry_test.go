@@ -0,0 +1,50 @@
+package jobs
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExponentialBackoff(t *testing.T) {
+	backoff := ExponentialBackoff(time.Second, 10*time.Second)
+
+	assert.Equal(t, time.Second, backoff(0))
+	assert.Equal(t, time.Second, backoff(1))
+	assert.Equal(t, 2*time.Second, backoff(2))
+	assert.Equal(t, 8*time.Second, backoff(4))
+	assert.Equal(t, 10*time.Second, backoff(5))
+	assert.Equal(t, 10*time.Second, backoff(100))
+}
+
+func TestConstantBackoff(t *testing.T) {
+	backoff := ConstantBackoff(3 * time.Second)
+
+	assert.Equal(t, 3*time.Second, backoff(1))
+	assert.Equal(t, 3*time.Second, backoff(10))
+}
+
+func TestPermanent(t *testing.T) {
+	base := errors.New("invalid payload")
+	err := fmt.Errorf("handler: %w", Permanent(base))
+
+	assert.True(t, IsPermanent(err))
+	assert.ErrorIs(t, err, base)
+	assert.False(t, IsPermanent(base))
+	assert.NoError(t, Permanent(nil))
+}
+
+func TestJobDecode(t *testing.T) {
+	job := &Job{Payload: []byte(`{"id":"42"}`)}
+
+	var payload struct {
+		ID string `json:"id"`
+	}
+	assert.NoError(t, job.Decode(&payload))
+	assert.Equal(t, "42", payload.ID)
+
+	assert.Error(t, (&Job{}).Decode(&payload))
+}
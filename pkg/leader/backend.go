@@ -0,0 +1,109 @@
+package leader
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	"github.com/rusgainew/tunduck-app/pkg/lock"
+)
+
+// redisBackend лидерство — распределенная блокировка pkg/lock с продлеваемым сроком
+type redisBackend struct {
+	locker *lock.Locker
+}
+
+// NewRedisBackend хранит лидерство в Redis под ключом блокировки leader:<name>
+func NewRedisBackend(locker *lock.Locker) Backend {
+	return &redisBackend{locker: locker}
+}
+
+func (b *redisBackend) Acquire(ctx context.Context, name string, ttl time.Duration) (Lease, error) {
+	l, err := b.locker.Acquire(ctx, "leader:"+name, ttl)
+	if errors.Is(err, lock.ErrNotAcquired) {
+		return nil, ErrHeld
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &redisLease{lock: l}, nil
+}
+
+type redisLease struct {
+	lock *lock.Lock
+}
+
+func (l *redisLease) Renew(ctx context.Context, ttl time.Duration) error {
+	return leaseError(l.lock.Refresh(ctx, ttl))
+}
+
+func (l *redisLease) Release(ctx context.Context) error {
+	return leaseError(l.lock.Release(ctx))
+}
+
+func leaseError(err error) error {
+	if errors.Is(err, lock.ErrLockLost) {
+		return fmt.Errorf("%w: %v", ErrLost, err)
+	}
+	return err
+}
+
+// postgresBackend лидерство — сессионная advisory-блокировка на выделенном подключении.
+// Блокировка живет, пока живо подключение, поэтому ttl не используется: при падении
+// лидера Postgres снимает ее, как только обнаружит разрыв соединения.
+type postgresBackend struct {
+	db *sql.DB
+}
+
+// NewPostgresBackend хранит лидерство в advisory-блокировке Postgres; ключ блокировки — хеш имени выборов
+func NewPostgresBackend(db *sql.DB) Backend {
+	return &postgresBackend{db: db}
+}
+
+func (b *postgresBackend) Acquire(ctx context.Context, name string, _ time.Duration) (Lease, error) {
+	conn, err := b.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key := advisoryKey(name)
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if !acquired {
+		conn.Close()
+		return nil, ErrHeld
+	}
+	return &postgresLease{conn: conn, key: key}, nil
+}
+
+type postgresLease struct {
+	conn *sql.Conn
+	key  int64
+}
+
+// Renew проверяет, что подключение с блокировкой живо; разрыв означает потерю блокировки
+func (l *postgresLease) Renew(ctx context.Context, _ time.Duration) error {
+	if err := l.conn.PingContext(ctx); err != nil {
+		return fmt.Errorf("%w: %v", ErrLost, err)
+	}
+	return nil
+}
+
+func (l *postgresLease) Release(ctx context.Context) error {
+	defer l.conn.Close()
+	_, err := l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.key)
+	return err
+}
+
+// advisoryKey ключ advisory-блокировки для имени выборов
+func advisoryKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("leader:" + name))
+	return int64(h.Sum64())
+}
@@ -0,0 +1,255 @@
+// Package leader выбирает один экземпляр приложения для работы, которая не должна выполняться
+// на нескольких репликах одновременно (планировщик, опрос outbox). Лидерство — блокировка с
+// ограниченным сроком (Redis) или сессионная advisory-блокировка (Postgres); лидер продлевает ее,
+// а остальные экземпляры периодически пытаются захватить ее и становятся лидером после отказа текущего.
+package leader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/pkg/metrics"
+)
+
+// Параметры выборов по умолчанию
+const (
+	DefaultName = "singleton"
+	// DefaultTTL срок лидерства без продления: за это время после отказа лидера его место займет другой экземпляр
+	DefaultTTL = 15 * time.Second
+	// DefaultRetryInterval пауза между попытками стать лидером
+	DefaultRetryInterval = 5 * time.Second
+)
+
+var (
+	// ErrHeld лидером является другой экземпляр
+	ErrHeld = errors.New("leadership held by another instance")
+	// ErrLost лидерство истекло или перехвачено
+	ErrLost = errors.New("leadership lost")
+)
+
+// Backend хранилище блокировки лидерства
+type Backend interface {
+	// Acquire захватывает лидерство name на ttl; ErrHeld — лидер уже есть
+	Acquire(ctx context.Context, name string, ttl time.Duration) (Lease, error)
+}
+
+// Lease захваченное лидерство
+type Lease interface {
+	// Renew продлевает лидерство на ttl; ErrLost — лидерство уже потеряно
+	Renew(ctx context.Context, ttl time.Duration) error
+	// Release отдает лидерство, чтобы другой экземпляр занял его без ожидания ttl
+	Release(ctx context.Context) error
+}
+
+// Config параметры выборов
+type Config struct {
+	Name          string
+	TTL           time.Duration
+	RetryInterval time.Duration
+	Registerer    prometheus.Registerer
+}
+
+// work работа, выполняемая только на лидере
+type work struct {
+	name string
+	run  func(ctx context.Context)
+}
+
+// Elector участвует в выборах лидера и запускает зарегистрированную работу, пока экземпляр лидер.
+// Контекст работы отменяется при потере лидерства; работа должна завершаться после отмены.
+type Elector struct {
+	backend Backend
+	cfg     Config
+	logger  *logrus.Logger
+
+	mu     sync.Mutex
+	works  []work
+	leader bool
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	isLeader  prometheus.Gauge
+	elections prometheus.Counter
+}
+
+// New создает участника выборов; работа добавляется через Add до Start
+func New(backend Backend, cfg Config, logger *logrus.Logger) *Elector {
+	if cfg.Name == "" {
+		cfg.Name = DefaultName
+	}
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultTTL
+	}
+	if cfg.RetryInterval <= 0 {
+		cfg.RetryInterval = DefaultRetryInterval
+	}
+	if cfg.Registerer == nil {
+		cfg.Registerer = prometheus.DefaultRegisterer
+	}
+
+	labels := prometheus.Labels{"election": cfg.Name}
+	return &Elector{
+		backend: backend,
+		cfg:     cfg,
+		logger:  logger,
+		isLeader: metrics.Register(cfg.Registerer, prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "leader_is_leader",
+			Help: "Whether this instance is the elected leader (1) or a follower (0)",
+		}, []string{"election"})).With(labels),
+		elections: metrics.Register(cfg.Registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "leader_elections_total",
+			Help: "Total number of times this instance became the leader",
+		}, []string{"election"})).With(labels),
+	}
+}
+
+// Add регистрирует работу, которая запускается при избрании и останавливается при потере лидерства.
+// run блокируется до отмены контекста.
+func (e *Elector) Add(name string, run func(ctx context.Context)) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.works = append(e.works, work{name: name, run: run})
+}
+
+// IsLeader сообщает, является ли экземпляр лидером
+func (e *Elector) IsLeader() bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.leader
+}
+
+// Start начинает участие в выборах в фоне
+func (e *Elector) Start(context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.done != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	e.cancel = cancel
+	e.done = make(chan struct{})
+	go e.run(ctx)
+	return nil
+}
+
+// Stop останавливает работу лидера и отдает лидерство, ожидая не дольше ctx
+func (e *Elector) Stop(ctx context.Context) error {
+	e.mu.Lock()
+	cancel, done := e.cancel, e.done
+	e.mu.Unlock()
+	if done == nil {
+		return nil
+	}
+
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("leader election %s: %w", e.cfg.Name, ctx.Err())
+	}
+}
+
+func (e *Elector) run(ctx context.Context) {
+	defer close(e.done)
+
+	for {
+		lease, err := e.backend.Acquire(ctx, e.cfg.Name, e.cfg.TTL)
+		switch {
+		case err == nil:
+			e.lead(ctx, lease)
+		case !errors.Is(err, ErrHeld) && ctx.Err() == nil:
+			e.logger.WithError(err).WithField("election", e.cfg.Name).Warn("Leader election attempt failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(e.cfg.RetryInterval):
+		}
+	}
+}
+
+// lead выполняет работу, пока лидерство продлевается, и отдает его при остановке
+func (e *Elector) lead(ctx context.Context, lease Lease) {
+	fields := logrus.Fields{"election": e.cfg.Name}
+	e.logger.WithFields(fields).Info("Became leader, starting singleton work")
+	e.elections.Inc()
+	e.setLeader(true)
+
+	workCtx, stopWork := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	e.mu.Lock()
+	works := append([]work(nil), e.works...)
+	e.mu.Unlock()
+	for _, w := range works {
+		wg.Add(1)
+		go func(w work) {
+			defer wg.Done()
+			w.run(workCtx)
+		}(w)
+	}
+
+	e.keep(ctx, lease, fields)
+
+	// Работа останавливается до того, как лидерство перейдет к другому экземпляру
+	stopWork()
+	wg.Wait()
+	e.setLeader(false)
+
+	releaseCtx, cancel := context.WithTimeout(context.Background(), e.cfg.RetryInterval)
+	defer cancel()
+	if err := lease.Release(releaseCtx); err != nil && !errors.Is(err, ErrLost) {
+		e.logger.WithError(err).WithFields(fields).Warn("Failed to release leadership")
+	}
+}
+
+// keep продлевает лидерство каждую треть TTL и возвращается при остановке или потере лидерства.
+// Временные ошибки хранилища терпимы, пока не истек срок последнего продления.
+func (e *Elector) keep(ctx context.Context, lease Lease, fields logrus.Fields) {
+	ticker := time.NewTicker(e.cfg.TTL / 3)
+	defer ticker.Stop()
+
+	renewed := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			e.logger.WithFields(fields).Info("Stepping down as leader")
+			return
+		case <-ticker.C:
+		}
+
+		err := lease.Renew(ctx, e.cfg.TTL)
+		if err == nil {
+			renewed = time.Now()
+			continue
+		}
+		if ctx.Err() != nil {
+			continue
+		}
+		if errors.Is(err, ErrLost) || time.Since(renewed) >= e.cfg.TTL {
+			e.logger.WithError(err).WithFields(fields).Warn("Leadership lost, stopping singleton work")
+			return
+		}
+		e.logger.WithError(err).WithFields(fields).Warn("Failed to renew leadership, retrying")
+	}
+}
+
+func (e *Elector) setLeader(leader bool) {
+	e.mu.Lock()
+	e.leader = leader
+	e.mu.Unlock()
+
+	if leader {
+		e.isLeader.Set(1)
+	} else {
+		e.isLeader.Set(0)
+	}
+}
@@ -0,0 +1,111 @@
+package leader
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryBackend хранит лидерство в памяти; lost имитирует перехват лидерства
+type memoryBackend struct {
+	mu     sync.Mutex
+	holder *memoryLease
+}
+
+type memoryLease struct {
+	backend *memoryBackend
+	lost    atomic.Bool
+}
+
+func (b *memoryBackend) Acquire(ctx context.Context, name string, ttl time.Duration) (Lease, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.holder != nil && !b.holder.lost.Load() {
+		return nil, ErrHeld
+	}
+	b.holder = &memoryLease{backend: b}
+	return b.holder, nil
+}
+
+func (l *memoryLease) Renew(ctx context.Context, ttl time.Duration) error {
+	if l.lost.Load() {
+		return ErrLost
+	}
+	return nil
+}
+
+func (l *memoryLease) Release(ctx context.Context) error {
+	l.backend.mu.Lock()
+	defer l.backend.mu.Unlock()
+	if l.backend.holder == l {
+		l.backend.holder = nil
+	}
+	return nil
+}
+
+func newTestElector(backend Backend, running *atomic.Int32) *Elector {
+	logger := logrus.New()
+	logger.SetLevel(logrus.PanicLevel)
+	e := New(backend, Config{TTL: 30 * time.Millisecond, RetryInterval: 10 * time.Millisecond, Registerer: prometheus.NewRegistry()}, logger)
+	e.Add("worker", func(ctx context.Context) {
+		running.Add(1)
+		<-ctx.Done()
+		running.Add(-1)
+	})
+	return e
+}
+
+func TestElector_SingleLeaderAndFailover(t *testing.T) {
+	backend := &memoryBackend{}
+	var running atomic.Int32
+	first := newTestElector(backend, &running)
+	second := newTestElector(backend, &running)
+
+	require.NoError(t, first.Start(context.Background()))
+	require.Eventually(t, first.IsLeader, time.Second, 5*time.Millisecond)
+	require.NoError(t, second.Start(context.Background()))
+	defer second.Stop(context.Background())
+
+	// Работа выполняется только на одном экземпляре
+	time.Sleep(50 * time.Millisecond)
+	assert.False(t, second.IsLeader())
+	assert.Equal(t, int32(1), running.Load())
+
+	// Остановленный лидер отдает лидерство, и работа переходит ко второму экземпляру
+	require.NoError(t, first.Stop(context.Background()))
+	assert.False(t, first.IsLeader())
+	require.Eventually(t, second.IsLeader, time.Second, 5*time.Millisecond)
+	require.Eventually(t, func() bool { return running.Load() == 1 }, time.Second, 5*time.Millisecond)
+}
+
+func TestElector_StopsWorkWhenLeadershipLost(t *testing.T) {
+	backend := &memoryBackend{}
+	var running atomic.Int32
+	e := newTestElector(backend, &running)
+
+	require.NoError(t, e.Start(context.Background()))
+	defer e.Stop(context.Background())
+	require.Eventually(t, func() bool { return running.Load() == 1 }, time.Second, 5*time.Millisecond)
+
+	backend.mu.Lock()
+	lease := backend.holder
+	backend.mu.Unlock()
+	lease.lost.Store(true)
+
+	// Потерянное лидерство останавливает работу, затем экземпляр избирается снова
+	require.Eventually(t, func() bool { return running.Load() == 0 }, time.Second, time.Millisecond)
+	require.Eventually(t, func() bool {
+		backend.mu.Lock()
+		defer backend.mu.Unlock()
+		return backend.holder != lease && running.Load() == 1
+	}, time.Second, 5*time.Millisecond)
+	assert.Equal(t, float64(2), testutil.ToFloat64(e.elections))
+}
@@ -0,0 +1,148 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Starter компонент, который запускается после сборки приложения
+type Starter interface {
+	Start(ctx context.Context) error
+}
+
+// Stopper компонент, который освобождает ресурсы при завершении приложения
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
+
+// Hook адаптирует к Starter и Stopper компоненты с другими сигнатурами (Stop() без контекста, Close() и т.п.).
+// Незаданная функция ничего не делает.
+type Hook struct {
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+// Start вызывает OnStart
+func (h Hook) Start(ctx context.Context) error {
+	if h.OnStart == nil {
+		return nil
+	}
+	return h.OnStart(ctx)
+}
+
+// Stop вызывает OnStop
+func (h Hook) Stop(ctx context.Context) error {
+	if h.OnStop == nil {
+		return nil
+	}
+	return h.OnStop(ctx)
+}
+
+// component зарегистрированный компонент
+type component struct {
+	name  string
+	value interface{}
+}
+
+// Manager запускает компоненты в порядке регистрации и останавливает в обратном.
+// Компонент регистрируется после своих зависимостей (БД, затем Redis, затем использующие их
+// сервисы), поэтому при остановке зависимости закрываются последними.
+type Manager struct {
+	mu         sync.Mutex
+	components []component
+	started    int
+	stopped    bool
+	logger     *logrus.Logger
+}
+
+// NewManager создает менеджер жизненного цикла
+func NewManager(log *logrus.Logger) *Manager {
+	return &Manager{logger: log}
+}
+
+// Register добавляет компонент, реализующий Starter и/или Stopper; остальные значения игнорируются
+func (m *Manager) Register(name string, value interface{}) {
+	_, starter := value.(Starter)
+	_, stopper := value.(Stopper)
+	if !starter && !stopper {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.components = append(m.components, component{name: name, value: value})
+}
+
+// Start запускает еще не запущенные компоненты по порядку. При ошибке уже запущенные
+// компоненты останавливаются в обратном порядке.
+func (m *Manager) Start(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for m.started < len(m.components) {
+		c := m.components[m.started]
+		if starter, ok := c.value.(Starter); ok {
+			if err := starter.Start(ctx); err != nil {
+				stopErr := m.stopLocked(ctx, m.started)
+				return errors.Join(fmt.Errorf("start %s: %w", c.name, err), stopErr)
+			}
+		}
+		m.started++
+	}
+	return nil
+}
+
+// Stop останавливает все компоненты в обратном порядке регистрации, в том числе не запущенные
+// (их ресурсы, например соединения, созданы при регистрации). Ошибка одного компонента не
+// прерывает остановку остальных; повторный вызов ничего не делает.
+func (m *Manager) Stop(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.stopped {
+		return nil
+	}
+	m.stopped = true
+	return m.stopLocked(ctx, len(m.components))
+}
+
+// stopLocked останавливает первые n компонентов в обратном порядке; вызывается под m.mu
+func (m *Manager) stopLocked(ctx context.Context, n int) error {
+	var errs []error
+	for i := n - 1; i >= 0; i-- {
+		c := m.components[i]
+		stopper, ok := c.value.(Stopper)
+		if !ok {
+			continue
+		}
+
+		start := time.Now()
+		if err := stopper.Stop(ctx); err != nil {
+			m.logger.WithError(err).WithField("component", c.name).Warn("Failed to stop component")
+			errs = append(errs, fmt.Errorf("stop %s: %w", c.name, err))
+			continue
+		}
+		m.logger.WithFields(logrus.Fields{
+			"component": c.name,
+			"duration":  time.Since(start).String(),
+		}).Info("Component stopped")
+	}
+	return errors.Join(errs...)
+}
+
+// Names возвращает имена компонентов в порядке регистрации
+func (m *Manager) Names() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	names := make([]string, len(m.components))
+	for i, c := range m.components {
+		names[i] = c.name
+	}
+	return names
+}
@@ -0,0 +1,78 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder записывает порядок запуска и остановки компонентов
+type recorder struct {
+	events []string
+}
+
+func (r *recorder) hook(name string, startErr, stopErr error) Hook {
+	return Hook{
+		OnStart: func(context.Context) error {
+			r.events = append(r.events, "start "+name)
+			return startErr
+		},
+		OnStop: func(context.Context) error {
+			r.events = append(r.events, "stop "+name)
+			return stopErr
+		},
+	}
+}
+
+func TestManager_StartStopOrder(t *testing.T) {
+	r := &recorder{}
+	m := NewManager(logrus.New())
+	m.Register("postgres", Hook{OnStop: func(context.Context) error {
+		r.events = append(r.events, "stop postgres")
+		return nil
+	}})
+	m.Register("redis", r.hook("redis", nil, nil))
+	m.Register("jobs", r.hook("jobs", nil, nil))
+	m.Register("not a component", struct{}{})
+
+	assert.Equal(t, []string{"postgres", "redis", "jobs"}, m.Names())
+	require.NoError(t, m.Start(context.Background()))
+	require.NoError(t, m.Stop(context.Background()))
+	assert.Equal(t, []string{"start redis", "start jobs", "stop jobs", "stop redis", "stop postgres"}, r.events)
+
+	// Повторная остановка ничего не делает
+	require.NoError(t, m.Stop(context.Background()))
+	assert.Len(t, r.events, 5)
+}
+
+func TestManager_StartFailureStopsStarted(t *testing.T) {
+	r := &recorder{}
+	m := NewManager(logrus.New())
+	m.Register("redis", r.hook("redis", nil, nil))
+	m.Register("scheduler", r.hook("scheduler", errors.New("boom"), nil))
+	m.Register("jobs", r.hook("jobs", nil, nil))
+
+	err := m.Start(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "start scheduler: boom")
+	assert.Equal(t, []string{"start redis", "start scheduler", "stop redis"}, r.events)
+}
+
+func TestManager_StopJoinsErrors(t *testing.T) {
+	r := &recorder{}
+	m := NewManager(logrus.New())
+	m.Register("postgres", r.hook("postgres", nil, errors.New("db busy")))
+	m.Register("redis", r.hook("redis", nil, nil))
+	m.Register("grpc", r.hook("grpc", nil, errors.New("deadline")))
+
+	err := m.Stop(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "stop grpc: deadline")
+	assert.Contains(t, err.Error(), "stop postgres: db busy")
+	// Ошибка одного компонента не прерывает остановку остальных
+	assert.Equal(t, []string{"stop grpc", "stop redis", "stop postgres"}, r.events)
+}
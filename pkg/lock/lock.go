@@ -0,0 +1,304 @@
+// Package lock предоставляет распределенные блокировки в Redis по алгоритму Redlock: блокировка
+// захвачена, если ее удалось поставить на большинстве независимых узлов Redis за время меньше TTL.
+// С одним узлом это обычная блокировка SET NX PX с токеном владельца.
+//
+// Ключи Redis (prefix по умолчанию "lock"):
+//
+//	<prefix>:<name>  токен владельца блокировки, TTL — срок ее действия
+package lock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// Параметры блокировок по умолчанию
+const (
+	DefaultPrefix = "lock"
+	// DefaultNodeTimeout время на запрос к одному узлу; намного меньше TTL, чтобы недоступный
+	// узел не съедал срок действия блокировки
+	DefaultNodeTimeout = 200 * time.Millisecond
+	// DefaultRetryDelay пауза между попытками захвата при WithWait
+	DefaultRetryDelay = 50 * time.Millisecond
+)
+
+// clockDriftFactor доля TTL, вычитаемая из срока действия на расхождение часов узлов
+const clockDriftFactor = 0.01
+
+var (
+	// ErrNotAcquired блокировка занята другим владельцем
+	ErrNotAcquired = errors.New("lock not acquired")
+	// ErrLockLost блокировка истекла или перехвачена другим владельцем
+	ErrLockLost = errors.New("lock lost")
+)
+
+// Config параметры Locker
+type Config struct {
+	Prefix      string
+	NodeTimeout time.Duration
+	RetryDelay  time.Duration
+}
+
+// Locker захватывает блокировки на одном или нескольких независимых узлах Redis
+type Locker struct {
+	nodes  []*redis.Client
+	cfg    Config
+	quorum int
+}
+
+// New создает Locker. Для Redlock узлы должны быть независимыми мастерами, а не репликами одного.
+func New(cfg Config, nodes ...*redis.Client) *Locker {
+	if cfg.Prefix == "" {
+		cfg.Prefix = DefaultPrefix
+	}
+	if cfg.NodeTimeout <= 0 {
+		cfg.NodeTimeout = DefaultNodeTimeout
+	}
+	if cfg.RetryDelay <= 0 {
+		cfg.RetryDelay = DefaultRetryDelay
+	}
+	return &Locker{nodes: nodes, cfg: cfg, quorum: len(nodes)/2 + 1}
+}
+
+// acquireOptions параметры одного захвата
+type acquireOptions struct {
+	wait time.Duration
+}
+
+// Option настраивает Acquire
+type Option func(*acquireOptions)
+
+// WithWait повторяет захват занятой блокировки до истечения wait
+func WithWait(wait time.Duration) Option {
+	return func(o *acquireOptions) { o.wait = wait }
+}
+
+// Acquire захватывает блокировку name на ttl. Занятая блокировка возвращает ErrNotAcquired
+// сразу или, с WithWait, после безуспешных попыток.
+func (l *Locker) Acquire(ctx context.Context, name string, ttl time.Duration, opts ...Option) (*Lock, error) {
+	if len(l.nodes) == 0 {
+		return nil, errors.New("lock: no Redis nodes configured")
+	}
+	var o acquireOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	deadline := time.Now().Add(o.wait)
+	for {
+		lock, err := l.tryAcquire(ctx, name, ttl)
+		if err == nil || !errors.Is(err, ErrNotAcquired) || !time.Now().Before(deadline) {
+			return lock, err
+		}
+
+		// Случайная пауза, чтобы конкуренты не повторяли попытки одновременно
+		delay := l.cfg.RetryDelay/2 + rand.N(l.cfg.RetryDelay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Do выполняет fn под блокировкой name, продлевая ее, пока fn работает. Контекст fn отменяется,
+// если блокировку потеряли; тогда Do возвращает ErrLockLost.
+func (l *Locker) Do(ctx context.Context, name string, ttl time.Duration, fn func(ctx context.Context) error, opts ...Option) error {
+	lock, err := l.Acquire(ctx, name, ttl, opts...)
+	if err != nil {
+		return err
+	}
+
+	runCtx, stop := lock.KeepAlive(ctx)
+	err = fn(runCtx)
+	lost := stop()
+
+	releaseErr := lock.Release(context.WithoutCancel(ctx))
+	if err != nil {
+		return err
+	}
+	if lost {
+		return fmt.Errorf("%s: %w", name, ErrLockLost)
+	}
+	if releaseErr != nil && !errors.Is(releaseErr, ErrLockLost) {
+		return releaseErr
+	}
+	return nil
+}
+
+func (l *Locker) tryAcquire(ctx context.Context, name string, ttl time.Duration) (*Lock, error) {
+	lock := &Lock{locker: l, name: name, key: l.cfg.Prefix + ":" + name, token: uuid.NewString(), ttl: ttl}
+
+	start := time.Now()
+	acquired, err := l.onNodes(ctx, func(nodeCtx context.Context, node *redis.Client) (bool, error) {
+		return node.SetNX(nodeCtx, lock.key, lock.token, ttl).Result()
+	})
+
+	validity := ttl - time.Since(start) - time.Duration(float64(ttl)*clockDriftFactor) - 2*time.Millisecond
+	if acquired >= l.quorum && validity > 0 {
+		lock.validUntil = start.Add(validity)
+		return lock, nil
+	}
+
+	// Снимаем частично поставленную блокировку, чтобы не ждать ее истечения
+	_ = lock.Release(context.WithoutCancel(ctx))
+	if acquired == 0 && err != nil {
+		return nil, fmt.Errorf("lock %s: %w", name, err)
+	}
+	return nil, fmt.Errorf("lock %s: %w", name, ErrNotAcquired)
+}
+
+// onNodes выполняет op на всех узлах параллельно и возвращает число успешных узлов и последнюю ошибку
+func (l *Locker) onNodes(ctx context.Context, op func(ctx context.Context, node *redis.Client) (bool, error)) (int, error) {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		ok      int
+		lastErr error
+	)
+	for _, node := range l.nodes {
+		wg.Add(1)
+		go func(node *redis.Client) {
+			defer wg.Done()
+			nodeCtx, cancel := context.WithTimeout(ctx, l.cfg.NodeTimeout)
+			defer cancel()
+
+			done, err := op(nodeCtx, node)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				lastErr = err
+			} else if done {
+				ok++
+			}
+		}(node)
+	}
+	wg.Wait()
+	return ok, lastErr
+}
+
+// refreshScript продлевает блокировку, только если она принадлежит владельцу токена
+var refreshScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// releaseScript снимает блокировку, только если она принадлежит владельцу токена
+var releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// Lock захваченная блокировка
+type Lock struct {
+	locker     *Locker
+	name       string
+	key        string
+	token      string
+	ttl        time.Duration
+	mu         sync.Mutex
+	validUntil time.Time
+}
+
+// Name имя блокировки
+func (lk *Lock) Name() string { return lk.name }
+
+// ValidUntil момент, до которого блокировка гарантированно принадлежит владельцу
+func (lk *Lock) ValidUntil() time.Time {
+	lk.mu.Lock()
+	defer lk.mu.Unlock()
+	return lk.validUntil
+}
+
+// Refresh продлевает блокировку на ttl. ErrLockLost — блокировка уже истекла или перехвачена.
+func (lk *Lock) Refresh(ctx context.Context, ttl time.Duration) error {
+	start := time.Now()
+	refreshed, err := lk.locker.onNodes(ctx, func(nodeCtx context.Context, node *redis.Client) (bool, error) {
+		res, err := refreshScript.Run(nodeCtx, node, []string{lk.key}, lk.token, ttl.Milliseconds()).Int()
+		return res == 1, err
+	})
+
+	validity := ttl - time.Since(start) - time.Duration(float64(ttl)*clockDriftFactor) - 2*time.Millisecond
+	if refreshed >= lk.locker.quorum && validity > 0 {
+		lk.mu.Lock()
+		lk.ttl = ttl
+		lk.validUntil = start.Add(validity)
+		lk.mu.Unlock()
+		return nil
+	}
+	if refreshed == 0 && err != nil {
+		return fmt.Errorf("refresh lock %s: %w", lk.name, err)
+	}
+	return fmt.Errorf("refresh lock %s: %w", lk.name, ErrLockLost)
+}
+
+// Release снимает блокировку на всех узлах. ErrLockLost — ни на одном узле она уже не принадлежала владельцу.
+func (lk *Lock) Release(ctx context.Context) error {
+	released, err := lk.locker.onNodes(ctx, func(nodeCtx context.Context, node *redis.Client) (bool, error) {
+		res, err := releaseScript.Run(nodeCtx, node, []string{lk.key}, lk.token).Int()
+		return res == 1, err
+	})
+	if released > 0 {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("release lock %s: %w", lk.name, err)
+	}
+	return fmt.Errorf("release lock %s: %w", lk.name, ErrLockLost)
+}
+
+// KeepAlive продлевает блокировку на ее TTL каждую треть TTL, пока не вызван stop. Возвращенный
+// контекст отменяется, если продлить блокировку не удалось до истечения ее срока; stop сообщает,
+// была ли блокировка потеряна.
+func (lk *Lock) KeepAlive(ctx context.Context) (context.Context, func() (lost bool)) {
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	var lost bool
+
+	go func() {
+		defer close(done)
+		lk.mu.Lock()
+		ttl := lk.ttl
+		lk.mu.Unlock()
+
+		ticker := time.NewTicker(ttl / 3)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-runCtx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			err := lk.Refresh(runCtx, ttl)
+			if err == nil {
+				continue
+			}
+			// Временная ошибка узлов терпима, пока не истек срок действия блокировки
+			if errors.Is(err, ErrLockLost) || !time.Now().Before(lk.ValidUntil()) {
+				if runCtx.Err() == nil {
+					lost = true
+					cancel()
+				}
+				return
+			}
+		}
+	}()
+
+	return runCtx, func() bool {
+		cancel()
+		<-done
+		return lost
+	}
+}
@@ -0,0 +1,108 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Note: тесты с Redis требуют Redis на localhost:6379
+// Run with: go test -v ./pkg/lock/
+
+func redisClient(t *testing.T) *redis.Client {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		t.Skip("Redis not running, skipping tests")
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func unreachableClient(t *testing.T) *redis.Client {
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestLocker_UnreachableRedis(t *testing.T) {
+	locker := New(Config{}, unreachableClient(t))
+
+	_, err := locker.Acquire(context.Background(), "orders", time.Second)
+	require.Error(t, err)
+	// Недоступный Redis не выдается за занятую блокировку
+	assert.False(t, errors.Is(err, ErrNotAcquired))
+
+	_, err = New(Config{}).Acquire(context.Background(), "orders", time.Second)
+	assert.Error(t, err)
+}
+
+func TestLocker_ExclusiveAcquireAndRelease(t *testing.T) {
+	locker := New(Config{Prefix: "lock-test-" + uuid.NewString()[:8]}, redisClient(t))
+	ctx := context.Background()
+
+	first, err := locker.Acquire(ctx, "provision", time.Second)
+	require.NoError(t, err)
+	assert.True(t, first.ValidUntil().After(time.Now()))
+
+	_, err = locker.Acquire(ctx, "provision", time.Second)
+	assert.ErrorIs(t, err, ErrNotAcquired)
+
+	// Ожидание дожидается освобождения блокировки
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_ = first.Release(ctx)
+	}()
+	second, err := locker.Acquire(ctx, "provision", time.Second, WithWait(time.Second))
+	require.NoError(t, err)
+
+	// Снять чужую блокировку нельзя
+	assert.ErrorIs(t, first.Release(ctx), ErrLockLost)
+	assert.ErrorIs(t, first.Refresh(ctx, time.Second), ErrLockLost)
+	require.NoError(t, second.Refresh(ctx, 2*time.Second))
+	require.NoError(t, second.Release(ctx))
+}
+
+func TestLocker_DoKeepsLockAlive(t *testing.T) {
+	client := redisClient(t)
+	locker := New(Config{Prefix: "lock-test-" + uuid.NewString()[:8]}, client)
+	ctx := context.Background()
+
+	// Работа дольше TTL продолжает держать блокировку
+	err := locker.Do(ctx, "warmup", 300*time.Millisecond, func(ctx context.Context) error {
+		time.Sleep(700 * time.Millisecond)
+		_, err := locker.Acquire(ctx, "warmup", time.Second)
+		assert.ErrorIs(t, err, ErrNotAcquired)
+		return nil
+	})
+	require.NoError(t, err)
+
+	// После Do блокировка свободна
+	lock, err := locker.Acquire(ctx, "warmup", time.Second)
+	require.NoError(t, err)
+	require.NoError(t, lock.Release(ctx))
+}
+
+func TestLocker_DoCancelsWhenLockLost(t *testing.T) {
+	client := redisClient(t)
+	prefix := "lock-test-" + uuid.NewString()[:8]
+	locker := New(Config{Prefix: prefix}, client)
+
+	err := locker.Do(context.Background(), "warmup", 300*time.Millisecond, func(ctx context.Context) error {
+		// Блокировку перехватил другой владелец
+		require.NoError(t, client.Set(context.Background(), prefix+":warmup", "other", time.Second).Err())
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(2 * time.Second):
+			return errors.New("context not cancelled")
+		}
+	})
+	assert.ErrorIs(t, err, ErrLockLost)
+	client.Del(context.Background(), prefix+":warmup")
+}
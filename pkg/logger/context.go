@@ -24,9 +24,17 @@ func WithContext(ctx context.Context, key ContextKey, value interface{}) context
 	return context.WithValue(ctx, key, value)
 }
 
-// FromContext извлекает значение из контекста
+// FromContext извлекает значение из контекста. Значение ищется по ключу key и по строке string(key),
+// поэтому в контексте запроса Fiber (c.Context()) находятся и значения c.Locals("request_id", ...).
 func FromContext(ctx context.Context, key ContextKey) (interface{}, bool) {
-	return ctx.Value(key), ctx.Value(key) != nil
+	if ctx == nil {
+		return nil, false
+	}
+	if value := ctx.Value(key); value != nil {
+		return value, true
+	}
+	value := ctx.Value(string(key))
+	return value, value != nil
 }
 
 // Logger обертка над logrus.Logger с дополнительным функционалом
@@ -136,27 +144,24 @@ func (l *Logger) Fatal(ctx context.Context, message string, err error, fields ..
 	entry.Fatal(message)
 }
 
-// addContextFields добавляет поля из контекста в entry
-func (l *Logger) addContextFields(entry *logrus.Entry, ctx context.Context) *logrus.Entry {
-	fields := logrus.Fields{}
-
-	if requestID, ok := FromContext(ctx, RequestIDKey); ok {
-		fields["request_id"] = requestID
-	}
-
-	if userID, ok := FromContext(ctx, UserIDKey); ok {
-		fields["user_id"] = userID
-	}
+// contextKeys ключи контекста, автоматически добавляемые в каждую запись
+var contextKeys = []ContextKey{RequestIDKey, UserIDKey, OrganizationID, TraceIDKey}
 
-	if orgID, ok := FromContext(ctx, OrganizationID); ok {
-		fields["org_id"] = orgID
-	}
+// addContextFields добавляет поля из контекста в entry: ID запроса (RequestIDMiddleware),
+// пользователя (JWT middleware) и организации, если они известны
+func (l *Logger) addContextFields(entry *logrus.Entry, ctx context.Context) *logrus.Entry {
+	return entry.WithFields(contextFields(ctx))
+}
 
-	if traceID, ok := FromContext(ctx, TraceIDKey); ok {
-		fields["trace_id"] = traceID
+// contextFields возвращает известные значения contextKeys из контекста
+func contextFields(ctx context.Context) logrus.Fields {
+	fields := logrus.Fields{}
+	for _, key := range contextKeys {
+		if value, ok := FromContext(ctx, key); ok {
+			fields[string(key)] = value
+		}
 	}
-
-	return entry.WithFields(fields)
+	return fields
 }
 
 // getStackTrace получает stack trace для логирования
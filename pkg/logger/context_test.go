@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger_ContextFields(t *testing.T) {
+	var buf bytes.Buffer
+	base := logrus.New()
+	base.SetOutput(&buf)
+	base.SetFormatter(&logrus.JSONFormatter{})
+	log := New(base)
+
+	// Значения middleware хранятся под строковыми ключами (c.Locals), значения сервисов — под ContextKey
+	ctx := context.WithValue(context.Background(), "request_id", "req-1")
+	ctx = WithContext(ctx, UserIDKey, "user-1")
+	ctx = context.WithValue(ctx, string(OrganizationID), "org-1")
+
+	log.Info(ctx, "Document sent")
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "req-1", entry["request_id"])
+	assert.Equal(t, "user-1", entry["user_id"])
+	assert.Equal(t, "org-1", entry["org_id"])
+	assert.NotContains(t, entry, "trace_id")
+}
+
+func TestFromContext_Nil(t *testing.T) {
+	_, ok := FromContext(nil, RequestIDKey)
+	assert.False(t, ok)
+}
@@ -0,0 +1,78 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// DefaultSlowQueryThreshold запрос дольше этого времени пишется как предупреждение
+const DefaultSlowQueryThreshold = 200 * time.Millisecond
+
+// GormLogger пишет запросы GORM в logrus: ошибки — error, медленные запросы — warn, остальные — debug.
+// Уровень берется из логгера, поэтому меняется во время работы (подсистема ModuleGorm).
+// Каждая строка содержит ID запроса и пользователя из контекста (db.WithContext), чтобы медленный
+// запрос можно было связать с вызовом API.
+type GormLogger struct {
+	log  *logrus.Logger
+	slow time.Duration
+}
+
+// NewGormLogger создает логгер GORM; slow <= 0 отключает предупреждения о медленных запросах
+func NewGormLogger(log *logrus.Logger, slow time.Duration) *GormLogger {
+	return &GormLogger{log: log, slow: slow}
+}
+
+// LogMode не меняет уровень: он задается логгером подсистемы
+func (g *GormLogger) LogMode(gormlogger.LogLevel) gormlogger.Interface {
+	return g
+}
+
+// Info пишет информационное сообщение GORM
+func (g *GormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	g.log.WithFields(contextFields(ctx)).Infof(msg, data...)
+}
+
+// Warn пишет предупреждение GORM
+func (g *GormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	g.log.WithFields(contextFields(ctx)).Warnf(msg, data...)
+}
+
+// Error пишет ошибку GORM
+func (g *GormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	g.log.WithFields(contextFields(ctx)).Errorf(msg, data...)
+}
+
+// Trace пишет выполненный запрос. SQL формируется только если запись пройдет по уровню.
+func (g *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+
+	var level logrus.Level
+	var message string
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		level, message = logrus.ErrorLevel, "Database query failed"
+	case g.slow > 0 && elapsed > g.slow:
+		level, message = logrus.WarnLevel, "Slow database query"
+	default:
+		level, message = logrus.DebugLevel, "Database query"
+	}
+	if !g.log.IsLevelEnabled(level) {
+		return
+	}
+
+	sql, rows := fc()
+	entry := g.log.WithFields(contextFields(ctx)).WithFields(logrus.Fields{
+		"sql":         sql,
+		"rows":        rows,
+		"duration_ms": elapsed.Milliseconds(),
+	})
+	if err != nil {
+		entry = entry.WithError(err)
+	}
+	entry.Log(level, message)
+}
@@ -0,0 +1,33 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGormLogger_TraceIncludesRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	base := logrus.New()
+	base.SetOutput(&buf)
+	base.SetFormatter(&logrus.JSONFormatter{})
+	log := NewGormLogger(base, 100*time.Millisecond)
+
+	// Контекст запроса Fiber хранит ID запроса под строковым ключом (c.Locals)
+	ctx := context.WithValue(context.Background(), "request_id", "req-1")
+	log.Trace(ctx, time.Now().Add(-time.Second), func() (string, int64) { return "SELECT 1", 1 }, nil)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "Slow database query", entry["msg"])
+	assert.Equal(t, "req-1", entry["request_id"])
+	assert.Equal(t, "SELECT 1", entry["sql"])
+	assert.GreaterOrEqual(t, entry["duration_ms"], float64(1000))
+	assert.NotContains(t, entry, "user_id")
+}
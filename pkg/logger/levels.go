@@ -0,0 +1,297 @@
+package logger
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Подсистемы с отдельным уровнем логирования
+const (
+	ModuleHTTP    = "http"
+	ModuleGorm    = "gorm"
+	ModuleGateway = "gateway"
+)
+
+// RootModule имя общего уровня в Levels.Set и Levels.Snapshot
+const RootModule = "root"
+
+// Значения выборки отладочных записей по умолчанию
+const (
+	DefaultSamplingInitial    = 100
+	DefaultSamplingThereafter = 100
+)
+
+// Config уровни логирования и выборка отладочных записей
+type Config struct {
+	// Level общий уровень и уровень подсистем, для которых не задан свой
+	Level logrus.Level
+	// Modules уровни подсистем (ModuleGorm, ModuleGateway, ModuleHTTP и т.п.)
+	Modules map[string]logrus.Level
+	// SamplingInitial записей debug/trace с одним сообщением в секунду, которые пишутся все; 0 выключает выборку
+	SamplingInitial int
+	// SamplingThereafter после SamplingInitial пишется каждая SamplingThereafter-я запись; 0 — ни одной
+	SamplingThereafter int
+}
+
+// Levels уровни логирования подсистем, изменяемые во время работы. Логгер подсистемы пишет
+// туда же и в том же формате, что и общий логгер, и добавляет поле module.
+type Levels struct {
+	mu       sync.Mutex
+	root     *logrus.Logger
+	format   logrus.Formatter
+	sampler  *sampler
+	modules  map[string]*logrus.Logger
+	explicit map[string]bool
+	levels   map[string]logrus.Level
+	hooks    []logrus.Hook
+}
+
+// ModuleLevel уровень подсистемы
+type ModuleLevel struct {
+	Module string `json:"module"`
+	Level  string `json:"level"`
+	// Inherited уровень не задан явно и совпадает с общим
+	Inherited bool `json:"inherited"`
+}
+
+// NewLevels применяет cfg к общему логгеру root и создает реестр уровней подсистем.
+// Вызывается после настройки вывода и формата root.
+func NewLevels(root *logrus.Logger, cfg Config) *Levels {
+	l := &Levels{
+		root:     root,
+		format:   root.Formatter,
+		modules:  map[string]*logrus.Logger{},
+		explicit: map[string]bool{},
+		levels:   map[string]logrus.Level{},
+	}
+	if cfg.SamplingInitial > 0 {
+		l.sampler = newSampler(cfg.SamplingInitial, cfg.SamplingThereafter)
+	}
+	root.SetLevel(cfg.Level)
+	root.SetFormatter(&moduleFormatter{next: l.format, sampler: l.sampler})
+	for module, level := range cfg.Modules {
+		l.explicit[module] = true
+		l.levels[module] = level
+	}
+	return l
+}
+
+// Module возвращает логгер подсистемы name (один на имя)
+func (l *Levels) Module(name string) *logrus.Logger {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if log, ok := l.modules[name]; ok {
+		return log
+	}
+
+	level, ok := l.levels[name]
+	if !ok {
+		level = l.root.GetLevel()
+	}
+	hooks := make(logrus.LevelHooks)
+	for _, hook := range l.hooks {
+		hooks.Add(&moduleHook{next: hook, module: name})
+	}
+	log := &logrus.Logger{
+		Out:          l.root.Out,
+		Hooks:        hooks,
+		Formatter:    &moduleFormatter{next: l.format, module: name, sampler: l.sampler},
+		ReportCaller: l.root.ReportCaller,
+		Level:        level,
+		ExitFunc:     l.root.ExitFunc,
+	}
+	l.modules[name] = log
+	return log
+}
+
+// AddHook подключает hook к общему логгеру и логгерам подсистем (в том числе созданным позже);
+// записи подсистем приходят в hook с полем module
+func (l *Levels) AddHook(hook logrus.Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.hooks = append(l.hooks, hook)
+	l.root.AddHook(hook)
+	for name, log := range l.modules {
+		log.AddHook(&moduleHook{next: hook, module: name})
+	}
+}
+
+// Set задает уровень подсистемы module; RootModule меняет общий уровень и уровни подсистем,
+// для которых свой не задан
+func (l *Levels) Set(module string, level logrus.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if module == RootModule {
+		l.root.SetLevel(level)
+		for name, log := range l.modules {
+			if !l.explicit[name] {
+				log.SetLevel(level)
+			}
+		}
+		return
+	}
+
+	l.explicit[module] = true
+	l.levels[module] = level
+	if log, ok := l.modules[module]; ok {
+		log.SetLevel(level)
+	}
+}
+
+// Reset возвращает подсистеме module общий уровень
+func (l *Levels) Reset(module string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.explicit, module)
+	delete(l.levels, module)
+	if log, ok := l.modules[module]; ok {
+		log.SetLevel(l.root.GetLevel())
+	}
+}
+
+// Snapshot возвращает общий уровень и уровни подсистем, отсортированные по имени
+func (l *Levels) Snapshot() []ModuleLevel {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	root := l.root.GetLevel()
+	names := map[string]bool{}
+	for name := range l.modules {
+		names[name] = true
+	}
+	for name := range l.levels {
+		names[name] = true
+	}
+
+	result := []ModuleLevel{{Module: RootModule, Level: root.String()}}
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, name)
+	}
+	sort.Strings(sorted)
+	for _, name := range sorted {
+		level, ok := l.levels[name]
+		if !ok {
+			level = root
+		}
+		result = append(result, ModuleLevel{Module: name, Level: level.String(), Inherited: !l.explicit[name]})
+	}
+	return result
+}
+
+// Dropped количество отладочных записей, отброшенных выборкой
+func (l *Levels) Dropped() uint64 {
+	if l.sampler == nil {
+		return 0
+	}
+	return l.sampler.dropped.Load()
+}
+
+// ParseModuleLevels разбирает уровни подсистем в формате "gorm=warn,gateway=debug,http=info"
+func ParseModuleLevels(raw string) (map[string]logrus.Level, error) {
+	levels := map[string]logrus.Level{}
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		module, rawLevel, ok := strings.Cut(part, "=")
+		module = strings.TrimSpace(module)
+		if !ok || module == "" {
+			return nil, fmt.Errorf("invalid module level %q, expected module=level", part)
+		}
+		level, err := logrus.ParseLevel(strings.TrimSpace(rawLevel))
+		if err != nil {
+			return nil, fmt.Errorf("invalid level for module %s: %w", module, err)
+		}
+		levels[module] = level
+	}
+	return levels, nil
+}
+
+// moduleFormatter добавляет поле module и отбрасывает отладочные записи сверх выборки
+// (пустой результат форматирования logrus не пишет)
+type moduleFormatter struct {
+	next    logrus.Formatter
+	module  string
+	sampler *sampler
+}
+
+func (f *moduleFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	if f.sampler != nil && !f.sampler.keep(entry) {
+		return nil, nil
+	}
+	if f.module != "" {
+		entry.Data["module"] = f.module
+	}
+	return f.next.Format(entry)
+}
+
+// moduleHook добавляет поле module до вызова hook: форматтер подсистемы срабатывает позже hooks
+type moduleHook struct {
+	next   logrus.Hook
+	module string
+}
+
+func (h *moduleHook) Levels() []logrus.Level {
+	return h.next.Levels()
+}
+
+func (h *moduleHook) Fire(entry *logrus.Entry) error {
+	entry.Data["module"] = h.module
+	return h.next.Fire(entry)
+}
+
+// sampler пропускает первые initial записей debug/trace с одинаковым сообщением в секунду,
+// затем каждую thereafter-ю. Записи info и выше пишутся всегда.
+type sampler struct {
+	initial    int
+	thereafter int
+
+	mu      sync.Mutex
+	second  int64
+	counts  map[string]int
+	now     func() time.Time
+	dropped atomic.Uint64
+}
+
+func newSampler(initial, thereafter int) *sampler {
+	return &sampler{
+		initial:    initial,
+		thereafter: thereafter,
+		counts:     map[string]int{},
+		now:        time.Now,
+	}
+}
+
+func (s *sampler) keep(entry *logrus.Entry) bool {
+	if entry.Level <= logrus.InfoLevel {
+		return true
+	}
+
+	s.mu.Lock()
+	second := s.now().Unix()
+	if second != s.second {
+		s.second = second
+		s.counts = map[string]int{}
+	}
+	s.counts[entry.Message]++
+	n := s.counts[entry.Message]
+	s.mu.Unlock()
+
+	if n <= s.initial || s.thereafter > 0 && (n-s.initial)%s.thereafter == 0 {
+		return true
+	}
+	s.dropped.Add(1)
+	return false
+}
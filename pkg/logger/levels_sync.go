@@ -0,0 +1,169 @@
+package logger
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+)
+
+// Параметры общих уровней логирования по умолчанию
+const (
+	DefaultLevelSyncKey = "log_levels"
+	// DefaultLevelOverrideTTL сколько изменения уровней хранятся в Redis после последнего изменения:
+	// включенный для расследования debug не остается навсегда у новых инстансов
+	DefaultLevelOverrideTTL = 24 * time.Hour
+	// DefaultLevelRefreshInterval период перечитывания уровней на случай пропущенного уведомления
+	DefaultLevelRefreshInterval = 30 * time.Second
+)
+
+// levelInherit значение в Redis для подсистемы, которой возвращен общий уровень
+const levelInherit = "inherit"
+
+// LevelSyncConfig параметры общих уровней логирования
+type LevelSyncConfig struct {
+	// Key ключ хеша изменений в Redis; изменения публикуются в канал "<key>:updated"
+	Key             string
+	OverrideTTL     time.Duration
+	RefreshInterval time.Duration
+}
+
+// LevelSync распространяет изменения уровней Levels на все инстансы: изменение сохраняется в
+// хеше Redis и применяется каждым инстансом по уведомлению, а новые инстансы загружают его при
+// запуске. Без Redis изменение действует только на этом инстансе.
+type LevelSync struct {
+	client *redis.Client
+	levels *Levels
+	cfg    LevelSyncConfig
+	logger *Logger
+}
+
+// NewLevelSync создает общие уровни логирования поверх levels; client может быть nil
+func NewLevelSync(client *redis.Client, levels *Levels, cfg LevelSyncConfig, log *logrus.Logger) *LevelSync {
+	if cfg.Key == "" {
+		cfg.Key = DefaultLevelSyncKey
+	}
+	if cfg.OverrideTTL <= 0 {
+		cfg.OverrideTTL = DefaultLevelOverrideTTL
+	}
+	if cfg.RefreshInterval <= 0 {
+		cfg.RefreshInterval = DefaultLevelRefreshInterval
+	}
+	return &LevelSync{client: client, levels: levels, cfg: cfg, logger: New(log)}
+}
+
+// Levels уровни этого инстанса
+func (s *LevelSync) Levels() *Levels {
+	return s.levels
+}
+
+// Set задает уровень подсистемы на всех инстансах (см. Levels.Set)
+func (s *LevelSync) Set(ctx context.Context, module string, level logrus.Level) error {
+	if err := s.store(ctx, module, level.String()); err != nil {
+		return err
+	}
+	s.levels.Set(module, level)
+	return nil
+}
+
+// Reset возвращает подсистеме общий уровень на всех инстансах (см. Levels.Reset)
+func (s *LevelSync) Reset(ctx context.Context, module string) error {
+	if err := s.store(ctx, module, levelInherit); err != nil {
+		return err
+	}
+	s.levels.Reset(module)
+	return nil
+}
+
+// store сохраняет изменение в Redis и уведомляет остальные инстансы
+func (s *LevelSync) store(ctx context.Context, module, value string) error {
+	if s.client == nil {
+		return nil
+	}
+	pipe := s.client.TxPipeline()
+	pipe.HSet(ctx, s.cfg.Key, module, value)
+	pipe.Expire(ctx, s.cfg.Key, s.cfg.OverrideTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+	if err := s.client.Publish(ctx, s.channel(), module).Err(); err != nil {
+		// Остальные инстансы применят изменение при следующем перечитывании
+		s.logger.Warn(ctx, "Failed to publish log level update", logrus.Fields{"error": err.Error()})
+	}
+	return nil
+}
+
+// Load применяет изменения уровней, сохраненные в Redis. Некорректные записи пропускаются.
+func (s *LevelSync) Load(ctx context.Context) error {
+	if s.client == nil {
+		return nil
+	}
+	raw, err := s.client.HGetAll(ctx, s.cfg.Key).Result()
+	if err != nil {
+		return err
+	}
+
+	// Общий уровень применяется первым: он задает уровень подсистем без своего
+	modules := make([]string, 0, len(raw))
+	for module := range raw {
+		modules = append(modules, module)
+	}
+	sort.Slice(modules, func(i, j int) bool {
+		return modules[i] == RootModule || (modules[j] != RootModule && modules[i] < modules[j])
+	})
+
+	for _, module := range modules {
+		value := raw[module]
+		if value == levelInherit {
+			if module != RootModule {
+				s.levels.Reset(module)
+			}
+			continue
+		}
+		level, err := logrus.ParseLevel(value)
+		if err != nil {
+			s.logger.Warn(ctx, "Invalid log level in Redis", logrus.Fields{"module": module, "level": value})
+			continue
+		}
+		s.levels.Set(module, level)
+	}
+	return nil
+}
+
+// Run применяет изменения других инстансов по уведомлениям и каждые RefreshInterval до отмены ctx
+func (s *LevelSync) Run(ctx context.Context) {
+	if s.client == nil {
+		return
+	}
+	if err := s.Load(ctx); err != nil {
+		s.logger.Warn(ctx, "Failed to load log levels", logrus.Fields{"error": err.Error()})
+	}
+
+	pubsub := s.client.Subscribe(ctx, s.channel())
+	defer pubsub.Close()
+	updates := pubsub.Channel()
+
+	ticker := time.NewTicker(s.cfg.RefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-updates:
+			if !ok {
+				return
+			}
+		case <-ticker.C:
+		}
+		if err := s.Load(ctx); err != nil {
+			s.logger.Warn(ctx, "Failed to reload log levels", logrus.Fields{"error": err.Error()})
+		}
+	}
+}
+
+func (s *LevelSync) channel() string {
+	return s.cfg.Key + ":updated"
+}
@@ -0,0 +1,51 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevelSync_WithoutRedisAppliesLocally(t *testing.T) {
+	var buf bytes.Buffer
+	levels := NewLevels(newTestRoot(&buf), Config{Level: logrus.InfoLevel})
+	sync := NewLevelSync(nil, levels, LevelSyncConfig{}, logrus.New())
+
+	require.NoError(t, sync.Set(context.Background(), ModuleGorm, logrus.DebugLevel))
+	assert.Equal(t, logrus.DebugLevel, levels.Module(ModuleGorm).GetLevel())
+	require.NoError(t, sync.Reset(context.Background(), ModuleGorm))
+	assert.Equal(t, logrus.InfoLevel, levels.Module(ModuleGorm).GetLevel())
+}
+
+func TestLevelSync_SharedBetweenInstances(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		t.Skip("Redis not running, skipping tests")
+	}
+	defer client.Close()
+
+	cfg := LevelSyncConfig{Key: "log-levels-test-" + uuid.NewString()[:8]}
+	defer client.Del(context.Background(), cfg.Key)
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	first := NewLevelSync(client, NewLevels(newTestRoot(&buf), Config{Level: logrus.InfoLevel}), cfg, logrus.New())
+	second := NewLevelSync(client, NewLevels(newTestRoot(&buf), Config{Level: logrus.InfoLevel}), cfg, logrus.New())
+
+	require.NoError(t, first.Set(ctx, RootModule, logrus.WarnLevel))
+	require.NoError(t, first.Set(ctx, ModuleGorm, logrus.DebugLevel))
+	require.NoError(t, first.Set(ctx, ModuleHTTP, logrus.ErrorLevel))
+	require.NoError(t, first.Reset(ctx, ModuleHTTP))
+
+	// Второй инстанс получает изменения первого
+	require.NoError(t, second.Load(ctx))
+	assert.Equal(t, logrus.DebugLevel, second.Levels().Module(ModuleGorm).GetLevel())
+	assert.Equal(t, logrus.WarnLevel, second.Levels().Module(ModuleHTTP).GetLevel())
+	assert.Equal(t, logrus.WarnLevel, second.Levels().Module(ModuleGateway).GetLevel())
+}
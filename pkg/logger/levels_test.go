@@ -0,0 +1,136 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRoot(buf *bytes.Buffer) *logrus.Logger {
+	root := logrus.New()
+	root.SetOutput(buf)
+	root.SetFormatter(&logrus.JSONFormatter{})
+	return root
+}
+
+func TestLevels_ModuleLevels(t *testing.T) {
+	var buf bytes.Buffer
+	levels := NewLevels(newTestRoot(&buf), Config{
+		Level:   logrus.InfoLevel,
+		Modules: map[string]logrus.Level{ModuleGorm: logrus.WarnLevel},
+	})
+
+	gorm := levels.Module(ModuleGorm)
+	http := levels.Module(ModuleHTTP)
+	assert.Same(t, gorm, levels.Module(ModuleGorm))
+	assert.Equal(t, logrus.WarnLevel, gorm.GetLevel())
+	assert.Equal(t, logrus.InfoLevel, http.GetLevel())
+
+	// Общий уровень меняет только подсистемы без своего уровня
+	levels.Set(RootModule, logrus.DebugLevel)
+	assert.Equal(t, logrus.WarnLevel, gorm.GetLevel())
+	assert.Equal(t, logrus.DebugLevel, http.GetLevel())
+
+	levels.Reset(ModuleGorm)
+	assert.Equal(t, logrus.DebugLevel, gorm.GetLevel())
+
+	levels.Set(ModuleGateway, logrus.TraceLevel)
+	assert.Equal(t, logrus.TraceLevel, levels.Module(ModuleGateway).GetLevel())
+
+	assert.Equal(t, []ModuleLevel{
+		{Module: RootModule, Level: "debug"},
+		{Module: ModuleGateway, Level: "trace"},
+		{Module: ModuleGorm, Level: "debug", Inherited: true},
+		{Module: ModuleHTTP, Level: "debug", Inherited: true},
+	}, levels.Snapshot())
+}
+
+func TestLevels_ModuleField(t *testing.T) {
+	var buf bytes.Buffer
+	levels := NewLevels(newTestRoot(&buf), Config{Level: logrus.InfoLevel})
+
+	levels.Module(ModuleGateway).Info("ESF gateway request")
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, ModuleGateway, entry["module"])
+}
+
+func TestLevels_Sampling(t *testing.T) {
+	var buf bytes.Buffer
+	levels := NewLevels(newTestRoot(&buf), Config{
+		Level:              logrus.DebugLevel,
+		SamplingInitial:    2,
+		SamplingThereafter: 3,
+	})
+	now := time.Unix(1_700_000_000, 0)
+	levels.sampler.now = func() time.Time { return now }
+
+	log := levels.Module(ModuleGorm)
+	for i := 0; i < 8; i++ {
+		log.Debug("Database query")
+	}
+	log.Warn("Slow database query")
+
+	// Пишутся 1-я, 2-я, 5-я и 8-я отладочные записи и предупреждение
+	assert.Equal(t, 5, strings.Count(buf.String(), "\n"))
+	assert.Equal(t, uint64(4), levels.Dropped())
+
+	// В следующую секунду счетчики начинаются заново
+	buf.Reset()
+	now = now.Add(time.Second)
+	log.Debug("Database query")
+	assert.Equal(t, 1, strings.Count(buf.String(), "\n"))
+}
+
+func TestParseModuleLevels(t *testing.T) {
+	levels, err := ParseModuleLevels(" gorm=warn, gateway=debug,,http=info ")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]logrus.Level{
+		ModuleGorm:    logrus.WarnLevel,
+		ModuleGateway: logrus.DebugLevel,
+		ModuleHTTP:    logrus.InfoLevel,
+	}, levels)
+
+	_, err = ParseModuleLevels("gorm")
+	assert.Error(t, err)
+	_, err = ParseModuleLevels("gorm=loud")
+	assert.Error(t, err)
+}
+
+// recordingHook запоминает поля полученных записей
+type recordingHook struct {
+	entries []logrus.Fields
+}
+
+func (h *recordingHook) Levels() []logrus.Level { return logrus.AllLevels }
+
+func (h *recordingHook) Fire(entry *logrus.Entry) error {
+	h.entries = append(h.entries, entry.Data)
+	return nil
+}
+
+func TestLevels_AddHook(t *testing.T) {
+	var buf bytes.Buffer
+	root := newTestRoot(&buf)
+	levels := NewLevels(root, Config{Level: logrus.InfoLevel})
+	gorm := levels.Module(ModuleGorm)
+
+	hook := &recordingHook{}
+	levels.AddHook(hook)
+
+	root.Info("Server started")
+	gorm.Warn("Slow database query")
+	levels.Module(ModuleGateway).Info("ESF gateway request")
+
+	require.Len(t, hook.entries, 3)
+	assert.NotContains(t, hook.entries[0], "module")
+	assert.Equal(t, ModuleGorm, hook.entries[1]["module"])
+	assert.Equal(t, ModuleGateway, hook.entries[2]["module"])
+}
@@ -0,0 +1,471 @@
+// Package logship отправляет журнал приложения в Loki или Logstash (GELF) по HTTP,
+// чтобы в контейнерах не зависеть от локального файла logs.log.
+//
+// Shipper — hook logrus: запись ставится в буфер в памяти, а отдельная горутина (Start)
+// отправляет записи пачками по BatchSize или раз в FlushInterval, повторяя неудачную отправку
+// с растущей паузой. При полном буфере запись отбрасывается (log_shipping_entries_total{result="dropped"}),
+// не задерживая запросы; Stop отправляет оставшиеся записи.
+package logship
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/pkg/metrics"
+	"github.com/rusgainew/tunduck-app/pkg/retry"
+)
+
+// Форматы получателя
+const (
+	// FormatLoki Loki push API (/loki/api/v1/push)
+	FormatLoki = "loki"
+	// FormatGELF сообщения GELF 1.1, по одному JSON в строке (Logstash http input с кодеком json_lines)
+	FormatGELF = "gelf"
+)
+
+// Параметры отправки по умолчанию
+const (
+	DefaultFormat        = FormatLoki
+	DefaultLevel         = logrus.InfoLevel
+	DefaultBatchSize     = 500
+	DefaultFlushInterval = 2 * time.Second
+	DefaultBufferSize    = 10000
+	DefaultTimeout       = 10 * time.Second
+	DefaultAppName       = "tunduck-app"
+)
+
+// DefaultRetryPolicy 5 попыток отправки пачки с паузой от 500 мс до 10 секунд
+var DefaultRetryPolicy = retry.Policy{
+	Name:            "log shipping",
+	MaxAttempts:     5,
+	InitialInterval: 500 * time.Millisecond,
+	MaxInterval:     10 * time.Second,
+	Multiplier:      2,
+	Jitter:          0.2,
+	MaxElapsed:      time.Minute,
+}
+
+// Config параметры получателя журнала
+type Config struct {
+	// URL адрес получателя: база Loki (http://loki:3100) или HTTP input Logstash; пустой выключает отправку
+	URL string
+	// Format loki (по умолчанию) или gelf
+	Format string
+	// Level наименьший отправляемый уровень (по умолчанию info): отладочные записи не выбираются
+	// выборкой в hook и по умолчанию остаются только в локальном журнале
+	Level logrus.Level
+	// AppName метка app в Loki и поле _app в GELF
+	AppName string
+	// Labels дополнительные метки потока Loki и поля GELF (например env=prod)
+	Labels map[string]string
+	// TenantID заголовок X-Scope-OrgID для многопользовательского Loki
+	TenantID string
+	// Username и Password базовой аутентификации
+	Username string
+	Password string
+	// BatchSize наибольшее число записей в одном запросе
+	BatchSize int
+	// FlushInterval наибольшее время ожидания неполной пачки
+	FlushInterval time.Duration
+	// BufferSize число записей, ожидающих отправки
+	BufferSize int
+	// Timeout одного запроса и отправки остатка при остановке
+	Timeout time.Duration
+	// Retry повторы отправки пачки; нулевое значение — DefaultRetryPolicy
+	Retry      retry.Policy
+	Registerer prometheus.Registerer
+}
+
+// Enabled сообщает, настроена ли отправка
+func (c Config) Enabled() bool {
+	return c.URL != ""
+}
+
+// record запись журнала, ожидающая отправки
+type record struct {
+	time    time.Time
+	level   logrus.Level
+	message string
+	fields  map[string]interface{}
+}
+
+// Shipper отправляет записи журнала получателю
+type Shipper struct {
+	cfg      Config
+	endpoint string
+	hostname string
+	queue    chan record
+	client   *http.Client
+	encode   func([]record) ([]byte, string, error)
+	// warn пишет о сбоях отправки в stderr: через общий логгер сообщение снова попало бы в очередь
+	warn *logrus.Logger
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	entries *prometheus.CounterVec
+}
+
+// New создает Shipper; отправка начинается после Start
+func New(cfg Config) (*Shipper, error) {
+	if cfg.Format == "" {
+		cfg.Format = DefaultFormat
+	}
+	// Нулевой уровень (panic) означает уровень по умолчанию
+	if cfg.Level == logrus.PanicLevel {
+		cfg.Level = DefaultLevel
+	}
+	if cfg.AppName == "" {
+		cfg.AppName = DefaultAppName
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = DefaultFlushInterval
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = DefaultBufferSize
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.Retry.MaxAttempts == 0 && cfg.Retry.MaxElapsed == 0 {
+		cfg.Retry = DefaultRetryPolicy
+	}
+	if cfg.Registerer == nil {
+		cfg.Registerer = prometheus.DefaultRegisterer
+	}
+
+	s := &Shipper{
+		cfg:    cfg,
+		queue:  make(chan record, cfg.BufferSize),
+		client: &http.Client{Timeout: cfg.Timeout},
+		warn:   logrus.New(),
+	}
+	switch cfg.Format {
+	case FormatLoki:
+		s.endpoint = strings.TrimRight(cfg.URL, "/") + "/loki/api/v1/push"
+		s.encode = s.encodeLoki
+	case FormatGELF:
+		s.endpoint = cfg.URL
+		s.encode = s.encodeGELF
+	default:
+		return nil, fmt.Errorf("unsupported log shipping format %q", cfg.Format)
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "unknown"
+	}
+	s.hostname = hostname
+
+	s.entries = metrics.Register(cfg.Registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "log_shipping_entries_total",
+		Help: "Total number of log entries shipped to the log collector by result (sent, dropped, failed)",
+	}, []string{"result"}))
+	metrics.Register(cfg.Registerer, prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "log_shipping_queue_length",
+		Help: "Number of log entries waiting to be shipped",
+	}, func() float64 { return float64(len(s.queue)) }))
+	return s, nil
+}
+
+// Levels уровни, которые отправляются (Config.Level и выше)
+func (s *Shipper) Levels() []logrus.Level {
+	levels := make([]logrus.Level, 0, len(logrus.AllLevels))
+	for _, level := range logrus.AllLevels {
+		if level <= s.cfg.Level {
+			levels = append(levels, level)
+		}
+	}
+	return levels
+}
+
+// Fire ставит запись в очередь; при полном буфере запись отбрасывается
+func (s *Shipper) Fire(entry *logrus.Entry) error {
+	rec := record{
+		time:    entry.Time,
+		level:   entry.Level,
+		message: entry.Message,
+		fields:  make(map[string]interface{}, len(entry.Data)),
+	}
+	for key, value := range entry.Data {
+		rec.fields[key] = jsonValue(value)
+	}
+
+	select {
+	case s.queue <- rec:
+	default:
+		s.entries.WithLabelValues("dropped").Inc()
+	}
+	return nil
+}
+
+// Start запускает отправку в фоне
+func (s *Shipper) Start(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	go s.run(ctx)
+	return nil
+}
+
+// Stop прекращает отправку и отправляет записи из буфера, ожидая не дольше ctx
+func (s *Shipper) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.mu.Unlock()
+	if done == nil {
+		return nil
+	}
+
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("log shipping flush: %w", ctx.Err())
+	}
+}
+
+func (s *Shipper) run(ctx context.Context) {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]record, 0, s.cfg.BatchSize)
+	for {
+		select {
+		case rec := <-s.queue:
+			if batch = append(batch, rec); len(batch) < s.cfg.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		case <-ctx.Done():
+			s.flush(batch)
+			return
+		}
+
+		if !s.send(ctx, batch) {
+			s.flush(batch)
+			return
+		}
+		batch = batch[:0]
+	}
+}
+
+// flush отправляет неполную пачку и остаток буфера одной попыткой в пределах Timeout
+func (s *Shipper) flush(batch []record) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.Timeout)
+	defer cancel()
+
+	for {
+		for len(batch) < s.cfg.BatchSize && len(s.queue) > 0 {
+			batch = append(batch, <-s.queue)
+		}
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.post(ctx, batch); err != nil {
+			failed := len(batch) + len(s.queue)
+			s.entries.WithLabelValues("failed").Add(float64(failed))
+			s.warn.WithError(err).WithField("entries", failed).Warn("Log entries not shipped on shutdown")
+			return
+		}
+		s.entries.WithLabelValues("sent").Add(float64(len(batch)))
+		batch = batch[:0]
+	}
+}
+
+// send отправляет пачку с повторами; после исчерпания попыток пачка отбрасывается.
+// false — отправка прервана остановкой, пачку отправит flush.
+func (s *Shipper) send(ctx context.Context, batch []record) bool {
+	err := retry.Do(ctx, s.cfg.Retry, func(ctx context.Context) error {
+		return s.post(ctx, batch)
+	})
+	if err == nil {
+		s.entries.WithLabelValues("sent").Add(float64(len(batch)))
+		return true
+	}
+	if ctx.Err() != nil {
+		return false
+	}
+
+	s.entries.WithLabelValues("failed").Add(float64(len(batch)))
+	s.warn.WithError(err).WithFields(logrus.Fields{
+		"url":     s.endpoint,
+		"entries": len(batch),
+		"queued":  len(s.queue),
+	}).Warn("Failed to ship log entries")
+	return true
+}
+
+func (s *Shipper) post(ctx context.Context, batch []record) error {
+	body, contentType, err := s.encode(batch)
+	if err != nil {
+		return retry.Permanent(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return retry.Permanent(err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if s.cfg.TenantID != "" {
+		req.Header.Set("X-Scope-OrgID", s.cfg.TenantID)
+	}
+	if s.cfg.Username != "" {
+		req.SetBasicAuth(s.cfg.Username, s.cfg.Password)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+	err = fmt.Errorf("log collector responded with status %d", resp.StatusCode)
+	// Отклоненную пачку (например, слишком старые записи) повторять бессмысленно
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return retry.Permanent(err)
+	}
+	return err
+}
+
+// lokiStream поток Loki: записи одного уровня с общими метками
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// encodeLoki группирует записи в потоки по уровню; строка записи — JSON с msg и полями
+func (s *Shipper) encodeLoki(batch []record) ([]byte, string, error) {
+	streams := map[logrus.Level]*lokiStream{}
+	order := []logrus.Level{}
+	for _, rec := range batch {
+		stream, ok := streams[rec.level]
+		if !ok {
+			labels := map[string]string{"app": s.cfg.AppName, "host": s.hostname}
+			for k, v := range s.cfg.Labels {
+				labels[k] = v
+			}
+			labels["level"] = rec.level.String()
+			stream = &lokiStream{Stream: labels}
+			streams[rec.level] = stream
+			order = append(order, rec.level)
+		}
+
+		line := make(map[string]interface{}, len(rec.fields)+1)
+		for k, v := range rec.fields {
+			line[k] = v
+		}
+		line["msg"] = rec.message
+		encoded, err := json.Marshal(line)
+		if err != nil {
+			return nil, "", err
+		}
+		stream.Values = append(stream.Values, [2]string{strconv.FormatInt(rec.time.UnixNano(), 10), string(encoded)})
+	}
+
+	payload := struct {
+		Streams []*lokiStream `json:"streams"`
+	}{}
+	sort.Slice(order, func(i, j int) bool { return order[i] < order[j] })
+	for _, level := range order {
+		payload.Streams = append(payload.Streams, streams[level])
+	}
+	body, err := json.Marshal(payload)
+	return body, "application/json", err
+}
+
+// encodeGELF представляет записи сообщениями GELF 1.1, по одному в строке
+func (s *Shipper) encodeGELF(batch []record) ([]byte, string, error) {
+	var buf bytes.Buffer
+	for _, rec := range batch {
+		msg := map[string]interface{}{
+			"version":       "1.1",
+			"host":          s.hostname,
+			"short_message": rec.message,
+			"timestamp":     float64(rec.time.UnixMilli()) / 1000,
+			"level":         syslogLevel(rec.level),
+			"_app":          s.cfg.AppName,
+		}
+		for k, v := range s.cfg.Labels {
+			msg["_"+k] = v
+		}
+		for k, v := range rec.fields {
+			// GELF запрещает поле _id
+			if k == "id" {
+				k = "id_"
+			}
+			msg["_"+k] = v
+		}
+		if err := json.NewEncoder(&buf).Encode(msg); err != nil {
+			return nil, "", err
+		}
+	}
+	return buf.Bytes(), "application/x-ndjson", nil
+}
+
+// syslogLevel уровень syslog для GELF
+func syslogLevel(level logrus.Level) int {
+	switch level {
+	case logrus.PanicLevel:
+		return 0
+	case logrus.FatalLevel:
+		return 2
+	case logrus.ErrorLevel:
+		return 3
+	case logrus.WarnLevel:
+		return 4
+	case logrus.InfoLevel:
+		return 6
+	default:
+		return 7
+	}
+}
+
+// jsonValue приводит значение поля к виду, который можно записать в JSON
+func jsonValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	case nil, string, bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return v
+	}
+	if _, err := json.Marshal(value); err != nil {
+		return fmt.Sprint(value)
+	}
+	return value
+}
@@ -0,0 +1,178 @@
+package logship
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/pkg/retry"
+)
+
+// collector тестовый получатель журнала
+type collector struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   [][]byte
+	failures int32
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if atomic.AddInt32(&c.failures, -1) >= 0 {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	c.mu.Lock()
+	c.requests = append(c.requests, r)
+	c.bodies = append(c.bodies, body)
+	c.mu.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *collector) received() [][]byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([][]byte(nil), c.bodies...)
+}
+
+func newTestLogger(shipper *Shipper) *logrus.Logger {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	log.SetLevel(logrus.DebugLevel)
+	log.AddHook(shipper)
+	return log
+}
+
+func TestShipper_Loki(t *testing.T) {
+	col := &collector{}
+	srv := httptest.NewServer(col)
+	defer srv.Close()
+
+	shipper, err := New(Config{
+		URL:        srv.URL + "/",
+		Labels:     map[string]string{"env": "test"},
+		TenantID:   "tunduck",
+		Registerer: prometheus.NewRegistry(),
+	})
+	require.NoError(t, err)
+	log := newTestLogger(shipper)
+
+	log.WithFields(logrus.Fields{"request_id": "req-1", "error": errors.New("boom")}).Warn("Slow database query")
+	log.Info("Document sent")
+	log.Debug("Database query")
+
+	require.NoError(t, shipper.Start(context.Background()))
+	require.NoError(t, shipper.Stop(context.Background()))
+
+	bodies := col.received()
+	require.Len(t, bodies, 1)
+	assert.Equal(t, "/loki/api/v1/push", col.requests[0].URL.Path)
+	assert.Equal(t, "tunduck", col.requests[0].Header.Get("X-Scope-OrgID"))
+
+	var payload struct {
+		Streams []struct {
+			Stream map[string]string `json:"stream"`
+			Values [][2]string       `json:"values"`
+		} `json:"streams"`
+	}
+	require.NoError(t, json.Unmarshal(bodies[0], &payload))
+	// Отладочная запись ниже уровня по умолчанию не отправляется
+	require.Len(t, payload.Streams, 2)
+	assert.Equal(t, "warning", payload.Streams[0].Stream["level"])
+	assert.Equal(t, "test", payload.Streams[0].Stream["env"])
+	assert.Equal(t, DefaultAppName, payload.Streams[0].Stream["app"])
+
+	var line map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(payload.Streams[0].Values[0][1]), &line))
+	assert.Equal(t, "Slow database query", line["msg"])
+	assert.Equal(t, "req-1", line["request_id"])
+	assert.Equal(t, "boom", line["error"])
+	assert.Equal(t, float64(2), testutil.ToFloat64(shipper.entries.WithLabelValues("sent")))
+}
+
+func TestShipper_GELFBatchesAndRetries(t *testing.T) {
+	col := &collector{failures: 2}
+	srv := httptest.NewServer(col)
+	defer srv.Close()
+
+	policy := DefaultRetryPolicy
+	policy.InitialInterval, policy.MaxInterval = time.Millisecond, time.Millisecond
+	shipper, err := New(Config{
+		URL:           srv.URL,
+		Format:        FormatGELF,
+		BatchSize:     2,
+		FlushInterval: time.Hour,
+		Retry:         policy,
+		Registerer:    prometheus.NewRegistry(),
+	})
+	require.NoError(t, err)
+	log := newTestLogger(shipper)
+	require.NoError(t, shipper.Start(context.Background()))
+
+	log.WithField("id", "doc-1").Error("Failed to send document")
+	log.Info("Document sent")
+	require.Eventually(t, func() bool { return len(col.received()) == 1 }, time.Second, 5*time.Millisecond)
+	require.NoError(t, shipper.Stop(context.Background()))
+
+	var messages []map[string]interface{}
+	scanner := bufio.NewScanner(bytes.NewReader(col.received()[0]))
+	for scanner.Scan() {
+		var msg map[string]interface{}
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &msg))
+		messages = append(messages, msg)
+	}
+	require.Len(t, messages, 2)
+	assert.Equal(t, "1.1", messages[0]["version"])
+	assert.Equal(t, "Failed to send document", messages[0]["short_message"])
+	assert.Equal(t, float64(3), messages[0]["level"])
+	assert.Equal(t, "doc-1", messages[0]["_id_"])
+	assert.Equal(t, float64(6), messages[1]["level"])
+}
+
+func TestShipper_DropsWhenBufferFull(t *testing.T) {
+	shipper, err := New(Config{URL: "http://127.0.0.1:1", BufferSize: 1, Registerer: prometheus.NewRegistry()})
+	require.NoError(t, err)
+	log := newTestLogger(shipper)
+
+	log.Info("first")
+	log.Info("second")
+	assert.Equal(t, float64(1), testutil.ToFloat64(shipper.entries.WithLabelValues("dropped")))
+}
+
+func TestShipper_RejectedBatchIsNotRetried(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	shipper, err := New(Config{URL: srv.URL, Registerer: prometheus.NewRegistry()})
+	require.NoError(t, err)
+
+	err = retry.Do(context.Background(), shipper.cfg.Retry, func(ctx context.Context) error {
+		return shipper.post(ctx, []record{{time: time.Now(), level: logrus.InfoLevel, message: "x"}})
+	})
+	assert.Error(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestNew_UnsupportedFormat(t *testing.T) {
+	_, err := New(Config{URL: "http://loki:3100", Format: "syslog", Registerer: prometheus.NewRegistry()})
+	assert.Error(t, err)
+}
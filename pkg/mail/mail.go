@@ -0,0 +1,84 @@
+// Package mail отправляет письма по SMTP и формирует их из HTML-шаблонов.
+//
+// Отправитель подключается через интерфейс Sender: SMTPSender работает с любым SMTP-сервером
+// (включая SMTP-интерфейсы SES и SendGrid), LogSender пишет письма в лог и используется
+// в разработке, если SMTP не настроен.
+package mail
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+)
+
+// Режимы TLS соединения с SMTP-сервером
+const (
+	TLSStartTLS = "starttls"
+	TLSImplicit = "tls"
+	TLSNone     = "none"
+)
+
+// Параметры по умолчанию
+const (
+	DefaultPort    = 587
+	DefaultTimeout = 10 * time.Second
+)
+
+// Message письмо одному или нескольким получателям
+type Message struct {
+	To      []string
+	Subject string
+	HTML    string
+}
+
+// Sender отправляет письма
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// Config параметры SMTP
+type Config struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	// TLS режим шифрования: starttls (по умолчанию), tls или none
+	TLS     string
+	Timeout time.Duration
+}
+
+// Enabled сообщает, настроен ли SMTP-сервер
+func (c Config) Enabled() bool {
+	return c.Host != ""
+}
+
+// NewSender возвращает SMTPSender или LogSender, если SMTP не настроен
+func NewSender(cfg Config, log *logrus.Logger) Sender {
+	if !cfg.Enabled() {
+		return NewLogSender(log)
+	}
+	return NewSMTPSender(cfg)
+}
+
+// LogSender пишет письма в лог вместо отправки
+type LogSender struct {
+	logger *logger.Logger
+}
+
+// NewLogSender создает отправителя для разработки
+func NewLogSender(log *logrus.Logger) *LogSender {
+	return &LogSender{logger: logger.New(log)}
+}
+
+// Send записывает получателей и тему письма в лог
+func (s *LogSender) Send(ctx context.Context, msg Message) error {
+	s.logger.Info(ctx, "Email not sent: SMTP is not configured", logrus.Fields{
+		"to":      msg.To,
+		"subject": msg.Subject,
+	})
+	return nil
+}
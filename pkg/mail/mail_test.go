@@ -0,0 +1,222 @@
+package mail
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"mime/quotedprintable"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/pkg/i18n"
+)
+
+func templateData() map[string]map[string]interface{} {
+	return map[string]map[string]interface{}{
+		TemplateInvitation: {
+			"OrganizationName": `ОсОО "Тундук"`,
+			"InviterName":      "Айбек",
+			"AcceptURL":        "https://app.example.kg/invitations/abc",
+			"ExpiresAt":        "20.10.2026",
+		},
+		TemplatePasswordReset: {
+			"UserName":  "Айбек",
+			"ResetURL":  "https://app.example.kg/reset/abc",
+			"ExpiresIn": "1 час",
+		},
+		TemplateDocumentNotification: {
+			"DocumentNumber":   "ЭСФ-42",
+			"Status":           "approved",
+			"OrganizationName": "Тундук",
+			"DocumentURL":      "https://app.example.kg/documents/42",
+		},
+		TemplateQuotaWarning: {
+			"Resource":         "documents",
+			"OrganizationName": "Тундук",
+			"Used":             900,
+			"Limit":            1000,
+			"Percent":          90,
+			"BillingURL":       "https://app.example.kg/billing",
+		},
+	}
+}
+
+func TestRenderer_AllTemplates(t *testing.T) {
+	r, err := NewRenderer()
+	require.NoError(t, err)
+
+	for name, data := range templateData() {
+		for _, lang := range []i18n.Language{i18n.Russian, i18n.Kyrgyz} {
+			subject, body, err := r.Render(name, lang, data)
+			require.NoError(t, err, "%s/%s", lang, name)
+			assert.NotEmpty(t, subject, "%s/%s", lang, name)
+			assert.Contains(t, body, `<html lang="`+string(lang)+`">`)
+			assert.NotContains(t, subject, "&#34;")
+		}
+	}
+}
+
+func TestRenderer_EscapesData(t *testing.T) {
+	r, err := NewRenderer()
+	require.NoError(t, err)
+
+	data := templateData()[TemplateInvitation]
+	data["InviterName"] = "<script>alert(1)</script>"
+
+	_, body, err := r.Render(TemplateInvitation, i18n.Russian, data)
+	require.NoError(t, err)
+	assert.NotContains(t, body, "<script>")
+}
+
+func TestRenderer_FallsBackToDefaultLanguage(t *testing.T) {
+	r, err := NewRenderer()
+	require.NoError(t, err)
+
+	_, body, err := r.Render(TemplatePasswordReset, i18n.English, templateData()[TemplatePasswordReset])
+	require.NoError(t, err)
+	assert.Contains(t, body, `<html lang="`+string(i18n.DefaultLanguage)+`">`)
+}
+
+func TestRenderer_Errors(t *testing.T) {
+	r, err := NewRenderer()
+	require.NoError(t, err)
+
+	_, _, err = r.Render("unknown", i18n.Russian, nil)
+	assert.ErrorIs(t, err, ErrUnknownTemplate)
+
+	_, _, err = r.Render(TemplatePasswordReset, i18n.Russian, map[string]interface{}{"UserName": "Айбек"})
+	assert.Error(t, err, "missing ResetURL must fail")
+}
+
+func TestBuildMessage(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	raw, err := buildMessage("Tunduck <noreply@tunduck.kg>", Message{
+		To:      []string{"user@example.kg"},
+		Subject: "Сброс пароля",
+		HTML:    "<p>Здравствуйте</p>",
+	}, now)
+	require.NoError(t, err)
+
+	header, body, found := strings.Cut(string(raw), "\r\n\r\n")
+	require.True(t, found)
+	assert.Contains(t, header, "To: user@example.kg\r\n")
+	assert.Contains(t, header, "Subject: =?utf-8?q?")
+	assert.Contains(t, header, "@tunduck.kg>\r\n")
+
+	decoded, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(body)))
+	require.NoError(t, err)
+	assert.Equal(t, "<p>Здравствуйте</p>", string(decoded))
+}
+
+func TestBuildMessage_RejectsHeaderInjection(t *testing.T) {
+	_, err := buildMessage("noreply@tunduck.kg", Message{
+		To:      []string{"user@example.kg\r\nBcc: victim@example.kg"},
+		Subject: "test",
+	}, time.Now())
+	assert.Error(t, err)
+}
+
+// fakeSMTPServer принимает одно письмо без TLS и возвращает его текст
+func fakeSMTPServer(t *testing.T) (string, int, <-chan string) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		rw := bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+		reply := func(line string) {
+			rw.WriteString(line + "\r\n")
+			rw.Flush()
+		}
+
+		reply("220 localhost ESMTP")
+		var data strings.Builder
+		for {
+			line, err := rw.ReadString('\n')
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case cmd == "DATA":
+				reply("354 go ahead")
+				for {
+					l, err := rw.ReadString('\n')
+					if err != nil {
+						return
+					}
+					if l == ".\r\n" {
+						break
+					}
+					data.WriteString(l)
+				}
+				received <- data.String()
+				reply("250 OK")
+			case cmd == "QUIT":
+				reply("221 bye")
+				return
+			default:
+				reply("250 OK")
+			}
+		}
+	}()
+
+	host, port, _ := net.SplitHostPort(ln.Addr().String())
+	p, _ := strconv.Atoi(port)
+	return host, p, received
+}
+
+func TestSMTPSender_Send(t *testing.T) {
+	host, port, received := fakeSMTPServer(t)
+
+	sender := NewSMTPSender(Config{Host: host, Port: port, From: "noreply@tunduck.kg", TLS: TLSNone, Timeout: 5 * time.Second})
+	err := sender.Send(context.Background(), Message{
+		To:      []string{"user@example.kg"},
+		Subject: "Test",
+		HTML:    "<p>ok</p>",
+	})
+	require.NoError(t, err)
+
+	select {
+	case msg := <-received:
+		assert.Contains(t, msg, "To: user@example.kg")
+		assert.Contains(t, msg, "<p>ok</p>")
+	case <-time.After(5 * time.Second):
+		t.Fatal("message was not received")
+	}
+}
+
+func TestSMTPSender_RequiresStartTLS(t *testing.T) {
+	host, port, _ := fakeSMTPServer(t)
+
+	sender := NewSMTPSender(Config{Host: host, Port: port, From: "noreply@tunduck.kg", Timeout: 5 * time.Second})
+	err := sender.Send(context.Background(), Message{To: []string{"user@example.kg"}, Subject: "Test"})
+	assert.ErrorContains(t, err, "STARTTLS")
+}
+
+func TestSMTPSender_Check(t *testing.T) {
+	host, port, received := fakeSMTPServer(t)
+
+	sender := NewSMTPSender(Config{Host: host, Port: port, From: "noreply@tunduck.kg", TLS: TLSNone, Timeout: 5 * time.Second})
+	require.NoError(t, sender.Check(context.Background()))
+	assert.Empty(t, received, "check must not send messages")
+
+	host, port, _ = fakeSMTPServer(t)
+	sender = NewSMTPSender(Config{Host: host, Port: port, Timeout: 5 * time.Second})
+	assert.ErrorContains(t, sender.Check(context.Background()), "STARTTLS")
+}
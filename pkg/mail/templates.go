@@ -0,0 +1,113 @@
+package mail
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"html"
+	"html/template"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/rusgainew/tunduck-app/pkg/i18n"
+)
+
+// Шаблоны писем
+const (
+	TemplateInvitation           = "invitation"
+	TemplatePasswordReset        = "password_reset"
+	TemplateDocumentNotification = "document_notification"
+	TemplateQuotaWarning         = "quota_warning"
+)
+
+// ErrUnknownTemplate возвращается для незарегистрированного шаблона
+var ErrUnknownTemplate = errors.New("unknown email template")
+
+//go:embed templates
+var templateFS embed.FS
+
+// Renderer формирует тему и HTML письма из шаблонов templates/<язык>/<шаблон>.html.
+// Шаблон определяет блоки "subject" и "content"; данные доступны как .Data, язык — как .Lang.
+// Отсутствующее в данных поле считается ошибкой, чтобы не отправить письмо с пустой ссылкой.
+type Renderer struct {
+	templates map[i18n.Language]map[string]*template.Template
+}
+
+// NewRenderer разбирает встроенные шаблоны
+func NewRenderer() (*Renderer, error) {
+	r := &Renderer{templates: make(map[i18n.Language]map[string]*template.Template)}
+
+	langs, err := fs.ReadDir(templateFS, "templates")
+	if err != nil {
+		return nil, err
+	}
+	for _, dir := range langs {
+		if !dir.IsDir() {
+			continue
+		}
+		lang := i18n.Language(dir.Name())
+		files, err := fs.Glob(templateFS, path.Join("templates", dir.Name(), "*.html"))
+		if err != nil {
+			return nil, err
+		}
+
+		footer := path.Join("templates", dir.Name(), "footer.html")
+		r.templates[lang] = make(map[string]*template.Template)
+		for _, file := range files {
+			if file == footer {
+				continue
+			}
+			name := strings.TrimSuffix(path.Base(file), ".html")
+			tmpl, err := template.New(name).
+				Funcs(template.FuncMap{"button": button}).
+				Option("missingkey=error").
+				ParseFS(templateFS, "templates/layout.html", footer, file)
+			if err != nil {
+				return nil, fmt.Errorf("parsing email template %s: %w", file, err)
+			}
+			r.templates[lang][name] = tmpl
+		}
+	}
+	return r, nil
+}
+
+// Has сообщает, есть ли шаблон с таким именем
+func (r *Renderer) Has(name string) bool {
+	_, ok := r.templates[i18n.DefaultLanguage][name]
+	return ok
+}
+
+// Render возвращает тему и HTML письма. Для языка без перевода используется i18n.DefaultLanguage.
+func (r *Renderer) Render(name string, lang i18n.Language, data map[string]interface{}) (string, string, error) {
+	set, ok := r.templates[lang]
+	if !ok || set[name] == nil {
+		lang = i18n.DefaultLanguage
+		set = r.templates[lang]
+	}
+	tmpl, ok := set[name]
+	if !ok {
+		return "", "", fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+	}
+
+	view := struct {
+		Lang string
+		Data map[string]interface{}
+	}{Lang: string(lang), Data: data}
+
+	var subject, body bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&subject, "subject", view); err != nil {
+		return "", "", fmt.Errorf("rendering %s subject: %w", name, err)
+	}
+	if err := tmpl.ExecuteTemplate(&body, "layout", view); err != nil {
+		return "", "", fmt.Errorf("rendering %s body: %w", name, err)
+	}
+	// Тема письма — заголовок, а не HTML: экранирование шаблона в ней не нужно
+	return strings.TrimSpace(html.UnescapeString(subject.String())), body.String(), nil
+}
+
+// button передает ссылку и надпись в общий блок кнопки
+func button(url, label string) map[string]string {
+	return map[string]string{"URL": url, "Label": label}
+}
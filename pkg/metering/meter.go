@@ -0,0 +1,165 @@
+// Package metering учитывает тарифицируемые действия организаций для выставления счетов.
+//
+// Meter копит приращения в памяти по ключу (организация, метрика, час) и периодически
+// (Run) добавляет их в хранилище одной пачкой, где они складываются с уже записанными
+// за тот же час. Поэтому запрос API не ждет записи в БД, а в таблице остается по одной
+// строке на организацию, метрику и час, сколько бы инстансов ни работало.
+package metering
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+)
+
+// Metric тарифицируемая метрика
+type Metric string
+
+const (
+	// MetricDocumentsSent документы, отправленные в ЭСФ
+	MetricDocumentsSent Metric = "documents_sent"
+	// MetricStorageBytes байты загруженных вложений
+	MetricStorageBytes Metric = "storage_bytes"
+	// MetricAPICalls запросы к API от имени организации
+	MetricAPICalls Metric = "api_calls"
+)
+
+// Metrics все метрики в порядке вывода в отчетах
+var Metrics = []Metric{MetricDocumentsSent, MetricStorageBytes, MetricAPICalls}
+
+// DefaultFlushInterval период записи накопленных приращений
+const DefaultFlushInterval = time.Minute
+
+// Config параметры учета
+type Config struct {
+	Enabled bool
+	// FlushInterval период записи накопленных приращений в БД
+	FlushInterval time.Duration
+}
+
+// Record приращение метрики организации за час, начинающийся в Hour (UTC)
+type Record struct {
+	OrganizationID uuid.UUID
+	Metric         Metric
+	Hour           time.Time
+	Quantity       int64
+}
+
+// Store добавляет приращения к почасовым суммам
+type Store interface {
+	AddUsage(ctx context.Context, records []Record) error
+}
+
+type recordKey struct {
+	orgID  uuid.UUID
+	metric Metric
+	hour   time.Time
+}
+
+// Meter накапливает приращения метрик и записывает их в Store. Методы nil-Meter ничего не делают,
+// поэтому сервисы вызывают Add, не проверяя, включен ли учет.
+type Meter struct {
+	store    Store
+	interval time.Duration
+	now      func() time.Time
+	logger   *logger.Logger
+
+	mu      sync.Mutex
+	pending map[recordKey]int64
+	// flushMu не дает периодической и финальной записи выполняться одновременно
+	flushMu sync.Mutex
+}
+
+// NewMeter создает учет поверх store; FlushInterval <= 0 — DefaultFlushInterval
+func NewMeter(store Store, cfg Config, log *logrus.Logger) *Meter {
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = DefaultFlushInterval
+	}
+	return &Meter{
+		store:    store,
+		interval: cfg.FlushInterval,
+		now:      time.Now,
+		logger:   logger.New(log),
+		pending:  make(map[recordKey]int64),
+	}
+}
+
+// Add учитывает quantity единиц метрики организации в текущем часе
+func (m *Meter) Add(orgID uuid.UUID, metric Metric, quantity int64) {
+	if m == nil || orgID == uuid.Nil || quantity <= 0 {
+		return
+	}
+	key := recordKey{orgID: orgID, metric: metric, hour: m.now().UTC().Truncate(time.Hour)}
+
+	m.mu.Lock()
+	m.pending[key] += quantity
+	m.mu.Unlock()
+}
+
+// Run записывает накопленное каждые interval до отмены ctx. Остаток записывается
+// при остановке приложения вызовом Flush.
+func (m *Meter) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.Flush(ctx); err != nil {
+				m.logger.Error(ctx, "Failed to flush usage records", err)
+			}
+		}
+	}
+}
+
+// Flush записывает накопленные приращения. При ошибке они возвращаются в буфер
+// и будут записаны следующим вызовом.
+func (m *Meter) Flush(ctx context.Context) error {
+	if m == nil {
+		return nil
+	}
+	m.flushMu.Lock()
+	defer m.flushMu.Unlock()
+
+	m.mu.Lock()
+	pending := m.pending
+	m.pending = make(map[recordKey]int64)
+	m.mu.Unlock()
+	if len(pending) == 0 {
+		return nil
+	}
+
+	records := make([]Record, 0, len(pending))
+	for key, quantity := range pending {
+		records = append(records, Record{OrganizationID: key.orgID, Metric: key.metric, Hour: key.hour, Quantity: quantity})
+	}
+	// Постоянный порядок строк в пачке исключает взаимные блокировки инстансов при upsert
+	sort.Slice(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if a.OrganizationID != b.OrganizationID {
+			return a.OrganizationID.String() < b.OrganizationID.String()
+		}
+		if a.Metric != b.Metric {
+			return a.Metric < b.Metric
+		}
+		return a.Hour.Before(b.Hour)
+	})
+
+	if err := m.store.AddUsage(ctx, records); err != nil {
+		m.mu.Lock()
+		for key, quantity := range pending {
+			m.pending[key] += quantity
+		}
+		m.mu.Unlock()
+		return err
+	}
+	return nil
+}
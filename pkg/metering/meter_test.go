@@ -0,0 +1,111 @@
+package metering
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubStore сохраняет записанные пачки; err возвращается вместо записи
+type stubStore struct {
+	batches [][]Record
+	err     error
+}
+
+func (s *stubStore) AddUsage(ctx context.Context, records []Record) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.batches = append(s.batches, records)
+	return nil
+}
+
+func newTestMeter(store Store, now *time.Time) *Meter {
+	m := NewMeter(store, Config{Enabled: true}, logrus.New())
+	m.now = func() time.Time { return *now }
+	return m
+}
+
+func TestMeter_FlushSumsPerHour(t *testing.T) {
+	store := &stubStore{}
+	now := time.Date(2026, 3, 1, 10, 15, 0, 0, time.UTC)
+	m := newTestMeter(store, &now)
+	orgID := uuid.New()
+
+	m.Add(orgID, MetricAPICalls, 1)
+	m.Add(orgID, MetricAPICalls, 2)
+	now = now.Add(time.Hour)
+	m.Add(orgID, MetricAPICalls, 5)
+	m.Add(uuid.Nil, MetricAPICalls, 1)
+	m.Add(orgID, MetricStorageBytes, 0)
+
+	require.NoError(t, m.Flush(context.Background()))
+	require.Len(t, store.batches, 1)
+	assert.Equal(t, []Record{
+		{OrganizationID: orgID, Metric: MetricAPICalls, Hour: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC), Quantity: 3},
+		{OrganizationID: orgID, Metric: MetricAPICalls, Hour: time.Date(2026, 3, 1, 11, 0, 0, 0, time.UTC), Quantity: 5},
+	}, store.batches[0])
+
+	// Записанное не повторяется, пустой буфер не пишется
+	require.NoError(t, m.Flush(context.Background()))
+	assert.Len(t, store.batches, 1)
+}
+
+func TestMeter_FlushKeepsRecordsOnError(t *testing.T) {
+	store := &stubStore{err: errors.New("db down")}
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	m := newTestMeter(store, &now)
+	orgID := uuid.New()
+
+	m.Add(orgID, MetricDocumentsSent, 1)
+	require.Error(t, m.Flush(context.Background()))
+	m.Add(orgID, MetricDocumentsSent, 1)
+
+	store.err = nil
+	require.NoError(t, m.Flush(context.Background()))
+	require.Len(t, store.batches, 1)
+	require.Len(t, store.batches[0], 1)
+	assert.Equal(t, int64(2), store.batches[0][0].Quantity)
+}
+
+func TestMeter_NilIsNoop(t *testing.T) {
+	var m *Meter
+	m.Add(uuid.New(), MetricAPICalls, 1)
+	assert.NoError(t, m.Flush(context.Background()))
+}
+
+func TestCountRequests(t *testing.T) {
+	store := &stubStore{}
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	m := newTestMeter(store, &now)
+	orgID := uuid.New()
+
+	app := fiber.New()
+	app.Use(CountRequests(m))
+	app.Get("/ok", func(ctx *fiber.Ctx) error { return ctx.SendStatus(fiber.StatusOK) })
+	app.Get("/fail", func(ctx *fiber.Ctx) error { return ctx.SendStatus(fiber.StatusInternalServerError) })
+
+	for _, target := range []string{"/ok", "/ok?orgId=" + orgID.String(), "/fail", "/missing"} {
+		req := httptest.NewRequest(fiber.MethodGet, target, nil)
+		req.Header.Set("X-Org-Id", orgID.String())
+		_, err := app.Test(req)
+		require.NoError(t, err)
+	}
+	// Без организации запрос не учитывается
+	_, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/ok", nil))
+	require.NoError(t, err)
+
+	require.NoError(t, m.Flush(context.Background()))
+	require.Len(t, store.batches, 1)
+	assert.Equal(t, []Record{
+		{OrganizationID: orgID, Metric: MetricAPICalls, Hour: now, Quantity: 2},
+	}, store.batches[0])
+}
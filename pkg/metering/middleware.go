@@ -0,0 +1,34 @@
+package metering
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// CountRequests создает middleware, учитывающий запросы к API от имени организации
+// (заголовок X-Org-Id или query orgId). Не учитываются запросы без организации, ответы 5xx
+// (сбой на нашей стороне) и ошибки, переданные обработчику ошибок Fiber (неизвестный маршрут).
+// С nil meter middleware только передает запрос дальше.
+func CountRequests(meter *Meter) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if meter == nil {
+			return ctx.Next()
+		}
+
+		if err := ctx.Next(); err != nil {
+			return err
+		}
+		if ctx.Response().StatusCode() >= fiber.StatusInternalServerError {
+			return nil
+		}
+
+		raw := ctx.Get("X-Org-Id")
+		if raw == "" {
+			raw = ctx.Query("orgId")
+		}
+		if orgID, err := uuid.Parse(raw); err == nil {
+			meter.Add(orgID, MetricAPICalls, 1)
+		}
+		return nil
+	}
+}
@@ -1,7 +1,11 @@
 package metrics
 
 import (
+	"database/sql"
+	"errors"
+
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
 	"github.com/prometheus/client_golang/prometheus/promauto"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
@@ -18,6 +22,11 @@ type Metrics struct {
 	HTTPRequestDuration prometheus.Histogram
 	HTTPRequestSize     prometheus.Histogram
 	HTTPResponseSize    prometheus.Histogram
+	PanicsTotal         *prometheus.CounterVec
+
+	// Health метрики
+	HealthStatus      *prometheus.GaugeVec
+	HealthComponentUp *prometheus.GaugeVec
 
 	// Cache метрики
 	CacheHitsTotal         prometheus.Counter
@@ -25,6 +34,7 @@ type Metrics struct {
 	CacheEvictionsTotal    prometheus.Counter
 	CacheItemsTotal        prometheus.Gauge
 	CacheOperationDuration prometheus.Histogram
+	CacheWarmingDuration   *prometheus.HistogramVec
 
 	// Database метрики
 	DBQueryDuration     prometheus.Histogram
@@ -66,6 +76,20 @@ func NewMetrics() *Metrics {
 			Help:    "HTTP response size in bytes",
 			Buckets: []float64{100, 1000, 10000, 100000, 1000000},
 		}),
+		PanicsTotal: promauto.NewCounterVec(prometheus.CounterOpts{
+			Name: "panics_total",
+			Help: "Total number of recovered panics in HTTP handlers",
+		}, []string{"method", "route"}),
+
+		// Health метрики
+		HealthStatus: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "health_status",
+			Help: "Current system health status (1 for the active status: UP, DEGRADED or DOWN)",
+		}, []string{"status"}),
+		HealthComponentUp: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "health_component_up",
+			Help: "Whether a health-checked component is up (1) or down (0)",
+		}, []string{"component", "optional"}),
 
 		// Cache метрики
 		CacheHitsTotal: promauto.NewCounter(prometheus.CounterOpts{
@@ -89,6 +113,11 @@ func NewMetrics() *Metrics {
 			Help:    "Cache operation duration in seconds",
 			Buckets: prometheus.DefBuckets,
 		}),
+		CacheWarmingDuration: promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "cache_warming_duration_seconds",
+			Help:    "Cache warming duration in seconds by warmer",
+			Buckets: []float64{0.01, 0.05, 0.1, 0.5, 1, 2.5, 5, 10, 30},
+		}, []string{"warmer", "status"}),
 
 		// Database метрики
 		DBQueryDuration: promauto.NewHistogram(prometheus.HistogramOpts{
@@ -138,3 +167,24 @@ func NewMetrics() *Metrics {
 		}),
 	}
 }
+
+// RegisterDBStats публикует состояние пула соединений (go_sql_*) с меткой db_name:
+// открытые, занятые и простаивающие соединения, лимит и ожидание свободного соединения
+func RegisterDBStats(reg prometheus.Registerer, db *sql.DB, dbName string) error {
+	return reg.Register(collectors.NewDBStatsCollector(db, dbName))
+}
+
+// Register регистрирует коллектор в reg. Если такой коллектор уже зарегистрирован (повторное создание
+// компонента с тем же Registerer, например в тестах), возвращает существующий, чтобы значения
+// продолжали накапливаться в нем.
+func Register[C prometheus.Collector](reg prometheus.Registerer, c C) C {
+	if err := reg.Register(c); err != nil {
+		var are prometheus.AlreadyRegisteredError
+		if errors.As(err, &are) {
+			if existing, ok := are.ExistingCollector.(C); ok {
+				return existing
+			}
+		}
+	}
+	return c
+}
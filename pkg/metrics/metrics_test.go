@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegisterDBStats(t *testing.T) {
+	// sql.Open не устанавливает соединение: статистика пула доступна без БД
+	db, err := sql.Open("pgx", "host=127.0.0.1 port=1 user=test dbname=test")
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(25)
+
+	reg := prometheus.NewRegistry()
+	require.NoError(t, RegisterDBStats(reg, db, "main"))
+
+	families, err := reg.Gather()
+	require.NoError(t, err)
+
+	values := map[string]float64{}
+	for _, mf := range families {
+		for _, m := range mf.GetMetric() {
+			assert.Equal(t, "main", m.GetLabel()[0].GetValue())
+			if m.GetGauge() != nil {
+				values[mf.GetName()] = m.GetGauge().GetValue()
+			}
+		}
+	}
+	assert.Equal(t, float64(25), values["go_sql_max_open_connections"])
+	assert.Contains(t, values, "go_sql_in_use_connections")
+	assert.Contains(t, values, "go_sql_idle_connections")
+
+	// Повторная регистрация той же БД отклоняется
+	assert.Error(t, RegisterDBStats(reg, db, "main"))
+}
+
+func TestRegister_ReturnsExisting(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	opts := prometheus.CounterOpts{Name: "test_events_total", Help: "Test events"}
+
+	first := Register(reg, prometheus.NewCounter(opts))
+	first.Inc()
+	// Повторная регистрация с тем же Registerer возвращает уже зарегистрированный счетчик
+	second := Register(reg, prometheus.NewCounter(opts))
+	second.Inc()
+
+	assert.Same(t, first, second)
+	families, err := reg.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+	assert.Equal(t, float64(2), families[0].GetMetric()[0].GetCounter().GetValue())
+}
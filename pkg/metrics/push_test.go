@@ -0,0 +1,81 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gatheredValues(t *testing.T, g prometheus.Gatherer) map[string]float64 {
+	t.Helper()
+	families, err := g.Gather()
+	require.NoError(t, err)
+	values := map[string]float64{}
+	for _, mf := range families {
+		values[mf.GetName()] = mf.GetMetric()[0].GetGauge().GetValue()
+	}
+	return values
+}
+
+func TestBatchJob_Gatherer(t *testing.T) {
+	job := NewBatchJob("tunduck_migrate")
+	applied := prometheus.NewGauge(prometheus.GaugeOpts{Name: "migrations_applied", Help: "test"})
+	applied.Set(3)
+	job.Registerer().MustRegister(applied)
+
+	values := gatheredValues(t, job.Gatherer(nil))
+	assert.Equal(t, float64(1), values["batch_job_success"])
+	assert.Equal(t, float64(3), values["migrations_applied"])
+	assert.GreaterOrEqual(t, values["batch_job_duration_seconds"], float64(0))
+	assert.Equal(t, values["batch_job_last_completion_timestamp_seconds"], values["batch_job_last_success_timestamp_seconds"])
+
+	// При ошибке время последнего успеха не отправляется и в Pushgateway остается прежним
+	values = gatheredValues(t, job.Gatherer(errors.New("boom")))
+	assert.Equal(t, float64(0), values["batch_job_success"])
+	assert.NotContains(t, values, "batch_job_last_success_timestamp_seconds")
+	assert.Contains(t, values, "batch_job_last_completion_timestamp_seconds")
+}
+
+func TestBatchJob_Push(t *testing.T) {
+	var method, path, user, password string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, path = r.Method, r.URL.Path
+		user, password, _ = r.BasicAuth()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	job := NewBatchJob("tunduck_seed")
+	ctx := WithBatchJob(context.Background(), job)
+	require.Same(t, job, BatchJobFromContext(ctx))
+	assert.Nil(t, BatchJobFromContext(context.Background()))
+
+	target := strings.Replace(server.URL, "http://", "http://ops:secret@", 1)
+	require.NoError(t, job.Push(ctx, PushConfig{URL: target, Instance: "cron-1"}, nil))
+	assert.Equal(t, http.MethodPost, method)
+	assert.Equal(t, "/metrics/job/tunduck_seed/instance/cron-1", path)
+	assert.Equal(t, "ops", user)
+	assert.Equal(t, "secret", password)
+
+	// Без адреса метрики не отправляются
+	require.NoError(t, job.Push(ctx, PushConfig{}, nil))
+}
+
+func TestBatchJob_PushErrorHidesCredentials(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	target := strings.Replace(server.URL, "http://", "http://ops:secret@", 1)
+	err := NewBatchJob("tunduck_seed").Push(context.Background(), PushConfig{URL: target}, nil)
+	require.Error(t, err)
+	assert.NotContains(t, err.Error(), "secret")
+}
@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/pkg/audit"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// auditRecordTimeout ограничивает запись в журнал после отправки ответа
+const auditRecordTimeout = 5 * time.Second
+
+// AuditMiddleware записывает в журнал аудита каждый POST, PUT, PATCH и DELETE запрос:
+// автора из JWT, организацию (X-Org-Id), сущность из маршрута, статус ответа, IP и request ID.
+// Тело запроса не сохраняется; состояние сущности до и после добавляют сервисы через audit.Entry.
+// Ошибка записи журнала не меняет ответ клиенту.
+func AuditMiddleware(recorder audit.Recorder, logger *logrus.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		switch c.Method() {
+		case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete:
+		default:
+			return c.Next()
+		}
+
+		entry := audit.NewEntry()
+		c.Locals(audit.LocalsKey, entry)
+
+		err := c.Next()
+
+		log := &entity.AuditLog{
+			Action:         auditAction(c.Method()),
+			Method:         c.Method(),
+			Path:           c.Path(),
+			StatusCode:     c.Response().StatusCode(),
+			IP:             c.IP(),
+			UserAgent:      truncate(c.Get(fiber.HeaderUserAgent), 512),
+			OrganizationID: c.Get("X-Org-Id"),
+			CreatedAt:      time.Now(),
+		}
+		if err != nil {
+			// Ошибка еще не обработана глобальным обработчиком, статус ответа не выставлен
+			log.StatusCode = fiber.StatusInternalServerError
+			if fe, ok := err.(*fiber.Error); ok {
+				log.StatusCode = fe.Code
+			}
+		}
+		if requestID, ok := c.Locals("request_id").(string); ok {
+			log.RequestID = requestID
+		}
+		log.ActorID, log.ActorName = auditActor(c)
+		var action string
+		log.EntityType, log.EntityID, action = auditEntity(c)
+		if action != "" {
+			log.Action = action
+		}
+
+		fields := logrus.Fields{"path": log.Path, "request_id": log.RequestID}
+		if applyErr := entry.Apply(log); applyErr != nil {
+			logger.WithFields(fields).WithError(applyErr).Warn("Failed to serialize audit change")
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), auditRecordTimeout)
+		defer cancel()
+		if recErr := recorder.Record(ctx, log); recErr != nil {
+			logger.WithFields(fields).WithError(recErr).Error("Failed to record audit log")
+		}
+
+		return err
+	}
+}
+
+// auditAction выводит действие из HTTP-метода
+func auditAction(method string) string {
+	switch method {
+	case fiber.MethodPost:
+		return "create"
+	case fiber.MethodDelete:
+		return "delete"
+	default:
+		return "update"
+	}
+}
+
+// auditActor возвращает ID и имя пользователя из JWT, если запрос аутентифицирован
+func auditActor(c *fiber.Ctx) (string, string) {
+	token, ok := c.Locals("user").(*jwt.Token)
+	if !ok {
+		return "", ""
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return "", ""
+	}
+	id, _ := claims["user_id"].(string)
+	name, _ := claims["username"].(string)
+	if name == "" {
+		name, _ = claims["email"].(string)
+	}
+	return id, name
+}
+
+// auditEntity определяет сущность по шаблону маршрута: последняя пара "сегмент/:параметр" дает тип и ID
+// ("/api/admin/users/:id/restore" -> users, id), а завершающий сегмент после нее — действие (restore).
+// Без параметров типом считается первый значимый сегмент ("/api/esf-documents" -> esf-documents).
+func auditEntity(c *fiber.Ctx) (entityType, entityID, action string) {
+	route := c.Route()
+	if route == nil {
+		return "", "", ""
+	}
+
+	var segments []string
+	for _, segment := range strings.Split(route.Path, "/") {
+		if segment != "" && segment != "api" && segment != "admin" {
+			segments = append(segments, segment)
+		}
+	}
+
+	pairEnd := -1
+	for i := 0; i+1 < len(segments); i++ {
+		if !strings.HasPrefix(segments[i], ":") && strings.HasPrefix(segments[i+1], ":") {
+			entityType = segments[i]
+			entityID = c.Params(strings.TrimPrefix(segments[i+1], ":"))
+			pairEnd = i + 1
+		}
+	}
+
+	switch {
+	case pairEnd < 0 && len(segments) > 0:
+		entityType = segments[0]
+	case pairEnd >= 0 && pairEnd == len(segments)-2 && !strings.HasPrefix(segments[pairEnd+1], ":"):
+		action = segments[pairEnd+1]
+	}
+	return entityType, entityID, action
+}
+
+func truncate(s string, n int) string {
+	if len(s) > n {
+		return s[:n]
+	}
+	return s
+}
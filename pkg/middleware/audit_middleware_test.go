@@ -0,0 +1,117 @@
+package middleware
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/pkg/audit"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+type recordingAuditRecorder struct {
+	logs []*entity.AuditLog
+}
+
+func (r *recordingAuditRecorder) Record(_ context.Context, log *entity.AuditLog) error {
+	r.logs = append(r.logs, log)
+	return nil
+}
+
+func newAuditTestApp(recorder audit.Recorder) *fiber.App {
+	logger, _ := test.NewNullLogger()
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals("request_id", "req-1")
+		c.Locals("user", &jwt.Token{Claims: jwt.MapClaims{"user_id": "u-1", "username": "admin"}})
+		return c.Next()
+	})
+	app.Use(AuditMiddleware(recorder, logger))
+	return app
+}
+
+func TestAuditMiddleware_RecordsMutations(t *testing.T) {
+	recorder := &recordingAuditRecorder{}
+	app := newAuditTestApp(recorder)
+	app.Post("/api/admin/users/:id/restore", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	req := httptest.NewRequest(fiber.MethodPost, "/api/admin/users/42/restore", nil)
+	req.Header.Set("X-Org-Id", "org-1")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	require.Len(t, recorder.logs, 1)
+	log := recorder.logs[0]
+	assert.Equal(t, "users", log.EntityType)
+	assert.Equal(t, "42", log.EntityID)
+	assert.Equal(t, "restore", log.Action)
+	assert.Equal(t, fiber.MethodPost, log.Method)
+	assert.Equal(t, "/api/admin/users/42/restore", log.Path)
+	assert.Equal(t, fiber.StatusOK, log.StatusCode)
+	assert.Equal(t, "u-1", log.ActorID)
+	assert.Equal(t, "admin", log.ActorName)
+	assert.Equal(t, "org-1", log.OrganizationID)
+	assert.Equal(t, "req-1", log.RequestID)
+}
+
+func TestAuditMiddleware_ServiceChange(t *testing.T) {
+	recorder := &recordingAuditRecorder{}
+	app := newAuditTestApp(recorder)
+	app.Put("/api/esf-documents/:id", func(c *fiber.Ctx) error {
+		// Сервисы получают запись через контекст запроса
+		entry := audit.FromContext(c.Context())
+		require.NotNil(t, entry)
+		entry.SetChange(map[string]string{"status": "draft"}, map[string]string{"status": "sent"})
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodPut, "/api/esf-documents/7", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+	require.Len(t, recorder.logs, 1)
+	log := recorder.logs[0]
+	assert.Equal(t, "esf-documents", log.EntityType)
+	assert.Equal(t, "7", log.EntityID)
+	assert.Equal(t, "update", log.Action)
+	assert.JSONEq(t, `{"status":{"from":"draft","to":"sent"}}`, string(log.Diff))
+}
+
+func TestAuditMiddleware_SkipsReads(t *testing.T) {
+	recorder := &recordingAuditRecorder{}
+	app := newAuditTestApp(recorder)
+	app.Get("/api/esf-documents/:id", func(c *fiber.Ctx) error {
+		assert.Nil(t, audit.FromContext(c.Context()))
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	_, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/api/esf-documents/7", nil))
+	require.NoError(t, err)
+	assert.Empty(t, recorder.logs)
+}
+
+func TestAuditMiddleware_RecordsErrors(t *testing.T) {
+	recorder := &recordingAuditRecorder{}
+	app := newAuditTestApp(recorder)
+	app.Delete("/api/esf-documents/:id", func(c *fiber.Ctx) error {
+		return fiber.ErrForbidden
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodDelete, "/api/esf-documents/7", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+
+	require.Len(t, recorder.logs, 1)
+	assert.Equal(t, "delete", recorder.logs[0].Action)
+	assert.Equal(t, fiber.StatusForbidden, recorder.logs[0].StatusCode)
+}
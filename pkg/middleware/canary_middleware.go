@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/rusgainew/tunduck-app/pkg/canary"
+)
+
+// CanaryMiddleware выбирает реализацию для запросов к маршрутам canary.Config.Routes по заголовку
+// X-Canary и ID организации (X-Org-Id или ?orgId) и сохраняет выбор в Locals, откуда его читают
+// сервисы. Вариант, обслуживший запрос, возвращается в заголовке X-Canary-Variant.
+func CanaryMiddleware(router *canary.Router) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if router == nil || !router.Matches(c.Path()) {
+			return c.Next()
+		}
+
+		orgID := c.Get("X-Org-Id")
+		if orgID == "" {
+			orgID = c.Query("orgId")
+		}
+		variant := router.Choose(c.Get(canary.HeaderCanary), orgID)
+		c.Locals(canary.LocalsKey, variant)
+		c.Set(canary.HeaderVariant, string(variant))
+		return c.Next()
+	}
+}
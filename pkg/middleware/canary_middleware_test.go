@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/pkg/canary"
+)
+
+func TestCanaryMiddleware(t *testing.T) {
+	router, err := canary.New(canary.Config{Registerer: prometheus.NewRegistry()})
+	require.NoError(t, err)
+
+	app := fiber.New()
+	app.Use(CanaryMiddleware(router))
+	handler := func(c *fiber.Ctx) error {
+		// Сервисы получают вариант из контекста запроса
+		return c.SendString(string(canary.FromContext(c.Context())))
+	}
+	app.Get("/api/esf-documents", handler)
+	app.Get("/api/contracts", handler)
+
+	send := func(path, header string) (string, string) {
+		req := httptest.NewRequest(fiber.MethodGet, path, nil)
+		if header != "" {
+			req.Header.Set(canary.HeaderCanary, header)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		body := make([]byte, 16)
+		n, _ := resp.Body.Read(body)
+		return string(body[:n]), resp.Header.Get(canary.HeaderVariant)
+	}
+
+	body, variant := send("/api/esf-documents", "1")
+	assert.Equal(t, "canary", body)
+	assert.Equal(t, "canary", variant)
+
+	body, variant = send("/api/esf-documents", "")
+	assert.Equal(t, "primary", body)
+	assert.Equal(t, "primary", variant)
+
+	// Маршрут вне выката всегда обслуживает основная реализация
+	body, variant = send("/api/contracts", "1")
+	assert.Equal(t, "primary", body)
+	assert.Empty(t, variant)
+}
@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+)
+
+// DefaultCORSMaxAge срок, на который браузер кеширует ответ на preflight-запрос
+const DefaultCORSMaxAge = 10 * time.Minute
+
+// DefaultCORSAllowHeaders заголовки, которые браузерный клиент может передавать в запросах
+var DefaultCORSAllowHeaders = []string{
+	fiber.HeaderOrigin,
+	fiber.HeaderContentType,
+	fiber.HeaderAccept,
+	fiber.HeaderAcceptLanguage,
+	fiber.HeaderAuthorization,
+	fiber.HeaderIfMatch,
+	fiber.HeaderIfNoneMatch,
+	"X-Org-Id",
+	"X-Request-ID",
+}
+
+// DefaultCORSExposeHeaders заголовки ответа, доступные скриптам на странице клиента
+var DefaultCORSExposeHeaders = []string{
+	fiber.HeaderETag,
+	fiber.HeaderLocation,
+	fiber.HeaderContentDisposition,
+	fiber.HeaderContentLanguage,
+	fiber.HeaderRetryAfter,
+	"X-Request-ID",
+	"X-RateLimit-Reset",
+}
+
+// corsMethods методы, разрешенные в межсайтовых запросах
+var corsMethods = []string{
+	fiber.MethodGet,
+	fiber.MethodHead,
+	fiber.MethodPost,
+	fiber.MethodPut,
+	fiber.MethodPatch,
+	fiber.MethodDelete,
+	fiber.MethodOptions,
+}
+
+// CORSConfig параметры CORS
+type CORSConfig struct {
+	// AllowOrigins источники вида https://app.tunduck.kg, шаблоны поддоменов https://*.tunduck.kg
+	// или "*" для любого источника; пустой список запрещает межсайтовые запросы
+	AllowOrigins []string
+	// AllowHeaders заголовки запроса, разрешенные в preflight-ответе
+	AllowHeaders []string
+	// ExposeHeaders заголовки ответа, доступные скриптам клиента
+	ExposeHeaders []string
+	// AllowCredentials разрешает запросы с cookie и заголовком Authorization, переданным браузером;
+	// несовместимо с "*"
+	AllowCredentials bool
+	// MaxAge срок кеширования preflight-ответа браузером (Access-Control-Max-Age); 0 — не кешировать
+	MaxAge time.Duration
+}
+
+// ErrCORSWildcardCredentials "*" в списке источников вместе с AllowCredentials
+var ErrCORSWildcardCredentials = errors.New(`origin "*" cannot be combined with credentials`)
+
+// OriginMatcher проверяет источник запроса по списку точных источников и шаблонов поддоменов
+type OriginMatcher struct {
+	any       bool
+	exact     map[string]struct{}
+	wildcards []wildcardOrigin
+}
+
+// wildcardOrigin шаблон https://*.tunduck.kg: схема и порт совпадают точно, хост — любой поддомен
+// suffix любой глубины, но не сам suffix
+type wildcardOrigin struct {
+	scheme string
+	suffix string
+	port   string
+}
+
+// ParseOrigins разбирает список источников. Источник — схема http или https и хост с необязательным
+// портом, без пути. Шаблон поддоменов задается звездочкой вместо первой метки хоста: https://*.tunduck.kg
+// разрешает https://app.tunduck.kg и https://a.b.tunduck.kg, но не https://tunduck.kg
+// и не https://eviltunduck.kg.
+func ParseOrigins(origins []string) (*OriginMatcher, error) {
+	m := &OriginMatcher{exact: make(map[string]struct{})}
+	for _, raw := range origins {
+		origin := strings.ToLower(strings.TrimSpace(raw))
+		if origin == "" {
+			continue
+		}
+		if origin == "*" {
+			m.any = true
+			continue
+		}
+
+		u, err := url.Parse(origin)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+			u.User != nil || u.Path != "" || u.RawQuery != "" || u.ForceQuery || u.Fragment != "" {
+			return nil, fmt.Errorf("invalid origin %q: must be scheme://host[:port]", raw)
+		}
+
+		host := u.Hostname()
+		if !strings.HasPrefix(host, "*.") {
+			if strings.Contains(host, "*") {
+				return nil, fmt.Errorf("invalid origin %q: * is allowed only as the first label", raw)
+			}
+			m.exact[origin] = struct{}{}
+			continue
+		}
+
+		suffix := strings.TrimPrefix(host, "*.")
+		if suffix == "" || strings.Contains(suffix, "*") || strings.HasPrefix(suffix, ".") || !strings.Contains(suffix, ".") {
+			return nil, fmt.Errorf("invalid origin %q: * is allowed only as the first label of a domain", raw)
+		}
+		m.wildcards = append(m.wildcards, wildcardOrigin{scheme: u.Scheme, suffix: suffix, port: u.Port()})
+	}
+	return m, nil
+}
+
+// Any сообщает, что разрешен любой источник ("*")
+func (m *OriginMatcher) Any() bool {
+	return m.any
+}
+
+// Allows сообщает, разрешен ли источник из заголовка Origin
+func (m *OriginMatcher) Allows(origin string) bool {
+	origin = strings.ToLower(origin)
+	if m.any {
+		return true
+	}
+	if _, ok := m.exact[origin]; ok {
+		return true
+	}
+	if len(m.wildcards) == 0 {
+		return false
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" || u.Path != "" || u.User != nil {
+		return false
+	}
+	host := u.Hostname()
+	for _, w := range m.wildcards {
+		if u.Scheme == w.scheme && u.Port() == w.port &&
+			strings.HasSuffix(host, "."+w.suffix) && len(host) > len(w.suffix)+1 {
+			return true
+		}
+	}
+	return false
+}
+
+// CORSMiddleware отвечает на preflight-запросы и добавляет заголовки CORS для разрешенных источников.
+// Источники проверяет OriginMatcher, а не встроенное сравнение Fiber, которое для *.tunduck.kg
+// пропустило бы и eviltunduck.kg. Ответ зависит от Origin (Vary: Origin), кроме режима "*".
+func CORSMiddleware(cfg CORSConfig) (fiber.Handler, error) {
+	matcher, err := ParseOrigins(cfg.AllowOrigins)
+	if err != nil {
+		return nil, err
+	}
+	if matcher.Any() && cfg.AllowCredentials {
+		return nil, ErrCORSWildcardCredentials
+	}
+
+	corsCfg := cors.Config{
+		AllowMethods:     strings.Join(corsMethods, ","),
+		AllowHeaders:     strings.Join(cfg.AllowHeaders, ","),
+		ExposeHeaders:    strings.Join(cfg.ExposeHeaders, ","),
+		AllowCredentials: cfg.AllowCredentials,
+		MaxAge:           int(cfg.MaxAge / time.Second),
+	}
+	if matcher.Any() {
+		corsCfg.AllowOrigins = "*"
+	} else {
+		corsCfg.AllowOriginsFunc = matcher.Allows
+	}
+	return cors.New(corsCfg), nil
+}
@@ -0,0 +1,121 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOriginMatcher(t *testing.T) {
+	matcher, err := ParseOrigins([]string{"https://tunduck.kg", " https://*.tunduck.kg", "http://*.tunduck.local:5173", ""})
+	require.NoError(t, err)
+
+	tests := map[string]bool{
+		"https://tunduck.kg":              true,
+		"https://app.tunduck.kg":          true,
+		"https://a.b.tunduck.kg":          true,
+		"https://APP.Tunduck.kg":          true,
+		"http://admin.tunduck.local:5173": true,
+		"https://eviltunduck.kg":          false,
+		"https://tunduck.kg.evil.com":     false,
+		"http://app.tunduck.kg":           false,
+		"https://app.tunduck.kg:8443":     false,
+		"http://admin.tunduck.local:3000": false,
+		"https://.tunduck.kg":             false,
+		"null":                            false,
+	}
+	for origin, want := range tests {
+		assert.Equal(t, want, matcher.Allows(origin), origin)
+	}
+	assert.False(t, matcher.Any())
+}
+
+func TestParseOrigins_Invalid(t *testing.T) {
+	for _, origin := range []string{
+		"tunduck.kg",
+		"ftp://tunduck.kg",
+		"https://tunduck.kg/",
+		"https://tunduck.kg?x=1",
+		"https://*.kg",
+		"https://*tunduck.kg",
+		"https://app.*.tunduck.kg",
+		"https://*.*.tunduck.kg",
+	} {
+		_, err := ParseOrigins([]string{origin})
+		assert.Error(t, err, origin)
+	}
+
+	matcher, err := ParseOrigins([]string{"*"})
+	require.NoError(t, err)
+	assert.True(t, matcher.Any())
+	assert.True(t, matcher.Allows("https://example.com"))
+
+	_, err = CORSMiddleware(CORSConfig{AllowOrigins: []string{"*"}, AllowCredentials: true})
+	assert.ErrorIs(t, err, ErrCORSWildcardCredentials)
+}
+
+func TestCORSMiddleware(t *testing.T) {
+	handler, err := CORSMiddleware(CORSConfig{
+		AllowOrigins:     []string{"https://*.tunduck.kg"},
+		AllowHeaders:     DefaultCORSAllowHeaders,
+		ExposeHeaders:    DefaultCORSExposeHeaders,
+		AllowCredentials: true,
+		MaxAge:           DefaultCORSMaxAge,
+	})
+	require.NoError(t, err)
+
+	app := fiber.New()
+	app.Use(handler)
+	app.Get("/documents", func(c *fiber.Ctx) error { return c.SendString("ok") })
+
+	do := func(method, origin string) *http.Response {
+		req := httptest.NewRequest(method, "/documents", nil)
+		req.Header.Set(fiber.HeaderOrigin, origin)
+		if method == fiber.MethodOptions {
+			req.Header.Set(fiber.HeaderAccessControlRequestMethod, fiber.MethodGet)
+			req.Header.Set(fiber.HeaderAccessControlRequestHeaders, "authorization, x-org-id")
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	// Preflight кешируется браузером и разрешает запросы с учетными данными
+	resp := do(fiber.MethodOptions, "https://app.tunduck.kg")
+	assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+	assert.Equal(t, "https://app.tunduck.kg", resp.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+	assert.Equal(t, "true", resp.Header.Get(fiber.HeaderAccessControlAllowCredentials))
+	assert.Equal(t, "600", resp.Header.Get(fiber.HeaderAccessControlMaxAge))
+	assert.Contains(t, resp.Header.Get(fiber.HeaderAccessControlAllowHeaders), "X-Org-Id")
+	assert.Contains(t, resp.Header.Get(fiber.HeaderAccessControlAllowMethods), fiber.MethodPatch)
+	assert.Contains(t, resp.Header.Get(fiber.HeaderVary), fiber.HeaderOrigin)
+
+	resp = do(fiber.MethodGet, "https://app.tunduck.kg")
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "https://app.tunduck.kg", resp.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+	assert.Contains(t, resp.Header.Get(fiber.HeaderAccessControlExposeHeaders), "X-Request-ID")
+
+	// Домен, оканчивающийся на то же имя, не является поддоменом
+	resp = do(fiber.MethodOptions, "https://eviltunduck.kg")
+	assert.Empty(t, resp.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+	assert.Empty(t, resp.Header.Get(fiber.HeaderAccessControlAllowCredentials))
+}
+
+func TestCORSMiddleware_NoMaxAge(t *testing.T) {
+	handler, err := CORSMiddleware(CORSConfig{AllowOrigins: []string{"*"}, MaxAge: 0})
+	require.NoError(t, err)
+
+	app := fiber.New()
+	app.Use(handler)
+	req := httptest.NewRequest(fiber.MethodOptions, "/", nil)
+	req.Header.Set(fiber.HeaderOrigin, "https://example.com")
+	req.Header.Set(fiber.HeaderAccessControlRequestMethod, fiber.MethodGet)
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, "*", resp.Header.Get(fiber.HeaderAccessControlAllowOrigin))
+	assert.Empty(t, resp.Header.Get(fiber.HeaderAccessControlMaxAge))
+}
@@ -1,8 +1,11 @@
 package middleware
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/response"
 	"github.com/sirupsen/logrus"
 )
 
@@ -18,131 +21,65 @@ func ErrorHandlingMiddleware(logger *logrus.Logger) fiber.Handler {
 		}
 
 		// Получаем requestID из контекста для логирования
-		requestID := c.Get("X-Request-ID")
+		requestID := response.RequestID(c)
 
-		// Преобразуем ошибку в AppError если это возможно
-		var appErr *apperror.AppError
-		var httpStatus int
-		var response fiber.Map
+		// Преобразуем ошибку в AppError
+		appErr := toAppError(err)
 
-		// Проверяем, является ли ошибка AppError
-		if ae, ok := err.(*apperror.AppError); ok {
-			appErr = ae
-			httpStatus = ae.HTTPStatus
+		fields := logrus.Fields{
+			"request_id": requestID,
+			"method":     c.Method(),
+			"path":       c.Path(),
+			"status":     appErr.HTTPStatus,
+			"error_code": appErr.Code,
+		}
 
+		if appErr.Err == nil {
 			// Логируем ошибку приложения
-			logger.WithFields(logrus.Fields{
-				"request_id": requestID,
-				"method":     c.Method(),
-				"path":       c.Path(),
-				"status":     httpStatus,
-				"error_code": appErr.Code,
-				"message":    appErr.Message,
-				"details":    appErr.Details,
-			}).Warn("Application error occurred")
-
-			response = fiber.Map{
-				"error":      appErr.Code,
-				"message":    appErr.Message,
-				"request_id": requestID,
-			}
-
-			// Добавляем детали если они есть
-			if appErr.Details != "" {
-				response["details"] = appErr.Details
-			}
-
-		} else if fe, ok := err.(*fiber.Error); ok {
-			// Обработка Fiber ошибок
-			httpStatus = fe.Code
-			logger.WithFields(logrus.Fields{
-				"request_id": requestID,
-				"method":     c.Method(),
-				"path":       c.Path(),
-				"status":     httpStatus,
-				"message":    fe.Message,
-			}).Warn("HTTP error occurred")
-
-			response = fiber.Map{
-				"error":      "HTTP_ERROR",
-				"message":    fe.Message,
-				"request_id": requestID,
-			}
-
+			fields["message"] = appErr.Message
+			fields["details"] = appErr.Details
+			logger.WithFields(fields).Warn("Application error occurred")
 		} else {
-			// Обработка неизвестных ошибок
-			httpStatus = fiber.StatusInternalServerError
-			logger.WithFields(logrus.Fields{
-				"request_id": requestID,
-				"method":     c.Method(),
-				"path":       c.Path(),
-				"status":     httpStatus,
-				"error":      err.Error(),
-			}).Error("Unknown error occurred")
-
-			response = fiber.Map{
-				"error":      "INTERNAL_SERVER_ERROR",
-				"message":    "An unexpected error occurred",
-				"request_id": requestID,
-			}
+			// Логируем ошибку с оригинальной причиной
+			fields["error"] = appErr.Err.Error()
+			logger.WithFields(fields).Error("Request failed with error")
 		}
 
-		// Отправляем ошибку в формате JSON
-		return c.Status(httpStatus).JSON(response)
+		// Отправляем ошибку в едином формате
+		return response.Error(c, appErr)
 	}
 }
 
 // GlobalErrorHandler обрабатывает паники и необработанные ошибки на уровне приложения
 func GlobalErrorHandler(logger *logrus.Logger) func(*fiber.Ctx, error) error {
 	return func(c *fiber.Ctx, err error) error {
-		code := fiber.StatusInternalServerError
-		message := "Internal Server Error"
-
-		requestID := c.Get("X-Request-ID")
-
-		// Пытаемся извлечь AppError
-		if appErr, ok := err.(*apperror.AppError); ok {
-			code = appErr.HTTPStatus
-			message = appErr.Message
-
-			logger.WithFields(logrus.Fields{
-				"request_id": requestID,
-				"method":     c.Method(),
-				"path":       c.Path(),
-				"status":     code,
-				"error_code": appErr.Code,
-			}).Error("Global error handler caught application error")
-
-		} else if fe, ok := err.(*fiber.Error); ok {
-			code = fe.Code
-			message = fe.Message
-
-			logger.WithFields(logrus.Fields{
-				"request_id": requestID,
-				"method":     c.Method(),
-				"path":       c.Path(),
-				"status":     code,
-			}).Warn("Global error handler caught Fiber error")
-
-		} else {
-			logger.WithFields(logrus.Fields{
-				"request_id": requestID,
-				"method":     c.Method(),
-				"path":       c.Path(),
-				"status":     code,
-				"error":      err.Error(),
-			}).Error("Global error handler caught unknown error")
-		}
+		appErr := toAppError(err)
+
+		logger.WithFields(logrus.Fields{
+			"request_id": response.RequestID(c),
+			"method":     c.Method(),
+			"path":       c.Path(),
+			"status":     appErr.HTTPStatus,
+			"error_code": appErr.Code,
+			"error":      err.Error(),
+		}).Error("Global error handler caught error")
+
+		return response.Error(c, appErr)
+	}
+}
 
-		// Не отправляем ответ, если заголовки уже отправлены
-		if c.Response().StatusCode() != 0 {
-			return nil
-		}
+// toAppError приводит ошибку обработчика к AppError.
+// Ошибки Fiber сохраняют свой HTTP статус, неизвестные ошибки скрываются за 500.
+func toAppError(err error) *apperror.AppError {
+	var appErr *apperror.AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
 
-		return c.Status(code).JSON(fiber.Map{
-			"error":      "ERROR",
-			"message":    message,
-			"request_id": requestID,
-		})
+	var fe *fiber.Error
+	if errors.As(err, &fe) {
+		return apperror.New(apperror.ErrHTTP, fe.Message).WithHTTPStatus(fe.Code)
 	}
+
+	return apperror.New(apperror.ErrInternal, "An unexpected error occurred").WithError(err)
 }
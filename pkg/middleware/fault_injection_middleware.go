@@ -0,0 +1,205 @@
+package middleware
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+)
+
+// HeaderFaultInjected отмечает ответ, к которому применен сбой (latency, error)
+const HeaderFaultInjected = "X-Fault-Injected"
+
+// FaultKind вид внедряемого сбоя
+type FaultKind string
+
+const (
+	// FaultLatency задерживает запрос перед обработкой
+	FaultLatency FaultKind = "latency"
+	// FaultError отвечает ошибкой вместо обработки запроса
+	FaultError FaultKind = "error"
+	// FaultDrop обрабатывает запрос, но закрывает соединение без ответа: клиент не знает,
+	// выполнен ли запрос, и должен повторить его безопасно
+	FaultDrop FaultKind = "drop"
+)
+
+// FaultRule правило внедрения сбоя на маршрутах
+type FaultRule struct {
+	// Method HTTP-метод; пустой — любой
+	Method string
+	// Path путь запроса; с * в конце — префикс пути
+	Path string
+	Kind FaultKind
+	// Latency задержка для FaultLatency
+	Latency time.Duration
+	// Status код ответа для FaultError
+	Status int
+	// Rate доля запросов (0..1), к которым применяется сбой
+	Rate float64
+}
+
+// Matches сообщает, относится ли правило к запросу
+func (r FaultRule) Matches(method, path string) bool {
+	if r.Method != "" && r.Method != method {
+		return false
+	}
+	if prefix, ok := strings.CutSuffix(r.Path, "*"); ok {
+		return strings.HasPrefix(path, prefix)
+	}
+	return path == r.Path
+}
+
+// String возвращает правило в формате FAULT_INJECTION_RULES
+func (r FaultRule) String() string {
+	var b strings.Builder
+	if r.Method != "" {
+		b.WriteString(r.Method + " ")
+	}
+	b.WriteString(r.Path + " " + string(r.Kind))
+	switch r.Kind {
+	case FaultLatency:
+		b.WriteString(":" + r.Latency.String())
+	case FaultError:
+		b.WriteString(":" + strconv.Itoa(r.Status))
+	}
+	b.WriteString(" " + strconv.FormatFloat(r.Rate*100, 'f', -1, 64) + "%")
+	return b.String()
+}
+
+// ParseFaultRules разбирает правила через ";" вида "[METHOD] PATH KIND[:VALUE] PERCENT%":
+//
+//	POST /api/esf-documents error:503 10%; GET /api/esf-documents/* latency:2s 50%; * /api/reports/* drop 5%
+//
+// Метод * или его отсутствие — любой метод. error без кода отвечает 503.
+func ParseFaultRules(raw string) ([]FaultRule, error) {
+	var rules []FaultRule
+	for _, item := range strings.Split(raw, ";") {
+		fields := strings.Fields(item)
+		if len(fields) == 0 {
+			continue
+		}
+		rule, err := parseFaultRule(fields)
+		if err != nil {
+			return nil, fmt.Errorf("invalid fault rule %q: %w", strings.TrimSpace(item), err)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func parseFaultRule(fields []string) (FaultRule, error) {
+	var rule FaultRule
+	switch len(fields) {
+	case 3:
+	case 4:
+		if method := strings.ToUpper(fields[0]); method != "*" {
+			rule.Method = method
+		}
+		fields = fields[1:]
+	default:
+		return rule, fmt.Errorf("expected [METHOD] PATH KIND[:VALUE] PERCENT%%")
+	}
+
+	rule.Path = fields[0]
+	if !strings.HasPrefix(rule.Path, "/") {
+		return rule, fmt.Errorf("path must start with /")
+	}
+
+	kind, value, _ := strings.Cut(fields[1], ":")
+	rule.Kind = FaultKind(strings.ToLower(kind))
+	switch rule.Kind {
+	case FaultLatency:
+		latency, err := time.ParseDuration(value)
+		if err != nil || latency <= 0 {
+			return rule, fmt.Errorf("latency must be a positive duration, got %q", value)
+		}
+		rule.Latency = latency
+	case FaultError:
+		rule.Status = http.StatusServiceUnavailable
+		if value != "" {
+			status, err := strconv.Atoi(value)
+			if err != nil || status < 400 || status > 599 {
+				return rule, fmt.Errorf("error status must be 4xx or 5xx, got %q", value)
+			}
+			rule.Status = status
+		}
+	case FaultDrop:
+		if value != "" {
+			return rule, fmt.Errorf("drop takes no value")
+		}
+	default:
+		return rule, fmt.Errorf("unknown fault %q, expected latency, error or drop", kind)
+	}
+
+	percent, ok := strings.CutSuffix(fields[2], "%")
+	rate, err := strconv.ParseFloat(percent, 64)
+	if !ok || err != nil || rate <= 0 || rate > 100 {
+		return rule, fmt.Errorf("rate must be a percentage from 0 to 100, got %q", fields[2])
+	}
+	rule.Rate = rate / 100
+	return rule, nil
+}
+
+// FaultInjectionConfig параметры внедрения сбоев
+type FaultInjectionConfig struct {
+	// Enabled включает внедрение; в продакшене запрещено проверкой конфигурации
+	Enabled bool
+	Rules   []FaultRule
+	// Rand источник случайных чисел [0, 1); по умолчанию rand.Float64
+	Rand func() float64
+}
+
+// FaultInjectionMiddleware внедряет сбои по правилам, чтобы фронтенд и логику повторов можно было
+// проверить на стенде: задержку перед обработкой, ответ с ошибкой FAULT_INJECTED вместо обработки
+// или обрыв соединения после обработки. Правила проверяются по порядку; каждое подходящее
+// срабатывает с вероятностью Rate независимо от остальных.
+func FaultInjectionMiddleware(cfg FaultInjectionConfig, log *logrus.Logger) fiber.Handler {
+	random := cfg.Rand
+	if random == nil {
+		random = rand.Float64
+	}
+
+	return func(c *fiber.Ctx) error {
+		drop := false
+		for _, rule := range cfg.Rules {
+			if !rule.Matches(c.Method(), c.Path()) || random() >= rule.Rate {
+				continue
+			}
+
+			log.WithFields(logrus.Fields{
+				"request_id": response.RequestID(c),
+				"method":     c.Method(),
+				"path":       c.Path(),
+				"rule":       rule.String(),
+			}).Info("Fault injected")
+
+			switch rule.Kind {
+			case FaultLatency:
+				c.Append(HeaderFaultInjected, string(FaultLatency))
+				time.Sleep(rule.Latency)
+			case FaultError:
+				c.Append(HeaderFaultInjected, string(FaultError))
+				return response.Error(c, apperror.New(apperror.ErrFaultInjected, "injected failure for testing").WithHTTPStatus(rule.Status))
+			case FaultDrop:
+				drop = true
+			}
+		}
+
+		err := c.Next()
+		if drop {
+			// Ответ не отправляется, соединение закрывается после возврата обработчика
+			c.Context().HijackSetNoResponse(true)
+			c.Context().Hijack(func(net.Conn) {})
+		}
+		return err
+	}
+}
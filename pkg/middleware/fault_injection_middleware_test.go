@@ -0,0 +1,94 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFaultRules(t *testing.T) {
+	rules, err := ParseFaultRules("POST /api/esf-documents error:502 10%; get /api/esf-documents/* latency:150ms 50% ;* /api/reports/* drop 100%;/health error 1.5%")
+	require.NoError(t, err)
+	assert.Equal(t, []FaultRule{
+		{Method: "POST", Path: "/api/esf-documents", Kind: FaultError, Status: 502, Rate: 0.1},
+		{Method: "GET", Path: "/api/esf-documents/*", Kind: FaultLatency, Latency: 150 * time.Millisecond, Rate: 0.5},
+		{Path: "/api/reports/*", Kind: FaultDrop, Rate: 1},
+		{Path: "/health", Kind: FaultError, Status: 503, Rate: 0.015},
+	}, rules)
+	assert.Equal(t, "GET /api/esf-documents/* latency:150ms 50%", rules[1].String())
+
+	empty, err := ParseFaultRules(" ; ")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	for _, raw := range []string{
+		"/api error 10",
+		"/api error:200 10%",
+		"/api latency 10%",
+		"/api drop:1 10%",
+		"/api timeout 10%",
+		"/api error 0%",
+		"/api error 150%",
+		"GET api error 10%",
+		"GET /api error",
+	} {
+		_, err := ParseFaultRules(raw)
+		assert.Error(t, err, raw)
+	}
+}
+
+func TestFaultInjectionMiddleware(t *testing.T) {
+	rules, err := ParseFaultRules("POST /documents error:502 50%; GET /documents/* latency:20ms 50%; DELETE /documents/* drop 100%")
+	require.NoError(t, err)
+
+	roll := 0.0
+	handled := 0
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+
+	app := fiber.New()
+	app.Use(FaultInjectionMiddleware(FaultInjectionConfig{Enabled: true, Rules: rules, Rand: func() float64 { return roll }}, log))
+	ok := func(c *fiber.Ctx) error {
+		handled++
+		return c.SendStatus(fiber.StatusOK)
+	}
+	app.Post("/documents", ok)
+	app.Get("/documents/:id", ok)
+	app.Delete("/documents/:id", ok)
+
+	// Выпало значение выше доли: сбой не применяется
+	roll = 0.7
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodPost, "/documents", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get(HeaderFaultInjected))
+	assert.Equal(t, 1, handled)
+
+	roll = 0.2
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodPost, "/documents", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusBadGateway, resp.StatusCode)
+	assert.Equal(t, "error", resp.Header.Get(HeaderFaultInjected))
+	body, _ := io.ReadAll(resp.Body)
+	assert.Contains(t, string(body), "FAULT_INJECTED")
+	assert.Equal(t, 1, handled, "handler must not run on injected error")
+
+	start := time.Now()
+	resp, err = app.Test(httptest.NewRequest(fiber.MethodGet, "/documents/1", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "latency", resp.Header.Get(HeaderFaultInjected))
+	assert.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	assert.Equal(t, 2, handled)
+
+	// Запрос обработан, но ответ не получен
+	_, err = app.Test(httptest.NewRequest(fiber.MethodDelete, "/documents/1", nil))
+	assert.Error(t, err)
+	assert.Equal(t, 3, handled)
+}
@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+	"github.com/rusgainew/tunduck-app/pkg/signature"
+	"github.com/sirupsen/logrus"
+)
+
+// APIKeyLocalsKey ключ Locals с ID проверенного ключа API
+const APIKeyLocalsKey = "api_key_id"
+
+// HMACSignatureMiddleware проверяет подпись запросов клиентов с ключом API.
+// Запросы без заголовка X-API-Key пропускаются без изменений.
+// Подписанный запрос должен содержать X-Timestamp, X-Nonce и X-Signature;
+// повтор перехваченного запроса отклоняется по nonce.
+func HMACSignatureMiddleware(verifier *signature.Verifier, logger *logrus.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		keyID := c.Get(signature.HeaderAPIKey)
+		if keyID == "" {
+			return c.Next()
+		}
+
+		sig := c.Get(signature.HeaderSignature)
+		nonce := c.Get(signature.HeaderNonce)
+		timestamp := c.Get(signature.HeaderTimestamp)
+		if sig == "" || nonce == "" || timestamp == "" {
+			return response.Error(c, apperror.New(apperror.ErrUnauthorized, "signed request headers are required"))
+		}
+
+		err := verifier.Verify(c.UserContext(), keyID, sig, signature.Request{
+			Method:    c.Method(),
+			Path:      c.Path(),
+			Query:     string(c.Request().URI().QueryString()),
+			Timestamp: timestamp,
+			Nonce:     nonce,
+			Body:      c.Body(),
+		})
+		if err != nil {
+			logger.WithFields(logrus.Fields{
+				"request_id": response.RequestID(c),
+				"path":       c.Path(),
+				"api_key":    keyID,
+				"error":      err.Error(),
+			}).Warn("Signed request rejected")
+			return response.Error(c, signatureError(err))
+		}
+
+		c.Locals(APIKeyLocalsKey, keyID)
+		return c.Next()
+	}
+}
+
+// signatureError преобразует ошибку проверки подписи в AppError
+func signatureError(err error) *apperror.AppError {
+	switch {
+	case errors.Is(err, signature.ErrUnknownKey), errors.Is(err, signature.ErrInvalidSignature):
+		return apperror.New(apperror.ErrUnauthorized, "invalid request signature")
+	case errors.Is(err, signature.ErrTimestampSkew):
+		return apperror.New(apperror.ErrUnauthorized, "request timestamp is outside the allowed window")
+	case errors.Is(err, signature.ErrNonceReused):
+		return apperror.New(apperror.ErrUnauthorized, "request has already been processed")
+	default:
+		return apperror.New(apperror.ErrInternal, "failed to verify request signature").WithError(err)
+	}
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/rusgainew/tunduck-app/pkg/i18n"
+)
+
+// I18nMiddleware определяет язык запроса по заголовку Accept-Language
+// и сохраняет его в контексте для локализации сообщений об ошибках
+func I18nMiddleware() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		lang := i18n.ParseAcceptLanguage(c.Get(fiber.HeaderAcceptLanguage))
+
+		c.Locals(i18n.LocalsKey, lang)
+		c.Set(fiber.HeaderContentLanguage, string(lang))
+		c.Vary(fiber.HeaderAcceptLanguage)
+
+		return c.Next()
+	}
+}
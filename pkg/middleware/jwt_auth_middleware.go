@@ -8,22 +8,24 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/auth"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
 	"github.com/rusgainew/tunduck-app/pkg/response"
 	"github.com/sirupsen/logrus"
 )
 
-// JWTAuthMiddleware создает улучшенный middleware для проверки JWT токенов
+// JWTAuthMiddleware создает улучшенный middleware для проверки JWT токенов ключами keys
 // с логированием и правильной обработкой ошибок
-func JWTAuthMiddleware(secret string, logger *logrus.Logger) fiber.Handler {
-	if secret == "" {
-		logger.Fatal("JWT_SECRET is required for JWT middleware")
+func JWTAuthMiddleware(keys *auth.KeyRing, logger *logrus.Logger) fiber.Handler {
+	if keys == nil {
+		logger.Fatal("JWT signing keys are required for JWT middleware")
 		return func(c *fiber.Ctx) error {
 			return response.Error(c, apperror.New(apperror.ErrInternal, "JWT middleware configuration error"))
 		}
 	}
 
 	return jwtware.New(jwtware.Config{
-		SigningKey: jwtware.SigningKey{Key: []byte(secret)},
+		KeyFunc: keys.Keyfunc,
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
 			requestID := c.Get("X-Request-ID")
 
@@ -37,15 +39,25 @@ func JWTAuthMiddleware(secret string, logger *logrus.Logger) fiber.Handler {
 			appErr := apperror.New(apperror.ErrInvalidToken, "Invalid or expired JWT token")
 			return response.Error(c, appErr)
 		},
-		ContextKey: "user",
+		SuccessHandler: bindUser,
+		ContextKey:     "user",
 	})
 }
 
+// bindUser сохраняет ID пользователя из проверенного токена в Locals("user_id"): его добавляет в записи
+// pkg/logger (logger.UserIDKey) и использует RateLimitAuthMiddleware
+func bindUser(c *fiber.Ctx) error {
+	if userID, err := GetUserIDFromContext(c); err == nil {
+		c.Locals(string(logger.UserIDKey), userID.String())
+	}
+	return c.Next()
+}
+
 // JWTOptionalMiddleware создает опциональный JWT middleware
 // Если токен присутствует - валидирует его, если нет - продолжает выполнение
-func JWTOptionalMiddleware(secret string, logger *logrus.Logger) fiber.Handler {
-	if secret == "" {
-		logger.Fatal("JWT_SECRET is required for JWT middleware")
+func JWTOptionalMiddleware(keys *auth.KeyRing, logger *logrus.Logger) fiber.Handler {
+	if keys == nil {
+		logger.Fatal("JWT signing keys are required for JWT middleware")
 		return func(c *fiber.Ctx) error {
 			return response.Error(c, apperror.New(apperror.ErrInternal, "JWT middleware configuration error"))
 		}
@@ -66,7 +78,7 @@ func JWTOptionalMiddleware(secret string, logger *logrus.Logger) fiber.Handler {
 
 		// Если заголовок есть, валидируем токен
 		config := jwtware.Config{
-			SigningKey: jwtware.SigningKey{Key: []byte(secret)},
+			KeyFunc: keys.Keyfunc,
 			ErrorHandler: func(c *fiber.Ctx, err error) error {
 				logger.WithFields(logrus.Fields{
 					"request_id": requestID,
@@ -78,7 +90,8 @@ func JWTOptionalMiddleware(secret string, logger *logrus.Logger) fiber.Handler {
 				appErr := apperror.New(apperror.ErrInvalidToken, "Invalid or expired JWT token")
 				return response.Error(c, appErr)
 			},
-			ContextKey: "user",
+			SuccessHandler: bindUser,
+			ContextKey:     "user",
 		}
 
 		handler := jwtware.New(config)
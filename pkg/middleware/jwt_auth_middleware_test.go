@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+)
+
+func TestBindUser_LoggerContext(t *testing.T) {
+	var buf bytes.Buffer
+	base := logrus.New()
+	base.SetOutput(&buf)
+	base.SetFormatter(&logrus.JSONFormatter{})
+	log := logger.New(base)
+
+	const userID = "7c1e4d3a-2f5b-4a8e-9c6d-1b2a3c4d5e6f"
+	app := fiber.New()
+	app.Use(RequestIDMiddleware())
+	app.Get("/", func(c *fiber.Ctx) error {
+		// Имитация проверенного JWT
+		c.Locals("user", &jwt.Token{Claims: jwt.MapClaims{"user_id": userID}})
+		return bindUser(c)
+	}, func(c *fiber.Ctx) error {
+		log.Info(c.Context(), "Handled")
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+
+	req := httptest.NewRequest(fiber.MethodGet, "/", nil)
+	req.Header.Set("X-Request-ID", "req-42")
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+
+	var entry map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+	assert.Equal(t, "req-42", entry["request_id"])
+	assert.Equal(t, userID, entry["user_id"])
+}
@@ -10,15 +10,17 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/auth"
 	"github.com/rusgainew/tunduck-app/pkg/cache"
 	"github.com/rusgainew/tunduck-app/pkg/response"
 	"github.com/sirupsen/logrus"
 )
 
-// JWTBlacklistMiddleware создает JWT middleware с поддержкой blacklist для logout
-func JWTBlacklistMiddleware(secret string, logger *logrus.Logger, cacheManager cache.CacheManager) fiber.Handler {
-	if secret == "" {
-		logger.Fatal("JWT_SECRET is required for JWT middleware")
+// JWTBlacklistMiddleware создает JWT middleware с поддержкой blacklist для logout;
+// токены проверяются ключами keys
+func JWTBlacklistMiddleware(keys *auth.KeyRing, logger *logrus.Logger, cacheManager cache.CacheManager) fiber.Handler {
+	if keys == nil {
+		logger.Fatal("JWT signing keys are required for JWT middleware")
 		return func(c *fiber.Ctx) error {
 			return response.Error(c, apperror.New(apperror.ErrInternal, "JWT middleware configuration error"))
 		}
@@ -51,16 +53,17 @@ func JWTBlacklistMiddleware(secret string, logger *logrus.Logger, cacheManager c
 
 		// Парсим токен с валидацией подписи
 		// Важно: используем jwt.MapClaims{} без указателя для совместимости с GetUserIDFromContext
-		parsedToken, err := jwt.ParseWithClaims(token, jwt.MapClaims{}, func(t *jwt.Token) (interface{}, error) {
-			return []byte(secret), nil
-		})
+		parsedToken, err := keys.Parse(token, jwt.MapClaims{})
 
 		if err != nil || !parsedToken.Valid {
-			logger.WithFields(logrus.Fields{
+			fields := logrus.Fields{
 				"request_id": requestID,
 				"path":       c.Path(),
-				"error":      err.Error(),
-			}).Warn("JWT validation failed")
+			}
+			if err != nil {
+				fields["error"] = err.Error()
+			}
+			logger.WithFields(fields).Warn("JWT validation failed")
 			return response.Error(c, apperror.New(apperror.ErrInvalidToken, "Invalid or expired JWT token"))
 		}
 
@@ -90,7 +93,7 @@ func JWTBlacklistMiddleware(secret string, logger *logrus.Logger, cacheManager c
 		c.Locals("token", token)
 		c.Locals("token_hash", tokenHash)
 
-		return c.Next()
+		return bindUser(c)
 	}
 }
 
@@ -145,8 +148,6 @@ func CreateLogoutHandler(cacheManager cache.CacheManager, logger *logrus.Logger)
 			"user_id":    (*userClaims)["user_id"],
 		}).Info("User logged out successfully")
 
-		return c.JSON(map[string]string{
-			"message": "Logged out successfully",
-		})
+		return response.SuccessOK(c, "Logged out successfully", nil)
 	}
 }
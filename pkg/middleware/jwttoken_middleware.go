@@ -1,35 +1,57 @@
 package middleware
 
 import (
-	"os"
+	"errors"
 
 	jwtware "github.com/gofiber/contrib/jwt"
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/auth"
+	"github.com/rusgainew/tunduck-app/pkg/response"
 )
 
-// JWTMiddleware создает middleware для проверки JWT токенов
+// JWTMiddleware создает middleware для проверки JWT токенов ключами из JWT_SECRET и JWT_KEYS
 func JWTMiddleware() fiber.Handler {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
+	keys, failed := envKeyRing()
+	if failed != nil {
 		// Возвращаем middleware который всегда возвращает ошибку
-		return func(c *fiber.Ctx) error {
-			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-				"error":   "Configuration Error",
-				"message": "JWT_SECRET environment variable is not set",
-			})
-		}
+		return failed
 	}
 
 	return jwtware.New(jwtware.Config{
-		SigningKey: jwtware.SigningKey{Key: []byte(secret)},
+		KeyFunc: keys.Keyfunc,
 		ErrorHandler: func(c *fiber.Ctx, err error) error {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"error":   "Unauthorized",
-				"message": "Invalid or expired JWT",
-			})
+			return response.Error(c, apperror.New(apperror.ErrInvalidToken, "Invalid or expired JWT"))
 		},
-		ContextKey: "user",
+		SuccessHandler: bindUser,
+		ContextKey:     "user",
+	})
+}
+
+// JWTQueryMiddleware как JWTMiddleware, но принимает токен и из query-параметра param.
+// Нужен для WebSocket: браузер не может передать заголовок Authorization при рукопожатии.
+func JWTQueryMiddleware(param string) fiber.Handler {
+	return JWTLookupMiddleware("header:" + fiber.HeaderAuthorization + ",query:" + param)
+}
+
+// JWTLookupMiddleware как JWTMiddleware, но ищет токен в источниках lookup,
+// например "header:Authorization,query:token,cookie:docs_token"
+func JWTLookupMiddleware(lookup string) fiber.Handler {
+	keys, failed := envKeyRing()
+	if failed != nil {
+		return failed
+	}
+
+	return jwtware.New(jwtware.Config{
+		KeyFunc:     keys.Keyfunc,
+		TokenLookup: lookup,
+		AuthScheme:  "Bearer",
+		ErrorHandler: func(c *fiber.Ctx, err error) error {
+			return response.Error(c, apperror.New(apperror.ErrInvalidToken, "Invalid or expired JWT"))
+		},
+		SuccessHandler: bindUser,
+		ContextKey:     "user",
 	})
 }
 
@@ -42,8 +64,8 @@ func GetUserFromToken(c *fiber.Ctx) (*jwt.MapClaims, error) {
 
 // OptionalJWT создает опциональный JWT middleware (не требует токен)
 func OptionalJWT() fiber.Handler {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
+	keys, err := auth.KeyRingFromEnv()
+	if err != nil {
 		// Возвращаем middleware который всегда продолжает выполнение
 		return func(c *fiber.Ctx) error {
 			return c.Next()
@@ -52,15 +74,32 @@ func OptionalJWT() fiber.Handler {
 
 	return func(c *fiber.Ctx) error {
 		config := jwtware.Config{
-			SigningKey: jwtware.SigningKey{Key: []byte(secret)},
+			KeyFunc: keys.Keyfunc,
 			ErrorHandler: func(c *fiber.Ctx, err error) error {
 				// Игнорируем ошибки и продолжаем выполнение
 				return c.Next()
 			},
-			ContextKey: "user",
+			SuccessHandler: bindUser,
+			ContextKey:     "user",
 		}
 
 		handler := jwtware.New(config)
 		return handler(c)
 	}
 }
+
+// envKeyRing собирает ключи подписи из окружения; при ошибке вместо ключей
+// возвращает обработчик, который отвечает ошибкой конфигурации
+func envKeyRing() (*auth.KeyRing, fiber.Handler) {
+	keys, err := auth.KeyRingFromEnv()
+	if err == nil {
+		return keys, nil
+	}
+	msg := "JWT middleware configuration error"
+	if errors.Is(err, auth.ErrNoSigningKeys) {
+		msg = "JWT_SECRET environment variable is not set"
+	}
+	return nil, func(c *fiber.Ctx) error {
+		return response.Error(c, apperror.New(apperror.ErrConfigError, msg))
+	}
+}
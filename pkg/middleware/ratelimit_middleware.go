@@ -2,10 +2,13 @@ package middleware
 
 import (
 	"net"
+	"strconv"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
 	"github.com/rusgainew/tunduck-app/pkg/ratelimit"
+	"github.com/rusgainew/tunduck-app/pkg/response"
 	"github.com/sirupsen/logrus"
 )
 
@@ -28,12 +31,10 @@ func RateLimitMiddleware(rl *ratelimit.RateLimiter, category string, logger *log
 				"category": category,
 				"path":     c.Path(),
 			}).Warn("Rate limit exceeded")
+			rl.RecordRejection(category, c.Route().Path, ratelimit.PrincipalIP)
 
-			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
-				"error":   "Too Many Requests",
-				"message": "Rate limit exceeded. Please try again later.",
-				"reset":   resetTime.Unix(),
-			})
+			c.Set("X-RateLimit-Reset", strconv.FormatInt(resetTime.Unix(), 10))
+			return response.Error(c, apperror.New(apperror.ErrRateLimitExceeded, "Rate limit exceeded. Please try again later."))
 		}
 
 		return c.Next()
@@ -47,12 +48,14 @@ func RateLimitAuthMiddleware(rl *ratelimit.RateLimiter, category string, logger
 		// Try to get user ID from context (set by JWT middleware)
 		userID := c.Locals("user_id")
 		identifier := ""
+		principal := ratelimit.PrincipalUser
 
 		if userID != nil {
 			identifier = userID.(string)
 		} else {
 			// Fallback to IP if user not authenticated
 			identifier = getClientIP(c)
+			principal = ratelimit.PrincipalIP
 		}
 
 		// Check rate limit
@@ -67,12 +70,10 @@ func RateLimitAuthMiddleware(rl *ratelimit.RateLimiter, category string, logger
 				"category":   category,
 				"path":       c.Path(),
 			}).Warn("Rate limit exceeded")
+			rl.RecordRejection(category, c.Route().Path, principal)
 
-			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
-				"error":   "Too Many Requests",
-				"message": "Rate limit exceeded. Please try again later.",
-				"reset":   resetTime.Unix(),
-			})
+			c.Set("X-RateLimit-Reset", strconv.FormatInt(resetTime.Unix(), 10))
+			return response.Error(c, apperror.New(apperror.ErrRateLimitExceeded, "Rate limit exceeded. Please try again later."))
 		}
 
 		return c.Next()
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+)
+
+// HeaderWarning предупреждение о том, что ответ может быть неактуален (RFC 7234)
+const HeaderWarning = "Warning"
+
+// Значения Warning в режиме только для чтения: ответ из кеша и ответ, сформированный без основной БД
+const (
+	WarningStaleResponse = `110 - "Response is Stale"`
+	WarningReadOnly      = `199 - "Read-only mode: primary database is unavailable"`
+)
+
+// readOnlyRetryAfter подсказка клиенту, через сколько повторить запись
+const readOnlyRetryAfter = 30 * time.Second
+
+// readOnlyLocalsKey отмечает запросы, обработанные в режиме только для чтения
+const readOnlyLocalsKey = "read_only"
+
+// ReadOnlyState сообщает, запрещена ли запись (health.ReadOnlyMode)
+type ReadOnlyState interface {
+	Active() bool
+}
+
+// ReadOnlyMiddleware пока основная БД недоступна отклоняет изменяющие запросы с 503 READ_ONLY_MODE
+// и помечает чтение заголовком Warning. Кешированные ответы GET отдает ResponseCache.Handler,
+// в этом режиме игнорируя Cache-Control: no-cache клиента.
+func ReadOnlyMiddleware(state ReadOnlyState) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if state == nil || !state.Active() {
+			return c.Next()
+		}
+
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+		default:
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(readOnlyRetryAfter.Seconds())))
+			return response.Error(c, apperror.New(apperror.ErrReadOnlyMode, "service is in read-only mode, please retry later"))
+		}
+
+		c.Locals(readOnlyLocalsKey, true)
+		c.Append(HeaderWarning, WarningReadOnly)
+		return c.Next()
+	}
+}
+
+// IsReadOnly сообщает, обрабатывается ли запрос в режиме только для чтения
+func IsReadOnly(c *fiber.Ctx) bool {
+	readOnly, _ := c.Locals(readOnlyLocalsKey).(bool)
+	return readOnly
+}
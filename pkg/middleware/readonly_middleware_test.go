@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/pkg/cache"
+)
+
+type readOnlyFlag bool
+
+func (f *readOnlyFlag) Active() bool { return bool(*f) }
+
+func TestReadOnlyMiddleware(t *testing.T) {
+	readOnly := readOnlyFlag(false)
+	rc := newTestResponseCache()
+	calls := 0
+
+	app := fiber.New()
+	app.Use(ReadOnlyMiddleware(&readOnly))
+	app.Get("/reference", rc.Handler(StaticTags(cache.TagReference)), func(c *fiber.Ctx) error {
+		calls++
+		return c.JSON(fiber.Map{"calls": calls})
+	})
+	app.Post("/documents", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusCreated) })
+
+	do := func(method, path string, headers map[string]string) (int, fiber.Map, string) {
+		req := httptest.NewRequest(method, path, nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, fiber.Map{
+			HeaderWarning:          resp.Header.Get(HeaderWarning),
+			HeaderXCache:           resp.Header.Get(HeaderXCache),
+			fiber.HeaderRetryAfter: resp.Header.Get(fiber.HeaderRetryAfter),
+		}, string(body)
+	}
+
+	status, headers, _ := do(fiber.MethodGet, "/reference", nil)
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Empty(t, headers[HeaderWarning])
+	status, _, _ = do(fiber.MethodPost, "/documents", nil)
+	assert.Equal(t, fiber.StatusCreated, status)
+
+	readOnly = true
+
+	// Запись отклоняется с понятной ошибкой
+	status, headers, body := do(fiber.MethodPost, "/documents", nil)
+	assert.Equal(t, fiber.StatusServiceUnavailable, status)
+	assert.Equal(t, "30", headers[fiber.HeaderRetryAfter])
+	assert.Contains(t, body, "READ_ONLY_MODE")
+
+	// Кешированный ответ отдается даже при no-cache и помечается устаревшим
+	status, headers, body = do(fiber.MethodGet, "/reference", map[string]string{fiber.HeaderCacheControl: "no-cache"})
+	assert.Equal(t, fiber.StatusOK, status)
+	assert.Equal(t, "HIT", headers[HeaderXCache])
+	assert.Equal(t, WarningReadOnly+", "+WarningStaleResponse, headers[HeaderWarning])
+	assert.JSONEq(t, `{"calls":1}`, body)
+	assert.Equal(t, 1, calls)
+}
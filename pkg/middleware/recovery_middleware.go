@@ -1,35 +1,157 @@
 package middleware
 
 import (
-	"runtime/debug"
+	"context"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/metrics"
+	"github.com/rusgainew/tunduck-app/pkg/response"
 	"github.com/sirupsen/logrus"
 )
 
+// maxStackFrames ограничивает количество кадров стека в логе и отчете
+const maxStackFrames = 64
+
+// StackFrame описывает один кадр стека вызовов
+type StackFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// PanicReport содержит информацию о перехваченной панике
+type PanicReport struct {
+	Err         error
+	Method      string
+	Route       string
+	Path        string
+	RequestID   string
+	GoroutineID int
+	Stack       []StackFrame
+	// Release версия приложения, в которой произошла паника (release в Sentry)
+	Release string
+}
+
+// ErrorReporter отправляет информацию о паниках во внешнюю систему (Sentry и т.п.)
+type ErrorReporter interface {
+	ReportPanic(ctx context.Context, report PanicReport)
+}
+
+// RecoveryConfig настройки RecoveryMiddleware
+type RecoveryConfig struct {
+	Logger *logrus.Logger
+	// Metrics опционально, увеличивает panics_total по маршруту
+	Metrics *metrics.Metrics
+	// Reporter опционально, получает отчет о каждой панике
+	Reporter ErrorReporter
+	// Release опционально, передается в отчете и журнале (buildinfo.Info.Release)
+	Release string
+}
+
 // RecoveryMiddleware создает middleware для восстановления после паник
 // Перехватывает паники в обработчиках и возвращает ошибку 500
 func RecoveryMiddleware(logger *logrus.Logger) fiber.Handler {
+	return RecoveryMiddlewareWithConfig(RecoveryConfig{Logger: logger})
+}
+
+// RecoveryMiddlewareWithConfig создает middleware для восстановления после паник
+// с метриками и отправкой отчетов во внешнюю систему
+func RecoveryMiddlewareWithConfig(cfg RecoveryConfig) fiber.Handler {
 	return func(c *fiber.Ctx) (err error) {
 		defer func() {
-			if r := recover(); r != nil {
-				// Логируем паник с полной информацией
-				logger.WithFields(logrus.Fields{
-					"panic":       r,
-					"method":      c.Method(),
-					"path":        c.Path(),
-					"request_id":  c.Get("X-Request-ID"),
-					"stack_trace": string(debug.Stack()),
+			r := recover()
+			if r == nil {
+				return
+			}
+
+			report := PanicReport{
+				Err:         panicError(r),
+				Method:      c.Method(),
+				Route:       c.Route().Path,
+				Path:        c.Path(),
+				RequestID:   response.RequestID(c),
+				GoroutineID: goroutineID(),
+				Stack:       captureStack(),
+				Release:     cfg.Release,
+			}
+
+			// Логируем панику со структурированным стеком
+			if cfg.Logger != nil {
+				cfg.Logger.WithFields(logrus.Fields{
+					"panic":        report.Err.Error(),
+					"method":       report.Method,
+					"route":        report.Route,
+					"path":         report.Path,
+					"request_id":   report.RequestID,
+					"goroutine_id": report.GoroutineID,
+					"stack":        report.Stack,
+					"release":      report.Release,
 				}).Error("Panic recovered in request handler")
+			}
+
+			if cfg.Metrics != nil && cfg.Metrics.PanicsTotal != nil {
+				cfg.Metrics.PanicsTotal.WithLabelValues(report.Method, report.Route).Inc()
+			}
 
-				// Отправляем ошибку 500
-				err = c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-					"error":      "Internal server error",
-					"request_id": c.Get("X-Request-ID"),
-				})
+			if cfg.Reporter != nil {
+				cfg.Reporter.ReportPanic(c.UserContext(), report)
 			}
+
+			// Отправляем ошибку 500
+			err = response.Error(c, apperror.New(apperror.ErrInternal, "Internal server error").WithError(report.Err))
 		}()
 
 		return c.Next()
 	}
 }
+
+// panicError преобразует значение паники в error
+func panicError(r interface{}) error {
+	if err, ok := r.(error); ok {
+		return err
+	}
+	return fmt.Errorf("%v", r)
+}
+
+// captureStack возвращает стек вызовов горутины, начиная с места паники
+func captureStack() []StackFrame {
+	pcs := make([]uintptr, maxStackFrames)
+	// Пропускаем runtime.Callers, captureStack и отложенную функцию middleware
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+
+	stack := make([]StackFrame, 0, n)
+	for {
+		frame, more := frames.Next()
+		// Кадры runtime (gopanic и т.п.) не несут полезной информации
+		if !strings.HasPrefix(frame.Function, "runtime.") {
+			stack = append(stack, StackFrame{
+				Function: frame.Function,
+				File:     frame.File,
+				Line:     frame.Line,
+			})
+		}
+		if !more {
+			break
+		}
+	}
+	return stack
+}
+
+// goroutineID извлекает ID текущей горутины из заголовка стека ("goroutine 42 [running]:")
+func goroutineID() int {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	header := strings.TrimPrefix(string(buf), "goroutine ")
+	if idx := strings.IndexByte(header, ' '); idx > 0 {
+		if id, err := strconv.Atoi(header[:idx]); err == nil {
+			return id
+		}
+	}
+	return 0
+}
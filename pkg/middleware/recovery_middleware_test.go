@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/pkg/metrics"
+)
+
+type recordingReporter struct {
+	reports []PanicReport
+}
+
+func (r *recordingReporter) ReportPanic(_ context.Context, report PanicReport) {
+	r.reports = append(r.reports, report)
+}
+
+func TestRecoveryMiddleware(t *testing.T) {
+	logger, hook := test.NewNullLogger()
+	panics := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "panics_total"}, []string{"method", "route"})
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(panics)
+	reporter := &recordingReporter{}
+
+	app := fiber.New()
+	app.Use(RecoveryMiddlewareWithConfig(RecoveryConfig{
+		Logger:   logger,
+		Metrics:  &metrics.Metrics{PanicsTotal: panics},
+		Reporter: reporter,
+		Release:  "tunduck-api@1.4.0+0123456",
+	}))
+	app.Get("/items/:id", func(c *fiber.Ctx) error {
+		panic("boom")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/items/42", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+
+	// Лог содержит структурированный стек
+	entry := hook.LastEntry()
+	require.NotNil(t, entry)
+	assert.Equal(t, logrus.ErrorLevel, entry.Level)
+	assert.Equal(t, "boom", entry.Data["panic"])
+	assert.Equal(t, "/items/:id", entry.Data["route"])
+	stack, ok := entry.Data["stack"].([]StackFrame)
+	require.True(t, ok)
+	require.NotEmpty(t, stack)
+	assert.Contains(t, stack[0].Function, "TestRecoveryMiddleware")
+
+	// Метрика увеличена по шаблону маршрута
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+	require.Len(t, families[0].GetMetric(), 1)
+	assert.Equal(t, float64(1), families[0].GetMetric()[0].GetCounter().GetValue())
+
+	// Отчет отправлен
+	require.Len(t, reporter.reports, 1)
+	assert.EqualError(t, reporter.reports[0].Err, "boom")
+	assert.Equal(t, fiber.MethodGet, reporter.reports[0].Method)
+	assert.Equal(t, "tunduck-api@1.4.0+0123456", reporter.reports[0].Release)
+}
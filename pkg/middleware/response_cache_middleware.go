@@ -0,0 +1,218 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/pkg/cache"
+	"github.com/rusgainew/tunduck-app/pkg/i18n"
+)
+
+// Значения по умолчанию для кеширования ответов
+const (
+	DefaultResponseCacheTTL    = 5 * time.Minute
+	DefaultResponseCacheMaxAge = time.Minute
+)
+
+// HeaderXCache сообщает, отдан ли ответ из кеша (HIT), сформирован заново (MISS) или кеш пропущен (BYPASS)
+const HeaderXCache = "X-Cache"
+
+// responseCacheKeyPrefix префикс ключей кешированных ответов в общем кеше
+const responseCacheKeyPrefix = "http:"
+
+// anonymousPrincipal субъект запросов без JWT
+const anonymousPrincipal = "anonymous"
+
+// cachedHeaders заголовки ответа, сохраняемые вместе с телом
+var cachedHeaders = []string{fiber.HeaderContentType, fiber.HeaderETag, fiber.HeaderLastModified}
+
+// ResponseCacheConfig параметры кеширования ответов GET
+type ResponseCacheConfig struct {
+	// Enabled включает кеширование; выключенный кеш не создается, и маршруты работают без него
+	Enabled bool
+	// TTL срок хранения ответа в Redis; изменение данных удаляет его раньше по тегам
+	TTL time.Duration
+	// MaxAge срок, в течение которого клиент может повторно использовать ответ (Cache-Control: max-age)
+	MaxAge time.Duration
+}
+
+// ResponseTags возвращает теги, по которым инвалидируется кешированный ответ
+type ResponseTags func(c *fiber.Ctx) []string
+
+// StaticTags теги, одинаковые для всех ответов маршрута
+func StaticTags(tags ...string) ResponseTags {
+	return func(c *fiber.Ctx) []string { return tags }
+}
+
+// ResponseCache кеширует в Redis успешные ответы безопасных GET-запросов (справочники,
+// публичные данные организаций). Ключ строится из пути, отсортированных параметров запроса,
+// субъекта (пользователь из JWT или anonymous) и языка ответа, поэтому пользователи не получают
+// чужие ответы. Записи связываются с тегами кеша (cache.TagReference, cache.OrgTag), и изменения,
+// инвалидирующие теги в сервисах, сразу удаляют и кешированные ответы.
+//
+// Nil *ResponseCache допустим: маршруты работают без кеширования.
+type ResponseCache struct {
+	store   cache.Cache
+	manager cache.CacheManager
+	config  ResponseCacheConfig
+	logger  *logrus.Logger
+}
+
+// cachedResponse сохраненный ответ
+type cachedResponse struct {
+	Headers map[string]string `json:"headers"`
+	Body    []byte            `json:"body"`
+}
+
+// NewResponseCache создает кеш ответов поверх общего кеша менеджера
+func NewResponseCache(manager cache.CacheManager, cfg ResponseCacheConfig, log *logrus.Logger) *ResponseCache {
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultResponseCacheTTL
+	}
+	if cfg.MaxAge < 0 {
+		cfg.MaxAge = 0
+	}
+	return &ResponseCache{
+		store:   manager.Generic(),
+		manager: manager,
+		config:  cfg,
+		logger:  log,
+	}
+}
+
+// Handler кеширует ответы маршрута с тегами tags. Регистрируется после JWTMiddleware, чтобы
+// ответы разделялись по пользователям. Запрос с Cache-Control: no-cache обходит кеш и обновляет
+// запись, с no-store — не читает и не сохраняет ее. В режиме только для чтения (ReadOnlyMiddleware)
+// кешированный ответ отдается всегда, с заголовком Warning.
+func (rc *ResponseCache) Handler(tags ResponseTags) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if rc == nil || c.Method() != fiber.MethodGet {
+			return c.Next()
+		}
+
+		principal := anonymousPrincipal
+		if userID, err := GetUserIDFromContext(c); err == nil {
+			principal = userID.String()
+		}
+		key := rc.key(c, principal)
+
+		directives := strings.ToLower(c.Get(fiber.HeaderCacheControl))
+		noStore := strings.Contains(directives, "no-store")
+		// Без основной БД кешированный ответ отдается, даже если клиент просил свежий
+		readOnly := IsReadOnly(c)
+		if readOnly || (!noStore && !strings.Contains(directives, "no-cache")) {
+			if cached, ok := rc.lookup(c.Context(), key); ok {
+				rc.setHeaders(c, principal, "HIT")
+				if readOnly {
+					c.Append(HeaderWarning, WarningStaleResponse)
+				}
+				return rc.send(c, cached)
+			}
+		}
+
+		if err := c.Next(); err != nil {
+			return err
+		}
+
+		if c.Response().StatusCode() != fiber.StatusOK || !rc.cacheable(c) {
+			return nil
+		}
+		if noStore {
+			rc.setHeaders(c, principal, "BYPASS")
+			return nil
+		}
+
+		rc.setHeaders(c, principal, "MISS")
+		entry := cachedResponse{Headers: map[string]string{}, Body: append([]byte(nil), c.Response().Body()...)}
+		for _, header := range cachedHeaders {
+			if value := c.GetRespHeader(header); value != "" {
+				entry.Headers[header] = value
+			}
+		}
+		if err := rc.store.SetWithTags(c.Context(), key, entry, rc.config.TTL, tags(c)...); err != nil {
+			rc.logger.WithError(err).WithField("path", c.Path()).Warn("Failed to cache response")
+		}
+		return nil
+	}
+}
+
+// Invalidate удаляет кешированные ответы с тегами tags (и другие записи кеша с этими тегами)
+func (rc *ResponseCache) Invalidate(ctx context.Context, tags ...string) error {
+	if rc == nil {
+		return nil
+	}
+	return rc.manager.InvalidateTags(ctx, tags...)
+}
+
+// key хеширует путь, параметры запроса, субъект и язык ответа
+func (rc *ResponseCache) key(c *fiber.Ctx, principal string) string {
+	query, err := url.ParseQuery(string(c.Request().URI().QueryString()))
+	if err != nil {
+		query = url.Values{}
+	}
+
+	sum := sha256.Sum256([]byte(c.Path() + "?" + query.Encode() + "\n" + principal + "\n" + string(i18n.FromCtx(c))))
+	return responseCacheKeyPrefix + hex.EncodeToString(sum[:])
+}
+
+// lookup читает ответ из кеша; ошибки кеша не мешают обработке запроса
+func (rc *ResponseCache) lookup(ctx context.Context, key string) (*cachedResponse, bool) {
+	value, err := rc.store.Get(ctx, key)
+	if err != nil {
+		rc.logger.WithError(err).Warn("Failed to read cached response")
+		return nil, false
+	}
+	if value == nil {
+		return nil, false
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, false
+	}
+	var cached cachedResponse
+	if err := json.Unmarshal(raw, &cached); err != nil {
+		return nil, false
+	}
+	return &cached, true
+}
+
+// cacheable отсекает ответы, которые обработчик запретил хранить или которые устанавливают cookie
+func (rc *ResponseCache) cacheable(c *fiber.Ctx) bool {
+	if len(c.Response().Header.Peek(fiber.HeaderSetCookie)) > 0 {
+		return false
+	}
+	directives := strings.ToLower(c.GetRespHeader(fiber.HeaderCacheControl))
+	return !strings.Contains(directives, "no-store") && !strings.Contains(directives, "private")
+}
+
+// setHeaders задает Cache-Control и Vary: ответы пользователей — private, анонимные — public
+func (rc *ResponseCache) setHeaders(c *fiber.Ctx, principal, status string) {
+	visibility := "private"
+	if principal == anonymousPrincipal {
+		visibility = "public"
+	}
+	c.Set(fiber.HeaderCacheControl, visibility+", max-age="+strconv.Itoa(int(rc.config.MaxAge.Seconds())))
+	c.Vary(fiber.HeaderAuthorization, fiber.HeaderAcceptLanguage)
+	c.Set(HeaderXCache, status)
+}
+
+// send отдает кешированный ответ; совпавший If-None-Match отвечает 304 без тела
+func (rc *ResponseCache) send(c *fiber.Ctx, cached *cachedResponse) error {
+	for header, value := range cached.Headers {
+		c.Set(header, value)
+	}
+	if etag := cached.Headers[fiber.HeaderETag]; etag != "" && c.Get(fiber.HeaderIfNoneMatch) == etag {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+	return c.Status(fiber.StatusOK).Send(cached.Body)
+}
@@ -0,0 +1,168 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/pkg/cache"
+)
+
+// memoryCache хранит значения в памяти так же, как Redis: в виде JSON
+type memoryCache struct {
+	cache.Cache
+	values map[string][]byte
+	tags   map[string][]string
+}
+
+func (m *memoryCache) Get(ctx context.Context, key string) (interface{}, error) {
+	raw, ok := m.values[key]
+	if !ok {
+		return nil, nil
+	}
+	var value interface{}
+	err := json.Unmarshal(raw, &value)
+	return value, err
+}
+
+func (m *memoryCache) SetWithTags(ctx context.Context, key string, value interface{}, ttl time.Duration, tags ...string) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	m.values[key] = raw
+	for _, tag := range tags {
+		m.tags[tag] = append(m.tags[tag], key)
+	}
+	return nil
+}
+
+type memoryCacheManager struct {
+	cache.CacheManager
+	cache *memoryCache
+}
+
+func (m *memoryCacheManager) Generic() cache.Cache { return m.cache }
+
+func (m *memoryCacheManager) InvalidateTags(ctx context.Context, tags ...string) error {
+	for _, tag := range tags {
+		for _, key := range m.cache.tags[tag] {
+			delete(m.cache.values, key)
+		}
+		delete(m.cache.tags, tag)
+	}
+	return nil
+}
+
+func newTestResponseCache() *ResponseCache {
+	manager := &memoryCacheManager{cache: &memoryCache{values: map[string][]byte{}, tags: map[string][]string{}}}
+	return NewResponseCache(manager, ResponseCacheConfig{MaxAge: 30 * time.Second}, logrus.New())
+}
+
+func TestResponseCache(t *testing.T) {
+	rc := newTestResponseCache()
+	calls := 0
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		// Имитация JWTMiddleware: пользователь из заголовка X-User
+		if id := c.Get("X-User"); id != "" {
+			c.Locals("user", &jwt.Token{Claims: jwt.MapClaims{"user_id": id}})
+		}
+		return c.Next()
+	})
+	app.Get("/reference", rc.Handler(StaticTags(cache.TagReference)), func(c *fiber.Ctx) error {
+		calls++
+		c.Set(fiber.HeaderETag, `"1"`)
+		return c.JSON(fiber.Map{"calls": calls, "q": c.Query("q")})
+	})
+	app.Get("/missing", rc.Handler(StaticTags(cache.TagReference)), func(c *fiber.Ctx) error {
+		calls++
+		return c.SendStatus(fiber.StatusNotFound)
+	})
+
+	do := func(path string, headers map[string]string) (*http.Response, string) {
+		req := httptest.NewRequest(fiber.MethodGet, path, nil)
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp, string(body)
+	}
+
+	alice := map[string]string{"X-User": "7c1e4d3a-2f5b-4a8e-9c6d-1b2a3c4d5e6f"}
+	bob := map[string]string{"X-User": "0a9b8c7d-6e5f-4a3b-8c1d-0e9f8a7b6c5d"}
+
+	resp, body := do("/reference?q=a&lang=ru", alice)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Equal(t, "MISS", resp.Header.Get(HeaderXCache))
+	assert.Equal(t, "private, max-age=30", resp.Header.Get(fiber.HeaderCacheControl))
+	assert.Contains(t, resp.Header.Get(fiber.HeaderVary), fiber.HeaderAuthorization)
+	assert.JSONEq(t, `{"calls":1,"q":"a"}`, body)
+
+	// Порядок параметров не влияет на ключ
+	resp, body = do("/reference?lang=ru&q=a", alice)
+	assert.Equal(t, "HIT", resp.Header.Get(HeaderXCache))
+	assert.Equal(t, `"1"`, resp.Header.Get(fiber.HeaderETag))
+	assert.Equal(t, fiber.MIMEApplicationJSON, resp.Header.Get(fiber.HeaderContentType))
+	assert.JSONEq(t, `{"calls":1,"q":"a"}`, body)
+
+	resp, _ = do("/reference?lang=ru&q=a", map[string]string{"X-User": alice["X-User"], fiber.HeaderIfNoneMatch: `"1"`})
+	assert.Equal(t, fiber.StatusNotModified, resp.StatusCode)
+
+	// Другой пользователь и анонимный запрос получают свои записи
+	resp, body = do("/reference?q=a&lang=ru", bob)
+	assert.Equal(t, "MISS", resp.Header.Get(HeaderXCache))
+	assert.JSONEq(t, `{"calls":2,"q":"a"}`, body)
+
+	resp, _ = do("/reference?q=a&lang=ru", nil)
+	assert.Equal(t, "MISS", resp.Header.Get(HeaderXCache))
+	assert.Equal(t, "public, max-age=30", resp.Header.Get(fiber.HeaderCacheControl))
+
+	// no-cache обходит кеш и обновляет запись
+	resp, body = do("/reference?q=a&lang=ru", map[string]string{"X-User": alice["X-User"], fiber.HeaderCacheControl: "no-cache"})
+	assert.Equal(t, "MISS", resp.Header.Get(HeaderXCache))
+	assert.JSONEq(t, `{"calls":4,"q":"a"}`, body)
+	_, body = do("/reference?q=a&lang=ru", alice)
+	assert.JSONEq(t, `{"calls":4,"q":"a"}`, body)
+
+	// Инвалидация по тегу удаляет записи всех пользователей
+	require.NoError(t, rc.Invalidate(context.Background(), cache.TagReference))
+	resp, body = do("/reference?q=a&lang=ru", alice)
+	assert.Equal(t, "MISS", resp.Header.Get(HeaderXCache))
+	assert.JSONEq(t, `{"calls":5,"q":"a"}`, body)
+
+	// Ошибки не кешируются
+	do("/missing", alice)
+	resp, _ = do("/missing", alice)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get(HeaderXCache))
+	assert.Equal(t, 7, calls)
+}
+
+func TestResponseCache_Nil(t *testing.T) {
+	var rc *ResponseCache
+	app := fiber.New()
+	app.Get("/", rc.Handler(StaticTags(cache.TagReference)), func(c *fiber.Ctx) error {
+		return c.SendString("ok")
+	})
+
+	resp, err := app.Test(httptest.NewRequest(fiber.MethodGet, "/", nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get(HeaderXCache))
+	assert.NoError(t, rc.Invalidate(context.Background(), cache.TagReference))
+}
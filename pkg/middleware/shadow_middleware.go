@@ -0,0 +1,44 @@
+package middleware
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/rusgainew/tunduck-app/pkg/response"
+	"github.com/rusgainew/tunduck-app/pkg/shadow"
+)
+
+// ShadowMiddleware копирует запросы к маршрутам shadow.Config.Routes на вторую установку или в
+// хранилище вместе со статусом ответа основной версии. Копия ставится в очередь после обработки
+// запроса и не влияет на ответ клиенту; копии, пришедшие с заголовком X-Shadow-Request, не копируются.
+func ShadowMiddleware(s *shadow.Shadow) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if s == nil || c.Get(shadow.HeaderShadow) != "" || !s.Matches(c.Path(), len(c.Body())) {
+			return c.Next()
+		}
+
+		// fasthttp переиспользует буферы запроса после ответа, поэтому копия снимается заранее
+		req := shadow.Request{
+			Time:    time.Now().UTC(),
+			Method:  c.Method(),
+			URI:     strings.Clone(c.OriginalURL()),
+			Headers: make(map[string]string),
+			Body:    append([]byte(nil), c.Body()...),
+		}
+		c.Request().Header.VisitAll(func(key, value []byte) {
+			if name := string(key); !s.SkipHeader(name) {
+				req.Headers[name] = string(value)
+			}
+		})
+
+		err := c.Next()
+
+		req.RequestID = strings.Clone(response.RequestID(c))
+		req.Status = c.Response().StatusCode()
+		req.DurationMs = time.Since(req.Time).Milliseconds()
+		s.Capture(req)
+		return err
+	}
+}
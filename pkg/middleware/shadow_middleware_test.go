@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/pkg/shadow"
+)
+
+func TestShadowMiddleware(t *testing.T) {
+	var mu sync.Mutex
+	var mirrored []*http.Request
+	var bodies []string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		mirrored = append(mirrored, r)
+		bodies = append(bodies, string(body))
+		mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer target.Close()
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	s, err := shadow.New(shadow.Config{Mode: shadow.ModeMirror, TargetURL: target.URL, Registerer: prometheus.NewRegistry()}, nil, log)
+	require.NoError(t, err)
+	require.NoError(t, s.Start(context.Background()))
+	defer s.Stop(context.Background())
+
+	app := fiber.New()
+	app.Use(RequestIDMiddleware())
+	app.Use(ShadowMiddleware(s))
+	app.Post("/api/esf-documents", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusCreated) })
+	app.Post("/api/auth/login", func(c *fiber.Ctx) error { return c.SendStatus(fiber.StatusOK) })
+
+	send := func(path string, headers map[string]string) {
+		req := httptest.NewRequest(fiber.MethodPost, path, strings.NewReader(`{"comment":"shadow"}`))
+		req.Header.Set("X-Org-Id", "org-1")
+		for k, v := range headers {
+			req.Header.Set(k, v)
+		}
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		assert.Less(t, resp.StatusCode, 300)
+	}
+
+	send("/api/esf-documents?draft=1", map[string]string{"X-Request-ID": "req-1"})
+	// Не входит в маршруты теневого трафика
+	send("/api/auth/login", nil)
+	// Уже копия: повторно не копируется
+	send("/api/esf-documents", map[string]string{shadow.HeaderShadow: "1"})
+
+	require.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(mirrored) == 1
+	}, time.Second, 5*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, mirrored, 1)
+	assert.Equal(t, "/api/esf-documents?draft=1", mirrored[0].URL.RequestURI())
+	assert.Equal(t, "org-1", mirrored[0].Header.Get("X-Org-Id"))
+	assert.Equal(t, "req-1", mirrored[0].Header.Get("X-Request-ID"))
+	assert.Equal(t, "201", mirrored[0].Header.Get(shadow.HeaderPrimaryStatus))
+	assert.Equal(t, `{"comment":"shadow"}`, bodies[0])
+}
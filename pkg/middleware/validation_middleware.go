@@ -1,47 +1,25 @@
 package middleware
 
 import (
-	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+	"github.com/rusgainew/tunduck-app/pkg/validation"
 )
 
-var validate = validator.New()
-
-// ValidateStruct валидирует структуру используя теги valid
+// ValidateStruct валидирует структуру по тегам validate и valid.
+// Возвращает *apperror.AppError со списком ошибок по полям.
 func ValidateStruct(data interface{}) error {
-	return validate.Struct(data)
+	if appErr := validation.Struct(data); appErr != nil {
+		return appErr
+	}
+	return nil
 }
 
 // ValidationMiddleware создает middleware для автоматической валидации тела запроса
 func ValidationMiddleware(modelType interface{}) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		if err := c.BodyParser(modelType); err != nil {
-			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error":   "Bad Request",
-				"message": "Failed to parse request body",
-				"details": err.Error(),
-			})
-		}
-
-		if err := ValidateStruct(modelType); err != nil {
-			validationErrors := make([]string, 0)
-
-			if _, ok := err.(*validator.InvalidValidationError); ok {
-				return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-					"error":   "Internal Server Error",
-					"message": "Validation error",
-				})
-			}
-
-			for _, err := range err.(validator.ValidationErrors) {
-				validationErrors = append(validationErrors, err.Error())
-			}
-
-			return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
-				"error":   "Validation Failed",
-				"message": "Request validation failed",
-				"details": validationErrors,
-			})
+		if appErr := validation.ParseBody(c, modelType); appErr != nil {
+			return response.Error(c, appErr)
 		}
 
 		return c.Next()
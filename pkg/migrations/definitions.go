@@ -0,0 +1,297 @@
+package migrations
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// Main миграции основной БД (пользователи, организации)
+func Main() *Migrator {
+	return New(
+		Migration{
+			Version:     "0001",
+			Description: "create users and organizations",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&entity.User{}, &entity.EstOrganization{})
+			},
+		},
+		Migration{
+			Version:     "0002",
+			Description: "add organization version for optimistic locking",
+			Up: func(tx *gorm.DB) error {
+				return addColumnIfMissing(tx, &entity.EstOrganization{}, "Version")
+			},
+		},
+		Migration{
+			Version:     "0003",
+			Description: "create domain event outbox",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&entity.OutboxEvent{})
+			},
+		},
+		Migration{
+			Version:     "0004",
+			Description: "create email deliveries",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&entity.EmailDelivery{})
+			},
+		},
+		Migration{
+			Version:     "0005",
+			Description: "create audit log",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&entity.AuditLog{})
+			},
+		},
+		Migration{
+			Version:     "0006",
+			Description: "create export jobs",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&entity.ExportJob{})
+			},
+		},
+		Migration{
+			Version:     "0007",
+			Description: "create inbound events",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&entity.InboundEvent{})
+			},
+		},
+		Migration{
+			Version:     "0008",
+			Description: "create dead letters",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&entity.DeadLetter{})
+			},
+		},
+		Migration{
+			Version:     "0009",
+			Description: "create operations",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&entity.Operation{})
+			},
+		},
+		Migration{
+			Version:     "0010",
+			Description: "create reference codes",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&entity.ReferenceCode{})
+			},
+		},
+		Migration{
+			Version:     "0011",
+			Description: "create exchange rates",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&entity.ExchangeRate{})
+			},
+		},
+		Migration{
+			Version:     "0012",
+			Description: "create signing delegations",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&entity.SigningDelegation{})
+			},
+		},
+		Migration{
+			Version:     "0013",
+			Description: "create saved views",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&entity.SavedView{})
+			},
+		},
+		Migration{
+			Version:     "0014",
+			Description: "add organization plan",
+			Up: func(tx *gorm.DB) error {
+				return addColumnIfMissing(tx, &entity.EstOrganization{}, "Plan")
+			},
+		},
+		Migration{
+			Version:     "0015",
+			Description: "create usage records",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&entity.UsageRecord{})
+			},
+		},
+		Migration{
+			Version:     "0016",
+			Description: "create tenant migrations",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&entity.TenantMigration{})
+			},
+		},
+		Migration{
+			Version:     "0017",
+			Description: "create tenant db credentials",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&entity.TenantDBCredential{})
+			},
+		},
+		Migration{
+			Version:     "0018",
+			Description: "add organization timezone",
+			Up: func(tx *gorm.DB) error {
+				return addColumnIfMissing(tx, &entity.EstOrganization{}, "Timezone")
+			},
+		},
+		Migration{
+			Version:     "0019",
+			Description: "add organization tin",
+			Up: func(tx *gorm.DB) error {
+				return addColumnIfMissing(tx, &entity.EstOrganization{}, "Tin")
+			},
+		},
+	)
+}
+
+// Tenant миграции БД организации (документы ЭСФ)
+func Tenant() *Migrator {
+	return New(
+		Migration{
+			Version:     "0001",
+			Description: "create esf documents and entries",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&entity.EsfDocument{}, &entity.EsfEntries{})
+			},
+		},
+		Migration{
+			Version:     "0002",
+			Description: "add document version for optimistic locking",
+			Up: func(tx *gorm.DB) error {
+				return addColumnIfMissing(tx, &entity.EsfDocument{}, "Version")
+			},
+		},
+		Migration{
+			Version:     "0003",
+			Description: "create search outbox and full-text index",
+			Up: func(tx *gorm.DB) error {
+				if err := tx.AutoMigrate(&entity.SearchOutboxEntry{}); err != nil {
+					return err
+				}
+				return tx.Exec("CREATE INDEX IF NOT EXISTS idx_esf_documents_fts ON esf_documents USING GIN (" + DocumentSearchVector + ")").Error
+			},
+		},
+		Migration{
+			Version:     "0004",
+			Description: "create domain event outbox",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&entity.OutboxEvent{})
+			},
+		},
+		Migration{
+			Version:     "0005",
+			Description: "add document esf status",
+			Up: func(tx *gorm.DB) error {
+				if err := addColumnIfMissing(tx, &entity.EsfDocument{}, "EsfStatus"); err != nil {
+					return err
+				}
+				return tx.Exec("CREATE INDEX IF NOT EXISTS idx_esf_documents_esf_status ON esf_documents (esf_status)").Error
+			},
+		},
+		Migration{
+			Version:     "0006",
+			Description: "create payments and add document due date",
+			Up: func(tx *gorm.DB) error {
+				if err := tx.AutoMigrate(&entity.Payment{}); err != nil {
+					return err
+				}
+				if err := addColumnIfMissing(tx, &entity.EsfDocument{}, "DueDate"); err != nil {
+					return err
+				}
+				return tx.Exec("CREATE INDEX IF NOT EXISTS idx_esf_documents_due_date ON esf_documents (due_date)").Error
+			},
+		},
+		Migration{
+			Version:     "0007",
+			Description: "create bank statements",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&entity.BankStatement{}, &entity.BankStatementLine{})
+			},
+		},
+		Migration{
+			Version:     "0008",
+			Description: "create contracts and link documents to them",
+			Up: func(tx *gorm.DB) error {
+				if err := tx.AutoMigrate(&entity.Contract{}); err != nil {
+					return err
+				}
+				if err := addColumnIfMissing(tx, &entity.EsfDocument{}, "ContractID"); err != nil {
+					return err
+				}
+				return tx.Exec("CREATE INDEX IF NOT EXISTS idx_esf_documents_contract_id ON esf_documents (contract_id)").Error
+			},
+		},
+		Migration{
+			Version:     "0009",
+			Description: "create price lists and customer groups",
+			Up: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&entity.PriceList{}, &entity.PriceListItem{}, &entity.CustomerGroupMember{})
+			},
+		},
+		Migration{
+			Version:     "0010",
+			Description: "add discounts and surcharges to entries",
+			Up: func(tx *gorm.DB) error {
+				if err := addColumnIfMissing(tx, &entity.EsfEntries{}, "DiscountAmount"); err != nil {
+					return err
+				}
+				return addColumnIfMissing(tx, &entity.EsfEntries{}, "SurchargeAmount")
+			},
+		},
+		Migration{
+			Version:     "0011",
+			Description: "add localized contractor and item names",
+			Up: func(tx *gorm.DB) error {
+				for _, field := range []string{"Language", "ContractorNames"} {
+					if err := addColumnIfMissing(tx, &entity.EsfDocument{}, field); err != nil {
+						return err
+					}
+				}
+				for _, field := range []string{"ForeignName", "Names"} {
+					if err := addColumnIfMissing(tx, &entity.EsfEntries{}, field); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		Migration{
+			Version:     "0012",
+			Description: "widen encrypted columns and drop account from search vector",
+			Up: func(tx *gorm.DB) error {
+				// Зашифрованное значение длиннее исходного, а искать по шифротексту бессмысленно
+				statements := []string{
+					"ALTER TABLE esf_documents ALTER COLUMN supplier_bank_account TYPE text",
+					"ALTER TABLE esf_documents ALTER COLUMN contractor_bank_account TYPE text",
+					"ALTER TABLE esf_documents ALTER COLUMN personal_account_number TYPE text",
+					"ALTER TABLE bank_statements ALTER COLUMN account TYPE text",
+					"DROP INDEX IF EXISTS idx_esf_documents_fts",
+					"CREATE INDEX IF NOT EXISTS idx_esf_documents_fts ON esf_documents USING GIN (" + DocumentSearchVector + ")",
+				}
+				for _, stmt := range statements {
+					if err := tx.Exec(stmt).Error; err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+	)
+}
+
+// DocumentSearchVector выражение tsvector для полнотекстового поиска документов в Postgres.
+// Используется и в индексе, и в запросах, чтобы планировщик мог применить индекс.
+// Зашифрованные поля (лицевой счет) в поиск не входят.
+const DocumentSearchVector = "to_tsvector('simple', " +
+	"coalesce(contractor_tin, '') || ' ' || coalesce(foreign_name, '') || ' ' || " +
+	"coalesce(supply_contract_number, '') || ' ' || coalesce(owned_crm_receipt_code, '') || ' ' || " +
+	"coalesce(comment, ''))"
+
+// addColumnIfMissing добавляет колонку поля модели; в новых БД ее уже создает AutoMigrate
+func addColumnIfMissing(tx *gorm.DB, model interface{}, field string) error {
+	if tx.Migrator().HasColumn(model, field) {
+		return nil
+	}
+	return tx.Migrator().AddColumn(model, field)
+}
@@ -0,0 +1,215 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// errDryRun откатывает транзакцию пробного запуска
+var errDryRun = errors.New("dry run")
+
+// Migration версионированная миграция схемы БД
+type Migration struct {
+	// Version уникальная сортируемая версия, например "0001"
+	Version     string
+	Description string
+	Up          func(tx *gorm.DB) error
+}
+
+// SchemaMigration запись о примененной миграции
+type SchemaMigration struct {
+	Version     string `gorm:"primaryKey;size:64"`
+	Description string `gorm:"size:255"`
+	AppliedAt   time.Time
+}
+
+// TableName имя таблицы учета миграций
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Status состояние миграции в конкретной БД
+type Status struct {
+	Version     string     `json:"version"`
+	Description string     `json:"description"`
+	Applied     bool       `json:"applied"`
+	AppliedAt   *time.Time `json:"appliedAt,omitempty"`
+}
+
+// Plan SQL, который выполнит неприменная миграция
+type Plan struct {
+	Version     string   `json:"version"`
+	Description string   `json:"description"`
+	SQL         []string `json:"sql"`
+}
+
+// Migrator применяет набор миграций и ведет их учет в schema_migrations
+type Migrator struct {
+	migrations []Migration
+}
+
+// New создает Migrator. Миграции сортируются по версии.
+func New(migrations ...Migration) *Migrator {
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	return &Migrator{migrations: sorted}
+}
+
+// Migrations возвращает зарегистрированные миграции
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+// Status возвращает примененные и ожидающие миграции БД
+func (m *Migrator) Status(ctx context.Context, db *gorm.DB) ([]Status, error) {
+	applied, err := m.applied(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, len(m.migrations))
+	for i, mig := range m.migrations {
+		statuses[i] = Status{Version: mig.Version, Description: mig.Description}
+		if rec, ok := applied[mig.Version]; ok {
+			appliedAt := rec.AppliedAt
+			statuses[i].Applied = true
+			statuses[i].AppliedAt = &appliedAt
+		}
+	}
+	return statuses, nil
+}
+
+// Pending возвращает версии неприменных миграций
+func (m *Migrator) Pending(ctx context.Context, db *gorm.DB) ([]Migration, error) {
+	applied, err := m.applied(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []Migration
+	for _, mig := range m.migrations {
+		if _, ok := applied[mig.Version]; !ok {
+			pending = append(pending, mig)
+		}
+	}
+	return pending, nil
+}
+
+// Up применяет все неприменные миграции, каждую в своей транзакции.
+// Возвращает версии примененных миграций.
+func (m *Migrator) Up(ctx context.Context, db *gorm.DB) ([]string, error) {
+	if err := db.WithContext(ctx).AutoMigrate(&SchemaMigration{}); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	pending, err := m.Pending(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []string
+	for _, mig := range pending {
+		err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := mig.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&SchemaMigration{
+				Version:     mig.Version,
+				Description: mig.Description,
+				AppliedAt:   time.Now(),
+			}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %s failed: %w", mig.Version, err)
+		}
+		done = append(done, mig.Version)
+	}
+	return done, nil
+}
+
+// DryRun выполняет неприменные миграции в транзакции с откатом и возвращает изменяющий SQL.
+// DDL в PostgreSQL транзакционный, поэтому схема не меняется.
+func (m *Migrator) DryRun(ctx context.Context, db *gorm.DB) ([]Plan, error) {
+	pending, err := m.Pending(ctx, db)
+	if err != nil {
+		return nil, err
+	}
+
+	plans := make([]Plan, 0, len(pending))
+	for _, mig := range pending {
+		recorder := &sqlRecorder{Interface: logger.Discard}
+		session := db.Session(&gorm.Session{Logger: recorder, Context: ctx})
+
+		err := session.Transaction(func(tx *gorm.DB) error {
+			if err := mig.Up(tx); err != nil {
+				return err
+			}
+			return errDryRun
+		})
+		if err != nil && !errors.Is(err, errDryRun) {
+			return nil, fmt.Errorf("dry run of migration %s failed: %w", mig.Version, err)
+		}
+
+		plans = append(plans, Plan{
+			Version:     mig.Version,
+			Description: mig.Description,
+			SQL:         recorder.statements(),
+		})
+	}
+	return plans, nil
+}
+
+// applied возвращает записи примененных миграций; до первого запуска таблицы нет
+func (m *Migrator) applied(ctx context.Context, db *gorm.DB) (map[string]SchemaMigration, error) {
+	result := make(map[string]SchemaMigration)
+
+	db = db.WithContext(ctx)
+	if !db.Migrator().HasTable(&SchemaMigration{}) {
+		return result, nil
+	}
+
+	var records []SchemaMigration
+	if err := db.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	for _, rec := range records {
+		result[rec.Version] = rec
+	}
+	return result, nil
+}
+
+// sqlRecorder перехватывает SQL, выполняемый GORM, кроме запросов чтения
+type sqlRecorder struct {
+	logger.Interface
+	mu  sync.Mutex
+	sql []string
+}
+
+func (r *sqlRecorder) LogMode(logger.LogLevel) logger.Interface {
+	return r
+}
+
+func (r *sqlRecorder) Trace(_ context.Context, _ time.Time, fc func() (string, int64), _ error) {
+	sql, _ := fc()
+	if strings.HasPrefix(strings.ToUpper(strings.TrimSpace(sql)), "SELECT") {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sql = append(r.sql, sql)
+}
+
+func (r *sqlRecorder) statements() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.sql...)
+}
@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm/logger"
+)
+
+func TestNew_SortsByVersion(t *testing.T) {
+	m := New(
+		Migration{Version: "0003"},
+		Migration{Version: "0001"},
+		Migration{Version: "0002"},
+	)
+
+	versions := make([]string, 0, 3)
+	for _, mig := range m.Migrations() {
+		versions = append(versions, mig.Version)
+	}
+	assert.Equal(t, []string{"0001", "0002", "0003"}, versions)
+}
+
+func TestDefinitions_UniqueVersions(t *testing.T) {
+	for name, m := range map[string]*Migrator{"main": Main(), "tenant": Tenant()} {
+		seen := make(map[string]bool)
+		for _, mig := range m.Migrations() {
+			assert.False(t, seen[mig.Version], "%s: duplicate version %s", name, mig.Version)
+			assert.NotNil(t, mig.Up, "%s: migration %s has no Up", name, mig.Version)
+			seen[mig.Version] = true
+		}
+	}
+}
+
+func TestSQLRecorder_SkipsReads(t *testing.T) {
+	r := &sqlRecorder{Interface: logger.Discard}
+	trace := func(sql string) {
+		r.Trace(context.Background(), time.Now(), func() (string, int64) { return sql, 0 }, nil)
+	}
+
+	trace("SELECT count(*) FROM information_schema.tables")
+	trace(`CREATE TABLE "esf_documents" ("id" uuid)`)
+	trace("  select 1")
+	trace(`CREATE INDEX "idx_esf_documents_deleted_at" ON "esf_documents" ("deleted_at")`)
+
+	assert.Equal(t, []string{
+		`CREATE TABLE "esf_documents" ("id" uuid)`,
+		`CREATE INDEX "idx_esf_documents_deleted_at" ON "esf_documents" ("deleted_at")`,
+	}, r.statements())
+}
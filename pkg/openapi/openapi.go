@@ -0,0 +1,363 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+)
+
+// Version версия спецификации OpenAPI, которую формирует Build
+const Version = "3.0.3"
+
+// Info общие сведения об API для раздела info спецификации
+type Info struct {
+	Title       string
+	Description string
+	Version     string
+	Servers     []Server
+}
+
+// Server адрес, на котором доступно API
+type Server struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// Operation описание маршрута, которое нельзя получить из Fiber: назначение, типы тела
+// запроса и ответа, параметры строки запроса. Типы задаются значениями структур
+// (models.LoginRequest{}), схемы строятся по их json/query/validate тегам.
+type Operation struct {
+	Summary     string
+	Description string
+	Tags        []string
+	// Secured маршрут требует Authorization: Bearer <JWT>
+	Secured bool
+	// Request тело запроса в JSON; nil — без тела
+	Request interface{}
+	// Query структура с тегами query, описывающая параметры строки запроса
+	Query interface{}
+	// Response содержимое поля data в конверте ответа; nil — без data
+	Response interface{}
+	// Meta содержимое поля meta (пагинация списков); nil — без meta
+	Meta interface{}
+	// Status код успешного ответа; по умолчанию 200
+	Status int
+	// ContentType тип ответа, если это не JSON-конверт (выгрузки, метрики)
+	ContentType string
+	// Raw тело ответа — сам Response без конверта (GraphQL, проверка здоровья)
+	Raw bool
+}
+
+// Registry описания операций по методу и пути маршрута Fiber
+type Registry struct {
+	ops map[string]Operation
+}
+
+// NewRegistry создает пустой реестр описаний
+func NewRegistry() *Registry {
+	return &Registry{ops: make(map[string]Operation)}
+}
+
+// Add описывает маршрут; path указывается так же, как при регистрации в Fiber ("/api/users/:id")
+func (r *Registry) Add(method, path string, op Operation) {
+	r.ops[routeKey(method, path)] = op
+}
+
+// Lookup возвращает описание маршрута
+func (r *Registry) Lookup(method, path string) (Operation, bool) {
+	op, ok := r.ops[routeKey(method, path)]
+	return op, ok
+}
+
+// Undocumented возвращает маршруты, для которых в реестре нет описания, в виде "METHOD path".
+// Используется в тестах и при экспорте, чтобы новые маршруты не оставались без документации.
+func (r *Registry) Undocumented(routes []fiber.Route) []string {
+	var missing []string
+	for _, route := range documentedRoutes(routes) {
+		if _, ok := r.Lookup(route.Method, route.Path); !ok {
+			missing = append(missing, route.Method+" "+normalizePath(route.Path))
+		}
+	}
+	return missing
+}
+
+// Stale возвращает описания, для которых нет зарегистрированного маршрута
+func (r *Registry) Stale(routes []fiber.Route) []string {
+	registered := make(map[string]bool)
+	for _, route := range documentedRoutes(routes) {
+		registered[routeKey(route.Method, route.Path)] = true
+	}
+	var stale []string
+	for key := range r.ops {
+		if !registered[key] {
+			stale = append(stale, key)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+// Document спецификация OpenAPI 3
+type Document struct {
+	OpenAPI    string                          `json:"openapi"`
+	Info       DocumentInfo                    `json:"info"`
+	Servers    []Server                        `json:"servers,omitempty"`
+	Paths      map[string]map[string]*PathItem `json:"paths"`
+	Components Components                      `json:"components"`
+}
+
+// DocumentInfo раздел info спецификации
+type DocumentInfo struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// Components раздел components спецификации
+type Components struct {
+	Schemas         map[string]*Schema         `json:"schemas"`
+	SecuritySchemes map[string]*SecurityScheme `json:"securitySchemes"`
+}
+
+// SecurityScheme схема авторизации
+type SecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+}
+
+// PathItem операция по одному методу пути
+type PathItem struct {
+	Tags        []string              `json:"tags,omitempty"`
+	Summary     string                `json:"summary,omitempty"`
+	Description string                `json:"description,omitempty"`
+	OperationID string                `json:"operationId"`
+	Parameters  []Parameter           `json:"parameters,omitempty"`
+	RequestBody *RequestBody          `json:"requestBody,omitempty"`
+	Responses   map[string]*Response  `json:"responses"`
+	Security    []map[string][]string `json:"security,omitempty"`
+}
+
+// Parameter параметр пути или строки запроса
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required,omitempty"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody тело запроса
+type RequestBody struct {
+	Required bool                  `json:"required"`
+	Content  map[string]*MediaType `json:"content"`
+}
+
+// Response описание ответа
+type Response struct {
+	Description string                `json:"description"`
+	Content     map[string]*MediaType `json:"content,omitempty"`
+}
+
+// MediaType схема содержимого определенного типа
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+const (
+	bearerScheme  = "BearerAuth"
+	errorSchema   = "ErrorEnvelope"
+	jsonMediaType = fiber.MIMEApplicationJSON
+)
+
+// Build формирует спецификацию по зарегистрированным в Fiber маршрутам (app.GetRoutes()).
+// Маршруты без описания в реестре тоже попадают в спецификацию — с путем, параметрами
+// пути и общими ответами, — поэтому спецификация не отстает от фактического API.
+func Build(info Info, routes []fiber.Route, reg *Registry) *Document {
+	schemas := newSchemaSet()
+	doc := &Document{
+		OpenAPI: Version,
+		Info:    DocumentInfo{Title: info.Title, Description: info.Description, Version: info.Version},
+		Servers: info.Servers,
+		Paths:   make(map[string]map[string]*PathItem),
+		Components: Components{
+			Schemas: schemas.defs,
+			SecuritySchemes: map[string]*SecurityScheme{
+				bearerScheme: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+	// Ошибки всех маршрутов отдаются в конверте response.Envelope с полем error
+	schemas.defs[errorSchema] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"success":    {Type: "boolean"},
+			"error":      schemas.ref(reflect.TypeOf(apperror.ErrorResponse{})),
+			"request_id": {Type: "string"},
+		},
+		Required: []string{"success", "error"},
+	}
+
+	for _, route := range documentedRoutes(routes) {
+		path := openAPIPath(route.Path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = make(map[string]*PathItem)
+		}
+		op, _ := reg.Lookup(route.Method, route.Path)
+		doc.Paths[path][strings.ToLower(route.Method)] = buildOperation(route, op, schemas)
+	}
+	return doc
+}
+
+func buildOperation(route fiber.Route, op Operation, schemas *schemaSet) *PathItem {
+	item := &PathItem{
+		Tags:        op.Tags,
+		Summary:     op.Summary,
+		Description: op.Description,
+		OperationID: operationID(route.Method, route.Path),
+		Responses:   make(map[string]*Response),
+	}
+	if len(item.Tags) == 0 {
+		item.Tags = []string{defaultTag(route.Path)}
+	}
+
+	for _, name := range route.Params {
+		item.Parameters = append(item.Parameters, Parameter{
+			Name: name, In: "path", Required: true, Schema: &Schema{Type: "string"},
+		})
+	}
+	if op.Query != nil {
+		item.Parameters = append(item.Parameters, queryParameters(reflect.TypeOf(op.Query), schemas)...)
+	}
+
+	if op.Request != nil {
+		item.RequestBody = &RequestBody{
+			Required: true,
+			Content:  map[string]*MediaType{jsonMediaType: {Schema: schemas.ref(reflect.TypeOf(op.Request))}},
+		}
+	}
+
+	status := op.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	success := &Response{Description: http.StatusText(status)}
+	switch {
+	case op.ContentType != "":
+		success.Content = map[string]*MediaType{op.ContentType: {Schema: &Schema{Type: "string"}}}
+	case status == http.StatusNoContent || status == http.StatusSwitchingProtocols:
+	case op.Raw && op.Response != nil:
+		success.Content = map[string]*MediaType{jsonMediaType: {Schema: schemas.ref(reflect.TypeOf(op.Response))}}
+	default:
+		success.Content = map[string]*MediaType{jsonMediaType: {Schema: envelopeSchema(op, schemas)}}
+	}
+	item.Responses[strconv.Itoa(status)] = success
+	item.Responses["default"] = &Response{
+		Description: "Error",
+		Content:     map[string]*MediaType{jsonMediaType: {Schema: &Schema{Ref: refPrefix + errorSchema}}},
+	}
+
+	if op.Secured {
+		item.Security = []map[string][]string{{bearerScheme: {}}}
+	}
+	return item
+}
+
+// envelopeSchema описывает успешный response.Envelope с типизированными data и meta
+func envelopeSchema(op Operation, schemas *schemaSet) *Schema {
+	s := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"success":    {Type: "boolean"},
+			"message":    {Type: "string"},
+			"request_id": {Type: "string"},
+		},
+		Required: []string{"success"},
+	}
+	if op.Response != nil {
+		s.Properties["data"] = schemas.ref(reflect.TypeOf(op.Response))
+	}
+	if op.Meta != nil {
+		s.Properties["meta"] = schemas.ref(reflect.TypeOf(op.Meta))
+	}
+	return s
+}
+
+// documentedRoutes отбирает маршруты для спецификации: без HEAD, который Fiber добавляет к GET,
+// и без маршрутов с * (статика Swagger UI, имитация шлюза), у которых нет фиксированного контракта
+func documentedRoutes(routes []fiber.Route) []fiber.Route {
+	seen := make(map[string]bool)
+	var result []fiber.Route
+	for _, route := range routes {
+		if route.Method == fiber.MethodHead || route.Method == fiber.MethodConnect ||
+			route.Method == fiber.MethodTrace || strings.Contains(route.Path, "*") {
+			continue
+		}
+		key := routeKey(route.Method, route.Path)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, route)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].Path != result[j].Path {
+			return normalizePath(result[i].Path) < normalizePath(result[j].Path)
+		}
+		return result[i].Method < result[j].Method
+	})
+	return result
+}
+
+func routeKey(method, path string) string {
+	return strings.ToUpper(method) + " " + normalizePath(path)
+}
+
+// normalizePath убирает завершающий слэш: group.Get("/") регистрирует "/api/users/"
+func normalizePath(path string) string {
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+	return path
+}
+
+// openAPIPath переводит "/api/users/:id" в "/api/users/{id}"
+func openAPIPath(path string) string {
+	segments := strings.Split(normalizePath(path), "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + strings.TrimSuffix(strings.TrimPrefix(segment, ":"), "?") + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// operationID формирует стабильный идентификатор операции для генераторов клиентов: "get_api_users_id"
+func operationID(method, path string) string {
+	var b strings.Builder
+	b.WriteString(strings.ToLower(method))
+	for _, r := range normalizePath(path) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	return strings.Trim(strings.ReplaceAll(b.String(), "__", "_"), "_")
+}
+
+// defaultTag группирует неописанные маршруты по первому значимому сегменту пути
+func defaultTag(path string) string {
+	for _, segment := range strings.Split(normalizePath(path), "/") {
+		if segment != "" && segment != "api" && !strings.HasPrefix(segment, ":") {
+			return segment
+		}
+	}
+	return "default"
+}
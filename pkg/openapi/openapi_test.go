@@ -0,0 +1,144 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/pkg/dates"
+)
+
+type testAddress struct {
+	City string `json:"city"`
+}
+
+type testUser struct {
+	ID        uuid.UUID    `json:"id"`
+	Email     string       `json:"email" validate:"required,email"`
+	Name      string       `json:"name" validate:"required,min=2,max=50"`
+	Role      string       `json:"role,omitempty" validate:"oneof=admin user"`
+	Age       int          `json:"age" validate:"min=18"`
+	Tin       string       `json:"tin" validate:"omitempty,tin"`
+	CreatedAt time.Time    `json:"createdAt"`
+	BirthDate dates.Date   `json:"birthDate"`
+	Address   *testAddress `json:"address"`
+	Tags      []string     `json:"tags"`
+	Password  string       `json:"-"`
+	internal  string
+}
+
+type testPage struct {
+	Page int `query:"page"`
+}
+
+type testListQuery struct {
+	testPage
+	Status string `query:"status" validate:"required"`
+}
+
+func testApp() *fiber.App {
+	app := fiber.New()
+	noop := func(c *fiber.Ctx) error { return nil }
+	users := app.Group("/api/users")
+	users.Get("/", noop)
+	users.Get("/:id", noop)
+	users.Post("/", noop)
+	app.Get("/static/*", noop)
+	app.Delete("/api/undocumented/:id", noop)
+	return app
+}
+
+func testRegistry() *Registry {
+	reg := NewRegistry()
+	reg.Add(fiber.MethodGet, "/api/users", Operation{
+		Tags: []string{"Users"}, Summary: "List users", Query: testListQuery{}, Response: []testUser{}, Meta: testPage{},
+	})
+	reg.Add(fiber.MethodGet, "/api/users/:id", Operation{Tags: []string{"Users"}, Response: testUser{}})
+	reg.Add(fiber.MethodPost, "/api/users/", Operation{
+		Tags: []string{"Users"}, Secured: true, Request: testUser{}, Response: testUser{}, Status: fiber.StatusCreated,
+	})
+	reg.Add(fiber.MethodGet, "/api/removed", Operation{Summary: "No longer registered"})
+	return reg
+}
+
+func TestBuild_PathsFromRoutes(t *testing.T) {
+	doc := Build(Info{Title: "Test", Version: "1.0.0"}, testApp().GetRoutes(true), testRegistry())
+
+	assert.Equal(t, Version, doc.OpenAPI)
+	assert.Contains(t, doc.Paths, "/api/users")
+	assert.Contains(t, doc.Paths, "/api/users/{id}")
+	assert.NotContains(t, doc.Paths, "/api/removed", "descriptions without a route are not published")
+	assert.NotContains(t, doc.Paths, "/static/*")
+	assert.NotContains(t, doc.Paths["/api/users"], "head")
+
+	get := doc.Paths["/api/users/{id}"]["get"]
+	require.Len(t, get.Parameters, 1)
+	assert.Equal(t, Parameter{Name: "id", In: "path", Required: true, Schema: &Schema{Type: "string"}}, get.Parameters[0])
+	assert.Equal(t, "get_api_users_id", get.OperationID)
+
+	// Неописанный маршрут попадает в спецификацию с тегом по пути
+	undocumented := doc.Paths["/api/undocumented/{id}"]["delete"]
+	require.NotNil(t, undocumented)
+	assert.Equal(t, []string{"undocumented"}, undocumented.Tags)
+	assert.Contains(t, undocumented.Responses, "default")
+}
+
+func TestBuild_OperationDetails(t *testing.T) {
+	doc := Build(Info{Title: "Test", Version: "1.0.0"}, testApp().GetRoutes(true), testRegistry())
+
+	list := doc.Paths["/api/users"]["get"]
+	require.Len(t, list.Parameters, 2, "embedded query structs are flattened")
+	assert.Equal(t, "page", list.Parameters[0].Name)
+	assert.Equal(t, "status", list.Parameters[1].Name)
+	assert.True(t, list.Parameters[1].Required)
+
+	body := list.Responses["200"].Content[fiber.MIMEApplicationJSON].Schema
+	assert.Equal(t, "array", body.Properties["data"].Type)
+	assert.Equal(t, refPrefix+"testUser", body.Properties["data"].Items.Ref)
+	assert.Equal(t, refPrefix+"testPage", body.Properties["meta"].Ref)
+
+	create := doc.Paths["/api/users"]["post"]
+	assert.Contains(t, create.Responses, "201")
+	assert.Equal(t, []map[string][]string{{bearerScheme: {}}}, create.Security)
+	require.NotNil(t, create.RequestBody)
+	assert.Equal(t, refPrefix+"testUser", create.RequestBody.Content[fiber.MIMEApplicationJSON].Schema.Ref)
+}
+
+func TestBuild_SchemaFromStruct(t *testing.T) {
+	doc := Build(Info{}, testApp().GetRoutes(true), testRegistry())
+	user := doc.Components.Schemas["testUser"]
+	require.NotNil(t, user)
+
+	assert.Equal(t, []string{"email", "name"}, user.Required)
+	assert.Equal(t, &Schema{Type: "string", Format: "uuid"}, user.Properties["id"])
+	assert.Equal(t, "email", user.Properties["email"].Format)
+	assert.Equal(t, 2, *user.Properties["name"].MinLength)
+	assert.Equal(t, 50, *user.Properties["name"].MaxLength)
+	assert.Equal(t, []string{"admin", "user"}, user.Properties["role"].Enum)
+	assert.Equal(t, 18.0, *user.Properties["age"].Minimum)
+	assert.Equal(t, "tin", user.Properties["tin"].Format)
+	assert.Equal(t, 14, *user.Properties["tin"].MaxLength)
+	assert.Equal(t, "date-time", user.Properties["createdAt"].Format)
+	assert.Equal(t, &Schema{Type: "string", Format: "date"}, user.Properties["birthDate"])
+	assert.Equal(t, refPrefix+"testAddress", user.Properties["address"].Ref)
+	assert.Equal(t, "array", user.Properties["tags"].Type)
+	assert.NotContains(t, user.Properties, "Password")
+	assert.NotContains(t, user.Properties, "internal")
+
+	assert.Contains(t, doc.Components.Schemas, errorSchema)
+	_, err := json.Marshal(doc)
+	require.NoError(t, err)
+}
+
+func TestRegistry_UndocumentedAndStale(t *testing.T) {
+	routes := testApp().GetRoutes(true)
+	reg := testRegistry()
+
+	assert.Equal(t, []string{"DELETE /api/undocumented/:id"}, reg.Undocumented(routes))
+	assert.Equal(t, []string{"GET /api/removed"}, reg.Stale(routes))
+}
@@ -0,0 +1,325 @@
+package openapi
+
+import (
+	"encoding"
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"github.com/rusgainew/tunduck-app/pkg/dates"
+)
+
+const refPrefix = "#/components/schemas/"
+
+// Schema схема JSON в подмножестве OpenAPI 3, которое нужно для моделей API
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
+
+var (
+	timeType       = reflect.TypeOf(time.Time{})
+	dateType       = reflect.TypeOf(dates.Date{})
+	uuidType       = reflect.TypeOf(uuid.UUID{})
+	deletedAtType  = reflect.TypeOf(gorm.DeletedAt{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+	textMarshaler  = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonMarshaler  = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+)
+
+// schemaSet строит схемы по типам Go; именованные структуры выносятся в components.schemas
+type schemaSet struct {
+	defs  map[string]*Schema
+	names map[reflect.Type]string
+}
+
+func newSchemaSet() *schemaSet {
+	return &schemaSet{
+		defs:  make(map[string]*Schema),
+		names: make(map[reflect.Type]string),
+	}
+}
+
+// ref возвращает ссылку на схему именованной структуры или встроенную схему для остальных типов
+func (s *schemaSet) ref(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || t.Name() == "" || scalar(t) != nil {
+		return s.of(t)
+	}
+	if name, ok := s.names[t]; ok {
+		return &Schema{Ref: refPrefix + name}
+	}
+
+	name := s.nameFor(t)
+	s.names[t] = name
+	// Заглушка до построения защищает от бесконечной рекурсии на рекурсивных типах
+	s.defs[name] = &Schema{Type: "object"}
+	s.defs[name] = s.object(t)
+	return &Schema{Ref: refPrefix + name}
+}
+
+// nameFor возвращает имя схемы; при совпадении имен типов из разных пакетов добавляется пакет.
+// Символы параметров обобщенных типов ("Page[...]") заменяются, чтобы имя годилось для $ref.
+func (s *schemaSet) nameFor(t reflect.Type) string {
+	name := sanitizeName(t.Name())
+	if _, taken := s.defs[name]; !taken {
+		return name
+	}
+	pkg := t.PkgPath()
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		pkg = pkg[i+1:]
+	}
+	return sanitizeName(pkg) + "." + name
+}
+
+func sanitizeName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '.':
+			return r
+		case r == ']':
+			return -1
+		default:
+			return '_'
+		}
+	}, name)
+}
+
+// of строит схему типа без выноса в components
+func (s *schemaSet) of(t reflect.Type) *Schema {
+	nullable := false
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		nullable = true
+	}
+	if sc := scalar(t); sc != nil {
+		sc.Nullable = sc.Nullable || nullable
+		return sc
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: s.ref(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: s.ref(t.Elem())}
+	case reflect.Struct:
+		if t.Name() != "" {
+			return s.ref(t)
+		}
+		return s.object(t)
+	default:
+		// interface{} и прочие типы: произвольное значение
+		return &Schema{}
+	}
+}
+
+// scalar распознает типы, которые сериализуются в JSON строкой
+func scalar(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case dateType:
+		return &Schema{Type: "string", Format: "date"}
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case deletedAtType:
+		return &Schema{Type: "string", Format: "date-time", Nullable: true}
+	case rawMessageType:
+		return &Schema{}
+	}
+	if t.Kind() == reflect.Struct && (t.Implements(textMarshaler) || reflect.PtrTo(t).Implements(textMarshaler)) {
+		return &Schema{Type: "string"}
+	}
+	if t.Kind() == reflect.Struct && (t.Implements(jsonMarshaler) || reflect.PtrTo(t).Implements(jsonMarshaler)) {
+		return &Schema{}
+	}
+	return nil
+}
+
+// object строит схему структуры по json и validate/valid тегам; встроенные структуры раскрываются
+func (s *schemaSet) object(t reflect.Type) *Schema {
+	obj := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	s.collectFields(t, obj)
+	return obj
+}
+
+func (s *schemaSet) collectFields(t reflect.Type, obj *Schema) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, ok := jsonName(field)
+		if !ok {
+			continue
+		}
+
+		ft := field.Type
+		if field.Anonymous && name == "" {
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && scalar(ft) == nil {
+				s.collectFields(ft, obj)
+				continue
+			}
+			name = ft.Name()
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := s.ref(ft)
+		rules := parseRules(field)
+		if rules.required {
+			obj.Required = append(obj.Required, name)
+		}
+		if prop.Ref == "" {
+			rules.apply(prop)
+		}
+		obj.Properties[name] = prop
+	}
+}
+
+// jsonName возвращает имя поля в JSON; пустое имя у встроенной структуры без тега означает раскрытие
+func jsonName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() && !field.Anonymous {
+		return "", false
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false
+	}
+	name := strings.Split(tag, ",")[0]
+	if name == "" && !field.Anonymous {
+		name = field.Name
+	}
+	return name, true
+}
+
+// queryParameters описывает параметры строки запроса по полям с тегом query;
+// встроенные структуры без тега раскрываются, что позволяет объединять параметры
+// пагинации и фильтров в одной структуре
+func queryParameters(t reflect.Type, schemas *schemaSet) []Parameter {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var params []Parameter
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Tag.Get("query") == "" {
+			params = append(params, queryParameters(field.Type, schemas)...)
+			continue
+		}
+		name := strings.Split(field.Tag.Get("query"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		schema := schemas.of(field.Type)
+		rules := parseRules(field)
+		rules.apply(schema)
+		params = append(params, Parameter{Name: name, In: "query", Required: rules.required, Schema: schema})
+	}
+	return params
+}
+
+// fieldRules ограничения поля из тегов validate (go-playground/validator) и valid
+type fieldRules struct {
+	required bool
+	format   string
+	enum     []string
+	min, max *float64
+}
+
+func parseRules(field reflect.StructField) fieldRules {
+	var r fieldRules
+	for _, tag := range []string{field.Tag.Get("validate"), field.Tag.Get("valid")} {
+		for _, rule := range strings.Split(tag, ",") {
+			name, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+			switch name {
+			case "required":
+				r.required = true
+			case "email", "uuid", "url", "uri":
+				r.format = name
+			case "uuid4":
+				r.format = "uuid"
+			case "oneof":
+				r.enum = strings.Fields(param)
+			case "min", "gte":
+				r.min = parseFloat(param)
+			case "max", "lte":
+				r.max = parseFloat(param)
+			case "len":
+				r.min, r.max = parseFloat(param), parseFloat(param)
+			case "tin":
+				// ИНН России (10 или 12 цифр) или Кыргызстана (14 цифр), pkg/tin
+				minLen, maxLen := 10.0, 14.0
+				r.format = name
+				r.min, r.max = &minLen, &maxLen
+			}
+		}
+	}
+	return r
+}
+
+// apply переносит ограничения в схему: для строк — длина, для чисел — диапазон
+func (r fieldRules) apply(s *Schema) {
+	if r.format != "" && s.Type == "string" {
+		s.Format = r.format
+	}
+	if len(r.enum) > 0 {
+		s.Enum = r.enum
+	}
+	switch s.Type {
+	case "string":
+		s.MinLength, s.MaxLength = toInt(r.min), toInt(r.max)
+	case "integer", "number":
+		s.Minimum, s.Maximum = r.min, r.max
+	}
+}
+
+func parseFloat(s string) *float64 {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil
+	}
+	return &v
+}
+
+func toInt(v *float64) *int {
+	if v == nil {
+		return nil
+	}
+	i := int(*v)
+	return &i
+}
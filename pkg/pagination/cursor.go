@@ -0,0 +1,252 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidCursor повертається, коли курсор неможливо декодувати
+// або він не відповідає поточному сортуванню
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor містить ключі сортування останнього (або, для попередньої сторінки, першого) елемента
+// сторінки. Клієнту передається лише в закодованому (непрозорому) вигляді.
+type Cursor struct {
+	Sort  string `json:"s"`
+	Order string `json:"o"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+	// Prev курсор попередньої сторінки: вибираються елементи перед ключем
+	Prev bool `json:"p,omitempty"`
+}
+
+// CursorParams містить параметри keyset-пагінації
+type CursorParams struct {
+	Cursor string `query:"cursor"`
+	Limit  int    `query:"limit" default:"10"`
+	Sort   string `query:"sort" default:"created_at"`
+	Order  string `query:"order" default:"desc"`
+}
+
+// CursorInfo містить інформацію про курсорну пагінацію. Загальна кількість не рахується:
+// курсорні списки призначені для великих вибірок.
+type CursorInfo struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	PrevCursor string `json:"prev_cursor,omitempty"`
+	HasNext    bool   `json:"has_next"`
+	HasPrev    bool   `json:"has_prev"`
+	Limit      int    `json:"limit"`
+}
+
+// CursorResponse містить дані з інформацією про курсорну пагінацію
+type CursorResponse struct {
+	Data       interface{} `json:"data"`
+	Pagination CursorInfo  `json:"pagination"`
+}
+
+// ExtractCursorParams витягує параметри курсорної пагінації з запиту.
+// Сортування дозволене лише по полях з allowedSorts (перше поле - за замовчуванням).
+func ExtractCursorParams(ctx *fiber.Ctx, allowedSorts ...string) CursorParams {
+	defaultSort := "created_at"
+	if len(allowedSorts) > 0 {
+		defaultSort = allowedSorts[0]
+	}
+
+	limit := ctx.QueryInt("limit", 10)
+	sort := ctx.Query("sort", defaultSort)
+	order := ctx.Query("order", "desc")
+
+	// Валідація
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	if !containsSort(allowedSorts, sort) {
+		sort = defaultSort
+	}
+
+	if order != "asc" && order != "desc" {
+		order = "desc"
+	}
+
+	return CursorParams{
+		Cursor: ctx.Query("cursor", ""),
+		Limit:  limit,
+		Sort:   sort,
+		Order:  order,
+	}
+}
+
+// EncodeCursor кодує курсор у непрозорий рядок
+func EncodeCursor(c Cursor) string {
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor декодує курсор з рядка
+func DecodeCursor(s string) (Cursor, error) {
+	var c Cursor
+
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return c, ErrInvalidCursor
+	}
+	if c.ID == "" {
+		return c, ErrInvalidCursor
+	}
+
+	return c, nil
+}
+
+// CursorValue перетворює значення ключа сортування в рядок для курсора
+func CursorValue(v interface{}) string {
+	switch val := v.(type) {
+	case time.Time:
+		return val.UTC().Format(time.RFC3339Nano)
+	case *time.Time:
+		if val == nil {
+			return ""
+		}
+		return val.UTC().Format(time.RFC3339Nano)
+	case string:
+		return val
+	default:
+		return fmt.Sprint(val)
+	}
+}
+
+// Apply застосовує keyset-умову, сортування та ліміт до GORM запиту.
+// Вибирається Limit+1 записів, щоб визначити наявність наступної сторінки. Для курсора
+// попередньої сторінки порядок вибірки зворотний; NewCursorPage повертає його до прямого.
+func (p CursorParams) Apply(query *gorm.DB) (*gorm.DB, error) {
+	order := p.Order
+	if p.Cursor != "" {
+		c, err := DecodeCursor(p.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		if c.Sort != p.Sort || c.Order != p.Order {
+			return nil, ErrInvalidCursor
+		}
+
+		if c.Prev {
+			order = reverseOrder(order)
+		}
+		op := "<"
+		if order == "asc" {
+			op = ">"
+		}
+		query = query.Where(fmt.Sprintf("(%s, id) %s (?, ?)", p.Sort, op), c.Value, c.ID)
+	}
+
+	return query.
+		Order(p.Sort + " " + order).
+		Order("id " + order).
+		Limit(p.GetLimit() + 1), nil
+}
+
+// prev повідомляє, чи запитана попередня сторінка
+func (p CursorParams) prev() bool {
+	if p.Cursor == "" {
+		return false
+	}
+	c, err := DecodeCursor(p.Cursor)
+	return err == nil && c.Prev
+}
+
+func reverseOrder(order string) string {
+	if order == "asc" {
+		return "desc"
+	}
+	return "asc"
+}
+
+// GetLimit повертає розмір сторінки
+func (p CursorParams) GetLimit() int {
+	if p.Limit <= 0 {
+		return 10
+	}
+	if p.Limit > 100 {
+		return 100
+	}
+	return p.Limit
+}
+
+// NewCursorPage обрізає вибірку до розміру сторінки та формує курсори наступної і попередньої
+// сторінок. key повертає значення ключа сортування та ID елемента.
+func NewCursorPage[T any](items []T, params CursorParams, key func(T) (interface{}, string)) ([]T, CursorInfo) {
+	limit := params.GetLimit()
+	info := CursorInfo{Limit: limit}
+
+	// Зайвий елемент означає, що вибірка продовжується в напрямку запиту
+	more := len(items) > limit
+	if more {
+		items = items[:limit]
+	}
+
+	if params.prev() {
+		// Попередня сторінка вибрана у зворотному порядку; за курсором є наступна
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+		info.HasPrev = more
+		info.HasNext = true
+	} else {
+		info.HasNext = more
+		info.HasPrev = params.Cursor != ""
+	}
+
+	if len(items) == 0 {
+		return items, info
+	}
+	cursor := func(item T, prev bool) string {
+		value, id := key(item)
+		return EncodeCursor(Cursor{
+			Sort:  params.Sort,
+			Order: params.Order,
+			Value: CursorValue(value),
+			ID:    id,
+			Prev:  prev,
+		})
+	}
+	if info.HasNext {
+		info.NextCursor = cursor(items[len(items)-1], false)
+	}
+	if info.HasPrev {
+		info.PrevCursor = cursor(items[0], true)
+	}
+
+	return items, info
+}
+
+// NewCursorResponse створює нову відповідь з курсорною пагінацією
+func NewCursorResponse(data interface{}, info CursorInfo) CursorResponse {
+	return CursorResponse{
+		Data:       data,
+		Pagination: info,
+	}
+}
+
+func containsSort(allowed []string, sort string) bool {
+	for _, s := range allowed {
+		if s == sort {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package pagination
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCursor_EncodeDecodeRoundTrip(t *testing.T) {
+	c := Cursor{Sort: "created_at", Order: "desc", Value: "2025-01-02T03:04:05Z", ID: "b7c1"}
+
+	decoded, err := DecodeCursor(EncodeCursor(c))
+	require.NoError(t, err)
+	assert.Equal(t, c, decoded)
+}
+
+func TestCursor_DecodeInvalid(t *testing.T) {
+	_, err := DecodeCursor("not-a-cursor!")
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+
+	_, err = DecodeCursor(EncodeCursor(Cursor{Sort: "created_at"}))
+	assert.ErrorIs(t, err, ErrInvalidCursor)
+}
+
+func TestNewCursorPage_TrimsAndBuildsNextCursor(t *testing.T) {
+	type item struct {
+		id      string
+		created time.Time
+	}
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	items := []item{
+		{"a", base.Add(3 * time.Hour)},
+		{"b", base.Add(2 * time.Hour)},
+		{"c", base.Add(1 * time.Hour)},
+	}
+	params := CursorParams{Limit: 2, Sort: "created_at", Order: "desc"}
+
+	page, info := NewCursorPage(items, params, func(i item) (interface{}, string) { return i.created, i.id })
+
+	require.Len(t, page, 2)
+	assert.True(t, info.HasNext)
+	assert.Equal(t, 2, info.Limit)
+
+	next, err := DecodeCursor(info.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, "b", next.ID)
+	assert.Equal(t, base.Add(2*time.Hour).Format(time.RFC3339Nano), next.Value)
+}
+
+func TestNewCursorPage_LastPage(t *testing.T) {
+	params := CursorParams{Limit: 5, Sort: "name", Order: "asc"}
+
+	page, info := NewCursorPage([]string{"x", "y"}, params, func(s string) (interface{}, string) { return s, s })
+
+	assert.Len(t, page, 2)
+	assert.False(t, info.HasNext)
+	assert.Empty(t, info.NextCursor)
+}
+
+func TestNewCursorPage_PrevCursor(t *testing.T) {
+	key := func(s string) (interface{}, string) { return s, s }
+	params := CursorParams{Limit: 2, Sort: "name", Order: "asc"}
+
+	// Друга сторінка вперед: є обидва курсори
+	params.Cursor = EncodeCursor(Cursor{Sort: "name", Order: "asc", Value: "b", ID: "b"})
+	page, info := NewCursorPage([]string{"c", "d", "e"}, params, key)
+	assert.Equal(t, []string{"c", "d"}, page)
+	assert.True(t, info.HasNext)
+	assert.True(t, info.HasPrev)
+
+	prev, err := DecodeCursor(info.PrevCursor)
+	require.NoError(t, err)
+	assert.Equal(t, Cursor{Sort: "name", Order: "asc", Value: "c", ID: "c", Prev: true}, prev)
+
+	// Попередня сторінка вибирається у зворотному порядку і повертається в прямому
+	params.Cursor = info.PrevCursor
+	page, info = NewCursorPage([]string{"b", "a"}, params, key)
+	assert.Equal(t, []string{"a", "b"}, page)
+	assert.True(t, info.HasNext)
+	assert.False(t, info.HasPrev)
+	assert.Empty(t, info.PrevCursor)
+
+	next, err := DecodeCursor(info.NextCursor)
+	require.NoError(t, err)
+	assert.Equal(t, "b", next.ID)
+	assert.False(t, next.Prev)
+}
@@ -1,7 +1,9 @@
 package pagination
 
 import (
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -13,40 +15,59 @@ type FilterParams struct {
 
 // DocumentFilterParams спеціалізована структура для фільтрації документів
 type DocumentFilterParams struct {
-	Status        string // active, archived
-	CreatedAfter  string // ISO 8601 дата
-	CreatedBefore string
-	Search        string // пошук по назві/опису
+	Status         string   `json:"status,omitempty" query:"status"`               // active, archived; кілька значень через кому
+	CreatedAfter   string   `json:"created_after,omitempty" query:"created_after"` // ISO 8601 дата
+	CreatedBefore  string   `json:"created_before,omitempty" query:"created_before"`
+	DeliveryAfter  string   `json:"delivery_after,omitempty" query:"delivery_after"` // діапазон дати поставки
+	DeliveryBefore string   `json:"delivery_before,omitempty" query:"delivery_before"`
+	AmountGte      *float64 `json:"amount_gte,omitempty" query:"amount_gte"` // діапазон загальної вартості
+	AmountLte      *float64 `json:"amount_lte,omitempty" query:"amount_lte"`
+	Search         string   `json:"search,omitempty" query:"search"`   // пошук по назві/опису
+	Payment        string   `json:"payment,omitempty" query:"payment"` // стан оплати: paid, unpaid, overdue
+	Filter         string   `json:"filter,omitempty" query:"filter"`   // вираз у стилі RSQL: status==sent;amount>=1000
 }
 
 // OrganizationFilterParams спеціалізована структура для фільтрації організацій
 type OrganizationFilterParams struct {
-	Status string // active, inactive
-	Search string // пошук по назві
+	Status        string `query:"status"`        // active, inactive; кілька значень через кому
+	CreatedAfter  string `query:"created_after"` // ISO 8601 дата
+	CreatedBefore string `query:"created_before"`
+	Search        string `query:"search"` // пошук по назві
+	Filter        string `query:"filter"` // вираз у стилі RSQL
 }
 
 // UserFilterParams спеціалізована структура для фільтрації користувачів
 type UserFilterParams struct {
-	Status string // active, inactive
-	Search string // пошук по імені/email
-	RoleID string
+	Status string `query:"status"` // active, inactive
+	Search string `query:"search"` // пошук по імені/email
+	RoleID string `query:"role_id"`
+	Filter string `query:"filter"` // вираз у стилі RSQL
 }
 
 // ExtractDocumentFilters витягує фільтри для документів
 func ExtractDocumentFilters(ctx *fiber.Ctx) DocumentFilterParams {
 	return DocumentFilterParams{
-		Status:        ctx.Query("status", ""),
-		CreatedAfter:  ctx.Query("created_after", ""),
-		CreatedBefore: ctx.Query("created_before", ""),
-		Search:        ctx.Query("search", ""),
+		Status:         ctx.Query("status", ""),
+		CreatedAfter:   ctx.Query("created_after", ""),
+		CreatedBefore:  ctx.Query("created_before", ""),
+		DeliveryAfter:  ctx.Query("delivery_after", ""),
+		DeliveryBefore: ctx.Query("delivery_before", ""),
+		AmountGte:      queryFloat(ctx, "amount_gte"),
+		AmountLte:      queryFloat(ctx, "amount_lte"),
+		Search:         ctx.Query("search", ""),
+		Payment:        ctx.Query("payment", ""),
+		Filter:         ctx.Query("filter", ""),
 	}
 }
 
 // ExtractOrganizationFilters витягує фільтри для організацій
 func ExtractOrganizationFilters(ctx *fiber.Ctx) OrganizationFilterParams {
 	return OrganizationFilterParams{
-		Status: ctx.Query("status", ""),
-		Search: ctx.Query("search", ""),
+		Status:        ctx.Query("status", ""),
+		CreatedAfter:  ctx.Query("created_after", ""),
+		CreatedBefore: ctx.Query("created_before", ""),
+		Search:        ctx.Query("search", ""),
+		Filter:        ctx.Query("filter", ""),
 	}
 }
 
@@ -56,23 +77,109 @@ func ExtractUserFilters(ctx *fiber.Ctx) UserFilterParams {
 		Status: ctx.Query("status", ""),
 		Search: ctx.Query("search", ""),
 		RoleID: ctx.Query("role_id", ""),
+		Filter: ctx.Query("filter", ""),
 	}
 }
 
 // HasFilters перевіряє, чи встановлені якісь фільтри
 func (f DocumentFilterParams) HasFilters() bool {
 	return f.Status != "" || f.CreatedAfter != "" ||
-		f.CreatedBefore != "" || strings.TrimSpace(f.Search) != ""
+		f.CreatedBefore != "" || f.DeliveryAfter != "" ||
+		f.DeliveryBefore != "" || f.AmountGte != nil ||
+		f.AmountLte != nil || strings.TrimSpace(f.Search) != "" ||
+		f.Payment != "" || strings.TrimSpace(f.Filter) != ""
+}
+
+// Merge накладає непорожні фільтри override поверх f: явні параметри запиту
+// важливіші за фільтри збереженого представлення. Вирази filter не замінюються,
+// а поєднуються через ";": запит звужує вибірку представлення
+func (f DocumentFilterParams) Merge(override DocumentFilterParams) DocumentFilterParams {
+	pick := func(base, value string) string {
+		if value != "" {
+			return value
+		}
+		return base
+	}
+	f.Status = pick(f.Status, override.Status)
+	f.CreatedAfter = pick(f.CreatedAfter, override.CreatedAfter)
+	f.CreatedBefore = pick(f.CreatedBefore, override.CreatedBefore)
+	f.DeliveryAfter = pick(f.DeliveryAfter, override.DeliveryAfter)
+	f.DeliveryBefore = pick(f.DeliveryBefore, override.DeliveryBefore)
+	f.Search = pick(f.Search, override.Search)
+	f.Payment = pick(f.Payment, override.Payment)
+	if override.AmountGte != nil {
+		f.AmountGte = override.AmountGte
+	}
+	if override.AmountLte != nil {
+		f.AmountLte = override.AmountLte
+	}
+	f.Filter = JoinFilters(f.Filter, override.Filter)
+	return f
+}
+
+// JoinFilters поєднує вирази filter через ";" (логічне І), пропускаючи порожні
+func JoinFilters(filters ...string) string {
+	var parts []string
+	for _, filter := range filters {
+		if filter = strings.TrimSpace(filter); filter != "" {
+			parts = append(parts, filter)
+		}
+	}
+	if len(parts) < 2 {
+		return strings.Join(parts, "")
+	}
+	return "(" + strings.Join(parts, ");(") + ")"
+}
+
+// ResolveDates замінює відносні дати на календарні відносно now: "today" — сьогодні,
+// "-30d" — 30 днів тому. Так збережене представлення «неоплачені понад 30 днів» лишається актуальним
+func (f DocumentFilterParams) ResolveDates(now time.Time) DocumentFilterParams {
+	f.CreatedAfter = resolveDate(f.CreatedAfter, now)
+	f.CreatedBefore = resolveDate(f.CreatedBefore, now)
+	f.DeliveryAfter = resolveDate(f.DeliveryAfter, now)
+	f.DeliveryBefore = resolveDate(f.DeliveryBefore, now)
+	return f
+}
+
+// IsRelativeDate перевіряє формат відносної дати: "today" або "-Nd"
+func IsRelativeDate(value string) bool {
+	_, ok := relativeDays(value)
+	return ok
+}
+
+func resolveDate(value string, now time.Time) string {
+	days, ok := relativeDays(value)
+	if !ok {
+		return value
+	}
+	return now.AddDate(0, 0, -days).Format("2006-01-02")
+}
+
+func relativeDays(value string) (int, bool) {
+	if value == "today" {
+		return 0, true
+	}
+	if !strings.HasPrefix(value, "-") || !strings.HasSuffix(value, "d") {
+		return 0, false
+	}
+	days, err := strconv.Atoi(value[1 : len(value)-1])
+	if err != nil || days < 0 {
+		return 0, false
+	}
+	return days, true
 }
 
 // HasFilters перевіряє, чи встановлені якісь фільтри
 func (f OrganizationFilterParams) HasFilters() bool {
-	return f.Status != "" || strings.TrimSpace(f.Search) != ""
+	return f.Status != "" || f.CreatedAfter != "" ||
+		f.CreatedBefore != "" || strings.TrimSpace(f.Search) != "" ||
+		strings.TrimSpace(f.Filter) != ""
 }
 
 // HasFilters перевіряє, чи встановлені якісь фільтри
 func (f UserFilterParams) HasFilters() bool {
-	return f.Status != "" || strings.TrimSpace(f.Search) != "" || f.RoleID != ""
+	return f.Status != "" || strings.TrimSpace(f.Search) != "" || f.RoleID != "" ||
+		strings.TrimSpace(f.Filter) != ""
 }
 
 // ToMap конвертує фільтри в map для GORM
@@ -119,3 +226,37 @@ func (f UserFilterParams) ToMap() map[string]interface{} {
 
 	return filters
 }
+
+// Statuses повертає список статусів з параметра status (через кому)
+func (f DocumentFilterParams) Statuses() []string {
+	return SplitList(f.Status)
+}
+
+// Statuses повертає список статусів з параметра status (через кому)
+func (f OrganizationFilterParams) Statuses() []string {
+	return SplitList(f.Status)
+}
+
+// SplitList розбиває значення параметра, розділене комами, відкидаючи порожні елементи
+func SplitList(raw string) []string {
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// queryFloat повертає числовий параметр запиту або nil, якщо він відсутній чи некоректний
+func queryFloat(ctx *fiber.Ctx, key string) *float64 {
+	raw := ctx.Query(key, "")
+	if raw == "" {
+		return nil
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil
+	}
+	return &v
+}
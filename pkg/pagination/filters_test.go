@@ -0,0 +1,31 @@
+package pagination
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocumentFilterParams_MergeAndResolveDates(t *testing.T) {
+	min := 100.0
+	view := DocumentFilterParams{Payment: "unpaid", DeliveryBefore: "-30d", AmountGte: &min, Search: "cement", Filter: "status==sent,status==registered"}
+	query := DocumentFilterParams{Search: "steel", Filter: "currencyCode==USD"}
+
+	merged := view.Merge(query)
+	assert.Equal(t, "steel", merged.Search, "an explicit query parameter wins over the view")
+	assert.Equal(t, "unpaid", merged.Payment)
+	assert.Equal(t, &min, merged.AmountGte)
+	assert.Equal(t, "(status==sent,status==registered);(currencyCode==USD)", merged.Filter, "filters narrow the view")
+	assert.Equal(t, "currencyCode==USD", DocumentFilterParams{}.Merge(query).Filter)
+
+	now := time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)
+	resolved := merged.ResolveDates(now)
+	assert.Equal(t, "2026-09-16", resolved.DeliveryBefore)
+	assert.Equal(t, "2026-10-16", DocumentFilterParams{CreatedAfter: "today"}.ResolveDates(now).CreatedAfter)
+	assert.Equal(t, "2026-01-01", DocumentFilterParams{CreatedAfter: "2026-01-01"}.ResolveDates(now).CreatedAfter)
+
+	assert.True(t, IsRelativeDate("-7d"))
+	assert.False(t, IsRelativeDate("-7w"))
+	assert.False(t, IsRelativeDate("--7d"))
+}
@@ -8,10 +8,11 @@ import (
 
 // PaginationParams містить параметри для пагінації
 type PaginationParams struct {
-	Page     int    `query:"page" default:"1"`
-	PageSize int    `query:"page_size" default:"10"`
-	Sort     string `query:"sort" default:"created_at"`
-	Order    string `query:"order" default:"desc"`
+	Page     int      `query:"page" default:"1"`
+	PageSize int      `query:"page_size" default:"10"`
+	Sort     string   `query:"sort" default:"created_at"` // кілька полів через кому: "-delivery_date,created_at" або "deliveryDate:desc,amount:asc"
+	Order    string   `query:"order" default:"desc"`
+	Fields   []string `query:"fields"` // поля відповіді (sparse fieldset): id,status,amount
 }
 
 // PaginatedResponse містить дані з інформацією про пагінацію
@@ -20,10 +21,12 @@ type PaginatedResponse struct {
 	Pagination PaginationInfo `json:"pagination"`
 }
 
-// PaginationInfo містить інформацію про пагінацію
+// PaginationInfo містить інформацію про пагінацію; meta усіх посторінкових списків
 type PaginationInfo struct {
-	Page       int   `json:"page"`
-	PageSize   int   `json:"page_size"`
+	Page     int   `json:"page"`
+	PageSize int   `json:"page_size"`
+	Total    int64 `json:"total"`
+	// TotalItems дублює Total для клієнтів, написаних до його появи
 	TotalItems int64 `json:"total_items"`
 	TotalPages int   `json:"total_pages"`
 	HasNext    bool  `json:"has_next"`
@@ -57,6 +60,7 @@ func ExtractPaginationParams(ctx *fiber.Ctx) PaginationParams {
 		PageSize: pageSize,
 		Sort:     sort,
 		Order:    order,
+		Fields:   SplitList(ctx.Query("fields", "")),
 	}
 }
 
@@ -89,6 +93,7 @@ func NewPaginationInfo(page, pageSize int, totalItems int64) PaginationInfo {
 	return PaginationInfo{
 		Page:       page,
 		PageSize:   pageSize,
+		Total:      totalItems,
 		TotalItems: totalItems,
 		TotalPages: totalPages,
 		HasNext:    page < totalPages,
@@ -96,6 +101,15 @@ func NewPaginationInfo(page, pageSize int, totalItems int64) PaginationInfo {
 	}
 }
 
+// NewOffsetInfo створює інформацію про пагінацію для списків з offset і limit: сторінка
+// визначається як номер сторінки розміру limit, на яку потрапляє offset
+func NewOffsetInfo(offset, limit int, totalItems int64) PaginationInfo {
+	info := NewPaginationInfo(offset/limit+1, limit, totalItems)
+	info.HasNext = int64(offset+limit) < totalItems
+	info.HasPrev = offset > 0
+	return info
+}
+
 // NewPaginatedResponse створює нову пагінвану відповідь
 func NewPaginatedResponse(data interface{}, page, pageSize int, totalItems int64) PaginatedResponse {
 	return PaginatedResponse{
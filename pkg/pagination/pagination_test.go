@@ -0,0 +1,30 @@
+package pagination
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewPaginationInfo(t *testing.T) {
+	info := NewPaginationInfo(2, 10, 25)
+	assert.Equal(t, PaginationInfo{Page: 2, PageSize: 10, Total: 25, TotalItems: 25, TotalPages: 3, HasNext: true, HasPrev: true}, info)
+
+	empty := NewPaginationInfo(1, 10, 0)
+	assert.Equal(t, 1, empty.TotalPages)
+	assert.False(t, empty.HasNext)
+}
+
+func TestNewOffsetInfo(t *testing.T) {
+	info := NewOffsetInfo(20, 10, 25)
+	assert.Equal(t, 3, info.Page)
+	assert.Equal(t, 3, info.TotalPages)
+	assert.False(t, info.HasNext)
+	assert.True(t, info.HasPrev)
+
+	// Зсув не кратний розміру сторінки
+	info = NewOffsetInfo(5, 10, 25)
+	assert.Equal(t, 1, info.Page)
+	assert.True(t, info.HasNext)
+	assert.True(t, info.HasPrev)
+}
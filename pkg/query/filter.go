@@ -0,0 +1,396 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidFilter возвращается, если выражение filter не разобрано или ссылается на недоступное поле
+var ErrInvalidFilter = errors.New("invalid filter")
+
+// Ограничения выражения filter: защищают базу от слишком тяжелых запросов
+const (
+	MaxFilterLength      = 2000
+	MaxFilterComparisons = 20
+	MaxFilterDepth       = 5
+	MaxFilterValues      = 100
+)
+
+// FieldType тип значения поля: определяет разбор значения и допустимые операторы
+type FieldType int
+
+const (
+	TypeString FieldType = iota
+	TypeNumber
+	TypeDate
+	TypeBool
+	TypeUUID
+)
+
+// FilterField поле, доступное в выражении filter
+type FilterField struct {
+	Column string
+	Type   FieldType
+}
+
+// FilterFields белый список полей ресурса для выражения filter. Поля задаются в snake_case;
+// в выражении можно писать и camelCase, как в JSON ответа (deliveryDate == delivery_date).
+type FilterFields map[string]FilterField
+
+// lookup находит поле по имени из выражения
+func (f FilterFields) lookup(name string) (FilterField, bool) {
+	if field, ok := f[name]; ok {
+		return field, true
+	}
+	field, ok := f[snakeCase(name)]
+	return field, ok
+}
+
+// Filter разбирает выражение filter в стиле RSQL и возвращает условие; пустое выражение не фильтрует,
+// ошибка разбора возвращается при применении условия.
+//
+//	status==sent;amount>=1000;deliveryDate=ge=2024-01-01
+//	(currencyCode==USD,currencyCode==EUR);contractorTin=in=(01234567890123,98765432109876)
+//
+// ";" — И, "," — ИЛИ (И связывает сильнее), скобки группируют. Операторы: == != > >= < <= и
+// их формы =gt= =ge= =lt= =le=, списки =in=(...) и =out=(...), проверка =isnull=true|false.
+// В строковых полях "*" в значении == и != — подстановочный знак (регистр не учитывается).
+// Значения со спецсимволами заключаются в одинарные или двойные кавычки.
+func Filter(raw string, fields FilterFields) Condition {
+	condition, err := ParseFilter(raw, fields)
+	if err != nil {
+		return Invalid(err)
+	}
+	return condition
+}
+
+// ParseFilter разбирает выражение filter; ошибки оборачивают ErrInvalidFilter
+func ParseFilter(raw string, fields FilterFields) (Condition, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return And(), nil
+	}
+	if len(raw) > MaxFilterLength {
+		return nil, fmt.Errorf("%w: longer than %d characters", ErrInvalidFilter, MaxFilterLength)
+	}
+
+	p := &filterParser{input: raw, fields: fields}
+	node, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.input) {
+		return nil, p.errorf("unexpected %q", p.input[p.pos])
+	}
+
+	sql, args := node.sql()
+	return Expr(sql, args...), nil
+}
+
+// filterNode узел разобранного выражения
+type filterNode interface {
+	sql() (string, []interface{})
+}
+
+// filterGroup объединение условий через AND или OR
+type filterGroup struct {
+	op    string
+	nodes []filterNode
+}
+
+func (g filterGroup) sql() (string, []interface{}) {
+	parts := make([]string, 0, len(g.nodes))
+	var args []interface{}
+	for _, node := range g.nodes {
+		part, nodeArgs := node.sql()
+		parts = append(parts, part)
+		args = append(args, nodeArgs...)
+	}
+	return "(" + strings.Join(parts, " "+g.op+" ") + ")", args
+}
+
+// filterComparison сравнение колонки со значением; колонка берется из белого списка
+type filterComparison struct {
+	clause string
+	args   []interface{}
+}
+
+func (c filterComparison) sql() (string, []interface{}) {
+	return c.clause, c.args
+}
+
+type filterParser struct {
+	input       string
+	pos         int
+	fields      FilterFields
+	comparisons int
+}
+
+func (p *filterParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: %s at position %d", ErrInvalidFilter, fmt.Sprintf(format, args...), p.pos+1)
+}
+
+func (p *filterParser) skipSpaces() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+// consume пропускает token, если выражение продолжается им
+func (p *filterParser) consume(token string) bool {
+	p.skipSpaces()
+	if strings.HasPrefix(p.input[p.pos:], token) {
+		p.pos += len(token)
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) parseOr(depth int) (filterNode, error) {
+	return p.parseGroup(depth, ",", "OR", p.parseAnd)
+}
+
+func (p *filterParser) parseAnd(depth int) (filterNode, error) {
+	return p.parseGroup(depth, ";", "AND", p.parseTerm)
+}
+
+func (p *filterParser) parseGroup(depth int, separator, op string, next func(int) (filterNode, error)) (filterNode, error) {
+	node, err := next(depth)
+	if err != nil {
+		return nil, err
+	}
+	nodes := []filterNode{node}
+	for p.consume(separator) {
+		if node, err = next(depth); err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, node)
+	}
+	if len(nodes) == 1 {
+		return nodes[0], nil
+	}
+	return filterGroup{op: op, nodes: nodes}, nil
+}
+
+func (p *filterParser) parseTerm(depth int) (filterNode, error) {
+	if !p.consume("(") {
+		return p.parseComparison()
+	}
+	if depth >= MaxFilterDepth {
+		return nil, p.errorf("nesting deeper than %d", MaxFilterDepth)
+	}
+	node, err := p.parseOr(depth + 1)
+	if err != nil {
+		return nil, err
+	}
+	if !p.consume(")") {
+		return nil, p.errorf("missing )")
+	}
+	return node, nil
+}
+
+// filterOperators операторы сравнения; длинные формы проверяются раньше коротких
+var filterOperators = []struct {
+	token string
+	op    string
+}{
+	{"=isnull=", "isnull"}, {"=out=", "out"}, {"=in=", "in"},
+	{"=ge=", ">="}, {"=gt=", ">"}, {"=le=", "<="}, {"=lt=", "<"},
+	{"==", "="}, {"!=", "<>"}, {">=", ">="}, {"<=", "<="}, {">", ">"}, {"<", "<"},
+}
+
+func (p *filterParser) parseComparison() (filterNode, error) {
+	p.comparisons++
+	if p.comparisons > MaxFilterComparisons {
+		return nil, p.errorf("more than %d comparisons", MaxFilterComparisons)
+	}
+
+	p.skipSpaces()
+	start := p.pos
+	for p.pos < len(p.input) && (isIdentRune(p.input[p.pos]) || p.pos > start && isDigit(p.input[p.pos])) {
+		p.pos++
+	}
+	name := p.input[start:p.pos]
+	if name == "" {
+		return nil, p.errorf("field name expected")
+	}
+	field, ok := p.fields.lookup(name)
+	if !ok {
+		p.pos = start
+		return nil, p.errorf("unknown field %q", name)
+	}
+
+	op := ""
+	for _, candidate := range filterOperators {
+		if p.consume(candidate.token) {
+			op = candidate.op
+			break
+		}
+	}
+	if op == "" {
+		return nil, p.errorf("operator expected after %q", name)
+	}
+
+	switch op {
+	case "in", "out":
+		return p.parseList(field, op)
+	case "isnull":
+		return p.parseIsNull(field)
+	}
+
+	raw, quoted, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	if field.Type == TypeString && !quoted && strings.Contains(raw, "*") && (op == "=" || op == "<>") {
+		like := "ILIKE"
+		if op == "<>" {
+			like = "NOT ILIKE"
+		}
+		return filterComparison{clause: field.Column + " " + like + " ?", args: []interface{}{wildcardPattern(raw)}}, nil
+	}
+	if op != "=" && op != "<>" && (field.Type == TypeBool || field.Type == TypeUUID) {
+		return nil, p.errorf("operator %s is not supported for %q", op, name)
+	}
+	value, err := p.convert(field, raw)
+	if err != nil {
+		return nil, err
+	}
+	return filterComparison{clause: field.Column + " " + op + " ?", args: []interface{}{value}}, nil
+}
+
+func (p *filterParser) parseList(field FilterField, op string) (filterNode, error) {
+	if !p.consume("(") {
+		return nil, p.errorf("( expected")
+	}
+	var values []interface{}
+	for {
+		raw, _, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		value, err := p.convert(field, raw)
+		if err != nil {
+			return nil, err
+		}
+		if values = append(values, value); len(values) > MaxFilterValues {
+			return nil, p.errorf("more than %d values in list", MaxFilterValues)
+		}
+		if p.consume(")") {
+			break
+		}
+		if !p.consume(",") {
+			return nil, p.errorf(", or ) expected")
+		}
+	}
+
+	clause := field.Column + " IN ?"
+	if op == "out" {
+		clause = field.Column + " NOT IN ?"
+	}
+	return filterComparison{clause: clause, args: []interface{}{values}}, nil
+}
+
+func (p *filterParser) parseIsNull(field FilterField) (filterNode, error) {
+	raw, _, err := p.parseValue()
+	if err != nil {
+		return nil, err
+	}
+	isNull, err := strconv.ParseBool(raw)
+	if err != nil {
+		return nil, p.errorf("=isnull= expects true or false")
+	}
+	if isNull {
+		return filterComparison{clause: field.Column + " IS NULL"}, nil
+	}
+	return filterComparison{clause: field.Column + " IS NOT NULL"}, nil
+}
+
+// parseValue читает значение: в кавычках (с экранированием \) или до ближайшего ; , ( )
+func (p *filterParser) parseValue() (string, bool, error) {
+	p.skipSpaces()
+	if p.pos < len(p.input) && (p.input[p.pos] == '\'' || p.input[p.pos] == '"') {
+		quote := p.input[p.pos]
+		p.pos++
+		var b strings.Builder
+		for p.pos < len(p.input) {
+			c := p.input[p.pos]
+			p.pos++
+			switch {
+			case c == '\\' && p.pos < len(p.input):
+				b.WriteByte(p.input[p.pos])
+				p.pos++
+			case c == quote:
+				return b.String(), true, nil
+			default:
+				b.WriteByte(c)
+			}
+		}
+		return "", false, p.errorf("unterminated quoted value")
+	}
+
+	start := p.pos
+	for p.pos < len(p.input) && !strings.ContainsRune(";,()'\"", rune(p.input[p.pos])) {
+		p.pos++
+	}
+	value := strings.TrimSpace(p.input[start:p.pos])
+	if value == "" {
+		return "", false, p.errorf("value expected")
+	}
+	return value, false, nil
+}
+
+// convert приводит значение к типу поля, чтобы ошибка формата была ошибкой запроса, а не базы
+func (p *filterParser) convert(field FilterField, raw string) (interface{}, error) {
+	switch field.Type {
+	case TypeNumber:
+		v, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, p.errorf("%q is not a number", raw)
+		}
+		return v, nil
+	case TypeDate:
+		if v, err := time.Parse("2006-01-02", raw); err == nil {
+			return v, nil
+		}
+		v, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, p.errorf("%q is not a date (YYYY-MM-DD or RFC 3339)", raw)
+		}
+		return v, nil
+	case TypeBool:
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, p.errorf("%q is not a boolean", raw)
+		}
+		return v, nil
+	case TypeUUID:
+		v, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, p.errorf("%q is not a UUID", raw)
+		}
+		return v, nil
+	default:
+		return raw, nil
+	}
+}
+
+// wildcardPattern заменяет * на % и экранирует символы шаблона LIKE
+func wildcardPattern(value string) string {
+	value = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+	return strings.ReplaceAll(value, "*", "%")
+}
+
+func isIdentRune(c byte) bool {
+	return c == '_' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
@@ -0,0 +1,89 @@
+package query
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var testFilterFields = FilterFields{
+	"id":            {Column: "id", Type: TypeUUID},
+	"status":        {Column: "esf_status", Type: TypeString},
+	"amount":        {Column: "total_amount", Type: TypeNumber},
+	"delivery_date": {Column: "delivery_date", Type: TypeDate},
+	"name":          {Column: "name", Type: TypeString},
+	"is_resident":   {Column: "is_resident", Type: TypeBool},
+}
+
+func TestFilter_ComparisonsAndGroups(t *testing.T) {
+	sql := toSQL(t, New(testSchema).Where(
+		Filter("status==sent;amount>=1000;deliveryDate=ge=2024-01-01", testFilterFields),
+	))
+	assert.Contains(t, sql, "(esf_status = 'sent' AND total_amount >= 1000 AND delivery_date >= '2024-01-01 00:00:00')")
+
+	sql = toSQL(t, New(testSchema).Where(
+		Filter("(status==sent,status==registered);amount=lt=5 ; is_resident==true", testFilterFields),
+	))
+	assert.Contains(t, sql, "((esf_status = 'sent' OR esf_status = 'registered') AND total_amount < 5 AND is_resident = true)")
+
+	// ; связывает сильнее, чем ,
+	sql = toSQL(t, New(testSchema).Where(Filter("status==sent,status==draft;amount>1", testFilterFields)))
+	assert.Contains(t, sql, "(esf_status = 'sent' OR (esf_status = 'draft' AND total_amount > 1))")
+}
+
+func TestFilter_ListsNullsAndWildcards(t *testing.T) {
+	sql := toSQL(t, New(testSchema).Where(Filter(
+		"status=in=(sent,'reg,istered');status=out=(cancelled);delivery_date=isnull=false;name==*o_o*",
+		testFilterFields,
+	)))
+	assert.Contains(t, sql, "esf_status IN ('sent','reg,istered')")
+	assert.Contains(t, sql, "esf_status NOT IN ('cancelled')")
+	assert.Contains(t, sql, "delivery_date IS NOT NULL")
+	assert.Contains(t, sql, `name ILIKE '%o\_o%'`)
+
+	sql = toSQL(t, New(testSchema).Where(Filter(`name==*50%*;status!="*"`, testFilterFields)))
+	assert.Contains(t, sql, `name ILIKE '%50\%%'`)
+	assert.Contains(t, sql, `esf_status <> '*'`, "quoted values are literal")
+}
+
+func TestFilter_Invalid(t *testing.T) {
+	db := newTestDB(t).Session(&gorm.Session{DryRun: true})
+
+	tests := map[string]string{
+		"unknown field":     "password==x",
+		"injected field":    "amount;DROP TABLE users==1",
+		"missing operator":  "status=sent",
+		"missing value":     "status==",
+		"bad number":        "amount>=lots",
+		"bad date":          "delivery_date>yesterday",
+		"bad uuid":          "id==42",
+		"ordering a bool":   "is_resident>true",
+		"unclosed group":    "(status==sent",
+		"trailing input":    "status==sent)",
+		"unterminated text": "name=='acme",
+		"too many":          strings.Repeat("amount>1;", MaxFilterComparisons) + "amount>1",
+		"too deep":          strings.Repeat("(", MaxFilterDepth+1) + "amount>1" + strings.Repeat(")", MaxFilterDepth+1),
+	}
+	for name, raw := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseFilter(raw, testFilterFields)
+			require.Error(t, err)
+			assert.True(t, errors.Is(err, ErrInvalidFilter), err.Error())
+
+			_, err = New(testSchema).Where(Filter(raw, testFilterFields)).Apply(db)
+			assert.ErrorIs(t, err, ErrInvalidFilter)
+		})
+	}
+
+	_, err := ParseFilter("password==x", testFilterFields)
+	assert.EqualError(t, err, `invalid filter: unknown field "password" at position 1`)
+}
+
+func TestFilter_Empty(t *testing.T) {
+	sql := toSQL(t, New(testSchema).Where(Filter("  ", testFilterFields)))
+	assert.NotContains(t, sql, "WHERE")
+}
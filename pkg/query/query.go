@@ -0,0 +1,165 @@
+// Package query реализует компонуемый DSL для фильтрации, сортировки и
+// выборки полей поверх GORM. Имена полей из API сопоставляются с колонками
+// через Schema, поэтому в SQL никогда не попадают непроверенные строки.
+package query
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// ErrUnknownField возвращается, если поле отсутствует в схеме
+var ErrUnknownField = errors.New("unknown field")
+
+// Schema сопоставляет имена полей API с колонками таблицы
+type Schema map[string]string
+
+// Column возвращает колонку для поля API; имя принимается и в camelCase, как в JSON ответа
+func (s Schema) Column(field string) (string, error) {
+	column, ok := s[field]
+	if !ok {
+		column, ok = s[snakeCase(field)]
+	}
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownField, field)
+	}
+	return column, nil
+}
+
+// Condition — типизированное условие фильтрации
+type Condition interface {
+	apply(db *gorm.DB, schema Schema) (*gorm.DB, error)
+}
+
+type conditionFunc func(db *gorm.DB, schema Schema) (*gorm.DB, error)
+
+func (f conditionFunc) apply(db *gorm.DB, schema Schema) (*gorm.DB, error) {
+	return f(db, schema)
+}
+
+// compare строит условие "column op ?". Пустое значение (nil или nil-указатель)
+// пропускается, чтобы необязательные фильтры не требовали проверок в репозитории.
+func compare(field, op string, value interface{}) Condition {
+	return conditionFunc(func(db *gorm.DB, schema Schema) (*gorm.DB, error) {
+		if isEmpty(value) {
+			return db, nil
+		}
+		column, err := schema.Column(field)
+		if err != nil {
+			return nil, err
+		}
+		return db.Where(column+" "+op+" ?", value), nil
+	})
+}
+
+// Eq — поле равно значению
+func Eq(field string, value interface{}) Condition {
+	return compare(field, "=", value)
+}
+
+// Gte — поле больше или равно значению
+func Gte(field string, value interface{}) Condition {
+	return compare(field, ">=", value)
+}
+
+// Lte — поле меньше или равно значению
+func Lte(field string, value interface{}) Condition {
+	return compare(field, "<=", value)
+}
+
+// Between — поле в диапазоне [from, to]; любая из границ может отсутствовать
+func Between(field string, from, to interface{}) Condition {
+	return And(Gte(field, from), Lte(field, to))
+}
+
+// In — поле входит в список значений; пустой список не фильтрует
+func In[T any](field string, values []T) Condition {
+	return conditionFunc(func(db *gorm.DB, schema Schema) (*gorm.DB, error) {
+		if len(values) == 0 {
+			return db, nil
+		}
+		column, err := schema.Column(field)
+		if err != nil {
+			return nil, err
+		}
+		return db.Where(column+" IN ?", values), nil
+	})
+}
+
+// Search — регистронезависимый поиск подстроки хотя бы в одном из полей
+func Search(term string, fields ...string) Condition {
+	return conditionFunc(func(db *gorm.DB, schema Schema) (*gorm.DB, error) {
+		term = strings.TrimSpace(term)
+		if term == "" || len(fields) == 0 {
+			return db, nil
+		}
+
+		clauses := make([]string, 0, len(fields))
+		args := make([]interface{}, 0, len(fields))
+		for _, field := range fields {
+			column, err := schema.Column(field)
+			if err != nil {
+				return nil, err
+			}
+			clauses = append(clauses, column+" ILIKE ?")
+			args = append(args, "%"+term+"%")
+		}
+		return db.Where("("+strings.Join(clauses, " OR ")+")", args...), nil
+	})
+}
+
+// Expr — условие на SQL-выражении, заданном в коде приложения. Значения из запроса
+// передаются только аргументами args, само выражение из входных данных не строится.
+func Expr(sql string, args ...interface{}) Condition {
+	return conditionFunc(func(db *gorm.DB, _ Schema) (*gorm.DB, error) {
+		return db.Where(sql, args...), nil
+	})
+}
+
+// Invalid — условие, которое отклоняет запрос с ошибкой (например, недопустимое значение фильтра)
+func Invalid(err error) Condition {
+	return conditionFunc(func(*gorm.DB, Schema) (*gorm.DB, error) {
+		return nil, err
+	})
+}
+
+// And объединяет условия
+func And(conditions ...Condition) Condition {
+	return conditionFunc(func(db *gorm.DB, schema Schema) (*gorm.DB, error) {
+		var err error
+		for _, c := range conditions {
+			if db, err = c.apply(db, schema); err != nil {
+				return nil, err
+			}
+		}
+		return db, nil
+	})
+}
+
+func isEmpty(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// snakeCase переводит имя поля из camelCase: deliveryDate -> delivery_date
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
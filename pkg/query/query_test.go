@@ -0,0 +1,152 @@
+package query
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type document struct {
+	ID     string
+	Status string
+	Amount float64
+}
+
+var testSchema = Schema{
+	"id":         "id",
+	"status":     "status",
+	"amount":     "total_amount",
+	"created_at": "created_at",
+	"name":       "name",
+}
+
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.Open("host=127.0.0.1 port=1 user=test dbname=test connect_timeout=1"), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	require.NoError(t, err)
+	return db
+}
+
+func toSQL(t *testing.T, spec *Spec) string {
+	t.Helper()
+
+	var applyErr error
+	sql := newTestDB(t).ToSQL(func(tx *gorm.DB) *gorm.DB {
+		q, err := spec.Apply(tx.Model(&document{}))
+		if err != nil {
+			applyErr = err
+			return tx
+		}
+		return q.Find(&[]document{})
+	})
+	require.NoError(t, applyErr)
+	return sql
+}
+
+func TestSpec_TypedFilters(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	minAmount := 100.0
+
+	sql := toSQL(t, New(testSchema).Where(
+		In("status", []string{"active", "archived"}),
+		Between("created_at", &from, nil),
+		Gte("amount", &minAmount),
+		Search("acme", "name"),
+	))
+
+	assert.Contains(t, sql, "status IN ('active','archived')")
+	assert.Contains(t, sql, "created_at >= '2025-01-01 00:00:00'")
+	assert.NotContains(t, sql, "created_at <=")
+	assert.Contains(t, sql, "total_amount >= 100")
+	assert.Contains(t, sql, "(name ILIKE '%acme%')")
+}
+
+func TestSpec_EmptyFiltersAreSkipped(t *testing.T) {
+	var maxAmount *float64
+
+	sql := toSQL(t, New(testSchema).Where(
+		In[string]("status", nil),
+		Lte("amount", maxAmount),
+		Search("  ", "name"),
+	))
+
+	assert.NotContains(t, sql, "WHERE")
+}
+
+func TestSpec_MultiSortAndProjection(t *testing.T) {
+	sql := toSQL(t, New(testSchema).
+		OrderBy(ParseSort("-amount,created_at", "asc")...).
+		Select("status", "amount"))
+
+	assert.Contains(t, sql, `SELECT "id","status",total_amount FROM`)
+	assert.Contains(t, sql, "ORDER BY total_amount desc,created_at asc,id asc")
+}
+
+func TestSpec_ExprAndInvalid(t *testing.T) {
+	sql := toSQL(t, New(testSchema).Where(Expr("(total_amount - paid) >= ?", 0.005)))
+	assert.Contains(t, sql, "(total_amount - paid) >= 0.005")
+
+	errBad := errors.New("bad filter")
+	_, err := New(testSchema).Where(Invalid(errBad)).Apply(newTestDB(t).Session(&gorm.Session{DryRun: true}))
+	assert.ErrorIs(t, err, errBad)
+}
+
+func TestSpec_UnknownField(t *testing.T) {
+	db := newTestDB(t).Session(&gorm.Session{DryRun: true})
+
+	tests := []struct {
+		name string
+		spec *Spec
+	}{
+		{"filter", New(testSchema).Where(Eq("password", "x"))},
+		{"sort", New(testSchema).OrderBy(Sort{Field: "id; DROP TABLE users"})},
+		{"projection", New(testSchema).Select("secret")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.spec.Apply(db)
+			assert.True(t, errors.Is(err, ErrUnknownField))
+		})
+	}
+}
+
+func TestParseSort(t *testing.T) {
+	assert.Equal(t, []Sort{
+		{Field: "delivery_date", Desc: true},
+		{Field: "created_at", Desc: true},
+		{Field: "name", Desc: false},
+	}, ParseSort("-delivery_date, created_at,+name,", "desc"))
+
+	assert.Empty(t, ParseSort("", "asc"))
+
+	assert.Equal(t, []Sort{
+		{Field: "deliveryDate", Desc: true},
+		{Field: "amount", Desc: false},
+		{Field: "name:sideways", Desc: false},
+	}, ParseSort("deliveryDate:desc,amount:asc,name:sideways", "asc"))
+}
+
+func TestSpec_SortByAPINamesWithIDTiebreak(t *testing.T) {
+	schema := Schema{"id": "id", "delivery_date": "delivery_date", "amount": "total_amount"}
+
+	sql := toSQL(t, New(schema).OrderBy(ParseSort("deliveryDate:desc,amount:asc", "desc")...))
+	assert.Contains(t, sql, "ORDER BY delivery_date desc,total_amount asc,id asc")
+
+	sql = toSQL(t, New(schema).OrderBy(ParseSort("id:desc,amount", "asc")...))
+	assert.Contains(t, sql, "ORDER BY id desc,total_amount asc")
+	assert.NotContains(t, sql, "total_amount asc,id")
+
+	_, err := New(schema).OrderBy(ParseSort("amount:sideways", "asc")...).Apply(newTestDB(t).Session(&gorm.Session{DryRun: true}))
+	assert.ErrorIs(t, err, ErrUnknownField)
+}
@@ -0,0 +1,169 @@
+package query
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Sort описывает сортировку по одному полю
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSort разбирает строку вида "-delivery_date,created_at" или "deliveryDate:desc,amount:asc".
+// Префикс "-" или суффикс ":desc" задает убывание, "+" или ":asc" — возрастание, без них
+// используется defaultOrder ("asc" или "desc"). Неизвестное направление оставляется в имени
+// поля, и Apply отклоняет его как неизвестное поле.
+func ParseSort(raw, defaultOrder string) []Sort {
+	var sorts []Sort
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		s := Sort{Field: part, Desc: defaultOrder == "desc"}
+		switch {
+		case part[0] == '-':
+			s = Sort{Field: part[1:], Desc: true}
+		case part[0] == '+':
+			s = Sort{Field: part[1:], Desc: false}
+		case strings.HasSuffix(part, ":desc"):
+			s = Sort{Field: strings.TrimSuffix(part, ":desc"), Desc: true}
+		case strings.HasSuffix(part, ":asc"):
+			s = Sort{Field: strings.TrimSuffix(part, ":asc"), Desc: false}
+		}
+		sorts = append(sorts, s)
+	}
+	return sorts
+}
+
+// Spec — спецификация запроса: условия, сортировка и проекция
+type Spec struct {
+	schema     Schema
+	conditions []Condition
+	sorts      []Sort
+	fields     []string
+}
+
+// New создает пустую спецификацию для схемы
+func New(schema Schema) *Spec {
+	return &Spec{schema: schema}
+}
+
+// Where добавляет условия фильтрации
+func (s *Spec) Where(conditions ...Condition) *Spec {
+	s.conditions = append(s.conditions, conditions...)
+	return s
+}
+
+// OrderBy добавляет сортировку
+func (s *Spec) OrderBy(sorts ...Sort) *Spec {
+	s.sorts = append(s.sorts, sorts...)
+	return s
+}
+
+// Select ограничивает выборку указанными полями
+func (s *Spec) Select(fields ...string) *Spec {
+	s.fields = append(s.fields, fields...)
+	return s
+}
+
+// Filter применяет к запросу только условия (например, для подсчета записей)
+func (s *Spec) Filter(db *gorm.DB) (*gorm.DB, error) {
+	return And(s.conditions...).apply(db, s.schema)
+}
+
+// Apply применяет условия, проекцию и сортировку. Если в схеме есть поле id,
+// оно всегда попадает в проекцию и замыкает сортировку для стабильного порядка.
+func (s *Spec) Apply(db *gorm.DB) (*gorm.DB, error) {
+	db, err := s.Filter(db)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s.fields) > 0 {
+		columns, err := s.columns()
+		if err != nil {
+			return nil, err
+		}
+		db = db.Select(columns)
+	}
+
+	id, hasID := s.schema["id"]
+	sortedByID := false
+	for _, sort := range s.sorts {
+		column, err := s.schema.Column(sort.Field)
+		if err != nil {
+			return nil, err
+		}
+		if hasID && column == id {
+			sortedByID = true
+		}
+		db = db.Order(column + " " + direction(sort.Desc))
+	}
+
+	if hasID && len(s.sorts) > 0 && !sortedByID {
+		db = db.Order(id + " " + direction(s.sorts[len(s.sorts)-1].Desc))
+	}
+
+	return db, nil
+}
+
+func (s *Spec) columns() ([]string, error) {
+	columns := make([]string, 0, len(s.fields)+1)
+	seen := make(map[string]bool, len(s.fields)+1)
+
+	if id, ok := s.schema["id"]; ok {
+		columns = append(columns, id)
+		seen[id] = true
+	}
+
+	for _, field := range s.fields {
+		column, err := s.schema.Column(field)
+		if err != nil {
+			return nil, err
+		}
+		if !seen[column] {
+			columns = append(columns, column)
+			seen[column] = true
+		}
+	}
+	return columns, nil
+}
+
+func direction(desc bool) string {
+	if desc {
+		return "desc"
+	}
+	return "asc"
+}
+
+// Page применяет спецификацию и возвращает страницу записей вместе с общим
+// количеством записей, удовлетворяющих условиям. scopes применяются только
+// к выборке записей (например, Preload), но не к подсчету.
+func Page[T any](db *gorm.DB, spec *Spec, offset, limit int, scopes ...func(*gorm.DB) *gorm.DB) ([]T, int64, error) {
+	filtered, err := spec.Filter(db)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int64
+	if err := filtered.Session(&gorm.Session{}).Model(new(T)).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query, err := spec.Apply(db)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var items []T
+	if err := query.Scopes(scopes...).Offset(offset).Limit(limit).Find(&items).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return items, total, nil
+}
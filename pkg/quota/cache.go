@@ -0,0 +1,173 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultCachePrefix префикс ключей расхода в Redis
+const DefaultCachePrefix = "quota:usage"
+
+// resources ресурсы, расход которых кешируется; Invalidate сбрасывает их все
+var resources = []Resource{ResourceDocuments, ResourceStorage}
+
+// UsageCache кеш расхода организаций. Реализация в Redis общая для всех экземпляров, поэтому
+// расход, учтенный одной репликой, сразу виден остальным.
+type UsageCache interface {
+	// Get возвращает расход из кеша; false — значения нет или оно истекло
+	Get(ctx context.Context, orgID uuid.UUID, resource Resource) (int64, bool, error)
+	// Set сохраняет расход на ttl
+	Set(ctx context.Context, orgID uuid.UUID, resource Resource, value int64, ttl time.Duration) error
+	// Incr увеличивает расход, только если он есть в кеше
+	Incr(ctx context.Context, orgID uuid.UUID, resource Resource, amount int64) error
+	// Invalidate сбрасывает расход организации по всем ресурсам
+	Invalidate(ctx context.Context, orgID uuid.UUID) error
+	// InvalidateResource сбрасывает расход ресурса всех организаций
+	InvalidateResource(ctx context.Context, resource Resource) error
+}
+
+// memoryUsageCache кеш расхода в памяти экземпляра
+type memoryUsageCache struct {
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[usageKey]cachedUsage
+}
+
+func newMemoryUsageCache(now func() time.Time) *memoryUsageCache {
+	return &memoryUsageCache{now: now, entries: make(map[usageKey]cachedUsage)}
+}
+
+func (c *memoryUsageCache) Get(_ context.Context, orgID uuid.UUID, resource Resource) (int64, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cached, ok := c.entries[usageKey{orgID: orgID, resource: resource}]
+	if !ok || !c.now().Before(cached.expires) {
+		return 0, false, nil
+	}
+	return cached.value, true, nil
+}
+
+func (c *memoryUsageCache) Set(_ context.Context, orgID uuid.UUID, resource Resource, value int64, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[usageKey{orgID: orgID, resource: resource}] = cachedUsage{value: value, expires: c.now().Add(ttl)}
+	return nil
+}
+
+func (c *memoryUsageCache) Incr(_ context.Context, orgID uuid.UUID, resource Resource, amount int64) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := usageKey{orgID: orgID, resource: resource}
+	if cached, ok := c.entries[key]; ok && c.now().Before(cached.expires) {
+		cached.value += amount
+		c.entries[key] = cached
+	}
+	return nil
+}
+
+func (c *memoryUsageCache) Invalidate(_ context.Context, orgID uuid.UUID) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if key.orgID == orgID {
+			delete(c.entries, key)
+		}
+	}
+	return nil
+}
+
+func (c *memoryUsageCache) InvalidateResource(_ context.Context, resource Resource) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key := range c.entries {
+		if key.resource == resource {
+			delete(c.entries, key)
+		}
+	}
+	return nil
+}
+
+// incrScript увеличивает расход, не создавая ключ: отсутствующее значение прочитается из БД
+var incrScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 1 then
+	return redis.call('INCRBY', KEYS[1], ARGV[1])
+end
+return 0
+`)
+
+// redisUsageCache кеш расхода в Redis: <prefix>:<orgID>:<resource> с TTL кеша
+type redisUsageCache struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisUsageCache создает общий для экземпляров кеш расхода; пустой prefix — DefaultCachePrefix
+func NewRedisUsageCache(client *redis.Client, prefix string) UsageCache {
+	if prefix == "" {
+		prefix = DefaultCachePrefix
+	}
+	return &redisUsageCache{client: client, prefix: prefix}
+}
+
+func (c *redisUsageCache) key(orgID uuid.UUID, resource Resource) string {
+	return c.prefix + ":" + orgID.String() + ":" + string(resource)
+}
+
+func (c *redisUsageCache) Get(ctx context.Context, orgID uuid.UUID, resource Resource) (int64, bool, error) {
+	raw, err := c.client.Get(ctx, c.key(orgID, resource)).Result()
+	if errors.Is(err, redis.Nil) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	value, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, false, nil
+	}
+	return value, true, nil
+}
+
+func (c *redisUsageCache) Set(ctx context.Context, orgID uuid.UUID, resource Resource, value int64, ttl time.Duration) error {
+	return c.client.Set(ctx, c.key(orgID, resource), value, ttl).Err()
+}
+
+func (c *redisUsageCache) Incr(ctx context.Context, orgID uuid.UUID, resource Resource, amount int64) error {
+	return incrScript.Run(ctx, c.client, []string{c.key(orgID, resource)}, amount).Err()
+}
+
+func (c *redisUsageCache) Invalidate(ctx context.Context, orgID uuid.UUID) error {
+	keys := make([]string, 0, len(resources))
+	for _, resource := range resources {
+		keys = append(keys, c.key(orgID, resource))
+	}
+	return c.client.Del(ctx, keys...).Err()
+}
+
+func (c *redisUsageCache) InvalidateResource(ctx context.Context, resource Resource) error {
+	iter := c.client.Scan(ctx, 0, c.prefix+":*:"+string(resource), 500).Iterator()
+	var keys []string
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+		if len(keys) == 500 {
+			if err := c.client.Del(ctx, keys...).Err(); err != nil {
+				return err
+			}
+			keys = keys[:0]
+		}
+	}
+	if err := iter.Err(); err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	return c.client.Del(ctx, keys...).Err()
+}
@@ -0,0 +1,53 @@
+package quota
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+)
+
+// Require создает middleware, который до обработчика проверяет квоту resource организации запроса.
+// orgID извлекает организацию (ошибку сообщит сам обработчик), amount — расход операции.
+// После успешного ответа (2xx) расход учитывается в кеше. С nil enforcer квоты не проверяются.
+func Require(enforcer *Enforcer, resource Resource, orgID func(*fiber.Ctx) (uuid.UUID, error), amount func(*fiber.Ctx) int64) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if enforcer == nil {
+			return ctx.Next()
+		}
+		id, err := orgID(ctx)
+		if err != nil {
+			return ctx.Next()
+		}
+
+		n := amount(ctx)
+		if err := enforcer.Check(ctx.Context(), id, resource, n); err != nil {
+			return response.FromError(ctx, err, apperror.ErrInternal, "failed to check quota")
+		}
+
+		if err := ctx.Next(); err != nil {
+			return err
+		}
+		if status := ctx.Response().StatusCode(); status >= 200 && status < 300 {
+			enforcer.Add(ctx.Context(), id, resource, n)
+		}
+		return nil
+	}
+}
+
+// One расход в одну единицу ресурса, например один созданный документ
+func One(*fiber.Ctx) int64 {
+	return 1
+}
+
+// FormFileSize расход, равный размеру файла из multipart-поля field; без файла — 0
+func FormFileSize(field string) func(*fiber.Ctx) int64 {
+	return func(ctx *fiber.Ctx) int64 {
+		header, err := ctx.FormFile(field)
+		if err != nil {
+			return 0
+		}
+		return header.Size
+	}
+}
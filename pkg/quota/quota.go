@@ -0,0 +1,259 @@
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+)
+
+// Resource ресурс, расход которого ограничивает тарифный план
+type Resource string
+
+const (
+	// ResourceDocuments документы ЭСФ, созданные организацией за текущий календарный месяц (UTC)
+	ResourceDocuments Resource = "documents"
+	// ResourceStorage суммарный размер вложений организации в байтах
+	ResourceStorage Resource = "storage"
+)
+
+// DefaultCacheTTL время, в течение которого расход берется из кеша, а не из БД и хранилища
+const DefaultCacheTTL = time.Minute
+
+// Plan тарифный план. Лимит 0 или отсутствующий ресурс — без ограничения.
+type Plan struct {
+	Name   string             `json:"name"`
+	Limits map[Resource]int64 `json:"limits"`
+	// Upgrade план, который предлагается при исчерпании квоты; пустой — предложения нет
+	Upgrade string `json:"upgrade,omitempty"`
+}
+
+// DefaultPlans планы, если QUOTA_PLANS не задан
+var DefaultPlans = []Plan{
+	{Name: "free", Limits: map[Resource]int64{ResourceDocuments: 50, ResourceStorage: 100 << 20}, Upgrade: "standard"},
+	{Name: "standard", Limits: map[Resource]int64{ResourceDocuments: 1000, ResourceStorage: 5 << 30}, Upgrade: "enterprise"},
+	{Name: "enterprise"},
+}
+
+// Config параметры проверки квот
+type Config struct {
+	Enabled bool
+	Plans   []Plan
+	// DefaultPlan план организаций без назначенного или с неизвестным планом
+	DefaultPlan string
+	CacheTTL    time.Duration
+	// UpgradeURL страница смены тарифа, возвращается клиенту в подсказке
+	UpgradeURL string
+	// Cache кеш расхода; nil — в памяти экземпляра. С несколькими репликами нужен общий
+	// кеш (NewRedisUsageCache), иначе каждая реплика видит только свой расход до истечения CacheTTL.
+	Cache UsageCache
+}
+
+// ParsePlans разбирает JSON-массив планов из QUOTA_PLANS:
+//
+//	[{"name":"free","limits":{"documents":50,"storage":104857600},"upgrade":"standard"}]
+func ParsePlans(raw string) ([]Plan, error) {
+	var plans []Plan
+	if err := json.Unmarshal([]byte(raw), &plans); err != nil {
+		return nil, fmt.Errorf("invalid QUOTA_PLANS: %w", err)
+	}
+	return plans, nil
+}
+
+// Usage сообщает текущий расход ресурса организацией
+type Usage interface {
+	Usage(ctx context.Context, orgID uuid.UUID, resource Resource) (int64, error)
+}
+
+// PlanResolver возвращает имя тарифного плана организации; пустое — план по умолчанию
+type PlanResolver interface {
+	Plan(ctx context.Context, orgID uuid.UUID) (string, error)
+}
+
+// Exceeded подсказка клиенту при исчерпании квоты, передается в поле data ответа об ошибке
+type Exceeded struct {
+	Resource    Resource `json:"resource"`
+	Plan        string   `json:"plan"`
+	Limit       int64    `json:"limit"`
+	Used        int64    `json:"used"`
+	Requested   int64    `json:"requested"`
+	UpgradePlan string   `json:"upgradePlan,omitempty"`
+	UpgradeURL  string   `json:"upgradeUrl,omitempty"`
+}
+
+type usageKey struct {
+	orgID    uuid.UUID
+	resource Resource
+}
+
+type cachedUsage struct {
+	value   int64
+	expires time.Time
+}
+
+// Enforcer проверяет расход организации по лимитам ее плана. Расход кешируется на CacheTTL
+// и увеличивается после каждой успешной операции, поэтому БД не опрашивается на каждый запрос.
+type Enforcer struct {
+	plans       map[string]Plan
+	defaultPlan string
+	cacheTTL    time.Duration
+	upgradeURL  string
+	resolver    PlanResolver
+	usage       Usage
+	now         func() time.Time
+	cache       UsageCache
+}
+
+// NewEnforcer создает проверку квот; план по умолчанию должен быть среди планов
+func NewEnforcer(cfg Config, resolver PlanResolver, usage Usage) (*Enforcer, error) {
+	if len(cfg.Plans) == 0 {
+		cfg.Plans = DefaultPlans
+	}
+	if cfg.CacheTTL <= 0 {
+		cfg.CacheTTL = DefaultCacheTTL
+	}
+
+	plans := make(map[string]Plan, len(cfg.Plans))
+	for _, p := range cfg.Plans {
+		if p.Name == "" {
+			return nil, fmt.Errorf("quota plan without name")
+		}
+		if _, ok := plans[p.Name]; ok {
+			return nil, fmt.Errorf("duplicate quota plan %q", p.Name)
+		}
+		plans[p.Name] = p
+	}
+	for _, p := range cfg.Plans {
+		if p.Upgrade != "" {
+			if _, ok := plans[p.Upgrade]; !ok {
+				return nil, fmt.Errorf("quota plan %q upgrades to unknown plan %q", p.Name, p.Upgrade)
+			}
+		}
+	}
+	if _, ok := plans[cfg.DefaultPlan]; !ok {
+		return nil, fmt.Errorf("unknown default quota plan %q", cfg.DefaultPlan)
+	}
+
+	e := &Enforcer{
+		plans:       plans,
+		defaultPlan: cfg.DefaultPlan,
+		cacheTTL:    cfg.CacheTTL,
+		upgradeURL:  cfg.UpgradeURL,
+		resolver:    resolver,
+		usage:       usage,
+		now:         time.Now,
+		cache:       cfg.Cache,
+	}
+	if e.cache == nil {
+		e.cache = newMemoryUsageCache(func() time.Time { return e.now() })
+	}
+	return e, nil
+}
+
+// HasPlan сообщает, известен ли план
+func (e *Enforcer) HasPlan(name string) bool {
+	_, ok := e.plans[name]
+	return ok
+}
+
+// PlanNames возвращает имена планов по алфавиту
+func (e *Enforcer) PlanNames() []string {
+	names := make([]string, 0, len(e.plans))
+	for name := range e.plans {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Check разрешает операцию, если после нее расход ресурса не превысит лимит плана.
+// При превышении возвращает ошибку QUOTA_EXCEEDED с подсказкой Exceeded в data.
+func (e *Enforcer) Check(ctx context.Context, orgID uuid.UUID, resource Resource, amount int64) error {
+	plan, err := e.planOf(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	limit := plan.Limits[resource]
+	if limit <= 0 {
+		return nil
+	}
+
+	used, err := e.used(ctx, orgID, resource)
+	if err != nil {
+		return apperror.From(err, apperror.ErrInternal, "failed to check quota")
+	}
+	if used+amount <= limit {
+		return nil
+	}
+
+	hint := Exceeded{
+		Resource:    resource,
+		Plan:        plan.Name,
+		Limit:       limit,
+		Used:        used,
+		Requested:   amount,
+		UpgradePlan: plan.Upgrade,
+	}
+	if plan.Upgrade != "" {
+		hint.UpgradeURL = e.upgradeURL
+	}
+	return apperror.New(apperror.ErrQuotaExceeded, "{resource} quota exceeded on plan {plan}").
+		WithParams(map[string]interface{}{"resource": string(resource), "plan": plan.Name}).
+		WithData(hint)
+}
+
+// Add учитывает в кеше расход после успешной операции; при пустом кеше ничего не делает,
+// следующий Check прочитает точное значение. Ошибка кеша не влияет на операцию: расход
+// перечитается из БД после истечения CacheTTL.
+func (e *Enforcer) Add(ctx context.Context, orgID uuid.UUID, resource Resource, amount int64) {
+	_ = e.cache.Incr(ctx, orgID, resource, amount)
+}
+
+// Invalidate сбрасывает кеш расхода организации, например после смены плана или удаления файлов
+func (e *Enforcer) Invalidate(ctx context.Context, orgID uuid.UUID) error {
+	return e.cache.Invalidate(ctx, orgID)
+}
+
+// ResetPeriod сбрасывает кеш расхода ресурсов, считаемых за календарный месяц, у всех организаций.
+// Вызывается в начале месяца, чтобы расход прошлого месяца не отклонял операции до истечения CacheTTL.
+func (e *Enforcer) ResetPeriod(ctx context.Context) error {
+	return e.cache.InvalidateResource(ctx, ResourceDocuments)
+}
+
+// NextPeriodStart начало следующего расчетного периода (календарного месяца UTC) после t
+func NextPeriodStart(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// planOf возвращает план организации; неизвестный план считается планом по умолчанию
+func (e *Enforcer) planOf(ctx context.Context, orgID uuid.UUID) (Plan, error) {
+	name, err := e.resolver.Plan(ctx, orgID)
+	if err != nil {
+		return Plan{}, apperror.From(err, apperror.ErrInternal, "failed to resolve organization plan")
+	}
+	if plan, ok := e.plans[name]; ok {
+		return plan, nil
+	}
+	return e.plans[e.defaultPlan], nil
+}
+
+// used возвращает расход из кеша или запрашивает его у Usage; недоступный кеш не мешает проверке
+func (e *Enforcer) used(ctx context.Context, orgID uuid.UUID, resource Resource) (int64, error) {
+	if value, ok, err := e.cache.Get(ctx, orgID, resource); err == nil && ok {
+		return value, nil
+	}
+
+	value, err := e.usage.Usage(ctx, orgID, resource)
+	if err != nil {
+		return 0, err
+	}
+
+	_ = e.cache.Set(ctx, orgID, resource, value, e.cacheTTL)
+	return value, nil
+}
@@ -0,0 +1,238 @@
+package quota
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+)
+
+// stubSource план и расход организаций в памяти; reads считает обращения к Usage
+type stubSource struct {
+	plans map[uuid.UUID]string
+	usage map[Resource]int64
+	reads int
+}
+
+func (s *stubSource) Plan(ctx context.Context, orgID uuid.UUID) (string, error) {
+	return s.plans[orgID], nil
+}
+
+func (s *stubSource) Usage(ctx context.Context, orgID uuid.UUID, resource Resource) (int64, error) {
+	s.reads++
+	return s.usage[resource], nil
+}
+
+func newTestEnforcer(t *testing.T, src *stubSource) *Enforcer {
+	t.Helper()
+	e, err := NewEnforcer(Config{
+		Plans: []Plan{
+			{Name: "free", Limits: map[Resource]int64{ResourceDocuments: 2}, Upgrade: "pro"},
+			{Name: "pro"},
+		},
+		DefaultPlan: "free",
+		UpgradeURL:  "https://example.com/billing",
+	}, src, src)
+	require.NoError(t, err)
+	return e
+}
+
+func TestEnforcer_CheckReturnsUpgradeHint(t *testing.T) {
+	src := &stubSource{usage: map[Resource]int64{ResourceDocuments: 1}}
+	e := newTestEnforcer(t, src)
+	orgID := uuid.New()
+	ctx := context.Background()
+
+	require.NoError(t, e.Check(ctx, orgID, ResourceDocuments, 1))
+	e.Add(ctx, orgID, ResourceDocuments, 1)
+
+	err := e.Check(ctx, orgID, ResourceDocuments, 1)
+	var appErr *apperror.AppError
+	require.True(t, errors.As(err, &appErr))
+	assert.Equal(t, apperror.ErrQuotaExceeded, appErr.Code)
+	assert.Equal(t, fiber.StatusPaymentRequired, appErr.HTTPStatus)
+	assert.Equal(t, Exceeded{
+		Resource: ResourceDocuments, Plan: "free", Limit: 2, Used: 2, Requested: 1,
+		UpgradePlan: "pro", UpgradeURL: "https://example.com/billing",
+	}, appErr.Data)
+	assert.Equal(t, 1, src.reads, "usage is cached between checks")
+
+	// Ресурс без лимита в плане не ограничен
+	assert.NoError(t, e.Check(ctx, orgID, ResourceStorage, 1<<40))
+}
+
+func TestEnforcer_PlanOfOrganization(t *testing.T) {
+	paid, unknown := uuid.New(), uuid.New()
+	src := &stubSource{
+		plans: map[uuid.UUID]string{paid: "pro", unknown: "legacy"},
+		usage: map[Resource]int64{ResourceDocuments: 100},
+	}
+	e := newTestEnforcer(t, src)
+	ctx := context.Background()
+
+	assert.NoError(t, e.Check(ctx, paid, ResourceDocuments, 1))
+	assert.Error(t, e.Check(ctx, unknown, ResourceDocuments, 1), "unknown plan falls back to the default")
+	assert.Error(t, e.Check(ctx, uuid.New(), ResourceDocuments, 1), "no plan means the default")
+}
+
+func TestEnforcer_CacheExpiresAndInvalidates(t *testing.T) {
+	src := &stubSource{usage: map[Resource]int64{ResourceDocuments: 2}}
+	e := newTestEnforcer(t, src)
+	now := time.Now()
+	e.now = func() time.Time { return now }
+	orgID := uuid.New()
+	ctx := context.Background()
+
+	require.Error(t, e.Check(ctx, orgID, ResourceDocuments, 1))
+
+	// Документы удалены в начале месяца: после истечения кеша расход перечитывается
+	src.usage[ResourceDocuments] = 0
+	assert.Error(t, e.Check(ctx, orgID, ResourceDocuments, 1))
+	now = now.Add(DefaultCacheTTL)
+	assert.NoError(t, e.Check(ctx, orgID, ResourceDocuments, 1))
+
+	src.usage[ResourceDocuments] = 2
+	require.NoError(t, e.Invalidate(ctx, orgID))
+	assert.Error(t, e.Check(ctx, orgID, ResourceDocuments, 1))
+	assert.Equal(t, 3, src.reads)
+}
+
+func TestEnforcer_ResetPeriod(t *testing.T) {
+	src := &stubSource{usage: map[Resource]int64{ResourceDocuments: 2}}
+	e := newTestEnforcer(t, src)
+	first, second := uuid.New(), uuid.New()
+	ctx := context.Background()
+
+	require.Error(t, e.Check(ctx, first, ResourceDocuments, 1))
+	require.Error(t, e.Check(ctx, second, ResourceDocuments, 1))
+	require.NoError(t, e.Check(ctx, first, ResourceStorage, 1))
+
+	// В новом месяце расход документов перечитывается у всех организаций, расход места остается в кеше
+	src.usage[ResourceDocuments] = 0
+	require.NoError(t, e.ResetPeriod(ctx))
+	assert.NoError(t, e.Check(ctx, first, ResourceDocuments, 1))
+	assert.NoError(t, e.Check(ctx, second, ResourceDocuments, 1))
+	assert.Equal(t, 4, src.reads)
+}
+
+func TestNextPeriodStart(t *testing.T) {
+	bishkek := time.FixedZone("KGT", 6*60*60)
+	assert.Equal(t, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+		NextPeriodStart(time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC)))
+	assert.Equal(t, time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+		NextPeriodStart(time.Date(2026, 12, 31, 23, 59, 59, 0, time.UTC)))
+	// Месяц считается по UTC: 1 ноября 05:00 в Бишкеке — еще октябрь
+	assert.Equal(t, time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+		NextPeriodStart(time.Date(2026, 11, 1, 5, 0, 0, 0, bishkek)))
+}
+
+func TestNewEnforcer_Validation(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+	}{
+		{"unknown default plan", Config{DefaultPlan: "gold"}},
+		{"plan without name", Config{Plans: []Plan{{}}, DefaultPlan: "free"}},
+		{"duplicate plan", Config{Plans: []Plan{{Name: "free"}, {Name: "free"}}, DefaultPlan: "free"}},
+		{"unknown upgrade", Config{Plans: []Plan{{Name: "free", Upgrade: "gold"}}, DefaultPlan: "free"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewEnforcer(tt.cfg, &stubSource{}, &stubSource{})
+			assert.Error(t, err)
+		})
+	}
+
+	e, err := NewEnforcer(Config{DefaultPlan: "free"}, &stubSource{}, &stubSource{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"enterprise", "free", "standard"}, e.PlanNames())
+}
+
+func TestParsePlans(t *testing.T) {
+	plans, err := ParsePlans(`[{"name":"free","limits":{"documents":10,"storage":1024},"upgrade":"pro"},{"name":"pro"}]`)
+	require.NoError(t, err)
+	require.Len(t, plans, 2)
+	assert.Equal(t, int64(1024), plans[0].Limits[ResourceStorage])
+
+	_, err = ParsePlans(`{`)
+	assert.Error(t, err)
+}
+
+func TestRequire(t *testing.T) {
+	src := &stubSource{usage: map[Resource]int64{ResourceDocuments: 1}}
+	e := newTestEnforcer(t, src)
+	orgID := uuid.New()
+	orgFromHeader := func(ctx *fiber.Ctx) (uuid.UUID, error) { return uuid.Parse(ctx.Get("X-Org-Id")) }
+
+	app := fiber.New()
+	app.Post("/docs", Require(e, ResourceDocuments, orgFromHeader, One), func(ctx *fiber.Ctx) error {
+		if ctx.Query("fail") != "" {
+			return ctx.SendStatus(fiber.StatusBadRequest)
+		}
+		return ctx.SendStatus(fiber.StatusCreated)
+	})
+	app.Post("/open", Require(nil, ResourceDocuments, orgFromHeader, One), func(ctx *fiber.Ctx) error {
+		return ctx.SendStatus(fiber.StatusCreated)
+	})
+	post := func(path, org string) int {
+		req := httptest.NewRequest(fiber.MethodPost, path, nil)
+		req.Header.Set("X-Org-Id", org)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp.StatusCode
+	}
+
+	// Неудачный запрос не расходует квоту
+	assert.Equal(t, fiber.StatusBadRequest, post("/docs?fail=1", orgID.String()))
+	assert.Equal(t, fiber.StatusCreated, post("/docs", orgID.String()))
+	assert.Equal(t, fiber.StatusPaymentRequired, post("/docs", orgID.String()))
+
+	// Без организации проверку пропускает, ошибку сообщит обработчик
+	assert.Equal(t, fiber.StatusCreated, post("/docs", "not-a-uuid"))
+	assert.Equal(t, fiber.StatusCreated, post("/open", orgID.String()))
+}
+
+func TestEnforcer_RedisCacheSharedBetweenInstances(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		t.Skip("Redis not running, skipping tests")
+	}
+	defer client.Close()
+
+	cache := NewRedisUsageCache(client, "quota-test-"+uuid.NewString()[:8])
+	src := &stubSource{usage: map[Resource]int64{ResourceDocuments: 0}}
+	newEnforcer := func() *Enforcer {
+		e, err := NewEnforcer(Config{
+			Plans:       []Plan{{Name: "free", Limits: map[Resource]int64{ResourceDocuments: 2}}},
+			DefaultPlan: "free",
+			Cache:       cache,
+		}, src, src)
+		require.NoError(t, err)
+		return e
+	}
+	first, second := newEnforcer(), newEnforcer()
+	orgID := uuid.New()
+	ctx := context.Background()
+	defer cache.Invalidate(ctx, orgID)
+
+	// Расход, учтенный одним экземпляром, виден другому
+	require.NoError(t, first.Check(ctx, orgID, ResourceDocuments, 1))
+	first.Add(ctx, orgID, ResourceDocuments, 1)
+	require.NoError(t, second.Check(ctx, orgID, ResourceDocuments, 1))
+	second.Add(ctx, orgID, ResourceDocuments, 1)
+	assert.Error(t, first.Check(ctx, orgID, ResourceDocuments, 1))
+	assert.Equal(t, 1, src.reads)
+
+	require.NoError(t, second.Invalidate(ctx, orgID))
+	src.usage[ResourceDocuments] = 0
+	assert.NoError(t, first.Check(ctx, orgID, ResourceDocuments, 1))
+}
@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// localSweepInterval how often idle buckets are dropped from memory
+const localSweepInterval = time.Minute
+
+// tokenBucket refills at RequestsPerMinute tokens per Window up to RequestsPerMinute
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+	window  time.Duration
+}
+
+// localLimiter keeps per-instance token buckets used while Redis is unavailable.
+// Each replica counts only its own requests, so the effective limit is multiplied
+// by the number of replicas until Redis is back.
+type localLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+}
+
+func newLocalLimiter() *localLimiter {
+	return &localLimiter{buckets: make(map[string]*tokenBucket)}
+}
+
+// allow takes a token for key and returns whether the request fits, the tokens left
+// and the share of the limit used
+func (l *localLimiter) allow(key string, config LimitConfig, now time.Time) (bool, int, float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.sweep(now)
+
+	limit := float64(config.RequestsPerMinute)
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: limit, updated: now, window: config.Window}
+		l.buckets[key] = b
+	} else if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens = min(limit, b.tokens+limit*elapsed.Seconds()/config.Window.Seconds())
+		b.updated = now
+	}
+
+	if b.tokens < 1 {
+		return false, 0, 1
+	}
+	b.tokens--
+	return true, int(b.tokens), (limit - b.tokens) / limit
+}
+
+// sweep drops buckets that have been idle long enough to be full again
+func (l *localLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < localSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for key, b := range l.buckets {
+		if now.Sub(b.updated) >= b.window {
+			delete(l.buckets, key)
+		}
+	}
+}
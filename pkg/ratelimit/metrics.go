@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/rusgainew/tunduck-app/pkg/metrics"
+)
+
+// Check outcomes reported in ratelimit_checks_total
+const (
+	resultAllowed  = "allowed"
+	resultRejected = "rejected"
+)
+
+// Backends that made the decision, reported in ratelimit_checks_total
+const (
+	backendRedis = "redis"
+	// backendLocal per-instance token buckets used while Redis is unavailable
+	backendLocal = "local"
+)
+
+// Principal kinds reported in ratelimit_rejections_total. Individual identifiers are not
+// used as labels to keep cardinality bounded; they are logged with every rejection.
+const (
+	PrincipalIP   = "ip"
+	PrincipalUser = "user"
+)
+
+type limiterMetrics struct {
+	checks         *prometheus.CounterVec
+	rejections     *prometheus.CounterVec
+	usage          *prometheus.HistogramVec
+	redisLatency   prometheus.Gauge
+	fallbackActive prometheus.Gauge
+}
+
+func newLimiterMetrics(reg prometheus.Registerer) *limiterMetrics {
+	return &limiterMetrics{
+		checks: metrics.Register(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ratelimit_checks_total",
+			Help: "Total number of rate limit checks by category, result (allowed, rejected) and backend (redis, local)",
+		}, []string{"category", "result", "backend"})),
+		rejections: metrics.Register(reg, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "ratelimit_rejections_total",
+			Help: "Total number of rate-limited requests by category, route and principal kind (ip, user)",
+		}, []string{"category", "route", "principal"})),
+		usage: metrics.Register(reg, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "ratelimit_usage_ratio",
+			Help:    "Share of the limit used by a principal within the current window, observed on every check",
+			Buckets: []float64{0.1, 0.25, 0.5, 0.75, 0.9, 1, 1.5, 2},
+		}, []string{"category"})),
+		redisLatency: metrics.Register(reg, prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "ratelimit_redis_latency_seconds",
+			Help: "Latency of the most recent rate limiter round trip to Redis",
+		})),
+		fallbackActive: metrics.Register(reg, prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "ratelimit_fallback_active",
+			Help: "1 while the rate limiter uses in-memory buckets because Redis is unavailable",
+		})),
+	}
+}
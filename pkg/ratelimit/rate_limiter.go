@@ -3,14 +3,25 @@ package ratelimit
 import (
 	"context"
 	"fmt"
+	"sync/atomic"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/redis/go-redis/v9"
 )
 
-// RateLimiter handles request rate limiting using Redis as a backend
+// redisRetryInterval how long the limiter stays on in-memory buckets after a Redis error
+// before trying Redis again, so requests do not each wait for a failing connection
+const redisRetryInterval = 5 * time.Second
+
+// RateLimiter handles request rate limiting using Redis as a backend.
+// While Redis is unavailable it falls back to per-instance in-memory token buckets.
 type RateLimiter struct {
 	redisClient *redis.Client
+	local       *localLimiter
+	// redisDownUntil unix nanoseconds until which Redis is skipped
+	redisDownUntil atomic.Int64
+	metrics        *limiterMetrics
 }
 
 // LimitConfig contains configuration for different rate limit scenarios
@@ -28,10 +39,17 @@ var DefaultLimits = map[string]LimitConfig{
 	"sensitive": {RequestsPerMinute: 5, Window: time.Minute},   // logout, password change
 }
 
-// NewRateLimiter creates a new rate limiter instance
+// NewRateLimiter creates a new rate limiter instance with metrics in the default Prometheus registry
 func NewRateLimiter(redisClient *redis.Client) *RateLimiter {
+	return NewRateLimiterWithRegisterer(redisClient, prometheus.DefaultRegisterer)
+}
+
+// NewRateLimiterWithRegisterer creates a rate limiter that registers its metrics in reg
+func NewRateLimiterWithRegisterer(redisClient *redis.Client, reg prometheus.Registerer) *RateLimiter {
 	return &RateLimiter{
 		redisClient: redisClient,
+		local:       newLocalLimiter(),
+		metrics:     newLimiterMetrics(reg),
 	}
 }
 
@@ -49,27 +67,52 @@ func (rl *RateLimiter) IsAllowed(ctx context.Context, identifier string, categor
 	now := time.Now()
 	resetTime := now.Add(config.Window)
 
-	// Use Redis INCR with expiration for rate limiting
-	pipe := rl.redisClient.Pipeline()
+	if now.UnixNano() >= rl.redisDownUntil.Load() {
+		// Use Redis INCR with expiration for rate limiting
+		pipe := rl.redisClient.Pipeline()
 
-	incrCmd := pipe.Incr(ctx, key)
-	pipe.Expire(ctx, key, config.Window)
-	_, err := pipe.Exec(ctx)
-	if err != nil && err != redis.Nil {
-		// On Redis error, allow request (graceful degradation)
-		return true, config.RequestsPerMinute, resetTime, nil
-	}
+		incrCmd := pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, config.Window)
+		_, err := pipe.Exec(ctx)
+		rl.metrics.redisLatency.Set(time.Since(now).Seconds())
+		if err == nil || err == redis.Nil {
+			rl.metrics.fallbackActive.Set(0)
+
+			count := incrCmd.Val()
+			allowed := count <= int64(config.RequestsPerMinute)
+			remaining := int(config.RequestsPerMinute) - int(count)
+			if remaining < 0 {
+				remaining = 0
+			}
 
-	count := incrCmd.Val()
-	allowed := count <= int64(config.RequestsPerMinute)
-	remaining := int(config.RequestsPerMinute) - int(count)
-	if remaining < 0 {
-		remaining = 0
+			rl.record(category, backendRedis, allowed, float64(count)/float64(config.RequestsPerMinute))
+			return allowed, remaining, resetTime, nil
+		}
+		rl.redisDownUntil.Store(now.Add(redisRetryInterval).UnixNano())
 	}
 
+	// Redis is unavailable: limit per instance instead of failing open
+	rl.metrics.fallbackActive.Set(1)
+	allowed, remaining, usage := rl.local.allow(key, config, now)
+	rl.record(category, backendLocal, allowed, usage)
 	return allowed, remaining, resetTime, nil
 }
 
+// record counts a check and observes the share of the limit used
+func (rl *RateLimiter) record(category, backend string, allowed bool, usage float64) {
+	result := resultAllowed
+	if !allowed {
+		result = resultRejected
+	}
+	rl.metrics.checks.WithLabelValues(category, result, backend).Inc()
+	rl.metrics.usage.WithLabelValues(category).Observe(usage)
+}
+
+// RecordRejection counts a rejected request by route pattern and principal kind (PrincipalIP, PrincipalUser)
+func (rl *RateLimiter) RecordRejection(category, route, principal string) {
+	rl.metrics.rejections.WithLabelValues(category, route, principal).Inc()
+}
+
 // Reset clears the rate limit counter for an identifier
 func (rl *RateLimiter) Reset(ctx context.Context, identifier string, category string) error {
 	key := fmt.Sprintf("ratelimit:%s:%s", category, identifier)
@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter_FallsBackToLocalBuckets(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	defer client.Close()
+	rl := NewRateLimiterWithRegisterer(client, prometheus.NewRegistry())
+
+	limit := DefaultLimits["sensitive"].RequestsPerMinute
+	for i := 0; i < limit; i++ {
+		allowed, remaining, _, err := rl.IsAllowed(context.Background(), "10.0.0.1", "sensitive")
+		require.NoError(t, err)
+		assert.True(t, allowed)
+		assert.Equal(t, limit-i-1, remaining)
+	}
+
+	// Without Redis the limit is enforced per instance instead of being switched off
+	allowed, _, _, err := rl.IsAllowed(context.Background(), "10.0.0.1", "sensitive")
+	require.NoError(t, err)
+	assert.False(t, allowed)
+
+	allowed, _, _, _ = rl.IsAllowed(context.Background(), "10.0.0.2", "sensitive")
+	assert.True(t, allowed, "other principals have their own buckets")
+
+	assert.Equal(t, float64(limit+1), testutil.ToFloat64(rl.metrics.checks.WithLabelValues("sensitive", resultAllowed, backendLocal)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(rl.metrics.checks.WithLabelValues("sensitive", resultRejected, backendLocal)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(rl.metrics.fallbackActive))
+	assert.Greater(t, testutil.ToFloat64(rl.metrics.redisLatency), float64(0))
+
+	rl.RecordRejection("sensitive", "/api/auth/logout", PrincipalUser)
+	assert.Equal(t, float64(1), testutil.ToFloat64(rl.metrics.rejections.WithLabelValues("sensitive", "/api/auth/logout", PrincipalUser)))
+}
+
+func TestLocalLimiter_RefillsAndSweeps(t *testing.T) {
+	l := newLocalLimiter()
+	config := LimitConfig{RequestsPerMinute: 2, Window: time.Minute}
+	now := time.Unix(1700000000, 0)
+
+	allowed, _, _ := l.allow("k", config, now)
+	assert.True(t, allowed)
+	allowed, _, usage := l.allow("k", config, now)
+	assert.True(t, allowed)
+	assert.Equal(t, float64(1), usage)
+	allowed, _, _ = l.allow("k", config, now)
+	assert.False(t, allowed)
+
+	// Half a window refills one of the two tokens
+	allowed, remaining, _ := l.allow("k", config, now.Add(30*time.Second))
+	assert.True(t, allowed)
+	assert.Equal(t, 0, remaining)
+
+	l.allow("other", config, now.Add(2*time.Minute))
+	assert.Len(t, l.buckets, 1, "idle bucket is dropped")
+}
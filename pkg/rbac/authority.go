@@ -0,0 +1,62 @@
+package rbac
+
+import (
+	"context"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+)
+
+// GrantKey используется для хранения Grant в контексте Fiber
+const GrantKey = "rbac_grant"
+
+// Grant подтвержденное право пользователя на действие: собственное по роли
+// или делегированное другим пользователем на срок
+type Grant struct {
+	UserID     uuid.UUID
+	Permission Permission
+	// DelegatorID и DelegationID заполнены, если право получено по делегированию
+	DelegatorID  *uuid.UUID
+	DelegationID *uuid.UUID
+}
+
+// Delegated сообщает, что пользователь действует от имени другого пользователя
+func (g *Grant) Delegated() bool {
+	return g != nil && g.DelegationID != nil
+}
+
+// Authority определяет права пользователя с учетом действующих делегирований
+type Authority interface {
+	// Authorize возвращает Grant или ошибку 403, если права нет ни по роли, ни по делегированию
+	Authorize(ctx context.Context, userID uuid.UUID, permission Permission) (*Grant, error)
+}
+
+// GrantFromContext возвращает Grant, подтвержденный RequireAuthority для текущего запроса, или nil
+func GrantFromContext(ctx context.Context) *Grant {
+	if ctx == nil {
+		return nil
+	}
+	grant, _ := ctx.Value(GrantKey).(*Grant)
+	return grant
+}
+
+// RequireAuthority создает middleware, который проверяет право permission через authority.
+// userID извлекает пользователя из запроса (обычно middleware.GetUserIDFromContext)
+func RequireAuthority(permission Permission, authority Authority, userID func(*fiber.Ctx) (uuid.UUID, error)) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		id, err := userID(ctx)
+		if err != nil {
+			return response.Error(ctx, apperror.From(err, apperror.ErrUnauthorized, "user is not authenticated"))
+		}
+
+		grant, err := authority.Authorize(ctx.Context(), id, permission)
+		if err != nil {
+			return response.Error(ctx, apperror.From(err, apperror.ErrInternal, "failed to check permissions"))
+		}
+
+		ctx.Locals(GrantKey, grant)
+		return ctx.Next()
+	}
+}
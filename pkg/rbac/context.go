@@ -1,6 +1,8 @@
 package rbac
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 )
 
@@ -18,6 +20,16 @@ func NewUserContext(userID uuid.UUID, role Role) *UserContext {
 	}
 }
 
+// FromContext возвращает контекст пользователя из ctx.Context() запроса Fiber или nil;
+// сервисы используют его для проверок разрешений внутри бизнес-логики
+func FromContext(ctx context.Context) *UserContext {
+	if ctx == nil {
+		return nil
+	}
+	uc, _ := ctx.Value(ContextKey).(*UserContext)
+	return uc
+}
+
 // HasPermission проверяет, есть ли у пользователя определенное разрешение
 func (uc *UserContext) HasPermission(permission Permission) bool {
 	if uc == nil || !uc.Role.IsValid() {
@@ -4,6 +4,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/response"
 )
 
 // ContextKey используется для хранения UserContext в контексте Fiber
@@ -30,8 +31,8 @@ func RequireRole(allowedRoles ...Role) fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
 		userCtx := ExtractUserContext(ctx)
 		if userCtx == nil {
-			appErr := apperror.New(apperror.ErrUnauthorized, "пользователь не авторизован")
-			return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+			appErr := apperror.New(apperror.ErrUnauthorized, "user is not authenticated")
+			return response.Error(ctx, appErr)
 		}
 
 		// Проверяем, есть ли роль пользователя в разрешенных
@@ -44,8 +45,8 @@ func RequireRole(allowedRoles ...Role) fiber.Handler {
 		}
 
 		if !allowed {
-			appErr := apperror.New(apperror.ErrForbidden, "недостаточно прав доступа")
-			return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+			appErr := apperror.New(apperror.ErrForbidden, "insufficient access rights")
+			return response.Error(ctx, appErr)
 		}
 
 		return ctx.Next()
@@ -57,13 +58,13 @@ func RequirePermission(permission Permission) fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
 		userCtx := ExtractUserContext(ctx)
 		if userCtx == nil {
-			appErr := apperror.New(apperror.ErrUnauthorized, "пользователь не авторизован")
-			return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+			appErr := apperror.New(apperror.ErrUnauthorized, "user is not authenticated")
+			return response.Error(ctx, appErr)
 		}
 
 		if !userCtx.HasPermission(permission) {
-			appErr := apperror.New(apperror.ErrForbidden, "недостаточно прав для выполнения этого действия")
-			return ctx.Status(appErr.HTTPStatus).JSON(appErr.ToResponse())
+			appErr := apperror.New(apperror.ErrForbidden, "insufficient permissions to perform this action")
+			return response.Error(ctx, appErr)
 		}
 
 		return ctx.Next()
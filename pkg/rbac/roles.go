@@ -44,6 +44,13 @@ const (
 	// Права для ролей
 	PermissionAssignRole Permission = "assign:role"
 	PermissionViewRoles  Permission = "view:roles"
+
+	// PermissionOverridePrice разрешает продажу ниже минимальной цены прайс-листа
+	PermissionOverridePrice Permission = "override:price"
+
+	// PermissionSendDocument право подписи: отправка документов в ЭСФ. Может быть
+	// передано другому пользователю на срок (см. Authority)
+	PermissionSendDocument Permission = "send:document"
 )
 
 // RolePermissions определяет какие разрешения есть у каждой роли
@@ -54,6 +61,7 @@ var RolePermissions = map[Role][]Permission{
 		PermissionCreateDocument, PermissionReadDocument, PermissionUpdateDocument, PermissionDeleteDocument,
 		PermissionCreateUser, PermissionReadUser, PermissionUpdateUser, PermissionDeleteUser,
 		PermissionAssignRole, PermissionViewRoles,
+		PermissionOverridePrice, PermissionSendDocument,
 	},
 	RoleUser: {
 		// Обычный пользователь может читать и создавать
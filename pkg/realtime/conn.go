@@ -0,0 +1,145 @@
+package realtime
+
+import (
+	"encoding/json"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/fasthttp/websocket"
+)
+
+// Параметры соединения подписчика по умолчанию
+const (
+	DefaultReadLimit    = 64 << 10
+	DefaultPingInterval = 30 * time.Second
+	DefaultPongTimeout  = 10 * time.Second
+	DefaultWriteTimeout = 10 * time.Second
+)
+
+// ConnConfig параметры соединения WebSocket подписчика
+type ConnConfig struct {
+	// ReadLimit максимальный размер входящего сообщения; больше — соединение закрывается с кодом 1009
+	ReadLimit int64
+	// PingInterval период отправки ping; соединение закрывается, если за PingInterval+PongTimeout
+	// от собеседника не пришло ни одного сообщения или pong. Отрицательное значение отключает ping.
+	PingInterval time.Duration
+	PongTimeout  time.Duration
+	WriteTimeout time.Duration
+}
+
+func (c ConnConfig) withDefaults() ConnConfig {
+	if c.ReadLimit <= 0 {
+		c.ReadLimit = DefaultReadLimit
+	}
+	if c.PingInterval == 0 {
+		c.PingInterval = DefaultPingInterval
+	}
+	if c.PongTimeout <= 0 {
+		c.PongTimeout = DefaultPongTimeout
+	}
+	if c.WriteTimeout <= 0 {
+		c.WriteTimeout = DefaultWriteTimeout
+	}
+	return c
+}
+
+// Conn соединение WebSocket подписчика поверх fasthttp/websocket: добавляет keepalive через
+// ping/pong и разрешает запись из нескольких горутин. Чтение — из одной горутины.
+type Conn struct {
+	ws  *websocket.Conn
+	cfg ConnConfig
+
+	writeMu sync.Mutex
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// NewConn настраивает соединение по cfg и, если ping не отключен, запускает keepalive
+func NewConn(ws *websocket.Conn, cfg ConnConfig) *Conn {
+	c := &Conn{ws: ws, cfg: cfg.withDefaults(), done: make(chan struct{})}
+	ws.SetReadLimit(c.cfg.ReadLimit)
+	if c.cfg.PingInterval > 0 {
+		c.extendReadDeadline()
+		ws.SetPongHandler(func(string) error {
+			c.extendReadDeadline()
+			return nil
+		})
+		go c.keepalive()
+	}
+	return c
+}
+
+// RemoteAddr адрес собеседника
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.ws.RemoteAddr()
+}
+
+// Done закрывается при закрытии соединения
+func (c *Conn) Done() <-chan struct{} {
+	return c.done
+}
+
+// ReadJSON читает сообщение и разбирает его как JSON; ошибка чтения закрывает соединение
+func (c *Conn) ReadJSON(v interface{}) error {
+	_, data, err := c.ws.ReadMessage()
+	if err != nil {
+		c.closeConn()
+		return err
+	}
+	c.extendReadDeadline()
+	return json.Unmarshal(data, v)
+}
+
+// WriteJSON отправляет v текстовым сообщением
+func (c *Conn) WriteJSON(v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_ = c.ws.SetWriteDeadline(time.Now().Add(c.cfg.WriteTimeout))
+	return c.ws.WriteMessage(websocket.TextMessage, data)
+}
+
+// Close отправляет close с кодом 1000 и закрывает соединение
+func (c *Conn) Close() error {
+	msg := websocket.FormatCloseMessage(websocket.CloseNormalClosure, "")
+	_ = c.ws.WriteControl(websocket.CloseMessage, msg, time.Now().Add(c.cfg.WriteTimeout))
+	return c.closeConn()
+}
+
+func (c *Conn) closeConn() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.done)
+		err = c.ws.Close()
+	})
+	return err
+}
+
+func (c *Conn) extendReadDeadline() {
+	if c.cfg.PingInterval > 0 {
+		_ = c.ws.SetReadDeadline(time.Now().Add(c.cfg.PingInterval + c.cfg.PongTimeout))
+	}
+}
+
+// keepalive периодически отправляет ping, пока соединение открыто
+func (c *Conn) keepalive() {
+	ticker := time.NewTicker(c.cfg.PingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			if err := c.ws.WriteControl(websocket.PingMessage, nil, time.Now().Add(c.cfg.WriteTimeout)); err != nil {
+				c.closeConn()
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package realtime
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fasthttp/websocket"
+	fiberws "github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// startEchoServer отвечает на каждое JSON-сообщение им же
+func startEchoServer(t *testing.T, cfg ConnConfig) string {
+	t.Helper()
+	app := fiber.New(fiber.Config{DisableStartupMessage: true})
+	app.Get("/echo", fiberws.New(func(ws *fiberws.Conn) {
+		conn := NewConn(ws.Conn, cfg)
+		defer conn.Close()
+		for {
+			var msg map[string]string
+			if err := conn.ReadJSON(&msg); err != nil {
+				return
+			}
+			_ = conn.WriteJSON(msg)
+		}
+	}))
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	go func() { _ = app.Listener(ln) }()
+	t.Cleanup(func() { _ = app.Shutdown() })
+	return "ws://" + ln.Addr().String() + "/echo"
+}
+
+func TestConn_KeepaliveAndReadLimit(t *testing.T) {
+	url := startEchoServer(t, ConnConfig{ReadLimit: 100, PingInterval: 20 * time.Millisecond, PongTimeout: 50 * time.Millisecond})
+	client, _, err := websocket.DefaultDialer.DialContext(context.Background(), url, nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	// Клиент отвечает на ping сервера при чтении, соединение живет дольше PingInterval+PongTimeout
+	done := make(chan map[string]string, 1)
+	go func() {
+		var msg map[string]string
+		_ = client.ReadJSON(&msg)
+		done <- msg
+	}()
+	time.Sleep(150 * time.Millisecond)
+	require.NoError(t, client.WriteJSON(map[string]string{"text": "late"}))
+	select {
+	case msg := <-done:
+		assert.Equal(t, "late", msg["text"])
+	case <-time.After(2 * time.Second):
+		t.Fatal("no echo received")
+	}
+
+	// Сообщение больше ReadLimit закрывает соединение с кодом 1009
+	require.NoError(t, client.WriteJSON(map[string]string{"text": strings.Repeat("x", 101)}))
+	_, _, err = client.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseMessageTooBig), "unexpected error: %v", err)
+}
+
+func TestConn_ClosesIdleConnection(t *testing.T) {
+	url := startEchoServer(t, ConnConfig{PingInterval: 20 * time.Millisecond, PongTimeout: 30 * time.Millisecond})
+	client, _, err := websocket.DefaultDialer.DialContext(context.Background(), url, nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	// Клиент не читает и не отвечает на ping — сервер закрывает соединение
+	time.Sleep(200 * time.Millisecond)
+	require.NoError(t, client.SetReadDeadline(time.Now().Add(2*time.Second)))
+	_, _, err = client.ReadMessage()
+	var netErr net.Error
+	require.Error(t, err)
+	assert.False(t, errors.As(err, &netErr) && netErr.Timeout(), "connection is still open")
+}
@@ -0,0 +1,49 @@
+package realtime
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/events"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+)
+
+// forwardedEvents доменные события, которые дублируются подписчикам канала организации
+var forwardedEvents = map[string]string{
+	events.DocumentStatusChanged: TypeDocumentStatus,
+}
+
+// EventForwarder публикует доменные события в шину и дублирует часть из них
+// в канал организации. Ошибка доставки уведомления не влияет на публикацию в шину.
+type EventForwarder struct {
+	next   events.Publisher
+	hub    *Hub
+	logger *logger.Logger
+}
+
+// NewEventForwarder оборачивает публикатор шины событий
+func NewEventForwarder(next events.Publisher, hub *Hub, log *logrus.Logger) *EventForwarder {
+	return &EventForwarder{next: next, hub: hub, logger: logger.New(log)}
+}
+
+// Publish реализует events.Publisher
+func (f *EventForwarder) Publish(ctx context.Context, event entity.OutboxEvent) error {
+	if err := f.next.Publish(ctx, event); err != nil {
+		return err
+	}
+
+	msgType, ok := forwardedEvents[event.Type]
+	if !ok {
+		return nil
+	}
+	if err := f.hub.Publish(ctx, OrgChannel(event.OrganizationID), msgType, event.Payload); err != nil {
+		f.logger.Warn(ctx, "Failed to publish realtime notification", logrus.Fields{
+			"event_id": event.EventID.String(),
+			"type":     event.Type,
+			"error":    err.Error(),
+		})
+	}
+	return nil
+}
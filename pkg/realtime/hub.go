@@ -0,0 +1,282 @@
+// Package realtime доставляет уведомления подписчикам (соединениям WebSocket) по каналам.
+// Сообщения публикуются в Redis pub/sub, поэтому подписчик получает их независимо от того,
+// на каком инстансе приложения произошло событие.
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+)
+
+// Параметры хаба по умолчанию
+const (
+	DefaultPrefix     = "realtime"
+	DefaultBufferSize = 64
+)
+
+// Типы уведомлений
+const (
+	// TypeDocumentStatus статус документа в ЭСФ изменен (данные events.StatusPayload)
+	TypeDocumentStatus = "document.status_changed"
+	// TypeMention пользователь упомянут в комментарии
+	TypeMention = "comment.mention"
+	// TypeImportProgress прогресс импорта документов организации
+	TypeImportProgress = "import.progress"
+	// TypeDraftReminder черновик документа долго не отправляется (данные DraftReminder)
+	TypeDraftReminder = "document.draft_reminder"
+)
+
+// OrgChannel канал уведомлений организации
+func OrgChannel(orgID uuid.UUID) string {
+	return "org:" + orgID.String()
+}
+
+// UserChannel личный канал пользователя (упоминания и т.п.)
+func UserChannel(userID uuid.UUID) string {
+	return "user:" + userID.String()
+}
+
+// DraftReminder данные уведомления TypeDraftReminder
+type DraftReminder struct {
+	DocumentID    uuid.UUID `json:"documentId"`
+	ContractorTin string    `json:"contractorTin"`
+	EsfStatus     string    `json:"esfStatus,omitempty"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+// Message уведомление в канале
+type Message struct {
+	Channel   string          `json:"channel"`
+	Type      string          `json:"type"`
+	Data      json.RawMessage `json:"data,omitempty"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// Config параметры хаба
+type Config struct {
+	// Prefix префикс каналов Redis: сообщения канала X публикуются в "<prefix>:X"
+	Prefix string
+	// BufferSize размер очереди подписчика; при переполнении сообщения отбрасываются
+	BufferSize int
+}
+
+// Hub рассылает сообщения локальным подписчикам. Без Redis работает в пределах процесса.
+type Hub struct {
+	client *redis.Client
+	cfg    Config
+	logger *logger.Logger
+
+	mu       sync.RWMutex
+	channels map[string]map[*Subscription]struct{}
+
+	// relaying true, пока Run получает сообщения из Redis; иначе Publish доставляет локально
+	relaying atomic.Bool
+	dropped  atomic.Int64
+}
+
+// NewHub создает хаб; client может быть nil для работы в одном процессе
+func NewHub(client *redis.Client, cfg Config, log *logrus.Logger) *Hub {
+	if cfg.Prefix == "" {
+		cfg.Prefix = DefaultPrefix
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = DefaultBufferSize
+	}
+	return &Hub{
+		client:   client,
+		cfg:      cfg,
+		logger:   logger.New(log),
+		channels: make(map[string]map[*Subscription]struct{}),
+	}
+}
+
+// Run получает сообщения всех инстансов из Redis и рассылает их локальным подписчикам
+// до отмены ctx. go-redis переподключается к Redis автоматически.
+func (h *Hub) Run(ctx context.Context) {
+	if h.client == nil {
+		return
+	}
+
+	pubsub := h.client.PSubscribe(ctx, h.cfg.Prefix+":*")
+	defer pubsub.Close()
+
+	if _, err := pubsub.Receive(ctx); err != nil {
+		// Redis недоступен: уведомления доставляются только подписчикам этого инстанса
+		h.logger.Warn(ctx, "Realtime hub cannot subscribe to Redis, delivering locally", logrus.Fields{"error": err.Error()})
+	} else {
+		h.relaying.Store(true)
+		defer h.relaying.Store(false)
+		h.logger.Info(ctx, "Realtime hub started", logrus.Fields{"prefix": h.cfg.Prefix})
+	}
+
+	ch := pubsub.Channel()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case raw, ok := <-ch:
+			if !ok {
+				return
+			}
+			var msg Message
+			if err := json.Unmarshal([]byte(raw.Payload), &msg); err != nil {
+				h.logger.Warn(ctx, "Invalid realtime message", logrus.Fields{"channel": raw.Channel, "error": err.Error()})
+				continue
+			}
+			msg.Channel = strings.TrimPrefix(raw.Channel, h.cfg.Prefix+":")
+			h.dispatch(msg)
+		}
+	}
+}
+
+// Publish отправляет уведомление в канал всем инстансам. data сериализуется в JSON.
+func (h *Hub) Publish(ctx context.Context, channel, msgType string, data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	msg := Message{Channel: channel, Type: msgType, Data: raw, Timestamp: time.Now().UTC()}
+
+	if !h.relaying.Load() {
+		h.dispatch(msg)
+		return nil
+	}
+
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if err := h.client.Publish(ctx, h.cfg.Prefix+":"+channel, payload).Err(); err != nil {
+		// Подписчики этого инстанса получат уведомление и без Redis
+		h.dispatch(msg)
+		return err
+	}
+	return nil
+}
+
+// Subscribe создает подписку без каналов; каналы добавляются через Join
+func (h *Hub) Subscribe() *Subscription {
+	return &Subscription{
+		hub:      h,
+		messages: make(chan Message, h.cfg.BufferSize),
+		channels: make(map[string]struct{}),
+	}
+}
+
+// Stats количество каналов с подписчиками и отброшенных из-за переполнения сообщений
+func (h *Hub) Stats() (channels int, dropped int64) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.channels), h.dropped.Load()
+}
+
+func (h *Hub) dispatch(msg Message) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for sub := range h.channels[msg.Channel] {
+		select {
+		case sub.messages <- msg:
+		default:
+			h.dropped.Add(1)
+		}
+	}
+}
+
+// Subscription подписка одного соединения на набор каналов
+type Subscription struct {
+	hub      *Hub
+	messages chan Message
+
+	mu       sync.Mutex
+	channels map[string]struct{}
+	closed   bool
+}
+
+// Messages канал входящих уведомлений; закрывается после Close
+func (s *Subscription) Messages() <-chan Message {
+	return s.messages
+}
+
+// Join подписывает на канал
+func (s *Subscription) Join(channel string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	if _, ok := s.channels[channel]; ok {
+		return
+	}
+	s.channels[channel] = struct{}{}
+
+	s.hub.mu.Lock()
+	subs := s.hub.channels[channel]
+	if subs == nil {
+		subs = make(map[*Subscription]struct{})
+		s.hub.channels[channel] = subs
+	}
+	subs[s] = struct{}{}
+	s.hub.mu.Unlock()
+}
+
+// Leave отписывает от канала
+func (s *Subscription) Leave(channel string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.channels[channel]; !ok {
+		return
+	}
+	delete(s.channels, channel)
+	s.hub.remove(channel, s)
+}
+
+// Channels каналы подписки
+func (s *Subscription) Channels() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	channels := make([]string, 0, len(s.channels))
+	for channel := range s.channels {
+		channels = append(channels, channel)
+	}
+	return channels
+}
+
+// Close отписывает от всех каналов и закрывает Messages
+func (s *Subscription) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	for channel := range s.channels {
+		s.hub.remove(channel, s)
+	}
+	s.channels = nil
+
+	// После удаления из хаба dispatch больше не пишет в канал
+	s.hub.mu.Lock()
+	close(s.messages)
+	s.hub.mu.Unlock()
+}
+
+func (h *Hub) remove(channel string, sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	subs := h.channels[channel]
+	delete(subs, sub)
+	if len(subs) == 0 {
+		delete(h.channels, channel)
+	}
+}
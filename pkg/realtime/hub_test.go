@@ -0,0 +1,151 @@
+package realtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/events"
+)
+
+func quietLogger() *logrus.Logger {
+	log := logrus.New()
+	log.SetLevel(logrus.PanicLevel)
+	return log
+}
+
+func receive(t *testing.T, sub *Subscription) Message {
+	t.Helper()
+	select {
+	case msg := <-sub.Messages():
+		return msg
+	case <-time.After(2 * time.Second):
+		t.Fatal("no message received")
+		return Message{}
+	}
+}
+
+func assertNoMessage(t *testing.T, sub *Subscription) {
+	t.Helper()
+	select {
+	case msg := <-sub.Messages():
+		t.Fatalf("unexpected message %+v", msg)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestHub_Local(t *testing.T) {
+	hub := NewHub(nil, Config{BufferSize: 1}, quietLogger())
+	ctx := context.Background()
+	orgID := uuid.New()
+
+	sub := hub.Subscribe()
+	other := hub.Subscribe()
+	sub.Join(OrgChannel(orgID))
+	sub.Join(OrgChannel(orgID))
+	other.Join(OrgChannel(uuid.New()))
+
+	require.NoError(t, hub.Publish(ctx, OrgChannel(orgID), TypeImportProgress, map[string]int{"progress": 50}))
+	msg := receive(t, sub)
+	assert.Equal(t, OrgChannel(orgID), msg.Channel)
+	assert.Equal(t, TypeImportProgress, msg.Type)
+	assert.JSONEq(t, `{"progress":50}`, string(msg.Data))
+	assertNoMessage(t, other)
+
+	// Переполненная очередь не блокирует публикацию
+	require.NoError(t, hub.Publish(ctx, OrgChannel(orgID), TypeImportProgress, 1))
+	require.NoError(t, hub.Publish(ctx, OrgChannel(orgID), TypeImportProgress, 2))
+	_, dropped := hub.Stats()
+	assert.Equal(t, int64(1), dropped)
+	receive(t, sub)
+
+	sub.Leave(OrgChannel(orgID))
+	require.NoError(t, hub.Publish(ctx, OrgChannel(orgID), TypeImportProgress, 3))
+	assertNoMessage(t, sub)
+
+	other.Close()
+	_, ok := <-other.Messages()
+	assert.False(t, ok)
+	channels, _ := hub.Stats()
+	assert.Zero(t, channels)
+}
+
+type stubPublisher struct {
+	published []entity.OutboxEvent
+	err       error
+}
+
+func (p *stubPublisher) Publish(ctx context.Context, event entity.OutboxEvent) error {
+	if p.err != nil {
+		return p.err
+	}
+	p.published = append(p.published, event)
+	return nil
+}
+
+func TestEventForwarder(t *testing.T) {
+	hub := NewHub(nil, Config{}, quietLogger())
+	bus := &stubPublisher{}
+	forwarder := NewEventForwarder(bus, hub, quietLogger())
+	ctx := context.Background()
+
+	orgID := uuid.New()
+	sub := hub.Subscribe()
+	defer sub.Close()
+	sub.Join(OrgChannel(orgID))
+
+	payload, _ := json.Marshal(events.StatusPayload{ID: uuid.New(), Status: "registered"})
+	statusEvent := entity.OutboxEvent{EventID: uuid.New(), Type: events.DocumentStatusChanged, OrganizationID: orgID, Payload: payload}
+
+	require.NoError(t, forwarder.Publish(ctx, entity.OutboxEvent{EventID: uuid.New(), Type: events.DocumentCreated, OrganizationID: orgID}))
+	require.NoError(t, forwarder.Publish(ctx, statusEvent))
+	assert.Len(t, bus.published, 2)
+
+	msg := receive(t, sub)
+	assert.Equal(t, TypeDocumentStatus, msg.Type)
+	assert.JSONEq(t, string(payload), string(msg.Data))
+	assertNoMessage(t, sub)
+
+	// Событие, не опубликованное в шину, не рассылается: ретранслятор повторит его
+	bus.err = errors.New("bus unavailable")
+	assert.Error(t, forwarder.Publish(ctx, statusEvent))
+	assertNoMessage(t, sub)
+}
+
+func TestHub_RedisFanOut(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		t.Skip("Redis not running, skipping tests")
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Два хаба с общим префиксом имитируют два инстанса приложения
+	cfg := Config{Prefix: "realtime-test-" + uuid.NewString()[:8]}
+	first := NewHub(client, cfg, quietLogger())
+	second := NewHub(client, cfg, quietLogger())
+	go first.Run(ctx)
+	go second.Run(ctx)
+	require.Eventually(t, func() bool { return first.relaying.Load() && second.relaying.Load() }, 2*time.Second, 10*time.Millisecond)
+
+	userID := uuid.New()
+	sub := second.Subscribe()
+	defer sub.Close()
+	sub.Join(UserChannel(userID))
+
+	require.NoError(t, first.Publish(ctx, UserChannel(userID), TypeMention, map[string]string{"comment": "hi"}))
+	msg := receive(t, sub)
+	assert.Equal(t, UserChannel(userID), msg.Channel)
+	assert.Equal(t, TypeMention, msg.Type)
+}
@@ -0,0 +1,155 @@
+package report
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Разметка страницы PDF: A4 альбомной ориентации, размеры в пунктах
+const (
+	pdfPageWidth  = 842.0
+	pdfPageHeight = 595.0
+	pdfMargin     = 36.0
+	pdfFontSize   = 8.0
+	pdfTitleSize  = 13.0
+	pdfLineHeight = 13.0
+	// pdfCellPadding отступ текста от правой границы колонки
+	pdfCellPadding = 4.0
+)
+
+// pdfFont семейство встроенного шрифта DejaVu Sans (fonts/LICENSE)
+const pdfFont = "DejaVu"
+
+var (
+	//go:embed fonts/DejaVuSans.ttf
+	dejaVuSans []byte
+	//go:embed fonts/DejaVuSans-Bold.ttf
+	dejaVuSansBold []byte
+)
+
+// WritePDF пишет отчет таблицей на страницах A4 с повтором шапки на каждой странице
+// и номерами страниц. Шрифт DejaVu Sans встраивается в файл подмножеством использованных
+// символов и покрывает латиницу и кириллицу, включая кыргызские буквы.
+func WritePDF(w io.Writer, t *Table) error {
+	if err := t.validate(); err != nil {
+		return err
+	}
+
+	widths := pdfColumnWidths(t.Columns)
+	lines := t.Rows
+	if t.Totals != nil {
+		lines = append(lines[:len(lines):len(lines)], t.Totals)
+	}
+
+	top := pdfPageHeight - pdfMargin - pdfLineHeight*2
+	if t.Subtitle != "" {
+		top -= pdfLineHeight
+	}
+	perPage := int((top - pdfMargin - pdfLineHeight) / pdfLineHeight)
+	pages := (len(lines) + perPage - 1) / perPage
+	if pages == 0 {
+		pages = 1
+	}
+
+	pdf := gofpdf.New("L", "pt", "A4", "")
+	pdf.SetAutoPageBreak(false, 0)
+	pdf.SetTitle(t.Title, true)
+	pdf.AddUTF8FontFromBytes(pdfFont, "", dejaVuSans)
+	pdf.AddUTF8FontFromBytes(pdfFont, "B", dejaVuSansBold)
+
+	// y отсчитывается от верхнего края страницы и указывает базовую линию текста
+	for p := 0; p < pages; p++ {
+		pdf.AddPage()
+		y := pdfMargin + pdfTitleSize
+		pdf.SetFont(pdfFont, "B", pdfTitleSize)
+		pdf.Text(pdfMargin, y, t.Title)
+		if t.Subtitle != "" {
+			y += pdfLineHeight
+			pdf.SetFont(pdfFont, "", pdfFontSize)
+			pdf.Text(pdfMargin, y, t.Subtitle)
+		}
+		y += pdfLineHeight * 2
+
+		header := make([]string, len(t.Columns))
+		for i, col := range t.Columns {
+			header[i] = col.Title
+		}
+		pdf.SetFont(pdfFont, "B", pdfFontSize)
+		pdfRow(pdf, t.Columns, widths, y, header, false)
+		pdf.Line(pdfMargin, y+3, pdfPageWidth-pdfMargin, y+3)
+
+		end := (p + 1) * perPage
+		if end > len(lines) {
+			end = len(lines)
+		}
+		for i := p * perPage; i < end; i++ {
+			y += pdfLineHeight
+			pdf.SetFont(pdfFont, "", pdfFontSize)
+			if t.Totals != nil && i == len(lines)-1 {
+				pdf.SetFont(pdfFont, "B", pdfFontSize)
+				pdf.Line(pdfMargin, y-pdfLineHeight+3, pdfPageWidth-pdfMargin, y-pdfLineHeight+3)
+			}
+			pdfRow(pdf, t.Columns, widths, y, lines[i], true)
+		}
+
+		footer := fmt.Sprintf("Page %d of %d", p+1, pages)
+		pdf.SetFont(pdfFont, "", pdfFontSize)
+		pdf.Text(pdfPageWidth-pdfMargin-pdf.GetStringWidth(footer), pdfPageHeight-pdfMargin/2, footer)
+	}
+
+	return pdf.Output(w)
+}
+
+// pdfColumnWidths распределяет ширину страницы между колонками пропорционально Column.Width
+func pdfColumnWidths(columns []Column) []float64 {
+	var total float64
+	for _, col := range columns {
+		total += columnWeight(col)
+	}
+	available := pdfPageWidth - 2*pdfMargin
+	widths := make([]float64, len(columns))
+	for i, col := range columns {
+		widths[i] = available * columnWeight(col) / total
+	}
+	return widths
+}
+
+func columnWeight(col Column) float64 {
+	if col.Width > 0 {
+		return col.Width
+	}
+	return 1
+}
+
+// pdfRow пишет строку таблицы текущим шрифтом; числовые колонки (кроме шапки) выравниваются вправо
+func pdfRow(pdf *gofpdf.Fpdf, columns []Column, widths []float64, y float64, values []string, alignNumbers bool) {
+	x := pdfMargin
+	for i, v := range values {
+		text := pdfFit(pdf, v, widths[i]-pdfCellPadding)
+		tx := x
+		if alignNumbers && columns[i].Numeric {
+			tx = x + widths[i] - pdfCellPadding - pdf.GetStringWidth(text)
+		}
+		if text != "" {
+			pdf.Text(tx, y, text)
+		}
+		x += widths[i]
+	}
+}
+
+// pdfFit усекает текст до ширины колонки по метрикам текущего шрифта
+func pdfFit(pdf *gofpdf.Fpdf, s string, width float64) string {
+	if pdf.GetStringWidth(s) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for n := len(runes) - 1; n > 0; n-- {
+		if text := string(runes[:n]) + "~"; pdf.GetStringWidth(text) <= width {
+			return text
+		}
+	}
+	return ""
+}
@@ -0,0 +1,55 @@
+// Package report формирует табличные отчеты в XLSX (excelize) и PDF (gofpdf).
+package report
+
+import "fmt"
+
+// Content-Type файлов отчетов
+const (
+	ContentTypeXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	ContentTypePDF  = "application/pdf"
+)
+
+// Column колонка отчета
+type Column struct {
+	Title string
+	// Numeric значения колонки — числа: в XLSX пишутся числом, в PDF выравниваются вправо
+	Numeric bool
+	// Width относительная ширина колонки в PDF; 0 — то же, что 1
+	Width float64
+}
+
+// Table отчет: заголовок, колонки, строки и необязательная итоговая строка.
+// Значения строк уже отформатированы; числовые колонки содержат числа вида 1234.50.
+type Table struct {
+	Title    string
+	Subtitle string
+	Columns  []Column
+	Rows     [][]string
+	Totals   []string
+}
+
+// AddRow добавляет строку отчета
+func (t *Table) AddRow(values ...string) {
+	t.Rows = append(t.Rows, values)
+}
+
+// Amount форматирует денежную сумму для числовой колонки
+func Amount(v float64) string {
+	return fmt.Sprintf("%.2f", v)
+}
+
+// validate проверяет, что строки совпадают с колонками по длине
+func (t *Table) validate() error {
+	if len(t.Columns) == 0 {
+		return fmt.Errorf("report %q has no columns", t.Title)
+	}
+	for i, row := range t.Rows {
+		if len(row) != len(t.Columns) {
+			return fmt.Errorf("report %q: row %d has %d values, want %d", t.Title, i+1, len(row), len(t.Columns))
+		}
+	}
+	if t.Totals != nil && len(t.Totals) != len(t.Columns) {
+		return fmt.Errorf("report %q: totals have %d values, want %d", t.Title, len(t.Totals), len(t.Columns))
+	}
+	return nil
+}
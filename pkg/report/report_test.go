@@ -0,0 +1,106 @@
+package report
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+
+	"github.com/rusgainew/tunduck-app/pkg/testutil"
+)
+
+func testTable() *Table {
+	t := &Table{
+		Title:    "Sales register",
+		Subtitle: "Period: 2025-01-01 - 2025-01-31",
+		Columns: []Column{
+			{Title: "Date"},
+			{Title: "Contractor <TIN>", Width: 1.5},
+			{Title: "Total", Numeric: true},
+		},
+		Totals: []string{"Total", "", Amount(300)},
+	}
+	t.AddRow("2025-01-10", "01234567890123", Amount(100))
+	t.AddRow("2025-01-20", "Ещё & (другой) 中", Amount(200))
+	return t
+}
+
+func TestWriteXLSX(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteXLSX(&buf, testTable()))
+
+	f, err := excelize.OpenReader(&buf)
+	require.NoError(t, err)
+	defer f.Close()
+	assert.Equal(t, []string{"Sales register"}, f.GetSheetList())
+
+	cell := func(ref string) string {
+		v, err := f.GetCellValue("Sales register", ref)
+		require.NoError(t, err)
+		return v
+	}
+	style := func(ref string) *excelize.Style {
+		id, err := f.GetCellStyle("Sales register", ref)
+		require.NoError(t, err)
+		s, err := f.GetStyle(id)
+		require.NoError(t, err)
+		return s
+	}
+	assert.Equal(t, "Contractor <TIN>", cell("B4"))
+	assert.True(t, style("B4").Font.Bold, "the header is bold")
+	assert.Equal(t, "Ещё & (другой) 中", cell("B6"))
+
+	raw, err := f.GetCellValue("Sales register", "C5", excelize.Options{RawCellValue: true})
+	require.NoError(t, err)
+	assert.Equal(t, "100", raw, "amounts are numeric cells")
+	assert.Equal(t, "100.00", cell("C5"))
+	assert.Equal(t, xlsxAmountFormat, style("C5").NumFmt)
+	assert.True(t, style("C7").Font.Bold, "totals are bold")
+	assert.Equal(t, "300.00", cell("C7"))
+}
+
+func TestWritePDF(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WritePDF(&buf, testTable()))
+	pdf := buf.String()
+
+	assert.True(t, strings.HasPrefix(pdf, "%PDF-"))
+	assert.True(t, strings.HasSuffix(strings.TrimSpace(pdf), "%%EOF"))
+	assert.Contains(t, pdf, "/FontFile2", "the TrueType font is embedded")
+	assert.Contains(t, pdf, "/Encoding /Identity-H")
+
+	text := testutil.PDFContent(t, buf.Bytes())
+	assert.Contains(t, text, testutil.PDFText("Sales register"))
+	assert.Contains(t, text, testutil.PDFText("Ещё & (другой)"), "Cyrillic is printed as is")
+	assert.Contains(t, text, testutil.PDFText("Page 1 of 1"))
+}
+
+func TestWritePDF_Paginates(t *testing.T) {
+	table := &Table{Title: "Long", Columns: []Column{{Title: "N", Numeric: true}}}
+	for i := 0; i < 100; i++ {
+		table.AddRow(strconv.Itoa(i))
+	}
+	var buf bytes.Buffer
+	require.NoError(t, WritePDF(&buf, table))
+	assert.Contains(t, buf.String(), "/Count 3")
+	assert.Contains(t, testutil.PDFContent(t, buf.Bytes()), testutil.PDFText("Page 3 of 3"))
+}
+
+func TestTable_Validate(t *testing.T) {
+	table := testTable()
+	table.AddRow("only one")
+	assert.Error(t, WriteXLSX(io.Discard, table))
+	assert.Error(t, WritePDF(io.Discard, table))
+	assert.Error(t, WritePDF(io.Discard, &Table{Title: "Empty"}))
+}
+
+func TestXLSXSheetName(t *testing.T) {
+	assert.Equal(t, "Sales  2025 01", xlsxSheetName("Sales: 2025/01"))
+	assert.Equal(t, "Report", xlsxSheetName("[]"))
+	assert.Len(t, []rune(xlsxSheetName(strings.Repeat("Реестр", 10))), 31)
+}
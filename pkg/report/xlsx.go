@@ -0,0 +1,150 @@
+package report
+
+import (
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// xlsxAmountFormat встроенный формат Excel "#,##0.00"
+const xlsxAmountFormat = 4
+
+// xlsxStyles стили ячеек листа
+type xlsxStyles struct {
+	bold, amount, total int
+}
+
+// WriteXLSX пишет отчет книгой Excel с одним листом: заголовок, шапка, строки и итоги.
+// Лист пишется через StreamWriter excelize, поэтому строки не собираются в памяти.
+func WriteXLSX(w io.Writer, t *Table) error {
+	if err := t.validate(); err != nil {
+		return err
+	}
+
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheet := xlsxSheetName(t.Title)
+	if err := f.SetSheetName(f.GetSheetName(0), sheet); err != nil {
+		return err
+	}
+	styles, err := newXLSXStyles(f)
+	if err != nil {
+		return err
+	}
+
+	sw, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		return err
+	}
+	// excelize добавляет ширину колонки в начало списка, поэтому колонки перебираются
+	// с конца: Excel ожидает описания колонок по возрастанию номера
+	for i := len(t.Columns) - 1; i >= 0; i-- {
+		width := 14.0
+		if t.Columns[i].Width > 0 {
+			width *= t.Columns[i].Width
+		}
+		if err := sw.SetColWidth(i+1, i+1, width); err != nil {
+			return err
+		}
+	}
+
+	row := 0
+	writeRow := func(values []string, bold bool, numeric func(int) bool) error {
+		row++
+		cells := make([]interface{}, len(values))
+		for i, v := range values {
+			if v == "" {
+				continue
+			}
+			if numeric(i) {
+				if n, err := strconv.ParseFloat(v, 64); err == nil {
+					style := styles.amount
+					if bold {
+						style = styles.total
+					}
+					cells[i] = excelize.Cell{StyleID: style, Value: n}
+					continue
+				}
+			}
+			style := 0
+			if bold {
+				style = styles.bold
+			}
+			cells[i] = excelize.Cell{StyleID: style, Value: v}
+		}
+		cell, err := excelize.CoordinatesToCellName(1, row)
+		if err != nil {
+			return err
+		}
+		return sw.SetRow(cell, cells)
+	}
+	text := func(int) bool { return false }
+	byColumn := func(i int) bool { return t.Columns[i].Numeric }
+
+	if err := writeRow([]string{t.Title}, true, text); err != nil {
+		return err
+	}
+	if t.Subtitle != "" {
+		if err := writeRow([]string{t.Subtitle}, false, text); err != nil {
+			return err
+		}
+	}
+	row++ // пустая строка перед таблицей
+
+	header := make([]string, len(t.Columns))
+	for i, col := range t.Columns {
+		header[i] = col.Title
+	}
+	if err := writeRow(header, true, text); err != nil {
+		return err
+	}
+	for _, values := range t.Rows {
+		if err := writeRow(values, false, byColumn); err != nil {
+			return err
+		}
+	}
+	if t.Totals != nil {
+		if err := writeRow(t.Totals, true, byColumn); err != nil {
+			return err
+		}
+	}
+
+	if err := sw.Flush(); err != nil {
+		return err
+	}
+	return f.Write(w)
+}
+
+// newXLSXStyles регистрирует жирный текст, сумму с двумя знаками и жирную сумму
+func newXLSXStyles(f *excelize.File) (xlsxStyles, error) {
+	var styles xlsxStyles
+	var err error
+	if styles.bold, err = f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}}); err != nil {
+		return styles, err
+	}
+	if styles.amount, err = f.NewStyle(&excelize.Style{NumFmt: xlsxAmountFormat}); err != nil {
+		return styles, err
+	}
+	styles.total, err = f.NewStyle(&excelize.Style{NumFmt: xlsxAmountFormat, Font: &excelize.Font{Bold: true}})
+	return styles, err
+}
+
+// xlsxSheetName имя листа; оно ограничено 31 символом и не содержит []:*?/\
+func xlsxSheetName(title string) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return ' '
+		}
+		return r
+	}, title)
+	if runes := []rune(name); len(runes) > 31 {
+		name = string(runes[:31])
+	}
+	if strings.TrimSpace(name) == "" {
+		name = "Report"
+	}
+	return name
+}
@@ -0,0 +1,149 @@
+package response
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+)
+
+// FieldSet поля ответа ресурса, которые клиент может запросить в параметре fields
+// (sparse fieldset): ответ сокращается на сервере, и мобильным клиентам не нужно
+// получать всю модель ради нескольких колонок списка.
+type FieldSet struct {
+	keys    map[string]bool
+	aliases map[string]string
+	always  []string
+}
+
+// NewFieldSet собирает поля из json-тегов model (встроенные структуры раскрываются).
+// aliases задают дополнительные имена полей, always — поля, которые возвращаются всегда.
+func NewFieldSet(model interface{}, aliases map[string]string, always ...string) FieldSet {
+	set := FieldSet{keys: map[string]bool{}, aliases: aliases, always: always}
+	collectJSONKeys(reflect.TypeOf(model), set.keys)
+	return set
+}
+
+func collectJSONKeys(t reflect.Type, keys map[string]bool) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() && !field.Anonymous {
+			continue
+		}
+		if name == "" && field.Anonymous {
+			collectJSONKeys(field.Type, keys)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		keys[name] = true
+	}
+}
+
+// Resolve переводит запрошенные поля в ключи ответа: принимаются ключи JSON, их snake_case
+// и псевдонимы. Пустой список означает полный ответ; неизвестное поле — ошибка проверки.
+func (s FieldSet) Resolve(requested []string) ([]string, *apperror.AppError) {
+	if len(requested) == 0 {
+		return nil, nil
+	}
+	keys := append([]string(nil), s.always...)
+	for _, name := range requested {
+		key, ok := s.key(name)
+		if !ok {
+			return nil, apperror.ValidationError("unknown field {field}").WithParams(map[string]interface{}{"field": name})
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func (s FieldSet) key(name string) (string, bool) {
+	if key, ok := s.aliases[name]; ok {
+		return key, true
+	}
+	if s.keys[name] {
+		return name, true
+	}
+	if key := camelCase(name); s.keys[key] {
+		return key, true
+	}
+	return "", false
+}
+
+// camelCase переводит имя из snake_case: delivery_date -> deliveryDate
+func camelCase(name string) string {
+	parts := strings.Split(name, "_")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+	return strings.Join(parts, "")
+}
+
+// Sparse оставляет в data (объекте или списке объектов) только ключи keys; пустой keys не меняет data
+func Sparse(data interface{}, keys []string) (interface{}, error) {
+	if len(keys) == 0 {
+		return data, nil
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(strings.TrimSpace(string(raw)), "[") {
+		var items []map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, err
+		}
+		for i := range items {
+			items[i] = pick(items[i], keys)
+		}
+		return items, nil
+	}
+
+	var item map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &item); err != nil {
+		return nil, err
+	}
+	return pick(item, keys), nil
+}
+
+func pick(item map[string]json.RawMessage, keys []string) map[string]json.RawMessage {
+	if item == nil {
+		return nil
+	}
+	picked := make(map[string]json.RawMessage, len(keys))
+	for _, key := range keys {
+		if value, ok := item[key]; ok {
+			picked[key] = value
+		}
+	}
+	return picked
+}
+
+// SparseList отправляет список, сокращенный до keys (см. FieldSet.Resolve)
+func SparseList(c *fiber.Ctx, keys []string, data interface{}, meta interface{}) error {
+	sparse, err := Sparse(data, keys)
+	if err != nil {
+		return Error(c, apperror.From(err, apperror.ErrInternal, "failed to encode response"))
+	}
+	return List(c, sparse, meta)
+}
+
+// SparseOK отправляет 200 OK с объектом, сокращенным до keys
+func SparseOK(c *fiber.Ctx, keys []string, data interface{}) error {
+	sparse, err := Sparse(data, keys)
+	if err != nil {
+		return Error(c, apperror.From(err, apperror.ErrInternal, "failed to encode response"))
+	}
+	return OK(c, sparse)
+}
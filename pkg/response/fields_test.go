@@ -0,0 +1,61 @@
+package response
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testBase struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+type testResource struct {
+	testBase
+	DeliveryDate string            `json:"deliveryDate"`
+	Total        float64           `json:"totalCurrencyValue"`
+	Secret       string            `json:"-"`
+	Links        map[string]string `json:"_links"`
+}
+
+func TestFieldSet_Resolve(t *testing.T) {
+	set := NewFieldSet(testResource{}, map[string]string{"amount": "totalCurrencyValue"}, "id")
+
+	keys, appErr := set.Resolve([]string{"name", "delivery_date", "amount", "_links"})
+	require.Nil(t, appErr)
+	assert.Equal(t, []string{"id", "name", "deliveryDate", "totalCurrencyValue", "_links"}, keys)
+
+	keys, appErr = set.Resolve(nil)
+	assert.Nil(t, appErr)
+	assert.Nil(t, keys, "no fields means the full response")
+
+	for _, name := range []string{"Secret", "secret", "testBase"} {
+		_, appErr = set.Resolve([]string{name})
+		assert.NotNil(t, appErr, name)
+	}
+}
+
+func TestSparse(t *testing.T) {
+	items := []testResource{
+		{testBase: testBase{ID: "1", Name: "a"}, Total: 10},
+		{testBase: testBase{ID: "2"}, Total: 20},
+	}
+
+	sparse, err := Sparse(items, []string{"id", "name", "totalCurrencyValue"})
+	require.NoError(t, err)
+	raw, err := json.Marshal(sparse)
+	require.NoError(t, err)
+	assert.JSONEq(t, `[{"id":"1","name":"a","totalCurrencyValue":10},{"id":"2","totalCurrencyValue":20}]`, string(raw))
+
+	sparse, err = Sparse(&items[0], []string{"id"})
+	require.NoError(t, err)
+	raw, _ = json.Marshal(sparse)
+	assert.JSONEq(t, `{"id":"1"}`, string(raw))
+
+	unchanged, err := Sparse(items, nil)
+	require.NoError(t, err)
+	assert.Equal(t, items, unchanged)
+}
@@ -3,37 +3,58 @@ package response
 import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/i18n"
 )
 
-// SuccessResponse представляет успешный ответ API
-type SuccessResponse struct {
-	Code      int         `json:"code"`
-	Status    string      `json:"status"`
-	Message   string      `json:"message"`
-	Data      interface{} `json:"data,omitempty"`
-	RequestID string      `json:"request_id,omitempty"`
+// Envelope единый формат тела ответа API.
+// Успешный ответ содержит data (и meta для списков), ошибочный - error.
+type Envelope struct {
+	Success   bool                    `json:"success"`
+	Data      interface{}             `json:"data,omitempty"`
+	Meta      interface{}             `json:"meta,omitempty"`
+	Message   string                  `json:"message,omitempty"`
+	Error     *apperror.ErrorResponse `json:"error,omitempty"`
+	RequestID string                  `json:"request_id,omitempty"`
 }
 
-// ErrorResponse представляет ошибочный ответ API
-type ErrorResponse struct {
-	Code      string `json:"code"`
-	Status    int    `json:"status"`
-	Message   string `json:"message"`
-	Details   string `json:"details,omitempty"`
-	RequestID string `json:"request_id,omitempty"`
+// RequestID возвращает ID запроса, установленный RequestIDMiddleware
+func RequestID(c *fiber.Ctx) string {
+	if id, ok := c.Locals("request_id").(string); ok && id != "" {
+		return id
+	}
+	return c.Get("X-Request-ID")
 }
 
 // Success отправляет успешный ответ
 func Success(c *fiber.Ctx, statusCode int, message string, data interface{}) error {
-	return c.Status(statusCode).JSON(SuccessResponse{
-		Code:      statusCode,
-		Status:    "success",
+	return c.Status(statusCode).JSON(Envelope{
+		Success:   true,
+		Data:      data,
 		Message:   message,
+		RequestID: RequestID(c),
+	})
+}
+
+// SuccessWithMeta отправляет успешный ответ с метаданными (пагинация и т.п.)
+func SuccessWithMeta(c *fiber.Ctx, statusCode int, data interface{}, meta interface{}) error {
+	return c.Status(statusCode).JSON(Envelope{
+		Success:   true,
 		Data:      data,
-		RequestID: c.Get("X-Request-ID"),
+		Meta:      meta,
+		RequestID: RequestID(c),
 	})
 }
 
+// OK отправляет 200 OK ответ с данными
+func OK(c *fiber.Ctx, data interface{}) error {
+	return Success(c, fiber.StatusOK, "", data)
+}
+
+// List отправляет 200 OK ответ со списком и метаданными
+func List(c *fiber.Ctx, data interface{}, meta interface{}) error {
+	return SuccessWithMeta(c, fiber.StatusOK, data, meta)
+}
+
 // SuccessCreated отправляет 201 Created ответ
 func SuccessCreated(c *fiber.Ctx, message string, data interface{}) error {
 	return Success(c, fiber.StatusCreated, message, data)
@@ -49,17 +70,20 @@ func SuccessNoContent(c *fiber.Ctx) error {
 	return c.SendStatus(fiber.StatusNoContent)
 }
 
-// Error отправляет ошибочный ответ
+// Error отправляет ошибочный ответ.
+// Сообщение переводится на язык запроса (Accept-Language).
 func Error(c *fiber.Ctx, appErr *apperror.AppError) error {
-	response := ErrorResponse{
-		Code:      string(appErr.Code),
-		Status:    appErr.HTTPStatus,
-		Message:   appErr.Message,
-		Details:   appErr.Details,
-		RequestID: c.Get("X-Request-ID"),
-	}
+	return c.Status(appErr.HTTPStatus).JSON(Envelope{
+		Success:   false,
+		Error:     appErr.ToLocalizedResponse(i18n.FromCtx(c)),
+		RequestID: RequestID(c),
+	})
+}
 
-	return c.Status(appErr.HTTPStatus).JSON(response)
+// FromError отправляет ошибочный ответ для произвольной ошибки.
+// Ошибки, не являющиеся AppError, оборачиваются с указанным кодом и сообщением.
+func FromError(c *fiber.Ctx, err error, code apperror.ErrorCode, message string) error {
+	return Error(c, apperror.From(err, code, message))
 }
 
 // BadRequest отправляет 400 Bad Request
@@ -0,0 +1,224 @@
+// Package retry повторяет операции с экспоненциальной задержкой и случайным разбросом.
+//
+// Повторы ограничиваются числом попыток (MaxAttempts), общим временем (MaxElapsed) и, при
+// необходимости, бюджетом повторов (Budget), общим для всех вызовов одного клиента: при
+// массовом сбое внешнего сервиса бюджет не дает повторам умножить нагрузку на него.
+//
+//	policy := retry.DefaultPolicy
+//	policy.Name, policy.Logger = "nbkr rates", log
+//	err := retry.Do(ctx, policy, func(ctx context.Context) error { ... })
+package retry
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+)
+
+// Policy политика повторов
+type Policy struct {
+	// Name имя операции в журнале
+	Name string
+	// MaxAttempts наибольшее число попыток, включая первую; 0 — без ограничения (нужен MaxElapsed или ctx)
+	MaxAttempts int
+	// InitialInterval задержка перед первым повтором
+	InitialInterval time.Duration
+	// MaxInterval предельная задержка между попытками
+	MaxInterval time.Duration
+	// Multiplier рост задержки с каждой попыткой; <= 1 — 2
+	Multiplier float64
+	// Jitter доля случайного разброса задержки от 0 до 1: задержка d становится d ± d*Jitter
+	Jitter float64
+	// MaxElapsed общее время на все попытки; повтор, который не успеет начаться в срок, не выполняется
+	MaxElapsed time.Duration
+	// Retryable решает, повторять ли ошибку; nil — повторяются все, кроме Permanent
+	Retryable func(error) bool
+	// Budget ограничивает долю повторов среди вызовов; nil — без ограничения
+	Budget *Budget
+	// Logger журнал попыток; nil — без журнала
+	Logger *logger.Logger
+}
+
+// DefaultPolicy 3 попытки с задержкой от 200 мс до 5 секунд, разбросом 20% и не дольше 30 секунд
+var DefaultPolicy = Policy{
+	MaxAttempts:     3,
+	InitialInterval: 200 * time.Millisecond,
+	MaxInterval:     5 * time.Second,
+	Multiplier:      2,
+	Jitter:          0.2,
+	MaxElapsed:      30 * time.Second,
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent помечает ошибку как неустранимую: Do возвращает ее без повторов
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent сообщает, помечена ли ошибка как неустранимая
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// Delay задержка перед попыткой attempt+1 без разброса; attempt начинается с 1
+func (p Policy) Delay(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	multiplier := p.Multiplier
+	if multiplier <= 1 {
+		multiplier = 2
+	}
+	d := float64(p.InitialInterval) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxInterval > 0 && d > float64(p.MaxInterval) {
+		return p.MaxInterval
+	}
+	return time.Duration(d)
+}
+
+// jittered добавляет к задержке случайный разброс
+func (p Policy) jittered(d time.Duration) time.Duration {
+	if p.Jitter <= 0 || d <= 0 {
+		return d
+	}
+	jitter := math.Min(p.Jitter, 1)
+	delta := (rand.Float64()*2 - 1) * jitter * float64(d)
+	return time.Duration(float64(d) + delta)
+}
+
+// sleep ждет d или отмены ctx
+var sleep = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// now текущее время; заменяется в тестах
+var now = time.Now
+
+// Do выполняет fn, повторяя ее по политике. Возвращает nil или последнюю ошибку fn
+// (Permanent снимается); при отмене ctx во время ожидания — последнюю ошибку fn.
+func Do(ctx context.Context, p Policy, fn func(ctx context.Context) error) error {
+	start := now()
+	p.Budget.deposit()
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			if attempt > 1 {
+				p.log(ctx, attempt, 0, nil, "Operation succeeded after retry")
+			}
+			return nil
+		}
+
+		var perm *permanentError
+		if errors.As(err, &perm) {
+			return perm.err
+		}
+		if p.Retryable != nil && !p.Retryable(err) {
+			return err
+		}
+		if p.MaxAttempts > 0 && attempt >= p.MaxAttempts {
+			p.log(ctx, attempt, 0, err, "Giving up after the last attempt")
+			return err
+		}
+
+		delay := p.jittered(p.Delay(attempt))
+		if p.MaxElapsed > 0 && now().Add(delay).Sub(start) > p.MaxElapsed {
+			p.log(ctx, attempt, 0, err, "Giving up, retry time budget exhausted")
+			return err
+		}
+		if !p.Budget.withdraw() {
+			p.log(ctx, attempt, 0, err, "Giving up, retry budget exhausted")
+			return err
+		}
+
+		p.log(ctx, attempt, delay, err, "Attempt failed, retrying")
+		if sleep(ctx, delay) != nil {
+			return err
+		}
+	}
+}
+
+func (p Policy) log(ctx context.Context, attempt int, delay time.Duration, err error, message string) {
+	if p.Logger == nil {
+		return
+	}
+	fields := logrus.Fields{"operation": p.Name, "attempt": attempt}
+	if delay > 0 {
+		fields["retry_in"] = delay.String()
+	}
+	if err != nil {
+		fields["error"] = err.Error()
+	}
+	p.Logger.Warn(ctx, message, fields)
+}
+
+// Budget бюджет повторов: каждый вызов Do пополняет его на Ratio, каждый повтор тратит единицу.
+// Так повторы составляют не больше Ratio от вызовов, а запас Max покрывает короткие всплески.
+type Budget struct {
+	ratio float64
+	max   float64
+
+	mu     sync.Mutex
+	tokens float64
+}
+
+// NewBudget создает полный бюджет; ratio — доля повторов (например 0.1), max — запас повторов
+func NewBudget(ratio float64, max int) *Budget {
+	return &Budget{ratio: ratio, max: float64(max), tokens: float64(max)}
+}
+
+// Remaining число повторов, доступных сейчас; для nil — без ограничения
+func (b *Budget) Remaining() int {
+	if b == nil {
+		return math.MaxInt
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return int(b.tokens)
+}
+
+func (b *Budget) deposit() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	b.tokens = math.Min(b.max, b.tokens+b.ratio)
+	b.mu.Unlock()
+}
+
+func (b *Budget) withdraw() bool {
+	if b == nil {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,141 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock подменяет sleep и now: ожидание сразу сдвигает часы
+type fakeClock struct {
+	at     time.Time
+	sleeps []time.Duration
+}
+
+func useFakeClock(t *testing.T) *fakeClock {
+	clock := &fakeClock{at: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)}
+	prevSleep, prevNow := sleep, now
+	sleep = func(ctx context.Context, d time.Duration) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		clock.sleeps = append(clock.sleeps, d)
+		clock.at = clock.at.Add(d)
+		return nil
+	}
+	now = func() time.Time { return clock.at }
+	t.Cleanup(func() { sleep, now = prevSleep, prevNow })
+	return clock
+}
+
+// failing возвращает err первые n вызовов, затем nil
+func failing(n int, err error) (func(context.Context) error, *int) {
+	calls := 0
+	return func(context.Context) error {
+		calls++
+		if calls <= n {
+			return err
+		}
+		return nil
+	}, &calls
+}
+
+var errFlaky = errors.New("flaky")
+
+func TestPolicy_Delay(t *testing.T) {
+	p := Policy{InitialInterval: 100 * time.Millisecond, MaxInterval: time.Second, Multiplier: 3}
+	assert.Equal(t, 100*time.Millisecond, p.Delay(1))
+	assert.Equal(t, 300*time.Millisecond, p.Delay(2))
+	assert.Equal(t, 900*time.Millisecond, p.Delay(3))
+	assert.Equal(t, time.Second, p.Delay(4), "capped by MaxInterval")
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		d := p.jittered(time.Second)
+		assert.True(t, d >= 500*time.Millisecond && d <= 1500*time.Millisecond, d)
+	}
+}
+
+func TestDo_RetriesUntilSuccess(t *testing.T) {
+	clock := useFakeClock(t)
+	fn, calls := failing(2, errFlaky)
+
+	err := Do(context.Background(), Policy{MaxAttempts: 5, InitialInterval: time.Second}, fn)
+	require.NoError(t, err)
+	assert.Equal(t, 3, *calls)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, clock.sleeps)
+}
+
+func TestDo_StopsAfterMaxAttempts(t *testing.T) {
+	useFakeClock(t)
+	fn, calls := failing(10, errFlaky)
+
+	err := Do(context.Background(), Policy{MaxAttempts: 3, InitialInterval: time.Second}, fn)
+	assert.ErrorIs(t, err, errFlaky)
+	assert.Equal(t, 3, *calls)
+}
+
+func TestDo_PermanentAndRetryable(t *testing.T) {
+	useFakeClock(t)
+	fn, calls := failing(10, Permanent(errFlaky))
+	err := Do(context.Background(), Policy{MaxAttempts: 3}, fn)
+	assert.Equal(t, errFlaky, err, "Permanent is unwrapped")
+	assert.False(t, IsPermanent(err))
+	assert.Equal(t, 1, *calls)
+
+	fn, calls = failing(10, errFlaky)
+	err = Do(context.Background(), Policy{MaxAttempts: 3, Retryable: func(error) bool { return false }}, fn)
+	assert.ErrorIs(t, err, errFlaky)
+	assert.Equal(t, 1, *calls)
+}
+
+func TestDo_MaxElapsed(t *testing.T) {
+	clock := useFakeClock(t)
+	fn, calls := failing(10, errFlaky)
+
+	// Задержки 1s, 2s, 4s: третья не укладывается в 5 секунд
+	err := Do(context.Background(), Policy{InitialInterval: time.Second, MaxElapsed: 5 * time.Second}, fn)
+	assert.ErrorIs(t, err, errFlaky)
+	assert.Equal(t, 3, *calls)
+	assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, clock.sleeps)
+}
+
+func TestDo_ContextCancelled(t *testing.T) {
+	useFakeClock(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	err := Do(ctx, Policy{MaxAttempts: 5}, func(context.Context) error {
+		calls++
+		cancel()
+		return errFlaky
+	})
+	assert.ErrorIs(t, err, errFlaky)
+	assert.Equal(t, 1, calls)
+}
+
+func TestBudget_LimitsRetries(t *testing.T) {
+	useFakeClock(t)
+	budget := NewBudget(0.5, 2)
+	p := Policy{MaxAttempts: 3, InitialInterval: time.Millisecond, Budget: budget}
+
+	// Запас в 2 повтора тратится первым вызовом
+	fn, calls := failing(10, errFlaky)
+	assert.ErrorIs(t, Do(context.Background(), p, fn), errFlaky)
+	assert.Equal(t, 3, *calls)
+	assert.Equal(t, 0, budget.Remaining())
+
+	// Каждый вызов пополняет бюджет на 0.5: повтор доступен раз в два вызова
+	fn, calls = failing(10, errFlaky)
+	assert.ErrorIs(t, Do(context.Background(), p, fn), errFlaky)
+	assert.Equal(t, 1, *calls)
+	fn, calls = failing(10, errFlaky)
+	assert.ErrorIs(t, Do(context.Background(), p, fn), errFlaky)
+	assert.Equal(t, 2, *calls)
+
+	var unlimited *Budget
+	assert.True(t, unlimited.withdraw())
+}
@@ -0,0 +1,56 @@
+package scheduler
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/robfig/cron/v3"
+)
+
+// Schedule вычисляет время следующего запуска задачи
+type Schedule interface {
+	// Next возвращает первый момент запуска строго после t
+	Next(t time.Time) time.Time
+}
+
+// Every запуск с фиксированным интервалом, выровненный по началу эпохи
+type Every time.Duration
+
+// Next возвращает следующий момент, кратный интервалу
+func (e Every) Next(t time.Time) time.Time {
+	d := time.Duration(e)
+	return t.Truncate(d).Add(d)
+}
+
+// cronParser стандартный cron из пяти полей (минута, час, день месяца, месяц, день недели) и дескрипторы
+var cronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+// Parse разбирает выражение cron ("*/15 * * * *", "0 3 * * 1-5"), дескриптор (@daily, @hourly, ...)
+// или интервал "@every 10m". Время cron-выражений считается в loc (nil — time.Local).
+//
+// Интервал "@every" выравнивается по началу эпохи, а не по моменту запуска, как в robfig/cron:
+// так все экземпляры вычисляют один и тот же момент запуска и блокировка в Redis срабатывает.
+func Parse(spec string, loc *time.Location) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if loc == nil {
+		loc = time.Local
+	}
+
+	if rest, ok := strings.CutPrefix(spec, "@every "); ok {
+		d, err := time.ParseDuration(strings.TrimSpace(rest))
+		if err != nil || d < time.Second {
+			return nil, fmt.Errorf("invalid interval in %q", spec)
+		}
+		return Every(d), nil
+	}
+
+	schedule, err := cronParser.Parse(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cron expression %q: %w", spec, err)
+	}
+	if s, ok := schedule.(*cron.SpecSchedule); ok {
+		s.Location = loc
+	}
+	return schedule, nil
+}
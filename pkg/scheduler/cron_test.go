@@ -0,0 +1,71 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Next(t *testing.T) {
+	// Среда, 15 января 2025, 10:07
+	from := time.Date(2025, time.January, 15, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2025, 1, 15, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2025, 1, 15, 10, 15, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2025, 1, 16, 3, 0, 0, 0, time.UTC)},
+		{"30 9 * * 1-5", time.Date(2025, 1, 16, 9, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 12 * * SUN", time.Date(2025, 1, 19, 12, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"0 0 1,20 * 6", time.Date(2025, 1, 18, 0, 0, 0, 0, time.UTC)},
+		{"10-20/5 10 * * *", time.Date(2025, 1, 15, 10, 10, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{"@every 1h", time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			schedule, err := Parse(tt.spec, time.UTC)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, schedule.Next(from))
+		})
+	}
+}
+
+func TestParse_Location(t *testing.T) {
+	bishkek := time.FixedZone("Asia/Bishkek", 6*3600)
+	schedule, err := Parse("0 3 * * *", bishkek)
+	require.NoError(t, err)
+
+	next := schedule.Next(time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2025, 1, 15, 21, 0, 0, 0, time.UTC), next.UTC())
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 7",
+		"*/0 * * * *",
+		"20-10 * * * *",
+		"a * * * *",
+		"@every",
+		"@every 10ms",
+		"@sometimes",
+	} {
+		_, err := Parse(spec, time.UTC)
+		assert.Error(t, err, spec)
+	}
+}
@@ -0,0 +1,340 @@
+// Package scheduler запускает периодические задачи по расписанию cron. В развертывании с несколькими
+// репликами каждый запуск выполняет только один инстанс: перед запуском он захватывает блокировку задачи
+// в Redis и отмечает обработанный момент расписания.
+//
+// Ключи Redis (prefix по умолчанию "scheduler"):
+//
+//	<prefix>:lock:<task>  блокировка выполняющейся задачи (токен инстанса, TTL = Timeout задачи)
+//	<prefix>:last:<task>  последний запущенный момент расписания (unix ms)
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/metrics"
+)
+
+// Параметры планировщика по умолчанию
+const (
+	DefaultPrefix        = "scheduler"
+	DefaultTimeout       = 10 * time.Minute
+	DefaultCheckInterval = time.Second
+)
+
+// Статусы запуска в метриках
+const (
+	statusSuccess = "success"
+	statusError   = "error"
+	statusSkipped = "skipped"
+)
+
+// ErrDuplicateTask возвращается при повторной регистрации задачи с тем же именем
+var ErrDuplicateTask = errors.New("scheduled task already registered")
+
+// Task периодическая задача
+type Task struct {
+	Name string
+	// Spec расписание в формате Parse: "0 3 * * *", "@hourly", "@every 15m"
+	Spec string
+	// Timeout ограничивает выполнение и время жизни блокировки (по умолчанию DefaultTimeout)
+	Timeout time.Duration
+	Run     func(ctx context.Context) error
+}
+
+// Config параметры планировщика
+type Config struct {
+	Prefix string
+	// Location часовой пояс cron-выражений (nil — time.Local)
+	Location      *time.Location
+	CheckInterval time.Duration
+	Registerer    prometheus.Registerer
+}
+
+// TaskInfo состояние задачи на этом инстансе
+type TaskInfo struct {
+	Name      string    `json:"name"`
+	Spec      string    `json:"spec"`
+	NextRun   time.Time `json:"next_run"`
+	LastRun   time.Time `json:"last_run,omitempty"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+type entry struct {
+	task      Task
+	schedule  Schedule
+	next      time.Time
+	running   bool
+	lastRun   time.Time
+	lastError string
+}
+
+// Scheduler запускает зарегистрированные задачи по расписанию
+type Scheduler struct {
+	client *redis.Client
+	cfg    Config
+	logger *logger.Logger
+	token  string
+
+	runs     *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+
+	mu      sync.Mutex
+	entries map[string]*entry
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New создает планировщик; задачи добавляются через Add до или после Start
+func New(client *redis.Client, cfg Config, log *logrus.Logger) *Scheduler {
+	if cfg.Prefix == "" {
+		cfg.Prefix = DefaultPrefix
+	}
+	if cfg.Location == nil {
+		cfg.Location = time.Local
+	}
+	if cfg.CheckInterval <= 0 {
+		cfg.CheckInterval = DefaultCheckInterval
+	}
+	if cfg.Registerer == nil {
+		cfg.Registerer = prometheus.DefaultRegisterer
+	}
+
+	return &Scheduler{
+		client: client,
+		cfg:    cfg,
+		logger: logger.New(log),
+		token:  uuid.NewString(),
+		runs: metrics.Register(cfg.Registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "scheduler_task_runs_total",
+			Help: "Total number of scheduled task runs by outcome (success, error, skipped)",
+		}, []string{"task", "status"})),
+		duration: metrics.Register(cfg.Registerer, prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "scheduler_task_duration_seconds",
+			Help:    "Scheduled task duration in seconds",
+			Buckets: []float64{0.1, 0.5, 1, 5, 15, 60, 300, 900},
+		}, []string{"task"})),
+		entries: make(map[string]*entry),
+	}
+}
+
+// Add регистрирует задачу; ошибка возвращается при неверном расписании или повторном имени
+func (s *Scheduler) Add(task Task) error {
+	if task.Name == "" || task.Run == nil {
+		return errors.New("scheduled task requires a name and a run function")
+	}
+	schedule, err := Parse(task.Spec, s.cfg.Location)
+	if err != nil {
+		return fmt.Errorf("task %s: %w", task.Name, err)
+	}
+	if task.Timeout <= 0 {
+		task.Timeout = DefaultTimeout
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.entries[task.Name]; ok {
+		return fmt.Errorf("%w: %s", ErrDuplicateTask, task.Name)
+	}
+	s.entries[task.Name] = &entry{
+		task:     task,
+		schedule: schedule,
+		next:     schedule.Next(time.Now()),
+	}
+	return nil
+}
+
+// Start запускает цикл проверки расписания
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.cfg.CheckInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				s.tick(ctx, now)
+			}
+		}
+	}()
+
+	s.logger.Info(ctx, "Scheduler started", logrus.Fields{"tasks": len(s.Tasks())})
+}
+
+// Stop останавливает планировщик и ждет завершения выполняющихся задач
+func (s *Scheduler) Stop() {
+	if s.cancel == nil {
+		return
+	}
+	s.cancel()
+	s.wg.Wait()
+	s.logger.Info(context.Background(), "Scheduler stopped")
+}
+
+// Tasks возвращает состояние задач, отсортированное по имени
+func (s *Scheduler) Tasks() []TaskInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	infos := make([]TaskInfo, 0, len(s.entries))
+	for _, e := range s.entries {
+		infos = append(infos, TaskInfo{
+			Name:      e.task.Name,
+			Spec:      e.task.Spec,
+			NextRun:   e.next,
+			LastRun:   e.lastRun,
+			LastError: e.lastError,
+		})
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name < infos[j].Name })
+	return infos
+}
+
+// tick запускает задачи, момент расписания которых наступил
+func (s *Scheduler) tick(ctx context.Context, now time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, e := range s.entries {
+		if e.next.IsZero() || now.Before(e.next) {
+			continue
+		}
+		occurrence := e.next
+		e.next = e.schedule.Next(now)
+
+		// Предыдущий запуск еще выполняется на этом инстансе
+		if e.running {
+			s.runs.WithLabelValues(e.task.Name, statusSkipped).Inc()
+			continue
+		}
+		e.running = true
+
+		s.wg.Add(1)
+		go s.run(ctx, e, occurrence)
+	}
+}
+
+func (s *Scheduler) run(ctx context.Context, e *entry, occurrence time.Time) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		e.running = false
+		s.mu.Unlock()
+	}()
+
+	name := e.task.Name
+	fields := logrus.Fields{"task": name, "occurrence": occurrence.Format(time.RFC3339)}
+
+	acquired, err := s.acquire(ctx, name, occurrence, e.task.Timeout)
+	if err != nil {
+		s.logger.Error(ctx, "Failed to acquire scheduled task lock", err, fields)
+		s.runs.WithLabelValues(name, statusSkipped).Inc()
+		return
+	}
+	if !acquired {
+		// Этот момент расписания уже обработан или задача выполняется другим инстансом
+		s.logger.Debug(ctx, "Scheduled task skipped, claimed by another instance", fields)
+		s.runs.WithLabelValues(name, statusSkipped).Inc()
+		return
+	}
+	defer s.release(name)
+
+	runCtx, cancel := context.WithTimeout(ctx, e.task.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err = s.execute(runCtx, e.task)
+	s.duration.WithLabelValues(name).Observe(time.Since(start).Seconds())
+
+	s.mu.Lock()
+	e.lastRun = start
+	e.lastError = ""
+	if err != nil {
+		e.lastError = err.Error()
+	}
+	s.mu.Unlock()
+
+	fields["duration"] = time.Since(start).String()
+	if err != nil {
+		s.logger.Error(ctx, "Scheduled task failed", err, fields)
+		s.runs.WithLabelValues(name, statusError).Inc()
+		return
+	}
+	s.logger.Info(ctx, "Scheduled task completed", fields)
+	s.runs.WithLabelValues(name, statusSuccess).Inc()
+}
+
+// execute выполняет задачу, превращая панику в ошибку
+func (s *Scheduler) execute(ctx context.Context, task Task) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("scheduled task panic: %v", r)
+		}
+	}()
+	return task.Run(ctx)
+}
+
+// acquireScript захватывает блокировку задачи, если момент расписания еще не обработан
+// и задача не выполняется другим инстансом
+var acquireScript = redis.NewScript(`
+local last = tonumber(redis.call('GET', KEYS[2]) or '0')
+if last >= tonumber(ARGV[2]) then
+	return 0
+end
+if not redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ARGV[3]) then
+	return 0
+end
+redis.call('SET', KEYS[2], ARGV[2])
+return 1
+`)
+
+// releaseScript снимает блокировку, только если она принадлежит этому инстансу
+var releaseScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+func (s *Scheduler) acquire(ctx context.Context, name string, occurrence time.Time, ttl time.Duration) (bool, error) {
+	res, err := acquireScript.Run(ctx, s.client,
+		[]string{s.lockKey(name), s.lastKey(name)},
+		s.token, occurrence.UnixMilli(), ttl.Milliseconds(),
+	).Int()
+	if err != nil {
+		return false, err
+	}
+	return res == 1, nil
+}
+
+func (s *Scheduler) release(name string) {
+	// Контекст задачи может быть отменен остановкой, блокировку снимаем независимо от него
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := releaseScript.Run(ctx, s.client, []string{s.lockKey(name)}, s.token).Err(); err != nil {
+		s.logger.Warn(ctx, "Failed to release scheduled task lock", logrus.Fields{"task": name, "error": err.Error()})
+	}
+}
+
+func (s *Scheduler) lockKey(name string) string { return s.cfg.Prefix + ":lock:" + name }
+func (s *Scheduler) lastKey(name string) string { return s.cfg.Prefix + ":last:" + name }
@@ -0,0 +1,79 @@
+package scheduler
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Note: TestScheduler_SingleRunAcrossInstances requires Redis to be running on localhost:6379
+// Run with: go test -v ./pkg/scheduler/
+
+func noop(ctx context.Context) error { return nil }
+
+func TestScheduler_Add(t *testing.T) {
+	s := New(nil, Config{Registerer: prometheus.NewRegistry()}, logrus.New())
+
+	require.NoError(t, s.Add(Task{Name: "rates", Spec: "@hourly", Run: noop}))
+	assert.ErrorIs(t, s.Add(Task{Name: "rates", Spec: "@daily", Run: noop}), ErrDuplicateTask)
+	assert.Error(t, s.Add(Task{Name: "broken", Spec: "* *", Run: noop}))
+	assert.Error(t, s.Add(Task{Name: "empty", Spec: "@daily"}))
+
+	tasks := s.Tasks()
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "rates", tasks[0].Name)
+	assert.True(t, tasks[0].NextRun.After(time.Now()))
+}
+
+func TestScheduler_SingleRunAcrossInstances(t *testing.T) {
+	client := redis.NewClient(&redis.Options{Addr: "localhost:6379"})
+	if err := client.Ping(context.Background()).Err(); err != nil {
+		t.Skip("Redis not running, skipping tests")
+	}
+	defer client.Close()
+
+	prefix := "scheduler-test-" + uuid.NewString()[:8]
+	defer client.Del(context.Background(), prefix+":lock:archive", prefix+":last:archive")
+
+	var runs int32
+	task := Task{
+		Name: "archive",
+		Spec: "@every 1s",
+		Run: func(ctx context.Context) error {
+			atomic.AddInt32(&runs, 1)
+			time.Sleep(100 * time.Millisecond)
+			return nil
+		},
+	}
+
+	// Три реплики с общим Redis
+	var instances []*Scheduler
+	for i := 0; i < 3; i++ {
+		s := New(client, Config{
+			Prefix:        prefix,
+			CheckInterval: 20 * time.Millisecond,
+			Registerer:    prometheus.NewRegistry(),
+		}, logrus.New())
+		require.NoError(t, s.Add(task))
+		s.Start(context.Background())
+		instances = append(instances, s)
+	}
+
+	time.Sleep(2500 * time.Millisecond)
+	for _, s := range instances {
+		s.Stop()
+	}
+
+	// За 2.5 секунды наступает 2-3 момента расписания, каждый выполняется одним инстансом
+	got := atomic.LoadInt32(&runs)
+	assert.GreaterOrEqual(t, got, int32(2))
+	assert.LessOrEqual(t, got, int32(3))
+}
@@ -0,0 +1,334 @@
+package sdkgen
+
+import (
+	"fmt"
+	"go/format"
+	"strings"
+
+	"github.com/rusgainew/tunduck-app/pkg/openapi"
+)
+
+// goRuntime общая часть Go клиента: транспорт, конверт ответа и ошибка API
+const goRuntime = `
+// Client клиент API. Token передается в заголовке Authorization: Bearer.
+type Client struct {
+	BaseURL    string
+	Token      string
+	HTTPClient *http.Client
+}
+
+// NewClient создает клиент для адреса API, например "https://api.example.com"
+func NewClient(baseURL string) *Client {
+	return &Client{BaseURL: strings.TrimRight(baseURL, "/"), HTTPClient: http.DefaultClient}
+}
+
+// Envelope единый формат ответа API
+type Envelope[T any] struct {
+	Success   bool            ` + "`json:\"success\"`" + `
+	Data      T               ` + "`json:\"data\"`" + `
+	Meta      json.RawMessage ` + "`json:\"meta,omitempty\"`" + `
+	Message   string          ` + "`json:\"message,omitempty\"`" + `
+	RequestID string          ` + "`json:\"request_id,omitempty\"`" + `
+}
+
+// APIError ошибка, которую вернул сервер
+type APIError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	RequestID  string
+	Body       []byte
+}
+
+func (e *APIError) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("api error %d %s: %s", e.StatusCode, e.Code, e.Message)
+	}
+	return fmt.Sprintf("api error %d", e.StatusCode)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body interface{}, out interface{}) error {
+	target := c.BaseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode >= 400 {
+		apiErr := &APIError{StatusCode: resp.StatusCode, Body: data}
+		var envelope struct {
+			Error *struct {
+				Code    string ` + "`json:\"code\"`" + `
+				Message string ` + "`json:\"message\"`" + `
+			} ` + "`json:\"error\"`" + `
+			RequestID string ` + "`json:\"request_id\"`" + `
+		}
+		if json.Unmarshal(data, &envelope) == nil && envelope.Error != nil {
+			apiErr.Code, apiErr.Message, apiErr.RequestID = envelope.Error.Code, envelope.Error.Message, envelope.RequestID
+		}
+		return apiErr
+	}
+
+	switch target := out.(type) {
+	case nil:
+		return nil
+	case *[]byte:
+		*target = data
+		return nil
+	default:
+		if len(data) == 0 {
+			return nil
+		}
+		return json.Unmarshal(data, out)
+	}
+}
+`
+
+// goGenerator собирает исходный код Go клиента
+type goGenerator struct {
+	types   strings.Builder
+	methods strings.Builder
+	time    bool
+}
+
+// generateGo генерирует Go клиент и форматирует его go/format
+func generateGo(doc *openapi.Document, pkg string) ([]byte, error) {
+	g := &goGenerator{}
+
+	for _, name := range sortedKeys(doc.Components.Schemas) {
+		schema := doc.Components.Schemas[name]
+		fmt.Fprintf(&g.types, "// %s схема %s\ntype %s %s\n\n", exportedName(name), name, exportedName(name), g.typeExpr(schema, true))
+	}
+	for _, op := range operations(doc) {
+		g.operation(op)
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "// Code generated by tunduck sdkgen from %s %s. DO NOT EDIT.\n\n", doc.Info.Title, doc.Info.Version)
+	fmt.Fprintf(&out, "// Package %s клиент %s.\npackage %s\n\n", pkg, doc.Info.Title, pkg)
+	out.WriteString("import (\n\t\"bytes\"\n\t\"context\"\n\t\"encoding/json\"\n\t\"fmt\"\n\t\"io\"\n\t\"net/http\"\n\t\"net/url\"\n\t\"strings\"\n")
+	if g.time {
+		out.WriteString("\t\"time\"\n")
+	}
+	out.WriteString(")\n")
+	out.WriteString(goRuntime)
+	out.WriteString("\n")
+	out.WriteString(g.types.String())
+	out.WriteString(g.methods.String())
+
+	formatted, err := format.Source([]byte(out.String()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format generated Go client: %w", err)
+	}
+	return formatted, nil
+}
+
+// typeExpr возвращает выражение типа Go для схемы; top — объявление именованного типа
+func (g *goGenerator) typeExpr(s *openapi.Schema, top bool) string {
+	if s == nil {
+		return "json.RawMessage"
+	}
+	if s.Ref != "" {
+		return exportedName(schemaName(s.Ref))
+	}
+
+	var expr string
+	switch s.Type {
+	case "string":
+		switch s.Format {
+		case "date-time":
+			g.time = true
+			expr = "time.Time"
+		case "byte":
+			expr = "[]byte"
+		default:
+			expr = "string"
+		}
+	case "integer":
+		if s.Format == "int64" {
+			expr = "int64"
+		} else {
+			expr = "int"
+		}
+	case "number":
+		expr = "float64"
+	case "boolean":
+		expr = "bool"
+	case "array":
+		expr = "[]" + g.typeExpr(s.Items, false)
+	case "object":
+		if s.AdditionalProperties != nil {
+			expr = "map[string]" + g.typeExpr(s.AdditionalProperties, false)
+		} else {
+			expr = g.structExpr(s)
+		}
+	default:
+		expr = "json.RawMessage"
+	}
+
+	if s.Nullable && !top && !strings.HasPrefix(expr, "[]") && !strings.HasPrefix(expr, "map[") && expr != "json.RawMessage" {
+		expr = "*" + expr
+	}
+	return expr
+}
+
+func (g *goGenerator) structExpr(s *openapi.Schema) string {
+	var b strings.Builder
+	b.WriteString("struct {\n")
+	for _, name := range sortedKeys(s.Properties) {
+		tag := name
+		if !isRequired(s, name) {
+			tag += ",omitempty"
+		}
+		fmt.Fprintf(&b, "\t%s %s `json:\"%s\"`\n", exportedName(name), g.typeExpr(s.Properties[name], false), tag)
+	}
+	b.WriteString("}")
+	return b.String()
+}
+
+// operation генерирует метод клиента и, при наличии параметров строки запроса, их структуру
+func (g *goGenerator) operation(op operation) {
+	name := exportedName(op.ID)
+	m := &g.methods
+
+	var result, zero string
+	switch op.Result {
+	case resultEnvelope:
+		result = "*Envelope[" + g.typeExpr(op.Data, false) + "]"
+	case resultRaw:
+		result = "*" + g.typeExpr(op.Data, false)
+	case resultBinary:
+		result = "[]byte"
+	}
+	if result != "" {
+		zero = "nil, "
+	}
+
+	params := []string{"ctx context.Context"}
+	for _, p := range op.PathParams {
+		params = append(params, goParamName(p.Name)+" string")
+	}
+	if len(op.QueryParams) > 0 {
+		g.queryStruct(name+"Params", op.QueryParams)
+		params = append(params, "params *"+name+"Params")
+	}
+	if op.Body != nil {
+		params = append(params, "body "+g.typeExpr(op.Body, false))
+	}
+
+	summary := op.Summary
+	if summary == "" {
+		summary = op.Method + " " + op.Path
+	}
+	fmt.Fprintf(m, "// %s %s\n", name, summary)
+	if result != "" {
+		fmt.Fprintf(m, "func (c *Client) %s(%s) (%s, error) {\n", name, strings.Join(params, ", "), result)
+	} else {
+		fmt.Fprintf(m, "func (c *Client) %s(%s) error {\n", name, strings.Join(params, ", "))
+	}
+
+	path := fmt.Sprintf("%q", op.Path)
+	for _, p := range op.PathParams {
+		path = strings.Replace(path, "{"+p.Name+"}", `" + url.PathEscape(`+goParamName(p.Name)+`) + "`, 1)
+	}
+	path = strings.TrimSuffix(strings.TrimPrefix(path, `"" + `), ` + ""`)
+
+	query := "nil"
+	if len(op.QueryParams) > 0 {
+		query = "params.values()"
+		fmt.Fprintf(m, "\tif params == nil {\n\t\tparams = &%sParams{}\n\t}\n", name)
+	}
+	body := "nil"
+	if op.Body != nil {
+		body = "body"
+	}
+
+	switch op.Result {
+	case resultEnvelope:
+		fmt.Fprintf(m, "\tout := new(Envelope[%s])\n", g.typeExpr(op.Data, false))
+	case resultRaw:
+		fmt.Fprintf(m, "\tout := new(%s)\n", g.typeExpr(op.Data, false))
+	case resultBinary:
+		m.WriteString("\tvar out []byte\n")
+	}
+	target := "nil"
+	switch op.Result {
+	case resultEnvelope, resultRaw:
+		target = "out"
+	case resultBinary:
+		target = "&out"
+	}
+
+	if result != "" {
+		fmt.Fprintf(m, "\tif err := c.do(ctx, %q, %s, %s, %s, %s); err != nil {\n\t\treturn %serr\n\t}\n\treturn out, nil\n}\n\n",
+			op.Method, path, query, body, target, zero)
+	} else {
+		fmt.Fprintf(m, "\treturn c.do(ctx, %q, %s, %s, %s, nil)\n}\n\n", op.Method, path, query, body)
+	}
+}
+
+// queryStruct генерирует структуру параметров строки запроса; незаданные параметры не передаются
+func (g *goGenerator) queryStruct(name string, params []openapi.Parameter) {
+	fmt.Fprintf(&g.types, "// %s параметры строки запроса\ntype %s struct {\n", name, name)
+	for _, p := range params {
+		expr := g.typeExpr(p.Schema, true)
+		if !strings.HasPrefix(expr, "[]") {
+			expr = "*" + expr
+		}
+		fmt.Fprintf(&g.types, "\t%s %s\n", exportedName(p.Name), expr)
+	}
+	g.types.WriteString("}\n\n")
+
+	fmt.Fprintf(&g.types, "func (p *%s) values() url.Values {\n\tq := url.Values{}\n", name)
+	for _, p := range params {
+		field := exportedName(p.Name)
+		if p.Schema != nil && p.Schema.Type == "array" {
+			fmt.Fprintf(&g.types, "\tfor _, v := range p.%s {\n\t\tq.Add(%q, fmt.Sprint(v))\n\t}\n", field, p.Name)
+		} else {
+			fmt.Fprintf(&g.types, "\tif p.%s != nil {\n\t\tq.Set(%q, fmt.Sprint(*p.%s))\n\t}\n", field, p.Name, field)
+		}
+	}
+	g.types.WriteString("\treturn q\n}\n\n")
+}
+
+// goParamName имя аргумента метода для параметра пути
+func goParamName(name string) string {
+	n := camelName(name)
+	if n == "type" || n == "range" || n == "func" || n == "ctx" || n == "body" || n == "params" {
+		n += "Param"
+	}
+	return n
+}
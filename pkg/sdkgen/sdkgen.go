@@ -0,0 +1,263 @@
+// Package sdkgen генерирует типизированные клиенты API (Go и TypeScript) по спецификации OpenAPI,
+// которую строит пакет openapi. Клиент каждого языка — один файл без внешних зависимостей.
+package sdkgen
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/rusgainew/tunduck-app/pkg/openapi"
+)
+
+// Language язык генерируемого клиента
+type Language string
+
+const (
+	LanguageGo         Language = "go"
+	LanguageTypeScript Language = "typescript"
+)
+
+// Languages поддерживаемые языки
+var Languages = []Language{LanguageGo, LanguageTypeScript}
+
+// Artifact сгенерированный файл клиента
+type Artifact struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+// Options параметры генерации
+type Options struct {
+	// GoPackage имя пакета Go клиента; по умолчанию "tunduck"
+	GoPackage string
+}
+
+// DefaultGoPackage имя пакета Go клиента по умолчанию
+const DefaultGoPackage = "tunduck"
+
+// ParseLanguage разбирает имя языка; "ts" — синоним typescript
+func ParseLanguage(s string) (Language, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "go", "golang":
+		return LanguageGo, nil
+	case "ts", "typescript":
+		return LanguageTypeScript, nil
+	default:
+		return "", fmt.Errorf("unsupported SDK language %q (available: go, typescript)", s)
+	}
+}
+
+// Generate генерирует клиент на указанном языке
+func Generate(doc *openapi.Document, lang Language, opts Options) (*Artifact, error) {
+	if opts.GoPackage == "" {
+		opts.GoPackage = DefaultGoPackage
+	}
+	switch lang {
+	case LanguageGo:
+		content, err := generateGo(doc, opts.GoPackage)
+		if err != nil {
+			return nil, err
+		}
+		return &Artifact{Filename: "client.go", ContentType: "text/x-go; charset=utf-8", Content: content}, nil
+	case LanguageTypeScript:
+		return &Artifact{Filename: "client.ts", ContentType: "application/typescript; charset=utf-8", Content: generateTypeScript(doc)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported SDK language %q", lang)
+	}
+}
+
+// resultKind форма успешного ответа операции
+type resultKind int
+
+const (
+	resultNone     resultKind = iota // тело не возвращается
+	resultEnvelope                   // конверт response.Envelope с data
+	resultRaw                        // JSON без конверта
+	resultBinary                     // файл или текст
+)
+
+// operation операция спецификации в виде, удобном генераторам
+type operation struct {
+	ID          string
+	Method      string
+	Path        string
+	Summary     string
+	PathParams  []openapi.Parameter
+	QueryParams []openapi.Parameter
+	Body        *openapi.Schema
+	Result      resultKind
+	// Data схема data в конверте или всего тела для resultRaw; nil — произвольное значение
+	Data *openapi.Schema
+}
+
+// operations возвращает операции спецификации в стабильном порядке
+func operations(doc *openapi.Document) []operation {
+	paths := make([]string, 0, len(doc.Paths))
+	for path := range doc.Paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	var ops []operation
+	for _, path := range paths {
+		methods := make([]string, 0, len(doc.Paths[path]))
+		for method := range doc.Paths[path] {
+			methods = append(methods, method)
+		}
+		sort.Strings(methods)
+
+		for _, method := range methods {
+			item := doc.Paths[path][method]
+			op := operation{
+				ID:      item.OperationID,
+				Method:  strings.ToUpper(method),
+				Path:    path,
+				Summary: item.Summary,
+			}
+			for _, p := range item.Parameters {
+				if p.In == "path" {
+					op.PathParams = append(op.PathParams, p)
+				} else if p.In == "query" {
+					op.QueryParams = append(op.QueryParams, p)
+				}
+			}
+			if item.RequestBody != nil {
+				if media := item.RequestBody.Content[fiber.MIMEApplicationJSON]; media != nil {
+					op.Body = media.Schema
+				}
+			}
+			op.Result, op.Data = successResult(item)
+			ops = append(ops, op)
+		}
+	}
+	return ops
+}
+
+// successResult определяет форму успешного ответа по первому коду 2xx
+func successResult(item *openapi.PathItem) (resultKind, *openapi.Schema) {
+	codes := make([]string, 0, len(item.Responses))
+	for code := range item.Responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+	if len(codes) == 0 {
+		return resultNone, nil
+	}
+
+	resp := item.Responses[codes[0]]
+	if len(resp.Content) == 0 || codes[0] == fmt.Sprint(http.StatusNoContent) {
+		return resultNone, nil
+	}
+	media := resp.Content[fiber.MIMEApplicationJSON]
+	if media == nil || media.Schema == nil {
+		return resultBinary, nil
+	}
+	schema := media.Schema
+	if schema.Ref == "" && schema.Type == "object" && schema.Properties["success"] != nil {
+		return resultEnvelope, schema.Properties["data"]
+	}
+	return resultRaw, schema
+}
+
+// schemaName имя схемы по ссылке "#/components/schemas/Name"
+func schemaName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// sortedKeys ключи карты схем в стабильном порядке
+func sortedKeys(m map[string]*openapi.Schema) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// isRequired проверяет, входит ли свойство в required схемы
+func isRequired(s *openapi.Schema, name string) bool {
+	for _, r := range s.Required {
+		if r == name {
+			return true
+		}
+	}
+	return false
+}
+
+// initialisms пишутся в идентификаторах Go целиком заглавными
+var initialisms = map[string]bool{
+	"api": true, "id": true, "ids": true, "url": true, "uuid": true, "json": true, "esf": true,
+	"sql": true, "http": true, "ws": true, "db": true, "tin": true, "vat": true, "csv": true,
+}
+
+// words разбивает имя на слова по разделителям и границам регистра: "created_at", "createdAt", "pkg.Name"
+func words(s string) []string {
+	var result []string
+	var current []rune
+	flush := func() {
+		if len(current) > 0 {
+			result = append(result, string(current))
+			current = nil
+		}
+	}
+	runes := []rune(s)
+	for i, r := range runes {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && i > 0 && (unicode.IsLower(runes[i-1]) ||
+			(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))):
+			flush()
+			current = append(current, r)
+		default:
+			current = append(current, r)
+		}
+	}
+	flush()
+	return result
+}
+
+// exportedName формирует экспортируемый идентификатор: "created_at" -> "CreatedAt", "org_id" -> "OrgID"
+func exportedName(s string) string {
+	var b strings.Builder
+	for _, w := range words(s) {
+		lower := strings.ToLower(w)
+		if initialisms[lower] {
+			b.WriteString(strings.ToUpper(w))
+			continue
+		}
+		r := []rune(w)
+		b.WriteRune(unicode.ToUpper(r[0]))
+		b.WriteString(string(r[1:]))
+	}
+	name := b.String()
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
+
+// camelName формирует идентификатор с первой строчной буквой: "get_api_users_id" -> "getApiUsersId"
+func camelName(s string) string {
+	var b strings.Builder
+	for i, w := range words(s) {
+		r := []rune(strings.ToLower(w))
+		if i > 0 {
+			r[0] = unicode.ToUpper(r[0])
+		}
+		b.WriteString(string(r))
+	}
+	name := b.String()
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "x" + name
+	}
+	return name
+}
@@ -0,0 +1,99 @@
+package sdkgen
+
+import (
+	"go/parser"
+	"go/token"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/pkg/openapi"
+)
+
+type testDocument struct {
+	ID        string     `json:"id"`
+	Number    string     `json:"number" validate:"required"`
+	Status    string     `json:"status" validate:"oneof=draft sent"`
+	Total     float64    `json:"total"`
+	CreatedAt time.Time  `json:"created_at"`
+	SentAt    *time.Time `json:"sent_at"`
+}
+
+type testListQuery struct {
+	Page   int      `query:"page"`
+	Fields []string `query:"fields"`
+}
+
+func testSpec() *openapi.Document {
+	app := fiber.New()
+	noop := func(c *fiber.Ctx) error { return nil }
+	app.Get("/api/org/:org_id/documents", noop)
+	app.Post("/api/org/:org_id/documents", noop)
+	app.Get("/api/documents/:id/pdf", noop)
+	app.Delete("/api/documents/:id", noop)
+
+	reg := openapi.NewRegistry()
+	reg.Add(fiber.MethodGet, "/api/org/:org_id/documents", openapi.Operation{
+		Summary: "List documents", Query: testListQuery{}, Response: []testDocument{},
+	})
+	reg.Add(fiber.MethodPost, "/api/org/:org_id/documents", openapi.Operation{
+		Request: testDocument{}, Response: testDocument{}, Status: fiber.StatusCreated,
+	})
+	reg.Add(fiber.MethodGet, "/api/documents/:id/pdf", openapi.Operation{ContentType: "application/pdf"})
+	reg.Add(fiber.MethodDelete, "/api/documents/:id", openapi.Operation{Status: fiber.StatusNoContent})
+	return openapi.Build(openapi.Info{Title: "Test", Version: "1.0.0"}, app.GetRoutes(true), reg)
+}
+
+func TestGenerate_Go(t *testing.T) {
+	artifact, err := Generate(testSpec(), LanguageGo, Options{GoPackage: "client"})
+	require.NoError(t, err)
+	assert.Equal(t, "client.go", artifact.Filename)
+
+	_, err = parser.ParseFile(token.NewFileSet(), artifact.Filename, artifact.Content, parser.AllErrors)
+	require.NoError(t, err, "generated client must be valid Go")
+
+	src := string(artifact.Content)
+	assert.Contains(t, src, "package client")
+	assert.Contains(t, src, "func (c *Client) GetAPIOrgOrgIDDocuments(ctx context.Context, orgId string, params *GetAPIOrgOrgIDDocumentsParams) (*Envelope[[]TestDocument], error)")
+	assert.Contains(t, src, "func (c *Client) PostAPIOrgOrgIDDocuments(ctx context.Context, orgId string, body TestDocument) (*Envelope[TestDocument], error)")
+	assert.Contains(t, src, "func (c *Client) GetAPIDocumentsIDPdf(ctx context.Context, id string) ([]byte, error)")
+	assert.Contains(t, src, "func (c *Client) DeleteAPIDocumentsID(ctx context.Context, id string) error")
+	assert.Contains(t, src, `"/api/org/"+url.PathEscape(orgId)+"/documents"`)
+	assert.Contains(t, src, "CreatedAt time.Time")
+	assert.Contains(t, src, "`json:\"number\"`", "required fields are always sent")
+	assert.Contains(t, src, `q.Add("fields", fmt.Sprint(v))`)
+}
+
+func TestGenerate_TypeScript(t *testing.T) {
+	artifact, err := Generate(testSpec(), LanguageTypeScript, Options{})
+	require.NoError(t, err)
+	assert.Equal(t, "client.ts", artifact.Filename)
+
+	src := string(artifact.Content)
+	assert.Contains(t, src, "export interface TestDocument {")
+	assert.Contains(t, src, "  number: string;")
+	assert.Contains(t, src, `  status?: "draft" | "sent";`)
+	assert.Contains(t, src, "getApiOrgOrgIdDocuments(orgId: string, params: GetAPIOrgOrgIDDocumentsParams = {}): Promise<Envelope<Array<TestDocument>>>")
+	assert.Contains(t, src, "`/api/org/${encodeURIComponent(orgId)}/documents`")
+	assert.Contains(t, src, "getApiDocumentsIdPdf(id: string): Promise<Blob>")
+	assert.Contains(t, src, "deleteApiDocumentsId(id: string): Promise<void>")
+}
+
+func TestParseLanguage(t *testing.T) {
+	lang, err := ParseLanguage("TS")
+	require.NoError(t, err)
+	assert.Equal(t, LanguageTypeScript, lang)
+
+	_, err = ParseLanguage("java")
+	assert.Error(t, err)
+}
+
+func TestNames(t *testing.T) {
+	assert.Equal(t, "CreatedAt", exportedName("created_at"))
+	assert.Equal(t, "OrganizationID", exportedName("organizationId"))
+	assert.Equal(t, "ModelsESFDocument", exportedName("models.ESFDocument"))
+	assert.Equal(t, "getApiUsersId", camelName("get_api_users_id"))
+}
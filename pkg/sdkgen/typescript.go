@@ -0,0 +1,233 @@
+package sdkgen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rusgainew/tunduck-app/pkg/openapi"
+)
+
+// tsRuntime общая часть TypeScript клиента: транспорт на fetch, конверт ответа и ошибка API
+const tsRuntime = `
+export interface Envelope<T> {
+  success: boolean;
+  data: T;
+  meta?: unknown;
+  message?: string;
+  request_id?: string;
+}
+
+export class ApiError extends Error {
+  constructor(
+    public readonly status: number,
+    public readonly code: string | undefined,
+    message: string,
+    public readonly requestId: string | undefined,
+    public readonly body: unknown,
+  ) {
+    super(message);
+    this.name = "ApiError";
+  }
+}
+
+export interface ClientOptions {
+  /** JWT, передается в заголовке Authorization: Bearer */
+  token?: string;
+  /** Реализация fetch; по умолчанию глобальная */
+  fetch?: typeof fetch;
+}
+
+export class TunduckClient {
+  private readonly baseURL: string;
+
+  constructor(baseURL: string, private readonly options: ClientOptions = {}) {
+    this.baseURL = baseURL.replace(/\/+$/, "");
+  }
+
+  private async request<T>(method: string, path: string, query?: object, body?: unknown, binary = false): Promise<T> {
+    const params = new URLSearchParams();
+    for (const [key, value] of Object.entries(query ?? {})) {
+      if (value === undefined || value === null) continue;
+      for (const item of Array.isArray(value) ? value : [value]) params.append(key, String(item));
+    }
+    const qs = params.toString();
+    const headers: Record<string, string> = {};
+    if (body !== undefined) headers["Content-Type"] = "application/json";
+    if (this.options.token) headers["Authorization"] = "Bearer " + this.options.token;
+
+    const doFetch = this.options.fetch ?? fetch;
+    const resp = await doFetch(this.baseURL + path + (qs ? "?" + qs : ""), {
+      method,
+      headers,
+      body: body === undefined ? undefined : JSON.stringify(body),
+    });
+    if (!resp.ok) {
+      const text = await resp.text();
+      let parsed: any;
+      try { parsed = JSON.parse(text); } catch { parsed = text; }
+      throw new ApiError(resp.status, parsed?.error?.code, parsed?.error?.message ?? resp.statusText, parsed?.request_id, parsed);
+    }
+    if (binary) return (await resp.blob()) as T;
+    const text = await resp.text();
+    return (text ? JSON.parse(text) : undefined) as T;
+  }
+`
+
+// generateTypeScript генерирует TypeScript клиент на fetch без зависимостей
+func generateTypeScript(doc *openapi.Document) []byte {
+	var types, methods strings.Builder
+
+	for _, name := range sortedKeys(doc.Components.Schemas) {
+		schema := doc.Components.Schemas[name]
+		if schema.Type == "object" && schema.AdditionalProperties == nil {
+			fmt.Fprintf(&types, "export interface %s %s\n\n", exportedName(name), tsObject(schema, ""))
+		} else {
+			fmt.Fprintf(&types, "export type %s = %s;\n\n", exportedName(name), tsType(schema))
+		}
+	}
+	for _, op := range operations(doc) {
+		tsOperation(&types, &methods, op)
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "// Code generated by tunduck sdkgen from %s %s. DO NOT EDIT.\n/* eslint-disable */\n\n", doc.Info.Title, doc.Info.Version)
+	out.WriteString(types.String())
+	out.WriteString(strings.TrimLeft(tsRuntime, "\n"))
+	out.WriteString(methods.String())
+	out.WriteString("}\n")
+	return []byte(out.String())
+}
+
+// tsType возвращает выражение типа TypeScript для схемы
+func tsType(s *openapi.Schema) string {
+	if s == nil {
+		return "unknown"
+	}
+	if s.Ref != "" {
+		return exportedName(schemaName(s.Ref))
+	}
+
+	var expr string
+	switch s.Type {
+	case "string":
+		if len(s.Enum) > 0 {
+			quoted := make([]string, len(s.Enum))
+			for i, v := range s.Enum {
+				quoted[i] = fmt.Sprintf("%q", v)
+			}
+			expr = strings.Join(quoted, " | ")
+		} else {
+			expr = "string"
+		}
+	case "integer", "number":
+		expr = "number"
+	case "boolean":
+		expr = "boolean"
+	case "array":
+		expr = "Array<" + tsType(s.Items) + ">"
+	case "object":
+		if s.AdditionalProperties != nil {
+			expr = "Record<string, " + tsType(s.AdditionalProperties) + ">"
+		} else if len(s.Properties) == 0 {
+			expr = "Record<string, unknown>"
+		} else {
+			expr = tsObject(s, "")
+		}
+	default:
+		expr = "unknown"
+	}
+	if s.Nullable {
+		expr += " | null"
+	}
+	return expr
+}
+
+// tsObject возвращает литерал объектного типа; indent — отступ вложенного литерала
+func tsObject(s *openapi.Schema, indent string) string {
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, name := range sortedKeys(s.Properties) {
+		optional := "?"
+		if isRequired(s, name) {
+			optional = ""
+		}
+		prop := s.Properties[name]
+		expr := tsType(prop)
+		if prop != nil && prop.Ref == "" && prop.Type == "object" && prop.AdditionalProperties == nil && len(prop.Properties) > 0 {
+			expr = tsObject(prop, indent+"  ")
+		}
+		fmt.Fprintf(&b, "%s  %s%s: %s;\n", indent, tsKey(name), optional, expr)
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+// tsKey экранирует имя свойства, если оно не является идентификатором
+func tsKey(name string) string {
+	for i, r := range name {
+		if !(r == '_' || r == '$' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || i > 0 && r >= '0' && r <= '9') {
+			return fmt.Sprintf("%q", name)
+		}
+	}
+	return name
+}
+
+// tsOperation генерирует метод клиента и тип параметров строки запроса
+func tsOperation(types, methods *strings.Builder, op operation) {
+	name := camelName(op.ID)
+
+	var result string
+	switch op.Result {
+	case resultEnvelope:
+		result = "Envelope<" + tsType(op.Data) + ">"
+	case resultRaw:
+		result = tsType(op.Data)
+	case resultBinary:
+		result = "Blob"
+	default:
+		result = "void"
+	}
+
+	var params []string
+	for _, p := range op.PathParams {
+		params = append(params, camelName(p.Name)+": string")
+	}
+	query := "undefined"
+	if len(op.QueryParams) > 0 {
+		queryType := exportedName(op.ID) + "Params"
+		fmt.Fprintf(types, "export interface %s {\n", queryType)
+		defaults := " = {}"
+		for _, p := range op.QueryParams {
+			optional := "?"
+			if p.Required {
+				optional, defaults = "", ""
+			}
+			fmt.Fprintf(types, "  %s%s: %s;\n", tsKey(p.Name), optional, tsType(p.Schema))
+		}
+		types.WriteString("}\n\n")
+		params = append(params, "params: "+queryType+defaults)
+		query = "params"
+	}
+	body := "undefined"
+	if op.Body != nil {
+		params = append(params, "body: "+tsType(op.Body))
+		body = "body"
+	}
+
+	path := op.Path
+	for _, p := range op.PathParams {
+		path = strings.Replace(path, "{"+p.Name+"}", "${encodeURIComponent("+camelName(p.Name)+")}", 1)
+	}
+
+	summary := op.Summary
+	if summary == "" {
+		summary = op.Method + " " + op.Path
+	}
+	fmt.Fprintf(methods, "\n  /** %s */\n", summary)
+	fmt.Fprintf(methods, "  %s(%s): Promise<%s> {\n", name, strings.Join(params, ", "), result)
+	binary := ""
+	if op.Result == resultBinary {
+		binary = ", true"
+	}
+	fmt.Fprintf(methods, "    return this.request<%s>(%q, `%s`, %s, %s%s);\n  }\n", result, op.Method, path, query, body, binary)
+}
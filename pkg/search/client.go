@@ -0,0 +1,253 @@
+// Package search реализует индексацию документов ЭСФ в OpenSearch/Elasticsearch
+// через REST API. Индекс создается отдельно для каждой организации.
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Параметры клиента по умолчанию
+const (
+	DefaultIndexPrefix   = "esf-documents"
+	DefaultTimeout       = 5 * time.Second
+	DefaultIndexInterval = 5 * time.Second
+)
+
+// ErrUnavailable возвращается при ошибке обращения к OpenSearch
+var ErrUnavailable = errors.New("search backend unavailable")
+
+// Config параметры подключения к OpenSearch
+type Config struct {
+	URL         string
+	Username    string
+	Password    string
+	IndexPrefix string
+	Timeout     time.Duration
+	// IndexInterval период опроса outbox индексатором
+	IndexInterval time.Duration
+	// Organizations ограничивает индексацию крупными организациями; пустой список — все организации
+	Organizations []string
+}
+
+// Enabled сообщает, настроен ли OpenSearch
+func (c Config) Enabled() bool {
+	return c.URL != ""
+}
+
+// Operation операция bulk-запроса
+type Operation struct {
+	Delete   bool
+	ID       string
+	Document *Document
+}
+
+// Result результат поиска: идентификаторы документов в порядке релевантности
+type Result struct {
+	IDs   []uuid.UUID
+	Total int64
+}
+
+// Client клиент OpenSearch
+type Client struct {
+	baseURL  string
+	username string
+	password string
+	prefix   string
+	orgs     map[string]bool
+	http     *http.Client
+}
+
+// NewClient создает клиент OpenSearch
+func NewClient(cfg Config) *Client {
+	if cfg.IndexPrefix == "" {
+		cfg.IndexPrefix = DefaultIndexPrefix
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+
+	orgs := make(map[string]bool, len(cfg.Organizations))
+	for _, id := range cfg.Organizations {
+		orgs[strings.ToLower(id)] = true
+	}
+
+	return &Client{
+		baseURL:  strings.TrimRight(cfg.URL, "/"),
+		username: cfg.Username,
+		password: cfg.Password,
+		prefix:   cfg.IndexPrefix,
+		orgs:     orgs,
+		http:     &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// Enabled сообщает, индексируются ли документы организации
+func (c *Client) Enabled(orgID uuid.UUID) bool {
+	return len(c.orgs) == 0 || c.orgs[orgID.String()]
+}
+
+// IndexName возвращает имя индекса организации
+func (c *Client) IndexName(orgID uuid.UUID) string {
+	return c.prefix + "-" + orgID.String()
+}
+
+// Ping проверяет доступность кластера
+func (c *Client) Ping(ctx context.Context) error {
+	_, err := c.do(ctx, http.MethodGet, "/", "", nil)
+	return err
+}
+
+// Bulk выполняет индексацию и удаление документов одним запросом
+func (c *Client) Bulk(ctx context.Context, orgID uuid.UUID, ops []Operation) error {
+	if len(ops) == 0 {
+		return nil
+	}
+
+	index := c.IndexName(orgID)
+	var body bytes.Buffer
+	enc := json.NewEncoder(&body)
+	for _, op := range ops {
+		action := "index"
+		if op.Delete {
+			action = "delete"
+		}
+		if err := enc.Encode(map[string]map[string]string{action: {"_index": index, "_id": op.ID}}); err != nil {
+			return err
+		}
+		if !op.Delete {
+			if err := enc.Encode(op.Document); err != nil {
+				return err
+			}
+		}
+	}
+
+	raw, err := c.do(ctx, http.MethodPost, "/_bulk", "application/x-ndjson", &body)
+	if err != nil {
+		return err
+	}
+
+	var resp struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Status int `json:"status"`
+			Error  *struct {
+				Reason string `json:"reason"`
+			} `json:"error"`
+		} `json:"items"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return fmt.Errorf("decoding bulk response: %w", err)
+	}
+	if !resp.Errors {
+		return nil
+	}
+
+	for _, item := range resp.Items {
+		for action, result := range item {
+			// Удаление отсутствующего в индексе документа не считается ошибкой
+			if action == "delete" && result.Status == http.StatusNotFound {
+				continue
+			}
+			if result.Error != nil {
+				return fmt.Errorf("bulk %s failed: %s", action, result.Error.Reason)
+			}
+		}
+	}
+	return nil
+}
+
+// Search ищет документы организации по тексту
+func (c *Client) Search(ctx context.Context, orgID uuid.UUID, text string, from, size int) (Result, error) {
+	query := map[string]interface{}{
+		"from":             from,
+		"size":             size,
+		"_source":          false,
+		"track_total_hits": true,
+		"query": map[string]interface{}{
+			"multi_match": map[string]interface{}{
+				"query":     text,
+				"fields":    searchFields,
+				"fuzziness": "AUTO",
+			},
+		},
+	}
+
+	body, err := json.Marshal(query)
+	if err != nil {
+		return Result{}, err
+	}
+
+	raw, err := c.do(ctx, http.MethodPost, "/"+c.IndexName(orgID)+"/_search", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return Result{}, err
+	}
+
+	var resp struct {
+		Hits struct {
+			Total struct {
+				Value int64 `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				ID string `json:"_id"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return Result{}, fmt.Errorf("decoding search response: %w", err)
+	}
+
+	result := Result{Total: resp.Hits.Total.Value, IDs: make([]uuid.UUID, 0, len(resp.Hits.Hits))}
+	for _, hit := range resp.Hits.Hits {
+		id, err := uuid.Parse(hit.ID)
+		if err != nil {
+			continue
+		}
+		result.IDs = append(result.IDs, id)
+	}
+	return result, nil
+}
+
+func (c *Client) do(ctx context.Context, method, path, contentType string, body io.Reader) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("%w: %s %s returned %d: %s", ErrUnavailable, method, path, resp.StatusCode, truncate(raw, 200))
+	}
+	return raw, nil
+}
+
+func truncate(b []byte, n int) string {
+	if len(b) > n {
+		return string(b[:n]) + "..."
+	}
+	return string(b)
+}
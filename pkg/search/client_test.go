@@ -0,0 +1,125 @@
+package search
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+func TestClient_Enabled(t *testing.T) {
+	large := uuid.New()
+
+	all := NewClient(Config{URL: "http://search"})
+	assert.True(t, all.Enabled(uuid.New()))
+
+	limited := NewClient(Config{URL: "http://search", Organizations: []string{large.String()}})
+	assert.True(t, limited.Enabled(large))
+	assert.False(t, limited.Enabled(uuid.New()))
+}
+
+func TestClient_Bulk(t *testing.T) {
+	orgID := uuid.New()
+	docID := uuid.New()
+	deletedID := uuid.New()
+
+	var lines []map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/_bulk", r.URL.Path)
+		assert.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"))
+
+		scanner := bufio.NewScanner(r.Body)
+		for scanner.Scan() {
+			var line map[string]interface{}
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+			lines = append(lines, line)
+		}
+
+		// Удаление отсутствующего документа (404) не должно считаться ошибкой
+		_, _ = w.Write([]byte(`{"errors":true,"items":[{"index":{"status":201}},{"delete":{"status":404}}]}`))
+	}))
+	defer srv.Close()
+
+	doc := NewDocument(&entity.EsfDocument{
+		ID:                 docID,
+		ContractorTin:      "01234567890123",
+		TotalCurrencyValue: 1500,
+		CatalogEntries:     []entity.EsfEntries{{SalesTaxCode: "1001"}},
+	})
+
+	err := NewClient(Config{URL: srv.URL}).Bulk(context.Background(), orgID, []Operation{
+		{ID: docID.String(), Document: &doc},
+		{ID: deletedID.String(), Delete: true},
+	})
+	require.NoError(t, err)
+
+	require.Len(t, lines, 3)
+	index := "esf-documents-" + orgID.String()
+	assert.Equal(t, map[string]interface{}{"_index": index, "_id": docID.String()}, lines[0]["index"])
+	assert.Equal(t, "01234567890123", lines[1]["contractor_tin"])
+	assert.Equal(t, []interface{}{"1001"}, lines[1]["sales_tax_codes"])
+	assert.Equal(t, map[string]interface{}{"_index": index, "_id": deletedID.String()}, lines[2]["delete"])
+}
+
+func TestClient_BulkItemError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"errors":true,"items":[{"index":{"status":400,"error":{"reason":"mapper_parsing_exception"}}}]}`))
+	}))
+	defer srv.Close()
+
+	doc := Document{ID: uuid.NewString()}
+	err := NewClient(Config{URL: srv.URL}).Bulk(context.Background(), uuid.New(), []Operation{{ID: doc.ID, Document: &doc}})
+	assert.ErrorContains(t, err, "mapper_parsing_exception")
+}
+
+func TestClient_Search(t *testing.T) {
+	orgID := uuid.New()
+	first, second := uuid.New(), uuid.New()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/esf-documents-"+orgID.String()+"/_search", r.URL.Path)
+		user, pass, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "admin", user)
+		assert.Equal(t, "secret", pass)
+
+		var body map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.EqualValues(t, 20, body["from"])
+		assert.EqualValues(t, 10, body["size"])
+
+		_, _ = w.Write([]byte(`{"hits":{"total":{"value":42},"hits":[{"_id":"` + first.String() + `"},{"_id":"` + second.String() + `"}]}}`))
+	}))
+	defer srv.Close()
+
+	client := NewClient(Config{URL: srv.URL, Username: "admin", Password: "secret"})
+	result, err := client.Search(context.Background(), orgID, "contract 15", 20, 10)
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(42), result.Total)
+	assert.Equal(t, []uuid.UUID{first, second}, result.IDs)
+}
+
+func TestClient_Unavailable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"error":{"type":"index_not_found_exception"}}`))
+	}))
+	defer srv.Close()
+
+	_, err := NewClient(Config{URL: srv.URL}).Search(context.Background(), uuid.New(), "x", 0, 10)
+	assert.True(t, errors.Is(err, ErrUnavailable))
+
+	srv.Close()
+	err = NewClient(Config{URL: srv.URL}).Ping(context.Background())
+	assert.True(t, errors.Is(err, ErrUnavailable))
+}
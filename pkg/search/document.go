@@ -0,0 +1,59 @@
+package search
+
+import (
+	"time"
+
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// Document представление документа ЭСФ в поисковом индексе
+type Document struct {
+	ID                    string    `json:"id"`
+	ContractorTin         string    `json:"contractor_tin"`
+	ForeignName           string    `json:"foreign_name,omitempty"`
+	SupplyContractNumber  string    `json:"supply_contract_number,omitempty"`
+	OwnedCrmReceiptCode   string    `json:"owned_crm_receipt_code,omitempty"`
+	PersonalAccountNumber string    `json:"personal_account_number,omitempty"`
+	Comment               string    `json:"comment,omitempty"`
+	OperationTypeCode     string    `json:"operation_type_code"`
+	CurrencyCode          string    `json:"currency_code"`
+	DeliveryDate          time.Time `json:"delivery_date"`
+	Amount                float64   `json:"amount"`
+	SalesTaxCodes         []string  `json:"sales_tax_codes,omitempty"`
+	CreatedAt             time.Time `json:"created_at"`
+}
+
+// NewDocument строит поисковое представление из сущности документа
+func NewDocument(doc *entity.EsfDocument) Document {
+	d := Document{
+		ID:                    doc.ID.String(),
+		ContractorTin:         doc.ContractorTin,
+		ForeignName:           doc.ForeignName,
+		SupplyContractNumber:  doc.SupplyContractNumber,
+		OwnedCrmReceiptCode:   doc.OwnedCrmReceiptCode,
+		PersonalAccountNumber: doc.PersonalAccountNumber,
+		Comment:               doc.Comment,
+		OperationTypeCode:     doc.OperationTypeCode,
+		CurrencyCode:          doc.CurrencyCode,
+		DeliveryDate:          doc.DeliveryDate.Time,
+		Amount:                doc.TotalCurrencyValue,
+		CreatedAt:             doc.CreatedAt,
+	}
+	for _, e := range doc.CatalogEntries {
+		if e.SalesTaxCode != "" {
+			d.SalesTaxCodes = append(d.SalesTaxCodes, e.SalesTaxCode)
+		}
+	}
+	return d
+}
+
+// searchFields поля полнотекстового поиска с весами
+var searchFields = []string{
+	"contractor_tin^3",
+	"supply_contract_number^2",
+	"owned_crm_receipt_code^2",
+	"personal_account_number^2",
+	"foreign_name",
+	"comment",
+	"sales_tax_codes",
+}
@@ -0,0 +1,173 @@
+package shadow
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Параметры воспроизведения по умолчанию
+const (
+	DefaultReplayConcurrency = 1
+	// maxReportedMismatches число расхождений, перечисляемых в отчете
+	maxReportedMismatches = 20
+)
+
+// ReplayConfig параметры воспроизведения записанных запросов
+type ReplayConfig struct {
+	// TargetURL базовый адрес проверяемой установки
+	TargetURL string
+	// Token заменяет заголовок Authorization: при записи учетные данные не сохраняются
+	Token string
+	// Concurrency число одновременных запросов; 1 сохраняет порядок записи
+	Concurrency int
+	Timeout     time.Duration
+}
+
+// Mismatch запрос, на который проверяемая версия ответила иначе, чем основная
+type Mismatch struct {
+	RequestID string
+	Method    string
+	URI       string
+	Primary   int
+	// Shadow статус ответа проверяемой версии; 0 — запрос не выполнен (Error)
+	Shadow int
+	Error  string
+}
+
+// ReplayReport итоги воспроизведения
+type ReplayReport struct {
+	Duration   time.Duration
+	Requests   int
+	Matched    int
+	Mismatched int
+	Failed     int
+	// Mismatches первые расхождения и ошибки
+	Mismatches []Mismatch
+}
+
+// Replayer воспроизводит записанные запросы на проверяемой установке и сравнивает статусы ответов
+type Replayer struct {
+	cfg    ReplayConfig
+	client *http.Client
+
+	mu     sync.Mutex
+	report ReplayReport
+}
+
+// NewReplayer создает Replayer
+func NewReplayer(cfg ReplayConfig) (*Replayer, error) {
+	if cfg.TargetURL == "" {
+		return nil, errors.New("shadow: replay target URL is required")
+	}
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = DefaultReplayConcurrency
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	return &Replayer{cfg: cfg, client: &http.Client{Timeout: cfg.Timeout}}, nil
+}
+
+// Replay воспроизводит запросы из r (NDJSON, как пишет режим record). Можно вызывать для нескольких
+// файлов подряд: итоги накапливаются в Report.
+func (rp *Replayer) Replay(ctx context.Context, r io.Reader) error {
+	start := time.Now()
+	defer func() {
+		rp.mu.Lock()
+		rp.report.Duration += time.Since(start)
+		rp.mu.Unlock()
+	}()
+
+	requests := make(chan Request)
+	var wg sync.WaitGroup
+	for i := 0; i < rp.cfg.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for req := range requests {
+				status, err := Send(ctx, rp.client, rp.cfg.TargetURL, req, rp.cfg.Token)
+				rp.record(req, status, err)
+			}
+		}()
+	}
+
+	err := decodeRequests(ctx, r, requests)
+	close(requests)
+	wg.Wait()
+	return err
+}
+
+// Report возвращает накопленные итоги
+func (rp *Replayer) Report() *ReplayReport {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+	report := rp.report
+	report.Mismatches = append([]Mismatch(nil), rp.report.Mismatches...)
+	return &report
+}
+
+func decodeRequests(ctx context.Context, r io.Reader, out chan<- Request) error {
+	reader := bufio.NewReader(r)
+	for line := 1; ; line++ {
+		raw, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(raw)) > 0 {
+			var req Request
+			if err := json.Unmarshal(raw, &req); err != nil {
+				return fmt.Errorf("line %d: %w", line, err)
+			}
+			select {
+			case out <- req:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (rp *Replayer) record(req Request, status int, err error) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	rp.report.Requests++
+	mismatch := Mismatch{RequestID: req.RequestID, Method: req.Method, URI: req.URI, Primary: req.Status, Shadow: status}
+	switch {
+	case err != nil:
+		rp.report.Failed++
+		mismatch.Error = err.Error()
+	case status == req.Status:
+		rp.report.Matched++
+		return
+	default:
+		rp.report.Mismatched++
+	}
+	if len(rp.report.Mismatches) < maxReportedMismatches {
+		rp.report.Mismatches = append(rp.report.Mismatches, mismatch)
+	}
+}
+
+// Write печатает итоги и расхождения
+func (r *ReplayReport) Write(w io.Writer) {
+	fmt.Fprintf(w, "Replayed %d requests in %s: %d matched, %d mismatched, %d failed\n",
+		r.Requests, r.Duration.Round(time.Millisecond), r.Matched, r.Mismatched, r.Failed)
+	for _, m := range r.Mismatches {
+		if m.Error != "" {
+			fmt.Fprintf(w, "  %s %s: primary %d, error: %s (request %s)\n", m.Method, m.URI, m.Primary, m.Error, m.RequestID)
+			continue
+		}
+		fmt.Fprintf(w, "  %s %s: primary %d, shadow %d (request %s)\n", m.Method, m.URI, m.Primary, m.Shadow, m.RequestID)
+	}
+}
@@ -0,0 +1,431 @@
+// Package shadow копирует входящие запросы (теневой трафик), чтобы проверить новую версию сервиса
+// документов на трафике продакшена, не влияя на ответы клиентам.
+//
+// Режимы:
+//   - mirror — запрос отправляется на вторую установку (TargetURL), ее ответ только сравнивается
+//     по статусу с ответом основной версии;
+//   - record — запросы пишутся в объектное хранилище файлами NDJSON, чтобы позже воспроизвести их
+//     командой shadow-replay.
+//
+// Копии ставятся в буфер в памяти и отправляются в фоне (Start). При полном буфере копия
+// отбрасывается (shadow_requests_total{result="dropped"}), не задерживая запрос клиента.
+package shadow
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/pkg/metrics"
+	"github.com/rusgainew/tunduck-app/pkg/storage"
+)
+
+// Режимы теневого трафика
+const (
+	ModeMirror = "mirror"
+	ModeRecord = "record"
+)
+
+// HeaderShadow отмечает копию запроса; такие запросы не копируются повторно
+const HeaderShadow = "X-Shadow-Request"
+
+// HeaderPrimaryStatus статус ответа основной версии на отправленную копию
+const HeaderPrimaryStatus = "X-Shadow-Primary-Status"
+
+// Параметры по умолчанию
+const (
+	DefaultSampleRate    = 1.0
+	DefaultMaxBodySize   = 1 << 20
+	DefaultBufferSize    = 1000
+	DefaultWorkers       = 4
+	DefaultTimeout       = 10 * time.Second
+	DefaultBatchSize     = 500
+	DefaultFlushInterval = 30 * time.Second
+	DefaultKeyPrefix     = "shadow/"
+)
+
+// DefaultRoutes маршруты сервиса документов
+var DefaultRoutes = []string{"/api/esf-documents"}
+
+// DefaultRedactHeaders заголовки с учетными данными, которые не записываются в хранилище
+var DefaultRedactHeaders = []string{"Authorization", "Cookie", "X-Api-Key", "X-Signature"}
+
+// skippedHeaders заголовки соединения, которые не копируются
+var skippedHeaders = map[string]bool{
+	"Connection":        true,
+	"Content-Length":    true,
+	"Host":              true,
+	"Keep-Alive":        true,
+	"Proxy-Connection":  true,
+	"Te":                true,
+	"Trailer":           true,
+	"Transfer-Encoding": true,
+	"Upgrade":           true,
+}
+
+// Config параметры теневого трафика
+type Config struct {
+	// Mode mirror или record; пустой выключает копирование
+	Mode string
+	// TargetURL базовый адрес второй установки для mirror
+	TargetURL string
+	// Routes префиксы путей, запросы к которым копируются
+	Routes []string
+	// SampleRate доля копируемых запросов (0..1]
+	SampleRate float64
+	// MaxBodySize запросы с телом больше этого размера не копируются
+	MaxBodySize int
+	// BufferSize число копий, ожидающих отправки
+	BufferSize int
+	// Workers число одновременных запросов к второй установке
+	Workers int
+	// Timeout запроса к второй установке и записи в хранилище
+	Timeout time.Duration
+	// BatchSize и FlushInterval — наибольшее число запросов в одном файле и время ожидания неполного файла
+	BatchSize     int
+	FlushInterval time.Duration
+	// KeyPrefix префикс ключей файлов в хранилище
+	KeyPrefix string
+	// RedactHeaders заголовки, которые не записываются в режиме record
+	RedactHeaders []string
+	Registerer    prometheus.Registerer
+}
+
+// Enabled сообщает, включено ли копирование
+func (c Config) Enabled() bool {
+	return c.Mode != ""
+}
+
+// Request копия запроса
+type Request struct {
+	Time      time.Time `json:"time"`
+	RequestID string    `json:"requestId,omitempty"`
+	Method    string    `json:"method"`
+	// URI путь с параметрами запроса
+	URI     string            `json:"uri"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    []byte            `json:"body,omitempty"`
+	// Status и DurationMs ответа основной версии
+	Status     int   `json:"status"`
+	DurationMs int64 `json:"durationMs"`
+}
+
+// Shadow копирует запросы на вторую установку или в хранилище
+type Shadow struct {
+	cfg    Config
+	store  storage.Storage
+	logger *logrus.Logger
+	client *http.Client
+	host   string
+	redact map[string]bool
+	queue  chan Request
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	requests *prometheus.CounterVec
+}
+
+// New создает Shadow; store нужен только для record. Копирование начинается после Start.
+func New(cfg Config, store storage.Storage, log *logrus.Logger) (*Shadow, error) {
+	switch cfg.Mode {
+	case ModeMirror:
+		if cfg.TargetURL == "" {
+			return nil, errors.New("shadow: target URL is required for mirror mode")
+		}
+	case ModeRecord:
+		if store == nil {
+			return nil, errors.New("shadow: storage is required for record mode")
+		}
+	default:
+		return nil, fmt.Errorf("shadow: unknown mode %q, expected mirror or record", cfg.Mode)
+	}
+	if len(cfg.Routes) == 0 {
+		cfg.Routes = DefaultRoutes
+	}
+	if cfg.SampleRate <= 0 || cfg.SampleRate > 1 {
+		cfg.SampleRate = DefaultSampleRate
+	}
+	if cfg.MaxBodySize <= 0 {
+		cfg.MaxBodySize = DefaultMaxBodySize
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = DefaultBufferSize
+	}
+	if cfg.Workers <= 0 {
+		cfg.Workers = DefaultWorkers
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = DefaultBatchSize
+	}
+	if cfg.FlushInterval <= 0 {
+		cfg.FlushInterval = DefaultFlushInterval
+	}
+	if cfg.KeyPrefix == "" {
+		cfg.KeyPrefix = DefaultKeyPrefix
+	}
+	if cfg.RedactHeaders == nil {
+		cfg.RedactHeaders = DefaultRedactHeaders
+	}
+	if cfg.Registerer == nil {
+		cfg.Registerer = prometheus.DefaultRegisterer
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "unknown"
+	}
+	s := &Shadow{
+		cfg:    cfg,
+		store:  store,
+		logger: log,
+		client: &http.Client{Timeout: cfg.Timeout},
+		host:   hostname,
+		redact: make(map[string]bool, len(cfg.RedactHeaders)),
+		queue:  make(chan Request, cfg.BufferSize),
+	}
+	for _, header := range cfg.RedactHeaders {
+		s.redact[http.CanonicalHeaderKey(header)] = true
+	}
+
+	s.requests = metrics.Register(cfg.Registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "shadow_requests_total",
+		Help: "Total number of shadowed requests by result (match, mismatch, recorded, failed, dropped)",
+	}, []string{"result"}))
+	metrics.Register(cfg.Registerer, prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "shadow_queue_length",
+		Help: "Number of shadowed requests waiting to be mirrored or recorded",
+	}, func() float64 { return float64(len(s.queue)) }))
+	return s, nil
+}
+
+// Mode режим копирования
+func (s *Shadow) Mode() string {
+	return s.cfg.Mode
+}
+
+// Config параметры с примененными значениями по умолчанию
+func (s *Shadow) Config() Config {
+	return s.cfg
+}
+
+// Matches сообщает, нужно ли копировать запрос к path с телом bodySize байт; учитывает выборку SampleRate
+func (s *Shadow) Matches(path string, bodySize int) bool {
+	if bodySize > s.cfg.MaxBodySize {
+		return false
+	}
+	matched := false
+	for _, prefix := range s.cfg.Routes {
+		if strings.HasPrefix(path, prefix) {
+			matched = true
+			break
+		}
+	}
+	return matched && (s.cfg.SampleRate >= 1 || rand.Float64() < s.cfg.SampleRate)
+}
+
+// SkipHeader сообщает, что заголовок не копируется: заголовки соединения, а в режиме record
+// также учетные данные из RedactHeaders
+func (s *Shadow) SkipHeader(name string) bool {
+	name = http.CanonicalHeaderKey(name)
+	return skippedHeaders[name] || (s.cfg.Mode == ModeRecord && s.redact[name])
+}
+
+// Capture ставит копию в очередь; при полном буфере копия отбрасывается
+func (s *Shadow) Capture(req Request) {
+	select {
+	case s.queue <- req:
+	default:
+		s.requests.WithLabelValues("dropped").Inc()
+	}
+}
+
+// Start запускает отправку копий в фоне
+func (s *Shadow) Start(context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.done != nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+	go s.run(ctx)
+	return nil
+}
+
+// Stop прекращает копирование. В режиме record оставшиеся запросы записываются, ожидая не дольше ctx;
+// в режиме mirror неотправленные копии отбрасываются.
+func (s *Shadow) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.mu.Unlock()
+	if done == nil {
+		return nil
+	}
+
+	cancel()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("shadow traffic stop: %w", ctx.Err())
+	}
+}
+
+func (s *Shadow) run(ctx context.Context) {
+	defer close(s.done)
+	if s.cfg.Mode == ModeRecord {
+		s.record(ctx)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < s.cfg.Workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case req := <-s.queue:
+					s.mirror(ctx, req)
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// mirror отправляет копию на вторую установку и сравнивает статус ответа с основной версией
+func (s *Shadow) mirror(ctx context.Context, req Request) {
+	status, err := Send(ctx, s.client, s.cfg.TargetURL, req, "")
+	switch {
+	case err != nil:
+		if ctx.Err() != nil {
+			return
+		}
+		s.requests.WithLabelValues("failed").Inc()
+		s.logger.WithError(err).WithFields(logrus.Fields{
+			"request_id": req.RequestID,
+			"method":     req.Method,
+			"uri":        req.URI,
+		}).Warn("Failed to mirror shadow request")
+	case status == req.Status:
+		s.requests.WithLabelValues("match").Inc()
+	default:
+		s.requests.WithLabelValues("mismatch").Inc()
+		s.logger.WithFields(logrus.Fields{
+			"request_id":     req.RequestID,
+			"method":         req.Method,
+			"uri":            req.URI,
+			"primary_status": req.Status,
+			"shadow_status":  status,
+		}).Warn("Shadow response status differs from primary")
+	}
+}
+
+// record пишет копии в хранилище файлами по BatchSize запросов или раз в FlushInterval
+func (s *Shadow) record(ctx context.Context) {
+	ticker := time.NewTicker(s.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]Request, 0, s.cfg.BatchSize)
+	for {
+		select {
+		case req := <-s.queue:
+			if batch = append(batch, req); len(batch) < s.cfg.BatchSize {
+				continue
+			}
+		case <-ticker.C:
+			if len(batch) == 0 {
+				continue
+			}
+		case <-ctx.Done():
+			// При остановке очередь дописывается теми же пачками не больше BatchSize
+			for len(s.queue) > 0 {
+				batch = append(batch, <-s.queue)
+			}
+			for len(batch) > 0 {
+				n := min(len(batch), s.cfg.BatchSize)
+				s.write(batch[:n])
+				batch = batch[n:]
+			}
+			return
+		}
+
+		s.write(batch)
+		batch = batch[:0]
+	}
+}
+
+// write записывает пачку одним объектом shadow/2006-01-02/<host>-<unix nano>.ndjson
+func (s *Shadow) write(batch []Request) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, req := range batch {
+		if err := enc.Encode(req); err != nil {
+			s.requests.WithLabelValues("failed").Inc()
+			continue
+		}
+	}
+
+	now := time.Now().UTC()
+	key := fmt.Sprintf("%s%s/%s-%d.ndjson", s.cfg.KeyPrefix, now.Format("2006-01-02"), s.host, now.UnixNano())
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.Timeout)
+	defer cancel()
+	if err := s.store.Put(ctx, key, bytes.NewReader(buf.Bytes()), int64(buf.Len()), storage.PutOptions{ContentType: "application/x-ndjson"}); err != nil {
+		s.requests.WithLabelValues("failed").Add(float64(len(batch)))
+		s.logger.WithError(err).WithFields(logrus.Fields{"key": key, "requests": len(batch)}).Warn("Failed to record shadow requests")
+		return
+	}
+	s.requests.WithLabelValues("recorded").Add(float64(len(batch)))
+}
+
+// Send отправляет копию запроса на baseURL и возвращает статус ответа; тело ответа не читается.
+// Непустой token заменяет заголовок Authorization.
+func Send(ctx context.Context, client *http.Client, baseURL string, req Request, token string) (int, error) {
+	var body io.Reader
+	if len(req.Body) > 0 {
+		body = bytes.NewReader(req.Body)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, req.Method, strings.TrimRight(baseURL, "/")+req.URI, body)
+	if err != nil {
+		return 0, err
+	}
+	for name, value := range req.Headers {
+		httpReq.Header.Set(name, value)
+	}
+	if token != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+token)
+	}
+	httpReq.Header.Set(HeaderShadow, "1")
+	httpReq.Header.Set(HeaderPrimaryStatus, strconv.Itoa(req.Status))
+
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return 0, err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,153 @@
+package shadow
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/pkg/storage"
+)
+
+// target тестовая проверяемая установка: отвечает 201 на POST и 404 на остальные запросы
+type target struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   []string
+}
+
+func (t *target) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	t.mu.Lock()
+	t.requests = append(t.requests, r)
+	t.bodies = append(t.bodies, string(body))
+	t.mu.Unlock()
+	if r.Method == http.MethodPost {
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+	w.WriteHeader(http.StatusNotFound)
+}
+
+func (t *target) count() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.requests)
+}
+
+func newTestLogger() *logrus.Logger {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	return log
+}
+
+func TestShadow_Mirror(t *testing.T) {
+	tgt := &target{}
+	srv := httptest.NewServer(tgt)
+	defer srv.Close()
+
+	s, err := New(Config{Mode: ModeMirror, TargetURL: srv.URL, Registerer: prometheus.NewRegistry()}, nil, newTestLogger())
+	require.NoError(t, err)
+	require.NoError(t, s.Start(context.Background()))
+
+	s.Capture(Request{Method: http.MethodPost, URI: "/api/esf-documents?draft=1", Headers: map[string]string{"Authorization": "Bearer t"}, Body: []byte(`{"a":1}`), Status: http.StatusCreated})
+	s.Capture(Request{Method: http.MethodGet, URI: "/api/esf-documents/1", Status: http.StatusOK})
+	require.Eventually(t, func() bool { return tgt.count() == 2 }, time.Second, 5*time.Millisecond)
+	require.NoError(t, s.Stop(context.Background()))
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(s.requests.WithLabelValues("match")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(s.requests.WithLabelValues("mismatch")))
+
+	for i, r := range tgt.requests {
+		assert.Equal(t, "1", r.Header.Get(HeaderShadow))
+		if r.Method == http.MethodPost {
+			assert.Equal(t, "/api/esf-documents?draft=1", r.URL.RequestURI())
+			assert.Equal(t, "Bearer t", r.Header.Get("Authorization"))
+			assert.Equal(t, "201", r.Header.Get(HeaderPrimaryStatus))
+			assert.Equal(t, `{"a":1}`, tgt.bodies[i])
+		}
+	}
+}
+
+func TestShadow_RecordAndReplay(t *testing.T) {
+	store, err := storage.NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	s, err := New(Config{Mode: ModeRecord, BatchSize: 2, FlushInterval: time.Hour, Registerer: prometheus.NewRegistry()}, store, newTestLogger())
+	require.NoError(t, err)
+	assert.True(t, s.SkipHeader("authorization"))
+	assert.True(t, s.SkipHeader("Content-Length"))
+	assert.False(t, s.SkipHeader("X-Org-Id"))
+	require.NoError(t, s.Start(context.Background()))
+
+	s.Capture(Request{RequestID: "req-1", Method: http.MethodPost, URI: "/api/esf-documents", Body: []byte(`{"a":1}`), Status: http.StatusCreated})
+	s.Capture(Request{RequestID: "req-2", Method: http.MethodGet, URI: "/api/esf-documents/1", Status: http.StatusOK})
+	s.Capture(Request{RequestID: "req-3", Method: http.MethodDelete, URI: "/api/esf-documents/1", Status: http.StatusNotFound})
+	// Запросы, не записанные до остановки, дописываются пачками по BatchSize: 2 и 1
+	require.NoError(t, s.Stop(context.Background()))
+	assert.Equal(t, float64(3), testutil.ToFloat64(s.requests.WithLabelValues("recorded")))
+
+	objects, err := store.List(context.Background(), DefaultKeyPrefix)
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	assert.True(t, strings.HasSuffix(objects[0].Key, ".ndjson"))
+
+	tgt := &target{}
+	srv := httptest.NewServer(tgt)
+	defer srv.Close()
+
+	replayer, err := NewReplayer(ReplayConfig{TargetURL: srv.URL, Token: "replay-token"})
+	require.NoError(t, err)
+	for _, obj := range objects {
+		r, _, err := store.Get(context.Background(), obj.Key)
+		require.NoError(t, err)
+		require.NoError(t, replayer.Replay(context.Background(), r))
+		r.Close()
+	}
+
+	report := replayer.Report()
+	assert.Equal(t, 3, report.Requests)
+	assert.Equal(t, 2, report.Matched)
+	assert.Equal(t, 1, report.Mismatched)
+	require.Len(t, report.Mismatches, 1)
+	assert.Equal(t, Mismatch{RequestID: "req-2", Method: http.MethodGet, URI: "/api/esf-documents/1", Primary: http.StatusOK, Shadow: http.StatusNotFound}, report.Mismatches[0])
+	assert.Equal(t, "Bearer replay-token", tgt.requests[0].Header.Get("Authorization"))
+
+	var out strings.Builder
+	report.Write(&out)
+	assert.Contains(t, out.String(), "Replayed 3 requests")
+	assert.Contains(t, out.String(), "GET /api/esf-documents/1: primary 200, shadow 404 (request req-2)")
+}
+
+func TestShadow_MatchesAndDrops(t *testing.T) {
+	s, err := New(Config{Mode: ModeMirror, TargetURL: "http://127.0.0.1:1", BufferSize: 1, MaxBodySize: 10, Registerer: prometheus.NewRegistry()}, nil, newTestLogger())
+	require.NoError(t, err)
+
+	assert.True(t, s.Matches("/api/esf-documents/1", 10))
+	assert.False(t, s.Matches("/api/esf-documents/1", 11))
+	assert.False(t, s.Matches("/api/auth/login", 0))
+	assert.False(t, s.SkipHeader("Authorization"), "mirror keeps credentials for the target")
+
+	s.Capture(Request{})
+	s.Capture(Request{})
+	assert.Equal(t, float64(1), testutil.ToFloat64(s.requests.WithLabelValues("dropped")))
+}
+
+func TestNew_InvalidConfig(t *testing.T) {
+	_, err := New(Config{Mode: ModeMirror}, nil, newTestLogger())
+	assert.Error(t, err)
+	_, err = New(Config{Mode: ModeRecord}, nil, newTestLogger())
+	assert.Error(t, err)
+	_, err = New(Config{Mode: "tee"}, nil, newTestLogger())
+	assert.Error(t, err)
+}
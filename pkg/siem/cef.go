@@ -0,0 +1,128 @@
+package siem
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+// Поля заголовка CEF, определяющие источник событий
+const (
+	cefVendor  = "Tunduck"
+	cefProduct = "tunduck-app"
+	cefVersion = "1.0"
+)
+
+// authEntity тип сущности журнала для маршрутов /api/auth (вход, регистрация, выход)
+const authEntity = "auth"
+
+// Важность событий по шкале CEF (0-10)
+const (
+	severityInfo   = 3
+	severityChange = 5
+	severityDenied = 7
+)
+
+// EventName класс события для SIEM: "auth.login" для аутентификации, иначе "<тип сущности>.<действие>"
+func EventName(log *entity.AuditLog) string {
+	if log.EntityType == authEntity {
+		return authEntity + "." + lastSegment(log.Path)
+	}
+	if log.EntityType == "" {
+		return log.Action
+	}
+	return log.EntityType + "." + log.Action
+}
+
+// Severity важность события: отказ в доступе и неудачный вход важнее удаления, удаление важнее прочих изменений
+func Severity(log *entity.AuditLog) int {
+	switch {
+	case log.StatusCode == 401 || log.StatusCode == 403:
+		return severityDenied
+	case log.Action == "delete":
+		return severityChange
+	default:
+		return severityInfo
+	}
+}
+
+// FormatCEF представляет запись журнала событием CEF:
+//
+//	CEF:0|Tunduck|tunduck-app|1.0|users.update|users update|3|rt=... suser=... request=...
+func FormatCEF(log *entity.AuditLog) string {
+	var b strings.Builder
+	b.WriteString("CEF:0|")
+	b.WriteString(cefHeader(cefVendor))
+	b.WriteByte('|')
+	b.WriteString(cefHeader(cefProduct))
+	b.WriteByte('|')
+	b.WriteString(cefHeader(cefVersion))
+	b.WriteByte('|')
+	b.WriteString(cefHeader(EventName(log)))
+	b.WriteByte('|')
+	b.WriteString(cefHeader(strings.TrimSpace(log.EntityType + " " + log.Action)))
+	b.WriteByte('|')
+	b.WriteString(strconv.Itoa(Severity(log)))
+	b.WriteByte('|')
+
+	outcome := "success"
+	if log.StatusCode >= 400 {
+		outcome = "failure"
+	}
+	ext := [][2]string{
+		{"rt", strconv.FormatInt(log.CreatedAt.UnixMilli(), 10)},
+		{"act", log.Action},
+		{"outcome", outcome},
+		{"suid", log.ActorID},
+		{"suser", log.ActorName},
+		{"src", log.IP},
+		{"requestMethod", log.Method},
+		{"request", log.Path},
+		{"requestClientApplication", log.UserAgent},
+		{"cs1Label", "organizationId"},
+		{"cs1", log.OrganizationID},
+		{"cs2Label", "entityType"},
+		{"cs2", log.EntityType},
+		{"cs3Label", "entityId"},
+		{"cs3", log.EntityID},
+		{"cs4Label", "requestId"},
+		{"cs4", log.RequestID},
+		{"cn1Label", "statusCode"},
+		{"cn1", strconv.Itoa(log.StatusCode)},
+		{"externalId", log.ID.String()},
+	}
+	first := true
+	for i, kv := range ext {
+		// Подпись поля cs*Label выводится, только если есть само значение
+		if strings.HasSuffix(kv[0], "Label") && i+1 < len(ext) && ext[i+1][1] == "" {
+			continue
+		}
+		if kv[1] == "" {
+			continue
+		}
+		if !first {
+			b.WriteByte(' ')
+		}
+		first = false
+		b.WriteString(kv[0])
+		b.WriteByte('=')
+		b.WriteString(cefValue(kv[1]))
+	}
+	return b.String()
+}
+
+// cefHeader экранирует поле заголовка CEF
+func cefHeader(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\n", " ", "\r", " ").Replace(s)
+}
+
+// cefValue экранирует значение расширения CEF
+func cefValue(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `=`, `\=`, "\n", `\n`, "\r", `\r`).Replace(s)
+}
+
+func lastSegment(path string) string {
+	path = strings.TrimRight(path, "/")
+	return path[strings.LastIndex(path, "/")+1:]
+}
@@ -0,0 +1,304 @@
+// Package siem передает журнал аудита (включая вход, регистрацию и выход пользователей)
+// в SIEM по syslog (RFC 5424) событиями в формате CEF почти в реальном времени.
+//
+// Exporter реализует audit.Recorder: запись ставится в буфер в памяти, а отдельная горутина
+// (Run) отправляет ее на SIEM_SYSLOG_ADDRESS, переподключаясь при обрывах. Пока получатель
+// недоступен, буфер заполняется; при полном буфере Record ждет не дольше EnqueueTimeout
+// и отбрасывает событие (siem_events_total{result="dropped"}), не задерживая ответы API.
+package siem
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/logger"
+	"github.com/rusgainew/tunduck-app/pkg/metrics"
+)
+
+// Транспорты syslog
+const (
+	NetworkUDP = "udp"
+	NetworkTCP = "tcp"
+	NetworkTLS = "tls"
+)
+
+// Параметры экспорта по умолчанию
+const (
+	DefaultNetwork        = NetworkTCP
+	DefaultAppName        = "tunduck-app"
+	DefaultFacility       = 13 // log audit
+	DefaultBufferSize     = 10000
+	DefaultEnqueueTimeout = 50 * time.Millisecond
+	DefaultTimeout        = 5 * time.Second
+	DefaultMaxBackoff     = 30 * time.Second
+)
+
+// ErrBufferFull возвращается Record, если событие отброшено из-за переполнения буфера
+var ErrBufferFull = errors.New("siem export buffer is full")
+
+// Config параметры получателя syslog
+type Config struct {
+	// Address host:port коллектора syslog; пустой адрес выключает экспорт
+	Address string
+	// Network транспорт: udp, tcp (по умолчанию) или tls
+	Network  string
+	AppName  string
+	Facility int
+	// BufferSize число событий, ожидающих отправки
+	BufferSize int
+	// EnqueueTimeout сколько Record ждет места в полном буфере
+	EnqueueTimeout time.Duration
+	// Timeout подключения и записи одного события
+	Timeout time.Duration
+	// MaxBackoff предельная пауза между попытками переподключения
+	MaxBackoff time.Duration
+	Registerer prometheus.Registerer
+}
+
+// Enabled сообщает, настроен ли экспорт
+func (c Config) Enabled() bool {
+	return c.Address != ""
+}
+
+// Exporter отправляет записи журнала аудита в SIEM
+type Exporter struct {
+	cfg      Config
+	hostname string
+	queue    chan *entity.AuditLog
+	dial     func(ctx context.Context) (net.Conn, error)
+	conn     net.Conn
+	logger   *logger.Logger
+
+	events *prometheus.CounterVec
+}
+
+// NewExporter создает экспортер; отправка начинается после запуска Run
+func NewExporter(cfg Config, log *logrus.Logger) (*Exporter, error) {
+	if cfg.Network == "" {
+		cfg.Network = DefaultNetwork
+	}
+	switch cfg.Network {
+	case NetworkUDP, NetworkTCP, NetworkTLS:
+	default:
+		return nil, fmt.Errorf("unsupported syslog network %q", cfg.Network)
+	}
+	if _, _, err := net.SplitHostPort(cfg.Address); err != nil {
+		return nil, fmt.Errorf("invalid syslog address %q: %w", cfg.Address, err)
+	}
+	if cfg.AppName == "" {
+		cfg.AppName = DefaultAppName
+	}
+	if cfg.Facility <= 0 || cfg.Facility > 23 {
+		cfg.Facility = DefaultFacility
+	}
+	if cfg.BufferSize <= 0 {
+		cfg.BufferSize = DefaultBufferSize
+	}
+	if cfg.EnqueueTimeout <= 0 {
+		cfg.EnqueueTimeout = DefaultEnqueueTimeout
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = DefaultMaxBackoff
+	}
+	if cfg.Registerer == nil {
+		cfg.Registerer = prometheus.DefaultRegisterer
+	}
+
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "-"
+	}
+
+	e := &Exporter{
+		cfg:      cfg,
+		hostname: hostname,
+		queue:    make(chan *entity.AuditLog, cfg.BufferSize),
+		logger:   logger.New(log),
+		events: metrics.Register(cfg.Registerer, prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "siem_events_total",
+			Help: "Total number of audit events exported to SIEM by result (sent, dropped)",
+		}, []string{"result"})),
+	}
+	metrics.Register(cfg.Registerer, prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "siem_queue_length",
+		Help: "Number of audit events waiting to be exported to SIEM",
+	}, func() float64 { return float64(len(e.queue)) }))
+	e.dial = e.dialSyslog
+	return e, nil
+}
+
+// Record ставит запись в очередь отправки. При полном буфере ждет освобождения места
+// не дольше EnqueueTimeout, затем отбрасывает запись и возвращает ErrBufferFull.
+func (e *Exporter) Record(ctx context.Context, log *entity.AuditLog) error {
+	select {
+	case e.queue <- log:
+		return nil
+	default:
+	}
+
+	timer := time.NewTimer(e.cfg.EnqueueTimeout)
+	defer timer.Stop()
+	select {
+	case e.queue <- log:
+		return nil
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	e.events.WithLabelValues("dropped").Inc()
+	return ErrBufferFull
+}
+
+// Run отправляет события из очереди до отмены ctx. Событие, которое не удалось отправить,
+// повторяется после переподключения с растущей паузой; после отмены ctx оставшиеся
+// в буфере события отправляются в пределах Timeout.
+func (e *Exporter) Run(ctx context.Context) {
+	defer e.close()
+	for {
+		select {
+		case <-ctx.Done():
+			e.flush()
+			return
+		case log := <-e.queue:
+			if !e.deliver(ctx, e.Format(log)) {
+				e.flush()
+				return
+			}
+		}
+	}
+}
+
+// Format представляет запись журнала сообщением syslog RFC 5424 с событием CEF
+func (e *Exporter) Format(log *entity.AuditLog) []byte {
+	priority := e.cfg.Facility*8 + syslogSeverity(Severity(log))
+	ts := log.CreatedAt
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	msg := "<" + strconv.Itoa(priority) + ">1 " + ts.UTC().Format(time.RFC3339Nano) + " " +
+		e.hostname + " " + e.cfg.AppName + " - " + msgID(log) + " - " + FormatCEF(log)
+
+	// Потоковые транспорты разделяют сообщения подсчетом октетов (RFC 6587)
+	if e.cfg.Network != NetworkUDP {
+		msg = strconv.Itoa(len(msg)) + " " + msg
+	}
+	return []byte(msg)
+}
+
+// deliver отправляет сообщение, пока это не удастся; false — ctx отменен до отправки
+func (e *Exporter) deliver(ctx context.Context, msg []byte) bool {
+	backoff := time.Second
+	for attempt := 1; ; attempt++ {
+		err := e.write(ctx, msg)
+		if err == nil {
+			e.events.WithLabelValues("sent").Inc()
+			return true
+		}
+		e.close()
+		if attempt == 1 || attempt%10 == 0 {
+			e.logger.Warn(ctx, "Failed to export audit event to SIEM", logrus.Fields{
+				"address": e.cfg.Address,
+				"attempt": attempt,
+				"queued":  len(e.queue),
+				"error":   err.Error(),
+			})
+		}
+
+		select {
+		case <-ctx.Done():
+			e.events.WithLabelValues("dropped").Inc()
+			return false
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; backoff > e.cfg.MaxBackoff {
+			backoff = e.cfg.MaxBackoff
+		}
+	}
+}
+
+// flush отправляет оставшиеся события одной попыткой в пределах Timeout
+func (e *Exporter) flush() {
+	ctx, cancel := context.WithTimeout(context.Background(), e.cfg.Timeout)
+	defer cancel()
+	for {
+		select {
+		case log := <-e.queue:
+			if err := e.write(ctx, e.Format(log)); err != nil {
+				dropped := 1 + len(e.queue)
+				e.events.WithLabelValues("dropped").Add(float64(dropped))
+				e.logger.Warn(ctx, "Audit events not exported to SIEM on shutdown", logrus.Fields{
+					"dropped": dropped,
+					"error":   err.Error(),
+				})
+				return
+			}
+			e.events.WithLabelValues("sent").Inc()
+		default:
+			return
+		}
+	}
+}
+
+func (e *Exporter) write(ctx context.Context, msg []byte) error {
+	if e.conn == nil {
+		conn, err := e.dial(ctx)
+		if err != nil {
+			return err
+		}
+		e.conn = conn
+	}
+	if err := e.conn.SetWriteDeadline(time.Now().Add(e.cfg.Timeout)); err != nil {
+		return err
+	}
+	_, err := e.conn.Write(msg)
+	return err
+}
+
+func (e *Exporter) close() {
+	if e.conn != nil {
+		_ = e.conn.Close()
+		e.conn = nil
+	}
+}
+
+func (e *Exporter) dialSyslog(ctx context.Context) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: e.cfg.Timeout}
+	if e.cfg.Network == NetworkTLS {
+		host, _, _ := net.SplitHostPort(e.cfg.Address)
+		tlsDialer := &tls.Dialer{NetDialer: dialer, Config: &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}}
+		return tlsDialer.DialContext(ctx, "tcp", e.cfg.Address)
+	}
+	return dialer.DialContext(ctx, e.cfg.Network, e.cfg.Address)
+}
+
+// syslogSeverity переводит важность CEF в уровень syslog: warning, notice или informational
+func syslogSeverity(cef int) int {
+	switch {
+	case cef >= severityDenied:
+		return 4
+	case cef >= severityChange:
+		return 5
+	default:
+		return 6
+	}
+}
+
+// msgID поле MSGID: AUTH для событий аутентификации, AUDIT для остальных
+func msgID(log *entity.AuditLog) string {
+	if log.EntityType == authEntity {
+		return "AUTH"
+	}
+	return "AUDIT"
+}
@@ -0,0 +1,103 @@
+package siem
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+)
+
+func testLog(path, entityType, action string, status int) *entity.AuditLog {
+	return &entity.AuditLog{
+		ID:         uuid.New(),
+		ActorID:    "c5a1b2d3-0000-0000-0000-000000000001",
+		ActorName:  "admin@example.com",
+		Action:     action,
+		EntityType: entityType,
+		Method:     "POST",
+		Path:       path,
+		StatusCode: status,
+		IP:         "10.0.0.7",
+		CreatedAt:  time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestFormatCEF(t *testing.T) {
+	login := testLog("/api/auth/login", "auth", "create", 401)
+	login.ActorName = "a=b|c"
+	event := FormatCEF(login)
+	assert.True(t, strings.HasPrefix(event, "CEF:0|Tunduck|tunduck-app|1.0|auth.login|auth create|7|"), event)
+	assert.Contains(t, event, "outcome=failure")
+	assert.Contains(t, event, `suser=a\=b|c`, "extension values escape '='")
+	assert.NotContains(t, event, "cs3Label", "labels without values are omitted")
+
+	deleted := testLog("/api/esf-documents/1", "esf-documents", "delete", 200)
+	deleted.EntityID = "1"
+	event = FormatCEF(deleted)
+	assert.Contains(t, event, "|esf-documents.delete|esf-documents delete|5|")
+	assert.Contains(t, event, "cs3Label=entityId cs3=1")
+}
+
+func TestExporter_StreamsOverTCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+
+	exporter, err := NewExporter(Config{Address: ln.Addr().String(), Registerer: prometheus.NewRegistry()}, logrus.New())
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go exporter.Run(ctx)
+
+	require.NoError(t, exporter.Record(ctx, testLog("/api/auth/login", "auth", "create", 200)))
+	require.NoError(t, exporter.Record(ctx, testLog("/api/users/1", "users", "update", 200)))
+
+	conn, err := ln.Accept()
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	r := bufio.NewReader(conn)
+
+	first := readFrame(t, r)
+	assert.True(t, strings.HasPrefix(first, "<110>1 2026-10-16T09:00:00Z "), first)
+	assert.Contains(t, first, " tunduck-app - AUTH - CEF:0|")
+	second := readFrame(t, r)
+	assert.Contains(t, second, " AUDIT - CEF:0|Tunduck|tunduck-app|1.0|users.update|")
+}
+
+func TestExporter_DropsWhenBufferIsFull(t *testing.T) {
+	exporter, err := NewExporter(Config{Address: "127.0.0.1:1", BufferSize: 1, EnqueueTimeout: time.Millisecond, Registerer: prometheus.NewRegistry()}, logrus.New())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, exporter.Record(ctx, testLog("/api/users", "users", "create", 201)))
+	assert.ErrorIs(t, exporter.Record(ctx, testLog("/api/users", "users", "create", 201)), ErrBufferFull)
+
+	_, err = NewExporter(Config{Address: "127.0.0.1:514", Network: "http"}, logrus.New())
+	assert.Error(t, err)
+}
+
+// readFrame читает сообщение, разделенное подсчетом октетов (RFC 6587)
+func readFrame(t *testing.T, r *bufio.Reader) string {
+	t.Helper()
+	prefix, err := r.ReadString(' ')
+	require.NoError(t, err)
+	n, err := strconv.Atoi(strings.TrimSpace(prefix))
+	require.NoError(t, err)
+	buf := make([]byte, n)
+	_, err = io.ReadFull(r, buf)
+	require.NoError(t, err)
+	return string(buf)
+}
@@ -0,0 +1,186 @@
+package signature
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Заголовки подписанного запроса API-ключа
+const (
+	HeaderAPIKey    = "X-API-Key"
+	HeaderTimestamp = "X-Timestamp"
+	HeaderNonce     = "X-Nonce"
+	HeaderSignature = "X-Signature"
+)
+
+// DefaultTolerance допустимое расхождение времени клиента и сервера
+const DefaultTolerance = 5 * time.Minute
+
+var (
+	// ErrUnknownKey ключ API не найден
+	ErrUnknownKey = errors.New("unknown API key")
+	// ErrTimestampSkew время запроса выходит за допустимое окно
+	ErrTimestampSkew = errors.New("request timestamp is outside the allowed window")
+	// ErrNonceReused nonce уже использовался (повтор запроса)
+	ErrNonceReused = errors.New("nonce has already been used")
+	// ErrInvalidSignature подпись не совпадает
+	ErrInvalidSignature = errors.New("invalid request signature")
+)
+
+// KeyStore возвращает секрет по ID ключа API
+type KeyStore interface {
+	Secret(ctx context.Context, keyID string) (string, error)
+}
+
+// StaticKeyStore хранит ключи API в памяти (из конфигурации)
+type StaticKeyStore map[string]string
+
+// ParseKeys разбирает ключи из строки вида "id1:secret1,id2:secret2"
+func ParseKeys(raw string) (StaticKeyStore, error) {
+	keys := StaticKeyStore{}
+	for _, pair := range strings.Split(raw, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		id, secret, ok := strings.Cut(pair, ":")
+		if !ok || id == "" || secret == "" {
+			return nil, fmt.Errorf("invalid API key entry %q, expected id:secret", pair)
+		}
+		keys[id] = secret
+	}
+	return keys, nil
+}
+
+// Secret возвращает секрет ключа API
+func (s StaticKeyStore) Secret(_ context.Context, keyID string) (string, error) {
+	secret, ok := s[keyID]
+	if !ok {
+		return "", ErrUnknownKey
+	}
+	return secret, nil
+}
+
+// NonceStore запоминает использованные nonce
+type NonceStore interface {
+	// Use атомарно помечает nonce использованным. Возвращает false, если nonce уже встречался.
+	Use(ctx context.Context, keyID, nonce string, ttl time.Duration) (bool, error)
+}
+
+// RedisNonceStore хранит nonce в Redis
+type RedisNonceStore struct {
+	client *redis.Client
+}
+
+// NewRedisNonceStore создает хранилище nonce в Redis
+func NewRedisNonceStore(client *redis.Client) *RedisNonceStore {
+	return &RedisNonceStore{client: client}
+}
+
+// Use помечает nonce использованным через SET NX с TTL
+func (s *RedisNonceStore) Use(ctx context.Context, keyID, nonce string, ttl time.Duration) (bool, error) {
+	key := fmt.Sprintf("nonce:%s:%s", keyID, nonce)
+	return s.client.SetNX(ctx, key, 1, ttl).Result()
+}
+
+// Request данные запроса, участвующие в подписи
+type Request struct {
+	Method    string
+	Path      string
+	Query     string
+	Timestamp string
+	Nonce     string
+	Body      []byte
+}
+
+// CanonicalString формирует строку для подписи:
+// METHOD\nPATH\nQUERY\nTIMESTAMP\nNONCE\nhex(sha256(BODY))
+func CanonicalString(r Request) string {
+	bodyHash := sha256.Sum256(r.Body)
+	return strings.Join([]string{
+		strings.ToUpper(r.Method),
+		r.Path,
+		r.Query,
+		r.Timestamp,
+		r.Nonce,
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")
+}
+
+// Sign вычисляет подпись запроса HMAC-SHA256 в hex
+func Sign(secret string, r Request) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(CanonicalString(r)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verifier проверяет подпись, время и уникальность nonce запроса
+type Verifier struct {
+	keys      KeyStore
+	nonces    NonceStore
+	tolerance time.Duration
+	now       func() time.Time
+}
+
+// NewVerifier создает Verifier. tolerance <= 0 заменяется на DefaultTolerance.
+func NewVerifier(keys KeyStore, nonces NonceStore, tolerance time.Duration) *Verifier {
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	return &Verifier{
+		keys:      keys,
+		nonces:    nonces,
+		tolerance: tolerance,
+		now:       time.Now,
+	}
+}
+
+// Verify проверяет подписанный запрос ключа keyID.
+// Timestamp - Unix-время в секундах. Nonce запоминается только для запросов с верной подписью.
+func (v *Verifier) Verify(ctx context.Context, keyID, sig string, r Request) error {
+	secret, err := v.keys.Secret(ctx, keyID)
+	if err != nil {
+		return err
+	}
+
+	ts, err := parseTimestamp(r.Timestamp)
+	if err != nil {
+		return err
+	}
+	if skew := v.now().Sub(ts); skew > v.tolerance || skew < -v.tolerance {
+		return ErrTimestampSkew
+	}
+
+	expected := Sign(secret, r)
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(sig))) {
+		return ErrInvalidSignature
+	}
+
+	// Nonce хранится дольше окна времени, чтобы перехваченный запрос нельзя было повторить
+	fresh, err := v.nonces.Use(ctx, keyID, r.Nonce, 2*v.tolerance)
+	if err != nil {
+		return fmt.Errorf("failed to store nonce: %w", err)
+	}
+	if !fresh {
+		return ErrNonceReused
+	}
+	return nil
+}
+
+// parseTimestamp разбирает Unix-время в секундах
+func parseTimestamp(raw string) (time.Time, error) {
+	sec, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return time.Time{}, ErrTimestampSkew
+	}
+	return time.Unix(sec, 0), nil
+}
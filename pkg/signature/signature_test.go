@@ -0,0 +1,84 @@
+package signature
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryNonceStore map[string]bool
+
+func (m memoryNonceStore) Use(_ context.Context, keyID, nonce string, _ time.Duration) (bool, error) {
+	key := keyID + ":" + nonce
+	if m[key] {
+		return false, nil
+	}
+	m[key] = true
+	return true, nil
+}
+
+func newTestVerifier(now time.Time) *Verifier {
+	v := NewVerifier(StaticKeyStore{"client": "secret"}, memoryNonceStore{}, time.Minute)
+	v.now = func() time.Time { return now }
+	return v
+}
+
+func signedRequest(now time.Time, nonce string) Request {
+	return Request{
+		Method:    "POST",
+		Path:      "/api/esf-documents",
+		Query:     "page=1",
+		Timestamp: strconv.FormatInt(now.Unix(), 10),
+		Nonce:     nonce,
+		Body:      []byte(`{"currencyCode":"KGS"}`),
+	}
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	v := newTestVerifier(now)
+	req := signedRequest(now, "n-1")
+
+	require.NoError(t, v.Verify(context.Background(), "client", Sign("secret", req), req))
+}
+
+func TestVerify_Replay(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	v := newTestVerifier(now)
+	req := signedRequest(now, "n-1")
+	sig := Sign("secret", req)
+
+	require.NoError(t, v.Verify(context.Background(), "client", sig, req))
+	assert.ErrorIs(t, v.Verify(context.Background(), "client", sig, req), ErrNonceReused)
+}
+
+func TestVerify_Rejects(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	v := newTestVerifier(now)
+
+	req := signedRequest(now, "n-1")
+	tampered := req
+	tampered.Body = []byte(`{"currencyCode":"USD"}`)
+	assert.ErrorIs(t, v.Verify(context.Background(), "client", Sign("secret", req), tampered), ErrInvalidSignature)
+
+	stale := signedRequest(now.Add(-2*time.Minute), "n-2")
+	assert.ErrorIs(t, v.Verify(context.Background(), "client", Sign("secret", stale), stale), ErrTimestampSkew)
+
+	assert.ErrorIs(t, v.Verify(context.Background(), "unknown", Sign("secret", req), req), ErrUnknownKey)
+
+	// Запрос с неверной подписью не расходует nonce
+	require.NoError(t, v.Verify(context.Background(), "client", Sign("secret", req), req))
+}
+
+func TestParseKeys(t *testing.T) {
+	keys, err := ParseKeys("a:1, b:2")
+	require.NoError(t, err)
+	assert.Equal(t, StaticKeyStore{"a": "1", "b": "2"}, keys)
+
+	_, err = ParseKeys("broken")
+	assert.Error(t, err)
+}
@@ -0,0 +1,40 @@
+package signature
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURLSigner(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	signer, err := NewURLSigner("secret")
+	require.NoError(t, err)
+	signer.now = func() time.Time { return now }
+
+	signed := signer.Sign("/api/exports/42/download", now.Add(time.Hour))
+	u, err := url.Parse(signed)
+	require.NoError(t, err)
+	assert.Equal(t, "/api/exports/42/download", u.Path)
+
+	expires, sig := u.Query().Get(QueryExpires), u.Query().Get(QuerySignature)
+	assert.NoError(t, signer.Verify(u.Path, expires, sig))
+
+	// Подпись привязана к пути, сроку и секрету
+	assert.ErrorIs(t, signer.Verify("/api/exports/43/download", expires, sig), ErrInvalidSignature)
+	assert.ErrorIs(t, signer.Verify(u.Path, "1800000000", sig), ErrInvalidSignature)
+	other, err := NewURLSigner("other")
+	require.NoError(t, err)
+	assert.ErrorIs(t, other.Verify(u.Path, expires, sig), ErrInvalidSignature)
+
+	signer.now = func() time.Time { return now.Add(time.Hour) }
+	assert.ErrorIs(t, signer.Verify(u.Path, expires, sig), ErrURLExpired)
+}
+
+func TestNewURLSigner_EmptySecret(t *testing.T) {
+	_, err := NewURLSigner("")
+	assert.ErrorIs(t, err, ErrEmptyURLSecret)
+}
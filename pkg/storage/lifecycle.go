@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"context"
+	"time"
+)
+
+// LifecycleRule удаляет объекты с префиксом Prefix старше ExpireAfter
+type LifecycleRule struct {
+	Prefix      string
+	ExpireAfter time.Duration
+}
+
+// ApplyLifecycle удаляет устаревшие объекты по правилам и возвращает количество удаленных.
+// Выполняется периодической задачей одинаково для всех бэкендов, поэтому
+// правила не нужно дублировать в настройках бакета.
+func ApplyLifecycle(ctx context.Context, s Storage, rules []LifecycleRule, now time.Time) (int, error) {
+	deleted := 0
+	for _, rule := range rules {
+		if rule.ExpireAfter <= 0 {
+			continue
+		}
+
+		objects, err := s.List(ctx, rule.Prefix)
+		if err != nil {
+			return deleted, err
+		}
+
+		cutoff := now.Add(-rule.ExpireAfter)
+		for _, obj := range objects {
+			if !obj.LastModified.Before(cutoff) {
+				continue
+			}
+			if err := s.Delete(ctx, obj.Key); err != nil {
+				return deleted, err
+			}
+			deleted++
+		}
+	}
+	return deleted, nil
+}
@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// uploadPartSize размер части multipart-загрузки объекта неизвестного размера. Часть
+// буферизуется в памяти, поэтому размер ограничен; предел объекта — 10000 частей.
+const uploadPartSize = 16 << 20
+
+// S3Storage хранит объекты в бакете S3-совместимого хранилища через minio-go
+type S3Storage struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3 создает клиент бакета. Timeout ограничивает ожидание ответа сервера,
+// но не длительность передачи тела, чтобы не обрывать большие файлы.
+func NewS3(cfg Config) (*S3Storage, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("storage: s3 bucket is required")
+	}
+	if cfg.Region == "" {
+		cfg.Region = DefaultRegion
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+
+	endpoint, err := url.Parse(strings.TrimRight(cfg.Endpoint, "/"))
+	if err != nil || endpoint.Host == "" || (endpoint.Path != "" && endpoint.Path != "/") {
+		return nil, fmt.Errorf("storage: invalid s3 endpoint %q", cfg.Endpoint)
+	}
+	secure := endpoint.Scheme == "https"
+
+	transport, err := minio.DefaultTransport(secure)
+	if err != nil {
+		return nil, err
+	}
+	transport.ResponseHeaderTimeout = cfg.Timeout
+
+	lookup := minio.BucketLookupDNS
+	if cfg.PathStyle {
+		lookup = minio.BucketLookupPath
+	}
+
+	client, err := minio.New(endpoint.Host, &minio.Options{
+		Creds:        credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure:       secure,
+		Region:       cfg.Region,
+		BucketLookup: lookup,
+		Transport:    transport,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("storage: creating s3 client: %w", err)
+	}
+	return &S3Storage{client: client, bucket: cfg.Bucket}, nil
+}
+
+// Put загружает объект. Объект неизвестного размера загружается по частям uploadPartSize.
+// Тело не хешируется (UNSIGNED-PAYLOAD), чтобы не читать поток дважды.
+func (s *S3Storage) Put(ctx context.Context, key string, r io.Reader, size int64, opts PutOptions) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.PutObject(ctx, s.bucket, key, r, size, minio.PutObjectOptions{
+		ContentType:          opts.ContentType,
+		PartSize:             uploadPartSize,
+		DisableContentSha256: true,
+	})
+	return s.wrapError("PUT", key, err)
+}
+
+// Get открывает поток чтения объекта
+func (s *S3Storage) Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	obj, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, nil, s.wrapError("GET", key, err)
+	}
+	// Stat выполняет первый запрос GET и возвращает его заголовки
+	stat, err := obj.Stat()
+	if err != nil {
+		obj.Close()
+		return nil, nil, s.wrapError("GET", key, err)
+	}
+	return obj, objectInfo(stat), nil
+}
+
+// Stat возвращает метаданные объекта запросом HEAD
+func (s *S3Storage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	key, err := cleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	stat, err := s.client.StatObject(ctx, s.bucket, key, minio.StatObjectOptions{})
+	if err != nil {
+		return nil, s.wrapError("HEAD", key, err)
+	}
+	return objectInfo(stat), nil
+}
+
+// Delete удаляет объект
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	key, err := cleanKey(key)
+	if err != nil {
+		return err
+	}
+
+	err = s.wrapError("DELETE", key, s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{}))
+	if errors.Is(err, ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// List постранично обходит бакет через ListObjectsV2
+func (s *S3Storage) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	objects := make([]ObjectInfo, 0)
+	for item := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if item.Err != nil {
+			return nil, s.wrapError("LIST", prefix, item.Err)
+		}
+		objects = append(objects, ObjectInfo{Key: item.Key, Size: item.Size, LastModified: item.LastModified})
+	}
+	return objects, nil
+}
+
+// wrapError преобразует ответ 404 в ErrNotFound и добавляет к остальным ошибкам операцию и ключ
+func (s *S3Storage) wrapError(op, key string, err error) error {
+	if err == nil {
+		return nil
+	}
+	resp := minio.ToErrorResponse(err)
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.Code != "" {
+		return fmt.Errorf("storage: %s %s/%s returned %d %s: %s", op, s.bucket, key, resp.StatusCode, resp.Code, resp.Message)
+	}
+	return fmt.Errorf("storage: %s %s/%s: %w", op, s.bucket, key, err)
+}
+
+func objectInfo(stat minio.ObjectInfo) *ObjectInfo {
+	return &ObjectInfo{Key: stat.Key, Size: stat.Size, ContentType: stat.ContentType, LastModified: stat.LastModified}
+}
@@ -0,0 +1,114 @@
+// Package storage хранит файлы (вложения, выгрузки, резервные копии) в объектном хранилище.
+//
+// Бэкенды:
+//   - local — каталог на диске, для разработки и однонодовых установок;
+//   - s3 — любое S3-совместимое хранилище (AWS S3, MinIO, Yandex Object Storage) через minio-go.
+//
+// Ключи объектов — пути через "/", например "exports/<org_id>/2026-10.csv".
+// Загрузка и выдача идут потоком, без чтения файла целиком в память.
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+// Бэкенды хранилища
+const (
+	BackendLocal = "local"
+	BackendS3    = "s3"
+)
+
+// Параметры по умолчанию
+const (
+	DefaultLocalPath = "./data/storage"
+	DefaultRegion    = "us-east-1"
+	DefaultTimeout   = 5 * time.Minute
+)
+
+var (
+	// ErrNotFound объект не существует
+	ErrNotFound = errors.New("storage: object not found")
+	// ErrInvalidKey ключ пустой, абсолютный или выходит за пределы хранилища
+	ErrInvalidKey = errors.New("storage: invalid object key")
+)
+
+// ObjectInfo метаданные объекта
+type ObjectInfo struct {
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	ContentType  string    `json:"contentType,omitempty"`
+	LastModified time.Time `json:"lastModified"`
+}
+
+// PutOptions параметры загрузки
+type PutOptions struct {
+	ContentType string
+}
+
+// Storage объектное хранилище
+type Storage interface {
+	// Put загружает объект из r. size — длина в байтах или -1, если неизвестна.
+	Put(ctx context.Context, key string, r io.Reader, size int64, opts PutOptions) error
+	// Get открывает объект на чтение; вызывающий закрывает поток
+	Get(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error)
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	// Delete удаляет объект; отсутствие объекта не считается ошибкой
+	Delete(ctx context.Context, key string) error
+	// List возвращает объекты с ключами, начинающимися с prefix
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+}
+
+// Config параметры хранилища
+type Config struct {
+	Backend string
+
+	// LocalPath корневой каталог бэкенда local
+	LocalPath string
+
+	// Параметры бэкенда s3. Endpoint пустой — AWS S3 в Region.
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PathStyle адресует бакет в пути (MinIO) вместо поддомена
+	PathStyle bool
+	Timeout   time.Duration
+
+	// Lifecycle правила удаления устаревших объектов
+	Lifecycle []LifecycleRule
+}
+
+// New создает хранилище по конфигурации
+func New(cfg Config) (Storage, error) {
+	switch cfg.Backend {
+	case "", BackendLocal:
+		dir := cfg.LocalPath
+		if dir == "" {
+			dir = DefaultLocalPath
+		}
+		return NewLocal(dir)
+	case BackendS3:
+		return NewS3(cfg)
+	default:
+		return nil, fmt.Errorf("storage: unknown backend %q", cfg.Backend)
+	}
+}
+
+// cleanKey нормализует ключ и отклоняет выход за пределы хранилища
+func cleanKey(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return "", ErrInvalidKey
+	}
+	cleaned := path.Clean(key)
+	if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", ErrInvalidKey
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,226 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCleanKey(t *testing.T) {
+	for _, key := range []string{"", "/etc/passwd", "../secret", "a/../../b", "..", "a\\b"} {
+		_, err := cleanKey(key)
+		assert.ErrorIs(t, err, ErrInvalidKey, key)
+	}
+
+	key, err := cleanKey("exports/./org/file.csv")
+	require.NoError(t, err)
+	assert.Equal(t, "exports/org/file.csv", key)
+}
+
+// testStorage проверяет общий контракт Storage
+func testStorage(t *testing.T, s Storage) {
+	ctx := context.Background()
+
+	require.NoError(t, s.Put(ctx, "exports/a.csv", strings.NewReader("a,b\n"), 4, PutOptions{ContentType: "text/csv"}))
+	require.NoError(t, s.Put(ctx, "exports/b.csv", strings.NewReader("unknown size"), -1, PutOptions{}))
+	require.NoError(t, s.Put(ctx, "backups/db.dump", strings.NewReader(""), 0, PutOptions{}))
+
+	r, info, err := s.Get(ctx, "exports/a.csv")
+	require.NoError(t, err)
+	data, err := io.ReadAll(r)
+	r.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "a,b\n", string(data))
+	assert.Equal(t, int64(4), info.Size)
+	assert.Contains(t, info.ContentType, "text/csv")
+
+	info, err = s.Stat(ctx, "exports/b.csv")
+	require.NoError(t, err)
+	assert.Equal(t, int64(len("unknown size")), info.Size)
+
+	objects, err := s.List(ctx, "exports/")
+	require.NoError(t, err)
+	keys := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		keys = append(keys, obj.Key)
+	}
+	sort.Strings(keys)
+	assert.Equal(t, []string{"exports/a.csv", "exports/b.csv"}, keys)
+
+	require.NoError(t, s.Delete(ctx, "exports/a.csv"))
+	require.NoError(t, s.Delete(ctx, "exports/a.csv"), "deleting a missing object is not an error")
+
+	_, _, err = s.Get(ctx, "exports/a.csv")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = s.Stat(ctx, "exports/a.csv")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.ErrorIs(t, s.Put(ctx, "../escape", strings.NewReader("x"), 1, PutOptions{}), ErrInvalidKey)
+}
+
+func TestLocalStorage(t *testing.T) {
+	s, err := NewLocal(t.TempDir())
+	require.NoError(t, err)
+	testStorage(t, s)
+}
+
+func TestS3Storage(t *testing.T) {
+	server := newFakeS3(t, "attachments")
+	s, err := NewS3(Config{
+		Backend:   BackendS3,
+		Endpoint:  server.URL,
+		Bucket:    "attachments",
+		AccessKey: "minio",
+		SecretKey: "minio-secret",
+		PathStyle: true,
+	})
+	require.NoError(t, err)
+	testStorage(t, s)
+}
+
+func TestApplyLifecycle(t *testing.T) {
+	ctx := context.Background()
+	s, err := NewLocal(t.TempDir())
+	require.NoError(t, err)
+
+	require.NoError(t, s.Put(ctx, "exports/old.csv", strings.NewReader("x"), 1, PutOptions{}))
+	require.NoError(t, s.Put(ctx, "backups/old.dump", strings.NewReader("x"), 1, PutOptions{}))
+
+	rules := []LifecycleRule{{Prefix: "exports/", ExpireAfter: time.Hour}}
+
+	deleted, err := ApplyLifecycle(ctx, s, rules, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 0, deleted, "fresh objects are kept")
+
+	deleted, err = ApplyLifecycle(ctx, s, rules, time.Now().Add(2*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	_, err = s.Stat(ctx, "backups/old.dump")
+	assert.NoError(t, err, "objects outside rule prefixes are kept")
+}
+
+func TestNew_UnknownBackend(t *testing.T) {
+	_, err := New(Config{Backend: "ftp"})
+	assert.Error(t, err)
+
+	_, err = New(Config{Backend: BackendS3})
+	assert.Error(t, err, "bucket is required")
+}
+
+// fakeObject объект фейкового S3
+type fakeObject struct {
+	data        []byte
+	contentType string
+	modified    time.Time
+}
+
+// newFakeS3 поднимает минимальный S3 с path-style адресацией одного бакета и multipart-загрузкой
+func newFakeS3(t *testing.T, bucket string) *httptest.Server {
+	var mu sync.Mutex
+	objects := make(map[string]fakeObject)
+	uploads := make(map[string]map[int][]byte)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=minio/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+
+		if strings.TrimSuffix(r.URL.Path, "/") == "/"+bucket && r.URL.Query().Get("list-type") == "2" {
+			type content struct {
+				Key          string
+				LastModified time.Time
+				Size         int64
+			}
+			var result struct {
+				XMLName     xml.Name `xml:"ListBucketResult"`
+				Contents    []content
+				IsTruncated bool
+			}
+			for key, obj := range objects {
+				if strings.HasPrefix(key, r.URL.Query().Get("prefix")) {
+					result.Contents = append(result.Contents, content{Key: key, LastModified: obj.modified, Size: int64(len(obj.data))})
+				}
+			}
+			_ = xml.NewEncoder(w).Encode(result)
+			return
+		}
+
+		key := strings.TrimPrefix(r.URL.Path, "/"+bucket+"/")
+		query := r.URL.Query()
+		switch {
+		case r.Method == http.MethodPost && query.Has("uploads"):
+			uploadID := strconv.Itoa(len(uploads) + 1)
+			uploads[uploadID] = map[int][]byte{}
+			_ = xml.NewEncoder(w).Encode(struct {
+				XMLName  xml.Name `xml:"InitiateMultipartUploadResult"`
+				Bucket   string
+				Key      string
+				UploadId string
+			}{Bucket: bucket, Key: key, UploadId: uploadID})
+			return
+		case r.Method == http.MethodPut && query.Has("uploadId"):
+			part, _ := strconv.Atoi(query.Get("partNumber"))
+			data, _ := io.ReadAll(r.Body)
+			uploads[query.Get("uploadId")][part] = data
+			w.Header().Set("ETag", `"part-`+query.Get("partNumber")+`"`)
+			return
+		case r.Method == http.MethodPost && query.Has("uploadId"):
+			parts := uploads[query.Get("uploadId")]
+			var data []byte
+			for part := 1; part <= len(parts); part++ {
+				data = append(data, parts[part]...)
+			}
+			objects[key] = fakeObject{data: data, contentType: "binary/octet-stream", modified: time.Now()}
+			_ = xml.NewEncoder(w).Encode(struct {
+				XMLName xml.Name `xml:"CompleteMultipartUploadResult"`
+				Bucket  string
+				Key     string
+				ETag    string
+			}{Bucket: bucket, Key: key, ETag: `"object"`})
+			return
+		}
+
+		switch r.Method {
+		case http.MethodPut:
+			if r.ContentLength < 0 {
+				w.WriteHeader(http.StatusLengthRequired)
+				return
+			}
+			data, _ := io.ReadAll(r.Body)
+			objects[key] = fakeObject{data: data, contentType: r.Header.Get("Content-Type"), modified: time.Now()}
+		case http.MethodGet, http.MethodHead:
+			obj, ok := objects[key]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			if obj.contentType != "" {
+				w.Header().Set("Content-Type", obj.contentType)
+			}
+			w.Header().Set("Last-Modified", obj.modified.UTC().Format(http.TimeFormat))
+			http.ServeContent(w, r, "", obj.modified, bytes.NewReader(obj.data))
+		case http.MethodDelete:
+			delete(objects, key)
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
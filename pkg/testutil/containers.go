@@ -0,0 +1,103 @@
+package testutil
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
+)
+
+// Образы контейнеров; совпадают с docker-compose.yml
+const (
+	PostgresImage = "postgres:15-alpine"
+	RedisImage    = "redis:7-alpine"
+)
+
+// Учетные данные PostgreSQL в контейнере
+const (
+	containerDBUser     = "postgres"
+	containerDBPassword = "postgres"
+	containerDBName     = "tunduck"
+)
+
+// containerStartTimeout время на загрузку образов и запуск контейнеров
+const containerStartTimeout = 3 * time.Minute
+
+// Infrastructure PostgreSQL и Redis, запущенные в контейнерах для одного теста
+type Infrastructure struct {
+	DBHost     string
+	DBPort     string
+	DBUser     string
+	DBPassword string
+	DBName     string
+	RedisHost  string
+	RedisPort  string
+}
+
+// Env возвращает переменные окружения подключения приложения к контейнерам (DB_*, REDIS_*)
+func (i Infrastructure) Env() map[string]string {
+	return map[string]string{
+		"DB_HOST":     i.DBHost,
+		"DB_PORT":     i.DBPort,
+		"DB_USER":     i.DBUser,
+		"DB_PASSWORD": i.DBPassword,
+		"DB_NAME":     i.DBName,
+		"DB_SSLMODE":  "disable",
+		"REDIS_HOST":  i.RedisHost,
+		"REDIS_PORT":  i.RedisPort,
+	}
+}
+
+// StartInfrastructure запускает PostgreSQL и Redis через testcontainers-go; контейнеры удаляются
+// после теста. Пользователь PostgreSQL — суперпользователь, поэтому приложение может создавать
+// БД организаций. Тест пропускается в режиме -short и когда Docker недоступен.
+func StartInfrastructure(t *testing.T) Infrastructure {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("Skipping container test in short mode")
+	}
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	ctx, cancel := context.WithTimeout(context.Background(), containerStartTimeout)
+	defer cancel()
+
+	pg, err := tcpostgres.Run(ctx, PostgresImage,
+		tcpostgres.WithDatabase(containerDBName),
+		tcpostgres.WithUsername(containerDBUser),
+		tcpostgres.WithPassword(containerDBPassword),
+		tcpostgres.BasicWaitStrategies(),
+	)
+	testcontainers.CleanupContainer(t, pg)
+	if err != nil {
+		t.Fatalf("failed to start PostgreSQL container: %v", err)
+	}
+
+	rd, err := tcredis.Run(ctx, RedisImage)
+	testcontainers.CleanupContainer(t, rd)
+	if err != nil {
+		t.Fatalf("failed to start Redis container: %v", err)
+	}
+
+	infra := Infrastructure{DBUser: containerDBUser, DBPassword: containerDBPassword, DBName: containerDBName}
+	if infra.DBHost, err = pg.Host(ctx); err != nil {
+		t.Fatalf("failed to get PostgreSQL host: %v", err)
+	}
+	dbPort, err := pg.MappedPort(ctx, "5432/tcp")
+	if err != nil {
+		t.Fatalf("failed to get PostgreSQL port: %v", err)
+	}
+	infra.DBPort = dbPort.Port()
+
+	if infra.RedisHost, err = rd.Host(ctx); err != nil {
+		t.Fatalf("failed to get Redis host: %v", err)
+	}
+	redisPort, err := rd.MappedPort(ctx, "6379/tcp")
+	if err != nil {
+		t.Fatalf("failed to get Redis port: %v", err)
+	}
+	infra.RedisPort = redisPort.Port()
+	return infra
+}
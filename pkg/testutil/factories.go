@@ -0,0 +1,140 @@
+// Package testutil содержит вспомогательные средства для тестов: фабрики сущностей,
+// подключение к тестовой PostgreSQL с изолированной схемой, HTTP-обвязку вокруг Fiber и разбор PDF.
+//
+// Фабрики возвращают валидные значения с уникальными полями; нужные поля переопределяются опциями:
+//
+//	admin := testutil.NewUser(func(u *entity.User) { u.Role = rbac.RoleAdmin })
+package testutil
+
+import (
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/rusgainew/tunduck-app/internal/models"
+	"github.com/rusgainew/tunduck-app/pkg/auth"
+	"github.com/rusgainew/tunduck-app/pkg/dates"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+)
+
+// DefaultPassword пароль пользователей, созданных NewUser
+const DefaultPassword = "Password123!"
+
+var (
+	sequence atomic.Int64
+
+	// passwordHash считается один раз: bcrypt заметно замедляет тесты
+	passwordHash = mustHash(DefaultPassword)
+)
+
+// next возвращает уникальный в пределах процесса номер
+func next() int64 {
+	return sequence.Add(1)
+}
+
+// NewUser создает активного пользователя с ролью user и паролем DefaultPassword
+func NewUser(opts ...func(*entity.User)) *entity.User {
+	n := next()
+	user := &entity.User{
+		ID:       uuid.New(),
+		Username: fmt.Sprintf("user%d", n),
+		Email:    fmt.Sprintf("user%d@example.kg", n),
+		FullName: fmt.Sprintf("Test User %d", n),
+		Phone:    fmt.Sprintf("+99655%07d", n%10000000),
+		Password: passwordHash,
+		Role:     rbac.RoleUser,
+		IsActive: true,
+	}
+	for _, opt := range opts {
+		opt(user)
+	}
+	return user
+}
+
+// NewOrganization создает организацию с уникальными именем, токеном и именем БД
+func NewOrganization(opts ...func(*entity.EstOrganization)) *entity.EstOrganization {
+	n := next()
+	org := &entity.EstOrganization{
+		ID:          uuid.New(),
+		Name:        fmt.Sprintf("Test Organization %d", n),
+		Description: "Организация для тестов",
+		Token:       fmt.Sprintf("test-token-%08d", n),
+		DBName:      fmt.Sprintf("test_org_%d", n),
+		Version:     1,
+	}
+	for _, opt := range opts {
+		opt(org)
+	}
+	return org
+}
+
+// NewDocumentRequest создает запрос на создание документа с одной позицией; суммы согласованы
+func NewDocumentRequest(opts ...func(*models.EsfCreateDocumentRequest)) *models.EsfCreateDocumentRequest {
+	n := next()
+	delivery := dates.Of(time.Now().UTC())
+	doc := &models.EsfCreateDocumentRequest{
+		IsPriceWithoutTaxes:            true,
+		OwnedCrmReceiptCode:            fmt.Sprintf("TEST-%05d", n),
+		OperationTypeCode:              "10",
+		DeliveryDate:                   delivery,
+		DeliveryTypeCode:               "101",
+		IsResident:                     true,
+		ContractorTin:                  "01234567890123",
+		CurrencyCode:                   "KGS",
+		CurrencyRate:                   1,
+		TotalCurrencyValue:             1120,
+		TotalCurrencyValueWithoutTaxes: 1000,
+		SupplyContractNumber:           fmt.Sprintf("Д-%d", n),
+		ContractStartDate:              dates.Of(delivery.AddDate(0, -1, 0)),
+		PaymentCode:                    "1",
+		TaxRateVATCode:                 "12",
+		AmountToBePaid:                 1120,
+		CatalogEntries: []models.EsfEntriesModel{{
+			UnitClassificationCode: "796",
+			SalesTaxCode:           "0",
+			Quantity:               10,
+			Price:                  100,
+			VatAmount:              120,
+			AmountWithoutTaxes:     1000,
+			TotalAmount:            1120,
+		}},
+	}
+	for _, opt := range opts {
+		opt(doc)
+	}
+	return doc
+}
+
+// NewAuditLog создает запись журнала аудита об обновлении документа
+func NewAuditLog(opts ...func(*entity.AuditLog)) *entity.AuditLog {
+	n := next()
+	log := &entity.AuditLog{
+		ID:         uuid.New(),
+		ActorID:    uuid.NewString(),
+		ActorName:  fmt.Sprintf("user%d", n),
+		Action:     "update",
+		EntityType: "esf-documents",
+		EntityID:   uuid.NewString(),
+		Method:     "PUT",
+		Path:       "/api/esf-documents",
+		StatusCode: 200,
+		IP:         "127.0.0.1",
+		RequestID:  fmt.Sprintf("req-%d", n),
+		CreatedAt:  time.Now().UTC(),
+	}
+	for _, opt := range opts {
+		opt(log)
+	}
+	return log
+}
+
+func mustHash(password string) string {
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		panic(err)
+	}
+	return hash
+}
@@ -0,0 +1,159 @@
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/auth"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+)
+
+// JWTSecret секрет, который Harness выставляет в JWT_SECRET на время теста
+const JWTSecret = "testutil-jwt-secret"
+
+// Harness Fiber-приложение с теми же обработчиком ошибок и базовыми middleware, что и в cmd/api.
+// Контроллеры регистрируются на App, запросы выполняются через Do без сетевого сокета.
+type Harness struct {
+	t      testing.TB
+	App    *fiber.App
+	Logger *logrus.Logger
+	// Logs перехватывает записи Logger для проверок в тестах
+	Logs *test.Hook
+}
+
+// NewHarness создает Harness; JWT_SECRET восстанавливается после теста
+func NewHarness(t testing.TB) *Harness {
+	t.Helper()
+	t.Setenv("JWT_SECRET", JWTSecret)
+
+	log, hook := test.NewNullLogger()
+	app := fiber.New(fiber.Config{
+		ErrorHandler: middleware.GlobalErrorHandler(log),
+	})
+	app.Use(middleware.RequestIDMiddleware())
+	app.Use(middleware.I18nMiddleware())
+	app.Use(middleware.ErrorHandlingMiddleware(log))
+
+	return &Harness{t: t, App: app, Logger: log, Logs: hook}
+}
+
+// Token выпускает JWT пользователя, подписанный JWTSecret
+func (h *Harness) Token(userID, email string) string {
+	h.t.Helper()
+	token, err := auth.GenerateToken(userID, email, JWTSecret, time.Hour)
+	if err != nil {
+		h.t.Fatalf("failed to generate token: %v", err)
+	}
+	return token
+}
+
+// RequestOption изменяет запрос перед отправкой
+type RequestOption func(*http.Request)
+
+// WithToken добавляет заголовок Authorization: Bearer
+func WithToken(token string) RequestOption {
+	return func(req *http.Request) {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+}
+
+// WithHeader добавляет произвольный заголовок
+func WithHeader(key, value string) RequestOption {
+	return func(req *http.Request) {
+		req.Header.Set(key, value)
+	}
+}
+
+// Do выполняет запрос; body сериализуется в JSON, если это не []byte и не nil
+func (h *Harness) Do(method, path string, body interface{}, opts ...RequestOption) *Response {
+	h.t.Helper()
+
+	var reader io.Reader
+	switch b := body.(type) {
+	case nil:
+	case []byte:
+		reader = bytes.NewReader(b)
+	default:
+		raw, err := json.Marshal(b)
+		if err != nil {
+			h.t.Fatalf("failed to marshal request body: %v", err)
+		}
+		reader = bytes.NewReader(raw)
+	}
+
+	req := httptest.NewRequest(method, path, reader)
+	if reader != nil {
+		req.Header.Set("Content-Type", fiber.MIMEApplicationJSON)
+	}
+	for _, opt := range opts {
+		opt(req)
+	}
+
+	resp, err := h.App.Test(req, -1)
+	if err != nil {
+		h.t.Fatalf("%s %s failed: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		h.t.Fatalf("failed to read response body: %v", err)
+	}
+	return &Response{t: h.t, StatusCode: resp.StatusCode, Header: resp.Header, Body: raw}
+}
+
+// Response ответ, полученный через Harness.Do
+type Response struct {
+	t          testing.TB
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// Envelope тело ответа в формате response.Envelope с отложенным разбором data и meta
+type Envelope struct {
+	Success   bool                    `json:"success"`
+	Data      json.RawMessage         `json:"data,omitempty"`
+	Meta      json.RawMessage         `json:"meta,omitempty"`
+	Message   string                  `json:"message,omitempty"`
+	Error     *apperror.ErrorResponse `json:"error,omitempty"`
+	RequestID string                  `json:"request_id,omitempty"`
+}
+
+// Envelope разбирает тело ответа
+func (r *Response) Envelope() Envelope {
+	r.t.Helper()
+	var env Envelope
+	if err := json.Unmarshal(r.Body, &env); err != nil {
+		r.t.Fatalf("response is not an envelope: %v (body: %s)", err, r.Body)
+	}
+	return env
+}
+
+// DecodeData разбирает поле data ответа в v
+func (r *Response) DecodeData(v interface{}) {
+	r.t.Helper()
+	env := r.Envelope()
+	if err := json.Unmarshal(env.Data, v); err != nil {
+		r.t.Fatalf("failed to decode data: %v (body: %s)", err, r.Body)
+	}
+}
+
+// ErrorCode возвращает код ошибки из ответа или пустую строку
+func (r *Response) ErrorCode() string {
+	r.t.Helper()
+	if env := r.Envelope(); env.Error != nil {
+		return env.Error.Code
+	}
+	return ""
+}
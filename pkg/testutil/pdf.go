@@ -0,0 +1,47 @@
+package testutil
+
+import (
+	"bytes"
+	"compress/zlib"
+	"io"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+// PDFContent распаковывает потоки FlateDecode файла PDF (содержимое страниц, шрифты) и склеивает их
+func PDFContent(t testing.TB, pdf []byte) string {
+	t.Helper()
+	var out strings.Builder
+	for {
+		start := bytes.Index(pdf, []byte("stream\n"))
+		if start == -1 {
+			return out.String()
+		}
+		pdf = pdf[start+len("stream\n"):]
+		end := bytes.Index(pdf, []byte("endstream"))
+		if end == -1 {
+			t.Fatal("PDF stream without endstream")
+		}
+		if zr, err := zlib.NewReader(bytes.NewReader(pdf[:end])); err == nil {
+			body, _ := io.ReadAll(zr)
+			out.Write(body)
+		}
+		pdf = pdf[end+len("endstream"):]
+	}
+}
+
+// PDFText строка так, как ее выводит в содержимое страницы шрифт Unicode (Identity-H):
+// UTF-16BE с экранированием скобок и обратной косой черты
+func PDFText(s string) string {
+	var b strings.Builder
+	for _, u := range utf16.Encode([]rune(s)) {
+		for _, c := range []byte{byte(u >> 8), byte(u)} {
+			if c == '(' || c == ')' || c == '\\' {
+				b.WriteByte('\\')
+			}
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package testutil
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"github.com/rusgainew/tunduck-app/pkg/migrations"
+)
+
+// PostgresDSNEnv переменная окружения с DSN тестовой PostgreSQL
+const PostgresDSNEnv = "TEST_DATABASE_DSN"
+
+// DefaultPostgresDSN используется, если PostgresDSNEnv не задана (docker run -p 5432:5432 -e POSTGRES_PASSWORD=postgres postgres)
+const DefaultPostgresDSN = "user=postgres password=postgres dbname=tunduct_test host=localhost port=5432 sslmode=disable"
+
+// Postgres подключается к тестовой PostgreSQL и создает для теста отдельную схему с примененными
+// основными миграциями; после теста схема удаляется. Тест пропускается в режиме -short
+// и когда БД недоступна, поэтому go test ./... проходит без инфраструктуры.
+func Postgres(t testing.TB) *gorm.DB {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("Skipping PostgreSQL test in short mode")
+	}
+
+	dsn := os.Getenv(PostgresDSNEnv)
+	if dsn == "" {
+		dsn = DefaultPostgresDSN
+	}
+
+	admin, err := open(dsn)
+	if err != nil {
+		t.Skipf("PostgreSQL not available: %v", err)
+	}
+
+	schema := "test_" + strings.ReplaceAll(uuid.NewString(), "-", "")[:12]
+	if err := admin.Exec(fmt.Sprintf(`CREATE SCHEMA "%s"`, schema)).Error; err != nil {
+		closeDB(admin)
+		t.Fatalf("failed to create schema %s: %v", schema, err)
+	}
+	// Расширения (uuid-ossp) остаются в public, поэтому она идет в search_path второй
+	db, err := open(dsn + fmt.Sprintf(" search_path=%s,public", schema))
+	if err != nil {
+		closeDB(admin)
+		t.Fatalf("failed to connect to schema %s: %v", schema, err)
+	}
+
+	t.Cleanup(func() {
+		closeDB(db)
+		if err := admin.Exec(fmt.Sprintf(`DROP SCHEMA IF EXISTS "%s" CASCADE`, schema)).Error; err != nil {
+			t.Logf("failed to drop schema %s: %v", schema, err)
+		}
+		closeDB(admin)
+	})
+
+	if _, err := migrations.Main().Up(context.Background(), db); err != nil {
+		t.Fatalf("failed to migrate test schema: %v", err)
+	}
+	return db
+}
+
+func open(dsn string) (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: gormlogger.Default.LogMode(gormlogger.Silent)})
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	if err := sqlDB.Ping(); err != nil {
+		sqlDB.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func closeDB(db *gorm.DB) {
+	if sqlDB, err := db.DB(); err == nil {
+		sqlDB.Close()
+	}
+}
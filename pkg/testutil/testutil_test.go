@@ -0,0 +1,74 @@
+package testutil
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/auth"
+	"github.com/rusgainew/tunduck-app/pkg/entity"
+	"github.com/rusgainew/tunduck-app/pkg/middleware"
+	"github.com/rusgainew/tunduck-app/pkg/rbac"
+	"github.com/rusgainew/tunduck-app/pkg/response"
+)
+
+func TestFactories_ProduceValidUniqueEntities(t *testing.T) {
+	first, second := NewUser(), NewUser()
+	require.NoError(t, first.Validate())
+	assert.NotEqual(t, first.Username, second.Username)
+	assert.NotEqual(t, first.Email, second.Email)
+	assert.True(t, auth.VerifyPassword(first.Password, DefaultPassword))
+
+	admin := NewUser(func(u *entity.User) { u.Role = rbac.RoleAdmin })
+	assert.Equal(t, rbac.RoleAdmin, admin.Role)
+
+	org := NewOrganization()
+	require.NoError(t, org.Validate())
+	assert.NotEqual(t, org.DBName, NewOrganization().DBName)
+
+	doc := NewDocumentRequest()
+	var total float64
+	for _, entry := range doc.CatalogEntries {
+		total += entry.TotalAmount
+	}
+	assert.Equal(t, doc.TotalCurrencyValue, total)
+}
+
+func TestHarness_Do(t *testing.T) {
+	h := NewHarness(t)
+	protected := h.App.Group("/api", middleware.JWTMiddleware())
+	protected.Post("/echo", func(c *fiber.Ctx) error {
+		userID, err := middleware.GetUserIDFromContext(c)
+		if err != nil {
+			return response.Error(c, apperror.From(err, apperror.ErrUnauthorized, "unauthorized"))
+		}
+		var body map[string]string
+		if err := c.BodyParser(&body); err != nil {
+			return response.Error(c, apperror.New(apperror.ErrInvalidRequest, "invalid request body"))
+		}
+		body["user_id"] = userID.String()
+		return response.OK(c, body)
+	})
+
+	resp := h.Do(http.MethodPost, "/api/echo", map[string]string{"name": "test"})
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+
+	user := NewUser()
+	resp = h.Do(http.MethodPost, "/api/echo", map[string]string{"name": "test"},
+		WithToken(h.Token(user.ID.String(), user.Email)))
+	require.Equal(t, fiber.StatusOK, resp.StatusCode, string(resp.Body))
+
+	var data map[string]string
+	resp.DecodeData(&data)
+	assert.Equal(t, "test", data["name"])
+	assert.Equal(t, user.ID.String(), data["user_id"])
+	assert.NotEmpty(t, resp.Envelope().RequestID)
+
+	resp = h.Do(http.MethodGet, "/missing", nil)
+	assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	assert.NotEmpty(t, resp.ErrorCode())
+}
@@ -0,0 +1,154 @@
+// Package tin проверка идентификационных номеров налогоплательщиков: ИНН Кыргызстана (14 цифр)
+// и ИНН России (10 цифр у организаций, 12 — у физических лиц).
+//
+// ИНН Кыргызстана: первая цифра 0 у организаций, 1 или 2 у физических лиц (пол), у физических лиц
+// следующие восемь цифр — дата рождения ДДММГГГГ. Алгоритм контрольной цифры ИНН Кыргызстана
+// не опубликован, поэтому проверяется только структура. ИНН России проверяется по контрольным
+// цифрам.
+package tin
+
+import (
+	"errors"
+	"time"
+)
+
+// Country страна, выдавшая ИНН
+type Country string
+
+const (
+	KG Country = "KG"
+	RU Country = "RU"
+)
+
+// Длины ИНН
+const (
+	LengthKG       = 14
+	LengthRUEntity = 10
+	LengthRUPerson = 12
+)
+
+// Коды ошибок проверки; возвращаются клиенту как правило валидации поля
+const (
+	CodeFormat    = "tin_format"
+	CodeCategory  = "tin_category"
+	CodeBirthDate = "tin_birth_date"
+	CodeChecksum  = "tin_checksum"
+)
+
+// Error ошибка проверки ИНН с машиночитаемым кодом
+type Error struct {
+	Code    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+var (
+	// ErrFormat ИНН не из 14 (Кыргызстан), 10 или 12 (Россия) цифр
+	ErrFormat = &Error{Code: CodeFormat, Message: "TIN format: 14 digits (Kyrgyzstan) or 10 or 12 digits (Russia)"}
+	// ErrCategory ИНН Кыргызстана начинается не с 0, 1 или 2
+	ErrCategory = &Error{Code: CodeCategory, Message: "Kyrgyz TIN format: must start with 0 (organization), 1 or 2 (individual)"}
+	// ErrBirthDate в ИНН физического лица Кыргызстана нет корректной даты рождения
+	ErrBirthDate = &Error{Code: CodeBirthDate, Message: "Kyrgyz TIN format: an individual's TIN must contain a valid birth date"}
+	// ErrChecksum контрольные цифры ИНН России не совпадают
+	ErrChecksum = &Error{Code: CodeChecksum, Message: "TIN check digits do not match"}
+)
+
+// Validate проверяет ИНН Кыргызстана или России, страна определяется по длине
+func Validate(s string) (Country, error) {
+	if !digits(s) {
+		return "", ErrFormat
+	}
+	switch len(s) {
+	case LengthKG:
+		return KG, ValidateKG(s)
+	case LengthRUEntity, LengthRUPerson:
+		return RU, ValidateRU(s)
+	}
+	return "", ErrFormat
+}
+
+// Valid сообщает, прошел ли ИНН проверку: формат для Кыргызстана, формат и контрольные цифры для России
+func Valid(s string) bool {
+	_, err := Validate(s)
+	return err == nil
+}
+
+// Code возвращает код ошибки проверки или пустую строку для других ошибок
+func Code(err error) string {
+	var tinErr *Error
+	if errors.As(err, &tinErr) {
+		return tinErr.Code
+	}
+	return ""
+}
+
+// ValidateKG проверяет формат ИНН Кыргызстана: категорию и дату рождения. Контрольная цифра
+// не проверяется, поэтому номер правильного формата может оказаться невыданным.
+func ValidateKG(s string) error {
+	if len(s) != LengthKG || !digits(s) {
+		return ErrFormat
+	}
+	switch s[0] {
+	case '0':
+		return nil
+	case '1', '2':
+		born, err := time.Parse("02012006", s[1:9])
+		if err != nil || born.Year() < 1900 || born.After(time.Now()) {
+			return ErrBirthDate
+		}
+		return nil
+	}
+	return ErrCategory
+}
+
+// Весовые коэффициенты контрольных цифр ИНН России
+var (
+	weightsRU10 = []int{2, 4, 10, 3, 5, 9, 4, 6, 8}
+	weightsRU11 = []int{7, 2, 4, 10, 3, 5, 9, 4, 6, 8}
+	weightsRU12 = []int{3, 7, 2, 4, 10, 3, 5, 9, 4, 6, 8}
+)
+
+// ValidateRU проверяет ИНН России: одну контрольную цифру у организаций, две — у физических лиц
+func ValidateRU(s string) error {
+	if !digits(s) {
+		return ErrFormat
+	}
+	switch len(s) {
+	case LengthRUEntity:
+		if checkDigit(s, weightsRU10) != s[9] {
+			return ErrChecksum
+		}
+	case LengthRUPerson:
+		if checkDigit(s, weightsRU11) != s[10] || checkDigit(s, weightsRU12) != s[11] {
+			return ErrChecksum
+		}
+	default:
+		return ErrFormat
+	}
+	return nil
+}
+
+// checkDigit контрольная цифра по первым len(weights) цифрам: остаток суммы по модулю 11,
+// затем по модулю 10
+func checkDigit(s string, weights []int) byte {
+	sum := 0
+	for i, w := range weights {
+		sum += int(s[i]-'0') * w
+	}
+	return byte('0' + sum%11%10)
+}
+
+func digits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,52 @@
+package tin
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	tests := map[string]struct {
+		tin     string
+		country Country
+		err     error
+	}{
+		"kg organization":          {"03009201710890", KG, nil},
+		"kg woman":                 {"12103198800456", KG, nil},
+		"kg man":                   {"21203199500789", KG, nil},
+		"kg unknown category":      {"30101199000555", KG, ErrCategory},
+		"kg invalid birth date":    {"23002199000123", KG, ErrBirthDate},
+		"kg birth in the future":   {"20101299900123", KG, ErrBirthDate},
+		"ru organization":          {"7707083893", RU, nil},
+		"ru organization checksum": {"7707083894", RU, ErrChecksum},
+		"ru individual":            {"500100732259", RU, nil},
+		"ru individual checksum":   {"500100732258", RU, ErrChecksum},
+		"too short":                {"123456789", "", ErrFormat},
+		"thirteen digits":          {"0123456789012", "", ErrFormat},
+		"letters":                  {"01234567890I23", "", ErrFormat},
+		"empty":                    {"", "", ErrFormat},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			country, err := Validate(tt.tin)
+			assert.Equal(t, tt.country, country)
+			assert.Equal(t, tt.err, err)
+			assert.Equal(t, tt.err == nil, Valid(tt.tin))
+		})
+	}
+}
+
+func TestCode(t *testing.T) {
+	assert.Equal(t, CodeChecksum, Code(ValidateRU("7707083894")))
+	assert.Equal(t, CodeFormat, Code(fmt.Errorf("contractorTin: %w", ErrFormat)))
+	assert.Empty(t, Code(nil))
+	assert.Empty(t, Code(fmt.Errorf("other")))
+}
+
+func TestValidateCountry(t *testing.T) {
+	// Номер проверяется по правилам указанной страны
+	assert.Equal(t, ErrFormat, ValidateKG("7707083893"))
+	assert.Equal(t, ErrFormat, ValidateRU("03009201710890"))
+}
@@ -0,0 +1,61 @@
+package transaction
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// txKey ключ активной транзакции в контексте
+type txKey struct{}
+
+// TxManager выполняет операции нескольких репозиториев атомарно (unit of work).
+// Репозитории получают соединение через FromContext и автоматически участвуют в транзакции.
+type TxManager interface {
+	// WithinTx выполняет fn в транзакции: commit при nil, rollback при ошибке или панике.
+	// Вложенный вызов создает savepoint и при ошибке откатывает только свою часть.
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// gormTxManager реализация TxManager на GORM
+type gormTxManager struct {
+	db     *gorm.DB
+	logger *logrus.Logger
+}
+
+// NewTxManager создает менеджер транзакций
+func NewTxManager(db *gorm.DB, logger *logrus.Logger) TxManager {
+	return &gormTxManager{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// WithinTx реализует TxManager. GORM сам создает savepoint, если транзакция уже открыта.
+func (m *gormTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	nested := InTx(ctx)
+
+	err := FromContext(ctx, m.db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+	if err != nil {
+		m.logger.WithError(err).WithField("nested", nested).Debug("Transaction rolled back")
+	}
+	return err
+}
+
+// FromContext возвращает транзакцию из контекста или db, если транзакции нет.
+// Транзакция другой БД (например, БД организации) не используется.
+func FromContext(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok && tx.Config.ConnPool == db.Config.ConnPool {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// InTx проверяет, выполняется ли код внутри WithinTx
+func InTx(ctx context.Context) bool {
+	_, ok := ctx.Value(txKey{}).(*gorm.DB)
+	return ok
+}
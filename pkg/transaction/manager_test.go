@@ -0,0 +1,63 @@
+package transaction
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeTxPool пул-заглушка открытой транзакции
+type fakeTxPool struct {
+	gorm.ConnPool
+}
+
+// openUnreachable создает *gorm.DB без установки соединения
+func openUnreachable(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.Open("host=127.0.0.1 port=1 user=test dbname=test connect_timeout=1"), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	require.NoError(t, err)
+	return db
+}
+
+func TestFromContext(t *testing.T) {
+	db := openUnreachable(t)
+	other := openUnreachable(t)
+	ctx := context.Background()
+
+	assert.False(t, InTx(ctx))
+	assert.Equal(t, db.Statement.ConnPool, FromContext(ctx, db).Statement.ConnPool)
+
+	// Транзакция из контекста используется только для той же БД
+	tx := db.Session(&gorm.Session{NewDB: true})
+	tx.Statement.ConnPool = &fakeTxPool{}
+	txCtx := context.WithValue(ctx, txKey{}, tx)
+	assert.True(t, InTx(txCtx))
+	assert.Equal(t, tx.Statement.ConnPool, FromContext(txCtx, db).Statement.ConnPool)
+	assert.Equal(t, other.Statement.ConnPool, FromContext(txCtx, other).Statement.ConnPool)
+}
+
+func TestWithinTx_BeginErrorSkipsFn(t *testing.T) {
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	manager := NewTxManager(openUnreachable(t), log)
+
+	called := false
+	err := manager.WithinTx(context.Background(), func(ctx context.Context) error {
+		called = true
+		return nil
+	})
+
+	assert.Error(t, err)
+	assert.False(t, called)
+}
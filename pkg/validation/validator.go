@@ -0,0 +1,202 @@
+package validation
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+	"github.com/rusgainew/tunduck-app/pkg/tin"
+)
+
+// Validator проверяет структуры запросов по тегам validate и valid.
+// Тег validate используется моделями аутентификации, тег valid - моделями ЭСФ.
+// Кроме стандартных правил в обоих тегах доступно правило tin: проверка формата ИНН Кыргызстана
+// (без контрольной цифры, ее алгоритм не опубликован) или ИНН России с контрольными цифрами, pkg/tin.
+type Validator struct {
+	validate *validator.Validate
+	valid    *validator.Validate
+}
+
+var defaultValidator = New()
+
+// New создает новый Validator
+func New() *Validator {
+	v := &Validator{
+		validate: validator.New(),
+		valid:    validator.New(),
+	}
+
+	v.validate.RegisterTagNameFunc(jsonFieldName)
+
+	v.valid.SetTagName("valid")
+	v.valid.RegisterTagNameFunc(jsonFieldName)
+
+	for _, engine := range []*validator.Validate{v.validate, v.valid} {
+		_ = engine.RegisterValidation(tinRule, validTIN)
+	}
+
+	return v
+}
+
+// tinRule правило проверки формата ИНН
+const tinRule = "tin"
+
+func validTIN(fl validator.FieldLevel) bool {
+	return tin.Valid(fl.Field().String())
+}
+
+// Default возвращает общий Validator приложения
+func Default() *Validator {
+	return defaultValidator
+}
+
+// Engine возвращает validator для тега validate (для регистрации собственных правил)
+func (v *Validator) Engine() *validator.Validate {
+	return v.validate
+}
+
+// Struct валидирует структуру и возвращает ошибку 422 со списком ошибок по полям
+func (v *Validator) Struct(s interface{}) *apperror.AppError {
+	var fields []apperror.FieldError
+
+	for _, engine := range []*validator.Validate{v.validate, v.valid} {
+		err := engine.Struct(s)
+		if err == nil {
+			continue
+		}
+
+		var validationErrors validator.ValidationErrors
+		if !errors.As(err, &validationErrors) {
+			return apperror.New(apperror.ErrInternal, "Validation error").WithError(err)
+		}
+
+		for _, fe := range validationErrors {
+			fields = append(fields, toFieldError(fe))
+		}
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return apperror.ValidationFailed(fields)
+}
+
+// Struct валидирует структуру общим Validator
+func Struct(s interface{}) *apperror.AppError {
+	return defaultValidator.Struct(s)
+}
+
+// ParseBody разбирает тело запроса в out и валидирует его.
+// Возвращает 400 при некорректном формате и 422 при ошибках валидации полей.
+func (v *Validator) ParseBody(c *fiber.Ctx, out interface{}) *apperror.AppError {
+	if err := c.BodyParser(out); err != nil {
+		return apperror.New(apperror.ErrInvalidRequest, "invalid request format").WithError(err)
+	}
+	return v.Struct(out)
+}
+
+// ParseBody разбирает и валидирует тело запроса общим Validator
+func ParseBody(c *fiber.Ctx, out interface{}) *apperror.AppError {
+	return defaultValidator.ParseBody(c, out)
+}
+
+// toFieldError преобразует ошибку validator в ошибку поля.
+// Для правила tin вместо имени правила возвращается код ошибки pkg/tin (tin_checksum и другие).
+func toFieldError(fe validator.FieldError) apperror.FieldError {
+	if fe.Tag() == tinRule {
+		_, err := tin.Validate(fmt.Sprint(fe.Value()))
+		code := tin.Code(err)
+		return apperror.FieldError{
+			Field:   fieldPath(fe.Namespace()),
+			Rule:    code,
+			Message: ruleMessage(code),
+		}
+	}
+	return apperror.FieldError{
+		Field:   fieldPath(fe.Namespace()),
+		Rule:    fe.Tag(),
+		Param:   fe.Param(),
+		Message: ruleMessage(fe.Tag()),
+	}
+}
+
+// embeddedSegment помечает в пути встроенные структуры без тега json
+const embeddedSegment = "~"
+
+// fieldPath убирает имя корневой структуры и встроенных структур из пути поля:
+// "Request.items[0].price" -> "items[0].price"
+func fieldPath(namespace string) string {
+	segments := strings.Split(namespace, ".")
+	if len(segments) > 1 {
+		segments = segments[1:]
+	}
+
+	path := segments[:0]
+	for _, s := range segments {
+		if s != embeddedSegment {
+			path = append(path, s)
+		}
+	}
+	return strings.Join(path, ".")
+}
+
+// ruleMessage возвращает шаблон сообщения для правила валидации.
+// Шаблоны переводятся через pkg/i18n, параметры: {field}, {param}.
+func ruleMessage(rule string) string {
+	switch rule {
+	case "required", "required_unless":
+		return "{field} is required"
+	case "email":
+		return "{field} must be a valid email"
+	case "min":
+		return "{field} must be at least {param}"
+	case "max":
+		return "{field} must be at most {param}"
+	case "len":
+		return "{field} must have length {param}"
+	case "eqfield":
+		return "{field} must match {param}"
+	case "oneof":
+		return "{field} must be one of: {param}"
+	case "uuid", "uuid4":
+		return "{field} must be a valid UUID"
+	case "gt", "gte":
+		return "{field} must be greater than {param}"
+	case "lt", "lte":
+		return "{field} must be less than {param}"
+	case tin.CodeFormat:
+		return "{field} does not match the TIN format: 14 digits (Kyrgyzstan) or 10 or 12 digits (Russia)"
+	case tin.CodeCategory:
+		return "{field} does not match the Kyrgyz TIN format: it must start with 0 (organization), 1 or 2 (individual)"
+	case tin.CodeBirthDate:
+		return "{field} does not match the Kyrgyz TIN format: it must contain a valid birth date"
+	case tin.CodeChecksum:
+		return "{field} has invalid check digits"
+	default:
+		return "{field} is invalid"
+	}
+}
+
+// jsonFieldName возвращает имя поля из тега json.
+// Встроенные структуры без тега json не добавляют сегмент в путь.
+func jsonFieldName(f reflect.StructField) string {
+	tag := f.Tag.Get("json")
+	if tag == "" && f.Anonymous {
+		return embeddedSegment
+	}
+
+	name := strings.SplitN(tag, ",", 2)[0]
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
@@ -0,0 +1,115 @@
+package validation
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/rusgainew/tunduck-app/pkg/apperror"
+)
+
+type testEntry struct {
+	Code  string  `json:"code" valid:"required"`
+	Price float64 `json:"price" valid:"required"`
+}
+
+type testDocument struct {
+	IsResident bool        `json:"isResident"`
+	Currency   string      `json:"currency" valid:"required"`
+	Entries    []testEntry `json:"entries" valid:"required,dive"`
+}
+
+type testEditDocument struct {
+	ID string `json:"id" valid:"required"`
+	testDocument
+}
+
+type testRegister struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required,min=8"`
+}
+
+func fieldNames(appErr *apperror.AppError) []string {
+	names := make([]string, 0, len(appErr.Fields))
+	for _, f := range appErr.Fields {
+		names = append(names, f.Field)
+	}
+	return names
+}
+
+func TestStruct_Valid(t *testing.T) {
+	doc := testDocument{
+		IsResident: false,
+		Currency:   "KGS",
+		Entries:    []testEntry{{Code: "1", Price: 10}},
+	}
+
+	assert.Nil(t, Struct(&doc))
+}
+
+func TestStruct_FieldErrors(t *testing.T) {
+	appErr := Struct(&testDocument{})
+	require.NotNil(t, appErr)
+
+	assert.Equal(t, apperror.ErrFieldValidation, appErr.Code)
+	assert.Equal(t, http.StatusUnprocessableEntity, appErr.HTTPStatus)
+	assert.ElementsMatch(t, []string{"currency", "entries"}, fieldNames(appErr))
+}
+
+func TestStruct_Dive(t *testing.T) {
+	doc := testDocument{
+		Currency: "KGS",
+		Entries:  []testEntry{{Code: "1", Price: 10}, {Price: 5}},
+	}
+
+	appErr := Struct(&doc)
+	require.NotNil(t, appErr)
+	require.Len(t, appErr.Fields, 1)
+	assert.Equal(t, "entries[1].code", appErr.Fields[0].Field)
+	assert.Equal(t, "required", appErr.Fields[0].Rule)
+}
+
+func TestStruct_Embedded(t *testing.T) {
+	appErr := Struct(&testEditDocument{testDocument: testDocument{Currency: "KGS"}})
+	require.NotNil(t, appErr)
+	assert.ElementsMatch(t, []string{"id", "entries"}, fieldNames(appErr))
+}
+
+func TestStruct_ValidateTag(t *testing.T) {
+	appErr := Struct(&testRegister{Email: "not-an-email", Password: "short"})
+	require.NotNil(t, appErr)
+	require.Len(t, appErr.Fields, 2)
+
+	resp := appErr.ToResponse()
+	assert.Equal(t, "email", resp.Fields[0].Field)
+	assert.Equal(t, "email must be a valid email", resp.Fields[0].Message)
+	assert.Equal(t, "password", resp.Fields[1].Field)
+	assert.Equal(t, "password must be at least 8", resp.Fields[1].Message)
+}
+
+type testContract struct {
+	ContractorTin string   `json:"contractorTin" validate:"required,tin"`
+	AffiliateTin  string   `json:"affiliateTin" valid:"omitempty,tin"`
+	GroupTins     []string `json:"groupTins" validate:"dive,tin"`
+}
+
+func TestStruct_TIN(t *testing.T) {
+	assert.Nil(t, Struct(&testContract{ContractorTin: "03009201710890", GroupTins: []string{"7707083893", "500100732259"}}))
+
+	appErr := Struct(&testContract{ContractorTin: "7707083894", AffiliateTin: "30101199000555", GroupTins: []string{"12345"}})
+	require.NotNil(t, appErr)
+	rules := map[string]string{}
+	for _, f := range appErr.Fields {
+		rules[f.Field] = f.Rule
+	}
+	assert.Equal(t, map[string]string{
+		"contractorTin": "tin_checksum",
+		"affiliateTin":  "tin_category",
+		"groupTins[0]":  "tin_format",
+	}, rules)
+
+	resp := appErr.ToResponse()
+	assert.Equal(t, "contractorTin has invalid check digits", resp.Fields[0].Message)
+}